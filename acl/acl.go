@@ -51,12 +51,31 @@ type ACL struct {
 	// We use an iradix for the purposes of ordered iteration.
 	wildcardNamespaces *iradix.Tree
 
+	// variables maps a namespace and variable path pattern, either of which
+	// may be a glob, to a capabilitySet
+	variables map[patternRule]capabilitySet
+
+	// jobs maps a namespace and job ID pattern, either of which may be a
+	// glob, to a capabilitySet
+	jobs map[patternRule]capabilitySet
+
+	// nodePools maps a node pool name, which may be a glob, to a
+	// capabilitySet
+	nodePools map[string]capabilitySet
+
 	agent    string
 	node     string
 	operator string
 	quota    string
 }
 
+// patternRule identifies the variables or jobs of a namespace a set of
+// capabilities is granted on, by a pattern of their path or ID
+type patternRule struct {
+	namespace string
+	pattern   string
+}
+
 // maxPrivilege returns the policy which grants the most privilege
 // This handles the case of Deny always taking maximum precedence.
 func maxPrivilege(a, b string) string {
@@ -80,7 +99,11 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	}
 
 	// Create the ACL object
-	acl := &ACL{}
+	acl := &ACL{
+		variables: make(map[patternRule]capabilitySet),
+		jobs:      make(map[patternRule]capabilitySet),
+		nodePools: make(map[string]capabilitySet),
+	}
 	nsTxn := iradix.New().Txn()
 	wnsTxn := iradix.New().Txn()
 
@@ -111,6 +134,14 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 				}
 			}
 
+			// Merge the variables and jobs capabilities
+			if ns.Variables != nil {
+				acl.addVariablesPolicy(ns.Name, ns.Variables)
+			}
+			for _, jp := range ns.Jobs {
+				addPatternCapabilities(acl.jobs, patternRule{namespace: ns.Name, pattern: jp.JobSpec}, jp.Capabilities, NamespaceCapabilityDeny)
+			}
+
 			// Deny always takes precedence
 			if capabilities.Check(NamespaceCapabilityDeny) {
 				continue NAMESPACES
@@ -128,6 +159,11 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 			}
 		}
 
+		// Merge the node pool capabilities
+		for _, np := range policy.NodePools {
+			addNodePoolCapabilities(acl.nodePools, np.Name, np.Capabilities)
+		}
+
 		// Take the maximum privilege for agent, node, and operator
 		if policy.Agent != nil {
 			acl.agent = maxPrivilege(acl.agent, policy.Agent.Policy)
@@ -264,6 +300,195 @@ func (a *ACL) findAllMatchingWildcards(ns string) []matchingGlob {
 	return matches
 }
 
+// addVariablesPolicy merges the capabilities of the variables policy of a
+// namespace
+func (a *ACL) addVariablesPolicy(ns string, policy *VariablesPolicy) {
+	for _, pp := range policy.Paths {
+		rule := patternRule{namespace: ns, pattern: pp.PathSpec}
+		addPatternCapabilities(a.variables, rule, pp.Capabilities, VariablesCapabilityDeny)
+	}
+}
+
+// addPatternCapabilities merges the capabilities granted by a rule. The deny
+// capability takes precedence and overwrites all other capabilities.
+func addPatternCapabilities(rules map[patternRule]capabilitySet, rule patternRule, caps []string, deny string) {
+	capabilities, ok := rules[rule]
+	if !ok {
+		capabilities = make(capabilitySet)
+		rules[rule] = capabilities
+	}
+
+	// Deny always takes precedence
+	if capabilities.Check(deny) {
+		return
+	}
+
+	for _, cap := range caps {
+		if cap == deny {
+			// Overwrite any existing capabilities
+			capabilities.Clear()
+			capabilities.Set(deny)
+			return
+		}
+		capabilities.Set(cap)
+	}
+}
+
+// AllowVariableOperation checks if a given operation is allowed on the variable
+// at the path in a namespace
+func (a *ACL) AllowVariableOperation(ns, path, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	// Check for a matching capability set
+	capabilities, ok := matchingPatternCapabilitySet(a.variables, ns, path)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted
+	return capabilities.Check(op)
+}
+
+// AllowJobOperation checks if a given operation is allowed on the job with
+// the ID in a namespace. The job policy most closely matching the job takes
+// precedence: it may grant the operation or deny it on the job, otherwise the
+// capabilities of the namespace apply.
+func (a *ACL) AllowJobOperation(ns, jobID, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	if capabilities, ok := matchingPatternCapabilitySet(a.jobs, ns, jobID); ok {
+		if capabilities.Check(NamespaceCapabilityDeny) {
+			return false
+		}
+		if capabilities.Check(op) {
+			return true
+		}
+	}
+
+	return a.AllowNamespaceOperation(ns, op)
+}
+
+// AllowJobOp is shorthand for AllowJobOperation
+func (a *ACL) AllowJobOp(ns, jobID, op string) bool {
+	return a.AllowJobOperation(ns, jobID, op)
+}
+
+// matchingPatternCapabilitySet looks for the capabilitySet of the rule that
+// most closely matches the namespace and the value, a variable path or job ID.
+// The closest matching rule is the one that has the smallest character
+// difference between its namespace and pattern and the requested ones, so
+// concrete definitions take precedence over globs.
+func matchingPatternCapabilitySet(rules map[patternRule]capabilitySet, ns, value string) (capabilitySet, bool) {
+	var best capabilitySet
+	var bestRule patternRule
+	bestDifference := -1
+	for rule, capabilities := range rules {
+		nsDifference, ok := globDifference(rule.namespace, ns)
+		if !ok {
+			continue
+		}
+		valueDifference, ok := globDifference(rule.pattern, value)
+		if !ok {
+			continue
+		}
+
+		// Break ties on the rule so that the result doesn't depend on the
+		// iteration order of the map
+		difference := nsDifference + valueDifference
+		if bestDifference == -1 || difference < bestDifference ||
+			(difference == bestDifference && (rule.namespace+rule.pattern) < (bestRule.namespace+bestRule.pattern)) {
+			best = capabilities
+			bestRule = rule
+			bestDifference = difference
+		}
+	}
+
+	return best, bestDifference != -1
+}
+
+// globDifference returns whether the pattern matches the value and if so the
+// character difference between them. Patterns without globs must match
+// exactly.
+func globDifference(pattern, value string) (int, bool) {
+	if !strings.Contains(pattern, glob.GLOB) {
+		return 0, pattern == value
+	}
+	if !glob.Glob(pattern, value) {
+		return 0, false
+	}
+	return len(value) - len(pattern) + strings.Count(pattern, glob.GLOB), true
+}
+
+// addNodePoolCapabilities merges the capabilities granted on the node pools
+// matching the name. The deny capability takes precedence and overwrites all
+// other capabilities.
+func addNodePoolCapabilities(pools map[string]capabilitySet, name string, caps []string) {
+	capabilities, ok := pools[name]
+	if !ok {
+		capabilities = make(capabilitySet)
+		pools[name] = capabilities
+	}
+
+	// Deny always takes precedence
+	if capabilities.Check(NodePoolCapabilityDeny) {
+		return
+	}
+
+	for _, cap := range caps {
+		if cap == NodePoolCapabilityDeny {
+			// Overwrite any existing capabilities
+			capabilities.Clear()
+			capabilities.Set(NodePoolCapabilityDeny)
+			return
+		}
+		capabilities.Set(cap)
+	}
+}
+
+// AllowNodePoolOperation checks if a given operation is allowed on a node
+// pool. The node pool policy most closely matching the pool's name applies.
+func (a *ACL) AllowNodePoolOperation(pool, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	var best capabilitySet
+	var bestName string
+	bestDifference := -1
+	for name, capabilities := range a.nodePools {
+		difference, ok := globDifference(name, pool)
+		if !ok {
+			continue
+		}
+
+		// Break ties on the name so that the result doesn't depend on the
+		// iteration order of the map
+		if bestDifference == -1 || difference < bestDifference ||
+			(difference == bestDifference && name < bestName) {
+			best = capabilities
+			bestName = name
+			bestDifference = difference
+		}
+	}
+	if bestDifference == -1 {
+		return false
+	}
+
+	return best.Check(op)
+}
+
+// AllowNodePoolOp is shorthand for AllowNodePoolOperation
+func (a *ACL) AllowNodePoolOp(pool, op string) bool {
+	return a.AllowNodePoolOperation(pool, op)
+}
+
 // AllowAgentRead checks if read operations are allowed for an agent
 func (a *ACL) AllowAgentRead() bool {
 	switch {
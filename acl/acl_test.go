@@ -314,6 +314,216 @@ func TestWildcardNamespaceMatching(t *testing.T) {
 	}
 }
 
+func TestAllowVariableOperation(t *testing.T) {
+	tests := []struct {
+		Policy string
+		Op     string
+		Allow  bool
+	}{
+		{
+			Policy: `namespace "default" {}`,
+			Op:     VariablesCapabilityRead,
+			Allow:  false,
+		},
+		{ // The namespace policy applies to all the variables
+			Policy: `namespace "default" { policy = "read" }`,
+			Op:     VariablesCapabilityRead,
+			Allow:  true,
+		},
+		{
+			Policy: `namespace "default" { policy = "read" }`,
+			Op:     VariablesCapabilityWrite,
+			Allow:  false,
+		},
+		{
+			Policy: `namespace "*" { policy = "write" }`,
+			Op:     VariablesCapabilityDestroy,
+			Allow:  true,
+		},
+		{ // Path globs match
+			Policy: `namespace "default" { variables { path "project/*" { capabilities = ["write"] } } }`,
+			Op:     VariablesCapabilityWrite,
+			Allow:  true,
+		},
+		{ // Paths not matching are not allowed
+			Policy: `namespace "default" { variables { path "other/*" { capabilities = ["write"] } } }`,
+			Op:     VariablesCapabilityWrite,
+			Allow:  false,
+		},
+		{ // Concrete paths take precedence
+			Policy: `namespace "default" {
+			           policy = "write"
+			           variables { path "project/db" { capabilities = ["deny"] } }
+			         }`,
+			Op:    VariablesCapabilityRead,
+			Allow: false,
+		},
+		{ // The closest path match wins
+			Policy: `namespace "default" {
+			           variables {
+			             path "*" { capabilities = ["deny"] }
+			             path "project/*" { capabilities = ["read"] }
+			           }
+			         }`,
+			Op:    VariablesCapabilityRead,
+			Allow: true,
+		},
+		{ // Deny takes precedence when merging
+			Policy: `namespace "default" { variables { path "project/*" { capabilities = ["read"] } } }
+			         namespace "default" { variables { path "project/*" { capabilities = ["deny"] } } }`,
+			Op:    VariablesCapabilityRead,
+			Allow: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.NoError(err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Allow, acl.AllowVariableOperation("default", "project/db", tc.Op))
+		})
+	}
+}
+
+func TestAllowJobOperation(t *testing.T) {
+	tests := []struct {
+		Policy string
+		JobID  string
+		Op     string
+		Allow  bool
+	}{
+		{ // The namespace capabilities apply to all the jobs
+			Policy: `namespace "default" { policy = "write" }`,
+			JobID:  "web",
+			Op:     NamespaceCapabilitySubmitJob,
+			Allow:  true,
+		},
+		{
+			Policy: `namespace "default" { policy = "read" }`,
+			JobID:  "web",
+			Op:     NamespaceCapabilitySubmitJob,
+			Allow:  false,
+		},
+		{ // Job ID globs match
+			Policy: `namespace "default" { job "web-*" { capabilities = ["submit-job"] } }`,
+			JobID:  "web-api",
+			Op:     NamespaceCapabilitySubmitJob,
+			Allow:  true,
+		},
+		{ // Jobs not matching are not allowed
+			Policy: `namespace "default" { job "web-*" { capabilities = ["submit-job"] } }`,
+			JobID:  "batch",
+			Op:     NamespaceCapabilitySubmitJob,
+			Allow:  false,
+		},
+		{ // Only the granted capabilities are allowed
+			Policy: `namespace "default" { job "web-*" { capabilities = ["submit-job"] } }`,
+			JobID:  "web-api",
+			Op:     NamespaceCapabilityDispatchJob,
+			Allow:  false,
+		},
+		{ // Job policies may deny jobs the namespace allows
+			Policy: `namespace "default" {
+			           policy = "write"
+			           job "web-db" { capabilities = ["deny"] }
+			         }`,
+			JobID: "web-db",
+			Op:    NamespaceCapabilitySubmitJob,
+			Allow: false,
+		},
+		{ // The closest job match wins
+			Policy: `namespace "default" {
+			           job "*" { capabilities = ["deny"] }
+			           job "web-*" { capabilities = ["submit-job"] }
+			         }`,
+			JobID: "web-api",
+			Op:    NamespaceCapabilitySubmitJob,
+			Allow: true,
+		},
+		{ // Namespace globs match
+			Policy: `namespace "*" { job "web-*" { capabilities = ["dispatch-job"] } }`,
+			JobID:  "web-api",
+			Op:     NamespaceCapabilityDispatchJob,
+			Allow:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.NoError(err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Allow, acl.AllowJobOperation("default", tc.JobID, tc.Op))
+		})
+	}
+}
+
+func TestAllowNodePoolOperation(t *testing.T) {
+	tests := []struct {
+		Policy string
+		Pool   string
+		Op     string
+		Allow  bool
+	}{
+		{
+			Policy: `node_pool "gpu" { policy = "write" }`,
+			Pool:   "gpu",
+			Op:     NodePoolCapabilitySubmitJob,
+			Allow:  true,
+		},
+		{
+			Policy: `node_pool "gpu" { policy = "read" }`,
+			Pool:   "gpu",
+			Op:     NodePoolCapabilitySubmitJob,
+			Allow:  false,
+		},
+		{ // Pools not matching are not allowed
+			Policy: `node_pool "gpu" { policy = "write" }`,
+			Pool:   "batch",
+			Op:     NodePoolCapabilityRead,
+			Allow:  false,
+		},
+		{ // Pool globs match
+			Policy: `node_pool "batch-*" { capabilities = ["submit-job"] }`,
+			Pool:   "batch-large",
+			Op:     NodePoolCapabilitySubmitJob,
+			Allow:  true,
+		},
+		{ // The closest pool match wins
+			Policy: `node_pool "*" { policy = "write" }
+			         node_pool "prod" { capabilities = ["deny"] }`,
+			Pool:  "prod",
+			Op:    NodePoolCapabilityRead,
+			Allow: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Policy, func(t *testing.T) {
+			assert := assert.New(t)
+
+			policy, err := Parse(tc.Policy)
+			assert.NoError(err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			assert.Nil(err)
+
+			assert.Equal(tc.Allow, acl.AllowNodePoolOperation(tc.Pool, tc.Op))
+		})
+	}
+}
+
 func TestACL_matchingCapabilitySet_returnsAllMatches(t *testing.T) {
 	tests := []struct {
 		Policy        string
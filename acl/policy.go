@@ -28,16 +28,43 @@ const (
 	NamespaceCapabilityDispatchJob      = "dispatch-job"
 	NamespaceCapabilityReadLogs         = "read-logs"
 	NamespaceCapabilityReadFS           = "read-fs"
+	NamespaceCapabilityWriteFS          = "write-fs"
+	NamespaceCapabilityRunAction        = "run-action"
+	NamespaceCapabilityAllocExec        = "alloc-exec"
 	NamespaceCapabilitySentinelOverride = "sentinel-override"
 )
 
+const (
+	// The following are the capabilities that can be granted on the variables of
+	// a namespace, by path. The deny capability takes precedence and overwrites
+	// all other capabilities.
+	VariablesCapabilityList    = "list"
+	VariablesCapabilityRead    = "read"
+	VariablesCapabilityWrite   = "write"
+	VariablesCapabilityDestroy = "destroy"
+	VariablesCapabilityDeny    = "deny"
+)
+
+const (
+	// The following are the capabilities that can be granted on a node pool.
+	// The deny capability takes precedence and overwrites all other
+	// capabilities.
+	NodePoolCapabilityDeny      = "deny"
+	NodePoolCapabilityRead      = "read"
+	NodePoolCapabilityWrite     = "write"
+	NodePoolCapabilityDelete    = "delete"
+	NodePoolCapabilitySubmitJob = "submit-job"
+)
+
 var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-*]{1,128}$")
+	validNodePool  = regexp.MustCompile("^[a-zA-Z0-9-_*]{1,128}$")
 )
 
 // Policy represents a parsed HCL or JSON policy.
 type Policy struct {
 	Namespaces []*NamespacePolicy `hcl:"namespace,expand"`
+	NodePools  []*NodePoolPolicy  `hcl:"node_pool,expand"`
 	Agent      *AgentPolicy       `hcl:"agent"`
 	Node       *NodePolicy        `hcl:"node"`
 	Operator   *OperatorPolicy    `hcl:"operator"`
@@ -49,6 +76,7 @@ type Policy struct {
 // comprised of only a raw policy.
 func (p *Policy) IsEmpty() bool {
 	return len(p.Namespaces) == 0 &&
+		len(p.NodePools) == 0 &&
 		p.Agent == nil &&
 		p.Node == nil &&
 		p.Operator == nil &&
@@ -60,6 +88,35 @@ type NamespacePolicy struct {
 	Name         string `hcl:",key"`
 	Policy       string
 	Capabilities []string
+	Variables    *VariablesPolicy `hcl:"variables"`
+	Jobs         []*JobPolicy     `hcl:"job,expand"`
+}
+
+// JobPolicy is the policy for the jobs of a namespace whose ID matches a
+// pattern, which may contain glob patterns
+type JobPolicy struct {
+	JobSpec      string `hcl:",key"`
+	Capabilities []string
+}
+
+// VariablesPolicy is the policy for the variables of a namespace
+type VariablesPolicy struct {
+	Paths []*VariablesPathPolicy `hcl:"path,expand"`
+}
+
+// VariablesPathPolicy is the policy for the variables matching a path, which
+// may contain glob patterns
+type VariablesPathPolicy struct {
+	PathSpec     string `hcl:",key"`
+	Capabilities []string
+}
+
+// NodePoolPolicy is the policy for the node pools whose name matches a
+// pattern, which may contain glob patterns
+type NodePoolPolicy struct {
+	Name         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
 }
 
 type AgentPolicy struct {
@@ -93,7 +150,8 @@ func isNamespaceCapabilityValid(cap string) bool {
 	switch cap {
 	case NamespaceCapabilityDeny, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
 		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
-		NamespaceCapabilityReadFS:
+		NamespaceCapabilityReadFS, NamespaceCapabilityWriteFS, NamespaceCapabilityRunAction,
+		NamespaceCapabilityAllocExec:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride:
@@ -103,6 +161,62 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isJobCapabilityValid ensures the given capability is valid for a job policy.
+// Only the capabilities checked against the ID of a job may be granted on
+// jobs.
+func isJobCapabilityValid(cap string) bool {
+	switch cap {
+	case NamespaceCapabilityDeny, NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob:
+		return true
+	default:
+		return false
+	}
+}
+
+// isVariablesCapabilityValid ensures the given capability is valid for a
+// variables path policy
+func isVariablesCapabilityValid(cap string) bool {
+	switch cap {
+	case VariablesCapabilityList, VariablesCapabilityRead, VariablesCapabilityWrite,
+		VariablesCapabilityDestroy, VariablesCapabilityDeny:
+		return true
+	default:
+		return false
+	}
+}
+
+// isNodePoolCapabilityValid ensures the given capability is valid for a node
+// pool policy
+func isNodePoolCapabilityValid(cap string) bool {
+	switch cap {
+	case NodePoolCapabilityDeny, NodePoolCapabilityRead, NodePoolCapabilityWrite,
+		NodePoolCapabilityDelete, NodePoolCapabilitySubmitJob:
+		return true
+	default:
+		return false
+	}
+}
+
+// expandNodePoolPolicy provides the equivalent set of capabilities for a node
+// pool policy
+func expandNodePoolPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{NodePoolCapabilityDeny}
+	case PolicyRead:
+		return []string{NodePoolCapabilityRead}
+	case PolicyWrite:
+		return []string{
+			NodePoolCapabilityRead,
+			NodePoolCapabilityWrite,
+			NodePoolCapabilityDelete,
+			NodePoolCapabilitySubmitJob,
+		}
+	default:
+		return nil
+	}
+}
+
 // expandNamespacePolicy provides the equivalent set of capabilities for
 // a namespace policy
 func expandNamespacePolicy(policy string) []string {
@@ -122,6 +236,32 @@ func expandNamespacePolicy(policy string) []string {
 			NamespaceCapabilityDispatchJob,
 			NamespaceCapabilityReadLogs,
 			NamespaceCapabilityReadFS,
+			NamespaceCapabilityWriteFS,
+			NamespaceCapabilityRunAction,
+			NamespaceCapabilityAllocExec,
+		}
+	default:
+		return nil
+	}
+}
+
+// expandVariablesPolicy provides the equivalent set of capabilities on all the
+// variables of a namespace for a namespace policy
+func expandVariablesPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariablesCapabilityDeny}
+	case PolicyRead:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+		}
+	case PolicyWrite:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+			VariablesCapabilityWrite,
+			VariablesCapabilityDestroy,
 		}
 	default:
 		return nil
@@ -164,11 +304,62 @@ func Parse(rules string) (*Policy, error) {
 			}
 		}
 
+		if ns.Variables != nil {
+			for _, pp := range ns.Variables.Paths {
+				if pp.PathSpec == "" {
+					return nil, fmt.Errorf("Invalid missing variable path in namespace %#v", ns)
+				}
+				for _, cap := range pp.Capabilities {
+					if !isVariablesCapabilityValid(cap) {
+						return nil, fmt.Errorf("Invalid variable capability '%s': %#v", cap, pp)
+					}
+				}
+			}
+		}
+
+		for _, jp := range ns.Jobs {
+			if jp.JobSpec == "" {
+				return nil, fmt.Errorf("Invalid missing job ID in namespace %#v", ns)
+			}
+			for _, cap := range jp.Capabilities {
+				if !isJobCapabilityValid(cap) {
+					return nil, fmt.Errorf("Invalid job capability '%s': %#v", cap, jp)
+				}
+			}
+		}
+
 		// Expand the short hand policy to the capabilities and
 		// add to any existing capabilities
 		if ns.Policy != "" {
 			extraCap := expandNamespacePolicy(ns.Policy)
 			ns.Capabilities = append(ns.Capabilities, extraCap...)
+
+			if ns.Variables == nil {
+				ns.Variables = &VariablesPolicy{}
+			}
+			ns.Variables.Paths = append(ns.Variables.Paths, &VariablesPathPolicy{
+				PathSpec:     "*",
+				Capabilities: expandVariablesPolicy(ns.Policy),
+			})
+		}
+	}
+
+	for _, np := range p.NodePools {
+		if !validNodePool.MatchString(np.Name) {
+			return nil, fmt.Errorf("Invalid node pool name: %#v", np)
+		}
+		if np.Policy != "" && !isPolicyValid(np.Policy) {
+			return nil, fmt.Errorf("Invalid node pool policy: %#v", np)
+		}
+		for _, cap := range np.Capabilities {
+			if !isNodePoolCapabilityValid(cap) {
+				return nil, fmt.Errorf("Invalid node pool capability '%s': %#v", cap, np)
+			}
+		}
+
+		// Expand the short hand policy to the capabilities
+		if np.Policy != "" {
+			np.Capabilities = append(np.Capabilities, expandNodePoolPolicy(np.Policy)...)
 		}
 	}
 
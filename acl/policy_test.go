@@ -31,6 +31,17 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
 						},
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "*",
+									Capabilities: []string{
+										VariablesCapabilityList,
+										VariablesCapabilityRead,
+									},
+								},
+							},
+						},
 					},
 				},
 			},
@@ -69,6 +80,17 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityListJobs,
 							NamespaceCapabilityReadJob,
 						},
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "*",
+									Capabilities: []string{
+										VariablesCapabilityList,
+										VariablesCapabilityRead,
+									},
+								},
+							},
+						},
 					},
 					{
 						Name:   "other",
@@ -80,6 +102,22 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityDispatchJob,
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
+							NamespaceCapabilityWriteFS,
+							NamespaceCapabilityRunAction,
+							NamespaceCapabilityAllocExec,
+						},
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "*",
+									Capabilities: []string{
+										VariablesCapabilityList,
+										VariablesCapabilityRead,
+										VariablesCapabilityWrite,
+										VariablesCapabilityDestroy,
+									},
+								},
+							},
 						},
 					},
 					{
@@ -104,6 +142,127 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "project/*" {
+						capabilities = ["read", "write"]
+					}
+					path "project/secret" {
+						capabilities = ["deny"]
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "project/*",
+									Capabilities: []string{
+										VariablesCapabilityRead,
+										VariablesCapabilityWrite,
+									},
+								},
+								{
+									PathSpec: "project/secret",
+									Capabilities: []string{
+										VariablesCapabilityDeny,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "default" {
+				capabilities = ["read-job"]
+				job "web-*" {
+					capabilities = ["submit-job", "dispatch-job"]
+				}
+				job "web-db" {
+					capabilities = ["deny"]
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Capabilities: []string{
+							NamespaceCapabilityReadJob,
+						},
+						Jobs: []*JobPolicy{
+							{
+								JobSpec: "web-*",
+								Capabilities: []string{
+									NamespaceCapabilitySubmitJob,
+									NamespaceCapabilityDispatchJob,
+								},
+							},
+							{
+								JobSpec: "web-db",
+								Capabilities: []string{
+									NamespaceCapabilityDeny,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			node_pool "batch-*" {
+				policy = "read"
+				capabilities = ["submit-job"]
+			}
+			node_pool "gpu" {
+				policy = "write"
+			}
+			`,
+			"",
+			&Policy{
+				NodePools: []*NodePoolPolicy{
+					{
+						Name:   "batch-*",
+						Policy: PolicyRead,
+						Capabilities: []string{
+							NodePoolCapabilitySubmitJob,
+							NodePoolCapabilityRead,
+						},
+					},
+					{
+						Name:   "gpu",
+						Policy: PolicyWrite,
+						Capabilities: []string{
+							NodePoolCapabilityRead,
+							NodePoolCapabilityWrite,
+							NodePoolCapabilityDelete,
+							NodePoolCapabilitySubmitJob,
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			node_pool "gpu" {
+				capabilities = ["read-job"]
+			}
+			`,
+			"Invalid node pool capability",
+			nil,
+		},
 		{
 			`
 			namespace "default" {
@@ -113,6 +272,30 @@ func TestParse(t *testing.T) {
 			"Invalid namespace policy",
 			nil,
 		},
+		{
+			`
+			namespace "default" {
+				job "web-*" {
+					capabilities = ["read-fs"]
+				}
+			}
+			`,
+			"Invalid job capability",
+			nil,
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "project/*" {
+						capabilities = ["read", "foo"]
+					}
+				}
+			}
+			`,
+			"Invalid variable capability",
+			nil,
+		},
 		{
 			`
 			namespace "default" {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -109,6 +110,53 @@ func TestACLPolicies_Info(t *testing.T) {
 	assert.Equal(t, policy.Name, out.Name)
 }
 
+func TestACLRoles(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	ar := c.ACLRoles()
+
+	// Register a role
+	role := &ACLRole{
+		Name:        "operators",
+		Description: "Operators of the cluster",
+		Policies:    []string{"readonly", "submit"},
+	}
+	wm, err := ar.Upsert(role, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// List the roles
+	result, qm, err := ar.List(nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, result, 1)
+
+	// Query the role
+	out, qm, err := ar.Info(role.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, role.Policies, out.Policies)
+
+	// Create a token associated with the role
+	token, wm, err := c.ACLTokens().Create(&ACLToken{
+		Type:  "client",
+		Roles: []string{role.Name},
+	}, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+	assert.Equal(t, []string{role.Name}, token.Roles)
+
+	// Delete the role
+	wm, err = ar.Delete(role.Name, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = ar.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
+
 func TestACLTokens_List(t *testing.T) {
 	t.Parallel()
 	c, s, _ := makeACLClient(t, nil, nil)
@@ -235,3 +283,108 @@ func TestACLTokens_Delete(t *testing.T) {
 	assert.Nil(t, err)
 	assertWriteMeta(t, wm)
 }
+
+func TestACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	am := c.ACLAuthMethods()
+
+	// Register an auth method
+	method := &ACLAuthMethod{
+		Name:     "okta",
+		Type:     "oidc",
+		TokenTTL: 8 * time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "https://example.okta.com",
+			OIDCClientID:        "nomad",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			Policies:            []string{"engineering"},
+		},
+	}
+	wm, err := am.Upsert(method, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// List the auth methods
+	result, qm, err := am.List(nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, result, 1)
+
+	// Query the auth method
+	out, qm, err := am.Info(method.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, method.TokenTTL, out.TokenTTL)
+	assert.Equal(t, method.Config.OIDCClientID, out.Config.OIDCClientID)
+
+	// Delete the auth method
+	wm, err = am.Delete(method.Name, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = am.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
+
+func TestACLBindingRules(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	br := c.ACLBindingRules()
+
+	// Register the JWT auth method of the binding rules
+	method := &ACLAuthMethod{
+		Name:     "ci",
+		Type:     "jwt",
+		TokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			JWKSURL:        "https://ci.example.com/.well-known/jwks",
+			BoundIssuer:    "https://ci.example.com",
+			BoundAudiences: []string{"nomad"},
+		},
+	}
+	_, err := c.ACLAuthMethods().Upsert(method, nil)
+	assert.Nil(t, err)
+
+	// Create a binding rule
+	rule := &ACLBindingRule{
+		AuthMethod: "ci",
+		Selector:   map[string]string{"ref": "refs/heads/main"},
+		BindType:   "role",
+		BindName:   "deploy-${project}",
+	}
+	out, wm, err := br.Create(rule, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+	assert.NotEmpty(t, out.ID)
+
+	// Update it
+	out.BindName = "deploy"
+	out, _, err = br.Update(out, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "deploy", out.BindName)
+
+	// List the binding rules of the auth method
+	result, qm, err := br.List("ci", nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, result, 1)
+
+	// Query the binding rule
+	info, qm, err := br.Info(out.ID, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, rule.Selector, info.Selector)
+
+	// Delete the binding rule
+	wm, err = br.Delete(out.ID, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	result, _, err = br.List("", nil)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
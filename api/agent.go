@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/url"
+	"strconv"
 )
 
 // Agent encapsulates an API client which talks to Nomad's
@@ -237,6 +239,78 @@ func (a *Agent) Health() (*AgentHealthResponse, error) {
 	return nil, fmt.Errorf("unable to unmarshal response with status %d: %v", resp.StatusCode, err)
 }
 
+// SelfHealth returns the health of the subsystems of the agent. The health
+// of unhealthy agents is returned without error.
+func (a *Agent) SelfHealth() (*AgentSelfHealth, error) {
+	req, err := a.client.newRequest("GET", "/v1/agent/self/health")
+	if err != nil {
+		return nil, err
+	}
+
+	var health AgentSelfHealth
+	_, resp, err := a.client.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Unhealthy agents return their health with an error status
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	if err == nil {
+		return &health, nil
+	}
+	return nil, fmt.Errorf("unable to unmarshal response with status %d: %v", resp.StatusCode, err)
+}
+
+// PprofOptions is used to configure the profiles collected from an agent.
+type PprofOptions struct {
+	// Seconds is the duration of CPU profiles and execution traces.
+	Seconds int
+
+	// Debug selects the format of other profiles. Zero returns the binary
+	// pprof format while higher values return text.
+	Debug int
+}
+
+// CPUProfile returns the CPU profile of the agent over the configured
+// duration.
+func (a *Agent) CPUProfile(opts PprofOptions, q *QueryOptions) ([]byte, error) {
+	return a.pprof("profile", opts, q)
+}
+
+// Trace returns an execution trace of the agent over the configured duration.
+func (a *Agent) Trace(opts PprofOptions, q *QueryOptions) ([]byte, error) {
+	return a.pprof("trace", opts, q)
+}
+
+// Lookup returns a named runtime profile of the agent, such as goroutine or
+// heap.
+func (a *Agent) Lookup(profile string, opts PprofOptions, q *QueryOptions) ([]byte, error) {
+	return a.pprof(profile, opts, q)
+}
+
+func (a *Agent) pprof(profile string, opts PprofOptions, q *QueryOptions) ([]byte, error) {
+	v := url.Values{}
+	if opts.Seconds > 0 {
+		v.Set("seconds", strconv.Itoa(opts.Seconds))
+	}
+	if opts.Debug > 0 {
+		v.Set("debug", strconv.Itoa(opts.Debug))
+	}
+
+	endpoint := "/v1/agent/pprof/" + profile
+	if len(v) > 0 {
+		endpoint += "?" + v.Encode()
+	}
+
+	body, err := a.client.rawQuery(endpoint, q)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
 // joinResponse is used to decode the response we get while
 // sending a member join request.
 type joinResponse struct {
@@ -298,6 +372,32 @@ type AgentHealthResponse struct {
 	Server *AgentHealth `json:"server,omitempty"`
 }
 
+// AgentSelfHealth is the health of the subsystems of an agent returned by
+// the SelfHealth endpoint.
+type AgentSelfHealth struct {
+	// Healthy is true if all the critical subsystems are healthy
+	Healthy bool
+
+	// Subsystems is the health of the subsystems by name, such as "raft",
+	// "serf" and "leadership" for servers and "servers", "heartbeat" and
+	// "driver.<name>" for clients
+	Subsystems map[string]*AgentSubsystemHealth
+}
+
+// AgentSubsystemHealth is the health of a subsystem of an agent.
+type AgentSubsystemHealth struct {
+	Healthy bool
+
+	// Critical is true if the subsystem counts towards the agent's health
+	Critical bool
+
+	// Message describes why the subsystem is unhealthy
+	Message string
+
+	// Details are the stats the health of the subsystem is based on
+	Details map[string]string
+}
+
 // AgentHealth describes the Client or Server's health in a Health request.
 type AgentHealth struct {
 	// Ok is false if the agent is unhealthy
@@ -257,3 +257,20 @@ func TestAgent_Health(t *testing.T) {
 	assert.Nil(err)
 	assert.True(health.Server.Ok)
 }
+
+func TestAgent_SelfHealth(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	a := c.Agent()
+
+	health, err := a.SelfHealth()
+	assert.Nil(err)
+	assert.True(health.Healthy)
+	assert.Contains(health.Subsystems, "raft")
+	if assert.Contains(health.Subsystems, "leadership") {
+		assert.True(health.Subsystems["leadership"].Healthy)
+		assert.True(health.Subsystems["leadership"].Critical)
+	}
+}
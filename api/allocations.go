@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 )
 
@@ -24,6 +25,7 @@ const (
 	AllocClientStatusComplete = "complete"
 	AllocClientStatusFailed   = "failed"
 	AllocClientStatusLost     = "lost"
+	AllocClientStatusUnknown  = "unknown"
 )
 
 // Allocations is used to query the alloc-related endpoints.
@@ -68,6 +70,23 @@ func (a *Allocations) Stats(alloc *Allocation, q *QueryOptions) (*AllocResourceU
 	return &resp, err
 }
 
+// StatsRange returns the latest resource usage of the allocation along with
+// the usage of each task collected between start and end. Clients only keep
+// the usage of the last minutes. A zero end is the current time.
+func (a *Allocations) StatsRange(alloc *Allocation, start, end time.Time, q *QueryOptions) (*AllocResourceUsage, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["start"] = strconv.FormatInt(start.UnixNano(), 10)
+	if !end.IsZero() {
+		q.Params["end"] = strconv.FormatInt(end.UnixNano(), 10)
+	}
+	return a.Stats(alloc, q)
+}
+
 func (a *Allocations) GC(alloc *Allocation, q *QueryOptions) error {
 	nodeClient, err := a.client.GetNodeClient(alloc.NodeID, q)
 	if err != nil {
@@ -79,6 +98,118 @@ func (a *Allocations) GC(alloc *Allocation, q *QueryOptions) error {
 	return err
 }
 
+// Restart restarts the tasks of the allocation in place. If req.WaitHealthy is
+// set, the request blocks until the allocation is healthy again or its healthy
+// deadline is reached.
+func (a *Allocations) Restart(alloc *Allocation, req *AllocRestartRequest, q *WriteOptions) (*AllocRestartResponse, error) {
+	if req == nil {
+		req = &AllocRestartRequest{}
+	}
+
+	var resp AllocRestartResponse
+	_, err := a.client.write("/v1/client/allocation/"+alloc.ID+"/restart", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AllocRestartRequest is used to restart the tasks of an allocation.
+type AllocRestartRequest struct {
+	// TaskName is the task to restart. All the tasks are restarted if it is
+	// empty.
+	TaskName string
+
+	// Ordered restarts the tasks one at a time, the tasks that aren't the
+	// leader of the group in the order they are defined and the leader task
+	// last. Each task must be running again before the next one is
+	// restarted.
+	Ordered bool
+
+	// WaitHealthy blocks the request until the restarted allocation is
+	// healthy or its healthy deadline is reached. Health is determined by the
+	// update stanza of the allocation's task group.
+	WaitHealthy bool
+}
+
+// AllocRestartResponse is the result of restarting an allocation.
+type AllocRestartResponse struct {
+	// Healthy is set if WaitHealthy was requested and the allocation became
+	// healthy after being restarted.
+	Healthy bool
+}
+
+// RunAction runs one of the task's actions inside the running allocation. The
+// action is run to completion before the response is returned.
+func (a *Allocations) RunAction(alloc *Allocation, task, action string, q *WriteOptions) (*AllocActionResponse, error) {
+	req := &AllocActionRequest{
+		Task:   task,
+		Action: action,
+	}
+
+	var resp AllocActionResponse
+	_, err := a.client.write("/v1/client/allocation/"+alloc.ID+"/action", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AllocActionRequest is used to run one of a task's actions.
+type AllocActionRequest struct {
+	// Task is the task defining the action and Action is its name.
+	Task   string
+	Action string
+}
+
+// AllocActionResponse is the result of running an action.
+type AllocActionResponse struct {
+	// Output is the output of the action's command.
+	Output string
+
+	// ExitCode is the exit code of the action's command.
+	ExitCode int
+}
+
+// Signal sends a signal to the running tasks of the allocation. If task is
+// set, only that task is signaled.
+func (a *Allocations) Signal(alloc *Allocation, task, signal string, q *WriteOptions) error {
+	req := &AllocSignalRequest{
+		Task:   task,
+		Signal: signal,
+	}
+	_, err := a.client.write("/v1/client/allocation/"+alloc.ID+"/signal", req, nil, q)
+	return err
+}
+
+// RescheduleNow reschedules a failed allocation without waiting for its
+// reschedule delay to elapse. It returns the ID of the evaluation that
+// reschedules the allocation.
+func (a *Allocations) RescheduleNow(alloc *Allocation, q *WriteOptions) (*AllocRescheduleNowResponse, *WriteMeta, error) {
+	var resp AllocRescheduleNowResponse
+	wm, err := a.client.write("/v1/allocation/"+alloc.ID+"/reschedule", nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// AllocRescheduleNowResponse is used to respond to a reschedule now request
+type AllocRescheduleNowResponse struct {
+	EvalID          string
+	EvalCreateIndex uint64
+}
+
+// AllocSignalRequest is used to send a signal to the tasks of an allocation.
+type AllocSignalRequest struct {
+	// Task is the task to signal. If empty, all running tasks of the
+	// allocation are signaled.
+	Task string
+
+	// Signal is the name of the signal to send, such as SIGHUP.
+	Signal string
+}
+
 // Allocation is used for serialization of allocations.
 type Allocation struct {
 	ID                    string
@@ -117,20 +248,38 @@ type Allocation struct {
 
 // AllocationMetric is used to deserialize allocation metrics.
 type AllocationMetric struct {
-	NodesEvaluated     int
-	NodesFiltered      int
-	NodesAvailable     map[string]int
-	ClassFiltered      map[string]int
-	ConstraintFiltered map[string]int
-	NodesExhausted     int
-	ClassExhausted     map[string]int
-	DimensionExhausted map[string]int
-	QuotaExhausted     []string
+	NodesEvaluated      int
+	NodesFiltered       int
+	NodesAvailable      map[string]int
+	ClassFiltered       map[string]int
+	ConstraintFiltered  map[string]int
+	NodesExhausted      int
+	ClassExhausted      map[string]int
+	DimensionExhausted  map[string]int
+	ExhaustedDimensions []*ExhaustedDimension
+	QuotaExhausted      []string
+	// ConcurrencyGroupHeldBy is the job running allocations in the
+	// concurrency group, which queued the placement
+	ConcurrencyGroupHeldBy string
 	// Deprecated, replaced with ScoreMetaData
 	Scores            map[string]float64
 	AllocationTime    time.Duration
 	CoalescedFailures int
 	ScoreMetaData     []*NodeScoreMeta
+	// FeasibilityCacheHits and FeasibilityCacheMisses count the nodes whose
+	// feasibility was or wasn't decided by the cached eligibility of their
+	// computed node class
+	FeasibilityCacheHits   int
+	FeasibilityCacheMisses int
+}
+
+// ExhaustedDimension is the number of nodes of a datacenter and node class
+// that were exhausted of a dimension while placing an allocation.
+type ExhaustedDimension struct {
+	Datacenter string
+	NodeClass  string
+	Dimension  string
+	Nodes      int
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -193,11 +342,15 @@ type AllocatedSharedResources struct {
 }
 
 type AllocatedCpuResources struct {
-	CpuShares int64
+	CpuShares     int64
+	ReservedCores []uint16
+	Realtime      bool
 }
 
 type AllocatedMemoryResources struct {
-	MemoryMB int64
+	MemoryMB     int64
+	HugePages2MB int64
+	HugePages1GB int64
 }
 
 // AllocIndexSort reverse sorts allocs by CreateIndex.
@@ -259,6 +412,31 @@ type RescheduleEvent struct {
 
 	// PrevNodeID is the node ID of the previous allocation
 	PrevNodeID string
+
+	// Delay is the reschedule delay associated with the attempt
+	Delay time.Duration
+}
+
+// AllocRescheduleStatus is the state of the reschedule tracker of the latest
+// allocation of a reschedule lineage.
+type AllocRescheduleStatus struct {
+	AllocID      string
+	TaskGroup    string
+	ClientStatus string
+
+	// Attempts is the number of reschedule attempts made within the interval
+	// of the reschedule policy
+	Attempts    int
+	MaxAttempts int
+	Unlimited   bool
+
+	Events []*RescheduleEvent
+
+	// NextRescheduleTime is the time on or after which a failed allocation is
+	// rescheduled, and Eligible whether it will be rescheduled at all
+	NextRescheduleTime time.Time
+	Eligible           bool
+	FollowupEvalID     string
 }
 
 // DesiredTransition is used to mark an allocation as having a desired state
@@ -272,6 +450,10 @@ type DesiredTransition struct {
 	// Reschedule is used to indicate that this allocation is eligible to be
 	// rescheduled.
 	Reschedule *bool
+
+	// ShutdownDelay is the minimum delay between deregistering the services
+	// of the allocation and killing its tasks when it is stopped.
+	ShutdownDelay *time.Duration
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -3,10 +3,12 @@ package api
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
@@ -50,11 +52,43 @@ type QueryOptions struct {
 	// If set, used as prefix for resource list searches
 	Prefix string
 
+	// PerPage is the maximum number of results returned by list queries that
+	// support pagination. Zero returns all the results.
+	PerPage int32
+
+	// NextToken resumes a paginated list query from the NextToken of the
+	// QueryMeta of the previous page.
+	NextToken string
+
 	// Set HTTP parameters on the query.
 	Params map[string]string
 
 	// AuthToken is the secret ID of an ACL token
 	AuthToken string
+
+	// ctx is the context of the request. Use Context and WithContext to
+	// manage it.
+	ctx context.Context
+}
+
+// Context returns the context of the query, or the background context if
+// none is set.
+func (o *QueryOptions) Context() context.Context {
+	if o != nil && o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a copy of the query options with the given context,
+// which cancels the request when done.
+func (o *QueryOptions) WithContext(ctx context.Context) *QueryOptions {
+	o2 := new(QueryOptions)
+	if o != nil {
+		*o2 = *o
+	}
+	o2.ctx = ctx
+	return o2
 }
 
 // WriteOptions are used to parameterize a write
@@ -68,6 +102,30 @@ type WriteOptions struct {
 
 	// AuthToken is the secret ID of an ACL token
 	AuthToken string
+
+	// ctx is the context of the request. Use Context and WithContext to
+	// manage it.
+	ctx context.Context
+}
+
+// Context returns the context of the write, or the background context if
+// none is set.
+func (o *WriteOptions) Context() context.Context {
+	if o != nil && o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+// WithContext returns a copy of the write options with the given context,
+// which cancels the request when done.
+func (o *WriteOptions) WithContext(ctx context.Context) *WriteOptions {
+	o2 := new(WriteOptions)
+	if o != nil {
+		*o2 = *o
+	}
+	o2.ctx = ctx
+	return o2
 }
 
 // QueryMeta is used to return meta data about a query
@@ -85,6 +143,10 @@ type QueryMeta struct {
 
 	// How long did the request take
 	RequestTime time.Duration
+
+	// NextToken is set by paginated list queries when more results are
+	// available. It is used as the NextToken of the query for the next page.
+	NextToken string
 }
 
 // WriteMeta is used to return meta data about a write
@@ -133,6 +195,78 @@ type Config struct {
 	// TLSConfig provides the various TLS related configurations for the http
 	// client
 	TLSConfig *TLSConfig
+
+	// RetryPolicy configures the retries of requests that fail with a
+	// connection error or a 5xx response. Requests are not retried if it is
+	// nil.
+	RetryPolicy *RetryPolicy
+
+	// RelayThroughServers sends the requests targeting a client node, such
+	// as the filesystem and logs requests, to the configured address instead
+	// of the HTTP address of the node. The servers relay them to the node,
+	// which is useful when the clients can't be reached directly.
+	RelayThroughServers bool
+}
+
+// RetryPolicy configures how requests that fail with a connection error or a
+// server error (5xx) are retried. The delay between attempts starts at
+// MinBackoff and doubles after each attempt, up to MaxBackoff. Requests whose
+// body can't be replayed and requests whose context is done are never
+// retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a failed request is retried.
+	MaxRetries int
+
+	// MinBackoff is the delay before the first retry.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between retries. Defaults to 5s.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns a retry policy that retries requests three
+// times, waiting between 250ms and 5s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: 250 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+	}
+}
+
+// backoff returns the delay before the given retry, starting at zero.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = DefaultRetryPolicy().MaxBackoff
+	}
+
+	d := p.MinBackoff
+	for i := 0; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// shouldRetry returns whether the request should be retried given the
+// outcome of its last attempt.
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, retry int) bool {
+	if p == nil || retry >= p.MaxRetries {
+		return false
+	}
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500
 }
 
 // ClientConfig copies the configuration with a new client address, region, and
@@ -152,6 +286,8 @@ func (c *Config) ClientConfig(region, address string, tlsEnabled bool) *Config {
 		HttpAuth:   c.HttpAuth,
 		WaitTime:   c.WaitTime,
 		TLSConfig:  c.TLSConfig.Copy(),
+
+		RetryPolicy: c.RetryPolicy,
 	}
 
 	// Update the tls server name for connecting to a client
@@ -256,6 +392,11 @@ func DefaultConfig() *Config {
 	if v := os.Getenv("NOMAD_TOKEN"); v != "" {
 		config.SecretID = v
 	}
+	if v := os.Getenv("NOMAD_RELAY_THROUGH_SERVERS"); v != "" {
+		if relay, err := strconv.ParseBool(v); err == nil {
+			config.RelayThroughServers = relay
+		}
+	}
 	return config
 }
 
@@ -379,14 +520,16 @@ func (c *Client) SetNamespace(namespace string) {
 }
 
 // GetNodeClient returns a new Client that will dial the specified node. If the
-// QueryOptions is set, its region will be used.
+// QueryOptions is set, its region will be used. If the client relays through
+// the servers, the client itself is returned instead.
 func (c *Client) GetNodeClient(nodeID string, q *QueryOptions) (*Client, error) {
 	return c.getNodeClientImpl(nodeID, -1, q, c.Nodes().Info)
 }
 
 // GetNodeClientWithTimeout returns a new Client that will dial the specified
 // node using the specified timeout. If the QueryOptions is set, its region will
-// be used.
+// be used. If the client relays through the servers, the client itself is
+// returned instead.
 func (c *Client) GetNodeClientWithTimeout(
 	nodeID string, timeout time.Duration, q *QueryOptions) (*Client, error) {
 	return c.getNodeClientImpl(nodeID, timeout, q, c.Nodes().Info)
@@ -407,6 +550,9 @@ func (c *Client) getNodeClientImpl(nodeID string, timeout time.Duration, q *Quer
 	if node.Status == "down" {
 		return nil, NodeDownErr
 	}
+	if c.config.RelayThroughServers {
+		return c, nil
+	}
 	if node.HTTPAddr == "" {
 		return nil, fmt.Errorf("http addr of node %q (%s) is not advertised", node.Name, nodeID)
 	}
@@ -447,6 +593,7 @@ type request struct {
 	token  string
 	body   io.Reader
 	obj    interface{}
+	ctx    context.Context
 }
 
 // setQueryOptions is used to annotate the request with
@@ -476,9 +623,16 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Prefix != "" {
 		r.params.Set("prefix", q.Prefix)
 	}
+	if q.PerPage != 0 {
+		r.params.Set("per_page", strconv.FormatInt(int64(q.PerPage), 10))
+	}
+	if q.NextToken != "" {
+		r.params.Set("next_token", q.NextToken)
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
+	r.ctx = q.ctx
 }
 
 // durToMsec converts a duration to a millisecond specified string
@@ -501,6 +655,7 @@ func (r *request) setWriteOptions(q *WriteOptions) {
 	if q.AuthToken != "" {
 		r.token = q.AuthToken
 	}
+	r.ctx = q.ctx
 }
 
 // toHTTP converts the request to an HTTP request
@@ -540,6 +695,9 @@ func (r *request) toHTTP() (*http.Request, error) {
 	req.URL.Host = r.url.Host
 	req.URL.Scheme = r.url.Scheme
 	req.Host = r.url.Host
+	if r.ctx != nil {
+		req = req.WithContext(r.ctx)
+	}
 	return req, nil
 }
 
@@ -604,7 +762,8 @@ func (m *multiCloser) Read(p []byte) (int, error) {
 	return m.reader.Read(p)
 }
 
-// doRequest runs a request with our client
+// doRequest runs a request with our client, retrying it according to the
+// retry policy of the client
 func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
 	req, err := r.toHTTP()
 	if err != nil {
@@ -612,6 +771,29 @@ func (c *Client) doRequest(r *request) (time.Duration, *http.Response, error) {
 	}
 	start := time.Now()
 	resp, err := c.config.httpClient.Do(req)
+	for retry := 0; c.config.RetryPolicy.shouldRetry(req, resp, err, retry); retry++ {
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return time.Now().Sub(start), nil, req.Context().Err()
+		case <-time.After(c.config.RetryPolicy.backoff(retry)):
+		}
+
+		// Replay the body of the request
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return time.Now().Sub(start), nil, err
+			}
+			req = req.WithContext(req.Context())
+			req.Body = body
+		}
+		resp, err = c.config.httpClient.Do(req)
+	}
 	diff := time.Now().Sub(start)
 
 	// If the response is compressed, we swap the body's reader.
@@ -732,6 +914,26 @@ func (c *Client) write(endpoint string, in, out interface{}, q *WriteOptions) (*
 	return wm, nil
 }
 
+// rawWrite is used to do a PUT request against an endpoint with the given
+// body, which is sent as is.
+func (c *Client) rawWrite(endpoint string, body io.Reader, q *WriteOptions) (*WriteMeta, error) {
+	r, err := c.newRequest("PUT", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(q)
+	r.body = body
+	rtt, resp, err := requireOK(c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+	return wm, nil
+}
+
 // delete is used to do a DELETE request against an endpoint
 // and serialize/deserialized using the standard Nomad conventions.
 func (c *Client) delete(endpoint string, out interface{}, q *WriteOptions) (*WriteMeta, error) {
@@ -782,6 +984,9 @@ func parseQueryMeta(resp *http.Response, q *QueryMeta) error {
 	default:
 		q.KnownLeader = false
 	}
+
+	// Parse the X-Nomad-NextToken
+	q.NextToken = header.Get("X-Nomad-NextToken")
 	return nil
 }
 
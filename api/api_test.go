@@ -1,18 +1,21 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/nomad/api/internal/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type configCallback func(c *Config)
@@ -134,6 +137,9 @@ func TestDefaultConfig_env(t *testing.T) {
 	os.Setenv("NOMAD_TOKEN", token)
 	defer os.Setenv("NOMAD_TOKEN", "")
 
+	os.Setenv("NOMAD_RELAY_THROUGH_SERVERS", "true")
+	defer os.Setenv("NOMAD_RELAY_THROUGH_SERVERS", "")
+
 	config := DefaultConfig()
 
 	if config.Address != url {
@@ -159,6 +165,10 @@ func TestDefaultConfig_env(t *testing.T) {
 	if config.SecretID != token {
 		t.Errorf("Expected %q to be %q", config.SecretID, token)
 	}
+
+	if !config.RelayThroughServers {
+		t.Errorf("Expected requests to be relayed through the servers")
+	}
 }
 
 func TestSetQueryOptions(t *testing.T) {
@@ -443,3 +453,116 @@ func TestClient_NodeClient(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_NodeClient_RelayThroughServers(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// The node doesn't advertise its HTTP address, as the client relays the
+	// requests through the servers
+	node := &Node{ID: "testID", Status: "ready"}
+	lookup := func(string, *QueryOptions) (*Node, *QueryMeta, error) {
+		return node, nil, nil
+	}
+
+	conf := DefaultConfig()
+	conf.RelayThroughServers = true
+	client, err := NewClient(conf)
+	require.NoError(err)
+
+	nodeClient, err := client.getNodeClientImpl("testID", -1, nil, lookup)
+	require.NoError(err)
+	require.True(nodeClient == client)
+
+	// Down nodes are still rejected
+	node.Status = "down"
+	_, err = client.getNodeClientImpl("testID", -1, nil, lookup)
+	require.Equal(NodeDownErr, err)
+}
+
+func TestClient_Context(t *testing.T) {
+	t.Parallel()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	conf := DefaultConfig()
+	conf.Address = ts.URL
+	client, err := NewClient(conf)
+	require.NoError(t, err)
+
+	// Canceling the context aborts the request
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	q := (&QueryOptions{Region: "global"}).WithContext(ctx)
+	require.Equal(t, ctx, q.Context())
+	require.Equal(t, "global", q.Region)
+
+	var out struct{}
+	_, err = client.query("/v1/jobs", &out, q)
+	require.Error(t, err)
+	require.Equal(t, context.DeadlineExceeded, ctx.Err())
+
+	// Options without a context use the background context
+	require.Equal(t, context.Background(), (&WriteOptions{}).Context())
+	require.Equal(t, context.Background(), (*QueryOptions)(nil).Context())
+}
+
+func TestClient_RetryPolicy(t *testing.T) {
+	t.Parallel()
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first two attempts, and check the body is replayed
+		var in map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		if atomic.AddInt32(&attempts, 1) <= 2 || in["Name"] != "example" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	conf := DefaultConfig()
+	conf.Address = ts.URL
+	conf.RetryPolicy = &RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	}
+	client, err := NewClient(conf)
+	require.NoError(t, err)
+
+	in := map[string]string{"Name": "example"}
+	_, err = client.write("/v1/jobs", in, nil, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, atomic.LoadInt32(&attempts))
+
+	// Requests fail once the retries are exhausted
+	atomic.StoreInt32(&attempts, -10)
+	_, err = client.write("/v1/jobs", in, nil, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "503")
+	require.EqualValues(t, -6, atomic.LoadInt32(&attempts))
+
+	// Requests are not retried without a retry policy
+	client.config.RetryPolicy = nil
+	atomic.StoreInt32(&attempts, 0)
+	_, err = client.write("/v1/jobs", in, nil, nil)
+	require.Error(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+	p := &RetryPolicy{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: time.Second,
+	}
+	require.Equal(t, 100*time.Millisecond, p.backoff(0))
+	require.Equal(t, 200*time.Millisecond, p.backoff(1))
+	require.Equal(t, 800*time.Millisecond, p.backoff(3))
+	require.Equal(t, time.Second, p.backoff(4))
+	require.Equal(t, time.Second, p.backoff(100))
+}
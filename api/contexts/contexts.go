@@ -8,6 +8,7 @@ const (
 	Deployments Context = "deployment"
 	Evals       Context = "evals"
 	Jobs        Context = "jobs"
+	JobTags     Context = "job_tags"
 	Nodes       Context = "nodes"
 	Namespaces  Context = "namespaces"
 	Quotas      Context = "quotas"
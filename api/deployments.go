@@ -65,6 +65,20 @@ func (d *Deployments) Fail(deploymentID string, q *WriteOptions) (*DeploymentUpd
 	return &resp, wm, nil
 }
 
+// Rollback is used to roll the job of the given deployment back to the latest
+// stable version that preceded the deployment. An active deployment is failed.
+func (d *Deployments) Rollback(deploymentID string, q *WriteOptions) (*DeploymentUpdateResponse, *WriteMeta, error) {
+	var resp DeploymentUpdateResponse
+	req := &DeploymentRollbackRequest{
+		DeploymentID: deploymentID,
+	}
+	wm, err := d.client.write("/v1/deployment/rollback/"+deploymentID, req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Pause is used to pause or unpause the given deployment.
 func (d *Deployments) Pause(deploymentID string, pause bool, q *WriteOptions) (*DeploymentUpdateResponse, *WriteMeta, error) {
 	var resp DeploymentUpdateResponse
@@ -177,6 +191,19 @@ type DeploymentState struct {
 	PlacedAllocs      int
 	HealthyAllocs     int
 	UnhealthyAllocs   int
+	StepAllocs        int
+	Progress          []*DeploymentProgress
+}
+
+// DeploymentProgress is the state of a task group of a deployment after one of
+// its allocations was placed or had its health set.
+type DeploymentProgress struct {
+	Time              time.Time
+	Index             uint64
+	PlacedAllocs      int
+	HealthyAllocs     int
+	UnhealthyAllocs   int
+	RequireProgressBy time.Time
 }
 
 // DeploymentIndexSort is a wrapper to sort deployments by CreateIndex. We
@@ -257,6 +284,12 @@ type DeploymentFailRequest struct {
 	WriteRequest
 }
 
+// DeploymentRollbackRequest is used to roll back a particular deployment
+type DeploymentRollbackRequest struct {
+	DeploymentID string
+	WriteRequest
+}
+
 // SingleDeploymentResponse is used to respond with a single deployment
 type SingleDeploymentResponse struct {
 	Deployment *Deployment
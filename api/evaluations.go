@@ -52,6 +52,16 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 	return resp, qm, nil
 }
 
+// Requeue is used to enqueue a blocked evaluation without waiting for node
+// capacity changes.
+func (e *Evaluations) Requeue(evalID string, q *WriteOptions) (*WriteMeta, error) {
+	wm, err := e.client.write("/v1/evaluation/"+evalID+"/requeue", nil, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
 // Evaluation is used to serialize an evaluation.
 type Evaluation struct {
 	ID                   string
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultEventStreamHeartbeatTimeout is how long an event stream waits
+	// for a frame before treating its connection as dead. Servers send
+	// heartbeats on idle streams every 10 seconds.
+	DefaultEventStreamHeartbeatTimeout = 30 * time.Second
+)
+
+// Topic is the kind of object an event is about.
+type Topic string
+
+const (
+	TopicDeployment Topic = "Deployment"
+	TopicAllocation Topic = "Allocation"
+	TopicJob        Topic = "Job"
+	TopicNode       Topic = "Node"
+
+	// TopicAll subscribes to the events of every topic.
+	TopicAll Topic = "*"
+)
+
+// Events is the batch of events of a Raft index. Err is set on the last value
+// sent by a stream if it ended because the servers rejected it.
+type Events struct {
+	Index  uint64
+	Events []Event
+	Err    error `json:"-"`
+}
+
+// IsHeartbeat returns whether the frame is a heartbeat of an idle stream.
+func (e *Events) IsHeartbeat() bool {
+	return e.Index == 0 && len(e.Events) == 0 && e.Err == nil
+}
+
+// Event is a change to an object of the state store of the servers.
+type Event struct {
+	Topic      Topic
+	Type       string
+	Key        string
+	Namespace  string
+	FilterKeys []string
+	Index      uint64
+
+	// Payload is the object after the change, keyed by the name of its type,
+	// such as "Job". Use the methods of the event to decode it.
+	Payload map[string]interface{}
+}
+
+// Job returns the job of the event, or nil if the event has none, such as
+// when the job was purged.
+func (e *Event) Job() (*Job, error) {
+	var job *Job
+	if err := e.decodePayload("Job", &job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// JobSummary returns the summary of the job of the event. It is only set for
+// JobCompleted events.
+func (e *Event) JobSummary() (*JobSummary, error) {
+	var summary *JobSummary
+	if err := e.decodePayload("Summary", &summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Allocation returns the allocation of the event, or nil if it has none. The
+// job of the allocation is not included.
+func (e *Event) Allocation() (*Allocation, error) {
+	var alloc *Allocation
+	if err := e.decodePayload("Allocation", &alloc); err != nil {
+		return nil, err
+	}
+	return alloc, nil
+}
+
+// Deployment returns the deployment of the event, or nil if it has none, such
+// as when the deployment was deleted.
+func (e *Event) Deployment() (*Deployment, error) {
+	var d *Deployment
+	if err := e.decodePayload("Deployment", &d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Node returns the node of the event, or nil if it has none, such as when the
+// node was deregistered.
+func (e *Event) Node() (*Node, error) {
+	var node *Node
+	if err := e.decodePayload("Node", &node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// decodePayload decodes the value of the key of the payload into out.
+func (e *Event) decodePayload(key string, out interface{}) error {
+	raw, ok := e.Payload[key]
+	if !ok || raw == nil {
+		return nil
+	}
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, out)
+}
+
+// EventStream is used to stream the events of the servers.
+type EventStream struct {
+	client *Client
+
+	// HeartbeatTimeout is how long the stream waits for an event or a
+	// heartbeat before reconnecting.
+	HeartbeatTimeout time.Duration
+}
+
+// EventStream returns a handle on the event stream endpoint.
+func (c *Client) EventStream() *EventStream {
+	return &EventStream{
+		client:           c,
+		HeartbeatTimeout: DefaultEventStreamHeartbeatTimeout,
+	}
+}
+
+// Stream subscribes to the events of the topics after the index, where the
+// key "*" matches every event of a topic and an index of zero only streams
+// new events. The error of the first connection is returned. Once
+// established, connections that fail or stop receiving heartbeats are
+// re-established with an exponential backoff, resuming after the index of
+// the last events received. The channel is closed once the context is done
+// or after sending Events with Err set if the servers rejected reconnecting.
+func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, index uint64,
+	q *QueryOptions) (<-chan *Events, error) {
+
+	conn, err := e.connect(ctx, topics, index, q)
+	if err != nil {
+		return nil, err
+	}
+
+	eventsCh := make(chan *Events, 10)
+	go e.run(ctx, conn, eventsCh, topics, index, q)
+	return eventsCh, nil
+}
+
+// eventConn is an established event stream connection.
+type eventConn struct {
+	body   io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *eventConn) close() {
+	c.cancel()
+	c.body.Close()
+}
+
+// connect opens an event stream connection resuming after the index.
+func (e *EventStream) connect(ctx context.Context, topics map[Topic][]string, index uint64,
+	q *QueryOptions) (*eventConn, error) {
+
+	connCtx, cancel := context.WithCancel(ctx)
+	r, err := e.client.newRequest("GET", "/v1/event/stream")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r.setQueryOptions(q.WithContext(connCtx))
+	for topic, keys := range topics {
+		for _, key := range keys {
+			r.params.Add("topic", string(topic)+":"+key)
+		}
+	}
+	if index != 0 {
+		r.params.Set("index", strconv.FormatUint(index, 10))
+	}
+
+	_, resp, err := requireOK(e.client.doRequest(r))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &eventConn{body: resp.Body, cancel: cancel}, nil
+}
+
+// run reads the events of the connection and reconnects until the context is
+// done or the servers reject the stream.
+func (e *EventStream) run(ctx context.Context, conn *eventConn, eventsCh chan<- *Events,
+	topics map[Topic][]string, index uint64, q *QueryOptions) {
+
+	defer close(eventsCh)
+	backoff := watchMinBackoff
+
+	for {
+		var received bool
+		index, received = e.read(ctx, conn, eventsCh, index)
+		if received {
+			backoff = watchMinBackoff
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > watchMaxBackoff {
+				backoff = watchMaxBackoff
+			}
+
+			var err error
+			conn, err = e.connect(ctx, topics, index, q)
+			if err == nil {
+				break
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Streams the servers rejected won't succeed when retried
+			if isClientError(err) {
+				select {
+				case eventsCh <- &Events{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}
+}
+
+// read sends the events of the connection until it fails or no frame is
+// received within the heartbeat timeout. It returns the index of the last
+// events sent and whether any frame was received.
+func (e *EventStream) read(ctx context.Context, conn *eventConn, eventsCh chan<- *Events,
+	index uint64) (uint64, bool) {
+
+	defer conn.close()
+
+	timeout := e.HeartbeatTimeout
+	if timeout <= 0 {
+		timeout = DefaultEventStreamHeartbeatTimeout
+	}
+
+	// Closing the connection fails the pending read
+	timer := time.AfterFunc(timeout, conn.cancel)
+	defer timer.Stop()
+
+	dec := json.NewDecoder(conn.body)
+	dec.UseNumber()
+
+	received := false
+	for {
+		var events Events
+		if err := dec.Decode(&events); err != nil {
+			return index, received
+		}
+		received = true
+
+		if !events.IsHeartbeat() {
+			// Waiting on the consumer doesn't count against the timeout
+			timer.Stop()
+			select {
+			case eventsCh <- &events:
+			case <-ctx.Done():
+				return index, received
+			}
+			index = events.Index
+		}
+		timer.Reset(timeout)
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var mu sync.Mutex
+	var indexes []string
+	conns := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		conns++
+		conn := conns
+		indexes = append(indexes, r.URL.Query().Get("index"))
+		mu.Unlock()
+
+		flusher := w.(http.Flusher)
+		switch conn {
+		case 1:
+			// Send an event and drop the connection
+			w.Write([]byte("{}\n"))
+			w.Write([]byte(`{"Index":5,"Events":[{"Topic":"Job","Type":"JobRegistered","Key":"example","Index":5,"Payload":{"Job":{"ID":"example","Priority":50}}}]}` + "\n"))
+		case 2:
+			// Send an event and stop sending heartbeats
+			w.Write([]byte(`{"Index":6,"Events":[{"Topic":"Node","Type":"NodeRegistration","Key":"n1","Index":6,"Payload":{"Node":{"ID":"n1","Status":"ready"}}}]}` + "\n"))
+			flusher.Flush()
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Permission denied"))
+		}
+	}))
+	defer ts.Close()
+
+	conf := DefaultConfig()
+	conf.Address = ts.URL
+	client, err := NewClient(conf)
+	require.NoError(err)
+
+	stream := client.EventStream()
+	stream.HeartbeatTimeout = 200 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	topics := map[Topic][]string{TopicJob: {"example"}, TopicNode: {"*"}}
+	eventsCh, err := stream.Stream(ctx, topics, 0, nil)
+	require.NoError(err)
+
+	// Payloads are decoded into their types
+	events := <-eventsCh
+	require.NoError(events.Err)
+	require.Equal(uint64(5), events.Index)
+	job, err := events.Events[0].Job()
+	require.NoError(err)
+	require.Equal("example", *job.ID)
+	require.Equal(50, *job.Priority)
+	node, err := events.Events[0].Node()
+	require.NoError(err)
+	require.Nil(node)
+
+	// The stream reconnects after the last index
+	events = <-eventsCh
+	require.NoError(events.Err)
+	require.Equal(uint64(6), events.Index)
+	node, err = events.Events[0].Node()
+	require.NoError(err)
+	require.Equal("n1", node.ID)
+
+	// Missing heartbeats reconnect and rejected streams end
+	events, ok := <-eventsCh
+	require.True(ok)
+	require.Error(events.Err)
+	require.Contains(events.Err.Error(), "403")
+	_, ok = <-eventsCh
+	require.False(ok)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal([]string{"", "5", "6"}, indexes)
+}
+
+func TestEventStream_ConnectError(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, []string{"Job:*"}, r.URL.Query()["topic"])
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	conf := DefaultConfig()
+	conf.Address = ts.URL
+	client, err := NewClient(conf)
+	require.NoError(t, err)
+
+	_, err = client.EventStream().Stream(context.Background(), map[Topic][]string{TopicJob: {"*"}}, 0, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "400")
+}
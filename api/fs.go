@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
+	"net/url"
+	"os"
 	"strconv"
 	"sync"
 	"time"
@@ -159,6 +163,82 @@ func (a *AllocFS) Cat(alloc *Allocation, path string, q *QueryOptions) (io.ReadC
 	return r, nil
 }
 
+// Archive is used to read a tar archive of the directory at the given path in
+// an allocation directory. If checksum is set, a manifest of the SHA-256
+// checksums of the files is appended to the archive as "SHA256SUMS".
+func (a *AllocFS) Archive(alloc *Allocation, path string, checksum bool, q *QueryOptions) (io.ReadCloser, error) {
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+
+	q.Params["path"] = path
+	q.Params["checksum"] = strconv.FormatBool(checksum)
+	reqPath := fmt.Sprintf("/v1/client/fs/archive/%s", alloc.ID)
+	r, err := nodeClient.rawQuery(reqPath, q)
+	if err != nil {
+		// There was a networking error when talking directly to the client.
+		if _, ok := err.(net.Error); !ok {
+			return nil, err
+		}
+
+		// Try via the server
+		r, err = a.client.rawQuery(reqPath, q)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Write is used to write the content of the reader to the file at the given
+// path in an allocation directory. The file and its parent directories are
+// created if needed, using the given mode for the file.
+func (a *AllocFS) Write(alloc *Allocation, path string, mode os.FileMode, r io.Reader, q *WriteOptions) error {
+	var nq *QueryOptions
+	if q != nil {
+		nq = &QueryOptions{Region: q.Region, Namespace: q.Namespace, AuthToken: q.AuthToken}
+	}
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, nq)
+	if err != nil {
+		return err
+	}
+
+	// Read the content so it can be sent again if the request falls back to
+	// the server
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	v := url.Values{}
+	v.Set("path", path)
+	v.Set("mode", strconv.FormatUint(uint64(mode.Perm()), 8))
+	reqPath := fmt.Sprintf("/v1/client/fs/write/%s?%s", alloc.ID, v.Encode())
+
+	if _, err := nodeClient.rawWrite(reqPath, bytes.NewReader(data), q); err != nil {
+		// There was a networking error when talking directly to the client.
+		if _, ok := err.(net.Error); !ok {
+			return err
+		}
+
+		// Try via the server
+		if _, err := a.client.rawWrite(reqPath, bytes.NewReader(data), q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
@@ -248,7 +328,7 @@ func (a *AllocFS) Stream(alloc *Allocation, path, origin string, offset int64,
 // * allocation: the allocation to stream from.
 // * follow: Whether the logs should be followed.
 // * task: the tasks name to stream logs for.
-// * logType: Either "stdout" or "stderr"
+// * logType: Either "stdout", "stderr" or "eventlog"
 // * origin: Either "start" or "end" and defines from where the offset is applied.
 // * offset: The offset to start streaming data at.
 // * cancel: A channel that when closed, streaming will end.
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorhill/cronexpr"
@@ -66,8 +67,23 @@ func (j *Jobs) ParseHCL(jobHCL string, canonicalize bool) (*Job, error) {
 }
 
 func (j *Jobs) Validate(job *Job, q *WriteOptions) (*JobValidateResponse, *WriteMeta, error) {
+	return j.ValidateOpts(job, nil, q)
+}
+
+// ValidateOptions is used to pass through job validation parameters
+type ValidateOptions struct {
+	// Admission additionally runs the checks that depend on the state of the
+	// cluster, such as whether any node can satisfy the job's constraints.
+	Admission bool
+}
+
+// ValidateOpts is used to validate a job with the given options.
+func (j *Jobs) ValidateOpts(job *Job, opts *ValidateOptions, q *WriteOptions) (*JobValidateResponse, *WriteMeta, error) {
 	var resp JobValidateResponse
 	req := &JobValidateRequest{Job: job}
+	if opts != nil {
+		req.Admission = opts.Admission
+	}
 	if q != nil {
 		req.WriteRequest = WriteRequest{Region: q.Region}
 	}
@@ -80,6 +96,18 @@ type RegisterOptions struct {
 	EnforceIndex   bool
 	ModifyIndex    uint64
 	PolicyOverride bool
+
+	// Signature is the base64 encoded detached signature of the job, as
+	// produced by "nomad job sign".
+	Signature string
+
+	// Submission is the job file the job was parsed from, stored along with
+	// the job version.
+	Submission *JobSubmission
+
+	// OverrideMinimumVersion allows registering a job matching a version
+	// older than the minimum version the job is pinned to.
+	OverrideMinimumVersion bool
 }
 
 // Register is used to register a new job. It returns the ID
@@ -109,6 +137,9 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		if opts.PolicyOverride {
 			req.PolicyOverride = true
 		}
+		req.Signature = opts.Signature
+		req.Submission = opts.Submission
+		req.OverrideMinimumVersion = opts.OverrideMinimumVersion
 	}
 
 	var resp JobRegisterResponse
@@ -130,11 +161,140 @@ func (j *Jobs) List(q *QueryOptions) ([]*JobListStub, *QueryMeta, error) {
 	return resp, qm, nil
 }
 
+// DispatchedJobsFilter filters the jobs dispatched from a parameterized job.
+type DispatchedJobsFilter struct {
+	// Meta lists only the jobs with all of the given meta values
+	Meta map[string]string
+
+	// PayloadHash lists only the jobs whose payload has the given hex encoded
+	// SHA-256 hash
+	PayloadHash string
+
+	// Status lists only the jobs with the given status
+	Status string
+}
+
+// Dispatched is used to list the jobs dispatched from the given parameterized
+// job that match the filter.
+func (j *Jobs) Dispatched(parentID string, filter *DispatchedJobsFilter, q *QueryOptions) ([]*JobListStub, *QueryMeta, error) {
+	var qo QueryOptions
+	if q != nil {
+		qo = *q
+	}
+	params := make(map[string]string, len(qo.Params)+3)
+	for k, v := range qo.Params {
+		params[k] = v
+	}
+	params["parent_id"] = parentID
+	if filter != nil {
+		for k, v := range filter.Meta {
+			params["meta."+k] = v
+		}
+		if filter.PayloadHash != "" {
+			params["payload_hash"] = filter.PayloadHash
+		}
+		if filter.Status != "" {
+			params["status"] = filter.Status
+		}
+	}
+	qo.Params = params
+	return j.List(&qo)
+}
+
+// Tagged is used to list the jobs with all of the given tags.
+func (j *Jobs) Tagged(tags []string, q *QueryOptions) ([]*JobListStub, *QueryMeta, error) {
+	var qo QueryOptions
+	if q != nil {
+		qo = *q
+	}
+	params := make(map[string]string, len(qo.Params)+1)
+	for k, v := range qo.Params {
+		params[k] = v
+	}
+	params["tags"] = strings.Join(tags, ",")
+	qo.Params = params
+	return j.List(&qo)
+}
+
 // PrefixList is used to list all existing jobs that match the prefix.
 func (j *Jobs) PrefixList(prefix string) ([]*JobListStub, *QueryMeta, error) {
 	return j.List(&QueryOptions{Prefix: prefix})
 }
 
+// Iterate returns an iterator over the jobs matching the query options. The
+// jobs are listed a page of PerPage jobs at a time, as they are consumed.
+func (j *Jobs) Iterate(q *QueryOptions) *JobIterator {
+	it := &JobIterator{jobs: j}
+	if q != nil {
+		it.q = *q
+	}
+	return it
+}
+
+// JobIterator iterates over the pages of a job list query. Next must be
+// called before each call to Job:
+//
+//	it := client.Jobs().Iterate(&api.QueryOptions{PerPage: 100})
+//	for it.Next() {
+//		job := it.Job()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type JobIterator struct {
+	jobs *Jobs
+	q    QueryOptions
+	meta *QueryMeta
+	page []*JobListStub
+	job  *JobListStub
+	done bool
+	err  error
+}
+
+// Next advances the iterator to the next job, querying the next page when
+// the current one is consumed. It returns false when there are no more jobs
+// or the query failed.
+func (it *JobIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			it.job = nil
+			return false
+		}
+
+		page, qm, err := it.jobs.List(&it.q)
+		if err != nil {
+			it.err = err
+			continue
+		}
+		it.meta = qm
+		it.page = page
+
+		// Later pages must not block on the index of the first one
+		it.q.WaitIndex = 0
+		it.q.NextToken = qm.NextToken
+		it.done = qm.NextToken == ""
+	}
+
+	it.job, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Job returns the current job.
+func (it *JobIterator) Job() *JobListStub {
+	return it.job
+}
+
+// Meta returns the query meta data of the last queried page.
+func (it *JobIterator) Meta() *QueryMeta {
+	return it.meta
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *JobIterator) Err() error {
+	return it.err
+}
+
 // Info is used to retrieve information about a particular
 // job given its unique ID.
 func (j *Jobs) Info(jobID string, q *QueryOptions) (*Job, *QueryMeta, error) {
@@ -157,6 +317,18 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Submission is used to retrieve the job file a version of the job was
+// registered with. An error is returned if the version was registered without
+// one.
+func (j *Jobs) Submission(jobID string, version uint64, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	var resp JobSubmission
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/submission?version=%d", jobID, version), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -212,12 +384,46 @@ func (j *Jobs) Evaluations(jobID string, q *QueryOptions) ([]*Evaluation, *Query
 	return resp, qm, nil
 }
 
+// RescheduleStatus is used to query the reschedule tracker state of the latest
+// allocation of each reschedule lineage of a job that has failed or been
+// rescheduled.
+func (j *Jobs) RescheduleStatus(jobID string, q *QueryOptions) ([]*AllocRescheduleStatus, *QueryMeta, error) {
+	var resp []*AllocRescheduleStatus
+	qm, err := j.client.query("/v1/job/"+jobID+"/reschedules", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 // Deregister is used to remove an existing job. If purge is set to true, the job
 // is deregistered and purged from the system versus still being queryable and
 // eventually GC'ed from the system. Most callers should not specify purge.
 func (j *Jobs) Deregister(jobID string, purge bool, q *WriteOptions) (string, *WriteMeta, error) {
+	return j.DeregisterOpts(jobID, &DeregisterOptions{Purge: purge}, q)
+}
+
+// DeregisterOptions is used to pass through job deregistration parameters
+type DeregisterOptions struct {
+	// Purge purges the job from the system instead of just marking it as
+	// stopped.
+	Purge bool
+
+	// ShutdownDelay, if set, is the minimum delay between deregistering the
+	// services of the running allocations of the job and killing their
+	// tasks, giving load balancers time to drain connections.
+	ShutdownDelay time.Duration
+}
+
+// DeregisterOpts is used to remove an existing job with the given options.
+func (j *Jobs) DeregisterOpts(jobID string, opts *DeregisterOptions, q *WriteOptions) (string, *WriteMeta, error) {
+	endpoint := fmt.Sprintf("/v1/job/%v?purge=%t", jobID, opts.Purge)
+	if opts.ShutdownDelay > 0 {
+		endpoint += "&shutdown_delay=" + opts.ShutdownDelay.String()
+	}
+
 	var resp JobDeregisterResponse
-	wm, err := j.client.delete(fmt.Sprintf("/v1/job/%v?purge=%t", jobID, purge), &resp, q)
+	wm, err := j.client.delete(endpoint, &resp, q)
 	if err != nil {
 		return "", nil, err
 	}
@@ -250,6 +456,17 @@ func (j *Jobs) EvaluateWithOpts(jobID string, opts EvalOptions, q *WriteOptions)
 	return resp.EvalID, wm, nil
 }
 
+// Bulk is used to stop, run or force-evaluate all the jobs of the namespace
+// matching the filter of the request.
+func (j *Jobs) Bulk(req *JobsBulkRequest, q *WriteOptions) (*JobsBulkResponse, *WriteMeta, error) {
+	var resp JobsBulkResponse
+	wm, err := j.client.write("/v1/jobs/bulk", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // PeriodicForce spawns a new instance of the periodic job and returns the eval ID
 func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta, error) {
 	var resp periodicForceResponse
@@ -304,11 +521,31 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
+	return j.DispatchOpts(jobID, &DispatchOptions{Meta: meta, Payload: payload}, q)
+}
+
+// DispatchOptions is used to pass through job dispatch parameters
+type DispatchOptions struct {
+	Meta    map[string]string
+	Payload []byte
+
+	// IdempotencyToken dispatches the job at most once. If a job was already
+	// dispatched with the same token, it is returned instead.
+	IdempotencyToken string
+}
+
+// DispatchOpts is used to dispatch a parameterized job with the given
+// options.
+func (j *Jobs) DispatchOpts(jobID string, opts *DispatchOptions,
+	q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
 	var resp JobDispatchResponse
 	req := &JobDispatchRequest{
-		JobID:   jobID,
-		Meta:    meta,
-		Payload: payload,
+		JobID: jobID,
+	}
+	if opts != nil {
+		req.Meta = opts.Meta
+		req.Payload = opts.Payload
+		req.IdempotencyToken = opts.IdempotencyToken
 	}
 	wm, err := j.client.write("/v1/job/"+jobID+"/dispatch", req, &resp, q)
 	if err != nil {
@@ -323,11 +560,34 @@ func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 func (j *Jobs) Revert(jobID string, version uint64, enforcePriorVersion *uint64,
 	q *WriteOptions) (*JobRegisterResponse, *WriteMeta, error) {
 
+	opts := RevertOptions{EnforcePriorVersion: enforcePriorVersion}
+	return j.RevertOpts(jobID, version, &opts, q)
+}
+
+// RevertOptions is used to pass through job revert parameters
+type RevertOptions struct {
+	// EnforcePriorVersion if set will enforce that the job is at the given
+	// version before reverting.
+	EnforcePriorVersion *uint64
+
+	// OverrideMinimumVersion allows reverting to a version older than the
+	// minimum version the job is pinned to.
+	OverrideMinimumVersion bool
+}
+
+// RevertOpts is used to revert a job to a prior version, using the given
+// revert options.
+func (j *Jobs) RevertOpts(jobID string, version uint64, opts *RevertOptions,
+	q *WriteOptions) (*JobRegisterResponse, *WriteMeta, error) {
+
 	var resp JobRegisterResponse
 	req := &JobRevertRequest{
-		JobID:               jobID,
-		JobVersion:          version,
-		EnforcePriorVersion: enforcePriorVersion,
+		JobID:      jobID,
+		JobVersion: version,
+	}
+	if opts != nil {
+		req.EnforcePriorVersion = opts.EnforcePriorVersion
+		req.OverrideMinimumVersion = opts.OverrideMinimumVersion
 	}
 	wm, err := j.client.write("/v1/job/"+jobID+"/revert", req, &resp, q)
 	if err != nil {
@@ -353,6 +613,24 @@ func (j *Jobs) Stable(jobID string, version uint64, stable bool,
 	return &resp, wm, nil
 }
 
+// PinVersion is used to pin a job to a minimum version. Registering a job
+// matching an older version is then rejected unless overridden. Pinning the
+// version zero unpins the job.
+func (j *Jobs) PinVersion(jobID string, version uint64,
+	q *WriteOptions) (*JobPinVersionResponse, *WriteMeta, error) {
+
+	var resp JobPinVersionResponse
+	req := &JobPinVersionRequest{
+		JobID:      jobID,
+		JobVersion: version,
+	}
+	wm, err := j.client.write("/v1/job/"+jobID+"/pin-version", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // periodicForceResponse is used to deserialize a force response
 type periodicForceResponse struct {
 	EvalID string
@@ -360,14 +638,33 @@ type periodicForceResponse struct {
 
 // UpdateStrategy defines a task groups update strategy.
 type UpdateStrategy struct {
-	Stagger          *time.Duration `mapstructure:"stagger"`
-	MaxParallel      *int           `mapstructure:"max_parallel"`
-	HealthCheck      *string        `mapstructure:"health_check"`
-	MinHealthyTime   *time.Duration `mapstructure:"min_healthy_time"`
-	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline"`
-	ProgressDeadline *time.Duration `mapstructure:"progress_deadline"`
-	AutoRevert       *bool          `mapstructure:"auto_revert"`
-	Canary           *int           `mapstructure:"canary"`
+	Stagger          *time.Duration     `mapstructure:"stagger"`
+	MaxParallel      *int               `mapstructure:"max_parallel"`
+	HealthCheck      *string            `mapstructure:"health_check"`
+	MinHealthyTime   *time.Duration     `mapstructure:"min_healthy_time"`
+	HealthyDeadline  *time.Duration     `mapstructure:"healthy_deadline"`
+	ProgressDeadline *time.Duration     `mapstructure:"progress_deadline"`
+	AutoRevert       *bool              `mapstructure:"auto_revert"`
+	Canary           *int               `mapstructure:"canary"`
+	ManualStep       *bool              `mapstructure:"manual_step"`
+	ProgressWebhook  *DeploymentWebhook `mapstructure:"progress_webhook"`
+}
+
+// DeploymentWebhook is an endpoint notified of the progress of deployments.
+type DeploymentWebhook struct {
+	URL *string `mapstructure:"url"`
+}
+
+func (w *DeploymentWebhook) Copy() *DeploymentWebhook {
+	if w == nil {
+		return nil
+	}
+
+	copy := new(DeploymentWebhook)
+	if w.URL != nil {
+		copy.URL = stringToPtr(*w.URL)
+	}
+	return copy
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -382,6 +679,7 @@ func DefaultUpdateStrategy() *UpdateStrategy {
 		ProgressDeadline: timeToPtr(10 * time.Minute),
 		AutoRevert:       boolToPtr(false),
 		Canary:           intToPtr(0),
+		ManualStep:       boolToPtr(false),
 	}
 }
 
@@ -424,6 +722,12 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.Canary = intToPtr(*u.Canary)
 	}
 
+	if u.ManualStep != nil {
+		copy.ManualStep = boolToPtr(*u.ManualStep)
+	}
+
+	copy.ProgressWebhook = u.ProgressWebhook.Copy()
+
 	return copy
 }
 
@@ -463,6 +767,14 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 	if o.Canary != nil {
 		u.Canary = intToPtr(*o.Canary)
 	}
+
+	if o.ManualStep != nil {
+		u.ManualStep = boolToPtr(*o.ManualStep)
+	}
+
+	if o.ProgressWebhook != nil {
+		u.ProgressWebhook = o.ProgressWebhook.Copy()
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -499,6 +811,10 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.Canary == nil {
 		u.Canary = d.Canary
 	}
+
+	if u.ManualStep == nil {
+		u.ManualStep = d.ManualStep
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -539,6 +855,14 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.ManualStep != nil && *u.ManualStep {
+		return false
+	}
+
+	if u.ProgressWebhook != nil {
+		return false
+	}
+
 	return true
 }
 
@@ -608,8 +932,43 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
 	Payload      string
-	MetaRequired []string `mapstructure:"meta_required"`
-	MetaOptional []string `mapstructure:"meta_optional"`
+	MetaRequired []string              `mapstructure:"meta_required"`
+	MetaOptional []string              `mapstructure:"meta_optional"`
+	MetaConfigs  []*DispatchMetaConfig `mapstructure:"meta"`
+}
+
+// DispatchMetaConfig validates the value of a metadata key given when
+// dispatching a parameterized job.
+type DispatchMetaConfig struct {
+	Key          string
+	Type         string
+	Enum         []string
+	Regex        string
+	RequiredWith []string `mapstructure:"required_with"`
+}
+
+// JobVersionRetention configures how many historic versions of a job are
+// kept.
+type JobVersionRetention struct {
+	Count int
+	Age   time.Duration
+}
+
+// JobPrefetch is a hint for the clients likely to run a job to fetch the
+// images and artifacts of its tasks ahead of its placements.
+type JobPrefetch struct {
+	Images    *bool
+	Artifacts *bool
+}
+
+// Canonicalize prefetches both the images and artifacts by default.
+func (p *JobPrefetch) Canonicalize() {
+	if p.Images == nil {
+		p.Images = boolToPtr(true)
+	}
+	if p.Artifacts == nil {
+		p.Artifacts = boolToPtr(true)
+	}
 }
 
 // Job is used to serialize a job.
@@ -624,6 +983,7 @@ type Job struct {
 	Priority          *int
 	AllAtOnce         *bool `mapstructure:"all_at_once"`
 	Datacenters       []string
+	NodePool          *string `mapstructure:"node_pool"`
 	Constraints       []*Constraint
 	Affinities        []*Affinity
 	TaskGroups        []*TaskGroup
@@ -636,7 +996,11 @@ type Job struct {
 	Reschedule        *ReschedulePolicy
 	Migrate           *MigrateStrategy
 	Meta              map[string]string
-	VaultToken        *string `mapstructure:"vault_token"`
+	Tags              []string
+	ConcurrencyGroup  *string `mapstructure:"concurrency_group"`
+	Prefetch          *JobPrefetch
+	VersionRetention  *JobVersionRetention `mapstructure:"version_retention"`
+	VaultToken        *string              `mapstructure:"vault_token"`
 	Status            *string
 	StatusDescription *string
 	Stable            *bool
@@ -645,6 +1009,14 @@ type Job struct {
 	CreateIndex       *uint64
 	ModifyIndex       *uint64
 	JobModifyIndex    *uint64
+
+	// MinimumVersion is the version the job is pinned to, if any.
+	MinimumVersion uint64 `json:",omitempty"`
+
+	// DispatchIdempotencyToken and DispatchPayloadHash are set on dispatched
+	// jobs to the idempotency token and payload hash of the dispatch.
+	DispatchIdempotencyToken string `json:",omitempty"`
+	DispatchPayloadHash      string `json:",omitempty"`
 }
 
 // IsPeriodic returns whether a job is periodic.
@@ -718,6 +1090,9 @@ func (j *Job) Canonicalize() {
 	if j.Update != nil {
 		j.Update.Canonicalize()
 	}
+	if j.Prefetch != nil {
+		j.Prefetch.Canonicalize()
+	}
 
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
@@ -777,6 +1152,11 @@ type TaskGroupSummary struct {
 	Running  int
 	Starting int
 	Lost     int
+	Unknown  int
+
+	// LastExitCodes are the exit codes of the tasks of the last allocation
+	// of a batch job that completed or failed, keyed by task name.
+	LastExitCodes map[string]int
 }
 
 // JobListStub is used to return a subset of information about
@@ -786,6 +1166,7 @@ type JobListStub struct {
 	ParentID          string
 	Name              string
 	Datacenters       []string
+	Tags              []string
 	Type              string
 	Priority          int
 	Periodic          bool
@@ -898,6 +1279,11 @@ type WriteRequest struct {
 // JobValidateRequest is used to validate a job
 type JobValidateRequest struct {
 	Job *Job
+
+	// Admission additionally runs the checks that depend on the state of the
+	// cluster.
+	Admission bool
+
 	WriteRequest
 }
 
@@ -916,6 +1302,26 @@ type JobValidateResponse struct {
 	// Warnings contains any warnings about the given job. These may include
 	// deprecation warnings.
 	Warnings string
+
+	// AdmissionWarnings contains the problems found by the admission checks
+	// if they were requested.
+	AdmissionWarnings []*JobAdmissionWarning
+}
+
+// JobAdmissionWarning is a problem found when checking whether a job would be
+// admitted and could be placed by the cluster.
+type JobAdmissionWarning struct {
+	// Type is the kind of check that failed, such as "constraint",
+	// "namespace" or "vault".
+	Type string
+
+	// TaskGroup and Task are set if the warning applies to a specific task
+	// group or task.
+	TaskGroup string
+	Task      string
+
+	// Message describes the problem.
+	Message string
 }
 
 // JobRevertRequest is used to revert a job to a prior version.
@@ -930,6 +1336,10 @@ type JobRevertRequest struct {
 	// version before reverting.
 	EnforcePriorVersion *uint64
 
+	// OverrideMinimumVersion allows reverting to a version older than the
+	// minimum version the job is pinned to.
+	OverrideMinimumVersion bool
+
 	WriteRequest
 }
 
@@ -942,16 +1352,40 @@ type JobRegisterRequest struct {
 	EnforceIndex   bool
 	JobModifyIndex uint64
 	PolicyOverride bool
+	Signature      string
+	Submission     *JobSubmission
+
+	// OverrideMinimumVersion allows registering a job matching a version
+	// older than the minimum version the job is pinned to.
+	OverrideMinimumVersion bool
 
 	WriteRequest
 }
 
 // RegisterJobRequest is used to serialize a job registration
 type RegisterJobRequest struct {
-	Job            *Job
-	EnforceIndex   bool   `json:",omitempty"`
-	JobModifyIndex uint64 `json:",omitempty"`
-	PolicyOverride bool   `json:",omitempty"`
+	Job                    *Job
+	EnforceIndex           bool           `json:",omitempty"`
+	JobModifyIndex         uint64         `json:",omitempty"`
+	PolicyOverride         bool           `json:",omitempty"`
+	Signature              string         `json:",omitempty"`
+	Submission             *JobSubmission `json:",omitempty"`
+	OverrideMinimumVersion bool           `json:",omitempty"`
+}
+
+// JobSubmission is the job file a job version was parsed from, along with the
+// values bound to the variables it declares.
+type JobSubmission struct {
+	// Source is the job file
+	Source string
+
+	// Variables are the values of the variables declared by the job file, in
+	// the "name = value" form of variable files
+	Variables string
+
+	Namespace string
+	JobID     string
+	Version   uint64
 }
 
 // JobRegisterResponse is used to respond to a job registration
@@ -1051,9 +1485,10 @@ type DesiredUpdates struct {
 }
 
 type JobDispatchRequest struct {
-	JobID   string
-	Payload []byte
-	Meta    map[string]string
+	JobID            string
+	Payload          []byte
+	Meta             map[string]string
+	IdempotencyToken string `json:",omitempty"`
 }
 
 type JobDispatchResponse struct {
@@ -1088,6 +1523,22 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobPinVersionRequest is used to pin a job to a minimum version.
+type JobPinVersionRequest struct {
+	// Job to pin
+	JobID string
+
+	// JobVersion is the minimum version of the job, zero unpins the job
+	JobVersion uint64
+
+	WriteRequest
+}
+
+// JobPinVersionResponse is the response when pinning a job version.
+type JobPinVersionResponse struct {
+	WriteMeta
+}
+
 // JobEvaluateRequest is used when we just need to re-evaluate a target job
 type JobEvaluateRequest struct {
 	JobID       string
@@ -1099,3 +1550,58 @@ type JobEvaluateRequest struct {
 type EvalOptions struct {
 	ForceReschedule bool
 }
+
+const (
+	// JobBulkOperationStop stops the running jobs, or purges all the jobs
+	// when Purge is set.
+	JobBulkOperationStop = "stop"
+
+	// JobBulkOperationRun runs the stopped jobs again.
+	JobBulkOperationRun = "run"
+
+	// JobBulkOperationEvaluate force-evaluates the running jobs.
+	JobBulkOperationEvaluate = "evaluate"
+)
+
+// JobsBulkRequest is used to apply an operation to all the jobs of a
+// namespace matching a filter in a single request.
+type JobsBulkRequest struct {
+	// Operation is the operation applied to the matching jobs.
+	Operation string
+
+	// Prefix and Tags filter the jobs by ID prefix and by tags, the jobs
+	// must have all of the tags. At least one of them must be set.
+	Prefix string
+	Tags   []string
+
+	// Purge purges the jobs when stopping them.
+	Purge bool
+
+	// EvalOptions are the options of the evaluations of the jobs when
+	// force-evaluating them.
+	EvalOptions EvalOptions
+
+	// DryRun returns the jobs the operation would apply to without applying
+	// it.
+	DryRun bool
+
+	WriteRequest
+}
+
+// JobsBulkResponse is the response of a bulk job operation.
+type JobsBulkResponse struct {
+	// Jobs has the result of the operation for each matching job, in job ID
+	// order.
+	Jobs []*JobBulkResult
+}
+
+// JobBulkResult is the result of a bulk operation for a single job.
+type JobBulkResult struct {
+	ID string
+
+	// EvalID is the ID of the evaluation created by the operation, if any.
+	EvalID string
+
+	// Error is set if the operation failed for the job.
+	Error string
+}
@@ -323,6 +323,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					ProgressDeadline: timeToPtr(10 * time.Minute),
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
+					ManualStep:       boolToPtr(false),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -357,6 +358,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							ProgressDeadline: timeToPtr(10 * time.Minute),
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
+							ManualStep:       boolToPtr(false),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -486,6 +488,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					ProgressDeadline: timeToPtr(7 * time.Minute),
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
+					ManualStep:       boolToPtr(false),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -541,6 +544,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					ProgressDeadline: timeToPtr(7 * time.Minute),
 					AutoRevert:       boolToPtr(false),
 					Canary:           intToPtr(0),
+					ManualStep:       boolToPtr(false),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -574,6 +578,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							ProgressDeadline: timeToPtr(7 * time.Minute),
 							AutoRevert:       boolToPtr(true),
 							Canary:           intToPtr(1),
+							ManualStep:       boolToPtr(false),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -616,6 +621,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							ProgressDeadline: timeToPtr(7 * time.Minute),
 							AutoRevert:       boolToPtr(false),
 							Canary:           intToPtr(0),
+							ManualStep:       boolToPtr(false),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -841,6 +847,55 @@ func TestJobs_PrefixList(t *testing.T) {
 	}
 }
 
+func TestJobs_Iterate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Iterating when nothing exists stops immediately
+	it := jobs.Iterate(&QueryOptions{PerPage: 2})
+	require.False(it.Next())
+	require.NoError(it.Err())
+	require.Nil(it.Job())
+
+	// Register the jobs
+	ids := []string{"job1", "job2", "job3", "job4", "job5"}
+	for _, id := range ids {
+		job := testJob()
+		job.ID = stringToPtr(id)
+		_, wm, err := jobs.Register(job, nil)
+		require.NoError(err)
+		assertWriteMeta(t, wm)
+	}
+
+	// The jobs are listed across pages
+	var listed []string
+	it = jobs.Iterate(&QueryOptions{PerPage: 2})
+	for it.Next() {
+		listed = append(listed, it.Job().ID)
+	}
+	require.NoError(it.Err())
+	require.Equal(ids, listed)
+	require.Empty(it.Meta().NextToken)
+	require.False(it.Next())
+
+	// A single page is listed without a page size
+	listed = nil
+	it = jobs.Iterate(&QueryOptions{Prefix: "job"})
+	for it.Next() {
+		listed = append(listed, it.Job().ID)
+	}
+	require.NoError(it.Err())
+	require.Equal(ids, listed)
+
+	// Errors stop the iteration
+	it = jobs.Iterate(&QueryOptions{Region: "nonexistent"})
+	require.False(it.Next())
+	require.Error(it.Err())
+}
+
 func TestJobs_List(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t, nil, nil)
@@ -1001,6 +1056,43 @@ func TestJobs_Deregister(t *testing.T) {
 	}
 }
 
+func TestJobs_Bulk(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Register a new job
+	_, wm, err := jobs.Register(testJob(), nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	// A dry run lists the job without stopping it
+	req := &JobsBulkRequest{
+		Operation: JobBulkOperationStop,
+		Prefix:    "job",
+		DryRun:    true,
+	}
+	resp, _, err := jobs.Bulk(req, nil)
+	require.NoError(err)
+	require.Len(resp.Jobs, 1)
+	require.Equal("job1", resp.Jobs[0].ID)
+	require.Empty(resp.Jobs[0].EvalID)
+
+	// Stop the job
+	req.DryRun = false
+	resp, wm, err = jobs.Bulk(req, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Len(resp.Jobs, 1)
+	require.NotEmpty(resp.Jobs[0].EvalID)
+
+	out, _, err := jobs.Info("job1", nil)
+	require.NoError(err)
+	require.True(*out.Stop)
+}
+
 func TestJobs_ForceEvaluate(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t, nil, nil)
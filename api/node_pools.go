@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+const (
+	// NodePoolAll is the node pool of all the nodes of the region
+	NodePoolAll = "all"
+
+	// NodePoolDefault is the node pool of the nodes and jobs that don't set
+	// a node pool
+	NodePoolDefault = "default"
+)
+
+// NodePools is used to query the node pool endpoints.
+type NodePools struct {
+	client *Client
+}
+
+// NodePools returns a new handle on the node pools.
+func (c *Client) NodePools() *NodePools {
+	return &NodePools{client: c}
+}
+
+// List is used to dump all of the node pools.
+func (n *NodePools) List(q *QueryOptions) ([]*NodePool, *QueryMeta, error) {
+	var resp []*NodePool
+	qm, err := n.client.query("/v1/node/pools", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Sort(NodePoolNameSort(resp))
+	return resp, qm, nil
+}
+
+// Info is used to query a single node pool by its name.
+func (n *NodePools) Info(name string, q *QueryOptions) (*NodePool, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, fmt.Errorf("missing node pool name")
+	}
+
+	var resp NodePool
+	qm, err := n.client.query("/v1/node/pool/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create or update a node pool.
+func (n *NodePools) Register(pool *NodePool, q *WriteOptions) (*WriteMeta, error) {
+	if pool == nil || pool.Name == "" {
+		return nil, fmt.Errorf("missing node pool name")
+	}
+
+	wm, err := n.client.write("/v1/node/pool/"+url.PathEscape(pool.Name), pool, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a node pool
+func (n *NodePools) Delete(name string, q *WriteOptions) (*WriteMeta, error) {
+	if name == "" {
+		return nil, fmt.Errorf("missing node pool name")
+	}
+
+	wm, err := n.client.delete("/v1/node/pool/"+url.PathEscape(name), nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// NodePool is used to serialize a node pool.
+type NodePool struct {
+	Name                   string
+	Description            string
+	Meta                   map[string]string
+	SchedulerConfiguration *NodePoolSchedulerConfiguration
+	CreateIndex            uint64
+	ModifyIndex            uint64
+}
+
+// NodePoolSchedulerConfiguration is the scheduler configuration of a node
+// pool. Unset fields use the cluster's scheduler configuration.
+type NodePoolSchedulerConfiguration struct {
+	SystemSchedulerPreemptionEnabled *bool
+}
+
+// NodePoolNameSort is a wrapper to sort node pools by name.
+type NodePoolNameSort []*NodePool
+
+func (n NodePoolNameSort) Len() int {
+	return len(n)
+}
+
+func (n NodePoolNameSort) Less(i, j int) bool {
+	return n[i].Name < n[j].Name
+}
+
+func (n NodePoolNameSort) Swap(i, j int) {
+	n[i], n[j] = n[j], n[i]
+}
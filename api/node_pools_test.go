@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePools_CRUD(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	nodePools := c.NodePools()
+
+	// The built-in node pools always exist
+	resp, qm, err := nodePools.List(nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Len(t, resp, 2)
+	require.Equal(t, NodePoolAll, resp[0].Name)
+	require.Equal(t, NodePoolDefault, resp[1].Name)
+
+	// Register a node pool
+	pool := &NodePool{
+		Name:        "gpu",
+		Description: "Nodes with GPUs",
+		Meta:        map[string]string{"team": "data"},
+	}
+	wm, err := nodePools.Register(pool, nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	// Query it back out
+	out, qm, err := nodePools.Info("gpu", nil)
+	require.NoError(t, err)
+	assertQueryMeta(t, qm)
+	require.Equal(t, pool.Description, out.Description)
+	require.Equal(t, pool.Meta, out.Meta)
+
+	// Delete it
+	wm, err = nodePools.Delete("gpu", nil)
+	require.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	_, _, err = nodePools.Info("gpu", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+
+	// Built-in node pools can't be deleted
+	_, err = nodePools.Delete(NodePoolDefault, nil)
+	require.Error(t, err)
+}
@@ -350,6 +350,11 @@ type NodeUpdateEligibilityRequest struct {
 	// NodeID is the node to update the drain specification for.
 	NodeID      string
 	Eligibility string
+
+	// TTL is how long the node stays ineligible before it is marked as
+	// eligible again. A zero TTL keeps the node ineligible until it is
+	// marked as eligible.
+	TTL time.Duration
 }
 
 // NodeEligibilityUpdateResponse is used to respond to a node eligibility update
@@ -371,7 +376,21 @@ func (n *Nodes) ToggleEligibility(nodeID string, eligible bool, q *WriteOptions)
 		NodeID:      nodeID,
 		Eligibility: e,
 	}
+	return n.updateEligibility(nodeID, req, q)
+}
+
+// MarkIneligible is used to mark the node as ineligible for scheduling for
+// the given TTL, after which it is marked as eligible again.
+func (n *Nodes) MarkIneligible(nodeID string, ttl time.Duration, q *WriteOptions) (*NodeEligibilityUpdateResponse, error) {
+	req := &NodeUpdateEligibilityRequest{
+		NodeID:      nodeID,
+		Eligibility: NodeSchedulingIneligible,
+		TTL:         ttl,
+	}
+	return n.updateEligibility(nodeID, req, q)
+}
 
+func (n *Nodes) updateEligibility(nodeID string, req *NodeUpdateEligibilityRequest, q *WriteOptions) (*NodeEligibilityUpdateResponse, error) {
 	var resp NodeEligibilityUpdateResponse
 	wm, err := n.client.write("/v1/node/"+nodeID+"/eligibility", req, &resp, q)
 	if err != nil {
@@ -412,6 +431,17 @@ func (n *Nodes) Stats(nodeID string, q *QueryOptions) (*HostStats, error) {
 	return &resp, nil
 }
 
+// Hardware is used to query the detailed hardware inventory of a node.
+func (n *Nodes) Hardware(nodeID string, q *QueryOptions) (*HostHardware, error) {
+	var resp HostHardware
+	path := fmt.Sprintf("/v1/client/hardware?node_id=%s", nodeID)
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 func (n *Nodes) GC(nodeID string, q *QueryOptions) error {
 	var resp struct{}
 	path := fmt.Sprintf("/v1/client/gc?node_id=%s", nodeID)
@@ -427,6 +457,124 @@ func (n *Nodes) GcAlloc(allocID string, q *QueryOptions) error {
 	return err
 }
 
+// NodeMetaApplyRequest is used to set or unset the metadata of a node at
+// runtime.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node to update. If empty, the metadata of the local
+	// agent's node is updated.
+	NodeID string
+
+	// Meta maps the keys to set to their values. Keys with a nil value are
+	// unset.
+	Meta map[string]*string
+}
+
+// NodeMetaResponse is used to return the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the node's metadata, including the metadata set at runtime.
+	Meta map[string]string
+
+	// Dynamic is the metadata set at runtime. Keys with a nil value were
+	// unset.
+	Dynamic map[string]*string
+}
+
+// ApplyMeta sets or unsets the metadata of a node at runtime. The changes are
+// persisted by the node and can be used in constraints immediately.
+func (n *Nodes) ApplyMeta(req *NodeMetaApplyRequest, q *WriteOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	if _, err := n.client.write("/v1/client/metadata", req, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReadMeta returns the metadata of a node. If nodeID is empty, the metadata of
+// the local agent's node is returned.
+func (n *Nodes) ReadMeta(nodeID string, q *QueryOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	path := fmt.Sprintf("/v1/client/metadata?node_id=%s", nodeID)
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FaultApplyRequest is used to set the faults injected into a node for
+// resilience testing.
+type FaultApplyRequest struct {
+	// NodeID is the node to inject faults into. If empty, the faults are
+	// injected into the local agent's node.
+	NodeID string
+
+	// HeartbeatDelay is added to the interval between the heartbeats of the
+	// node. Nil leaves it unchanged and zero clears it.
+	HeartbeatDelay *time.Duration
+
+	// FailAllocations is the number of allocations placed on the node to
+	// fail. Nil leaves it unchanged and zero clears it.
+	FailAllocations *int
+}
+
+// FaultResponse is used to return the faults injected into a node.
+type FaultResponse struct {
+	// HeartbeatDelay is added to the interval between the heartbeats of the
+	// node.
+	HeartbeatDelay time.Duration
+
+	// FailAllocations is the number of allocations left to fail.
+	FailAllocations int
+}
+
+// FaultKillTaskRequest is used to kill a random running task of a job on a
+// node.
+type FaultKillTaskRequest struct {
+	// NodeID is the node to kill the task on. If empty, the task is killed on
+	// the local agent's node.
+	NodeID string
+
+	// JobID is the job whose task is killed. The namespace of the job is set
+	// through the write options.
+	JobID string
+}
+
+// FaultKillTaskResponse is used to return the task killed by a
+// FaultKillTaskRequest.
+type FaultKillTaskResponse struct {
+	AllocID string
+	Task    string
+}
+
+// ApplyFaults sets the faults injected into a node for resilience testing.
+func (n *Nodes) ApplyFaults(req *FaultApplyRequest, q *WriteOptions) (*FaultResponse, error) {
+	var resp FaultResponse
+	if _, err := n.client.write("/v1/client/fault", req, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReadFaults returns the faults injected into a node. If nodeID is empty, the
+// faults of the local agent's node are returned.
+func (n *Nodes) ReadFaults(nodeID string, q *QueryOptions) (*FaultResponse, error) {
+	var resp FaultResponse
+	path := fmt.Sprintf("/v1/client/fault?node_id=%s", nodeID)
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FaultKillTask kills a random running task of a job on a node, as if the task
+// crashed. The task is restarted according to its restart policy.
+func (n *Nodes) FaultKillTask(req *FaultKillTaskRequest, q *WriteOptions) (*FaultKillTaskResponse, error) {
+	var resp FaultKillTaskResponse
+	if _, err := n.client.write("/v1/client/fault/kill-task", req, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // DriverInfo is used to deserialize a DriverInfo entry
 type DriverInfo struct {
 	Attributes        map[string]string
@@ -451,9 +599,12 @@ type Node struct {
 	Links                 map[string]string
 	Meta                  map[string]string
 	NodeClass             string
+	NodePool              string
 	Drain                 bool
 	DrainStrategy         *DrainStrategy
 	SchedulingEligibility string
+	IneligibleUntil       time.Time
+	MaintenanceWindows    []*NodeMaintenanceWindow
 	Status                string
 	StatusDescription     string
 	StatusUpdatedAt       int64
@@ -463,6 +614,14 @@ type Node struct {
 	ModifyIndex           uint64
 }
 
+// NodeMaintenanceWindow is a recurring window during which the node is marked
+// as ineligible for scheduling.
+type NodeMaintenanceWindow struct {
+	Cron     string
+	Duration time.Duration
+	TimeZone string
+}
+
 type NodeResources struct {
 	Cpu      NodeCpuResources
 	Memory   NodeMemoryResources
@@ -472,11 +631,15 @@ type NodeResources struct {
 }
 
 type NodeCpuResources struct {
-	CpuShares int64
+	CpuShares          int64
+	TotalCpuCores      uint16
+	ReservableCpuCores []uint16
 }
 
 type NodeMemoryResources struct {
-	MemoryMB int64
+	MemoryMB     int64
+	HugePages2MB int64
+	HugePages1GB int64
 }
 
 type NodeDiskResources struct {
@@ -575,10 +738,58 @@ type HostStats struct {
 	CPU              []*HostCPUStats
 	DiskStats        []*HostDiskStats
 	DeviceStats      []*DeviceGroupStats
+	PressureStats    *PressureStats
 	Uptime           uint64
 	CPUTicksConsumed float64
 }
 
+// HostHardware is the detailed hardware inventory of the host running a Nomad
+// client. Parts of the inventory that can't be determined on the platform of
+// the client are empty.
+type HostHardware struct {
+	CPUs      []*HostHardwareCPU
+	NUMANodes []*HostHardwareNUMANode
+	Disks     []*HostHardwareDisk
+	NICs      []*HostHardwareNIC
+}
+
+// HostHardwareCPU is a physical CPU package of the host.
+type HostHardwareCPU struct {
+	ID      string
+	Vendor  string
+	Model   string
+	MHz     float64
+	Cores   int
+	Threads int
+	Flags   []string
+}
+
+// HostHardwareNUMANode is a NUMA node of the host, with its CPUs in the Linux
+// cpulist format and its total memory in bytes.
+type HostHardwareNUMANode struct {
+	ID     int
+	CPUs   string
+	Memory uint64
+}
+
+// HostHardwareDisk is a physical block device of the host, with its size in
+// bytes.
+type HostHardwareDisk struct {
+	Name       string
+	Model      string
+	Size       uint64
+	Rotational bool
+}
+
+// HostHardwareNIC is a network interface of the host, with its link speed in
+// Mb/s or zero if it is unknown.
+type HostHardwareNIC struct {
+	Name  string
+	MAC   string
+	MTU   int
+	Speed int
+}
+
 type HostMemoryStats struct {
 	Total     uint64
 	Available uint64
@@ -703,6 +914,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
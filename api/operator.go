@@ -1,6 +1,9 @@
 package api
 
-import "strconv"
+import (
+	"strconv"
+	"time"
+)
 
 // Operator can be used to perform low-level operator tasks for Nomad.
 type Operator struct {
@@ -37,6 +40,43 @@ type RaftServer struct {
 
 	// RaftProtocol is the version of the Raft protocol spoken by this server.
 	RaftProtocol string
+
+	// Stats has the replication and log store stats of this server, or is
+	// nil if they couldn't be fetched, in which case StatsError has the
+	// reason.
+	Stats      *RaftServerStats
+	StatsError string
+
+	// Lag is the number of log entries this server is behind the leader.
+	Lag uint64
+}
+
+// RaftServerStats has the replication and log store stats of a server in the
+// Raft configuration.
+type RaftServerStats struct {
+	// LastContact is the time since the server last heard from the leader,
+	// zero on the leader itself or -1 if the server never heard from it.
+	LastContact time.Duration
+
+	// LastIndex is the index of the last log entry of the server.
+	LastIndex uint64
+
+	// LastSnapshotIndex and LastSnapshotTime are the index and the time of
+	// the last snapshot of the server, zero if it didn't take any.
+	LastSnapshotIndex uint64
+	LastSnapshotTime  time.Time
+
+	// LogStoreSize is the size in bytes of the log store of the server, zero
+	// if the log store is in memory.
+	LogStoreSize int64
+
+	// LogStoreEntries is the number of entries in the log store of the
+	// server, which snapshots truncate.
+	LogStoreEntries uint64
+
+	// Warnings has the issues found with the log store of the server, such
+	// as it needing compaction.
+	Warnings []string
 }
 
 // RaftConfiguration is returned when querying for the current Raft configuration.
@@ -114,6 +154,10 @@ type SchedulerConfiguration struct {
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// PlanRejectionTracker configures the quarantine of nodes whose
+	// placements are repeatedly rejected by the plan applier.
+	PlanRejectionTracker PlanRejectionTrackerConfig
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -174,3 +218,192 @@ func (op *Operator) SchedulerCASConfiguration(conf *SchedulerConfiguration, q *W
 
 	return &out, wm, nil
 }
+
+// EvalBrokerStatus is a point in time view of the evaluation broker's
+// internal state.
+type EvalBrokerStatus struct {
+	Enabled      bool
+	TotalReady   int
+	TotalUnacked int
+	TotalBlocked int
+	TotalWaiting int
+	TotalFailed  int
+	ByScheduler  map[string]*EvalBrokerSchedulerStatus
+}
+
+// EvalBrokerSchedulerStatus is the evaluation broker's state for a single
+// scheduler type.
+type EvalBrokerSchedulerStatus struct {
+	Ready         int
+	Unacked       int
+	Blocked       int
+	OldestReady   time.Duration
+	OldestUnacked time.Duration
+}
+
+// EvalBrokerStatus is used to query the state of the evaluation broker.
+func (op *Operator) EvalBrokerStatus(q *QueryOptions) (*EvalBrokerStatus, *QueryMeta, error) {
+	var resp EvalBrokerStatus
+	qm, err := op.c.query("/v1/operator/scheduler/broker", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// PlanRejectionTrackerConfig configures the quarantine of nodes whose
+// placements are repeatedly rejected by the plan applier. Nodes are marked as
+// ineligible for scheduling once they reach NodeThreshold rejections within
+// NodeWindow.
+type PlanRejectionTrackerConfig struct {
+	Enabled       bool
+	NodeThreshold int
+	NodeWindow    time.Duration
+}
+
+// NodePlanRejections are the plan rejections of a node tracked by the leader.
+type NodePlanRejections struct {
+	NodeID        string
+	Rejections    int
+	Total         uint64
+	LastReason    string
+	LastEvalID    string
+	LastJobID     string
+	LastRejection time.Time
+	Quarantined   bool
+}
+
+// PlanRejections is used to query the nodes whose placements were rejected by
+// the plan applier.
+func (op *Operator) PlanRejections(q *QueryOptions) ([]*NodePlanRejections, *QueryMeta, error) {
+	var resp []*NodePlanRejections
+	qm, err := op.c.query("/v1/operator/scheduler/plan-rejections", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// ReplicationStatus is a point in time view of a region's replication of data
+// from the authoritative region.
+type ReplicationStatus struct {
+	Enabled      bool
+	SourceRegion string
+	SourceError  string
+	ACLPolicies  *ReplicationTypeStatus
+	ACLRoles     *ReplicationTypeStatus
+	ACLTokens    *ReplicationTypeStatus
+}
+
+// ReplicationTypeStatus is the replication status for a single type of data.
+type ReplicationTypeStatus struct {
+	Running          bool
+	ReplicatedIndex  uint64
+	SourceIndex      uint64
+	LastSuccess      time.Time
+	LastError        time.Time
+	LastErrorMessage string
+}
+
+// ReplicationStatus is used to query the status of replication from the
+// authoritative region.
+func (op *Operator) ReplicationStatus(q *QueryOptions) (*ReplicationStatus, *QueryMeta, error) {
+	var resp ReplicationStatus
+	qm, err := op.c.query("/v1/operator/replication", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// UsageReport is the inventory of the jobs, allocations and nodes of the
+// cluster along with the resources allocated to them within a time window.
+type UsageReport struct {
+	Start       time.Time
+	End         time.Time
+	Totals      *UsageTotals
+	Jobs        []*JobUsage
+	Allocations []*AllocUsage
+	Nodes       []*NodeUsage
+}
+
+// UsageTotals are the totals of a usage report.
+type UsageTotals struct {
+	Jobs            int
+	Allocations     int
+	Nodes           int
+	CPUMHz          int64
+	MemoryMB        int64
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// JobUsage is the resources allocated to a job within the window of a usage
+// report.
+type JobUsage struct {
+	Namespace       string
+	ID              string
+	ParentID        string
+	Type            string
+	Allocations     int
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// AllocUsage is the resources allocated to an allocation within the window of
+// a usage report.
+type AllocUsage struct {
+	ID              string
+	Namespace       string
+	JobID           string
+	TaskGroup       string
+	NodeID          string
+	ClientStatus    string
+	Start           time.Time
+	End             time.Time
+	CPUMHz          int64
+	MemoryMB        int64
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// NodeUsage is the capacity of a node and the resources allocated on it within
+// the window of a usage report.
+type NodeUsage struct {
+	ID              string
+	Name            string
+	Datacenter      string
+	NodeClass       string
+	NodePool        string
+	Status          string
+	CPUMHz          int64
+	MemoryMB        int64
+	Allocations     int
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// UsageExport is used to export the inventory and resource usage of the
+// cluster between start and end. A zero end is the current time and a zero
+// start is a day before the end.
+func (op *Operator) UsageExport(start, end time.Time, q *QueryOptions) (*UsageReport, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	if !start.IsZero() {
+		q.Params["start"] = strconv.FormatInt(start.UnixNano(), 10)
+	}
+	if !end.IsZero() {
+		q.Params["end"] = strconv.FormatInt(end.UnixNano(), 10)
+	}
+
+	var resp UsageReport
+	qm, err := op.c.query("/v1/operator/usage", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
@@ -8,11 +8,16 @@ import (
 // a given task or task group.
 type Resources struct {
 	CPU      *int
+	Cores    *int
 	MemoryMB *int `mapstructure:"memory"`
 	DiskMB   *int `mapstructure:"disk"`
 	Networks []*NetworkResource
 	Devices  []*RequestedDevice
 
+	HugePages2MB *int `mapstructure:"hugepages_2mb"`
+	HugePages1GB *int `mapstructure:"hugepages_1gb"`
+	Realtime     *bool
+
 	// COMPAT(0.10)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
 	// 0.10 and is only being kept to allow any references to be removed before
@@ -25,7 +30,12 @@ type Resources struct {
 func (r *Resources) Canonicalize() {
 	defaultResources := DefaultResources()
 	if r.CPU == nil {
-		r.CPU = defaultResources.CPU
+		// Tasks reserving whole cores don't ask for CPU shares
+		if r.Cores != nil && *r.Cores > 0 {
+			r.CPU = intToPtr(0)
+		} else {
+			r.CPU = defaultResources.CPU
+		}
 	}
 	if r.MemoryMB == nil {
 		r.MemoryMB = defaultResources.MemoryMB
@@ -69,6 +79,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.CPU != nil {
 		r.CPU = other.CPU
 	}
+	if other.Cores != nil {
+		r.Cores = other.Cores
+	}
 	if other.MemoryMB != nil {
 		r.MemoryMB = other.MemoryMB
 	}
@@ -81,6 +94,15 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.HugePages2MB != nil {
+		r.HugePages2MB = other.HugePages2MB
+	}
+	if other.HugePages1GB != nil {
+		r.HugePages1GB = other.HugePages1GB
+	}
+	if other.Realtime != nil {
+		r.Realtime = other.Realtime
+	}
 }
 
 type Port struct {
@@ -97,6 +119,7 @@ type NetworkResource struct {
 	MBits         *int
 	ReservedPorts []Port
 	DynamicPorts  []Port
+	DNS           *DNSConfig
 }
 
 func (n *NetworkResource) Canonicalize() {
@@ -105,6 +128,13 @@ func (n *NetworkResource) Canonicalize() {
 	}
 }
 
+// DNSConfig is the resolver configuration of a task.
+type DNSConfig struct {
+	Servers  []string `mapstructure:"servers"`
+	Searches []string `mapstructure:"searches"`
+	Options  []string `mapstructure:"options"`
+}
+
 // NodeDeviceResource captures a set of devices sharing a common
 // vendor/type/device_name tuple.
 type NodeDeviceResource struct {
@@ -143,6 +173,9 @@ type NodeDevice struct {
 	// Locality stores HW locality information for the node to optionally be
 	// used when making placement decisions.
 	Locality *NodeDeviceLocality
+
+	// Slices is the number of allocations that can share the device.
+	Slices uint32 `json:",omitempty"`
 }
 
 // Attribute is used to describe the value of an attribute, optionally
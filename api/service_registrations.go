@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ServiceRegistrations is used to query the services registered with the
+// Nomad service provider.
+type ServiceRegistrations struct {
+	client *Client
+}
+
+// ServiceRegistrations returns a new handle on the service registrations.
+func (c *Client) ServiceRegistrations() *ServiceRegistrations {
+	return &ServiceRegistrations{client: c}
+}
+
+// ServiceRegistration is the registration of a service of a task with the
+// Nomad service provider.
+type ServiceRegistration struct {
+	ID          string
+	ServiceName string
+	Namespace   string
+	NodeID      string
+	Datacenter  string
+	JobID       string
+	AllocID     string
+	Tags        []string
+	Address     string
+	Port        int
+
+	// Status is the aggregated status of the health checks of the service:
+	// passing, warning or critical.
+	Status string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ServiceRegistrationStub is the summary of a service of a namespace.
+type ServiceRegistrationStub struct {
+	ServiceName string
+	Tags        []string
+}
+
+// List is used to list the services registered in a namespace.
+func (s *ServiceRegistrations) List(q *QueryOptions) ([]*ServiceRegistrationStub, *QueryMeta, error) {
+	var resp []*ServiceRegistrationStub
+	qm, err := s.client.query("/v1/services", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Get is used to read the registrations of a service.
+func (s *ServiceRegistrations) Get(name string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, fmt.Errorf("missing service name")
+	}
+	var resp []*ServiceRegistration
+	qm, err := s.client.query("/v1/service/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Delete is used to remove a registration of a service.
+func (s *ServiceRegistrations) Delete(name, id string, q *WriteOptions) (*WriteMeta, error) {
+	if name == "" || id == "" {
+		return nil, fmt.Errorf("missing service name or registration ID")
+	}
+	wm, err := s.client.delete("/v1/service/"+url.PathEscape(name)+"/"+url.PathEscape(id), nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
@@ -10,12 +10,30 @@ func (c *Client) System() *System {
 	return &System{client: c}
 }
 
+// GarbageCollectRequest is used to restrict a forced garbage collection to the
+// objects of a namespace, or of a single job within that namespace.
+type GarbageCollectRequest struct {
+	// Namespace restricts the garbage collection to the given namespace.
+	Namespace string
+
+	// JobID restricts the garbage collection to the given job. Namespace must
+	// also be set.
+	JobID string
+}
+
 func (s *System) GarbageCollect() error {
 	var req struct{}
 	_, err := s.client.write("/v1/system/gc", &req, nil, nil)
 	return err
 }
 
+// GarbageCollectTarget forces a garbage collection of the GCable objects
+// matching the request, such as the dead dispatched instances of a job.
+func (s *System) GarbageCollectTarget(req *GarbageCollectRequest, q *WriteOptions) error {
+	_, err := s.client.write("/v1/system/gc", req, nil, q)
+	return err
+}
+
 func (s *System) ReconcileSummaries() error {
 	var req struct{}
 	_, err := s.client.write("/v1/system/reconcile/summaries", &req, nil, nil)
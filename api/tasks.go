@@ -16,6 +16,12 @@ const (
 	// RestartPolicyModeFail causes a job to fail if the specified number of
 	// attempts are reached within an interval.
 	RestartPolicyModeFail = "fail"
+
+	// ServiceCheckRoleReadiness checks only control whether the service
+	// receives traffic, while ServiceCheckRoleLiveness checks only restart
+	// the task.
+	ServiceCheckRoleReadiness = "readiness"
+	ServiceCheckRoleLiveness  = "liveness"
 )
 
 // MemoryStats holds memory usage related stats
@@ -41,11 +47,34 @@ type CpuStats struct {
 	Measured         []string
 }
 
+// PressureStats holds the pressure stall information of the cpu, memory and
+// io resources
+type PressureStats struct {
+	CPU    *Pressure
+	Memory *Pressure
+	IO     *Pressure
+}
+
+// Pressure holds the percentage of time some or all of the tasks were stalled
+// waiting on a resource, averaged over 10, 60 and 300 seconds, and the total
+// stall time in microseconds
+type Pressure struct {
+	SomeAvg10  float64
+	SomeAvg60  float64
+	SomeAvg300 float64
+	SomeTotal  uint64
+	FullAvg10  float64
+	FullAvg60  float64
+	FullAvg300 float64
+	FullTotal  uint64
+}
+
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage struct {
-	MemoryStats *MemoryStats
-	CpuStats    *CpuStats
-	DeviceStats []*DeviceGroupStats
+	MemoryStats   *MemoryStats
+	CpuStats      *CpuStats
+	DeviceStats   []*DeviceGroupStats
+	PressureStats *PressureStats
 }
 
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
@@ -62,6 +91,10 @@ type AllocResourceUsage struct {
 	ResourceUsage *ResourceUsage
 	Tasks         map[string]*TaskResourceUsage
 	Timestamp     int64
+
+	// History is the recent resource usage of each task, oldest first. It is
+	// only set by Allocations.StatsRange.
+	History map[string][]*TaskResourceUsage
 }
 
 // RestartPolicy defines how the Nomad client restarts
@@ -280,6 +313,8 @@ type CheckRestart struct {
 	Limit          int            `mapstructure:"limit"`
 	Grace          *time.Duration `mapstructure:"grace"`
 	IgnoreWarnings bool           `mapstructure:"ignore_warnings"`
+	Delay          *time.Duration `mapstructure:"delay"`
+	MaxDelay       *time.Duration `mapstructure:"max_delay"`
 }
 
 // Canonicalize CheckRestart fields if not nil.
@@ -291,6 +326,17 @@ func (c *CheckRestart) Canonicalize() {
 	if c.Grace == nil {
 		c.Grace = timeToPtr(1 * time.Second)
 	}
+
+	if c.Delay == nil {
+		c.Delay = timeToPtr(0)
+	}
+
+	if c.MaxDelay == nil {
+		c.MaxDelay = timeToPtr(0)
+		if *c.Delay > 0 {
+			c.MaxDelay = timeToPtr(1 * time.Hour)
+		}
+	}
 }
 
 // Copy returns a copy of CheckRestart or nil if unset.
@@ -306,6 +352,14 @@ func (c *CheckRestart) Copy() *CheckRestart {
 		nc.Grace = &g
 	}
 	nc.IgnoreWarnings = c.IgnoreWarnings
+	if c.Delay != nil {
+		d := *c.Delay
+		nc.Delay = &d
+	}
+	if c.MaxDelay != nil {
+		d := *c.MaxDelay
+		nc.MaxDelay = &d
+	}
 	return nc
 }
 
@@ -336,6 +390,14 @@ func (c *CheckRestart) Merge(o *CheckRestart) *CheckRestart {
 		nc.IgnoreWarnings = o.IgnoreWarnings
 	}
 
+	if o.Delay != nil {
+		nc.Delay = o.Delay
+	}
+
+	if o.MaxDelay != nil {
+		nc.MaxDelay = o.MaxDelay
+	}
+
 	return nc
 }
 
@@ -360,6 +422,11 @@ type ServiceCheck struct {
 	CheckRestart  *CheckRestart `mapstructure:"check_restart"`
 	GRPCService   string        `mapstructure:"grpc_service"`
 	GRPCUseTLS    bool          `mapstructure:"grpc_use_tls"`
+
+	// Role is ServiceCheckRoleReadiness or ServiceCheckRoleLiveness, or empty
+	// for checks that both control whether the service receives traffic and
+	// restart the task.
+	Role string
 }
 
 // The Service model represents a Consul service definition
@@ -372,6 +439,10 @@ type Service struct {
 	AddressMode  string   `mapstructure:"address_mode"`
 	Checks       []ServiceCheck
 	CheckRestart *CheckRestart `mapstructure:"check_restart"`
+
+	// Provider is the service discovery provider, "consul" or "nomad".
+	// Services are registered with Consul by default.
+	Provider string
 }
 
 func (s *Service) Canonicalize(t *Task, tg *TaskGroup, job *Job) {
@@ -385,8 +456,12 @@ func (s *Service) Canonicalize(t *Task, tg *TaskGroup, job *Job) {
 	}
 
 	// Canonicalize CheckRestart on Checks and merge Service.CheckRestart
-	// into each check.
+	// into each check. Readiness checks never restart the task, so they
+	// don't inherit it.
 	for i, check := range s.Checks {
+		if check.Role == ServiceCheckRoleReadiness && check.CheckRestart == nil {
+			continue
+		}
 		s.Checks[i].CheckRestart = s.CheckRestart.Merge(check.CheckRestart)
 		s.Checks[i].CheckRestart.Canonicalize()
 	}
@@ -419,6 +494,30 @@ func (e *EphemeralDisk) Canonicalize() {
 	}
 }
 
+// SharedMemory is the IPC namespace and /dev/shm shared by the tasks of a
+// task group.
+type SharedMemory struct {
+	SizeMB *int `mapstructure:"size"`
+}
+
+func (s *SharedMemory) Canonicalize() {
+	if s.SizeMB == nil {
+		s.SizeMB = intToPtr(64)
+	}
+}
+
+// Consul is the Consul cluster the services and templates of a task group
+// use.
+type Consul struct {
+	Cluster *string
+}
+
+func (c *Consul) Canonicalize() {
+	if c.Cluster == nil {
+		c.Cluster = stringToPtr("")
+	}
+}
+
 // MigrateStrategy describes how allocations for a task group should be
 // migrated between nodes (eg when draining).
 type MigrateStrategy struct {
@@ -482,18 +581,22 @@ func (m *MigrateStrategy) Copy() *MigrateStrategy {
 
 // TaskGroup is the unit of scheduling.
 type TaskGroup struct {
-	Name             *string
-	Count            *int
-	Constraints      []*Constraint
-	Affinities       []*Affinity
-	Tasks            []*Task
-	Spreads          []*Spread
-	RestartPolicy    *RestartPolicy
-	ReschedulePolicy *ReschedulePolicy
-	EphemeralDisk    *EphemeralDisk
-	Update           *UpdateStrategy
-	Migrate          *MigrateStrategy
-	Meta             map[string]string
+	Name                *string
+	Count               *int
+	Constraints         []*Constraint
+	Affinities          []*Affinity
+	Tasks               []*Task
+	Spreads             []*Spread
+	RestartPolicy       *RestartPolicy
+	ReschedulePolicy    *ReschedulePolicy
+	MaxClientDisconnect *time.Duration `mapstructure:"max_client_disconnect"`
+	ReconnectStrategy   *string        `mapstructure:"reconnect_strategy"`
+	EphemeralDisk       *EphemeralDisk
+	SharedMemory        *SharedMemory `mapstructure:"shared_memory"`
+	Consul              *Consul
+	Update              *UpdateStrategy
+	Migrate             *MigrateStrategy
+	Meta                map[string]string
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -519,6 +622,12 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	} else {
 		g.EphemeralDisk.Canonicalize()
 	}
+	if g.SharedMemory != nil {
+		g.SharedMemory.Canonicalize()
+	}
+	if g.Consul != nil {
+		g.Consul.Canonicalize()
+	}
 
 	// Merge the update policy from the job
 	if ju, tu := job.Update != nil, g.Update != nil; ju && tu {
@@ -640,6 +749,13 @@ func (g *TaskGroup) RequireDisk(disk *EphemeralDisk) *TaskGroup {
 	return g
 }
 
+// ShareMemory makes the tasks of the group share an IPC namespace and a
+// /dev/shm of the given size.
+func (g *TaskGroup) ShareMemory(sizeMB int) *TaskGroup {
+	g.SharedMemory = &SharedMemory{SizeMB: intToPtr(sizeMB)}
+	return g
+}
+
 // AddSpread is used to add a new spread preference to a task group.
 func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 	g.Spreads = append(g.Spreads, s)
@@ -650,6 +766,12 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 type LogConfig struct {
 	MaxFiles      *int `mapstructure:"max_files"`
 	MaxFileSizeMB *int `mapstructure:"max_file_size"`
+
+	// EventLogProviders are the providers whose Windows Event Log entries
+	// are captured into the eventlog logs of the task, from the
+	// EventLogChannel channel.
+	EventLogProviders []string `mapstructure:"event_log_providers"`
+	EventLogChannel   *string  `mapstructure:"event_log_channel"`
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -666,6 +788,9 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = intToPtr(10)
 	}
+	if l.EventLogChannel == nil && len(l.EventLogProviders) != 0 {
+		l.EventLogChannel = stringToPtr("Application")
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -673,6 +798,14 @@ type DispatchPayloadConfig struct {
 	File string
 }
 
+// WorkloadIdentity configures how the workload identity of a task is exposed
+// to it, as the NOMAD_TOKEN environment variable or the secrets/nomad_token
+// file.
+type WorkloadIdentity struct {
+	Env  bool
+	File bool
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string
@@ -689,11 +822,16 @@ type Task struct {
 	LogConfig       *LogConfig     `mapstructure:"logs"`
 	Artifacts       []*TaskArtifact
 	Vault           *Vault
+	Identity        *WorkloadIdentity
 	Templates       []*Template
 	DispatchPayload *DispatchPayloadConfig
 	Leader          bool
 	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`
 	KillSignal      string        `mapstructure:"kill_signal"`
+	KillEscalation  []*KillStep   `mapstructure:"kill_escalation"`
+	Actions         []*Action
+	Secrets         []*Secret
+	MetricLabels    []string `mapstructure:"metric_labels"`
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -724,6 +862,103 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	for _, a := range t.Affinities {
 		a.Canonicalize()
 	}
+	for _, a := range t.Actions {
+		a.Canonicalize()
+	}
+	for _, s := range t.Secrets {
+		s.Canonicalize()
+	}
+	for _, s := range t.KillEscalation {
+		s.Canonicalize()
+	}
+}
+
+// KillStep is a step of the kill escalation chain of a task: a signal sent to
+// the task and the time it has to exit before the next step.
+type KillStep struct {
+	Signal  *string
+	Timeout *time.Duration
+}
+
+func (s *KillStep) Canonicalize() {
+	if s.Signal == nil {
+		s.Signal = stringToPtr("")
+	} else {
+		s.Signal = stringToPtr(strings.ToUpper(*s.Signal))
+	}
+	if s.Timeout == nil {
+		s.Timeout = timeToPtr(5 * time.Second)
+	}
+}
+
+// Action is a predefined command that can be run inside a running task.
+type Action struct {
+	Name    *string
+	Command *string
+	Args    []string
+	Timeout *time.Duration
+}
+
+func (a *Action) Canonicalize() {
+	if a.Name == nil {
+		a.Name = stringToPtr("")
+	}
+	if a.Command == nil {
+		a.Command = stringToPtr("")
+	}
+	if a.Timeout == nil {
+		a.Timeout = timeToPtr(1 * time.Minute)
+	}
+}
+
+// Secret is an item of a Vault secret or Nomad variable written to a file in
+// the secrets directory of the task.
+type Secret struct {
+	Name         *string
+	Provider     *string
+	Path         *string
+	Key          *string
+	Perms        *string
+	Uid          *int
+	Gid          *int
+	ChangeMode   *string `mapstructure:"change_mode"`
+	ChangeSignal *string `mapstructure:"change_signal"`
+}
+
+func (s *Secret) Canonicalize() {
+	if s.Name == nil {
+		s.Name = stringToPtr("")
+	}
+	if s.Provider == nil {
+		s.Provider = stringToPtr("")
+	}
+	if s.Path == nil {
+		s.Path = stringToPtr("")
+	}
+	if s.Key == nil {
+		s.Key = stringToPtr("")
+	}
+	if s.Perms == nil {
+		s.Perms = stringToPtr("0400")
+	}
+	if s.Uid == nil {
+		s.Uid = intToPtr(0)
+	}
+	if s.Gid == nil {
+		s.Gid = intToPtr(0)
+	}
+	if s.ChangeMode == nil {
+		s.ChangeMode = stringToPtr("restart")
+	}
+	if s.ChangeSignal == nil {
+		if *s.ChangeMode == "signal" {
+			s.ChangeSignal = stringToPtr("SIGHUP")
+		} else {
+			s.ChangeSignal = stringToPtr("")
+		}
+	} else {
+		s.ChangeSignal = stringToPtr(strings.ToUpper(*s.ChangeSignal))
+	}
 }
 
 // TaskArtifact is used to download artifacts before running a task.
@@ -763,6 +998,7 @@ type Template struct {
 	EmbeddedTmpl *string        `mapstructure:"data"`
 	ChangeMode   *string        `mapstructure:"change_mode"`
 	ChangeSignal *string        `mapstructure:"change_signal"`
+	ChangeScript *ChangeScript  `mapstructure:"change_script"`
 	Splay        *time.Duration `mapstructure:"splay"`
 	Perms        *string        `mapstructure:"perms"`
 	LeftDelim    *string        `mapstructure:"left_delimiter"`
@@ -794,6 +1030,9 @@ func (tmpl *Template) Canonicalize() {
 		sig := *tmpl.ChangeSignal
 		tmpl.ChangeSignal = stringToPtr(strings.ToUpper(sig))
 	}
+	if tmpl.ChangeScript != nil {
+		tmpl.ChangeScript.Canonicalize()
+	}
 	if tmpl.Splay == nil {
 		tmpl.Splay = timeToPtr(5 * time.Second)
 	}
@@ -814,14 +1053,43 @@ func (tmpl *Template) Canonicalize() {
 	}
 }
 
+// ChangeScript is a command executed inside the task when a template with
+// change mode script is re-rendered.
+type ChangeScript struct {
+	Command     *string        `mapstructure:"command"`
+	Args        []string       `mapstructure:"args"`
+	Timeout     *time.Duration `mapstructure:"timeout"`
+	FailOnError *bool          `mapstructure:"fail_on_error"`
+}
+
+func (ch *ChangeScript) Canonicalize() {
+	if ch.Command == nil {
+		ch.Command = stringToPtr("")
+	}
+	if ch.Timeout == nil {
+		ch.Timeout = timeToPtr(5 * time.Second)
+	}
+	if ch.FailOnError == nil {
+		ch.FailOnError = boolToPtr(false)
+	}
+}
+
 type Vault struct {
 	Policies     []string
+	Namespace    *string
+	Cluster      *string
 	Env          *bool
 	ChangeMode   *string `mapstructure:"change_mode"`
 	ChangeSignal *string `mapstructure:"change_signal"`
 }
 
 func (v *Vault) Canonicalize() {
+	if v.Namespace == nil {
+		v.Namespace = stringToPtr("")
+	}
+	if v.Cluster == nil {
+		v.Cluster = stringToPtr("")
+	}
 	if v.Env == nil {
 		v.Env = boolToPtr(true)
 	}
@@ -916,6 +1184,7 @@ const (
 	TaskRestartSignal          = "Restart Signaled"
 	TaskLeaderDead             = "Leader Task Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
+	TaskUnhealthyCheck         = "Unhealthy Check"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -574,6 +574,10 @@ func TestService_CheckRestart(t *testing.T) {
 			{
 				Name: "unset",
 			},
+			{
+				Name: "readiness",
+				Role: ServiceCheckRoleReadiness,
+			},
 		},
 	}
 
@@ -589,6 +593,9 @@ func TestService_CheckRestart(t *testing.T) {
 	assert.Equal(t, service.Checks[2].CheckRestart.Limit, 11)
 	assert.Equal(t, *service.Checks[2].CheckRestart.Grace, 11*time.Second)
 	assert.True(t, service.Checks[2].CheckRestart.IgnoreWarnings)
+
+	// Readiness checks never restart the task
+	assert.Nil(t, service.Checks[3].CheckRestart)
 }
 
 // TestSpread_Canonicalize asserts that the spread stanza is canonicalized correctly
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	// watchMinBackoff and watchMaxBackoff bound the delay before a watch
+	// retries a failed blocking query.
+	watchMinBackoff = 250 * time.Millisecond
+	watchMaxBackoff = 30 * time.Second
+)
+
+// watchQuery runs a blocking query with the given options and returns the
+// result to pass to the handler of the watch.
+type watchQuery func(q *QueryOptions) (interface{}, *QueryMeta, error)
+
+// watch runs the blocking query until the context is done or the handler
+// returns an error, calling the handler with each new result. The index of
+// the query is tracked across calls and reset if the servers report a lower
+// index, and queries that fail with a connection or server error are retried
+// with an exponential backoff. The error of the handler or of a rejected
+// query is returned, or the error of the context once it is done.
+func watch(ctx context.Context, q *QueryOptions, query watchQuery, handler func(interface{}, *QueryMeta) error) error {
+	opts := q.WithContext(ctx)
+	backoff := watchMinBackoff
+
+	for {
+		out, meta, err := query(opts)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err != nil {
+			// Requests the servers rejected won't succeed when retried
+			if isClientError(err) {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > watchMaxBackoff {
+				backoff = watchMaxBackoff
+			}
+			continue
+		}
+		backoff = watchMinBackoff
+
+		// Blocking queries return after the wait time even if nothing
+		// changed, so only results with a new index are handled
+		switch {
+		case meta.LastIndex < opts.WaitIndex:
+			// The index went backwards, for example after the state of
+			// the servers was restored, so start over
+			opts.WaitIndex = 0
+			continue
+		case meta.LastIndex == opts.WaitIndex && opts.WaitIndex != 0:
+			continue
+		}
+
+		if err := handler(out, meta); err != nil {
+			return err
+		}
+		opts.WaitIndex = meta.LastIndex
+	}
+}
+
+// isClientError returns whether the error is a 4xx response of the servers.
+func isClientError(err error) bool {
+	return strings.HasPrefix(err.Error(), "Unexpected response code: 4")
+}
+
+// WatchAllocations calls the handler with the allocations of the job each
+// time they change, starting with their current state, until the context is
+// done or the handler returns an error. Queries that fail with a connection
+// or server error are retried, while the error of rejected queries, such as
+// for an unknown job, is returned.
+func (j *Jobs) WatchAllocations(ctx context.Context, jobID string, allAllocs bool, q *QueryOptions,
+	handler func([]*AllocationListStub, *QueryMeta) error) error {
+
+	query := func(q *QueryOptions) (interface{}, *QueryMeta, error) {
+		return j.Allocations(jobID, allAllocs, q)
+	}
+	return watch(ctx, q, query, func(out interface{}, meta *QueryMeta) error {
+		return handler(out.([]*AllocationListStub), meta)
+	})
+}
+
+// WatchDeployment calls the handler with the deployment each time it
+// changes, starting with its current state, until the context is done or the
+// handler returns an error.
+func (d *Deployments) WatchDeployment(ctx context.Context, deploymentID string, q *QueryOptions,
+	handler func(*Deployment, *QueryMeta) error) error {
+
+	query := func(q *QueryOptions) (interface{}, *QueryMeta, error) {
+		return d.Info(deploymentID, q)
+	}
+	return watch(ctx, q, query, func(out interface{}, meta *QueryMeta) error {
+		return handler(out.(*Deployment), meta)
+	})
+}
+
+// WatchEvaluation calls the handler with the evaluation each time it
+// changes, starting with its current state, until the context is done or the
+// handler returns an error.
+func (e *Evaluations) WatchEvaluation(ctx context.Context, evalID string, q *QueryOptions,
+	handler func(*Evaluation, *QueryMeta) error) error {
+
+	query := func(q *QueryOptions) (interface{}, *QueryMeta, error) {
+		return e.Info(evalID, q)
+	}
+	return watch(ctx, q, query, func(out interface{}, meta *QueryMeta) error {
+		return handler(out.(*Evaluation), meta)
+	})
+}
+
+// WatchNode calls the handler with the node each time it changes, starting
+// with its current state, until the context is done or the handler returns
+// an error.
+func (n *Nodes) WatchNode(ctx context.Context, nodeID string, q *QueryOptions,
+	handler func(*Node, *QueryMeta) error) error {
+
+	query := func(q *QueryOptions) (interface{}, *QueryMeta, error) {
+		return n.Info(nodeID, q)
+	}
+	return watch(ctx, q, query, func(out interface{}, meta *QueryMeta) error {
+		return handler(out.(*Node), meta)
+	})
+}
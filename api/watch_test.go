@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testWatchServer serves a sequence of responses to the allocations of a job,
+// given as their index, or an error status code if negative.
+func testWatchServer(t *testing.T, responses []int) (*Client, *httptest.Server, func() []uint64) {
+	var mu sync.Mutex
+	var waits []uint64
+	i := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		wait, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+		waits = append(waits, wait)
+
+		if i >= len(responses) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp := responses[i]
+		i++
+		if resp < 0 {
+			w.WriteHeader(-resp)
+			return
+		}
+		w.Header().Set("X-Nomad-Index", strconv.Itoa(resp))
+		w.Write([]byte(`[{"ID": "` + strconv.Itoa(resp) + `"}]`))
+	}))
+
+	conf := DefaultConfig()
+	conf.Address = ts.URL
+	client, err := NewClient(conf)
+	require.NoError(t, err)
+	return client, ts, func() []uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return waits
+	}
+}
+
+func TestJobs_WatchAllocations(t *testing.T) {
+	t.Parallel()
+	client, ts, waits := testWatchServer(t, []int{5, 5, -500, 7, 3, 3})
+	defer ts.Close()
+
+	var seen []string
+	errDone := errors.New("done")
+	err := client.Jobs().WatchAllocations(context.Background(), "example", false, nil,
+		func(allocs []*AllocationListStub, meta *QueryMeta) error {
+			require.Len(t, allocs, 1)
+			seen = append(seen, allocs[0].ID)
+			if allocs[0].ID == "3" {
+				return errDone
+			}
+			return nil
+		})
+	require.Equal(t, errDone, err)
+
+	// Unchanged results and server errors are skipped, and the index is
+	// reset when it goes backwards
+	require.Equal(t, []string{"5", "7", "3"}, seen)
+	require.Equal(t, []uint64{0, 5, 5, 5, 7, 0}, waits())
+}
+
+func TestJobs_WatchAllocations_Errors(t *testing.T) {
+	t.Parallel()
+
+	// Rejected queries are returned
+	client, ts, _ := testWatchServer(t, []int{5})
+	defer ts.Close()
+	calls := 0
+	err := client.Jobs().WatchAllocations(context.Background(), "example", false, nil,
+		func([]*AllocationListStub, *QueryMeta) error {
+			calls++
+			return nil
+		})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "404")
+	require.Equal(t, 1, calls)
+
+	// Canceling the context stops the watch
+	client, ts2, _ := testWatchServer(t, []int{-500, -500, -500})
+	defer ts2.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.Jobs().WatchAllocations(ctx, "example", false, nil,
+		func([]*AllocationListStub, *QueryMeta) error {
+			t.Fatal("unexpected call")
+			return nil
+		})
+	require.Equal(t, context.Canceled, err)
+}
@@ -101,6 +101,46 @@ func TestClient_ACL_resolvePolicies(t *testing.T) {
 	}
 }
 
+func TestClient_ACL_resolveRoles(t *testing.T) {
+	s1, _, root := testACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	// Create a role / token
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+	assert.Nil(t, s1.State().UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	assert.Nil(t, s1.State().UpsertACLRoles(110, []*structs.ACLRole{role}))
+	assert.Nil(t, s1.State().UpsertACLTokens(120, []*structs.ACLToken{token}))
+
+	// Test the client resolution
+	out, err := c1.resolveRoles(root.SecretID, []string{role.Name})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(out))
+
+	// Test caching
+	out2, err := c1.resolveRoles(root.SecretID, []string{role.Name})
+	assert.Nil(t, err)
+	if out[0] != out2[0] {
+		t.Fatalf("bad caching")
+	}
+
+	// The token is granted the policies of its role
+	aclObj, err := c1.ResolveToken(token.SecretID)
+	assert.Nil(t, err)
+	assert.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilitySubmitJob))
+}
+
 func TestClient_ACL_ResolveToken_Disabled(t *testing.T) {
 	s1, _ := testServer(t, nil)
 	defer s1.Shutdown()
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"fmt"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -48,6 +49,63 @@ func (a *Allocations) GarbageCollect(args *nstructs.AllocSpecificRequest, reply
 	return nil
 }
 
+// Restart is used to restart the tasks of an allocation in place.
+func (a *Allocations) Restart(args *cstructs.AllocRestartRequest, reply *cstructs.AllocRestartResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "restart"}, time.Now())
+
+	// Check submit job permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	healthy, err := a.c.RestartAllocation(args.AllocID, args.TaskName, args.Ordered, args.WaitHealthy)
+	if err != nil {
+		return err
+	}
+
+	reply.Healthy = healthy
+	return nil
+}
+
+// RunAction is used to run one of a task's actions inside the allocation.
+func (a *Allocations) RunAction(args *cstructs.AllocActionRequest, reply *cstructs.AllocActionResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "run_action"}, time.Now())
+
+	// Check run action permissions. Exec permissions allow running any
+	// command in the allocation, including its actions.
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityRunAction) &&
+		!aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityAllocExec) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	output, exitCode, err := a.c.RunAction(args.AllocID, args.Task, args.Action)
+	if err != nil {
+		return err
+	}
+
+	reply.Output = output
+	reply.ExitCode = exitCode
+	return nil
+}
+
+// Signal is used to send a signal to the tasks of an allocation.
+func (a *Allocations) Signal(args *cstructs.AllocSignalRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "signal"}, time.Now())
+
+	// Check submit job permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return a.c.SignalAllocation(args.AllocID, args.Task, args.Signal)
+}
+
 // Stats is used to collect allocation statistics
 func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.AllocStatsResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "stats"}, time.Now())
@@ -70,6 +128,19 @@ func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.Al
 		return err
 	}
 
+	if args.Start != 0 {
+		end := args.End
+		if end == 0 {
+			end = time.Now().UnixNano()
+		}
+		if end < args.Start {
+			return fmt.Errorf("end of the stats range must not be before its start")
+		}
+		if stats.History, err = aStats.AllocStatsHistory(args.Task, args.Start, end); err != nil {
+			return err
+		}
+	}
+
 	reply.Stats = stats
 	return nil
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
@@ -175,6 +176,374 @@ func TestAllocations_GarbageCollect_ACL(t *testing.T) {
 	}
 }
 
+func TestAllocations_Restart(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "30s",
+	}
+	a.Job.TaskGroups[0].Update = &nstructs.UpdateStrategy{
+		HealthCheck:     nstructs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:  100 * time.Millisecond,
+		HealthyDeadline: 10 * time.Second,
+	}
+	require.Nil(client.addAlloc(a, ""))
+
+	// Try with bad alloc
+	req := &cstructs.AllocRestartRequest{}
+	var resp cstructs.AllocRestartResponse
+	err := client.ClientRPC("Allocations.Restart", &req, &resp)
+	require.True(nstructs.IsErrUnknownAllocation(err))
+
+	// Wait for the task to be running
+	taskName := a.Job.TaskGroups[0].Tasks[0].Name
+	testutil.WaitForResult(func() (bool, error) {
+		ar, err := client.getAllocRunner(a.ID)
+		if err != nil {
+			return false, err
+		}
+		state := ar.AllocState().TaskStates[taskName]
+		if state == nil || state.State != nstructs.TaskStateRunning {
+			return false, fmt.Errorf("task not running: %#v", state)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Restart the alloc and wait for it to be healthy
+	req.AllocID = a.ID
+	req.WaitHealthy = true
+	require.Nil(client.ClientRPC("Allocations.Restart", &req, &resp))
+	require.True(resp.Healthy)
+
+	ar, err := client.getAllocRunner(a.ID)
+	require.Nil(err)
+	state := ar.AllocState().TaskStates[taskName]
+	require.Equal(nstructs.TaskStateRunning, state.State)
+
+	found := false
+	for _, e := range state.Events {
+		if e.Type == nstructs.TaskRestartSignal {
+			found = true
+		}
+	}
+	require.True(found, "missing restart event: %#v", state.Events)
+}
+
+func TestAllocations_Restart_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	// Try request without a token and expect failure
+	{
+		req := &cstructs.AllocRestartRequest{}
+		var resp cstructs.AllocRestartResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a read only token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocRestartRequest{}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp cstructs.AllocRestartResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+		req := &cstructs.AllocRestartRequest{}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp cstructs.AllocRestartResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &cstructs.AllocRestartRequest{}
+		req.AuthToken = root.SecretID
+
+		var resp cstructs.AllocRestartResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
+func TestAllocations_RunAction(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	a := mock.Alloc()
+	task := a.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "30s",
+	}
+	task.Actions = []*nstructs.Action{
+		{
+			Name:    "flush",
+			Command: "/bin/flush",
+			Args:    []string{"-all"},
+			Timeout: 5 * time.Second,
+		},
+	}
+	require.Nil(client.addAlloc(a, ""))
+
+	// Try with bad alloc
+	req := &cstructs.AllocActionRequest{}
+	var resp cstructs.AllocActionResponse
+	err := client.ClientRPC("Allocations.RunAction", &req, &resp)
+	require.True(nstructs.IsErrUnknownAllocation(err))
+
+	// Wait for the task to be running
+	testutil.WaitForResult(func() (bool, error) {
+		ar, err := client.getAllocRunner(a.ID)
+		if err != nil {
+			return false, err
+		}
+		state := ar.AllocState().TaskStates[task.Name]
+		if state == nil || state.State != nstructs.TaskStateRunning {
+			return false, fmt.Errorf("task not running: %#v", state)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Try with an unknown action
+	req.AllocID = a.ID
+	req.Task = task.Name
+	req.Action = "unknown"
+	err = client.ClientRPC("Allocations.RunAction", &req, &resp)
+	require.EqualError(err, fmt.Sprintf("Task %q has no action %q", task.Name, "unknown"))
+
+	// Run the action
+	req.Action = "flush"
+	require.Nil(client.ClientRPC("Allocations.RunAction", &req, &resp))
+	require.Equal(0, resp.ExitCode)
+	require.Contains(string(resp.Output), "/bin/flush")
+	require.Contains(string(resp.Output), "-all")
+}
+
+func TestAllocations_RunAction_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	// Try request without a token and expect failure
+	{
+		req := &cstructs.AllocActionRequest{}
+		var resp cstructs.AllocActionResponse
+		err := client.ClientRPC("Allocations.RunAction", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a read only token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocActionRequest{}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp cstructs.AllocActionResponse
+		err := client.ClientRPC("Allocations.RunAction", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityRunAction}))
+		req := &cstructs.AllocActionRequest{}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp cstructs.AllocActionResponse
+		err := client.ClientRPC("Allocations.RunAction", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &cstructs.AllocActionRequest{}
+		req.AuthToken = root.SecretID
+
+		var resp cstructs.AllocActionResponse
+		err := client.ClientRPC("Allocations.RunAction", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
+func TestAllocations_Signal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "30s",
+	}
+	require.Nil(client.addAlloc(a, ""))
+
+	// Try with bad alloc
+	req := &cstructs.AllocSignalRequest{Signal: "SIGHUP"}
+	var resp nstructs.GenericResponse
+	err := client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.True(nstructs.IsErrUnknownAllocation(err))
+
+	// Wait for the task to be running
+	taskName := a.Job.TaskGroups[0].Tasks[0].Name
+	testutil.WaitForResult(func() (bool, error) {
+		ar, err := client.getAllocRunner(a.ID)
+		if err != nil {
+			return false, err
+		}
+		state := ar.AllocState().TaskStates[taskName]
+		if state == nil || state.State != nstructs.TaskStateRunning {
+			return false, fmt.Errorf("task not running: %#v", state)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Try with a bad signal and a bad task
+	req.AllocID = a.ID
+	req.Signal = "SIGFOO"
+	err = client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "invalid signal")
+
+	req.Signal = "sighup"
+	req.Task = "foo"
+	err = client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), `task "foo" not found`)
+
+	// Signal the task
+	req.Task = taskName
+	require.Nil(client.ClientRPC("Allocations.Signal", &req, &resp))
+
+	// Task states reach the alloc runner asynchronously
+	testutil.WaitForResult(func() (bool, error) {
+		ar, err := client.getAllocRunner(a.ID)
+		if err != nil {
+			return false, err
+		}
+		state := ar.AllocState().TaskStates[taskName]
+		if state == nil || state.State != nstructs.TaskStateRunning {
+			return false, fmt.Errorf("task not running: %#v", state)
+		}
+		for _, e := range state.Events {
+			if e.Type == nstructs.TaskSignaling && e.TaskSignal == "hangup" {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("missing signal event: %#v", state.Events)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestAllocations_Signal_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	// Try request without a token and expect failure
+	{
+		req := &cstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a read only token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &cstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+		req := &cstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &cstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		req.AuthToken = root.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
 func TestAllocations_Stats(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -206,6 +575,15 @@ func TestAllocations_Stats(t *testing.T) {
 	}, func(err error) {
 		t.Fatalf("err: %v", err)
 	})
+
+	// The history of each task is returned when a range is requested
+	req.Start = time.Now().Add(-time.Minute).UnixNano()
+	require.NoError(client.ClientRPC("Allocations.Stats", &req, &resp))
+	require.Contains(resp.Stats.History, "web")
+
+	// Invalid ranges are rejected
+	req.End = req.Start - 1
+	require.Error(client.ClientRPC("Allocations.Stats", &req, &resp))
 }
 
 func TestAllocations_Stats_ACL(t *testing.T) {
@@ -2,7 +2,9 @@ package allocdir
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -30,6 +32,10 @@ var (
 	// error file written by Snapshot when it encounters as error.
 	SnapshotErrorTime = time.Date(2000, 0, 0, 0, 0, 0, 0, time.UTC)
 
+	// ArchiveChecksumFile is the name of the manifest of checksums written
+	// at the end of archives by Archive.
+	ArchiveChecksumFile = "SHA256SUMS"
+
 	// The name of the directory that is shared across tasks in a task group.
 	SharedAllocName = "alloc"
 
@@ -59,6 +65,23 @@ var (
 	TaskDirs = map[string]os.FileMode{TmpDirName: os.ModeSticky | 0777}
 )
 
+// DirOwner is the dynamic user and group an allocation's directories belong
+// to. Only they may access the directories.
+type DirOwner struct {
+	UID int
+	GID int
+}
+
+// setDirPermissions sets the permissions of a directory of the allocation.
+// Without a dynamic owner the directory is given to nobody and opened to all
+// users.
+func (o *DirOwner) setDirPermissions(path string, desired os.FileMode) error {
+	if o == nil {
+		return dropDirPermissions(path, desired)
+	}
+	return restrictDirPermissions(path, desired, o.UID, o.GID)
+}
+
 // AllocDir allows creating, destroying, and accessing an allocation's
 // directory. All methods are safe for concurrent use.
 type AllocDir struct {
@@ -73,6 +96,11 @@ type AllocDir struct {
 	// TaskDirs is a mapping of task names to their non-shared directory.
 	TaskDirs map[string]*TaskDir
 
+	// Owner is the dynamic user owning the directories of the allocation, or
+	// nil if the allocation wasn't assigned one. It must be set before the
+	// task directories are created.
+	Owner *DirOwner
+
 	// built is true if Build has successfully run
 	built bool
 
@@ -86,6 +114,8 @@ type AllocDirFS interface {
 	List(path string) ([]*cstructs.AllocFileInfo, error)
 	Stat(path string) (*cstructs.AllocFileInfo, error)
 	ReadAt(path string, offset int64) (io.ReadCloser, error)
+	Write(path string, r io.Reader, mode os.FileMode) error
+	Archive(w io.Writer, path string, checksum bool) error
 	Snapshot(w io.Writer) error
 	BlockUntilExists(ctx context.Context, path string) (chan error, error)
 	ChangeEvents(ctx context.Context, path string, curOffset int64) (*watch.FileChanges, error)
@@ -116,6 +146,7 @@ func (d *AllocDir) Copy() *AllocDir {
 		AllocDir:  d.AllocDir,
 		SharedDir: d.SharedDir,
 		TaskDirs:  make(map[string]*TaskDir, len(d.TaskDirs)),
+		Owner:     d.Owner,
 		logger:    d.logger,
 	}
 	for k, v := range d.TaskDirs {
@@ -130,6 +161,7 @@ func (d *AllocDir) NewTaskDir(name string) *TaskDir {
 	defer d.mu.Unlock()
 
 	td := newTaskDir(d.logger, d.AllocDir, name)
+	td.Owner = d.Owner
 	d.TaskDirs[name] = td
 	return td
 }
@@ -239,6 +271,9 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 		if err := os.Rename(otherDataDir, dataDir); err != nil {
 			return fmt.Errorf("error moving data dir: %v", err)
 		}
+		if err := d.chownMoved(dataDir); err != nil {
+			return err
+		}
 	}
 
 	// Move the task directories
@@ -258,12 +293,27 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 			if err := os.Rename(otherTaskLocal, localDir); err != nil {
 				return fmt.Errorf("error moving task %q local dir: %v", task.Name, err)
 			}
+			if err := d.chownMoved(localDir); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
+// chownMoved gives the files moved from a previous allocation to the dynamic
+// owner of this allocation, as they belong to the owner of the previous one.
+func (d *AllocDir) chownMoved(path string) error {
+	if d.Owner == nil {
+		return nil
+	}
+	if err := chownTree(path, d.Owner.UID, d.Owner.GID); err != nil {
+		return fmt.Errorf("error changing owner of moved dir %q: %v", path, err)
+	}
+	return nil
+}
+
 // Tears down previously build directory structure.
 func (d *AllocDir) Destroy() error {
 	// Unmount all mounted shared alloc dirs.
@@ -272,8 +322,15 @@ func (d *AllocDir) Destroy() error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
-	if err := os.RemoveAll(d.AllocDir); err != nil {
-		mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to remove alloc dir %q: %v", d.AllocDir, err))
+	// Rename the alloc dir aside and delete it in the background so that
+	// deleting large alloc dirs doesn't stall the disk. Fall back to deleting
+	// it in place if it can't be renamed.
+	if aside, err := renameAside(d.AllocDir); err == nil {
+		deleteAsync(d.logger, aside)
+	} else if !os.IsNotExist(err) {
+		if err := os.RemoveAll(d.AllocDir); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to remove alloc dir %q: %v", d.AllocDir, err))
+		}
 	}
 
 	// Unset built since the alloc dir has been destroyed.
@@ -330,7 +387,7 @@ func (d *AllocDir) Build() error {
 	}
 
 	// Make the shared directory have non-root permissions.
-	if err := dropDirPermissions(d.SharedDir, os.ModePerm); err != nil {
+	if err := d.Owner.setDirPermissions(d.SharedDir, os.ModePerm); err != nil {
 		return err
 	}
 
@@ -340,7 +397,7 @@ func (d *AllocDir) Build() error {
 		if err := os.MkdirAll(p, 0777); err != nil {
 			return err
 		}
-		if err := dropDirPermissions(p, os.ModePerm); err != nil {
+		if err := d.Owner.setDirPermissions(p, os.ModePerm); err != nil {
 			return err
 		}
 	}
@@ -431,6 +488,151 @@ func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
 	return f, nil
 }
 
+// Write writes the content of the reader to the file at the path relative to
+// the alloc dir, creating the file and its parent directories if needed.
+func (d *AllocDir) Write(path string, r io.Reader, mode os.FileMode) error {
+	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
+		return fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+
+	// Check if it is trying to write into a secret directory
+	d.mu.RLock()
+	for _, dir := range d.TaskDirs {
+		if filepath.HasPrefix(p, dir.SecretsDir) {
+			d.mu.RUnlock()
+			return fmt.Errorf("Writing secret file prohibited: %s", path)
+		}
+	}
+	d.mu.RUnlock()
+
+	if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Archive writes a tar archive of the directory at the path relative to the
+// alloc dir to the writer. The files are named relative to the directory and
+// the secret directories of the tasks are skipped. If checksum is set, a
+// manifest of the SHA-256 checksums of the files, in the format of sha256sum,
+// is written as the last entry of the archive.
+func (d *AllocDir) Archive(w io.Writer, path string, checksum bool) error {
+	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
+		return fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	root := filepath.Join(d.AllocDir, path)
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("file %q is not a directory", path)
+	}
+
+	d.mu.RLock()
+	var secretDirs []string
+	for _, dir := range d.TaskDirs {
+		secretDirs = append(secretDirs, dir.SecretsDir)
+	}
+	d.mu.RUnlock()
+
+	tw := tar.NewWriter(w)
+	var manifest bytes.Buffer
+
+	walkFn := func(p string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+
+		// Skip the secrets of the tasks
+		for _, dir := range secretDirs {
+			if p == dir {
+				return filepath.SkipDir
+			}
+		}
+
+		relPath, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		link := ""
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(p)
+			if err != nil {
+				return fmt.Errorf("error reading symlink: %v", err)
+			}
+			link = target
+		}
+		hdr, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return fmt.Errorf("error creating file header: %v", err)
+		}
+		hdr.Name = filepath.ToSlash(relPath)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		// Only the content of regular files is written into the archive
+		if !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(tw, h), file); err != nil {
+			return err
+		}
+		fmt.Fprintf(&manifest, "%x  %s\n", h.Sum(nil), hdr.Name)
+		return nil
+	}
+
+	if err := filepath.Walk(root, walkFn); err != nil {
+		return fmt.Errorf("failed to archive %s: %v", path, err)
+	}
+
+	if checksum {
+		hdr := &tar.Header{
+			Name:     ArchiveChecksumFile,
+			Mode:     0644,
+			Size:     int64(manifest.Len()),
+			ModTime:  time.Now(),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(manifest.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return tw.Close()
+}
+
 // BlockUntilExists blocks until the passed file relative the allocation
 // directory exists. The block can be cancelled with the passed context.
 func (d *AllocDir) BlockUntilExists(ctx context.Context, path string) (chan error, error) {
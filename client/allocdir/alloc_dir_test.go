@@ -4,6 +4,8 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -318,6 +320,11 @@ func TestAllocDir_EscapeChecking(t *testing.T) {
 		t.Fatalf("ReadAt of escaping path didn't error: %v", err)
 	}
 
+	// Write
+	if err := d.Write("../foo", strings.NewReader("foo"), 0644); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("Write of escaping path didn't error: %v", err)
+	}
+
 	// BlockUntilExists
 	if _, err := d.BlockUntilExists(context.Background(), "../foo"); err == nil || !strings.Contains(err.Error(), "escapes") {
 		t.Fatalf("BlockUntilExists of escaping path didn't error: %v", err)
@@ -355,6 +362,99 @@ func TestAllocDir_ReadAt_SecretDir(t *testing.T) {
 	}
 }
 
+// Test that files can be written in the alloc dir but not in secrets
+func TestAllocDir_Write(t *testing.T) {
+	require := require.New(t)
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp)
+	require.NoError(d.Build())
+	defer d.Destroy()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(td.Build(false, nil))
+
+	// Write a file in a missing directory
+	p := filepath.Join(t1.Name, TaskLocal, "debug", "script.sh")
+	require.NoError(d.Write(p, strings.NewReader("foo"), 0700))
+
+	info, err := os.Stat(filepath.Join(tmp, p))
+	require.NoError(err)
+	require.Equal(os.FileMode(0700), info.Mode().Perm())
+
+	// Overwriting truncates the file
+	require.NoError(d.Write(p, strings.NewReader("b"), 0700))
+	contents, err := ioutil.ReadFile(filepath.Join(tmp, p))
+	require.NoError(err)
+	require.Equal("b", string(contents))
+
+	// Write of secret dir should fail
+	secret := filepath.Join(t1.Name, TaskSecrets, "test_file")
+	err = d.Write(secret, strings.NewReader("foo"), 0644)
+	require.Error(err)
+	require.Contains(err.Error(), "secret file prohibited")
+}
+
+// Test that directories are archived without their secrets
+func TestAllocDir_Archive(t *testing.T) {
+	require := require.New(t)
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp)
+	require.NoError(d.Build())
+	defer d.Destroy()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(td.Build(false, nil))
+
+	require.NoError(d.Write(filepath.Join(t1.Name, TaskLocal, "a.txt"), strings.NewReader("foo"), 0644))
+	require.NoError(d.Write(filepath.Join(t1.Name, TaskLocal, "sub", "b.txt"), strings.NewReader("bar"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(td.SecretsDir, "token"), []byte("secret"), 0600))
+
+	var buf bytes.Buffer
+	require.NoError(d.Archive(&buf, t1.Name, true))
+
+	files := map[string]string{}
+	var last string
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		require.False(strings.HasPrefix(hdr.Name, TaskSecrets), "archived %q", hdr.Name)
+
+		contents, err := ioutil.ReadAll(tr)
+		require.NoError(err)
+		files[hdr.Name] = string(contents)
+		last = hdr.Name
+	}
+
+	require.Equal("foo", files["local/a.txt"])
+	require.Equal("bar", files["local/sub/b.txt"])
+	require.Contains(files, "local/sub")
+
+	// The checksums are written last
+	require.Equal(ArchiveChecksumFile, last)
+	sum := sha256.Sum256([]byte("bar"))
+	require.Contains(files[ArchiveChecksumFile], fmt.Sprintf("%x  local/sub/b.txt\n", sum))
+
+	// Files can't be archived
+	err = d.Archive(&buf, filepath.Join(t1.Name, TaskLocal, "a.txt"), false)
+	require.Error(err)
+	require.Contains(err.Error(), "not a directory")
+
+	// Paths can't escape the alloc dir
+	err = d.Archive(&buf, "../", false)
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+}
+
 func TestAllocDir_SplitPath(t *testing.T) {
 	dir, err := ioutil.TempDir("", "tmpdirtest")
 	if err != nil {
@@ -438,6 +538,35 @@ func TestAllocDir_Copy(t *testing.T) {
 	}
 }
 
+// TestAllocDir_Owner asserts that the directories of an allocation with a
+// dynamic owner belong to it and are only accessible by it.
+func TestAllocDir_Owner(t *testing.T) {
+	MountCompatible(t)
+
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp)
+	d.Owner = &DirOwner{UID: 80000, GID: 80001}
+	defer d.Destroy()
+	td := d.NewTaskDir(t1.Name)
+	require.Equal(t, d.Owner, td.Owner)
+
+	require.NoError(t, d.Build())
+	require.NoError(t, td.Build(false, nil))
+
+	for _, dir := range []string{d.SharedDir, td.Dir, td.LocalDir, td.SecretsDir} {
+		fi, err := os.Stat(dir)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0700), fi.Mode().Perm(), dir)
+
+		uid, gid := getOwner(fi)
+		require.Equal(t, 80000, uid, dir)
+		require.Equal(t, 80001, gid, dir)
+	}
+}
+
 func TestPathFuncs(t *testing.T) {
 	dir, err := ioutil.TempDir("", "nomadtest-pathfuncs")
 	if err != nil {
@@ -0,0 +1,136 @@
+package allocdir
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// destroyedDirPrefix prefixes the names the destroyed alloc dirs are
+	// renamed to until they are deleted.
+	destroyedDirPrefix = ".destroyed-"
+
+	// deleteChunkSize is the size by which large files are truncated when
+	// they are deleted, so that freeing their blocks doesn't stall the disk.
+	deleteChunkSize = 16 * 1024 * 1024
+
+	// deleteRate is the rate in bytes per second at which the files of the
+	// destroyed alloc dirs are deleted, across all the deletions.
+	deleteRate = 256 * 1024 * 1024
+)
+
+var (
+	// deleteLimiter limits the rate of the deletions.
+	deleteLimiter = rate.NewLimiter(rate.Limit(deleteRate), deleteChunkSize)
+
+	// deletes is the number of alloc dirs being deleted and deletesDoneCh is
+	// closed once they are all deleted.
+	deletes       int
+	deletesDoneCh chan struct{}
+	deletesLock   sync.Mutex
+)
+
+// renameAside renames the alloc dir to a unique sibling path to be deleted in
+// the background, and returns that path.
+func renameAside(dir string) (string, error) {
+	name := fmt.Sprintf("%s%s-%s", destroyedDirPrefix, filepath.Base(dir), uuid.Generate()[:8])
+	aside := filepath.Join(filepath.Dir(dir), name)
+	if err := os.Rename(dir, aside); err != nil {
+		return "", err
+	}
+	return aside, nil
+}
+
+// DeleteDestroyed deletes in the background the destroyed alloc dirs found
+// in the given directory, which were renamed aside but not yet deleted when
+// the client stopped.
+func DeleteDestroyed(logger hclog.Logger, root string) {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		logger.Warn("failed to list destroyed alloc dirs", "alloc_dir", root, "error", err)
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), destroyedDirPrefix) {
+			deleteAsync(logger, filepath.Join(root, entry.Name()))
+		}
+	}
+}
+
+// DeletesDone returns a channel that is closed once the destroyed alloc dirs
+// being deleted in the background are deleted. The space they use isn't freed
+// until then.
+func DeletesDone() <-chan struct{} {
+	deletesLock.Lock()
+	defer deletesLock.Unlock()
+
+	if deletes == 0 {
+		doneCh := make(chan struct{})
+		close(doneCh)
+		return doneCh
+	}
+	return deletesDoneCh
+}
+
+// deleteAsync deletes the directory in the background.
+func deleteAsync(logger hclog.Logger, dir string) {
+	deletesLock.Lock()
+	if deletes == 0 {
+		deletesDoneCh = make(chan struct{})
+	}
+	deletes++
+	deletesLock.Unlock()
+
+	go func() {
+		deleteDir(logger, dir)
+
+		deletesLock.Lock()
+		deletes--
+		if deletes == 0 {
+			close(deletesDoneCh)
+		}
+		deletesLock.Unlock()
+	}()
+}
+
+// deleteDir deletes the directory at the rate of the deletions. Large files
+// are truncated gradually before being removed, unless they are hard linked
+// from outside the directory such as the files of chroots.
+func deleteDir(logger hclog.Logger, dir string) {
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() || getLinkCount(info) != 1 {
+			return nil
+		}
+
+		for size := info.Size(); size > 0; {
+			chunk := size
+			if chunk > deleteChunkSize {
+				chunk = deleteChunkSize
+			}
+			deleteLimiter.WaitN(context.Background(), int(chunk))
+
+			size -= chunk
+			if size == 0 {
+				break
+			}
+			if err := os.Truncate(path, size); err != nil {
+				break
+			}
+		}
+		return nil
+	})
+
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Warn("failed to delete destroyed alloc dir", "path", dir, "error", err)
+	}
+}
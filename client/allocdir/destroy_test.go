@@ -0,0 +1,96 @@
+package allocdir
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// waitDeletes waits for the background deletions to finish.
+func waitDeletes(t *testing.T) {
+	select {
+	case <-DeletesDone():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("timed out waiting for the alloc dirs to be deleted")
+	}
+}
+
+// Test that destroyed alloc dirs are renamed aside and deleted in the
+// background
+func TestAllocDir_Destroy_Background(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	d := NewAllocDir(testlog.HCLogger(t), filepath.Join(root, "alloc"))
+	require.NoError(d.Build())
+	require.NoError(ioutil.WriteFile(filepath.Join(d.SharedDir, "data", "out"), []byte("foo"), 0666))
+
+	require.NoError(d.Destroy())
+	_, err = os.Stat(d.AllocDir)
+	require.True(os.IsNotExist(err), "alloc dir wasn't destroyed: %v", err)
+
+	waitDeletes(t)
+	entries, err := ioutil.ReadDir(root)
+	require.NoError(err)
+	require.Empty(entries)
+}
+
+// Test that files hard linked into the deleted dirs aren't truncated
+func TestAllocDir_DeleteDir_HardLinks(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	const size = 2 * deleteChunkSize
+	outside := filepath.Join(root, "outside")
+	require.NoError(ioutil.WriteFile(outside, []byte("foo"), 0666))
+	require.NoError(os.Truncate(outside, size))
+
+	dir := filepath.Join(root, destroyedDirPrefix+"alloc")
+	require.NoError(os.MkdirAll(filepath.Join(dir, "bin"), 0777))
+	require.NoError(os.Link(outside, filepath.Join(dir, "bin", "linked")))
+	large := filepath.Join(dir, "large")
+	require.NoError(ioutil.WriteFile(large, []byte("foo"), 0666))
+	require.NoError(os.Truncate(large, size))
+
+	deleteDir(testlog.HCLogger(t), dir)
+
+	_, err = os.Stat(dir)
+	require.True(os.IsNotExist(err), "dir wasn't deleted: %v", err)
+
+	fi, err := os.Stat(outside)
+	require.NoError(err)
+	require.EqualValues(size, fi.Size())
+}
+
+// Test that the alloc dirs destroyed before a restart are deleted
+func TestAllocDir_DeleteDestroyed(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	destroyed := filepath.Join(root, destroyedDirPrefix+"alloc-1234")
+	require.NoError(os.MkdirAll(filepath.Join(destroyed, "alloc", "data"), 0777))
+	live := filepath.Join(root, "alloc")
+	require.NoError(os.MkdirAll(live, 0777))
+
+	DeleteDestroyed(testlog.HCLogger(t), root)
+	waitDeletes(t)
+
+	_, err = os.Stat(destroyed)
+	require.True(os.IsNotExist(err), "destroyed dir wasn't deleted: %v", err)
+	_, err = os.Stat(live)
+	require.NoError(err)
+}
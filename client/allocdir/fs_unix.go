@@ -61,6 +61,44 @@ func dropDirPermissions(path string, desired os.FileMode) error {
 	return nil
 }
 
+// restrictDirPermissions only gives access to a directory to its owner and
+// sets the owner to the given user and group.
+func restrictDirPermissions(path string, desired os.FileMode, uid, gid int) error {
+	if err := os.Chmod(path, desired&os.ModeSticky|0700); err != nil {
+		return fmt.Errorf("Chmod(%v) failed: %v", path, err)
+	}
+
+	// Can't change owner if not root.
+	if unix.Geteuid() != 0 {
+		return nil
+	}
+
+	if err := os.Chown(path, uid, gid); err != nil {
+		return fmt.Errorf("Couldn't change owner/group of %v to (uid: %v, gid: %v): %v", path, uid, gid, err)
+	}
+
+	return nil
+}
+
+// chownTree sets the owner of a directory and everything in it to the given
+// user and group.
+func chownTree(path string, uid, gid int) error {
+	// Can't change owner if not root.
+	if unix.Geteuid() != 0 {
+		return nil
+	}
+
+	return filepath.Walk(path, func(p string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := os.Lchown(p, uid, gid); err != nil {
+			return fmt.Errorf("Couldn't change owner/group of %v to (uid: %v, gid: %v): %v", p, uid, gid, err)
+		}
+		return nil
+	})
+}
+
 // getUid for a user
 func getUid(u *user.User) (int, error) {
 	uid, err := strconv.Atoi(u.Uid)
@@ -105,3 +143,12 @@ func getOwner(fi os.FileInfo) (int, int) {
 	}
 	return int(stat.Uid), int(stat.Gid)
 }
+
+// getLinkCount returns the number of hard links to the file.
+func getLinkCount(fi os.FileInfo) uint64 {
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	return uint64(stat.Nlink)
+}
@@ -49,6 +49,16 @@ func dropDirPermissions(path string, desired os.FileMode) error {
 	return nil
 }
 
+// The windows version does nothing currently.
+func restrictDirPermissions(path string, desired os.FileMode, uid, gid int) error {
+	return nil
+}
+
+// The windows version does nothing currently.
+func chownTree(path string, uid, gid int) error {
+	return nil
+}
+
 // MountSpecialDirs mounts the dev and proc file system on the chroot of the
 // task. It's a no-op on windows.
 func MountSpecialDirs(taskDir string) error {
@@ -59,3 +69,9 @@ func MountSpecialDirs(taskDir string) error {
 func getOwner(os.FileInfo) (int, int) {
 	return idUnsupported, idUnsupported
 }
+
+// getLinkCount isn't supported on Windows, so files are never truncated
+// before being deleted.
+func getLinkCount(os.FileInfo) uint64 {
+	return 0
+}
@@ -39,6 +39,10 @@ type TaskDir struct {
 	// <task_dir>/secrets/
 	SecretsDir string
 
+	// Owner is the dynamic user owning the directories of the allocation, or
+	// nil if the allocation wasn't assigned one.
+	Owner *DirOwner
+
 	logger hclog.Logger
 }
 
@@ -80,7 +84,7 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 	}
 
 	// Make the task directory have non-root permissions.
-	if err := dropDirPermissions(t.Dir, os.ModePerm); err != nil {
+	if err := t.Owner.setDirPermissions(t.Dir, os.ModePerm); err != nil {
 		return err
 	}
 
@@ -89,7 +93,7 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 		return err
 	}
 
-	if err := dropDirPermissions(t.LocalDir, os.ModePerm); err != nil {
+	if err := t.Owner.setDirPermissions(t.LocalDir, os.ModePerm); err != nil {
 		return err
 	}
 
@@ -100,7 +104,7 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 			return err
 		}
 
-		if err := dropDirPermissions(absdir, perms); err != nil {
+		if err := t.Owner.setDirPermissions(absdir, perms); err != nil {
 			return err
 		}
 	}
@@ -124,7 +128,7 @@ func (t *TaskDir) Build(createChroot bool, chroot map[string]string) error {
 		return err
 	}
 
-	if err := dropDirPermissions(t.SecretsDir, os.ModePerm); err != nil {
+	if err := t.Owner.setDirPermissions(t.SecretsDir, os.ModePerm); err != nil {
 		return err
 	}
 
@@ -110,6 +110,11 @@ func NewTracker(parentCtx context.Context, logger hclog.Logger, alloc *structs.A
 
 	for _, task := range t.tg.Tasks {
 		for _, s := range task.Services {
+			// Checks of services of other providers aren't registered
+			// with Consul
+			if !s.UsesConsul() {
+				continue
+			}
 			t.consulCheckCount += len(s.Checks)
 		}
 	}
@@ -419,6 +424,9 @@ func (t *taskHealthState) event(deadline time.Time, minHealthyTime time.Duration
 	requireChecks := false
 	desiredChecks := 0
 	for _, s := range t.task.Services {
+		if !s.UsesConsul() {
+			continue
+		}
 		if nc := len(s.Checks); nc > 0 {
 			requireChecks = true
 			desiredChecks += nc
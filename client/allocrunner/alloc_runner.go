@@ -8,7 +8,9 @@ import (
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allochealth"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/state"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
@@ -16,7 +18,12 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
+	"github.com/hashicorp/nomad/client/diskquota"
+	"github.com/hashicorp/nomad/client/dynamicusers"
+	"github.com/hashicorp/nomad/client/externalservices"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/keyring"
+	"github.com/hashicorp/nomad/client/nomadservices"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	cstate "github.com/hashicorp/nomad/client/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -60,9 +67,20 @@ type allocRunner struct {
 	// registering services and checks
 	consulClient consul.ConsulServiceAPI
 
+	// nomadServices is the client used by the service hook for registering
+	// services with the Nomad service provider
+	nomadServices nomadservices.ServiceAPI
+
+	// externalServices is the client used by the service hook for
+	// registering services with external service providers
+	externalServices externalservices.ServiceAPI
+
 	// vaultClient is the used to manage Vault tokens
 	vaultClient vaultclient.VaultClient
 
+	// vaultClusters are the clients of the named Vault clusters
+	vaultClusters map[string]vaultclient.VaultClient
+
 	// waitCh is closed when the Run loop has exited
 	waitCh chan struct{}
 
@@ -135,6 +153,21 @@ type allocRunner struct {
 	// driverManager is responsible for dispensing driver plugins and registering
 	// event handlers
 	driverManager drivermanager.Manager
+
+	// rpcClient is used to make RPC calls to the servers
+	rpcClient cinterfaces.RPCer
+
+	// dynamicUsers is the pool the dynamic user of the allocation was
+	// acquired from, or nil if the allocation doesn't have one.
+	dynamicUsers *dynamicusers.Pool
+
+	// diskQuotas enforces the ephemeral disk size of the allocation, or is
+	// nil if the client doesn't support project quotas.
+	diskQuotas *diskquota.Quotas
+
+	// keyring encrypts the secrets directories of tasks while they aren't
+	// running, or is nil if data encryption is disabled.
+	keyring *keyring.Keyring
 }
 
 // NewAllocRunner returns a new allocation runner.
@@ -151,6 +184,7 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		clientConfig:             config.ClientConfig,
 		consulClient:             config.Consul,
 		vaultClient:              config.Vault,
+		vaultClusters:            config.VaultClusters,
 		tasks:                    make(map[string]*taskrunner.TaskRunner, len(tg.Tasks)),
 		waitCh:                   make(chan struct{}),
 		destroyCh:                make(chan struct{}),
@@ -166,6 +200,11 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 		prevAllocMigrator:        config.PrevAllocMigrator,
 		devicemanager:            config.DeviceManager,
 		driverManager:            config.DriverManager,
+		rpcClient:                config.RPCClient,
+		nomadServices:            config.NomadServices,
+		externalServices:         config.ExternalServices,
+		diskQuotas:               config.DiskQuotas,
+		keyring:                  config.Keyring,
 	}
 
 	// Create the logger based on the allocation ID
@@ -175,13 +214,27 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 	ar.allocBroadcaster = cstructs.NewAllocBroadcaster(ar.logger)
 
 	// Create alloc dir
-	ar.allocDir = allocdir.NewAllocDir(ar.logger, filepath.Join(config.ClientConfig.AllocDir, alloc.ID))
+	ar.allocDir = allocdir.NewAllocDir(ar.logger, filepath.Join(config.ClientConfig.AllocDirFor(alloc), alloc.ID))
+
+	// Assign the allocation a dynamic user before the task directories are
+	// created, so they belong to it
+	if config.DynamicUsers != nil && usesDynamicUser(tg) {
+		owner, err := acquireDynamicUser(config.DynamicUsers, ar.stateDB, alloc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to assign dynamic user: %v", err)
+		}
+		ar.allocDir.Owner = owner
+		ar.dynamicUsers = config.DynamicUsers
+	}
 
 	// Initialize the runners hooks.
 	ar.initRunnerHooks()
 
 	// Create the TaskRunners
 	if err := ar.initTaskRunners(tg.Tasks); err != nil {
+		if ar.dynamicUsers != nil {
+			ar.dynamicUsers.Release(alloc.ID)
+		}
 		return nil, err
 	}
 
@@ -190,20 +243,33 @@ func NewAllocRunner(config *Config) (*allocRunner, error) {
 
 // initTaskRunners creates task runners but does *not* run them.
 func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
+	tg := ar.alloc.Job.LookupTaskGroup(ar.alloc.TaskGroup)
 	for _, task := range tasks {
+		vaultClient := ar.vaultClient
+		if cluster := task.Vault.GetCluster(); cluster != structs.VaultDefaultCluster {
+			vaultClient = ar.vaultClusters[cluster]
+			if task.Vault != nil && vaultClient == nil {
+				return fmt.Errorf("failed creating runner for task %q: Vault cluster %q not configured", task.Name, cluster)
+			}
+		}
+
 		config := &taskrunner.Config{
 			Alloc:               ar.alloc,
-			ClientConfig:        ar.clientConfig,
+			ClientConfig:        taskClientConfig(ar.clientConfig, tg, task),
 			Task:                task,
 			TaskDir:             ar.allocDir.NewTaskDir(task.Name),
 			Logger:              ar.logger,
 			StateDB:             ar.stateDB,
 			StateUpdater:        ar,
 			Consul:              ar.consulClient,
-			Vault:               ar.vaultClient,
+			Vault:               vaultClient,
 			DeviceStatsReporter: ar.deviceStatsReporter,
 			DeviceManager:       ar.devicemanager,
 			DriverManager:       ar.driverManager,
+			RPCClient:           ar.rpcClient,
+			NomadServices:       ar.nomadServices,
+			ExternalServices:    ar.externalServices,
+			Keyring:             ar.keyring,
 		}
 
 		// Create, but do not Run, the task runner
@@ -217,6 +283,26 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 	return nil
 }
 
+// taskClientConfig returns the client config of the task, whose Vault and
+// Consul configs are the ones of the clusters used by the task when the
+// client is configured with them.
+func taskClientConfig(conf *config.Config, tg *structs.TaskGroup, task *structs.Task) *config.Config {
+	vaultConf := conf.VaultClusterConfig(task.Vault.GetCluster())
+	consulConf := conf.ConsulClusterConfig(tg.ConsulCluster())
+	if (vaultConf == nil || vaultConf == conf.VaultConfig) && (consulConf == nil || consulConf == conf.ConsulConfig) {
+		return conf
+	}
+
+	taskConf := conf.Copy()
+	if vaultConf != nil {
+		taskConf.VaultConfig = vaultConf.Copy()
+	}
+	if consulConf != nil {
+		taskConf.ConsulConfig = consulConf.Copy()
+	}
+	return taskConf
+}
+
 func (ar *allocRunner) WaitCh() <-chan struct{} {
 	return ar.waitCh
 }
@@ -923,6 +1009,20 @@ func (ar *allocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResou
 	return astat, nil
 }
 
+// AllocStatsHistory returns the resource usage of each task collected between
+// the start and end UnixNano timestamps, oldest first. If taskFilter is set,
+// only the usage of that task -- if it exists -- is returned.
+func (ar *allocRunner) AllocStatsHistory(taskFilter string, start, end int64) (map[string][]*cstructs.TaskResourceUsage, error) {
+	history := make(map[string][]*cstructs.TaskResourceUsage, len(ar.tasks))
+	for name, tr := range ar.tasks {
+		if taskFilter != "" && taskFilter != name {
+			continue
+		}
+		history[name] = tr.ResourceUsageHistory(start, end)
+	}
+	return history, nil
+}
+
 func (ar *allocRunner) GetTaskEventHandler(taskName string) drivermanager.EventHandler {
 	if tr, ok := ar.tasks[taskName]; ok {
 		return func(ev *drivers.TaskEvent) {
@@ -936,3 +1036,234 @@ func (ar *allocRunner) GetTaskEventHandler(taskName string) drivermanager.EventH
 	}
 	return nil
 }
+
+// ExecTask runs the command inside the allocation's running task and returns
+// its output and exit code.
+func (ar *allocRunner) ExecTask(taskName string, timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return nil, 0, fmt.Errorf("Unknown task %q", taskName)
+	}
+
+	return tr.Exec(timeout, cmd, args)
+}
+
+// RestartAll restarts all of the allocation's running tasks in place with the
+// given event. It blocks until the tasks have exited. The task runners then
+// start them again immediately; restarts do not count against the restart
+// policy.
+func (ar *allocRunner) RestartAll(event *structs.TaskEvent) error {
+	var mu sync.Mutex
+	var mErr multierror.Error
+
+	wg := sync.WaitGroup{}
+	for name, tr := range ar.tasks {
+		wg.Add(1)
+		go func(name string, tr *taskrunner.TaskRunner) {
+			defer wg.Done()
+			err := tr.Restart(context.TODO(), event.Copy(), false)
+			if err != nil && err != taskrunner.ErrTaskNotRunning {
+				mu.Lock()
+				multierror.Append(&mErr, fmt.Errorf("failed to restart task %q: %v", name, err))
+				mu.Unlock()
+			}
+		}(name, tr)
+	}
+	wg.Wait()
+
+	return mErr.ErrorOrNil()
+}
+
+// RestartTask restarts the allocation's task in place with the given event.
+// It blocks until the task has exited.
+func (ar *allocRunner) RestartTask(taskName string, event *structs.TaskEvent) error {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return fmt.Errorf("task %q not found in allocation", taskName)
+	}
+
+	if err := tr.Restart(context.TODO(), event, false); err != nil {
+		return fmt.Errorf("failed to restart task %q: %v", taskName, err)
+	}
+	return nil
+}
+
+// RestartOrdered restarts the allocation's running tasks in place one at a
+// time with the given event: the tasks of the group that aren't the leader in
+// the order they are defined, followed by the leader task. Each task must be
+// running again before the next task is restarted, within the healthy
+// deadline of the group.
+func (ar *allocRunner) RestartOrdered(event *structs.TaskEvent) error {
+	alloc := ar.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return fmt.Errorf("unknown task group %q", alloc.TaskGroup)
+	}
+
+	for _, task := range restartOrder(tg.Tasks) {
+		tr, ok := ar.tasks[task.Name]
+		if !ok {
+			continue
+		}
+
+		deadline, _, _ := getHealthParams(time.Now(), tg, tg.Update != nil)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		err := ar.restartAndWaitRunning(ctx, task.Name, tr, event.Copy())
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restartOrder returns the tasks in the order they are restarted by
+// RestartOrdered.
+func restartOrder(tasks []*structs.Task) []*structs.Task {
+	ordered := make([]*structs.Task, 0, len(tasks))
+	var leaders []*structs.Task
+	for _, task := range tasks {
+		if task.Leader {
+			leaders = append(leaders, task)
+			continue
+		}
+		ordered = append(ordered, task)
+	}
+	return append(ordered, leaders...)
+}
+
+// restartAndWaitRunning restarts the task and blocks until it is running
+// again. Tasks that aren't running are skipped.
+func (ar *allocRunner) restartAndWaitRunning(ctx context.Context, name string, tr *taskrunner.TaskRunner, event *structs.TaskEvent) error {
+	listener := ar.Listener()
+	defer listener.Close()
+
+	restartedAt := time.Now()
+	err := tr.Restart(ctx, event, false)
+	if err == taskrunner.ErrTaskNotRunning {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to restart task %q: %v", name, err)
+	}
+
+	state := tr.TaskState()
+	for {
+		if state != nil {
+			switch state.State {
+			case structs.TaskStateRunning:
+				if state.StartedAt.After(restartedAt) {
+					return nil
+				}
+			case structs.TaskStateDead:
+				return fmt.Errorf("task %q is dead after being restarted", name)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for task %q to be running after being restarted", name)
+		case update, ok := <-listener.Ch():
+			if !ok {
+				return fmt.Errorf("allocation stopped while restarting task %q", name)
+			}
+			state = update.TaskStates[name]
+		}
+	}
+}
+
+// Signal sends the signal to the allocation's running tasks with the given
+// event. If taskName is set, only that task is signaled. Tasks that are not
+// running are skipped.
+func (ar *allocRunner) Signal(taskName string, event *structs.TaskEvent, signal string) error {
+	if taskName != "" {
+		tr, ok := ar.tasks[taskName]
+		if !ok {
+			return fmt.Errorf("task %q not found in allocation", taskName)
+		}
+		if err := tr.Signal(event, signal); err != nil && err != taskrunner.ErrTaskNotRunning {
+			return fmt.Errorf("failed to signal task %q: %v", taskName, err)
+		}
+		return nil
+	}
+
+	var mErr multierror.Error
+	for name, tr := range ar.tasks {
+		err := tr.Signal(event.Copy(), signal)
+		if err != nil && err != taskrunner.ErrTaskNotRunning {
+			multierror.Append(&mErr, fmt.Errorf("failed to signal task %q: %v", name, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// WaitHealthy blocks until every task of the allocation has been started
+// after since and the allocation is healthy. Health is determined the same
+// way as for deployments, using the update stanza of the allocation's task
+// group or its migrate stanza if there is none. False is returned if the
+// allocation becomes unhealthy, the healthy deadline is reached or the context
+// is canceled.
+func (ar *allocRunner) WaitHealthy(ctx context.Context, since time.Time) bool {
+	alloc := ar.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return false
+	}
+
+	deadline, useChecks, minHealthyTime := getHealthParams(time.Now(), tg, tg.Update != nil)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	listener := ar.Listener()
+	defer listener.Close()
+
+	// Wait for the tasks to be started again so the tracker doesn't watch the
+	// task states from before the restart.
+	taskStates := make(map[string]*structs.TaskState, len(ar.tasks))
+	for name, tr := range ar.tasks {
+		taskStates[name] = tr.TaskState()
+	}
+	for !tasksStartedSince(taskStates, since) {
+		select {
+		case <-ctx.Done():
+			return false
+		case update, ok := <-listener.Ch():
+			if !ok {
+				return false
+			}
+			taskStates = update.TaskStates
+		}
+	}
+
+	tracked := alloc.Copy()
+	tracked.TaskStates = taskStates
+	tracker := allochealth.NewTracker(ctx, ar.logger, tracked, listener,
+		ar.consulClient, minHealthyTime, useChecks)
+	tracker.Start()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-tracker.AllocStoppedCh():
+		return false
+	case healthy := <-tracker.HealthyCh():
+		return healthy
+	}
+}
+
+// tasksStartedSince returns whether every task has either been started after
+// since or is dead.
+func tasksStartedSince(taskStates map[string]*structs.TaskState, since time.Time) bool {
+	for _, state := range taskStates {
+		switch state.State {
+		case structs.TaskStateDead:
+		case structs.TaskStateRunning:
+			if !state.StartedAt.After(since) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -75,6 +75,19 @@ func (a *allocHealthSetter) SetHealth(healthy, isDeploy bool, trackerTaskEvents
 	a.ar.allocBroadcaster.Send(calloc)
 }
 
+// allocTaskEventEmitter is a shim to allow hooks to emit events to all the
+// tasks of the alloc without full access to the alloc runner state
+type allocTaskEventEmitter struct {
+	ar *allocRunner
+}
+
+// EmitEvent emits a copy of the event to each task.
+func (a *allocTaskEventEmitter) EmitEvent(event *structs.TaskEvent) {
+	for _, tr := range a.ar.tasks {
+		tr.EmitEvent(event.Copy())
+	}
+}
+
 // initRunnerHooks intializes the runners hooks.
 func (ar *allocRunner) initRunnerHooks() {
 	hookLogger := ar.logger.Named("runner_hook")
@@ -90,6 +103,19 @@ func (ar *allocRunner) initRunnerHooks() {
 		newDiskMigrationHook(hookLogger, ar.prevAllocMigrator, ar.allocDir),
 		newAllocHealthWatcherHook(hookLogger, ar.Alloc(), hs, ar.Listener(), ar.consulClient),
 	}
+
+	if ar.dynamicUsers != nil {
+		ar.runnerHooks = append(ar.runnerHooks, newDynamicUserHook(hookLogger, ar.dynamicUsers, ar.id))
+	}
+
+	// Enforce the ephemeral disk size once the previous alloc dir was
+	// migrated, as files can't be moved across projects
+	tg := ar.alloc.Job.LookupTaskGroup(ar.alloc.TaskGroup)
+	if ar.diskQuotas != nil && ar.diskQuotas.Supported(ar.allocDir.AllocDir) && tg.EphemeralDisk != nil {
+		emitter := &allocTaskEventEmitter{ar}
+		ar.runnerHooks = append(ar.runnerHooks,
+			newDiskQuotaHook(hookLogger, ar.diskQuotas, ar.allocDir, tg.EphemeralDisk.SizeMB, emitter))
+	}
 }
 
 // prerun is used to run the runners prerun hooks.
@@ -942,3 +942,142 @@ func TestAllocRunner_TerminalUpdate_Destroy(t *testing.T) {
 		require.Fail(t, "err: %v", err)
 	})
 }
+
+// TestAllocRunner_RestartOrdered asserts that ordered restarts restart the
+// tasks one at a time with the leader task last.
+func TestAllocRunner_RestartOrdered(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.Alloc()
+	tr := alloc.AllocatedResources.Tasks[alloc.Job.TaskGroups[0].Tasks[0].Name]
+
+	// Create a leader defined between two sidecars
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Name = "sidecar1"
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	task2 := task.Copy()
+	task2.Name = "main"
+	task2.Leader = true
+
+	task3 := task.Copy()
+	task3.Name = "sidecar2"
+	alloc.Job.TaskGroups[0].Tasks = append(alloc.Job.TaskGroups[0].Tasks, task2, task3)
+	alloc.AllocatedResources.Tasks[task.Name] = tr
+	alloc.AllocatedResources.Tasks[task2.Name] = tr
+	alloc.AllocatedResources.Tasks[task3.Name] = tr
+
+	conf, cleanup := testAllocRunnerConfig(t, alloc)
+	defer cleanup()
+	ar, err := NewAllocRunner(conf)
+	require.NoError(t, err)
+	defer destroy(ar)
+	go ar.Run()
+
+	// Wait for tasks to start
+	testutil.WaitForResult(func() (bool, error) {
+		states := ar.AllocState().TaskStates
+		if n := len(states); n != 3 {
+			return false, fmt.Errorf("Not enough task states (want: 3; found %d)", n)
+		}
+		for name, state := range states {
+			if state.State != structs.TaskStateRunning {
+				return false, fmt.Errorf("Task %q is not running yet (it's %q)", name, state.State)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	restartedAt := time.Now()
+	event := structs.NewTaskEvent(structs.TaskRestartSignal)
+	require.NoError(t, ar.RestartOrdered(event))
+
+	// Every task is running again, each one started after the previous one
+	states := ar.AllocState().TaskStates
+	for _, name := range []string{"sidecar1", "sidecar2", "main"} {
+		state := states[name]
+		require.Equal(t, structs.TaskStateRunning, state.State, "task %q", name)
+		require.True(t, state.StartedAt.After(restartedAt), "task %q wasn't restarted", name)
+	}
+	require.True(t, states["sidecar1"].StartedAt.Before(states["sidecar2"].StartedAt))
+	require.True(t, states["sidecar2"].StartedAt.Before(states["main"].StartedAt))
+}
+
+// TestAllocRunner_RestartTask asserts that only the given task is restarted.
+func TestAllocRunner_RestartTask(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.Alloc()
+	tr := alloc.AllocatedResources.Tasks[alloc.Job.TaskGroups[0].Tasks[0].Name]
+
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Name = "main"
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	task2 := task.Copy()
+	task2.Name = "sidecar"
+	alloc.Job.TaskGroups[0].Tasks = append(alloc.Job.TaskGroups[0].Tasks, task2)
+	alloc.AllocatedResources.Tasks[task.Name] = tr
+	alloc.AllocatedResources.Tasks[task2.Name] = tr
+
+	conf, cleanup := testAllocRunnerConfig(t, alloc)
+	defer cleanup()
+	ar, err := NewAllocRunner(conf)
+	require.NoError(t, err)
+	defer destroy(ar)
+	go ar.Run()
+
+	// Wait for tasks to start
+	testutil.WaitForResult(func() (bool, error) {
+		for name, state := range ar.AllocState().TaskStates {
+			if state.State != structs.TaskStateRunning {
+				return false, fmt.Errorf("Task %q is not running yet (it's %q)", name, state.State)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Unknown tasks can't be restarted
+	event := structs.NewTaskEvent(structs.TaskRestartSignal)
+	require.Error(t, ar.RestartTask("unknown", event))
+
+	require.NoError(t, ar.RestartTask("sidecar", event))
+
+	hasRestartEvent := func(state *structs.TaskState) bool {
+		for _, e := range state.Events {
+			if e.Type == structs.TaskRestartSignal {
+				return true
+			}
+		}
+		return false
+	}
+	states := ar.AllocState().TaskStates
+	require.True(t, hasRestartEvent(states["sidecar"]))
+	require.False(t, hasRestartEvent(states["main"]))
+}
+
+func TestRestartOrder(t *testing.T) {
+	t.Parallel()
+
+	tasks := []*structs.Task{
+		{Name: "sidecar1"},
+		{Name: "main", Leader: true},
+		{Name: "sidecar2"},
+	}
+
+	var names []string
+	for _, task := range restartOrder(tasks) {
+		names = append(names, task.Name)
+	}
+	require.Equal(t, []string{"sidecar1", "sidecar2", "main"}, names)
+}
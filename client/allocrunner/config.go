@@ -6,7 +6,12 @@ import (
 	clientconfig "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
+	"github.com/hashicorp/nomad/client/diskquota"
+	"github.com/hashicorp/nomad/client/dynamicusers"
+	"github.com/hashicorp/nomad/client/externalservices"
 	"github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/keyring"
+	"github.com/hashicorp/nomad/client/nomadservices"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	cstate "github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/vaultclient"
@@ -33,6 +38,10 @@ type Config struct {
 	// Vault is the Vault client to use to retrieve Vault tokens
 	Vault vaultclient.VaultClient
 
+	// VaultClusters are the clients of the named Vault clusters, keyed by
+	// name, used by the tasks selecting another cluster than the default.
+	VaultClusters map[string]vaultclient.VaultClient
+
 	// StateUpdater is used to emit updated task state
 	StateUpdater interfaces.AllocStateHandler
 
@@ -51,4 +60,27 @@ type Config struct {
 
 	// DriverManager handles dispensing of driver plugins
 	DriverManager drivermanager.Manager
+
+	// RPCClient is used to make RPC calls to the servers
+	RPCClient interfaces.RPCer
+
+	// NomadServices is used to register task services with the Nomad
+	// service provider
+	NomadServices nomadservices.ServiceAPI
+
+	// ExternalServices is used to register task services with external
+	// service providers
+	ExternalServices externalservices.ServiceAPI
+
+	// DynamicUsers assigns dynamic users to allocations. It is nil if the
+	// client doesn't use dynamic users.
+	DynamicUsers *dynamicusers.Pool
+
+	// DiskQuotas enforces the ephemeral disk sizes of allocations. It is nil
+	// if the client doesn't support project quotas.
+	DiskQuotas *diskquota.Quotas
+
+	// Keyring encrypts the secrets directories of tasks while they aren't
+	// running. It is nil if data encryption is disabled.
+	Keyring *keyring.Keyring
 }
@@ -0,0 +1,135 @@
+package allocrunner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// diskQuotaCheckInterval is the interval at which the disk usage of the
+// allocation is compared to its ephemeral disk size.
+var diskQuotaCheckInterval = 10 * time.Second
+
+// diskQuotaSlack is how close to the ephemeral disk size the disk usage is
+// considered to reach it, since writes fail before filling the last blocks.
+const diskQuotaSlack = 1024 * 1024
+
+// diskQuotas limits the disk usage of alloc dirs. It is implemented by
+// diskquota.Quotas.
+type diskQuotas interface {
+	Set(dir string, bytes uint64) error
+	Usage(dir string) (uint64, error)
+	Release(dir string) error
+}
+
+// taskEventEmitter emits an event to all the tasks of the allocation.
+type taskEventEmitter interface {
+	EmitEvent(event *structs.TaskEvent)
+}
+
+// diskQuotaHook limits the disk usage of the allocation to its ephemeral disk
+// size with a project quota, and emits a task event when the usage reaches it.
+// Depends on the alloc dir being built and migrated.
+type diskQuotaHook struct {
+	quotas   diskQuotas
+	allocDir *allocdir.AllocDir
+	sizeMB   int
+	emitter  taskEventEmitter
+	logger   log.Logger
+
+	// hookLock is held by hook methods to prevent concurrent access by
+	// Destroy
+	hookLock sync.Mutex
+
+	// cancelFn stops watching the disk usage. Must hold hookLock to access.
+	cancelFn context.CancelFunc
+}
+
+func newDiskQuotaHook(logger log.Logger, quotas diskQuotas, allocDir *allocdir.AllocDir,
+	sizeMB int, emitter taskEventEmitter) *diskQuotaHook {
+	h := &diskQuotaHook{
+		quotas:   quotas,
+		allocDir: allocDir,
+		sizeMB:   sizeMB,
+		emitter:  emitter,
+		cancelFn: func() {},
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (h *diskQuotaHook) Name() string {
+	return "disk_quota"
+}
+
+func (h *diskQuotaHook) Prerun() error {
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	limit := uint64(h.sizeMB) * 1024 * 1024
+	if err := h.quotas.Set(h.allocDir.AllocDir, limit); err != nil {
+		// Soft-fail as the ephemeral disk size was only advisory before
+		h.logger.Warn("failed to enforce ephemeral disk size", "error", err)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancelFn = cancel
+	go h.watch(ctx, limit)
+	return nil
+}
+
+// watch emits a task event whenever the disk usage reaches the limit.
+func (h *diskQuotaHook) watch(ctx context.Context, limit uint64) {
+	ticker := time.NewTicker(diskQuotaCheckInterval)
+	defer ticker.Stop()
+
+	exceeded := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		used, err := h.quotas.Usage(h.allocDir.AllocDir)
+		if err != nil {
+			h.logger.Debug("failed to read disk usage", "error", err)
+			continue
+		}
+
+		reached := used+diskQuotaSlack >= limit
+		if reached && !exceeded {
+			h.logger.Warn("disk usage reached the ephemeral disk size", "used", used, "limit", limit)
+			h.emitter.EmitEvent(structs.NewTaskEvent(structs.TaskDiskExceeded).SetDiskLimit(int64(h.sizeMB)))
+		}
+		exceeded = reached
+	}
+}
+
+func (h *diskQuotaHook) Postrun() error {
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	h.cancelFn()
+	return nil
+}
+
+func (h *diskQuotaHook) Shutdown() {
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	h.cancelFn()
+}
+
+func (h *diskQuotaHook) Destroy() error {
+	h.hookLock.Lock()
+	defer h.hookLock.Unlock()
+
+	h.cancelFn()
+	return h.quotas.Release(h.allocDir.AllocDir)
+}
@@ -0,0 +1,110 @@
+package allocrunner
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDiskQuotas records the limits set and reports the usage given.
+type fakeDiskQuotas struct {
+	limits map[string]uint64
+	used   uint64
+	l      sync.Mutex
+}
+
+func (q *fakeDiskQuotas) Set(dir string, bytes uint64) error {
+	q.l.Lock()
+	defer q.l.Unlock()
+	q.limits[dir] = bytes
+	return nil
+}
+
+func (q *fakeDiskQuotas) Usage(dir string) (uint64, error) {
+	q.l.Lock()
+	defer q.l.Unlock()
+	return q.used, nil
+}
+
+func (q *fakeDiskQuotas) Release(dir string) error {
+	q.l.Lock()
+	defer q.l.Unlock()
+	delete(q.limits, dir)
+	return nil
+}
+
+func (q *fakeDiskQuotas) setUsed(used uint64) {
+	q.l.Lock()
+	defer q.l.Unlock()
+	q.used = used
+}
+
+// fakeEventEmitter records the events emitted.
+type fakeEventEmitter struct {
+	events []*structs.TaskEvent
+	l      sync.Mutex
+}
+
+func (e *fakeEventEmitter) EmitEvent(event *structs.TaskEvent) {
+	e.l.Lock()
+	defer e.l.Unlock()
+	e.events = append(e.events, event)
+}
+
+func (e *fakeEventEmitter) numEvents() int {
+	e.l.Lock()
+	defer e.l.Unlock()
+	return len(e.events)
+}
+
+func TestDiskQuotaHook(t *testing.T) {
+	require := require.New(t)
+
+	oldInterval := diskQuotaCheckInterval
+	diskQuotaCheckInterval = 10 * time.Millisecond
+	defer func() {
+		diskQuotaCheckInterval = oldInterval
+	}()
+
+	quotas := &fakeDiskQuotas{limits: make(map[string]uint64)}
+	emitter := &fakeEventEmitter{}
+	allocDir := allocdir.NewAllocDir(testlog.HCLogger(t), "/alloc/123")
+	h := newDiskQuotaHook(testlog.HCLogger(t), quotas, allocDir, 100, emitter)
+
+	require.NoError(h.Prerun())
+	require.EqualValues(100*1024*1024, quotas.limits["/alloc/123"])
+
+	// An event is emitted once when the usage reaches the size
+	quotas.setUsed(100 * 1024 * 1024)
+	testutil.WaitForResult(func() (bool, error) {
+		return emitter.numEvents() == 1, nil
+	}, func(err error) {
+		t.Fatalf("expected 1 event, got %d", emitter.numEvents())
+	})
+	time.Sleep(5 * diskQuotaCheckInterval)
+	require.Equal(1, emitter.numEvents())
+
+	event := emitter.events[0]
+	require.Equal(structs.TaskDiskExceeded, event.Type)
+	require.EqualValues(100, event.DiskLimit)
+
+	// It is emitted again when the usage reaches the size after dropping
+	quotas.setUsed(0)
+	time.Sleep(5 * diskQuotaCheckInterval)
+	quotas.setUsed(100 * 1024 * 1024)
+	testutil.WaitForResult(func() (bool, error) {
+		return emitter.numEvents() == 2, nil
+	}, func(err error) {
+		t.Fatalf("expected 2 events, got %d", emitter.numEvents())
+	})
+
+	require.NoError(h.Postrun())
+	require.NoError(h.Destroy())
+	require.Empty(quotas.limits)
+}
@@ -0,0 +1,83 @@
+package allocrunner
+
+import (
+	"fmt"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/dynamicusers"
+	cstate "github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// dynamicUserDrivers are the drivers whose tasks run as the dynamic user of
+// their allocation.
+var dynamicUserDrivers = map[string]struct{}{
+	"exec":     {},
+	"raw_exec": {},
+}
+
+// usesDynamicUser returns whether the allocations of the task group are
+// assigned a dynamic user. Only task groups whose tasks all run with a
+// dynamic user driver and without an explicit user are, so that every task
+// of the allocation may access its shared directory.
+func usesDynamicUser(tg *structs.TaskGroup) bool {
+	for _, task := range tg.Tasks {
+		if _, ok := dynamicUserDrivers[task.Driver]; !ok || task.User != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// acquireDynamicUser returns the owner of the directories of the allocation,
+// restoring the dynamic user it was assigned before the client restarted or
+// assigning it a new one.
+func acquireDynamicUser(pool *dynamicusers.Pool, db cstate.StateDB, allocID string) (*allocdir.DirOwner, error) {
+	id, err := db.GetDynamicUser(allocID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic user: %v", err)
+	}
+
+	if id != 0 {
+		if err := pool.Restore(allocID, id); err != nil {
+			return nil, err
+		}
+	} else {
+		if id, err = pool.Acquire(allocID); err != nil {
+			return nil, err
+		}
+		if err := db.PutDynamicUser(allocID, id); err != nil {
+			pool.Release(allocID)
+			return nil, fmt.Errorf("failed to store dynamic user: %v", err)
+		}
+	}
+
+	return &allocdir.DirOwner{UID: id, GID: id}, nil
+}
+
+// dynamicUserHook releases the dynamic user of an allocation when it is
+// destroyed.
+type dynamicUserHook struct {
+	pool    *dynamicusers.Pool
+	allocID string
+	logger  log.Logger
+}
+
+func newDynamicUserHook(logger log.Logger, pool *dynamicusers.Pool, allocID string) *dynamicUserHook {
+	h := &dynamicUserHook{
+		pool:    pool,
+		allocID: allocID,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (h *dynamicUserHook) Name() string {
+	return "dynamic_user"
+}
+
+func (h *dynamicUserHook) Destroy() error {
+	h.pool.Release(h.allocID)
+	return nil
+}
@@ -0,0 +1,71 @@
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/dynamicusers"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicUserHook_UsesDynamicUser(t *testing.T) {
+	t.Parallel()
+
+	tg := mock.Job().TaskGroups[0]
+	tg.Tasks[0].Driver = "exec"
+	require.True(t, usesDynamicUser(tg))
+
+	// Tasks with an explicit user don't use dynamic users
+	tg.Tasks[0].User = "nobody"
+	require.False(t, usesDynamicUser(tg))
+
+	// Neither do task groups with tasks of other drivers, which couldn't
+	// access the shared alloc dir
+	tg.Tasks[0].User = ""
+	raw := tg.Tasks[0].Copy()
+	raw.Name = "raw"
+	raw.Driver = "raw_exec"
+	tg.Tasks = append(tg.Tasks, raw)
+	require.True(t, usesDynamicUser(tg))
+
+	tg.Tasks[1].Driver = "docker"
+	require.False(t, usesDynamicUser(tg))
+}
+
+func TestDynamicUserHook_Acquire(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db := state.NewMemDB(testlog.HCLogger(t))
+	pool, err := dynamicusers.NewPool(80000, 80009)
+	require.NoError(err)
+
+	owner, err := acquireDynamicUser(pool, db, "alloc1")
+	require.NoError(err)
+	require.Equal(80000, owner.UID)
+	require.Equal(80000, owner.GID)
+
+	id, err := db.GetDynamicUser("alloc1")
+	require.NoError(err)
+	require.Equal(80000, id)
+
+	// After a restart the allocation gets its dynamic user back, and other
+	// allocations don't get it
+	pool, err = dynamicusers.NewPool(80000, 80009)
+	require.NoError(err)
+
+	owner, err = acquireDynamicUser(pool, db, "alloc1")
+	require.NoError(err)
+	require.Equal(80000, owner.UID)
+
+	other, err := acquireDynamicUser(pool, db, "alloc2")
+	require.NoError(err)
+	require.Equal(80001, other.UID)
+
+	// Destroying the allocation releases its dynamic user
+	hook := newDynamicUserHook(testlog.HCLogger(t), pool, "alloc1")
+	require.NoError(hook.Destroy())
+	require.NoError(pool.Restore("alloc3", 80000))
+}
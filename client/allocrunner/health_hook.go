@@ -70,9 +70,17 @@ type allocHealthWatcherHook struct {
 func newAllocHealthWatcherHook(logger log.Logger, alloc *structs.Allocation, hs healthSetter,
 	listener *cstructs.AllocListener, consul consul.ConsulServiceAPI) interfaces.RunnerHook {
 
-	// Neither deployments nor migrations care about the health of
-	// non-service jobs so never watch their health
-	if alloc.Job.Type != structs.JobTypeService {
+	// Neither deployments nor migrations care about the health of batch
+	// jobs so never watch their health. System jobs are only watched when
+	// their task group is rolled out by an update strategy.
+	switch alloc.Job.Type {
+	case structs.JobTypeService:
+	case structs.JobTypeSystem:
+		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil || tg.Update == nil || tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual {
+			return noopAllocHealthWatcherHook{}
+		}
+	default:
 		return noopAllocHealthWatcherHook{}
 	}
 
@@ -115,16 +123,20 @@ func (h *allocHealthWatcherHook) init() error {
 
 	h.isDeploy = h.alloc.DeploymentID != ""
 
+	// System jobs are rolled out by the update strategy of their task group
+	// without a deployment
+	useUpdate := h.isDeploy || h.alloc.Job.Type == structs.JobTypeSystem
+
 	// No need to watch allocs for deployments that rely on operators
 	// manually setting health
-	if h.isDeploy && (tg.Update == nil || tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual) {
+	if useUpdate && (tg.Update == nil || tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual) {
 		return nil
 	}
 
-	// Define the deadline, health method, min healthy time from the
-	// deployment if this is a deployment; otherwise from the migration
-	// strategy.
-	deadline, useChecks, minHealthyTime := getHealthParams(time.Now(), tg, h.isDeploy)
+	// Define the deadline, health method, min healthy time from the update
+	// strategy if this is a deployment or system job; otherwise from the
+	// migration strategy.
+	deadline, useChecks, minHealthyTime := getHealthParams(time.Now(), tg, useUpdate)
 
 	// Create a context that is canceled when the tracker should shutdown.
 	ctx := context.Background()
@@ -235,9 +247,9 @@ func (h *allocHealthWatcherHook) watchHealth(ctx context.Context, deadline time.
 }
 
 // getHealthParams returns the health watcher parameters which vary based on
-// whether this allocation is in a deployment or migration.
-func getHealthParams(now time.Time, tg *structs.TaskGroup, isDeploy bool) (deadline time.Time, useChecks bool, minHealthyTime time.Duration) {
-	if isDeploy {
+// whether this allocation is rolled out by its update strategy or migrated.
+func getHealthParams(now time.Time, tg *structs.TaskGroup, useUpdate bool) (deadline time.Time, useChecks bool, minHealthyTime time.Duration) {
+	if useUpdate {
 		deadline = now.Add(tg.Update.HealthyDeadline)
 		minHealthyTime = tg.Update.MinHealthyTime
 		useChecks = tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Checks
@@ -321,6 +321,26 @@ func TestHealthHook_SystemNoop(t *testing.T) {
 	require.False(t, ok)
 }
 
+// TestHealthHook_SystemUpdate asserts that system jobs with an update strategy
+// have their health watched using it.
+func TestHealthHook_SystemUpdate(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.SystemAlloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	tg.Update = structs.DefaultUpdateStrategy.Copy()
+
+	h := newAllocHealthWatcherHook(testlog.HCLogger(t), alloc, nil, nil, nil)
+	_, ok := h.(noopAllocHealthWatcherHook)
+	require.False(t, ok)
+
+	// Manually set health isn't watched
+	tg.Update.HealthCheck = structs.UpdateStrategyHealthCheck_Manual
+	h = newAllocHealthWatcherHook(testlog.HCLogger(t), alloc, nil, nil, nil)
+	_, ok = h.(noopAllocHealthWatcherHook)
+	require.True(t, ok)
+}
+
 // TestHealthHook_BatchNoop asserts that batch jobs return the noop tracker.
 func TestHealthHook_BatchNoop(t *testing.T) {
 	t.Parallel()
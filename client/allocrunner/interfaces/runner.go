@@ -31,4 +31,5 @@ type TaskStateHandler interface {
 // allocation
 type AllocStatsReporter interface {
 	LatestAllocStats(taskFilter string) (*cstructs.AllocResourceUsage, error)
+	AllocStatsHistory(taskFilter string, start, end int64) (map[string][]*cstructs.TaskResourceUsage, error)
 }
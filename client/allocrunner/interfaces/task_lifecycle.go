@@ -123,7 +123,12 @@ type TaskPoststartHook interface {
 	Poststart(context.Context, *TaskPoststartRequest, *TaskPoststartResponse) error
 }
 
-type TaskPreKillRequest struct{}
+type TaskPreKillRequest struct {
+	// ShutdownDelay is the minimum delay between deregistering the services
+	// of the task and killing it, set when the job of the allocation is
+	// stopped with a shutdown delay.
+	ShutdownDelay time.Duration
+}
 type TaskPreKillResponse struct{}
 
 type TaskPreKillHook interface {
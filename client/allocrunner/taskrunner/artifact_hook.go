@@ -14,12 +14,17 @@ import (
 // artifactHook downloads artifacts for a task.
 type artifactHook struct {
 	eventEmitter ti.EventEmitter
-	logger       log.Logger
+
+	// cache holds the artifacts prefetched by the client. It may be nil.
+	cache *getter.Cache
+
+	logger log.Logger
 }
 
-func newArtifactHook(e ti.EventEmitter, logger log.Logger) *artifactHook {
+func newArtifactHook(e ti.EventEmitter, cache *getter.Cache, logger log.Logger) *artifactHook {
 	h := &artifactHook{
 		eventEmitter: e,
+		cache:        cache,
 	}
 	h.logger = logger.Named(h.Name())
 	return h
@@ -50,6 +55,17 @@ func (h *artifactHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 			continue
 		}
 
+		if h.cache != nil {
+			ok, err := h.cache.Get(artifact, req.TaskDir.Dir)
+			if err != nil {
+				h.logger.Warn("failed to copy prefetched artifact", "artifact", artifact.GetterSource, "error", err)
+			} else if ok {
+				h.logger.Debug("using prefetched artifact", "artifact", artifact.GetterSource)
+				resp.State[aid] = "1"
+				continue
+			}
+		}
+
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource)
 		//XXX add ctx to GetArtifact to allow cancelling long downloads
 		if err := getter.GetArtifact(req.TaskEnv, artifact, req.TaskDir.Dir); err != nil {
@@ -0,0 +1,153 @@
+package taskrunner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// HookNameDNS is the name of the DNS hook
+	HookNameDNS = "dns"
+
+	// resolvConfName is the name of the resolver configuration written to
+	// the task dir
+	resolvConfName = "resolv.conf"
+
+	// resolvConfTaskPath is where the resolver configuration is mounted in
+	// the task
+	resolvConfTaskPath = "/etc/resolv.conf"
+)
+
+// hostResolvConf is the resolver configuration of the host, whose values are
+// used for the fields the task doesn't set.
+var hostResolvConf = "/etc/resolv.conf"
+
+// dnsHook writes the resolver configuration of the task's network to the task
+// dir and mounts it at /etc/resolv.conf. It's run on every start so that the
+// mount is provided again after restarts.
+type dnsHook struct {
+	logger log.Logger
+}
+
+func newDNSHook(logger log.Logger) *dnsHook {
+	h := &dnsHook{}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*dnsHook) Name() string {
+	return HookNameDNS
+}
+
+func (h *dnsHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	dns := taskDNSConfig(req.Task)
+	if dns == nil {
+		return nil
+	}
+
+	// Use the host's values for the fields the task doesn't set, so that
+	// setting only the search domains keeps the host's nameservers
+	host, err := readResolvConf(hostResolvConf)
+	if err != nil {
+		h.logger.Debug("failed to read host resolver configuration", "error", err)
+		host = &structs.DNSConfig{}
+	}
+	conf := &structs.DNSConfig{
+		Servers:  dns.Servers,
+		Searches: dns.Searches,
+		Options:  dns.Options,
+	}
+	if len(conf.Servers) == 0 {
+		conf.Servers = host.Servers
+	}
+	if len(conf.Searches) == 0 {
+		conf.Searches = host.Searches
+	}
+	if len(conf.Options) == 0 {
+		conf.Options = host.Options
+	}
+
+	path := filepath.Join(req.TaskDir.Dir, resolvConfName)
+	if err := ioutil.WriteFile(path, formatResolvConf(conf), 0644); err != nil {
+		return fmt.Errorf("failed to write resolver configuration: %v", err)
+	}
+
+	resp.Mounts = []*drivers.MountConfig{
+		{
+			TaskPath: resolvConfTaskPath,
+			HostPath: path,
+			Readonly: true,
+		},
+	}
+	return nil
+}
+
+// taskDNSConfig returns the resolver configuration of the task's network, or
+// nil if it doesn't set one.
+func taskDNSConfig(task *structs.Task) *structs.DNSConfig {
+	if task.Resources == nil {
+		return nil
+	}
+	for _, n := range task.Resources.Networks {
+		if n.DNS != nil {
+			return n.DNS
+		}
+	}
+	return nil
+}
+
+// readResolvConf reads the nameservers, search domains and options of a
+// resolv.conf file.
+func readResolvConf(path string) (*structs.DNSConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var conf structs.DNSConfig
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			conf.Servers = append(conf.Servers, fields[1])
+		case "search", "domain":
+			// The last search or domain line wins
+			conf.Searches = fields[1:]
+		case "options":
+			conf.Options = append(conf.Options, fields[1:]...)
+		}
+	}
+	return &conf, scanner.Err()
+}
+
+// formatResolvConf returns the contents of a resolv.conf file.
+func formatResolvConf(conf *structs.DNSConfig) []byte {
+	var buf bytes.Buffer
+	for _, s := range conf.Servers {
+		fmt.Fprintf(&buf, "nameserver %s\n", s)
+	}
+	if len(conf.Searches) != 0 {
+		fmt.Fprintf(&buf, "search %s\n", strings.Join(conf.Searches, " "))
+	}
+	if len(conf.Options) != 0 {
+		fmt.Fprintf(&buf, "options %s\n", strings.Join(conf.Options, " "))
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,72 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDNSHook_NoDNS asserts tasks without a resolver configuration keep the
+// one provided by their driver.
+func TestDNSHook_NoDNS(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	h := newDNSHook(testlog.HCLogger(t))
+	req := &interfaces.TaskPrestartRequest{
+		Task: mock.Job().TaskGroups[0].Tasks[0],
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(h.Prestart(context.Background(), req, &resp))
+	require.Empty(resp.Mounts)
+}
+
+// TestDNSHook_Prestart asserts the resolver configuration is written to the
+// task dir, with the host's values for the fields the task doesn't set.
+func TestDNSHook_Prestart(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "dns_hook")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// Fake the host's configuration
+	hostPath := filepath.Join(dir, "host-resolv.conf")
+	host := "# comment\nnameserver 10.0.0.1\nsearch example.com\noptions ndots:1 rotate\n"
+	require.NoError(ioutil.WriteFile(hostPath, []byte(host), 0644))
+	orig := hostResolvConf
+	hostResolvConf = hostPath
+	defer func() { hostResolvConf = orig }()
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+	task.Resources.Networks[0].DNS = &structs.DNSConfig{
+		Searches: []string{"service.consul", "example.com"},
+	}
+
+	h := newDNSHook(testlog.HCLogger(t))
+	req := &interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{Dir: dir},
+	}
+	var resp interfaces.TaskPrestartResponse
+	require.NoError(h.Prestart(context.Background(), req, &resp))
+
+	// The hook must run again after restarts to provide the mount
+	require.False(resp.Done)
+	require.Len(resp.Mounts, 1)
+	require.Equal("/etc/resolv.conf", resp.Mounts[0].TaskPath)
+	require.True(resp.Mounts[0].Readonly)
+
+	out, err := ioutil.ReadFile(resp.Mounts[0].HostPath)
+	require.NoError(err)
+	require.Equal("nameserver 10.0.0.1\nsearch service.consul example.com\noptions ndots:1 rotate\n", string(out))
+}
@@ -40,8 +40,45 @@ func (h *DriverHandle) Update(task *structs.Task) error {
 	return nil
 }
 
+// Kill kills the task by going through its kill steps. The signal of each
+// step but the last is sent to the task, which then has the timeout of the
+// step to exit. The last step is left to the driver, which force kills the
+// task if it outlives the timeout.
 func (h *DriverHandle) Kill() error {
-	return h.driver.StopTask(h.taskID, h.task.KillTimeout, h.task.KillSignal)
+	steps := h.task.KillSteps()
+	last := len(steps) - 1
+	for _, step := range steps[:last] {
+		if err := h.driver.SignalTask(h.taskID, step.Signal); err != nil {
+			if err == drivers.ErrTaskNotFound {
+				return err
+			}
+
+			// Escalate right away if the signal can't be sent
+			continue
+		}
+		if h.waitExit(step.Timeout) {
+			return nil
+		}
+	}
+	return h.driver.StopTask(h.taskID, steps[last].Timeout, steps[last].Signal)
+}
+
+// waitExit waits up to the timeout for the task to exit and returns whether
+// it did.
+func (h *DriverHandle) waitExit(timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	waitCh, err := h.driver.WaitTask(ctx, h.taskID)
+	if err != nil {
+		return false
+	}
+	select {
+	case _, ok := <-waitCh:
+		return ok && ctx.Err() == nil
+	case <-ctx.Done():
+		return false
+	}
 }
 
 func (h *DriverHandle) Stats(ctx context.Context, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
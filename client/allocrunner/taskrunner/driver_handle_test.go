@@ -0,0 +1,118 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+)
+
+// killDriver is a driver recording the kill calls of a task, which exits
+// when sent exitSignal.
+type killDriver struct {
+	drivers.DriverPlugin
+
+	exitSignal string
+	signalErr  error
+
+	calls  []string
+	exitCh chan struct{}
+	mu     sync.Mutex
+}
+
+func newKillDriver(exitSignal string) *killDriver {
+	return &killDriver{
+		exitSignal: exitSignal,
+		exitCh:     make(chan struct{}),
+	}
+}
+
+func (d *killDriver) SignalTask(taskID, signal string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = append(d.calls, "signal "+signal)
+	if d.signalErr != nil {
+		return d.signalErr
+	}
+	if signal == d.exitSignal {
+		close(d.exitCh)
+	}
+	return nil
+}
+
+func (d *killDriver) StopTask(taskID string, timeout time.Duration, signal string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = append(d.calls, fmt.Sprintf("stop %s %v", signal, timeout))
+	return nil
+}
+
+func (d *killDriver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
+	ch := make(chan *drivers.ExitResult)
+	go func() {
+		defer close(ch)
+		select {
+		case <-d.exitCh:
+			ch <- &drivers.ExitResult{}
+		case <-ctx.Done():
+		}
+	}()
+	return ch, nil
+}
+
+func (d *killDriver) getCalls() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.calls...)
+}
+
+func TestDriverHandle_Kill(t *testing.T) {
+	t.Parallel()
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+	task.KillSignal = "SIGQUIT"
+	task.KillTimeout = 10 * time.Second
+
+	// Without an escalation chain the driver sends the kill signal
+	driver := newKillDriver("")
+	require.NoError(t, NewDriverHandle(driver, "id", task, nil).Kill())
+	require.Equal(t, []string{"stop SIGQUIT 10s"}, driver.getCalls())
+}
+
+func TestDriverHandle_Kill_Escalation(t *testing.T) {
+	t.Parallel()
+
+	task := mock.Job().TaskGroups[0].Tasks[0]
+	task.KillEscalation = []*structs.KillStep{
+		{Signal: "SIGTERM", Timeout: 10 * time.Millisecond},
+		{Signal: "SIGINT", Timeout: 10 * time.Millisecond},
+		{Signal: "SIGQUIT", Timeout: 5 * time.Second},
+	}
+
+	// The task outlives every signal
+	driver := newKillDriver("")
+	require.NoError(t, NewDriverHandle(driver, "id", task, nil).Kill())
+	require.Equal(t, []string{"signal SIGTERM", "signal SIGINT", "stop SIGQUIT 5s"}, driver.getCalls())
+
+	// The task exits on the second signal
+	driver = newKillDriver("SIGINT")
+	require.NoError(t, NewDriverHandle(driver, "id", task, nil).Kill())
+	require.Equal(t, []string{"signal SIGTERM", "signal SIGINT"}, driver.getCalls())
+
+	// Signals that can't be sent escalate right away
+	driver = newKillDriver("")
+	driver.signalErr = fmt.Errorf("not supported")
+	require.NoError(t, NewDriverHandle(driver, "id", task, nil).Kill())
+	require.Equal(t, []string{"signal SIGTERM", "signal SIGINT", "stop SIGQUIT 5s"}, driver.getCalls())
+
+	// Tasks that are gone are reported to the task runner
+	driver = newKillDriver("")
+	driver.signalErr = drivers.ErrTaskNotFound
+	require.Equal(t, drivers.ErrTaskNotFound, NewDriverHandle(driver, "id", task, nil).Kill())
+}
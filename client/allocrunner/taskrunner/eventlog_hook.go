@@ -0,0 +1,103 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/lib/eventlog"
+	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// eventLogHook collects the Windows Event Log entries of the providers of the
+// task into its log directory while the task is running.
+type eventLogHook struct {
+	task   *structs.Task
+	logDir string
+
+	// cancel is called by Exited
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+
+	logger hclog.Logger
+}
+
+func newEventLogHook(task *structs.Task, logDir string, logger hclog.Logger) *eventLogHook {
+	h := &eventLogHook{
+		task:   task,
+		logDir: logDir,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*eventLogHook) Name() string {
+	return "eventlog"
+}
+
+func (h *eventLogHook) Poststart(ctx context.Context, req *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !eventlog.Supported {
+		return fmt.Errorf("event log collection is only supported on Windows")
+	}
+
+	// This shouldn't happen, but better safe than risk leaking a goroutine
+	if h.cancel != nil {
+		h.logger.Debug("poststart called twice without exiting between")
+		h.cancel()
+	}
+
+	lc := h.task.LogConfig
+	rotator, err := logging.NewFileRotator(h.logDir, fmt.Sprintf("%s.eventlog", h.task.Name),
+		lc.MaxFiles, int64(lc.MaxFileSizeMB*1024*1024), h.logger)
+	if err != nil {
+		return fmt.Errorf("failed to create event log file: %v", err)
+	}
+
+	// Collect the events until the task exits rather than for the scope of
+	// the Poststart request
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.collect(ctx, rotator)
+
+	return nil
+}
+
+func (h *eventLogHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel == nil {
+		return nil
+	}
+
+	h.cancel()
+	h.cancel = nil
+	return nil
+}
+
+// collect writes the events to the rotator until the context is cancelled.
+func (h *eventLogHook) collect(ctx context.Context, rotator *logging.FileRotator) {
+	defer rotator.Close()
+
+	lc := h.task.LogConfig
+	channel := lc.EventLogChannel
+	if channel == "" {
+		channel = eventlog.DefaultChannel
+	}
+
+	err := eventlog.Subscribe(ctx, channel, lc.EventLogProviders, func(e *eventlog.Event) {
+		if _, err := rotator.Write([]byte(e.Format())); err != nil {
+			h.logger.Warn("failed to write event", "error", err)
+		}
+	})
+	if err != nil {
+		h.logger.Error("failed to collect the event log", "channel", channel, "error", err)
+	}
+}
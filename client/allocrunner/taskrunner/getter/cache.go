@@ -0,0 +1,170 @@
+package getter
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// cacheContent is the name of the content of an artifact within its entry of
+// the cache.
+const cacheContent = "content"
+
+// noopReplacer is an EnvReplacer for the artifacts of the cache, which aren't
+// interpolated.
+type noopReplacer struct{}
+
+func (noopReplacer) ReplaceEnv(s string) string {
+	return s
+}
+
+// Cache is the cache of the artifacts prefetched by the client, shared by its
+// tasks. Only prefetchable artifacts are cached, see
+// structs.TaskArtifact.Prefetchable.
+type Cache struct {
+	dir string
+}
+
+// NewCache returns the cache of artifacts stored in the directory.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// cacheKey returns the name of the entry of the artifact. Artifacts that only
+// differ by their destination share an entry.
+func cacheKey(artifact *structs.TaskArtifact) string {
+	a := artifact.Copy()
+	a.RelativeDest = ""
+
+	// The hash is base64 encoded and may contain path separators
+	return cacheKeyReplacer.Replace(a.Hash())
+}
+
+// cacheKeyReplacer maps the standard base64 alphabet to the URL safe one.
+var cacheKeyReplacer = strings.NewReplacer("/", "_", "+", "-")
+
+// Fetch downloads the artifact into the cache unless it's already cached.
+func (c *Cache) Fetch(artifact *structs.TaskArtifact) error {
+	entry := filepath.Join(c.dir, cacheKey(artifact))
+	if _, err := os.Stat(entry); err == nil {
+		return nil
+	}
+
+	// Download into a temporary directory so partial downloads are never
+	// used
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(c.dir, "fetch-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	a := artifact.Copy()
+	a.RelativeDest = cacheContent
+	if err := GetArtifact(noopReplacer{}, a, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, entry)
+}
+
+// Get copies the artifact from the cache into the task directory. It returns
+// false if the artifact isn't cached.
+func (c *Cache) Get(artifact *structs.TaskArtifact, taskDir string) (bool, error) {
+	if !artifact.Prefetchable() {
+		return false, nil
+	}
+
+	src := filepath.Join(c.dir, cacheKey(artifact), cacheContent)
+	if _, err := os.Lstat(src); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	dest := filepath.Join(taskDir, artifact.RelativeDest)
+	return true, copyTree(src, dest)
+}
+
+// Prune removes the artifacts from the cache other than the given ones.
+func (c *Cache) Prune(keep []*structs.TaskArtifact) error {
+	keys := make(map[string]struct{}, len(keep))
+	for _, artifact := range keep {
+		keys[cacheKey(artifact)] = struct{}{}
+	}
+
+	entries, err := ioutil.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if _, ok := keys[entry.Name()]; ok {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTree copies the file or directory src to dst, merging directories
+// with the existing content of dst.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+// copyFile copies the file src to dst, creating the parent directories of
+// dst.
+func copyFile(src, dst string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,77 @@
+package getter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	require := require.New(t)
+
+	ts := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir("./test-fixtures/"))))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := NewCache(filepath.Join(dir, "cache"))
+
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/test.sh", ts.URL),
+		GetterOptions: map[string]string{
+			"checksum": "md5:bce963762aa2dbfed13caf492a45fb72",
+		},
+		RelativeDest: "local/bin",
+	}
+
+	// Not cached yet
+	taskDir := filepath.Join(dir, "task")
+	ok, err := cache.Get(artifact, taskDir)
+	require.NoError(err)
+	require.False(ok)
+
+	require.NoError(cache.Fetch(artifact))
+
+	// Artifacts only differing by their destination share the entry
+	other := artifact.Copy()
+	other.RelativeDest = "local/other"
+	require.NoError(cache.Fetch(other))
+	entries, err := ioutil.ReadDir(filepath.Join(dir, "cache"))
+	require.NoError(err)
+	require.Len(entries, 1)
+
+	ok, err = cache.Get(artifact, taskDir)
+	require.NoError(err)
+	require.True(ok)
+	expected, err := ioutil.ReadFile("./test-fixtures/test.sh")
+	require.NoError(err)
+	actual, err := ioutil.ReadFile(filepath.Join(taskDir, "local/bin/test.sh"))
+	require.NoError(err)
+	require.Equal(expected, actual)
+
+	// Artifacts without a checksum aren't cached
+	noChecksum := artifact.Copy()
+	noChecksum.GetterOptions = nil
+	ok, err = cache.Get(noChecksum, taskDir)
+	require.NoError(err)
+	require.False(ok)
+
+	// Pruning removes the artifacts no longer prefetched
+	require.NoError(cache.Prune([]*structs.TaskArtifact{other}))
+	ok, err = cache.Get(artifact, taskDir)
+	require.NoError(err)
+	require.True(ok)
+
+	require.NoError(cache.Prune(nil))
+	ok, err = cache.Get(artifact, taskDir)
+	require.NoError(err)
+	require.False(ok)
+}
@@ -0,0 +1,244 @@
+package getter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/helper/cloudjson"
+)
+
+const (
+	// gcsServiceAccountOption is the artifact option of the service account
+	// to impersonate before downloading from GCS
+	gcsServiceAccountOption = "service_account"
+
+	// defaultGCPCredentialsURL is the address of the IAM Service Account
+	// Credentials API
+	defaultGCPCredentialsURL = "https://iamcredentials.googleapis.com/v1"
+
+	// gcsReadScope is the OAuth scope requested for impersonated service
+	// accounts
+	gcsReadScope = "https://www.googleapis.com/auth/devstorage.read_only"
+)
+
+// gcsGetter downloads artifacts from Google Cloud Storage. Sources take the
+// form gcs::https://www.googleapis.com/storage/v1/<bucket>/<path>. Requests
+// are authenticated with the default service account of the GCE instance,
+// or with a service account it impersonates if the service_account option is
+// set.
+type gcsGetter struct {
+	// metadataURL and credentialsURL are the addresses of the GCE metadata
+	// server and of the IAM Service Account Credentials API
+	metadataURL    string
+	credentialsURL string
+
+	httpClient *http.Client
+
+	// token caches the access token of the instance's service account
+	token cloudjson.GCPTokenCache
+}
+
+func newGCSGetter() *gcsGetter {
+	return &gcsGetter{
+		metadataURL:    cloudjson.DefaultGCPMetadataURL,
+		credentialsURL: defaultGCPCredentialsURL,
+		httpClient:     &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+// gcsObject is an object as returned by the GCS JSON API.
+type gcsObject struct {
+	Name string `json:"name"`
+}
+
+func (g *gcsGetter) ClientMode(u *url.URL) (gg.ClientMode, error) {
+	api, bucket, path, err := g.parseURL(u)
+	if err != nil {
+		return 0, err
+	}
+	token, err := g.accessToken(u)
+	if err != nil {
+		return 0, err
+	}
+
+	// Use file mode if the path is an object, otherwise assume it is a
+	// prefix of objects
+	req, err := http.NewRequest("GET", api+"/b/"+bucket+"/o/"+url.PathEscape(path), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	err = cloudjson.Do(g.httpClient, req, &gcsObject{})
+	if err == nil {
+		return gg.ClientModeFile, nil
+	}
+	if cloudjson.IsNotFound(err) {
+		return gg.ClientModeDir, nil
+	}
+	return 0, err
+}
+
+func (g *gcsGetter) Get(dst string, u *url.URL) error {
+	api, bucket, path, err := g.parseURL(u)
+	if err != nil {
+		return err
+	}
+	token, err := g.accessToken(u)
+	if err != nil {
+		return err
+	}
+
+	// Remove the destination if it already exists
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	pageToken := ""
+	for {
+		q := url.Values{"prefix": {prefix}}
+		if pageToken != "" {
+			q.Set("pageToken", pageToken)
+		}
+		req, err := http.NewRequest("GET", api+"/b/"+bucket+"/o?"+q.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		var resp struct {
+			Items         []gcsObject `json:"items"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+		if err := cloudjson.Do(g.httpClient, req, &resp); err != nil {
+			return fmt.Errorf("failed to list objects of gs://%s/%s: %v", bucket, prefix, err)
+		}
+
+		for _, object := range resp.Items {
+			// Skip the placeholders of directories
+			if strings.HasSuffix(object.Name, "/") {
+				continue
+			}
+
+			rel, err := filepath.Rel(prefix, object.Name)
+			if err != nil {
+				return err
+			}
+			if err := g.getObject(api, token, filepath.Join(dst, rel), bucket, object.Name, ""); err != nil {
+				return err
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (g *gcsGetter) GetFile(dst string, u *url.URL) error {
+	api, bucket, path, err := g.parseURL(u)
+	if err != nil {
+		return err
+	}
+	token, err := g.accessToken(u)
+	if err != nil {
+		return err
+	}
+	return g.getObject(api, token, dst, bucket, path, u.Query().Get("generation"))
+}
+
+// getObject downloads an object into the dst file.
+func (g *gcsGetter) getObject(api, token, dst, bucket, name, generation string) error {
+	q := url.Values{"alt": {"media"}}
+	if generation != "" {
+		q.Set("generation", generation)
+	}
+	req, err := http.NewRequest("GET", api+"/b/"+bucket+"/o/"+url.PathEscape(name)+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to download gs://%s/%s: unexpected response code %d: %s",
+			bucket, name, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+// parseURL returns the base address of the JSON API, the bucket and the path
+// of a GCS source.
+func (g *gcsGetter) parseURL(u *url.URL) (api, bucket, path string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/storage/v1/"), "/", 2)
+	if !strings.HasPrefix(u.Path, "/storage/v1/") || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("URL is not a valid GCS URL")
+	}
+	return u.Scheme + "://" + u.Host + "/storage/v1", parts[0], parts[1], nil
+}
+
+// accessToken returns the access token to download the source with: the
+// token of the instance's default service account, or of the service
+// account it impersonates if the source sets one.
+func (g *gcsGetter) accessToken(u *url.URL) (string, error) {
+	token, err := g.token.Token(g.httpClient, g.metadataURL)
+	if err != nil {
+		return "", err
+	}
+
+	account := u.Query().Get(gcsServiceAccountOption)
+	if account == "" {
+		return token, nil
+	}
+
+	body, err := json.Marshal(map[string][]string{"scope": {gcsReadScope}})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("POST", g.credentialsURL+"/projects/-/serviceAccounts/"+url.PathEscape(account)+":generateAccessToken", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	var out struct {
+		AccessToken string `json:"accessToken"`
+	}
+	if err := cloudjson.Do(g.httpClient, req, &out); err != nil {
+		return "", fmt.Errorf("failed to impersonate service account %q: %v", account, err)
+	}
+	return out.AccessToken, nil
+}
@@ -0,0 +1,161 @@
+package getter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gg "github.com/hashicorp/go-getter"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGCS returns a GCS getter backed by a fake metadata server and a fake
+// GCS API serving the given objects of the "bucket" bucket, and the address
+// of the API.
+func newTestGCS(t *testing.T, objects map[string]string) (*gcsGetter, string, func()) {
+	metadata := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`)
+	}))
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Header.Get("Authorization") == "Bearer token" && req.Method == "POST":
+			// Impersonation of a service account
+			fmt.Fprint(w, `{"accessToken":"impersonated"}`)
+			return
+		case req.Header.Get("Authorization") != "Bearer token" && req.Header.Get("Authorization") != "Bearer impersonated":
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		const prefix = "/storage/v1/b/bucket/o"
+		if req.URL.Path == prefix {
+			fmt.Fprint(w, `{"items":[`)
+			first := true
+			for name := range objects {
+				if !strings.HasPrefix(name, req.URL.Query().Get("prefix")) {
+					continue
+				}
+				if !first {
+					fmt.Fprint(w, ",")
+				}
+				first = false
+				fmt.Fprintf(w, `{"name":%q}`, name)
+			}
+			fmt.Fprint(w, `]}`)
+			return
+		}
+
+		name, err := url.PathUnescape(req.URL.EscapedPath()[len(prefix)+1:])
+		require.NoError(t, err)
+		content, ok := objects[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.URL.Query().Get("alt") == "media" {
+			fmt.Fprint(w, content)
+			return
+		}
+		fmt.Fprintf(w, `{"name":%q}`, name)
+	}))
+
+	g := newGCSGetter()
+	g.metadataURL = metadata.URL
+	g.credentialsURL = api.URL
+	return g, api.URL, func() {
+		metadata.Close()
+		api.Close()
+	}
+}
+
+func TestGCSGetter_File(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	g, api, cleanup := newTestGCS(t, map[string]string{"path/to/file.txt": "hello"})
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	u, err := url.Parse(api + "/storage/v1/bucket/path/to/file.txt")
+	require.NoError(err)
+
+	mode, err := g.ClientMode(u)
+	require.NoError(err)
+	require.Equal(gg.ClientModeFile, mode)
+
+	dst := filepath.Join(dir, "file.txt")
+	require.NoError(g.GetFile(dst, u))
+	content, err := ioutil.ReadFile(dst)
+	require.NoError(err)
+	require.Equal("hello", string(content))
+}
+
+func TestGCSGetter_Dir(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	g, api, cleanup := newTestGCS(t, map[string]string{
+		"path/a.txt":     "a",
+		"path/sub/b.txt": "b",
+		"path/sub/":      "",
+		"other/c.txt":    "c",
+	})
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	u, err := url.Parse(api + "/storage/v1/bucket/path?service_account=reader@project.iam.gserviceaccount.com")
+	require.NoError(err)
+
+	mode, err := g.ClientMode(u)
+	require.NoError(err)
+	require.Equal(gg.ClientModeDir, mode)
+
+	dst := filepath.Join(dir, "out")
+	require.NoError(g.Get(dst, u))
+
+	content, err := ioutil.ReadFile(filepath.Join(dst, "a.txt"))
+	require.NoError(err)
+	require.Equal("a", string(content))
+	content, err = ioutil.ReadFile(filepath.Join(dst, "sub", "b.txt"))
+	require.NoError(err)
+	require.Equal("b", string(content))
+
+	_, err = os.Stat(filepath.Join(dst, "c.txt"))
+	require.True(os.IsNotExist(err))
+}
+
+func TestGCSGetter_ParseURL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	g := newGCSGetter()
+	u, err := url.Parse("https://www.googleapis.com/storage/v1/bucket/path/to/file")
+	require.NoError(err)
+	api, bucket, path, err := g.parseURL(u)
+	require.NoError(err)
+	require.Equal("https://www.googleapis.com/storage/v1", api)
+	require.Equal("bucket", bucket)
+	require.Equal("path/to/file", path)
+
+	u, err = url.Parse("https://www.googleapis.com/bucket/path")
+	require.NoError(err)
+	_, _, _, err = g.parseURL(u)
+	require.Error(err)
+}
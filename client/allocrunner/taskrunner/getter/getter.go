@@ -18,7 +18,7 @@ var (
 	lock    sync.Mutex
 
 	// supported is the set of download schemes supported by Nomad
-	supported = []string{"http", "https", "s3", "hg", "git"}
+	supported = []string{"http", "https", "s3", "gcs", "hg", "git"}
 )
 
 const (
@@ -45,6 +45,10 @@ func getClient(src string, mode gg.ClientMode, dst string) *gg.Client {
 				getters[getter] = impl
 			}
 		}
+
+		// Cloud storage getters using the identity of the node
+		getters["s3"] = newS3Getter()
+		getters["gcs"] = newGCSGetter()
 	}
 
 	return &gg.Client{
@@ -0,0 +1,184 @@
+package getter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/signer/v4"
+	gg "github.com/hashicorp/go-getter"
+)
+
+const (
+	// s3RoleARNOption and s3RoleSessionNameOption are the artifact options
+	// of the role to assume before downloading from S3
+	s3RoleARNOption         = "role_arn"
+	s3RoleSessionNameOption = "role_session_name"
+
+	// defaultRoleSessionName is the session name of assumed roles if the
+	// artifact doesn't set one
+	defaultRoleSessionName = "nomad-artifact"
+
+	// stsAPIVersion is the version of the STS API
+	stsAPIVersion = "2011-06-15"
+)
+
+// s3Getter downloads artifacts from S3. Without static keys in the artifact
+// options, requests are signed with the default AWS credential chain, which
+// includes the instance profile of the node. If the role_arn option is set,
+// the role is assumed with those credentials and the download uses the
+// temporary credentials of the role.
+type s3Getter struct {
+	gg.S3Getter
+
+	// awsConfig is the base configuration of the STS client
+	awsConfig *aws.Config
+}
+
+func newS3Getter() *s3Getter {
+	return &s3Getter{
+		awsConfig: aws.NewConfig().WithRegion("us-east-1"),
+	}
+}
+
+func (g *s3Getter) ClientMode(u *url.URL) (gg.ClientMode, error) {
+	u, err := g.assumeRole(u)
+	if err != nil {
+		return 0, err
+	}
+	return g.S3Getter.ClientMode(u)
+}
+
+func (g *s3Getter) Get(dst string, u *url.URL) error {
+	u, err := g.assumeRole(u)
+	if err != nil {
+		return err
+	}
+	return g.S3Getter.Get(dst, u)
+}
+
+func (g *s3Getter) GetFile(dst string, u *url.URL) error {
+	u, err := g.assumeRole(u)
+	if err != nil {
+		return err
+	}
+	return g.S3Getter.GetFile(dst, u)
+}
+
+// assumeRole returns the URL to download with. If the URL sets a role to
+// assume, a copy of the URL is returned carrying the role's temporary
+// credentials in place of the role options.
+func (g *s3Getter) assumeRole(u *url.URL) (*url.URL, error) {
+	q := u.Query()
+	roleARN := q.Get(s3RoleARNOption)
+	if roleARN == "" {
+		return u, nil
+	}
+	sessionName := q.Get(s3RoleSessionNameOption)
+	if sessionName == "" {
+		sessionName = defaultRoleSessionName
+	}
+
+	// Static keys in the options are used to assume the role, otherwise the
+	// default credential chain is
+	cfg := g.awsConfig.Copy()
+	if q.Get("aws_access_key_id") != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(
+			q.Get("aws_access_key_id"),
+			q.Get("aws_access_key_secret"),
+			q.Get("aws_access_token"),
+		)
+	}
+
+	creds, err := stsAssumeRole(cfg, roleARN, sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume role %q: %v", roleARN, err)
+	}
+
+	q.Del(s3RoleARNOption)
+	q.Del(s3RoleSessionNameOption)
+	q.Set("aws_access_key_id", creds.AccessKeyID)
+	q.Set("aws_access_key_secret", creds.SecretAccessKey)
+	q.Set("aws_access_token", creds.SessionToken)
+
+	assumed := *u
+	assumed.RawQuery = q.Encode()
+	return &assumed, nil
+}
+
+// stsCredentials are the temporary credentials of an assumed role.
+type stsCredentials struct {
+	AccessKeyID     string    `xml:"AccessKeyId"`
+	SecretAccessKey string    `xml:"SecretAccessKey"`
+	SessionToken    string    `xml:"SessionToken"`
+	Expiration      time.Time `xml:"Expiration"`
+}
+
+// stsAssumeRole calls the STS AssumeRole operation using the query protocol,
+// which the vendored AWS SDK has no client for.
+func stsAssumeRole(cfg *aws.Config, roleARN, sessionName string) (*stsCredentials, error) {
+	cc := session.New(cfg).ClientConfig("sts")
+	svc := client.New(
+		*cc.Config,
+		metadata.ClientInfo{
+			ServiceName:   "sts",
+			SigningRegion: cc.SigningRegion,
+			Endpoint:      cc.Endpoint,
+			APIVersion:    stsAPIVersion,
+		},
+		cc.Handlers,
+	)
+	svc.Handlers.Sign.PushBack(v4.Sign)
+	svc.Handlers.Build.PushBack(func(r *request.Request) {
+		body := url.Values{
+			"Action":          {r.Operation.Name},
+			"Version":         {r.ClientInfo.APIVersion},
+			"RoleArn":         {roleARN},
+			"RoleSessionName": {sessionName},
+		}
+		r.SetBufferBody([]byte(body.Encode()))
+		r.HTTPRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	})
+	svc.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		defer r.HTTPResponse.Body.Close()
+		var resp struct {
+			Credentials stsCredentials `xml:"AssumeRoleResult>Credentials"`
+		}
+		if err := xml.NewDecoder(r.HTTPResponse.Body).Decode(&resp); err != nil {
+			r.Error = awserr.New("SerializationError", "failed to decode response", err)
+			return
+		}
+		*(r.Data.(*stsCredentials)) = resp.Credentials
+	})
+	svc.Handlers.UnmarshalError.PushBack(func(r *request.Request) {
+		defer r.HTTPResponse.Body.Close()
+		var resp struct {
+			Code    string `xml:"Error>Code"`
+			Message string `xml:"Error>Message"`
+		}
+		xml.NewDecoder(r.HTTPResponse.Body).Decode(&resp)
+		if resp.Code == "" {
+			resp.Code = "UnknownError"
+		}
+		r.Error = awserr.NewRequestFailure(awserr.New(resp.Code, resp.Message, nil), r.HTTPResponse.StatusCode, r.RequestID)
+	})
+
+	op := &request.Operation{
+		Name:       "AssumeRole",
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	creds := &stsCredentials{}
+	if err := svc.NewRequest(op, &struct{}{}, creds).Send(); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
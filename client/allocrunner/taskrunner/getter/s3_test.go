@@ -0,0 +1,72 @@
+package getter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Getter_AssumeRole(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.NoError(req.ParseForm())
+		require.Equal("AssumeRole", req.Form.Get("Action"))
+		require.Contains(req.Header.Get("Authorization"), "Credential=AKIABASE/")
+
+		if req.Form.Get("RoleArn") != "arn:aws:iam::123456789012:role/artifacts" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<ErrorResponse><Error><Code>AccessDenied</Code><Message>not authorized</Message></Error></ErrorResponse>`)
+			return
+		}
+		require.Equal("deploy", req.Form.Get("RoleSessionName"))
+		fmt.Fprint(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>
+<AccessKeyId>ASIAROLE</AccessKeyId>
+<SecretAccessKey>secret</SecretAccessKey>
+<SessionToken>token</SessionToken>
+<Expiration>2030-01-01T00:00:00Z</Expiration>
+</Credentials></AssumeRoleResult></AssumeRoleResponse>`)
+	}))
+	defer srv.Close()
+
+	g := newS3Getter()
+	g.awsConfig = aws.NewConfig().
+		WithRegion("us-east-1").
+		WithEndpoint(srv.URL).
+		WithCredentials(credentials.NewStaticCredentials("AKIABASE", "secret", ""))
+
+	// URLs without a role are unchanged
+	u, err := url.Parse("https://s3.amazonaws.com/bucket/key")
+	require.NoError(err)
+	assumed, err := g.assumeRole(u)
+	require.NoError(err)
+	require.Equal(u, assumed)
+
+	u, err = url.Parse("https://s3.amazonaws.com/bucket/key?role_arn=arn:aws:iam::123456789012:role/artifacts&role_session_name=deploy")
+	require.NoError(err)
+	assumed, err = g.assumeRole(u)
+	require.NoError(err)
+
+	q := assumed.Query()
+	require.Equal("ASIAROLE", q.Get("aws_access_key_id"))
+	require.Equal("secret", q.Get("aws_access_key_secret"))
+	require.Equal("token", q.Get("aws_access_token"))
+	require.Empty(q.Get(s3RoleARNOption))
+	require.Empty(q.Get(s3RoleSessionNameOption))
+
+	// The original URL isn't modified
+	require.Equal("deploy", u.Query().Get(s3RoleSessionNameOption))
+
+	u, err = url.Parse("https://s3.amazonaws.com/bucket/key?role_arn=arn:aws:iam::123456789012:role/other")
+	require.NoError(err)
+	_, err = g.assumeRole(u)
+	require.Error(err)
+	require.Contains(err.Error(), "AccessDenied")
+}
@@ -0,0 +1,107 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// workloadTokenFile is the name of the file holding the workload identity
+	// inside the task's secret directory
+	workloadTokenFile = "nomad_token"
+)
+
+// identityHook exposes the workload identity of the task to it, so the task
+// can call the Nomad API. The identity is signed by the servers and is valid
+// for as long as the allocation runs.
+type identityHook struct {
+	alloc        *structs.Allocation
+	task         string
+	clientConfig *config.Config
+	rpcClient    cinterfaces.RPCer
+	logger       log.Logger
+}
+
+func newIdentityHook(alloc *structs.Allocation, task string, clientConfig *config.Config,
+	rpcClient cinterfaces.RPCer, logger log.Logger) *identityHook {
+	h := &identityHook{
+		alloc:        alloc,
+		task:         task,
+		clientConfig: clientConfig,
+		rpcClient:    rpcClient,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*identityHook) Name() string {
+	return "identity"
+}
+
+func (h *identityHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	identity := req.Task.Identity
+	if identity == nil || (!identity.Env && !identity.File) {
+		resp.Done = true
+		return nil
+	}
+
+	token, err := h.signIdentity()
+	if err != nil {
+		return err
+	}
+
+	if identity.File {
+		tokenPath := filepath.Join(req.TaskDir.SecretsDir, workloadTokenFile)
+		if err := ioutil.WriteFile(tokenPath, []byte(token), 0666); err != nil {
+			return fmt.Errorf("failed to write workload identity: %v", err)
+		}
+	}
+	if identity.Env {
+		resp.Env = map[string]string{taskenv.WorkloadToken: token}
+	}
+
+	// The identity is valid until the allocation is terminal, so it doesn't
+	// need to be signed again when the task restarts
+	resp.Done = true
+	return nil
+}
+
+// signIdentity requests the workload identity of the task for the Nomad API
+// from the servers.
+func (h *identityHook) signIdentity() (string, error) {
+	node := h.clientConfig.Node
+	req := &structs.SignIdentitiesRequest{
+		NodeID:   node.ID,
+		SecretID: node.SecretID,
+		AllocID:  h.alloc.ID,
+		Tasks:    []string{h.task},
+		Audience: structs.IdentityAudienceNomad,
+		QueryOptions: structs.QueryOptions{
+			Region:     h.clientConfig.Region,
+			AllowStale: false,
+		},
+	}
+
+	var resp structs.SignIdentitiesResponse
+	if err := h.rpcClient.RPC("Node.SignIdentities", req, &resp); err != nil {
+		return "", structs.NewRecoverableError(fmt.Errorf("failed to sign workload identity: %v", err), true)
+	}
+	if resp.Error != nil {
+		return "", structs.NewWrappedServerError(resp.Error)
+	}
+
+	token, ok := resp.Tasks[h.task]
+	if !ok {
+		return "", fmt.Errorf("workload identity missing for task %q", h.task)
+	}
+	return token, nil
+}
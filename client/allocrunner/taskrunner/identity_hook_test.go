@@ -0,0 +1,89 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// Statically assert the identity hook implements the expected interfaces
+var _ interfaces.TaskPrestartHook = (*identityHook)(nil)
+
+// mockIdentityRPC is a mock of the RPC client that serves
+// Node.SignIdentities with a fixed token
+type mockIdentityRPC struct {
+	args *structs.SignIdentitiesRequest
+}
+
+func (m *mockIdentityRPC) RPC(method string, args interface{}, reply interface{}) error {
+	if method != "Node.SignIdentities" {
+		return fmt.Errorf("unexpected RPC %q", method)
+	}
+
+	m.args = args.(*structs.SignIdentitiesRequest)
+	resp := reply.(*structs.SignIdentitiesResponse)
+	resp.Tasks = map[string]string{}
+	for _, task := range m.args.Tasks {
+		resp.Tasks[task] = "header.claims.signature"
+	}
+	return nil
+}
+
+func TestTaskRunner_IdentityHook(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	logger := testlog.HCLogger(t)
+	allocDir := allocdir.NewAllocDir(logger, "nomadtest_identity")
+	defer allocDir.Destroy()
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identity = &structs.WorkloadIdentity{Env: true, File: true}
+	taskDir := allocDir.NewTaskDir(task.Name)
+	require.NoError(taskDir.Build(false, nil))
+
+	conf := config.DefaultConfig()
+	conf.Node = mock.Node()
+	rpc := &mockIdentityRPC{}
+	h := newIdentityHook(alloc, task.Name, conf, rpc, logger)
+
+	req := interfaces.TaskPrestartRequest{
+		Task:    task,
+		TaskDir: taskDir,
+	}
+	resp := interfaces.TaskPrestartResponse{}
+	require.NoError(h.Prestart(context.Background(), &req, &resp))
+	require.True(resp.Done)
+
+	// The identity is requested for the Nomad API
+	require.Equal(structs.IdentityAudienceNomad, rpc.args.Audience)
+	require.Equal(conf.Node.SecretID, rpc.args.SecretID)
+	require.Equal([]string{task.Name}, rpc.args.Tasks)
+
+	// The identity is exposed in the environment and the secrets dir
+	require.Equal("header.claims.signature", resp.Env[taskenv.WorkloadToken])
+	token, err := ioutil.ReadFile(filepath.Join(taskDir.SecretsDir, workloadTokenFile))
+	require.NoError(err)
+	require.Equal("header.claims.signature", string(token))
+
+	// Nothing is requested if the identity isn't exposed
+	rpc.args = nil
+	task.Identity = &structs.WorkloadIdentity{}
+	resp = interfaces.TaskPrestartResponse{}
+	require.NoError(h.Prestart(context.Background(), &req, &resp))
+	require.True(resp.Done)
+	require.Nil(rpc.args)
+	require.Empty(resp.Env)
+}
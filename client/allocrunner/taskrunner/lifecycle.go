@@ -2,6 +2,7 @@ package taskrunner
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -93,3 +94,19 @@ func (tr *TaskRunner) Kill(ctx context.Context, event *structs.TaskEvent) error
 
 	return tr.getKillErr()
 }
+
+// Exec runs the command inside the running task and returns its output and
+// exit code. Returns ErrTaskNotRunning if the task is not running.
+func (tr *TaskRunner) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	tr.logger.Trace("Exec requested", "command", cmd)
+
+	// Grab the handle
+	handle := tr.getDriverHandle()
+
+	// Check it is running
+	if handle == nil {
+		return nil, 0, ErrTaskNotRunning
+	}
+
+	return handle.Exec(timeout, cmd, args)
+}
@@ -0,0 +1,102 @@
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/keyring"
+)
+
+const (
+	// HookNameSecretsDecrypt is the name of the hook decrypting the secrets
+	// directory
+	HookNameSecretsDecrypt = "secrets_decrypt"
+
+	// HookNameSecretsEncrypt is the name of the hook encrypting the secrets
+	// directory
+	HookNameSecretsEncrypt = "secrets_encrypt"
+)
+
+// secretsDecryptHook decrypts the secrets directory of the task before it
+// starts. It runs before the hooks reading or writing secrets, such as the
+// vault and template hooks.
+type secretsDecryptHook struct {
+	keyring *keyring.Keyring
+	logger  log.Logger
+}
+
+func newSecretsDecryptHook(kr *keyring.Keyring, logger log.Logger) *secretsDecryptHook {
+	h := &secretsDecryptHook{keyring: kr}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*secretsDecryptHook) Name() string {
+	return HookNameSecretsDecrypt
+}
+
+func (h *secretsDecryptHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	if err := walkSecretsDir(req.TaskDir.SecretsDir, h.keyring.DecryptFile); err != nil {
+		return fmt.Errorf("failed to decrypt secrets directory: %v", err)
+	}
+	return nil
+}
+
+// secretsEncryptHook encrypts the secrets directory of the task once it
+// exits, so that rendered secrets aren't stored in plaintext while the task
+// isn't running. It runs after all the other hooks so that no secrets are
+// written once it's done.
+type secretsEncryptHook struct {
+	keyring    *keyring.Keyring
+	secretsDir string
+	logger     log.Logger
+}
+
+func newSecretsEncryptHook(kr *keyring.Keyring, secretsDir string, logger log.Logger) *secretsEncryptHook {
+	h := &secretsEncryptHook{
+		keyring:    kr,
+		secretsDir: secretsDir,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*secretsEncryptHook) Name() string {
+	return HookNameSecretsEncrypt
+}
+
+func (h *secretsEncryptHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	return h.encrypt()
+}
+
+func (h *secretsEncryptHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	return h.encrypt()
+}
+
+func (h *secretsEncryptHook) encrypt() error {
+	if err := walkSecretsDir(h.secretsDir, h.keyring.EncryptFile); err != nil {
+		return fmt.Errorf("failed to encrypt secrets directory: %v", err)
+	}
+	return nil
+}
+
+// walkSecretsDir calls fn for every regular file of the secrets directory.
+// A missing directory is ignored.
+func walkSecretsDir(dir string, fn func(path string) error) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return fn(path)
+	})
+}
@@ -0,0 +1,67 @@
+package taskrunner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/keyring"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSecretsEncryptionHooks asserts the secrets directory is encrypted when
+// the task exits or stops and decrypted before it starts again.
+func TestSecretsEncryptionHooks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "secrets_hook")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	kr, err := keyring.Load(dir, nil)
+	require.NoError(err)
+
+	secretsDir := filepath.Join(dir, "secrets")
+	require.NoError(os.MkdirAll(filepath.Join(secretsDir, "nested"), 0755))
+	token := filepath.Join(secretsDir, "vault_token")
+	nested := filepath.Join(secretsDir, "nested", "config.json")
+	require.NoError(ioutil.WriteFile(token, []byte("token"), 0600))
+	require.NoError(ioutil.WriteFile(nested, []byte("config"), 0640))
+
+	encrypt := newSecretsEncryptHook(kr, secretsDir, testlog.HCLogger(t))
+	decrypt := newSecretsDecryptHook(kr, testlog.HCLogger(t))
+
+	// Exiting encrypts all the files
+	require.NoError(encrypt.Exited(context.Background(), &interfaces.TaskExitedRequest{}, &interfaces.TaskExitedResponse{}))
+	require.True(keyring.IsEncryptedFile(token))
+	require.True(keyring.IsEncryptedFile(nested))
+
+	// Starting again decrypts them
+	req := &interfaces.TaskPrestartRequest{
+		TaskDir: &allocdir.TaskDir{SecretsDir: secretsDir},
+	}
+	require.NoError(decrypt.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+	buf, err := ioutil.ReadFile(token)
+	require.NoError(err)
+	require.Equal("token", string(buf))
+	buf, err = ioutil.ReadFile(nested)
+	require.NoError(err)
+	require.Equal("config", string(buf))
+
+	// Stopping encrypts them, even after exiting
+	require.NoError(encrypt.Exited(context.Background(), &interfaces.TaskExitedRequest{}, &interfaces.TaskExitedResponse{}))
+	require.NoError(encrypt.Stop(context.Background(), &interfaces.TaskStopRequest{}, &interfaces.TaskStopResponse{}))
+	require.True(keyring.IsEncryptedFile(token))
+	require.NoError(decrypt.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+	require.False(keyring.IsEncryptedFile(token))
+
+	// A missing secrets directory is ignored
+	require.NoError(os.RemoveAll(secretsDir))
+	require.NoError(encrypt.Stop(context.Background(), &interfaces.TaskStopRequest{}, &interfaces.TaskStopResponse{}))
+}
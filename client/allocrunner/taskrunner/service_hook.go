@@ -10,6 +10,8 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	tinterfaces "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/consul"
+	"github.com/hashicorp/nomad/client/externalservices"
+	"github.com/hashicorp/nomad/client/nomadservices"
 	"github.com/hashicorp/nomad/client/taskenv"
 	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -21,6 +23,13 @@ type serviceHookConfig struct {
 	task   *structs.Task
 	consul consul.ConsulServiceAPI
 
+	// nomadServices registers the services using the Nomad service provider
+	nomadServices nomadservices.ServiceAPI
+
+	// externalServices registers the services using external service
+	// providers
+	externalServices externalservices.ServiceAPI
+
 	// Restarter is a subset of the TaskLifecycle interface
 	restarter agentconsul.TaskRestarter
 
@@ -28,11 +37,15 @@ type serviceHookConfig struct {
 }
 
 type serviceHook struct {
-	consul    consul.ConsulServiceAPI
-	allocID   string
-	taskName  string
-	restarter agentconsul.TaskRestarter
-	logger    log.Logger
+	consul           consul.ConsulServiceAPI
+	nomadServices    nomadservices.ServiceAPI
+	externalServices externalservices.ServiceAPI
+	allocID          string
+	namespace        string
+	jobID            string
+	taskName         string
+	restarter        agentconsul.TaskRestarter
+	logger           log.Logger
 
 	// The following fields may be updated
 	delay      time.Duration
@@ -50,12 +63,16 @@ type serviceHook struct {
 
 func newServiceHook(c serviceHookConfig) *serviceHook {
 	h := &serviceHook{
-		consul:    c.consul,
-		allocID:   c.alloc.ID,
-		taskName:  c.task.Name,
-		services:  c.task.Services,
-		restarter: c.restarter,
-		delay:     c.task.ShutdownDelay,
+		consul:           c.consul,
+		nomadServices:    c.nomadServices,
+		externalServices: c.externalServices,
+		allocID:          c.alloc.ID,
+		namespace:        c.alloc.Namespace,
+		jobID:            c.alloc.JobID,
+		taskName:         c.task.Name,
+		services:         c.task.Services,
+		restarter:        c.restarter,
+		delay:            c.task.ShutdownDelay,
 	}
 
 	// COMPAT(0.10): Just use the AllocatedResources
@@ -91,9 +108,26 @@ func (h *serviceHook) Poststart(ctx context.Context, req *interfaces.TaskPoststa
 	h.taskEnv = req.TaskEnv
 
 	// Create task services struct with request's driver metadata
-	taskServices := h.getTaskServices()
+	consulServices, nomadServices, externalServices := splitTaskServices(h.getTaskServices())
 
-	return h.consul.RegisterTask(taskServices)
+	if err := h.consul.RegisterTask(consulServices); err != nil {
+		return err
+	}
+	if len(nomadServices.Services) > 0 {
+		if h.nomadServices == nil {
+			return fmt.Errorf("the %q service provider is not available", structs.ServiceProviderNomad)
+		}
+		if err := h.nomadServices.RegisterTask(nomadServices); err != nil {
+			return err
+		}
+	}
+	if len(externalServices.Services) > 0 {
+		if h.externalServices == nil {
+			return fmt.Errorf("the %q service provider is not available", externalServices.Services[0].Provider)
+		}
+		return h.externalServices.RegisterTask(externalServices)
+	}
+	return nil
 }
 
 func (h *serviceHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
@@ -102,7 +136,7 @@ func (h *serviceHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequ
 
 	// Create old task services struct with request's driver metadata as it
 	// can't change due to Updates
-	oldTaskServices := h.getTaskServices()
+	oldConsulServices, oldNomadServices, oldExternalServices := splitTaskServices(h.getTaskServices())
 
 	// Store new updated values out of request
 	canary := false
@@ -135,9 +169,26 @@ func (h *serviceHook) Update(ctx context.Context, req *interfaces.TaskUpdateRequ
 	h.canary = canary
 
 	// Create new task services struct with those new values
-	newTaskServices := h.getTaskServices()
+	newConsulServices, newNomadServices, newExternalServices := splitTaskServices(h.getTaskServices())
 
-	return h.consul.UpdateTask(oldTaskServices, newTaskServices)
+	if err := h.consul.UpdateTask(oldConsulServices, newConsulServices); err != nil {
+		return err
+	}
+	if len(oldNomadServices.Services) > 0 || len(newNomadServices.Services) > 0 {
+		if h.nomadServices == nil {
+			return fmt.Errorf("the %q service provider is not available", structs.ServiceProviderNomad)
+		}
+		if err := h.nomadServices.UpdateTask(oldNomadServices, newNomadServices); err != nil {
+			return err
+		}
+	}
+	if len(oldExternalServices.Services) > 0 || len(newExternalServices.Services) > 0 {
+		if h.externalServices == nil {
+			return fmt.Errorf("no external service providers are available")
+		}
+		return h.externalServices.UpdateTask(oldExternalServices, newExternalServices)
+	}
+	return nil
 }
 
 func (h *serviceHook) PreKilling(ctx context.Context, req *interfaces.TaskPreKillRequest, resp *interfaces.TaskPreKillResponse) error {
@@ -147,15 +198,21 @@ func (h *serviceHook) PreKilling(ctx context.Context, req *interfaces.TaskPreKil
 	// Deregister before killing task
 	h.deregister()
 
+	// The job may have been stopped with a longer delay than the task's
+	delay := h.delay
+	if req.ShutdownDelay > delay {
+		delay = req.ShutdownDelay
+	}
+
 	// If there's no shutdown delay, exit early
-	if h.delay == 0 {
+	if delay == 0 {
 		return nil
 	}
 
-	h.logger.Debug("waiting before killing task", "shutdown_delay", h.delay)
+	h.logger.Debug("waiting before killing task", "shutdown_delay", delay)
 	select {
 	case <-ctx.Done():
-	case <-time.After(h.delay):
+	case <-time.After(delay):
 	}
 	return nil
 }
@@ -167,16 +224,28 @@ func (h *serviceHook) Exited(context.Context, *interfaces.TaskExitedRequest, *in
 	return nil
 }
 
-// deregister services from Consul.
+// deregister services from Consul, the Nomad service provider and external
+// service providers.
 func (h *serviceHook) deregister() {
-	taskServices := h.getTaskServices()
-	h.consul.RemoveTask(taskServices)
+	consulServices, nomadServices, externalServices := splitTaskServices(h.getTaskServices())
+	h.consul.RemoveTask(consulServices)
 
 	// Canary flag may be getting flipped when the alloc is being
 	// destroyed, so remove both variations of the service
-	taskServices.Canary = !taskServices.Canary
-	h.consul.RemoveTask(taskServices)
+	consulServices.Canary = !consulServices.Canary
+	h.consul.RemoveTask(consulServices)
 
+	if h.nomadServices != nil && len(nomadServices.Services) > 0 {
+		h.nomadServices.RemoveTask(nomadServices)
+		nomadServices.Canary = !nomadServices.Canary
+		h.nomadServices.RemoveTask(nomadServices)
+	}
+
+	if h.externalServices != nil && len(externalServices.Services) > 0 {
+		h.externalServices.RemoveTask(externalServices)
+		externalServices.Canary = !externalServices.Canary
+		h.externalServices.RemoveTask(externalServices)
+	}
 }
 
 func (h *serviceHook) getTaskServices() *agentconsul.TaskServices {
@@ -186,6 +255,8 @@ func (h *serviceHook) getTaskServices() *agentconsul.TaskServices {
 	// Create task services struct with request's driver metadata
 	return &agentconsul.TaskServices{
 		AllocID:       h.allocID,
+		Namespace:     h.namespace,
+		JobID:         h.jobID,
 		Name:          h.taskName,
 		Restarter:     h.restarter,
 		Services:      interpolatedServices,
@@ -196,6 +267,25 @@ func (h *serviceHook) getTaskServices() *agentconsul.TaskServices {
 	}
 }
 
+// splitTaskServices returns copies of the task services holding only the
+// services registered with Consul, the Nomad service provider and external
+// service providers.
+func splitTaskServices(ts *agentconsul.TaskServices) (consulServices, nomadServices, externalServices *agentconsul.TaskServices) {
+	c, n, e := *ts, *ts, *ts
+	c.Services, n.Services, e.Services = nil, nil, nil
+	for _, service := range ts.Services {
+		switch {
+		case service.UsesConsul():
+			c.Services = append(c.Services, service)
+		case service.Provider == structs.ServiceProviderNomad:
+			n.Services = append(n.Services, service)
+		default:
+			e.Services = append(e.Services, service)
+		}
+	}
+	return &c, &n, &e
+}
+
 // interpolateServices returns an interpolated copy of services and checks with
 // values from the task's environment.
 func interpolateServices(taskEnv *taskenv.TaskEnv, services []*structs.Service) []*structs.Service {
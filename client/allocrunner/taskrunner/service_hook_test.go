@@ -1,10 +1,16 @@
 package taskrunner
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/taskenv"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
 )
@@ -91,3 +97,63 @@ func TestTaskRunner_ServiceHook_InterpolateServices(t *testing.T) {
 
 	require.Equal(t, exp, interpolated)
 }
+
+// TestTaskRunner_ServiceHook_SplitTaskServices asserts that services are
+// split by their provider.
+func TestTaskRunner_ServiceHook_SplitTaskServices(t *testing.T) {
+	t.Parallel()
+	ts := &agentconsul.TaskServices{
+		AllocID: "alloc",
+		Name:    "web",
+		Services: []*structs.Service{
+			{Name: "default"},
+			{Name: "consul", Provider: structs.ServiceProviderConsul},
+			{Name: "nomad", Provider: structs.ServiceProviderNomad},
+			{Name: "lb", Provider: "f5"},
+		},
+	}
+
+	consulServices, nomadServices, externalServices := splitTaskServices(ts)
+	require.Len(t, consulServices.Services, 2)
+	require.Equal(t, "default", consulServices.Services[0].Name)
+	require.Equal(t, "consul", consulServices.Services[1].Name)
+	require.Len(t, nomadServices.Services, 1)
+	require.Equal(t, "nomad", nomadServices.Services[0].Name)
+	require.Equal(t, "alloc", nomadServices.AllocID)
+	require.Equal(t, "web", nomadServices.Name)
+	require.Len(t, externalServices.Services, 1)
+	require.Equal(t, "lb", externalServices.Services[0].Name)
+
+	// The original services are untouched
+	require.Len(t, ts.Services, 4)
+}
+
+// TestTaskRunner_ServiceHook_PreKilling_ShutdownDelay asserts that the
+// shutdown delay of a stopped job overrides a shorter delay of the task.
+func TestTaskRunner_ServiceHook_PreKilling_ShutdownDelay(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	logger := testlog.HCLogger(t)
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.ShutdownDelay = 0
+	consulClient := consul.NewMockConsulServiceClient(t, logger)
+
+	h := newServiceHook(serviceHookConfig{
+		alloc:  alloc,
+		task:   task,
+		consul: consulClient,
+		logger: logger,
+	})
+
+	start := time.Now()
+	req := &interfaces.TaskPreKillRequest{ShutdownDelay: 100 * time.Millisecond}
+	require.NoError(h.PreKilling(context.Background(), req, nil))
+	require.True(time.Since(start) >= 100*time.Millisecond)
+
+	// The services were deregistered before waiting
+	ops := consulClient.GetOps()
+	require.NotEmpty(ops)
+	require.Equal("remove", ops[0].Op)
+}
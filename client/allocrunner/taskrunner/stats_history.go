@@ -0,0 +1,75 @@
+package taskrunner
+
+import (
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+const (
+	// statsHistoryRetention is how long the resource usage of a task is
+	// kept in memory to answer range queries.
+	statsHistoryRetention = 10 * time.Minute
+)
+
+// statsHistory is a ring buffer of the resource usage collected for a task,
+// sized to hold the samples collected during the retention period. It is not
+// safe for concurrent use.
+type statsHistory struct {
+	samples []*cstructs.TaskResourceUsage
+	next    int
+	full    bool
+}
+
+// newStatsHistory returns a history holding the samples collected every
+// interval during the retention period.
+func newStatsHistory(interval time.Duration) *statsHistory {
+	size := 1
+	if interval > 0 {
+		size = int((statsHistoryRetention + interval - 1) / interval)
+	}
+	return &statsHistory{
+		samples: make([]*cstructs.TaskResourceUsage, size),
+	}
+}
+
+// add records a sample, overwriting the oldest one if the history is full.
+// The per process usage isn't kept.
+func (h *statsHistory) add(ru *cstructs.TaskResourceUsage) {
+	usage := *ru.ResourceUsage
+	h.samples[h.next] = &cstructs.TaskResourceUsage{
+		ResourceUsage: &usage,
+		Timestamp:     ru.Timestamp,
+	}
+
+	h.next++
+	if h.next == len(h.samples) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// between returns the samples collected between the start and end UnixNano
+// timestamps inclusive, oldest first. Samples older than the retention
+// period are never returned.
+func (h *statsHistory) between(start, end int64, now time.Time) []*cstructs.TaskResourceUsage {
+	if oldest := now.Add(-statsHistoryRetention).UnixNano(); start < oldest {
+		start = oldest
+	}
+
+	var first, count int
+	if h.full {
+		first, count = h.next, len(h.samples)
+	} else {
+		count = h.next
+	}
+
+	var out []*cstructs.TaskResourceUsage
+	for i := 0; i < count; i++ {
+		ru := h.samples[(first+i)%len(h.samples)]
+		if ru.Timestamp >= start && ru.Timestamp <= end {
+			out = append(out, ru)
+		}
+	}
+	return out
+}
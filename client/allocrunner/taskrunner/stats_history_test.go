@@ -0,0 +1,66 @@
+package taskrunner
+
+import (
+	"testing"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHistory(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// The history holds the samples of the retention period
+	h := newStatsHistory(time.Minute)
+	require.Len(h.samples, 10)
+
+	now := time.Now()
+	sample := func(ago time.Duration) *cstructs.TaskResourceUsage {
+		return &cstructs.TaskResourceUsage{
+			ResourceUsage: &cstructs.ResourceUsage{
+				MemoryStats: &cstructs.MemoryStats{RSS: uint64(ago)},
+			},
+			Timestamp: now.Add(-ago).UnixNano(),
+			Pids:      map[string]*cstructs.ResourceUsage{"1": {}},
+		}
+	}
+	timestamps := func(samples []*cstructs.TaskResourceUsage) []int64 {
+		var out []int64
+		for _, s := range samples {
+			out = append(out, s.Timestamp)
+		}
+		return out
+	}
+
+	// Samples are returned oldest first, without their per process usage
+	for i := 12; i > 0; i-- {
+		h.add(sample(time.Duration(i) * time.Minute))
+	}
+	all := h.between(0, now.UnixNano(), now)
+	require.Equal([]int64{
+		now.Add(-10 * time.Minute).UnixNano(),
+		now.Add(-9 * time.Minute).UnixNano(),
+		now.Add(-8 * time.Minute).UnixNano(),
+		now.Add(-7 * time.Minute).UnixNano(),
+		now.Add(-6 * time.Minute).UnixNano(),
+		now.Add(-5 * time.Minute).UnixNano(),
+		now.Add(-4 * time.Minute).UnixNano(),
+		now.Add(-3 * time.Minute).UnixNano(),
+		now.Add(-2 * time.Minute).UnixNano(),
+		now.Add(-1 * time.Minute).UnixNano(),
+	}, timestamps(all))
+	require.Nil(all[0].Pids)
+	require.Equal(uint64(10*time.Minute), all[0].ResourceUsage.MemoryStats.RSS)
+
+	// Ranges are inclusive
+	require.Equal([]int64{
+		now.Add(-3 * time.Minute).UnixNano(),
+		now.Add(-2 * time.Minute).UnixNano(),
+	}, timestamps(h.between(now.Add(-3*time.Minute).UnixNano(), now.Add(-2*time.Minute).UnixNano(), now)))
+
+	// Samples older than the retention period are never returned
+	later := now.Add(5 * time.Minute)
+	require.Len(h.between(0, later.UnixNano(), later), 5)
+}
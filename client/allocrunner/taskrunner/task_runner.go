@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,7 +20,10 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
+	"github.com/hashicorp/nomad/client/externalservices"
 	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/keyring"
+	"github.com/hashicorp/nomad/client/nomadservices"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	cstate "github.com/hashicorp/nomad/client/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -35,8 +39,9 @@ import (
 
 const (
 	// defaultMaxEvents is the default max capacity for task events on the
-	// task state. Overrideable for testing.
-	defaultMaxEvents = 10
+	// task state if the client config doesn't set one. Overrideable for
+	// testing.
+	defaultMaxEvents = 25
 
 	// killBackoffBaseline is the baseline time for exponential backoff while
 	// killing a task.
@@ -158,6 +163,18 @@ type TaskRunner struct {
 	// registering services and checks
 	consulClient consul.ConsulServiceAPI
 
+	// nomadServices is the client used by the service hook for registering
+	// services with the Nomad service provider
+	nomadServices nomadservices.ServiceAPI
+
+	// externalServices is the client used by the service hook for
+	// registering services with external service providers
+	externalServices externalservices.ServiceAPI
+
+	// keyring encrypts the secrets directory while the task isn't running,
+	// or is nil if data encryption is disabled
+	keyring *keyring.Keyring
+
 	// vaultClient is the client to use to derive and renew Vault tokens
 	vaultClient vaultclient.VaultClient
 
@@ -179,6 +196,10 @@ type TaskRunner struct {
 	resourceUsage     *cstructs.TaskResourceUsage
 	resourceUsageLock sync.Mutex
 
+	// resourceUsageHistory holds the resource utilization collected during
+	// the last minutes. It is guarded by resourceUsageLock.
+	resourceUsageHistory *statsHistory
+
 	// deviceStatsReporter is used to lookup resource usage for alloc devices
 	deviceStatsReporter cinterfaces.DeviceStatsReporter
 
@@ -190,13 +211,16 @@ type TaskRunner struct {
 	// handlers
 	driverManager drivermanager.Manager
 
+	// rpcClient is used to make RPC calls to the servers
+	rpcClient cinterfaces.RPCer
+
 	// runLaunched marks whether the Run goroutine has been started. It should
 	// be accessed via helpers
 	runLaunched     bool
 	runLaunchedLock sync.Mutex
 
 	// maxEvents is the capacity of the TaskEvents on the TaskState.
-	// Defaults to defaultMaxEvents but overrideable for testing.
+	// Defaults to the client's MaxTaskEvents but overrideable for testing.
 	maxEvents int
 }
 
@@ -227,6 +251,21 @@ type Config struct {
 	// DriverManager is used to dispense driver plugins and register event
 	// handlers
 	DriverManager drivermanager.Manager
+
+	// RPCClient is used to make RPC calls to the servers
+	RPCClient cinterfaces.RPCer
+
+	// NomadServices is used to register services with the Nomad service
+	// provider
+	NomadServices nomadservices.ServiceAPI
+
+	// ExternalServices is used to register services with external service
+	// providers
+	ExternalServices externalservices.ServiceAPI
+
+	// Keyring encrypts the secrets directory of the task while it isn't
+	// running. It is nil if data encryption is disabled.
+	Keyring *keyring.Keyring
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -274,8 +313,16 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		waitCh:              make(chan struct{}),
 		devicemanager:       config.DeviceManager,
 		driverManager:       config.DriverManager,
-		maxEvents:           defaultMaxEvents,
+		rpcClient:           config.RPCClient,
+		nomadServices:       config.NomadServices,
+		externalServices:    config.ExternalServices,
+		keyring:             config.Keyring,
+		maxEvents:           config.ClientConfig.MaxTaskEvents,
 	}
+	if tr.maxEvents <= 0 {
+		tr.maxEvents = defaultMaxEvents
+	}
+	tr.resourceUsageHistory = newStatsHistory(tr.clientConfig.StatsCollectionInterval)
 
 	// Create the logger based on the allocation ID
 	tr.logger = config.Logger.Named("task_runner").With("task", config.Task.Name)
@@ -373,6 +420,20 @@ func (tr *TaskRunner) initLabels() {
 			})
 		}
 	}
+
+	// Attach the meta values the task asked to label its metrics with. Meta
+	// keys without a value are skipped.
+	if len(tr.task.MetricLabels) != 0 {
+		meta := alloc.Job.CombinedTaskMeta(alloc.TaskGroup, tr.taskName)
+		for _, label := range tr.task.MetricLabels {
+			if value, ok := meta[label]; ok {
+				tr.baseLabels = append(tr.baseLabels, metrics.Label{
+					Name:  label,
+					Value: value,
+				})
+			}
+		}
+	}
 }
 
 // Run the TaskRunner. Starts the user's task or reattaches to a restored task.
@@ -820,6 +881,27 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 	taskResources := tr.taskResources
 	env := tr.envBuilder.Build()
 
+	// Run the task as the dynamic user of the allocation if it has one
+	user := task.User
+	if owner := tr.taskDir.Owner; owner != nil {
+		user = fmt.Sprintf("%d:%d", owner.UID, owner.GID)
+	}
+
+	// Share the IPC namespace and /dev/shm of the allocation if the group
+	// asks for it
+	var sharedMemoryMB int64
+	if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil && tg.SharedMemory != nil {
+		sharedMemoryMB = int64(tg.SharedMemory.SizeMB)
+	}
+
+	// Give the tasks using the real-time scheduling policies the real-time
+	// runtime of the client in each second
+	var rtRuntime, rtPeriod int64
+	if taskResources.Cpu.Realtime && tr.clientConfig.RealtimeRuntime > 0 {
+		rtRuntime = int64(tr.clientConfig.RealtimeRuntime / time.Microsecond)
+		rtPeriod = int64(time.Second / time.Microsecond)
+	}
+
 	return &drivers.TaskConfig{
 		ID:            fmt.Sprintf("%s/%s/%s", alloc.ID, task.Name, invocationid),
 		Name:          task.Name,
@@ -828,21 +910,37 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 		Resources: &drivers.Resources{
 			NomadResources: taskResources,
 			LinuxResources: &drivers.LinuxResources{
-				MemoryLimitBytes: taskResources.Memory.MemoryMB * 1024 * 1024,
-				CPUShares:        taskResources.Cpu.CpuShares,
-				PercentTicks:     float64(taskResources.Cpu.CpuShares) / float64(tr.clientConfig.Node.NodeResources.Cpu.CpuShares),
+				MemoryLimitBytes:   taskResources.Memory.MemoryMB * 1024 * 1024,
+				CPUShares:          taskResources.Cpu.CpuShares,
+				PercentTicks:       float64(taskResources.Cpu.CpuShares) / float64(tr.clientConfig.Node.NodeResources.Cpu.CpuShares),
+				CpusetCPUs:         cpusetCPUs(taskResources.Cpu.ReservedCores),
+				HugePages2MB:       taskResources.Memory.HugePages2MB,
+				HugePages1GB:       taskResources.Memory.HugePages1GB,
+				CPURealtimeRuntime: rtRuntime,
+				CPURealtimePeriod:  rtPeriod,
 			},
 		},
-		Devices:    tr.hookResources.getDevices(),
-		Mounts:     tr.hookResources.getMounts(),
-		Env:        env.Map(),
-		DeviceEnv:  env.DeviceEnv(),
-		User:       task.User,
-		AllocDir:   tr.taskDir.AllocDir,
-		StdoutPath: tr.logmonHookConfig.stdoutFifo,
-		StderrPath: tr.logmonHookConfig.stderrFifo,
-		AllocID:    tr.allocID,
+		Devices:        tr.hookResources.getDevices(),
+		Mounts:         tr.hookResources.getMounts(),
+		Env:            env.Map(),
+		DeviceEnv:      env.DeviceEnv(),
+		User:           user,
+		AllocDir:       tr.taskDir.AllocDir,
+		StdoutPath:     tr.logmonHookConfig.stdoutFifo,
+		StderrPath:     tr.logmonHookConfig.stderrFifo,
+		AllocID:        tr.allocID,
+		SharedMemoryMB: sharedMemoryMB,
+	}
+}
+
+// cpusetCPUs returns the cpuset list of the reserved cores, or an empty string
+// if the task didn't reserve any.
+func cpusetCPUs(cores []uint16) string {
+	ids := make([]string, len(cores))
+	for i, c := range cores {
+		ids[i] = strconv.Itoa(int(c))
 	}
+	return strings.Join(ids, ",")
 }
 
 // Restore task runner state. Called by AllocRunner.Restore after NewTaskRunner
@@ -1129,10 +1227,22 @@ func (tr *TaskRunner) LatestResourceUsage() *cstructs.TaskResourceUsage {
 	return ru
 }
 
+// ResourceUsageHistory returns the resource utilization datapoints collected
+// between the start and end UnixNano timestamps, oldest first. Only the
+// datapoints of the last minutes are kept.
+func (tr *TaskRunner) ResourceUsageHistory(start, end int64) []*cstructs.TaskResourceUsage {
+	tr.resourceUsageLock.Lock()
+	defer tr.resourceUsageLock.Unlock()
+	return tr.resourceUsageHistory.between(start, end, time.Now())
+}
+
 // UpdateStats updates and emits the latest stats from the driver.
 func (tr *TaskRunner) UpdateStats(ru *cstructs.TaskResourceUsage) {
 	tr.resourceUsageLock.Lock()
 	tr.resourceUsage = ru
+	if ru != nil && ru.ResourceUsage != nil {
+		tr.resourceUsageHistory.add(ru)
+	}
 	tr.resourceUsageLock.Unlock()
 	if ru != nil {
 		tr.emitStats(ru)
@@ -1196,6 +1306,31 @@ func (tr *TaskRunner) setGaugeForCPU(ru *cstructs.TaskResourceUsage) {
 	}
 }
 
+func (tr *TaskRunner) setGaugeForPressure(ru *cstructs.TaskResourceUsage) {
+	if tr.clientConfig.DisableTaggedMetrics {
+		return
+	}
+
+	for resource, p := range ru.ResourceUsage.PressureStats.Resources() {
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "some_avg10"},
+			float32(p.SomeAvg10), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "some_avg60"},
+			float32(p.SomeAvg60), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "some_avg300"},
+			float32(p.SomeAvg300), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "some_total"},
+			float32(p.SomeTotal), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "full_avg10"},
+			float32(p.FullAvg10), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "full_avg60"},
+			float32(p.FullAvg60), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "full_avg300"},
+			float32(p.FullAvg300), tr.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "allocs", "pressure", resource, "full_total"},
+			float32(p.FullTotal), tr.baseLabels)
+	}
+}
+
 // emitStats emits resource usage stats of tasks to remote metrics collector
 // sinks
 func (tr *TaskRunner) emitStats(ru *cstructs.TaskResourceUsage) {
@@ -1210,9 +1345,16 @@ func (tr *TaskRunner) emitStats(ru *cstructs.TaskResourceUsage) {
 	if ru.ResourceUsage.CpuStats != nil {
 		tr.setGaugeForCPU(ru)
 	}
+
+	if ru.ResourceUsage.PressureStats != nil {
+		tr.setGaugeForPressure(ru)
+	}
 }
 
-// appendTaskEvent updates the task status by appending the new event.
+// appendTaskEvent updates the task status by appending the new event. Once the
+// capacity is reached, the first quarter of the events, describing how the task
+// was set up and started, is kept and the oldest of the later events is
+// dropped, so the start of long lived tasks isn't lost.
 func appendTaskEvent(state *structs.TaskState, event *structs.TaskEvent, capacity int) {
 	if state.Events == nil {
 		state.Events = make([]*structs.TaskEvent, 1, capacity)
@@ -1220,11 +1362,15 @@ func appendTaskEvent(state *structs.TaskState, event *structs.TaskEvent, capacit
 		return
 	}
 
-	// If we hit capacity, then shift it.
-	if len(state.Events) == capacity {
+	// If we hit capacity, then shift the events after the kept ones. The
+	// events may exceed the capacity if it was lowered since they were
+	// restored.
+	if len(state.Events) >= capacity {
+		kept := capacity / 4
 		old := state.Events
 		state.Events = make([]*structs.TaskEvent, 0, capacity)
-		state.Events = append(state.Events, old[1:]...)
+		state.Events = append(state.Events, old[:kept]...)
+		state.Events = append(state.Events, old[len(old)-capacity+kept+1:]...)
 	}
 
 	state.Events = append(state.Events, event)
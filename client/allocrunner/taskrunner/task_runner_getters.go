@@ -57,7 +57,7 @@ func (tr *TaskRunner) setVaultToken(token string) {
 	tr.vaultToken = token
 
 	// Update the task's environment
-	tr.envBuilder.SetVaultToken(token, tr.task.Vault.Env)
+	tr.envBuilder.SetVaultToken(token, tr.task.Vault.Namespace, tr.task.Vault.Env)
 }
 
 // getDriverHandle returns a driver handle.
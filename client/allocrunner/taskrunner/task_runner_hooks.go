@@ -8,6 +8,7 @@ import (
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers"
@@ -16,7 +17,12 @@ import (
 // hookResources captures the resources for the task provided by hooks.
 type hookResources struct {
 	Devices []*drivers.DeviceConfig
-	Mounts  []*drivers.MountConfig
+
+	// Mounts are stored by hook name, in the order the hooks are run, so
+	// that hooks can provide mounts without replacing those of other hooks
+	Mounts     map[string][]*drivers.MountConfig
+	mountHooks []string
+
 	sync.RWMutex
 }
 
@@ -32,16 +38,28 @@ func (h *hookResources) getDevices() []*drivers.DeviceConfig {
 	return h.Devices
 }
 
-func (h *hookResources) setMounts(m []*drivers.MountConfig) {
+func (h *hookResources) setMounts(hook string, m []*drivers.MountConfig) {
 	h.Lock()
-	h.Mounts = m
-	h.Unlock()
+	defer h.Unlock()
+
+	if h.Mounts == nil {
+		h.Mounts = make(map[string][]*drivers.MountConfig)
+	}
+	if _, ok := h.Mounts[hook]; !ok {
+		h.mountHooks = append(h.mountHooks, hook)
+	}
+	h.Mounts[hook] = m
 }
 
 func (h *hookResources) getMounts() []*drivers.MountConfig {
 	h.RLock()
 	defer h.RUnlock()
-	return h.Mounts
+
+	var mounts []*drivers.MountConfig
+	for _, hook := range h.mountHooks {
+		mounts = append(mounts, h.Mounts[hook]...)
+	}
+	return mounts
 }
 
 // initHooks intializes the tasks hooks.
@@ -59,11 +77,26 @@ func (tr *TaskRunner) initHooks() {
 	tr.runnerHooks = []interfaces.TaskHook{
 		newValidateHook(tr.clientConfig, hookLogger),
 		newTaskDirHook(tr, hookLogger),
+	}
+
+	// If data encryption is enabled, decrypt the secrets directory before
+	// the hooks using it run
+	if tr.keyring != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newSecretsDecryptHook(tr.keyring, hookLogger))
+	}
+
+	tr.runnerHooks = append(tr.runnerHooks,
 		newLogMonHook(tr.logmonHookConfig, hookLogger),
 		newDispatchHook(tr.Alloc(), hookLogger),
-		newArtifactHook(tr, hookLogger),
+		newArtifactHook(tr, getter.NewCache(tr.clientConfig.PrefetchDir()), hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
+		newDNSHook(hookLogger),
+	)
+
+	// If the task collects the event log, add the hook
+	if task.LogConfig != nil && len(task.LogConfig.EventLogProviders) != 0 {
+		tr.runnerHooks = append(tr.runnerHooks, newEventLogHook(task, tr.taskDir.LogDir, hookLogger))
 	}
 
 	// If Vault is enabled, add the hook
@@ -80,28 +113,47 @@ func (tr *TaskRunner) initHooks() {
 		}))
 	}
 
-	// If there are templates is enabled, add the hook
-	if len(task.Templates) != 0 {
+	// If the task is exposed its workload identity, add the hook
+	if task.Identity != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newIdentityHook(
+			tr.Alloc(), tr.taskName, tr.clientConfig, tr.rpcClient, hookLogger))
+	}
+
+	// If there are templates or secrets, add the hook
+	if len(task.Templates) != 0 || len(task.Secrets) != 0 {
 		tr.runnerHooks = append(tr.runnerHooks, newTemplateHook(&templateHookConfig{
 			logger:       hookLogger,
 			lifecycle:    tr,
 			events:       tr,
 			templates:    task.Templates,
+			secrets:      task.Secrets,
 			clientConfig: tr.clientConfig,
 			envBuilder:   tr.envBuilder,
+			namespace:    tr.alloc.Namespace,
+			jobID:        tr.alloc.JobID,
+			rpcClient:    tr.rpcClient,
 		}))
 	}
 
 	// If there are any services, add the hook
 	if len(task.Services) != 0 {
 		tr.runnerHooks = append(tr.runnerHooks, newServiceHook(serviceHookConfig{
-			alloc:     tr.Alloc(),
-			task:      tr.Task(),
-			consul:    tr.consulClient,
-			restarter: tr,
-			logger:    hookLogger,
+			alloc:            tr.Alloc(),
+			task:             tr.Task(),
+			consul:           tr.consulClient,
+			nomadServices:    tr.nomadServices,
+			externalServices: tr.externalServices,
+			restarter:        tr,
+			logger:           hookLogger,
 		}))
 	}
+
+	// If data encryption is enabled, encrypt the secrets directory once the
+	// task exits. This must be the last hook so that no secrets are written
+	// after it.
+	if tr.keyring != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newSecretsEncryptHook(tr.keyring, tr.taskDir.SecretsDir, hookLogger))
+	}
 }
 
 func (tr *TaskRunner) emitHookError(err error, hookName string) {
@@ -218,7 +270,7 @@ func (tr *TaskRunner) prestart() error {
 			tr.hookResources.setDevices(resp.Devices)
 		}
 		if len(resp.Mounts) != 0 {
-			tr.hookResources.setMounts(resp.Mounts)
+			tr.hookResources.setMounts(name, resp.Mounts)
 		}
 
 		if tr.logger.IsTrace() {
@@ -448,6 +500,12 @@ func (tr *TaskRunner) preKill() {
 		}()
 	}
 
+	// The shutdown delay the allocation was stopped with, if any
+	var shutdownDelay time.Duration
+	if d := tr.Alloc().DesiredTransition.ShutdownDelay; d != nil {
+		shutdownDelay = *d
+	}
+
 	for _, hook := range tr.runnerHooks {
 		killHook, ok := hook.(interfaces.TaskPreKillHook)
 		if !ok {
@@ -464,7 +522,9 @@ func (tr *TaskRunner) preKill() {
 		}
 
 		// Run the pre kill hook
-		req := interfaces.TaskPreKillRequest{}
+		req := interfaces.TaskPreKillRequest{
+			ShutdownDelay: shutdownDelay,
+		}
 		var resp interfaces.TaskPreKillResponse
 		if err := killHook.PreKilling(context.Background(), &req, &resp); err != nil {
 			tr.emitHookError(err, name)
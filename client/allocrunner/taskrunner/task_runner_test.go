@@ -703,6 +703,33 @@ func TestTaskRunner_Dispatch_Payload(t *testing.T) {
 	require.Equal(t, expected, data)
 }
 
+// TestTaskRunner_MetricLabels asserts the meta values listed in a task's
+// metric labels are attached to its metrics.
+func TestTaskRunner_MetricLabels(t *testing.T) {
+	t.Parallel()
+
+	alloc := mock.Alloc()
+	alloc.Job.Meta = map[string]string{"team": "storage"}
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Meta = map[string]string{"tier": "gold"}
+	task.MetricLabels = []string{"team", "tier", "missing"}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+
+	tr, err := NewTaskRunner(conf)
+	require.NoError(t, err)
+
+	labels := make(map[string]string, len(tr.baseLabels))
+	for _, l := range tr.baseLabels {
+		labels[l.Name] = l.Value
+	}
+	require.Equal(t, "storage", labels["team"])
+	require.Equal(t, "gold", labels["tier"])
+	require.NotContains(t, labels, "missing")
+	require.Equal(t, task.Name, labels["task"])
+}
+
 // TestTaskRunner_SignalFailure asserts that signal errors are properly
 // propagated from the driver to TaskRunner.
 func TestTaskRunner_SignalFailure(t *testing.T) {
@@ -828,15 +855,15 @@ func TestTaskRunner_CheckWatcher_Restart(t *testing.T) {
 		"Received",
 		"Task Setup",
 		"Started",
-		"Restart Signaled",
+		"Unhealthy Check",
 		"Terminated",
 		"Restarting",
 		"Started",
-		"Restart Signaled",
+		"Unhealthy Check",
 		"Terminated",
 		"Restarting",
 		"Started",
-		"Restart Signaled",
+		"Unhealthy Check",
 		"Terminated",
 		"Not Restarting",
 	}
@@ -1720,3 +1747,28 @@ func testWaitForTaskToStart(t *testing.T, tr *TaskRunner) {
 		require.NoError(t, err)
 	})
 }
+
+func TestTaskRunner_AppendTaskEvent(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := structs.NewTaskState()
+	for i := 0; i < 20; i++ {
+		appendTaskEvent(state, &structs.TaskEvent{Time: int64(i)}, 8)
+	}
+
+	// The first quarter of the events is kept along with the latest ones
+	times := make([]int64, len(state.Events))
+	for i, event := range state.Events {
+		times[i] = event.Time
+	}
+	require.Equal([]int64{0, 1, 14, 15, 16, 17, 18, 19}, times)
+
+	// Events restored above a lowered capacity are trimmed
+	appendTaskEvent(state, &structs.TaskEvent{Time: 20}, 4)
+	times = times[:0]
+	for _, event := range state.Events {
+		times = append(times, event.Time)
+	}
+	require.Equal([]int64{0, 18, 19, 20}, times)
+}
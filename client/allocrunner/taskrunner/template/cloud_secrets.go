@@ -0,0 +1,174 @@
+package template
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/hashicorp/nomad/helper/awsjson"
+	"github.com/hashicorp/nomad/helper/cloudjson"
+)
+
+const (
+	// cloudSecretsOption is the Client option that determines whether
+	// templates may read secrets from the cloud secret stores using the
+	// node's instance identity
+	cloudSecretsOption = "template.allow_cloud_secrets"
+
+	// defaultGCPSecretManagerURL is the address of the GCP Secret Manager API
+	defaultGCPSecretManagerURL = "https://secretmanager.googleapis.com/v1"
+)
+
+// cloudSecrets implements the template functions reading secrets from AWS
+// Secrets Manager, AWS Systems Manager Parameter Store and GCP Secret
+// Manager. Requests are authenticated with the identity of the instance the
+// client runs on: the default AWS credential chain, including the EC2
+// instance role, and the default service account of the GCE instance.
+type cloudSecrets struct {
+	// enabled is whether the client allows templates to read cloud secrets
+	enabled bool
+
+	// awsConfig is the base configuration of the AWS clients
+	awsConfig *aws.Config
+
+	// gcpMetadataURL and gcpSecretManagerURL are the addresses of the GCE
+	// metadata server and of the Secret Manager API
+	gcpMetadataURL      string
+	gcpSecretManagerURL string
+
+	httpClient *http.Client
+
+	// gcpToken caches the access token of the instance's service account
+	gcpToken cloudjson.GCPTokenCache
+}
+
+func newCloudSecrets(enabled bool) *cloudSecrets {
+	return &cloudSecrets{
+		enabled:             enabled,
+		awsConfig:           aws.NewConfig(),
+		gcpMetadataURL:      cloudjson.DefaultGCPMetadataURL,
+		gcpSecretManagerURL: defaultGCPSecretManagerURL,
+		httpClient:          &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// funcs returns the template functions.
+func (c *cloudSecrets) funcs() map[string]interface{} {
+	return map[string]interface{}{
+		"awsSecret":    c.checkEnabled("awsSecret", c.awsSecret),
+		"awsParameter": c.checkEnabled("awsParameter", c.awsParameter),
+		"gcpSecret":    c.checkEnabled("gcpSecret", c.gcpSecret),
+	}
+}
+
+// checkEnabled wraps a template function so it fails unless the client
+// allows templates to read cloud secrets.
+func (c *cloudSecrets) checkEnabled(name string, fn func(string) (string, error)) func(string) (string, error) {
+	return func(id string) (string, error) {
+		if !c.enabled {
+			return "", fmt.Errorf("%s is disabled by the client configuration; set the %q option to enable it", name, cloudSecretsOption)
+		}
+		return fn(id)
+	}
+}
+
+// awsSecret returns the value of the current version of a secret in AWS
+// Secrets Manager, given its name or ARN. A secret given by ARN is read from
+// the region in the ARN.
+func (c *cloudSecrets) awsSecret(id string) (string, error) {
+	region := ""
+	if parts := strings.SplitN(id, ":", 5); len(parts) == 5 && parts[0] == "arn" {
+		region = parts[3]
+	}
+
+	var out struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary []byte `json:"SecretBinary"`
+	}
+	in := &struct {
+		SecretId string `json:"SecretId"`
+	}{id}
+	if err := c.awsCall("secretsmanager", region, "secretsmanager.GetSecretValue", in, &out); err != nil {
+		return "", fmt.Errorf("failed to read AWS secret %q: %v", id, err)
+	}
+
+	if out.SecretString != "" {
+		return out.SecretString, nil
+	}
+	return string(out.SecretBinary), nil
+}
+
+// awsParameter returns the decrypted value of a parameter in AWS Systems
+// Manager Parameter Store.
+func (c *cloudSecrets) awsParameter(name string) (string, error) {
+	var out struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	in := &struct {
+		Name           string `json:"Name"`
+		WithDecryption bool   `json:"WithDecryption"`
+	}{name, true}
+	if err := c.awsCall("ssm", "", "AmazonSSM.GetParameter", in, &out); err != nil {
+		return "", fmt.Errorf("failed to read AWS parameter %q: %v", name, err)
+	}
+	return out.Parameter.Value, nil
+}
+
+// awsCall calls an operation of an AWS API. If region is empty, the
+// configured region or the region of the EC2 instance is used.
+func (c *cloudSecrets) awsCall(service, region, target string, in, out interface{}) error {
+	cfg := c.awsConfig.Copy()
+	if region != "" {
+		cfg.Region = aws.String(region)
+	}
+	return awsjson.Call(cfg, service, target, in, out)
+}
+
+// gcpSecret returns the value of a secret version in GCP Secret Manager. The
+// name may be the full resource name of a version or of a secret, whose
+// latest version is read, or the ID of a secret in the project of the
+// instance.
+func (c *cloudSecrets) gcpSecret(name string) (string, error) {
+	token, err := c.gcpToken.Token(c.httpClient, c.gcpMetadataURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to read GCP secret %q: %v", name, err)
+	}
+
+	resource := name
+	if !strings.HasPrefix(resource, "projects/") {
+		project, err := cloudjson.GCPMetadata(c.httpClient, c.gcpMetadataURL, "project/project-id")
+		if err != nil {
+			return "", fmt.Errorf("failed to read GCP secret %q: failed to determine the project: %v", name, err)
+		}
+		resource = "projects/" + project + "/secrets/" + resource
+	}
+	if !strings.Contains(resource, "/versions/") {
+		resource += "/versions/latest"
+	}
+
+	req, err := http.NewRequest("GET", c.gcpSecretManagerURL+"/"+resource+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := cloudjson.Do(c.httpClient, req, &out); err != nil {
+		return "", fmt.Errorf("failed to read GCP secret %q: %v", name, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode GCP secret %q: %v", name, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,132 @@
+package template
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudSecrets_Disabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	secrets := newCloudSecrets(false)
+	fn := secrets.funcs()["awsSecret"].(func(string) (string, error))
+	_, err := fn("db")
+	require.Error(err)
+	require.Contains(err.Error(), cloudSecretsOption)
+}
+
+func TestCloudSecrets_AWS(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var in map[string]interface{}
+		json.NewDecoder(req.Body).Decode(&in)
+
+		switch req.Header.Get("X-Amz-Target") {
+		case "secretsmanager.GetSecretValue":
+			if in["SecretId"] != "db" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+				return
+			}
+			fmt.Fprint(w, `{"Name":"db","SecretString":"hunter2"}`)
+		case "AmazonSSM.GetParameter":
+			require.Equal(true, in["WithDecryption"])
+			fmt.Fprintf(w, `{"Parameter":{"Name":%q,"Value":"value"}}`, in["Name"])
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	secrets := newCloudSecrets(true)
+	secrets.awsConfig = aws.NewConfig().
+		WithRegion("us-east-1").
+		WithEndpoint(srv.URL).
+		WithCredentials(credentials.NewStaticCredentials("AKIAEXAMPLE", "secret", ""))
+
+	value, err := secrets.awsSecret("db")
+	require.NoError(err)
+	require.Equal("hunter2", value)
+
+	_, err = secrets.awsSecret("missing")
+	require.Error(err)
+	require.Contains(err.Error(), "ResourceNotFoundException")
+
+	value, err = secrets.awsParameter("/app/db")
+	require.NoError(err)
+	require.Equal("value", value)
+}
+
+func TestCloudSecrets_GCP(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var lock sync.Mutex
+	tokens := 0
+	metadataSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch req.URL.Path {
+		case "/instance/service-accounts/default/token":
+			lock.Lock()
+			tokens++
+			lock.Unlock()
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`)
+		case "/project/project-id":
+			fmt.Fprint(w, "my-project")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer metadataSrv.Close()
+
+	var paths []string
+	secretSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		lock.Lock()
+		paths = append(paths, req.URL.Path)
+		lock.Unlock()
+		data := base64.StdEncoding.EncodeToString([]byte("hunter2"))
+		fmt.Fprintf(w, `{"name":%q,"payload":{"data":%q}}`, req.URL.Path, data)
+	}))
+	defer secretSrv.Close()
+
+	secrets := newCloudSecrets(true)
+	secrets.gcpMetadataURL = metadataSrv.URL
+	secrets.gcpSecretManagerURL = secretSrv.URL
+
+	value, err := secrets.gcpSecret("db")
+	require.NoError(err)
+	require.Equal("hunter2", value)
+
+	value, err = secrets.gcpSecret("projects/other/secrets/db/versions/3")
+	require.NoError(err)
+	require.Equal("hunter2", value)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal([]string{
+		"/projects/my-project/secrets/db/versions/latest:access",
+		"/projects/other/secrets/db/versions/3:access",
+	}, paths)
+
+	// The access token is reused
+	require.Equal(1, tokens)
+}
@@ -0,0 +1,424 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// templateFuncRefreshInterval is the interval at which the calls to the
+	// template functions provided by Nomad are made again
+	templateFuncRefreshInterval = 1 * time.Minute
+)
+
+// templateFuncNames is the set of functions a template may call besides the
+// ones provided by Nomad: the built-in functions of text/template and the
+// functions of the vendored consul-template. It is only used to parse the
+// templates calling Nomad's functions.
+var templateFuncNames = func() map[string]interface{} {
+	names := []string{
+		// text/template
+		"and", "call", "html", "index", "js", "len", "not", "or", "print",
+		"printf", "println", "slice", "urlquery", "eq", "ge", "gt", "le",
+		"lt", "ne",
+
+		// consul-template
+		"datacenters", "file", "key", "keyExists", "keyOrDefault", "ls",
+		"node", "nodes", "secret", "secrets", "service", "services", "tree",
+		"scratch", "byKey", "byTag", "contains", "containsAll", "containsAny",
+		"containsNone", "containsNotAll", "env", "executeTemplate", "explode",
+		"in", "indent", "loop", "join", "trimSpace", "parseBool",
+		"parseFloat", "parseInt", "parseJSON", "parseUint", "plugin",
+		"regexReplaceAll", "regexMatch", "replaceAll", "timestamp",
+		"toLower", "toJSON", "toJSONPretty", "toTitle", "toTOML", "toUpper",
+		"toYAML", "split", "add", "subtract", "multiply", "divide", "modulo",
+	}
+	m := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		m[name] = name
+	}
+	return m
+}()
+
+// templateFunc is a template function provided by Nomad. It takes a single
+// string argument.
+type templateFunc struct {
+	// call returns the result of calling the function with the argument
+	call func(string) (interface{}, error)
+
+	// json is whether the result is encoded as JSON, rather than being a
+	// string returned as is
+	json bool
+}
+
+// templateCall is a call to a template function provided by Nomad
+type templateCall struct {
+	fn  string
+	arg string
+}
+
+// templateCallResult is the last result of a call, written to the file the
+// template reads in place of the call
+type templateCallResult struct {
+	path    string
+	content []byte
+	err     error
+}
+
+// templateFuncs provides Nomad's template functions to the templates.
+// consul-template can't be given additional functions, so the templates are
+// rewritten to read the results of the calls to Nomad's functions from files
+// with consul-template's file function. The calls are made again periodically
+// and the files are only written when the results change, so consul-template
+// re-renders the templates when they do.
+type templateFuncs struct {
+	funcs map[string]*templateFunc
+
+	// dir is the directory of the files holding the results of the calls. It
+	// is created when the first call is found.
+	dir string
+
+	// calls are the calls found in the templates
+	calls map[templateCall]*templateCallResult
+
+	// stopped is whether the functions have been stopped and the directory
+	// removed
+	stopped bool
+	lock    sync.Mutex
+}
+
+// newTemplateFuncs returns the template functions provided by Nomad to the
+// task's templates.
+func newTemplateFuncs(config *TaskTemplateManagerConfig) *templateFuncs {
+	nomadVar := nomadVarFunc(config)
+	nomadService := nomadServiceFunc(config)
+	funcs := map[string]*templateFunc{
+		"nomadVar": {
+			call: func(path string) (interface{}, error) { return nomadVar(path) },
+			json: true,
+		},
+		"nomadService": {
+			call: func(name string) (interface{}, error) { return nomadService(name) },
+			json: true,
+		},
+	}
+
+	secrets := newCloudSecrets(config.ClientConfig.ReadBoolDefault(cloudSecretsOption, false))
+	for name, fn := range secrets.funcs() {
+		fn := fn.(func(string) (string, error))
+		funcs[name] = &templateFunc{
+			call: func(id string) (interface{}, error) { return fn(id) },
+		}
+	}
+
+	return &templateFuncs{
+		funcs: funcs,
+		calls: make(map[templateCall]*templateCallResult),
+	}
+}
+
+// rewrite returns the template with the calls to Nomad's functions replaced
+// by reads of the files holding their results. The argument of each call must
+// be a string literal. Templates that don't call Nomad's functions are
+// returned unchanged.
+func (f *templateFuncs) rewrite(name, contents, leftDelim, rightDelim string) (string, error) {
+	called := false
+	for fn := range f.funcs {
+		if strings.Contains(contents, fn) {
+			called = true
+			break
+		}
+	}
+	if !called {
+		return contents, nil
+	}
+
+	// Parse the template to find the calls, including in the templates it
+	// defines
+	funcNames := make(map[string]interface{}, len(f.funcs))
+	for fn := range f.funcs {
+		funcNames[fn] = fn
+	}
+	trees, err := parse.Parse(name, contents, leftDelim, rightDelim, templateFuncNames, funcNames)
+	if err != nil {
+		return "", err
+	}
+
+	// Walk the trees in a stable order, so the calls keep their files when
+	// the template is rewritten again
+	treeNames := make([]string, 0, len(trees))
+	for treeName := range trees {
+		treeNames = append(treeNames, treeName)
+	}
+	sort.Strings(treeNames)
+
+	var replacements []*templateCallReplacement
+	for _, treeName := range treeNames {
+		tree := trees[treeName]
+		if tree.Root == nil {
+			continue
+		}
+		if err := f.findCalls(tree.Root, &replacements); err != nil {
+			return "", fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	if len(replacements) == 0 {
+		return contents, nil
+	}
+
+	// Replace the calls starting from the end, so the positions of the
+	// preceding ones still apply
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start > replacements[j].start
+	})
+	rewritten := contents
+	for _, r := range replacements {
+		rewritten = rewritten[:r.start] + r.text + rewritten[r.end:]
+	}
+	return rewritten, nil
+}
+
+// templateCallReplacement replaces the text of a call in a template
+type templateCallReplacement struct {
+	start, end int
+	text       string
+}
+
+// findCalls walks the parsed template and adds the replacements of the calls
+// to Nomad's functions it finds.
+func (f *templateFuncs) findCalls(node parse.Node, replacements *[]*templateCallReplacement) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, node := range n.Nodes {
+			if err := f.findCalls(node, replacements); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return f.findCalls(n.Pipe, replacements)
+	case *parse.IfNode:
+		return f.findBranchCalls(&n.BranchNode, replacements)
+	case *parse.RangeNode:
+		return f.findBranchCalls(&n.BranchNode, replacements)
+	case *parse.WithNode:
+		return f.findBranchCalls(&n.BranchNode, replacements)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			return f.findCalls(n.Pipe, replacements)
+		}
+	case *parse.ChainNode:
+		return f.findCalls(n.Node, replacements)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for i, cmd := range n.Cmds {
+			// A call receiving the result of the previous command would
+			// have an argument that isn't a literal
+			if i > 0 && len(cmd.Args) > 0 {
+				if ident, ok := cmd.Args[0].(*parse.IdentifierNode); ok && f.funcs[ident.Ident] != nil {
+					return fmt.Errorf("the result of a command can't be piped to %s", ident.Ident)
+				}
+			}
+			if err := f.findCalls(cmd, replacements); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for i, arg := range n.Args {
+			ident, ok := arg.(*parse.IdentifierNode)
+			if !ok || f.funcs[ident.Ident] == nil {
+				if err := f.findCalls(arg, replacements); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var str *parse.StringNode
+			if i == 0 && len(n.Args) == 2 {
+				str, _ = n.Args[1].(*parse.StringNode)
+			}
+			if str == nil {
+				return fmt.Errorf("%s must be called with a single string literal argument", ident.Ident)
+			}
+
+			text, err := f.callText(templateCall{fn: ident.Ident, arg: str.Text})
+			if err != nil {
+				return err
+			}
+			*replacements = append(*replacements, &templateCallReplacement{
+				start: int(ident.Position()),
+				end:   int(str.Position()) + len(str.Quoted),
+				text:  text,
+			})
+			break
+		}
+	}
+	return nil
+}
+
+func (f *templateFuncs) findBranchCalls(n *parse.BranchNode, replacements *[]*templateCallReplacement) error {
+	if err := f.findCalls(n.Pipe, replacements); err != nil {
+		return err
+	}
+	if err := f.findCalls(n.List, replacements); err != nil {
+		return err
+	}
+	if n.ElseList != nil {
+		return f.findCalls(n.ElseList, replacements)
+	}
+	return nil
+}
+
+// callText registers the call and returns the text replacing it in the
+// template, which reads the file holding its result.
+func (f *templateFuncs) callText(call templateCall) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.dir == "" {
+		dir, err := ioutil.TempDir("", "nomad-template")
+		if err != nil {
+			return "", fmt.Errorf("failed to create the directory of the template function results: %v", err)
+		}
+		f.dir = dir
+	}
+
+	result, ok := f.calls[call]
+	if !ok {
+		result = &templateCallResult{
+			path: filepath.Join(f.dir, strconv.Itoa(len(f.calls))),
+		}
+		f.calls[call] = result
+	}
+
+	text := "file " + strconv.Quote(result.path)
+	if f.funcs[call.fn].json {
+		text += " | parseJSON"
+	}
+	return text, nil
+}
+
+// refresh makes the calls and writes the results that changed. The previous
+// result of a failed call is kept. The errors of the calls that failed are
+// returned, including those that failed the last time already if all is set.
+func (f *templateFuncs) refresh(all bool) error {
+	f.lock.Lock()
+	calls := make(map[templateCall]*templateCallResult, len(f.calls))
+	for call, result := range f.calls {
+		calls[call] = result
+	}
+	f.lock.Unlock()
+
+	var mErr multierror.Error
+	for call, result := range calls {
+		content, err := f.call(call)
+		if err == nil {
+			err = f.write(result, content)
+		}
+
+		f.lock.Lock()
+		lastErr := result.err
+		result.err = err
+		f.lock.Unlock()
+
+		if err != nil && (all || lastErr == nil) {
+			multierror.Append(&mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// call makes the call and returns the content of the file holding its result
+func (f *templateFuncs) call(call templateCall) ([]byte, error) {
+	fn := f.funcs[call.fn]
+	value, err := fn.call(call.arg)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q failed: %v", call.fn, call.arg, err)
+	}
+
+	if !fn.json {
+		return []byte(value.(string)), nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the result of %s %q: %v", call.fn, call.arg, err)
+	}
+	return content, nil
+}
+
+// write replaces the file holding the result of a call if it changed
+func (f *templateFuncs) write(result *templateCallResult, content []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.stopped {
+		return nil
+	}
+	if result.content != nil && bytes.Equal(result.content, content) {
+		return nil
+	}
+
+	// Rename the file in place so it's never read partially written
+	tmp := result.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return fmt.Errorf("failed to write template function result: %v", err)
+	}
+	if err := os.Rename(tmp, result.path); err != nil {
+		return fmt.Errorf("failed to write template function result: %v", err)
+	}
+	result.content = content
+	return nil
+}
+
+// run refreshes the results of the calls until the shutdown channel is
+// closed, passing the errors of the calls that started failing to the error
+// handler.
+func (f *templateFuncs) run(shutdownCh <-chan struct{}, handleErr func(error)) {
+	f.lock.Lock()
+	calls := len(f.calls)
+	f.lock.Unlock()
+	if calls == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(templateFuncRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-shutdownCh:
+			return
+		case <-ticker.C:
+		}
+
+		if err := f.refresh(false); err != nil {
+			handleErr(err)
+		}
+	}
+}
+
+// stop removes the files holding the results of the calls
+func (f *templateFuncs) stop() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.stopped = true
+	if f.dir != "" {
+		os.RemoveAll(f.dir)
+	}
+}
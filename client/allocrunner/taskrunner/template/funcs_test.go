@@ -0,0 +1,147 @@
+package template
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplateFuncs(t *testing.T, rpc *mockVariablesRPC) *templateFuncs {
+	funcs := newTemplateFuncs(&TaskTemplateManagerConfig{
+		ClientConfig: &config.Config{Region: "global", Node: mock.Node()},
+		Namespace:    structs.DefaultNamespace,
+		JobID:        "example",
+		RPCClient:    rpc,
+	})
+	return funcs
+}
+
+func TestTemplateFuncs_Rewrite(t *testing.T) {
+	t.Parallel()
+	funcs := testTemplateFuncs(t, &mockVariablesRPC{})
+	defer funcs.stop()
+
+	// The results of the calls are read from the files in the order the
+	// calls are found
+	file := func(i int, json bool) string {
+		s := "file " + strconv.Quote(filepath.Join(funcs.dir, strconv.Itoa(i)))
+		if json {
+			s += " | parseJSON"
+		}
+		return s
+	}
+
+	cases := []struct {
+		name     string
+		contents string
+		delims   [2]string
+		expected func() string
+		err      string
+	}{
+		{
+			name:     "no calls",
+			contents: `{{ key "foo" }} nomadVar`,
+			expected: func() string { return `{{ key "foo" }} nomadVar` },
+		},
+		{
+			name:     "with",
+			contents: `{{ with nomadVar "nomad/jobs/example/db" }}{{ .password }}{{ end }}`,
+			expected: func() string {
+				return `{{ with ` + file(0, true) + ` }}{{ .password }}{{ end }}`
+			},
+		},
+		{
+			name:     "same call in a pipeline and in parentheses",
+			contents: `{{ nomadVar "nomad/jobs/example/db" | toJSON }}{{ index (nomadVar "nomad/jobs/example/db") "user" }}`,
+			expected: func() string {
+				return `{{ ` + file(0, true) + ` | toJSON }}{{ index (` + file(0, true) + `) "user" }}`
+			},
+		},
+		{
+			name:     "range and define with custom delimiters",
+			contents: `[[ define "up" ]][[ range nomadService "db" ]][[ .Address ]][[ end ]][[ end ]][[ template "up" ]][[ awsSecret ` + "`db`" + ` ]]`,
+			delims:   [2]string{"[[", "]]"},
+			expected: func() string {
+				return `[[ define "up" ]][[ range ` + file(2, true) + ` ]][[ .Address ]][[ end ]][[ end ]][[ template "up" ]][[ ` + file(1, false) + ` ]]`
+			},
+		},
+		{
+			name:     "argument not a literal",
+			contents: `{{ nomadVar (env "VAR_PATH") }}`,
+			err:      "nomadVar must be called with a single string literal argument",
+		},
+		{
+			name:     "piped argument",
+			contents: `{{ "nomad/jobs/example/db" | nomadVar }}`,
+			err:      "the result of a command can't be piped to nomadVar",
+		},
+		{
+			name:     "unknown function",
+			contents: `{{ nomadVar "nomad/jobs/example/db" | unicorn }}`,
+			err:      `function "unicorn" not defined`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := funcs.rewrite("test", c.contents, c.delims[0], c.delims[1])
+			if c.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected(), out)
+		})
+	}
+}
+
+func TestTemplateFuncs_Refresh(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	rpc := &mockVariablesRPC{
+		vars: map[string]map[string]string{
+			"nomad/jobs/example/db": {"password": "hunter2"},
+		},
+	}
+	funcs := testTemplateFuncs(t, rpc)
+	defer funcs.stop()
+	_, err := funcs.rewrite("test", `{{ with nomadVar "nomad/jobs/example/db" }}{{ .password }}{{ end }}`, "", "")
+	require.NoError(err)
+	path := funcs.calls[templateCall{fn: "nomadVar", arg: "nomad/jobs/example/db"}].path
+
+	require.NoError(funcs.refresh(true))
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.JSONEq(`{"password": "hunter2"}`, string(raw))
+
+	// An updated variable is written again
+	rpc.vars["nomad/jobs/example/db"] = map[string]string{"password": "hunter3"}
+	require.NoError(funcs.refresh(false))
+	raw, err = ioutil.ReadFile(path)
+	require.NoError(err)
+	require.JSONEq(`{"password": "hunter3"}`, string(raw))
+
+	// A failed call keeps the previous result and is only reported once
+	delete(rpc.vars, "nomad/jobs/example/db")
+	err = funcs.refresh(false)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+	require.NoError(funcs.refresh(false))
+	require.Error(funcs.refresh(true))
+
+	raw, err = ioutil.ReadFile(path)
+	require.NoError(err)
+	require.JSONEq(`{"password": "hunter3"}`, string(raw))
+
+	// The results are removed once stopped
+	funcs.stop()
+	_, err = ioutil.ReadFile(path)
+	require.Error(err)
+}
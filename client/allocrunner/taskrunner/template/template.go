@@ -3,6 +3,7 @@ package template
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -15,10 +16,12 @@ import (
 	ctconf "github.com/hashicorp/consul-template/config"
 	"github.com/hashicorp/consul-template/manager"
 	"github.com/hashicorp/consul-template/signals"
+	"github.com/hashicorp/consul/api"
 	envparse "github.com/hashicorp/go-envparse"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/config"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -53,10 +56,18 @@ type TaskTemplateManager struct {
 	// runner is the consul-template runner
 	runner *manager.Runner
 
+	// funcs provides Nomad's template functions to the templates
+	funcs *templateFuncs
+
 	// signals is a lookup map from the string representation of a signal to its
 	// actual signal
 	signals map[string]os.Signal
 
+	// driverHandle is used to execute change scripts inside the task. It is
+	// nil until the task has started.
+	driverHandle interfaces.ScriptExecutor
+	handleLock   sync.Mutex
+
 	// shutdownCh is used to signal and started goroutine to shutdown
 	shutdownCh chan struct{}
 
@@ -81,6 +92,10 @@ type TaskTemplateManagerConfig struct {
 	// Templates is the set of templates we are managing
 	Templates []*structs.Template
 
+	// Secrets is the set of secrets rendered to the secrets directory along
+	// with the templates
+	Secrets []*structs.Secret
+
 	// ClientConfig is the Nomad Client configuration
 	ClientConfig *config.Config
 
@@ -93,6 +108,17 @@ type TaskTemplateManagerConfig struct {
 	// EnvBuilder is the environment variable builder for the task.
 	EnvBuilder *taskenv.Builder
 
+	// Namespace is the namespace of the task's allocation, in which the
+	// variables rendered by the templates are read.
+	Namespace string
+
+	// JobID is the ID of the job of the task's allocation. Templates may only
+	// read the variables below the path of the job.
+	JobID string
+
+	// RPCClient is used to read variables from the servers
+	RPCClient cinterfaces.RPCer
+
 	// MaxTemplateEventRate is the maximum rate at which we should emit events.
 	MaxTemplateEventRate time.Duration
 
@@ -129,6 +155,16 @@ func NewTaskTemplateManager(config *TaskTemplateManagerConfig) (*TaskTemplateMan
 		return nil, err
 	}
 
+	// Secrets are rendered by templates of their own
+	if len(config.Secrets) != 0 {
+		templates := make([]*structs.Template, 0, len(config.Templates)+len(config.Secrets))
+		templates = append(templates, config.Templates...)
+		for _, s := range config.Secrets {
+			templates = append(templates, s.Template())
+		}
+		config.Templates = templates
+	}
+
 	tm := &TaskTemplateManager{
 		config:     config,
 		shutdownCh: make(chan struct{}),
@@ -153,8 +189,10 @@ func NewTaskTemplateManager(config *TaskTemplateManagerConfig) (*TaskTemplateMan
 	}
 
 	// Build the consul-template runner
-	runner, lookup, err := templateRunner(config)
+	tm.funcs = newTemplateFuncs(config)
+	runner, lookup, err := templateRunner(config, tm.funcs)
 	if err != nil {
+		tm.funcs.stop()
 		return nil, err
 	}
 	tm.runner = runner
@@ -180,6 +218,17 @@ func (tm *TaskTemplateManager) Stop() {
 	if tm.runner != nil {
 		tm.runner.Stop()
 	}
+
+	// Remove the results of Nomad's template functions
+	tm.funcs.stop()
+}
+
+// SetDriverHandle sets the handle used to execute change scripts inside the
+// task once it has started.
+func (tm *TaskTemplateManager) SetDriverHandle(executor interfaces.ScriptExecutor) {
+	tm.handleLock.Lock()
+	defer tm.handleLock.Unlock()
+	tm.driverHandle = executor
 }
 
 // run is the long lived loop that handles errors and templates being rendered
@@ -191,6 +240,20 @@ func (tm *TaskTemplateManager) run() {
 		return
 	}
 
+	// Make the calls to Nomad's template functions before the templates
+	// read their results
+	if err := tm.funcs.refresh(true); err != nil {
+		tm.config.Lifecycle.Kill(context.Background(),
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+		return
+	}
+	go tm.funcs.run(tm.shutdownCh, func(err error) {
+		tm.config.Events.EmitEvent(structs.NewTaskEvent(structs.TaskHookFailed).
+			SetDisplayMessage(fmt.Sprintf("Template failed to refresh: %v", err)))
+	})
+
 	// Start the runner
 	go tm.runner.Start()
 
@@ -259,6 +322,14 @@ WAIT:
 					SetFailsTask().
 					SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
 		case <-tm.runner.TemplateRenderedCh():
+			if err := tm.chownSecrets(); err != nil {
+				tm.config.Lifecycle.Kill(context.Background(),
+					structs.NewTaskEvent(structs.TaskKilling).
+						SetFailsTask().
+						SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+				continue
+			}
+
 			// A template has been rendered, figure out what to do
 			events := tm.runner.RenderEvents()
 
@@ -362,9 +433,18 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 					SetFailsTask().
 					SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
 		case <-tm.runner.TemplateRenderedCh():
+			if err := tm.chownSecrets(); err != nil {
+				tm.config.Lifecycle.Kill(context.Background(),
+					structs.NewTaskEvent(structs.TaskKilling).
+						SetFailsTask().
+						SetDisplayMessage(fmt.Sprintf("Template failed: %v", err)))
+				continue
+			}
+
 			// A template has been rendered, figure out what to do
 			var handling []string
 			signals := make(map[string]struct{})
+			var scripts []*structs.ChangeScript
 			restart := false
 			var splay time.Duration
 
@@ -409,6 +489,8 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 						signals[tmpl.ChangeSignal] = struct{}{}
 					case structs.TemplateChangeModeRestart:
 						restart = true
+					case structs.TemplateChangeModeScript:
+						scripts = append(scripts, tmpl.ChangeScript)
 					case structs.TemplateChangeModeNoop:
 						continue
 					}
@@ -421,7 +503,7 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 				handling = append(handling, id)
 			}
 
-			if restart || len(signals) != 0 {
+			if restart || len(signals) != 0 || len(scripts) != 0 {
 				if splay != 0 {
 					ns := splay.Nanoseconds()
 					offset := rand.Int63n(ns)
@@ -439,11 +521,16 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 					handledRenders[id] = events[id].LastDidRender
 				}
 
+				// A restart supersedes signals and scripts, as the task
+				// reads all of its templates again when it starts
 				if restart {
 					tm.config.Lifecycle.Restart(context.Background(),
 						structs.NewTaskEvent(structs.TaskRestartSignal).
 							SetDisplayMessage("Template with change_mode restart re-rendered"), false)
-				} else if len(signals) != 0 {
+					continue
+				}
+
+				if len(signals) != 0 {
 					var mErr multierror.Error
 					for signal := range signals {
 						s := tm.signals[signal]
@@ -465,11 +552,75 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 								SetDisplayMessage(fmt.Sprintf("Template failed to send signals %v: %v", flat, err)))
 					}
 				}
+
+				if len(scripts) != 0 {
+					var wg sync.WaitGroup
+					for _, script := range scripts {
+						wg.Add(1)
+						go func(script *structs.ChangeScript) {
+							defer wg.Done()
+							tm.processScript(script)
+						}(script)
+					}
+					wg.Wait()
+				}
 			}
 		}
 	}
 }
 
+// processScript executes a change script inside the task. If the script
+// fails and is marked to fail on error the task is killed, otherwise an event
+// is emitted.
+func (tm *TaskTemplateManager) processScript(script *structs.ChangeScript) {
+	tm.handleLock.Lock()
+	handle := tm.driverHandle
+	tm.handleLock.Unlock()
+
+	var err error
+	if handle == nil {
+		err = fmt.Errorf("task is not running")
+	} else {
+		var exitCode int
+		_, exitCode, err = handle.Exec(script.Timeout, script.Command, script.Args)
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("exited with code %d", exitCode)
+		}
+	}
+
+	if err == nil {
+		tm.config.Events.EmitEvent(structs.NewTaskEvent(consulTemplateSourceName).
+			SetDisplayMessage(fmt.Sprintf("Template re-rendered, ran script %q", script.Command)))
+		return
+	}
+
+	msg := fmt.Sprintf("Template failed to run script %q: %v", script.Command, err)
+	if script.FailOnError {
+		tm.config.Lifecycle.Kill(context.Background(),
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(msg))
+		return
+	}
+	tm.config.Events.EmitEvent(structs.NewTaskEvent(structs.TaskHookFailed).SetDisplayMessage(msg))
+}
+
+// chownSecrets sets the owner of the rendered secret files. Files are replaced
+// when they are rendered, so this is done after every render.
+func (tm *TaskTemplateManager) chownSecrets() error {
+	for _, s := range tm.config.Secrets {
+		if s.Uid == 0 && s.Gid == 0 {
+			continue
+		}
+
+		path := filepath.Join(tm.config.TaskDir, s.DestPath())
+		if err := os.Chown(path, s.Uid, s.Gid); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to set owner of secret %q: %v", s.Name, err)
+		}
+	}
+	return nil
+}
+
 // allTemplatesNoop returns whether all the managed templates have change mode noop.
 func (tm *TaskTemplateManager) allTemplatesNoop() bool {
 	for _, tmpl := range tm.config.Templates {
@@ -478,13 +629,20 @@ func (tm *TaskTemplateManager) allTemplatesNoop() bool {
 		}
 	}
 
+	// Secrets with an owner must be chowned again when re-rendered
+	for _, s := range tm.config.Secrets {
+		if s.Uid != 0 || s.Gid != 0 {
+			return false
+		}
+	}
+
 	return true
 }
 
 // templateRunner returns a consul-template runner for the given templates and a
 // lookup by destination to the template. If no templates are in the config, a
 // nil template runner and lookup is returned.
-func templateRunner(config *TaskTemplateManagerConfig) (
+func templateRunner(config *TaskTemplateManagerConfig, funcs *templateFuncs) (
 	*manager.Runner, map[string][]*structs.Template, error) {
 
 	if len(config.Templates) == 0 {
@@ -492,7 +650,7 @@ func templateRunner(config *TaskTemplateManagerConfig) (
 	}
 
 	// Parse the templates
-	ctmplMapping, err := parseTemplateConfigs(config)
+	ctmplMapping, err := parseTemplateConfigs(config, funcs)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -525,9 +683,77 @@ func templateRunner(config *TaskTemplateManagerConfig) (
 	return runner, lookup, nil
 }
 
+// nomadVarFunc returns the nomadVar template function, which returns the items
+// of the variable at the given path. The variable is read using the node's
+// secret ID. Only the variables below the path of the task's job may be read.
+func nomadVarFunc(config *TaskTemplateManagerConfig) func(string) (map[string]string, error) {
+	jobPath := structs.VariablesJobsPrefix + "/" + config.JobID
+	return func(path string) (map[string]string, error) {
+		if config.RPCClient == nil {
+			return nil, fmt.Errorf("variables are not available to this template")
+		}
+		if path != jobPath && !strings.HasPrefix(path, jobPath+"/") {
+			return nil, fmt.Errorf("variable %q is not below the path of the job %q", path, jobPath)
+		}
+
+		args := &structs.VariablesReadRequest{
+			Path: path,
+			QueryOptions: structs.QueryOptions{
+				Region:     config.ClientConfig.Region,
+				Namespace:  config.Namespace,
+				AuthToken:  config.ClientConfig.Node.SecretID,
+				AllowStale: true,
+			},
+		}
+		var resp structs.VariablesReadResponse
+		if err := config.RPCClient.RPC("Variables.Read", args, &resp); err != nil {
+			return nil, fmt.Errorf("failed to read variable %q: %v", path, err)
+		}
+		if resp.Data == nil {
+			return nil, fmt.Errorf("variable %q not found", path)
+		}
+		return resp.Data.Items, nil
+	}
+}
+
+// nomadServiceFunc returns the nomadService template function, which returns
+// the passing registrations of the given service of the Nomad service
+// provider in the namespace of the task. The registrations are read using the
+// node's secret ID.
+func nomadServiceFunc(config *TaskTemplateManagerConfig) func(string) ([]*structs.ServiceRegistration, error) {
+	return func(name string) ([]*structs.ServiceRegistration, error) {
+		if config.RPCClient == nil {
+			return nil, fmt.Errorf("services are not available to this template")
+		}
+
+		args := &structs.ServiceRegistrationByNameRequest{
+			ServiceName: name,
+			QueryOptions: structs.QueryOptions{
+				Region:     config.ClientConfig.Region,
+				Namespace:  config.Namespace,
+				AuthToken:  config.ClientConfig.Node.SecretID,
+				AllowStale: true,
+			},
+		}
+		var resp structs.ServiceRegistrationByNameResponse
+		if err := config.RPCClient.RPC("ServiceRegistration.GetService", args, &resp); err != nil {
+			return nil, fmt.Errorf("failed to read service %q: %v", name, err)
+		}
+
+		passing := make([]*structs.ServiceRegistration, 0, len(resp.Services))
+		for _, s := range resp.Services {
+			if s.Status == api.HealthPassing {
+				passing = append(passing, s)
+			}
+		}
+		return passing, nil
+	}
+}
+
 // parseTemplateConfigs converts the tasks templates in the config into
-// consul-templates
-func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[ctconf.TemplateConfig]*structs.Template, error) {
+// consul-templates, replacing the calls to Nomad's template functions if funcs
+// is set
+func parseTemplateConfigs(config *TaskTemplateManagerConfig, funcs *templateFuncs) (map[ctconf.TemplateConfig]*structs.Template, error) {
 	allowAbs := config.ClientConfig.ReadBoolDefault(hostSrcOption, true)
 	taskEnv := config.EnvBuilder.Build()
 
@@ -549,10 +775,33 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[ctconf.Templat
 			dest = filepath.Join(config.TaskDir, taskEnv.ReplaceEnv(tmpl.DestPath))
 		}
 
+		// Replace the calls to Nomad's template functions. A template read
+		// from a file is embedded once rewritten.
+		contents := tmpl.EmbeddedTmpl
+		if funcs != nil {
+			original := contents
+			if src != "" {
+				raw, err := ioutil.ReadFile(src)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read template %q: %v", src, err)
+				}
+				original = string(raw)
+			}
+
+			rewritten, err := funcs.rewrite(tmpl.DestPath, original, tmpl.LeftDelim, tmpl.RightDelim)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse template: %v", err)
+			}
+			if rewritten != original {
+				src = ""
+				contents = rewritten
+			}
+		}
+
 		ct := ctconf.DefaultTemplateConfig()
 		ct.Source = &src
 		ct.Destination = &dest
-		ct.Contents = &tmpl.EmbeddedTmpl
+		ct.Contents = &contents
 		ct.LeftDelim = &tmpl.LeftDelim
 		ct.RightDelim = &tmpl.RightDelim
 
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -102,10 +103,12 @@ type testHarness struct {
 	manager    *TaskTemplateManager
 	mockHooks  *MockTaskHooks
 	templates  []*structs.Template
+	secrets    []*structs.Secret
 	envBuilder *taskenv.Builder
 	node       *structs.Node
 	config     *config.Config
 	vaultToken string
+	rpcClient  *mockVariablesRPC
 	taskDir    string
 	vault      *testutil.TestVault
 	consul     *ctestutil.TestServer
@@ -164,18 +167,25 @@ func (h *testHarness) start(t *testing.T) {
 
 func (h *testHarness) startWithErr() error {
 	var err error
-	h.manager, err = NewTaskTemplateManager(&TaskTemplateManagerConfig{
+	config := &TaskTemplateManagerConfig{
 		UnblockCh:            h.mockHooks.UnblockCh,
 		Lifecycle:            h.mockHooks,
 		Events:               h.mockHooks,
 		Templates:            h.templates,
+		Secrets:              h.secrets,
 		ClientConfig:         h.config,
 		VaultToken:           h.vaultToken,
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.envBuilder,
 		MaxTemplateEventRate: h.emitRate,
 		retryRate:            10 * time.Millisecond,
-	})
+	}
+	if h.rpcClient != nil {
+		config.RPCClient = h.rpcClient
+		config.Namespace = structs.DefaultNamespace
+		config.JobID = "example"
+	}
+	h.manager, err = NewTaskTemplateManager(config)
 
 	return err
 }
@@ -499,6 +509,212 @@ func TestTaskTemplateManager_Unblock_Static_NomadEnv(t *testing.T) {
 	}
 }
 
+func TestTaskTemplateManager_Unblock_Static_NomadVar(t *testing.T) {
+	t.Parallel()
+	// Make a template that will render a Nomad variable
+	content := `{{ with nomadVar "nomad/jobs/example/db" }}{{ .password }}{{ end }}`
+	expected := "hunter2"
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.config.Node = harness.node
+	harness.rpcClient = &mockVariablesRPC{
+		vars: map[string]map[string]string{
+			"nomad/jobs/example/db": {"password": "hunter2"},
+		},
+	}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Check the file is there
+	path := filepath.Join(harness.taskDir, file)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+
+	if s := string(raw); s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+
+	// The variable is read with the node's secret ID
+	args := harness.rpcClient.lastArgs()
+	require.NotNil(t, args)
+	require.Equal(t, harness.node.SecretID, args.AuthToken)
+	require.Equal(t, structs.DefaultNamespace, args.Namespace)
+}
+
+func TestTaskTemplateManager_Secret_NomadVar(t *testing.T) {
+	t.Parallel()
+	secret := &structs.Secret{
+		Name:       "db_password",
+		Provider:   structs.SecretProviderNomad,
+		Path:       "nomad/jobs/example/db",
+		Key:        "password",
+		Perms:      "0400",
+		ChangeMode: structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, nil, false, false)
+	harness.secrets = []*structs.Secret{secret}
+	harness.config.Node = harness.node
+	harness.rpcClient = &mockVariablesRPC{
+		vars: map[string]map[string]string{
+			"nomad/jobs/example/db": {"password": "hunter2"},
+		},
+	}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Check the file is in the secrets directory
+	path := filepath.Join(harness.taskDir, "secrets", "db_password")
+	raw, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(raw))
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0400), fi.Mode())
+}
+
+func TestTaskTemplateManager_NomadVar_OtherJob(t *testing.T) {
+	t.Parallel()
+	config := &TaskTemplateManagerConfig{
+		ClientConfig: &config.Config{Region: "global", Node: mock.Node()},
+		Namespace:    structs.DefaultNamespace,
+		JobID:        "example",
+		RPCClient: &mockVariablesRPC{
+			vars: map[string]map[string]string{
+				"nomad/jobs/other": {"password": "hunter2"},
+			},
+		},
+	}
+
+	// Variables of other jobs can't be read
+	nomadVar := nomadVarFunc(config)
+	_, err := nomadVar("nomad/jobs/other")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not below the path of the job")
+
+	_, err = nomadVar("nomad/jobs/example-other")
+	require.Error(t, err)
+
+	// Missing variables of the job return an error
+	_, err = nomadVar("nomad/jobs/example")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+}
+
+func TestTaskTemplateManager_NomadService(t *testing.T) {
+	t.Parallel()
+	node := mock.Node()
+	rpc := &mockServicesRPC{
+		services: []*structs.ServiceRegistration{
+			{ID: "a", ServiceName: "web", Address: "10.0.0.1", Port: 8080, Status: "passing"},
+			{ID: "b", ServiceName: "web", Address: "10.0.0.2", Port: 8080, Status: "critical"},
+			{ID: "c", ServiceName: "db", Address: "10.0.0.3", Port: 5432, Status: "passing"},
+		},
+	}
+	config := &TaskTemplateManagerConfig{
+		ClientConfig: &config.Config{Region: "global", Node: node},
+		Namespace:    structs.DefaultNamespace,
+		JobID:        "example",
+		RPCClient:    rpc,
+	}
+
+	// Only the passing registrations of the service are returned
+	nomadService := nomadServiceFunc(config)
+	services, err := nomadService("web")
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, "10.0.0.1", services[0].Address)
+
+	// The registrations are read with the node's secret ID
+	require.Equal(t, node.SecretID, rpc.args.AuthToken)
+	require.Equal(t, structs.DefaultNamespace, rpc.args.Namespace)
+
+	// Unknown services have no registrations
+	services, err = nomadService("missing")
+	require.NoError(t, err)
+	require.Empty(t, services)
+}
+
+// mockServicesRPC is a mock of the RPC client that serves
+// ServiceRegistration.GetService from a static set of registrations
+type mockServicesRPC struct {
+	services []*structs.ServiceRegistration
+	args     *structs.ServiceRegistrationByNameRequest
+}
+
+func (m *mockServicesRPC) RPC(method string, args interface{}, reply interface{}) error {
+	if method != "ServiceRegistration.GetService" {
+		return fmt.Errorf("unexpected RPC %q", method)
+	}
+
+	m.args = args.(*structs.ServiceRegistrationByNameRequest)
+	resp := reply.(*structs.ServiceRegistrationByNameResponse)
+	for _, s := range m.services {
+		if s.ServiceName == m.args.ServiceName {
+			resp.Services = append(resp.Services, s)
+		}
+	}
+	return nil
+}
+
+// mockVariablesRPC is a mock of the RPC client that serves Variables.Read
+// from a static set of variables
+type mockVariablesRPC struct {
+	vars map[string]map[string]string
+
+	mu   sync.Mutex
+	args *structs.VariablesReadRequest
+}
+
+func (m *mockVariablesRPC) RPC(method string, args interface{}, reply interface{}) error {
+	if method != "Variables.Read" {
+		return fmt.Errorf("unexpected RPC %q", method)
+	}
+
+	req := args.(*structs.VariablesReadRequest)
+	m.mu.Lock()
+	m.args = req
+	m.mu.Unlock()
+
+	resp := reply.(*structs.VariablesReadResponse)
+	if items, ok := m.vars[req.Path]; ok {
+		resp.Data = &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: req.Path},
+			Items:            items,
+		}
+	}
+	return nil
+}
+
+func (m *mockVariablesRPC) lastArgs() *structs.VariablesReadRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.args
+}
+
 func TestTaskTemplateManager_Unblock_Static_AlreadyRendered(t *testing.T) {
 	t.Parallel()
 	// Make a template that will render immediately
@@ -932,6 +1148,114 @@ OUTER:
 	}
 }
 
+// mockExecutor implements ScriptExecutor by recording the executed commands
+type mockExecutor struct {
+	sync.Mutex
+	commands []string
+	exitCode int
+	execCh   chan struct{}
+}
+
+func (m *mockExecutor) Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error) {
+	m.Lock()
+	m.commands = append(m.commands, cmd)
+	m.Unlock()
+	select {
+	case m.execCh <- struct{}{}:
+	default:
+	}
+	return nil, m.exitCode, nil
+}
+
+func TestTaskTemplateManager_Rerender_Script(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Make a template that renders based on a key in Consul and runs a script
+	key1 := "bam"
+	content1_1 := "cat"
+	content1_2 := "dog"
+	template := &structs.Template{
+		EmbeddedTmpl: fmt.Sprintf(`{{key "%s"}}`, key1),
+		DestPath:     "my.tmpl",
+		ChangeMode:   structs.TemplateChangeModeScript,
+		ChangeScript: &structs.ChangeScript{
+			Command: "/bin/reload",
+			Timeout: 5 * time.Second,
+		},
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, true, false)
+	harness.start(t)
+	defer harness.stop()
+
+	executor := &mockExecutor{execCh: make(chan struct{}, 1)}
+	harness.manager.SetDriverHandle(executor)
+
+	// Write the key to Consul and wait for the unblock
+	harness.consul.SetKV(t, key1, []byte(content1_1))
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Update the key in Consul and wait for the script
+	harness.consul.SetKV(t, key1, []byte(content1_2))
+	select {
+	case <-executor.execCh:
+	case <-harness.mockHooks.RestartCh:
+		t.Fatalf("Restart with script change mode: %+v", harness.mockHooks)
+	case <-time.After(time.Duration(10*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Should have executed the script: %+v", harness.mockHooks)
+	}
+
+	executor.Lock()
+	require.Equal([]string{"/bin/reload"}, executor.commands)
+	executor.Unlock()
+}
+
+func TestTaskTemplateManager_Rerender_Script_FailOnError(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Make a template that runs a failing script that kills the task
+	key1 := "bam"
+	template := &structs.Template{
+		EmbeddedTmpl: fmt.Sprintf(`{{key "%s"}}`, key1),
+		DestPath:     "my.tmpl",
+		ChangeMode:   structs.TemplateChangeModeScript,
+		ChangeScript: &structs.ChangeScript{
+			Command:     "/bin/reload",
+			Timeout:     5 * time.Second,
+			FailOnError: true,
+		},
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, true, false)
+	harness.start(t)
+	defer harness.stop()
+
+	harness.manager.SetDriverHandle(&mockExecutor{exitCode: 1})
+
+	harness.consul.SetKV(t, key1, []byte("cat"))
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	harness.consul.SetKV(t, key1, []byte("dog"))
+	select {
+	case <-harness.mockHooks.KillCh:
+	case <-time.After(time.Duration(10*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Should have killed the task: %+v", harness.mockHooks)
+	}
+
+	require.NotNil(harness.mockHooks.KillEvent)
+	require.Contains(harness.mockHooks.KillEvent.DisplayMessage, "exited with code 1")
+}
+
 func TestTaskTemplateManager_Interpolate_Destination(t *testing.T) {
 	t.Parallel()
 	// Make a template that will have its destination interpolated
@@ -1317,7 +1641,7 @@ func TestTaskTemplateManager_Config_VaultGrace(t *testing.T) {
 		EnvBuilder: taskenv.NewBuilder(c.Node, alloc, alloc.Job.TaskGroups[0].Tasks[0], c.Region),
 	}
 
-	ctmplMapping, err := parseTemplateConfigs(config)
+	ctmplMapping, err := parseTemplateConfigs(config, nil)
 	assert.Nil(err, "Parsing Templates")
 
 	ctconf, err := newRunnerConfig(config, ctmplMapping)
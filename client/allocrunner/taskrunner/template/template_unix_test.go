@@ -0,0 +1,59 @@
+// +build !windows
+
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaskTemplateManager_Secret_Owner asserts that the rendered secret files
+// are chowned to the owner of the secret.
+func TestTaskTemplateManager_Secret_Owner(t *testing.T) {
+	t.Parallel()
+	if os.Geteuid() != 0 {
+		t.Skip("Must be run as root")
+	}
+
+	secret := &structs.Secret{
+		Name:       "db_password",
+		Provider:   structs.SecretProviderNomad,
+		Path:       "nomad/jobs/example/db",
+		Key:        "password",
+		Perms:      "0400",
+		Uid:        1000,
+		Gid:        1001,
+		ChangeMode: structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, nil, false, false)
+	harness.secrets = []*structs.Secret{secret}
+	harness.config.Node = harness.node
+	harness.rpcClient = &mockVariablesRPC{
+		vars: map[string]map[string]string{
+			"nomad/jobs/example/db": {"password": "hunter2"},
+		},
+	}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	fi, err := os.Stat(filepath.Join(harness.taskDir, "secrets", "db_password"))
+	require.NoError(t, err)
+	stat := fi.Sys().(*syscall.Stat_t)
+	require.Equal(t, uint32(1000), stat.Uid)
+	require.Equal(t, uint32(1001), stat.Gid)
+}
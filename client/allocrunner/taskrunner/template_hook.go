@@ -10,6 +10,7 @@ import (
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/template"
 	"github.com/hashicorp/nomad/client/config"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -27,11 +28,23 @@ type templateHookConfig struct {
 	// templates is the set of templates we are managing
 	templates []*structs.Template
 
+	// secrets is the set of secrets rendered along with the templates
+	secrets []*structs.Secret
+
 	// clientConfig is the Nomad Client configuration
 	clientConfig *config.Config
 
 	// envBuilder is the environment variable builder for the task.
 	envBuilder *taskenv.Builder
+
+	// namespace is the namespace of the task's allocation
+	namespace string
+
+	// jobID is the ID of the job of the task's allocation
+	jobID string
+
+	// rpcClient is used to read variables from the servers
+	rpcClient cinterfaces.RPCer
 }
 
 type templateHook struct {
@@ -44,6 +57,9 @@ type templateHook struct {
 	templateManager *template.TaskTemplateManager
 	managerLock     sync.Mutex
 
+	// driverHandle is used to execute change scripts inside the task
+	driverHandle ti.ScriptExecutor
+
 	// vaultToken is the current Vault token
 	vaultToken string
 
@@ -89,6 +105,19 @@ func (h *templateHook) Prestart(ctx context.Context, req *interfaces.TaskPrestar
 	return nil
 }
 
+func (h *templateHook) Poststart(ctx context.Context, req *interfaces.TaskPoststartRequest, resp *interfaces.TaskPoststartResponse) error {
+	h.managerLock.Lock()
+	defer h.managerLock.Unlock()
+
+	// Store the driver handle so change scripts can be executed in the task
+	h.driverHandle = req.DriverExec
+	if h.templateManager != nil {
+		h.templateManager.SetDriverHandle(h.driverHandle)
+	}
+
+	return nil
+}
+
 func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 	unblock = make(chan struct{})
 	m, err := template.NewTaskTemplateManager(&template.TaskTemplateManagerConfig{
@@ -96,10 +125,14 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		Lifecycle:            h.config.lifecycle,
 		Events:               h.config.events,
 		Templates:            h.config.templates,
+		Secrets:              h.config.secrets,
 		ClientConfig:         h.config.clientConfig,
 		VaultToken:           h.vaultToken,
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.config.envBuilder,
+		Namespace:            h.config.namespace,
+		JobID:                h.config.jobID,
+		RPCClient:            h.config.rpcClient,
 		MaxTemplateEventRate: template.DefaultMaxTemplateEventRate,
 	})
 	if err != nil {
@@ -107,6 +140,10 @@ func (h *templateHook) newManager() (unblock chan struct{}, err error) {
 		return nil, err
 	}
 
+	if h.driverHandle != nil {
+		m.SetDriverHandle(h.driverHandle)
+	}
+
 	h.templateManager = m
 	return unblock, nil
 }
@@ -217,7 +217,7 @@ OUTER:
 		}
 
 		// Start the renewal process
-		renewCh, err := h.client.RenewToken(token, 30)
+		renewCh, err := h.client.RenewToken(token, h.vaultStanza.Namespace, 30)
 
 		// An error returned means the token is not being renewed
 		if err != nil {
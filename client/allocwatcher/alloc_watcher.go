@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -18,12 +19,23 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
 )
 
 const (
 	// getRemoteRetryIntv is minimum interval on which we retry
 	// to fetch remote objects. We pick a value between this and 2x this.
 	getRemoteRetryIntv = 30 * time.Second
+
+	// migrateRetryIntv is the minimum interval on which we resume streaming
+	// the snapshot of a remote previous alloc. We pick a value between this
+	// and 2x this.
+	migrateRetryIntv = 1 * time.Second
+
+	// migrateMaxAttempts is the number of consecutive attempts at streaming
+	// the snapshot of a remote previous alloc without any progress after
+	// which the migration fails.
+	migrateMaxAttempts = 5
 )
 
 // RPCer is the interface needed by a prevAllocWatcher to make RPC calls.
@@ -91,6 +103,10 @@ type Config struct {
 	// enabled.
 	MigrateToken string
 
+	// MigrateLimiter limits the bandwidth used to migrate remote alloc dirs.
+	// It is shared by all the migrations of the client. Nil if unlimited.
+	MigrateLimiter *rate.Limiter
+
 	Logger hclog.Logger
 }
 
@@ -119,10 +135,12 @@ func newMigratorForAlloc(c Config, tg *structs.TaskGroup, watchedAllocID string,
 		allocID:      c.Alloc.ID,
 		prevAllocID:  c.Alloc.PreviousAllocation,
 		tasks:        tasks,
+		allocDir:     c.Config.AllocDirFor(c.Alloc),
 		config:       c.Config,
 		migrate:      migrate,
 		rpc:          c.RPC,
 		migrateToken: c.MigrateToken,
+		limiter:      c.MigrateLimiter,
 		logger:       logger,
 	}
 }
@@ -145,6 +163,7 @@ func newWatcherForAlloc(c Config, watchedAllocID string, m AllocRunnerMeta) Prev
 	return &remotePrevAlloc{
 		allocID:      c.Alloc.ID,
 		prevAllocID:  c.Alloc.PreviousAllocation,
+		allocDir:     c.Config.AllocDirFor(c.Alloc),
 		config:       c.Config,
 		rpc:          c.RPC,
 		migrateToken: c.MigrateToken,
@@ -313,7 +332,11 @@ type remotePrevAlloc struct {
 	// tasks on the new alloc
 	tasks []*structs.Task
 
-	// config for the Client to get AllocDir, Region, and Node.SecretID
+	// allocDir is the directory storing the data of the alloc being blocked,
+	// to which the previous alloc dir is migrated
+	allocDir string
+
+	// config for the Client to get Region and Node.SecretID
 	config *config.Config
 
 	// migrate is true if data should be moved between nodes
@@ -339,6 +362,10 @@ type remotePrevAlloc struct {
 	// migrateToken allows a client to migrate data in an ACL-protected remote
 	// volume
 	migrateToken string
+
+	// limiter limits the bandwidth used to stream the remote alloc dir. Nil
+	// if unlimited.
+	limiter *rate.Limiter
 }
 
 // IsWaiting returns true if there's a concurrent call inside Wait
@@ -509,7 +536,7 @@ func (p *remotePrevAlloc) getNodeAddr(ctx context.Context, nodeID string) (strin
 // Destroy on the returned allocdir if no error occurs.
 func (p *remotePrevAlloc) migrateAllocDir(ctx context.Context, nodeAddr string) (*allocdir.AllocDir, error) {
 	// Create the previous alloc dir
-	prevAllocDir := allocdir.NewAllocDir(p.logger, filepath.Join(p.config.AllocDir, p.prevAllocID))
+	prevAllocDir := allocdir.NewAllocDir(p.logger, filepath.Join(p.allocDir, p.prevAllocID))
 	if err := prevAllocDir.Build(); err != nil {
 		return nil, fmt.Errorf("error building alloc dir for previous alloc %q: %v", p.prevAllocID, err)
 	}
@@ -528,29 +555,74 @@ func (p *remotePrevAlloc) migrateAllocDir(ctx context.Context, nodeAddr string)
 		return nil, err
 	}
 
+	// The snapshot of a terminal alloc is the same every time it is taken, so
+	// an interrupted stream is resumed from the end of the last entry written
+	// by asking the remote node to skip the bytes before it.
 	url := fmt.Sprintf("/v1/client/allocation/%v/snapshot", p.prevAllocID)
-	qo := &nomadapi.QueryOptions{AuthToken: p.migrateToken}
-	resp, err := apiClient.Raw().Response(url, qo)
-	if err != nil {
-		prevAllocDir.Destroy()
-		return nil, fmt.Errorf("error getting snapshot from previous alloc %q: %v", p.prevAllocID, err)
-	}
+	var offset int64
+	failures := 0
+	for {
+		qo := &nomadapi.QueryOptions{
+			AuthToken: p.migrateToken,
+			Params:    map[string]string{"offset": strconv.FormatInt(offset, 10)},
+		}
+		resp, err := apiClient.Raw().Response(url, qo)
+		if err != nil {
+			err = resumableError{fmt.Errorf("error getting snapshot from previous alloc %q: %v", p.prevAllocID, err)}
+		} else {
+			var n int64
+			n, err = p.streamAllocDir(ctx, p.limitReader(ctx, resp), prevAllocDir.AllocDir)
+			if err == nil {
+				return prevAllocDir, nil
+			}
+			if n > 0 {
+				offset += n
+				failures = 0
+			}
+		}
 
-	if err := p.streamAllocDir(ctx, resp, prevAllocDir.AllocDir); err != nil {
-		prevAllocDir.Destroy()
-		return nil, err
+		failures++
+		if _, ok := err.(resumableError); !ok || failures >= migrateMaxAttempts || ctx.Err() != nil {
+			prevAllocDir.Destroy()
+			return nil, err
+		}
+
+		p.logger.Warn("streaming snapshot of previous alloc interrupted, resuming",
+			"error", err, "offset", offset, "failures", failures)
+		select {
+		case <-time.After(migrateRetryIntv + lib.RandomStagger(migrateRetryIntv)):
+		case <-ctx.Done():
+			prevAllocDir.Destroy()
+			return nil, ctx.Err()
+		}
 	}
+}
 
-	return prevAllocDir, nil
+// limitReader limits the bandwidth used to read the stream if a limiter is
+// set.
+func (p *remotePrevAlloc) limitReader(ctx context.Context, r io.ReadCloser) io.ReadCloser {
+	if p.limiter == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, limiter: p.limiter}
 }
 
 // stream remote alloc to dir to a local path. Caller should cleanup dest on
-// error.
-func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser, dest string) error {
+// error. The number of bytes of the stream up to the end of the last entry
+// written is returned, from which a resumableError can be resumed.
+func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser, dest string) (int64, error) {
 	p.logger.Debug("streaming snapshot of previous alloc", "destination", dest)
-	tr := tar.NewReader(resp)
+	cr := &countingReader{r: resp}
+	tr := tar.NewReader(cr)
 	defer resp.Close()
 
+	// written is the end of the last entry written. Entries are padded to
+	// the tar block size.
+	var written int64
+	entryWritten := func() {
+		written = (cr.n + tarBlockSize - 1) / tarBlockSize * tarBlockSize
+	}
+
 	// Cache effective uid as we only run Chown if we're root
 	euid := syscall.Geteuid()
 
@@ -567,19 +639,19 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 	// if we see this file, there was an error on the remote side
 	errorFilename := allocdir.SnapshotErrorFilename(p.prevAllocID)
 
-	buf := make([]byte, 1024)
+	buf := make([]byte, 32*1024)
 	for !canceled() {
 		// Get the next header
 		hdr, err := tr.Next()
 
 		// Snapshot has ended
 		if err == io.EOF {
-			return nil
+			return written, nil
 		}
 
 		if err != nil {
-			return fmt.Errorf("error streaming previous alloc %q for new alloc %q: %v",
-				p.prevAllocID, p.allocID, err)
+			return written, resumableError{fmt.Errorf("error streaming previous alloc %q for new alloc %q: %v",
+				p.prevAllocID, p.allocID, err)}
 		}
 
 		if hdr.Name == errorFilename {
@@ -587,10 +659,10 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 			// the message out of the file and return it.
 			errBuf := make([]byte, int(hdr.Size))
 			if _, err := tr.Read(errBuf); err != nil && err != io.EOF {
-				return fmt.Errorf("error streaming previous alloc %q for new alloc %q; failed reading error message: %v",
+				return written, fmt.Errorf("error streaming previous alloc %q for new alloc %q; failed reading error message: %v",
 					p.prevAllocID, p.allocID, err)
 			}
-			return fmt.Errorf("error streaming previous alloc %q for new alloc %q: %s",
+			return written, fmt.Errorf("error streaming previous alloc %q for new alloc %q: %s",
 				p.prevAllocID, p.allocID, string(errBuf))
 		}
 
@@ -602,36 +674,39 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 			// Can't change owner if not root or on Windows.
 			if euid == 0 {
 				if err := os.Chown(name, hdr.Uid, hdr.Gid); err != nil {
-					return fmt.Errorf("error chowning directory %v", err)
+					return written, fmt.Errorf("error chowning directory %v", err)
 				}
 			}
+			entryWritten()
 			continue
 		}
 		// If the header is for a symlink we create the symlink
 		if hdr.Typeflag == tar.TypeSymlink {
 			if err = os.Symlink(hdr.Linkname, filepath.Join(dest, hdr.Name)); err != nil {
-				return fmt.Errorf("error creating symlink: %v", err)
+				return written, fmt.Errorf("error creating symlink: %v", err)
 			}
+			entryWritten()
 			continue
 		}
-		// If the header is a file, we write to a file
+		// If the header is a file, we write to a file. A file whose stream
+		// was interrupted is recreated when resuming.
 		if hdr.Typeflag == tar.TypeReg {
 			f, err := os.Create(filepath.Join(dest, hdr.Name))
 			if err != nil {
-				return fmt.Errorf("error creating file: %v", err)
+				return written, fmt.Errorf("error creating file: %v", err)
 			}
 
 			// Setting the permissions of the file as the origin.
 			if err := f.Chmod(os.FileMode(hdr.Mode)); err != nil {
 				f.Close()
-				return fmt.Errorf("error chmoding file %v", err)
+				return written, fmt.Errorf("error chmoding file %v", err)
 			}
 
 			// Can't change owner if not root or on Windows.
 			if euid == 0 {
 				if err := f.Chown(hdr.Uid, hdr.Gid); err != nil {
 					f.Close()
-					return fmt.Errorf("error chowning file %v", err)
+					return written, fmt.Errorf("error chowning file %v", err)
 				}
 			}
 
@@ -640,17 +715,25 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 			for !canceled() {
 				n, err := tr.Read(buf)
 				if n > 0 && (err == nil || err == io.EOF) {
-					if _, err := f.Write(buf[:n]); err != nil {
+					if err := writeSparse(f, buf[:n]); err != nil {
 						f.Close()
-						return fmt.Errorf("error writing to file %q: %v", f.Name(), err)
+						return written, fmt.Errorf("error writing to file %q: %v", f.Name(), err)
 					}
 				}
 
 				if err != nil {
-					f.Close()
 					if err != io.EOF {
-						return fmt.Errorf("error reading snapshot: %v", err)
+						f.Close()
+						return written, resumableError{fmt.Errorf("error reading snapshot: %v", err)}
+					}
+
+					// Set the size of files ending with a hole
+					if err := f.Truncate(hdr.Size); err != nil {
+						f.Close()
+						return written, fmt.Errorf("error writing to file %q: %v", f.Name(), err)
 					}
+					f.Close()
+					entryWritten()
 					break
 				}
 			}
@@ -659,10 +742,70 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 	}
 
 	if canceled() {
-		return ctx.Err()
+		return written, ctx.Err()
 	}
 
-	return nil
+	return written, nil
+}
+
+// resumableError is an error reading the snapshot stream of a remote previous
+// alloc, after which the stream can be resumed.
+type resumableError struct {
+	error
+}
+
+// tarBlockSize is the size of the blocks tar entries are padded to.
+const tarBlockSize = 512
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// limitedReader waits for the limiter before each read, reading at most the
+// limiter's burst at a time.
+type limitedReader struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if burst := l.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := l.r.Read(p)
+	if n > 0 {
+		if werr := l.limiter.WaitN(l.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+func (l *limitedReader) Close() error {
+	return l.r.Close()
+}
+
+// writeSparse writes the chunk to the file, seeking over it instead if it
+// only holds zeros so that sparse files stay sparse once migrated.
+func writeSparse(f *os.File, chunk []byte) error {
+	for _, b := range chunk {
+		if b != 0 {
+			_, err := f.Write(chunk)
+			return err
+		}
+	}
+
+	_, err := f.Seek(int64(len(chunk)), io.SeekCurrent)
+	return err
 }
 
 // NoopPrevAlloc does not block or migrate on a previous allocation and never
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -254,7 +255,7 @@ func TestPrevAlloc_StreamAllocDir_Error(t *testing.T) {
 	}
 
 	// Assert streamAllocDir fails
-	err = prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(tarBuf), dest)
+	_, err = prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(tarBuf), dest)
 	if err == nil {
 		t.Fatalf("expected an error from streamAllocDir")
 	}
@@ -272,3 +273,73 @@ func TestPrevAlloc_StreamAllocDir_Error(t *testing.T) {
 		t.Fatalf("expected foo.txt to be size 1 but found %d", fi.Size())
 	}
 }
+
+// errReader fails every read with its error
+type errReader struct {
+	err error
+}
+
+func (e errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
+
+// TestPrevAlloc_StreamAllocDir_Resume asserts that an interrupted stream
+// reports the end of the last entry written, from which it can be resumed.
+func TestPrevAlloc_StreamAllocDir_Resume(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dest, err := ioutil.TempDir("", "nomadtest-")
+	require.NoError(err)
+	defer os.RemoveAll(dest)
+
+	prevAlloc := &remotePrevAlloc{
+		logger:      testlog.HCLogger(t),
+		allocID:     "123",
+		prevAllocID: "abc",
+		migrate:     true,
+	}
+
+	files := []struct {
+		name     string
+		contents string
+	}{
+		{"foo.txt", "foo"},
+		{"bar.txt", strings.Repeat("bar", 1000)},
+	}
+	tarBuf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(tarBuf)
+	require.NoError(tw.WriteHeader(&tar.Header{Name: "dir", Mode: 0755, Typeflag: tar.TypeDir}))
+	for _, f := range files {
+		require.NoError(tw.WriteHeader(&tar.Header{
+			Name:     filepath.Join("dir", f.name),
+			Mode:     0644,
+			Size:     int64(len(f.contents)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(f.contents))
+		require.NoError(err)
+	}
+	require.NoError(tw.Close())
+	snapshot := tarBuf.Bytes()
+
+	// Interrupt the stream in the middle of bar.txt, after the headers of
+	// dir and foo.txt and the padded contents of foo.txt
+	const barOffset = 3 * 512
+	interrupted := io.MultiReader(bytes.NewReader(snapshot[:barOffset+512+100]), errReader{io.ErrUnexpectedEOF})
+	n, err := prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(interrupted), dest)
+	require.Error(err)
+	require.IsType(resumableError{}, err)
+	require.EqualValues(barOffset, n)
+
+	// Resume the stream
+	n, err = prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(bytes.NewReader(snapshot[n:])), dest)
+	require.NoError(err)
+	require.NotZero(n)
+
+	for _, f := range files {
+		out, err := ioutil.ReadFile(filepath.Join(dest, "dir", f.name))
+		require.NoError(err)
+		require.Equal(f.contents, string(out))
+	}
+}
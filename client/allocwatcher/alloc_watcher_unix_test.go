@@ -16,6 +16,7 @@ import (
 
 	ctestutil "github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
 )
 
 // TestPrevAlloc_StreamAllocDir_Ok asserts that streaming a tar to an alloc dir
@@ -125,7 +126,7 @@ func TestPrevAlloc_StreamAllocDir_Ok(t *testing.T) {
 
 	rc := ioutil.NopCloser(buf)
 	prevAlloc := &remotePrevAlloc{logger: testlog.HCLogger(t)}
-	if err := prevAlloc.streamAllocDir(context.Background(), rc, dir1); err != nil {
+	if _, err := prevAlloc.streamAllocDir(context.Background(), rc, dir1); err != nil {
 		t.Fatalf("err: %v", err)
 	}
 
@@ -159,3 +160,57 @@ func TestPrevAlloc_StreamAllocDir_Ok(t *testing.T) {
 		t.Fatalf("mode: %v", fi2.Mode())
 	}
 }
+
+// TestPrevAlloc_StreamAllocDir_Sparse asserts that the runs of zeros of the
+// streamed files are written as holes.
+func TestPrevAlloc_StreamAllocDir_Sparse(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// A 1MiB file with a byte at its start and end
+	const size = 1024 * 1024
+	contents := make([]byte, size)
+	contents[0], contents[size-1] = 1, 2
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	require.NoError(tw.WriteHeader(&tar.Header{
+		Name:     "sparse",
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     size,
+	}))
+	_, err := tw.Write(contents)
+	require.NoError(err)
+
+	// A file ending with a hole
+	require.NoError(tw.WriteHeader(&tar.Header{
+		Name:     "trailing",
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     size,
+	}))
+	_, err = tw.Write(make([]byte, size))
+	require.NoError(err)
+	require.NoError(tw.Close())
+
+	dir, err := ioutil.TempDir("", "nomadtest-")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	prevAlloc := &remotePrevAlloc{logger: testlog.HCLogger(t)}
+	_, err = prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(buf), dir)
+	require.NoError(err)
+
+	out, err := ioutil.ReadFile(filepath.Join(dir, "sparse"))
+	require.NoError(err)
+	require.Equal(contents, out)
+
+	fi, err := os.Stat(filepath.Join(dir, "sparse"))
+	require.NoError(err)
+	require.True(fi.Sys().(*syscall.Stat_t).Blocks*512 < size, "file isn't sparse")
+
+	fi, err = os.Stat(filepath.Join(dir, "trailing"))
+	require.NoError(err)
+	require.EqualValues(size, fi.Size())
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/consul-template/signals"
 	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
 	hclog "github.com/hashicorp/go-hclog"
@@ -27,7 +29,12 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/devicemanager"
+	"github.com/hashicorp/nomad/client/diskquota"
+	"github.com/hashicorp/nomad/client/dynamicusers"
+	"github.com/hashicorp/nomad/client/externalservices"
 	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/client/keyring"
+	"github.com/hashicorp/nomad/client/nomadservices"
 	"github.com/hashicorp/nomad/client/pluginmanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/servers"
@@ -46,6 +53,7 @@ import (
 	"github.com/hashicorp/nomad/plugins/device"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/shirou/gopsutil/host"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -117,10 +125,16 @@ type AllocRunner interface {
 	IsWaiting() bool
 	Listener() *cstructs.AllocListener
 	Restore() error
+	RestartAll(event *structs.TaskEvent) error
+	RestartTask(taskName string, event *structs.TaskEvent) error
+	RestartOrdered(event *structs.TaskEvent) error
+	ExecTask(taskName string, timeout time.Duration, cmd string, args []string) ([]byte, int, error)
 	Run()
+	Signal(taskName string, event *structs.TaskEvent, signal string) error
 	StatsReporter() interfaces.AllocStatsReporter
 	Update(*structs.Allocation)
 	WaitCh() <-chan struct{}
+	WaitHealthy(ctx context.Context, since time.Time) bool
 	DestroyCh() <-chan struct{}
 	ShutdownCh() <-chan struct{}
 	GetTaskEventHandler(taskName string) drivermanager.EventHandler
@@ -136,6 +150,10 @@ type Client struct {
 	// stateDB is used to efficiently store client state.
 	stateDB state.StateDB
 
+	// keyring encrypts the state persisted in the state directory if data
+	// encryption is enabled
+	keyring *keyring.Keyring
+
 	// configCopy is a copy that should be passed to alloc-runners.
 	configCopy *config.Config
 	configLock sync.RWMutex
@@ -193,6 +211,18 @@ type Client struct {
 	// and checks.
 	consulService consulApi.ConsulServiceAPI
 
+	// consulServices are the service clients of the named Consul clusters,
+	// keyed by name.
+	consulServices map[string]*consul.ServiceClient
+
+	// nomadServices registers task services with the Nomad service provider
+	// and runs their checks.
+	nomadServices *nomadservices.ServiceClient
+
+	// externalServices pushes task services to external service providers
+	// once their checks pass.
+	externalServices *externalservices.ServiceClient
+
 	// consulCatalog is the subset of Consul's Catalog API Nomad uses.
 	consulCatalog consul.CatalogAPI
 
@@ -215,6 +245,10 @@ type Client struct {
 	// vaultClient is used to interact with Vault for token and secret renewals
 	vaultClient vaultclient.VaultClient
 
+	// vaultClients are the clients of the named Vault clusters, keyed by
+	// name.
+	vaultClients map[string]vaultclient.VaultClient
+
 	// garbageCollector is used to garbage collect terminal allocations present
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
@@ -236,6 +270,14 @@ type Client struct {
 	// drivermanager is responsible for managing driver plugins
 	drivermanager drivermanager.Manager
 
+	// dynamicUsers assigns dynamic users to allocations. It is nil if
+	// dynamic users are disabled.
+	dynamicUsers *dynamicusers.Pool
+
+	// diskQuotas enforces the ephemeral disk sizes of allocations. It is nil
+	// if the filesystems of the alloc dirs don't support project quotas.
+	diskQuotas *diskquota.Quotas
+
 	// baseLabels are used when emitting tagged metrics. All client metrics will
 	// have these tags, and optionally more.
 	baseLabels []metrics.Label
@@ -246,6 +288,26 @@ type Client struct {
 	// fpInitialized chan is closed when the first batch of fingerprints are
 	// applied to the node and the server is updated
 	fpInitialized chan struct{}
+
+	// dynamicMeta is the node metadata set at runtime. Keys with a nil value
+	// were unset. It is persisted to the state database and must be accessed
+	// while holding the configLock.
+	dynamicMeta map[string]*string
+
+	// faults holds the faults injected for resilience testing
+	faults faultInjector
+
+	// readiness holds the checks that must pass before the node is marked
+	// as ready
+	readiness *readinessGates
+
+	// migrateLimiter limits the bandwidth of the migrations of ephemeral
+	// disks from other clients. Nil if unlimited.
+	migrateLimiter *rate.Limiter
+
+	// rpcLimiter limits the bandwidth of the data sent to the servers over
+	// RPC connections. Nil if unlimited.
+	rpcLimiter *rate.Limiter
 }
 
 var (
@@ -311,6 +373,33 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		return nil, fmt.Errorf("failed to initialize client: %v", err)
 	}
 
+	// Setup the dynamic users of allocations
+	if cfg.DynamicUsers {
+		if os.Geteuid() != 0 {
+			return nil, fmt.Errorf("dynamic users require running the client as root")
+		}
+		pool, err := dynamicusers.NewPool(cfg.DynamicUserMinID, cfg.DynamicUserMaxID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup dynamic users: %v", err)
+		}
+		c.dynamicUsers = pool
+	}
+
+	// Enforce the ephemeral disk sizes when the alloc dirs support it
+	c.diskQuotas = diskquota.New(c.logger, c.config.AllocDirs())
+
+	// Limit the bandwidth of ephemeral disk migrations
+	if limit := cfg.MigrateBandwidthLimit; limit > 0 {
+		c.migrateLimiter = rate.NewLimiter(rate.Limit(limit), limit)
+	}
+
+	// Compress and limit the bandwidth of the RPC connections to the servers
+	if limit := cfg.RPCBandwidthLimit; limit > 0 {
+		c.rpcLimiter = rate.NewLimiter(rate.Limit(limit), limit)
+		c.connPool.SetBandwidthLimiter(c.rpcLimiter)
+	}
+	c.connPool.SetCompression(cfg.RPCCompression)
+
 	// Setup the clients RPC server
 	c.setupClientRpc()
 
@@ -330,6 +419,16 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 	c.configCopy = c.config.Copy()
 	c.configLock.Unlock()
 
+	// Setup the Nomad service provider client
+	c.nomadServices = nomadservices.NewServiceClient(c.logger, c, c.configCopy.Node, c.configCopy.Region)
+
+	// Setup the client of the external service providers
+	serviceProviders := make(map[string]externalservices.Provider, len(cfg.ServiceProviders))
+	for _, p := range cfg.ServiceProviders {
+		serviceProviders[p.Name] = externalservices.NewHTTPProvider(p.URL, p.Timeout)
+	}
+	c.externalServices = externalservices.NewServiceClient(c.logger, c.configCopy.Node, serviceProviders)
+
 	fingerprintManager := NewFingerprintManager(
 		c.configCopy.PluginSingletonLoader, c.GetConfig, c.configCopy.Node,
 		c.shutdownCh, c.updateNodeFromFingerprint, c.logger)
@@ -345,6 +444,12 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 	allowlistDrivers := cfg.ReadStringListToMap("driver.whitelist")
 	blocklistDrivers := cfg.ReadStringListToMap("driver.blacklist")
 
+	// Index the remediations of the drivers
+	driverRemediations := make(map[string]*config.DriverRemediation, len(cfg.DriverRemediations))
+	for _, r := range cfg.DriverRemediations {
+		driverRemediations[r.Driver] = r
+	}
+
 	// Setup the driver manager
 	driverConfig := &drivermanager.Config{
 		Logger:              c.logger,
@@ -355,6 +460,8 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		State:               c.stateDB,
 		AllowedDrivers:      allowlistDrivers,
 		BlockedDrivers:      blocklistDrivers,
+		Remediations:        driverRemediations,
+		TriggerNodeEvent:    c.triggerNodeEvent,
 	}
 	drvManager := drivermanager.New(driverConfig)
 	c.drivermanager = drvManager
@@ -416,6 +523,11 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		return nil, fmt.Errorf("failed to setup vault client: %v", err)
 	}
 
+	// Setup the service clients of the named Consul clusters
+	if err := c.setupConsulClusters(); err != nil {
+		return nil, fmt.Errorf("failed to setup consul clusters: %v", err)
+	}
+
 	// Restore the state
 	if err := c.restoreState(); err != nil {
 		logger.Error("failed to restore state", "error", err)
@@ -430,6 +542,13 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		return nil, fmt.Errorf("failed to restore state")
 	}
 
+	// Check the readiness gates, holding the node as initializing until
+	// they pass
+	c.readiness = newReadinessGates(c.logger, c.configCopy.ReadinessGates)
+	if !c.readiness.Passed() {
+		go c.runReadinessGates()
+	}
+
 	// Register and then start heartbeating to the servers.
 	c.shutdownGroup.Go(c.registerAndHeartbeat)
 
@@ -481,6 +600,13 @@ func (c *Client) init() error {
 	}
 	c.logger.Info("using state directory", "state_dir", c.config.StateDir)
 
+	// Load the node key encrypting the state
+	if c.config.DataEncryption != nil && !c.config.DevMode {
+		if err := c.setupKeyring(); err != nil {
+			return err
+		}
+	}
+
 	// Open the state database
 	db, err := c.config.StateDBFactory(c.logger, c.config.StateDir)
 	if err != nil {
@@ -497,6 +623,14 @@ func (c *Client) init() error {
 		return fmt.Errorf("failed to upgrade state database: %v", err)
 	}
 
+	// Encrypt the state database, including the state persisted before
+	// encryption was enabled
+	if c.keyring != nil {
+		if err := db.Encrypt(c.keyring); err != nil {
+			return fmt.Errorf("failed to encrypt state database: %v", err)
+		}
+	}
+
 	c.stateDB = db
 
 	// Ensure the alloc dir exists if we have one
@@ -524,7 +658,25 @@ func (c *Client) init() error {
 		c.config.AllocDir = p
 	}
 
+	// Ensure the alloc dirs of the namespaces and job types exist
+	for _, dir := range c.config.AllocDirs()[1:] {
+		if err := os.MkdirAll(dir, 0711); err != nil {
+			return fmt.Errorf("failed creating alloc dir: %s", err)
+		}
+	}
+
+	// Finish deleting the alloc dirs destroyed before a restart
+	for _, dir := range c.config.AllocDirs() {
+		allocdir.DeleteDestroyed(c.logger, dir)
+	}
+
 	c.logger.Info("using alloc directory", "alloc_dir", c.config.AllocDir)
+	for ns, dir := range c.config.NamespaceAllocDirs {
+		c.logger.Info("using namespace alloc directory", "namespace", ns, "alloc_dir", dir)
+	}
+	for jobType, dir := range c.config.JobTypeAllocDirs {
+		c.logger.Info("using job type alloc directory", "job_type", jobType, "alloc_dir", dir)
+	}
 	return nil
 }
 
@@ -636,6 +788,9 @@ func (c *Client) Shutdown() error {
 	if c.vaultClient != nil {
 		c.vaultClient.Stop()
 	}
+	for _, vc := range c.vaultClients {
+		vc.Stop()
+	}
 
 	// Stop Garbage collector
 	c.garbageCollector.Stop()
@@ -659,6 +814,13 @@ func (c *Client) Shutdown() error {
 	// Shutdown the plugin managers
 	c.pluginManagers.Shutdown()
 
+	// Deregister the services of the named Consul clusters
+	for name, sc := range c.consulServices {
+		if err := sc.Shutdown(); err != nil {
+			c.logger.Error("failed to shutdown consul service client", "consul_cluster", name, "error", err)
+		}
+	}
+
 	c.shutdown = true
 	close(c.shutdownCh)
 
@@ -688,9 +850,21 @@ func (c *Client) Stats() map[string]map[string]string {
 		},
 		"runtime": hstats.RuntimeStats(),
 	}
+	if gates := c.readiness.Stats(); len(gates) != 0 {
+		stats["readiness_gates"] = gates
+	}
 	return stats
 }
 
+// LastHeartbeat returns the time of the last successful heartbeat of the
+// client and the TTL before which it must heartbeat again. The time is zero
+// until the client registers.
+func (c *Client) LastHeartbeat() (time.Time, time.Duration) {
+	c.heartbeatLock.Lock()
+	defer c.heartbeatLock.Unlock()
+	return c.lastHeartbeat, c.heartbeatTTL
+}
+
 // CollectAllocation garbage collects a single allocation on a node. Returns
 // true if alloc was found and garbage collected; otherwise false.
 func (c *Client) CollectAllocation(allocID string) bool {
@@ -703,6 +877,87 @@ func (c *Client) CollectAllAllocs() {
 	c.garbageCollector.CollectAll()
 }
 
+// RestartAllocation restarts the tasks of an allocation in place. If taskName
+// is set, only that task is restarted. If ordered is set, the tasks are
+// restarted one at a time, the leader task last. If waitHealthy is set, it
+// then blocks until the allocation is healthy or its healthy deadline is
+// reached and returns whether it became healthy.
+func (c *Client) RestartAllocation(allocID, taskName string, ordered, waitHealthy bool) (bool, error) {
+	if ordered && taskName != "" {
+		return false, fmt.Errorf("ordered restarts restart all the tasks of the allocation")
+	}
+
+	ar, err := c.getAllocRunner(allocID)
+	if err != nil {
+		return false, err
+	}
+
+	restartedAt := time.Now()
+	event := structs.NewTaskEvent(structs.TaskRestartSignal).
+		SetRestartReason("User requested restart")
+	switch {
+	case taskName != "":
+		err = ar.RestartTask(taskName, event)
+	case ordered:
+		err = ar.RestartOrdered(event)
+	default:
+		err = ar.RestartAll(event)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !waitHealthy {
+		return false, nil
+	}
+	return ar.WaitHealthy(context.Background(), restartedAt), nil
+}
+
+// RunAction runs the task's action inside the running allocation and returns
+// the action's output and exit code.
+func (c *Client) RunAction(allocID, taskName, actionName string) ([]byte, int, error) {
+	ar, err := c.getAllocRunner(allocID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	alloc := ar.Alloc()
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil, 0, fmt.Errorf("Unknown task group %q", alloc.TaskGroup)
+	}
+	task := tg.LookupTask(taskName)
+	if task == nil {
+		return nil, 0, fmt.Errorf("Unknown task %q", taskName)
+	}
+	action := task.LookupAction(actionName)
+	if action == nil {
+		return nil, 0, fmt.Errorf("Task %q has no action %q", taskName, actionName)
+	}
+
+	c.logger.Info("running action", "alloc_id", allocID, "task", taskName, "action", actionName)
+	return ar.ExecTask(taskName, action.Timeout, action.Command, action.Args)
+}
+
+// SignalAllocation sends the signal to the running tasks of an allocation. If
+// taskName is set, only that task is signaled.
+func (c *Client) SignalAllocation(allocID, taskName, signal string) error {
+	ar, err := c.getAllocRunner(allocID)
+	if err != nil {
+		return err
+	}
+
+	sig, err := signals.Parse(signal)
+	if err != nil {
+		return err
+	}
+
+	event := structs.NewTaskEvent(structs.TaskSignaling).
+		SetTaskSignal(sig).
+		SetTaskSignalReason("User requested signal")
+	return ar.Signal(taskName, event, strings.ToUpper(signal))
+}
+
 // Node returns the locally registered node
 func (c *Client) Node() *structs.Node {
 	c.configLock.RLock()
@@ -710,6 +965,80 @@ func (c *Client) Node() *structs.Node {
 	return c.configCopy.Node
 }
 
+// NodeMeta returns the node's metadata along with the subset of it that was
+// set at runtime. Keys of the dynamic metadata with a nil value were unset.
+func (c *Client) NodeMeta() (map[string]string, map[string]*string) {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+	return helper.CopyMapStringString(c.config.Node.Meta), copyNodeMeta(c.dynamicMeta)
+}
+
+// UpdateNodeMeta sets the node's metadata at runtime. Keys with a nil value
+// are unset. The metadata is persisted so it is restored when the client
+// restarts and the node is re-registered so the new metadata can be used in
+// constraints immediately. The node's resulting metadata is returned.
+func (c *Client) UpdateNodeMeta(updates map[string]*string) (map[string]string, map[string]*string, error) {
+	c.configLock.Lock()
+	dynamicMeta := copyNodeMeta(c.dynamicMeta)
+	if dynamicMeta == nil {
+		dynamicMeta = make(map[string]*string, len(updates))
+	}
+	for k, v := range updates {
+		dynamicMeta[k] = v
+	}
+
+	if err := c.stateDB.PutNodeMeta(dynamicMeta); err != nil {
+		c.configLock.Unlock()
+		return nil, nil, fmt.Errorf("failed to persist node metadata: %v", err)
+	}
+
+	c.dynamicMeta = dynamicMeta
+	applyNodeMeta(c.config.Node.Meta, updates)
+	c.updateNodeLocked()
+	meta := helper.CopyMapStringString(c.config.Node.Meta)
+	c.configLock.Unlock()
+
+	// Register the node immediately rather than waiting for the batched node
+	// update, unless the node has not completed its initial registration.
+	select {
+	case <-c.fpInitialized:
+		if err := c.registerNode(); err != nil {
+			c.logger.Warn("failed to register node after updating metadata; will retry", "error", err)
+		}
+	default:
+	}
+
+	return meta, copyNodeMeta(dynamicMeta), nil
+}
+
+// applyNodeMeta applies the updates to the node's metadata, deleting the keys
+// with a nil value.
+func applyNodeMeta(meta map[string]string, updates map[string]*string) {
+	for k, v := range updates {
+		if v == nil {
+			delete(meta, k)
+			continue
+		}
+		meta[k] = *v
+	}
+}
+
+// copyNodeMeta returns a copy of the dynamic node metadata.
+func copyNodeMeta(meta map[string]*string) map[string]*string {
+	if meta == nil {
+		return nil
+	}
+
+	c := make(map[string]*string, len(meta))
+	for k, v := range meta {
+		if v != nil {
+			v = helper.StringToPtr(*v)
+		}
+		c[k] = v
+	}
+	return c
+}
+
 func (c *Client) getAllocRunner(allocID string) (AllocRunner, error) {
 	c.allocLock.RLock()
 	defer c.allocLock.RUnlock()
@@ -952,12 +1281,19 @@ func (c *Client) restoreState() error {
 			StateDB:             c.stateDB,
 			StateUpdater:        c,
 			DeviceStatsReporter: c,
-			Consul:              c.consulService,
+			Consul:              c.consulServiceFor(alloc),
 			Vault:               c.vaultClient,
+			VaultClusters:       c.vaultClients,
 			PrevAllocWatcher:    prevAllocWatcher,
 			PrevAllocMigrator:   prevAllocMigrator,
 			DeviceManager:       c.devicemanager,
 			DriverManager:       c.drivermanager,
+			RPCClient:           c,
+			NomadServices:       c.nomadServices,
+			ExternalServices:    c.externalServices,
+			DynamicUsers:        c.dynamicUsers,
+			DiskQuotas:          c.diskQuotas,
+			Keyring:             c.keyring,
 		}
 		c.configLock.RUnlock()
 
@@ -1053,6 +1389,28 @@ func (c *Client) NumAllocs() int {
 	return n
 }
 
+// setupKeyring loads the node key encrypting the state of the client,
+// generating it on the first start.
+func (c *Client) setupKeyring() error {
+	var wrapper keyring.Wrapper
+	if conf := c.config.DataEncryption; conf.VaultTransitKey != "" {
+		w, err := keyring.NewVaultTransitWrapper(conf.VaultAddress, conf.VaultToken, conf.VaultTransitMount, conf.VaultTransitKey)
+		if err != nil {
+			return fmt.Errorf("failed to setup node key wrapper: %v", err)
+		}
+		wrapper = w
+	} else {
+		c.logger.Warn("data encryption is enabled without a key wrapper, the node key is stored in plaintext in the state directory")
+	}
+
+	kr, err := keyring.Load(c.config.StateDir, wrapper)
+	if err != nil {
+		return fmt.Errorf("failed to load node key: %v", err)
+	}
+	c.keyring = kr
+	return nil
+}
+
 // nodeID restores, or generates if necessary, a unique node ID and SecretID.
 // The node ID is, if available, a persistent unique ID.  The secret ID is a
 // high-entropy random UUID.
@@ -1083,9 +1441,14 @@ func (c *Client) nodeID() (id, secret string, err error) {
 		return "", "", err
 	}
 
-	// Attempt to read existing secret ID
+	// Attempt to read existing secret ID, which is encrypted if data
+	// encryption is enabled
 	secretPath := filepath.Join(c.config.StateDir, "secret-id")
-	secretBuf, err := ioutil.ReadFile(secretPath)
+	readSecret, writeSecret := ioutil.ReadFile, ioutil.WriteFile
+	if c.keyring != nil {
+		readSecret, writeSecret = c.keyring.ReadFile, c.keyring.WriteFile
+	}
+	secretBuf, err := readSecret(secretPath)
 	if err != nil && !os.IsNotExist(err) {
 		return "", "", err
 	}
@@ -1104,12 +1467,19 @@ func (c *Client) nodeID() (id, secret string, err error) {
 
 	if len(secretBuf) != 0 {
 		secret = string(secretBuf)
+
+		// Encrypt a secret ID persisted before encryption was enabled
+		if c.keyring != nil && !keyring.IsEncryptedFile(secretPath) {
+			if err := writeSecret(secretPath, secretBuf, 0700); err != nil {
+				return "", "", err
+			}
+		}
 	} else {
 		// Generate new ID
 		secret = uuid.Generate()
 
 		// Persist the ID
-		if err := ioutil.WriteFile(secretPath, []byte(secret), 0700); err != nil {
+		if err := writeSecret(secretPath, []byte(secret), 0700); err != nil {
 			return "", "", err
 		}
 	}
@@ -1144,6 +1514,21 @@ func (c *Client) setupNode() error {
 	if node.Meta == nil {
 		node.Meta = make(map[string]string)
 	}
+
+	// Advertise the external service providers so jobs can be constrained
+	// to the nodes that can register their services
+	for _, p := range c.config.ServiceProviders {
+		node.Attributes["service_provider."+p.Name] = "true"
+	}
+
+	// Restore the metadata set at runtime over the configured metadata
+	if dynamicMeta, err := c.stateDB.GetNodeMeta(); err != nil {
+		c.logger.Error("failed to restore node metadata set at runtime", "error", err)
+	} else {
+		applyNodeMeta(node.Meta, dynamicMeta)
+		c.dynamicMeta = dynamicMeta
+	}
+
 	if node.NodeResources == nil {
 		node.NodeResources = &structs.NodeResources{}
 	}
@@ -1273,6 +1658,12 @@ func (c *Client) registerAndHeartbeat() {
 	// Start watching for emitting node events
 	go c.watchNodeEvents()
 
+	// Start prefetching the images and artifacts of the jobs the node can
+	// run
+	if !c.config.DisablePrefetch {
+		go c.watchPrefetches()
+	}
+
 	// Setup the heartbeat timer, for the initial registration
 	// we want to do this quickly. We want to do it extra quickly
 	// in development mode.
@@ -1283,10 +1674,19 @@ func (c *Client) registerAndHeartbeat() {
 		heartbeat = time.After(lib.RandomStagger(initialHeartbeatStagger))
 	}
 
+	// Heartbeat as soon as the readiness gates pass to mark the node as
+	// ready
+	var readinessPassed <-chan struct{}
+	if !c.readiness.Passed() {
+		readinessPassed = c.readiness.PassedCh()
+	}
+
 	for {
 		select {
 		case <-c.rpcRetryWatcher():
 		case <-heartbeat:
+		case <-readinessPassed:
+			readinessPassed = nil
 		case <-c.shutdownCh:
 			return
 		}
@@ -1309,7 +1709,7 @@ func (c *Client) registerAndHeartbeat() {
 			}
 		} else {
 			c.heartbeatLock.Lock()
-			heartbeat = time.After(c.heartbeatTTL)
+			heartbeat = time.After(c.heartbeatTTL + c.faults.HeartbeatDelay())
 			c.heartbeatLock.Unlock()
 		}
 	}
@@ -1518,10 +1918,12 @@ func (c *Client) registerNode() error {
 		return err
 	}
 
-	// Update the node status to ready after we register.
+	// Update the node status to ready after we register, unless the
+	// readiness gates are pending.
+	status := c.nodeStatus()
 	c.configLock.Lock()
-	node.Status = structs.NodeStatusReady
-	c.config.Node.Status = structs.NodeStatusReady
+	node.Status = status
+	c.config.Node.Status = status
 	c.configLock.Unlock()
 
 	c.logger.Info("node registration complete")
@@ -1541,7 +1943,7 @@ func (c *Client) updateNodeStatus() error {
 	start := time.Now()
 	req := structs.NodeUpdateStatusRequest{
 		NodeID:       c.NodeID(),
-		Status:       structs.NodeStatusReady,
+		Status:       c.nodeStatus(),
 		WriteRequest: structs.WriteRequest{Region: c.Region()},
 	}
 	var resp structs.NodeUpdateResponse
@@ -1551,6 +1953,15 @@ func (c *Client) updateNodeStatus() error {
 	}
 	end := time.Now()
 
+	// Keep the status of the registered node in sync once the readiness
+	// gates passed
+	c.configLock.Lock()
+	if c.config.Node.Status != req.Status {
+		c.config.Node.Status = req.Status
+		c.configCopy.Node.Status = req.Status
+	}
+	c.configLock.Unlock()
+
 	if len(resp.EvalIDs) != 0 {
 		c.logger.Debug("evaluations triggered by node update", "num_evals", len(resp.EvalIDs))
 	}
@@ -1573,6 +1984,10 @@ func (c *Client) updateNodeStatus() error {
 		if haveHeartbeated {
 			c.logger.Warn("missed heartbeat",
 				"req_latency", end.Sub(start), "heartbeat_ttl", oldTTL, "since_last_heartbeat", time.Since(last))
+
+			// The servers may have marked our allocations as unknown while
+			// we were disconnected, so report their state again
+			go c.resyncAllocStates()
 		}
 	}
 
@@ -1642,6 +2057,21 @@ func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
 	}
 }
 
+// resyncAllocStates sends the state of every allocation to the servers. It is
+// used after the node missed its heartbeats, since the servers mark the
+// allocations of disconnected nodes as unknown.
+func (c *Client) resyncAllocStates() {
+	for _, ar := range c.getAllocRunners() {
+		state := ar.AllocState()
+		alloc := ar.Alloc().Copy()
+		alloc.TaskStates = state.TaskStates
+		alloc.ClientStatus = state.ClientStatus
+		alloc.ClientDescription = state.ClientDescription
+		alloc.DeploymentStatus = state.DeploymentStatus
+		c.AllocStateUpdated(alloc)
+	}
+}
+
 // allocSync is a long lived function that batches allocation updates to the
 // server.
 func (c *Client) allocSync() {
@@ -2077,6 +2507,11 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		return nil
 	}
 
+	// Fail the allocation if allocation failures are injected
+	if err := c.faults.failAllocation(); err != nil {
+		return err
+	}
+
 	// Initialize local copy of alloc before creating the alloc runner so
 	// we can't end up with an alloc runner that does not have an alloc.
 	if err := c.stateDB.PutAllocation(alloc); err != nil {
@@ -2101,6 +2536,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		RPC:              c,
 		Config:           c.configCopy,
 		MigrateToken:     migrateToken,
+		MigrateLimiter:   c.migrateLimiter,
 		Logger:           c.logger,
 	}
 	prevAllocWatcher, prevAllocMigrator := allocwatcher.NewAllocWatcher(watcherConfig)
@@ -2114,14 +2550,21 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 		Logger:              c.logger,
 		ClientConfig:        c.configCopy,
 		StateDB:             c.stateDB,
-		Consul:              c.consulService,
+		Consul:              c.consulServiceFor(alloc),
 		Vault:               c.vaultClient,
+		VaultClusters:       c.vaultClients,
 		StateUpdater:        c,
 		DeviceStatsReporter: c,
 		PrevAllocWatcher:    prevAllocWatcher,
 		PrevAllocMigrator:   prevAllocMigrator,
 		DeviceManager:       c.devicemanager,
 		DriverManager:       c.drivermanager,
+		RPCClient:           c,
+		NomadServices:       c.nomadServices,
+		ExternalServices:    c.externalServices,
+		DynamicUsers:        c.dynamicUsers,
+		DiskQuotas:          c.diskQuotas,
+		Keyring:             c.keyring,
 	}
 	c.configLock.RUnlock()
 
@@ -2154,9 +2597,57 @@ func (c *Client) setupVaultClient() error {
 	// Start renewing tokens and secrets
 	c.vaultClient.Start()
 
+	for _, vconf := range c.config.VaultClusters {
+		vc, err := vaultclient.NewVaultClient(vconf, c.logger.With("vault_cluster", vconf.Name), c.deriveToken)
+		if err != nil {
+			return fmt.Errorf("vault cluster %q: %v", vconf.Name, err)
+		}
+		if c.vaultClients == nil {
+			c.vaultClients = make(map[string]vaultclient.VaultClient)
+		}
+		c.vaultClients[vconf.Name] = vc
+		vc.Start()
+	}
+
+	return nil
+}
+
+// setupConsulClusters creates the service clients registering the services
+// of the groups using the named Consul clusters.
+func (c *Client) setupConsulClusters() error {
+	for _, cconf := range c.config.ConsulClusters {
+		apiConf, err := cconf.ApiConfig()
+		if err != nil {
+			return fmt.Errorf("consul cluster %q: %v", cconf.Name, err)
+		}
+		client, err := consulapi.NewClient(apiConf)
+		if err != nil {
+			return fmt.Errorf("consul cluster %q: %v", cconf.Name, err)
+		}
+
+		sc := consul.NewServiceClient(client.Agent(), c.logger.With("consul_cluster", cconf.Name), true)
+		if c.consulServices == nil {
+			c.consulServices = make(map[string]*consul.ServiceClient)
+		}
+		c.consulServices[cconf.Name] = sc
+		go sc.Run()
+	}
 	return nil
 }
 
+// consulServiceFor returns the service client of the Consul cluster used by
+// the allocation's group.
+func (c *Client) consulServiceFor(alloc *structs.Allocation) consulApi.ConsulServiceAPI {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return c.consulService
+	}
+	if sc, ok := c.consulServices[tg.ConsulCluster()]; ok {
+		return sc
+	}
+	return c.consulService
+}
+
 // deriveToken takes in an allocation and a set of tasks and derives vault
 // tokens for each of the tasks, unwraps all of them using the supplied vault
 // client and returns a map of unwrapped tokens, indexed by the task name.
@@ -2191,6 +2682,19 @@ func (c *Client) deriveToken(alloc *structs.Allocation, taskNames []string, vcli
 		verifiedTasks = append(verifiedTasks, taskName)
 	}
 
+	// The tasks of a request share the Vault cluster of the client
+	// deriving their tokens
+	cluster := taskVaultCluster(group, verifiedTasks[0])
+	vaultConf := c.config.VaultClusterConfig(cluster)
+	if vaultConf == nil {
+		return nil, fmt.Errorf("Vault cluster %q not configured", cluster)
+	}
+
+	// Tasks log in to Vault themselves when they use workload identities
+	if vaultConf.UsesIdentity() {
+		return c.deriveTokenWithIdentity(alloc, group, verifiedTasks, vclient, vaultConf)
+	}
+
 	// DeriveVaultToken of nomad server can take in a set of tasks and
 	// creates tokens for all the tasks.
 	req := &structs.DeriveVaultTokenRequest{
@@ -2230,7 +2734,8 @@ func (c *Client) deriveToken(alloc *structs.Allocation, taskNames []string, vcli
 			return nil, fmt.Errorf("wrapped token missing for task %q", taskName)
 		}
 
-		// Unwrap the vault token
+		// Unwrap the vault token in the namespace it was created in
+		vaultclient.SetNamespace(vclient, taskVaultNamespace(group, taskName))
 		unwrapResp, err := vclient.Logical().Unwrap(wrappedToken)
 		if err != nil {
 			if structs.VaultUnrecoverableError.MatchString(err.Error()) {
@@ -2263,6 +2768,96 @@ func (c *Client) deriveToken(alloc *structs.Allocation, taskNames []string, vcli
 	return unwrappedTokens, nil
 }
 
+// deriveTokenWithIdentity requests the workload identities of the tasks from
+// the servers and exchanges them for Vault tokens with the Vault JWT auth
+// method.
+func (c *Client) deriveTokenWithIdentity(alloc *structs.Allocation, group *structs.TaskGroup, taskNames []string, vclient *vaultapi.Client, vaultConf *nconfig.VaultConfig) (map[string]string, error) {
+	vlogger := c.logger.Named("vault")
+
+	req := &structs.SignIdentitiesRequest{
+		NodeID:   c.NodeID(),
+		SecretID: c.secretNodeID(),
+		AllocID:  alloc.ID,
+		Tasks:    taskNames,
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AllowStale: false,
+		},
+	}
+
+	var resp structs.SignIdentitiesResponse
+	if err := c.RPC("Node.SignIdentities", &req, &resp); err != nil {
+		vlogger.Error("error making sign identities RPC", "error", err)
+		return nil, fmt.Errorf("SignIdentities RPC failed: %v", err)
+	}
+	if resp.Error != nil {
+		vlogger.Error("error signing workload identities", "error", resp.Error)
+		return nil, structs.NewWrappedServerError(resp.Error)
+	}
+
+	authPath := vaultConf.JWTAuthPath
+	if authPath == "" {
+		authPath = nconfig.DefaultVaultJWTAuthPath
+	}
+	loginPath := fmt.Sprintf("auth/%s/login", strings.Trim(authPath, "/"))
+
+	tokens := make(map[string]string, len(taskNames))
+	for _, taskName := range taskNames {
+		jwt, ok := resp.Tasks[taskName]
+		if !ok {
+			vlogger.Error("workload identity missing for task", "task_name", taskName)
+			return nil, fmt.Errorf("workload identity missing for task %q", taskName)
+		}
+
+		data := map[string]interface{}{"jwt": jwt}
+		if role := vaultConf.JWTAuthRole; role != "" {
+			data["role"] = role
+		}
+
+		// Log in to the auth method of the task's namespace
+		vaultclient.SetNamespace(vclient, taskVaultNamespace(group, taskName))
+
+		secret, err := vclient.Logical().Write(loginPath, data)
+		if err != nil {
+			if structs.VaultUnrecoverableError.MatchString(err.Error()) {
+				return nil, err
+			}
+
+			// The error is recoverable
+			return nil, structs.NewRecoverableError(
+				fmt.Errorf("failed to log in to Vault for task %q: %v", taskName, err), true)
+		}
+
+		if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+			err := fmt.Errorf("Vault returned no token when logging in for task %q", taskName)
+			vlogger.Warn("error logging in with workload identity", "error", err)
+			return nil, structs.NewRecoverableError(err, true)
+		}
+
+		tokens[taskName] = secret.Auth.ClientToken
+	}
+
+	return tokens, nil
+}
+
+// taskVaultNamespace returns the Vault namespace the task's token lives in.
+func taskVaultNamespace(group *structs.TaskGroup, taskName string) string {
+	task := group.LookupTask(taskName)
+	if task == nil || task.Vault == nil {
+		return ""
+	}
+	return task.Vault.Namespace
+}
+
+// taskVaultCluster returns the Vault cluster the task's token comes from.
+func taskVaultCluster(group *structs.TaskGroup, taskName string) string {
+	task := group.LookupTask(taskName)
+	if task == nil {
+		return structs.VaultDefaultCluster
+	}
+	return task.Vault.GetCluster()
+}
+
 // triggerDiscovery causes a Consul discovery to begin (if one hasn't already)
 func (c *Client) triggerDiscovery() {
 	select {
@@ -2583,6 +3178,25 @@ func (c *Client) setGaugeForAllocationStats(nodeID string) {
 }
 
 // No labels are required so we emit with only a key/value syntax
+// setGaugeForPressureStats proxies metrics for the pressure stall information
+// of the host
+func (c *Client) setGaugeForPressureStats(hStats *stats.HostStats) {
+	if hStats.PressureStats == nil || c.config.DisableTaggedMetrics {
+		return
+	}
+
+	for resource, p := range hStats.PressureStats.Resources() {
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "some_avg10"}, float32(p.SomeAvg10), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "some_avg60"}, float32(p.SomeAvg60), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "some_avg300"}, float32(p.SomeAvg300), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "some_total"}, float32(p.SomeTotal), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "full_avg10"}, float32(p.FullAvg10), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "full_avg60"}, float32(p.FullAvg60), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "full_avg300"}, float32(p.FullAvg300), c.baseLabels)
+		metrics.SetGaugeWithLabels([]string{"client", "host", "pressure", resource, "full_total"}, float32(p.FullTotal), c.baseLabels)
+	}
+}
+
 func (c *Client) setGaugeForUptime(hStats *stats.HostStats) {
 	if !c.config.DisableTaggedMetrics {
 		metrics.SetGaugeWithLabels([]string{"client", "uptime"}, float32(hStats.Uptime), c.baseLabels)
@@ -2601,6 +3215,7 @@ func (c *Client) emitHostStats() {
 	c.setGaugeForUptime(hStats)
 	c.setGaugeForCPUStats(nodeID, hStats)
 	c.setGaugeForDiskStats(nodeID, hStats)
+	c.setGaugeForPressureStats(hStats)
 }
 
 // emitClientMetrics emits lower volume client metrics
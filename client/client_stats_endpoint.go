@@ -4,6 +4,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/stats"
 	"github.com/hashicorp/nomad/client/structs"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
 )
@@ -28,3 +29,23 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	reply.HostStats = clientStats.LatestHostStats()
 	return nil
 }
+
+// Hardware is used to retrieve the Clients hardware inventory.
+func (s *ClientStats) Hardware(args *nstructs.NodeSpecificRequest, reply *structs.ClientHardwareResponse) error {
+	defer metrics.MeasureSince([]string{"client", "client_stats", "hardware"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := s.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	hardware, err := stats.CollectHardwareInventory()
+	if err != nil {
+		return err
+	}
+
+	reply.Hardware = hardware
+	return nil
+}
@@ -84,3 +84,16 @@ func TestClientStats_Stats_ACL(t *testing.T) {
 		require.NotNil(resp.HostStats)
 	}
 }
+
+func TestClientStats_Hardware(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	req := &nstructs.NodeSpecificRequest{}
+	var resp structs.ClientHardwareResponse
+	require.Nil(client.ClientRPC("ClientStats.Hardware", &req, &resp))
+	require.NotNil(resp.Hardware)
+	require.NotEmpty(resp.Hardware.CPUs)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/client/keyring"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/helper/pluginutils/catalog"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -98,6 +99,28 @@ func TestClient_RPC(t *testing.T) {
 	})
 }
 
+func TestClient_RPC_Compressed(t *testing.T) {
+	t.Parallel()
+	s1, addr := testServer(t, nil)
+	defer s1.Shutdown()
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.RPCCompression = true
+		c.RPCBandwidthLimit = 1024 * 1024
+	})
+	defer cleanup()
+
+	// RPC should succeed
+	testutil.WaitForResult(func() (bool, error) {
+		var out struct{}
+		err := c1.RPC("Status.Ping", struct{}{}, &out)
+		return err == nil, err
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 func TestClient_RPC_FireRetryWatchers(t *testing.T) {
 	t.Parallel()
 	s1, addr := testServer(t, nil)
@@ -634,6 +657,35 @@ func TestClient_SaveRestoreState(t *testing.T) {
 	}
 }
 
+func TestClient_DataEncryption(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.DevMode = false
+		c.DataEncryption = &config.DataEncryption{}
+	})
+	defer cleanup()
+
+	// The node key is generated and encrypts the secret ID
+	require.FileExists(filepath.Join(c1.config.StateDir, "node-key"))
+	require.True(keyring.IsEncryptedFile(filepath.Join(c1.config.StateDir, "secret-id")))
+	secretID := c1.Node().SecretID
+
+	require.NoError(c1.Shutdown())
+
+	// A new client reads the encrypted state
+	logger := testlog.HCLogger(t)
+	c1.config.Logger = logger
+	catalog := consul.NewMockCatalog(logger)
+	mockService := consulApi.NewMockConsulServiceClient(t, logger)
+
+	c2, err := NewClient(c1.config, catalog, mockService)
+	require.NoError(err)
+	defer c2.Shutdown()
+	require.Equal(secretID, c2.Node().SecretID)
+}
+
 func TestClient_RestoreError(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1162,6 +1214,15 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 	client, cleanup := TestClient(t, func(c *config.Config) {})
 	defer cleanup()
 
+	// The cores are fingerprinted during test client initialization and
+	// kept when the CPU shares are overridden
+	fingerprintedCpu := client.configCopy.Node.NodeResources.Cpu
+	expectedCpu := structs.NodeCpuResources{
+		CpuShares:          123,
+		TotalCpuCores:      fingerprintedCpu.TotalCpuCores,
+		ReservableCpuCores: fingerprintedCpu.ReservableCpuCores,
+	}
+
 	client.updateNodeFromFingerprint(&fingerprint.FingerprintResponse{
 		NodeResources: &structs.NodeResources{
 			Cpu: structs.NodeCpuResources{CpuShares: 123},
@@ -1188,7 +1249,7 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 		Disk:     client.configCopy.Node.NodeResources.Disk,
 
 		// injected
-		Cpu:    structs.NodeCpuResources{CpuShares: 123},
+		Cpu:    expectedCpu,
 		Memory: structs.NodeMemoryResources{MemoryMB: 1024},
 		Devices: []*structs.NodeDeviceResource{
 			{
@@ -1225,7 +1286,7 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 		Disk:     client.configCopy.Node.NodeResources.Disk,
 
 		// injected
-		Cpu:    structs.NodeCpuResources{CpuShares: 123},
+		Cpu:    expectedCpu,
 		Memory: structs.NodeMemoryResources{MemoryMB: 2048},
 		Devices: []*structs.NodeDeviceResource{
 			{
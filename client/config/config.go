@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -124,10 +127,76 @@ type Config struct {
 	// communicating with plugin subsystems over loopback
 	ClientMinPort uint
 
+	// DynamicUsers runs the tasks of each allocation as a user and group
+	// unique to the allocation, which owns the allocation's directories. Only
+	// allocations whose tasks all use the exec or raw_exec drivers without
+	// setting a user are assigned a dynamic user.
+	DynamicUsers bool
+
+	// DynamicUserMinID is the lower range of the user and group IDs assigned
+	// to allocations
+	DynamicUserMinID int
+
+	// DynamicUserMaxID is the upper range of the user and group IDs assigned
+	// to allocations
+	DynamicUserMaxID int
+
+	// DisablePrefetch disables fetching the images and artifacts of the jobs
+	// with a prefetch hint ahead of their placements
+	DisablePrefetch bool
+
+	// MigrateBandwidthLimit is the number of bytes per second the client
+	// uses to migrate the ephemeral disks of allocations from other clients.
+	// Zero if unlimited.
+	MigrateBandwidthLimit int
+
+	// RPCCompression enables the compression of the RPC connections to the
+	// servers.
+	RPCCompression bool
+
+	// EnableFaultInjection enables the fault injection endpoints used for
+	// resilience testing.
+	EnableFaultInjection bool
+
+	// RPCBandwidthLimit is the number of bytes per second the client sends
+	// to the servers over its RPC connections. Zero if unlimited.
+	RPCBandwidthLimit int
+
+	// RealtimeRuntime is the CPU time per second that each task using the
+	// real-time scheduling policies may run real-time threads. Zero disables
+	// real-time scheduling.
+	RealtimeRuntime time.Duration
+
+	// ReadinessGates are the checks that must pass before the node is
+	// marked as ready.
+	ReadinessGates []*ReadinessGate
+
+	// DriverRemediations configure how drivers are remediated when their
+	// fingerprint becomes unhealthy.
+	DriverRemediations []*DriverRemediation
+
+	// ServiceProviders are the external service providers the services of
+	// tasks can be registered with.
+	ServiceProviders []*ServiceProvider
+
+	// DataEncryption, if set, encrypts the state the client persists in its
+	// state directory with a node key.
+	DataEncryption *DataEncryption
+
 	// A mapping of directories on the host OS to attempt to embed inside each
 	// task's chroot.
 	ChrootEnv map[string]string
 
+	// NamespaceAllocDirs maps namespaces to the directories storing the data
+	// of their allocations instead of AllocDir. They can be on separate
+	// filesystems to keep noisy tenants off the storage of the others.
+	NamespaceAllocDirs map[string]string
+
+	// JobTypeAllocDirs maps job types to the directories storing the data of
+	// their allocations, for the allocations whose namespace isn't in
+	// NamespaceAllocDirs.
+	JobTypeAllocDirs map[string]string
+
 	// Options provides arbitrary key-value configuration for nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -143,6 +212,15 @@ type Config struct {
 	// VaultConfig is this Agent's Vault configuration
 	VaultConfig *config.VaultConfig
 
+	// ConsulClusters are the named Consul clusters the services of task
+	// groups may be registered in instead of the default cluster configured
+	// by ConsulConfig.
+	ConsulClusters []*config.ConsulConfig
+
+	// VaultClusters are the named Vault clusters tasks may use instead of
+	// the default cluster configured by VaultConfig.
+	VaultClusters []*config.VaultConfig
+
 	// StatsCollectionInterval is the interval at which the Nomad client
 	// collects resource usage stats
 	StatsCollectionInterval time.Duration
@@ -178,6 +256,11 @@ type Config struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int
 
+	// MaxTaskEvents is the maximum number of events retained per task. Once
+	// reached, the earliest events of the task are kept and the oldest of
+	// the later events are dropped.
+	MaxTaskEvents int
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
@@ -220,17 +303,198 @@ type Config struct {
 	StateDBFactory state.NewStateDBFunc
 }
 
+// ReadinessGate is a check that must pass before the node is marked as ready,
+// so that no work is placed on the node before it can run it. The check either
+// runs Command, passing if it exits successfully, or queries URL, passing on a
+// 2xx response.
+type ReadinessGate struct {
+	Name    string
+	Command string
+	Args    []string
+	URL     string
+
+	// Interval is how often the check is retried until it passes, and
+	// Timeout how long a single check may take. Defaults are used if zero.
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// Validate returns an error if the readiness gate is invalid.
+func (g *ReadinessGate) Validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if (g.Command == "") == (g.URL == "") {
+		return fmt.Errorf("exactly one of command or url must be set")
+	}
+	if g.Command == "" && len(g.Args) != 0 {
+		return fmt.Errorf("args require a command")
+	}
+	if g.URL != "" && !strings.HasPrefix(g.URL, "http://") && !strings.HasPrefix(g.URL, "https://") {
+		return fmt.Errorf("url must use the http or https scheme")
+	}
+	if g.Interval < 0 {
+		return fmt.Errorf("interval must not be negative")
+	}
+	if g.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
+
+// DriverRemediation configures the remediation of a driver whose fingerprint
+// became unhealthy, such as the docker driver after dockerd crashed. Until the
+// driver is healthy again, Command is run if set and the driver is then
+// fingerprinted again after a backoff, so that it is placed on again as soon
+// as it recovered rather than at its next periodic fingerprint.
+type DriverRemediation struct {
+	Driver  string
+	Command string
+	Args    []string
+
+	// Timeout is how long a single run of the command may take.
+	Timeout time.Duration
+
+	// Backoff is the delay before the first fingerprint, doubling after
+	// each attempt up to BackoffLimit. Defaults are used if zero.
+	Backoff      time.Duration
+	BackoffLimit time.Duration
+}
+
+// Validate returns an error if the driver remediation is invalid.
+func (r *DriverRemediation) Validate() error {
+	if r.Driver == "" {
+		return fmt.Errorf("missing driver")
+	}
+	if r.Command == "" && len(r.Args) != 0 {
+		return fmt.Errorf("args require a command")
+	}
+	if r.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	if r.Backoff < 0 || r.BackoffLimit < 0 {
+		return fmt.Errorf("backoff must not be negative")
+	}
+	if r.Backoff != 0 && r.BackoffLimit != 0 && r.BackoffLimit < r.Backoff {
+		return fmt.Errorf("backoff_limit must not be less than backoff")
+	}
+	return nil
+}
+
+// validServiceProviderName matches the names of external service providers,
+// which services use as their provider.
+var validServiceProviderName = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// ServiceProvider is an external service provider, such as a load balancer,
+// that the services of tasks using it as their provider are pushed to once
+// their checks pass. Targets are registered with a PUT request to
+// <URL>/<target ID> and deregistered with a DELETE request to the same URL.
+type ServiceProvider struct {
+	Name string
+	URL  string
+
+	// Timeout is how long a single request may take. A default is used if
+	// zero.
+	Timeout time.Duration
+}
+
+// Validate returns an error if the service provider is invalid.
+func (p *ServiceProvider) Validate() error {
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("missing name")
+	case p.Name == structs.ServiceProviderConsul || p.Name == structs.ServiceProviderNomad:
+		return fmt.Errorf("name %q is reserved", p.Name)
+	case !validServiceProviderName.MatchString(p.Name):
+		return fmt.Errorf("name may only contain alphanumeric characters, dashes and underscores")
+	}
+	if !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+		return fmt.Errorf("url must use the http or https scheme")
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
+
+// DataEncryption configures the encryption of the client state with a node
+// key. The node key is generated on the first start of the client and is
+// stored in the state directory, wrapped by a key of the Vault transit
+// secrets engine if VaultTransitKey is set.
+type DataEncryption struct {
+	// VaultTransitKey is the name of the transit key wrapping the node key
+	// and VaultTransitMount the mount path of the transit secrets engine.
+	VaultTransitKey   string
+	VaultTransitMount string
+
+	// VaultAddress and VaultToken are the address of Vault and the token
+	// used to encrypt and decrypt with the transit key. They default to the
+	// VAULT_ADDR and VAULT_TOKEN environment variables.
+	VaultAddress string
+	VaultToken   string
+}
+
+// Validate returns an error if the data encryption config is invalid.
+func (e *DataEncryption) Validate() error {
+	if e.VaultTransitKey == "" && (e.VaultTransitMount != "" || e.VaultAddress != "" || e.VaultToken != "") {
+		return fmt.Errorf("vault_transit_key must be set to configure Vault")
+	}
+	return nil
+}
+
 func (c *Config) Copy() *Config {
 	nc := new(Config)
 	*nc = *c
 	nc.Node = nc.Node.Copy()
 	nc.Servers = helper.CopySliceString(nc.Servers)
 	nc.Options = helper.CopyMapStringString(nc.Options)
+	nc.NamespaceAllocDirs = helper.CopyMapStringString(nc.NamespaceAllocDirs)
+	nc.JobTypeAllocDirs = helper.CopyMapStringString(nc.JobTypeAllocDirs)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
+	if c.ConsulClusters != nil {
+		nc.ConsulClusters = make([]*config.ConsulConfig, len(c.ConsulClusters))
+		for i, consul := range c.ConsulClusters {
+			nc.ConsulClusters[i] = consul.Copy()
+		}
+	}
+	if c.VaultClusters != nil {
+		nc.VaultClusters = make([]*config.VaultConfig, len(c.VaultClusters))
+		for i, vault := range c.VaultClusters {
+			nc.VaultClusters[i] = vault.Copy()
+		}
+	}
 	return nc
 }
 
+// ConsulClusterConfig returns the config of the Consul cluster of the name,
+// or nil if the cluster isn't configured.
+func (c *Config) ConsulClusterConfig(name string) *config.ConsulConfig {
+	if name == "" || name == structs.ConsulDefaultCluster {
+		return c.ConsulConfig
+	}
+	for _, consul := range c.ConsulClusters {
+		if consul.Name == name {
+			return consul
+		}
+	}
+	return nil
+}
+
+// VaultClusterConfig returns the config of the Vault cluster of the name, or
+// nil if the cluster isn't configured.
+func (c *Config) VaultClusterConfig(name string) *config.VaultConfig {
+	if name == "" || name == structs.VaultDefaultCluster {
+		return c.VaultConfig
+	}
+	for _, vault := range c.VaultClusters {
+		if vault.Name == name {
+			return vault
+		}
+	}
+	return nil
+}
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
@@ -247,6 +511,7 @@ func DefaultConfig() *Config {
 		GCDiskUsageThreshold:       80,
 		GCInodeUsageThreshold:      70,
 		GCMaxAllocs:                50,
+		MaxTaskEvents:              25,
 		NoHostUUID:                 true,
 		DisableTaggedMetrics:       false,
 		BackwardsCompatibleMetrics: false,
@@ -254,6 +519,45 @@ func DefaultConfig() *Config {
 	}
 }
 
+// AllocDirFor returns the directory storing the data of the allocation. It is
+// the directory of the allocation's namespace, else of its job type, else
+// AllocDir.
+func (c *Config) AllocDirFor(alloc *structs.Allocation) string {
+	if dir, ok := c.NamespaceAllocDirs[alloc.Namespace]; ok {
+		return dir
+	}
+	if alloc.Job != nil {
+		if dir, ok := c.JobTypeAllocDirs[alloc.Job.Type]; ok {
+			return dir
+		}
+	}
+	return c.AllocDir
+}
+
+// AllocDirs returns the distinct directories storing the data of allocations,
+// starting with AllocDir and followed by the others in order.
+func (c *Config) AllocDirs() []string {
+	dirs := []string{c.AllocDir}
+	seen := map[string]struct{}{c.AllocDir: {}}
+	for _, m := range []map[string]string{c.NamespaceAllocDirs, c.JobTypeAllocDirs} {
+		for _, dir := range m {
+			if _, ok := seen[dir]; ok {
+				continue
+			}
+			seen[dir] = struct{}{}
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs[1:])
+	return dirs
+}
+
+// PrefetchDir returns the directory storing the artifacts prefetched by the
+// client.
+func (c *Config) PrefetchDir() string {
+	return filepath.Join(c.StateDir, "prefetch")
+}
+
 // Read returns the specified configuration value or "".
 func (c *Config) Read(id string) string {
 	return c.Options[id]
@@ -1,6 +1,13 @@
 package config
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
 
 func TestConfigRead(t *testing.T) {
 	config := Config{}
@@ -34,3 +41,47 @@ func TestConfigReadDefault(t *testing.T) {
 		t.Errorf("Expected %s, found %s", expected, actual)
 	}
 }
+
+func TestConfig_AllocDirFor(t *testing.T) {
+	config := Config{
+		AllocDir: "/var/nomad/alloc",
+		NamespaceAllocDirs: map[string]string{
+			"tenant": "/mnt/tenant/alloc",
+		},
+		JobTypeAllocDirs: map[string]string{
+			structs.JobTypeBatch: "/mnt/scratch/alloc",
+		},
+	}
+
+	alloc := mock.Alloc()
+	require.Equal(t, "/var/nomad/alloc", config.AllocDirFor(alloc))
+
+	alloc.Job.Type = structs.JobTypeBatch
+	require.Equal(t, "/mnt/scratch/alloc", config.AllocDirFor(alloc))
+
+	// The namespace takes precedence over the job type
+	alloc.Namespace = "tenant"
+	require.Equal(t, "/mnt/tenant/alloc", config.AllocDirFor(alloc))
+
+	require.Equal(t, []string{"/var/nomad/alloc", "/mnt/scratch/alloc", "/mnt/tenant/alloc"}, config.AllocDirs())
+}
+
+func TestConfig_ClusterConfigs(t *testing.T) {
+	conf := DefaultConfig()
+	conf.VaultClusters = []*config.VaultConfig{{Name: "secrets"}}
+	conf.ConsulClusters = []*config.ConsulConfig{{Name: "edge"}}
+
+	require.Equal(t, conf.VaultConfig, conf.VaultClusterConfig(""))
+	require.Equal(t, conf.VaultConfig, conf.VaultClusterConfig(structs.VaultDefaultCluster))
+	require.Equal(t, conf.VaultClusters[0], conf.VaultClusterConfig("secrets"))
+	require.Nil(t, conf.VaultClusterConfig("unknown"))
+
+	require.Equal(t, conf.ConsulConfig, conf.ConsulClusterConfig(structs.ConsulDefaultCluster))
+	require.Equal(t, conf.ConsulClusters[0], conf.ConsulClusterConfig("edge"))
+	require.Nil(t, conf.ConsulClusterConfig("unknown"))
+
+	// Named clusters are deep copied
+	copied := conf.Copy()
+	require.Equal(t, "secrets", copied.VaultClusterConfig("secrets").Name)
+	require.False(t, copied.VaultClusters[0] == conf.VaultClusters[0])
+}
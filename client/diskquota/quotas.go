@@ -0,0 +1,219 @@
+// Package diskquota enforces the ephemeral disk sizes of allocations with the
+// project quotas of the filesystems of their alloc dirs. Each alloc dir is
+// assigned a project whose files are limited to the ephemeral disk size.
+package diskquota
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// minProjectID and maxProjectID bound the projects assigned to alloc
+	// dirs, so that projects set up by operators aren't reused.
+	minProjectID = 100000
+	maxProjectID = 199999
+)
+
+// filesystem sets and reads the project quotas of a filesystem.
+type filesystem interface {
+	// projectID returns the project of the file.
+	projectID(path string) (uint32, error)
+
+	// setProjectID assigns the directory tree to the project, and makes the
+	// files created in it inherit the project.
+	setProjectID(dir string, id uint32) error
+
+	// setLimit limits the space used by the project. A zero limit removes it.
+	setLimit(id uint32, bytes uint64) error
+
+	// usage returns the space used by the project.
+	usage(id uint32) (uint64, error)
+}
+
+// Quotas assigns projects to alloc dirs and limits their space. All methods
+// are safe for concurrent use.
+type Quotas struct {
+	logger hclog.Logger
+
+	// filesystems maps the alloc dirs' parent directories whose filesystem
+	// supports project quotas to that filesystem.
+	filesystems map[string]filesystem
+
+	// next is where the search for a free project starts, so that released
+	// projects aren't immediately reused
+	next uint32
+
+	// byDir maps alloc dirs to their project and used maps the projects in
+	// use to their alloc dir
+	byDir map[string]uint32
+	used  map[uint32]string
+
+	l sync.Mutex
+}
+
+// New returns the quotas of the alloc dirs created in the given directories.
+// It returns nil if none of their filesystems supports project quotas.
+func New(logger hclog.Logger, roots []string) *Quotas {
+	logger = logger.Named("disk_quota")
+	filesystems := make(map[string]filesystem, len(roots))
+	for _, root := range roots {
+		fs, err := newFilesystem(root)
+		if err != nil {
+			logger.Debug("project quotas unsupported, ephemeral disk sizes aren't enforced",
+				"alloc_dir", root, "error", err)
+			continue
+		}
+		logger.Info("enforcing ephemeral disk sizes with project quotas", "alloc_dir", root)
+		filesystems[root] = fs
+	}
+	if len(filesystems) == 0 {
+		return nil
+	}
+
+	q := newQuotas(logger, filesystems)
+	q.restore()
+	return q
+}
+
+func newQuotas(logger hclog.Logger, filesystems map[string]filesystem) *Quotas {
+	return &Quotas{
+		logger:      logger,
+		filesystems: filesystems,
+		next:        minProjectID,
+		byDir:       make(map[string]uint32),
+		used:        make(map[uint32]string),
+	}
+}
+
+// restore marks the projects of the existing alloc dirs as used, so that they
+// are kept across restarts.
+func (q *Quotas) restore() {
+	for root, fs := range q.filesystems {
+		entries, err := ioutil.ReadDir(root)
+		if err != nil {
+			q.logger.Warn("failed to list alloc dirs", "alloc_dir", root, "error", err)
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			dir := filepath.Join(root, entry.Name())
+			id, err := fs.projectID(dir)
+			if err != nil || id < minProjectID || id > maxProjectID {
+				continue
+			}
+			q.byDir[dir] = id
+			q.used[id] = dir
+		}
+	}
+}
+
+// Supported returns whether the filesystem of the alloc dir supports project
+// quotas.
+func (q *Quotas) Supported(dir string) bool {
+	_, ok := q.filesystems[filepath.Dir(dir)]
+	return ok
+}
+
+// Set assigns the alloc dir a project, keeping the project it already has,
+// and limits it to the given size.
+func (q *Quotas) Set(dir string, bytes uint64) error {
+	fs, ok := q.filesystems[filepath.Dir(dir)]
+	if !ok {
+		return fmt.Errorf("project quotas unsupported for alloc dir %q", dir)
+	}
+
+	id, err := q.acquire(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.setProjectID(dir, id); err != nil {
+		q.release(dir)
+		return fmt.Errorf("failed to set project of alloc dir %q: %v", dir, err)
+	}
+	if err := fs.setLimit(id, bytes); err != nil {
+		q.release(dir)
+		return fmt.Errorf("failed to set quota of alloc dir %q: %v", dir, err)
+	}
+	return nil
+}
+
+// Usage returns the space used by the alloc dir.
+func (q *Quotas) Usage(dir string) (uint64, error) {
+	fs, ok := q.filesystems[filepath.Dir(dir)]
+	if !ok {
+		return 0, fmt.Errorf("project quotas unsupported for alloc dir %q", dir)
+	}
+
+	q.l.Lock()
+	id, ok := q.byDir[dir]
+	q.l.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("alloc dir %q has no project", dir)
+	}
+	return fs.usage(id)
+}
+
+// Release removes the limit of the alloc dir and frees its project.
+func (q *Quotas) Release(dir string) error {
+	fs, ok := q.filesystems[filepath.Dir(dir)]
+	if !ok {
+		return nil
+	}
+
+	id, ok := q.release(dir)
+	if !ok {
+		return nil
+	}
+	if err := fs.setLimit(id, 0); err != nil {
+		return fmt.Errorf("failed to remove quota of alloc dir %q: %v", dir, err)
+	}
+	return nil
+}
+
+// acquire returns the project of the alloc dir, assigning it a free one if it
+// doesn't have one yet.
+func (q *Quotas) acquire(dir string) (uint32, error) {
+	q.l.Lock()
+	defer q.l.Unlock()
+
+	if id, ok := q.byDir[dir]; ok {
+		return id, nil
+	}
+
+	for i := 0; i <= maxProjectID-minProjectID; i++ {
+		id := q.next
+		q.next++
+		if q.next > maxProjectID {
+			q.next = minProjectID
+		}
+		if _, ok := q.used[id]; ok {
+			continue
+		}
+		q.byDir[dir] = id
+		q.used[id] = dir
+		return id, nil
+	}
+	return 0, fmt.Errorf("no free project for alloc dir %q", dir)
+}
+
+// release frees the project of the alloc dir and returns it.
+func (q *Quotas) release(dir string) (uint32, bool) {
+	q.l.Lock()
+	defer q.l.Unlock()
+
+	id, ok := q.byDir[dir]
+	if ok {
+		delete(q.byDir, dir)
+		delete(q.used, id)
+	}
+	return id, ok
+}
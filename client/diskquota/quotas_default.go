@@ -0,0 +1,9 @@
+// +build !linux
+
+package diskquota
+
+import "fmt"
+
+func newFilesystem(root string) (filesystem, error) {
+	return nil, fmt.Errorf("project quotas are only supported on Linux")
+}
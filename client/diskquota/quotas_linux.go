@@ -0,0 +1,196 @@
+package diskquota
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// fsIocFsgetxattr and fsIocFssetxattr get and set the attributes of a
+	// file, including its project
+	fsIocFsgetxattr = 0x801c581f
+	fsIocFssetxattr = 0x401c5820
+
+	// fsXflagProjinherit makes the files created in a directory inherit its
+	// project
+	fsXflagProjinherit = 0x200
+
+	// qGetquota and qSetquota get and set the quota of an ID, and prjQuota
+	// selects project IDs
+	qGetquota = 0x800007
+	qSetquota = 0x800008
+	prjQuota  = 2
+
+	// qifBlimits marks the block limits of a quota as set, which are counted
+	// in units of qifDqblksize bytes
+	qifBlimits   = 1
+	qifDqblksize = 1024
+
+	// quotaDevName is the name of the block device node created in the
+	// directory to refer to its filesystem in quotactl calls
+	quotaDevName = ".quota-dev"
+)
+
+// fsxattr is the struct fsxattr of the FS_IOC_FSGETXATTR ioctl.
+type fsxattr struct {
+	xflags     uint32
+	extsize    uint32
+	nextents   uint32
+	projid     uint32
+	cowextsize uint32
+	pad        [8]byte
+}
+
+// ifDqblk is the struct if_dqblk of the quotactl syscall.
+type ifDqblk struct {
+	bhardlimit uint64
+	bsoftlimit uint64
+	curspace   uint64
+	ihardlimit uint64
+	isoftlimit uint64
+	curinodes  uint64
+	btime      uint64
+	itime      uint64
+	valid      uint32
+}
+
+// linuxFS sets project quotas with the quotactl syscall, supported by XFS
+// and by ext4 mounted with the prjquota option.
+type linuxFS struct {
+	// dev is the path of the block device node of the filesystem
+	dev string
+
+	// devID is the ID of the filesystem's device, whose files are assigned
+	// to projects
+	devID uint64
+}
+
+func newFilesystem(root string) (filesystem, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(root, &st); err != nil {
+		return nil, err
+	}
+
+	dev := filepath.Join(root, quotaDevName)
+	if err := os.Remove(dev); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err := unix.Mknod(dev, unix.S_IFBLK|0600, int(st.Dev)); err != nil {
+		return nil, fmt.Errorf("failed to create block device node: %v", err)
+	}
+
+	fs := &linuxFS{dev: dev, devID: uint64(st.Dev)}
+	if _, err := fs.projectID(root); err != nil {
+		return nil, fmt.Errorf("failed to read project: %v", err)
+	}
+	if _, err := fs.usage(0); err != nil {
+		return nil, fmt.Errorf("failed to read project quota: %v", err)
+	}
+	return fs, nil
+}
+
+func (fs *linuxFS) projectID(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	attr, err := getXattr(f)
+	if err != nil {
+		return 0, err
+	}
+	return attr.projid, nil
+}
+
+func (fs *linuxFS) setProjectID(dir string, id uint32) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Skip the files of other filesystems, such as the secrets dirs,
+		// and the files that can't be opened without side effects
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || uint64(st.Dev) != fs.devID {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		attr, err := getXattr(f)
+		if err != nil {
+			return err
+		}
+		attr.projid = id
+		if info.IsDir() {
+			attr.xflags |= fsXflagProjinherit
+		}
+		return setXattr(f, attr)
+	})
+}
+
+func (fs *linuxFS) setLimit(id uint32, bytes uint64) error {
+	limit := (bytes + qifDqblksize - 1) / qifDqblksize
+	dq := ifDqblk{
+		bhardlimit: limit,
+		bsoftlimit: limit,
+		valid:      qifBlimits,
+	}
+	return fs.quotactl(qSetquota, id, &dq)
+}
+
+func (fs *linuxFS) usage(id uint32) (uint64, error) {
+	var dq ifDqblk
+	if err := fs.quotactl(qGetquota, id, &dq); err != nil {
+		return 0, err
+	}
+	return dq.curspace, nil
+}
+
+func (fs *linuxFS) quotactl(cmd int, id uint32, dq *ifDqblk) error {
+	dev, err := unix.BytePtrFromString(fs.dev)
+	if err != nil {
+		return err
+	}
+
+	cmd = cmd<<8 | prjQuota
+	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, uintptr(cmd), uintptr(unsafe.Pointer(dev)),
+		uintptr(id), uintptr(unsafe.Pointer(dq)), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func getXattr(f *os.File) (*fsxattr, error) {
+	var attr fsxattr
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), fsIocFsgetxattr, uintptr(unsafe.Pointer(&attr)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &attr, nil
+}
+
+func setXattr(f *os.File, attr *fsxattr) error {
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), fsIocFssetxattr, uintptr(unsafe.Pointer(attr)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
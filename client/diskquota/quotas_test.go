@@ -0,0 +1,112 @@
+package diskquota
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFS records the projects and limits set on it.
+type fakeFS struct {
+	projects map[string]uint32
+	limits   map[uint32]uint64
+	usages   map[uint32]uint64
+}
+
+func newFakeFS() *fakeFS {
+	return &fakeFS{
+		projects: make(map[string]uint32),
+		limits:   make(map[uint32]uint64),
+		usages:   make(map[uint32]uint64),
+	}
+}
+
+func (fs *fakeFS) projectID(path string) (uint32, error) {
+	return fs.projects[path], nil
+}
+
+func (fs *fakeFS) setProjectID(dir string, id uint32) error {
+	fs.projects[dir] = id
+	return nil
+}
+
+func (fs *fakeFS) setLimit(id uint32, bytes uint64) error {
+	if bytes == 0 {
+		delete(fs.limits, id)
+		return nil
+	}
+	fs.limits[id] = bytes
+	return nil
+}
+
+func (fs *fakeFS) usage(id uint32) (uint64, error) {
+	return fs.usages[id], nil
+}
+
+func TestQuotas_SetRelease(t *testing.T) {
+	require := require.New(t)
+	fs := newFakeFS()
+	q := newQuotas(testlog.HCLogger(t), map[string]filesystem{"/alloc": fs})
+
+	require.True(q.Supported("/alloc/a"))
+	require.False(q.Supported("/scratch/a"))
+	require.Error(q.Set("/scratch/a", 1024))
+
+	// Each alloc dir is assigned its own project
+	require.NoError(q.Set("/alloc/a", 1024))
+	require.NoError(q.Set("/alloc/b", 2048))
+	idA, idB := fs.projects["/alloc/a"], fs.projects["/alloc/b"]
+	require.NotEqual(idA, idB)
+	require.EqualValues(1024, fs.limits[idA])
+	require.EqualValues(2048, fs.limits[idB])
+
+	// Setting the quota again keeps the project
+	require.NoError(q.Set("/alloc/a", 4096))
+	require.Equal(idA, fs.projects["/alloc/a"])
+	require.EqualValues(4096, fs.limits[idA])
+
+	fs.usages[idB] = 512
+	used, err := q.Usage("/alloc/b")
+	require.NoError(err)
+	require.EqualValues(512, used)
+
+	// Releasing removes the limit and frees the project
+	require.NoError(q.Release("/alloc/a"))
+	require.NotContains(fs.limits, idA)
+	_, err = q.Usage("/alloc/a")
+	require.Error(err)
+}
+
+func TestQuotas_Restore(t *testing.T) {
+	require := require.New(t)
+
+	root, err := ioutil.TempDir("", "diskquota")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	a, b, c := filepath.Join(root, "a"), filepath.Join(root, "b"), filepath.Join(root, "c")
+	for _, dir := range []string{a, b, c} {
+		require.NoError(os.Mkdir(dir, 0755))
+	}
+
+	// Projects assigned before a restart are kept and not reused, unlike
+	// the projects outside of the range
+	fs := newFakeFS()
+	fs.projects[a] = minProjectID
+	fs.projects[b] = 42
+	q := newQuotas(testlog.HCLogger(t), map[string]filesystem{root: fs})
+	q.restore()
+
+	require.NoError(q.Set(c, 1024))
+	require.EqualValues(minProjectID+1, fs.projects[c])
+
+	require.NoError(q.Set(a, 1024))
+	require.EqualValues(minProjectID, fs.projects[a])
+
+	require.NoError(q.Set(b, 1024))
+	require.EqualValues(minProjectID+2, fs.projects[b])
+}
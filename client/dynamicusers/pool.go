@@ -0,0 +1,107 @@
+// Package dynamicusers assigns unprivileged user and group IDs to the
+// allocations of a client, so that the tasks of different allocations can't
+// access each other's files.
+package dynamicusers
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Pool hands out the IDs of a range to allocations. An allocation is given
+// the same number as its user ID and group ID. All methods are safe for
+// concurrent use.
+type Pool struct {
+	min int
+	max int
+
+	// next is where the search for a free ID starts, so that released IDs
+	// aren't immediately reused
+	next int
+
+	// byAlloc maps allocation IDs to their ID and used maps the IDs in use
+	// to their allocation
+	byAlloc map[string]int
+	used    map[int]string
+
+	l sync.Mutex
+}
+
+// NewPool returns a pool of the IDs in the inclusive range [min, max].
+func NewPool(min, max int) (*Pool, error) {
+	if min <= 0 {
+		return nil, fmt.Errorf("dynamic user minimum ID must be positive")
+	}
+	if max < min {
+		return nil, fmt.Errorf("dynamic user maximum ID %d is lower than the minimum ID %d", max, min)
+	}
+
+	return &Pool{
+		min:     min,
+		max:     max,
+		next:    min,
+		byAlloc: make(map[string]int),
+		used:    make(map[int]string),
+	}, nil
+}
+
+// Acquire returns the ID of the allocation, assigning it a free one if it
+// doesn't have one yet.
+func (p *Pool) Acquire(allocID string) (int, error) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if id, ok := p.byAlloc[allocID]; ok {
+		return id, nil
+	}
+
+	size := p.max - p.min + 1
+	for i := 0; i < size; i++ {
+		id := p.next
+		p.next++
+		if p.next > p.max {
+			p.next = p.min
+		}
+
+		if _, ok := p.used[id]; ok {
+			continue
+		}
+		p.byAlloc[allocID] = id
+		p.used[id] = allocID
+		return id, nil
+	}
+
+	return 0, fmt.Errorf("no dynamic user IDs available in range %d-%d", p.min, p.max)
+}
+
+// Restore reclaims the ID an allocation was assigned before the client
+// restarted.
+func (p *Pool) Restore(allocID string, id int) error {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if id < p.min || id > p.max {
+		return fmt.Errorf("dynamic user ID %d is outside of range %d-%d", id, p.min, p.max)
+	}
+	if other, ok := p.used[id]; ok && other != allocID {
+		return fmt.Errorf("dynamic user ID %d is already assigned to allocation %s", id, other)
+	}
+	if existing, ok := p.byAlloc[allocID]; ok && existing != id {
+		return fmt.Errorf("allocation %s is already assigned dynamic user ID %d", allocID, existing)
+	}
+
+	p.byAlloc[allocID] = id
+	p.used[id] = allocID
+	return nil
+}
+
+// Release frees the ID of the allocation.
+func (p *Pool) Release(allocID string) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if id, ok := p.byAlloc[allocID]; ok {
+		delete(p.byAlloc, allocID)
+		delete(p.used, id)
+	}
+}
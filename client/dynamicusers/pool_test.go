@@ -0,0 +1,72 @@
+package dynamicusers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPool(0, 10)
+	require.Error(t, err)
+
+	_, err = NewPool(20, 10)
+	require.Error(t, err)
+
+	_, err = NewPool(10, 10)
+	require.NoError(t, err)
+}
+
+func TestPool_Acquire(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	p, err := NewPool(100, 101)
+	require.NoError(err)
+
+	id1, err := p.Acquire("a")
+	require.NoError(err)
+	require.Equal(100, id1)
+
+	// Acquiring is idempotent
+	id, err := p.Acquire("a")
+	require.NoError(err)
+	require.Equal(id1, id)
+
+	id2, err := p.Acquire("b")
+	require.NoError(err)
+	require.Equal(101, id2)
+
+	// The pool is exhausted
+	_, err = p.Acquire("c")
+	require.Error(err)
+
+	// Released IDs are reused
+	p.Release("a")
+	id, err = p.Acquire("c")
+	require.NoError(err)
+	require.Equal(id1, id)
+}
+
+func TestPool_Restore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	p, err := NewPool(100, 109)
+	require.NoError(err)
+
+	require.NoError(p.Restore("a", 100))
+	require.NoError(p.Restore("a", 100))
+
+	// IDs out of range or assigned to other allocations are rejected
+	require.Error(p.Restore("b", 99))
+	require.Error(p.Restore("b", 100))
+	require.Error(p.Restore("a", 101))
+
+	// Restored IDs aren't handed out again
+	id, err := p.Acquire("b")
+	require.NoError(err)
+	require.Equal(101, id)
+}
@@ -0,0 +1,123 @@
+package externalservices
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultProviderTimeout is the timeout of requests to HTTP providers
+	// that don't set one
+	defaultProviderTimeout = 10 * time.Second
+)
+
+// Target is the address of a service of an allocation pushed to an external
+// provider, such as the member of a load balancer pool.
+type Target struct {
+	// ID is the unique ID of the target, derived from the allocation, task
+	// and service definition.
+	ID string
+
+	// Provider is the name of the provider of the service.
+	Provider string
+
+	// ServiceName is the name of the service.
+	ServiceName string
+
+	Namespace  string
+	NodeID     string
+	Datacenter string
+	JobID      string
+	AllocID    string
+	Task       string
+
+	// Tags are the tags of the service, which are its canary tags when the
+	// allocation is a canary.
+	Tags []string
+
+	Address string
+	Port    int
+}
+
+// Copy returns a copy of the target.
+func (t *Target) Copy() *Target {
+	if t == nil {
+		return nil
+	}
+	nt := *t
+	nt.Tags = append([]string(nil), t.Tags...)
+	return &nt
+}
+
+// Provider is an external service provider that targets are pushed to.
+// Register is called once the checks of a service pass and Deregister once
+// they fail or the task stops. Both must be idempotent as targets may be
+// pushed again after client restarts.
+type Provider interface {
+	Register(*Target) error
+	Deregister(*Target) error
+}
+
+// HTTPProvider is a Provider that pushes targets to an HTTP API, such as an
+// adapter of a load balancer. Targets are registered with a PUT request of
+// their JSON encoding to <url>/<id> and deregistered with a DELETE request to
+// the same URL. Any 2xx response is a success.
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProvider returns an HTTPProvider for the base URL. A zero timeout
+// uses the default timeout.
+func NewHTTPProvider(baseURL string, timeout time.Duration) *HTTPProvider {
+	if timeout == 0 {
+		timeout = defaultProviderTimeout
+	}
+	return &HTTPProvider{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Register implements Provider.
+func (p *HTTPProvider) Register(t *Target) error {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return p.do("PUT", t.ID, bytes.NewReader(body))
+}
+
+// Deregister implements Provider.
+func (p *HTTPProvider) Deregister(t *Target) error {
+	return p.do("DELETE", t.ID, nil)
+}
+
+func (p *HTTPProvider) do(method, id string, body io.Reader) error {
+	req, err := http.NewRequest(method, p.url+"/"+url.PathEscape(id), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package externalservices
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/nomadservices"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceAPI is the interface the task runner uses to register and remove
+// the services of tasks using external service providers.
+type ServiceAPI interface {
+	RegisterTask(*agentconsul.TaskServices) error
+	RemoveTask(*agentconsul.TaskServices)
+	UpdateTask(old, newTask *agentconsul.TaskServices) error
+}
+
+// ServiceClient pushes the services of tasks using external providers to
+// their provider. The checks of the services are run by the client and gate
+// the registrations: a service is only registered with its provider while
+// its checks aren't critical, so load balancers only send traffic to healthy
+// allocations. Services without checks are registered as soon as their task
+// starts.
+type ServiceClient struct {
+	providers map[string]Provider
+	node      *structs.Node
+	logger    log.Logger
+
+	// targets are the targets of the client by ID
+	targets map[string]*serviceTarget
+	mu      sync.Mutex
+}
+
+// serviceTarget tracks a target and the status of its checks.
+type serviceTarget struct {
+	target   *Target
+	provider Provider
+
+	// checks is the last status of each check of the service by check ID
+	checks map[string]string
+
+	// registered is whether the target is registered with its provider.
+	// syncMu serializes the calls to the provider for the target.
+	registered bool
+	syncMu     sync.Mutex
+
+	// ctx is canceled to stop the checks of the service
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewServiceClient returns a ServiceClient that pushes the services of the
+// node to the given providers by name.
+func NewServiceClient(logger log.Logger, node *structs.Node, providers map[string]Provider) *ServiceClient {
+	return &ServiceClient{
+		providers: providers,
+		node:      node,
+		logger:    logger.Named("external_services"),
+		targets:   make(map[string]*serviceTarget),
+	}
+}
+
+// RegisterTask starts the checks of the services of the task and registers
+// the healthy services with their provider. Services that are already
+// tracked have their checks restarted.
+func (c *ServiceClient) RegisterTask(task *agentconsul.TaskServices) error {
+	if len(task.Services) == 0 {
+		return nil
+	}
+
+	targets := make([]*serviceTarget, 0, len(task.Services))
+	checks := make(map[*serviceTarget][]*nomadservices.Check)
+	for _, service := range task.Services {
+		st, serviceChecks, err := c.serviceTarget(task, service)
+		if err != nil {
+			for _, t := range targets {
+				t.cancel()
+			}
+			return err
+		}
+		targets = append(targets, st)
+		checks[st] = serviceChecks
+	}
+
+	c.mu.Lock()
+	replaced := make(map[*serviceTarget]*serviceTarget)
+	for _, st := range targets {
+		if existing, ok := c.targets[st.target.ID]; ok {
+			existing.cancel()
+			replaced[st] = existing
+		}
+		c.targets[st.target.ID] = st
+	}
+	c.mu.Unlock()
+
+	// Replaced targets have the same ID and so remain registered with their
+	// provider
+	for st, existing := range replaced {
+		existing.syncMu.Lock()
+		st.registered = existing.registered
+		existing.syncMu.Unlock()
+	}
+
+	var err error
+	for _, st := range targets {
+		if serr := c.sync(st); serr != nil && err == nil {
+			err = fmt.Errorf("failed to register service %q with the %q service provider: %v", st.target.ServiceName, st.target.Provider, serr)
+		}
+		for _, chk := range checks[st] {
+			go c.runCheck(st, chk)
+		}
+	}
+	return err
+}
+
+// UpdateTask removes the services of the old task that were removed or
+// changed, and registers the services of the new task.
+func (c *ServiceClient) UpdateTask(old, newTask *agentconsul.TaskServices) error {
+	newIDs := make(map[string]struct{}, len(newTask.Services))
+	for _, service := range newTask.Services {
+		newIDs[agentconsul.MakeTaskServiceID(newTask.AllocID, newTask.Name, service, newTask.Canary)] = struct{}{}
+	}
+
+	for _, service := range old.Services {
+		id := agentconsul.MakeTaskServiceID(old.AllocID, old.Name, service, old.Canary)
+		if _, ok := newIDs[id]; !ok {
+			c.remove(id)
+		}
+	}
+
+	return c.RegisterTask(newTask)
+}
+
+// RemoveTask stops the checks of the services of the task and deregisters
+// them from their provider.
+func (c *ServiceClient) RemoveTask(task *agentconsul.TaskServices) {
+	for _, service := range task.Services {
+		c.remove(agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, task.Canary))
+	}
+}
+
+// remove stops the checks of the service and deregisters it if it is tracked
+// by the client.
+func (c *ServiceClient) remove(id string) {
+	c.mu.Lock()
+	st, ok := c.targets[id]
+	if ok {
+		st.cancel()
+		delete(c.targets, id)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	st.syncMu.Lock()
+	defer st.syncMu.Unlock()
+	if !st.registered {
+		return
+	}
+	if err := st.provider.Deregister(st.target.Copy()); err != nil {
+		c.logger.Warn("failed to deregister service", "provider", st.target.Provider, "service", st.target.ServiceName, "id", id, "error", err)
+		return
+	}
+	st.registered = false
+}
+
+// sync registers or deregisters the target with its provider depending on
+// the status of its checks. Failures are retried on the next run of the
+// checks of the service.
+func (c *ServiceClient) sync(st *serviceTarget) error {
+	st.syncMu.Lock()
+	defer st.syncMu.Unlock()
+
+	// The target was removed or replaced
+	if st.ctx.Err() != nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	healthy := nomadservices.AggregateStatus(st.checks) != api.HealthCritical
+	c.mu.Unlock()
+
+	if healthy == st.registered {
+		return nil
+	}
+
+	if healthy {
+		if err := st.provider.Register(st.target.Copy()); err != nil {
+			return err
+		}
+		c.logger.Debug("registered service", "provider", st.target.Provider, "service", st.target.ServiceName, "id", st.target.ID)
+	} else {
+		if err := st.provider.Deregister(st.target.Copy()); err != nil {
+			return err
+		}
+		c.logger.Debug("deregistered unhealthy service", "provider", st.target.Provider, "service", st.target.ServiceName, "id", st.target.ID)
+	}
+	st.registered = healthy
+	return nil
+}
+
+// serviceTarget builds the target and checks of a service of the task.
+func (c *ServiceClient) serviceTarget(task *agentconsul.TaskServices, service *structs.Service) (*serviceTarget, []*nomadservices.Check, error) {
+	provider, ok := c.providers[service.Provider]
+	if !ok {
+		return nil, nil, fmt.Errorf("the %q service provider is not available", service.Provider)
+	}
+
+	// Service address modes default to auto
+	addrMode := service.AddressMode
+	if addrMode == "" {
+		addrMode = structs.AddressModeAuto
+	}
+
+	ip, port, err := agentconsul.GetAddress(addrMode, service.PortLabel, task.Networks, task.DriverNetwork)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
+	}
+
+	// Determine whether to use tags or canary_tags
+	tags := service.Tags
+	if task.Canary && len(service.CanaryTags) > 0 {
+		tags = service.CanaryTags
+	}
+
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, task.Canary)
+	st := &serviceTarget{
+		target: &Target{
+			ID:          id,
+			Provider:    service.Provider,
+			ServiceName: service.Name,
+			Namespace:   task.Namespace,
+			NodeID:      c.node.ID,
+			Datacenter:  c.node.Datacenter,
+			JobID:       task.JobID,
+			AllocID:     task.AllocID,
+			Task:        task.Name,
+			Tags:        append([]string(nil), tags...),
+			Address:     ip,
+			Port:        port,
+		},
+		provider: provider,
+		checks:   make(map[string]string, len(service.Checks)),
+	}
+	st.ctx, st.cancel = context.WithCancel(context.Background())
+
+	checks := make([]*nomadservices.Check, 0, len(service.Checks))
+	for _, sc := range service.Checks {
+		chk, err := nomadservices.NewCheck(id, task, service, sc)
+		if err != nil {
+			st.cancel()
+			return nil, nil, fmt.Errorf("error getting address for check %q: %v", sc.Name, err)
+		}
+		checks = append(checks, chk)
+		st.checks[chk.ID()] = chk.InitialStatus()
+	}
+	return st, checks, nil
+}
+
+// runCheck runs the check at its interval until the target is removed,
+// syncing the target with its provider after each run.
+func (c *ServiceClient) runCheck(st *serviceTarget, chk *nomadservices.Check) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-st.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		status := chk.Run(st.ctx)
+		if st.ctx.Err() != nil {
+			return
+		}
+
+		c.mu.Lock()
+		st.checks[chk.ID()] = status
+		c.mu.Unlock()
+
+		if err := c.sync(st); err != nil {
+			c.logger.Warn("failed to sync service", "provider", st.target.Provider, "service", st.target.ServiceName, "id", st.target.ID, "error", err)
+		}
+		timer.Reset(chk.Interval())
+	}
+}
@@ -0,0 +1,302 @@
+package externalservices
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProvider records the targets registered with it.
+type mockProvider struct {
+	targets map[string]*Target
+	calls   int
+	err     error
+	mu      sync.Mutex
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{targets: make(map[string]*Target)}
+}
+
+func (m *mockProvider) Register(t *Target) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.err != nil {
+		return m.err
+	}
+	m.targets[t.ID] = t
+	return nil
+}
+
+func (m *mockProvider) Deregister(t *Target) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.err != nil {
+		return m.err
+	}
+	delete(m.targets, t.ID)
+	return nil
+}
+
+func (m *mockProvider) target(id string) *Target {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.targets[id]
+}
+
+func (m *mockProvider) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// testTaskServices returns the services of a task with a port labeled http
+// on the address.
+func testTaskServices(t *testing.T, addr string, services ...*structs.Service) *agentconsul.TaskServices {
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return &agentconsul.TaskServices{
+		AllocID:   uuid.Generate(),
+		Namespace: structs.DefaultNamespace,
+		JobID:     "example",
+		Name:      "web",
+		Services:  services,
+		Networks: structs.Networks{
+			{
+				IP:            host,
+				ReservedPorts: []structs.Port{{Label: "http", Value: port}},
+			},
+		},
+	}
+}
+
+func TestServiceClient_NoChecks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	service := &structs.Service{
+		Name:      "web",
+		PortLabel: "http",
+		Tags:      []string{"primary"},
+		Provider:  "f5",
+	}
+	task := testTaskServices(t, "10.0.0.1:8080", service)
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	provider := newMockProvider()
+	node := mock.Node()
+	c := NewServiceClient(testlog.HCLogger(t), node, map[string]Provider{"f5": provider})
+
+	// Services without checks are registered right away
+	require.NoError(c.RegisterTask(task))
+	target := provider.target(id)
+	require.NotNil(target)
+	require.Equal("f5", target.Provider)
+	require.Equal("web", target.ServiceName)
+	require.Equal("web", target.Task)
+	require.Equal(node.ID, target.NodeID)
+	require.Equal(task.AllocID, target.AllocID)
+	require.Equal([]string{"primary"}, target.Tags)
+	require.Equal("10.0.0.1", target.Address)
+	require.Equal(8080, target.Port)
+
+	// Registering the task again doesn't register the target again
+	require.NoError(c.RegisterTask(task))
+	require.Equal(1, provider.calls)
+
+	c.RemoveTask(task)
+	require.Nil(provider.target(id))
+
+	// Unknown providers are an error
+	service.Provider = "alb"
+	err := c.RegisterTask(task)
+	require.Error(err)
+	require.Contains(err.Error(), `the "alb" service provider is not available`)
+}
+
+func TestServiceClient_HealthGating(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var code int32 = http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&code)))
+	}))
+	defer ts.Close()
+
+	service := &structs.Service{
+		Name:      "web",
+		PortLabel: "http",
+		Provider:  "f5",
+		Checks: []*structs.ServiceCheck{
+			{
+				Name:     "alive",
+				Type:     structs.ServiceCheckHTTP,
+				Path:     "/health",
+				Interval: 10 * time.Millisecond,
+				Timeout:  time.Second,
+			},
+		},
+	}
+	task := testTaskServices(t, ts.Listener.Addr().String(), service)
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	provider := newMockProvider()
+	c := NewServiceClient(testlog.HCLogger(t), mock.Node(), map[string]Provider{"f5": provider})
+	require.NoError(c.RegisterTask(task))
+
+	waitForRegistered := func(registered bool) {
+		testutil.WaitForResult(func() (bool, error) {
+			if r := provider.target(id) != nil; r != registered {
+				return false, fmt.Errorf("expected registered %v; got %v", registered, r)
+			}
+			return true, nil
+		}, func(err error) {
+			t.Fatal(err)
+		})
+	}
+
+	// The target isn't registered until the check passes
+	time.Sleep(50 * time.Millisecond)
+	require.Nil(provider.target(id))
+	atomic.StoreInt32(&code, http.StatusOK)
+	waitForRegistered(true)
+
+	// Warnings keep the target registered and failures deregister it
+	atomic.StoreInt32(&code, http.StatusTooManyRequests)
+	time.Sleep(50 * time.Millisecond)
+	require.NotNil(provider.target(id))
+	atomic.StoreInt32(&code, http.StatusInternalServerError)
+	waitForRegistered(false)
+
+	// Failed registrations are retried on the next check
+	provider.setErr(fmt.Errorf("unavailable"))
+	atomic.StoreInt32(&code, http.StatusOK)
+	time.Sleep(50 * time.Millisecond)
+	require.Nil(provider.target(id))
+	provider.setErr(nil)
+	waitForRegistered(true)
+
+	// Removing the task stops the check and deregisters the target
+	c.RemoveTask(task)
+	require.Nil(provider.target(id))
+	atomic.StoreInt32(&code, http.StatusOK)
+	time.Sleep(50 * time.Millisecond)
+	require.Nil(provider.target(id))
+}
+
+func TestServiceClient_InitialStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Nothing listens on the port so the check fails once it runs
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	addr := l.Addr().String()
+	l.Close()
+
+	service := &structs.Service{
+		Name:      "web",
+		PortLabel: "http",
+		Provider:  "f5",
+		Checks: []*structs.ServiceCheck{
+			{
+				Name:          "alive",
+				Type:          structs.ServiceCheckTCP,
+				Interval:      time.Hour,
+				Timeout:       time.Second,
+				InitialStatus: api.HealthPassing,
+			},
+		},
+	}
+	task := testTaskServices(t, addr, service)
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	provider := newMockProvider()
+	c := NewServiceClient(testlog.HCLogger(t), mock.Node(), map[string]Provider{"f5": provider})
+
+	// A passing initial status registers the target before the check runs
+	require.NoError(c.RegisterTask(task))
+	testutil.WaitForResult(func() (bool, error) {
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		if provider.calls != 2 {
+			return false, fmt.Errorf("expected 2 calls; got %d", provider.calls)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	require.Nil(provider.target(id))
+}
+
+func TestHTTPProvider(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var (
+		mu       sync.Mutex
+		requests []string
+		body     Target
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		if r.Method == "PUT" {
+			require.Equal("application/json", r.Header.Get("Content-Type"))
+			require.NoError(json.NewDecoder(r.Body).Decode(&body))
+		}
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("pool is locked\n"))
+		}
+	}))
+	defer ts.Close()
+
+	p := NewHTTPProvider(ts.URL+"/targets/", time.Second)
+	target := &Target{
+		ID:          "_nomad-task-abc-web-web-http",
+		Provider:    "f5",
+		ServiceName: "web",
+		Address:     "10.0.0.1",
+		Port:        8080,
+	}
+	require.NoError(p.Register(target))
+	require.NoError(p.Deregister(target))
+
+	mu.Lock()
+	require.Equal([]string{
+		"PUT /targets/_nomad-task-abc-web-web-http",
+		"DELETE /targets/_nomad-task-abc-web-web-http",
+	}, requests)
+	require.Equal(*target, body)
+	mu.Unlock()
+
+	// Non 2xx responses are errors
+	err := p.Register(&Target{ID: "fail"})
+	require.Error(err)
+	require.Contains(err.Error(), "502: pool is locked")
+}
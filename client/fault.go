@@ -0,0 +1,122 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+var (
+	// errInjectedFault is the error failing the allocations added while
+	// allocation failures are injected.
+	errInjectedFault = errors.New("injected fault")
+)
+
+// faultInjector holds the faults injected into the client for resilience
+// testing. Its zero value injects no faults.
+type faultInjector struct {
+	// heartbeatDelay is added to the interval between heartbeats
+	heartbeatDelay time.Duration
+
+	// failAllocs is the number of allocations left to fail when added
+	failAllocs int
+
+	l sync.Mutex
+}
+
+// HeartbeatDelay returns the delay added between heartbeats.
+func (f *faultInjector) HeartbeatDelay() time.Duration {
+	f.l.Lock()
+	defer f.l.Unlock()
+	return f.heartbeatDelay
+}
+
+// failAllocation returns errInjectedFault if the allocation being added
+// should be failed, consuming one of the allocation failures left.
+func (f *faultInjector) failAllocation() error {
+	f.l.Lock()
+	defer f.l.Unlock()
+	if f.failAllocs <= 0 {
+		return nil
+	}
+	f.failAllocs--
+	return errInjectedFault
+}
+
+// Faults returns the heartbeat delay and the number of allocations left to
+// fail injected into the client.
+func (c *Client) Faults() (time.Duration, int) {
+	c.faults.l.Lock()
+	defer c.faults.l.Unlock()
+	return c.faults.heartbeatDelay, c.faults.failAllocs
+}
+
+// InjectFaults sets the faults injected into the client. Nil values leave the
+// fault unchanged, while zero values clear it. The resulting faults are
+// returned.
+func (c *Client) InjectFaults(heartbeatDelay *time.Duration, failAllocs *int) (time.Duration, int, error) {
+	if heartbeatDelay != nil && *heartbeatDelay < 0 {
+		return 0, 0, fmt.Errorf("heartbeat delay must not be negative")
+	}
+	if failAllocs != nil && *failAllocs < 0 {
+		return 0, 0, fmt.Errorf("number of allocations to fail must not be negative")
+	}
+
+	c.faults.l.Lock()
+	defer c.faults.l.Unlock()
+	if heartbeatDelay != nil {
+		c.faults.heartbeatDelay = *heartbeatDelay
+	}
+	if failAllocs != nil {
+		c.faults.failAllocs = *failAllocs
+	}
+
+	c.logger.Warn("injecting faults", "heartbeat_delay", c.faults.heartbeatDelay,
+		"fail_allocations", c.faults.failAllocs)
+	return c.faults.heartbeatDelay, c.faults.failAllocs, nil
+}
+
+// KillRandomTask kills a random running task of the job's allocations on the
+// node, as if the task crashed, and returns the allocation ID and name of the
+// killed task. The task is restarted according to its restart policy.
+func (c *Client) KillRandomTask(namespace, jobID string) (string, string, error) {
+	type candidate struct {
+		ar   AllocRunner
+		task string
+	}
+
+	var candidates []candidate
+	for _, ar := range c.getAllocRunners() {
+		alloc := ar.Alloc()
+		if alloc.Namespace != namespace || alloc.JobID != jobID || alloc.TerminalStatus() {
+			continue
+		}
+
+		for name, ts := range ar.AllocState().TaskStates {
+			if ts.State == structs.TaskStateRunning {
+				candidates = append(candidates, candidate{ar, name})
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", "", fmt.Errorf("no running task of job %q in namespace %q on the node", jobID, namespace)
+	}
+
+	victim := candidates[rand.Intn(len(candidates))]
+	event := structs.NewTaskEvent(structs.TaskSignaling).
+		SetTaskSignal(os.Kill).
+		SetTaskSignalReason("Fault injected")
+	if err := victim.ar.Signal(victim.task, event, "SIGKILL"); err != nil {
+		return "", "", err
+	}
+
+	allocID := victim.ar.Alloc().ID
+	c.logger.Warn("killed task to inject fault", "alloc_id", allocID, "task", victim.task)
+	return allocID, victim.task, nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Fault endpoint is used for injecting faults into a client for resilience
+// testing
+type Fault struct {
+	c *Client
+}
+
+// Apply is used to set the faults injected into the node.
+func (f *Fault) Apply(args *structs.FaultApplyRequest, reply *structs.FaultResponse) error {
+	defer metrics.MeasureSince([]string{"client", "fault", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := f.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if args.HeartbeatDelay == nil && args.FailAllocations == nil {
+		return errors.New("missing faults to apply")
+	}
+
+	delay, failAllocs, err := f.c.InjectFaults(args.HeartbeatDelay, args.FailAllocations)
+	if err != nil {
+		return err
+	}
+
+	reply.HeartbeatDelay = delay
+	reply.FailAllocations = failAllocs
+	return nil
+}
+
+// Read is used to retrieve the faults injected into the node.
+func (f *Fault) Read(args *nstructs.NodeSpecificRequest, reply *structs.FaultResponse) error {
+	defer metrics.MeasureSince([]string{"client", "fault", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := f.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	reply.HeartbeatDelay, reply.FailAllocations = f.c.Faults()
+	return nil
+}
+
+// KillTask is used to kill a random running task of a job on the node.
+func (f *Fault) KillTask(args *structs.FaultKillTaskRequest, reply *structs.FaultKillTaskResponse) error {
+	defer metrics.MeasureSince([]string{"client", "fault", "kill_task"}, time.Now())
+
+	// Check node write and submit job permissions
+	if aclObj, err := f.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && (!aclObj.AllowNodeWrite() ||
+		!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob)) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if args.JobID == "" {
+		return errors.New("missing JobID")
+	}
+
+	allocID, task, err := f.c.KillRandomTask(args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+
+	reply.AllocID = allocID
+	reply.Task = task
+	return nil
+}
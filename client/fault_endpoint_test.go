@@ -0,0 +1,160 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFault_Disabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// The endpoints aren't served unless fault injection is enabled
+	req := &structs.FaultApplyRequest{FailAllocations: helper.IntToPtr(1)}
+	var resp structs.FaultResponse
+	err := client.ClientRPC("Fault.Apply", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "can't find service")
+	require.Nil(client.endpoints.Fault)
+}
+
+func TestFault_Apply(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.EnableFaultInjection = true
+	})
+	defer cleanup()
+
+	// Apply without faults fails
+	req := &structs.FaultApplyRequest{}
+	var resp structs.FaultResponse
+	require.EqualError(client.ClientRPC("Fault.Apply", req, &resp), "missing faults to apply")
+
+	// Negative faults are rejected
+	req.FailAllocations = helper.IntToPtr(-1)
+	require.Error(client.ClientRPC("Fault.Apply", req, &resp))
+
+	req.HeartbeatDelay = helper.TimeToPtr(10 * time.Second)
+	req.FailAllocations = helper.IntToPtr(2)
+	require.NoError(client.ClientRPC("Fault.Apply", req, &resp))
+	require.Equal(10*time.Second, resp.HeartbeatDelay)
+	require.Equal(2, resp.FailAllocations)
+
+	// Unset faults are left unchanged
+	req.HeartbeatDelay = nil
+	req.FailAllocations = helper.IntToPtr(1)
+	require.NoError(client.ClientRPC("Fault.Apply", req, &resp))
+	require.Equal(10*time.Second, resp.HeartbeatDelay)
+	require.Equal(1, resp.FailAllocations)
+
+	// The next allocation added fails
+	err := client.addAlloc(mock.Alloc(), "")
+	require.Equal(errInjectedFault, err)
+
+	// Reading returns the faults left
+	var readResp structs.FaultResponse
+	require.NoError(client.ClientRPC("Fault.Read", &nstructs.NodeSpecificRequest{}, &readResp))
+	require.Equal(10*time.Second, readResp.HeartbeatDelay)
+	require.Zero(readResp.FailAllocations)
+	require.Equal(10*time.Second, client.faults.HeartbeatDelay())
+}
+
+func TestFault_KillTask(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.EnableFaultInjection = true
+	})
+	defer cleanup()
+
+	// Killing requires a job
+	req := &structs.FaultKillTaskRequest{}
+	var resp structs.FaultKillTaskResponse
+	require.EqualError(client.ClientRPC("Fault.KillTask", req, &resp), "missing JobID")
+
+	// The job has no running task on the node
+	req.JobID = "foo"
+	err := client.ClientRPC("Fault.KillTask", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "no running task")
+}
+
+func TestFault_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+		c.EnableFaultInjection = true
+	})
+	defer cleanup()
+
+	delay := helper.TimeToPtr(time.Second)
+
+	// Try request without a token and expect failure
+	{
+		req := &structs.FaultApplyRequest{HeartbeatDelay: delay}
+		var resp structs.FaultResponse
+		err := client.ClientRPC("Fault.Apply", req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a read token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+		req := &structs.FaultApplyRequest{HeartbeatDelay: delay}
+		req.AuthToken = token.SecretID
+
+		var resp structs.FaultResponse
+		err := client.ClientRPC("Fault.Apply", req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+		// Reading is allowed
+		readReq := &nstructs.NodeSpecificRequest{}
+		readReq.AuthToken = token.SecretID
+		require.NoError(client.ClientRPC("Fault.Read", readReq, &resp))
+	}
+
+	// Try request with a write token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "write", mock.NodePolicy(acl.PolicyWrite))
+		req := &structs.FaultApplyRequest{HeartbeatDelay: delay}
+		req.AuthToken = token.SecretID
+
+		var resp structs.FaultResponse
+		require.NoError(client.ClientRPC("Fault.Apply", req, &resp))
+		require.Equal(time.Second, resp.HeartbeatDelay)
+
+		// Killing tasks also requires submitting jobs
+		killReq := &structs.FaultKillTaskRequest{JobID: "foo"}
+		killReq.AuthToken = token.SecretID
+		var killResp structs.FaultKillTaskResponse
+		err := client.ClientRPC("Fault.KillTask", killReq, &killResp)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a management token
+	{
+		req := &structs.FaultApplyRequest{HeartbeatDelay: delay}
+		req.AuthToken = root.SecretID
+
+		var resp structs.FaultResponse
+		require.NoError(client.ClientRPC("Fault.Apply", req, &resp))
+	}
+}
@@ -4,6 +4,8 @@ package fingerprint
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 )
@@ -52,9 +54,25 @@ func (f *CGroupFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpri
 	resp.AddAttribute("unique.cgroup.mountpoint", mount)
 	resp.Detected = true
 
+	// Tasks can only use the real-time scheduling policies if the kernel
+	// supports real-time group scheduling and the client gives them a
+	// real-time runtime
+	if req.Config.RealtimeRuntime > 0 && realtimeGroupSched(mount) {
+		resp.AddAttribute("cpu.realtime", "true")
+	} else {
+		resp.RemoveAttribute("cpu.realtime")
+	}
+
 	if f.lastState == cgroupUnavailable {
 		f.logger.Info("cgroups are available")
 	}
 	f.lastState = cgroupAvailable
 	return nil
 }
+
+// realtimeGroupSched returns whether the cpu cgroup controller mounted under
+// the cgroup mount point supports real-time group scheduling.
+func realtimeGroupSched(mount string) bool {
+	_, err := os.Stat(filepath.Join(mount, "cpu", "cpu.rt_runtime_us"))
+	return err == nil
+}
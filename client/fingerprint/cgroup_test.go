@@ -4,7 +4,11 @@ package fingerprint
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper/testlog"
@@ -32,6 +36,13 @@ func (m *MountPointDetectorValidMountPoint) MountPoint() (string, error) {
 	return "/sys/fs/cgroup", nil
 }
 
+// A fake mount point detector that returns the given path
+type MountPointDetectorPath string
+
+func (m MountPointDetectorPath) MountPoint() (string, error) {
+	return string(m), nil
+}
+
 // A fake mount point detector that returns an empty path
 type MountPointDetectorEmptyMountPoint struct{}
 
@@ -128,3 +139,57 @@ func TestCGroupFingerprint(t *testing.T) {
 		}
 	}
 }
+
+func TestCGroupFingerprint_Realtime(t *testing.T) {
+	mount, err := ioutil.TempDir("", "nomadtest")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(mount)
+
+	f := &CGroupFingerprint{
+		logger:             testlog.HCLogger(t),
+		lastState:          cgroupUnavailable,
+		mountPointDetector: MountPointDetectorPath(mount),
+	}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	conf := &config.Config{RealtimeRuntime: 50 * time.Millisecond}
+
+	// The kernel doesn't support real-time group scheduling
+	var response FingerprintResponse
+	if err := f.Fingerprint(&FingerprintRequest{Config: conf, Node: node}, &response); err != nil {
+		t.Fatalf("unexpected error, %s", err)
+	}
+	if _, ok := response.Attributes["cpu.realtime"]; !ok {
+		t.Fatalf("expected cpu.realtime to be removed")
+	}
+	if a := response.Attributes["cpu.realtime"]; a != "" {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+
+	// The kernel supports real-time group scheduling
+	if err := os.MkdirAll(filepath.Join(mount, "cpu"), 0755); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(mount, "cpu", "cpu.rt_runtime_us"), []byte("0\n"), 0644); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	response = FingerprintResponse{}
+	if err := f.Fingerprint(&FingerprintRequest{Config: conf, Node: node}, &response); err != nil {
+		t.Fatalf("unexpected error, %s", err)
+	}
+	if a := response.Attributes["cpu.realtime"]; a != "true" {
+		t.Fatalf("expected cpu.realtime to be true, got %q", a)
+	}
+
+	// The client doesn't give tasks a real-time runtime
+	response = FingerprintResponse{}
+	if err := f.Fingerprint(&FingerprintRequest{Config: &config.Config{}, Node: node}, &response); err != nil {
+		t.Fatalf("unexpected error, %s", err)
+	}
+	if a := response.Attributes["cpu.realtime"]; a != "" {
+		t.Fatalf("unexpected attribute found, %s", a)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	consul "github.com/hashicorp/consul/api"
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 const (
@@ -19,6 +20,13 @@ type ConsulFingerprint struct {
 	logger    log.Logger
 	client    *consul.Client
 	lastState string
+
+	// name is the name of the fingerprinted cluster, empty for the default
+	// cluster
+	name string
+
+	// clusters fingerprint the named Consul clusters
+	clusters map[string]*ConsulFingerprint
 }
 
 // NewConsulFingerprint is used to create a Consul fingerprint
@@ -27,10 +35,35 @@ func NewConsulFingerprint(logger log.Logger) Fingerprint {
 }
 
 func (f *ConsulFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
+	// Named clusters are fingerprinted under the consul.<name> attributes
+	for _, cconf := range req.Config.ConsulClusters {
+		cf, ok := f.clusters[cconf.Name]
+		if !ok {
+			cf = &ConsulFingerprint{
+				logger:    f.logger.With("consul_cluster", cconf.Name),
+				lastState: consulUnavailable,
+				name:      cconf.Name,
+			}
+			if f.clusters == nil {
+				f.clusters = make(map[string]*ConsulFingerprint)
+			}
+			f.clusters[cconf.Name] = cf
+		}
+		if err := cf.fingerprint(cconf, resp); err != nil {
+			return err
+		}
+	}
+
+	return f.fingerprint(req.Config.ConsulConfig, resp)
+}
+
+// fingerprint fingerprints the Consul cluster of the config. The attributes
+// of named clusters are limited to the ones constraints are expected on.
+func (f *ConsulFingerprint) fingerprint(conf *config.ConsulConfig, resp *FingerprintResponse) error {
 	// Only create the client once to avoid creating too many connections to
 	// Consul.
 	if f.client == nil {
-		consulConfig, err := req.Config.ConsulConfig.ApiConfig()
+		consulConfig, err := conf.ApiConfig()
 		if err != nil {
 			return fmt.Errorf("Failed to initialize the Consul client config: %v", err)
 		}
@@ -44,6 +77,9 @@ func (f *ConsulFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpri
 	// We'll try to detect consul by making a query to to the agent's self API.
 	// If we can't hit this URL consul is probably not running on this machine.
 	info, err := f.client.Agent().Self()
+	if f.name != "" {
+		return f.fingerprintCluster(info, err, resp)
+	}
 	if err != nil {
 		f.clearConsulAttributes(resp)
 
@@ -100,6 +136,39 @@ func (f *ConsulFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpri
 	return nil
 }
 
+// fingerprintCluster sets the attributes of a named cluster from the
+// response of the agent's self API.
+func (f *ConsulFingerprint) fingerprintCluster(info map[string]map[string]interface{}, err error, resp *FingerprintResponse) error {
+	prefix := "consul." + f.name
+	if err != nil {
+		resp.RemoveAttribute(prefix + ".version")
+		resp.RemoveAttribute(prefix + ".datacenter")
+		if f.lastState == consulAvailable {
+			f.logger.Info("consul agent is unavailable")
+		}
+		f.lastState = consulUnavailable
+		return nil
+	}
+
+	if v, ok := info["Config"]["Version"].(string); ok {
+		resp.AddAttribute(prefix+".version", v)
+	} else {
+		f.logger.Warn("unable to fingerprint consul version")
+	}
+	if d, ok := info["Config"]["Datacenter"].(string); ok {
+		resp.AddAttribute(prefix+".datacenter", d)
+	} else {
+		f.logger.Warn("unable to fingerprint consul datacenter")
+	}
+
+	if f.lastState == consulUnavailable {
+		f.logger.Info("consul agent is available")
+	}
+	f.lastState = consulAvailable
+	resp.Detected = true
+	return nil
+}
+
 // clearConsulAttributes removes consul attributes and links from the passed
 // Node.
 func (f *ConsulFingerprint) clearConsulAttributes(r *FingerprintResponse) {
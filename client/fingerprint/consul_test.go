@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,6 +51,44 @@ func TestConsulFingerprint(t *testing.T) {
 	}
 }
 
+func TestConsulFingerprint_Cluster(t *testing.T) {
+	fp := NewConsulFingerprint(testlog.HCLogger(t))
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, mockConsulResponse)
+	}))
+	defer ts.Close()
+
+	// Only the named cluster is reachable
+	conf := config.DefaultConfig()
+	conf.ConsulConfig.Addr = "127.0.0.1:1"
+	cluster := sconfig.DefaultConsulConfig()
+	cluster.Name = "other"
+	cluster.Addr = strings.TrimPrefix(ts.URL, "http://")
+	conf.ConsulClusters = []*sconfig.ConsulConfig{cluster}
+
+	request := &FingerprintRequest{Config: conf, Node: node}
+	var response FingerprintResponse
+	err := fp.Fingerprint(request, &response)
+	if err != nil {
+		t.Fatalf("Failed to fingerprint: %s", err)
+	}
+
+	if !response.Detected {
+		t.Fatalf("expected response to be applicable")
+	}
+
+	assertNodeAttributeContains(t, response.Attributes, "consul.other.version")
+	assertNodeAttributeContains(t, response.Attributes, "consul.other.datacenter")
+	if v := response.Attributes["consul.version"]; v != "" {
+		t.Errorf("Unexpected consul.version attribute %q", v)
+	}
+}
+
 // Taken from tryconsul using consul release 0.5.2
 const mockConsulResponse = `
 {
@@ -33,6 +33,15 @@ func (f *CPUFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintR
 				CpuShares: int64(totalCompute),
 			},
 		}
+
+		// All the cores of the node can be reserved by tasks
+		if numCores := stats.CPUNumCores(); numCores > 0 {
+			resp.NodeResources.Cpu.TotalCpuCores = uint16(numCores)
+			resp.NodeResources.Cpu.ReservableCpuCores = make([]uint16, numCores)
+			for i := range resp.NodeResources.Cpu.ReservableCpuCores {
+				resp.NodeResources.Cpu.ReservableCpuCores[i] = uint16(i)
+			}
+		}
 	}
 
 	if err := stats.Init(); err != nil {
@@ -52,6 +52,11 @@ func TestCPUFingerprint(t *testing.T) {
 	if response.NodeResources == nil || response.NodeResources.Cpu.CpuShares == 0 {
 		t.Fatalf("Expected to find CPU Resources")
 	}
+
+	cpu := response.NodeResources.Cpu
+	if cpu.TotalCpuCores == 0 || len(cpu.ReservableCpuCores) != int(cpu.TotalCpuCores) {
+		t.Fatalf("Expected to find reservable cores: %#v", cpu)
+	}
 }
 
 // TestCPUFingerprint_OverrideCompute asserts that setting cpu_total_compute in
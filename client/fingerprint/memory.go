@@ -2,6 +2,10 @@ package fingerprint
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -10,6 +14,9 @@ import (
 
 const bytesInMB = 1024 * 1024
 
+// hugePagesDir is the sysfs directory of the huge page pools of the kernel.
+var hugePagesDir = "/sys/kernel/mm/hugepages"
+
 // MemoryFingerprint is used to fingerprint the available memory on the node
 type MemoryFingerprint struct {
 	StaticFingerprinter
@@ -50,10 +57,38 @@ func (f *MemoryFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpri
 
 		resp.NodeResources = &structs.NodeResources{
 			Memory: structs.NodeMemoryResources{
-				MemoryMB: int64(totalMemory / bytesInMB),
+				MemoryMB:     int64(totalMemory / bytesInMB),
+				HugePages2MB: readHugePages(2048),
+				HugePages1GB: readHugePages(1024 * 1024),
 			},
 		}
+
+		if n := resp.NodeResources.Memory.HugePages2MB; n > 0 {
+			resp.AddAttribute("memory.hugepages_2mb", strconv.FormatInt(n, 10))
+		} else {
+			resp.RemoveAttribute("memory.hugepages_2mb")
+		}
+		if n := resp.NodeResources.Memory.HugePages1GB; n > 0 {
+			resp.AddAttribute("memory.hugepages_1gb", strconv.FormatInt(n, 10))
+		} else {
+			resp.RemoveAttribute("memory.hugepages_1gb")
+		}
 	}
 
 	return nil
 }
+
+// readHugePages returns the number of huge pages of the size in kB allocated
+// by the kernel, or zero if the kernel doesn't support huge pages.
+func readHugePages(sizeKB int) int64 {
+	path := filepath.Join(hugePagesDir, fmt.Sprintf("hugepages-%dkB", sizeKB), "nr_hugepages")
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(string(buf)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -1,6 +1,9 @@
 package fingerprint
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/config"
@@ -59,3 +62,32 @@ func TestMemoryFingerprint_Override(t *testing.T) {
 	require.Equal(response.Resources.MemoryMB, memoryMB)
 	require.EqualValues(response.NodeResources.Memory.MemoryMB, memoryMB)
 }
+
+func TestMemoryFingerprint_HugePages(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(os.MkdirAll(filepath.Join(dir, "hugepages-2048kB"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "hugepages-2048kB", "nr_hugepages"), []byte("512\n"), 0644))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "hugepages-1048576kB"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "hugepages-1048576kB", "nr_hugepages"), []byte("0\n"), 0644))
+
+	defer func(old string) { hugePagesDir = old }(hugePagesDir)
+	hugePagesDir = dir
+
+	f := NewMemoryFingerprint(testlog.HCLogger(t))
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	request := &FingerprintRequest{Config: &config.Config{MemoryMB: 15000}, Node: node}
+	var response FingerprintResponse
+	require.NoError(f.Fingerprint(request, &response))
+
+	require.EqualValues(512, response.NodeResources.Memory.HugePages2MB)
+	require.EqualValues(0, response.NodeResources.Memory.HugePages1GB)
+	require.Equal("512", response.Attributes["memory.hugepages_2mb"])
+	require.Equal("", response.Attributes["memory.hugepages_1gb"])
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	vapi "github.com/hashicorp/vault/api"
 )
 
@@ -20,24 +21,52 @@ type VaultFingerprint struct {
 	logger    log.Logger
 	client    *vapi.Client
 	lastState string
+
+	// prefix is the prefix of the attributes of the fingerprinted cluster
+	prefix string
+
+	// clusters fingerprint the named Vault clusters
+	clusters map[string]*VaultFingerprint
 }
 
 // NewVaultFingerprint is used to create a Vault fingerprint
 func NewVaultFingerprint(logger log.Logger) Fingerprint {
-	return &VaultFingerprint{logger: logger.Named("vault"), lastState: vaultUnavailable}
+	return &VaultFingerprint{logger: logger.Named("vault"), lastState: vaultUnavailable, prefix: "vault"}
 }
 
 func (f *VaultFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
-	config := req.Config
+	// Named clusters are fingerprinted under the vault.<name> attributes
+	for _, vconf := range req.Config.VaultClusters {
+		cf, ok := f.clusters[vconf.Name]
+		if !ok {
+			cf = &VaultFingerprint{
+				logger:    f.logger.With("vault_cluster", vconf.Name),
+				lastState: vaultUnavailable,
+				prefix:    "vault." + vconf.Name,
+			}
+			if f.clusters == nil {
+				f.clusters = make(map[string]*VaultFingerprint)
+			}
+			f.clusters[vconf.Name] = cf
+		}
+		if err := cf.fingerprint(vconf, resp); err != nil {
+			return err
+		}
+	}
+
+	return f.fingerprint(req.Config.VaultConfig, resp)
+}
 
-	if config.VaultConfig == nil || !config.VaultConfig.IsEnabled() {
+// fingerprint fingerprints the Vault cluster of the config.
+func (f *VaultFingerprint) fingerprint(conf *config.VaultConfig, resp *FingerprintResponse) error {
+	if conf == nil || !conf.IsEnabled() {
 		return nil
 	}
 
 	// Only create the client once to avoid creating too many connections to
 	// Vault.
 	if f.client == nil {
-		vaultConfig, err := config.VaultConfig.ApiConfig()
+		vaultConfig, err := conf.ApiConfig()
 		if err != nil {
 			return fmt.Errorf("Failed to initialize the Vault client config: %v", err)
 		}
@@ -60,12 +89,12 @@ func (f *VaultFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerprin
 		return nil
 	}
 
-	resp.AddAttribute("vault.accessible", strconv.FormatBool(true))
+	resp.AddAttribute(f.prefix+".accessible", strconv.FormatBool(true))
 	// We strip the Vault prefix because < 0.6.2 the version looks like:
 	// status.Version = "Vault v0.6.1"
-	resp.AddAttribute("vault.version", strings.TrimPrefix(status.Version, "Vault "))
-	resp.AddAttribute("vault.cluster_id", status.ClusterID)
-	resp.AddAttribute("vault.cluster_name", status.ClusterName)
+	resp.AddAttribute(f.prefix+".version", strings.TrimPrefix(status.Version, "Vault "))
+	resp.AddAttribute(f.prefix+".cluster_id", status.ClusterID)
+	resp.AddAttribute(f.prefix+".cluster_name", status.ClusterName)
 
 	// If Vault was previously unavailable print a message to indicate the Agent
 	// is available now
@@ -82,8 +111,8 @@ func (f *VaultFingerprint) Periodic() (bool, time.Duration) {
 }
 
 func (f *VaultFingerprint) clearVaultAttributes(r *FingerprintResponse) {
-	r.RemoveAttribute("vault.accessible")
-	r.RemoveAttribute("vault.version")
-	r.RemoveAttribute("vault.cluster_id")
-	r.RemoveAttribute("vault.cluster_name")
+	r.RemoveAttribute(f.prefix + ".accessible")
+	r.RemoveAttribute(f.prefix + ".version")
+	r.RemoveAttribute(f.prefix + ".cluster_id")
+	r.RemoveAttribute(f.prefix + ".cluster_name")
 }
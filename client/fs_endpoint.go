@@ -30,7 +30,7 @@ var (
 	allocIDNotPresentErr = fmt.Errorf("must provide a valid alloc id")
 	pathNotPresentErr    = fmt.Errorf("must provide a file path")
 	taskNotPresentErr    = fmt.Errorf("must provide task name")
-	logTypeNotPresentErr = fmt.Errorf("must provide log type (stdout/stderr)")
+	logTypeNotPresentErr = fmt.Errorf("must provide log type (stdout/stderr/eventlog)")
 	invalidOrigin        = fmt.Errorf("origin must be start or end")
 )
 
@@ -78,6 +78,7 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 	f := &FileSystem{c}
 	f.c.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.c.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.c.streamingRpcs.Register("FileSystem.Archive", f.archive)
 	return f
 }
 
@@ -143,6 +144,33 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	return nil
 }
 
+// Write is used to write a file in the allocation's directory.
+func (f *FileSystem) Write(args *cstructs.FsWriteRequest, reply *structs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "file_system", "write"}, time.Now())
+
+	// Check write permissions
+	if aclObj, err := f.c.ResolveToken(args.QueryOptions.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityWriteFS) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Path == "" {
+		return pathNotPresentErr
+	}
+
+	fs, err := f.c.GetAllocFS(args.AllocID)
+	if err != nil {
+		return err
+	}
+
+	mode := args.FileMode
+	if mode == 0 {
+		mode = 0644
+	}
+	return fs.Write(args.Path, bytes.NewReader(args.Data), mode)
+}
+
 // stream is is used to stream the contents of file in an allocation's
 // directory.
 func (f *FileSystem) stream(conn io.ReadWriteCloser) {
@@ -317,6 +345,105 @@ OUTER:
 	}
 }
 
+// archive is used to stream a tar archive of a directory of an allocation.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "archive"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check read permissions
+	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		f.handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(req.Namespace, acl.NamespaceCapabilityReadFS) {
+		f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.AllocID == "" {
+		f.handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if req.Path == "" {
+		f.handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	fs, err := f.c.GetAllocFS(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		f.handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	fileInfo, err := fs.Stat(req.Path)
+	if err != nil {
+		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if !fileInfo.IsDir {
+		f.handleStreamResultError(
+			fmt.Errorf("file %q is not a directory", req.Path),
+			helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Write the archive into a pipe which is read in frames
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(fs.Archive(pw, req.Path, req.Checksum))
+	}()
+
+	// Create a goroutine to detect the remote side closing, which stops
+	// writing the archive
+	go func() {
+		for {
+			if _, err := conn.Read(nil); err != nil {
+				pr.CloseWithError(err)
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, streamFrameSize)
+	for {
+		n, err := io.ReadFull(pr, buf)
+		if n > 0 {
+			resp := cstructs.StreamErrWrapper{Payload: buf[:n]}
+			if err := encoder.Encode(resp); err != nil {
+				f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+				return
+			}
+			encoder.Reset(conn)
+		}
+
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			// The archive was fully written
+			return
+		default:
+			f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		}
+	}
+}
+
 // logs is is used to stream a task's logs.
 func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	defer metrics.MeasureSince([]string{"client", "file_system", "logs"}, time.Now())
@@ -355,7 +482,7 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		return
 	}
 	switch req.LogType {
-	case "stdout", "stderr":
+	case "stdout", "stderr", "eventlog":
 	default:
 		f.handleStreamResultError(logTypeNotPresentErr, helper.Int64ToPtr(400), encoder)
 		return
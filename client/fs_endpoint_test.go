@@ -1,6 +1,8 @@
 package client
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -111,6 +113,120 @@ func TestFS_Stat(t *testing.T) {
 	require.True(resp.Info.IsDir)
 }
 
+func TestFS_Write(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := nomad.TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Create and add an alloc
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+
+	// Make the request without a path
+	req := &cstructs.FsWriteRequest{
+		AllocID:      alloc.ID,
+		Data:         []byte("foo"),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	var resp structs.GenericResponse
+	err := c.ClientRPC("FileSystem.Write", req, &resp)
+	require.EqualError(err, pathNotPresentErr.Error())
+
+	// Write the file
+	req.Path = "alloc/data/debug/foo"
+	require.Nil(c.ClientRPC("FileSystem.Write", req, &resp))
+
+	statReq := &cstructs.FsStatRequest{
+		AllocID:      alloc.ID,
+		Path:         req.Path,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var statResp cstructs.FsStatResponse
+	require.Nil(c.ClientRPC("FileSystem.Stat", statReq, &statResp))
+	require.False(statResp.Info.IsDir)
+	require.EqualValues(3, statResp.Info.Size)
+	require.Equal("-rw-r--r--", statResp.Info.FileMode)
+}
+
+func TestFS_Write_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := nomad.TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Create a read only token and a write token
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityWriteFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "read token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: structs.ErrUnknownAllocationPrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: structs.ErrUnknownAllocationPrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FsWriteRequest{
+				AllocID: uuid.Generate(),
+				Path:    "alloc/data/foo",
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+					Namespace: structs.DefaultNamespace,
+				},
+			}
+
+			var resp structs.GenericResponse
+			err := client.ClientRPC("FileSystem.Write", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
+
 func TestFS_Stat_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -796,6 +912,126 @@ OUTER:
 	}
 }
 
+func TestFS_Archive_NoAlloc(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a client
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Make the request with bad allocation id
+	req := &cstructs.FsArchiveRequest{
+		AllocID:      uuid.Generate(),
+		Path:         "/",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("FileSystem.Archive")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	var msg cstructs.StreamErrWrapper
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	require.NoError(decoder.Decode(&msg))
+	require.NotNil(msg.Error)
+	require.True(structs.IsErrUnknownAllocation(msg.Error))
+}
+
+func TestFS_Archive(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := nomad.TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	expected := "Hello from the other side"
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for":       "2s",
+		"stdout_string": expected,
+	}
+
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+
+	// Make the request
+	req := &cstructs.FsArchiveRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/logs",
+		Checksum:     true,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("FileSystem.Archive")
+	require.Nil(err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Send the request
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(encoder.Encode(req))
+
+	// Read the archive until the handler closes the pipe
+	var archive bytes.Buffer
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	for {
+		var msg cstructs.StreamErrWrapper
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF || strings.Contains(err.Error(), "closed") {
+				break
+			}
+			t.Fatalf("error decoding: %v", err)
+		}
+		require.Nil(msg.Error)
+		archive.Write(msg.Payload)
+	}
+
+	files := map[string]string{}
+	tr := tar.NewReader(&archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+
+		contents, err := ioutil.ReadAll(tr)
+		require.NoError(err)
+		files[hdr.Name] = string(contents)
+	}
+
+	require.Equal(expected, files["web.stdout.0"])
+	require.Contains(files[allocdir.ArchiveChecksumFile], "web.stdout.0")
+}
+
 func TestFS_Logs_NoAlloc(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
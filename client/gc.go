@@ -7,6 +7,7 @@ import (
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/stats"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -131,6 +132,7 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		diskStats := a.statsCollector.Stats().AllocDirStats
 		reason := ""
 		logf := a.logger.Warn
+		diskPressure := false
 
 		liveAllocs := a.allocCounter.NumAllocs()
 
@@ -138,9 +140,11 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 		case diskStats.UsedPercent > a.config.DiskUsageThreshold:
 			reason = fmt.Sprintf("disk usage of %.0f is over gc threshold of %.0f",
 				diskStats.UsedPercent, a.config.DiskUsageThreshold)
+			diskPressure = true
 		case diskStats.InodesUsedPercent > a.config.InodeUsageThreshold:
 			reason = fmt.Sprintf("inode usage of %.0f is over gc threshold of %.0f",
 				diskStats.InodesUsedPercent, a.config.InodeUsageThreshold)
+			diskPressure = true
 		case liveAllocs > a.config.MaxAllocs:
 			// if we're unable to gc, don't WARN until at least 2x over limit
 			if liveAllocs < (a.config.MaxAllocs * 2) {
@@ -154,6 +158,22 @@ func (a *AllocGarbageCollector) keepUsageBelowThreshold() error {
 			break
 		}
 
+		// The alloc dirs of the collected allocations are deleted in the
+		// background, so wait for them to free their space before
+		// collecting more allocations
+		if diskPressure {
+			select {
+			case <-allocdir.DeletesDone():
+			default:
+				select {
+				case <-allocdir.DeletesDone():
+				case <-a.shutdownCh:
+					return nil
+				}
+				continue
+			}
+		}
+
 		// Collect an allocation
 		gcAlloc := a.allocRunners.Pop()
 		if gcAlloc == nil {
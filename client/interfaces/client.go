@@ -16,6 +16,11 @@ type AllocStateHandler interface {
 	AllocStateUpdated(alloc *structs.Allocation)
 }
 
+// RPCer is the interface used to make RPC calls to the servers
+type RPCer interface {
+	RPC(method string, args interface{}, reply interface{}) error
+}
+
 // DeviceStatsReporter gives access to the latest resource usage
 // for devices
 type DeviceStatsReporter interface {
@@ -0,0 +1,273 @@
+// Package keyring encrypts the sensitive data the client persists under its
+// state directory, such as the client state database and its secret ID, with
+// a node key. The node key is generated by the client on its first start and
+// may be wrapped by a key management service, so that a copy of the state
+// directory alone doesn't expose its content.
+package keyring
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// keyFile is the name of the file storing the node key in the state
+	// directory
+	keyFile = "node-key"
+
+	// keySize is the size in bytes of the AES-256 node key
+	keySize = 32
+)
+
+// fileHeader prefixes the files encrypted by the keyring so that files
+// written in plaintext before encryption was enabled can still be read.
+var fileHeader = []byte("nomad-encrypted:v1\n")
+
+// Wrapper wraps the node key with a key management service so that it isn't
+// stored in plaintext.
+type Wrapper interface {
+	// Name is the name of the wrapper, stored with the wrapped key.
+	Name() string
+
+	Wrap(key []byte) ([]byte, error)
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+// storedKey is the node key as stored in its file.
+type storedKey struct {
+	// Wrapper is the name of the wrapper of the key or empty if the key is
+	// stored in plaintext.
+	Wrapper string `json:",omitempty"`
+
+	Key []byte
+}
+
+// Keyring encrypts data with the node key using AES-256-GCM.
+type Keyring struct {
+	aead cipher.AEAD
+}
+
+// New returns a Keyring encrypting data with the key.
+func New(key []byte) (*Keyring, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("node key must be %d bytes, got %d", keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{aead: aead}, nil
+}
+
+// Load returns the Keyring of the node key stored in the state directory,
+// generating the key if it doesn't exist yet. If the wrapper is not nil the
+// key is stored wrapped by it, and a key stored in plaintext by a previous
+// run is wrapped.
+func Load(stateDir string, wrapper Wrapper) (*Keyring, error) {
+	path := filepath.Join(stateDir, keyFile)
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return create(path, wrapper)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read node key: %v", err)
+	}
+
+	var stored storedKey
+	if err := json.Unmarshal(buf, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode node key: %v", err)
+	}
+
+	key := stored.Key
+	switch {
+	case stored.Wrapper == "":
+		// Wrap the key if it was stored in plaintext before a wrapper was
+		// configured
+		if wrapper != nil {
+			if err := store(path, key, wrapper); err != nil {
+				return nil, err
+			}
+		}
+	case wrapper == nil:
+		return nil, fmt.Errorf("node key is wrapped by %q but no key wrapper is configured", stored.Wrapper)
+	case wrapper.Name() != stored.Wrapper:
+		return nil, fmt.Errorf("node key is wrapped by %q but the %q key wrapper is configured", stored.Wrapper, wrapper.Name())
+	default:
+		key, err = wrapper.Unwrap(stored.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap node key with %q: %v", wrapper.Name(), err)
+		}
+	}
+
+	return New(key)
+}
+
+// create generates and stores a new node key.
+func create(path string, wrapper Wrapper) (*Keyring, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate node key: %v", err)
+	}
+	if err := store(path, key, wrapper); err != nil {
+		return nil, err
+	}
+	return New(key)
+}
+
+// store writes the node key to its file, wrapped by the wrapper if it isn't
+// nil.
+func store(path string, key []byte, wrapper Wrapper) error {
+	stored := storedKey{Key: key}
+	if wrapper != nil {
+		wrapped, err := wrapper.Wrap(key)
+		if err != nil {
+			return fmt.Errorf("failed to wrap node key with %q: %v", wrapper.Name(), err)
+		}
+		stored = storedKey{Wrapper: wrapper.Name(), Key: wrapped}
+	}
+
+	buf, err := json.Marshal(&stored)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, buf, 0600); err != nil {
+		return fmt.Errorf("failed to write node key: %v", err)
+	}
+	return nil
+}
+
+// Encrypt encrypts the plaintext, prefixing it with a random nonce.
+func (k *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	return k.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt decrypts ciphertext encrypted by Encrypt.
+func (k *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	size := k.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("malformed ciphertext")
+	}
+	return k.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+}
+
+// WriteFile encrypts the data and atomically writes it to the file.
+func (k *Keyring) WriteFile(path string, data []byte, perm os.FileMode) error {
+	ciphertext, err := k.Encrypt(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(append([]byte(nil), fileHeader...), ciphertext...), perm)
+}
+
+// ReadFile reads and decrypts a file written by WriteFile. Files written in
+// plaintext are returned as is.
+func (k *Keyring) ReadFile(path string) ([]byte, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(buf, fileHeader) {
+		return buf, nil
+	}
+
+	plaintext, err := k.Decrypt(buf[len(fileHeader):])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %v", path, err)
+	}
+	return plaintext, nil
+}
+
+// EncryptFile encrypts the file in place. The file is rewritten rather than
+// replaced so that it keeps its mode and owner, which may be the task user.
+// Files that are already encrypted are left as is.
+func (k *Keyring) EncryptFile(path string) error {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.HasPrefix(buf, fileHeader) {
+		return nil
+	}
+
+	ciphertext, err := k.Encrypt(buf)
+	if err != nil {
+		return err
+	}
+	return rewriteFile(path, append(append([]byte(nil), fileHeader...), ciphertext...))
+}
+
+// DecryptFile decrypts a file encrypted by EncryptFile or WriteFile in place.
+// Files in plaintext are left as is.
+func (k *Keyring) DecryptFile(path string) error {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !bytes.HasPrefix(buf, fileHeader) {
+		return nil
+	}
+
+	plaintext, err := k.Decrypt(buf[len(fileHeader):])
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %v", path, err)
+	}
+	return rewriteFile(path, plaintext)
+}
+
+// IsEncryptedFile returns whether the file was written by WriteFile.
+func IsEncryptedFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	header := make([]byte, len(fileHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, fileHeader)
+}
+
+// rewriteFile truncates the existing file and writes data to it.
+func rewriteFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeFileAtomic writes the file through a temporary file so that a crash
+// never leaves it partially written.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
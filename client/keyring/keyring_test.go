@@ -0,0 +1,220 @@
+package keyring
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reverseWrapper is an insecure Wrapper for testing.
+type reverseWrapper struct {
+	name string
+}
+
+func (w *reverseWrapper) Name() string {
+	return w.name
+}
+
+func (w *reverseWrapper) Wrap(key []byte) ([]byte, error) {
+	out := make([]byte, len(key))
+	for i, b := range key {
+		out[len(key)-1-i] = b
+	}
+	return out, nil
+}
+
+func (w *reverseWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	return w.Wrap(wrapped)
+}
+
+func TestKeyring_Load(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// The key is generated on the first load and reused after
+	k1, err := Load(dir, nil)
+	require.NoError(err)
+	ciphertext, err := k1.Encrypt([]byte("secret"))
+	require.NoError(err)
+	require.NotContains(string(ciphertext), "secret")
+
+	k2, err := Load(dir, nil)
+	require.NoError(err)
+	plaintext, err := k2.Decrypt(ciphertext)
+	require.NoError(err)
+	require.Equal("secret", string(plaintext))
+
+	// Configuring a wrapper wraps the plaintext key
+	wrapper := &reverseWrapper{name: "reverse"}
+	k3, err := Load(dir, wrapper)
+	require.NoError(err)
+	plaintext, err = k3.Decrypt(ciphertext)
+	require.NoError(err)
+	require.Equal("secret", string(plaintext))
+
+	var stored storedKey
+	buf, err := ioutil.ReadFile(filepath.Join(dir, keyFile))
+	require.NoError(err)
+	require.NoError(json.Unmarshal(buf, &stored))
+	require.Equal("reverse", stored.Wrapper)
+
+	// Wrapped keys need their wrapper
+	_, err = Load(dir, nil)
+	require.Error(err)
+	require.Contains(err.Error(), `node key is wrapped by "reverse" but no key wrapper is configured`)
+
+	_, err = Load(dir, &reverseWrapper{name: "other"})
+	require.Error(err)
+	require.Contains(err.Error(), `node key is wrapped by "reverse" but the "other" key wrapper is configured`)
+
+	k4, err := Load(dir, wrapper)
+	require.NoError(err)
+	plaintext, err = k4.Decrypt(ciphertext)
+	require.NoError(err)
+	require.Equal("secret", string(plaintext))
+}
+
+func TestKeyring_File(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	k, err := Load(dir, nil)
+	require.NoError(err)
+
+	// Encrypted files are decrypted
+	path := filepath.Join(dir, "secret-id")
+	require.NoError(k.WriteFile(path, []byte("secret"), 0600))
+	buf, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.NotContains(string(buf), "secret")
+
+	buf, err = k.ReadFile(path)
+	require.NoError(err)
+	require.Equal("secret", string(buf))
+	require.True(IsEncryptedFile(path))
+
+	// Plaintext files are read as is
+	require.NoError(ioutil.WriteFile(path, []byte("plaintext"), 0600))
+	buf, err = k.ReadFile(path)
+	require.NoError(err)
+	require.Equal("plaintext", string(buf))
+	require.False(IsEncryptedFile(path))
+
+	// Files encrypted with another key can't be read
+	other, err := New(make([]byte, keySize))
+	require.NoError(err)
+	require.NoError(other.WriteFile(path, []byte("secret"), 0600))
+	_, err = k.ReadFile(path)
+	require.Error(err)
+}
+
+func TestVaultTransitWrapper(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Fake the encrypt and decrypt endpoints of a transit key, reversing
+	// the base64 encoded plaintext
+	reverse := func(s string) string {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+			return
+		}
+
+		var req map[string]string
+		require.NoError(json.NewDecoder(r.Body).Decode(&req))
+
+		var data map[string]string
+		switch r.URL.Path {
+		case "/v1/secrets/encrypt/nomad":
+			data = map[string]string{"ciphertext": "vault:v1:" + reverse(req["plaintext"])}
+		case "/v1/secrets/decrypt/nomad":
+			data = map[string]string{"plaintext": reverse(strings.TrimPrefix(req["ciphertext"], "vault:v1:"))}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer ts.Close()
+
+	w, err := NewVaultTransitWrapper(ts.URL, "root", "secrets", "nomad")
+	require.NoError(err)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := w.Wrap(key)
+	require.NoError(err)
+	require.True(strings.HasPrefix(string(wrapped), "vault:v1:"))
+
+	unwrapped, err := w.Unwrap(wrapped)
+	require.NoError(err)
+	require.Equal(key, unwrapped)
+
+	// Vault errors are returned
+	w, err = NewVaultTransitWrapper(ts.URL, "bad", "secrets", "nomad")
+	require.NoError(err)
+	_, err = w.Wrap(key)
+	require.Error(err)
+	require.Contains(err.Error(), "permission denied")
+}
+
+func TestKeyring_EncryptFile(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	k, err := Load(dir, nil)
+	require.NoError(err)
+
+	path := filepath.Join(dir, "vault_token")
+	require.NoError(ioutil.WriteFile(path, []byte("secret"), 0640))
+
+	// Encrypting keeps the mode and is idempotent
+	require.NoError(k.EncryptFile(path))
+	require.True(IsEncryptedFile(path))
+	encrypted, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.NotContains(string(encrypted), "secret")
+	require.NoError(k.EncryptFile(path))
+	buf, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal(encrypted, buf)
+
+	fi, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0640), fi.Mode().Perm())
+
+	// Decrypting restores the plaintext and is idempotent
+	require.NoError(k.DecryptFile(path))
+	require.NoError(k.DecryptFile(path))
+	buf, err = ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("secret", string(buf))
+	require.False(IsEncryptedFile(path))
+}
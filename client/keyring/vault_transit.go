@@ -0,0 +1,107 @@
+package keyring
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+const (
+	// VaultTransitWrapperName is the name of the wrapper using the Vault
+	// transit secrets engine.
+	VaultTransitWrapperName = "vault_transit"
+
+	// DefaultVaultTransitMount is the default mount path of the Vault
+	// transit secrets engine.
+	DefaultVaultTransitMount = "transit"
+)
+
+// VaultTransitWrapper wraps the node key with a key of the Vault transit
+// secrets engine, so the node key can only be read while Vault is reachable
+// and the token of the client is allowed to decrypt with the key.
+type VaultTransitWrapper struct {
+	client *vaultapi.Client
+	mount  string
+	key    string
+}
+
+// NewVaultTransitWrapper returns a wrapper using the transit key mounted at
+// the mount path. The address and token of Vault default to the VAULT_ADDR
+// and VAULT_TOKEN environment variables if empty.
+func NewVaultTransitWrapper(address, token, mount, key string) (*VaultTransitWrapper, error) {
+	conf := vaultapi.DefaultConfig()
+	if conf.Error != nil {
+		return nil, conf.Error
+	}
+	if address != "" {
+		conf.Address = address
+	}
+
+	client, err := vaultapi.NewClient(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault client: %v", err)
+	}
+	if token != "" {
+		client.SetToken(token)
+	}
+
+	if mount == "" {
+		mount = DefaultVaultTransitMount
+	}
+	return &VaultTransitWrapper{
+		client: client,
+		mount:  mount,
+		key:    key,
+	}, nil
+}
+
+// Name implements Wrapper.
+func (w *VaultTransitWrapper) Name() string {
+	return VaultTransitWrapperName
+}
+
+// Wrap implements Wrapper.
+func (w *VaultTransitWrapper) Wrap(key []byte) ([]byte, error) {
+	secret, err := w.client.Logical().Write(path.Join(w.mount, "encrypt", w.key), map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := w.field(secret, "ciphertext")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(ciphertext), nil
+}
+
+// Unwrap implements Wrapper.
+func (w *VaultTransitWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	secret, err := w.client.Logical().Write(path.Join(w.mount, "decrypt", w.key), map[string]interface{}{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := w.field(secret, "plaintext")
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(plaintext)
+}
+
+// field returns the string field of the data of the response.
+func (w *VaultTransitWrapper) field(secret *vaultapi.Secret, name string) (string, error) {
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("empty response from Vault")
+	}
+	v, ok := secret.Data[name].(string)
+	if !ok || v == "" {
+		return "", fmt.Errorf("missing %s in response from Vault", name)
+	}
+	return v, nil
+}
@@ -0,0 +1,101 @@
+// Package eventlog reads the events of the Windows Event Log.
+package eventlog
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultChannel is the channel events are read from if none is given.
+const DefaultChannel = "Application"
+
+// ErrNotSupported is returned when subscribing to the event log on a platform
+// other than Windows.
+var ErrNotSupported = errors.New("the Windows event log is only supported on Windows")
+
+// levels are the names of the standard event levels.
+var levels = map[int]string{
+	0: "Information",
+	1: "Critical",
+	2: "Error",
+	3: "Warning",
+	4: "Information",
+	5: "Verbose",
+}
+
+// Event is an event of the event log.
+type Event struct {
+	TimeCreated time.Time
+	Provider    string
+	EventID     uint32
+	Level       string
+	Message     string
+}
+
+// Format returns the event as a single log line.
+func (e *Event) Format() string {
+	return fmt.Sprintf("%s [%s] %s (%d): %s\n",
+		e.TimeCreated.UTC().Format(time.RFC3339), e.Level, e.Provider, e.EventID, e.Message)
+}
+
+// Query returns the XPath query selecting the events of the providers.
+func Query(providers []string) string {
+	names := make([]string, len(providers))
+	for i, p := range providers {
+		names[i] = fmt.Sprintf("@Name='%s'", p)
+	}
+	return fmt.Sprintf("*[System[Provider[%s]]]", strings.Join(names, " or "))
+}
+
+// eventXML is the XML rendering of an event. The rendering info is only
+// present if the event was formatted with the metadata of its provider.
+type eventXML struct {
+	Provider struct {
+		Name string `xml:"Name,attr"`
+	} `xml:"System>Provider"`
+	EventID     uint32 `xml:"System>EventID"`
+	Level       int    `xml:"System>Level"`
+	TimeCreated struct {
+		SystemTime string `xml:"SystemTime,attr"`
+	} `xml:"System>TimeCreated"`
+	Data          []string `xml:"EventData>Data"`
+	Message       string   `xml:"RenderingInfo>Message"`
+	RenderedLevel string   `xml:"RenderingInfo>Level"`
+}
+
+// parseEvent parses the XML rendering of an event. Events without a formatted
+// message use their data as the message.
+func parseEvent(buf []byte) (*Event, error) {
+	var x eventXML
+	if err := xml.Unmarshal(buf, &x); err != nil {
+		return nil, fmt.Errorf("failed to parse event: %v", err)
+	}
+
+	e := &Event{
+		Provider: x.Provider.Name,
+		EventID:  x.EventID,
+		Level:    x.RenderedLevel,
+		Message:  x.Message,
+	}
+	if x.TimeCreated.SystemTime != "" {
+		t, err := time.Parse(time.RFC3339Nano, x.TimeCreated.SystemTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse event time: %v", err)
+		}
+		e.TimeCreated = t
+	}
+	if e.Level == "" {
+		e.Level = levels[x.Level]
+		if e.Level == "" {
+			e.Level = fmt.Sprintf("Level %d", x.Level)
+		}
+	}
+	if e.Message == "" {
+		e.Message = strings.Join(x.Data, " ")
+	}
+	e.Message = strings.TrimSpace(strings.Replace(e.Message, "\r\n", "\n", -1))
+	return e, nil
+}
@@ -0,0 +1,14 @@
+// +build !windows
+
+package eventlog
+
+import "context"
+
+// Supported is whether the event log can be read on this platform.
+const Supported = false
+
+// Subscribe returns ErrNotSupported as the event log is only supported on
+// Windows.
+func Subscribe(ctx context.Context, channel string, providers []string, fn func(*Event)) error {
+	return ErrNotSupported
+}
@@ -0,0 +1,63 @@
+package eventlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+	require.Equal(t, "*[System[Provider[@Name='app']]]", Query([]string{"app"}))
+	require.Equal(t, "*[System[Provider[@Name='app' or @Name='MSSQLSERVER']]]",
+		Query([]string{"app", "MSSQLSERVER"}))
+}
+
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// An event formatted with the metadata of its provider
+	formatted := `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'>
+  <System>
+    <Provider Name='MSSQLSERVER'/>
+    <EventID Qualifiers='49152'>17058</EventID>
+    <Level>2</Level>
+    <TimeCreated SystemTime='2019-01-02T03:04:05.1234567Z'/>
+  </System>
+  <EventData><Data>c:\errorlog</Data></EventData>
+  <RenderingInfo Culture='en-US'>
+    <Message>initerrlog: Could not open error log file 'c:\errorlog'.&#13;&#10;</Message>
+    <Level>Error</Level>
+  </RenderingInfo>
+</Event>`
+
+	e, err := parseEvent([]byte(formatted))
+	require.NoError(err)
+	require.Equal("MSSQLSERVER", e.Provider)
+	require.Equal(uint32(17058), e.EventID)
+	require.Equal("Error", e.Level)
+	require.Equal(`initerrlog: Could not open error log file 'c:\errorlog'.`, e.Message)
+	require.Equal(time.Date(2019, 1, 2, 3, 4, 5, 123456700, time.UTC), e.TimeCreated)
+	require.Equal("2019-01-02T03:04:05Z [Error] MSSQLSERVER (17058): initerrlog: Could not open error log file 'c:\\errorlog'.\n", e.Format())
+
+	// An event without rendering info uses its data as the message
+	raw := `<Event xmlns='http://schemas.microsoft.com/win/2004/08/events/event'>
+  <System>
+    <Provider Name='app'/>
+    <EventID>1</EventID>
+    <Level>3</Level>
+    <TimeCreated SystemTime='2019-01-02T03:04:05Z'/>
+  </System>
+  <EventData><Data>disk</Data><Data>almost full</Data></EventData>
+</Event>`
+
+	e, err = parseEvent([]byte(raw))
+	require.NoError(err)
+	require.Equal("Warning", e.Level)
+	require.Equal("disk almost full", e.Message)
+
+	_, err = parseEvent([]byte("<Event>"))
+	require.Error(err)
+}
@@ -0,0 +1,201 @@
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Supported is whether the event log can be read on this platform.
+const Supported = true
+
+var (
+	modwevtapi = windows.NewLazySystemDLL("wevtapi.dll")
+
+	procEvtSubscribe             = modwevtapi.NewProc("EvtSubscribe")
+	procEvtNext                  = modwevtapi.NewProc("EvtNext")
+	procEvtRender                = modwevtapi.NewProc("EvtRender")
+	procEvtFormatMessage         = modwevtapi.NewProc("EvtFormatMessage")
+	procEvtOpenPublisherMetadata = modwevtapi.NewProc("EvtOpenPublisherMetadata")
+	procEvtClose                 = modwevtapi.NewProc("EvtClose")
+)
+
+const (
+	evtSubscribeToFutureEvents = 1
+	evtRenderEventXml          = 1
+	evtFormatMessageXml        = 9
+
+	errorNoMoreItems syscall.Errno = 259
+
+	// pollInterval is how often the context is checked while waiting for
+	// events.
+	pollInterval = 500
+
+	// batchSize is the number of events read at once.
+	batchSize = 16
+)
+
+// Subscribe calls fn with the events of the providers written to the channel
+// from now on, until the context is cancelled.
+func Subscribe(ctx context.Context, channel string, providers []string, fn func(*Event)) error {
+	signal, err := windows.CreateEvent(nil, 1, 1, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create signal event: %v", err)
+	}
+	defer windows.CloseHandle(signal)
+
+	channelPtr, err := windows.UTF16PtrFromString(channel)
+	if err != nil {
+		return err
+	}
+	queryPtr, err := windows.UTF16PtrFromString(Query(providers))
+	if err != nil {
+		return err
+	}
+
+	sub, _, err := procEvtSubscribe.Call(0, uintptr(signal),
+		uintptr(unsafe.Pointer(channelPtr)), uintptr(unsafe.Pointer(queryPtr)),
+		0, 0, 0, evtSubscribeToFutureEvents)
+	if sub == 0 {
+		return fmt.Errorf("failed to subscribe to the %s channel: %v", channel, err)
+	}
+	defer evtClose(sub)
+
+	// Keep the metadata of the providers to format the messages of their
+	// events
+	publishers := make(map[string]uintptr)
+	defer func() {
+		for _, md := range publishers {
+			if md != 0 {
+				evtClose(md)
+			}
+		}
+	}()
+
+	events := make([]uintptr, batchSize)
+	for {
+		status, err := windows.WaitForSingleObject(signal, pollInterval)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if status == windows.WAIT_TIMEOUT {
+			continue
+		}
+		if status != windows.WAIT_OBJECT_0 {
+			return fmt.Errorf("failed to wait for events: %v", err)
+		}
+
+		for {
+			var returned uint32
+			r, _, err := procEvtNext.Call(sub, uintptr(len(events)),
+				uintptr(unsafe.Pointer(&events[0])), windows.INFINITE, 0,
+				uintptr(unsafe.Pointer(&returned)))
+			if r == 0 {
+				if err == errorNoMoreItems {
+					windows.ResetEvent(signal)
+					break
+				}
+				return fmt.Errorf("failed to read events: %v", err)
+			}
+
+			for _, h := range events[:returned] {
+				e, err := renderEvent(h, publishers)
+				evtClose(h)
+				if err != nil {
+					return err
+				}
+				fn(e)
+			}
+		}
+	}
+}
+
+// renderEvent returns the event of the handle, with its message formatted if
+// the metadata of its provider is available.
+func renderEvent(h uintptr, publishers map[string]uintptr) (*Event, error) {
+	buf, err := evtRender(h)
+	if err != nil {
+		return nil, err
+	}
+	e, err := parseEvent(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	md, ok := publishers[e.Provider]
+	if !ok {
+		md = evtOpenPublisherMetadata(e.Provider)
+		publishers[e.Provider] = md
+	}
+	if md == 0 {
+		return e, nil
+	}
+
+	buf, err = evtFormatMessage(md, h)
+	if err != nil {
+		return e, nil
+	}
+	if formatted, err := parseEvent(buf); err == nil {
+		return formatted, nil
+	}
+	return e, nil
+}
+
+// evtRender returns the XML rendering of the event.
+func evtRender(h uintptr) ([]byte, error) {
+	var used, count uint32
+	r, _, err := procEvtRender.Call(0, h, evtRenderEventXml, 0, 0,
+		uintptr(unsafe.Pointer(&used)), uintptr(unsafe.Pointer(&count)))
+	if r == 0 && err != windows.ERROR_INSUFFICIENT_BUFFER {
+		return nil, fmt.Errorf("failed to render event: %v", err)
+	}
+
+	// The size is in bytes
+	buf := make([]uint16, used/2+1)
+	r, _, err = procEvtRender.Call(0, h, evtRenderEventXml, uintptr(len(buf)*2),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&used)),
+		uintptr(unsafe.Pointer(&count)))
+	if r == 0 {
+		return nil, fmt.Errorf("failed to render event: %v", err)
+	}
+	return []byte(windows.UTF16ToString(buf)), nil
+}
+
+// evtFormatMessage returns the XML rendering of the event including its
+// formatted message.
+func evtFormatMessage(md, h uintptr) ([]byte, error) {
+	var used uint32
+	r, _, err := procEvtFormatMessage.Call(md, h, 0, 0, 0, evtFormatMessageXml, 0, 0,
+		uintptr(unsafe.Pointer(&used)))
+	if r == 0 && err != windows.ERROR_INSUFFICIENT_BUFFER {
+		return nil, fmt.Errorf("failed to format event: %v", err)
+	}
+
+	// The size is in characters
+	buf := make([]uint16, used+1)
+	r, _, err = procEvtFormatMessage.Call(md, h, 0, 0, 0, evtFormatMessageXml,
+		uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(unsafe.Pointer(&used)))
+	if r == 0 {
+		return nil, fmt.Errorf("failed to format event: %v", err)
+	}
+	return []byte(windows.UTF16ToString(buf)), nil
+}
+
+// evtOpenPublisherMetadata returns the metadata of the provider, or zero if
+// it's not available.
+func evtOpenPublisherMetadata(provider string) uintptr {
+	providerPtr, err := windows.UTF16PtrFromString(provider)
+	if err != nil {
+		return 0
+	}
+	md, _, _ := procEvtOpenPublisherMetadata.Call(0, uintptr(unsafe.Pointer(providerPtr)), 0, 0, 0)
+	return md
+}
+
+func evtClose(h uintptr) {
+	procEvtClose.Call(h)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeMeta endpoint is used for reading and updating the metadata of a client
+// at runtime
+type NodeMeta struct {
+	c *Client
+}
+
+// Apply is used to set or unset the node's metadata.
+func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if len(args.Meta) == 0 {
+		return errors.New("missing metadata to apply")
+	}
+
+	meta, dynamic, err := n.c.UpdateNodeMeta(args.Meta)
+	if err != nil {
+		return err
+	}
+
+	reply.Meta = meta
+	reply.Dynamic = dynamic
+	return nil
+}
+
+// Read is used to retrieve the node's metadata.
+func (n *NodeMeta) Read(args *nstructs.NodeSpecificRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	reply.Meta, reply.Dynamic = n.c.NodeMeta()
+	return nil
+}
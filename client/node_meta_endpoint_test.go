@@ -0,0 +1,179 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/config"
+	cstate "github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Apply(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr := testServer(t, nil)
+	defer server.Shutdown()
+
+	db := cstate.NewMemDB(testlog.HCLogger(t))
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.StateDBFactory = func(hclog.Logger, string) (cstate.StateDB, error) {
+			return db, nil
+		}
+		c.Node.Meta = map[string]string{
+			"static": "value",
+			"remove": "me",
+		}
+	})
+	defer cleanup()
+
+	// Apply without metadata fails
+	req := &structs.NodeMetaApplyRequest{}
+	var resp structs.NodeMetaResponse
+	require.EqualError(client.ClientRPC("NodeMeta.Apply", req, &resp), "missing metadata to apply")
+
+	req.Meta = map[string]*string{
+		"dynamic": helper.StringToPtr("set"),
+		"remove":  nil,
+	}
+	require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+
+	expected := map[string]string{
+		"static":  "value",
+		"dynamic": "set",
+	}
+	require.Equal(expected, resp.Meta)
+	require.Equal(req.Meta, resp.Dynamic)
+
+	// Reading returns the same metadata
+	var readResp structs.NodeMetaResponse
+	require.NoError(client.ClientRPC("NodeMeta.Read", &nstructs.NodeSpecificRequest{}, &readResp))
+	require.Equal(expected, readResp.Meta)
+	require.Equal(req.Meta, readResp.Dynamic)
+
+	// The metadata set at runtime is persisted
+	persisted, err := db.GetNodeMeta()
+	require.NoError(err)
+	require.Equal(req.Meta, persisted)
+
+	// The server sees the updated metadata
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := server.State().NodeByID(nil, client.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil {
+			return false, fmt.Errorf("node not registered")
+		}
+		if node.Meta["dynamic"] != "set" {
+			return false, fmt.Errorf("metadata not updated: %v", node.Meta)
+		}
+		if _, ok := node.Meta["remove"]; ok {
+			return false, fmt.Errorf("metadata not unset: %v", node.Meta)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestNodeMeta_Restore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db := cstate.NewMemDB(testlog.HCLogger(t))
+	require.NoError(db.PutNodeMeta(map[string]*string{
+		"dynamic": helper.StringToPtr("set"),
+		"static":  helper.StringToPtr("overridden"),
+		"remove":  nil,
+	}))
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.StateDBFactory = func(hclog.Logger, string) (cstate.StateDB, error) {
+			return db, nil
+		}
+		c.Node.Meta = map[string]string{
+			"static": "value",
+			"remove": "me",
+			"keep":   "me",
+		}
+	})
+	defer cleanup()
+
+	// The metadata set at runtime is applied over the configured metadata
+	require.Equal(map[string]string{
+		"dynamic": "set",
+		"static":  "overridden",
+		"keep":    "me",
+	}, client.Node().Meta)
+}
+
+func TestNodeMeta_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer cleanup()
+
+	meta := map[string]*string{"foo": helper.StringToPtr("bar")}
+
+	// Try request without a token and expect failure
+	{
+		req := &structs.NodeMetaApplyRequest{Meta: meta}
+		var resp structs.NodeMetaResponse
+		err := client.ClientRPC("NodeMeta.Apply", req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a read token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+		req := &structs.NodeMetaApplyRequest{Meta: meta}
+		req.AuthToken = token.SecretID
+
+		var resp structs.NodeMetaResponse
+		err := client.ClientRPC("NodeMeta.Apply", req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+		// Reading is allowed
+		readReq := &nstructs.NodeSpecificRequest{}
+		readReq.AuthToken = token.SecretID
+		require.NoError(client.ClientRPC("NodeMeta.Read", readReq, &resp))
+	}
+
+	// Try request with a write token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "write", mock.NodePolicy(acl.PolicyWrite))
+		req := &structs.NodeMetaApplyRequest{Meta: meta}
+		req.AuthToken = token.SecretID
+
+		var resp structs.NodeMetaResponse
+		require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+		require.Equal("bar", resp.Meta["foo"])
+	}
+
+	// Try request with a management token
+	{
+		req := &structs.NodeMetaApplyRequest{Meta: meta}
+		req.AuthToken = root.SecretID
+
+		var resp structs.NodeMetaResponse
+		require.NoError(client.ClientRPC("NodeMeta.Apply", req, &resp))
+	}
+}
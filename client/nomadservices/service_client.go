@@ -0,0 +1,490 @@
+package nomadservices
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	log "github.com/hashicorp/go-hclog"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// defaultCheckTimeout is the timeout of checks that don't set one
+	defaultCheckTimeout = 10 * time.Second
+)
+
+// ServiceAPI is the interface the task runner uses to register and remove
+// the services of tasks using the Nomad service provider.
+type ServiceAPI interface {
+	RegisterTask(*agentconsul.TaskServices) error
+	RemoveTask(*agentconsul.TaskServices)
+	UpdateTask(old, newTask *agentconsul.TaskServices) error
+}
+
+// ServiceClient registers the services of tasks using the Nomad service
+// provider with the servers and runs their health checks. The aggregated
+// status of the checks of a service is written to its registration whenever
+// it changes.
+type ServiceClient struct {
+	rpc    cinterfaces.RPCer
+	node   *structs.Node
+	region string
+	logger log.Logger
+
+	// services are the registrations of the client by ID
+	services map[string]*serviceRegistration
+	mu       sync.Mutex
+}
+
+// serviceRegistration tracks a registration and the status of its checks.
+type serviceRegistration struct {
+	reg *structs.ServiceRegistration
+
+	// checks is the last status of each check of the service by check ID
+	checks map[string]string
+
+	// ctx is canceled to stop the checks of the service
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewServiceClient returns a ServiceClient that registers services of the
+// node in the region.
+func NewServiceClient(logger log.Logger, rpc cinterfaces.RPCer, node *structs.Node, region string) *ServiceClient {
+	return &ServiceClient{
+		rpc:      rpc,
+		node:     node,
+		region:   region,
+		logger:   logger.Named("nomad_services"),
+		services: make(map[string]*serviceRegistration),
+	}
+}
+
+// RegisterTask registers the services of the task and starts their checks.
+// Services that are already registered have their checks restarted.
+func (c *ServiceClient) RegisterTask(task *agentconsul.TaskServices) error {
+	if len(task.Services) == 0 {
+		return nil
+	}
+
+	regs := make([]*serviceRegistration, 0, len(task.Services))
+	checks := make(map[*serviceRegistration][]*Check)
+	for _, service := range task.Services {
+		sreg, serviceChecks, err := c.serviceReg(task, service)
+		if err != nil {
+			for _, r := range regs {
+				r.cancel()
+			}
+			return err
+		}
+		regs = append(regs, sreg)
+		checks[sreg] = serviceChecks
+	}
+
+	c.mu.Lock()
+	upserts := make([]*structs.ServiceRegistration, 0, len(regs))
+	for _, sreg := range regs {
+		if existing, ok := c.services[sreg.reg.ID]; ok {
+			existing.cancel()
+		}
+		c.services[sreg.reg.ID] = sreg
+		upserts = append(upserts, sreg.reg.Copy())
+	}
+	c.mu.Unlock()
+
+	if err := c.upsert(upserts); err != nil {
+		c.mu.Lock()
+		for _, sreg := range regs {
+			sreg.cancel()
+			delete(c.services, sreg.reg.ID)
+		}
+		c.mu.Unlock()
+		return fmt.Errorf("failed to register services: %v", err)
+	}
+
+	for sreg, serviceChecks := range checks {
+		for _, chk := range serviceChecks {
+			go c.runCheck(sreg.ctx, sreg.reg.ID, chk)
+		}
+	}
+	return nil
+}
+
+// UpdateTask removes the services of the old task that were removed or
+// changed, and registers the services of the new task.
+func (c *ServiceClient) UpdateTask(old, newTask *agentconsul.TaskServices) error {
+	newIDs := make(map[string]struct{}, len(newTask.Services))
+	for _, service := range newTask.Services {
+		newIDs[agentconsul.MakeTaskServiceID(newTask.AllocID, newTask.Name, service, newTask.Canary)] = struct{}{}
+	}
+
+	for _, service := range old.Services {
+		id := agentconsul.MakeTaskServiceID(old.AllocID, old.Name, service, old.Canary)
+		if _, ok := newIDs[id]; !ok {
+			c.remove(id)
+		}
+	}
+
+	return c.RegisterTask(newTask)
+}
+
+// RemoveTask stops the checks of the services of the task and removes their
+// registrations.
+func (c *ServiceClient) RemoveTask(task *agentconsul.TaskServices) {
+	for _, service := range task.Services {
+		c.remove(agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, task.Canary))
+	}
+}
+
+// remove stops the checks of the service and removes its registration if it
+// is tracked by the client.
+func (c *ServiceClient) remove(id string) {
+	c.mu.Lock()
+	sreg, ok := c.services[id]
+	if ok {
+		sreg.cancel()
+		delete(c.services, id)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	args := structs.ServiceRegistrationDeleteByIDRequest{
+		ID: id,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.region,
+			Namespace: sreg.reg.Namespace,
+			AuthToken: c.node.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := c.rpc.RPC("ServiceRegistration.DeleteByID", &args, &resp); err != nil {
+		c.logger.Warn("failed to remove service registration", "service", sreg.reg.ServiceName, "id", id, "error", err)
+	}
+}
+
+// upsert writes the registrations to the servers.
+func (c *ServiceClient) upsert(regs []*structs.ServiceRegistration) error {
+	args := structs.ServiceRegistrationUpsertRequest{
+		Services: regs,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.region,
+			AuthToken: c.node.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	return c.rpc.RPC("ServiceRegistration.Upsert", &args, &resp)
+}
+
+// serviceReg builds the registration and checks of a service of the task.
+func (c *ServiceClient) serviceReg(task *agentconsul.TaskServices, service *structs.Service) (*serviceRegistration, []*Check, error) {
+	// Service address modes default to auto
+	addrMode := service.AddressMode
+	if addrMode == "" {
+		addrMode = structs.AddressModeAuto
+	}
+
+	ip, port, err := agentconsul.GetAddress(addrMode, service.PortLabel, task.Networks, task.DriverNetwork)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
+	}
+
+	// Determine whether to use tags or canary_tags
+	tags := service.Tags
+	if task.Canary && len(service.CanaryTags) > 0 {
+		tags = service.CanaryTags
+	}
+
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, task.Canary)
+	sreg := &serviceRegistration{
+		reg: &structs.ServiceRegistration{
+			ID:          id,
+			ServiceName: service.Name,
+			Namespace:   task.Namespace,
+			NodeID:      c.node.ID,
+			Datacenter:  c.node.Datacenter,
+			JobID:       task.JobID,
+			AllocID:     task.AllocID,
+			Tags:        append([]string(nil), tags...),
+			Address:     ip,
+			Port:        port,
+		},
+		checks: make(map[string]string, len(service.Checks)),
+	}
+	sreg.ctx, sreg.cancel = context.WithCancel(context.Background())
+
+	checks := make([]*Check, 0, len(service.Checks))
+	for _, sc := range service.Checks {
+		chk, err := NewCheck(id, task, service, sc)
+		if err != nil {
+			sreg.cancel()
+			return nil, nil, fmt.Errorf("error getting address for check %q: %v", sc.Name, err)
+		}
+		checks = append(checks, chk)
+		sreg.checks[chk.id] = chk.InitialStatus()
+	}
+	sreg.reg.Status = AggregateStatus(sreg.checks)
+	return sreg, checks, nil
+}
+
+// runCheck runs the check at its interval until the context is canceled.
+func (c *ServiceClient) runCheck(ctx context.Context, id string, chk *Check) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		status := chk.Run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		c.setCheckStatus(id, chk.id, status)
+		timer.Reset(chk.Interval())
+	}
+}
+
+// setCheckStatus records the status of a check and updates the registration
+// of its service if its aggregated status changed.
+func (c *ServiceClient) setCheckStatus(id, checkID, status string) {
+	c.mu.Lock()
+	sreg, ok := c.services[id]
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+	if _, ok := sreg.checks[checkID]; !ok {
+		c.mu.Unlock()
+		return
+	}
+	sreg.checks[checkID] = status
+	aggregated := AggregateStatus(sreg.checks)
+	if aggregated == sreg.reg.Status {
+		c.mu.Unlock()
+		return
+	}
+	sreg.reg.Status = aggregated
+	reg := sreg.reg.Copy()
+	c.mu.Unlock()
+
+	c.logger.Debug("service status changed", "service", reg.ServiceName, "id", id, "status", aggregated)
+	if err := c.upsert([]*structs.ServiceRegistration{reg}); err != nil {
+		c.logger.Warn("failed to update service registration", "service", reg.ServiceName, "id", id, "error", err)
+	}
+}
+
+// AggregateStatus returns the status of a service given the status of its
+// checks: critical if any check is critical, warning if any check is warning
+// and passing otherwise.
+func AggregateStatus(checks map[string]string) string {
+	status := api.HealthPassing
+	for _, s := range checks {
+		switch s {
+		case api.HealthCritical:
+			return api.HealthCritical
+		case api.HealthWarning:
+			status = api.HealthWarning
+		}
+	}
+	return status
+}
+
+// Check is a HTTP, TCP or gRPC health check of a service, run by the client
+// rather than Consul.
+type Check struct {
+	id  string
+	def *structs.ServiceCheck
+
+	// addr is the host:port the check connects to and url the address of
+	// HTTP checks
+	addr string
+	url  string
+
+	client *http.Client
+}
+
+// NewCheck returns the check of the service of the task with the given
+// service ID.
+func NewCheck(serviceID string, task *agentconsul.TaskServices, service *structs.Service, sc *structs.ServiceCheck) (*Check, error) {
+	// Default to the service's port but allow check to override
+	portLabel := sc.PortLabel
+	if portLabel == "" {
+		portLabel = service.PortLabel
+	}
+
+	// Checks address mode defaults to host for pre-#3380 backward compat
+	addrMode := sc.AddressMode
+	if addrMode == "" {
+		addrMode = structs.AddressModeHost
+	}
+
+	ip, port, err := agentconsul.GetAddress(addrMode, portLabel, task.Networks, task.DriverNetwork)
+	if err != nil {
+		return nil, err
+	}
+	if port == 0 {
+		return nil, fmt.Errorf("%s checks require an address", sc.Type)
+	}
+
+	timeout := sc.Timeout
+	if timeout == 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	chk := &Check{
+		id:   sc.Hash(serviceID),
+		def:  sc,
+		addr: net.JoinHostPort(ip, strconv.Itoa(port)),
+	}
+
+	if sc.Type == structs.ServiceCheckHTTP {
+		proto := sc.Protocol
+		if proto == "" {
+			proto = "http"
+		}
+		base := url.URL{
+			Scheme: proto,
+			Host:   chk.addr,
+		}
+		relative, err := url.Parse(sc.Path)
+		if err != nil {
+			return nil, err
+		}
+		chk.url = base.ResolveReference(relative).String()
+		chk.client = &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: sc.TLSSkipVerify},
+			},
+		}
+	}
+	return chk, nil
+}
+
+// ID returns the ID of the check.
+func (c *Check) ID() string {
+	return c.id
+}
+
+// Interval returns how often the check is run.
+func (c *Check) Interval() time.Duration {
+	return c.def.Interval
+}
+
+// InitialStatus returns the status of the check before it first runs.
+func (c *Check) InitialStatus() string {
+	if c.def.InitialStatus != "" {
+		return c.def.InitialStatus
+	}
+	return api.HealthCritical
+}
+
+// Run runs the check once and returns its status.
+func (c *Check) Run(ctx context.Context) string {
+	switch c.def.Type {
+	case structs.ServiceCheckHTTP:
+		return c.runHTTP(ctx)
+	case structs.ServiceCheckGRPC:
+		return c.runGRPC(ctx)
+	default:
+		return c.runTCP()
+	}
+}
+
+// runHTTP returns passing for 2xx responses, warning for 429 responses and
+// critical otherwise, matching the HTTP checks of Consul.
+func (c *Check) runHTTP(ctx context.Context) string {
+	method := c.def.Method
+	if method == "" {
+		method = "GET"
+	}
+	req, err := http.NewRequest(method, c.url, nil)
+	if err != nil {
+		return api.HealthCritical
+	}
+	for k, vs := range c.def.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return api.HealthCritical
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return api.HealthPassing
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return api.HealthWarning
+	default:
+		return api.HealthCritical
+	}
+}
+
+// runTCP returns passing if a connection can be established and critical
+// otherwise.
+func (c *Check) runTCP() string {
+	timeout := c.def.Timeout
+	if timeout == 0 {
+		timeout = defaultCheckTimeout
+	}
+	conn, err := net.DialTimeout("tcp", c.addr, timeout)
+	if err != nil {
+		return api.HealthCritical
+	}
+	conn.Close()
+	return api.HealthPassing
+}
+
+// runGRPC returns passing if the service reports it is serving with the
+// standard gRPC health checking protocol and critical otherwise, matching the
+// gRPC checks of Consul.
+func (c *Check) runGRPC(ctx context.Context) string {
+	timeout := c.def.Timeout
+	if timeout == 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	creds := grpc.WithInsecure()
+	if c.def.GRPCUseTLS {
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			InsecureSkipVerify: c.def.TLSSkipVerify,
+		}))
+	}
+	conn, err := grpc.DialContext(ctx, c.addr, creds, grpc.WithBlock())
+	if err != nil {
+		return api.HealthCritical
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: c.def.GRPCService,
+	})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return api.HealthCritical
+	}
+	return api.HealthPassing
+}
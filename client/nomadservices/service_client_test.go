@@ -0,0 +1,300 @@
+package nomadservices
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// mockRPC records the service registrations written by the client.
+type mockRPC struct {
+	services map[string]*structs.ServiceRegistration
+	deletes  []string
+	mu       sync.Mutex
+}
+
+func newMockRPC() *mockRPC {
+	return &mockRPC{services: make(map[string]*structs.ServiceRegistration)}
+}
+
+func (m *mockRPC) RPC(method string, args interface{}, reply interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch method {
+	case "ServiceRegistration.Upsert":
+		for _, s := range args.(*structs.ServiceRegistrationUpsertRequest).Services {
+			m.services[s.ID] = s
+		}
+	case "ServiceRegistration.DeleteByID":
+		id := args.(*structs.ServiceRegistrationDeleteByIDRequest).ID
+		delete(m.services, id)
+		m.deletes = append(m.deletes, id)
+	default:
+		return fmt.Errorf("unexpected RPC %q", method)
+	}
+	return nil
+}
+
+func (m *mockRPC) status(id string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.services[id]; ok {
+		return s.Status
+	}
+	return ""
+}
+
+// testTaskServices returns the services of a task with a port labeled http
+// on the address.
+func testTaskServices(t *testing.T, addr string, services ...*structs.Service) *agentconsul.TaskServices {
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	return &agentconsul.TaskServices{
+		AllocID:   uuid.Generate(),
+		Namespace: structs.DefaultNamespace,
+		JobID:     "example",
+		Name:      "web",
+		Services:  services,
+		Networks: structs.Networks{
+			{
+				IP:            host,
+				ReservedPorts: []structs.Port{{Label: "http", Value: port}},
+			},
+		},
+	}
+}
+
+func TestServiceClient_HTTPCheck(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var code int32 = http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(int(atomic.LoadInt32(&code)))
+	}))
+	defer ts.Close()
+
+	service := &structs.Service{
+		Name:      "web",
+		PortLabel: "http",
+		Tags:      []string{"primary"},
+		Provider:  structs.ServiceProviderNomad,
+		Checks: []*structs.ServiceCheck{
+			{
+				Name:     "alive",
+				Type:     structs.ServiceCheckHTTP,
+				Path:     "/health",
+				Interval: 10 * time.Millisecond,
+				Timeout:  time.Second,
+			},
+		},
+	}
+	task := testTaskServices(t, ts.Listener.Addr().String(), service)
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	rpc := newMockRPC()
+	node := mock.Node()
+	c := NewServiceClient(testlog.HCLogger(t), rpc, node, "global")
+	require.NoError(c.RegisterTask(task))
+
+	// The registration describes the task's service
+	rpc.mu.Lock()
+	reg := rpc.services[id]
+	rpc.mu.Unlock()
+	require.NotNil(reg)
+	require.Equal("web", reg.ServiceName)
+	require.Equal(node.ID, reg.NodeID)
+	require.Equal(task.AllocID, reg.AllocID)
+	require.Equal([]string{"primary"}, reg.Tags)
+	require.Equal(ts.Listener.Addr().String(), net.JoinHostPort(reg.Address, strconv.Itoa(reg.Port)))
+
+	waitForStatus := func(status string) {
+		testutil.WaitForResult(func() (bool, error) {
+			if s := rpc.status(id); s != status {
+				return false, fmt.Errorf("expected status %q; got %q", status, s)
+			}
+			return true, nil
+		}, func(err error) {
+			t.Fatal(err)
+		})
+	}
+
+	// The check passes, then warns on 429 and fails on errors
+	waitForStatus(api.HealthPassing)
+	atomic.StoreInt32(&code, http.StatusTooManyRequests)
+	waitForStatus(api.HealthWarning)
+	atomic.StoreInt32(&code, http.StatusInternalServerError)
+	waitForStatus(api.HealthCritical)
+
+	// Removing the task removes the registration
+	c.RemoveTask(task)
+	rpc.mu.Lock()
+	defer rpc.mu.Unlock()
+	require.Equal([]string{id}, rpc.deletes)
+	require.Empty(rpc.services)
+}
+
+func TestServiceClient_TCPCheck(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	addr := l.Addr().String()
+
+	service := &structs.Service{
+		Name:      "db",
+		PortLabel: "http",
+		Provider:  structs.ServiceProviderNomad,
+		Checks: []*structs.ServiceCheck{
+			{
+				Name:          "alive",
+				Type:          structs.ServiceCheckTCP,
+				Interval:      10 * time.Millisecond,
+				Timeout:       time.Second,
+				InitialStatus: api.HealthWarning,
+			},
+		},
+	}
+	task := testTaskServices(t, addr, service)
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	rpc := newMockRPC()
+	c := NewServiceClient(testlog.HCLogger(t), rpc, mock.Node(), "global")
+	defer c.RemoveTask(task)
+	require.NoError(c.RegisterTask(task))
+
+	testutil.WaitForResult(func() (bool, error) {
+		if s := rpc.status(id); s != api.HealthPassing {
+			return false, fmt.Errorf("expected passing; got %q", s)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Closing the listener fails the check
+	l.Close()
+	testutil.WaitForResult(func() (bool, error) {
+		if s := rpc.status(id); s != api.HealthCritical {
+			return false, fmt.Errorf("expected critical; got %q", s)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestServiceClient_GRPCCheck(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	srv := grpc.NewServer()
+	defer srv.Stop()
+	health := grpchealth.NewServer()
+	health.SetServingStatus("api", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, health)
+	go srv.Serve(l)
+
+	service := &structs.Service{
+		Name:      "api",
+		PortLabel: "http",
+		Provider:  structs.ServiceProviderNomad,
+		Checks: []*structs.ServiceCheck{
+			{
+				Name:        "health",
+				Type:        structs.ServiceCheckGRPC,
+				GRPCService: "api",
+				Interval:    10 * time.Millisecond,
+				Timeout:     time.Second,
+			},
+		},
+	}
+	task := testTaskServices(t, l.Addr().String(), service)
+	id := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	rpc := newMockRPC()
+	c := NewServiceClient(testlog.HCLogger(t), rpc, mock.Node(), "global")
+	defer c.RemoveTask(task)
+	require.NoError(c.RegisterTask(task))
+
+	testutil.WaitForResult(func() (bool, error) {
+		if s := rpc.status(id); s != api.HealthPassing {
+			return false, fmt.Errorf("expected passing; got %q", s)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// A service that isn't serving fails the check
+	health.SetServingStatus("api", healthpb.HealthCheckResponse_NOT_SERVING)
+	testutil.WaitForResult(func() (bool, error) {
+		if s := rpc.status(id); s != api.HealthCritical {
+			return false, fmt.Errorf("expected critical; got %q", s)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestServiceClient_UpdateTask(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	service := &structs.Service{
+		Name:      "web",
+		PortLabel: "http",
+		Provider:  structs.ServiceProviderNomad,
+	}
+	task := testTaskServices(t, "127.0.0.1:8080", service)
+	oldID := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, service, false)
+
+	rpc := newMockRPC()
+	c := NewServiceClient(testlog.HCLogger(t), rpc, mock.Node(), "global")
+	require.NoError(c.RegisterTask(task))
+
+	// Services without checks are passing
+	require.Equal(api.HealthPassing, rpc.status(oldID))
+
+	// Changing the tags replaces the registration
+	newTask := task.Copy()
+	newTask.Services[0].Tags = []string{"v2"}
+	newID := agentconsul.MakeTaskServiceID(task.AllocID, task.Name, newTask.Services[0], false)
+	require.NotEqual(oldID, newID)
+	require.NoError(c.UpdateTask(task, newTask))
+
+	rpc.mu.Lock()
+	defer rpc.mu.Unlock()
+	require.Equal([]string{oldID}, rpc.deletes)
+	require.Len(rpc.services, 1)
+	require.Equal([]string{"v2"}, rpc.services[newID].Tags)
+}
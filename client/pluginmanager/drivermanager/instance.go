@@ -7,6 +7,7 @@ import (
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/helper/pluginutils/singleton"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -53,6 +54,13 @@ type instanceManagerConfig struct {
 
 	// EventHandlerFactory is used to fetch a task event handler
 	EventHandlerFactory TaskEventHandlerFactory
+
+	// Remediation configures how the driver is remediated when it becomes
+	// unhealthy. The driver isn't remediated if nil.
+	Remediation *config.DriverRemediation
+
+	// TriggerNodeEvent is used to emit node events
+	TriggerNodeEvent TriggerNodeEventFn
 }
 
 // instanceManager is used to manage a single driver plugin
@@ -107,6 +115,22 @@ type instanceManager struct {
 	// lastHealthState is the last known health fingerprinted by the manager
 	lastHealthState   drivers.HealthState
 	lastHealthStateMu sync.Mutex
+
+	// remediation configures how the driver is remediated when it becomes
+	// unhealthy
+	remediation *config.DriverRemediation
+
+	// remediationCancel stops the running remediation, and is nil if the
+	// driver isn't being remediated. It's only accessed by the fingerprint
+	// loop.
+	remediationCancel context.CancelFunc
+
+	// refingerprintCh is used by the remediation to trigger a new
+	// fingerprint of the driver
+	refingerprintCh chan struct{}
+
+	// triggerNodeEvent is used to emit node events
+	triggerNodeEvent TriggerNodeEventFn
 }
 
 // newInstanceManager returns a new driver instance manager. It is expected that
@@ -127,6 +151,9 @@ func newInstanceManager(c *instanceManagerConfig) *instanceManager {
 		updateNodeFromDriver: c.UpdateNodeFromDriver,
 		eventHandlerFactory:  c.EventHandlerFactory,
 		firstFingerprintCh:   make(chan struct{}),
+		remediation:          c.Remediation,
+		refingerprintCh:      make(chan struct{}, 1),
+		triggerNodeEvent:     c.TriggerNodeEvent,
 	}
 
 	go i.run()
@@ -296,6 +323,19 @@ func (i *instanceManager) fingerprint() {
 		case <-i.ctx.Done():
 			cancel()
 			return
+		case <-i.refingerprintCh:
+			// Open a new fingerprint channel so the driver fingerprints
+			// immediately rather than at its next period
+			newFpChan, newCancel, err := i.dispenseFingerprintCh()
+			if err != nil {
+				i.logger.Warn("error re-fingerprinting driver", "error", err)
+				continue
+			}
+			if cancel != nil {
+				cancel()
+			}
+			fpChan = newFpChan
+			cancel = newCancel
 		case fp, ok := <-fpChan:
 			if ok {
 				if fp.Err == nil {
@@ -369,6 +409,8 @@ func (i *instanceManager) handleFingerprint(fp *drivers.Fingerprint) {
 	i.lastHealthState = fp.Health
 	i.lastHealthStateMu.Unlock()
 
+	i.updateRemediation(fp)
+
 	// if this is the first fingerprint, mark that we have received it
 	if !i.hasFingerprinted {
 		i.logger.Debug("initial driver fingerprint", "health", fp.Health, "description", fp.HealthDescription)
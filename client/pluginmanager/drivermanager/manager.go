@@ -7,6 +7,7 @@ import (
 
 	log "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/pluginmanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
@@ -52,6 +53,9 @@ type StateStorage interface {
 // fingerprinting
 type UpdateNodeDriverInfoFn func(string, *structs.DriverInfo)
 
+// TriggerNodeEventFn is the callback used to emit node events
+type TriggerNodeEventFn func(*structs.NodeEvent)
+
 // StorePluginReattachFn is used to store plugin reattachment configurations.
 type StorePluginReattachFn func(*plugin.ReattachConfig) error
 
@@ -85,6 +89,14 @@ type Config struct {
 
 	// BlockedDrivers if set will not allow the given driver plugins to start
 	BlockedDrivers map[string]struct{}
+
+	// Remediations configure how the given drivers are remediated when they
+	// become unhealthy
+	Remediations map[string]*config.DriverRemediation
+
+	// TriggerNodeEvent is used to emit node events about the remediation of
+	// drivers
+	TriggerNodeEvent TriggerNodeEventFn
 }
 
 // manager is used to manage a set of driver plugins
@@ -124,6 +136,13 @@ type manager struct {
 	allowedDrivers map[string]struct{}
 	blockedDrivers map[string]struct{}
 
+	// remediations configure how drivers are remediated when they become
+	// unhealthy
+	remediations map[string]*config.DriverRemediation
+
+	// triggerNodeEvent is used to emit node events
+	triggerNodeEvent TriggerNodeEventFn
+
 	// readyCh is ticked once at the end of Run()
 	readyCh chan struct{}
 }
@@ -144,6 +163,8 @@ func New(c *Config) *manager {
 		reattachConfigs:     make(map[loader.PluginID]*pstructs.ReattachConfig),
 		allowedDrivers:      c.AllowedDrivers,
 		blockedDrivers:      c.BlockedDrivers,
+		remediations:        c.Remediations,
+		triggerNodeEvent:    c.TriggerNodeEvent,
 		readyCh:             make(chan struct{}),
 	}
 }
@@ -193,6 +214,8 @@ func (m *manager) Run() {
 			ID:                   &id,
 			UpdateNodeFromDriver: m.updater,
 			EventHandlerFactory:  m.eventHandlerFactory,
+			Remediation:          m.remediations[id.Name],
+			TriggerNodeEvent:     m.triggerNodeEvent,
 		})
 
 		m.instancesMu.Lock()
@@ -0,0 +1,138 @@
+package drivermanager
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// defaultRemediationTimeout is how long a single run of the recovery
+	// command may take if no timeout is configured.
+	defaultRemediationTimeout = 1 * time.Minute
+
+	// remediationMaxOutput is the maximum number of bytes of the output of a
+	// failed recovery command kept in its error.
+	remediationMaxOutput = 512
+)
+
+// updateRemediation starts remediating the driver when its fingerprint
+// becomes unhealthy, and stops once it's healthy again or no longer detected.
+// The driver is only remediated if a remediation is configured for it.
+func (i *instanceManager) updateRemediation(fp *drivers.Fingerprint) {
+	if i.remediation == nil {
+		return
+	}
+
+	unhealthy := fp.Health == drivers.HealthStateUnhealthy
+	switch {
+	case unhealthy && i.remediationCancel == nil:
+		i.logger.Warn("driver is unhealthy, starting remediation", "description", fp.HealthDescription)
+		ctx, cancel := context.WithCancel(i.ctx)
+		i.remediationCancel = cancel
+		go i.remediate(ctx)
+	case !unhealthy && i.remediationCancel != nil:
+		i.logger.Info("stopping driver remediation", "health", fp.Health)
+		i.remediationCancel()
+		i.remediationCancel = nil
+	}
+}
+
+// remediate remediates the unhealthy driver until the context is cancelled.
+// Each attempt runs the recovery command, if any, then waits for the backoff
+// before fingerprinting the driver again. The backoff doubles after each
+// attempt, up to its limit.
+func (i *instanceManager) remediate(ctx context.Context) {
+	r := i.remediation
+	backoff := r.Backoff
+	if backoff == 0 {
+		backoff = driverFPBackoffBaseline
+	}
+	limit := r.BackoffLimit
+	if limit == 0 {
+		limit = driverFPBackoffLimit
+	}
+	if backoff > limit {
+		backoff = limit
+	}
+
+	for attempt := 1; ; attempt++ {
+		if r.Command != "" {
+			err := runRemediationCommand(ctx, r)
+			if ctx.Err() != nil {
+				return
+			}
+			i.emitRemediationEvent(attempt, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		i.logger.Debug("re-fingerprinting unhealthy driver", "attempt", attempt)
+		select {
+		case i.refingerprintCh <- struct{}{}:
+		default:
+		}
+
+		backoff *= 2
+		if backoff > limit {
+			backoff = limit
+		}
+	}
+}
+
+// emitRemediationEvent logs the result of a run of the recovery command and
+// emits it as a node event.
+func (i *instanceManager) emitRemediationEvent(attempt int, err error) {
+	event := structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemDriver).
+		AddDetail("driver", i.id.Name).
+		AddDetail("attempt", strconv.Itoa(attempt))
+	if err != nil {
+		i.logger.Warn("driver recovery command failed", "attempt", attempt, "error", err)
+		event.SetMessage("Driver recovery command failed").AddDetail("error", err.Error())
+	} else {
+		i.logger.Info("driver recovery command succeeded", "attempt", attempt)
+		event.SetMessage("Driver recovery command succeeded")
+	}
+
+	if i.triggerNodeEvent != nil {
+		i.triggerNodeEvent(event)
+	}
+}
+
+// runRemediationCommand runs the recovery command of the remediation,
+// returning an error if it failed.
+func runRemediationCommand(ctx context.Context, r *config.DriverRemediation) error {
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = defaultRemediationTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, r.Command, r.Args...).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("command timed out")
+	}
+	if err != nil {
+		if len(out) > remediationMaxOutput {
+			out = out[:remediationMaxOutput]
+		}
+		if output := strings.TrimSpace(string(out)); output != "" {
+			return fmt.Errorf("command failed: %v: %s", err, output)
+		}
+		return fmt.Errorf("command failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package drivermanager
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	dtu "github.com/hashicorp/nomad/plugins/drivers/testutils"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Remediation(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	fpChan := make(chan *drivers.Fingerprint)
+	var fingerprints int32
+	drv := &dtu.MockDriver{
+		FingerprintF: func(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
+			atomic.AddInt32(&fingerprints, 1)
+			return fpChan, nil
+		},
+		TaskEventsF: func(ctx context.Context) (<-chan *drivers.TaskEvent, error) {
+			return nil, nil
+		},
+	}
+
+	events := make(chan *structs.NodeEvent, 10)
+	cfg := &Config{
+		Logger:              testlog.HCLogger(t),
+		Loader:              mockCatalog(map[string]drivers.DriverPlugin{"mock": drv}),
+		PluginConfig:        &base.AgentConfig{},
+		Updater:             noopUpdater,
+		EventHandlerFactory: noopEventHandlerFactory,
+		State:               state.NoopDB{},
+		Remediations: map[string]*config.DriverRemediation{
+			"mock": {
+				Driver:       "mock",
+				Command:      "/bin/sh",
+				Args:         []string{"-c", "echo dockerd not found; exit 3"},
+				Backoff:      10 * time.Millisecond,
+				BackoffLimit: 20 * time.Millisecond,
+			},
+		},
+		TriggerNodeEvent: func(e *structs.NodeEvent) {
+			select {
+			case events <- e:
+			default:
+			}
+		},
+	}
+	mgr := New(cfg)
+	go mgr.Run()
+	defer mgr.Shutdown()
+
+	// A healthy driver isn't remediated
+	fpChan <- &drivers.Fingerprint{Health: drivers.HealthStateHealthy}
+	fpChan <- &drivers.Fingerprint{Health: drivers.HealthStateUnhealthy}
+
+	// The recovery command is run once the driver is unhealthy
+	select {
+	case e := <-events:
+		require.Equal(structs.NodeEventSubsystemDriver, e.Subsystem)
+		require.Equal("Driver recovery command failed", e.Message)
+		require.Equal("mock", e.Details["driver"])
+		require.Equal("1", e.Details["attempt"])
+		require.Contains(e.Details["error"], "exit status 3: dockerd not found")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the recovery command")
+	}
+
+	// The driver is fingerprinted again
+	testutil.WaitForResult(func() (bool, error) {
+		n := atomic.LoadInt32(&fingerprints)
+		return n >= 3, fmt.Errorf("expected the driver to be fingerprinted again, got %d fingerprints", n)
+	}, func(err error) {
+		require.NoError(err)
+	})
+
+	// The remediation stops once the driver is healthy
+	fpChan <- &drivers.Fingerprint{Health: drivers.HealthStateHealthy}
+	time.Sleep(50 * time.Millisecond)
+	n := atomic.LoadInt32(&fingerprints)
+	testutil.AssertUntil(200*time.Millisecond, func() (bool, error) {
+		current := atomic.LoadInt32(&fingerprints)
+		return current == n, fmt.Errorf("expected no more fingerprints, got %d after %d", current, n)
+	}, func(err error) {
+		require.NoError(err)
+	})
+}
+
+func TestRunRemediationCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	require.NoError(runRemediationCommand(ctx, &config.DriverRemediation{Command: "/bin/true"}))
+
+	err := runRemediationCommand(ctx, &config.DriverRemediation{
+		Command: "/bin/sh",
+		Args:    []string{"-c", "exit 1"},
+	})
+	require.EqualError(err, "command failed: exit status 1")
+
+	err = runRemediationCommand(ctx, &config.DriverRemediation{
+		Command: "/bin/sleep",
+		Args:    []string{"10"},
+		Timeout: 50 * time.Millisecond,
+	})
+	require.EqualError(err, "command timed out")
+}
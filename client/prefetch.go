@@ -0,0 +1,156 @@
+package client
+
+import (
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
+	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// prefetchCallerID is the ID the client references the images it prefetches
+// with, so drivers keep them until they're released.
+const prefetchCallerID = "prefetch"
+
+// watchPrefetches is a long lived goroutine fetching the images and artifacts
+// of the jobs with a prefetch hint the node can run, ahead of their
+// placements.
+func (c *Client) watchPrefetches() {
+	p := newPrefetcher(c.drivermanager, getter.NewCache(c.config.PrefetchDir()), c.logger)
+
+	req := structs.NodeSpecificRequest{
+		NodeID:   c.NodeID(),
+		SecretID: c.secretNodeID(),
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AllowStale: true,
+		},
+	}
+
+	for {
+		var resp structs.NodePrefetchesResponse
+		if err := c.RPC("Node.GetPrefetches", &req, &resp); err != nil {
+			// Shutdown often causes EOF errors, so check for shutdown first
+			select {
+			case <-c.shutdownCh:
+				return
+			default:
+			}
+
+			if err != noServersErr {
+				c.logger.Error("error querying node prefetches", "error", err)
+			}
+			retry := c.retryIntv(getAllocRetryIntv)
+			select {
+			case <-c.rpcRetryWatcher():
+				continue
+			case <-time.After(retry):
+				continue
+			case <-c.shutdownCh:
+				return
+			}
+		}
+
+		// Check for shutdown
+		select {
+		case <-c.shutdownCh:
+			return
+		default:
+		}
+
+		// The query timed out without changes
+		if resp.Index <= req.MinQueryIndex {
+			continue
+		}
+		req.MinQueryIndex = resp.Index
+
+		p.update(resp.Prefetches)
+	}
+}
+
+// prefetchedImage is an image prefetched by a driver.
+type prefetchedImage struct {
+	driver string
+	image  string
+}
+
+// prefetcher fetches the images and artifacts of the prefetches of the node,
+// releasing them once they're no longer prefetched.
+type prefetcher struct {
+	drivers drivermanager.Manager
+	cache   *getter.Cache
+	logger  log.Logger
+
+	// images are the images currently prefetched
+	images map[prefetchedImage]struct{}
+}
+
+func newPrefetcher(drivers drivermanager.Manager, cache *getter.Cache, logger log.Logger) *prefetcher {
+	return &prefetcher{
+		drivers: drivers,
+		cache:   cache,
+		logger:  logger.Named("prefetcher"),
+		images:  make(map[prefetchedImage]struct{}),
+	}
+}
+
+// update fetches the images and artifacts of the prefetches, and releases
+// those no longer prefetched. Failed fetches are retried on the next update.
+func (p *prefetcher) update(prefetches []*structs.TaskPrefetch) {
+	images := make(map[prefetchedImage]struct{})
+	var artifacts []*structs.TaskArtifact
+	for _, tp := range prefetches {
+		if tp.Image != "" {
+			images[prefetchedImage{driver: tp.Driver, image: tp.Image}] = struct{}{}
+		}
+		artifacts = append(artifacts, tp.Artifacts...)
+	}
+
+	for img := range p.images {
+		if _, ok := images[img]; ok {
+			continue
+		}
+		if d := p.imagePrefetcher(img.driver); d != nil {
+			p.logger.Debug("releasing prefetched image", "driver", img.driver, "image", img.image)
+			d.ReleaseImage(img.image, prefetchCallerID)
+		}
+		delete(p.images, img)
+	}
+
+	for img := range images {
+		if _, ok := p.images[img]; ok {
+			continue
+		}
+		d := p.imagePrefetcher(img.driver)
+		if d == nil {
+			continue
+		}
+		p.logger.Debug("prefetching image", "driver", img.driver, "image", img.image)
+		if err := d.PrefetchImage(img.image, prefetchCallerID); err != nil {
+			p.logger.Warn("failed to prefetch image", "driver", img.driver, "image", img.image, "error", err)
+			continue
+		}
+		p.images[img] = struct{}{}
+	}
+
+	if err := p.cache.Prune(artifacts); err != nil {
+		p.logger.Warn("failed to prune prefetched artifacts", "error", err)
+	}
+	for _, artifact := range artifacts {
+		if err := p.cache.Fetch(artifact); err != nil {
+			p.logger.Warn("failed to prefetch artifact", "artifact", artifact.GetterSource, "error", err)
+		}
+	}
+}
+
+// imagePrefetcher returns the driver if it can prefetch images, or nil.
+func (p *prefetcher) imagePrefetcher(driver string) drivers.DriverImagePrefetcher {
+	d, err := p.drivers.Dispense(driver)
+	if err != nil {
+		return nil
+	}
+	ip, _ := d.(drivers.DriverImagePrefetcher)
+	return ip
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
+	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	dtu "github.com/hashicorp/nomad/plugins/drivers/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// prefetchDriver is a mock driver recording the images it holds.
+type prefetchDriver struct {
+	dtu.MockDriver
+	images map[string]struct{}
+	fail   bool
+}
+
+func (d *prefetchDriver) PrefetchImage(image, callerID string) error {
+	if d.fail {
+		return fmt.Errorf("pull failed")
+	}
+	d.images[image] = struct{}{}
+	return nil
+}
+
+func (d *prefetchDriver) ReleaseImage(image, callerID string) {
+	delete(d.images, image)
+}
+
+// prefetchDriverManager dispenses a single prefetchDriver.
+type prefetchDriverManager struct {
+	drivermanager.Manager
+	driver *prefetchDriver
+}
+
+func (m *prefetchDriverManager) Dispense(driver string) (drivers.DriverPlugin, error) {
+	if driver != "docker" {
+		return nil, drivermanager.ErrDriverNotFound
+	}
+	return m.driver, nil
+}
+
+func TestPrefetcher_Update(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	d := &prefetchDriver{images: make(map[string]struct{})}
+	p := newPrefetcher(&prefetchDriverManager{driver: d}, getter.NewCache(dir), testlog.HCLogger(t))
+
+	p.update([]*structs.TaskPrefetch{
+		{Driver: "docker", Image: "app:1.0"},
+		{Driver: "docker", Image: "proxy:1.0"},
+		{Driver: "exec", Image: "ignored"},
+	})
+	require.Len(d.images, 2)
+	require.Contains(d.images, "app:1.0")
+	require.Contains(d.images, "proxy:1.0")
+
+	// Images no longer prefetched are released
+	p.update([]*structs.TaskPrefetch{
+		{Driver: "docker", Image: "app:2.0"},
+		{Driver: "docker", Image: "proxy:1.0"},
+	})
+	require.Len(d.images, 2)
+	require.Contains(d.images, "app:2.0")
+	require.Contains(d.images, "proxy:1.0")
+
+	// Failed pulls are retried on the next update
+	d.fail = true
+	p.update([]*structs.TaskPrefetch{{Driver: "docker", Image: "app:3.0"}})
+	require.Empty(d.images)
+	require.Empty(p.images)
+
+	d.fail = false
+	p.update([]*structs.TaskPrefetch{{Driver: "docker", Image: "app:3.0"}})
+	require.Len(d.images, 1)
+	require.Contains(d.images, "app:3.0")
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// defaultReadinessGateInterval is how often a readiness gate is retried
+	// until it passes if no interval is configured.
+	defaultReadinessGateInterval = 10 * time.Second
+
+	// defaultReadinessGateTimeout is how long a single readiness gate check
+	// may take if no timeout is configured.
+	defaultReadinessGateTimeout = 5 * time.Second
+
+	// readinessGateMaxOutput is the maximum number of bytes of the output of
+	// a failed check kept in its error.
+	readinessGateMaxOutput = 512
+)
+
+// readinessGates tracks the checks that must pass before the node is marked
+// as ready. Until then the node heartbeats as initializing, so the schedulers
+// don't place work on it. Each gate is retried until it passes, and isn't
+// checked again afterwards. A nil readinessGates has passed.
+type readinessGates struct {
+	logger log.Logger
+	gates  []*config.ReadinessGate
+
+	// passed is closed once all the gates passed
+	passed chan struct{}
+
+	// status is the result of the last check of each gate
+	status map[string]string
+	l      sync.Mutex
+}
+
+// newReadinessGates returns the readiness gates of the node. They have passed
+// already if no gates are configured.
+func newReadinessGates(logger log.Logger, gates []*config.ReadinessGate) *readinessGates {
+	r := &readinessGates{
+		logger: logger.Named("readiness"),
+		gates:  gates,
+		passed: make(chan struct{}),
+		status: make(map[string]string, len(gates)),
+	}
+	for _, gate := range gates {
+		r.status[gate.Name] = "pending"
+	}
+	if len(gates) == 0 {
+		close(r.passed)
+	}
+	return r
+}
+
+// Passed returns whether all the gates passed.
+func (r *readinessGates) Passed() bool {
+	if r == nil {
+		return true
+	}
+	select {
+	case <-r.passed:
+		return true
+	default:
+		return false
+	}
+}
+
+// PassedCh returns a chan that is closed once all the gates passed.
+func (r *readinessGates) PassedCh() <-chan struct{} {
+	if r == nil {
+		return nil
+	}
+	return r.passed
+}
+
+// Stats returns the status of each gate, either "pending", "passed" or the
+// error of its last check.
+func (r *readinessGates) Stats() map[string]string {
+	if r == nil {
+		return nil
+	}
+	r.l.Lock()
+	defer r.l.Unlock()
+	stats := make(map[string]string, len(r.status))
+	for name, status := range r.status {
+		stats[name] = status
+	}
+	return stats
+}
+
+// run checks the gates until they all pass, returning true, or until shutdown,
+// returning false.
+func (r *readinessGates) run(shutdownCh <-chan struct{}) bool {
+	if r.Passed() {
+		return true
+	}
+
+	results := make(chan bool, len(r.gates))
+	for _, gate := range r.gates {
+		go func(gate *config.ReadinessGate) {
+			results <- r.runGate(gate, shutdownCh)
+		}(gate)
+	}
+
+	for range r.gates {
+		if !<-results {
+			return false
+		}
+	}
+	close(r.passed)
+	return true
+}
+
+// runGate checks the gate until it passes, returning true, or until shutdown,
+// returning false.
+func (r *readinessGates) runGate(gate *config.ReadinessGate, shutdownCh <-chan struct{}) bool {
+	interval := gate.Interval
+	if interval == 0 {
+		interval = defaultReadinessGateInterval
+	}
+	timeout := gate.Timeout
+	if timeout == 0 {
+		timeout = defaultReadinessGateTimeout
+	}
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := checkReadinessGate(ctx, gate)
+		cancel()
+
+		r.l.Lock()
+		if err == nil {
+			r.status[gate.Name] = "passed"
+		} else {
+			r.status[gate.Name] = err.Error()
+		}
+		r.l.Unlock()
+
+		if err == nil {
+			r.logger.Info("readiness gate passed", "gate", gate.Name)
+			return true
+		}
+		r.logger.Debug("readiness gate failed", "gate", gate.Name, "error", err, "retry", interval)
+
+		select {
+		case <-time.After(interval):
+		case <-shutdownCh:
+			return false
+		}
+	}
+}
+
+// checkReadinessGate runs a single check of the gate, returning an error if it
+// failed.
+func checkReadinessGate(ctx context.Context, gate *config.ReadinessGate) error {
+	if gate.Command != "" {
+		out, err := exec.CommandContext(ctx, gate.Command, gate.Args...).CombinedOutput()
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("command timed out")
+		}
+		if err != nil {
+			if output := readinessGateOutput(out); output != "" {
+				return fmt.Errorf("command failed: %v: %s", err, output)
+			}
+			return fmt.Errorf("command failed: %v", err)
+		}
+		return nil
+	}
+
+	req, err := http.NewRequest("GET", gate.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// readinessGateOutput returns the trimmed output of a failed check, truncated
+// to readinessGateMaxOutput bytes.
+func readinessGateOutput(out []byte) string {
+	if len(out) > readinessGateMaxOutput {
+		out = out[:readinessGateMaxOutput]
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// runReadinessGates checks the readiness gates of the node until they pass,
+// then heartbeats to mark the node as ready. It's intended to be started in a
+// goroutine.
+func (c *Client) runReadinessGates() {
+	if !c.readiness.run(c.shutdownCh) {
+		return
+	}
+
+	names := make([]string, 0, len(c.readiness.gates))
+	for _, gate := range c.readiness.gates {
+		names = append(names, gate.Name)
+	}
+	sort.Strings(names)
+
+	c.logger.Info("readiness gates passed, marking node as ready")
+	c.triggerNodeEvent(structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemCluster).
+		SetMessage("Node readiness gates passed").
+		AddDetail("gates", strings.Join(names, ",")))
+}
+
+// nodeStatus returns the status reported for the node, which is initializing
+// until the readiness gates passed.
+func (c *Client) nodeStatus() string {
+	if !c.readiness.Passed() {
+		return structs.NodeStatusInit
+	}
+	return structs.NodeStatusReady
+}
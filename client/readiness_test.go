@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadinessGates_None(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	r := newReadinessGates(testlog.HCLogger(t), nil)
+	require.True(r.Passed())
+	require.True(r.run(nil))
+	require.Empty(r.Stats())
+
+	var nilGates *readinessGates
+	require.True(nilGates.Passed())
+	require.Nil(nilGates.PassedCh())
+}
+
+func TestReadinessGates_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "warmed")
+
+	var healthy int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	r := newReadinessGates(testlog.HCLogger(t), []*config.ReadinessGate{
+		{
+			Name:     "image-cache",
+			Command:  "test",
+			Args:     []string{"-f", path},
+			Interval: 10 * time.Millisecond,
+		},
+		{
+			Name:     "volume",
+			URL:      ts.URL,
+			Interval: 10 * time.Millisecond,
+		},
+	})
+	require.False(r.Passed())
+	require.Equal(map[string]string{"image-cache": "pending", "volume": "pending"}, r.Stats())
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	done := make(chan bool, 1)
+	go func() {
+		done <- r.run(shutdownCh)
+	}()
+
+	testutil.WaitForResult(func() (bool, error) {
+		stats := r.Stats()
+		if stats["volume"] != "unexpected response code: 503" {
+			return false, fmt.Errorf("unexpected volume status %q", stats["volume"])
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Pass the gates one at a time
+	require.NoError(ioutil.WriteFile(path, nil, 0644))
+	testutil.WaitForResult(func() (bool, error) {
+		if status := r.Stats()["image-cache"]; status != "passed" {
+			return false, fmt.Errorf("unexpected image-cache status %q", status)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	require.False(r.Passed())
+
+	atomic.StoreInt32(&healthy, 1)
+	select {
+	case passed := <-done:
+		require.True(passed)
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("readiness gates should have passed")
+	}
+	require.True(r.Passed())
+	require.Equal(map[string]string{"image-cache": "passed", "volume": "passed"}, r.Stats())
+}
+
+func TestReadinessGates_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	r := newReadinessGates(testlog.HCLogger(t), []*config.ReadinessGate{
+		{
+			Name:     "never",
+			Command:  "false",
+			Interval: time.Hour,
+		},
+	})
+
+	shutdownCh := make(chan struct{})
+	done := make(chan bool, 1)
+	go func() {
+		done <- r.run(shutdownCh)
+	}()
+	close(shutdownCh)
+
+	select {
+	case passed := <-done:
+		require.False(t, passed)
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("readiness gates should have stopped")
+	}
+	require.False(t, r.Passed())
+}
+
+func TestCheckReadinessGate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name string
+		Gate *config.ReadinessGate
+		Err  string
+	}{
+		{
+			Name: "command passes",
+			Gate: &config.ReadinessGate{Command: "true"},
+		},
+		{
+			Name: "command fails with output",
+			Gate: &config.ReadinessGate{Command: "sh", Args: []string{"-c", "echo volume not attached; exit 2"}},
+			Err:  "command failed: exit status 2: volume not attached",
+		},
+		{
+			Name: "command times out",
+			Gate: &config.ReadinessGate{Command: "sleep", Args: []string{"5"}},
+			Err:  "command timed out",
+		},
+		{
+			Name: "url unreachable",
+			Gate: &config.ReadinessGate{URL: "http://127.0.0.1:0"},
+			Err:  "request failed",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			err := checkReadinessGate(ctx, tc.Gate)
+			if tc.Err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.Err)
+			}
+		})
+	}
+}
+
+// TestClient_ReadinessGates asserts that the node is registered as
+// initializing until its readiness gates pass.
+func TestClient_ReadinessGates(t *testing.T) {
+	t.Parallel()
+	s1, _ := testServer(t, func(c *nomad.Config) {
+		c.MinHeartbeatTTL = 50 * time.Millisecond
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	var healthy int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ReadinessGates = []*config.ReadinessGate{
+			{
+				Name:     "volume",
+				URL:      ts.URL,
+				Interval: 10 * time.Millisecond,
+			},
+		}
+	})
+	defer cleanup()
+
+	req := structs.NodeSpecificRequest{
+		NodeID:       c1.Node().ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// The node registers and heartbeats as initializing
+	testutil.WaitForResult(func() (bool, error) {
+		var out structs.SingleNodeResponse
+		if err := s1.RPC("Node.GetNode", &req, &out); err != nil {
+			return false, err
+		}
+		if out.Node == nil {
+			return false, fmt.Errorf("missing reg")
+		}
+		c1.heartbeatLock.Lock()
+		haveHeartbeated := c1.haveHeartbeated
+		c1.heartbeatLock.Unlock()
+		if !haveHeartbeated {
+			return false, fmt.Errorf("no heartbeat yet")
+		}
+		return out.Node.Status == structs.NodeStatusInit, fmt.Errorf("unexpected status %q", out.Node.Status)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The gate is pending until its first check fails
+	testutil.WaitForResult(func() (bool, error) {
+		status := c1.Stats()["readiness_gates"]["volume"]
+		return status == "unexpected response code: 503", fmt.Errorf("unexpected gate status %q", status)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The node is marked as ready once the gates pass
+	atomic.StoreInt32(&healthy, 1)
+	testutil.WaitForResult(func() (bool, error) {
+		var out structs.SingleNodeResponse
+		if err := s1.RPC("Node.GetNode", &req, &out); err != nil {
+			return false, err
+		}
+		return out.Node.Status == structs.NodeStatusReady, fmt.Errorf("unexpected status %q", out.Node.Status)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+	require.Equal(t, structs.NodeStatusReady, c1.Node().Status)
+}
@@ -23,6 +23,8 @@ type rpcEndpoints struct {
 	ClientStats *ClientStats
 	FileSystem  *FileSystem
 	Allocations *Allocations
+	NodeMeta    *NodeMeta
+	Fault       *Fault
 }
 
 // ClientRPC is used to make a local, client only RPC call
@@ -159,6 +161,11 @@ func (c *Client) streamingRpcConn(server *servers.Server, method string) (net.Co
 		tcp.SetNoDelay(true)
 	}
 
+	// Limit the bandwidth of the data sent on the wire
+	if c.rpcLimiter != nil {
+		conn = pool.NewLimitedConn(conn, c.rpcLimiter)
+	}
+
 	// Check if TLS is enabled
 	c.tlsWrapLock.RLock()
 	tlsWrap := c.tlsWrap
@@ -180,6 +187,15 @@ func (c *Client) streamingRpcConn(server *servers.Server, method string) (net.Co
 		conn = tlsConn
 	}
 
+	// Check if compression is enabled
+	if c.config.RPCCompression {
+		if _, err := conn.Write([]byte{byte(pool.RpcCompressed)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = pool.NewCompressedConn(conn)
+	}
+
 	// Write the multiplex byte to set the mode
 	if _, err := conn.Write([]byte{byte(pool.RpcStreaming)}); err != nil {
 		conn.Close()
@@ -218,6 +234,13 @@ func (c *Client) setupClientRpc() {
 	c.endpoints.ClientStats = &ClientStats{c}
 	c.endpoints.FileSystem = NewFileSystemEndpoint(c)
 	c.endpoints.Allocations = &Allocations{c}
+	c.endpoints.NodeMeta = &NodeMeta{c}
+
+	// Only serve the fault injection endpoints if explicitly enabled since
+	// they disrupt the node
+	if c.config.EnableFaultInjection {
+		c.endpoints.Fault = &Fault{c}
+	}
 
 	// Create the RPC Server
 	c.rpcServer = rpc.NewServer()
@@ -234,6 +257,10 @@ func (c *Client) setupClientRpcServer(server *rpc.Server) {
 	server.Register(c.endpoints.ClientStats)
 	server.Register(c.endpoints.FileSystem)
 	server.Register(c.endpoints.Allocations)
+	server.Register(c.endpoints.NodeMeta)
+	if c.endpoints.Fault != nil {
+		server.Register(c.endpoints.Fault)
+	}
 }
 
 // rpcConnListener is a long lived function that listens for new connections
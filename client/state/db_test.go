@@ -9,6 +9,7 @@ import (
 	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -244,6 +245,58 @@ func TestStateDB_DriverManager(t *testing.T) {
 	})
 }
 
+// TestStateDB_NodeMeta asserts the behavior of node metadata related StateDB
+// methods.
+func TestStateDB_NodeMeta(t *testing.T) {
+	t.Parallel()
+
+	testDB(t, func(t *testing.T, db StateDB) {
+		require := require.New(t)
+
+		// Getting nonexistent metadata should return nil
+		meta, err := db.GetNodeMeta()
+		require.NoError(err)
+		require.Nil(meta)
+
+		// Putting metadata, including unset keys, should work
+		expected := map[string]*string{
+			"foo": helper.StringToPtr("bar"),
+			"baz": nil,
+		}
+		require.NoError(db.PutNodeMeta(expected))
+
+		// Getting should return the stored metadata
+		meta, err = db.GetNodeMeta()
+		require.NoError(err)
+		require.Equal(expected, meta)
+	})
+}
+
+func TestStateDB_DynamicUser(t *testing.T) {
+	t.Parallel()
+
+	testDB(t, func(t *testing.T, db StateDB) {
+		require := require.New(t)
+
+		// Allocations without a dynamic user should return zero
+		id, err := db.GetDynamicUser("alloc")
+		require.NoError(err)
+		require.Zero(id)
+
+		// Putting and getting the dynamic user should work
+		require.NoError(db.PutDynamicUser("alloc", 80000))
+		id, err = db.GetDynamicUser("alloc")
+		require.NoError(err)
+		require.Equal(80000, id)
+
+		// Deleting the allocation should delete its dynamic user
+		require.NoError(db.DeleteAllocationBucket("alloc"))
+		id, err = db.GetDynamicUser("alloc")
+		require.NoError(err)
+		require.Zero(id)
+	})
+}
+
 // TestStateDB_Upgrade asserts calling Upgrade on new databases always
 // succeeds.
 func TestStateDB_Upgrade(t *testing.T) {
@@ -253,3 +306,69 @@ func TestStateDB_Upgrade(t *testing.T) {
 		require.NoError(t, db.Upgrade())
 	})
 }
+
+// xorCipher is an insecure boltdd.Cipher for testing.
+type xorCipher struct{}
+
+func (xorCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	out := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		out[i] = b ^ 0x5a
+	}
+	return out, nil
+}
+
+func (c xorCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return c.Encrypt(ciphertext)
+}
+
+// TestStateDB_Encrypt asserts the state persisted before encryption was
+// enabled is encrypted and can still be read.
+func TestStateDB_Encrypt(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	db, err := NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+
+	alloc := mock.Alloc()
+	require.NoError(db.PutAllocation(alloc))
+	meta := map[string]*string{"rack": helper.StringToPtr("r1")}
+	require.NoError(db.PutNodeMeta(meta))
+	require.NoError(db.Encrypt(xorCipher{}))
+
+	// Values are read back and new values are written encrypted
+	allocs, errs, err := db.GetAllAllocations()
+	require.NoError(err)
+	require.Empty(errs)
+	require.Len(allocs, 1)
+	require.Equal(alloc.ID, allocs[0].ID)
+
+	alloc2 := mock.Alloc()
+	require.NoError(db.PutAllocation(alloc2))
+	require.NoError(db.Close())
+
+	// The state can't be read without the cipher
+	db, err = NewBoltStateDB(testlog.HCLogger(t), dir)
+	require.NoError(err)
+	_, errs, err = db.GetAllAllocations()
+	require.NoError(err)
+	require.Len(errs, 2)
+	_, err = db.GetNodeMeta()
+	require.Error(err)
+
+	require.NoError(db.Encrypt(xorCipher{}))
+	allocs, errs, err = db.GetAllAllocations()
+	require.NoError(err)
+	require.Empty(errs)
+	require.Len(allocs, 2)
+
+	out, err := db.GetNodeMeta()
+	require.NoError(err)
+	require.Equal(meta, out)
+	require.NoError(db.Close())
+}
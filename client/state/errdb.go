@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	"github.com/hashicorp/nomad/helper/boltdd"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -23,6 +24,10 @@ func (m *ErrDB) Upgrade() error {
 	return nil
 }
 
+func (m *ErrDB) Encrypt(boltdd.Cipher) error {
+	return nil
+}
+
 func (m *ErrDB) GetAllAllocations() ([]*structs.Allocation, map[string]error, error) {
 	return m.Allocs, nil, nil
 }
@@ -39,6 +44,14 @@ func (m *ErrDB) PutDeploymentStatus(allocID string, ds *structs.AllocDeploymentS
 	return fmt.Errorf("Error!")
 }
 
+func (m *ErrDB) GetDynamicUser(allocID string) (int, error) {
+	return 0, fmt.Errorf("Error!")
+}
+
+func (m *ErrDB) PutDynamicUser(allocID string, id int) error {
+	return fmt.Errorf("Error!")
+}
+
 func (m *ErrDB) GetTaskRunnerState(allocID string, taskName string) (*state.LocalState, *structs.TaskState, error) {
 	return nil, nil, fmt.Errorf("Error!")
 }
@@ -77,6 +90,14 @@ func (m *ErrDB) PutDriverPluginState(ps *driverstate.PluginState) error {
 	return fmt.Errorf("Error!")
 }
 
+func (m *ErrDB) GetNodeMeta() (map[string]*string, error) {
+	return nil, fmt.Errorf("Error!")
+}
+
+func (m *ErrDB) PutNodeMeta(meta map[string]*string) error {
+	return fmt.Errorf("Error!")
+}
+
 func (m *ErrDB) Close() error {
 	return fmt.Errorf("Error!")
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	"github.com/hashicorp/nomad/helper/boltdd"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -17,6 +18,11 @@ type StateDB interface {
 	// Errors should be considered critical and unrecoverable.
 	Upgrade() error
 
+	// Encrypt encrypts the state persisted from now on with the cipher and
+	// the state persisted before. Implementations that don't persist state
+	// to disk ignore it.
+	Encrypt(boltdd.Cipher) error
+
 	// GetAllAllocations returns all valid allocations and a map of
 	// allocation IDs to retrieval errors.
 	//
@@ -32,6 +38,11 @@ type StateDB interface {
 	GetDeploymentStatus(allocID string) (*structs.AllocDeploymentStatus, error)
 	PutDeploymentStatus(allocID string, ds *structs.AllocDeploymentStatus) error
 
+	// Get/Put DynamicUser get and put the ID of the dynamic user assigned to
+	// the allocation. It is zero if the allocation wasn't assigned one.
+	GetDynamicUser(allocID string) (int, error)
+	PutDynamicUser(allocID string, id int) error
+
 	// GetTaskRunnerState returns the LocalState and TaskState for a
 	// TaskRunner. Either state may be nil if it is not found, but if an
 	// error is encountered only the error will be non-nil.
@@ -69,6 +80,13 @@ type StateDB interface {
 	// state.
 	PutDriverPluginState(state *driverstate.PluginState) error
 
+	// GetNodeMeta is used to retrieve the node metadata set at runtime. Keys
+	// with a nil value were unset. It may be nil.
+	GetNodeMeta() (map[string]*string, error)
+
+	// PutNodeMeta is used to store the node metadata set at runtime.
+	PutNodeMeta(meta map[string]*string) error
+
 	// Close the database. Unsafe for further use after calling regardless
 	// of return value.
 	Close() error
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	"github.com/hashicorp/nomad/helper/boltdd"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -19,6 +20,9 @@ type MemDB struct {
 	// alloc_id -> value
 	deployStatus map[string]*structs.AllocDeploymentStatus
 
+	// alloc_id -> value
+	dynamicUsers map[string]int
+
 	// alloc_id -> task_name -> value
 	localTaskState map[string]map[string]*state.LocalState
 	taskState      map[string]map[string]*structs.TaskState
@@ -29,6 +33,9 @@ type MemDB struct {
 	// drivermanager -> plugin-state
 	driverManagerPs *driverstate.PluginState
 
+	// node -> meta
+	nodeMeta map[string]*string
+
 	logger hclog.Logger
 
 	mu sync.RWMutex
@@ -39,6 +46,7 @@ func NewMemDB(logger hclog.Logger) *MemDB {
 	return &MemDB{
 		allocs:         make(map[string]*structs.Allocation),
 		deployStatus:   make(map[string]*structs.AllocDeploymentStatus),
+		dynamicUsers:   make(map[string]int),
 		localTaskState: make(map[string]map[string]*state.LocalState),
 		taskState:      make(map[string]map[string]*structs.TaskState),
 		logger:         logger,
@@ -53,6 +61,10 @@ func (m *MemDB) Upgrade() error {
 	return nil
 }
 
+func (m *MemDB) Encrypt(boltdd.Cipher) error {
+	return nil
+}
+
 func (m *MemDB) GetAllAllocations() ([]*structs.Allocation, map[string]error, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -85,6 +97,19 @@ func (m *MemDB) PutDeploymentStatus(allocID string, ds *structs.AllocDeploymentS
 	return nil
 }
 
+func (m *MemDB) GetDynamicUser(allocID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.dynamicUsers[allocID], nil
+}
+
+func (m *MemDB) PutDynamicUser(allocID string, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dynamicUsers[allocID] = id
+	return nil
+}
+
 func (m *MemDB) GetTaskRunnerState(allocID string, taskName string) (*state.LocalState, *structs.TaskState, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -159,6 +184,7 @@ func (m *MemDB) DeleteAllocationBucket(allocID string) error {
 	defer m.mu.Unlock()
 
 	delete(m.allocs, allocID)
+	delete(m.dynamicUsers, allocID)
 	delete(m.taskState, allocID)
 	delete(m.localTaskState, allocID)
 
@@ -193,6 +219,19 @@ func (m *MemDB) PutDriverPluginState(ps *driverstate.PluginState) error {
 	return nil
 }
 
+func (m *MemDB) GetNodeMeta() (map[string]*string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.nodeMeta, nil
+}
+
+func (m *MemDB) PutNodeMeta(meta map[string]*string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nodeMeta = meta
+	return nil
+}
+
 func (m *MemDB) Close() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -4,6 +4,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	dmstate "github.com/hashicorp/nomad/client/devicemanager/state"
 	driverstate "github.com/hashicorp/nomad/client/pluginmanager/drivermanager/state"
+	"github.com/hashicorp/nomad/helper/boltdd"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -18,6 +19,10 @@ func (n NoopDB) Upgrade() error {
 	return nil
 }
 
+func (n NoopDB) Encrypt(boltdd.Cipher) error {
+	return nil
+}
+
 func (n NoopDB) GetAllAllocations() ([]*structs.Allocation, map[string]error, error) {
 	return nil, nil, nil
 }
@@ -34,6 +39,14 @@ func (n NoopDB) PutDeploymentStatus(allocID string, ds *structs.AllocDeploymentS
 	return nil
 }
 
+func (n NoopDB) GetDynamicUser(allocID string) (int, error) {
+	return 0, nil
+}
+
+func (n NoopDB) PutDynamicUser(allocID string, id int) error {
+	return nil
+}
+
 func (n NoopDB) GetTaskRunnerState(allocID string, taskName string) (*state.LocalState, *structs.TaskState, error) {
 	return nil, nil, nil
 }
@@ -70,6 +83,14 @@ func (n NoopDB) GetDriverPluginState() (*driverstate.PluginState, error) {
 	return nil, nil
 }
 
+func (n NoopDB) PutNodeMeta(meta map[string]*string) error {
+	return nil
+}
+
+func (n NoopDB) GetNodeMeta() (map[string]*string, error) {
+	return nil, nil
+}
+
 func (n NoopDB) Close() error {
 	return nil
 }
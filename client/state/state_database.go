@@ -24,6 +24,7 @@ allocations/
 |--> <alloc-id>/
    |--> alloc         -> allocEntry{*structs.Allocation}
    |--> deploy_status -> deployStatusEntry{*structs.AllocDeploymentStatus}
+   |--> dynamic_user  -> dynamicUserEntry{int}
    |--> task-<name>/
       |--> local_state -> *trstate.LocalState # Local-only state
       |--> task_state  -> *structs.TaskState  # Sync'd to servers
@@ -63,6 +64,10 @@ var (
 	// stored under.
 	allocDeployStatusKey = []byte("deploy_status")
 
+	// allocDynamicUserKey is the key the ID of the dynamic user assigned to
+	// an allocation is stored under.
+	allocDynamicUserKey = []byte("dynamic_user")
+
 	// allocations -> $allocid -> task-$taskname -> the keys below
 	taskLocalStateKey = []byte("local_state")
 	taskStateKey      = []byte("task_state")
@@ -78,6 +83,12 @@ var (
 	// managerPluginStateKey is the key by which plugin manager plugin state is
 	// stored at
 	managerPluginStateKey = []byte("plugin_state")
+
+	// nodeBucket is the bucket name containing all node related data
+	nodeBucket = []byte("node")
+
+	// nodeMetaKey is the key the node metadata set at runtime is stored at
+	nodeMetaKey = []byte("meta")
 )
 
 // taskBucketName returns the bucket name for the given task name.
@@ -287,6 +298,61 @@ func (s *BoltStateDB) GetDeploymentStatus(allocID string) (*structs.AllocDeploym
 	return entry.DeploymentStatus, nil
 }
 
+// dynamicUserEntry wraps values for DynamicUser keys.
+type dynamicUserEntry struct {
+	ID int
+}
+
+// PutDynamicUser stores the ID of the dynamic user assigned to an allocation
+// or returns an error.
+func (s *BoltStateDB) PutDynamicUser(allocID string, id int) error {
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		allocBkt, err := getAllocationBucket(tx, allocID)
+		if err != nil {
+			return err
+		}
+
+		entry := dynamicUserEntry{
+			ID: id,
+		}
+		return allocBkt.Put(allocDynamicUserKey, &entry)
+	})
+}
+
+// GetDynamicUser retrieves the ID of the dynamic user assigned to an
+// allocation or returns an error. It is zero if the allocation wasn't
+// assigned one.
+func (s *BoltStateDB) GetDynamicUser(allocID string) (int, error) {
+	var entry dynamicUserEntry
+
+	err := s.db.View(func(tx *boltdd.Tx) error {
+		allAllocsBkt := tx.Bucket(allocationsBucketName)
+		if allAllocsBkt == nil {
+			// No state, return
+			return nil
+		}
+
+		allocBkt := allAllocsBkt.Bucket([]byte(allocID))
+		if allocBkt == nil {
+			// No state for alloc, return
+			return nil
+		}
+
+		return allocBkt.Get(allocDynamicUserKey, &entry)
+	})
+
+	// It's valid for this field to be missing
+	if boltdd.IsErrNotFound(err) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return entry.ID, nil
+}
+
 // GetTaskRunnerState returns the LocalState and TaskState for a
 // TaskRunner. LocalState or TaskState will be nil if they do not exist.
 //
@@ -587,6 +653,77 @@ func (s *BoltStateDB) GetDriverPluginState() (*driverstate.PluginState, error) {
 	return ps, nil
 }
 
+// PutNodeMeta stores the node metadata set at runtime or returns an error.
+func (s *BoltStateDB) PutNodeMeta(meta map[string]*string) error {
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		// Retrieve the root node bucket
+		nodeBkt, err := tx.CreateBucketIfNotExists(nodeBucket)
+		if err != nil {
+			return err
+		}
+
+		return nodeBkt.Put(nodeMetaKey, meta)
+	})
+}
+
+// GetNodeMeta retrieves the node metadata set at runtime or returns an error.
+func (s *BoltStateDB) GetNodeMeta() (map[string]*string, error) {
+	var meta map[string]*string
+
+	err := s.db.View(func(tx *boltdd.Tx) error {
+		nodeBkt := tx.Bucket(nodeBucket)
+		if nodeBkt == nil {
+			// No state, return
+			return nil
+		}
+
+		if err := nodeBkt.Get(nodeMetaKey, &meta); err != nil {
+			if !boltdd.IsErrNotFound(err) {
+				return fmt.Errorf("failed to read node metadata: %v", err)
+			}
+
+			// Key not found, reset meta to nil
+			meta = nil
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// Encrypt sets the cipher encrypting the values of the database and encrypts
+// the values persisted in plaintext before. The metadata of the database isn't
+// encrypted.
+func (s *BoltStateDB) Encrypt(c boltdd.Cipher) error {
+	s.db.SetCipher(c)
+
+	return s.db.Update(func(tx *boltdd.Tx) error {
+		n := 0
+		for _, name := range [][]byte{allocationsBucketName, devManagerBucket, driverManagerBucket, nodeBucket} {
+			bkt := tx.Bucket(name)
+			if bkt == nil {
+				continue
+			}
+
+			encrypted, err := bkt.EncryptValues()
+			if err != nil {
+				return fmt.Errorf("failed to encrypt bucket %q: %v", name, err)
+			}
+			n += encrypted
+		}
+
+		if n != 0 {
+			s.logger.Info("encrypted plaintext state", "values", n)
+		}
+		return nil
+	})
+}
+
 // init initializes metadata entries in a newly created state database.
 func (s *BoltStateDB) init() error {
 	return s.db.Update(func(tx *boltdd.Tx) error {
@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/shirou/gopsutil/cpu"
+)
+
+// HardwareInventory is the detailed hardware inventory of the host. It
+// complements the fingerprinted attributes of the node when authoring
+// constraints or auditing the capacity of the cluster. Parts of the inventory
+// that can't be determined on the platform are left empty.
+type HardwareInventory struct {
+	CPUs      []*HardwareCPU
+	NUMANodes []*HardwareNUMANode
+	Disks     []*HardwareDisk
+	NICs      []*HardwareNIC
+}
+
+// HardwareCPU is a physical CPU package of the host.
+type HardwareCPU struct {
+	ID      string
+	Vendor  string
+	Model   string
+	MHz     float64
+	Cores   int
+	Threads int
+	Flags   []string
+}
+
+// HardwareNUMANode is a NUMA node of the host, with its CPUs in the Linux
+// cpulist format, such as "0-3,8-11", and its total memory in bytes.
+type HardwareNUMANode struct {
+	ID     int
+	CPUs   string
+	Memory uint64
+}
+
+// HardwareDisk is a physical block device of the host, with its size in
+// bytes.
+type HardwareDisk struct {
+	Name       string
+	Model      string
+	Size       uint64
+	Rotational bool
+}
+
+// HardwareNIC is a network interface of the host, with its link speed in
+// Mb/s or zero if it is unknown.
+type HardwareNIC struct {
+	Name  string
+	MAC   string
+	MTU   int
+	Speed int
+}
+
+// CollectHardwareInventory collects the hardware inventory of the host.
+func CollectHardwareInventory() (*HardwareInventory, error) {
+	cpus, err := hardwareCPUs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect cpu inventory: %v", err)
+	}
+
+	nics, err := hardwareNICs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect network interface inventory: %v", err)
+	}
+
+	return &HardwareInventory{
+		CPUs:      cpus,
+		NUMANodes: hardwareNUMANodes(),
+		Disks:     hardwareDisks(),
+		NICs:      nics,
+	}, nil
+}
+
+// hardwareCPUs returns the physical CPU packages of the host by merging the
+// logical CPUs of each package.
+func hardwareCPUs() ([]*HardwareCPU, error) {
+	infos, err := cpu.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]*HardwareCPU)
+	cores := make(map[string]map[string]struct{})
+	for _, info := range infos {
+		p, ok := packages[info.PhysicalID]
+		if !ok {
+			p = &HardwareCPU{
+				ID:     info.PhysicalID,
+				Vendor: info.VendorID,
+				Model:  info.ModelName,
+				MHz:    info.Mhz,
+				Flags:  info.Flags,
+			}
+			packages[info.PhysicalID] = p
+			cores[info.PhysicalID] = make(map[string]struct{})
+		}
+
+		p.Threads += int(info.Cores)
+		if info.CoreID != "" {
+			cores[info.PhysicalID][info.CoreID] = struct{}{}
+		}
+	}
+
+	cpus := make([]*HardwareCPU, 0, len(packages))
+	for id, p := range packages {
+		// Platforms not reporting the cores report a single logical CPU
+		// per core
+		p.Cores = len(cores[id])
+		if p.Cores == 0 {
+			p.Cores = p.Threads
+		}
+		cpus = append(cpus, p)
+	}
+	sort.Slice(cpus, func(i, j int) bool { return cpus[i].ID < cpus[j].ID })
+	return cpus, nil
+}
+
+// hardwareNICs returns the network interfaces of the host, except for the
+// loopback interfaces.
+func hardwareNICs() ([]*HardwareNIC, error) {
+	intfs, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var nics []*HardwareNIC
+	for _, intf := range intfs {
+		if intf.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		nics = append(nics, &HardwareNIC{
+			Name:  intf.Name,
+			MAC:   intf.HardwareAddr.String(),
+			MTU:   intf.MTU,
+			Speed: nicSpeed(intf.Name),
+		})
+	}
+	return nics, nil
+}
@@ -0,0 +1,21 @@
+// +build !linux
+
+package stats
+
+// hardwareNUMANodes returns the NUMA nodes of the host, which are only
+// determined on Linux.
+func hardwareNUMANodes() []*HardwareNUMANode {
+	return nil
+}
+
+// hardwareDisks returns the physical block devices of the host, which are only
+// determined on Linux.
+func hardwareDisks() []*HardwareDisk {
+	return nil
+}
+
+// nicSpeed returns the link speed of the network interface in Mb/s, which is
+// only determined on Linux.
+func nicSpeed(name string) int {
+	return 0
+}
@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sysDir is the directory of the sysfs filesystem describing the hardware of
+// the host on Linux
+const sysDir = "/sys"
+
+// hardwareNUMANodes returns the NUMA nodes of the host.
+func hardwareNUMANodes() []*HardwareNUMANode {
+	return readNUMANodes(sysDir)
+}
+
+// hardwareDisks returns the physical block devices of the host.
+func hardwareDisks() []*HardwareDisk {
+	return readDisks(sysDir)
+}
+
+// nicSpeed returns the link speed of the network interface in Mb/s, or zero
+// if it is unknown.
+func nicSpeed(name string) int {
+	return readNICSpeed(sysDir, name)
+}
+
+// readNUMANodes reads the NUMA nodes from the sysfs directory.
+func readNUMANodes(sys string) []*HardwareNUMANode {
+	dirs, err := filepath.Glob(filepath.Join(sys, "devices", "system", "node", "node[0-9]*"))
+	if err != nil {
+		return nil
+	}
+
+	var nodes []*HardwareNUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "node"))
+		if err != nil {
+			continue
+		}
+
+		nodes = append(nodes, &HardwareNUMANode{
+			ID:     id,
+			CPUs:   readSysString(filepath.Join(dir, "cpulist")),
+			Memory: readNUMAMemory(filepath.Join(dir, "meminfo")),
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// readNUMAMemory returns the total memory in bytes from the meminfo file of a
+// NUMA node, whose lines look like "Node 0 MemTotal:  16323480 kB".
+func readNUMAMemory(path string) uint64 {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb * 1024
+	}
+	return 0
+}
+
+// readDisks reads the physical block devices from the sysfs directory. Virtual
+// devices, such as loop devices, have no backing device and are skipped.
+func readDisks(sys string) []*HardwareDisk {
+	dirs, err := filepath.Glob(filepath.Join(sys, "block", "*"))
+	if err != nil {
+		return nil
+	}
+
+	var disks []*HardwareDisk
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, "device")); err != nil {
+			continue
+		}
+
+		// The size is always in 512 byte sectors
+		sectors, _ := strconv.ParseUint(readSysString(filepath.Join(dir, "size")), 10, 64)
+
+		disks = append(disks, &HardwareDisk{
+			Name:       filepath.Base(dir),
+			Model:      readSysString(filepath.Join(dir, "device", "model")),
+			Size:       sectors * 512,
+			Rotational: readSysString(filepath.Join(dir, "queue", "rotational")) == "1",
+		})
+	}
+	return disks
+}
+
+// readNICSpeed reads the link speed of the network interface in Mb/s from the
+// sysfs directory. Interfaces without a link report a negative speed, or fail
+// the read.
+func readNICSpeed(sys, name string) int {
+	speed, err := strconv.Atoi(readSysString(filepath.Join(sys, "class", "net", name, "speed")))
+	if err != nil || speed < 0 {
+		return 0
+	}
+	return speed
+}
+
+// readSysString returns the trimmed content of a sysfs file, or an empty
+// string if it can't be read.
+func readSysString(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
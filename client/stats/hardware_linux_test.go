@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeSysFiles writes the files relative to the sysfs directory.
+func writeSysFiles(t *testing.T, sys string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(sys, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestHardware_ReadSys(t *testing.T) {
+	require := require.New(t)
+
+	sys, err := ioutil.TempDir("", "nomad-sys")
+	require.NoError(err)
+	defer os.RemoveAll(sys)
+
+	writeSysFiles(t, sys, map[string]string{
+		"devices/system/node/node0/cpulist": "0-3\n",
+		"devices/system/node/node0/meminfo": "Node 0 MemTotal:        1024 kB\nNode 0 MemFree:          512 kB\n",
+		"devices/system/node/node1/cpulist": "4-7\n",
+		"devices/system/node/node1/meminfo": "Node 1 MemTotal:        2048 kB\n",
+		"devices/system/node/online":        "0-1\n",
+		"block/sda/size":                    "1000\n",
+		"block/sda/queue/rotational":        "1\n",
+		"block/sda/device/model":            "ST4000DM004    \n",
+		"block/nvme0n1/size":                "2000\n",
+		"block/nvme0n1/queue/rotational":    "0\n",
+		"block/nvme0n1/device/model":        "Samsung SSD 970\n",
+		"block/loop0/size":                  "8\n",
+		"block/loop0/queue/rotational":      "0\n",
+		"class/net/eth0/speed":              "10000\n",
+		"class/net/eth1/speed":              "-1\n",
+	})
+
+	require.Equal([]*HardwareNUMANode{
+		{ID: 0, CPUs: "0-3", Memory: 1024 * 1024},
+		{ID: 1, CPUs: "4-7", Memory: 2048 * 1024},
+	}, readNUMANodes(sys))
+
+	require.Equal([]*HardwareDisk{
+		{Name: "nvme0n1", Model: "Samsung SSD 970", Size: 2000 * 512},
+		{Name: "sda", Model: "ST4000DM004", Size: 1000 * 512, Rotational: true},
+	}, readDisks(sys))
+
+	require.Equal(10000, readNICSpeed(sys, "eth0"))
+	require.Zero(readNICSpeed(sys, "eth1"))
+	require.Zero(readNICSpeed(sys, "eth2"))
+}
@@ -0,0 +1,19 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHardware_CollectInventory(t *testing.T) {
+	require := require.New(t)
+
+	inventory, err := CollectHardwareInventory()
+	require.NoError(err)
+	require.NotEmpty(inventory.CPUs)
+	for _, cpu := range inventory.CPUs {
+		require.NotZero(cpu.Threads)
+		require.NotZero(cpu.Cores)
+	}
+}
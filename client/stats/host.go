@@ -3,6 +3,7 @@ package stats
 import (
 	"fmt"
 	"math"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"time"
@@ -22,6 +23,7 @@ type HostStats struct {
 	DiskStats        []*DiskStats
 	AllocDirStats    *DiskStats
 	DeviceStats      []*DeviceGroupStats
+	PressureStats    *PressureStats
 	Uptime           uint64
 	Timestamp        int64
 	CPUTicksConsumed float64
@@ -154,6 +156,12 @@ func (h *HostStatsCollector) collectLocked() error {
 	deviceStats := h.collectDeviceGroupStats()
 	hs.DeviceStats = deviceStats
 
+	// Collect the pressure stall information, if the host exposes it
+	hs.PressureStats = ReadPressure(
+		filepath.Join(hostPressureDir, "cpu"),
+		filepath.Join(hostPressureDir, "memory"),
+		filepath.Join(hostPressureDir, "io"))
+
 	// Update the collected status object.
 	h.hostStats = hs
 
@@ -0,0 +1,189 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// hostPressureDir is the directory holding the pressure stall information of
+// the host on Linux
+const hostPressureDir = "/proc/pressure"
+
+// PressureStats holds the pressure stall information (PSI) of the cpu, memory
+// and io resources. The pressure of a resource is nil if it isn't available.
+type PressureStats struct {
+	CPU    *Pressure
+	Memory *Pressure
+	IO     *Pressure
+}
+
+// Pressure holds the percentage of time some or all of the tasks were stalled
+// waiting on a resource, averaged over 10, 60 and 300 seconds, and the total
+// stall time in microseconds.
+type Pressure struct {
+	SomeAvg10  float64
+	SomeAvg60  float64
+	SomeAvg300 float64
+	SomeTotal  uint64
+	FullAvg10  float64
+	FullAvg60  float64
+	FullAvg300 float64
+	FullTotal  uint64
+}
+
+// Add merges the pressure of other into the stats, keeping the highest
+// pressure of each resource.
+func (ps *PressureStats) Add(other *PressureStats) {
+	if other == nil {
+		return
+	}
+
+	ps.CPU = maxPressure(ps.CPU, other.CPU)
+	ps.Memory = maxPressure(ps.Memory, other.Memory)
+	ps.IO = maxPressure(ps.IO, other.IO)
+}
+
+// Resources returns the available pressure of each resource by name.
+func (ps *PressureStats) Resources() map[string]*Pressure {
+	resources := make(map[string]*Pressure, 3)
+	if ps.CPU != nil {
+		resources["cpu"] = ps.CPU
+	}
+	if ps.Memory != nil {
+		resources["memory"] = ps.Memory
+	}
+	if ps.IO != nil {
+		resources["io"] = ps.IO
+	}
+	return resources
+}
+
+// maxPressure returns the highest value of each field of the pressures.
+func maxPressure(a, b *Pressure) *Pressure {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	p := *a
+	if b.SomeAvg10 > p.SomeAvg10 {
+		p.SomeAvg10 = b.SomeAvg10
+	}
+	if b.SomeAvg60 > p.SomeAvg60 {
+		p.SomeAvg60 = b.SomeAvg60
+	}
+	if b.SomeAvg300 > p.SomeAvg300 {
+		p.SomeAvg300 = b.SomeAvg300
+	}
+	if b.SomeTotal > p.SomeTotal {
+		p.SomeTotal = b.SomeTotal
+	}
+	if b.FullAvg10 > p.FullAvg10 {
+		p.FullAvg10 = b.FullAvg10
+	}
+	if b.FullAvg60 > p.FullAvg60 {
+		p.FullAvg60 = b.FullAvg60
+	}
+	if b.FullAvg300 > p.FullAvg300 {
+		p.FullAvg300 = b.FullAvg300
+	}
+	if b.FullTotal > p.FullTotal {
+		p.FullTotal = b.FullTotal
+	}
+	return &p
+}
+
+// ReadPressure returns the pressure read from the given cpu, memory and io
+// pressure files. The pressure of a resource is left nil if its path is empty
+// or its file can't be read, such as on kernels without PSI support. If no
+// pressure is available, nil is returned.
+func ReadPressure(cpuPath, memoryPath, ioPath string) *PressureStats {
+	ps := &PressureStats{
+		CPU:    readPressure(cpuPath),
+		Memory: readPressure(memoryPath),
+		IO:     readPressure(ioPath),
+	}
+	if ps.CPU == nil && ps.Memory == nil && ps.IO == nil {
+		return nil
+	}
+	return ps
+}
+
+// readPressure returns the pressure of the given file or nil if it can't be
+// read.
+func readPressure(path string) *Pressure {
+	if path == "" {
+		return nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	p, err := parsePressure(b)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// parsePressure parses the content of a pressure file, made of a "some" and an
+// optional "full" line such as:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(b []byte) (*Pressure, error) {
+	p := &Pressure{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var avg10, avg60, avg300 *float64
+		var total *uint64
+		switch fields[0] {
+		case "some":
+			avg10, avg60, avg300, total = &p.SomeAvg10, &p.SomeAvg60, &p.SomeAvg300, &p.SomeTotal
+		case "full":
+			avg10, avg60, avg300, total = &p.FullAvg10, &p.FullAvg60, &p.FullAvg300, &p.FullTotal
+		default:
+			return nil, fmt.Errorf("unexpected pressure line %q", scanner.Text())
+		}
+
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid pressure field %q", f)
+			}
+
+			var err error
+			switch kv[0] {
+			case "avg10":
+				*avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				*avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				*avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				*total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid pressure field %q: %v", f, err)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
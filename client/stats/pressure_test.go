@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPressure_Parse(t *testing.T) {
+	require := require.New(t)
+
+	p, err := parsePressure([]byte(`some avg10=1.50 avg60=0.75 avg300=0.25 total=12345
+full avg10=0.50 avg60=0.10 avg300=0.00 total=678
+`))
+	require.NoError(err)
+	require.Equal(&Pressure{
+		SomeAvg10:  1.5,
+		SomeAvg60:  0.75,
+		SomeAvg300: 0.25,
+		SomeTotal:  12345,
+		FullAvg10:  0.5,
+		FullAvg60:  0.1,
+		FullTotal:  678,
+	}, p)
+
+	// The full line is optional
+	p, err = parsePressure([]byte("some avg10=2.00 avg60=0.00 avg300=0.00 total=1\n"))
+	require.NoError(err)
+	require.Equal(2.0, p.SomeAvg10)
+	require.Zero(p.FullTotal)
+
+	_, err = parsePressure([]byte("some avg10=abc\n"))
+	require.Error(err)
+
+	_, err = parsePressure([]byte("other avg10=0.00\n"))
+	require.Error(err)
+}
+
+func TestPressure_Read(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomadtest-pressure")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cpuPath := filepath.Join(dir, "cpu.pressure")
+	require.NoError(ioutil.WriteFile(cpuPath,
+		[]byte("some avg10=3.00 avg60=0.00 avg300=0.00 total=10\n"), 0644))
+
+	// Missing files and empty paths leave the resource unset
+	ps := ReadPressure(cpuPath, filepath.Join(dir, "memory.pressure"), "")
+	require.NotNil(ps)
+	require.Equal(3.0, ps.CPU.SomeAvg10)
+	require.Nil(ps.Memory)
+	require.Nil(ps.IO)
+	require.Len(ps.Resources(), 1)
+
+	require.Nil(ReadPressure("", filepath.Join(dir, "memory.pressure"), ""))
+}
+
+func TestPressureStats_Add(t *testing.T) {
+	require := require.New(t)
+
+	ps := &PressureStats{}
+	ps.Add(&PressureStats{
+		CPU: &Pressure{SomeAvg10: 1, SomeTotal: 100},
+	})
+	ps.Add(&PressureStats{
+		CPU:    &Pressure{SomeAvg10: 5, SomeTotal: 50},
+		Memory: &Pressure{FullAvg60: 2},
+	})
+	ps.Add(nil)
+
+	require.Equal(&Pressure{SomeAvg10: 5, SomeTotal: 100}, ps.CPU)
+	require.Equal(&Pressure{FullAvg60: 2}, ps.Memory)
+	require.Nil(ps.IO)
+}
@@ -4,6 +4,7 @@ package structs
 
 import (
 	"errors"
+	"os"
 	"time"
 
 	"github.com/hashicorp/nomad/client/stats"
@@ -34,6 +35,87 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// ClientHardwareResponse is used to return the hardware inventory of a node.
+type ClientHardwareResponse struct {
+	Hardware *stats.HardwareInventory
+	structs.QueryMeta
+}
+
+// NodeMetaApplyRequest is used to set or unset the metadata of a node at
+// runtime.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node being targeted.
+	NodeID string
+
+	// Meta maps the keys to set to their values. Keys with a nil value are
+	// unset.
+	Meta map[string]*string
+
+	structs.QueryOptions
+}
+
+// NodeMetaResponse is used to return the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the node's metadata, including the metadata set at runtime.
+	Meta map[string]string
+
+	// Dynamic is the metadata set at runtime. Keys with a nil value were
+	// unset.
+	Dynamic map[string]*string
+
+	structs.QueryMeta
+}
+
+// FaultApplyRequest is used to set the faults injected into a node for
+// resilience testing.
+type FaultApplyRequest struct {
+	// NodeID is the node being targeted.
+	NodeID string
+
+	// HeartbeatDelay is added to the interval between the heartbeats of the
+	// node. Nil leaves it unchanged and zero clears it.
+	HeartbeatDelay *time.Duration
+
+	// FailAllocations is the number of allocations placed on the node to
+	// fail. Nil leaves it unchanged and zero clears it.
+	FailAllocations *int
+
+	structs.QueryOptions
+}
+
+// FaultResponse is used to return the faults injected into a node.
+type FaultResponse struct {
+	// HeartbeatDelay is added to the interval between the heartbeats of the
+	// node.
+	HeartbeatDelay time.Duration
+
+	// FailAllocations is the number of allocations left to fail.
+	FailAllocations int
+
+	structs.QueryMeta
+}
+
+// FaultKillTaskRequest is used to kill a random running task of a job on a
+// node.
+type FaultKillTaskRequest struct {
+	// NodeID is the node being targeted.
+	NodeID string
+
+	// JobID is the job whose task is killed.
+	JobID string
+
+	structs.QueryOptions
+}
+
+// FaultKillTaskResponse is used to return the task killed by a
+// FaultKillTaskRequest.
+type FaultKillTaskResponse struct {
+	AllocID string
+	Task    string
+
+	structs.QueryMeta
+}
+
 // AllocFileInfo holds information about a file inside the AllocDir
 type AllocFileInfo struct {
 	Name     string
@@ -81,6 +163,23 @@ type FsStatResponse struct {
 	structs.QueryMeta
 }
 
+// FsWriteRequest is used to write a file in an allocation's directory.
+type FsWriteRequest struct {
+	// AllocID is the allocation to write the file in
+	AllocID string
+
+	// Path is the path of the file to write
+	Path string
+
+	// Data is the content of the file
+	Data []byte
+
+	// FileMode is the mode of the file if it is created
+	FileMode os.FileMode
+
+	structs.QueryOptions
+}
+
 // FsStreamRequest is the initial request for streaming the content of a file.
 type FsStreamRequest struct {
 	// AllocID is the allocation to stream logs from
@@ -108,6 +207,22 @@ type FsStreamRequest struct {
 	structs.QueryOptions
 }
 
+// FsArchiveRequest is the initial request for streaming a tar archive of a
+// directory of an allocation.
+type FsArchiveRequest struct {
+	// AllocID is the allocation to archive the directory of
+	AllocID string
+
+	// Path is the path to the directory to archive
+	Path string
+
+	// Checksum appends a manifest of the SHA-256 checksums of the archived
+	// files to the archive.
+	Checksum bool
+
+	structs.QueryOptions
+}
+
 // FsLogsRequest is the initial request for accessing allocation logs.
 type FsLogsRequest struct {
 	// AllocID is the allocation to stream logs from
@@ -153,6 +268,12 @@ type AllocStatsRequest struct {
 	// Task is an optional filter to only request stats for the task.
 	Task string
 
+	// Start and End bound the range of the recent resource usage returned
+	// along with the latest stats, as UnixNano timestamps. The history is
+	// only returned if Start is set, and End defaults to the current time.
+	Start int64
+	End   int64
+
 	structs.QueryOptions
 }
 
@@ -163,6 +284,76 @@ type AllocStatsResponse struct {
 	structs.QueryMeta
 }
 
+// AllocRestartRequest is used to restart the tasks of an allocation in place.
+type AllocRestartRequest struct {
+	// AllocID is the allocation to restart
+	AllocID string
+
+	// TaskName is the task to restart. All the tasks are restarted if it
+	// is empty.
+	TaskName string
+
+	// Ordered restarts the tasks one at a time, the leader task last,
+	// waiting for each task to be running again before restarting the
+	// next one.
+	Ordered bool
+
+	// WaitHealthy blocks the request until the restarted allocation is
+	// healthy or its healthy deadline is reached.
+	WaitHealthy bool
+
+	structs.QueryOptions
+}
+
+// AllocRestartResponse is used to return the result of restarting an
+// allocation.
+type AllocRestartResponse struct {
+	// Healthy is set if WaitHealthy was requested and the allocation became
+	// healthy after being restarted.
+	Healthy bool
+
+	structs.QueryMeta
+}
+
+// AllocActionRequest is used to run one of a task's actions inside a running
+// allocation.
+type AllocActionRequest struct {
+	// AllocID is the allocation to run the action in
+	AllocID string
+
+	// Task is the task defining the action and Action is its name
+	Task   string
+	Action string
+
+	structs.QueryOptions
+}
+
+// AllocActionResponse is used to return the result of running an action.
+type AllocActionResponse struct {
+	// Output is the output of the action's command
+	Output []byte
+
+	// ExitCode is the exit code of the action's command
+	ExitCode int
+
+	structs.QueryMeta
+}
+
+// AllocSignalRequest is used to send a signal to the tasks of an allocation.
+type AllocSignalRequest struct {
+	// AllocID is the allocation to signal
+	AllocID string
+
+	// Task is the task to signal. If empty, all of the allocation's running
+	// tasks are signaled.
+	Task string
+
+	// Signal is the name of the signal to send, such as SIGHUP
+	Signal string
+
+	structs.QueryOptions
+}
+
 // MemoryStats holds memory usage related stats
 type MemoryStats struct {
 	RSS            uint64
@@ -224,12 +415,23 @@ type ResourceUsage struct {
 	MemoryStats *MemoryStats
 	CpuStats    *CpuStats
 	DeviceStats []*device.DeviceGroupStats
+
+	// PressureStats is the pressure stall information of the resources, if
+	// exposed by the driver. It is the highest pressure of the tasks once
+	// aggregated for an allocation.
+	PressureStats *stats.PressureStats
 }
 
 func (ru *ResourceUsage) Add(other *ResourceUsage) {
 	ru.MemoryStats.Add(other.MemoryStats)
 	ru.CpuStats.Add(other.CpuStats)
 	ru.DeviceStats = append(ru.DeviceStats, other.DeviceStats...)
+	if other.PressureStats != nil {
+		if ru.PressureStats == nil {
+			ru.PressureStats = &stats.PressureStats{}
+		}
+		ru.PressureStats.Add(other.PressureStats)
+	}
 }
 
 // TaskResourceUsage holds aggregated resource usage of all processes in a Task
@@ -251,6 +453,10 @@ type AllocResourceUsage struct {
 
 	// The max timestamp of all the Tasks
 	Timestamp int64
+
+	// History contains the recent resource usage of each task, oldest
+	// first, if a range was requested
+	History map[string][]*TaskResourceUsage
 }
 
 // joinStringSet takes two slices of strings and joins them
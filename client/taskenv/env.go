@@ -83,8 +83,38 @@ const (
 	// MetaPrefix is the prefix for passing task meta data.
 	MetaPrefix = "NOMAD_META_"
 
+	// NodeClass is the environment variable for passing the class of the
+	// node the alloc is running on.
+	NodeClass = "NOMAD_NODE_CLASS"
+
+	// NodePool is the environment variable for passing the node pool of the
+	// node the alloc is running on.
+	NodePool = "NOMAD_NODE_POOL"
+
+	// NodeMetaPrefix is the prefix for passing the node's meta data.
+	NodeMetaPrefix = "NOMAD_NODE_META_"
+
+	// DeviceIDs is the environment variable for passing the comma separated
+	// IDs of the device instances assigned to the task.
+	DeviceIDs = "NOMAD_DEVICE_IDS"
+
+	// DNSServers, DNSSearches and DNSOptions are the environment variables
+	// for passing the comma separated resolver configuration of the task's
+	// network, if it sets one.
+	DNSServers  = "NOMAD_DNS_SERVERS"
+	DNSSearches = "NOMAD_DNS_SEARCHES"
+	DNSOptions  = "NOMAD_DNS_OPTIONS"
+
 	// VaultToken is the environment variable for passing the Vault token
 	VaultToken = "VAULT_TOKEN"
+
+	// VaultNamespace is the environment variable for passing the Vault
+	// namespace of the token
+	VaultNamespace = "VAULT_NAMESPACE"
+
+	// WorkloadToken is the environment variable for passing the workload
+	// identity of the task, which is a valid Nomad API token
+	WorkloadToken = "NOMAD_TOKEN"
 )
 
 // The node values that can be interpreted.
@@ -94,6 +124,7 @@ const (
 	nodeRegionKey = "node.region"
 	nodeNameKey   = "node.unique.name"
 	nodeClassKey  = "node.class"
+	nodePoolKey   = "node.pool"
 
 	// Prefixes used for lookups.
 	nodeAttributePrefix = "attr."
@@ -283,6 +314,9 @@ type Builder struct {
 	// nodeAttrs are Node attributes and metadata
 	nodeAttrs map[string]string
 
+	// nodeEnv are the env vars describing the node
+	nodeEnv map[string]string
+
 	// taskMeta are the meta attributes on the task
 	taskMeta map[string]string
 
@@ -305,9 +339,13 @@ type Builder struct {
 	allocName        string
 	groupName        string
 	vaultToken       string
+	vaultNamespace   string
 	injectVaultToken bool
 	jobName          string
 
+	// deviceIDs are the IDs of the device instances assigned to the task
+	deviceIDs []string
+
 	// otherPorts for tasks in the same alloc
 	otherPorts map[string]string
 
@@ -320,6 +358,9 @@ type Builder struct {
 	// and affect network env vars.
 	networks []*structs.NetworkResource
 
+	// dns is the resolver configuration of the task's network
+	dns *structs.DNSConfig
+
 	// hookEnvs are env vars set by hooks and stored by hook name to
 	// support adding/removing vars from multiple hooks (eg HookA adds A:1,
 	// HookB adds A:2, HookA removes A, A should equal 2)
@@ -410,8 +451,21 @@ func (b *Builder) Build() *TaskEnv {
 		nodeAttrs[nodeRegionKey] = b.region
 	}
 
+	// Add the node and its assigned devices
+	for k, v := range b.nodeEnv {
+		envMap[k] = v
+	}
+	if len(b.deviceIDs) != 0 {
+		envMap[DeviceIDs] = strings.Join(b.deviceIDs, ",")
+	}
+
 	// Build the network related env vars
 	buildNetworkEnv(envMap, b.networks, b.driverNetwork)
+	if b.dns != nil {
+		envMap[DNSServers] = strings.Join(b.dns.Servers, ",")
+		envMap[DNSSearches] = strings.Join(b.dns.Searches, ",")
+		envMap[DNSOptions] = strings.Join(b.dns.Options, ",")
+	}
 
 	// Build the addr of the other tasks
 	for k, v := range b.otherPorts {
@@ -421,6 +475,9 @@ func (b *Builder) Build() *TaskEnv {
 	// Build the Vault Token
 	if b.injectVaultToken && b.vaultToken != "" {
 		envMap[VaultToken] = b.vaultToken
+		if b.vaultNamespace != "" {
+			envMap[VaultNamespace] = b.vaultNamespace
+		}
 	}
 
 	// Copy task meta
@@ -530,6 +587,7 @@ func (b *Builder) setTask(task *structs.Task) *Builder {
 	for k, v := range task.Env {
 		b.envvars[k] = v
 	}
+	b.dns = nil
 
 	// COMPAT(0.11): Remove in 0.11
 	if task.Resources == nil {
@@ -543,6 +601,12 @@ func (b *Builder) setTask(task *structs.Task) *Builder {
 		b.networks = make([]*structs.NetworkResource, len(task.Resources.Networks))
 		for i, n := range task.Resources.Networks {
 			b.networks[i] = n.Copy()
+
+			// The allocated networks replacing these don't carry the
+			// resolver configuration
+			if n.DNS != nil {
+				b.dns = n.DNS.Copy()
+			}
 		}
 	}
 	return b
@@ -582,12 +646,16 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 	}
 
 	// COMPAT(0.11): Remove in 0.11
+	b.deviceIDs = nil
 	b.otherPorts = make(map[string]string, len(alloc.Job.LookupTaskGroup(alloc.TaskGroup).Tasks)*2)
 	if alloc.AllocatedResources != nil {
 		// Populate task resources
 		if tr, ok := alloc.AllocatedResources.Tasks[b.taskName]; ok {
 			b.cpuLimit = tr.Cpu.CpuShares
 			b.memLimit = tr.Memory.MemoryMB
+			for _, d := range tr.Devices {
+				b.deviceIDs = append(b.deviceIDs, d.DeviceIDs...)
+			}
 
 			// Copy networks to prevent sharing
 			b.networks = make([]*structs.NetworkResource, len(tr.Networks))
@@ -633,13 +701,27 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 
 // setNode is called from NewBuilder to populate node attributes.
 func (b *Builder) setNode(n *structs.Node) *Builder {
-	b.nodeAttrs = make(map[string]string, 4+len(n.Attributes)+len(n.Meta))
+	b.nodeAttrs = make(map[string]string, 5+len(n.Attributes)+len(n.Meta))
 	b.nodeAttrs[nodeIdKey] = n.ID
 	b.nodeAttrs[nodeNameKey] = n.Name
 	b.nodeAttrs[nodeClassKey] = n.NodeClass
 	b.nodeAttrs[nodeDcKey] = n.Datacenter
+	b.nodeAttrs[nodePoolKey] = n.NodePool
 	b.datacenter = n.Datacenter
 
+	// Set up the node env vars, with the meta keys given and upper cased
+	b.nodeEnv = make(map[string]string, 2+len(n.Meta)*2)
+	if n.NodeClass != "" {
+		b.nodeEnv[NodeClass] = n.NodeClass
+	}
+	if n.NodePool != "" {
+		b.nodeEnv[NodePool] = n.NodePool
+	}
+	for k, v := range n.Meta {
+		b.nodeEnv[fmt.Sprintf("%s%s", NodeMetaPrefix, strings.ToUpper(k))] = v
+		b.nodeEnv[fmt.Sprintf("%s%s", NodeMetaPrefix, k)] = v
+	}
+
 	// Set up the attributes.
 	for k, v := range n.Attributes {
 		b.nodeAttrs[fmt.Sprintf("%s%s", nodeAttributePrefix, k)] = v
@@ -753,9 +835,10 @@ func (b *Builder) SetTemplateEnv(m map[string]string) *Builder {
 	return b
 }
 
-func (b *Builder) SetVaultToken(token string, inject bool) *Builder {
+func (b *Builder) SetVaultToken(token, namespace string, inject bool) *Builder {
 	b.mu.Lock()
 	b.vaultToken = token
+	b.vaultNamespace = namespace
 	b.injectVaultToken = inject
 	b.mu.Unlock()
 	return b
@@ -202,6 +202,10 @@ func TestEnvironment_AsList(t *testing.T) {
 		"NOMAD_META_elb_check_type=http",
 		"NOMAD_META_foo=bar",
 		"NOMAD_META_owner=armon",
+		"NOMAD_NODE_CLASS=linux-medium-pci",
+		"NOMAD_NODE_POOL=default",
+		"NOMAD_NODE_META_METAKEY=metaVal",
+		"NOMAD_NODE_META_metaKey=metaVal",
 		"NOMAD_JOB_NAME=my-job",
 		fmt.Sprintf("NOMAD_ALLOC_ID=%s", a.ID),
 		"NOMAD_ALLOC_INDEX=0",
@@ -313,6 +317,10 @@ func TestEnvironment_AsList_Old(t *testing.T) {
 		"NOMAD_META_elb_check_type=http",
 		"NOMAD_META_foo=bar",
 		"NOMAD_META_owner=armon",
+		"NOMAD_NODE_CLASS=linux-medium-pci",
+		"NOMAD_NODE_POOL=default",
+		"NOMAD_NODE_META_METAKEY=metaVal",
+		"NOMAD_NODE_META_metaKey=metaVal",
 		"NOMAD_JOB_NAME=my-job",
 		fmt.Sprintf("NOMAD_ALLOC_ID=%s", a.ID),
 		"NOMAD_ALLOC_INDEX=0",
@@ -369,9 +377,11 @@ func TestEnvironment_AllValues(t *testing.T) {
 	require.NoError(t, err)
 
 	// Assert the keys we couldn't nest were reported
-	require.Len(t, errs, 5)
+	require.Len(t, errs, 7)
 	require.Contains(t, errs, "invalid...taskkey")
 	require.Contains(t, errs, "meta.invalid...metakey")
+	require.Contains(t, errs, "NOMAD_NODE_META_invalid...metakey")
+	require.Contains(t, errs, "NOMAD_NODE_META_INVALID...METAKEY")
 	require.Contains(t, errs, ".a")
 	require.Contains(t, errs, "b.")
 	require.Contains(t, errs, ".")
@@ -383,6 +393,7 @@ func TestEnvironment_AllValues(t *testing.T) {
 		"node.datacenter":         n.Datacenter,
 		"node.unique.name":        n.Name,
 		"node.class":              n.NodeClass,
+		"node.pool":               n.NodePool,
 		"meta.metaKey":            "metaVal",
 		"attr.arch":               "x86",
 		"attr.driver.exec":        "1",
@@ -430,6 +441,10 @@ func TestEnvironment_AllValues(t *testing.T) {
 		"NOMAD_META_elb_check_type":     "http",
 		"NOMAD_META_foo":                "bar",
 		"NOMAD_META_owner":              "armon",
+		"NOMAD_NODE_CLASS":              n.NodeClass,
+		"NOMAD_NODE_POOL":               n.NodePool,
+		"NOMAD_NODE_META_metaKey":       "metaVal",
+		"NOMAD_NODE_META_METAKEY":       "metaVal",
 		"NOMAD_JOB_NAME":                "my-job",
 		"NOMAD_ALLOC_ID":                a.ID,
 		"NOMAD_ALLOC_INDEX":             "0",
@@ -464,31 +479,62 @@ func TestEnvironment_AllValues(t *testing.T) {
 	}
 }
 
+func TestEnvironment_DeviceIDs(t *testing.T) {
+	n := mock.Node()
+	a := mock.Alloc()
+	a.AllocatedResources.Tasks["web"].Devices = []*structs.AllocatedDeviceResource{
+		{
+			Vendor:    "nvidia",
+			Type:      "gpu",
+			Name:      "1080ti",
+			DeviceIDs: []string{"GPU-1", "GPU-2"},
+		},
+		{
+			Vendor:    "xilinx",
+			Type:      "fpga",
+			Name:      "vu9p",
+			DeviceIDs: []string{"FPGA-1"},
+		},
+	}
+	task := a.Job.TaskGroups[0].Tasks[0]
+	task.Env = map[string]string{
+		"GPUS": "${NOMAD_DEVICE_IDS}",
+	}
+
+	env := NewBuilder(n, a, task, "global").Build().Map()
+	require.Equal(t, "GPU-1,GPU-2,FPGA-1", env[DeviceIDs])
+	require.Equal(t, "GPU-1,GPU-2,FPGA-1", env["GPUS"])
+}
+
 func TestEnvironment_VaultToken(t *testing.T) {
 	n := mock.Node()
 	a := mock.Alloc()
 	env := NewBuilder(n, a, a.Job.TaskGroups[0].Tasks[0], "global")
-	env.SetVaultToken("123", false)
+	env.SetVaultToken("123", "vault-ns-a", false)
 
 	{
 		act := env.Build().All()
 		if act[VaultToken] != "" {
 			t.Fatalf("Unexpected environment variables: %s=%q", VaultToken, act[VaultToken])
 		}
+		if act[VaultNamespace] != "" {
+			t.Fatalf("Unexpected environment variables: %s=%q", VaultNamespace, act[VaultNamespace])
+		}
 	}
 
 	{
-		act := env.SetVaultToken("123", true).Build().List()
-		exp := "VAULT_TOKEN=123"
-		found := false
-		for _, entry := range act {
-			if entry == exp {
-				found = true
-				break
+		act := env.SetVaultToken("123", "vault-ns-a", true).Build().List()
+		for _, exp := range []string{"VAULT_TOKEN=123", "VAULT_NAMESPACE=vault-ns-a"} {
+			found := false
+			for _, entry := range act {
+				if entry == exp {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("did not find %q in:\n%s", exp, strings.Join(act, "\n"))
 			}
-		}
-		if !found {
-			t.Fatalf("did not find %q in:\n%s", exp, strings.Join(act, "\n"))
 		}
 	}
 }
@@ -697,3 +743,23 @@ func TestEnvironment_InterpolateEmptyOptionalMeta(t *testing.T) {
 	require.Equal("metaopt1val", env.ReplaceEnv("${NOMAD_META_metaopt1}"))
 	require.Empty(env.ReplaceEnv("${NOMAD_META_metaopt2}"))
 }
+
+// TestEnvironment_DNS asserts the resolver configuration of the task's network
+// is exposed even though the allocated networks don't carry it.
+func TestEnvironment_DNS(t *testing.T) {
+	require := require.New(t)
+	a := mock.Alloc()
+	task := a.Job.TaskGroups[0].Tasks[0]
+
+	env := NewBuilder(mock.Node(), a, task, "global").Build().Map()
+	require.NotContains(env, DNSServers)
+
+	task.Resources.Networks[0].DNS = &structs.DNSConfig{
+		Servers:  []string{"10.0.0.53", "10.0.1.53"},
+		Searches: []string{"service.consul"},
+	}
+	env = NewBuilder(mock.Node(), a, task, "global").Build().Map()
+	require.Equal("10.0.0.53,10.0.1.53", env[DNSServers])
+	require.Equal("service.consul", env[DNSSearches])
+	require.Equal("", env[DNSOptions])
+}
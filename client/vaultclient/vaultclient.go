@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	vaultapi "github.com/hashicorp/vault/api"
+	vaultconsts "github.com/hashicorp/vault/helper/consts"
 )
 
 // TokenDeriverFunc takes in an allocation and a set of tasks and derives a
@@ -38,9 +39,9 @@ type VaultClient interface {
 	// GetConsulACL fetches the Consul ACL token required for the task
 	GetConsulACL(string, string) (*vaultapi.Secret, error)
 
-	// RenewToken renews a token with the given increment and adds it to
-	// the min-heap for periodic renewal.
-	RenewToken(string, int) (<-chan error, error)
+	// RenewToken renews a token in the given Vault namespace with the given
+	// increment and adds it to the min-heap for periodic renewal.
+	RenewToken(string, string, int) (<-chan error, error)
 
 	// StopRenewToken removes the token from the min-heap, stopping its
 	// renewal.
@@ -96,6 +97,10 @@ type vaultClientRenewalRequest struct {
 	// id is an identifier which represents either a token or a lease
 	id string
 
+	// namespace is the Vault namespace of the token. It is empty for
+	// tokens in the namespace of the agent.
+	namespace string
+
 	// increment is the duration for which the token or lease should be
 	// renewed for
 	increment int
@@ -218,9 +223,25 @@ func (c *vaultClient) Stop() {
 // lock. Helper method for deferring a call that does both.
 func (c *vaultClient) unlockAndUnset() {
 	c.client.SetToken("")
+	SetNamespace(c.client, "")
 	c.lock.Unlock()
 }
 
+// SetNamespace sets the Vault namespace the API client sends its requests to.
+// The empty namespace removes it.
+func SetNamespace(client *vaultapi.Client, namespace string) {
+	headers := client.Headers()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+	if namespace == "" {
+		headers.Del(vaultconsts.NamespaceHeaderName)
+	} else {
+		headers.Set(vaultconsts.NamespaceHeaderName, namespace)
+	}
+	client.SetHeaders(headers)
+}
+
 // DeriveToken takes in an allocation and a set of tasks and for each of the
 // task, it derives a vault token from nomad server and unwraps it using vault.
 // The return value is a map containing all the unwrapped tokens indexed by the
@@ -271,14 +292,14 @@ func (c *vaultClient) GetConsulACL(token, path string) (*vaultapi.Secret, error)
 	return c.client.Logical().Read(path)
 }
 
-// RenewToken renews the supplied token for a given duration (in seconds) and
-// adds it to the min-heap so that it is renewed periodically by the renewal
-// loop. Any error returned during renewal will be written to a buffered
+// RenewToken renews the supplied token, which lives in the given Vault
+// namespace, for a given duration (in seconds) and adds it to the min-heap so
+// that it is renewed periodically by the renewal loop. Any error returned during renewal will be written to a buffered
 // channel and the channel is returned instead of an actual error. This helps
 // the caller be notified of a renewal failure asynchronously for appropriate
 // actions to be taken. The caller of this function need not have to close the
 // error channel.
-func (c *vaultClient) RenewToken(token string, increment int) (<-chan error, error) {
+func (c *vaultClient) RenewToken(token, namespace string, increment int) (<-chan error, error) {
 	if token == "" {
 		err := fmt.Errorf("missing token")
 		return nil, err
@@ -296,6 +317,7 @@ func (c *vaultClient) RenewToken(token string, increment int) (<-chan error, err
 	renewalReq := &vaultClientRenewalRequest{
 		errCh:     errCh,
 		id:        token,
+		namespace: namespace,
 		isToken:   true,
 		increment: increment,
 	}
@@ -388,6 +410,7 @@ func (c *vaultClient) renew(req *vaultClientRenewalRequest) error {
 		// Set the token in the API client to the one that needs
 		// renewal
 		c.client.SetToken(req.id)
+		SetNamespace(c.client, req.namespace)
 
 		// Renew the token
 		renewResp, err := c.client.Auth().Token().RenewSelf(req.increment)
@@ -402,6 +425,7 @@ func (c *vaultClient) renew(req *vaultClientRenewalRequest) error {
 
 		// Reset the token in the API client before returning
 		c.client.SetToken("")
+		SetNamespace(c.client, "")
 	} else {
 		// Renew the secret
 		renewResp, err := c.client.Sys().Renew(req.id, req.increment)
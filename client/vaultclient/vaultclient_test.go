@@ -60,7 +60,7 @@ func TestVaultClient_TokenRenewals(t *testing.T) {
 
 		tokens[i] = secret.Auth.ClientToken
 
-		errCh, err := c.RenewToken(tokens[i], secret.Auth.LeaseDuration)
+		errCh, err := c.RenewToken(tokens[i], "", secret.Auth.LeaseDuration)
 		if err != nil {
 			t.Fatalf("Unexpected error: %v", err)
 		}
@@ -239,7 +239,7 @@ func TestVaultClient_RenewNonRenewableLease(t *testing.T) {
 		t.Fatal("failed to derive a wrapped vault token")
 	}
 
-	_, err = c.RenewToken(secret.Auth.ClientToken, secret.Auth.LeaseDuration)
+	_, err = c.RenewToken(secret.Auth.ClientToken, "", secret.Auth.LeaseDuration)
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	} else if !strings.Contains(err.Error(), "lease is not renewable") {
@@ -272,7 +272,7 @@ func TestVaultClient_RenewNonexistentLease(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err = c.RenewToken(c.client.Token(), 10)
+	_, err = c.RenewToken(c.client.Token(), "", 10)
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 		// The Vault error message changed between 0.10.2 and 1.0.1
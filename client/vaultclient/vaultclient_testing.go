@@ -76,7 +76,7 @@ func (vc *MockVaultClient) SetDeriveTokenError(allocID string, tasks []string, e
 	}
 }
 
-func (vc *MockVaultClient) RenewToken(token string, interval int) (<-chan error, error) {
+func (vc *MockVaultClient) RenewToken(token, namespace string, interval int) (<-chan error, error) {
 	vc.mu.Lock()
 	defer vc.mu.Unlock()
 
@@ -0,0 +1,151 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodApplyCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodApplyCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method apply [options] <name>
+
+  Apply is used to create or update an ACL auth method. The provider specific
+  configuration of the auth method is given as JSON with -config.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -type=<type>
+    Specifies the type of the auth method, "oidc" or "jwt". Defaults to "oidc".
+
+  -token-ttl=<duration>
+    Specifies how long the tokens created by logging in with the auth method
+    are valid for, such as "8h". Required.
+
+  -default
+    Specifies that the auth method is used when logging in without naming an
+    auth method.
+
+  -config=<config>
+    Specifies the JSON configuration of the auth method. Prefix the value with
+    "@" to read it from a file, or use "@-" to read it from stdin. Required.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-type":      complete.PredictSet("oidc", "jwt"),
+			"-token-ttl": complete.PredictAnything,
+			"-default":   complete.PredictNothing,
+			"-config":    complete.PredictFiles("*.json"),
+		})
+}
+
+func (c *ACLAuthMethodApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodApplyCommand) Synopsis() string {
+	return "Create or update an ACL auth method"
+}
+
+func (c *ACLAuthMethodApplyCommand) Name() string { return "acl auth-method apply" }
+
+func (c *ACLAuthMethodApplyCommand) Run(args []string) int {
+	var methodType, rawConfig string
+	var tokenTTL time.Duration
+	var isDefault bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&methodType, "type", "oidc", "")
+	flags.DurationVar(&tokenTTL, "token-ttl", 0, "")
+	flags.BoolVar(&isDefault, "default", false, "")
+	flags.StringVar(&rawConfig, "config", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	methodName := args[0]
+
+	if tokenTTL <= 0 {
+		c.Ui.Error("A token TTL must be given with -token-ttl")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if rawConfig == "" {
+		c.Ui.Error("A configuration must be given with -config")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Read the configuration from a file or stdin if requested
+	configJSON := []byte(rawConfig)
+	if strings.HasPrefix(rawConfig, "@") {
+		var err error
+		if path := rawConfig[1:]; path == "-" {
+			configJSON, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			configJSON, err = ioutil.ReadFile(path)
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read configuration: %v", err))
+			return 1
+		}
+	}
+
+	var config api.ACLAuthMethodConfig
+	if err := json.Unmarshal(configJSON, &config); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse configuration: %v", err))
+		return 1
+	}
+
+	// Construct the auth method
+	method := &api.ACLAuthMethod{
+		Name:     methodName,
+		Type:     methodType,
+		TokenTTL: tokenTTL,
+		Default:  isDefault,
+		Config:   &config,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Upsert the auth method
+	_, err = client.ACLAuthMethods().Upsert(method, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing ACL auth method: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote %q ACL auth method!", methodName))
+	return 0
+}
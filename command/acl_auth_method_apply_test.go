@@ -0,0 +1,64 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodApplyCommand(t *testing.T) {
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(t, token, "failed to bootstrap ACL token")
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Write the configuration to a file
+	f, err := ioutil.TempFile("", "nomad-test")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	require.NoError(t, ioutil.WriteFile(f.Name(), []byte(`{
+  "OIDCDiscoveryURL": "https://oidc.example.com",
+  "OIDCClientID": "nomad",
+  "OIDCClientSecret": "secret",
+  "AllowedRedirectURIs": ["http://localhost:4649/oidc/callback"],
+  "Policies": ["engineering"]
+}`), 0600))
+
+	// A token TTL is required
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-config=@" + f.Name(), "okta"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-token-ttl")
+	ui.ErrorWriter.Reset()
+
+	// Attempt to apply an auth method without a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-token-ttl=8h", "-config=@" + f.Name(), "okta"})
+	require.Equal(t, 1, code)
+
+	// Apply the auth method with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-token-ttl=8h", "-default", "-config=@" + f.Name(), "okta"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.True(t, strings.Contains(ui.OutputWriter.String(), "Successfully wrote"))
+
+	method, err := srv.Agent.Server().State().ACLAuthMethodByName(nil, "okta")
+	require.NoError(t, err)
+	require.NotNil(t, method)
+	require.Equal(t, 8*time.Hour, method.TokenTTL)
+	require.True(t, method.Default)
+	require.Equal(t, "nomad", method.Config.OIDCClientID)
+}
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodInfoCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodInfoCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method info <name>
+
+  Info is used to fetch information on an existing ACL auth method. Requires a
+  management token.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *ACLAuthMethodInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodInfoCommand) Synopsis() string {
+	return "Fetch info on an existing ACL auth method"
+}
+
+func (c *ACLAuthMethodInfoCommand) Name() string { return "acl auth-method info" }
+
+func (c *ACLAuthMethodInfoCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	methodName := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Fetch info on the auth method
+	method, _, err := client.ACLAuthMethods().Info(methodName, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching info on ACL auth method: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVAuthMethod(method))
+	return 0
+}
+
+// formatKVAuthMethod returns a K/V formatted auth method. The client secret
+// is not shown.
+func formatKVAuthMethod(method *api.ACLAuthMethod) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", method.Name),
+		fmt.Sprintf("Type|%s", method.Type),
+		fmt.Sprintf("Token TTL|%v", method.TokenTTL),
+		fmt.Sprintf("Default|%v", method.Default),
+	}
+	if config := method.Config; config != nil {
+		output = append(output,
+			fmt.Sprintf("OIDC Discovery URL|%s", config.OIDCDiscoveryURL),
+			fmt.Sprintf("OIDC Client ID|%s", config.OIDCClientID),
+			fmt.Sprintf("OIDC Scopes|%s", strings.Join(config.OIDCScopes, ",")),
+			fmt.Sprintf("Bound Audiences|%s", strings.Join(config.BoundAudiences, ",")),
+			fmt.Sprintf("Allowed Redirect URIs|%s", strings.Join(config.AllowedRedirectURIs, ",")),
+			fmt.Sprintf("Bound Claims|%s", formatBoundClaims(config.BoundClaims)),
+			fmt.Sprintf("Policies|%s", strings.Join(config.Policies, ",")),
+			fmt.Sprintf("Groups Claim|%s", config.GroupsClaim),
+		)
+	}
+	output = append(output,
+		fmt.Sprintf("Create Index|%d", method.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", method.ModifyIndex),
+	)
+	return formatKV(output)
+}
+
+// formatBoundClaims returns the bound claims as sorted key=value pairs.
+func formatBoundClaims(claims map[string]string) string {
+	pairs := make([]string, 0, len(claims))
+	for k, v := range claims {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
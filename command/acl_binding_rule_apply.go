@@ -0,0 +1,141 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLBindingRuleApplyCommand struct {
+	Meta
+}
+
+func (c *ACLBindingRuleApplyCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule apply [options]
+
+  Apply is used to create or update an ACL binding rule. Without -id a new
+  rule is created and its ID is printed. With -id the existing rule is
+  replaced.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -id=<id>
+    Specifies the ID of the binding rule to update.
+
+  -description
+    Specifies a human readable description for the binding rule.
+
+  -auth-method=<name>
+    Specifies the auth method the binding rule applies to. Required.
+
+  -selector=<claim>=<value>
+    Specifies a claim the identity of the user must have the given value for.
+    Can be specified multiple times. Without selectors, the rule applies to
+    every login with the auth method.
+
+  -bind-type=<type>
+    Specifies what the rule grants the token: "policy" or "role". Required.
+
+  -bind-name=<name>
+    Specifies the name of the policy or role to grant. Claims of the identity
+    can be interpolated with "${claim}". Required.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLBindingRuleApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-id":          complete.PredictAnything,
+			"-description": complete.PredictAnything,
+			"-auth-method": complete.PredictAnything,
+			"-selector":    complete.PredictAnything,
+			"-bind-type":   complete.PredictSet("policy", "role"),
+			"-bind-name":   complete.PredictAnything,
+		})
+}
+
+func (c *ACLBindingRuleApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLBindingRuleApplyCommand) Synopsis() string {
+	return "Create or update an ACL binding rule"
+}
+
+func (c *ACLBindingRuleApplyCommand) Name() string { return "acl binding-rule apply" }
+
+func (c *ACLBindingRuleApplyCommand) Run(args []string) int {
+	var id, description, authMethod, bindType, bindName string
+	selector := make(map[string]string)
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&id, "id", "", "")
+	flags.StringVar(&description, "description", "", "")
+	flags.StringVar(&authMethod, "auth-method", "", "")
+	flags.StringVar(&bindType, "bind-type", "", "")
+	flags.StringVar(&bindName, "bind-name", "", "")
+	flags.Var((funcVar)(func(s string) error {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("selector %q is not of the form <claim>=<value>", s)
+		}
+		selector[parts[0]] = parts[1]
+		return nil
+	}), "selector", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if authMethod == "" || bindType == "" || bindName == "" {
+		c.Ui.Error("-auth-method, -bind-type and -bind-name are required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Construct the binding rule
+	rule := &api.ACLBindingRule{
+		ID:          id,
+		Description: description,
+		AuthMethod:  authMethod,
+		Selector:    selector,
+		BindType:    bindType,
+		BindName:    bindName,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create or update the binding rule
+	if id == "" {
+		rule, _, err = client.ACLBindingRules().Create(rule, nil)
+	} else {
+		rule, _, err = client.ACLBindingRules().Update(rule, nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing ACL binding rule: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVBindingRule(rule))
+	return 0
+}
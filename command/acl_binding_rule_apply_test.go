@@ -0,0 +1,64 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleApplyCommand(t *testing.T) {
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	token := srv.RootToken
+	require.NotNil(t, token, "failed to bootstrap ACL token")
+
+	method := mock.ACLJWTAuthMethod()
+	require.NoError(t, state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// The auth method and binding are required
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-bind-type=role"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-auth-method")
+	ui.ErrorWriter.Reset()
+
+	// Attempt to apply a binding rule without a management token
+	args := []string{"-auth-method=" + method.Name, "-selector=ref=refs/heads/main", "-bind-type=role", "-bind-name=deploy-${project}"}
+	code = cmd.Run(append([]string{"-address=" + url, "-token=" + mock.ACLToken().SecretID}, args...))
+	require.Equal(t, 1, code)
+
+	code = cmd.Run(append([]string{"-address=" + url, "-token=" + token.SecretID}, args...))
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "deploy-${project}")
+
+	rules, err := state.ACLBindingRulesByAuthMethod(nil, method.Name)
+	require.NoError(t, err)
+	raw := rules.Next()
+	require.NotNil(t, raw)
+	rule := raw.(*structs.ACLBindingRule)
+	require.Equal(t, map[string]string{"ref": "refs/heads/main"}, rule.Selector)
+	ui.OutputWriter.Reset()
+
+	// Update the binding rule
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-id=" + rule.ID,
+		"-auth-method=" + method.Name, "-bind-type=policy", "-bind-name=readonly"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	rule, err = state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.Equal(t, "readonly", rule.BindName)
+	require.Empty(t, rule.Selector)
+}
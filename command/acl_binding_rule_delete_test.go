@@ -0,0 +1,45 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleDeleteCommand(t *testing.T) {
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	token := srv.RootToken
+	require.NotNil(t, token, "failed to bootstrap ACL token")
+
+	method := mock.ACLJWTAuthMethod()
+	require.NoError(t, state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	rule := mock.ACLBindingRule(method.Name)
+	require.NoError(t, state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Attempt to delete the binding rule without a management token
+	code := cmd.Run([]string{"-address=" + url, "-token=" + mock.ACLToken().SecretID, rule.ID})
+	require.Equal(t, 1, code)
+
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, rule.ID})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Successfully deleted")
+
+	out, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
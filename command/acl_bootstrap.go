@@ -103,11 +103,19 @@ func formatKVACLToken(token *api.ACLToken) string {
 		output = append(output, "Policies|n/a")
 	} else {
 		output = append(output, fmt.Sprintf("Policies|%v", token.Policies))
+		if len(token.Roles) != 0 {
+			output = append(output, fmt.Sprintf("Roles|%v", token.Roles))
+		}
 	}
 
 	// Add the generic output
 	output = append(output,
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
+	)
+	if token.ExpirationTime != nil {
+		output = append(output, fmt.Sprintf("Expiration Time|%v", *token.ExpirationTime))
+	}
+	output = append(output,
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	)
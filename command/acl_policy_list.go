@@ -27,6 +27,10 @@ List Options:
   -json
     Output the ACL policies in a JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display the ACL policies using a Go template.
 `
@@ -37,8 +41,9 @@ List Options:
 func (c *ACLPolicyListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":   complete.PredictNothing,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
 		})
 }
 
@@ -53,18 +58,21 @@ func (c *ACLPolicyListCommand) Synopsis() string {
 func (c *ACLPolicyListCommand) Name() string { return "acl policy list" }
 
 func (c *ACLPolicyListCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got no arguments
 	args = flags.Args()
 	if l := len(args); l != 0 {
@@ -87,8 +95,8 @@ func (c *ACLPolicyListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, policies)
+	if format.enabled() {
+		out, err := format.Format(policies)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleApplyCommand(t *testing.T) {
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	token := srv.RootToken
+	require.NotNil(t, token, "failed to bootstrap ACL token")
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Policies are required
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "operators"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "-policy")
+	ui.ErrorWriter.Reset()
+
+	// Attempt to apply a role without a management token
+	args := []string{"-description=Cluster operators", "-policy=readonly", "-policy=submit", "operators"}
+	code = cmd.Run(append([]string{"-address=" + url, "-token=" + mock.ACLToken().SecretID}, args...))
+	require.Equal(t, 1, code)
+
+	code = cmd.Run(append([]string{"-address=" + url, "-token=" + token.SecretID}, args...))
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), "Successfully wrote")
+
+	role, err := state.ACLRoleByName(nil, "operators")
+	require.NoError(t, err)
+	require.NotNil(t, role)
+	require.Equal(t, "Cluster operators", role.Description)
+	require.Equal(t, []string{"readonly", "submit"}, role.Policies)
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type ActionCommand struct {
+	Meta
+}
+
+func (f *ActionCommand) Help() string {
+	helpText := `
+Usage: nomad action <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with job actions. Actions are
+  predefined commands declared in a task's action blocks, such as flushing a
+  cache or triggering a compaction, that can be run inside the task's running
+  allocations.
+
+  List the actions of a job:
+
+      $ nomad action list <job>
+
+  Run an action inside a running allocation of a job:
+
+      $ nomad action run <job> <action>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ActionCommand) Synopsis() string {
+	return "Interact with job actions"
+}
+
+func (f *ActionCommand) Name() string { return "action" }
+
+func (f *ActionCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// jobAction is an action along with the task group and task defining it.
+type jobAction struct {
+	Group  string
+	Task   string
+	Action *api.Action
+}
+
+// jobActions returns the actions defined by the job's tasks, in the order
+// they appear in the job.
+func jobActions(job *api.Job) []*jobAction {
+	var actions []*jobAction
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			for _, action := range task.Actions {
+				actions = append(actions, &jobAction{
+					Group:  *tg.Name,
+					Task:   task.Name,
+					Action: action,
+				})
+			}
+		}
+	}
+	return actions
+}
+
+// lookupActionJob returns the job with the given ID or ID prefix.
+func lookupActionJob(client *api.Client, jobID string) (*api.Job, error) {
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job: %s", err)
+	}
+	if len(jobs) == 0 {
+		return nil, fmt.Errorf("No job(s) with prefix or id %q found", jobID)
+	}
+	if len(jobs) > 1 && strings.TrimSpace(jobID) != jobs[0].ID {
+		return nil, fmt.Errorf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs))
+	}
+
+	// Prefix lookup matched a single job
+	job, _, err := client.Jobs().Info(jobs[0].ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job: %s", err)
+	}
+	return job, nil
+}
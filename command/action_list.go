@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type ActionListCommand struct {
+	Meta
+}
+
+func (c *ActionListCommand) Help() string {
+	helpText := `
+Usage: nomad action list [options] <job>
+
+  List the actions defined by the tasks of a job. Actions can be run inside the
+  running allocations of the job with the action run command.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ActionListCommand) Synopsis() string {
+	return "List the actions of a job"
+}
+
+func (c *ActionListCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *ActionListCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Jobs)
+}
+
+func (c *ActionListCommand) Name() string { return "action list" }
+
+func (c *ActionListCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	job, err := lookupActionJob(client, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	actions := jobActions(job)
+	if len(actions) == 0 {
+		c.Ui.Output(fmt.Sprintf("Job %q has no actions", *job.ID))
+		return 0
+	}
+
+	out := make([]string, len(actions)+1)
+	out[0] = "Group|Task|Action|Command"
+	for i, a := range actions {
+		command := strings.TrimSpace(strings.Join(append([]string{*a.Action.Command}, a.Action.Args...), " "))
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s", a.Group, a.Task, *a.Action.Name, command)
+	}
+	c.Ui.Output(formatList(out))
+	return 0
+}
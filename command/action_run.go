@@ -0,0 +1,193 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type ActionRunCommand struct {
+	Meta
+}
+
+func (c *ActionRunCommand) Help() string {
+	helpText := `
+Usage: nomad action run [options] <job> <action>
+
+  Run one of the actions defined by the tasks of a job inside a running
+  allocation of the job. The action's output is displayed once it completes.
+  The command exits with an error if the action's exit code is not zero.
+
+  If multiple tasks of the job define the action, the task must be selected
+  with -task. If no allocation is given with -alloc, the action is run in one
+  of the running allocations of the task's group.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Action Run Options:
+
+  -alloc <alloc-id>
+    Run the action in the allocation with the given ID or ID prefix.
+
+  -task <task-name>
+    Run the action defined by the given task.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ActionRunCommand) Synopsis() string {
+	return "Run an action inside a running allocation"
+}
+
+func (c *ActionRunCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-alloc": c.Meta.PredictSearch(contexts.Allocs),
+			"-task":  complete.PredictAnything,
+		})
+}
+
+func (c *ActionRunCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Jobs)
+}
+
+func (c *ActionRunCommand) Name() string { return "action run" }
+
+func (c *ActionRunCommand) Run(args []string) int {
+	var allocID, taskName string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&allocID, "alloc", "", "")
+	flags.StringVar(&taskName, "task", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got the job and the action
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <job> <action>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	actionName := args[1]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	job, err := lookupActionJob(client, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	action, err := selectJobAction(job, taskName, actionName)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	alloc, err := c.selectAlloc(client, job, action.Group, allocID)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	resp, err := client.Allocations().RunAction(&api.Allocation{ID: alloc.ID}, action.Task, actionName, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running action %q: %s", actionName, err))
+		return 1
+	}
+
+	if resp.Output != "" {
+		c.Ui.Output(strings.TrimSuffix(resp.Output, "\n"))
+	}
+	if resp.ExitCode != 0 {
+		c.Ui.Error(fmt.Sprintf("Action %q exited with code %d", actionName, resp.ExitCode))
+		return 1
+	}
+	return 0
+}
+
+// selectJobAction returns the job's action with the given name. If multiple
+// tasks define it, taskName must select one of them.
+func selectJobAction(job *api.Job, taskName, actionName string) (*jobAction, error) {
+	var matches []*jobAction
+	for _, a := range jobActions(job) {
+		if *a.Action.Name != actionName {
+			continue
+		}
+		if taskName != "" && a.Task != taskName {
+			continue
+		}
+		matches = append(matches, a)
+	}
+
+	switch len(matches) {
+	case 0:
+		if taskName != "" {
+			return nil, fmt.Errorf("Task %q of job %q has no action %q", taskName, *job.ID, actionName)
+		}
+		return nil, fmt.Errorf("Job %q has no action %q", *job.ID, actionName)
+	case 1:
+		return matches[0], nil
+	default:
+		tasks := make([]string, len(matches))
+		for i, a := range matches {
+			tasks[i] = a.Task
+		}
+		return nil, fmt.Errorf("Action %q is defined by multiple tasks, select one with -task: %s",
+			actionName, strings.Join(tasks, ", "))
+	}
+}
+
+// selectAlloc returns the running allocation of the job's group to run the
+// action in. If allocID is set, it must match a running allocation of the
+// group.
+func (c *ActionRunCommand) selectAlloc(client *api.Client, job *api.Job, group, allocID string) (*api.AllocationListStub, error) {
+	allocs, _, err := client.Jobs().Allocations(*job.ID, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying job allocations: %s", err)
+	}
+
+	var running []*api.AllocationListStub
+	for _, alloc := range allocs {
+		if alloc.TaskGroup != group ||
+			alloc.DesiredStatus != api.AllocDesiredStatusRun ||
+			alloc.ClientStatus != api.AllocClientStatusRunning {
+			continue
+		}
+		if allocID != "" && !strings.HasPrefix(alloc.ID, sanitizeUUIDPrefix(allocID)) {
+			continue
+		}
+		running = append(running, alloc)
+	}
+
+	if len(running) == 0 {
+		if allocID != "" {
+			return nil, fmt.Errorf("No running allocation of group %q with prefix or id %q found", group, allocID)
+		}
+		return nil, fmt.Errorf("Group %q of job %q has no running allocations", group, *job.ID)
+	}
+	if allocID != "" && len(running) > 1 {
+		return nil, fmt.Errorf("Prefix matched multiple allocations\n\n%s",
+			formatAllocListStubs(running, false, shortId))
+	}
+
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].Name < running[j].Name
+	})
+	return running[0], nil
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionRunCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &ActionRunCommand{}
+}
+
+func TestActionRunCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &ActionRunCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "example", "flush"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying job") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestActionRunCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		if _, ok := nodes[0].Drivers["mock_driver"]; !ok {
+			return false, fmt.Errorf("mock_driver not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Register a long running service job with an action
+	job := testJob("job1")
+	job.Type = helper.StringToPtr(api.JobTypeService)
+	job.TaskGroups[0].Tasks[0].Config["run_for"] = "30s"
+	job.TaskGroups[0].Tasks[0].Actions = []*api.Action{
+		{
+			Name:    helper.StringToPtr("flush"),
+			Command: helper.StringToPtr("/bin/flush"),
+			Args:    []string{"-all"},
+			Timeout: helper.TimeToPtr(5 * time.Second),
+		},
+	}
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations("job1", false, nil)
+		if err != nil {
+			return false, err
+		}
+		if len(allocs) != 1 || allocs[0].ClientStatus != api.AllocClientStatusRunning {
+			return false, fmt.Errorf("allocation not running")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// The action is listed
+	ui := new(cli.MockUi)
+	listCmd := &ActionListCommand{Meta: Meta{Ui: ui}}
+	code := listCmd.Run([]string{"-address=" + url, "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Regexp(`group1\s+task1\s+flush\s+/bin/flush -all`, ui.OutputWriter.String())
+
+	// Unknown actions are rejected
+	ui = new(cli.MockUi)
+	cmd := &ActionRunCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "job1", "unknown"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), `Job "job1" has no action "unknown"`)
+
+	// Run the action
+	ui = new(cli.MockUi)
+	cmd = &ActionRunCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-task=task1", "job1", "flush"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "/bin/flush")
+}
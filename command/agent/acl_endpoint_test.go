@@ -8,6 +8,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_ACLPolicyList(t *testing.T) {
@@ -187,6 +188,57 @@ func TestHTTP_ACLPolicyDelete(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLRoles(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		role := mock.ACLRole()
+
+		// Create the role
+		buf := encodeReq(role)
+		req, err := http.NewRequest("PUT", "/v1/acl/role/"+role.Name, buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// List the roles
+		req, err = http.NewRequest("GET", "/v1/acl/roles", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		obj, err := s.Server.ACLRolesRequest(respW, req)
+		require.NoError(t, err)
+		stubs := obj.([]*structs.ACLRoleListStub)
+		require.Len(t, stubs, 1)
+		require.Equal(t, role.Name, stubs[0].Name)
+
+		// Query the role
+		req, err = http.NewRequest("GET", "/v1/acl/role/"+role.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		out := obj.(*structs.ACLRole)
+		require.Equal(t, role.Policies, out.Policies)
+
+		// Delete the role
+		req, err = http.NewRequest("DELETE", "/v1/acl/role/"+role.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		state := s.Agent.server.State()
+		r, err := state.ACLRoleByName(nil, role.Name)
+		require.NoError(t, err)
+		require.Nil(t, r)
+	})
+}
+
 func TestHTTP_ACLTokenBootstrap(t *testing.T) {
 	t.Parallel()
 	conf := func(c *Config) {
@@ -371,6 +423,37 @@ func TestHTTP_ACLTokenSelf(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLOneTimeToken(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create a one-time token for the root token
+		req, err := http.NewRequest("POST", "/v1/acl/token/onetime", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLTokenSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		ott := obj.(*structs.OneTimeToken)
+		require.Equal(s.RootToken.AccessorID, ott.AccessorID)
+
+		// Exchange it without a token
+		buf := encodeReq(&structs.OneTimeTokenExchangeRequest{
+			OneTimeSecretID: ott.OneTimeSecretID,
+		})
+		req, err = http.NewRequest("POST", "/v1/acl/token/onetime/exchange", buf)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.ACLTokenSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(s.RootToken.SecretID, obj.(*structs.ACLToken).SecretID)
+	})
+}
+
 func TestHTTP_ACLTokenCreate(t *testing.T) {
 	t.Parallel()
 	httpACLTest(t, nil, func(s *TestAgent) {
@@ -448,3 +531,109 @@ func TestHTTP_ACLTokenDelete(t *testing.T) {
 		assert.Nil(t, out)
 	})
 }
+
+func TestHTTP_ACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		method := mock.ACLAuthMethod()
+
+		// Create the auth method
+		buf := encodeReq(method)
+		req, err := http.NewRequest("PUT", "/v1/acl/auth-method/"+method.Name, buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// List the auth methods without a token
+		req, err = http.NewRequest("GET", "/v1/acl/auth-methods", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.ACLAuthMethodsRequest(respW, req)
+		require.NoError(t, err)
+		stubs := obj.([]*structs.ACLAuthMethodListStub)
+		require.Len(t, stubs, 1)
+		require.Equal(t, method.Name, stubs[0].Name)
+
+		// Query the auth method
+		req, err = http.NewRequest("GET", "/v1/acl/auth-method/"+method.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+		out := obj.(*structs.ACLAuthMethod)
+		require.Equal(t, method.Config.OIDCClientID, out.Config.OIDCClientID)
+
+		// Delete the auth method
+		req, err = http.NewRequest("DELETE", "/v1/acl/auth-method/"+method.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		state := s.Agent.server.State()
+		m, err := state.ACLAuthMethodByName(nil, method.Name)
+		require.NoError(t, err)
+		require.Nil(t, m)
+	})
+}
+
+func TestHTTP_ACLBindingRules(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		method := mock.ACLJWTAuthMethod()
+		state := s.Agent.server.State()
+		require.NoError(t, state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+		// Create the binding rule
+		rule := mock.ACLBindingRule(method.Name)
+		rule.ID = ""
+		buf := encodeReq(rule)
+		req, err := http.NewRequest("PUT", "/v1/acl/binding-rule", buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		obj, err := s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		created := obj.(*structs.ACLBindingRule)
+		require.NotEmpty(t, created.ID)
+
+		// List the binding rules of the auth method
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rules?auth_method="+method.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRulesRequest(respW, req)
+		require.NoError(t, err)
+		stubs := obj.([]*structs.ACLBindingRuleListStub)
+		require.Len(t, stubs, 1)
+		require.Equal(t, created.ID, stubs[0].ID)
+
+		// Query the binding rule
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rule/"+created.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+		out := obj.(*structs.ACLBindingRule)
+		require.Equal(t, rule.Selector, out.Selector)
+
+		// Delete the binding rule
+		req, err = http.NewRequest("DELETE", "/v1/acl/binding-rule/"+created.ID, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		r, err := state.ACLBindingRuleByID(nil, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, r)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	golog "log"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	humanize "github.com/dustin/go-humanize"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/consul/lib"
 	log "github.com/hashicorp/go-hclog"
@@ -79,6 +81,19 @@ type Agent struct {
 	// instances of the plugins.
 	pluginSingletonLoader loader.PluginCatalog
 
+	// autoTLS issues and rotates the TLS certificate of the agent. It is nil
+	// unless automatic TLS certificates are enabled.
+	autoTLS *autoTLS
+
+	// serverJoinStatus and clientJoinStatus track the retry join of the
+	// server and the client for the diagnostics of the agent.
+	serverJoinStatus *retryJoinStatus
+	clientJoinStatus *retryJoinStatus
+
+	// watchdog checks the health of the agent. It is nil unless the agent
+	// configures it.
+	watchdog *watchdog
+
 	shutdown     bool
 	shutdownCh   chan struct{}
 	shutdownLock sync.Mutex
@@ -93,6 +108,9 @@ func NewAgent(config *Config, logger log.Logger, logOutput io.Writer, inmem *met
 		logOutput:  logOutput,
 		shutdownCh: make(chan struct{}),
 		InmemSink:  inmem,
+
+		serverJoinStatus: &retryJoinStatus{},
+		clientJoinStatus: &retryJoinStatus{},
 	}
 
 	// Create the loggers
@@ -110,6 +128,10 @@ func NewAgent(config *Config, logger log.Logger, logOutput io.Writer, inmem *met
 		return nil, err
 	}
 
+	if err := a.setupAutoTLS(); err != nil {
+		return nil, err
+	}
+
 	if err := a.setupServer(); err != nil {
 		return nil, err
 	}
@@ -120,6 +142,14 @@ func NewAgent(config *Config, logger log.Logger, logOutput io.Writer, inmem *met
 		return nil, fmt.Errorf("must have at least client or server mode enabled")
 	}
 
+	if err := a.setupPrometheusRemoteWrite(); err != nil {
+		return nil, err
+	}
+
+	if err := a.setupWatchdog(); err != nil {
+		return nil, err
+	}
+
 	return a, nil
 }
 
@@ -189,12 +219,32 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.EnabledSchedulers = schedulers
 
 	}
+	if workers := agentConfig.Server.SchedulerWorkers; workers != nil {
+		conf.SchedulerWorkers = conf.SchedulerWorkers.Merge(workers)
+		if err := conf.SchedulerWorkers.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid scheduler_workers config: %v", err)
+		}
+	}
+	if relay := agentConfig.Server.ClientRelay; relay != nil {
+		conf.ClientRelay = conf.ClientRelay.Merge(relay)
+		if err := conf.ClientRelay.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid client_relay config: %v", err)
+		}
+	}
+	if limit := agentConfig.Server.WriteRateLimit; limit != nil {
+		if err := limit.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid write_rate_limit config: %v", err)
+		}
+		conf.WriteRateLimit = limit.Copy()
+	}
 	if agentConfig.ACL.Enabled {
 		conf.ACLEnabled = true
 	}
 	if agentConfig.ACL.ReplicationToken != "" {
 		conf.ReplicationToken = agentConfig.ACL.ReplicationToken
 	}
+	conf.ACLTokenMaxExpirationTTL = agentConfig.ACL.TokenMaxExpirationTTL
+	conf.ACLBootstrapTokenFile = agentConfig.ACL.BootstrapTokenFile
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
@@ -207,6 +257,76 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Server.UpgradeVersion != "" {
 		conf.UpgradeVersion = agentConfig.Server.UpgradeVersion
 	}
+	for _, webhook := range agentConfig.Server.JobAdmissionWebhooks {
+		if err := webhook.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid job_admission_webhook config: %v", err)
+		}
+		webhook = webhook.Copy()
+		webhook.Canonicalize()
+		conf.JobAdmissionWebhooks = append(conf.JobAdmissionWebhooks, webhook)
+	}
+	ruleNamespaces := make(map[string]struct{})
+	for _, rule := range agentConfig.Server.JobAdmissionRules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid job_admission_rule config: %v", err)
+		}
+		if err := validateConfigNamespace(rule.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid job_admission_rule config: %v", err)
+		}
+		if _, ok := ruleNamespaces[rule.Namespace]; ok {
+			return nil, fmt.Errorf("invalid job_admission_rule config: duplicate rule for namespace %q", rule.Namespace)
+		}
+		ruleNamespaces[rule.Namespace] = struct{}{}
+		conf.JobAdmissionRules = append(conf.JobAdmissionRules, rule.Copy())
+	}
+	if limit := agentConfig.Server.DispatchPayloadSizeLimit; limit != "" {
+		size, err := humanize.ParseBytes(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dispatch_payload_size_limit %q: %v", limit, err)
+		}
+		if size == 0 || size > math.MaxInt32 {
+			return nil, fmt.Errorf("dispatch_payload_size_limit must be between 1 byte and 2GiB")
+		}
+		conf.DispatchPayloadSizeLimit = int(size)
+	}
+	if key := agentConfig.Server.DeploymentWebhookSigningKey; key != "" {
+		conf.DeploymentWebhookSigningKey = key
+	}
+	for _, pattern := range agentConfig.Server.RedactEnvVars {
+		if pattern == "" {
+			return nil, fmt.Errorf("redact_env_vars patterns must not be empty")
+		}
+		conf.RedactEnvVars = append(conf.RedactEnvVars, pattern)
+	}
+	for _, signing := range agentConfig.Server.JobSigning {
+		if err := signing.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid job_signing config: %v", err)
+		}
+		if err := validateConfigNamespace(signing.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid job_signing config: %v", err)
+		}
+		conf.JobSigning = append(conf.JobSigning, signing.Copy())
+	}
+	defaultsNamespaces := make(map[string]struct{})
+	for _, defaults := range agentConfig.Server.NamespaceDefaults {
+		if err := defaults.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid namespace_defaults config: %v", err)
+		}
+		if err := validateConfigNamespace(defaults.Namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace_defaults config: %v", err)
+		}
+		if _, ok := defaultsNamespaces[defaults.Namespace]; ok {
+			return nil, fmt.Errorf("invalid namespace_defaults config: duplicate defaults for namespace %q", defaults.Namespace)
+		}
+		defaultsNamespaces[defaults.Namespace] = struct{}{}
+		if c := defaults.VaultCluster; c != "" && !agentConfig.hasVaultCluster(c) {
+			return nil, fmt.Errorf("invalid namespace_defaults config: namespace %q uses unknown Vault cluster %q", defaults.Namespace, c)
+		}
+		if c := defaults.ConsulCluster; c != "" && !agentConfig.hasConsulCluster(c) {
+			return nil, fmt.Errorf("invalid namespace_defaults config: namespace %q uses unknown Consul cluster %q", defaults.Namespace, c)
+		}
+		conf.NamespaceDefaults = append(conf.NamespaceDefaults, defaults.Copy())
+	}
 	if agentConfig.Autopilot != nil {
 		if agentConfig.Autopilot.CleanupDeadServers != nil {
 			conf.AutopilotConfig.CleanupDeadServers = *agentConfig.Autopilot.CleanupDeadServers
@@ -282,6 +402,27 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.JobGCThreshold = dur
 	}
+	if gcThreshold := agentConfig.Server.BatchJobGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.BatchJobGCThreshold = dur
+	}
+	if gcThreshold := agentConfig.Server.ServiceJobGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.ServiceJobGCThreshold = dur
+	}
+	if gcThreshold := agentConfig.Server.SystemJobGCThreshold; gcThreshold != "" {
+		dur, err := time.ParseDuration(gcThreshold)
+		if err != nil {
+			return nil, err
+		}
+		conf.SystemJobGCThreshold = dur
+	}
 	if gcThreshold := agentConfig.Server.EvalGCThreshold; gcThreshold != "" {
 		dur, err := time.ParseDuration(gcThreshold)
 		if err != nil {
@@ -314,10 +455,24 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	// Add the Consul and Vault configs
 	conf.ConsulConfig = agentConfig.Consul
 	conf.VaultConfig = agentConfig.Vault
+	conf.ConsulClusters = agentConfig.Consuls
+	conf.VaultClusters = agentConfig.Vaults
 
 	// Set the TLS config
 	conf.TLSConfig = agentConfig.TLSConfig
 
+	// Set the snapshot agent config
+	if err := agentConfig.SnapshotAgent.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid snapshot_agent config: %v", err)
+	}
+	conf.SnapshotAgentConfig = agentConfig.SnapshotAgent
+
+	// Set the external DNS config
+	if err := agentConfig.ExternalDNS.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid external_dns config: %v", err)
+	}
+	conf.ExternalDNSConfig = agentConfig.ExternalDNS
+
 	// Setup telemetry related config
 	conf.StatsCollectionInterval = agentConfig.Telemetry.collectionInterval
 	conf.DisableTaggedMetrics = agentConfig.Telemetry.DisableTaggedMetrics
@@ -441,6 +596,23 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 		conf.NetworkInterface = agentConfig.Client.NetworkInterface
 	}
 	conf.ChrootEnv = agentConfig.Client.ChrootEnv
+	for ns, dir := range agentConfig.Client.NamespaceAllocDirs {
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("Alloc dir %q of namespace %q must be an absolute path", dir, ns)
+		}
+	}
+	conf.NamespaceAllocDirs = agentConfig.Client.NamespaceAllocDirs
+	for jobType, dir := range agentConfig.Client.JobTypeAllocDirs {
+		switch jobType {
+		case structs.JobTypeService, structs.JobTypeBatch, structs.JobTypeSystem:
+		default:
+			return nil, fmt.Errorf("Invalid job type %q in job_type_alloc_dirs", jobType)
+		}
+		if !filepath.IsAbs(dir) {
+			return nil, fmt.Errorf("Alloc dir %q of job type %q must be an absolute path", dir, jobType)
+		}
+	}
+	conf.JobTypeAllocDirs = agentConfig.Client.JobTypeAllocDirs
 	conf.Options = agentConfig.Client.Options
 	if agentConfig.Client.NetworkSpeed != 0 {
 		conf.NetworkSpeed = agentConfig.Client.NetworkSpeed
@@ -460,6 +632,36 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.ClientMaxPort = uint(agentConfig.Client.ClientMaxPort)
 	conf.ClientMinPort = uint(agentConfig.Client.ClientMinPort)
+	conf.DynamicUsers = agentConfig.Client.DynamicUsers
+	conf.DynamicUserMinID = agentConfig.Client.DynamicUserMinID
+	conf.DynamicUserMaxID = agentConfig.Client.DynamicUserMaxID
+	conf.DisablePrefetch = agentConfig.Client.DisablePrefetch
+	if limit := agentConfig.Client.MigrateBandwidthLimit; limit != "" {
+		bandwidth, err := humanize.ParseBytes(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migrate_bandwidth_limit %q: %v", limit, err)
+		}
+		if bandwidth == 0 || bandwidth > math.MaxInt32 {
+			return nil, fmt.Errorf("migrate_bandwidth_limit must be between 1 byte and 2GiB")
+		}
+		conf.MigrateBandwidthLimit = int(bandwidth)
+	}
+	conf.RPCCompression = agentConfig.Client.RPCCompression
+	conf.EnableFaultInjection = agentConfig.Client.EnableFaultInjection
+	if limit := agentConfig.Client.RPCBandwidthLimit; limit != "" {
+		bandwidth, err := humanize.ParseBytes(limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc_bandwidth_limit %q: %v", limit, err)
+		}
+		if bandwidth == 0 || bandwidth > math.MaxInt32 {
+			return nil, fmt.Errorf("rpc_bandwidth_limit must be between 1 byte and 2GiB")
+		}
+		conf.RPCBandwidthLimit = int(bandwidth)
+	}
+	if rt := agentConfig.Client.RealtimeRuntime; rt < 0 || rt > time.Second {
+		return nil, fmt.Errorf("realtime_runtime must be between 0 and 1s")
+	}
+	conf.RealtimeRuntime = agentConfig.Client.RealtimeRuntime
 
 	// Setup the node
 	conf.Node = new(structs.Node)
@@ -467,6 +669,92 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.Node.Name = agentConfig.NodeName
 	conf.Node.Meta = agentConfig.Client.Meta
 	conf.Node.NodeClass = agentConfig.Client.NodeClass
+	conf.Node.NodePool = agentConfig.Client.NodePool
+	if conf.Node.NodePool == "" {
+		conf.Node.NodePool = structs.NodePoolDefault
+	}
+	for _, w := range agentConfig.Client.MaintenanceWindows {
+		window := &structs.NodeMaintenanceWindow{
+			Cron:     w.Cron,
+			Duration: w.Duration,
+			TimeZone: w.TimeZone,
+		}
+		if err := window.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid maintenance_window config: %v", err)
+		}
+		conf.Node.MaintenanceWindows = append(conf.Node.MaintenanceWindows, window)
+	}
+	gateNames := make(map[string]struct{}, len(agentConfig.Client.ReadinessGates))
+	for _, g := range agentConfig.Client.ReadinessGates {
+		gate := &clientconfig.ReadinessGate{
+			Name:     g.Name,
+			Command:  g.Command,
+			Args:     g.Args,
+			URL:      g.URL,
+			Interval: g.Interval,
+			Timeout:  g.Timeout,
+		}
+		if err := gate.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid readiness_gate %q config: %v", g.Name, err)
+		}
+		if _, ok := gateNames[g.Name]; ok {
+			return nil, fmt.Errorf("duplicate readiness_gate %q", g.Name)
+		}
+		gateNames[g.Name] = struct{}{}
+		conf.ReadinessGates = append(conf.ReadinessGates, gate)
+	}
+	remediatedDrivers := make(map[string]struct{}, len(agentConfig.Client.DriverRemediations))
+	for _, r := range agentConfig.Client.DriverRemediations {
+		remediation := &clientconfig.DriverRemediation{
+			Driver:       r.Driver,
+			Command:      r.Command,
+			Args:         r.Args,
+			Timeout:      r.Timeout,
+			Backoff:      r.Backoff,
+			BackoffLimit: r.BackoffLimit,
+		}
+		if err := remediation.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid driver_remediation %q config: %v", r.Driver, err)
+		}
+		if _, ok := remediatedDrivers[r.Driver]; ok {
+			return nil, fmt.Errorf("duplicate driver_remediation %q", r.Driver)
+		}
+		remediatedDrivers[r.Driver] = struct{}{}
+		conf.DriverRemediations = append(conf.DriverRemediations, remediation)
+	}
+	providerNames := make(map[string]struct{}, len(agentConfig.Client.ServiceProviders))
+	for _, p := range agentConfig.Client.ServiceProviders {
+		provider := &clientconfig.ServiceProvider{
+			Name:    p.Name,
+			URL:     p.URL,
+			Timeout: p.Timeout,
+		}
+		if err := provider.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid service_provider %q config: %v", p.Name, err)
+		}
+		if _, ok := providerNames[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate service_provider %q", p.Name)
+		}
+		providerNames[p.Name] = struct{}{}
+		conf.ServiceProviders = append(conf.ServiceProviders, provider)
+	}
+	if e := agentConfig.Client.DataEncryption; e != nil && e.Enabled != nil && *e.Enabled {
+		encryption := &clientconfig.DataEncryption{
+			VaultTransitKey:   e.VaultTransitKey,
+			VaultTransitMount: e.VaultTransitMount,
+			VaultAddress:      e.VaultAddress,
+			VaultToken:        e.VaultToken,
+		}
+		if err := encryption.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid data_encryption config: %v", err)
+		}
+
+		// Reach Vault at the address of the vault block by default
+		if encryption.VaultTransitKey != "" && encryption.VaultAddress == "" && agentConfig.Vault != nil {
+			encryption.VaultAddress = agentConfig.Vault.Addr
+		}
+		conf.DataEncryption = encryption
+	}
 
 	// Set up the HTTP advertise address
 	conf.Node.HTTPAddr = agentConfig.AdvertiseAddrs.HTTP
@@ -500,6 +788,8 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 
 	conf.ConsulConfig = agentConfig.Consul
 	conf.VaultConfig = agentConfig.Vault
+	conf.ConsulClusters = agentConfig.Consuls
+	conf.VaultClusters = agentConfig.Vaults
 
 	// Set up Telemetry configuration
 	conf.StatsCollectionInterval = agentConfig.Telemetry.collectionInterval
@@ -518,6 +808,9 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.GCDiskUsageThreshold = agentConfig.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = agentConfig.Client.GCInodeUsageThreshold
 	conf.GCMaxAllocs = agentConfig.Client.GCMaxAllocs
+	if agentConfig.Client.MaxTaskEvents != 0 {
+		conf.MaxTaskEvents = agentConfig.Client.MaxTaskEvents
+	}
 	if agentConfig.Client.NoHostUUID != nil {
 		conf.NoHostUUID = *agentConfig.Client.NoHostUUID
 	} else {
@@ -924,6 +1217,12 @@ func (a *Agent) Stats() map[string]map[string]string {
 			stats[k] = v
 		}
 	}
+	if joinStats := a.serverJoinStatus.Stats(); joinStats != nil {
+		stats["server_join"] = joinStats
+	}
+	if joinStats := a.clientJoinStatus.Stats(); joinStats != nil {
+		stats["client_join"] = joinStats
+	}
 	return stats
 }
 
@@ -933,6 +1232,11 @@ func (a *Agent) ShouldReload(newConfig *Config) (agent, http bool) {
 	a.configLock.Lock()
 	defer a.configLock.Unlock()
 
+	// Automatic certificates keep being used until the agent restarts
+	if a.autoTLS != nil && newConfig.TLSConfig != nil {
+		a.autoTLS.apply(newConfig.TLSConfig)
+	}
+
 	isEqual, err := a.config.TLSConfig.CertificateInfoIsEqual(newConfig.TLSConfig)
 	if err != nil {
 		a.logger.Error("parsing TLS certificate", "error", err)
@@ -965,6 +1269,11 @@ func (a *Agent) Reload(newConfig *Config) error {
 		return fmt.Errorf("cannot reload agent with nil configuration")
 	}
 
+	// Automatic certificates keep being used until the agent restarts
+	if a.autoTLS != nil {
+		a.autoTLS.apply(newConfig.TLSConfig)
+	}
+
 	// This is just a TLS configuration reload, we don't need to refresh
 	// existing network connections
 	if !a.config.TLSConfig.IsEmpty() && !newConfig.TLSConfig.IsEmpty() {
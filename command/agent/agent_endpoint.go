@@ -1,11 +1,18 @@
 package agent
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"runtime/pprof"
+	"runtime/trace"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -13,6 +20,11 @@ import (
 	"github.com/mitchellh/copystructure"
 )
 
+const (
+	// maxPprofSeconds is the longest CPU profile or trace an agent collects
+	maxPprofSeconds = 300
+)
+
 type Member struct {
 	Name        string
 	Addr        net.IP
@@ -290,6 +302,96 @@ func (s *HTTPServer) KeyringOperationRequest(resp http.ResponseWriter, req *http
 	return kresp, nil
 }
 
+// AgentPprofRequest returns a runtime profile of the agent. The CPU profile
+// and the execution trace are collected for the duration given by the seconds
+// query parameter, while other profiles are snapshots in the format selected
+// by the debug query parameter, as for the net/http/pprof handlers.
+func (s *HTTPServer) AgentPprofRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var secret string
+	s.parseToken(req, &secret)
+
+	var aclObj *acl.ACL
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		aclObj, err = srv.ResolveToken(secret)
+	} else {
+		aclObj, err = s.agent.Client().ResolveToken(secret)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Profiles expose the memory of the agent so they require agent write
+	// permissions, or enable_debug when ACLs are disabled
+	if aclObj == nil && !s.agent.config.EnableDebug {
+		return nil, CodedError(403, "profiling requires enable_debug when ACLs are disabled")
+	} else if aclObj != nil && !aclObj.AllowAgentWrite() {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	query := req.URL.Query()
+	seconds := 1
+	if secondsStr := query.Get("seconds"); secondsStr != "" {
+		seconds, err = strconv.Atoi(secondsStr)
+		if err != nil || seconds <= 0 || seconds > maxPprofSeconds {
+			return nil, CodedError(400, fmt.Sprintf("seconds must be between 1 and %d", maxPprofSeconds))
+		}
+	}
+	debug := 0
+	if debugStr := query.Get("debug"); debugStr != "" {
+		debug, err = strconv.Atoi(debugStr)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse debug: %v", err))
+		}
+	}
+
+	var buf bytes.Buffer
+	profile := strings.TrimPrefix(req.URL.Path, "/v1/agent/pprof/")
+	switch profile {
+	case "profile":
+		if err := pprof.StartCPUProfile(&buf); err != nil {
+			return nil, CodedError(500, err.Error())
+		}
+		waitPprof(req, seconds)
+		pprof.StopCPUProfile()
+	case "trace":
+		if err := trace.Start(&buf); err != nil {
+			return nil, CodedError(500, err.Error())
+		}
+		waitPprof(req, seconds)
+		trace.Stop()
+	default:
+		p := pprof.Lookup(profile)
+		if p == nil {
+			return nil, CodedError(404, fmt.Sprintf("unknown profile %q", profile))
+		}
+		if err := p.WriteTo(&buf, debug); err != nil {
+			return nil, CodedError(500, err.Error())
+		}
+	}
+
+	if debug > 0 && profile != "profile" && profile != "trace" {
+		resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		resp.Header().Set("Content-Type", "application/octet-stream")
+	}
+	_, err = io.Copy(resp, &buf)
+	return nil, err
+}
+
+// waitPprof waits for the duration of a profile or until the request is
+// canceled.
+func waitPprof(req *http.Request, seconds int) {
+	select {
+	case <-time.After(time.Duration(seconds) * time.Second):
+	case <-req.Context().Done():
+	}
+}
+
 type agentSelf struct {
 	Config *Config                      `json:"config"`
 	Member Member                       `json:"member,omitempty"`
@@ -380,6 +482,26 @@ func (s *HTTPServer) HealthRequest(resp http.ResponseWriter, req *http.Request)
 	return nil, CodedError(500, string(jsonResp))
 }
 
+// AgentSelfHealthRequest returns the health of the subsystems of the agent.
+// Like the health endpoint it doesn't require a token, so that load balancers
+// and service managers can check it, and it fails if the agent is unhealthy.
+func (s *HTTPServer) AgentSelfHealthRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	health := s.agent.selfHealth()
+	if health.Healthy {
+		return health, nil
+	}
+
+	jsonResp, err := json.Marshal(health)
+	if err != nil {
+		return nil, err
+	}
+	return nil, CodedError(503, string(jsonResp))
+}
+
 type healthResponse struct {
 	Client *healthResponseAgent `json:"client,omitempty"`
 	Server *healthResponseAgent `json:"server,omitempty"`
@@ -667,6 +667,91 @@ func TestHTTP_AgentRemoveKey(t *testing.T) {
 	})
 }
 
+func TestHTTP_AgentPprof(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		// Profiles are snapshots in the requested format
+		req, err := http.NewRequest("GET", "/v1/agent/pprof/goroutine?debug=2", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.AgentPprofRequest(respW, req)
+		require.NoError(err)
+		require.Contains(respW.Body.String(), "goroutine")
+		require.Contains(respW.Header().Get("Content-Type"), "text/plain")
+
+		// CPU profiles are collected for the given duration
+		req, err = http.NewRequest("GET", "/v1/agent/pprof/profile?seconds=1", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.AgentPprofRequest(respW, req)
+		require.NoError(err)
+		require.NotZero(respW.Body.Len())
+		require.Equal("application/octet-stream", respW.Header().Get("Content-Type"))
+
+		// Unknown profiles and bad durations are rejected
+		req, err = http.NewRequest("GET", "/v1/agent/pprof/unknown", nil)
+		require.NoError(err)
+		_, err = s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Equal(404, err.(HTTPCodedError).Code())
+
+		req, err = http.NewRequest("GET", "/v1/agent/pprof/trace?seconds=0", nil)
+		require.NoError(err)
+		_, err = s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+	})
+
+	// Profiling without ACLs requires enable_debug
+	httpTest(t, func(c *Config) { c.EnableDebug = false }, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/agent/pprof/heap", nil)
+		require.NoError(err)
+		_, err = s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Equal(403, err.(HTTPCodedError).Code())
+	})
+}
+
+func TestHTTP_AgentPprof_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpACLTest(t, func(c *Config) { c.EnableDebug = false }, func(s *TestAgent) {
+		state := s.Agent.server.State()
+
+		req, err := http.NewRequest("GET", "/v1/agent/pprof/heap", nil)
+		require.NoError(err)
+
+		// Try request without a token and expect failure
+		{
+			_, err := s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+		}
+
+		// Try request with a read token and expect failure
+		{
+			token := mock.CreatePolicyAndToken(t, state, 1005, "invalid", mock.AgentPolicy(acl.PolicyRead))
+			setToken(req, token)
+			_, err := s.Server.AgentPprofRequest(httptest.NewRecorder(), req)
+			require.NotNil(err)
+			require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+		}
+
+		// Try request with a valid token
+		{
+			token := mock.CreatePolicyAndToken(t, state, 1007, "valid", mock.AgentPolicy(acl.PolicyWrite))
+			setToken(req, token)
+			respW := httptest.NewRecorder()
+			_, err := s.Server.AgentPprofRequest(respW, req)
+			require.Nil(err)
+			require.NotZero(respW.Body.Len())
+		}
+	})
+}
+
 func TestHTTP_AgentHealth_Ok(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -747,6 +832,37 @@ func TestHTTP_AgentHealth_Ok(t *testing.T) {
 	})
 }
 
+func TestHTTP_AgentSelfHealth(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	httpTest(t, nil, func(s *TestAgent) {
+		testutil.WaitForLeader(t, s.Agent.RPC)
+
+		req, err := http.NewRequest("GET", "/v1/agent/self/health", nil)
+		require.Nil(err)
+
+		respW := httptest.NewRecorder()
+		healthI, err := s.Server.AgentSelfHealthRequest(respW, req)
+		require.Nil(err)
+		health := healthI.(*agentSelfHealth)
+		require.True(health.Healthy)
+		for _, name := range []string{selfHealthRaft, selfHealthSerf, selfHealthLeadership, selfHealthServers} {
+			require.Contains(health.Subsystems, name)
+			require.True(health.Subsystems[name].Critical, name)
+		}
+		require.True(health.Subsystems[selfHealthLeadership].Healthy)
+		require.Equal("true", health.Subsystems[selfHealthLeadership].Details["leader"])
+
+		// Only GET is allowed
+		req, err = http.NewRequest("PUT", "/v1/agent/self/health", nil)
+		require.Nil(err)
+		_, err = s.Server.AgentSelfHealthRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+	})
+}
+
 func TestHTTP_AgentHealth_BadServer(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -262,6 +262,121 @@ func TestAgent_ServerConfig(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_NamespaceDefaults(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Vaults = []*sconfig.VaultConfig{{Name: "secrets"}}
+	conf.Consuls = []*sconfig.ConsulConfig{{Name: "edge"}}
+	conf.Server.NamespaceDefaults = []*sconfig.NamespaceDefaultsConfig{
+		{
+			Namespace:     "default",
+			VaultCluster:  "secrets",
+			ConsulCluster: "edge",
+		},
+	}
+	conf.AdvertiseAddrs.Serf = "127.0.0.1:4000"
+	conf.AdvertiseAddrs.RPC = "127.0.0.1:4001"
+	conf.AdvertiseAddrs.HTTP = "127.0.0.1:4005"
+	require.NoError(conf.normalizeAddrs())
+	a := &Agent{config: conf}
+
+	out, err := a.serverConfig()
+	require.NoError(err)
+	require.Len(out.NamespaceDefaults, 1)
+	require.Equal("secrets", out.NamespaceDefaults[0].VaultCluster)
+	require.Len(out.VaultClusters, 1)
+	require.Len(out.ConsulClusters, 1)
+
+	// Defaults must use configured clusters
+	conf.Server.NamespaceDefaults[0].ConsulCluster = "unknown"
+	_, err = a.serverConfig()
+	require.Error(err)
+	require.Contains(err.Error(), `unknown Consul cluster "unknown"`)
+
+	// Defaults can only apply to existing namespaces
+	conf.Server.NamespaceDefaults[0].ConsulCluster = "default"
+	conf.Server.NamespaceDefaults[0].Namespace = "finance"
+	_, err = a.serverConfig()
+	require.Error(err)
+	require.Contains(err.Error(), `namespace "finance" does not exist`)
+
+	// Namespaces can only have one defaults block
+	conf.Server.NamespaceDefaults[0].Namespace = "default"
+	conf.Server.NamespaceDefaults = append(conf.Server.NamespaceDefaults,
+		&sconfig.NamespaceDefaultsConfig{Namespace: "default", VaultCluster: "secrets"})
+	_, err = a.serverConfig()
+	require.Error(err)
+	require.Contains(err.Error(), `duplicate defaults for namespace "default"`)
+}
+
+func TestAgent_ServerConfig_JobAdmissionRules(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Server.JobAdmissionRules = []*sconfig.JobAdmissionRuleConfig{
+		{
+			Namespace: "default",
+			Drivers:   []string{"docker"},
+		},
+	}
+	conf.AdvertiseAddrs.Serf = "127.0.0.1:4000"
+	conf.AdvertiseAddrs.RPC = "127.0.0.1:4001"
+	conf.AdvertiseAddrs.HTTP = "127.0.0.1:4005"
+	require.NoError(conf.normalizeAddrs())
+	a := &Agent{config: conf}
+
+	out, err := a.serverConfig()
+	require.NoError(err)
+	require.Len(out.JobAdmissionRules, 1)
+
+	// Rules can only apply to existing namespaces
+	conf.Server.JobAdmissionRules[0].Namespace = "untrusted"
+	_, err = a.serverConfig()
+	require.Error(err)
+	require.Contains(err.Error(), `namespace "untrusted" does not exist`)
+}
+
+func TestAgent_ServerConfig_JobSigning(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Server.JobSigning = []*sconfig.JobSigningConfig{
+		{
+			Namespace: "default",
+			Signers: []*sconfig.JobSignerConfig{
+				{
+					Name: "release-bot",
+					PublicKey: `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEADXFRXX3lQICIxRalPeaiiKaD7jSQQ6fxtn8MuM98ook=
+-----END PUBLIC KEY-----`,
+				},
+			},
+		},
+	}
+	conf.AdvertiseAddrs.Serf = "127.0.0.1:4000"
+	conf.AdvertiseAddrs.RPC = "127.0.0.1:4001"
+	conf.AdvertiseAddrs.HTTP = "127.0.0.1:4005"
+	require.NoError(conf.normalizeAddrs())
+	a := &Agent{config: conf}
+
+	out, err := a.serverConfig()
+	require.NoError(err)
+	require.Len(out.JobSigning, 1)
+
+	// Signers can only be configured for existing namespaces
+	conf.Server.JobSigning[0].Namespace = "prod"
+	_, err = a.serverConfig()
+	require.Error(err)
+	require.Contains(err.Error(), `namespace "prod" does not exist`)
+}
+
 func TestAgent_ClientConfig(t *testing.T) {
 	t.Parallel()
 	conf := DefaultConfig()
@@ -306,6 +421,198 @@ func TestAgent_ClientConfig(t *testing.T) {
 	}
 }
 
+func TestAgent_ClientConfig_AllocDirs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Client.Enabled = true
+	conf.Client.NamespaceAllocDirs = map[string]string{"tenant": "/mnt/tenant/alloc"}
+	conf.Client.JobTypeAllocDirs = map[string]string{"batch": "/mnt/scratch/alloc"}
+	a := &Agent{config: conf}
+
+	c, err := a.clientConfig()
+	require.NoError(err)
+	require.Equal(conf.Client.NamespaceAllocDirs, c.NamespaceAllocDirs)
+	require.Equal(conf.Client.JobTypeAllocDirs, c.JobTypeAllocDirs)
+
+	// Job types must be known
+	conf.Client.JobTypeAllocDirs = map[string]string{"cron": "/mnt/scratch/alloc"}
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "Invalid job type")
+
+	// Directories must be absolute
+	conf.Client.JobTypeAllocDirs = nil
+	conf.Client.NamespaceAllocDirs = map[string]string{"tenant": "alloc"}
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "absolute path")
+}
+
+func TestAgent_ClientConfig_ReadinessGates(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Client.Enabled = true
+	conf.Client.ReadinessGates = []*ReadinessGateConfig{
+		{
+			Name:     "image-cache",
+			Command:  "/usr/local/bin/check-image-cache",
+			Args:     []string{"-min", "3"},
+			Interval: 5 * time.Second,
+		},
+		{
+			Name: "volume",
+			URL:  "http://127.0.0.1:8080/volume",
+		},
+	}
+	a := &Agent{config: conf}
+
+	c, err := a.clientConfig()
+	require.NoError(err)
+	require.Len(c.ReadinessGates, 2)
+	require.Equal("image-cache", c.ReadinessGates[0].Name)
+	require.Equal([]string{"-min", "3"}, c.ReadinessGates[0].Args)
+	require.Equal(5*time.Second, c.ReadinessGates[0].Interval)
+	require.Equal("http://127.0.0.1:8080/volume", c.ReadinessGates[1].URL)
+
+	// Gates must either run a command or query a url
+	conf.Client.ReadinessGates[1].Command = "true"
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "exactly one of command or url")
+
+	// Names must be unique
+	conf.Client.ReadinessGates[1].Command = ""
+	conf.Client.ReadinessGates[1].Name = "image-cache"
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "duplicate readiness_gate")
+}
+
+func TestAgent_ClientConfig_ServiceProviders(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Client.Enabled = true
+	conf.Client.ServiceProviders = []*ServiceProviderConfig{
+		{
+			Name:    "f5",
+			URL:     "https://lb-adapter.example.com/v1/pools/web",
+			Timeout: 5 * time.Second,
+		},
+	}
+	a := &Agent{config: conf}
+
+	c, err := a.clientConfig()
+	require.NoError(err)
+	require.Len(c.ServiceProviders, 1)
+	require.Equal("f5", c.ServiceProviders[0].Name)
+	require.Equal("https://lb-adapter.example.com/v1/pools/web", c.ServiceProviders[0].URL)
+	require.Equal(5*time.Second, c.ServiceProviders[0].Timeout)
+
+	// The names of the built-in providers are reserved
+	conf.Client.ServiceProviders[0].Name = "nomad"
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "reserved")
+
+	// Names must be unique
+	conf.Client.ServiceProviders[0].Name = "f5"
+	conf.Client.ServiceProviders = append(conf.Client.ServiceProviders, &ServiceProviderConfig{
+		Name: "f5",
+		URL:  "http://127.0.0.1:8080",
+	})
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "duplicate service_provider")
+}
+
+func TestAgent_ClientConfig_DataEncryption(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Client.Enabled = true
+	a := &Agent{config: conf}
+
+	// Encryption is disabled by default
+	c, err := a.clientConfig()
+	require.NoError(err)
+	require.Nil(c.DataEncryption)
+
+	conf.Client.DataEncryption = &DataEncryptionConfig{
+		Enabled: helper.BoolToPtr(true),
+	}
+	c, err = a.clientConfig()
+	require.NoError(err)
+	require.NotNil(c.DataEncryption)
+	require.Empty(c.DataEncryption.VaultAddress)
+
+	// Vault is reached at the address of the vault block by default
+	conf.Vault.Addr = "https://vault.service.consul:8200"
+	conf.Client.DataEncryption.VaultTransitKey = "nomad-client"
+	c, err = a.clientConfig()
+	require.NoError(err)
+	require.Equal("nomad-client", c.DataEncryption.VaultTransitKey)
+	require.Equal("https://vault.service.consul:8200", c.DataEncryption.VaultAddress)
+
+	// Vault options need a transit key
+	conf.Client.DataEncryption.VaultTransitKey = ""
+	conf.Client.DataEncryption.VaultTransitMount = "secrets"
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "vault_transit_key must be set")
+}
+
+func TestAgent_ClientConfig_DriverRemediations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.DevMode = true
+	conf.Client.Enabled = true
+	conf.Client.DriverRemediations = []*DriverRemediationConfig{
+		{
+			Driver:  "docker",
+			Command: "/usr/local/bin/restart-docker",
+			Backoff: 10 * time.Second,
+		},
+		{
+			Driver: "exec",
+		},
+	}
+	a := &Agent{config: conf}
+
+	c, err := a.clientConfig()
+	require.NoError(err)
+	require.Len(c.DriverRemediations, 2)
+	require.Equal("docker", c.DriverRemediations[0].Driver)
+	require.Equal("/usr/local/bin/restart-docker", c.DriverRemediations[0].Command)
+	require.Equal(10*time.Second, c.DriverRemediations[0].Backoff)
+	require.Empty(c.DriverRemediations[1].Command)
+
+	// The backoff limit can't be less than the backoff
+	conf.Client.DriverRemediations[0].BackoffLimit = time.Second
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "backoff_limit")
+
+	// Drivers must be unique
+	conf.Client.DriverRemediations[0].BackoffLimit = 0
+	conf.Client.DriverRemediations[1].Driver = "docker"
+	_, err = a.clientConfig()
+	require.Error(err)
+	require.Contains(err.Error(), "duplicate driver_remediation")
+}
+
 // Clients should inherit telemetry configuration
 func TestAget_Client_TelemetryConfiguration(t *testing.T) {
 	assert := assert.New(t)
@@ -2,10 +2,13 @@ package agent
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/golang/snappy"
+	"github.com/hashicorp/nomad/api"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -41,7 +44,15 @@ func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request)
 }
 
 func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
-	allocID := strings.TrimPrefix(req.URL.Path, "/v1/allocation/")
+	path := strings.TrimPrefix(req.URL.Path, "/v1/allocation/")
+	if strings.HasSuffix(path, "/reschedule") {
+		allocID := strings.TrimSuffix(path, "/reschedule")
+		return s.allocRescheduleNow(resp, req, allocID)
+	}
+	return s.allocQuery(resp, req, path)
+}
+
+func (s *HTTPServer) allocQuery(resp http.ResponseWriter, req *http.Request, allocID string) (interface{}, error) {
 	if req.Method != "GET" {
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
@@ -78,6 +89,25 @@ func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Re
 	return alloc, nil
 }
 
+func (s *HTTPServer) allocRescheduleNow(resp http.ResponseWriter, req *http.Request, allocID string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.AllocRescheduleNowRequest{
+		AllocID: allocID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.AllocRescheduleNowResponse
+	if err := s.agent.RPC("Alloc.RescheduleNow", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
 	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/client/allocation/")
@@ -100,6 +130,12 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocSnapshot(allocID, resp, req)
 	case "gc":
 		return s.allocGC(allocID, resp, req)
+	case "restart":
+		return s.allocRestart(allocID, resp, req)
+	case "signal":
+		return s.allocSignal(allocID, resp, req)
+	case "action":
+		return s.allocRunAction(allocID, resp, req)
 	}
 
 	return nil, CodedError(404, resourceNotFoundErr)
@@ -172,6 +208,149 @@ func (s *HTTPServer) allocGC(allocID string, resp http.ResponseWriter, req *http
 	return nil, rpcErr
 }
 
+func (s *HTTPServer) allocRestart(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token
+	var restart api.AllocRestartRequest
+	if req.ContentLength != 0 {
+		if err := decodeBody(req, &restart); err != nil {
+			return nil, CodedError(400, err.Error())
+		}
+	}
+	if restart.Ordered && restart.TaskName != "" {
+		return nil, CodedError(400, "ordered restarts restart all the tasks of the allocation")
+	}
+	args := cstructs.AllocRestartRequest{
+		AllocID:     allocID,
+		TaskName:    restart.TaskName,
+		Ordered:     restart.Ordered,
+		WaitHealthy: restart.WaitHealthy,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply cstructs.AllocRestartResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Restart", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Restart", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Restart", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+		return nil, rpcErr
+	}
+
+	return &api.AllocRestartResponse{Healthy: reply.Healthy}, nil
+}
+
+func (s *HTTPServer) allocRunAction(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token
+	var action api.AllocActionRequest
+	if err := decodeBody(req, &action); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if action.Task == "" || action.Action == "" {
+		return nil, CodedError(400, "Task and Action must be specified")
+	}
+	args := cstructs.AllocActionRequest{
+		AllocID: allocID,
+		Task:    action.Task,
+		Action:  action.Action,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply cstructs.AllocActionResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.RunAction", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.RunAction", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.RunAction", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+		return nil, rpcErr
+	}
+
+	return &api.AllocActionResponse{
+		Output:   string(reply.Output),
+		ExitCode: reply.ExitCode,
+	}, nil
+}
+
+func (s *HTTPServer) allocSignal(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token
+	var signal api.AllocSignalRequest
+	if err := decodeBody(req, &signal); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if signal.Signal == "" {
+		return nil, CodedError(400, "Signal must be set")
+	}
+	args := cstructs.AllocSignalRequest{
+		AllocID: allocID,
+		Task:    signal.Task,
+		Signal:  signal.Signal,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply structs.GenericResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Signal", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Signal", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Signal", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return nil, rpcErr
+}
+
 func (s *HTTPServer) allocSnapshot(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var secret string
 	s.parseToken(req, &secret)
@@ -179,16 +358,47 @@ func (s *HTTPServer) allocSnapshot(allocID string, resp http.ResponseWriter, req
 		return nil, structs.ErrPermissionDenied
 	}
 
+	// The offset allows resuming an interrupted migration, skipping the
+	// bytes of the snapshot already streamed
+	var offset int64
+	if o := req.URL.Query().Get("offset"); o != "" {
+		var err error
+		offset, err = strconv.ParseInt(o, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, CodedError(400, fmt.Sprintf("invalid offset %q", o))
+		}
+	}
+
 	allocFS, err := s.agent.Client().GetAllocFS(allocID)
 	if err != nil {
 		return nil, fmt.Errorf(allocNotFoundErr)
 	}
-	if err := allocFS.Snapshot(resp); err != nil {
+	if err := allocFS.Snapshot(&skipWriter{w: resp, skip: offset}); err != nil {
 		return nil, fmt.Errorf("error making snapshot: %v", err)
 	}
 	return nil, nil
 }
 
+// skipWriter discards the first skip bytes written to it.
+type skipWriter struct {
+	w    io.Writer
+	skip int64
+}
+
+func (s *skipWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if s.skip >= int64(n) {
+		s.skip -= int64(n)
+		return n, nil
+	}
+	p = p[s.skip:]
+	s.skip = 0
+	if _, err := s.w.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
 func (s *HTTPServer) allocStats(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
 	// Build the request and parse the ACL token
@@ -199,6 +409,23 @@ func (s *HTTPServer) allocStats(allocID string, resp http.ResponseWriter, req *h
 	}
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
 
+	// Parse the optional range of the history to return
+	for param, dst := range map[string]*int64{"start": &args.Start, "end": &args.End} {
+		if v := req.URL.Query().Get(param); v != "" {
+			ts, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, CodedError(400, fmt.Sprintf("invalid %s timestamp %q: %v", param, v, err))
+			}
+			*dst = ts
+		}
+	}
+	if args.End != 0 && args.Start == 0 {
+		return nil, CodedError(400, "end requires a start timestamp")
+	}
+	if args.End != 0 && args.End < args.Start {
+		return nil, CodedError(400, "end must not be before start")
+	}
+
 	// Determine the handler to use
 	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
 
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"archive/tar"
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -204,6 +206,47 @@ func TestHTTP_AllocQuery(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocRescheduleNow(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		alloc.ClientStatus = structs.AllocClientStatusFailed
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", "/v1/allocation/"+alloc.ID+"/reschedule", nil)
+		require.NoError(err)
+		_, err = s.Server.AllocSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+
+		// Make the HTTP request
+		req, err = http.NewRequest("PUT", "/v1/allocation/"+alloc.ID+"/reschedule", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.AllocSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out := obj.(structs.AllocRescheduleNowResponse)
+		require.NotEmpty(out.EvalID)
+
+		// Check the reschedule status of the job
+		req, err = http.NewRequest("GET", "/v1/job/"+alloc.JobID+"/reschedules", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.JobSpecificRequest(respW, req)
+		require.NoError(err)
+
+		statuses := obj.([]*structs.AllocRescheduleStatus)
+		require.Len(statuses, 1)
+		require.Equal(alloc.ID, statuses[0].AllocID)
+	})
+}
+
 func TestHTTP_AllocQuery_Payload(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -281,6 +324,19 @@ func TestHTTP_AllocStats(t *testing.T) {
 			require.True(structs.IsErrUnknownAllocation(err))
 		}
 
+		// Invalid ranges are rejected
+		for _, query := range []string{"start=foo", "end=10", "start=10&end=5"} {
+			req, err := http.NewRequest("GET", fmt.Sprintf("/v1/client/allocation/%s/stats?%s", uuid.Generate(), query), nil)
+			require.Nil(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ClientAllocRequest(respW, req)
+			require.Error(err, query)
+			codedErr, ok := err.(HTTPCodedError)
+			require.True(ok, query)
+			require.Equal(400, codedErr.Code(), query)
+		}
+
 		// Local node, server resp
 		{
 			srv := s.server
@@ -432,6 +488,29 @@ func TestHTTP_AllocSnapshot_WithMigrateToken(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocSnapshot_Offset(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/client/allocation/123/snapshot?offset=-1", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "invalid offset")
+	})
+
+	// The first bytes written are skipped, even across writes
+	buf := new(bytes.Buffer)
+	w := &skipWriter{w: buf, skip: 5}
+	for _, p := range []string{"abc", "defg", "hij"} {
+		n, err := w.Write([]byte(p))
+		require.NoError(err)
+		require.Equal(len(p), n)
+	}
+	require.Equal("fghij", buf.String())
+}
+
 // TestHTTP_AllocSnapshot_Atomic ensures that when a client encounters an error
 // snapshotting a valid tar is not returned.
 func TestHTTP_AllocSnapshot_Atomic(t *testing.T) {
@@ -669,6 +748,129 @@ func TestHTTP_AllocGC_ACL(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocRestart(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/restart", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+
+		// Ordered restarts restart all the tasks
+		req, err = http.NewRequest("PUT", path, strings.NewReader(`{"TaskName": "web", "Ordered": true}`))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+
+		// Local node, local resp
+		body := encodeReq(&api.AllocRestartRequest{WaitHealthy: true})
+		req, err = http.NewRequest("PUT", path, body)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err), "unexpected err: %v", err)
+
+		// Local node, server resp
+		srv := s.server
+		s.server = nil
+		req, err = http.NewRequest("PUT", path, nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err), "unexpected err: %v", err)
+		s.server = srv
+	})
+}
+
+func TestHTTP_AllocRunAction(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/action", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+
+		// The task and action are required
+		req, err = http.NewRequest("PUT", path, encodeReq(&api.AllocActionRequest{Task: "web"}))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+
+		// Local node, local resp
+		body := &api.AllocActionRequest{Task: "web", Action: "flush"}
+		req, err = http.NewRequest("PUT", path, encodeReq(body))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err), "unexpected err: %v", err)
+
+		// Local node, server resp
+		srv := s.server
+		s.server = nil
+		req, err = http.NewRequest("PUT", path, encodeReq(body))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err), "unexpected err: %v", err)
+		s.server = srv
+	})
+}
+
+func TestHTTP_AllocSignal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/signal", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+
+		// The signal is required
+		req, err = http.NewRequest("PUT", path, encodeReq(&api.AllocSignalRequest{}))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+
+		// Local node, local resp
+		body := &api.AllocSignalRequest{Signal: "SIGHUP"}
+		req, err = http.NewRequest("PUT", path, encodeReq(body))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err), "unexpected err: %v", err)
+
+		// Local node, server resp
+		srv := s.server
+		s.server = nil
+		req, err = http.NewRequest("PUT", path, encodeReq(body))
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.True(structs.IsErrUnknownAllocation(err), "unexpected err: %v", err)
+		s.server = srv
+	})
+}
+
 func TestHTTP_AllocAllGC(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
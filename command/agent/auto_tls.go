@@ -0,0 +1,433 @@
+package agent
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+const (
+	// autoTLSCertFile, autoTLSKeyFile and autoTLSCAFile are the names of the
+	// files automatic certificates are written to
+	autoTLSCertFile = "cert.pem"
+	autoTLSKeyFile  = "key.pem"
+	autoTLSCAFile   = "ca.pem"
+
+	// autoTLSRetryInterval is how long to wait before retrying to renew a
+	// certificate after a failure
+	autoTLSRetryInterval = time.Minute
+)
+
+// certRequest describes the certificate to issue.
+type certRequest struct {
+	CommonName string
+	DNSNames   []string
+	IPs        []net.IP
+	TTL        time.Duration
+}
+
+// issuedCert is an issued certificate with its key and the CA that issued
+// it, PEM encoded.
+type issuedCert struct {
+	Cert []byte
+	Key  []byte
+	CA   []byte
+}
+
+// certIssuer issues the TLS certificates of the agent.
+type certIssuer interface {
+	Issue(req *certRequest) (*issuedCert, error)
+}
+
+// autoTLS issues the TLS certificate of the agent and rotates it before it
+// expires.
+type autoTLS struct {
+	issuer  certIssuer
+	request *certRequest
+
+	certFile string
+	keyFile  string
+	caFile   string
+
+	// writeCA is whether the CA returned by the issuer is written to caFile.
+	// It isn't when the operator manages the CA file.
+	writeCA bool
+
+	logger log.Logger
+}
+
+// setupAutoTLS issues the agent's first certificate if automatic
+// certificates are enabled, and points the TLS configuration at it. It must
+// run before the server and client are created.
+func (a *Agent) setupAutoTLS() error {
+	tlsConf := a.config.TLSConfig
+	if tlsConf == nil || !tlsConf.Auto.IsEnabled() {
+		return nil
+	}
+	auto := tlsConf.Auto
+	if err := auto.Validate(); err != nil {
+		return fmt.Errorf("invalid tls auto configuration: %v", err)
+	}
+
+	dir := auto.Dir
+	if dir == "" {
+		if a.config.DataDir == "" {
+			return fmt.Errorf("tls auto dir must be set when the agent has no data_dir")
+		}
+		dir = filepath.Join(a.config.DataDir, "tls")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create tls auto dir: %v", err)
+	}
+
+	var issuer certIssuer
+	var err error
+	switch auto.Provider {
+	case config.AutoTLSProviderVault:
+		issuer, err = newVaultCertIssuer(a.config.Vault, auto)
+	case config.AutoTLSProviderBuiltin:
+		issuer, err = newBuiltinCertIssuer(tlsConf.CAFile, auto.CAKeyFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	at := &autoTLS{
+		issuer:   issuer,
+		request:  a.autoTLSRequest(auto),
+		certFile: filepath.Join(dir, autoTLSCertFile),
+		keyFile:  filepath.Join(dir, autoTLSKeyFile),
+		caFile:   tlsConf.CAFile,
+		logger:   a.logger.Named("auto_tls"),
+	}
+	if at.caFile == "" {
+		at.caFile = filepath.Join(dir, autoTLSCAFile)
+		at.writeCA = true
+	}
+
+	cert, err := at.renew()
+	if err != nil {
+		return fmt.Errorf("failed to issue TLS certificate: %v", err)
+	}
+
+	a.autoTLS = at
+	at.apply(tlsConf)
+	go at.run(cert, a.reloadAutoTLS, a.shutdownCh)
+	return nil
+}
+
+// autoTLSRequest returns the certificate request of the agent. The
+// certificate is valid for the names servers and clients verify each other
+// with, and for the local address.
+func (a *Agent) autoTLSRequest(auto *config.AutoTLSConfig) *certRequest {
+	region := a.config.Region
+	if region == "" {
+		region = "global"
+	}
+
+	req := &certRequest{
+		IPs: []net.IP{net.ParseIP("127.0.0.1")},
+		TTL: auto.TTL,
+	}
+	if a.config.Server != nil && a.config.Server.Enabled {
+		req.DNSNames = append(req.DNSNames, fmt.Sprintf("server.%s.nomad", region))
+	}
+	if a.config.Client != nil && a.config.Client.Enabled {
+		req.DNSNames = append(req.DNSNames, fmt.Sprintf("client.%s.nomad", region))
+	}
+	req.DNSNames = append(req.DNSNames, "localhost")
+	for _, name := range auto.AltNames {
+		if ip := net.ParseIP(name); ip != nil {
+			req.IPs = append(req.IPs, ip)
+		} else {
+			req.DNSNames = append(req.DNSNames, name)
+		}
+	}
+	req.CommonName = req.DNSNames[0]
+	return req
+}
+
+// reloadAutoTLS loads the renewed certificate into the key loader shared by
+// the agent, server and client.
+func (a *Agent) reloadAutoTLS() error {
+	a.configLock.Lock()
+	defer a.configLock.Unlock()
+
+	tlsConf := a.config.TLSConfig
+	_, err := tlsConf.GetKeyLoader().LoadKeyPair(tlsConf.CertFile, tlsConf.KeyFile)
+	return err
+}
+
+// apply points the TLS configuration at the automatic certificate.
+func (at *autoTLS) apply(tlsConf *config.TLSConfig) {
+	tlsConf.CertFile = at.certFile
+	tlsConf.KeyFile = at.keyFile
+	tlsConf.CAFile = at.caFile
+}
+
+// renew issues a new certificate and writes it to disk, returning the parsed
+// certificate.
+func (at *autoTLS) renew() (*x509.Certificate, error) {
+	issued, err := at.issuer.Issue(at.request)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := parseCertificatePEM(issued.Cert)
+	if err != nil {
+		return nil, err
+	}
+
+	if at.writeCA {
+		if len(issued.CA) == 0 {
+			return nil, fmt.Errorf("issuer returned no CA certificate and tls ca_file isn't set")
+		}
+		if err := writeFileAtomic(at.caFile, issued.CA, 0644); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeFileAtomic(at.keyFile, issued.Key, 0600); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(at.certFile, issued.Cert, 0644); err != nil {
+		return nil, err
+	}
+
+	at.logger.Info("issued TLS certificate", "serial", cert.SerialNumber.String(), "expires", cert.NotAfter)
+	return cert, nil
+}
+
+// run renews the certificate once two thirds of its remaining lifetime has
+// elapsed and reloads it, until the shutdown channel is closed.
+func (at *autoTLS) run(cert *x509.Certificate, reload func() error, shutdownCh <-chan struct{}) {
+	timer := time.NewTimer(renewAfter(cert, time.Now()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+		case <-shutdownCh:
+			return
+		}
+
+		renewed, err := at.renew()
+		if err == nil {
+			err = reload()
+		}
+		if err != nil {
+			at.logger.Error("failed to renew TLS certificate", "error", err, "expires", cert.NotAfter)
+			timer.Reset(autoTLSRetryInterval)
+			continue
+		}
+
+		cert = renewed
+		timer.Reset(renewAfter(cert, time.Now()))
+	}
+}
+
+// renewAfter returns how long to wait before renewing a certificate that
+// was just issued: two thirds of its remaining lifetime.
+func renewAfter(cert *x509.Certificate, now time.Time) time.Duration {
+	wait := cert.NotAfter.Sub(now) * 2 / 3
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// writeFileAtomic writes the file through a temporary file so that readers
+// never see a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func parseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("failed to decode certificate PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// vaultCertIssuer issues certificates with the PKI secrets engine of Vault.
+type vaultCertIssuer struct {
+	client *vaultapi.Client
+	path   string
+}
+
+func newVaultCertIssuer(vaultConf *config.VaultConfig, auto *config.AutoTLSConfig) (*vaultCertIssuer, error) {
+	if vaultConf == nil {
+		vaultConf = config.DefaultVaultConfig()
+	}
+	apiConf, err := vaultConf.ApiConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault API config: %v", err)
+	}
+	client, err := vaultapi.NewClient(apiConf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault client: %v", err)
+	}
+	if vaultConf.Token != "" {
+		client.SetToken(vaultConf.Token)
+	}
+
+	return &vaultCertIssuer{
+		client: client,
+		path:   path.Join(strings.Trim(auto.VaultPKIPath, "/"), "issue", auto.VaultRole),
+	}, nil
+}
+
+func (v *vaultCertIssuer) Issue(req *certRequest) (*issuedCert, error) {
+	ips := make([]string, len(req.IPs))
+	for i, ip := range req.IPs {
+		ips[i] = ip.String()
+	}
+
+	secret, err := v.client.Logical().Write(v.path, map[string]interface{}{
+		"common_name": req.CommonName,
+		"alt_names":   strings.Join(req.DNSNames, ","),
+		"ip_sans":     strings.Join(ips, ","),
+		"ttl":         req.TTL.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("Vault returned no certificate")
+	}
+
+	cert, _ := secret.Data["certificate"].(string)
+	key, _ := secret.Data["private_key"].(string)
+	ca, _ := secret.Data["issuing_ca"].(string)
+	if cert == "" || key == "" {
+		return nil, fmt.Errorf("Vault returned no certificate")
+	}
+	return &issuedCert{
+		Cert: []byte(cert),
+		Key:  []byte(key),
+		CA:   []byte(ca),
+	}, nil
+}
+
+// builtinCertIssuer issues certificates signed by a CA whose certificate and
+// key are managed by the operator.
+type builtinCertIssuer struct {
+	caCert *x509.Certificate
+	caKey  crypto.Signer
+}
+
+func newBuiltinCertIssuer(caFile, caKeyFile string) (*builtinCertIssuer, error) {
+	if caFile == "" {
+		return nil, fmt.Errorf("tls ca_file must be set with the %q provider", config.AutoTLSProviderBuiltin)
+	}
+	certPEM, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %v", err)
+	}
+	caCert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %v", err)
+	}
+
+	keyPEM, err := ioutil.ReadFile(caKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA key: %v", err)
+	}
+	caKey, err := parsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA key: %v", err)
+	}
+
+	return &builtinCertIssuer{
+		caCert: caCert,
+		caKey:  caKey,
+	}, nil
+}
+
+func (b *builtinCertIssuer) Issue(req *certRequest) (*issuedCert, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	// Certificates can't outlive the CA
+	now := time.Now()
+	notAfter := now.Add(req.TTL)
+	if notAfter.After(b.caCert.NotAfter) {
+		notAfter = b.caCert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: req.CommonName},
+		DNSNames:     req.DNSNames,
+		IPAddresses:  req.IPs,
+		NotBefore:    now.Add(-time.Minute),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, b.caCert, &key.PublicKey, b.caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &issuedCert{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		CA:   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: b.caCert.Raw}),
+	}, nil
+}
+
+// parsePrivateKeyPEM parses an RSA or ECDSA private key in the PKCS #1, SEC 1
+// or PKCS #8 formats.
+func parsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode key PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported key format")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type")
+	}
+	return signer, nil
+}
@@ -0,0 +1,263 @@
+package agent
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCA writes a self-signed CA certificate and its key to dir and
+// returns their paths.
+func writeTestCA(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Nomad Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(dir, "ca.pem")
+	caKeyFile := filepath.Join(dir, "ca-key.pem")
+	require.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+	require.NoError(t, ioutil.WriteFile(caKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return caFile, caKeyFile
+}
+
+func TestAutoTLS_BuiltinIssuer(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	caFile, caKeyFile := writeTestCA(t, dir)
+	issuer, err := newBuiltinCertIssuer(caFile, caKeyFile)
+	require.NoError(err)
+
+	issued, err := issuer.Issue(&certRequest{
+		CommonName: "server.global.nomad",
+		DNSNames:   []string{"server.global.nomad", "localhost"},
+		IPs:        []net.IP{net.ParseIP("127.0.0.1")},
+		TTL:        time.Hour,
+	})
+	require.NoError(err)
+
+	cert, err := parseCertificatePEM(issued.Cert)
+	require.NoError(err)
+	require.Equal([]string{"server.global.nomad", "localhost"}, cert.DNSNames)
+	require.True(cert.IPAddresses[0].Equal(net.ParseIP("127.0.0.1")))
+	require.WithinDuration(time.Now().Add(time.Hour), cert.NotAfter, time.Minute)
+
+	// The certificate chains to the CA and its key matches
+	roots := x509.NewCertPool()
+	require.True(roots.AppendCertsFromPEM(issued.CA))
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:   "server.global.nomad",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(err)
+	_, err = parsePrivateKeyPEM(issued.Key)
+	require.NoError(err)
+
+	// Certificates can't outlive the CA
+	issued, err = issuer.Issue(&certRequest{CommonName: "client.global.nomad", TTL: 48 * time.Hour})
+	require.NoError(err)
+	cert, err = parseCertificatePEM(issued.Cert)
+	require.NoError(err)
+	require.Equal(issuer.caCert.NotAfter, cert.NotAfter)
+}
+
+func TestAutoTLS_VaultIssuer(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	caFile, caKeyFile := writeTestCA(t, dir)
+	builtin, err := newBuiltinCertIssuer(caFile, caKeyFile)
+	require.NoError(err)
+
+	// Fake the PKI secrets engine with the builtin issuer
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/pki/issue/nomad" || req.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var body map[string]string
+		require.NoError(json.NewDecoder(req.Body).Decode(&body))
+		require.Equal("client.global.nomad", body["common_name"])
+		require.Equal("client.global.nomad,localhost", body["alt_names"])
+		require.Equal("127.0.0.1", body["ip_sans"])
+		require.Equal("1h0m0s", body["ttl"])
+
+		issued, err := builtin.Issue(&certRequest{CommonName: body["common_name"], TTL: time.Hour})
+		require.NoError(err)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{
+				"certificate": string(issued.Cert),
+				"private_key": string(issued.Key),
+				"issuing_ca":  string(issued.CA),
+			},
+		})
+	}))
+	defer srv.Close()
+
+	vaultConf := config.DefaultVaultConfig()
+	vaultConf.Addr = srv.URL
+	vaultConf.Token = "root"
+	auto := config.DefaultAutoTLSConfig()
+	auto.VaultRole = "nomad"
+
+	issuer, err := newVaultCertIssuer(vaultConf, auto)
+	require.NoError(err)
+	issued, err := issuer.Issue(&certRequest{
+		CommonName: "client.global.nomad",
+		DNSNames:   []string{"client.global.nomad", "localhost"},
+		IPs:        []net.IP{net.ParseIP("127.0.0.1")},
+		TTL:        time.Hour,
+	})
+	require.NoError(err)
+
+	cert, err := parseCertificatePEM(issued.Cert)
+	require.NoError(err)
+	require.Equal("client.global.nomad", cert.Subject.CommonName)
+	require.NotEmpty(issued.Key)
+	require.NotEmpty(issued.CA)
+
+	// Roles that don't exist fail
+	auto.VaultRole = "other"
+	issuer, err = newVaultCertIssuer(vaultConf, auto)
+	require.NoError(err)
+	_, err = issuer.Issue(&certRequest{CommonName: "client.global.nomad", TTL: time.Hour})
+	require.Error(err)
+}
+
+func TestAutoTLS_Request(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := DefaultConfig()
+	conf.Region = "east"
+	conf.Server.Enabled = true
+	conf.Client.Enabled = true
+	a := &Agent{config: conf}
+
+	auto := config.DefaultAutoTLSConfig()
+	auto.AltNames = []string{"nomad.example.com", "10.0.0.1"}
+	req := a.autoTLSRequest(auto)
+
+	require.Equal("server.east.nomad", req.CommonName)
+	require.Equal([]string{"server.east.nomad", "client.east.nomad", "localhost", "nomad.example.com"}, req.DNSNames)
+	require.Len(req.IPs, 2)
+	require.True(req.IPs[1].Equal(net.ParseIP("10.0.0.1")))
+	require.Equal(auto.TTL, req.TTL)
+}
+
+func TestAutoTLS_Rotate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	caFile, caKeyFile := writeTestCA(t, dir)
+	tlsDir := filepath.Join(dir, "tls")
+	conf := DefaultConfig()
+	conf.Server.Enabled = true
+	conf.TLSConfig = &config.TLSConfig{
+		EnableRPC: true,
+		CAFile:    caFile,
+		Auto: &config.AutoTLSConfig{
+			Enabled:   helper.BoolToPtr(true),
+			Provider:  config.AutoTLSProviderBuiltin,
+			Dir:       tlsDir,
+			TTL:       3 * time.Second,
+			CAKeyFile: caKeyFile,
+		},
+	}
+
+	a := &Agent{
+		config:     conf,
+		logger:     testlog.HCLogger(t),
+		shutdownCh: make(chan struct{}),
+	}
+	defer close(a.shutdownCh)
+	require.NoError(a.setupAutoTLS())
+
+	// The TLS configuration points at the issued certificate and the
+	// operator's CA is left untouched
+	require.Equal(filepath.Join(tlsDir, autoTLSCertFile), conf.TLSConfig.CertFile)
+	require.Equal(filepath.Join(tlsDir, autoTLSKeyFile), conf.TLSConfig.KeyFile)
+	require.Equal(caFile, conf.TLSConfig.CAFile)
+
+	keyloader := conf.TLSConfig.GetKeyLoader()
+	_, err = keyloader.LoadKeyPair(conf.TLSConfig.CertFile, conf.TLSConfig.KeyFile)
+	require.NoError(err)
+	first, err := keyloader.GetOutgoingCertificate(nil)
+	require.NoError(err)
+
+	// The certificate is renewed and reloaded after two thirds of its
+	// lifetime
+	testutil.WaitForResult(func() (bool, error) {
+		cert, err := keyloader.GetOutgoingCertificate(nil)
+		if err != nil {
+			return false, err
+		}
+		if string(cert.Certificate[0]) == string(first.Certificate[0]) {
+			return false, fmt.Errorf("certificate wasn't renewed")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestAutoTLS_RenewAfter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	now := time.Now()
+	cert := &x509.Certificate{
+		NotBefore: now.Add(-time.Minute),
+		NotAfter:  now.Add(3 * time.Hour),
+	}
+	require.Equal(2*time.Hour, renewAfter(cert, now))
+	require.Equal(80*time.Minute, renewAfter(cert, now.Add(time.Hour)))
+	require.Equal(time.Duration(0), renewAfter(cert, now.Add(4*time.Hour)))
+}
@@ -660,6 +660,7 @@ func (c *Command) handleRetryJoin(config *Config) error {
 			logger:        c.agent.logger.Named("joiner"),
 			serverJoin:    c.agent.server.Join,
 			serverEnabled: true,
+			status:        c.agent.serverJoinStatus,
 		}
 
 		if err := joiner.Validate(config); err != nil {
@@ -693,6 +694,7 @@ func (c *Command) handleRetryJoin(config *Config) error {
 			logger:        c.agent.logger.Named("joiner"),
 			serverJoin:    c.agent.server.Join,
 			serverEnabled: true,
+			status:        c.agent.serverJoinStatus,
 		}
 
 		if err := joiner.Validate(config); err != nil {
@@ -711,6 +713,7 @@ func (c *Command) handleRetryJoin(config *Config) error {
 			logger:        c.agent.logger.Named("joiner"),
 			clientJoin:    c.agent.client.SetServers,
 			clientEnabled: true,
+			status:        c.agent.clientJoinStatus,
 		}
 
 		if err := joiner.Validate(config); err != nil {
@@ -738,6 +741,9 @@ WAIT:
 		sig = os.Interrupt
 	case <-c.retryJoinErrCh:
 		return 1
+	case <-c.agent.WatchdogExitCh():
+		c.Ui.Error("Watchdog found the agent unhealthy for too long, exiting")
+		return 1
 	}
 
 	// Skip any SIGPIPE signal and don't try to log it (See issues #1798, #3554)
@@ -946,8 +952,8 @@ func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 		fanout = append(fanout, sink)
 	}
 
-	// Configure the prometheus sink
-	if telConfig.PrometheusMetrics {
+	// Configure the prometheus sink, which remote write pushes the metrics of
+	if telConfig.PrometheusMetrics || telConfig.PrometheusRemoteWrite != nil {
 		promSink, err := prometheus.NewPrometheusSink()
 		if err != nil {
 			return inm, err
@@ -109,6 +109,14 @@ type Config struct {
 	// parameters necessary to derive tokens.
 	Vault *config.VaultConfig `mapstructure:"vault"`
 
+	// Consuls are the named Consul clusters that jobs may register their
+	// services in instead of the default cluster configured by Consul.
+	Consuls []*config.ConsulConfig `mapstructure:"-"`
+
+	// Vaults are the named Vault clusters that jobs may derive their tokens
+	// from instead of the default cluster configured by Vault.
+	Vaults []*config.VaultConfig `mapstructure:"-"`
+
 	// NomadConfig is used to override the default config.
 	// This is largely used for testing purposes.
 	NomadConfig *nomad.Config `mapstructure:"-" json:"-"`
@@ -140,6 +148,18 @@ type Config struct {
 	// Autopilot contains the configuration for Autopilot behavior.
 	Autopilot *config.AutopilotConfig `mapstructure:"autopilot"`
 
+	// SnapshotAgent contains the configuration for the server's scheduled
+	// snapshot agent.
+	SnapshotAgent *config.SnapshotAgentConfig `mapstructure:"snapshot_agent"`
+
+	// ExternalDNS contains the configuration for publishing Nomad services
+	// to an external DNS provider.
+	ExternalDNS *config.ExternalDNSConfig `mapstructure:"external_dns"`
+
+	// Watchdog configures the agent to notify systemd of its health and to
+	// exit when it stays unhealthy.
+	Watchdog *WatchdogConfig `mapstructure:"watchdog"`
+
 	// Plugins is the set of configured plugins
 	Plugins []*config.PluginConfig `hcl:"plugin,expand"`
 }
@@ -161,6 +181,10 @@ type ClientConfig struct {
 	// NodeClass is used to group the node by class
 	NodeClass string `mapstructure:"node_class"`
 
+	// NodePool is the node pool the node belongs to. Only jobs targeting the
+	// pool are placed on the node.
+	NodePool string `mapstructure:"node_pool"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -174,6 +198,14 @@ type ClientConfig struct {
 	// task's chroot.
 	ChrootEnv map[string]string `mapstructure:"chroot_env"`
 
+	// NamespaceAllocDirs maps namespaces to the directories storing the data
+	// of their allocations instead of the alloc_dir.
+	NamespaceAllocDirs map[string]string `mapstructure:"namespace_alloc_dirs"`
+
+	// JobTypeAllocDirs maps job types to the directories storing the data of
+	// their allocations instead of the alloc_dir.
+	JobTypeAllocDirs map[string]string `mapstructure:"job_type_alloc_dirs"`
+
 	// Interface to use for network fingerprinting
 	NetworkInterface string `mapstructure:"network_interface"`
 
@@ -198,6 +230,43 @@ type ClientConfig struct {
 	// communicating with plugin subsystems
 	ClientMinPort int `mapstructure:"client_min_port"`
 
+	// DynamicUsers runs the tasks of each allocation as a user and group
+	// unique to the allocation
+	DynamicUsers bool `mapstructure:"dynamic_users"`
+
+	// DynamicUserMinID is the lower range of the user and group IDs assigned
+	// to allocations
+	DynamicUserMinID int `mapstructure:"dynamic_user_min_id"`
+
+	// DynamicUserMaxID is the upper range of the user and group IDs assigned
+	// to allocations
+	DynamicUserMaxID int `mapstructure:"dynamic_user_max_id"`
+
+	// DisablePrefetch disables fetching the images and artifacts of the jobs
+	// with a prefetch hint ahead of their placements
+	DisablePrefetch bool `mapstructure:"disable_prefetch"`
+
+	// MigrateBandwidthLimit is the bandwidth per second, such as "50MB",
+	// used to migrate the ephemeral disks of allocations from other clients
+	MigrateBandwidthLimit string `mapstructure:"migrate_bandwidth_limit"`
+
+	// RPCCompression enables the compression of the RPC connections to the
+	// servers
+	RPCCompression bool `mapstructure:"rpc_compression"`
+
+	// EnableFaultInjection enables the fault injection endpoints used for
+	// resilience testing
+	EnableFaultInjection bool `mapstructure:"enable_fault_injection"`
+
+	// RPCBandwidthLimit is the bandwidth per second, such as "1MB", the
+	// client sends to the servers over its RPC connections
+	RPCBandwidthLimit string `mapstructure:"rpc_bandwidth_limit"`
+
+	// RealtimeRuntime is the CPU time per second that each task using the
+	// real-time scheduling policies may run real-time threads. Zero disables
+	// real-time scheduling on the client.
+	RealtimeRuntime time.Duration `mapstructure:"realtime_runtime"`
+
 	// Reserved is used to reserve resources from being used by Nomad. This can
 	// be used to target a certain utilization or to prevent Nomad from using a
 	// particular set of ports.
@@ -223,12 +292,103 @@ type ClientConfig struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int `mapstructure:"gc_max_allocs"`
 
+	// MaxTaskEvents is the maximum number of events retained per task.
+	MaxTaskEvents int `mapstructure:"max_task_events"`
+
 	// NoHostUUID disables using the host's UUID and will force generation of a
 	// random UUID.
 	NoHostUUID *bool `mapstructure:"no_host_uuid"`
 
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
+
+	// MaintenanceWindows are the recurring windows during which the node is
+	// automatically marked as ineligible for scheduling.
+	MaintenanceWindows []*MaintenanceWindowConfig `mapstructure:"maintenance_window"`
+
+	// ReadinessGates are the checks that must pass before the node is
+	// marked as ready and work is placed on it.
+	ReadinessGates []*ReadinessGateConfig `mapstructure:"readiness_gate"`
+
+	// DriverRemediations configure how drivers are remediated when their
+	// fingerprint becomes unhealthy.
+	DriverRemediations []*DriverRemediationConfig `mapstructure:"driver_remediation"`
+
+	// ServiceProviders are the external service providers the services of
+	// tasks can be registered with.
+	ServiceProviders []*ServiceProviderConfig `mapstructure:"service_provider"`
+
+	// DataEncryption configures the encryption of the state the client
+	// persists in its state directory.
+	DataEncryption *DataEncryptionConfig `mapstructure:"data_encryption"`
+}
+
+// MaintenanceWindowConfig is a recurring window during which the node is
+// marked as ineligible for scheduling.
+type MaintenanceWindowConfig struct {
+	// Cron is the cron expression of the start of the window.
+	Cron string `mapstructure:"cron"`
+
+	// Duration is how long the window lasts.
+	Duration time.Duration `mapstructure:"duration"`
+
+	// TimeZone is the time zone the cron expression is evaluated in.
+	TimeZone string `mapstructure:"time_zone"`
+}
+
+// ReadinessGateConfig is a check that must pass before the node is marked as
+// ready. It either runs a command, passing if it exits successfully, or
+// queries a URL, passing on a 2xx response.
+type ReadinessGateConfig struct {
+	// Name is the name of the gate.
+	Name string `mapstructure:"-"`
+
+	// Command and Args are the command run by the check.
+	Command string   `mapstructure:"command"`
+	Args    []string `mapstructure:"args"`
+
+	// URL is the URL queried by the check.
+	URL string `mapstructure:"url"`
+
+	// Interval is how often the check is retried until it passes.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout is how long a single check may take.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// DriverRemediationConfig configures the remediation of a driver whose
+// fingerprint became unhealthy. Until the driver is healthy again, a recovery
+// command is optionally run and the driver is fingerprinted again with a
+// backoff.
+type DriverRemediationConfig struct {
+	// Driver is the name of the remediated driver.
+	Driver string `mapstructure:"-"`
+
+	// Command and Args are the recovery command.
+	Command string   `mapstructure:"command"`
+	Args    []string `mapstructure:"args"`
+
+	// Timeout is how long a single run of the command may take.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Backoff is the delay before fingerprinting the driver again, doubling
+	// after each attempt up to BackoffLimit.
+	Backoff      time.Duration `mapstructure:"backoff"`
+	BackoffLimit time.Duration `mapstructure:"backoff_limit"`
+}
+
+// ServiceProviderConfig is an external service provider, such as a load
+// balancer, that the services of tasks are pushed to with HTTP requests.
+type ServiceProviderConfig struct {
+	// Name is the name services use as their provider.
+	Name string `mapstructure:"-"`
+
+	// URL is the base URL targets are registered below.
+	URL string `mapstructure:"url"`
+
+	// Timeout is how long a single request may take.
+	Timeout time.Duration `mapstructure:"timeout"`
 }
 
 // ACLConfig is configuration specific to the ACL system
@@ -252,6 +412,15 @@ type ACLConfig struct {
 	// from the authoritative region. This must be a valid management token
 	// within the authoritative region.
 	ReplicationToken string `mapstructure:"replication_token"`
+
+	// TokenMaxExpirationTTL is the longest expiration TTL tokens can be
+	// created with. Zero allows any TTL.
+	TokenMaxExpirationTTL time.Duration `mapstructure:"token_max_expiration_ttl"`
+
+	// BootstrapTokenFile is the path of a file holding the Secret ID of a
+	// pre-generated management token. Servers of the authoritative region
+	// bootstrap the ACL system with it if it isn't bootstrapped yet.
+	BootstrapTokenFile string `mapstructure:"bootstrap_token_file"`
 }
 
 // ServerConfig is configuration specific to the server mode
@@ -287,6 +456,18 @@ type ServerConfig struct {
 	// that the workers dequeue for processing.
 	EnabledSchedulers []string `mapstructure:"enabled_schedulers"`
 
+	// SchedulerWorkers configures the workers pinned to each scheduler type
+	// and the autoscaling of the shared workers.
+	SchedulerWorkers *config.SchedulerWorkersConfig `mapstructure:"scheduler_workers"`
+
+	// ClientRelay configures the relaying of filesystem, logs and exec
+	// requests to the clients on behalf of API consumers.
+	ClientRelay *config.ClientRelayConfig `mapstructure:"client_relay"`
+
+	// WriteRateLimit limits the rate of the write RPCs, such as job
+	// registrations, of each ACL token and of each namespace.
+	WriteRateLimit *config.WriteRateLimitConfig `mapstructure:"write_rate_limit"`
+
 	// NodeGCThreshold controls how "old" a node must be to be collected by GC.
 	// Age is not the only requirement for a node to be GCed but the threshold
 	// can be used to filter by age.
@@ -297,6 +478,12 @@ type ServerConfig struct {
 	// can be used to filter by age.
 	JobGCThreshold string `mapstructure:"job_gc_threshold"`
 
+	// BatchJobGCThreshold, ServiceJobGCThreshold and SystemJobGCThreshold
+	// override JobGCThreshold for jobs of the matching type.
+	BatchJobGCThreshold   string `mapstructure:"batch_job_gc_threshold"`
+	ServiceJobGCThreshold string `mapstructure:"service_job_gc_threshold"`
+	SystemJobGCThreshold  string `mapstructure:"system_job_gc_threshold"`
+
 	// EvalGCThreshold controls how "old" an eval must be to be collected by GC.
 	// Age is not the only requirement for a eval to be GCed but the threshold
 	// can be used to filter by age.
@@ -364,6 +551,83 @@ type ServerConfig struct {
 
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
+
+	// JobAdmissionWebhooks are the external webhooks jobs are sent to when
+	// they are registered, in the order they are called.
+	JobAdmissionWebhooks []*config.JobAdmissionWebhookConfig `mapstructure:"job_admission_webhook"`
+
+	// JobAdmissionRules restrict the datacenters, node classes and drivers
+	// the jobs of a namespace may use.
+	JobAdmissionRules []*config.JobAdmissionRuleConfig `mapstructure:"job_admission_rule"`
+
+	// JobSigning configures the verification of the signatures of the jobs
+	// registered in each namespace.
+	JobSigning []*config.JobSigningConfig `mapstructure:"job_signing"`
+
+	// NamespaceDefaults set the Vault and Consul clusters used by the jobs of
+	// each namespace that don't select a cluster themselves.
+	NamespaceDefaults []*config.NamespaceDefaultsConfig `mapstructure:"namespace_defaults"`
+
+	// DispatchPayloadSizeLimit is the maximum size of the payload of dispatch
+	// requests, such as "64KiB".
+	DispatchPayloadSizeLimit string `mapstructure:"dispatch_payload_size_limit"`
+
+	// DeploymentWebhookSigningKey is the secret the deployment progress
+	// webhook payloads are signed with.
+	DeploymentWebhookSigningKey string `mapstructure:"deployment_webhook_signing_key"`
+
+	// RedactEnvVars is the list of glob patterns of the names of the task
+	// environment variables whose values are redacted from the jobs,
+	// allocations and events returned to non-management tokens.
+	RedactEnvVars []string `mapstructure:"redact_env_vars"`
+}
+
+// DataEncryptionConfig configures the encryption of the state the client
+// persists in its state directory with a node key, optionally wrapped by a key
+// of the Vault transit secrets engine.
+type DataEncryptionConfig struct {
+	// Enabled enables the encryption of the client state.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// VaultTransitKey is the name of the transit key wrapping the node key
+	// and VaultTransitMount the mount path of the transit secrets engine.
+	VaultTransitKey   string `mapstructure:"vault_transit_key"`
+	VaultTransitMount string `mapstructure:"vault_transit_mount"`
+
+	// VaultAddress and VaultToken are the address of Vault and the token
+	// used with the transit key.
+	VaultAddress string `mapstructure:"vault_address"`
+	VaultToken   string `mapstructure:"vault_token"`
+}
+
+func (d *DataEncryptionConfig) Merge(b *DataEncryptionConfig) *DataEncryptionConfig {
+	if d == nil {
+		return b
+	}
+
+	result := *d
+
+	if b == nil {
+		return &result
+	}
+
+	if b.Enabled != nil {
+		result.Enabled = b.Enabled
+	}
+	if b.VaultTransitKey != "" {
+		result.VaultTransitKey = b.VaultTransitKey
+	}
+	if b.VaultTransitMount != "" {
+		result.VaultTransitMount = b.VaultTransitMount
+	}
+	if b.VaultAddress != "" {
+		result.VaultAddress = b.VaultAddress
+	}
+	if b.VaultToken != "" {
+		result.VaultToken = b.VaultToken
+	}
+
+	return &result
 }
 
 // ServerJoin is used in both clients and servers to bootstrap connections to
@@ -387,6 +651,11 @@ type ServerJoin struct {
 	// attempts on agent start. The minimum allowed value is 1 second and
 	// the default is 30s.
 	RetryInterval time.Duration `mapstructure:"retry_interval"`
+
+	// RetryMaxInterval enables backing off between join attempts. When set,
+	// the interval between attempts doubles after each failed attempt, up to
+	// RetryMaxInterval.
+	RetryMaxInterval time.Duration `mapstructure:"retry_max_interval"`
 }
 
 func (s *ServerJoin) Merge(b *ServerJoin) *ServerJoin {
@@ -412,6 +681,9 @@ func (s *ServerJoin) Merge(b *ServerJoin) *ServerJoin {
 	if b.RetryInterval != 0 {
 		result.RetryInterval = b.RetryInterval
 	}
+	if b.RetryMaxInterval != 0 {
+		result.RetryMaxInterval = b.RetryMaxInterval
+	}
 
 	return &result
 }
@@ -456,6 +728,10 @@ type Telemetry struct {
 	// a small memory overhead.
 	DisableDispatchedJobSummaryMetrics bool `mapstructure:"disable_dispatched_job_summary_metrics"`
 
+	// PrometheusRemoteWrite pushes metrics to a Prometheus remote write
+	// endpoint, for agents that can't be scraped
+	PrometheusRemoteWrite *PrometheusRemoteWrite `mapstructure:"prometheus_remote_write"`
+
 	// Circonus: see https://github.com/circonus-labs/circonus-gometrics
 	// for more details on the various configuration options.
 	// Valid configuration combinations:
@@ -528,6 +804,94 @@ type Telemetry struct {
 	CirconusBrokerSelectTag string `mapstructure:"circonus_broker_select_tag"`
 }
 
+// PrometheusRemoteWrite is the configuration of pushing metrics to a
+// Prometheus remote write endpoint
+type PrometheusRemoteWrite struct {
+	// URL is the remote write endpoint metrics are pushed to
+	URL string `mapstructure:"url"`
+
+	// Interval is how often metrics are pushed
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout limits how long pushing metrics may take
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// BearerToken or the content of BearerTokenFile is sent as the bearer
+	// token of the pushes
+	BearerToken     string `mapstructure:"bearer_token"`
+	BearerTokenFile string `mapstructure:"bearer_token_file"`
+
+	// CAFile is the CA certificate the endpoint is verified with, while
+	// CertFile and KeyFile are the client certificate used for mTLS
+	CAFile        string `mapstructure:"ca_file"`
+	CertFile      string `mapstructure:"cert_file"`
+	KeyFile       string `mapstructure:"key_file"`
+	TLSServerName string `mapstructure:"tls_server_name"`
+}
+
+// Validate returns an error if the remote write configuration is invalid.
+func (p *PrometheusRemoteWrite) Validate() error {
+	switch {
+	case p.URL == "":
+		return fmt.Errorf("url must be set")
+	case p.Interval < 0 || p.Timeout < 0:
+		return fmt.Errorf("interval and timeout must not be negative")
+	case p.BearerToken != "" && p.BearerTokenFile != "":
+		return fmt.Errorf("only one of bearer_token and bearer_token_file may be set")
+	case (p.CertFile == "") != (p.KeyFile == ""):
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	return nil
+}
+
+// Copy returns a copy of the remote write configuration.
+func (p *PrometheusRemoteWrite) Copy() *PrometheusRemoteWrite {
+	if p == nil {
+		return nil
+	}
+	np := *p
+	return &np
+}
+
+// WatchdogConfig is the configuration of the agent's watchdog, which
+// periodically checks the health of the agent's subsystems.
+type WatchdogConfig struct {
+	// Interval is how often the health of the agent is checked
+	Interval time.Duration `mapstructure:"interval"`
+
+	// SystemdNotify notifies systemd that the agent is ready and sends it
+	// watchdog keep-alives while the agent is healthy
+	SystemdNotify bool `mapstructure:"systemd_notify"`
+
+	// ExitOnUnhealthy is how long the agent may stay unhealthy before it
+	// exits, to be restarted by its service manager. Zero disables it.
+	ExitOnUnhealthy time.Duration `mapstructure:"exit_on_unhealthy"`
+
+	// ExitOnLostQuorum is how long a server may go without a leader before
+	// it exits. Zero disables it.
+	ExitOnLostQuorum time.Duration `mapstructure:"exit_on_lost_quorum"`
+}
+
+// Validate returns an error if the watchdog configuration is invalid.
+func (w *WatchdogConfig) Validate() error {
+	switch {
+	case w.Interval < 0:
+		return fmt.Errorf("interval must not be negative")
+	case w.ExitOnUnhealthy < 0 || w.ExitOnLostQuorum < 0:
+		return fmt.Errorf("exit_on_unhealthy and exit_on_lost_quorum must not be negative")
+	}
+	return nil
+}
+
+// Copy returns a copy of the watchdog configuration.
+func (w *WatchdogConfig) Copy() *WatchdogConfig {
+	if w == nil {
+		return nil
+	}
+	nw := *w
+	return &nw
+}
+
 // PrefixFilters parses the PrefixFilter field and returns a list of allowed and blocked filters
 func (t *Telemetry) PrefixFilters() (allowed, blocked []string, err error) {
 	for _, rule := range t.PrefixFilter {
@@ -638,12 +1002,15 @@ func DefaultConfig() *Config {
 			MaxKillTimeout:        "30s",
 			ClientMinPort:         14000,
 			ClientMaxPort:         14512,
+			DynamicUserMinID:      80000,
+			DynamicUserMaxID:      89999,
 			Reserved:              &Resources{},
 			GCInterval:            1 * time.Minute,
 			GCParallelDestroys:    2,
 			GCDiskUsageThreshold:  80,
 			GCInodeUsageThreshold: 70,
 			GCMaxAllocs:           50,
+			MaxTaskEvents:         25,
 			NoHostUUID:            helper.BoolToPtr(true),
 			ServerJoin: &ServerJoin{
 				RetryJoin:        []string{},
@@ -674,6 +1041,8 @@ func DefaultConfig() *Config {
 		Sentinel:           &config.SentinelConfig{},
 		Version:            version.GetVersion(),
 		Autopilot:          config.DefaultAutopilotConfig(),
+		SnapshotAgent:      config.DefaultSnapshotAgentConfig(),
+		ExternalDNS:        config.DefaultExternalDNSConfig(),
 		DisableUpdateCheck: helper.BoolToPtr(false),
 	}
 }
@@ -831,6 +1200,10 @@ func (c *Config) Merge(b *Config) *Config {
 		result.Vault = result.Vault.Merge(b.Vault)
 	}
 
+	// Apply the named Consul and Vault clusters
+	result.Consuls = mergeConsulClusters(result.Consuls, b.Consuls)
+	result.Vaults = mergeVaultClusters(result.Vaults, b.Vaults)
+
 	// Apply the sentinel config
 	if result.Sentinel == nil && b.Sentinel != nil {
 		server := *b.Sentinel
@@ -846,6 +1219,24 @@ func (c *Config) Merge(b *Config) *Config {
 		result.Autopilot = result.Autopilot.Merge(b.Autopilot)
 	}
 
+	if result.SnapshotAgent == nil && b.SnapshotAgent != nil {
+		result.SnapshotAgent = b.SnapshotAgent.Copy()
+	} else if b.SnapshotAgent != nil {
+		result.SnapshotAgent = result.SnapshotAgent.Merge(b.SnapshotAgent)
+	}
+
+	// Apply the external DNS config
+	if result.ExternalDNS == nil && b.ExternalDNS != nil {
+		result.ExternalDNS = b.ExternalDNS.Copy()
+	} else if b.ExternalDNS != nil {
+		result.ExternalDNS = result.ExternalDNS.Merge(b.ExternalDNS)
+	}
+
+	// Apply the watchdog config
+	if b.Watchdog != nil {
+		result.Watchdog = b.Watchdog.Copy()
+	}
+
 	if len(result.Plugins) == 0 && len(b.Plugins) != 0 {
 		copy := make([]*config.PluginConfig, len(b.Plugins))
 		for i, v := range b.Plugins {
@@ -870,6 +1261,76 @@ func (c *Config) Merge(b *Config) *Config {
 	return &result
 }
 
+// hasConsulCluster returns whether the Consul cluster of the name is
+// configured.
+func (c *Config) hasConsulCluster(name string) bool {
+	if name == structs.ConsulDefaultCluster {
+		return true
+	}
+	for _, consul := range c.Consuls {
+		if consul.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasVaultCluster returns whether the Vault cluster of the name is
+// configured.
+func (c *Config) hasVaultCluster(name string) bool {
+	if name == structs.VaultDefaultCluster {
+		return true
+	}
+	for _, vault := range c.Vaults {
+		if vault.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeConsulClusters merges the named Consul clusters of b into a, merging
+// the clusters of the same name. Clusters only in b are merged over the
+// default Consul config.
+func mergeConsulClusters(a, b []*config.ConsulConfig) []*config.ConsulConfig {
+	var result []*config.ConsulConfig
+	for _, c := range a {
+		result = append(result, c.Copy())
+	}
+OUTER:
+	for _, c := range b {
+		for i, r := range result {
+			if r.Name == c.Name {
+				result[i] = r.Merge(c)
+				continue OUTER
+			}
+		}
+		result = append(result, config.DefaultConsulConfig().Merge(c))
+	}
+	return result
+}
+
+// mergeVaultClusters merges the named Vault clusters of b into a, merging the
+// clusters of the same name. Clusters only in b are merged over the default
+// Vault config.
+func mergeVaultClusters(a, b []*config.VaultConfig) []*config.VaultConfig {
+	var result []*config.VaultConfig
+	for _, v := range a {
+		result = append(result, v.Copy())
+	}
+OUTER:
+	for _, v := range b {
+		for i, r := range result {
+			if r.Name == v.Name {
+				result[i] = r.Merge(v)
+				continue OUTER
+			}
+		}
+		result = append(result, config.DefaultVaultConfig().Merge(v))
+	}
+	return result
+}
+
 // normalizeAddrs normalizes Addresses and AdvertiseAddrs to always be
 // initialized and have sane defaults.
 func (c *Config) normalizeAddrs() error {
@@ -1051,6 +1512,12 @@ func (a *ACLConfig) Merge(b *ACLConfig) *ACLConfig {
 	if b.ReplicationToken != "" {
 		result.ReplicationToken = b.ReplicationToken
 	}
+	if b.TokenMaxExpirationTTL != 0 {
+		result.TokenMaxExpirationTTL = b.TokenMaxExpirationTTL
+	}
+	if b.BootstrapTokenFile != "" {
+		result.BootstrapTokenFile = b.BootstrapTokenFile
+	}
 	return &result
 }
 
@@ -1085,6 +1552,15 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.JobGCThreshold != "" {
 		result.JobGCThreshold = b.JobGCThreshold
 	}
+	if b.BatchJobGCThreshold != "" {
+		result.BatchJobGCThreshold = b.BatchJobGCThreshold
+	}
+	if b.ServiceJobGCThreshold != "" {
+		result.ServiceJobGCThreshold = b.ServiceJobGCThreshold
+	}
+	if b.SystemJobGCThreshold != "" {
+		result.SystemJobGCThreshold = b.SystemJobGCThreshold
+	}
 	if b.EvalGCThreshold != "" {
 		result.EvalGCThreshold = b.EvalGCThreshold
 	}
@@ -1118,16 +1594,48 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.UpgradeVersion != "" {
 		result.UpgradeVersion = b.UpgradeVersion
 	}
+	if b.DispatchPayloadSizeLimit != "" {
+		result.DispatchPayloadSizeLimit = b.DispatchPayloadSizeLimit
+	}
+	if b.DeploymentWebhookSigningKey != "" {
+		result.DeploymentWebhookSigningKey = b.DeploymentWebhookSigningKey
+	}
 	if b.EncryptKey != "" {
 		result.EncryptKey = b.EncryptKey
 	}
 	if b.ServerJoin != nil {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
+	if result.SchedulerWorkers == nil && b.SchedulerWorkers != nil {
+		result.SchedulerWorkers = b.SchedulerWorkers.Copy()
+	} else if b.SchedulerWorkers != nil {
+		result.SchedulerWorkers = result.SchedulerWorkers.Merge(b.SchedulerWorkers)
+	}
+	if result.ClientRelay == nil && b.ClientRelay != nil {
+		result.ClientRelay = b.ClientRelay.Copy()
+	} else if b.ClientRelay != nil {
+		result.ClientRelay = result.ClientRelay.Merge(b.ClientRelay)
+	}
+	if result.WriteRateLimit == nil && b.WriteRateLimit != nil {
+		result.WriteRateLimit = b.WriteRateLimit.Copy()
+	} else if b.WriteRateLimit != nil {
+		result.WriteRateLimit = result.WriteRateLimit.Merge(b.WriteRateLimit)
+	}
 
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
+	// Add the admission webhooks
+	result.JobAdmissionWebhooks = append(result.JobAdmissionWebhooks, b.JobAdmissionWebhooks...)
+
+	// Add the admission rules
+	result.JobAdmissionRules = append(result.JobAdmissionRules, b.JobAdmissionRules...)
+	result.JobSigning = append(result.JobSigning, b.JobSigning...)
+	result.NamespaceDefaults = append(result.NamespaceDefaults, b.NamespaceDefaults...)
+
+	// Add the redacted environment variables
+	result.RedactEnvVars = append(result.RedactEnvVars, b.RedactEnvVars...)
+
 	// Copy the start join addresses
 	result.StartJoin = make([]string, 0, len(a.StartJoin)+len(b.StartJoin))
 	result.StartJoin = append(result.StartJoin, a.StartJoin...)
@@ -1157,6 +1665,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.NodePool != "" {
+		result.NodePool = b.NodePool
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -1178,6 +1689,33 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.ClientMinPort != 0 {
 		result.ClientMinPort = b.ClientMinPort
 	}
+	if b.DynamicUsers {
+		result.DynamicUsers = true
+	}
+	if b.DynamicUserMinID != 0 {
+		result.DynamicUserMinID = b.DynamicUserMinID
+	}
+	if b.DynamicUserMaxID != 0 {
+		result.DynamicUserMaxID = b.DynamicUserMaxID
+	}
+	if b.DisablePrefetch {
+		result.DisablePrefetch = true
+	}
+	if b.MigrateBandwidthLimit != "" {
+		result.MigrateBandwidthLimit = b.MigrateBandwidthLimit
+	}
+	if b.RPCCompression {
+		result.RPCCompression = true
+	}
+	if b.EnableFaultInjection {
+		result.EnableFaultInjection = true
+	}
+	if b.RPCBandwidthLimit != "" {
+		result.RPCBandwidthLimit = b.RPCBandwidthLimit
+	}
+	if b.RealtimeRuntime != 0 {
+		result.RealtimeRuntime = b.RealtimeRuntime
+	}
 	if result.Reserved == nil && b.Reserved != nil {
 		reserved := *b.Reserved
 		result.Reserved = &reserved
@@ -1199,6 +1737,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCMaxAllocs != 0 {
 		result.GCMaxAllocs = b.GCMaxAllocs
 	}
+	if b.MaxTaskEvents != 0 {
+		result.MaxTaskEvents = b.MaxTaskEvents
+	}
 	// NoHostUUID defaults to true, merge if false
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
@@ -1207,6 +1748,12 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
 
+	// Add the maintenance windows
+	result.MaintenanceWindows = append(result.MaintenanceWindows, b.MaintenanceWindows...)
+	result.ReadinessGates = append(result.ReadinessGates, b.ReadinessGates...)
+	result.DriverRemediations = append(result.DriverRemediations, b.DriverRemediations...)
+	result.ServiceProviders = append(result.ServiceProviders, b.ServiceProviders...)
+
 	// Add the options map values
 	if result.Options == nil {
 		result.Options = make(map[string]string)
@@ -1231,10 +1778,30 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.ChrootEnv[k] = v
 	}
 
+	// Add the namespace_alloc_dirs map values
+	if result.NamespaceAllocDirs == nil {
+		result.NamespaceAllocDirs = make(map[string]string)
+	}
+	for k, v := range b.NamespaceAllocDirs {
+		result.NamespaceAllocDirs[k] = v
+	}
+
+	// Add the job_type_alloc_dirs map values
+	if result.JobTypeAllocDirs == nil {
+		result.JobTypeAllocDirs = make(map[string]string)
+	}
+	for k, v := range b.JobTypeAllocDirs {
+		result.JobTypeAllocDirs[k] = v
+	}
+
 	if b.ServerJoin != nil {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
 
+	if b.DataEncryption != nil {
+		result.DataEncryption = result.DataEncryption.Merge(b.DataEncryption)
+	}
+
 	return &result
 }
 
@@ -1257,6 +1824,9 @@ func (a *Telemetry) Merge(b *Telemetry) *Telemetry {
 	if b.PrometheusMetrics {
 		result.PrometheusMetrics = b.PrometheusMetrics
 	}
+	if b.PrometheusRemoteWrite != nil {
+		result.PrometheusRemoteWrite = b.PrometheusRemoteWrite.Copy()
+	}
 	if b.DisableHostname {
 		result.DisableHostname = true
 	}
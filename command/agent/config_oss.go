@@ -2,7 +2,24 @@
 
 package agent
 
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
 // DefaultEntConfig is an empty config in open source
 func DefaultEntConfig() *Config {
 	return &Config{}
 }
+
+// validateConfigNamespace returns an error if the namespace a server config
+// block is labeled with doesn't exist. Only the default namespace exists
+// without Nomad Enterprise.
+func validateConfigNamespace(namespace string) error {
+	if namespace != structs.DefaultNamespace {
+		return fmt.Errorf("namespace %q does not exist; only the %q namespace is supported",
+			namespace, structs.DefaultNamespace)
+	}
+	return nil
+}
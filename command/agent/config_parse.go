@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/mitchellh/mapstructure"
 )
@@ -103,6 +104,9 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		"acl",
 		"sentinel",
 		"autopilot",
+		"snapshot_agent",
+		"external_dns",
+		"watchdog",
 		"plugin",
 	}
 	if err := helper.CheckHCLKeys(list, valid); err != nil {
@@ -128,6 +132,9 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 	delete(m, "acl")
 	delete(m, "sentinel")
 	delete(m, "autopilot")
+	delete(m, "snapshot_agent")
+	delete(m, "external_dns")
+	delete(m, "watchdog")
 	delete(m, "plugin")
 
 	// Decode the rest
@@ -186,14 +193,14 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 
 	// Parse the consul config
 	if o := list.Filter("consul"); len(o.Items) > 0 {
-		if err := parseConsulConfig(&result.Consul, o); err != nil {
+		if err := parseConsulConfigs(&result.Consul, &result.Consuls, o); err != nil {
 			return multierror.Prefix(err, "consul ->")
 		}
 	}
 
 	// Parse the vault config
 	if o := list.Filter("vault"); len(o.Items) > 0 {
-		if err := parseVaultConfig(&result.Vault, o); err != nil {
+		if err := parseVaultConfigs(&result.Vault, &result.Vaults, o); err != nil {
 			return multierror.Prefix(err, "vault ->")
 		}
 	}
@@ -219,6 +226,27 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse Snapshot Agent config
+	if o := list.Filter("snapshot_agent"); len(o.Items) > 0 {
+		if err := parseSnapshotAgent(&result.SnapshotAgent, o); err != nil {
+			return multierror.Prefix(err, "snapshot_agent->")
+		}
+	}
+
+	// Parse External DNS config
+	if o := list.Filter("external_dns"); len(o.Items) > 0 {
+		if err := parseExternalDNS(&result.ExternalDNS, o); err != nil {
+			return multierror.Prefix(err, "external_dns->")
+		}
+	}
+
+	// Parse Watchdog config
+	if o := list.Filter("watchdog"); len(o.Items) > 0 {
+		if err := parseWatchdog(&result.Watchdog, o); err != nil {
+			return multierror.Prefix(err, "watchdog->")
+		}
+	}
+
 	// Parse Plugin configs
 	if o := list.Filter("plugin"); len(o.Items) > 0 {
 		if err := parsePlugins(&result.Plugins, o); err != nil {
@@ -363,9 +391,12 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"alloc_dir",
 		"servers",
 		"node_class",
+		"node_pool",
 		"options",
 		"meta",
 		"chroot_env",
+		"namespace_alloc_dirs",
+		"job_type_alloc_dirs",
 		"network_interface",
 		"network_speed",
 		"memory_total_mb",
@@ -373,6 +404,15 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"max_kill_timeout",
 		"client_max_port",
 		"client_min_port",
+		"dynamic_users",
+		"dynamic_user_min_id",
+		"dynamic_user_max_id",
+		"disable_prefetch",
+		"migrate_bandwidth_limit",
+		"rpc_compression",
+		"enable_fault_injection",
+		"rpc_bandwidth_limit",
+		"realtime_runtime",
 		"reserved",
 		"stats",
 		"gc_interval",
@@ -380,8 +420,14 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"gc_inode_usage_threshold",
 		"gc_parallel_destroys",
 		"gc_max_allocs",
+		"max_task_events",
 		"no_host_uuid",
 		"server_join",
+		"maintenance_window",
+		"readiness_gate",
+		"driver_remediation",
+		"service_provider",
+		"data_encryption",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -395,9 +441,16 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 	delete(m, "options")
 	delete(m, "meta")
 	delete(m, "chroot_env")
+	delete(m, "namespace_alloc_dirs")
+	delete(m, "job_type_alloc_dirs")
 	delete(m, "reserved")
 	delete(m, "stats")
 	delete(m, "server_join")
+	delete(m, "maintenance_window")
+	delete(m, "readiness_gate")
+	delete(m, "driver_remediation")
+	delete(m, "service_provider")
+	delete(m, "data_encryption")
 
 	var config ClientConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -454,6 +507,34 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse out namespace_alloc_dirs fields. These are in HCL as a list so we
+	// need to iterate over them and merge them.
+	if allocDirsO := listVal.Filter("namespace_alloc_dirs"); len(allocDirsO.Items) > 0 {
+		for _, o := range allocDirsO.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &config.NamespaceAllocDirs); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Parse out job_type_alloc_dirs fields. These are in HCL as a list so we
+	// need to iterate over them and merge them.
+	if allocDirsO := listVal.Filter("job_type_alloc_dirs"); len(allocDirsO.Items) > 0 {
+		for _, o := range allocDirsO.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &config.JobTypeAllocDirs); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Parse reserved config
 	if o := listVal.Filter("reserved"); len(o.Items) > 0 {
 		if err := parseReserved(&config.Reserved, o); err != nil {
@@ -468,10 +549,222 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the maintenance windows
+	if o := listVal.Filter("maintenance_window"); len(o.Items) > 0 {
+		if err := parseMaintenanceWindows(&config.MaintenanceWindows, o); err != nil {
+			return multierror.Prefix(err, "maintenance_window ->")
+		}
+	}
+
+	// Parse the readiness gates
+	if o := listVal.Filter("readiness_gate"); len(o.Items) > 0 {
+		if err := parseReadinessGates(&config.ReadinessGates, o); err != nil {
+			return multierror.Prefix(err, "readiness_gate ->")
+		}
+	}
+
+	// Parse the driver remediations
+	if o := listVal.Filter("driver_remediation"); len(o.Items) > 0 {
+		if err := parseDriverRemediations(&config.DriverRemediations, o); err != nil {
+			return multierror.Prefix(err, "driver_remediation ->")
+		}
+	}
+
+	// Parse the external service providers
+	if o := listVal.Filter("service_provider"); len(o.Items) > 0 {
+		if err := parseServiceProviders(&config.ServiceProviders, o); err != nil {
+			return multierror.Prefix(err, "service_provider ->")
+		}
+	}
+
+	// Parse the data encryption config
+	if o := listVal.Filter("data_encryption"); len(o.Items) > 0 {
+		if err := parseDataEncryption(&config.DataEncryption, o); err != nil {
+			return multierror.Prefix(err, "data_encryption ->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseMaintenanceWindows(result *[]*MaintenanceWindowConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"cron",
+		"duration",
+		"time_zone",
+	}
+
+	var windows []*MaintenanceWindowConfig
+	for i, item := range list.Elem().Items {
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%d ->", i+1))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		var window MaintenanceWindowConfig
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &window,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%d ->", i+1))
+		}
+
+		windows = append(windows, &window)
+	}
+
+	*result = windows
+	return nil
+}
+
+func parseReadinessGates(result *[]*ReadinessGateConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"command",
+		"args",
+		"url",
+		"interval",
+		"timeout",
+	}
+
+	var gates []*ReadinessGateConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("readiness gate %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		gate := &ReadinessGateConfig{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           gate,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		gates = append(gates, gate)
+	}
+
+	*result = gates
+	return nil
+}
+
+func parseDriverRemediations(result *[]*DriverRemediationConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"command",
+		"args",
+		"timeout",
+		"backoff",
+		"backoff_limit",
+	}
+
+	var remediations []*DriverRemediationConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("driver remediation %d doesn't include a driver key", i+1)
+		}
+		driver := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", driver))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		remediation := &DriverRemediationConfig{Driver: driver}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           remediation,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", driver))
+		}
+
+		remediations = append(remediations, remediation)
+	}
+
+	*result = remediations
+	return nil
+}
+
+func parseServiceProviders(result *[]*ServiceProviderConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"url",
+		"timeout",
+	}
+
+	var providers []*ServiceProviderConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("service provider %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		provider := &ServiceProviderConfig{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           provider,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		providers = append(providers, provider)
+	}
+
+	*result = providers
+	return nil
+}
+
 func parseReserved(result **Resources, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -546,6 +839,9 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"node_gc_threshold",
 		"eval_gc_threshold",
 		"job_gc_threshold",
+		"batch_job_gc_threshold",
+		"service_job_gc_threshold",
+		"system_job_gc_threshold",
 		"deployment_gc_threshold",
 		"heartbeat_grace",
 		"min_heartbeat_ttl",
@@ -556,8 +852,18 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"non_voting_server",
 		"redundancy_zone",
 		"upgrade_version",
+		"dispatch_payload_size_limit",
+		"deployment_webhook_signing_key",
 
 		"server_join",
+		"job_admission_webhook",
+		"job_admission_rule",
+		"job_signing",
+		"namespace_defaults",
+		"scheduler_workers",
+		"client_relay",
+		"write_rate_limit",
+		"redact_env_vars",
 
 		// For backwards compatibility
 		"start_join",
@@ -575,6 +881,13 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 	}
 
 	delete(m, "server_join")
+	delete(m, "job_admission_webhook")
+	delete(m, "job_admission_rule")
+	delete(m, "job_signing")
+	delete(m, "namespace_defaults")
+	delete(m, "scheduler_workers")
+	delete(m, "client_relay")
+	delete(m, "write_rate_limit")
 
 	var config ServerConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -602,10 +915,94 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the job admission webhooks
+	if o := listVal.Filter("job_admission_webhook"); len(o.Items) > 0 {
+		if err := parseJobAdmissionWebhooks(&config.JobAdmissionWebhooks, o); err != nil {
+			return multierror.Prefix(err, "job_admission_webhook->")
+		}
+	}
+
+	// Parse the job admission rules
+	if o := listVal.Filter("job_admission_rule"); len(o.Items) > 0 {
+		if err := parseJobAdmissionRules(&config.JobAdmissionRules, o); err != nil {
+			return multierror.Prefix(err, "job_admission_rule->")
+		}
+	}
+
+	// Parse the job signing configs
+	if o := listVal.Filter("job_signing"); len(o.Items) > 0 {
+		if err := parseJobSigning(&config.JobSigning, o); err != nil {
+			return multierror.Prefix(err, "job_signing->")
+		}
+	}
+
+	// Parse the namespace defaults
+	if o := listVal.Filter("namespace_defaults"); len(o.Items) > 0 {
+		if err := parseNamespaceDefaults(&config.NamespaceDefaults, o); err != nil {
+			return multierror.Prefix(err, "namespace_defaults->")
+		}
+	}
+
+	// Parse the scheduler workers config
+	if o := listVal.Filter("scheduler_workers"); len(o.Items) > 0 {
+		if err := parseSchedulerWorkers(&config.SchedulerWorkers, o); err != nil {
+			return multierror.Prefix(err, "scheduler_workers->")
+		}
+	}
+
+	// Parse the client relay config
+	if o := listVal.Filter("client_relay"); len(o.Items) > 0 {
+		if err := parseClientRelay(&config.ClientRelay, o); err != nil {
+			return multierror.Prefix(err, "client_relay->")
+		}
+	}
+
+	// Parse the write rate limits
+	if o := listVal.Filter("write_rate_limit"); len(o.Items) > 0 {
+		if err := parseWriteRateLimit(&config.WriteRateLimit, o); err != nil {
+			return multierror.Prefix(err, "write_rate_limit->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseDataEncryption(result **DataEncryptionConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'data_encryption' block allowed")
+	}
+
+	// Get our object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"vault_transit_key",
+		"vault_transit_mount",
+		"vault_address",
+		"vault_token",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var encryption DataEncryptionConfig
+	if err := mapstructure.WeakDecode(m, &encryption); err != nil {
+		return err
+	}
+
+	*result = &encryption
+	return nil
+}
+
 func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -621,6 +1018,7 @@ func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 		"retry_join",
 		"retry_max",
 		"retry_interval",
+		"retry_max_interval",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -648,13 +1046,59 @@ func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 	return nil
 }
 
-func parseACL(result **ACLConfig, list *ast.ObjectList) error {
+func parseJobAdmissionWebhooks(result *[]*config.JobAdmissionWebhookConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"type",
+		"url",
+		"timeout",
+		"failure_policy",
+	}
+
+	var webhooks []*config.JobAdmissionWebhookConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("webhook %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		webhook := &config.JobAdmissionWebhookConfig{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           webhook,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		webhooks = append(webhooks, webhook)
+	}
+
+	*result = webhooks
+	return nil
+}
+
+func parseSchedulerWorkers(result **config.SchedulerWorkersConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
-		return fmt.Errorf("only one 'acl' block allowed")
+		return fmt.Errorf("only one 'scheduler_workers' block allowed")
 	}
 
-	// Get our server object
+	// Get our scheduler workers object
 	obj := list.Items[0]
 
 	// Value should be an object
@@ -662,15 +1106,16 @@ func parseACL(result **ACLConfig, list *ast.ObjectList) error {
 	if ot, ok := obj.Val.(*ast.ObjectType); ok {
 		listVal = ot.List
 	} else {
-		return fmt.Errorf("acl value: should be an object")
+		return fmt.Errorf("scheduler_workers value: should be an object")
 	}
 
 	// Check for invalid keys
 	valid := []string{
-		"enabled",
-		"token_ttl",
-		"policy_ttl",
-		"replication_token",
+		"pinned",
+		"autoscale",
+		"max_workers",
+		"ready_per_worker",
+		"scale_interval",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -681,11 +1126,13 @@ func parseACL(result **ACLConfig, list *ast.ObjectList) error {
 		return err
 	}
 
-	var config ACLConfig
+	delete(m, "pinned")
+
+	var workersConfig config.SchedulerWorkersConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
 		WeaklyTypedInput: true,
-		Result:           &config,
+		Result:           &workersConfig,
 	})
 	if err != nil {
 		return err
@@ -694,49 +1141,46 @@ func parseACL(result **ACLConfig, list *ast.ObjectList) error {
 		return err
 	}
 
-	*result = &config
+	// Parse out the pinned workers. These are in HCL as a list so we need to
+	// iterate over them and merge them.
+	if pinnedO := listVal.Filter("pinned"); len(pinnedO.Items) > 0 {
+		for _, o := range pinnedO.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return err
+			}
+			if err := mapstructure.WeakDecode(m, &workersConfig.Pinned); err != nil {
+				return multierror.Prefix(err, "pinned ->")
+			}
+		}
+	}
+
+	*result = &workersConfig
 	return nil
 }
 
-func parseTelemetry(result **Telemetry, list *ast.ObjectList) error {
+func parseClientRelay(result **config.ClientRelayConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
-		return fmt.Errorf("only one 'telemetry' block allowed")
+		return fmt.Errorf("only one 'client_relay' block allowed")
 	}
 
-	// Get our telemetry object
-	listVal := list.Items[0].Val
+	// Get our client relay object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("client_relay value: should be an object")
+	}
 
 	// Check for invalid keys
 	valid := []string{
-		"statsite_address",
-		"statsd_address",
-		"disable_hostname",
-		"use_node_name",
-		"collection_interval",
-		"publish_allocation_metrics",
-		"publish_node_metrics",
-		"datadog_address",
-		"datadog_tags",
-		"prometheus_metrics",
-		"circonus_api_token",
-		"circonus_api_app",
-		"circonus_api_url",
-		"circonus_submission_interval",
-		"circonus_submission_url",
-		"circonus_check_id",
-		"circonus_check_force_metric_activation",
-		"circonus_check_instance_id",
-		"circonus_check_search_tag",
-		"circonus_check_display_name",
-		"circonus_check_tags",
-		"circonus_broker_id",
-		"circonus_broker_select_tag",
-		"disable_tagged_metrics",
-		"backwards_compatible_metrics",
-		"prefix_filter",
-		"filter_default",
-		"disable_dispatched_job_summary_metrics",
+		"enabled",
+		"rate_limit",
+		"burst",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -747,32 +1191,469 @@ func parseTelemetry(result **Telemetry, list *ast.ObjectList) error {
 		return err
 	}
 
-	var telemetry Telemetry
-	if err := mapstructure.WeakDecode(m, &telemetry); err != nil {
+	var relayConfig config.ClientRelayConfig
+	if err := mapstructure.WeakDecode(m, &relayConfig); err != nil {
 		return err
 	}
-	if telemetry.CollectionInterval != "" {
-		if dur, err := time.ParseDuration(telemetry.CollectionInterval); err != nil {
-			return fmt.Errorf("error parsing value of %q: %v", "collection_interval", err)
-		} else {
-			telemetry.collectionInterval = dur
-		}
-	}
-	*result = &telemetry
+
+	*result = &relayConfig
 	return nil
 }
 
-func parseConsulConfig(result **config.ConsulConfig, list *ast.ObjectList) error {
+func parseWriteRateLimit(result **config.WriteRateLimitConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
-		return fmt.Errorf("only one 'consul' block allowed")
+		return fmt.Errorf("only one 'write_rate_limit' block allowed")
 	}
 
-	// Get our Consul object
+	// Get our write rate limit object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("write_rate_limit value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"token_rate",
+		"token_burst",
+		"namespace_rate",
+		"namespace_burst",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var limitConfig config.WriteRateLimitConfig
+	if err := mapstructure.WeakDecode(m, &limitConfig); err != nil {
+		return err
+	}
+
+	*result = &limitConfig
+	return nil
+}
+
+func parseJobSigning(result *[]*config.JobSigningConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"required",
+		"signer",
+	}
+
+	var signings []*config.JobSigningConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("job signing %d doesn't include a namespace key", i+1)
+		}
+		namespace := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", namespace))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+		delete(m, "signer")
+
+		signing := &config.JobSigningConfig{Namespace: namespace}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			WeaklyTypedInput: true,
+			Result:           signing,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", namespace))
+		}
+
+		// Parse the signers
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			if o := ot.List.Filter("signer"); len(o.Items) > 0 {
+				if err := parseJobSigners(&signing.Signers, o); err != nil {
+					return multierror.Prefix(err, fmt.Sprintf("%s -> signer ->", namespace))
+				}
+			}
+		}
+
+		signings = append(signings, signing)
+	}
+
+	*result = signings
+	return nil
+}
+
+func parseJobSigners(result *[]*config.JobSignerConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"public_key",
+	}
+
+	var signers []*config.JobSignerConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("signer %d doesn't include a name key", i+1)
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		signer := &config.JobSignerConfig{Name: name}
+		if err := mapstructure.WeakDecode(m, signer); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", name))
+		}
+		signers = append(signers, signer)
+	}
+
+	*result = signers
+	return nil
+}
+
+func parseJobAdmissionRules(result *[]*config.JobAdmissionRuleConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"datacenters",
+		"node_classes",
+		"drivers",
+	}
+
+	var rules []*config.JobAdmissionRuleConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("rule %d doesn't include a namespace key", i+1)
+		}
+		namespace := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", namespace))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		rule := &config.JobAdmissionRuleConfig{Namespace: namespace}
+		if err := mapstructure.WeakDecode(m, rule); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", namespace))
+		}
+
+		rules = append(rules, rule)
+	}
+
+	*result = rules
+	return nil
+}
+
+func parseNamespaceDefaults(result *[]*config.NamespaceDefaultsConfig, list *ast.ObjectList) error {
+	// Check for invalid keys
+	valid := []string{
+		"vault_cluster",
+		"consul_cluster",
+	}
+
+	var defaults []*config.NamespaceDefaultsConfig
+	for i, item := range list.Items {
+		unwrapLegacyHCLObjectKeysFromJSON(item, 1)
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("defaults %d don't include a namespace key", i+1)
+		}
+		namespace := item.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", namespace))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		d := &config.NamespaceDefaultsConfig{Namespace: namespace}
+		if err := mapstructure.WeakDecode(m, d); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s ->", namespace))
+		}
+
+		defaults = append(defaults, d)
+	}
+
+	*result = defaults
+	return nil
+}
+
+func parseACL(result **ACLConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'acl' block allowed")
+	}
+
+	// Get our server object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("acl value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"token_ttl",
+		"policy_ttl",
+		"replication_token",
+		"token_max_expiration_ttl",
+		"bootstrap_token_file",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var config ACLConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &config,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &config
+	return nil
+}
+
+func parseTelemetry(result **Telemetry, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'telemetry' block allowed")
+	}
+
+	// Get our telemetry object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"statsite_address",
+		"statsd_address",
+		"disable_hostname",
+		"use_node_name",
+		"collection_interval",
+		"publish_allocation_metrics",
+		"publish_node_metrics",
+		"datadog_address",
+		"datadog_tags",
+		"prometheus_metrics",
+		"circonus_api_token",
+		"circonus_api_app",
+		"circonus_api_url",
+		"circonus_submission_interval",
+		"circonus_submission_url",
+		"circonus_check_id",
+		"circonus_check_force_metric_activation",
+		"circonus_check_instance_id",
+		"circonus_check_search_tag",
+		"circonus_check_display_name",
+		"circonus_check_tags",
+		"circonus_broker_id",
+		"circonus_broker_select_tag",
+		"disable_tagged_metrics",
+		"backwards_compatible_metrics",
+		"prefix_filter",
+		"filter_default",
+		"disable_dispatched_job_summary_metrics",
+		"prometheus_remote_write",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+	delete(m, "prometheus_remote_write")
+
+	var telemetry Telemetry
+	if err := mapstructure.WeakDecode(m, &telemetry); err != nil {
+		return err
+	}
+
+	// Parse the remote write endpoint
+	if ot, ok := listVal.(*ast.ObjectType); !ok {
+		return fmt.Errorf("telemetry value: should be an object")
+	} else if o := ot.List.Filter("prometheus_remote_write"); len(o.Items) > 0 {
+		if err := parsePrometheusRemoteWrite(&telemetry.PrometheusRemoteWrite, o); err != nil {
+			return multierror.Prefix(err, "prometheus_remote_write->")
+		}
+	}
+	if telemetry.CollectionInterval != "" {
+		if dur, err := time.ParseDuration(telemetry.CollectionInterval); err != nil {
+			return fmt.Errorf("error parsing value of %q: %v", "collection_interval", err)
+		} else {
+			telemetry.collectionInterval = dur
+		}
+	}
+	*result = &telemetry
+	return nil
+}
+
+func parsePrometheusRemoteWrite(result **PrometheusRemoteWrite, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'prometheus_remote_write' block allowed")
+	}
+
+	// Get our object
 	listVal := list.Items[0].Val
 
 	// Check for invalid keys
 	valid := []string{
+		"url",
+		"interval",
+		"timeout",
+		"bearer_token",
+		"bearer_token_file",
+		"ca_file",
+		"cert_file",
+		"key_file",
+		"tls_server_name",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var remoteWrite PrometheusRemoteWrite
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &remoteWrite,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &remoteWrite
+	return nil
+}
+
+func parseWatchdog(result **WatchdogConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'watchdog' block allowed")
+	}
+
+	// Get our watchdog object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"interval",
+		"systemd_notify",
+		"exit_on_unhealthy",
+		"exit_on_lost_quorum",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var watchdog WatchdogConfig
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &watchdog,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &watchdog
+	return nil
+}
+
+// parseConsulConfigs parses the consul blocks. The block without a name
+// configures the default cluster and the others the named clusters.
+func parseConsulConfigs(result **config.ConsulConfig, named *[]*config.ConsulConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	names := make(map[string]struct{}, len(list.Items))
+	for _, item := range list.Items {
+		var consulConfig *config.ConsulConfig
+		if err := parseConsulConfig(&consulConfig, item.Val); err != nil {
+			return err
+		}
+
+		name := consulConfig.Name
+		if name == structs.ConsulDefaultCluster {
+			consulConfig.Name, name = "", ""
+		}
+		if _, ok := names[name]; ok {
+			if name == "" {
+				return fmt.Errorf("only one 'consul' block allowed without a name")
+			}
+			return fmt.Errorf("only one 'consul' block allowed with name %q", name)
+		}
+		names[name] = struct{}{}
+
+		if name == "" {
+			*result = consulConfig
+		} else {
+			*named = append(*named, consulConfig)
+		}
+	}
+	return nil
+}
+
+func parseConsulConfig(result **config.ConsulConfig, listVal ast.Node) error {
+	// Check for invalid keys
+	valid := []string{
+		"name",
 		"address",
 		"auth",
 		"auto_advertise",
@@ -841,6 +1722,7 @@ func parseTLSConfig(result **config.TLSConfig, list *ast.ObjectList) error {
 		"tls_cipher_suites",
 		"tls_min_version",
 		"tls_prefer_server_cipher_suites",
+		"auto",
 	}
 
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
@@ -851,12 +1733,22 @@ func parseTLSConfig(result **config.TLSConfig, list *ast.ObjectList) error {
 	if err := hcl.DecodeObject(&m, listVal); err != nil {
 		return err
 	}
+	delete(m, "auto")
 
 	var tlsConfig config.TLSConfig
 	if err := mapstructure.WeakDecode(m, &tlsConfig); err != nil {
 		return err
 	}
 
+	// Parse the automatic certificates
+	if ot, ok := listVal.(*ast.ObjectType); ok {
+		if o := ot.List.Filter("auto"); len(o.Items) > 0 {
+			if err := parseAutoTLS(&tlsConfig.Auto, o); err != nil {
+				return multierror.Prefix(err, "auto ->")
+			}
+		}
+	}
+
 	if _, err := tlsutil.ParseCiphers(&tlsConfig); err != nil {
 		return err
 	}
@@ -869,17 +1761,92 @@ func parseTLSConfig(result **config.TLSConfig, list *ast.ObjectList) error {
 	return nil
 }
 
-func parseVaultConfig(result **config.VaultConfig, list *ast.ObjectList) error {
+func parseAutoTLS(result **config.AutoTLSConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
-		return fmt.Errorf("only one 'vault' block allowed")
+		return fmt.Errorf("only one 'auto' block allowed")
 	}
 
-	// Get our Vault object
+	// Get our auto object
 	listVal := list.Items[0].Val
 
 	// Check for invalid keys
 	valid := []string{
+		"enabled",
+		"provider",
+		"dir",
+		"ttl",
+		"alt_names",
+		"vault_pki_path",
+		"vault_role",
+		"ca_key_file",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	autoConfig := config.DefaultAutoTLSConfig()
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &autoConfig,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = autoConfig
+	return nil
+}
+
+// parseVaultConfigs parses the vault blocks. The block without a name
+// configures the default cluster and the others the named clusters.
+func parseVaultConfigs(result **config.VaultConfig, named *[]*config.VaultConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	names := make(map[string]struct{}, len(list.Items))
+	for _, item := range list.Items {
+		var vaultConfig *config.VaultConfig
+		if err := parseVaultConfig(&vaultConfig, item.Val); err != nil {
+			return err
+		}
+
+		name := vaultConfig.Name
+		if name == structs.VaultDefaultCluster {
+			vaultConfig.Name, name = "", ""
+		}
+		if _, ok := names[name]; ok {
+			if name == "" {
+				return fmt.Errorf("only one 'vault' block allowed without a name")
+			}
+			return fmt.Errorf("only one 'vault' block allowed with name %q", name)
+		}
+		names[name] = struct{}{}
+
+		if name == "" {
+			*result = vaultConfig
+		} else {
+			// Named clusters are configured to be used
+			if vaultConfig.Enabled == nil {
+				vaultConfig.Enabled = helper.BoolToPtr(true)
+			}
+			*named = append(*named, vaultConfig)
+		}
+	}
+	return nil
+}
+
+func parseVaultConfig(result **config.VaultConfig, listVal ast.Node) error {
+	// Check for invalid keys
+	valid := []string{
+		"name",
 		"address",
 		"allow_unauthenticated",
 		"enabled",
@@ -892,6 +1859,10 @@ func parseVaultConfig(result **config.VaultConfig, list *ast.ObjectList) error {
 		"tls_server_name",
 		"tls_skip_verify",
 		"token",
+		"use_identity",
+		"jwt_auth_path",
+		"jwt_auth_role",
+		"allowed_namespaces",
 	}
 
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
@@ -1000,6 +1971,206 @@ func parseAutopilot(result **config.AutopilotConfig, list *ast.ObjectList) error
 	return nil
 }
 
+func parseSnapshotAgent(result **config.SnapshotAgentConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'snapshot_agent' block allowed")
+	}
+
+	// Get our snapshot agent object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("snapshot_agent value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"interval",
+		"retain",
+		"local_path",
+		"s3",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	delete(m, "s3")
+
+	snapshotConfig := config.DefaultSnapshotAgentConfig()
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &snapshotConfig,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the S3 destination
+	if o := listVal.Filter("s3"); len(o.Items) > 0 {
+		if err := parseSnapshotAgentS3(&snapshotConfig.S3, o); err != nil {
+			return multierror.Prefix(err, "s3->")
+		}
+	}
+
+	*result = snapshotConfig
+	return nil
+}
+
+func parseSnapshotAgentS3(result **config.SnapshotAgentS3Config, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 's3' block allowed")
+	}
+
+	// Get our object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"bucket",
+		"key_prefix",
+		"region",
+		"endpoint",
+		"force_path_style",
+		"access_key_id",
+		"secret_access_key",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var s3Config config.SnapshotAgentS3Config
+	if err := mapstructure.WeakDecode(m, &s3Config); err != nil {
+		return err
+	}
+
+	*result = &s3Config
+	return nil
+}
+
+func parseExternalDNS(result **config.ExternalDNSConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'external_dns' block allowed")
+	}
+
+	// Get our external DNS object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("external_dns value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"zone",
+		"ttl",
+		"interval",
+		"route53",
+		"coredns",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	delete(m, "route53")
+	delete(m, "coredns")
+
+	dnsConfig := config.DefaultExternalDNSConfig()
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &dnsConfig,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the providers
+	if o := listVal.Filter("route53"); len(o.Items) > 0 {
+		valid := []string{
+			"hosted_zone_id",
+			"endpoint",
+			"access_key_id",
+			"secret_access_key",
+		}
+		var route53Config config.ExternalDNSRoute53Config
+		if err := parseExternalDNSProvider(&route53Config, valid, o); err != nil {
+			return multierror.Prefix(err, "route53->")
+		}
+		dnsConfig.Route53 = &route53Config
+	}
+	if o := listVal.Filter("coredns"); len(o.Items) > 0 {
+		valid := []string{
+			"etcd_address",
+			"path_prefix",
+		}
+		var coreDNSConfig config.ExternalDNSCoreDNSConfig
+		if err := parseExternalDNSProvider(&coreDNSConfig, valid, o); err != nil {
+			return multierror.Prefix(err, "coredns->")
+		}
+		dnsConfig.CoreDNS = &coreDNSConfig
+	}
+
+	*result = dnsConfig
+	return nil
+}
+
+func parseExternalDNSProvider(result interface{}, valid []string, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one block allowed")
+	}
+
+	// Get our object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	return mapstructure.WeakDecode(m, result)
+}
+
 func parsePlugins(result *[]*config.PluginConfig, list *ast.ObjectList) error {
 	listLen := len(list.Items)
 	plugins := make([]*config.PluginConfig, listLen)
@@ -49,11 +49,50 @@ func TestConfig_Parse(t *testing.T) {
 					AllocDir:  "/tmp/alloc",
 					Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
 					NodeClass: "linux-medium-64bit",
+					NodePool:  "linux",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
 						RetryMaxAttempts: 3,
 					},
+					MaintenanceWindows: []*MaintenanceWindowConfig{
+						{
+							Cron:     "0 2 * * SUN",
+							Duration: 2 * time.Hour,
+							TimeZone: "America/New_York",
+						},
+					},
+					ReadinessGates: []*ReadinessGateConfig{
+						{
+							Name:     "image-cache",
+							Command:  "/usr/local/bin/check-image-cache",
+							Args:     []string{"-min", "3"},
+							Interval: 5 * time.Second,
+							Timeout:  2 * time.Second,
+						},
+					},
+					DriverRemediations: []*DriverRemediationConfig{
+						{
+							Driver:       "docker",
+							Command:      "/usr/local/bin/restart-docker",
+							Args:         []string{"-force"},
+							Timeout:      30 * time.Second,
+							Backoff:      10 * time.Second,
+							BackoffLimit: time.Minute,
+						},
+					},
+					ServiceProviders: []*ServiceProviderConfig{
+						{
+							Name:    "f5",
+							URL:     "https://lb-adapter.example.com/v1/pools/web",
+							Timeout: 5 * time.Second,
+						},
+					},
+					DataEncryption: &DataEncryptionConfig{
+						Enabled:           helper.BoolToPtr(true),
+						VaultTransitKey:   "nomad-client",
+						VaultTransitMount: "secrets",
+					},
 					Meta: map[string]string{
 						"foo": "bar",
 						"baz": "zip",
@@ -66,13 +105,28 @@ func TestConfig_Parse(t *testing.T) {
 						"/opt/myapp/etc": "/etc",
 						"/opt/myapp/bin": "/bin",
 					},
-					NetworkInterface: "eth0",
-					NetworkSpeed:     100,
-					CpuCompute:       4444,
-					MemoryMB:         0,
-					MaxKillTimeout:   "10s",
-					ClientMinPort:    1000,
-					ClientMaxPort:    2000,
+					NamespaceAllocDirs: map[string]string{
+						"batch-tenants": "/mnt/scratch/alloc",
+					},
+					JobTypeAllocDirs: map[string]string{
+						"batch": "/mnt/batch/alloc",
+					},
+					NetworkInterface:      "eth0",
+					NetworkSpeed:          100,
+					CpuCompute:            4444,
+					MemoryMB:              0,
+					MaxKillTimeout:        "10s",
+					ClientMinPort:         1000,
+					ClientMaxPort:         2000,
+					DynamicUsers:          true,
+					DynamicUserMinID:      60000,
+					DynamicUserMaxID:      60999,
+					DisablePrefetch:       true,
+					MigrateBandwidthLimit: "50MB",
+					RPCCompression:        true,
+					EnableFaultInjection:  true,
+					RPCBandwidthLimit:     "1MB",
+					RealtimeRuntime:       50 * time.Millisecond,
 					Reserved: &Resources{
 						CPU:           10,
 						MemoryMB:      10,
@@ -84,44 +138,110 @@ func TestConfig_Parse(t *testing.T) {
 					GCDiskUsageThreshold:  82,
 					GCInodeUsageThreshold: 91,
 					GCMaxAllocs:           50,
+					MaxTaskEvents:         40,
 					NoHostUUID:            helper.BoolToPtr(false),
 				},
 				Server: &ServerConfig{
-					Enabled:                true,
-					AuthoritativeRegion:    "foobar",
-					BootstrapExpect:        5,
-					DataDir:                "/tmp/data",
-					ProtocolVersion:        3,
-					RaftProtocol:           3,
-					NumSchedulers:          helper.IntToPtr(2),
-					EnabledSchedulers:      []string{"test"},
-					NodeGCThreshold:        "12h",
-					EvalGCThreshold:        "12h",
-					JobGCThreshold:         "12h",
-					DeploymentGCThreshold:  "12h",
-					HeartbeatGrace:         30 * time.Second,
-					MinHeartbeatTTL:        33 * time.Second,
-					MaxHeartbeatsPerSecond: 11.0,
-					RetryJoin:              []string{"1.1.1.1", "2.2.2.2"},
-					StartJoin:              []string{"1.1.1.1", "2.2.2.2"},
-					RetryInterval:          15 * time.Second,
-					RejoinAfterLeave:       true,
-					RetryMaxAttempts:       3,
-					NonVotingServer:        true,
-					RedundancyZone:         "foo",
-					UpgradeVersion:         "0.8.0",
-					EncryptKey:             "abc",
+					Enabled:                     true,
+					AuthoritativeRegion:         "foobar",
+					BootstrapExpect:             5,
+					DataDir:                     "/tmp/data",
+					ProtocolVersion:             3,
+					RaftProtocol:                3,
+					NumSchedulers:               helper.IntToPtr(2),
+					EnabledSchedulers:           []string{"test"},
+					NodeGCThreshold:             "12h",
+					EvalGCThreshold:             "12h",
+					JobGCThreshold:              "12h",
+					BatchJobGCThreshold:         "1h",
+					ServiceJobGCThreshold:       "24h",
+					SystemJobGCThreshold:        "48h",
+					DeploymentGCThreshold:       "12h",
+					HeartbeatGrace:              30 * time.Second,
+					MinHeartbeatTTL:             33 * time.Second,
+					MaxHeartbeatsPerSecond:      11.0,
+					RetryJoin:                   []string{"1.1.1.1", "2.2.2.2"},
+					StartJoin:                   []string{"1.1.1.1", "2.2.2.2"},
+					RetryInterval:               15 * time.Second,
+					RejoinAfterLeave:            true,
+					RetryMaxAttempts:            3,
+					NonVotingServer:             true,
+					RedundancyZone:              "foo",
+					UpgradeVersion:              "0.8.0",
+					DispatchPayloadSizeLimit:    "64KiB",
+					DeploymentWebhookSigningKey: "deployment-secret",
+					RedactEnvVars:               []string{"*_PASSWORD", "AWS_SECRET_ACCESS_KEY"},
+					EncryptKey:                  "abc",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
 						RetryMaxAttempts: 3,
+						RetryMaxInterval: 2 * time.Minute,
+					},
+					JobAdmissionWebhooks: []*config.JobAdmissionWebhookConfig{
+						{
+							Name:          "require-meta",
+							Type:          "validating",
+							URL:           "https://admission.example.com/validate",
+							Timeout:       5 * time.Second,
+							FailurePolicy: "ignore",
+						},
+					},
+					JobAdmissionRules: []*config.JobAdmissionRuleConfig{
+						{
+							Namespace:   "untrusted",
+							Datacenters: []string{"dc2"},
+							Drivers:     []string{"docker"},
+						},
+					},
+					NamespaceDefaults: []*config.NamespaceDefaultsConfig{
+						{
+							Namespace:     "finance",
+							VaultCluster:  "secrets",
+							ConsulCluster: "edge",
+						},
+					},
+					JobSigning: []*config.JobSigningConfig{
+						{
+							Namespace: "default",
+							Required:  true,
+							Signers: []*config.JobSignerConfig{
+								{
+									Name:      "release-bot",
+									PublicKey: "-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEADXFRXX3lQICIxRalPeaiiKaD7jSQQ6fxtn8MuM98ook=\n-----END PUBLIC KEY-----\n",
+								},
+							},
+						},
+					},
+					SchedulerWorkers: &config.SchedulerWorkersConfig{
+						Pinned: map[string]int{
+							"service": 2,
+							"batch":   1,
+						},
+						Autoscale:      helper.BoolToPtr(true),
+						MaxWorkers:     8,
+						ReadyPerWorker: 5,
+						ScaleInterval:  30 * time.Second,
+					},
+					ClientRelay: &config.ClientRelayConfig{
+						Enabled:   helper.BoolToPtr(true),
+						RateLimit: 2.5,
+						Burst:     10,
+					},
+					WriteRateLimit: &config.WriteRateLimitConfig{
+						TokenRate:      5,
+						TokenBurst:     10,
+						NamespaceRate:  20,
+						NamespaceBurst: 40,
 					},
 				},
 				ACL: &ACLConfig{
-					Enabled:          true,
-					TokenTTL:         60 * time.Second,
-					PolicyTTL:        60 * time.Second,
-					ReplicationToken: "foobar",
+					Enabled:               true,
+					TokenTTL:              60 * time.Second,
+					PolicyTTL:             60 * time.Second,
+					ReplicationToken:      "foobar",
+					TokenMaxExpirationTTL: 720 * time.Hour,
+					BootstrapTokenFile:    "/etc/nomad/bootstrap-token",
 				},
 				Telemetry: &Telemetry{
 					StatsiteAddr:               "127.0.0.1:1234",
@@ -135,6 +255,12 @@ func TestConfig_Parse(t *testing.T) {
 					PublishNodeMetrics:         true,
 					DisableTaggedMetrics:       true,
 					BackwardsCompatibleMetrics: true,
+					PrometheusRemoteWrite: &PrometheusRemoteWrite{
+						URL:             "https://prometheus.example.com/api/v1/write",
+						Interval:        30 * time.Second,
+						BearerTokenFile: "/etc/nomad/prometheus-token",
+						CAFile:          "/etc/nomad/prometheus-ca.pem",
+					},
 				},
 				LeaveOnInt:                true,
 				LeaveOnTerm:               true,
@@ -175,6 +301,24 @@ func TestConfig_Parse(t *testing.T) {
 					TLSSkipVerify:        &trueValue,
 					TaskTokenTTL:         "1s",
 					Token:                "12345",
+					UseIdentity:          &trueValue,
+					JWTAuthPath:          "jwt-nomad",
+					JWTAuthRole:          "nomad-workloads",
+					AllowedNamespaces:    []string{"engineering", "finance"},
+				},
+				Consuls: []*config.ConsulConfig{
+					{
+						Name: "edge",
+						Addr: "127.0.0.1:9600",
+					},
+				},
+				Vaults: []*config.VaultConfig{
+					{
+						Name:    "secrets",
+						Addr:    "127.0.0.1:9700",
+						Role:    "finance_role",
+						Enabled: &trueValue,
+					},
 				},
 				TLSConfig: &config.TLSConfig{
 					EnableHTTP:                  true,
@@ -188,6 +332,15 @@ func TestConfig_Parse(t *testing.T) {
 					TLSPreferServerCipherSuites: true,
 					TLSCipherSuites:             "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
 					TLSMinVersion:               "tls12",
+					Auto: &config.AutoTLSConfig{
+						Enabled:      helper.BoolToPtr(true),
+						Provider:     config.AutoTLSProviderBuiltin,
+						Dir:          "/tmp/tls",
+						TTL:          24 * time.Hour,
+						AltNames:     []string{"nomad.example.com", "10.0.0.1"},
+						VaultPKIPath: "pki",
+						CAKeyFile:    "/path/to/ca-key.pem",
+					},
 				},
 				HTTPAPIResponseHeaders: map[string]string{
 					"Access-Control-Allow-Origin": "*",
@@ -215,6 +368,38 @@ func TestConfig_Parse(t *testing.T) {
 					DisableUpgradeMigration: &trueValue,
 					EnableCustomUpgrades:    &trueValue,
 				},
+				SnapshotAgent: &config.SnapshotAgentConfig{
+					Enabled:   &trueValue,
+					Interval:  30 * time.Minute,
+					Retain:    helper.IntToPtr(12),
+					LocalPath: "/opt/nomad/snapshots",
+					S3: &config.SnapshotAgentS3Config{
+						Bucket:          "nomad-snapshots",
+						KeyPrefix:       "global/",
+						Region:          "us-east-1",
+						Endpoint:        "https://s3.example.com",
+						ForcePathStyle:  &trueValue,
+						AccessKeyID:     "AKIAEXAMPLE",
+						SecretAccessKey: "secret",
+					},
+				},
+				ExternalDNS: &config.ExternalDNSConfig{
+					Enabled:  &trueValue,
+					Zone:     "nomad.example.com",
+					TTL:      15 * time.Second,
+					Interval: 10 * time.Minute,
+					Route53: &config.ExternalDNSRoute53Config{
+						HostedZoneID:    "Z123EXAMPLE",
+						AccessKeyID:     "AKIAEXAMPLE",
+						SecretAccessKey: "secret",
+					},
+				},
+				Watchdog: &WatchdogConfig{
+					Interval:         5 * time.Second,
+					SystemdNotify:    true,
+					ExitOnUnhealthy:  10 * time.Minute,
+					ExitOnLostQuorum: 2 * time.Minute,
+				},
 				Plugins: []*config.PluginConfig{
 					{
 						Name: "docker",
@@ -270,11 +455,50 @@ func TestConfig_Parse(t *testing.T) {
 					AllocDir:  "/tmp/alloc",
 					Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
 					NodeClass: "linux-medium-64bit",
+					NodePool:  "linux",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
 						RetryMaxAttempts: 3,
 					},
+					MaintenanceWindows: []*MaintenanceWindowConfig{
+						{
+							Cron:     "0 2 * * SUN",
+							Duration: 2 * time.Hour,
+							TimeZone: "America/New_York",
+						},
+					},
+					ReadinessGates: []*ReadinessGateConfig{
+						{
+							Name:     "image-cache",
+							Command:  "/usr/local/bin/check-image-cache",
+							Args:     []string{"-min", "3"},
+							Interval: 5 * time.Second,
+							Timeout:  2 * time.Second,
+						},
+					},
+					DriverRemediations: []*DriverRemediationConfig{
+						{
+							Driver:       "docker",
+							Command:      "/usr/local/bin/restart-docker",
+							Args:         []string{"-force"},
+							Timeout:      30 * time.Second,
+							Backoff:      10 * time.Second,
+							BackoffLimit: time.Minute,
+						},
+					},
+					ServiceProviders: []*ServiceProviderConfig{
+						{
+							Name:    "f5",
+							URL:     "https://lb-adapter.example.com/v1/pools/web",
+							Timeout: 5 * time.Second,
+						},
+					},
+					DataEncryption: &DataEncryptionConfig{
+						Enabled:           helper.BoolToPtr(true),
+						VaultTransitKey:   "nomad-client",
+						VaultTransitMount: "secrets",
+					},
 					Meta: map[string]string{
 						"foo": "bar",
 						"baz": "zip",
@@ -287,13 +511,28 @@ func TestConfig_Parse(t *testing.T) {
 						"/opt/myapp/etc": "/etc",
 						"/opt/myapp/bin": "/bin",
 					},
-					NetworkInterface: "eth0",
-					NetworkSpeed:     100,
-					CpuCompute:       4444,
-					MemoryMB:         0,
-					MaxKillTimeout:   "10s",
-					ClientMinPort:    1000,
-					ClientMaxPort:    2000,
+					NamespaceAllocDirs: map[string]string{
+						"batch-tenants": "/mnt/scratch/alloc",
+					},
+					JobTypeAllocDirs: map[string]string{
+						"batch": "/mnt/batch/alloc",
+					},
+					NetworkInterface:      "eth0",
+					NetworkSpeed:          100,
+					CpuCompute:            4444,
+					MemoryMB:              0,
+					MaxKillTimeout:        "10s",
+					ClientMinPort:         1000,
+					ClientMaxPort:         2000,
+					DynamicUsers:          true,
+					DynamicUserMinID:      60000,
+					DynamicUserMaxID:      60999,
+					DisablePrefetch:       true,
+					MigrateBandwidthLimit: "50MB",
+					RPCCompression:        true,
+					EnableFaultInjection:  true,
+					RPCBandwidthLimit:     "1MB",
+					RealtimeRuntime:       50 * time.Millisecond,
 					Reserved: &Resources{
 						CPU:           10,
 						MemoryMB:      10,
@@ -305,44 +544,110 @@ func TestConfig_Parse(t *testing.T) {
 					GCDiskUsageThreshold:  82,
 					GCInodeUsageThreshold: 91,
 					GCMaxAllocs:           50,
+					MaxTaskEvents:         40,
 					NoHostUUID:            helper.BoolToPtr(false),
 				},
 				Server: &ServerConfig{
-					Enabled:                true,
-					AuthoritativeRegion:    "foobar",
-					BootstrapExpect:        5,
-					DataDir:                "/tmp/data",
-					ProtocolVersion:        3,
-					RaftProtocol:           3,
-					NumSchedulers:          helper.IntToPtr(2),
-					EnabledSchedulers:      []string{"test"},
-					NodeGCThreshold:        "12h",
-					EvalGCThreshold:        "12h",
-					JobGCThreshold:         "12h",
-					DeploymentGCThreshold:  "12h",
-					HeartbeatGrace:         30 * time.Second,
-					MinHeartbeatTTL:        33 * time.Second,
-					MaxHeartbeatsPerSecond: 11.0,
-					RetryJoin:              []string{"1.1.1.1", "2.2.2.2"},
-					StartJoin:              []string{"1.1.1.1", "2.2.2.2"},
-					RetryInterval:          15 * time.Second,
-					RejoinAfterLeave:       true,
-					RetryMaxAttempts:       3,
-					NonVotingServer:        true,
-					RedundancyZone:         "foo",
-					UpgradeVersion:         "0.8.0",
-					EncryptKey:             "abc",
+					Enabled:                     true,
+					AuthoritativeRegion:         "foobar",
+					BootstrapExpect:             5,
+					DataDir:                     "/tmp/data",
+					ProtocolVersion:             3,
+					RaftProtocol:                3,
+					NumSchedulers:               helper.IntToPtr(2),
+					EnabledSchedulers:           []string{"test"},
+					NodeGCThreshold:             "12h",
+					EvalGCThreshold:             "12h",
+					JobGCThreshold:              "12h",
+					BatchJobGCThreshold:         "1h",
+					ServiceJobGCThreshold:       "24h",
+					SystemJobGCThreshold:        "48h",
+					DeploymentGCThreshold:       "12h",
+					HeartbeatGrace:              30 * time.Second,
+					MinHeartbeatTTL:             33 * time.Second,
+					MaxHeartbeatsPerSecond:      11.0,
+					RetryJoin:                   []string{"1.1.1.1", "2.2.2.2"},
+					StartJoin:                   []string{"1.1.1.1", "2.2.2.2"},
+					RetryInterval:               15 * time.Second,
+					RejoinAfterLeave:            true,
+					RetryMaxAttempts:            3,
+					NonVotingServer:             true,
+					RedundancyZone:              "foo",
+					UpgradeVersion:              "0.8.0",
+					DispatchPayloadSizeLimit:    "64KiB",
+					DeploymentWebhookSigningKey: "deployment-secret",
+					RedactEnvVars:               []string{"*_PASSWORD", "AWS_SECRET_ACCESS_KEY"},
+					EncryptKey:                  "abc",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
 						RetryMaxAttempts: 3,
+						RetryMaxInterval: 2 * time.Minute,
+					},
+					JobAdmissionWebhooks: []*config.JobAdmissionWebhookConfig{
+						{
+							Name:          "require-meta",
+							Type:          "validating",
+							URL:           "https://admission.example.com/validate",
+							Timeout:       5 * time.Second,
+							FailurePolicy: "ignore",
+						},
+					},
+					JobAdmissionRules: []*config.JobAdmissionRuleConfig{
+						{
+							Namespace:   "untrusted",
+							Datacenters: []string{"dc2"},
+							Drivers:     []string{"docker"},
+						},
+					},
+					NamespaceDefaults: []*config.NamespaceDefaultsConfig{
+						{
+							Namespace:     "finance",
+							VaultCluster:  "secrets",
+							ConsulCluster: "edge",
+						},
+					},
+					JobSigning: []*config.JobSigningConfig{
+						{
+							Namespace: "default",
+							Required:  true,
+							Signers: []*config.JobSignerConfig{
+								{
+									Name:      "release-bot",
+									PublicKey: "-----BEGIN PUBLIC KEY-----\nMCowBQYDK2VwAyEADXFRXX3lQICIxRalPeaiiKaD7jSQQ6fxtn8MuM98ook=\n-----END PUBLIC KEY-----\n",
+								},
+							},
+						},
+					},
+					SchedulerWorkers: &config.SchedulerWorkersConfig{
+						Pinned: map[string]int{
+							"service": 2,
+							"batch":   1,
+						},
+						Autoscale:      helper.BoolToPtr(true),
+						MaxWorkers:     8,
+						ReadyPerWorker: 5,
+						ScaleInterval:  30 * time.Second,
+					},
+					ClientRelay: &config.ClientRelayConfig{
+						Enabled:   helper.BoolToPtr(true),
+						RateLimit: 2.5,
+						Burst:     10,
+					},
+					WriteRateLimit: &config.WriteRateLimitConfig{
+						TokenRate:      5,
+						TokenBurst:     10,
+						NamespaceRate:  20,
+						NamespaceBurst: 40,
 					},
 				},
 				ACL: &ACLConfig{
-					Enabled:          true,
-					TokenTTL:         60 * time.Second,
-					PolicyTTL:        60 * time.Second,
-					ReplicationToken: "foobar",
+					Enabled:               true,
+					TokenTTL:              60 * time.Second,
+					PolicyTTL:             60 * time.Second,
+					ReplicationToken:      "foobar",
+					TokenMaxExpirationTTL: 720 * time.Hour,
+					BootstrapTokenFile:    "/etc/nomad/bootstrap-token",
 				},
 				Telemetry: &Telemetry{
 					StatsiteAddr:               "127.0.0.1:1234",
@@ -356,6 +661,12 @@ func TestConfig_Parse(t *testing.T) {
 					PublishNodeMetrics:         true,
 					DisableTaggedMetrics:       true,
 					BackwardsCompatibleMetrics: true,
+					PrometheusRemoteWrite: &PrometheusRemoteWrite{
+						URL:             "https://prometheus.example.com/api/v1/write",
+						Interval:        30 * time.Second,
+						BearerTokenFile: "/etc/nomad/prometheus-token",
+						CAFile:          "/etc/nomad/prometheus-ca.pem",
+					},
 				},
 				LeaveOnInt:                true,
 				LeaveOnTerm:               true,
@@ -396,6 +707,24 @@ func TestConfig_Parse(t *testing.T) {
 					TLSSkipVerify:        &trueValue,
 					TaskTokenTTL:         "1s",
 					Token:                "12345",
+					UseIdentity:          &trueValue,
+					JWTAuthPath:          "jwt-nomad",
+					JWTAuthRole:          "nomad-workloads",
+					AllowedNamespaces:    []string{"engineering", "finance"},
+				},
+				Consuls: []*config.ConsulConfig{
+					{
+						Name: "edge",
+						Addr: "127.0.0.1:9600",
+					},
+				},
+				Vaults: []*config.VaultConfig{
+					{
+						Name:    "secrets",
+						Addr:    "127.0.0.1:9700",
+						Role:    "finance_role",
+						Enabled: &trueValue,
+					},
 				},
 				TLSConfig: &config.TLSConfig{
 					EnableHTTP:                  true,
@@ -409,6 +738,15 @@ func TestConfig_Parse(t *testing.T) {
 					TLSPreferServerCipherSuites: true,
 					TLSCipherSuites:             "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
 					TLSMinVersion:               "tls12",
+					Auto: &config.AutoTLSConfig{
+						Enabled:      helper.BoolToPtr(true),
+						Provider:     config.AutoTLSProviderBuiltin,
+						Dir:          "/tmp/tls",
+						TTL:          24 * time.Hour,
+						AltNames:     []string{"nomad.example.com", "10.0.0.1"},
+						VaultPKIPath: "pki",
+						CAKeyFile:    "/path/to/ca-key.pem",
+					},
 				},
 				HTTPAPIResponseHeaders: map[string]string{
 					"Access-Control-Allow-Origin": "*",
@@ -436,6 +774,38 @@ func TestConfig_Parse(t *testing.T) {
 					DisableUpgradeMigration: &trueValue,
 					EnableCustomUpgrades:    &trueValue,
 				},
+				SnapshotAgent: &config.SnapshotAgentConfig{
+					Enabled:   &trueValue,
+					Interval:  30 * time.Minute,
+					Retain:    helper.IntToPtr(12),
+					LocalPath: "/opt/nomad/snapshots",
+					S3: &config.SnapshotAgentS3Config{
+						Bucket:          "nomad-snapshots",
+						KeyPrefix:       "global/",
+						Region:          "us-east-1",
+						Endpoint:        "https://s3.example.com",
+						ForcePathStyle:  &trueValue,
+						AccessKeyID:     "AKIAEXAMPLE",
+						SecretAccessKey: "secret",
+					},
+				},
+				ExternalDNS: &config.ExternalDNSConfig{
+					Enabled:  &trueValue,
+					Zone:     "nomad.example.com",
+					TTL:      15 * time.Second,
+					Interval: 10 * time.Minute,
+					Route53: &config.ExternalDNSRoute53Config{
+						HostedZoneID:    "Z123EXAMPLE",
+						AccessKeyID:     "AKIAEXAMPLE",
+						SecretAccessKey: "secret",
+					},
+				},
+				Watchdog: &WatchdogConfig{
+					Interval:         5 * time.Second,
+					SystemdNotify:    true,
+					ExitOnUnhealthy:  10 * time.Minute,
+					ExitOnLostQuorum: 2 * time.Minute,
+				},
 				Plugins: []*config.PluginConfig{
 					{
 						Name: "docker",
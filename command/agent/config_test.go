@@ -38,6 +38,8 @@ func TestConfig_Merge(t *testing.T) {
 		Consul:         &config.ConsulConfig{},
 		Sentinel:       &config.SentinelConfig{},
 		Autopilot:      &config.AutopilotConfig{},
+		SnapshotAgent:  &config.SnapshotAgentConfig{},
+		ExternalDNS:    &config.ExternalDNSConfig{},
 	}
 
 	c2 := &Config{
@@ -85,6 +87,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state1",
 			AllocDir:  "/tmp/alloc1",
 			NodeClass: "class1",
+			NodePool:  "pool1",
 			Options: map[string]string{
 				"foo": "bar",
 			},
@@ -176,6 +179,29 @@ func TestConfig_Merge(t *testing.T) {
 			DisableUpgradeMigration: &falseValue,
 			EnableCustomUpgrades:    &falseValue,
 		},
+		SnapshotAgent: &config.SnapshotAgentConfig{
+			Enabled:   &falseValue,
+			Interval:  1 * time.Hour,
+			Retain:    helper.IntToPtr(1),
+			LocalPath: "/tmp/snapshots1",
+			S3: &config.SnapshotAgentS3Config{
+				Bucket:         "bucket1",
+				ForcePathStyle: &falseValue,
+			},
+		},
+		ExternalDNS: &config.ExternalDNSConfig{
+			Enabled:  &falseValue,
+			Zone:     "nomad1.example.com",
+			TTL:      10 * time.Second,
+			Interval: 1 * time.Minute,
+			CoreDNS: &config.ExternalDNSCoreDNSConfig{
+				EtcdAddress: "http://127.0.0.1:2379",
+			},
+		},
+		Watchdog: &WatchdogConfig{
+			Interval:        5 * time.Second,
+			ExitOnUnhealthy: 5 * time.Minute,
+		},
 		Plugins: []*config.PluginConfig{
 			{
 				Name: "docker",
@@ -236,6 +262,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state2",
 			AllocDir:  "/tmp/alloc2",
 			NodeClass: "class2",
+			NodePool:  "pool2",
 			Servers:   []string{"server2"},
 			Meta: map[string]string{
 				"baz": "zip",
@@ -244,7 +271,13 @@ func TestConfig_Merge(t *testing.T) {
 				"foo": "bar",
 				"baz": "zip",
 			},
-			ChrootEnv:      map[string]string{},
+			ChrootEnv: map[string]string{},
+			NamespaceAllocDirs: map[string]string{
+				"tenant": "/mnt/tenant/alloc",
+			},
+			JobTypeAllocDirs: map[string]string{
+				"batch": "/mnt/scratch/alloc",
+			},
 			ClientMaxPort:  20000,
 			ClientMinPort:  22000,
 			NetworkSpeed:   105,
@@ -282,12 +315,15 @@ func TestConfig_Merge(t *testing.T) {
 			NonVotingServer:        true,
 			RedundancyZone:         "bar",
 			UpgradeVersion:         "bar",
+			RedactEnvVars:          []string{"*_PASSWORD"},
 		},
 		ACL: &ACLConfig{
-			Enabled:          true,
-			TokenTTL:         20 * time.Second,
-			PolicyTTL:        20 * time.Second,
-			ReplicationToken: "foobar",
+			Enabled:               true,
+			TokenTTL:              20 * time.Second,
+			PolicyTTL:             20 * time.Second,
+			ReplicationToken:      "foobar",
+			TokenMaxExpirationTTL: 24 * time.Hour,
+			BootstrapTokenFile:    "/etc/nomad/bootstrap-token",
 		},
 		Ports: &Ports{
 			HTTP: 20000,
@@ -354,6 +390,36 @@ func TestConfig_Merge(t *testing.T) {
 			DisableUpgradeMigration: &trueValue,
 			EnableCustomUpgrades:    &trueValue,
 		},
+		SnapshotAgent: &config.SnapshotAgentConfig{
+			Enabled:   &trueValue,
+			Interval:  2 * time.Hour,
+			Retain:    helper.IntToPtr(2),
+			LocalPath: "/tmp/snapshots2",
+			S3: &config.SnapshotAgentS3Config{
+				Bucket:          "bucket2",
+				KeyPrefix:       "prefix2",
+				Region:          "us-west-2",
+				Endpoint:        "https://s3.example.com",
+				ForcePathStyle:  &trueValue,
+				AccessKeyID:     "key2",
+				SecretAccessKey: "secret2",
+			},
+		},
+		ExternalDNS: &config.ExternalDNSConfig{
+			Enabled:  &trueValue,
+			Zone:     "nomad2.example.com",
+			TTL:      20 * time.Second,
+			Interval: 2 * time.Minute,
+			CoreDNS: &config.ExternalDNSCoreDNSConfig{
+				EtcdAddress: "http://127.0.0.2:2379",
+				PathPrefix:  "/dns",
+			},
+		},
+		Watchdog: &WatchdogConfig{
+			Interval:         10 * time.Second,
+			SystemdNotify:    true,
+			ExitOnLostQuorum: 2 * time.Minute,
+		},
 		Plugins: []*config.PluginConfig{
 			{
 				Name: "docker",
@@ -897,12 +963,14 @@ func TestMergeServerJoin(t *testing.T) {
 		startJoin := []string{"127.0.0.1", "127.0.0.2"}
 		retryMaxAttempts := 1
 		retryInterval := time.Duration(0)
+		retryMaxInterval := 2 * time.Minute
 
 		a := &ServerJoin{
 			RetryJoin:        retryJoin,
 			StartJoin:        startJoin,
 			RetryMaxAttempts: retryMaxAttempts,
 			RetryInterval:    time.Duration(retryInterval),
+			RetryMaxInterval: retryMaxInterval,
 		}
 		b := &ServerJoin{}
 
@@ -911,12 +979,14 @@ func TestMergeServerJoin(t *testing.T) {
 		require.Equal(result.StartJoin, startJoin)
 		require.Equal(result.RetryMaxAttempts, retryMaxAttempts)
 		require.Equal(result.RetryInterval, retryInterval)
+		require.Equal(result.RetryMaxInterval, retryMaxInterval)
 	}
 	{
 		retryJoin := []string{"127.0.0.1", "127.0.0.2"}
 		startJoin := []string{"127.0.0.1", "127.0.0.2"}
 		retryMaxAttempts := 1
 		retryInterval := time.Duration(0)
+		retryMaxInterval := 2 * time.Minute
 
 		a := &ServerJoin{}
 		b := &ServerJoin{
@@ -924,6 +994,7 @@ func TestMergeServerJoin(t *testing.T) {
 			StartJoin:        startJoin,
 			RetryMaxAttempts: retryMaxAttempts,
 			RetryInterval:    time.Duration(retryInterval),
+			RetryMaxInterval: retryMaxInterval,
 		}
 
 		result := a.Merge(b)
@@ -931,6 +1002,7 @@ func TestMergeServerJoin(t *testing.T) {
 		require.Equal(result.StartJoin, startJoin)
 		require.Equal(result.RetryMaxAttempts, retryMaxAttempts)
 		require.Equal(result.RetryInterval, retryInterval)
+		require.Equal(result.RetryMaxInterval, retryMaxInterval)
 	}
 	{
 		retryJoin := []string{"127.0.0.1", "127.0.0.2"}
@@ -1059,3 +1131,25 @@ func TestTelemetry_Parse(t *testing.T) {
 	require.Exactly([]string{"+nomad.raft"}, config.Telemetry.PrefixFilter)
 	require.True(config.Telemetry.DisableDispatchedJobSummaryMetrics)
 }
+
+func TestSnapshotAgent_Parse_RetainZero(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "nomad")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	file1 := filepath.Join(dir, "config1.hcl")
+	err = ioutil.WriteFile(file1, []byte(`snapshot_agent {
+		enabled    = true
+		retain     = 0
+		local_path = "/tmp/snapshots"
+	}`), 0600)
+	require.NoError(err)
+
+	config, err := LoadConfig(dir)
+	require.NoError(err)
+
+	// Zero keeps all snapshots and must survive merging with the defaults
+	config = DefaultConfig().Merge(config)
+	require.Equal(0, *config.SnapshotAgent.Retain)
+}
@@ -14,6 +14,10 @@ import (
 const (
 	// defaultPollFreq is the default rate to poll the Consul Checks API
 	defaultPollFreq = 900 * time.Millisecond
+
+	// backoffExpiry is how long after its last restart the backoff of a task
+	// whose checks are no longer watched is forgotten
+	backoffExpiry = 1 * time.Hour
 )
 
 // ChecksAPI is the part of the Consul API the checkWatcher requires.
@@ -40,9 +44,19 @@ type checkRestart struct {
 	interval       time.Duration
 	timeLimit      time.Duration
 	ignoreWarnings bool
+	delay          time.Duration
+	maxDelay       time.Duration
 
 	// Mutable fields
 
+	// backoff is shared by the checks of the task to delay its consecutive
+	// restarts. It is nil if the check has no delay.
+	backoff *checkBackoff
+
+	// healthySince is the time the check last became healthy, used to reset
+	// the backoff of the task.
+	healthySince time.Time
+
 	// unhealthyState is the time a check first went unhealthy. Set to the
 	// zero value if the check passes before timeLimit.
 	unhealthyState time.Time
@@ -60,12 +74,24 @@ type checkRestart struct {
 //
 // Returns true if a restart was triggered in which case this check should be
 // removed (checks are added on task startup).
-func (c *checkRestart) apply(ctx context.Context, now time.Time, status string) bool {
+func (c *checkRestart) apply(ctx context.Context, now time.Time, status, output string) bool {
 	healthy := func() {
 		if !c.unhealthyState.IsZero() {
 			c.logger.Debug("canceling restart because check became healthy")
 			c.unhealthyState = time.Time{}
 		}
+
+		// Reset the backoff once the check stays healthy for as long as
+		// the delay of the next restart
+		if c.backoff != nil && c.backoff.delay > 0 {
+			if c.healthySince.IsZero() {
+				c.healthySince = now
+			}
+			if now.Sub(c.healthySince) >= c.backoff.delay {
+				c.logger.Debug("resetting restart delay because check stayed healthy")
+				c.backoff.delay = 0
+			}
+		}
 	}
 	switch status {
 	case api.HealthCritical:
@@ -81,6 +107,8 @@ func (c *checkRestart) apply(ctx context.Context, now time.Time, status string)
 		return false
 	}
 
+	c.healthySince = time.Time{}
+
 	if now.Before(c.graceUntil) {
 		// In grace period, exit
 		return false
@@ -105,18 +133,54 @@ func (c *checkRestart) apply(ctx context.Context, now time.Time, status string)
 		// Tell TaskRunner to restart due to failure
 		const failure = true
 		reason := fmt.Sprintf("healthcheck: check %q unhealthy", c.checkName)
-		event := structs.NewTaskEvent(structs.TaskRestartSignal).SetRestartReason(reason)
+		event := structs.NewTaskEvent(structs.TaskUnhealthyCheck).
+			SetRestartReason(reason).
+			SetCheckOutput(c.checkName, output)
 		err := c.task.Restart(ctx, event, failure)
 		if err != nil {
 			// Error restarting
 			return false
 		}
+
+		if c.backoff != nil {
+			c.backoff.next(now, c.delay, c.maxDelay)
+			c.logger.Debug("delaying the next restart due to unhealthy checks", "delay", c.backoff.delay)
+		}
 		return true
 	}
 
 	return false
 }
 
+// checkBackoff delays the consecutive restarts of a task due to unhealthy
+// checks exponentially, so that flapping tasks aren't restarted in a tight
+// loop.
+type checkBackoff struct {
+	// delay is added to the grace period of the checks of the task after a
+	// restart. It is zero until the checks first restart the task.
+	delay time.Duration
+
+	// restartedAt is when the checks last restarted the task
+	restartedAt time.Time
+}
+
+// next doubles the delay after a restart, starting from the initial delay
+// and up to the maximum delay. The delay is constant if there is no maximum.
+func (b *checkBackoff) next(now time.Time, initial, max time.Duration) {
+	b.restartedAt = now
+	if b.delay == 0 {
+		b.delay = initial
+		return
+	}
+	b.delay *= 2
+	if max < initial {
+		max = initial
+	}
+	if b.delay > max {
+		b.delay = max
+	}
+}
+
 // checkWatchUpdates add or remove checks from the watcher
 type checkWatchUpdate struct {
 	checkID      string
@@ -166,6 +230,9 @@ func (w *checkWatcher) Run(ctx context.Context) {
 	// map of check IDs to their metadata
 	checks := map[string]*checkRestart{}
 
+	// map of task keys to the backoff of their restarts
+	backoffs := map[string]*checkBackoff{}
+
 	// timer for check polling
 	checkTimer := time.NewTimer(0)
 	defer checkTimer.Stop() // ensure timer is never leaked
@@ -196,7 +263,17 @@ func (w *checkWatcher) Run(ctx context.Context) {
 				continue
 			}
 
-			// Add/update a check
+			// Add/update a check, delaying it if the task was restarted
+			// by its checks
+			if c := update.checkRestart; c.delay > 0 {
+				b, ok := backoffs[c.taskKey]
+				if !ok {
+					b = &checkBackoff{}
+					backoffs[c.taskKey] = b
+				}
+				c.backoff = b
+				c.graceUntil = c.graceUntil.Add(b.delay)
+			}
 			checks[update.checkID] = update.checkRestart
 			w.logger.Debug("watching check", "alloc_id", update.checkRestart.allocID,
 				"task", update.checkRestart.taskName, "check", update.checkRestart.checkName)
@@ -254,7 +331,7 @@ func (w *checkWatcher) Run(ctx context.Context) {
 					continue
 				}
 
-				restarted := check.apply(ctx, now, result.Status)
+				restarted := check.apply(ctx, now, result.Status, result.Output)
 				if restarted {
 					// Checks are registered+watched on
 					// startup, so it's safe to remove them
@@ -273,6 +350,21 @@ func (w *checkWatcher) Run(ctx context.Context) {
 					}
 				}
 			}
+
+			// Forget the backoffs of the tasks that are no longer watched,
+			// unless they are about to be watched again after a restart
+			watched := make(map[string]struct{}, len(checks))
+			for _, check := range checks {
+				watched[check.taskKey] = struct{}{}
+			}
+			for key, b := range backoffs {
+				if _, ok := watched[key]; ok {
+					continue
+				}
+				if b.delay == 0 || now.Sub(b.restartedAt) > b.delay+backoffExpiry {
+					delete(backoffs, key)
+				}
+			}
 		}
 	}
 }
@@ -296,6 +388,8 @@ func (w *checkWatcher) Watch(allocID, taskName, checkID string, check *structs.S
 		graceUntil:     time.Now().Add(check.CheckRestart.Grace),
 		timeLimit:      check.Interval * time.Duration(check.CheckRestart.Limit-1),
 		ignoreWarnings: check.CheckRestart.IgnoreWarnings,
+		delay:          check.CheckRestart.Delay,
+		maxDelay:       check.CheckRestart.MaxDelay,
 		logger:         w.logger.With("alloc_id", allocID, "task", taskName, "check", check.Name),
 	}
 
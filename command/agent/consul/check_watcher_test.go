@@ -17,6 +17,7 @@ type checkRestartRecord struct {
 	timestamp time.Time
 	source    string
 	reason    string
+	details   map[string]string
 	failure   bool
 }
 
@@ -57,6 +58,7 @@ func (c *fakeCheckRestarter) Restart(ctx context.Context, event *structs.TaskEve
 		timestamp: time.Now(),
 		source:    event.Type,
 		reason:    event.DisplayMessage,
+		details:   event.Details,
 		failure:   failure,
 	}
 	c.restarts = append(c.restarts, restart)
@@ -113,6 +115,7 @@ func (c *fakeChecksAPI) Checks() (map[string]*api.AgentCheck, error) {
 				CheckID: k,
 				Name:    k,
 				Status:  v.status,
+				Output:  fmt.Sprintf("check is %s", v.status),
 			}
 		}
 	}
@@ -327,3 +330,77 @@ func TestCheckWatcher_MultipleChecks(t *testing.T) {
 		t.Errorf("expected check 3 to not be restarted but found %d:\n%s", n, restarter3)
 	}
 }
+
+// TestCheckWatcher_Event asserts restarts emit an unhealthy check event with
+// the output of the check.
+func TestCheckWatcher_Event(t *testing.T) {
+	t.Parallel()
+
+	fakeAPI, cw := testWatcherSetup(t)
+
+	check := testCheck()
+	check.CheckRestart.Limit = 1
+	check.CheckRestart.Grace = 0
+	restarter := newFakeCheckRestarter(cw, "testalloc1", "testtask1", "testcheck1", check)
+	cw.Watch("testalloc1", "testtask1", "testcheck1", check, restarter)
+
+	fakeAPI.add("testcheck1", "critical", time.Time{})
+
+	// Run
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	cw.Run(ctx)
+
+	if len(restarter.restarts) == 0 {
+		t.Fatalf("expected check to be restarted")
+	}
+	r := restarter.restarts[0]
+	if r.source != structs.TaskUnhealthyCheck {
+		t.Errorf("expected event type %q but found %q", structs.TaskUnhealthyCheck, r.source)
+	}
+	if name := r.details["check_name"]; name != check.Name {
+		t.Errorf("expected check name %q but found %q", check.Name, name)
+	}
+	if output := r.details["check_output"]; output != "check is critical" {
+		t.Errorf("expected check output %q but found %q", "check is critical", output)
+	}
+}
+
+// TestCheckWatcher_Backoff asserts the delay between restarts grows up to
+// max_delay.
+func TestCheckWatcher_Backoff(t *testing.T) {
+	t.Parallel()
+
+	fakeAPI, cw := testWatcherSetup(t)
+
+	check := testCheck()
+	check.CheckRestart.Limit = 1
+	check.CheckRestart.Grace = 0
+	check.CheckRestart.Delay = 100 * time.Millisecond
+	check.CheckRestart.MaxDelay = 200 * time.Millisecond
+	restarter := newFakeCheckRestarter(cw, "testalloc1", "testtask1", "testcheck1", check)
+	cw.Watch("testalloc1", "testtask1", "testcheck1", check, restarter)
+
+	fakeAPI.add("testcheck1", "critical", time.Time{})
+
+	// Run
+	ctx, cancel := context.WithTimeout(context.Background(), 700*time.Millisecond)
+	defer cancel()
+	cw.Run(ctx)
+
+	// Without a delay the check would be restarted on every poll
+	if n := len(restarter.restarts); n < 3 || n > 5 {
+		t.Fatalf("expected check to be restarted 3 to 5 times but found %d:\n%s", n, restarter)
+	}
+
+	// The delays double from delay up to max_delay
+	for i := 1; i < len(restarter.restarts); i++ {
+		expected := check.CheckRestart.Delay << uint(i-1)
+		if expected > check.CheckRestart.MaxDelay {
+			expected = check.CheckRestart.MaxDelay
+		}
+		if d := restarter.restarts[i].timestamp.Sub(restarter.restarts[i-1].timestamp); d < expected {
+			t.Errorf("expected restart %d to be delayed %s but found %s:\n%s", i, expected, d, restarter)
+		}
+	}
+}
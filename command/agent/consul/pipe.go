@@ -0,0 +1,29 @@
+package consul
+
+import (
+	"fmt"
+	"time"
+)
+
+// pipeExec is a ScriptExecutor checking that a Windows named pipe accepts
+// connections. It runs pipe checks from the client, which heartbeats their TTL
+// like script checks.
+type pipeExec struct {
+	path string
+}
+
+// newPipeExec returns a pipeExec for the named pipe at path.
+func newPipeExec(path string) *pipeExec {
+	return &pipeExec{path: path}
+}
+
+// Exec connects to the named pipe, ignoring the command and its arguments. The
+// check is critical if the connection fails.
+func (p *pipeExec) Exec(timeout time.Duration, _ string, _ []string) ([]byte, int, error) {
+	conn, err := dialPipe(p.path, timeout)
+	if err != nil {
+		return []byte(fmt.Sprintf("failed to connect to %s: %v", p.path, err)), 2, nil
+	}
+	conn.Close()
+	return []byte(fmt.Sprintf("connected to %s", p.path)), 0, nil
+}
@@ -0,0 +1,15 @@
+// +build !windows
+
+package consul
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialPipe connects to the named pipe at path. Named pipes are only supported
+// on Windows.
+func dialPipe(path string, timeout time.Duration) (net.Conn, error) {
+	return nil, fmt.Errorf("named pipes are only supported on Windows")
+}
@@ -0,0 +1,19 @@
+// +build !windows
+
+package consul
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeExec_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	out, code, err := newPipeExec(`\\.\pipe\app`).Exec(time.Second, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, code)
+	require.Equal(t, `failed to connect to \\.\pipe\app: named pipes are only supported on Windows`, string(out))
+}
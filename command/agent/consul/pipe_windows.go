@@ -0,0 +1,13 @@
+package consul
+
+import (
+	"net"
+	"time"
+
+	winio "github.com/Microsoft/go-winio"
+)
+
+// dialPipe connects to the named pipe at path.
+func dialPipe(path string, timeout time.Duration) (net.Conn, error) {
+	return winio.DialPipe(path, &timeout)
+}
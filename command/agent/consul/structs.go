@@ -9,6 +9,10 @@ import (
 type TaskServices struct {
 	AllocID string
 
+	// Namespace and JobID of the task's allocation
+	Namespace string
+	JobID     string
+
 	// Name of the task
 	Name string
 
@@ -35,6 +39,8 @@ type TaskServices struct {
 func NewTaskServices(alloc *structs.Allocation, task *structs.Task, restarter TaskRestarter, exec interfaces.ScriptExecutor, net *drivers.DriverNetwork) *TaskServices {
 	ts := TaskServices{
 		AllocID:       alloc.ID,
+		Namespace:     alloc.Namespace,
+		JobID:         alloc.JobID,
 		Name:          task.Name,
 		Restarter:     restarter,
 		Services:      task.Services,
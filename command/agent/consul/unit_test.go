@@ -1545,6 +1545,34 @@ func TestCreateCheckReg_GRPC(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestCreateCheckReg_Pipe(t *testing.T) {
+	t.Parallel()
+	check := &structs.ServiceCheck{
+		Name:     "name",
+		Type:     "pipe",
+		Path:     `\\.\pipe\app`,
+		Timeout:  time.Second,
+		Interval: time.Minute,
+	}
+
+	serviceID := "testService"
+	checkID := check.Hash(serviceID)
+
+	expected := &api.AgentCheckRegistration{
+		ID:        checkID,
+		Name:      "name",
+		ServiceID: serviceID,
+		AgentServiceCheck: api.AgentServiceCheck{
+			Timeout: "1s",
+			TTL:     (time.Minute + ttlCheckBuffer).String(),
+		},
+	}
+
+	actual, err := createCheckReg(serviceID, checkID, check, "", 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
 // TestGetAddress asserts Nomad uses the correct ip and port for services and
 // checks depending on port labels, driver networks, and address mode.
 func TestGetAddress(t *testing.T) {
@@ -1705,8 +1733,8 @@ func TestGetAddress(t *testing.T) {
 				i++
 			}
 
-			// Run getAddress
-			ip, port, err := getAddress(tc.Mode, tc.PortLabel, networks, tc.Driver)
+			// Run GetAddress
+			ip, port, err := GetAddress(tc.Mode, tc.PortLabel, networks, tc.Driver)
 
 			// Assert the results
 			assert.Equal(t, tc.ExpectedIP, ip, "IP mismatch")
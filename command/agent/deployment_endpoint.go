@@ -41,6 +41,9 @@ func (s *HTTPServer) DeploymentSpecificRequest(resp http.ResponseWriter, req *ht
 	case strings.HasPrefix(path, "pause/"):
 		deploymentID := strings.TrimPrefix(path, "pause/")
 		return s.deploymentPause(resp, req, deploymentID)
+	case strings.HasPrefix(path, "rollback/"):
+		deploymentID := strings.TrimPrefix(path, "rollback/")
+		return s.deploymentRollback(resp, req, deploymentID)
 	case strings.HasPrefix(path, "promote/"):
 		deploymentID := strings.TrimPrefix(path, "promote/")
 		return s.deploymentPromote(resp, req, deploymentID)
@@ -70,6 +73,23 @@ func (s *HTTPServer) deploymentFail(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) deploymentRollback(resp http.ResponseWriter, req *http.Request, deploymentID string) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.DeploymentRollbackRequest{
+		DeploymentID: deploymentID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.DeploymentUpdateResponse
+	if err := s.agent.RPC("Deployment.Rollback", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) deploymentPause(resp http.ResponseWriter, req *http.Request, deploymentID string) (interface{}, error) {
 	if req.Method != "PUT" && req.Method != "POST" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/pkg/ioutils"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+// EventStream streams the events of the state store as newline delimited
+// JSON, with an empty object sent as heartbeat on idle streams.
+func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.EventStreamRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	query := req.URL.Query()
+	if indexStr := query.Get("index"); indexStr != "" {
+		index, err := strconv.ParseUint(indexStr, 10, 64)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse index: %v", err))
+		}
+		args.Index = index
+	}
+
+	topics, err := parseEventTopics(query["topic"])
+	if err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	args.Topics = topics
+
+	// Servers stream the events of their own state store while client only
+	// agents forward the stream to a server
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if srv := s.agent.Server(); srv != nil {
+		handler, handlerErr = srv.StreamingRpcHandler("Event.Stream")
+	} else if client := s.agent.Client(); client != nil {
+		handler, handlerErr = client.RemoteStreamingRpcHandler("Event.Stream")
+	} else {
+		handlerErr = errors.New("agent is neither a server nor a client")
+	}
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the http response
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(req.Context())
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	// Create an output that gets flushed on every write
+	output := ioutils.NewWriteFlusher(resp)
+
+	// Create a channel that decodes the results
+	errCh := make(chan error)
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		for {
+			var res cstructs.StreamErrWrapper
+			if err := decoder.Decode(&res); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+			decoder.Reset(httpPipe)
+
+			if err := res.Error; err != nil {
+				if err.Code != nil {
+					errCh <- CodedError(int(*err.Code), err.Error())
+				} else {
+					errCh <- errors.New(err.Error())
+				}
+				return
+			}
+
+			if _, err := io.Copy(output, bytes.NewReader(res.Payload)); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+		}
+	}()
+
+	handler(handlerPipe)
+	cancel()
+	err = <-errCh
+
+	// Ignore EOF and ErrClosedPipe errors.
+	if err != nil &&
+		(err == io.EOF ||
+			strings.Contains(err.Error(), "closed") ||
+			strings.Contains(err.Error(), "EOF")) {
+		err = nil
+	}
+	return nil, err
+}
+
+// parseEventTopics parses topic query parameters of the form "Topic:key" or
+// "Topic", which subscribes to every event of the topic.
+func parseEventTopics(params []string) (map[structs.Topic][]string, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	topics := make(map[structs.Topic][]string, len(params))
+	for _, param := range params {
+		parts := strings.SplitN(param, ":", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid topic %q", param)
+		}
+
+		key := "*"
+		if len(parts) == 2 {
+			key = parts[1]
+		}
+		topic := structs.Topic(parts[0])
+		topics[topic] = append(topics[topic], key)
+	}
+	return topics, nil
+}
@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_EventStream(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		job := mock.Job()
+		topics := map[api.Topic][]string{api.TopicJob: {job.ID}}
+		eventsCh, err := s.Client().EventStream().Stream(ctx, topics, 0, nil)
+		require.NoError(err)
+
+		// Register the job
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(s.Agent.RPC("Job.Register", &args, &resp))
+
+		select {
+		case events := <-eventsCh:
+			require.NoError(events.Err)
+			require.Equal(resp.JobModifyIndex, events.Index)
+			require.Len(events.Events, 1)
+			require.Equal(structs.TypeJobRegistered, events.Events[0].Type)
+
+			out, err := events.Events[0].Job()
+			require.NoError(err)
+			require.Equal(job.ID, *out.ID)
+			require.Equal(job.TaskGroups[0].Count, *out.TaskGroups[0].Count)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for event")
+		}
+	})
+}
+
+func TestHTTP_EventStream_Topics(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	topics, err := parseEventTopics([]string{"Job:web", "Job:db", "Node", "Allocation:*"})
+	require.NoError(err)
+	require.Equal(map[structs.Topic][]string{
+		structs.TopicJob:        {"web", "db"},
+		structs.TopicNode:       {"*"},
+		structs.TopicAllocation: {"*"},
+	}, topics)
+
+	topics, err = parseEventTopics(nil)
+	require.NoError(err)
+	require.Nil(topics)
+
+	_, err = parseEventTopics([]string{":web"})
+	require.Error(err)
+}
@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) FaultRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.faultRead(resp, req)
+	case "PUT", "POST":
+		return s.faultApply(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) faultRead(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Get the requested Node ID
+	requestedNode := req.URL.Query().Get("node_id")
+
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: requestedNode,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.FaultResponse
+	if err := s.clientNodeRPC(requestedNode, "Fault.Read", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &api.FaultResponse{
+		HeartbeatDelay:  reply.HeartbeatDelay,
+		FailAllocations: reply.FailAllocations,
+	}, nil
+}
+
+func (s *HTTPServer) faultApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var applyReq api.FaultApplyRequest
+	if err := decodeBody(req, &applyReq); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if applyReq.HeartbeatDelay == nil && applyReq.FailAllocations == nil {
+		return nil, CodedError(400, "missing faults to apply")
+	}
+
+	// Build the request and parse the ACL token
+	args := cstructs.FaultApplyRequest{
+		NodeID:          applyReq.NodeID,
+		HeartbeatDelay:  applyReq.HeartbeatDelay,
+		FailAllocations: applyReq.FailAllocations,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.FaultResponse
+	if err := s.clientNodeRPC(applyReq.NodeID, "Fault.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &api.FaultResponse{
+		HeartbeatDelay:  reply.HeartbeatDelay,
+		FailAllocations: reply.FailAllocations,
+	}, nil
+}
+
+func (s *HTTPServer) FaultKillTaskRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var killReq api.FaultKillTaskRequest
+	if err := decodeBody(req, &killReq); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if killReq.JobID == "" {
+		return nil, CodedError(400, "missing job ID")
+	}
+
+	// Build the request and parse the ACL token
+	args := cstructs.FaultKillTaskRequest{
+		NodeID: killReq.NodeID,
+		JobID:  killReq.JobID,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.FaultKillTaskResponse
+	if err := s.clientNodeRPC(killReq.NodeID, "Fault.KillTask", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &api.FaultKillTaskResponse{
+		AllocID: reply.AllocID,
+		Task:    reply.Task,
+	}, nil
+}
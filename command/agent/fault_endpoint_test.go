@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Fault(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, func(c *Config) {
+		c.Client.EnableFaultInjection = true
+	}, func(s *TestAgent) {
+		// Applying without faults fails
+		{
+			req, err := http.NewRequest("PUT", "/v1/client/fault", encodeReq(&api.FaultApplyRequest{}))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.FaultRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), "missing faults")
+		}
+
+		// Apply faults to the local node
+		{
+			args := &api.FaultApplyRequest{
+				HeartbeatDelay:  helper.TimeToPtr(5 * time.Second),
+				FailAllocations: helper.IntToPtr(2),
+			}
+			req, err := http.NewRequest("PUT", "/v1/client/fault", encodeReq(args))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.FaultRequest(respW, req)
+			require.NoError(err)
+
+			resp := obj.(*api.FaultResponse)
+			require.Equal(5*time.Second, resp.HeartbeatDelay)
+			require.Equal(2, resp.FailAllocations)
+		}
+
+		// Read the faults of the local node
+		{
+			req, err := http.NewRequest("GET", "/v1/client/fault", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.FaultRequest(respW, req)
+			require.NoError(err)
+
+			resp := obj.(*api.FaultResponse)
+			require.Equal(5*time.Second, resp.HeartbeatDelay)
+			require.Equal(2, resp.FailAllocations)
+		}
+
+		// Killing a task requires a job
+		{
+			req, err := http.NewRequest("PUT", "/v1/client/fault/kill-task", encodeReq(&api.FaultKillTaskRequest{}))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.FaultKillTaskRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), "missing job ID")
+		}
+
+		// Unsupported methods are rejected
+		{
+			req, err := http.NewRequest("DELETE", "/v1/client/fault", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.FaultRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), ErrInvalidMethod)
+		}
+	})
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 
@@ -20,7 +22,7 @@ var (
 	allocIDNotPresentErr  = fmt.Errorf("must provide a valid alloc id")
 	fileNameNotPresentErr = fmt.Errorf("must provide a file name")
 	taskNotPresentErr     = fmt.Errorf("must provide task name")
-	logTypeNotPresentErr  = fmt.Errorf("must provide log type (stdout/stderr)")
+	logTypeNotPresentErr  = fmt.Errorf("must provide log type (stdout/stderr/eventlog)")
 	clientNotRunning      = fmt.Errorf("node is not running a Nomad Client")
 	invalidOrigin         = fmt.Errorf("origin must be start or end")
 )
@@ -36,10 +38,14 @@ func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (int
 		return s.FileReadAtRequest(resp, req)
 	case strings.HasPrefix(path, "cat/"):
 		return s.FileCatRequest(resp, req)
+	case strings.HasPrefix(path, "write/"):
+		return s.FileWriteRequest(resp, req)
 	case strings.HasPrefix(path, "stream/"):
 		return s.Stream(resp, req)
 	case strings.HasPrefix(path, "logs/"):
 		return s.Logs(resp, req)
+	case strings.HasPrefix(path, "archive/"):
+		return s.Archive(resp, req)
 	default:
 		return nil, CodedError(404, ErrInvalidMethod)
 	}
@@ -191,6 +197,73 @@ func (s *HTTPServer) FileCatRequest(resp http.ResponseWriter, req *http.Request)
 	return s.fsStreamImpl(resp, req, "FileSystem.Stream", fsReq, fsReq.AllocID)
 }
 
+// FileWriteRequest writes the request body to a file in an allocation
+// directory. The parameters are:
+// * path: path to the file to write.
+// * mode: The octal mode of the file if it is created, defaults to 0644.
+func (s *HTTPServer) FileWriteRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var allocID, path string
+	var mode uint64
+
+	q := req.URL.Query()
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/write/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = q.Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Parse the mode
+	if modeStr := q.Get("mode"); modeStr != "" {
+		var err error
+		if mode, err = strconv.ParseUint(modeStr, 8, 32); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("error parsing mode: %v", err))
+		}
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("error reading body: %v", err))
+	}
+
+	// Create the request
+	args := &cstructs.FsWriteRequest{
+		AllocID:  allocID,
+		Path:     path,
+		Data:     data,
+		FileMode: os.FileMode(mode),
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Make the RPC
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+
+	var reply structs.GenericResponse
+	var rpcErr error
+	if localClient {
+		rpcErr = s.agent.Client().ClientRPC("FileSystem.Write", &args, &reply)
+	} else if remoteClient {
+		rpcErr = s.agent.Client().RPC("FileSystem.Write", &args, &reply)
+	} else if localServer {
+		rpcErr = s.agent.Server().RPC("FileSystem.Write", &args, &reply)
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+
+		return nil, rpcErr
+	}
+
+	return nil, nil
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
@@ -242,9 +315,47 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 	return s.fsStreamImpl(resp, req, "FileSystem.Stream", fsReq, fsReq.AllocID)
 }
 
+// Archive streams a tar archive of a directory. The parameters are:
+// * path: path to the directory to archive, defaults to the alloc dir.
+// * checksum: A boolean of whether to append a manifest of the SHA-256
+//             checksums of the files to the archive.
+func (s *HTTPServer) Archive(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var allocID, path string
+	var checksum bool
+	var err error
+
+	q := req.URL.Query()
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/archive/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+
+	if path = q.Get("path"); path == "" {
+		path = "/"
+	}
+
+	if checksumStr := q.Get("checksum"); checksumStr != "" {
+		if checksum, err = strconv.ParseBool(checksumStr); err != nil {
+			return nil, fmt.Errorf("Failed to parse checksum field to boolean: %v", err)
+		}
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsArchiveRequest{
+		AllocID:  allocID,
+		Path:     path,
+		Checksum: checksum,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	resp.Header().Set("Content-Type", "application/x-tar")
+
+	// Make the request
+	return s.fsStreamImpl(resp, req, "FileSystem.Archive", fsReq, fsReq.AllocID)
+}
+
 // Logs streams the content of a log blocking on EOF. The parameters are:
 // * task: task name to stream logs for.
-// * type: stdout/stderr to stream.
+// * type: stdout/stderr/eventlog to stream.
 // * follow: A boolean of whether to follow the logs.
 // * offset: The offset to start streaming data at, defaults to zero.
 // * origin: Either "start" or "end" and defines from where the offset is
@@ -277,7 +388,7 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 
 	logType = q.Get("type")
 	switch logType {
-	case "stdout", "stderr":
+	case "stdout", "stderr", "eventlog":
 	default:
 		return nil, logTypeNotPresentErr
 	}
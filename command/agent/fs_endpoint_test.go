@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"archive/tar"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -184,6 +185,42 @@ func TestHTTP_FS_Cat_MissingParams(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Write_MissingParams(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/client/fs/write/foo?path=foo", nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.FileWriteRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+
+		req, err = http.NewRequest("PUT", "/v1/client/fs/write/", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.FileWriteRequest(respW, req)
+		require.EqualError(err, allocIDNotPresentErr.Error())
+
+		req, err = http.NewRequest("PUT", "/v1/client/fs/write/foo", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.FileWriteRequest(respW, req)
+		require.EqualError(err, fileNameNotPresentErr.Error())
+
+		req, err = http.NewRequest("PUT", "/v1/client/fs/write/foo?path=foo&mode=999", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.FileWriteRequest(respW, req)
+		require.NotNil(err)
+		require.Equal(400, err.(HTTPCodedError).Code())
+	})
+}
+
 func TestHTTP_FS_Stream_MissingParams(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -254,6 +291,29 @@ func TestHTTP_FS_Logs_MissingParams(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Archive_MissingParams(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// AllocID Not Present
+		req, err := http.NewRequest("GET", "/v1/client/fs/archive/", nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.Archive(respW, req)
+		require.EqualError(err, allocIDNotPresentErr.Error())
+
+		// Bad checksum
+		req, err = http.NewRequest("GET", "/v1/client/fs/archive/foo?checksum=maybe", nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.Archive(respW, req)
+		require.Error(err)
+		require.Contains(err.Error(), "checksum")
+	})
+}
+
 func TestHTTP_FS_List(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -341,6 +401,70 @@ func TestHTTP_FS_Cat(t *testing.T) {
 	})
 }
 
+func TestHTTP_FS_Archive(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), nil)
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		path := fmt.Sprintf("/v1/client/fs/archive/%s?path=alloc/logs", a.ID)
+
+		req, err := http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.Archive(respW, req)
+		require.Nil(err)
+		require.Equal("application/x-tar", respW.Header().Get("Content-Type"))
+
+		found := false
+		tr := tar.NewReader(respW.Result().Body)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.Nil(err)
+			if hdr.Name != "web.stdout.0" {
+				continue
+			}
+
+			output, err := ioutil.ReadAll(tr)
+			require.Nil(err)
+			require.EqualValues(defaultLoggerMockDriverStdout, output)
+			found = true
+		}
+		require.True(found)
+	})
+}
+
+func TestHTTP_FS_Write(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), nil)
+		addAllocToClient(s, a, terminalClientAlloc)
+
+		path := fmt.Sprintf("/v1/client/fs/write/%s?path=alloc/data/foo&mode=0600", a.ID)
+		req, err := http.NewRequest("PUT", path, strings.NewReader("hello"))
+		require.Nil(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.FileWriteRequest(respW, req)
+		require.Nil(err)
+
+		path = fmt.Sprintf("/v1/client/fs/cat/%s?path=alloc/data/foo", a.ID)
+		req, err = http.NewRequest("GET", path, nil)
+		require.Nil(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.FileCatRequest(respW, req)
+		require.Nil(err)
+
+		output, err := ioutil.ReadAll(respW.Result().Body)
+		require.Nil(err)
+		require.EqualValues("hello", output)
+	})
+}
+
 func TestHTTP_FS_Stream(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
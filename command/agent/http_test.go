@@ -266,6 +266,28 @@ func TestTokenNotFound(t *testing.T) {
 	assert.Equal(t, resp.Code, 403)
 }
 
+func TestRateLimitErrors(t *testing.T) {
+	s := makeHTTPServer(t, nil)
+	defer s.Shutdown()
+
+	cases := map[error]int{
+		structs.ErrClientRelayDisabled:    403,
+		structs.ErrClientRelayRateLimited: 429,
+		structs.ErrWriteRateLimited:       429,
+	}
+	for relayErr, code := range cases {
+		resp := httptest.NewRecorder()
+		handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+			return nil, fmt.Errorf("rpc error: %v", relayErr)
+		}
+
+		req, _ := http.NewRequest("GET", "/v1/client/fs/ls/foo", nil)
+		s.Server.wrap(handler)(resp, req)
+		assert.Equal(t, code, resp.Code)
+		assert.Equal(t, relayErr.Error(), resp.Body.String())
+	}
+}
+
 func TestParseWait(t *testing.T) {
 	t.Parallel()
 	resp := httptest.NewRecorder()
@@ -329,6 +351,50 @@ func TestParseWait_InvalidIndex(t *testing.T) {
 	}
 }
 
+func TestParsePagination(t *testing.T) {
+	t.Parallel()
+	resp := httptest.NewRecorder()
+	var b structs.QueryOptions
+
+	req, err := http.NewRequest("GET",
+		"/v1/jobs?per_page=10&next_token=example", nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if d := parsePagination(resp, req, &b); d {
+		t.Fatalf("unexpected done")
+	}
+
+	if b.PerPage != 10 {
+		t.Fatalf("Bad: %v", b)
+	}
+	if b.NextToken != "example" {
+		t.Fatalf("Bad: %v", b)
+	}
+}
+
+func TestParsePagination_InvalidPerPage(t *testing.T) {
+	t.Parallel()
+	for _, perPage := range []string{"foo", "-1"} {
+		resp := httptest.NewRecorder()
+		var b structs.QueryOptions
+
+		req, err := http.NewRequest("GET", "/v1/jobs?per_page="+perPage, nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		if d := parsePagination(resp, req, &b); !d {
+			t.Fatalf("expected done for %q", perPage)
+		}
+
+		if resp.Code != 400 {
+			t.Fatalf("bad code for %q: %v", perPage, resp.Code)
+		}
+	}
+}
+
 func TestParseConsistency(t *testing.T) {
 	t.Parallel()
 	var b structs.QueryOptions
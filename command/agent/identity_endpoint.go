@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"net/http"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JWKS is the JSON Web Key Set of the public keys that verify workload
+// identities.
+type JWKS struct {
+	Keys []*structs.JSONWebKey `json:"keys"`
+}
+
+// JWKSRequest returns the public keys that verify workload identities in the
+// JSON Web Key Set format, so that Vault's JWT auth method can be configured
+// with the URL of the endpoint as its jwks_url.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.GenericRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	jwks := &JWKS{Keys: out.Keys}
+	if jwks.Keys == nil {
+		jwks.Keys = make([]*structs.JSONWebKey, 0)
+	}
+	return jwks, nil
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_JWKS(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only reads are allowed
+		req, err := http.NewRequest("PUT", "/.well-known/jwks.json", nil)
+		require.Nil(err)
+		_, err = s.Server.JWKSRequest(httptest.NewRecorder(), req)
+		require.NotNil(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+
+		// The root key is created once the leader is established
+		testutil.WaitForResult(func() (bool, error) {
+			req, err := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+			if err != nil {
+				return false, err
+			}
+			obj, err := s.Server.JWKSRequest(httptest.NewRecorder(), req)
+			if err != nil {
+				return false, err
+			}
+
+			jwks := obj.(*JWKS)
+			if len(jwks.Keys) != 1 {
+				return false, fmt.Errorf("expected 1 key, got %d", len(jwks.Keys))
+			}
+			key := jwks.Keys[0]
+			if key.KeyType != "EC" || key.Curve != "P-256" || key.X == "" || key.Y == "" {
+				return false, fmt.Errorf("unexpected key: %#v", key)
+			}
+			return true, nil
+		}, func(err error) {
+			t.Fatalf("err: %v", err)
+		})
+	})
+}
@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/jobspec"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -28,6 +30,7 @@ func (s *HTTPServer) jobListRequest(resp http.ResponseWriter, req *http.Request)
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
+	parseJobListFilters(req, &args)
 
 	var out structs.JobListResponse
 	if err := s.agent.RPC("Job.List", &args, &out); err != nil {
@@ -41,6 +44,27 @@ func (s *HTTPServer) jobListRequest(resp http.ResponseWriter, req *http.Request)
 	return out.Jobs, nil
 }
 
+// parseJobListFilters parses the filters of a job list request. Meta values
+// are filtered with the "meta.<key>" query parameters and tags with the comma
+// separated "tags" query parameter.
+func parseJobListFilters(req *http.Request, args *structs.JobListRequest) {
+	query := req.URL.Query()
+	args.ParentID = query.Get("parent_id")
+	args.PayloadHash = query.Get("payload_hash")
+	args.Status = query.Get("status")
+	if tags := query.Get("tags"); tags != "" {
+		args.Tags = strings.Split(tags, ",")
+	}
+	for k := range query {
+		if key := strings.TrimPrefix(k, "meta."); key != k && key != "" {
+			if args.Meta == nil {
+				args.Meta = make(map[string]string)
+			}
+			args.Meta[key] = query.Get(k)
+		}
+	}
+}
+
 func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/v1/job/")
 	switch {
@@ -53,6 +77,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/evaluations"):
 		jobName := strings.TrimSuffix(path, "/evaluations")
 		return s.jobEvaluations(resp, req, jobName)
+	case strings.HasSuffix(path, "/reschedules"):
+		jobName := strings.TrimSuffix(path, "/reschedules")
+		return s.jobRescheduleStatus(resp, req, jobName)
 	case strings.HasSuffix(path, "/periodic/force"):
 		jobName := strings.TrimSuffix(path, "/periodic/force")
 		return s.periodicForceRequest(resp, req, jobName)
@@ -68,6 +95,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
@@ -80,6 +110,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/stable"):
 		jobName := strings.TrimSuffix(path, "/stable")
 		return s.jobStable(resp, req, jobName)
+	case strings.HasSuffix(path, "/pin-version"):
+		jobName := strings.TrimSuffix(path, "/pin-version")
+		return s.jobPinVersion(resp, req, jobName)
 	default:
 		return s.jobCRUD(resp, req, path)
 	}
@@ -176,7 +209,8 @@ func (s *HTTPServer) ValidateJobRequest(resp http.ResponseWriter, req *http.Requ
 
 	job := ApiJobToStructJob(validateRequest.Job)
 	args := structs.JobValidateRequest{
-		Job: job,
+		Job:       job,
+		Admission: validateRequest.Admission,
 		WriteRequest: structs.WriteRequest{
 			Region: validateRequest.Region,
 		},
@@ -241,6 +275,31 @@ func (s *HTTPServer) jobAllocations(resp http.ResponseWriter, req *http.Request,
 	return out.Allocations, nil
 }
 
+func (s *HTTPServer) jobRescheduleStatus(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobSpecificRequest{
+		JobID: jobName,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobRescheduleStatusResponse
+	if err := s.agent.RPC("Job.RescheduleStatus", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Allocs == nil {
+		out.Allocs = make([]*structs.AllocRescheduleStatus, 0)
+	}
+	return out.Allocs, nil
+}
+
 func (s *HTTPServer) jobEvaluations(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 	if req.Method != "GET" {
@@ -377,10 +436,13 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 	sJob := ApiJobToStructJob(args.Job)
 
 	regReq := structs.JobRegisterRequest{
-		Job:            sJob,
-		EnforceIndex:   args.EnforceIndex,
-		JobModifyIndex: args.JobModifyIndex,
-		PolicyOverride: args.PolicyOverride,
+		Job:                    sJob,
+		EnforceIndex:           args.EnforceIndex,
+		JobModifyIndex:         args.JobModifyIndex,
+		PolicyOverride:         args.PolicyOverride,
+		Signature:              args.Signature,
+		Submission:             ApiJobSubmissionToStructs(args.Submission),
+		OverrideMinimumVersion: args.OverrideMinimumVersion,
 		WriteRequest: structs.WriteRequest{
 			Region:    args.WriteRequest.Region,
 			AuthToken: args.WriteRequest.SecretID,
@@ -410,9 +472,19 @@ func (s *HTTPServer) jobDelete(resp http.ResponseWriter, req *http.Request,
 		}
 	}
 
+	var shutdownDelay time.Duration
+	if delayStr := req.URL.Query().Get("shutdown_delay"); delayStr != "" {
+		var err error
+		shutdownDelay, err = time.ParseDuration(delayStr)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse value of %q (%v) as a duration: %v", "shutdown_delay", delayStr, err)
+		}
+	}
+
 	args := structs.JobDeregisterRequest{
-		JobID: jobName,
-		Purge: purgeBool,
+		JobID:         jobName,
+		Purge:         purgeBool,
+		ShutdownDelay: shutdownDelay,
 	}
 	s.parseWriteRequest(req, &args.WriteRequest)
 
@@ -458,6 +530,38 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	versionStr := req.URL.Query().Get("version")
+	if versionStr == "" {
+		return nil, CodedError(400, "version must be specified")
+	}
+	version, err := strconv.ParseUint(versionStr, 10, 64)
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID:   jobName,
+		Version: version,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job submission not found")
+	}
+	return out.Submission, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -516,6 +620,35 @@ func (s *HTTPServer) jobStable(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobPinVersion(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var pinRequest structs.JobPinVersionRequest
+	if err := decodeBody(req, &pinRequest); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if pinRequest.JobID == "" {
+		return nil, CodedError(400, "JobID must be specified")
+	}
+	if pinRequest.JobID != jobName {
+		return nil, CodedError(400, "Job ID does not match")
+	}
+
+	s.parseWriteRequest(req, &pinRequest.WriteRequest)
+
+	var out structs.JobPinVersionResponse
+	if err := s.agent.RPC("Job.PinVersion", &pinRequest, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
 func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
 	args := structs.JobSummaryRequest{
 		JobID: name,
@@ -588,6 +721,33 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 	return jobStruct, nil
 }
 
+// JobsBulkRequest applies an operation to all the jobs of a namespace matching
+// a filter.
+func (s *HTTPServer) JobsBulkRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.JobBulkRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+	if err := args.Validate(); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	var out structs.JobBulkResponse
+	if err := s.agent.RPC("Job.Bulk", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	if out.Jobs == nil {
+		out.Jobs = make([]*structs.JobBulkResult, 0)
+	}
+	return out, nil
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -604,11 +764,20 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Datacenters: job.Datacenters,
 		Payload:     job.Payload,
 		Meta:        job.Meta,
+		Tags:        job.Tags,
 		VaultToken:  *job.VaultToken,
 		Constraints: ApiConstraintsToStructs(job.Constraints),
 		Affinities:  ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.NodePool != nil {
+		j.NodePool = *job.NodePool
+	}
+
+	if job.ConcurrencyGroup != nil {
+		j.ConcurrencyGroup = *job.ConcurrencyGroup
+	}
+
 	// COMPAT: Remove in 0.7.0. Update has been pushed into the task groups
 	if job.Update != nil {
 		j.Update = structs.UpdateStrategy{}
@@ -647,6 +816,30 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 			MetaRequired: job.ParameterizedJob.MetaRequired,
 			MetaOptional: job.ParameterizedJob.MetaOptional,
 		}
+
+		for _, c := range job.ParameterizedJob.MetaConfigs {
+			j.ParameterizedJob.MetaConfigs = append(j.ParameterizedJob.MetaConfigs, &structs.DispatchMetaConfig{
+				Key:          c.Key,
+				Type:         c.Type,
+				Enum:         c.Enum,
+				Regex:        c.Regex,
+				RequiredWith: c.RequiredWith,
+			})
+		}
+	}
+
+	if job.Prefetch != nil {
+		j.Prefetch = &structs.JobPrefetch{
+			Images:    *job.Prefetch.Images,
+			Artifacts: *job.Prefetch.Artifacts,
+		}
+	}
+
+	if job.VersionRetention != nil {
+		j.VersionRetention = &structs.JobVersionRetention{
+			Count: job.VersionRetention.Count,
+			Age:   job.VersionRetention.Age,
+		}
 	}
 
 	if l := len(job.TaskGroups); l != 0 {
@@ -661,6 +854,18 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 	return j
 }
 
+// ApiJobSubmissionToStructs converts the job file of a job registration. The
+// job version it belongs to is set by the servers.
+func ApiJobSubmissionToStructs(in *api.JobSubmission) *structs.JobSubmission {
+	if in == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:    in.Source,
+		Variables: in.Variables,
+	}
+}
+
 func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 	tg.Name = *taskGroup.Name
 	tg.Count = *taskGroup.Count
@@ -686,6 +891,14 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 		}
 	}
 
+	if taskGroup.MaxClientDisconnect != nil {
+		tg.MaxClientDisconnect = helper.TimeToPtr(*taskGroup.MaxClientDisconnect)
+	}
+
+	if taskGroup.ReconnectStrategy != nil {
+		tg.ReconnectStrategy = *taskGroup.ReconnectStrategy
+	}
+
 	if taskGroup.Migrate != nil {
 		tg.Migrate = &structs.MigrateStrategy{
 			MaxParallel:     *taskGroup.Migrate.MaxParallel,
@@ -701,6 +914,18 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 		Migrate: *taskGroup.EphemeralDisk.Migrate,
 	}
 
+	if taskGroup.SharedMemory != nil {
+		tg.SharedMemory = &structs.SharedMemory{
+			SizeMB: *taskGroup.SharedMemory.SizeMB,
+		}
+	}
+
+	if taskGroup.Consul != nil {
+		tg.Consul = &structs.Consul{
+			Cluster: *taskGroup.Consul.Cluster,
+		}
+	}
+
 	if l := len(taskGroup.Spreads); l != 0 {
 		tg.Spreads = make([]*structs.Spread, l)
 		for k, spread := range taskGroup.Spreads {
@@ -718,6 +943,14 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 			ProgressDeadline: *taskGroup.Update.ProgressDeadline,
 			AutoRevert:       *taskGroup.Update.AutoRevert,
 			Canary:           *taskGroup.Update.Canary,
+			ManualStep:       *taskGroup.Update.ManualStep,
+		}
+
+		if webhook := taskGroup.Update.ProgressWebhook; webhook != nil {
+			tg.Update.ProgressWebhook = &structs.DeploymentWebhook{}
+			if webhook.URL != nil {
+				tg.Update.ProgressWebhook.URL = *webhook.URL
+			}
 		}
 	}
 
@@ -744,6 +977,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
 	structsTask.KillSignal = apiTask.KillSignal
+	structsTask.MetricLabels = apiTask.MetricLabels
 	structsTask.Constraints = ApiConstraintsToStructs(apiTask.Constraints)
 	structsTask.Affinities = ApiAffinitiesToStructs(apiTask.Affinities)
 
@@ -756,6 +990,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 				Tags:        service.Tags,
 				CanaryTags:  service.CanaryTags,
 				AddressMode: service.AddressMode,
+				Provider:    service.Provider,
 			}
 
 			if l := len(service.Checks); l != 0 {
@@ -778,12 +1013,15 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 						Method:        check.Method,
 						GRPCService:   check.GRPCService,
 						GRPCUseTLS:    check.GRPCUseTLS,
+						Role:          check.Role,
 					}
 					if check.CheckRestart != nil {
 						structsTask.Services[i].Checks[j].CheckRestart = &structs.CheckRestart{
 							Limit:          check.CheckRestart.Limit,
 							Grace:          *check.CheckRestart.Grace,
 							IgnoreWarnings: check.CheckRestart.IgnoreWarnings,
+							Delay:          *check.CheckRestart.Delay,
+							MaxDelay:       *check.CheckRestart.MaxDelay,
 						}
 					}
 				}
@@ -794,8 +1032,12 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	structsTask.Resources = ApiResourcesToStructs(apiTask.Resources)
 
 	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
+		MaxFiles:          *apiTask.LogConfig.MaxFiles,
+		MaxFileSizeMB:     *apiTask.LogConfig.MaxFileSizeMB,
+		EventLogProviders: apiTask.LogConfig.EventLogProviders,
+	}
+	if apiTask.LogConfig.EventLogChannel != nil {
+		structsTask.LogConfig.EventLogChannel = *apiTask.LogConfig.EventLogChannel
 	}
 
 	if l := len(apiTask.Artifacts); l != 0 {
@@ -813,6 +1055,8 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	if apiTask.Vault != nil {
 		structsTask.Vault = &structs.Vault{
 			Policies:     apiTask.Vault.Policies,
+			Namespace:    *apiTask.Vault.Namespace,
+			Cluster:      *apiTask.Vault.Cluster,
 			Env:          *apiTask.Vault.Env,
 			ChangeMode:   *apiTask.Vault.ChangeMode,
 			ChangeSignal: *apiTask.Vault.ChangeSignal,
@@ -835,6 +1079,14 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 				Envvars:      *template.Envvars,
 				VaultGrace:   *template.VaultGrace,
 			}
+			if cs := template.ChangeScript; cs != nil {
+				structsTask.Templates[i].ChangeScript = &structs.ChangeScript{
+					Command:     *cs.Command,
+					Args:        cs.Args,
+					Timeout:     *cs.Timeout,
+					FailOnError: *cs.FailOnError,
+				}
+			}
 		}
 	}
 
@@ -843,6 +1095,52 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 			File: apiTask.DispatchPayload.File,
 		}
 	}
+
+	if apiTask.Identity != nil {
+		structsTask.Identity = &structs.WorkloadIdentity{
+			Env:  apiTask.Identity.Env,
+			File: apiTask.Identity.File,
+		}
+	}
+
+	if l := len(apiTask.Actions); l != 0 {
+		structsTask.Actions = make([]*structs.Action, l)
+		for i, action := range apiTask.Actions {
+			structsTask.Actions[i] = &structs.Action{
+				Name:    *action.Name,
+				Command: *action.Command,
+				Args:    action.Args,
+				Timeout: *action.Timeout,
+			}
+		}
+	}
+
+	if l := len(apiTask.Secrets); l != 0 {
+		structsTask.Secrets = make([]*structs.Secret, l)
+		for i, secret := range apiTask.Secrets {
+			structsTask.Secrets[i] = &structs.Secret{
+				Name:         *secret.Name,
+				Provider:     *secret.Provider,
+				Path:         *secret.Path,
+				Key:          *secret.Key,
+				Perms:        *secret.Perms,
+				Uid:          *secret.Uid,
+				Gid:          *secret.Gid,
+				ChangeMode:   *secret.ChangeMode,
+				ChangeSignal: *secret.ChangeSignal,
+			}
+		}
+	}
+
+	if l := len(apiTask.KillEscalation); l != 0 {
+		structsTask.KillEscalation = make([]*structs.KillStep, l)
+		for i, step := range apiTask.KillEscalation {
+			structsTask.KillEscalation[i] = &structs.KillStep{
+				Signal:  *step.Signal,
+				Timeout: *step.Timeout,
+			}
+		}
+	}
 }
 
 func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
@@ -855,6 +1153,22 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 		MemoryMB: *in.MemoryMB,
 	}
 
+	if in.Cores != nil {
+		out.Cores = *in.Cores
+	}
+
+	if in.HugePages2MB != nil {
+		out.HugePages2MB = *in.HugePages2MB
+	}
+
+	if in.HugePages1GB != nil {
+		out.HugePages1GB = *in.HugePages1GB
+	}
+
+	if in.Realtime != nil {
+		out.Realtime = *in.Realtime
+	}
+
 	// COMPAT(0.10): Only being used to issue warnings
 	if in.IOPS != nil {
 		out.IOPS = *in.IOPS
@@ -888,6 +1202,14 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 					}
 				}
 			}
+
+			if nw.DNS != nil {
+				out.Networks[i].DNS = &structs.DNSConfig{
+					Servers:  nw.DNS.Servers,
+					Searches: nw.DNS.Searches,
+					Options:  nw.DNS.Options,
+				}
+			}
 		}
 	}
 
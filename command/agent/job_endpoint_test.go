@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_JobsList(t *testing.T) {
@@ -126,6 +127,62 @@ func TestHTTP_PrefixJobsList(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobsList_Filters(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		for _, queue := range []string{"a", "b"} {
+			// Create the job
+			job := mock.Job()
+			job.Meta = map[string]string{"queue": queue}
+			job.Tags = []string{"owner:web", "queue:" + queue}
+			args := structs.JobRegisterRequest{
+				Job: job,
+				WriteRequest: structs.WriteRequest{
+					Region:    "global",
+					Namespace: structs.DefaultNamespace,
+				},
+			}
+			var resp structs.JobRegisterResponse
+			if err := s.Agent.RPC("Job.Register", &args, &resp); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+		}
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/jobs?meta.queue=b&status=pending", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.JobsRequest(respW, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// Check the job
+		j := obj.([]*structs.JobListStub)
+		if len(j) != 1 {
+			t.Fatalf("bad: %#v", j)
+		}
+
+		// Filter the jobs by tags
+		req, err = http.NewRequest("GET", "/v1/jobs?tags=owner:web,queue:a", nil)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		obj, err = s.Server.JobsRequest(httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		j = obj.([]*structs.JobListStub)
+		if len(j) != 1 || j[0].Tags[1] != "queue:a" {
+			t.Fatalf("bad: %#v", j)
+		}
+	})
+}
+
 func TestHTTP_JobsRegister(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -566,6 +623,38 @@ func TestHTTP_JobDelete(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobDelete_ShutdownDelay(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the job
+		job := mock.Job()
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Invalid durations are rejected
+		req, err := http.NewRequest("DELETE", "/v1/job/"+job.ID+"?shutdown_delay=soon", nil)
+		require.NoError(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "as a duration")
+
+		req, err = http.NewRequest("DELETE", "/v1/job/"+job.ID+"?shutdown_delay=30s", nil)
+		require.NoError(err)
+		obj, err := s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		require.NotEmpty(obj.(structs.JobDeregisterResponse).EvalID)
+	})
+}
+
 func TestHTTP_JobForceEvaluate(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -609,6 +698,53 @@ func TestHTTP_JobForceEvaluate(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobsBulk(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the job
+		job := mock.Job()
+		job.Tags = []string{"owner:payments"}
+		args := structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: structs.DefaultNamespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(s.Agent.RPC("Job.Register", &args, &resp))
+
+		// Stop the jobs with the tag
+		buf := encodeReq(api.JobsBulkRequest{
+			Operation: api.JobBulkOperationStop,
+			Tags:      []string{"owner:payments"},
+		})
+		req, err := http.NewRequest("PUT", "/v1/jobs/bulk", buf)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JobsBulkRequest(respW, req)
+		require.NoError(err)
+		out := obj.(structs.JobBulkResponse)
+		require.Len(out.Jobs, 1)
+		require.Equal(job.ID, out.Jobs[0].ID)
+		require.NotEmpty(out.Jobs[0].EvalID)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Requests without a filter are rejected
+		buf = encodeReq(api.JobsBulkRequest{
+			Operation: api.JobBulkOperationStop,
+		})
+		req, err = http.NewRequest("PUT", "/v1/jobs/bulk", buf)
+		require.NoError(err)
+		_, err = s.Server.JobsBulkRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "require a job ID prefix or tags")
+	})
+}
+
 func TestHTTP_JobEvaluate_ForceReschedule(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1228,6 +1364,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			ProgressDeadline: helper.TimeToPtr(3 * time.Minute),
 			AutoRevert:       helper.BoolToPtr(false),
 			Canary:           helper.IntToPtr(1),
+			ProgressWebhook: &api.DeploymentWebhook{
+				URL: helper.StringToPtr("https://ci.example.com/deployments"),
+			},
 		},
 		Spreads: []*api.Spread{
 			{
@@ -1252,6 +1391,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			Payload:      "payload",
 			MetaRequired: []string{"a", "b"},
 			MetaOptional: []string{"c", "d"},
+			MetaConfigs: []*api.DispatchMetaConfig{
+				{
+					Key:          "a",
+					Type:         "int",
+					Enum:         []string{"1", "2"},
+					Regex:        "^[0-9]$",
+					RequiredWith: []string{"c"},
+				},
+			},
+		},
+		VersionRetention: &api.JobVersionRetention{
+			Count: 20,
+			Age:   time.Hour,
 		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
@@ -1290,6 +1442,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Unlimited:     helper.BoolToPtr(true),
 					MaxDelay:      helper.TimeToPtr(20 * time.Minute),
 				},
+				MaxClientDisconnect: helper.TimeToPtr(10 * time.Minute),
+				ReconnectStrategy:   helper.StringToPtr("keep_original"),
 				Migrate: &api.MigrateStrategy{
 					MaxParallel:     helper.IntToPtr(12),
 					HealthCheck:     helper.StringToPtr("task_events"),
@@ -1313,6 +1467,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Sticky:  helper.BoolToPtr(true),
 					Migrate: helper.BoolToPtr(true),
 				},
+				SharedMemory: &api.SharedMemory{
+					SizeMB: helper.IntToPtr(256),
+				},
+				Consul: &api.Consul{
+					Cluster: helper.StringToPtr("edge"),
+				},
 				Update: &api.UpdateStrategy{
 					HealthCheck:      helper.StringToPtr(structs.UpdateStrategyHealthCheck_Checks),
 					MinHealthyTime:   helper.TimeToPtr(2 * time.Minute),
@@ -1379,6 +1539,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 										Interval:      4 * time.Second,
 										Timeout:       2 * time.Second,
 										InitialStatus: "ok",
+										Role:          "liveness",
 										CheckRestart: &api.CheckRestart{
 											Limit:          3,
 											IgnoreWarnings: true,
@@ -1414,6 +1575,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 											Value: 2000,
 										},
 									},
+									DNS: &api.DNSConfig{
+										Servers: []string{"10.0.0.53"},
+									},
 								},
 							},
 							Devices: []*api.RequestedDevice{
@@ -1445,8 +1609,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Meta: map[string]string{
 							"lol": "code",
 						},
-						KillTimeout: helper.TimeToPtr(10 * time.Second),
-						KillSignal:  "SIGQUIT",
+						MetricLabels: []string{"lol"},
+						KillTimeout:  helper.TimeToPtr(10 * time.Second),
+						KillSignal:   "SIGQUIT",
 						LogConfig: &api.LogConfig{
 							MaxFiles:      helper.IntToPtr(10),
 							MaxFileSizeMB: helper.IntToPtr(100),
@@ -1463,6 +1628,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						},
 						Vault: &api.Vault{
 							Policies:     []string{"a", "b", "c"},
+							Namespace:    helper.StringToPtr("engineering"),
+							Cluster:      helper.StringToPtr("secrets"),
 							Env:          helper.BoolToPtr(true),
 							ChangeMode:   helper.StringToPtr("c"),
 							ChangeSignal: helper.StringToPtr("sighup"),
@@ -1474,6 +1641,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								EmbeddedTmpl: helper.StringToPtr("embedded"),
 								ChangeMode:   helper.StringToPtr("change"),
 								ChangeSignal: helper.StringToPtr("signal"),
+								ChangeScript: &api.ChangeScript{
+									Command:     helper.StringToPtr("/bin/foo"),
+									Args:        []string{"-h"},
+									Timeout:     helper.TimeToPtr(10 * time.Second),
+									FailOnError: helper.BoolToPtr(true),
+								},
 								Splay:        helper.TimeToPtr(1 * time.Minute),
 								Perms:        helper.StringToPtr("666"),
 								LeftDelim:    helper.StringToPtr("abc"),
@@ -1482,9 +1655,32 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								VaultGrace:   helper.TimeToPtr(3 * time.Second),
 							},
 						},
+						Secrets: []*api.Secret{
+							{
+								Name:         helper.StringToPtr("token"),
+								Provider:     helper.StringToPtr("vault"),
+								Path:         helper.StringToPtr("secret/data/token"),
+								Key:          helper.StringToPtr("value"),
+								Uid:          helper.IntToPtr(1000),
+								ChangeMode:   helper.StringToPtr("signal"),
+								ChangeSignal: helper.StringToPtr("sigusr1"),
+							},
+						},
+						KillEscalation: []*api.KillStep{
+							{
+								Signal:  helper.StringToPtr("sigterm"),
+								Timeout: helper.TimeToPtr(30 * time.Second),
+							},
+							{
+								Signal: helper.StringToPtr("SIGINT"),
+							},
+						},
 						DispatchPayload: &api.DispatchPayloadConfig{
 							File: "fileA",
 						},
+						Identity: &api.WorkloadIdentity{
+							Env: true,
+						},
 					},
 				},
 			},
@@ -1551,6 +1747,19 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			Payload:      "payload",
 			MetaRequired: []string{"a", "b"},
 			MetaOptional: []string{"c", "d"},
+			MetaConfigs: []*structs.DispatchMetaConfig{
+				{
+					Key:          "a",
+					Type:         "int",
+					Enum:         []string{"1", "2"},
+					Regex:        "^[0-9]$",
+					RequiredWith: []string{"c"},
+				},
+			},
+		},
+		VersionRetention: &structs.JobVersionRetention{
+			Count: 20,
+			Age:   time.Hour,
 		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
@@ -1601,6 +1810,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Unlimited:     true,
 					MaxDelay:      20 * time.Minute,
 				},
+				MaxClientDisconnect: helper.TimeToPtr(10 * time.Minute),
+				ReconnectStrategy:   "keep_original",
 				Migrate: &structs.MigrateStrategy{
 					MaxParallel:     12,
 					HealthCheck:     "task_events",
@@ -1612,6 +1823,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Sticky:  true,
 					Migrate: true,
 				},
+				SharedMemory: &structs.SharedMemory{
+					SizeMB: 256,
+				},
+				Consul: &structs.Consul{
+					Cluster: "edge",
+				},
 				Update: &structs.UpdateStrategy{
 					Stagger:          1 * time.Second,
 					MaxParallel:      5,
@@ -1621,6 +1838,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					ProgressDeadline: 5 * time.Minute,
 					AutoRevert:       true,
 					Canary:           1,
+					ProgressWebhook: &structs.DeploymentWebhook{
+						URL: "https://ci.example.com/deployments",
+					},
 				},
 				Meta: map[string]string{
 					"key": "value",
@@ -1674,6 +1894,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 										InitialStatus: "ok",
 										GRPCService:   "foo.Bar",
 										GRPCUseTLS:    true,
+										Role:          "liveness",
 										CheckRestart: &structs.CheckRestart{
 											Limit:          3,
 											Grace:          11 * time.Second,
@@ -1713,6 +1934,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 											Value: 2000,
 										},
 									},
+									DNS: &structs.DNSConfig{
+										Servers: []string{"10.0.0.53"},
+									},
 								},
 							},
 							Devices: []*structs.RequestedDevice{
@@ -1744,8 +1968,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						Meta: map[string]string{
 							"lol": "code",
 						},
-						KillTimeout: 10 * time.Second,
-						KillSignal:  "SIGQUIT",
+						MetricLabels: []string{"lol"},
+						KillTimeout:  10 * time.Second,
+						KillSignal:   "SIGQUIT",
 						LogConfig: &structs.LogConfig{
 							MaxFiles:      10,
 							MaxFileSizeMB: 100,
@@ -1762,6 +1987,8 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						},
 						Vault: &structs.Vault{
 							Policies:     []string{"a", "b", "c"},
+							Namespace:    "engineering",
+							Cluster:      "secrets",
 							Env:          true,
 							ChangeMode:   "c",
 							ChangeSignal: "sighup",
@@ -1773,6 +2000,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								EmbeddedTmpl: "embedded",
 								ChangeMode:   "change",
 								ChangeSignal: "SIGNAL",
+								ChangeScript: &structs.ChangeScript{
+									Command:     "/bin/foo",
+									Args:        []string{"-h"},
+									Timeout:     10 * time.Second,
+									FailOnError: true,
+								},
 								Splay:        1 * time.Minute,
 								Perms:        "666",
 								LeftDelim:    "abc",
@@ -1781,9 +2014,34 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								VaultGrace:   3 * time.Second,
 							},
 						},
+						Secrets: []*structs.Secret{
+							{
+								Name:         "token",
+								Provider:     "vault",
+								Path:         "secret/data/token",
+								Key:          "value",
+								Perms:        "0400",
+								Uid:          1000,
+								ChangeMode:   "signal",
+								ChangeSignal: "SIGUSR1",
+							},
+						},
+						KillEscalation: []*structs.KillStep{
+							{
+								Signal:  "SIGTERM",
+								Timeout: 30 * time.Second,
+							},
+							{
+								Signal:  "SIGINT",
+								Timeout: 5 * time.Second,
+							},
+						},
 						DispatchPayload: &structs.DispatchPayloadConfig{
 							File: "fileA",
 						},
+						Identity: &structs.WorkloadIdentity{
+							Env: true,
+						},
 					},
 				},
 			},
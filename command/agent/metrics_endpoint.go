@@ -1,11 +1,27 @@
 package agent
 
 import (
+	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// defaultMetricsStreamInterval is how often a metrics stream sends a
+	// snapshot if no interval is requested. It matches the interval of the
+	// in-memory metrics sink.
+	defaultMetricsStreamInterval = 10 * time.Second
+
+	// minMetricsStreamInterval is the shortest interval a metrics stream may
+	// request.
+	minMetricsStreamInterval = 1 * time.Second
 )
 
 var (
@@ -29,6 +45,62 @@ func (s *HTTPServer) MetricsRequest(resp http.ResponseWriter, req *http.Request)
 	return s.agent.InmemSink.DisplayMetrics(resp, req)
 }
 
+// MetricsStreamRequest streams snapshots of the agent's metrics as newline
+// delimited JSON until the client disconnects. A snapshot is sent immediately
+// and then at the requested interval.
+func (s *HTTPServer) MetricsStreamRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	interval := defaultMetricsStreamInterval
+	if intervalStr := req.URL.Query().Get("interval"); intervalStr != "" {
+		d, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse interval: %v", err))
+		}
+		if d < minMetricsStreamInterval {
+			return nil, CodedError(400, fmt.Sprintf("Interval must be at least %v", minMetricsStreamInterval))
+		}
+		interval = d
+	}
+
+	// Fail the request before streaming if no metrics are available yet
+	summary, err := s.agent.InmemSink.DisplayMetrics(resp, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	output := ioutils.NewWriteFlusher(resp)
+	encoder := codec.NewEncoder(output, structs.JsonHandle)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := encoder.Encode(summary); err != nil {
+			// The client went away
+			return nil, nil
+		}
+		if _, err := output.Write([]byte("\n")); err != nil {
+			return nil, nil
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, nil
+		case <-ticker.C:
+		}
+
+		summary, err = s.agent.InmemSink.DisplayMetrics(resp, req)
+		if err != nil {
+			s.logger.Error("failed to collect metrics for stream", "error", err)
+			return nil, nil
+		}
+	}
+}
+
 func (s *HTTPServer) prometheusHandler() http.Handler {
 	promOnce.Do(func() {
 		handlerOptions := promhttp.HandlerOpts{
@@ -1,9 +1,13 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/testutil"
@@ -56,3 +60,50 @@ func TestHTTP_Metrics(t *testing.T) {
 		})
 	})
 }
+
+func TestHTTP_MetricsStream(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		// Wait for metrics to be written
+		testutil.WaitForResult(func() (bool, error) {
+			req, err := http.NewRequest("GET", "/v1/metrics", nil)
+			if err != nil {
+				return false, err
+			}
+			_, err = s.Server.MetricsRequest(httptest.NewRecorder(), req)
+			return err == nil, err
+		}, func(err error) {
+			t.Fatalf("should have metrics: %v", err)
+		})
+
+		// Intervals that are too short or malformed are rejected
+		for _, interval := range []string{"10ms", "foo"} {
+			req, err := http.NewRequest("GET", "/v1/metrics/stream?interval="+interval, nil)
+			assert.Nil(err)
+			_, err = s.Server.MetricsStreamRequest(httptest.NewRecorder(), req)
+			assert.NotNil(err)
+			assert.Equal(400, err.(HTTPCodedError).Code())
+		}
+
+		// Stream until the request is canceled
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequest("GET", "/v1/metrics/stream?interval=1s", nil)
+		assert.Nil(err)
+		req = req.WithContext(ctx)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.MetricsStreamRequest(respW, req)
+		assert.Nil(err)
+
+		lines := strings.Split(strings.TrimSpace(respW.Body.String()), "\n")
+		assert.Len(lines, 2)
+		for _, line := range lines {
+			var summary metrics.MetricsSummary
+			assert.Nil(json.Unmarshal([]byte(line), &summary))
+			assert.NotEmpty(summary.Timestamp)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NodeMetaRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.nodeMetaRead(resp, req)
+	case "PUT", "POST":
+		return s.nodeMetaApply(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) nodeMetaRead(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Get the requested Node ID
+	requestedNode := req.URL.Query().Get("node_id")
+
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: requestedNode,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.clientNodeRPC(requestedNode, "NodeMeta.Read", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &api.NodeMetaResponse{
+		Meta:    reply.Meta,
+		Dynamic: reply.Dynamic,
+	}, nil
+}
+
+func (s *HTTPServer) nodeMetaApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var applyReq api.NodeMetaApplyRequest
+	if err := decodeBody(req, &applyReq); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	if len(applyReq.Meta) == 0 {
+		return nil, CodedError(400, "missing metadata to apply")
+	}
+
+	// Build the request and parse the ACL token
+	args := cstructs.NodeMetaApplyRequest{
+		NodeID: applyReq.NodeID,
+		Meta:   applyReq.Meta,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.clientNodeRPC(applyReq.NodeID, "NodeMeta.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return &api.NodeMetaResponse{
+		Meta:    reply.Meta,
+		Dynamic: reply.Dynamic,
+	}, nil
+}
+
+// clientNodeRPC makes the client RPC using the local client if it is the
+// requested node and otherwise by forwarding it to the node.
+func (s *HTTPServer) clientNodeRPC(requestedNode, method string, args, reply interface{}) error {
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(requestedNode)
+
+	// Make the RPC
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC(method, args, reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC(method, args, reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC(method, args, reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		} else if strings.Contains(rpcErr.Error(), "Unknown node") {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return rpcErr
+}
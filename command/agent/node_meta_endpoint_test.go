@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodeMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Applying without metadata fails
+		{
+			req, err := http.NewRequest("PUT", "/v1/client/metadata", encodeReq(&api.NodeMetaApplyRequest{}))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.NodeMetaRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), "missing metadata")
+		}
+
+		// Apply metadata to the local node
+		{
+			args := &api.NodeMetaApplyRequest{
+				Meta: map[string]*string{"foo": helper.StringToPtr("bar")},
+			}
+			req, err := http.NewRequest("PUT", "/v1/client/metadata", encodeReq(args))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.NodeMetaRequest(respW, req)
+			require.NoError(err)
+
+			resp := obj.(*api.NodeMetaResponse)
+			require.Equal("bar", resp.Meta["foo"])
+			require.Equal(args.Meta, resp.Dynamic)
+		}
+
+		// Read the metadata of the local node
+		{
+			req, err := http.NewRequest("GET", "/v1/client/metadata", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.NodeMetaRequest(respW, req)
+			require.NoError(err)
+
+			resp := obj.(*api.NodeMetaResponse)
+			require.Equal("bar", resp.Meta["foo"])
+			require.Equal("bar", s.client.Node().Meta["foo"])
+		}
+
+		// Unsupported methods are rejected
+		{
+			req, err := http.NewRequest("DELETE", "/v1/client/metadata", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.NodeMetaRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), ErrInvalidMethod)
+		}
+	})
+}
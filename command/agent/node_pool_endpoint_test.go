@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodePoolCRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		pool := mock.NodePool()
+		buf := encodeReq(pool)
+
+		// Create the node pool
+		req, err := http.NewRequest("PUT", "/v1/node/pool/"+pool.Name, buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Read it back
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err := s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+		out := obj.(*structs.NodePool)
+		require.Equal(t, pool.Name, out.Name)
+		require.Equal(t, pool.Meta, out.Meta)
+
+		// List includes the built-in node pools
+		req, err = http.NewRequest("GET", "/v1/node/pools", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.NodePoolsRequest(respW, req)
+		require.NoError(t, err)
+		require.Len(t, obj.([]*structs.NodePool), 3)
+
+		// Delete the node pool
+		req, err = http.NewRequest("DELETE", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.NoError(t, err)
+
+		req, err = http.NewRequest("GET", "/v1/node/pool/"+pool.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestHTTP_NodePoolNameMismatch(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		pool := mock.NodePool()
+		req, err := http.NewRequest("PUT", "/v1/node/pool/other", encodeReq(pool))
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.NodePoolSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not match")
+	})
+}
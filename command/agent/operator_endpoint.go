@@ -240,6 +240,69 @@ func (s *HTTPServer) schedulerGetConfig(resp http.ResponseWriter, req *http.Requ
 	return reply, nil
 }
 
+// OperatorSchedulerBroker is used to inspect the state of the evaluation
+// broker.
+func (s *HTTPServer) OperatorSchedulerBroker(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.EvalBrokerStatusResponse
+	if err := s.agent.RPC("Operator.EvalBrokerStatus", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply.Status, nil
+}
+
+// OperatorSchedulerPlanRejections is used to inspect the nodes whose
+// placements were rejected by the plan applier.
+func (s *HTTPServer) OperatorSchedulerPlanRejections(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.PlanRejectionsResponse
+	if err := s.agent.RPC("Operator.PlanRejections", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply.Nodes, nil
+}
+
+// OperatorReplicationStatus is used to inspect the status of replication from
+// the authoritative region.
+func (s *HTTPServer) OperatorReplicationStatus(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.GenericRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	var reply structs.ReplicationStatusResponse
+	if err := s.agent.RPC("Operator.ReplicationStatus", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply.Status, nil
+}
+
 func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var args structs.SchedulerSetConfigRequest
 	s.parseWriteRequest(req, &args.WriteRequest)
@@ -251,6 +314,11 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 
 	args.Config = structs.SchedulerConfiguration{
 		PreemptionConfig: structs.PreemptionConfig{SystemSchedulerEnabled: conf.PreemptionConfig.SystemSchedulerEnabled},
+		PlanRejectionTracker: structs.PlanRejectionTrackerConfig{
+			Enabled:       conf.PlanRejectionTracker.Enabled,
+			NodeThreshold: conf.PlanRejectionTracker.NodeThreshold,
+			NodeWindow:    conf.PlanRejectionTracker.NodeWindow,
+		},
 	}
 
 	// Check for cas value
@@ -271,3 +339,38 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 	setIndex(resp, reply.Index)
 	return reply, nil
 }
+
+// OperatorUsageExport is used to export the inventory and resource usage of
+// the cluster within a time window.
+func (s *HTTPServer) OperatorUsageExport(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.UsageExportRequest
+	if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+		return nil, nil
+	}
+
+	// Parse the optional window of the export
+	for param, dst := range map[string]*int64{"start": &args.Start, "end": &args.End} {
+		if v := req.URL.Query().Get(param); v != "" {
+			ts, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, CodedError(400, fmt.Sprintf("invalid %s timestamp %q: %v", param, v, err))
+			}
+			*dst = ts
+		}
+	}
+	if args.Start != 0 && args.End != 0 && args.End <= args.Start {
+		return nil, CodedError(400, "end must be after start")
+	}
+
+	var reply structs.UsageExportResponse
+	if err := s.agent.RPC("Operator.UsageExport", &args, &reply); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &reply.QueryMeta)
+
+	return reply.Usage, nil
+}
@@ -281,6 +281,10 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 		require := require.New(t)
 		body := bytes.NewBuffer([]byte(`{"PreemptionConfig": {
                      "SystemSchedulerEnabled": true
+        }, "PlanRejectionTracker": {
+                     "Enabled": true,
+                     "NodeThreshold": 42,
+                     "NodeWindow": 60000000000
         }}`))
 		req, _ := http.NewRequest("PUT", "/v1/operator/scheduler/configuration", body)
 		resp := httptest.NewRecorder()
@@ -301,6 +305,9 @@ func TestOperator_SchedulerSetConfiguration(t *testing.T) {
 		err = s.RPC("Operator.SchedulerGetConfiguration", &args, &reply)
 		require.Nil(err)
 		require.True(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
+		require.True(reply.SchedulerConfig.PlanRejectionTracker.Enabled)
+		require.Equal(42, reply.SchedulerConfig.PlanRejectionTracker.NodeThreshold)
+		require.Equal(time.Minute, reply.SchedulerConfig.PlanRejectionTracker.NodeWindow)
 	})
 }
 
@@ -371,3 +378,115 @@ func TestOperator_SchedulerCASConfiguration(t *testing.T) {
 		require.False(reply.SchedulerConfig.PreemptionConfig.SystemSchedulerEnabled)
 	})
 }
+
+func TestOperator_SchedulerBroker(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		req, _ := http.NewRequest("GET", "/v1/operator/scheduler/broker", nil)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorSchedulerBroker(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		out, ok := obj.(*structs.EvalBrokerStatus)
+		require.True(ok)
+		require.True(out.Enabled)
+
+		// Only GET is allowed
+		req, _ = http.NewRequest("PUT", "/v1/operator/scheduler/broker", nil)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorSchedulerBroker(resp, req)
+		require.Error(err)
+	})
+}
+
+func TestOperator_SchedulerPlanRejections(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		req, _ := http.NewRequest("GET", "/v1/operator/scheduler/plan-rejections", nil)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorSchedulerPlanRejections(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		out, ok := obj.([]*structs.NodePlanRejections)
+		require.True(ok)
+		require.Empty(out)
+
+		// Only GET is allowed
+		req, _ = http.NewRequest("PUT", "/v1/operator/scheduler/plan-rejections", nil)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorSchedulerPlanRejections(resp, req)
+		require.Error(err)
+	})
+}
+
+func TestOperator_ReplicationStatus(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		req, _ := http.NewRequest("GET", "/v1/operator/replication", nil)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorReplicationStatus(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		out, ok := obj.(*structs.ReplicationStatus)
+		require.True(ok)
+		require.False(out.Enabled)
+		require.Equal("global", out.SourceRegion)
+
+		// Only GET is allowed
+		req, _ = http.NewRequest("PUT", "/v1/operator/replication", nil)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorReplicationStatus(resp, req)
+		require.Error(err)
+	})
+}
+
+func TestOperator_UsageExport(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		req, _ := http.NewRequest("GET", "/v1/operator/usage", nil)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorUsageExport(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		out, ok := obj.(*structs.UsageReport)
+		require.True(ok)
+		require.Equal(24*time.Hour, out.End.Sub(out.Start))
+		require.NotNil(out.Totals)
+
+		// Explicit window
+		end := time.Now()
+		start := end.Add(-time.Hour)
+		url := fmt.Sprintf("/v1/operator/usage?start=%d&end=%d", start.UnixNano(), end.UnixNano())
+		req, _ = http.NewRequest("GET", url, nil)
+		resp = httptest.NewRecorder()
+		obj, err = s.Server.OperatorUsageExport(resp, req)
+		require.Nil(err)
+		out = obj.(*structs.UsageReport)
+		require.Equal(start.UnixNano(), out.Start.UnixNano())
+		require.Equal(end.UnixNano(), out.End.UnixNano())
+
+		// Invalid windows are rejected
+		for _, url := range []string{
+			"/v1/operator/usage?start=yesterday",
+			fmt.Sprintf("/v1/operator/usage?start=%d&end=%d", end.UnixNano(), start.UnixNano()),
+		} {
+			req, _ = http.NewRequest("GET", url, nil)
+			resp = httptest.NewRecorder()
+			_, err = s.Server.OperatorUsageExport(resp, req)
+			require.Error(err)
+			codedErr, ok := err.(HTTPCodedError)
+			require.True(ok)
+			require.Equal(400, codedErr.Code())
+		}
+
+		// Only GET is allowed
+		req, _ = http.NewRequest("PUT", "/v1/operator/usage", nil)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorUsageExport(resp, req)
+		require.Error(err)
+	})
+}
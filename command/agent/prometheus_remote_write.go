@@ -0,0 +1,285 @@
+package agent
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	log "github.com/hashicorp/go-hclog"
+	rootcerts "github.com/hashicorp/go-rootcerts"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	// defaultRemoteWriteInterval and defaultRemoteWriteTimeout are used when
+	// the remote write configuration doesn't set them
+	defaultRemoteWriteInterval = 15 * time.Second
+	defaultRemoteWriteTimeout  = 10 * time.Second
+)
+
+// promRemoteWriter periodically pushes the metrics collected by the
+// Prometheus sink to a Prometheus remote write endpoint.
+type promRemoteWriter struct {
+	url             string
+	interval        time.Duration
+	bearerToken     string
+	bearerTokenFile string
+
+	client   *http.Client
+	gatherer prometheus.Gatherer
+	logger   log.Logger
+}
+
+// promLabel is a label of a remote write time series.
+type promLabel struct {
+	name  string
+	value string
+}
+
+// promSample is a single sample of a remote write time series. Its labels
+// include the metric name.
+type promSample struct {
+	labels []promLabel
+	value  float64
+}
+
+// setupPrometheusRemoteWrite starts pushing metrics if a remote write
+// endpoint is configured.
+func (a *Agent) setupPrometheusRemoteWrite() error {
+	if a.config.Telemetry == nil || a.config.Telemetry.PrometheusRemoteWrite == nil {
+		return nil
+	}
+
+	w, err := newPromRemoteWriter(a.config.Telemetry.PrometheusRemoteWrite,
+		prometheus.DefaultGatherer, a.logger.Named("prometheus_remote_write"))
+	if err != nil {
+		return fmt.Errorf("invalid prometheus_remote_write configuration: %v", err)
+	}
+
+	go w.run(a.shutdownCh)
+	return nil
+}
+
+// newPromRemoteWriter returns a writer pushing the metrics of the gatherer
+// as configured.
+func newPromRemoteWriter(conf *PrometheusRemoteWrite, gatherer prometheus.Gatherer, logger log.Logger) (*promRemoteWriter, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	tlsConf := &tls.Config{
+		ServerName: conf.TLSServerName,
+	}
+	if err := rootcerts.ConfigureTLS(tlsConf, &rootcerts.Config{CAFile: conf.CAFile}); err != nil {
+		return nil, err
+	}
+	if conf.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = tlsConf
+
+	w := &promRemoteWriter{
+		url:             conf.URL,
+		interval:        conf.Interval,
+		bearerToken:     conf.BearerToken,
+		bearerTokenFile: conf.BearerTokenFile,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   conf.Timeout,
+		},
+		gatherer: gatherer,
+		logger:   logger,
+	}
+	if w.interval == 0 {
+		w.interval = defaultRemoteWriteInterval
+	}
+	if w.client.Timeout == 0 {
+		w.client.Timeout = defaultRemoteWriteTimeout
+	}
+	return w, nil
+}
+
+// run pushes the metrics every interval until shutdownCh is closed.
+func (w *promRemoteWriter) run(shutdownCh <-chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.push(); err != nil {
+				w.logger.Warn("failed to push metrics", "url", w.url, "error", err)
+			}
+		case <-shutdownCh:
+			return
+		}
+	}
+}
+
+// push gathers the metrics and writes them to the remote write endpoint.
+func (w *promRemoteWriter) push() error {
+	families, err := w.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return err
+	}
+
+	body := snappy.Encode(nil, encodeWriteRequest(families, time.Now()))
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "Nomad")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	// The token file is read on every push so rotated tokens are picked up
+	token := w.bearerToken
+	if w.bearerTokenFile != "" {
+		b, err := ioutil.ReadFile(w.bearerTokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read bearer token file: %v", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	return nil
+}
+
+// encodeWriteRequest encodes the metric families as the protobuf
+// WriteRequest message of the remote write protocol, with all the samples
+// taken at the given time.
+func encodeWriteRequest(families []*dto.MetricFamily, now time.Time) []byte {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+
+	req := proto.NewBuffer(nil)
+	series := proto.NewBuffer(nil)
+	for _, family := range families {
+		for _, metric := range family.Metric {
+			for _, sample := range metricSamples(family.GetName(), family.GetType(), metric) {
+				series.Reset()
+				encodeTimeSeries(series, sample, timestamp)
+
+				// WriteRequest.timeseries
+				req.EncodeVarint(1<<3 | proto.WireBytes)
+				req.EncodeRawBytes(series.Bytes())
+			}
+		}
+	}
+	return req.Bytes()
+}
+
+// encodeTimeSeries encodes the sample as a TimeSeries message.
+func encodeTimeSeries(b *proto.Buffer, sample *promSample, timestamp int64) {
+	for _, label := range sample.labels {
+		l := proto.NewBuffer(nil)
+		l.EncodeVarint(1<<3 | proto.WireBytes)
+		l.EncodeStringBytes(label.name)
+		l.EncodeVarint(2<<3 | proto.WireBytes)
+		l.EncodeStringBytes(label.value)
+
+		// TimeSeries.labels
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		b.EncodeRawBytes(l.Bytes())
+	}
+
+	s := proto.NewBuffer(nil)
+	s.EncodeVarint(1<<3 | proto.WireFixed64)
+	s.EncodeFixed64(math.Float64bits(sample.value))
+	s.EncodeVarint(2<<3 | proto.WireVarint)
+	s.EncodeVarint(uint64(timestamp))
+
+	// TimeSeries.samples
+	b.EncodeVarint(2<<3 | proto.WireBytes)
+	b.EncodeRawBytes(s.Bytes())
+}
+
+// metricSamples returns the samples of a metric, named as the Prometheus
+// text format names them. Summaries and histograms are split into a sample
+// per quantile or bucket, along with their sum and count.
+func metricSamples(name string, typ dto.MetricType, metric *dto.Metric) []*promSample {
+	sample := func(suffix string, value float64, extra ...promLabel) *promSample {
+		labels := make([]promLabel, 0, len(metric.Label)+len(extra)+1)
+		labels = append(labels, promLabel{"__name__", name + suffix})
+		for _, l := range metric.Label {
+			labels = append(labels, promLabel{l.GetName(), l.GetValue()})
+		}
+		labels = append(labels, extra...)
+		sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+		return &promSample{labels: labels, value: value}
+	}
+
+	switch typ {
+	case dto.MetricType_COUNTER:
+		return []*promSample{sample("", metric.GetCounter().GetValue())}
+	case dto.MetricType_GAUGE:
+		return []*promSample{sample("", metric.GetGauge().GetValue())}
+	case dto.MetricType_UNTYPED:
+		return []*promSample{sample("", metric.GetUntyped().GetValue())}
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		var samples []*promSample
+		for _, q := range summary.Quantile {
+			samples = append(samples, sample("", q.GetValue(),
+				promLabel{"quantile", formatFloat(q.GetQuantile())}))
+		}
+		return append(samples,
+			sample("_sum", summary.GetSampleSum()),
+			sample("_count", float64(summary.GetSampleCount())))
+	case dto.MetricType_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		var samples []*promSample
+		for _, b := range histogram.Bucket {
+			samples = append(samples, sample("_bucket", float64(b.GetCumulativeCount()),
+				promLabel{"le", formatFloat(b.GetUpperBound())}))
+		}
+		if n := len(histogram.Bucket); n == 0 || !math.IsInf(histogram.Bucket[n-1].GetUpperBound(), 1) {
+			samples = append(samples, sample("_bucket", float64(histogram.GetSampleCount()),
+				promLabel{"le", "+Inf"}))
+		}
+		return append(samples,
+			sample("_sum", histogram.GetSampleSum()),
+			sample("_count", float64(histogram.GetSampleCount())))
+	}
+	return nil
+}
+
+// formatFloat formats a quantile or bucket bound as Prometheus does.
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+// The remote write messages, decoded by reflection to check the encoding
+type testWriteRequest struct {
+	Timeseries []*testTimeSeries `protobuf:"bytes,1,rep,name=timeseries"`
+}
+
+func (m *testWriteRequest) Reset()         { *m = testWriteRequest{} }
+func (m *testWriteRequest) String() string { return proto.CompactTextString(m) }
+func (*testWriteRequest) ProtoMessage()    {}
+
+type testTimeSeries struct {
+	Labels  []*testLabel  `protobuf:"bytes,1,rep,name=labels"`
+	Samples []*testSample `protobuf:"bytes,2,rep,name=samples"`
+}
+
+func (m *testTimeSeries) Reset()         { *m = testTimeSeries{} }
+func (m *testTimeSeries) String() string { return proto.CompactTextString(m) }
+func (*testTimeSeries) ProtoMessage()    {}
+
+type testLabel struct {
+	Name  string `protobuf:"bytes,1,opt,name=name"`
+	Value string `protobuf:"bytes,2,opt,name=value"`
+}
+
+func (m *testLabel) Reset()         { *m = testLabel{} }
+func (m *testLabel) String() string { return proto.CompactTextString(m) }
+func (*testLabel) ProtoMessage()    {}
+
+type testSample struct {
+	Value     float64 `protobuf:"fixed64,1,opt,name=value"`
+	Timestamp int64   `protobuf:"varint,2,opt,name=timestamp"`
+}
+
+func (m *testSample) Reset()         { *m = testSample{} }
+func (m *testSample) String() string { return proto.CompactTextString(m) }
+func (*testSample) ProtoMessage()    {}
+
+func TestPrometheusRemoteWrite_Push(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nomad_test_counter",
+		Help: "test",
+	}, []string{"host"})
+	counter.WithLabelValues("node1").Add(3)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "nomad_test_histogram",
+		Help:    "test",
+		Buckets: []float64{1, 5},
+	})
+	histogram.Observe(2)
+	registry.MustRegister(counter, histogram)
+
+	reqCh := make(chan *http.Request, 1)
+	bodyCh := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		reqCh <- r
+		bodyCh <- body
+	}))
+	defer srv.Close()
+
+	w, err := newPromRemoteWriter(&PrometheusRemoteWrite{
+		URL:         srv.URL,
+		BearerToken: "secret",
+	}, registry, testlog.HCLogger(t))
+	require.NoError(err)
+	require.NoError(w.push())
+
+	r := <-reqCh
+	require.Equal("snappy", r.Header.Get("Content-Encoding"))
+	require.Equal("Bearer secret", r.Header.Get("Authorization"))
+
+	raw, err := snappy.Decode(nil, <-bodyCh)
+	require.NoError(err)
+	var writeReq testWriteRequest
+	require.NoError(proto.Unmarshal(raw, &writeReq))
+
+	// Index the series by their labels
+	series := make(map[string]float64)
+	for _, ts := range writeReq.Timeseries {
+		key := ""
+		for _, l := range ts.Labels {
+			key += l.Name + "=" + l.Value + ","
+		}
+		require.Len(ts.Samples, 1)
+		require.NotZero(ts.Samples[0].Timestamp)
+		series[key] = ts.Samples[0].Value
+	}
+	require.Equal(map[string]float64{
+		"__name__=nomad_test_counter,host=node1,":       3,
+		"__name__=nomad_test_histogram_bucket,le=1,":    0,
+		"__name__=nomad_test_histogram_bucket,le=5,":    1,
+		"__name__=nomad_test_histogram_bucket,le=+Inf,": 1,
+		"__name__=nomad_test_histogram_sum,":            2,
+		"__name__=nomad_test_histogram_count,":          1,
+	}, series)
+}
+
+func TestPrometheusRemoteWrite_Error(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no write access", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	w, err := newPromRemoteWriter(&PrometheusRemoteWrite{URL: srv.URL},
+		prometheus.NewRegistry(), testlog.HCLogger(t))
+	require.NoError(err)
+	err = w.push()
+	require.Error(err)
+	require.Contains(err.Error(), "403")
+
+	// Invalid configurations are rejected
+	_, err = newPromRemoteWriter(&PrometheusRemoteWrite{
+		URL:         srv.URL,
+		BearerToken: "secret", BearerTokenFile: "/tmp/token",
+	}, prometheus.NewRegistry(), testlog.HCLogger(t))
+	require.Error(err)
+}
@@ -2,7 +2,9 @@ package agent
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	golog "log"
@@ -53,6 +55,112 @@ type retryJoiner struct {
 
 	// logger is the retry joiners logger
 	logger log.Logger
+
+	// status tracks the progress of the retry join for the agent-info
+	// diagnostics. It may be nil.
+	status *retryJoinStatus
+}
+
+// retryJoinStatus is the progress of a retry join, surfaced by the agent
+// stats to diagnose a cluster that fails to form.
+type retryJoinStatus struct {
+	l sync.Mutex
+
+	started     bool
+	joined      bool
+	failed      bool
+	attempts    int
+	addrs       []string
+	lastErr     error
+	lastAttempt time.Time
+	nextRetry   time.Time
+}
+
+// attempt records the outcome of a join attempt against the given addresses.
+func (s *retryJoinStatus) attempt(addrs []string, err error) {
+	if s == nil {
+		return
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.started = true
+	s.attempts++
+	s.addrs = addrs
+	s.lastErr = err
+	s.lastAttempt = time.Now()
+	s.joined = err == nil
+}
+
+// retry records when the next join attempt will happen.
+func (s *retryJoinStatus) retry(wait time.Duration) {
+	if s == nil {
+		return
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.nextRetry = time.Now().Add(wait)
+}
+
+// fail records that the join retries were exhausted.
+func (s *retryJoinStatus) fail() {
+	if s == nil {
+		return
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.failed = true
+}
+
+// Stats returns the progress of the retry join as agent stats, or nil if no
+// retry join was attempted.
+func (s *retryJoinStatus) Stats() map[string]string {
+	if s == nil {
+		return nil
+	}
+	s.l.Lock()
+	defer s.l.Unlock()
+	if !s.started {
+		return nil
+	}
+
+	status := "joining"
+	switch {
+	case s.joined:
+		status = "joined"
+	case s.failed:
+		status = "failed"
+	}
+
+	stats := map[string]string{
+		"status":           status,
+		"attempts":         strconv.Itoa(s.attempts),
+		"discovered_addrs": strings.Join(s.addrs, ","),
+		"last_attempt":     s.lastAttempt.Format(time.RFC3339),
+	}
+	if s.lastErr != nil {
+		stats["last_error"] = s.lastErr.Error()
+	}
+	if status == "joining" {
+		stats["next_retry"] = s.nextRetry.Format(time.RFC3339)
+	}
+	return stats
+}
+
+// retryWait returns the wait before the next join attempt after the given
+// number of failed attempts. The wait doubles after each failed attempt up to
+// the maximum interval, if one is set.
+func retryWait(serverJoin *ServerJoin, attempt int) time.Duration {
+	wait := serverJoin.RetryInterval
+	if serverJoin.RetryMaxInterval <= wait {
+		return wait
+	}
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= serverJoin.RetryMaxInterval {
+			return serverJoin.RetryMaxInterval
+		}
+	}
+	return wait
 }
 
 // Validate ensures that the configuration passes validity checks for the
@@ -114,9 +222,10 @@ func (r *retryJoiner) RetryJoin(serverJoin *ServerJoin) {
 		for _, addr := range serverJoin.RetryJoin {
 			switch {
 			case strings.HasPrefix(addr, "provider="):
-				servers, err := r.discover.Addrs(addr, standardLogger)
-				if err != nil {
-					r.logger.Error("determining join addresses failed", "error", err)
+				servers, discoverErr := r.discover.Addrs(addr, standardLogger)
+				if discoverErr != nil {
+					r.logger.Error("determining join addresses failed", "error", discoverErr)
+					err = fmt.Errorf("determining join addresses failed: %v", discoverErr)
 				} else {
 					addrs = append(addrs, servers...)
 				}
@@ -129,6 +238,7 @@ func (r *retryJoiner) RetryJoin(serverJoin *ServerJoin) {
 			if r.serverEnabled && r.serverJoin != nil {
 				n, err = r.serverJoin(addrs)
 				if err == nil {
+					r.status.attempt(addrs, nil)
 					r.logger.Info("retry join completed", "initial_servers", n, "agent_mode", "server")
 					return
 				}
@@ -136,22 +246,27 @@ func (r *retryJoiner) RetryJoin(serverJoin *ServerJoin) {
 			if r.clientEnabled && r.clientJoin != nil {
 				n, err = r.clientJoin(addrs)
 				if err == nil {
+					r.status.attempt(addrs, nil)
 					r.logger.Info("retry join completed", "initial_servers", n, "agent_mode", "client")
 					return
 				}
 			}
+		} else if err == nil {
+			err = fmt.Errorf("no addresses discovered")
 		}
+		r.status.attempt(addrs, err)
 
 		attempt++
 		if serverJoin.RetryMaxAttempts > 0 && attempt > serverJoin.RetryMaxAttempts {
 			r.logger.Error("max join retry exhausted, exiting")
+			r.status.fail()
 			close(r.errCh)
 			return
 		}
 
-		if err != nil {
-			r.logger.Warn("join failed", "error", err, "retry", serverJoin.RetryInterval)
-		}
-		time.Sleep(serverJoin.RetryInterval)
+		wait := retryWait(serverJoin, attempt)
+		r.status.retry(wait)
+		r.logger.Warn("join failed", "error", err, "retry", wait)
+		time.Sleep(wait)
 	}
 }
@@ -196,6 +196,95 @@ func TestRetryJoin_Client(t *testing.T) {
 	require.Equal(stubAddress, output[0])
 }
 
+func TestRetryJoin_Status(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	serverJoin := &ServerJoin{
+		RetryMaxAttempts: 1,
+		RetryJoin:        []string{"127.0.0.1"},
+	}
+
+	attempts := 0
+	mockJoin := func(s []string) (int, error) {
+		attempts++
+		if attempts == 1 {
+			return 0, fmt.Errorf("connection refused")
+		}
+		return 1, nil
+	}
+
+	status := &retryJoinStatus{}
+	require.Nil(status.Stats())
+
+	joiner := retryJoiner{
+		discover:      &MockDiscover{},
+		serverJoin:    mockJoin,
+		serverEnabled: true,
+		logger:        testlog.HCLogger(t),
+		errCh:         make(chan struct{}),
+		status:        status,
+	}
+
+	joiner.RetryJoin(serverJoin)
+
+	stats := status.Stats()
+	require.Equal("joined", stats["status"])
+	require.Equal("2", stats["attempts"])
+	require.Equal("127.0.0.1", stats["discovered_addrs"])
+	require.NotContains(stats, "last_error")
+	require.NotContains(stats, "next_retry")
+}
+
+func TestRetryJoin_Status_Failed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	serverJoin := &ServerJoin{
+		RetryMaxAttempts: 1,
+		RetryJoin:        []string{"127.0.0.1"},
+	}
+
+	mockJoin := func(s []string) (int, error) {
+		return 0, fmt.Errorf("connection refused")
+	}
+
+	status := &retryJoinStatus{}
+	joiner := retryJoiner{
+		discover:      &MockDiscover{},
+		serverJoin:    mockJoin,
+		serverEnabled: true,
+		logger:        testlog.HCLogger(t),
+		errCh:         make(chan struct{}),
+		status:        status,
+	}
+
+	joiner.RetryJoin(serverJoin)
+
+	stats := status.Stats()
+	require.Equal("failed", stats["status"])
+	require.Equal("2", stats["attempts"])
+	require.Equal("connection refused", stats["last_error"])
+}
+
+func TestRetryJoin_Wait(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Without a maximum interval the interval is fixed
+	serverJoin := &ServerJoin{RetryInterval: 10 * time.Second}
+	require.Equal(10*time.Second, retryWait(serverJoin, 1))
+	require.Equal(10*time.Second, retryWait(serverJoin, 5))
+
+	// With a maximum interval the interval doubles up to the maximum
+	serverJoin.RetryMaxInterval = time.Minute
+	require.Equal(10*time.Second, retryWait(serverJoin, 1))
+	require.Equal(20*time.Second, retryWait(serverJoin, 2))
+	require.Equal(40*time.Second, retryWait(serverJoin, 3))
+	require.Equal(time.Minute, retryWait(serverJoin, 4))
+	require.Equal(time.Minute, retryWait(serverJoin, 50))
+}
+
 func TestRetryJoin_Validate(t *testing.T) {
 	t.Parallel()
 	type validateExpect struct {
@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/serf/serf"
+)
+
+// agentSelfHealth is the health of the subsystems of the agent, returned by
+// the /v1/agent/self/health endpoint and checked by the watchdog.
+type agentSelfHealth struct {
+	// Healthy is true if all the critical subsystems are healthy.
+	Healthy bool
+
+	// Subsystems is the health of the subsystems by name.
+	Subsystems map[string]*subsystemHealth
+}
+
+// subsystemHealth is the health of a subsystem of the agent.
+type subsystemHealth struct {
+	Healthy bool
+
+	// Critical is true if the subsystem counts towards the health of the
+	// agent. The health of task drivers is reported but isn't critical, as
+	// restarting the agent doesn't fix them.
+	Critical bool
+
+	// Message explains why the subsystem is unhealthy.
+	Message string `json:",omitempty"`
+
+	// Details are the stats the health of the subsystem is based on.
+	Details map[string]string `json:",omitempty"`
+}
+
+const (
+	// selfHealthRaft, selfHealthSerf and selfHealthLeadership are the
+	// subsystems of servers.
+	selfHealthRaft       = "raft"
+	selfHealthSerf       = "serf"
+	selfHealthLeadership = "leadership"
+
+	// selfHealthServers and selfHealthHeartbeat are the subsystems of
+	// clients, which also report their task drivers prefixed by
+	// selfHealthDriverPrefix.
+	selfHealthServers      = "servers"
+	selfHealthHeartbeat    = "heartbeat"
+	selfHealthDriverPrefix = "driver."
+)
+
+// selfHealth returns the health of the subsystems of the agent's server and
+// client.
+func (a *Agent) selfHealth() *agentSelfHealth {
+	health := &agentSelfHealth{
+		Subsystems: make(map[string]*subsystemHealth),
+	}
+	if a.server != nil {
+		a.serverSelfHealth(health.Subsystems)
+	}
+	if a.client != nil {
+		a.clientSelfHealth(health.Subsystems)
+	}
+
+	health.Healthy = true
+	for _, s := range health.Subsystems {
+		if s.Critical && !s.Healthy {
+			health.Healthy = false
+		}
+	}
+	return health
+}
+
+// serverSelfHealth adds the health of the server's Raft, Serf and leadership
+// to the subsystems.
+func (a *Agent) serverSelfHealth(subsystems map[string]*subsystemHealth) {
+	stats := a.server.Stats()
+
+	// Followers are healthy as long as they hear from the leader within the
+	// autopilot threshold of healthy servers
+	raftStats := stats["raft"]
+	raftHealth := &subsystemHealth{
+		Healthy:  true,
+		Critical: true,
+		Details: map[string]string{
+			"state":          raftStats["state"],
+			"last_contact":   raftStats["last_contact"],
+			"last_log_index": raftStats["last_log_index"],
+			"applied_index":  raftStats["applied_index"],
+			"num_peers":      raftStats["num_peers"],
+		},
+	}
+	switch raftStats["state"] {
+	case "Leader":
+	case "Follower":
+		var threshold time.Duration
+		if a.config.Autopilot != nil {
+			threshold = a.config.Autopilot.LastContactThreshold
+		}
+		if raftStats["last_contact"] == "never" {
+			raftHealth.Healthy = false
+			raftHealth.Message = "no contact with the leader"
+		} else if lastContact, err := time.ParseDuration(raftStats["last_contact"]); err != nil {
+			raftHealth.Healthy = false
+			raftHealth.Message = fmt.Sprintf("invalid last contact %q: %v", raftStats["last_contact"], err)
+		} else if threshold > 0 && lastContact > threshold {
+			raftHealth.Healthy = false
+			raftHealth.Message = fmt.Sprintf("last contact with the leader %v ago exceeds %v", lastContact, threshold)
+		}
+	default:
+		raftHealth.Healthy = false
+		raftHealth.Message = fmt.Sprintf("raft is in the %s state", strings.ToLower(raftStats["state"]))
+	}
+	subsystems[selfHealthRaft] = raftHealth
+
+	var alive, failed int
+	for _, member := range a.server.Members() {
+		switch member.Status {
+		case serf.StatusAlive:
+			alive++
+		case serf.StatusFailed:
+			failed++
+		}
+	}
+	serfHealth := &subsystemHealth{
+		Healthy:  true,
+		Critical: true,
+		Details: map[string]string{
+			"members":        strconv.Itoa(alive),
+			"failed_members": strconv.Itoa(failed),
+		},
+	}
+	if status := a.server.LocalMember().Status; status != serf.StatusAlive {
+		serfHealth.Healthy = false
+		serfHealth.Message = fmt.Sprintf("local member is %s", status)
+	}
+	subsystems[selfHealthSerf] = serfHealth
+
+	leader := stats["nomad"]["leader_addr"]
+	leadershipHealth := &subsystemHealth{
+		Healthy:  true,
+		Critical: true,
+		Details: map[string]string{
+			"leader":           strconv.FormatBool(a.server.IsLeader()),
+			"leader_addr":      leader,
+			"plan_queue_depth": strconv.Itoa(a.server.PlanQueueDepth()),
+		},
+	}
+	if leader == "" {
+		leadershipHealth.Healthy = false
+		leadershipHealth.Message = "no leader"
+	}
+	subsystems[selfHealthLeadership] = leadershipHealth
+}
+
+// clientSelfHealth adds the health of the client's connection to the servers,
+// its heartbeats and its task drivers to the subsystems.
+func (a *Agent) clientSelfHealth(subsystems map[string]*subsystemHealth) {
+	servers := a.client.GetServers()
+	serversHealth := &subsystemHealth{
+		Healthy:  true,
+		Critical: true,
+		Details: map[string]string{
+			"known_servers": strings.Join(servers, ","),
+		},
+	}
+	if len(servers) == 0 {
+		serversHealth.Healthy = false
+		serversHealth.Message = "no known servers"
+	}
+	subsystems[selfHealthServers] = serversHealth
+
+	// The client heartbeats once per TTL, so it missed a heartbeat if the
+	// last one is older than twice the TTL
+	last, ttl := a.client.LastHeartbeat()
+	heartbeatHealth := &subsystemHealth{
+		Healthy:  true,
+		Critical: true,
+		Details: map[string]string{
+			"heartbeat_ttl": ttl.String(),
+		},
+	}
+	if last.IsZero() {
+		heartbeatHealth.Healthy = false
+		heartbeatHealth.Message = "node not registered"
+	} else {
+		since := time.Since(last)
+		heartbeatHealth.Details["last_heartbeat"] = since.String()
+		if since > 2*ttl {
+			heartbeatHealth.Healthy = false
+			heartbeatHealth.Message = fmt.Sprintf("last heartbeat %v ago, missed heartbeats", since)
+		}
+	}
+	subsystems[selfHealthHeartbeat] = heartbeatHealth
+
+	for name, info := range a.client.Node().Drivers {
+		if info == nil || !info.Detected {
+			continue
+		}
+		driverHealth := &subsystemHealth{
+			Healthy: info.Healthy,
+		}
+		if !info.Healthy {
+			driverHealth.Message = info.HealthDescription
+		}
+		subsystems[selfHealthDriverPrefix+name] = driverHealth
+	}
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Register a service of an allocation
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+		service := mock.ServiceRegistration(alloc)
+		require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{service}))
+
+		// List the services
+		{
+			req, err := http.NewRequest("GET", "/v1/services", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ServiceRegistrationListRequest(respW, req)
+			require.NoError(err)
+			require.Equal("1001", respW.HeaderMap.Get("X-Nomad-Index"))
+
+			list := obj.([]*structs.ServiceRegistrationStub)
+			require.Len(list, 1)
+			require.Equal(service.ServiceName, list[0].ServiceName)
+		}
+
+		// Read the registrations of the service
+		{
+			req, err := http.NewRequest("GET", "/v1/service/"+service.ServiceName, nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ServiceRegistrationRequest(respW, req)
+			require.NoError(err)
+
+			services := obj.([]*structs.ServiceRegistration)
+			require.Len(services, 1)
+			require.Equal(service.ID, services[0].ID)
+		}
+
+		// Deleting requires a registration ID
+		{
+			req, err := http.NewRequest("DELETE", "/v1/service/"+service.ServiceName, nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ServiceRegistrationRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), "Missing service registration ID")
+		}
+
+		// Delete the registration
+		{
+			req, err := http.NewRequest("DELETE", "/v1/service/"+service.ServiceName+"/"+service.ID, nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ServiceRegistrationRequest(respW, req)
+			require.NoError(err)
+			require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		}
+
+		// The service no longer has registrations
+		{
+			req, err := http.NewRequest("GET", "/v1/service/"+service.ServiceName, nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ServiceRegistrationRequest(respW, req)
+			require.NoError(err)
+			require.Empty(obj.([]*structs.ServiceRegistration))
+		}
+	})
+}
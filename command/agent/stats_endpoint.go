@@ -46,3 +46,25 @@ func (s *HTTPServer) ClientStatsRequest(resp http.ResponseWriter, req *http.Requ
 
 	return reply.HostStats, nil
 }
+
+func (s *HTTPServer) ClientHardwareRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Get the requested Node ID
+	requestedNode := req.URL.Query().Get("node_id")
+
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: requestedNode,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.ClientHardwareResponse
+	if err := s.clientNodeRPC(requestedNode, "ClientStats.Hardware", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return reply.Hardware, nil
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/stats"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -76,6 +77,37 @@ func TestClientStatsRequest(t *testing.T) {
 	})
 }
 
+func TestClientHardwareRequest(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Local node, local resp
+		{
+			req, err := http.NewRequest("GET", "/v1/client/hardware", nil)
+			require.Nil(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.ClientHardwareRequest(respW, req)
+			require.Nil(err)
+
+			hardware, ok := obj.(*stats.HardwareInventory)
+			require.True(ok)
+			require.NotEmpty(hardware.CPUs)
+		}
+
+		// Unknown node
+		{
+			req, err := http.NewRequest("GET", fmt.Sprintf("/v1/client/hardware?node_id=%s", uuid.Generate()), nil)
+			require.Nil(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ClientHardwareRequest(respW, req)
+			require.NotNil(err)
+			require.Contains(err.Error(), "Unknown node")
+		}
+	})
+}
+
 func TestClientStatsRequest_ACL(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
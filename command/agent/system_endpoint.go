@@ -1,8 +1,10 @@
 package agent
 
 import (
+	"io"
 	"net/http"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -11,11 +13,26 @@ func (s *HTTPServer) GarbageCollectRequest(resp http.ResponseWriter, req *http.R
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
 
-	var args structs.GenericRequest
+	// The request body is optional and restricts the collection
+	var gcRequest api.GarbageCollectRequest
+	if req.Body != nil {
+		if err := decodeBody(req, &gcRequest); err != nil && err != io.EOF {
+			return nil, CodedError(400, err.Error())
+		}
+	}
+
+	args := structs.GarbageCollectRequest{
+		TargetNamespace: gcRequest.Namespace,
+		JobID:           gcRequest.JobID,
+	}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
 
+	if args.JobID != "" && args.TargetNamespace == "" {
+		return nil, CodedError(400, "garbage collecting a job requires its namespace")
+	}
+
 	var gResp structs.GenericResponse
 	if err := s.agent.RPC("System.GarbageCollect", &args, &gResp); err != nil {
 		return nil, err
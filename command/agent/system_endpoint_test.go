@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/hashicorp/nomad/api"
 )
 
 func TestHTTP_SystemGarbageCollect(t *testing.T) {
@@ -23,6 +25,39 @@ func TestHTTP_SystemGarbageCollect(t *testing.T) {
 	})
 }
 
+func TestHTTP_SystemGarbageCollect_Target(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		// Make the HTTP request targeting a job
+		buf := encodeReq(api.GarbageCollectRequest{
+			Namespace: "default",
+			JobID:     "example",
+		})
+		req, err := http.NewRequest("PUT", "/v1/system/gc", buf)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		if _, err := s.Server.GarbageCollectRequest(respW, req); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		// A job without a namespace is rejected
+		buf = encodeReq(api.GarbageCollectRequest{JobID: "example"})
+		req, err = http.NewRequest("PUT", "/v1/system/gc", buf)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		respW = httptest.NewRecorder()
+
+		if _, err := s.Server.GarbageCollectRequest(respW, req); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
 func TestHTTP_ReconcileJobSummaries(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -29,6 +29,7 @@ client {
 	alloc_dir = "/tmp/alloc"
 	servers = ["a.b.c:80", "127.0.0.1:1234"]
 	node_class = "linux-medium-64bit"
+	node_pool = "linux"
 	meta {
 		foo = "bar"
 		baz = "zip"
@@ -38,6 +39,33 @@ client {
 		retry_max = 3
 		retry_interval = "15s"
 	}
+	maintenance_window {
+		cron = "0 2 * * SUN"
+		duration = "2h"
+		time_zone = "America/New_York"
+	}
+	readiness_gate "image-cache" {
+		command = "/usr/local/bin/check-image-cache"
+		args = ["-min", "3"]
+		interval = "5s"
+		timeout = "2s"
+	}
+	driver_remediation "docker" {
+		command = "/usr/local/bin/restart-docker"
+		args = ["-force"]
+		timeout = "30s"
+		backoff = "10s"
+		backoff_limit = "1m"
+	}
+	service_provider "f5" {
+		url = "https://lb-adapter.example.com/v1/pools/web"
+		timeout = "5s"
+	}
+	data_encryption {
+		enabled = true
+		vault_transit_key = "nomad-client"
+		vault_transit_mount = "secrets"
+	}
 	options {
 		foo = "bar"
 		baz = "zip"
@@ -46,6 +74,12 @@ client {
 		"/opt/myapp/etc" = "/etc"
 		"/opt/myapp/bin" = "/bin"
 	}
+	namespace_alloc_dirs {
+		"batch-tenants" = "/mnt/scratch/alloc"
+	}
+	job_type_alloc_dirs {
+		batch = "/mnt/batch/alloc"
+	}
 	network_interface = "eth0"
 	network_speed = 100
 	cpu_total_compute = 4444
@@ -57,6 +91,15 @@ client {
 	}
 	client_min_port = 1000
 	client_max_port = 2000
+	dynamic_users = true
+	dynamic_user_min_id = 60000
+	dynamic_user_max_id = 60999
+	disable_prefetch = true
+	migrate_bandwidth_limit = "50MB"
+	rpc_compression = true
+	enable_fault_injection = true
+	rpc_bandwidth_limit = "1MB"
+	realtime_runtime = "50ms"
 	max_kill_timeout = "10s"
 	stats {
 		data_points = 35
@@ -67,6 +110,7 @@ client {
 	gc_disk_usage_threshold = 82
 	gc_inode_usage_threshold = 91
 	gc_max_allocs = 50
+	max_task_events = 40
 	no_host_uuid = false
 }
 server {
@@ -80,6 +124,9 @@ server {
 	enabled_schedulers = ["test"]
 	node_gc_threshold = "12h"
 	job_gc_threshold = "12h"
+	batch_job_gc_threshold = "1h"
+	service_job_gc_threshold = "24h"
+	system_job_gc_threshold = "48h"
 	eval_gc_threshold = "12h"
 	deployment_gc_threshold = "12h"
 	heartbeat_grace   = "30s"
@@ -93,11 +140,60 @@ server {
 	non_voting_server = true
 	redundancy_zone = "foo"
 	upgrade_version = "0.8.0"
+	dispatch_payload_size_limit = "64KiB"
+	deployment_webhook_signing_key = "deployment-secret"
+	redact_env_vars = [ "*_PASSWORD", "AWS_SECRET_ACCESS_KEY" ]
 	encrypt = "abc"
 	server_join {
 		retry_join = [ "1.1.1.1", "2.2.2.2" ]
 		retry_max = 3
 		retry_interval = "15s"
+		retry_max_interval = "2m"
+	}
+	job_admission_webhook "require-meta" {
+		type = "validating"
+		url = "https://admission.example.com/validate"
+		timeout = "5s"
+		failure_policy = "ignore"
+	}
+	job_admission_rule "untrusted" {
+		datacenters = ["dc2"]
+		drivers = ["docker"]
+	}
+	namespace_defaults "finance" {
+		vault_cluster = "secrets"
+		consul_cluster = "edge"
+	}
+	job_signing "default" {
+		required = true
+		signer "release-bot" {
+			public_key = <<EOF
+-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEADXFRXX3lQICIxRalPeaiiKaD7jSQQ6fxtn8MuM98ook=
+-----END PUBLIC KEY-----
+EOF
+		}
+	}
+	scheduler_workers {
+		pinned {
+			service = 2
+			batch = 1
+		}
+		autoscale = true
+		max_workers = 8
+		ready_per_worker = 5
+		scale_interval = "30s"
+	}
+	client_relay {
+		enabled = true
+		rate_limit = 2.5
+		burst = 10
+	}
+	write_rate_limit {
+		token_rate = 5
+		token_burst = 10
+		namespace_rate = 20
+		namespace_burst = 40
 	}
 }
 acl {
@@ -105,6 +201,8 @@ acl {
 	token_ttl = "60s"
 	policy_ttl = "60s"
 	replication_token = "foobar"
+	token_max_expiration_ttl = "720h"
+	bootstrap_token_file = "/etc/nomad/bootstrap-token"
 }
 telemetry {
 	statsite_address = "127.0.0.1:1234"
@@ -116,6 +214,12 @@ telemetry {
 	publish_node_metrics = true
 	disable_tagged_metrics = true
 	backwards_compatible_metrics = true
+	prometheus_remote_write {
+		url = "https://prometheus.example.com/api/v1/write"
+		interval = "30s"
+		bearer_token_file = "/etc/nomad/prometheus-token"
+		ca_file = "/etc/nomad/prometheus-ca.pem"
+	}
 }
 leave_on_interrupt = true
 leave_on_terminate = true
@@ -146,6 +250,10 @@ consul {
 	auto_advertise = true
 	checks_use_advertise = true
 }
+consul {
+	name = "edge"
+	address = "127.0.0.1:9600"
+}
 vault {
 	address = "127.0.0.1:9500"
 	allow_unauthenticated = true
@@ -159,6 +267,15 @@ vault {
 	tls_server_name = "foobar"
 	tls_skip_verify = true
 	create_from_role = "test_role"
+	use_identity = true
+	jwt_auth_path = "jwt-nomad"
+	jwt_auth_role = "nomad-workloads"
+	allowed_namespaces = ["engineering", "finance"]
+}
+vault {
+	name = "secrets"
+	address = "127.0.0.1:9700"
+	create_from_role = "finance_role"
 }
 tls {
 	http = true
@@ -172,6 +289,15 @@ tls {
 	tls_prefer_server_cipher_suites = true
 	tls_cipher_suites = "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"
 	tls_min_version = "tls12"
+	auto {
+		enabled = true
+		provider = "builtin"
+		dir = "/tmp/tls"
+		ttl = "24h"
+		alt_names = ["nomad.example.com", "10.0.0.1"]
+		ca_key_file = "/path/to/ca-key.pem"
+		vault_pki_path = "pki"
+	}
 }
 sentinel {
 	import "foo" {
@@ -192,6 +318,38 @@ autopilot {
 	server_stabilization_time = "23057s"
 	enable_custom_upgrades = true
 }
+snapshot_agent {
+	enabled = true
+	interval = "30m"
+	retain = 12
+	local_path = "/opt/nomad/snapshots"
+	s3 {
+		bucket = "nomad-snapshots"
+		key_prefix = "global/"
+		region = "us-east-1"
+		endpoint = "https://s3.example.com"
+		force_path_style = true
+		access_key_id = "AKIAEXAMPLE"
+		secret_access_key = "secret"
+	}
+}
+external_dns {
+	enabled = true
+	zone = "nomad.example.com"
+	ttl = "15s"
+	interval = "10m"
+	route53 {
+		hosted_zone_id = "Z123EXAMPLE"
+		access_key_id = "AKIAEXAMPLE"
+		secret_access_key = "secret"
+	}
+}
+watchdog {
+	interval = "5s"
+	systemd_notify = true
+	exit_on_unhealthy = "10m"
+	exit_on_lost_quorum = "2m"
+}
 plugin "docker" {
   args = ["foo", "bar"]
   config {
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Variables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Listing without variables returns an empty list
+		{
+			req, err := http.NewRequest("GET", "/v1/vars", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariablesListRequest(respW, req)
+			require.NoError(err)
+			require.Empty(obj.([]*structs.VariableMetadata))
+			require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		}
+
+		// Writing a variable with a mismatched path fails
+		{
+			args := &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: "project/other"},
+				Items:            map[string]string{"password": "hunter2"},
+			}
+			req, err := http.NewRequest("PUT", "/v1/var/project/db", encodeReq(args))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), "does not match")
+		}
+
+		// Write a variable
+		{
+			args := &structs.VariableDecrypted{
+				Items: map[string]string{"password": "hunter2"},
+			}
+			req, err := http.NewRequest("PUT", "/v1/var/project/db", encodeReq(args))
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(err)
+			require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+			meta := obj.(*structs.VariableMetadata)
+			require.Equal("project/db", meta.Path)
+			require.NotZero(meta.CreateIndex)
+		}
+
+		// Read the variable
+		{
+			req, err := http.NewRequest("GET", "/v1/var/project/db", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(err)
+			require.Equal("hunter2", obj.(*structs.VariableDecrypted).Items["password"])
+		}
+
+		// List the variables
+		{
+			req, err := http.NewRequest("GET", "/v1/vars?prefix=project", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			obj, err := s.Server.VariablesListRequest(respW, req)
+			require.NoError(err)
+
+			list := obj.([]*structs.VariableMetadata)
+			require.Len(list, 1)
+			require.Equal("project/db", list[0].Path)
+		}
+
+		// Delete the variable
+		{
+			req, err := http.NewRequest("DELETE", "/v1/var/project/db", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.NoError(err)
+			require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		}
+
+		// Reading a missing variable returns a 404
+		{
+			req, err := http.NewRequest("GET", "/v1/var/project/db", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.Error(err)
+			codedErr, ok := err.(HTTPCodedError)
+			require.True(ok)
+			require.Equal(404, codedErr.Code())
+		}
+
+		// Unsupported methods are rejected
+		{
+			req, err := http.NewRequest("PATCH", "/v1/var/project/db", nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.VariableSpecificRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), ErrInvalidMethod)
+		}
+	})
+}
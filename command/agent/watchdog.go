@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// defaultWatchdogInterval is used when the watchdog configuration doesn't
+	// set the interval
+	defaultWatchdogInterval = 10 * time.Second
+)
+
+// watchdog periodically checks the health of the agent. It notifies systemd
+// while the agent is healthy and closes its exit channel when the agent stays
+// unhealthy for longer than configured, so that the agent exits and is
+// restarted by its service manager.
+type watchdog struct {
+	config *WatchdogConfig
+	health func() *agentSelfHealth
+	notify func(state string) error
+	logger log.Logger
+
+	// exitCh is closed when the agent must exit
+	exitCh chan struct{}
+
+	// ready is true once systemd was notified that the agent is ready
+	ready bool
+
+	// unhealthySince and noLeaderSince are the times since which the agent
+	// is unhealthy and the server has no leader, or zero
+	unhealthySince time.Time
+	noLeaderSince  time.Time
+}
+
+// setupWatchdog starts the watchdog if the agent configures it.
+func (a *Agent) setupWatchdog() error {
+	if a.config.Watchdog == nil {
+		return nil
+	}
+	if err := a.config.Watchdog.Validate(); err != nil {
+		return fmt.Errorf("invalid watchdog configuration: %v", err)
+	}
+
+	a.watchdog = newWatchdog(a.config.Watchdog, a.selfHealth, a.logger.Named("watchdog"))
+	go a.watchdog.run(a.shutdownCh)
+	return nil
+}
+
+// WatchdogExitCh returns a channel closed when the watchdog found the agent
+// unhealthy for longer than configured. It is nil if the watchdog is
+// disabled.
+func (a *Agent) WatchdogExitCh() <-chan struct{} {
+	if a.watchdog == nil {
+		return nil
+	}
+	return a.watchdog.exitCh
+}
+
+func newWatchdog(conf *WatchdogConfig, health func() *agentSelfHealth, logger log.Logger) *watchdog {
+	return &watchdog{
+		config: conf,
+		health: health,
+		notify: notifySystemd,
+		logger: logger,
+		exitCh: make(chan struct{}),
+	}
+}
+
+func (w *watchdog) run(shutdownCh <-chan struct{}) {
+	interval := w.config.Interval
+	if interval == 0 {
+		interval = defaultWatchdogInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if w.check(time.Now()) {
+			close(w.exitCh)
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-shutdownCh:
+			return
+		}
+	}
+}
+
+// check checks the health of the agent at the given time and returns whether
+// the agent must exit.
+func (w *watchdog) check(now time.Time) bool {
+	health := w.health()
+
+	if health.Healthy {
+		if !w.unhealthySince.IsZero() {
+			w.logger.Info("agent is healthy again", "unhealthy_for", now.Sub(w.unhealthySince))
+		}
+		w.unhealthySince = time.Time{}
+		if w.config.SystemdNotify {
+			w.notifyHealthy()
+		}
+	} else if w.unhealthySince.IsZero() {
+		w.unhealthySince = now
+		w.logger.Warn("agent is unhealthy", "subsystems", unhealthySubsystems(health))
+	}
+
+	// Servers without a leader lost the quorum of the cluster or are
+	// partitioned from it
+	if leadership, ok := health.Subsystems[selfHealthLeadership]; ok && !leadership.Healthy {
+		if w.noLeaderSince.IsZero() {
+			w.noLeaderSince = now
+		}
+	} else {
+		w.noLeaderSince = time.Time{}
+	}
+
+	if d := w.config.ExitOnUnhealthy; d > 0 && !w.unhealthySince.IsZero() && now.Sub(w.unhealthySince) >= d {
+		w.logger.Error("agent unhealthy for too long, exiting",
+			"unhealthy_for", now.Sub(w.unhealthySince), "subsystems", unhealthySubsystems(health))
+		return true
+	}
+	if d := w.config.ExitOnLostQuorum; d > 0 && !w.noLeaderSince.IsZero() && now.Sub(w.noLeaderSince) >= d {
+		w.logger.Error("server without a leader for too long, exiting", "no_leader_for", now.Sub(w.noLeaderSince))
+		return true
+	}
+	return false
+}
+
+// notifyHealthy notifies systemd that the agent is ready the first time it is
+// healthy, and sends it a watchdog keep-alive.
+func (w *watchdog) notifyHealthy() {
+	if !w.ready {
+		if err := w.notify("READY=1"); err != nil {
+			w.logger.Warn("failed to notify systemd", "error", err)
+			return
+		}
+		w.ready = true
+	}
+	if err := w.notify("WATCHDOG=1"); err != nil {
+		w.logger.Warn("failed to notify systemd", "error", err)
+	}
+}
+
+// unhealthySubsystems returns the sorted names of the critical subsystems
+// that are unhealthy.
+func unhealthySubsystems(health *agentSelfHealth) []string {
+	var names []string
+	for name, s := range health.Subsystems {
+		if s.Critical && !s.Healthy {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// notifySystemd sends the state to systemd through the socket set in
+// NOTIFY_SOCKET. It does nothing if the agent isn't run by systemd as a
+// notify service.
+func notifySystemd(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return err
+}
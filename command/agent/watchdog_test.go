@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+// testWatchdog returns a watchdog checking the returned health, and the
+// states it notified systemd of.
+func testWatchdog(t *testing.T, conf *WatchdogConfig) (*watchdog, *agentSelfHealth, *[]string) {
+	health := &agentSelfHealth{
+		Healthy: true,
+		Subsystems: map[string]*subsystemHealth{
+			selfHealthLeadership: {Healthy: true, Critical: true},
+		},
+	}
+	var notified []string
+
+	w := newWatchdog(conf, func() *agentSelfHealth { return health }, testlog.HCLogger(t))
+	w.notify = func(state string) error {
+		notified = append(notified, state)
+		return nil
+	}
+	return w, health, &notified
+}
+
+func TestWatchdog_SystemdNotify(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	w, health, notified := testWatchdog(t, &WatchdogConfig{SystemdNotify: true})
+	now := time.Now()
+
+	// Unhealthy agents aren't ready
+	health.Healthy = false
+	require.False(w.check(now))
+	require.Empty(*notified)
+
+	health.Healthy = true
+	require.False(w.check(now.Add(time.Second)))
+	require.False(w.check(now.Add(2 * time.Second)))
+	require.Equal([]string{"READY=1", "WATCHDOG=1", "WATCHDOG=1"}, *notified)
+}
+
+func TestWatchdog_ExitOnUnhealthy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	w, health, notified := testWatchdog(t, &WatchdogConfig{ExitOnUnhealthy: time.Minute})
+	now := time.Now()
+
+	health.Healthy = false
+	require.False(w.check(now))
+	require.False(w.check(now.Add(30 * time.Second)))
+
+	// Recovering resets the timer
+	health.Healthy = true
+	require.False(w.check(now.Add(40 * time.Second)))
+	health.Healthy = false
+	require.False(w.check(now.Add(90 * time.Second)))
+	require.True(w.check(now.Add(150 * time.Second)))
+
+	// Systemd isn't notified unless enabled
+	require.Empty(*notified)
+}
+
+func TestWatchdog_ExitOnLostQuorum(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	w, health, _ := testWatchdog(t, &WatchdogConfig{ExitOnLostQuorum: time.Minute})
+	now := time.Now()
+
+	health.Healthy = false
+	health.Subsystems[selfHealthLeadership].Healthy = false
+	require.False(w.check(now))
+
+	// Electing a leader resets the timer
+	health.Subsystems[selfHealthLeadership].Healthy = true
+	require.False(w.check(now.Add(30 * time.Second)))
+	health.Subsystems[selfHealthLeadership].Healthy = false
+	require.False(w.check(now.Add(80 * time.Second)))
+	require.True(w.check(now.Add(140 * time.Second)))
+}
+
+func TestWatchdog_Disabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	w, health, _ := testWatchdog(t, &WatchdogConfig{})
+	now := time.Now()
+
+	health.Healthy = false
+	health.Subsystems[selfHealthLeadership].Healthy = false
+	require.False(w.check(now))
+	require.False(w.check(now.Add(24 * time.Hour)))
+}
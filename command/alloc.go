@@ -25,6 +25,14 @@ Usage: nomad alloc <subcommand> [options] [args]
 
       $ nomad alloc logs -f <alloc-id> <task>
 
+  Restart the tasks of an allocation in order, the leader task last:
+
+      $ nomad alloc restart -all-tasks -ordered <alloc-id>
+
+  Reschedule a failed allocation without waiting for its reschedule delay:
+
+      $ nomad alloc reschedule-now <alloc-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
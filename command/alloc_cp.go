@@ -0,0 +1,284 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocCpCommand struct {
+	Meta
+}
+
+func (c *AllocCpCommand) Help() string {
+	helpText := `
+Usage: nomad alloc cp [options] <allocation>:<path> <local-path>
+       nomad alloc cp [options] <local-path> <allocation>:<path>
+
+  Copy files or directories between an allocation directory and the local
+  filesystem. The allocation path is relative to the root of the alloc dir.
+  Directories are copied recursively.
+
+  If the destination is an existing directory, the source is copied into it
+  under its own name. Otherwise the source is copied to the destination path.
+  Copying into an allocation creates the missing parent directories of the
+  files, but empty directories are not copied.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Cp Specific Options:
+
+  -verbose
+    Show each file as it is copied.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocCpCommand) Synopsis() string {
+	return "Copy files in and out of an allocation directory"
+}
+
+func (c *AllocCpCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *AllocCpCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Allocs)
+}
+
+func (c *AllocCpCommand) Name() string { return "alloc cp" }
+
+func (c *AllocCpCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got a source and a destination
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <source> <destination>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	srcAlloc, srcPath := splitAllocPath(args[0])
+	destAlloc, destPath := splitAllocPath(args[1])
+	if (srcAlloc == "") == (destAlloc == "") {
+		c.Ui.Error("Exactly one of the source and destination must be an allocation path in the form <allocation>:<path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	allocID := srcAlloc
+	if allocID == "" {
+		allocID = destAlloc
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		// Format the allocs
+		out := formatAllocListStubs(allocs, verbose, shortId)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+	// Prefix lookup matched a single allocation
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	cp := &allocCopier{
+		ui:      c.Ui.Output,
+		fs:      client.AllocFS(),
+		alloc:   alloc,
+		verbose: verbose,
+	}
+	if srcAlloc != "" {
+		err = cp.copyOut(srcPath, destPath)
+	} else {
+		err = cp.copyIn(srcPath, destPath)
+	}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	return 0
+}
+
+// splitAllocPath splits an argument in the form <allocation>:<path> into the
+// allocation ID prefix and the path. Arguments that are not allocation paths
+// return an empty allocation. A path prefix containing a path separator or
+// made of a single character, such as a Windows drive letter, is treated as a
+// local path.
+func splitAllocPath(arg string) (string, string) {
+	idx := strings.Index(arg, ":")
+	if idx <= 1 || strings.ContainsAny(arg[:idx], `/\`) {
+		return "", arg
+	}
+
+	p := arg[idx+1:]
+	if p == "" {
+		p = "/"
+	}
+	return arg[:idx], p
+}
+
+// allocCopier copies files between an allocation directory and the local
+// filesystem.
+type allocCopier struct {
+	ui      func(string)
+	fs      *api.AllocFS
+	alloc   *api.Allocation
+	verbose bool
+}
+
+// copyOut copies the file or directory at the allocation path to the local
+// path.
+func (c *allocCopier) copyOut(src, dest string) error {
+	info, _, err := c.fs.Stat(c.alloc, src, nil)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+
+	// Copy into the destination if it is an existing directory
+	if st, err := os.Stat(dest); err == nil && st.IsDir() {
+		dest = filepath.Join(dest, path.Base(path.Clean("/"+src)))
+	}
+
+	if !info.IsDir {
+		return c.copyFileOut(src, dest)
+	}
+	return c.copyDirOut(src, dest)
+}
+
+func (c *allocCopier) copyDirOut(src, dest string) error {
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("Error creating directory %q: %v", dest, err)
+	}
+
+	files, _, err := c.fs.List(c.alloc, src, nil)
+	if err != nil {
+		return fmt.Errorf("Error listing %q: %v", src, err)
+	}
+	for _, file := range files {
+		s := path.Join(src, file.Name)
+		d := filepath.Join(dest, file.Name)
+		if file.IsDir {
+			err = c.copyDirOut(s, d)
+		} else {
+			err = c.copyFileOut(s, d)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *allocCopier) copyFileOut(src, dest string) error {
+	r, err := c.fs.Cat(c.alloc, src, nil)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+	defer r.Close()
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("Error creating file %q: %v", dest, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("Error copying %q: %v", src, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Error writing file %q: %v", dest, err)
+	}
+
+	if c.verbose {
+		c.ui(fmt.Sprintf("%s -> %s", src, dest))
+	}
+	return nil
+}
+
+// copyIn copies the local file or directory to the allocation path.
+func (c *allocCopier) copyIn(src, dest string) error {
+	st, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+
+	// Copy into the destination if it is an existing directory
+	if info, _, err := c.fs.Stat(c.alloc, dest, nil); err == nil && info.IsDir {
+		dest = path.Join(dest, filepath.Base(src))
+	}
+
+	if !st.IsDir() {
+		return c.copyFileIn(src, dest, st.Mode())
+	}
+
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("Error reading %q: %v", p, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		return c.copyFileIn(p, path.Join(dest, filepath.ToSlash(rel)), info.Mode())
+	})
+}
+
+func (c *allocCopier) copyFileIn(src, dest string, mode os.FileMode) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", src, err)
+	}
+	defer f.Close()
+
+	if err := c.fs.Write(c.alloc, dest, mode, f, nil); err != nil {
+		return fmt.Errorf("Error writing %q: %v", dest, err)
+	}
+
+	if c.verbose {
+		c.ui(fmt.Sprintf("%s -> %s", src, dest))
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocCpCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &AllocCpCommand{}
+}
+
+func TestAllocCpCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &AllocCpCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails without an allocation path
+	if code := cmd.Run([]string{"foo", "bar"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Exactly one of the source and destination") {
+		t.Fatalf("expected allocation path error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "foobar:/alloc", "bar"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying allocation") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing alloc
+	if code := cmd.Run([]string{"-address=" + url, "26470238:/alloc", "bar"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "No allocation(s) with prefix or id") {
+		t.Fatalf("expected not found error, got: %s", out)
+	}
+}
+
+func TestAllocCpCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		if _, ok := nodes[0].Drivers["mock_driver"]; !ok {
+			return false, fmt.Errorf("mock_driver not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Register a long running job
+	job := testJob("job1")
+	job.Type = helper.StringToPtr(api.JobTypeService)
+	job.TaskGroups[0].Tasks[0].Config["run_for"] = "30s"
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	var allocID string
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations("job1", false, nil)
+		if err != nil {
+			return false, err
+		}
+		if len(allocs) != 1 || allocs[0].ClientStatus != api.AllocClientStatusRunning {
+			return false, fmt.Errorf("allocation not running")
+		}
+		allocID = allocs[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Create a local directory to copy in
+	tmp, err := ioutil.TempDir("", "nomad-alloc-cp")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	src := filepath.Join(tmp, "debug")
+	require.NoError(os.MkdirAll(filepath.Join(src, "scripts"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(src, "foo"), []byte("foo"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(src, "scripts", "bar.sh"), []byte("bar"), 0755))
+
+	// Copy the directory into an existing directory of the allocation
+	ui := new(cli.MockUi)
+	cmd := &AllocCpCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + url, src, allocID[:8] + ":alloc/data"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	// Copy it back out to a new local directory
+	dest := filepath.Join(tmp, "out")
+	ui = new(cli.MockUi)
+	cmd = &AllocCpCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + url, "-verbose", allocID + ":alloc/data/debug", dest}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	require.Contains(ui.OutputWriter.String(), "alloc/data/debug/scripts/bar.sh")
+
+	contents, err := ioutil.ReadFile(filepath.Join(dest, "foo"))
+	require.NoError(err)
+	require.Equal("foo", string(contents))
+
+	contents, err = ioutil.ReadFile(filepath.Join(dest, "scripts", "bar.sh"))
+	require.NoError(err)
+	require.Equal("bar", string(contents))
+
+	// Copy a single file out into an existing directory
+	ui = new(cli.MockUi)
+	cmd = &AllocCpCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + url, allocID + ":alloc/data/debug/foo", tmp}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	contents, err = ioutil.ReadFile(filepath.Join(tmp, "foo"))
+	require.NoError(err)
+	require.Equal("foo", string(contents))
+}
+
+func TestSplitAllocPath(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Arg   string
+		Alloc string
+		Path  string
+	}{
+		{"26470238:/alloc/data", "26470238", "/alloc/data"},
+		{"26470238:", "26470238", "/"},
+		{"local/file", "", "local/file"},
+		{"./foo:bar", "", "./foo:bar"},
+		{`C:\Users\foo`, "", `C:\Users\foo`},
+	}
+
+	for _, c := range cases {
+		alloc, path := splitAllocPath(c.Arg)
+		require.Equal(t, c.Alloc, alloc, c.Arg)
+		require.Equal(t, c.Path, path, c.Arg)
+	}
+}
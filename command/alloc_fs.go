@@ -95,18 +95,7 @@ func (c *AllocFSCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (f *AllocFSCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := f.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Allocs]
-	})
+	return f.Meta.PredictSearch(contexts.Allocs)
 }
 
 func (f *AllocFSCommand) Name() string { return "alloc fs" }
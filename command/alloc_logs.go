@@ -34,6 +34,9 @@ Logs Specific Options:
   -stderr
     Display stderr logs.
 
+  -eventlog
+    Display the Windows Event Log entries collected for the task.
+
   -verbose
     Show full information.
 
@@ -65,35 +68,25 @@ func (l *AllocLogsCommand) Synopsis() string {
 func (c *AllocLogsCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-stderr":  complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
-			"-job":     complete.PredictAnything,
-			"-f":       complete.PredictNothing,
-			"-tail":    complete.PredictAnything,
-			"-n":       complete.PredictAnything,
-			"-c":       complete.PredictAnything,
+			"-stderr":   complete.PredictNothing,
+			"-eventlog": complete.PredictNothing,
+			"-verbose":  complete.PredictNothing,
+			"-job":      complete.PredictAnything,
+			"-f":        complete.PredictNothing,
+			"-tail":     complete.PredictAnything,
+			"-n":        complete.PredictAnything,
+			"-c":        complete.PredictAnything,
 		})
 }
 
 func (l *AllocLogsCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := l.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Allocs]
-	})
+	return l.Meta.PredictSearch(contexts.Allocs)
 }
 
 func (l *AllocLogsCommand) Name() string { return "alloc logs" }
 
 func (l *AllocLogsCommand) Run(args []string) int {
-	var verbose, job, tail, stderr, follow bool
+	var verbose, job, tail, stderr, eventlog, follow bool
 	var numLines, numBytes int64
 
 	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
@@ -103,12 +96,20 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	flags.BoolVar(&tail, "tail", false, "")
 	flags.BoolVar(&follow, "f", false, "")
 	flags.BoolVar(&stderr, "stderr", false, "")
+	flags.BoolVar(&eventlog, "eventlog", false, "")
 	flags.Int64Var(&numLines, "n", -1, "")
 	flags.Int64Var(&numBytes, "c", -1, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
+
+	if stderr && eventlog {
+		l.Ui.Error("The -stderr and -eventlog flags are mutually exclusive")
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
 	args = flags.Args()
 
 	if numArgs := len(args); numArgs < 1 {
@@ -212,6 +213,8 @@ func (l *AllocLogsCommand) Run(args []string) int {
 	logType := "stdout"
 	if stderr {
 		logType = "stderr"
+	} else if eventlog {
+		logType = "eventlog"
 	}
 
 	// We have a file, output it.
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocRescheduleNowCommand struct {
+	Meta
+}
+
+func (c *AllocRescheduleNowCommand) Help() string {
+	helpText := `
+Usage: nomad alloc reschedule-now [options] <allocation>
+
+  Reschedule-now is used to reschedule a failed allocation without waiting for
+  the remaining delay of its reschedule policy to elapse. The allocation must
+  have reschedule attempts left.
+
+  Upon success, the evaluation that reschedules the allocation will be
+  monitored. This can be disabled by supplying the detach flag.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Reschedule-Now Options:
+
+  -detach
+    Return immediately instead of entering monitor mode. The evaluation ID
+    will be printed to the screen, which can be used to examine the
+    evaluation using the eval status command.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocRescheduleNowCommand) Synopsis() string {
+	return "Reschedule a failed allocation without delay"
+}
+
+func (c *AllocRescheduleNowCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-detach":  complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *AllocRescheduleNowCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Allocs)
+}
+
+func (c *AllocRescheduleNowCommand) Name() string { return "alloc reschedule-now" }
+
+func (c *AllocRescheduleNowCommand) Run(args []string) int {
+	var detach, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one allocation ID
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <allocation>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	allocID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	if len(allocID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Identifier must contain at least two characters."))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, verbose, length)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Allocations().RescheduleNow(alloc, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rescheduling allocation: %s", err))
+		return 1
+	}
+
+	if detach {
+		c.Ui.Output("Evaluation ID: " + resp.EvalID)
+		return 0
+	}
+
+	mon := newMonitor(c.Ui, client, length)
+	return mon.monitor(resp.EvalID, false)
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocRescheduleNowCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &AllocRescheduleNowCommand{}
+}
+
+func TestAllocRescheduleNowCommand_Fails(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &AllocRescheduleNowCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on alloc lookup failure
+	if code := cmd.Run([]string{"-address=" + url, "3E55C771-76FC-423B-BCED-3E5314F433B1"}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "No allocation(s) with prefix or id") {
+		t.Fatalf("expect not found error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying allocation") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestAllocRescheduleNowCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	failed := mock.Alloc()
+	failed.ClientStatus = structs.AllocClientStatusFailed
+	running := mock.Alloc()
+	require.NoError(state.UpsertJobSummary(998, mock.JobSummary(failed.JobID)))
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(running.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{failed, running}))
+
+	ui := new(cli.MockUi)
+	cmd := &AllocRescheduleNowCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-detach", failed.ID}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Evaluation ID: ")
+
+	// Only failed allocations can be rescheduled
+	ui = new(cli.MockUi)
+	cmd = &AllocRescheduleNowCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, cmd.Run([]string{"-address=" + url, running.ID}))
+	require.Contains(ui.ErrorWriter.String(), "is not failed")
+}
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocRestartCommand struct {
+	Meta
+}
+
+func (c *AllocRestartCommand) Help() string {
+	helpText := `
+Usage: nomad alloc restart [options] <allocation> [<task>]
+
+  Restart the tasks of an allocation in place, on the node it is running on.
+  If a task is given, only that task is restarted. Otherwise all the running
+  tasks of the allocation are restarted at the same time.
+
+  Restarting the tasks in order is useful for allocations whose sidecar tasks
+  must be refreshed together with the main task. The tasks of the group that
+  aren't its leader are then restarted first, one at a time and in the order
+  they are defined, followed by the leader task. Each task must be running
+  again before the next task is restarted.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Restart Options:
+
+  -all-tasks
+    Restart all the tasks of the allocation. This is the default if no task
+    is given, and can't be combined with a task.
+
+  -ordered
+    Restart the tasks one at a time, with the leader task last, instead of
+    restarting them at the same time. Requires restarting all the tasks.
+
+  -task=<task>
+    Restart only the task. Can be used instead of the task argument.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocRestartCommand) Synopsis() string {
+	return "Restart the tasks of an allocation in place"
+}
+
+func (c *AllocRestartCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-all-tasks": complete.PredictNothing,
+			"-ordered":   complete.PredictNothing,
+			"-task":      complete.PredictAnything,
+			"-verbose":   complete.PredictNothing,
+		})
+}
+
+func (c *AllocRestartCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Allocs)
+}
+
+func (c *AllocRestartCommand) Name() string { return "alloc restart" }
+
+func (c *AllocRestartCommand) Run(args []string) int {
+	var allTasks, ordered, verbose bool
+	var task string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&allTasks, "all-tasks", false, "")
+	flags.BoolVar(&ordered, "ordered", false, "")
+	flags.StringVar(&task, "task", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got the allocation ID and at most one task
+	args = flags.Args()
+	if l := len(args); l < 1 || l > 2 {
+		c.Ui.Error("This command takes one or two arguments: <allocation> [<task>]")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	allocID := args[0]
+	if len(args) == 2 {
+		if task != "" && task != args[1] {
+			c.Ui.Error("The -task flag and the task argument name different tasks")
+			return 1
+		}
+		task = args[1]
+	}
+
+	if task != "" && allTasks {
+		c.Ui.Error("The -all-tasks flag can't be combined with a task")
+		return 1
+	}
+	if task != "" && ordered {
+		c.Ui.Error("The -ordered flag requires restarting all the tasks")
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	if len(allocID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Identifier must contain at least two characters."))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, verbose, length)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	req := &api.AllocRestartRequest{
+		TaskName: task,
+		Ordered:  ordered,
+	}
+	if _, err := client.Allocations().Restart(alloc, req, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error restarting allocation: %s", err))
+		return 1
+	}
+
+	if task != "" {
+		c.Ui.Output(fmt.Sprintf("Restarted task %q of allocation %q", task, limit(alloc.ID, length)))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Restarted the tasks of allocation %q", limit(alloc.ID, length)))
+	}
+	return 0
+}
@@ -43,9 +43,18 @@ Alloc Status Options:
   -verbose
     Show full information.
 
+  -events-only
+    Display only the events of each task, with the duration since the previous
+    event of the task. Combined with -json, -output or -t, the events are
+    exported by task with their timestamps.
+
   -json
     Output the allocation in its JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display allocation using a Go template.
 `
@@ -60,46 +69,48 @@ func (c *AllocStatusCommand) Synopsis() string {
 func (c *AllocStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-short":   complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-t":       complete.PredictAnything,
+			"-short":       complete.PredictNothing,
+			"-verbose":     complete.PredictNothing,
+			"-events-only": complete.PredictNothing,
+			"-json":        complete.PredictNothing,
+			"-output":      complete.PredictSet("json", "yaml", "template"),
+			"-t":           complete.PredictAnything,
 		})
 }
 
 func (c *AllocStatusCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Allocs]
-	})
+	return c.Meta.PredictSearch(contexts.Allocs)
 }
 
 func (c *AllocStatusCommand) Name() string { return "alloc status" }
 
 func (c *AllocStatusCommand) Run(args []string) int {
-	var short, displayStats, verbose, json bool
-	var tmpl string
+	var short, displayStats, verbose, eventsOnly bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&short, "short", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&displayStats, "stats", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&eventsOnly, "events-only", false, "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if eventsOnly && (short || displayStats) {
+		c.Ui.Error("-events-only can't be combined with -short or -stats")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Check that we got exactly one allocation ID
 	args = flags.Args()
 
@@ -111,14 +122,14 @@ func (c *AllocStatusCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the allocations data list
-	if len(args) == 0 && json || len(tmpl) > 0 {
+	if len(args) == 0 && format.enabled() && !eventsOnly {
 		allocs, _, err := client.Allocations().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying allocations: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, allocs)
+		out, err := format.Format(allocs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -171,9 +182,13 @@ func (c *AllocStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if eventsOnly {
+		return c.outputEvents(alloc, format)
+	}
+
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, alloc)
+	if format.enabled() {
+		out, err := format.Format(alloc)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -344,20 +359,85 @@ func (c *AllocStatusCommand) outputTaskStatus(state *api.TaskState) {
 	c.Ui.Output("")
 
 	c.Ui.Output("Recent Events:")
-	events := make([]string, len(state.Events)+1)
-	events[0] = "Time|Type|Description"
+	c.Ui.Output(formatTaskEvents(state.Events))
+}
 
-	size := len(state.Events)
-	for i, event := range state.Events {
+// formatTaskEvents formats the events of a task from the most recent one, with
+// the duration since the previous event.
+func formatTaskEvents(taskEvents []*api.TaskEvent) string {
+	events := make([]string, len(taskEvents)+1)
+	events[0] = "Time|Since Previous|Type|Description"
+
+	size := len(taskEvents)
+	for i, event := range taskEvents {
 		msg := event.DisplayMessage
 		if msg == "" {
 			msg = buildDisplayMessage(event)
 		}
 		formattedTime := formatUnixNanoTime(event.Time)
-		events[size-i] = fmt.Sprintf("%s|%s|%s", formattedTime, event.Type, msg)
+		since := "N/A"
+		if i > 0 {
+			since = formatTimeDifference(time.Unix(0, taskEvents[i-1].Time), time.Unix(0, event.Time), time.Millisecond)
+		}
+		events[size-i] = fmt.Sprintf("%s|%s|%s|%s", formattedTime, since, event.Type, msg)
 		// Reverse order so we are sorted by time
 	}
-	c.Ui.Output(formatList(events))
+	return formatList(events)
+}
+
+// exportedTaskEvent is a task event exported by -events-only, with its
+// timestamp and the duration since the previous event of the task.
+type exportedTaskEvent struct {
+	*api.TaskEvent
+	Timestamp     time.Time
+	SincePrevious time.Duration
+}
+
+// exportTaskEvents returns the events of each task of the allocation, from the
+// earliest one.
+func exportTaskEvents(alloc *api.Allocation) map[string][]*exportedTaskEvent {
+	out := make(map[string][]*exportedTaskEvent, len(alloc.TaskStates))
+	for task, state := range alloc.TaskStates {
+		events := make([]*exportedTaskEvent, 0, len(state.Events))
+		for i, event := range state.Events {
+			e := &exportedTaskEvent{
+				TaskEvent: event,
+				Timestamp: time.Unix(0, event.Time).UTC(),
+			}
+			if i > 0 {
+				e.SincePrevious = time.Duration(event.Time - state.Events[i-1].Time)
+			}
+			events = append(events, e)
+		}
+		out[task] = events
+	}
+	return out
+}
+
+// outputEvents prints or exports the events of each task of the allocation.
+func (c *AllocStatusCommand) outputEvents(alloc *api.Allocation, format outputFormat) int {
+	if format.enabled() {
+		out, err := format.Format(exportTaskEvents(alloc))
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	first := true
+	for task := range c.sortedTaskStateIterator(alloc.TaskStates) {
+		if !first {
+			c.Ui.Output("")
+		}
+		first = false
+
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]Task %q events[reset]", task)))
+		c.Ui.Output(formatTaskEvents(alloc.TaskStates[task].Events))
+	}
+	return 0
 }
 
 func buildDisplayMessage(event *api.TaskEvent) string {
@@ -464,6 +544,11 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		desc = event.DriverMessage
 	case api.TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case api.TaskUnhealthyCheck:
+		desc = fmt.Sprintf("Restarting due to unhealthy check %q", event.Details["check_name"])
+		if output := event.Details["check_output"]; output != "" {
+			desc = fmt.Sprintf("%s: %s", desc, output)
+		}
 	default:
 		desc = event.Message
 	}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -284,6 +285,55 @@ func TestAllocStatusCommand_ScoreMetrics(t *testing.T) {
 	require.Contains(out, "final score")
 }
 
+func TestAllocStatusCommand_EventsOnly(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &AllocStatusCommand{Meta: Meta{Ui: ui}}
+
+	start := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	a := mock.Alloc()
+	a.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State: structs.TaskStateRunning,
+			Events: []*structs.TaskEvent{
+				{Type: structs.TaskReceived, Time: start.UnixNano(), DisplayMessage: "Task received by client"},
+				{Type: structs.TaskStarted, Time: start.Add(1500 * time.Millisecond).UnixNano(), DisplayMessage: "Task started by client"},
+			},
+		},
+	}
+	require.NoError(srv.Agent.Server().State().UpsertAllocs(1000, []*structs.Allocation{a}))
+
+	// Fails combined with -short
+	require.Equal(1, cmd.Run([]string{"-address=" + url, "-events-only", "-short", a.ID}))
+	require.Contains(ui.ErrorWriter.String(), "-events-only can't be combined")
+	ui.ErrorWriter.Reset()
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-events-only", a.ID}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, `Task "web" events`)
+	require.Contains(out, "Since Previous")
+	require.Regexp(regexp.MustCompile(`1\.5s\s+Started`), out)
+	require.NotContains(out, "Client Status")
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-events-only", "-json", a.ID}))
+	var events map[string][]struct {
+		Type          string
+		Timestamp     time.Time
+		SincePrevious time.Duration
+	}
+	require.NoError(json.Unmarshal(ui.OutputWriter.Bytes(), &events))
+	require.Len(events["web"], 2)
+	require.Equal(structs.TaskReceived, events["web"][0].Type)
+	require.True(start.Equal(events["web"][0].Timestamp))
+	require.Zero(events["web"][0].SincePrevious)
+	require.Equal(1500*time.Millisecond, events["web"][1].SincePrevious)
+}
+
 func TestAllocStatusCommand_AutocompleteArgs(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
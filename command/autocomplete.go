@@ -0,0 +1,92 @@
+package command
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+// PredictSearch returns a predictor completing the IDs of the given context
+// by prefix searching the cluster. The client honors the -address, -region,
+// -namespace, -token and TLS flags already typed on the command line.
+func (m *Meta) PredictSearch(ctx contexts.Context) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := m.autocompleteClient(a)
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, ctx, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[ctx]
+	})
+}
+
+// autocompleteClient returns an API client configured by the client flags of
+// the arguments completed so far. Flags are not parsed when completing, so
+// without this the client would ignore them and query the default agent.
+func (m *Meta) autocompleteClient(a complete.Args) (*api.Client, error) {
+	return m.autocompleteMeta(a).Client()
+}
+
+// autocompleteMeta returns a copy of the meta with the client flags of the
+// completed arguments applied.
+func (m *Meta) autocompleteMeta(a complete.Args) *Meta {
+	meta := *m
+	stringFlags := map[string]*string{
+		"address":     &meta.flagAddress,
+		"region":      &meta.region,
+		"namespace":   &meta.namespace,
+		"token":       &meta.token,
+		"ca-cert":     &meta.caCert,
+		"ca-path":     &meta.caPath,
+		"client-cert": &meta.clientCert,
+		"client-key":  &meta.clientKey,
+	}
+
+	args := a.Completed
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if idx := strings.Index(name, "="); idx != -1 {
+			name, value, hasValue = name[:idx], name[idx+1:], true
+		}
+
+		switch name {
+		case "insecure", "tls-skip-verify":
+			meta.insecure = true
+			if hasValue {
+				meta.insecure, _ = strconv.ParseBool(value)
+			}
+			continue
+		}
+
+		target, ok := stringFlags[name]
+		if !ok {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				continue
+			}
+			i++
+			value = args[i]
+		}
+		*target = value
+	}
+
+	return &meta
+}
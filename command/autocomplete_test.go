@@ -0,0 +1,64 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeta_AutocompleteMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	m := &Meta{Ui: new(cli.MockUi), flagAddress: "http://default:4646", region: "global"}
+	args := complete.Args{
+		Completed: []string{
+			"status", "-address=http://other:4646", "-namespace", "dev",
+			"-token", "secret", "-tls-skip-verify", "-verbose", "-ca-cert",
+		},
+	}
+
+	meta := m.autocompleteMeta(args)
+	require.Equal("http://other:4646", meta.flagAddress)
+	require.Equal("global", meta.region)
+	require.Equal("dev", meta.namespace)
+	require.Equal("secret", meta.token)
+	require.True(meta.insecure)
+	require.Empty(meta.caCert)
+
+	// The meta options are left untouched
+	require.Equal("http://default:4646", m.flagAddress)
+	require.Empty(m.namespace)
+	require.False(m.insecure)
+}
+
+func TestMeta_PredictSearch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	require.NoError(state.UpsertJob(1000, j))
+
+	// The address given on the command line is used over the default one
+	m := &Meta{Ui: new(cli.MockUi), flagAddress: "http://127.0.0.1:1"}
+	predictor := m.PredictSearch(contexts.Jobs)
+
+	args := complete.Args{
+		Completed: []string{"status", "-address", url},
+		Last:      j.ID[:len(j.ID)-5],
+	}
+	res := predictor.Predict(args)
+	require.Equal([]string{j.ID}, res)
+
+	// Searching another namespace does not find the job
+	args.Completed = append(args.Completed, "-namespace=other")
+	require.Empty(predictor.Predict(args))
+}
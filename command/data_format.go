@@ -2,11 +2,16 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/ugorji/go/codec"
+	yaml "gopkg.in/yaml.v2"
 )
 
 var (
@@ -30,6 +35,11 @@ func DataFormat(format, tmpl string) (DataFormatter, error) {
 			return nil, fmt.Errorf("json format does not support template option.")
 		}
 		return &JSONFormat{}, nil
+	case "yaml":
+		if len(tmpl) > 0 {
+			return nil, fmt.Errorf("yaml format does not support template option.")
+		}
+		return &YAMLFormat{}, nil
 	case "template":
 		return &TemplateFormat{tmpl}, nil
 	}
@@ -51,6 +61,58 @@ func (p *JSONFormat) TransformData(data interface{}) (string, error) {
 	return buf.String(), nil
 }
 
+type YAMLFormat struct {
+}
+
+// TransformData returns YAML format string data. The data is converted through
+// JSON so that the keys match the JSON output.
+func (p *YAMLFormat) TransformData(data interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, jsonHandlePretty)
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+
+	var generic interface{}
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return "", err
+	}
+
+	out, err := yaml.Marshal(yamlNumbers(generic))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// yamlNumbers replaces the json.Numbers in the decoded JSON value v with
+// integers or floats so they are not emitted as strings. Integers are kept
+// exact, since indexes may not fit in a float.
+func yamlNumbers(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = yamlNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = yamlNumbers(e)
+		}
+	}
+	return v
+}
+
 type TemplateFormat struct {
 	tmpl string
 }
@@ -98,3 +160,87 @@ func Format(json bool, template string, data interface{}) (string, error) {
 
 	return out, nil
 }
+
+// outputFormat holds the flags of the commands that can display their data in
+// a machine readable format instead of the human readable output.
+type outputFormat struct {
+	json   bool
+	tmpl   string
+	output string
+}
+
+// setFlags registers the -output flag along with the -json and -t flags it
+// supersedes.
+func (o *outputFormat) setFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&o.json, "json", false, "")
+	flags.StringVar(&o.tmpl, "t", "", "")
+	flags.StringVar(&o.output, "output", "", "")
+}
+
+// enabled returns whether a machine readable format was requested.
+func (o *outputFormat) enabled() bool {
+	return o.json || len(o.tmpl) > 0 || o.output != ""
+}
+
+// format returns the name of the requested format and validates that the
+// flags do not conflict.
+func (o *outputFormat) format() (string, error) {
+	format := o.output
+	switch format {
+	case "", "json", "yaml", "template":
+	default:
+		return "", fmt.Errorf("Unsupported output format %q: must be one of json, yaml or template", format)
+	}
+
+	if o.json && len(o.tmpl) > 0 {
+		return "", fmt.Errorf("Both json and template formatting are not allowed")
+	}
+
+	if o.json {
+		if format != "" && format != "json" {
+			return "", fmt.Errorf("-json can not be combined with -output=%s", format)
+		}
+		format = "json"
+	}
+
+	if len(o.tmpl) > 0 {
+		if format != "" && format != "template" {
+			return "", fmt.Errorf("-t can only be used with -output=template")
+		}
+		format = "template"
+	} else if format == "template" {
+		return "", fmt.Errorf("-output=template requires a template to be given with -t")
+	}
+
+	return format, nil
+}
+
+// validate returns an error if the output flags are invalid. Commands call it
+// after parsing their flags so that misuse is reported before any request is
+// made.
+func (o *outputFormat) validate() error {
+	_, err := o.format()
+	return err
+}
+
+// Format returns the data in the requested format.
+func (o *outputFormat) Format(data interface{}) (string, error) {
+	format, err := o.format()
+	if err != nil {
+		return "", err
+	}
+	if format == "" {
+		return "", fmt.Errorf("no formatting option given")
+	}
+
+	f, err := DataFormat(format, o.tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := f.TransformData(data)
+	if err != nil {
+		return "", fmt.Errorf("Error formatting the data: %s", err)
+	}
+	return out, nil
+}
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"flag"
 	"strings"
 	"testing"
 )
@@ -17,10 +18,14 @@ const expectJSON = `{
     "Region": "global"
 }`
 
+const expectYAML = `ID: "1"
+Name: example
+Region: global`
+
 var (
 	tData        = testData{"global", "1", "example"}
-	testFormat   = map[string]string{"json": "", "template": "{{.Region}}"}
-	expectOutput = map[string]string{"json": expectJSON, "template": "global"}
+	testFormat   = map[string]string{"json": "", "yaml": "", "template": "{{.Region}}"}
+	expectOutput = map[string]string{"json": expectJSON, "yaml": expectYAML, "template": "global"}
 )
 
 func TestDataFormat(t *testing.T) {
@@ -64,3 +69,72 @@ func TestInvalidJSONTemplate(t *testing.T) {
 		t.Fatalf("expected not specified template error, got: %s", err.Error())
 	}
 }
+
+func TestYAMLFormat_Numbers(t *testing.T) {
+	t.Parallel()
+	data := struct {
+		Index uint64
+		Ratio float64
+	}{Index: 1<<63 + 1, Ratio: 0.5}
+
+	fm, err := DataFormat("yaml", "")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	result, err := fm.TransformData(data)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected := "Index: 9223372036854775809\nRatio: 0.5"
+	if result != expected {
+		t.Fatalf("expected output:\n%s\nactual:\n%s", expected, result)
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		args   []string
+		format string
+		err    string
+	}{
+		{args: nil, format: ""},
+		{args: []string{"-json"}, format: "json"},
+		{args: []string{"-t", "{{.ID}}"}, format: "template"},
+		{args: []string{"-output", "yaml"}, format: "yaml"},
+		{args: []string{"-output", "json", "-json"}, format: "json"},
+		{args: []string{"-output", "template", "-t", "{{.ID}}"}, format: "template"},
+		{args: []string{"-output", "xml"}, err: "Unsupported output format"},
+		{args: []string{"-output", "yaml", "-json"}, err: "-json can not be combined"},
+		{args: []string{"-output", "yaml", "-t", "{{.ID}}"}, err: "-t can only be used"},
+		{args: []string{"-json", "-t", "{{.ID}}"}, err: "Both json and template"},
+		{args: []string{"-output", "template"}, err: "requires a template"},
+	}
+
+	for _, tc := range cases {
+		var o outputFormat
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		o.setFlags(flags)
+		if err := flags.Parse(tc.args); err != nil {
+			t.Fatalf("%v: err: %v", tc.args, err)
+		}
+
+		format, err := o.format()
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("%v: expected error %q, got: %v", tc.args, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: err: %v", tc.args, err)
+		}
+		if format != tc.format {
+			t.Fatalf("%v: expected format %q, got %q", tc.args, tc.format, format)
+		}
+		if o.enabled() != (tc.format != "") {
+			t.Fatalf("%v: unexpected enabled %v", tc.args, o.enabled())
+		}
+	}
+}
@@ -34,6 +34,11 @@ Usage: nomad deployment <subcommand> [options] [args]
 
       $ nomad deployment fail <deployment-id>
 
+  Roll the job back to the stable version that preceded a deployment, even if
+  the job does not auto revert:
+
+      $ nomad deployment rollback <deployment-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
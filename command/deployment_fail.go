@@ -51,18 +51,7 @@ func (c *DeploymentFailCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *DeploymentFailCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Deployments, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Deployments]
-	})
+	return c.Meta.PredictSearch(contexts.Deployments)
 }
 
 func (c *DeploymentFailCommand) Name() string { return "deployment fail" }
@@ -27,6 +27,10 @@ List Options:
   -json
     Output the deployments in a JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display the deployments using a Go template.
 
@@ -40,6 +44,7 @@ func (c *DeploymentListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-json":    complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
 			"-t":       complete.PredictAnything,
 			"-verbose": complete.PredictNothing,
 		})
@@ -56,19 +61,23 @@ func (c *DeploymentListCommand) Synopsis() string {
 func (c *DeploymentListCommand) Name() string { return "deployment list" }
 
 func (c *DeploymentListCommand) Run(args []string) int {
-	var json, verbose bool
-	var tmpl string
+	var verbose bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got no arguments
 	args = flags.Args()
 	if l := len(args); l != 0 {
@@ -96,8 +105,8 @@ func (c *DeploymentListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if format.enabled() {
+		out, err := format.Format(deploys)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -0,0 +1,130 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+)
+
+// deploymentMonitor follows a deployment and writes the progress of its task
+// groups as allocations are placed and have their health set.
+type deploymentMonitor struct {
+	ui     cli.Ui
+	client *api.Client
+
+	// length determines the number of characters for identifiers in the ui.
+	length int
+
+	// status is the last seen status of the deployment
+	status string
+
+	// index is the Raft index of the last progress written
+	index uint64
+}
+
+// newDeploymentMonitor returns a new deployment monitor. The returned monitor
+// will write output information to the provided ui.
+func newDeploymentMonitor(ui cli.Ui, client *api.Client, length int) *deploymentMonitor {
+	if colorUi, ok := ui.(*cli.ColoredUi); ok {
+		// Disable Info color for monitored output
+		ui = &cli.ColoredUi{
+			ErrorColor: colorUi.ErrorColor,
+			WarnColor:  colorUi.WarnColor,
+			InfoColor:  cli.UiColorNone,
+			Ui:         colorUi.Ui,
+		}
+	}
+	return &deploymentMonitor{
+		ui: &cli.PrefixedUi{
+			InfoPrefix:   "==> ",
+			OutputPrefix: "    ",
+			ErrorPrefix:  "==> ",
+			Ui:           ui,
+		},
+		client: client,
+		length: length,
+	}
+}
+
+// update writes the progress recorded since the last update and the change
+// of status of the deployment, if any.
+func (m *deploymentMonitor) update(d *api.Deployment) {
+	type groupProgress struct {
+		group    string
+		progress *api.DeploymentProgress
+		desired  int
+	}
+
+	var updates []*groupProgress
+	for group, state := range d.TaskGroups {
+		for _, p := range state.Progress {
+			if p.Index > m.index {
+				updates = append(updates, &groupProgress{group, p, state.DesiredTotal})
+			}
+		}
+	}
+
+	// Write the progress in the order it was recorded
+	sort.Slice(updates, func(i, j int) bool {
+		if updates[i].progress.Index != updates[j].progress.Index {
+			return updates[i].progress.Index < updates[j].progress.Index
+		}
+		return updates[i].group < updates[j].group
+	})
+
+	for _, u := range updates {
+		p := u.progress
+		line := fmt.Sprintf("%s: Task group %q: %d/%d placed, %d healthy, %d unhealthy",
+			formatTime(p.Time), u.group, p.PlacedAllocs, u.desired, p.HealthyAllocs, p.UnhealthyAllocs)
+		if !p.RequireProgressBy.IsZero() {
+			line += fmt.Sprintf(", progress deadline in %s",
+				formatTimeDifference(p.Time, p.RequireProgressBy, time.Second))
+		}
+		m.ui.Output(line)
+		m.index = p.Index
+	}
+
+	if m.status != "" && m.status != d.Status {
+		m.ui.Output(fmt.Sprintf("Deployment status changed: %q -> %q (%s)",
+			m.status, d.Status, d.StatusDescription))
+	}
+	m.status = d.Status
+}
+
+// monitor follows the given deployment until it is no longer running or
+// paused, and returns the exit code for the command. The return code will be
+// 0 if the deployment is successful and 2 if it failed or was cancelled. For
+// any other failures (API connectivity, internal errors, etc), the return code
+// will be 1.
+func (m *deploymentMonitor) monitor(deployID string) int {
+	m.ui.Info(fmt.Sprintf("Monitoring deployment %q", limit(deployID, m.length)))
+
+	q := &api.QueryOptions{}
+	for {
+		d, meta, err := m.client.Deployments().Info(deployID, q)
+		if err != nil {
+			m.ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
+			return 1
+		}
+
+		m.update(d)
+
+		switch d.Status {
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+			// Wait for the next change of the deployment
+			q.WaitIndex = meta.LastIndex
+			continue
+		}
+
+		m.ui.Info(fmt.Sprintf("Deployment %q finished with status %q",
+			limit(d.ID, m.length), d.Status))
+		if d.Status != structs.DeploymentStatusSuccessful {
+			return 2
+		}
+		return 0
+	}
+}
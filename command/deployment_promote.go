@@ -61,18 +61,7 @@ func (c *DeploymentPromoteCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *DeploymentPromoteCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Deployments, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Deployments]
-	})
+	return c.Meta.PredictSearch(contexts.Deployments)
 }
 
 func (c *DeploymentPromoteCommand) Name() string { return "deployment promote" }
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type DeploymentRollbackCommand struct {
+	Meta
+}
+
+func (c *DeploymentRollbackCommand) Help() string {
+	helpText := `
+Usage: nomad deployment rollback [options] <deployment id>
+
+  Rollback is used to revert the job of a deployment to the latest stable job
+  version that preceded the deployment. If the deployment is still active it is
+  marked as failed, regardless of whether the job is configured to auto revert.
+  The rollback is itself rolled out as a new job version and deployment.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Rollback Options:
+
+  -detach
+    Return immediately instead of entering monitor mode. After deployment
+    rollback, the evaluation ID will be printed to the screen, which can be
+    used to examine the evaluation using the eval-status command.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *DeploymentRollbackCommand) Synopsis() string {
+	return "Roll back a deployment to the prior stable job version"
+}
+
+func (c *DeploymentRollbackCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-detach":  complete.PredictNothing,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *DeploymentRollbackCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Deployments)
+}
+
+func (c *DeploymentRollbackCommand) Name() string { return "deployment rollback" }
+
+func (c *DeploymentRollbackCommand) Run(args []string) int {
+	var detach, verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&detach, "detach", false, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <deployment id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	dID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Do a prefix lookup
+	deploy, possible, err := getDeployment(client.Deployments(), dID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
+		return 1
+	}
+
+	if len(possible) != 0 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple deployments\n\n%s", formatDeployments(possible, length)))
+		return 1
+	}
+
+	u, _, err := client.Deployments().Rollback(deploy.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rolling back deployment: %s", err))
+		return 1
+	}
+
+	if u.RevertedJobVersion == nil {
+		c.Ui.Output(fmt.Sprintf("Deployment %q rolled back", deploy.ID))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Deployment %q rolled back to job version %d", deploy.ID, *u.RevertedJobVersion))
+	}
+
+	evalCreated := u.EvalID != ""
+
+	// Nothing to do
+	if detach || !evalCreated {
+		return 0
+	}
+
+	c.Ui.Output("")
+	mon := newMonitor(c.Ui, client, length)
+	return mon.monitor(u.EvalID, false)
+}
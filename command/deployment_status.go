@@ -30,9 +30,18 @@ Status Options:
   -verbose
     Display full information.
 
+  -monitor
+    Monitor the deployment until it completes, printing the progress of its
+    task groups as allocations are placed and become healthy. The exit code is
+    0 if the deployment is successful and 2 if it fails or is cancelled.
+
   -json
     Output the deployment in its JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display deployment using a Go template.
 `
@@ -47,42 +56,43 @@ func (c *DeploymentStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-verbose": complete.PredictNothing,
+			"-monitor": complete.PredictNothing,
 			"-json":    complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
 			"-t":       complete.PredictAnything,
 		})
 }
 
 func (c *DeploymentStatusCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Deployments, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Deployments]
-	})
+	return c.Meta.PredictSearch(contexts.Deployments)
 }
 
 func (c *DeploymentStatusCommand) Name() string { return "deployment status" }
 
 func (c *DeploymentStatusCommand) Run(args []string) int {
-	var json, verbose bool
-	var tmpl string
+	var verbose, monitor bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&monitor, "monitor", false, "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if monitor && format.enabled() {
+		c.Ui.Error("The -monitor flag can't be used with an output format")
+		return 1
+	}
+
 	// Check that we got exactly one argument
 	args = flags.Args()
 	if l := len(args); l != 1 {
@@ -118,8 +128,8 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploy)
+	if format.enabled() {
+		out, err := format.Format(deploy)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -129,6 +139,24 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 0
 	}
 
+	if monitor {
+		mon := newDeploymentMonitor(c.Ui, client, length)
+		code := mon.monitor(deploy.ID)
+		if code == 1 {
+			return code
+		}
+
+		// Display the final state of the deployment
+		deploy, _, err = client.Deployments().Info(deploy.ID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error retrieving deployment: %s", err))
+			return 1
+		}
+		c.Ui.Output("")
+		c.Ui.Output(c.Colorize().Color(formatDeployment(deploy, length)))
+		return code
+	}
+
 	c.Ui.Output(c.Colorize().Color(formatDeployment(deploy, length)))
 	return 0
 }
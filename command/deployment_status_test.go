@@ -3,11 +3,14 @@ package command
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDeploymentStatusCommand_Implements(t *testing.T) {
@@ -61,3 +64,43 @@ func TestDeploymentStatusCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(d.ID, res[0])
 }
+
+func TestDeploymentStatusCommand_Monitor(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Create a completed deployment with some progress
+	state := srv.Agent.Server().State()
+	d := mock.Deployment()
+	d.Status = structs.DeploymentStatusSuccessful
+	d.StatusDescription = structs.DeploymentStatusDescriptionSuccessful
+	now := time.Now().UTC()
+	tg := d.TaskGroups["web"]
+	tg.ProgressDeadline = 10 * time.Minute
+	tg.PlacedAllocs = 1
+	tg.RequireProgressBy = now.Add(10 * time.Minute)
+	tg.AddProgress(now, 900)
+	tg.HealthyAllocs = 1
+	tg.AddProgress(now.Add(time.Minute), 950)
+	require.Nil(state.UpsertDeployment(1000, d))
+
+	ui := new(cli.MockUi)
+	cmd := &DeploymentStatusCommand{Meta: Meta{Ui: ui}}
+
+	// Fails with an output format
+	code := cmd.Run([]string{"-address=" + url, "-monitor", "-json", d.ID})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "can't be used with an output format")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-monitor", d.ID})
+	require.Equal(0, code)
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, `Task group "web": 1/10 placed, 0 healthy, 0 unhealthy, progress deadline in 10m0s`)
+	require.Contains(out, `Task group "web": 1/10 placed, 1 healthy, 0 unhealthy, progress deadline in 9m0s`)
+	require.Contains(out, `finished with status "successful"`)
+}
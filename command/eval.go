@@ -19,10 +19,18 @@ Usage: nomad eval <subcommand> [options] [args]
   detail but can be useful for debugging placement failures when the cluster
   does not have the resources to run a given job.
 
+  List evaluations:
+
+      $ nomad eval list
+
   Examine an evaluations status:
 
       $ nomad eval status <eval-id>
 
+  Requeue a blocked evaluation:
+
+      $ nomad eval requeue <eval-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,177 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalListCommand struct {
+	Meta
+}
+
+func (c *EvalListCommand) Help() string {
+	helpText := `
+Usage: nomad eval list [options]
+
+  List is used to list the set of evaluations tracked by Nomad, most recent
+  first.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -status <status>
+    Only list evaluations with the given status, such as "blocked" or
+    "pending".
+
+  -job <job>
+    Only list evaluations of the given job ID.
+
+  -scheduler <scheduler>
+    Only list evaluations handled by the given scheduler, such as "service",
+    "batch" or "system".
+
+  -json
+    Output the evaluations in a JSON format.
+
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
+  -t
+    Format and display the evaluations using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-status":    complete.PredictSet("blocked", "pending", "complete", "failed", "canceled"),
+			"-job":       c.Meta.PredictSearch(contexts.Jobs),
+			"-scheduler": complete.PredictSet("service", "batch", "system"),
+			"-json":      complete.PredictNothing,
+			"-output":    complete.PredictSet("json", "yaml", "template"),
+			"-t":         complete.PredictAnything,
+			"-verbose":   complete.PredictNothing,
+		})
+}
+
+func (c *EvalListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *EvalListCommand) Synopsis() string {
+	return "List evaluations"
+}
+
+func (c *EvalListCommand) Name() string { return "eval list" }
+
+func (c *EvalListCommand) Run(args []string) int {
+	var verbose bool
+	var status, jobID, scheduler string
+	var format outputFormat
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.StringVar(&status, "status", "", "")
+	flags.StringVar(&jobID, "job", "", "")
+	flags.StringVar(&scheduler, "scheduler", "", "")
+	format.setFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	evals, _, err := client.Evaluations().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving evaluations: %s", err))
+		return 1
+	}
+
+	// Filter the evaluations
+	filtered := evals[:0]
+	for _, eval := range evals {
+		if status != "" && eval.Status != status {
+			continue
+		}
+		if jobID != "" && eval.JobID != jobID {
+			continue
+		}
+		if scheduler != "" && eval.Type != scheduler {
+			continue
+		}
+		filtered = append(filtered, eval)
+	}
+
+	if format.enabled() {
+		out, err := format.Format(filtered)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatEvalList(filtered, length))
+	return 0
+}
+
+func formatEvalList(evals []*api.Evaluation, uuidLength int) string {
+	if len(evals) == 0 {
+		return "No evaluations found"
+	}
+
+	rows := make([]string, len(evals)+1)
+	rows[0] = "ID|Priority|Triggered By|Job ID|Scheduler|Status|Placement Failures"
+	for i, eval := range evals {
+		failures, _ := evalFailureStatus(eval)
+		rows[i+1] = fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s",
+			limit(eval.ID, uuidLength),
+			eval.Priority,
+			eval.TriggeredBy,
+			eval.JobID,
+			eval.Type,
+			eval.Status,
+			failures)
+	}
+	return formatList(rows)
+}
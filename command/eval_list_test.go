@@ -0,0 +1,84 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &EvalListCommand{}
+}
+
+func TestEvalListCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &EvalListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving evaluations") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestEvalListCommand_Filters(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Create evals with different statuses, jobs and schedulers
+	state := srv.Agent.Server().State()
+	e1 := mock.Eval()
+	e1.Status = structs.EvalStatusBlocked
+	e2 := mock.Eval()
+	e2.Type = structs.JobTypeBatch
+	e3 := mock.Eval()
+	e3.JobID = "other"
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{e1, e2, e3}))
+
+	for _, c := range []struct {
+		args     []string
+		expected []*structs.Evaluation
+	}{
+		{[]string{"-status=blocked"}, []*structs.Evaluation{e1}},
+		{[]string{"-scheduler=batch"}, []*structs.Evaluation{e2}},
+		{[]string{"-job=other"}, []*structs.Evaluation{e3}},
+		{[]string{"-status=pending", "-scheduler=service"}, []*structs.Evaluation{e3}},
+		{[]string{"-status=failed"}, nil},
+	} {
+		ui := new(cli.MockUi)
+		cmd := &EvalListCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run(append([]string{"-address=" + url, "-verbose"}, c.args...))
+		require.Equal(0, code, ui.ErrorWriter.String())
+
+		out := ui.OutputWriter.String()
+		if len(c.expected) == 0 {
+			require.Contains(out, "No evaluations found")
+		}
+		for _, e := range []*structs.Evaluation{e1, e2, e3} {
+			listed := false
+			for _, expected := range c.expected {
+				listed = listed || expected == e
+			}
+			require.Equal(listed, strings.Contains(out, e.ID), "%v: %s", c.args, out)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalRequeueCommand struct {
+	Meta
+}
+
+func (c *EvalRequeueCommand) Help() string {
+	helpText := `
+Usage: nomad eval requeue [options] <evaluation>
+
+  Requeue is used to run a blocked evaluation through the scheduler again
+  without waiting for node capacity to change. It can be used to retry an
+  evaluation that is stuck in the blocked state without updating its job.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Requeue Options:
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalRequeueCommand) Synopsis() string {
+	return "Requeue a blocked evaluation"
+}
+
+func (c *EvalRequeueCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *EvalRequeueCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Evals)
+}
+
+func (c *EvalRequeueCommand) Name() string { return "eval requeue" }
+
+func (c *EvalRequeueCommand) Run(args []string) int {
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one evaluation ID
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <evaluation>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	evalID := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	if len(evalID) == 1 {
+		c.Ui.Error(fmt.Sprintf("Identifier must contain at least two characters."))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	evalID = sanitizeUUIDPrefix(evalID)
+	evals, _, err := client.Evaluations().PrefixList(evalID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation: %v", err))
+		return 1
+	}
+	if len(evals) == 0 {
+		c.Ui.Error(fmt.Sprintf("No evaluation(s) with prefix or id %q found", evalID))
+		return 1
+	}
+	if len(evals) > 1 {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple evaluations\n\n%s", formatEvalList(evals, length)))
+		return 1
+	}
+
+	eval := evals[0]
+	if eval.Status != "blocked" {
+		c.Ui.Error(fmt.Sprintf("Evaluation %q is not blocked, its status is %q", limit(eval.ID, length), eval.Status))
+		return 1
+	}
+
+	if _, err := client.Evaluations().Requeue(eval.ID, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error requeuing evaluation: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Evaluation %q requeued", limit(eval.ID, length)))
+	return 0
+}
@@ -20,7 +20,8 @@ Usage: nomad eval status [options] <evaluation>
 
   Display information about evaluations. This command can be used to inspect the
   current status of an evaluation as well as determine the reason an evaluation
-  did not place all allocations.
+  did not place all allocations. For blocked evaluations, the node classes whose
+  capacity changes unblock the evaluation are displayed.
 
 General Options:
 
@@ -32,11 +33,16 @@ Eval Status Options:
     Monitor an outstanding evaluation
 
   -verbose
-    Show full information.
+    Show full information, including the scores of the nodes that were
+    considered for placement.
 
   -json
     Output the evaluation in its JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display evaluation using a Go template.
 `
@@ -53,47 +59,37 @@ func (c *EvalStatusCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-json":    complete.PredictNothing,
 			"-monitor": complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
 			"-t":       complete.PredictAnything,
 			"-verbose": complete.PredictNothing,
 		})
 }
 
 func (c *EvalStatusCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Evals]
-	})
+	return c.Meta.PredictSearch(contexts.Evals)
 }
 
 func (c *EvalStatusCommand) Name() string { return "eval status" }
 
 func (c *EvalStatusCommand) Run(args []string) int {
-	var monitor, verbose, json bool
-	var tmpl string
+	var monitor, verbose bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&monitor, "monitor", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got exactly one evaluation ID
 	args = flags.Args()
 
@@ -105,14 +101,14 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the evaluations data list
-	if len(args) == 0 && json || len(tmpl) > 0 {
+	if len(args) == 0 && format.enabled() {
 		evals, _, err := client.Evaluations().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying evaluations: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, evals)
+		out, err := format.Format(evals)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -185,8 +181,8 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, eval)
+	if format.enabled() {
+		out, err := format.Format(eval)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -220,6 +216,11 @@ func (c *EvalStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Priority|%d", eval.Priority),
 		fmt.Sprintf("Placement Failures|%s", failureString))
 
+	if eval.QuotaLimitReached != "" {
+		basic = append(basic,
+			fmt.Sprintf("Quota Limit Reached|%s", eval.QuotaLimitReached))
+	}
+
 	if !eval.WaitUntil.IsZero() {
 		basic = append(basic,
 			fmt.Sprintf("Wait Until|%s", formatTime(eval.WaitUntil)))
@@ -245,7 +246,9 @@ func (c *EvalStatusCommand) Run(args []string) int {
 				noun += "s"
 			}
 			c.Ui.Output(fmt.Sprintf("Task Group %q (failed to place %d %s):", tg, metrics.CoalescedFailures+1, noun))
-			c.Ui.Output(formatAllocMetrics(metrics, false, "  "))
+			c.Ui.Output(fmt.Sprintf("  * Evaluated %d nodes, %d filtered, %d exhausted",
+				metrics.NodesEvaluated, metrics.NodesFiltered, metrics.NodesExhausted))
+			c.Ui.Output(formatAllocMetrics(metrics, verbose, "  "))
 			c.Ui.Output("")
 		}
 
@@ -255,9 +258,47 @@ func (c *EvalStatusCommand) Run(args []string) int {
 		}
 	}
 
+	// Display which node classes can unblock the blocked evaluation
+	blocked := eval
+	if eval.Status != "blocked" && eval.BlockedEval != "" {
+		blocked, _, err = client.Evaluations().Info(eval.BlockedEval, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying blocked evaluation: %s", err))
+			return 1
+		}
+	}
+	if blocked.Status == "blocked" {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Class Eligibility[reset]"))
+		c.Ui.Output(formatClassEligibility(blocked))
+	}
+
 	return 0
 }
 
+// formatClassEligibility returns the node classes a blocked evaluation is
+// eligible for, which are the classes whose capacity changes unblock it.
+func formatClassEligibility(eval *api.Evaluation) string {
+	if eval.EscapedComputedClass {
+		return "Constraints escaped computed node classes, capacity changes of any node unblock the evaluation"
+	}
+	if len(eval.ClassEligibility) == 0 {
+		return "No node classes evaluated, capacity changes of any node unblock the evaluation"
+	}
+
+	classes := make([]string, 0, len(eval.ClassEligibility))
+	for class := range eval.ClassEligibility {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	rows := make([]string, len(classes)+1)
+	rows[0] = "Class|Eligible"
+	for i, class := range classes {
+		rows[i+1] = fmt.Sprintf("%s|%t", class, eval.ClassEligibility[class])
+	}
+	return formatList(rows)
+}
+
 func sortedTaskGroupFromMetrics(groups map[string]*api.AllocationMetric) []string {
 	tgs := make([]string, 0, len(groups))
 	for tg := range groups {
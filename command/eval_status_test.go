@@ -9,6 +9,7 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEvalStatusCommand_Implements(t *testing.T) {
@@ -84,3 +85,39 @@ func TestEvalStatusCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(e.ID, res[0])
 }
+
+func TestEvalStatusCommand_ClassEligibility(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Create a failed eval and the blocked eval it created
+	state := srv.Agent.Server().State()
+	blocked := mock.Eval()
+	blocked.Status = structs.EvalStatusBlocked
+	blocked.ClassEligibility = map[string]bool{"v1:123": true, "v1:456": false}
+	e := mock.Eval()
+	e.Status = structs.EvalStatusComplete
+	e.BlockedEval = blocked.ID
+	e.FailedTGAllocs = map[string]*structs.AllocMetric{
+		"web": {
+			NodesEvaluated:     3,
+			NodesFiltered:      2,
+			NodesExhausted:     1,
+			ConstraintFiltered: map[string]int{"${attr.kernel.name} = windows": 2},
+		},
+	}
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{blocked, e}))
+
+	ui := new(cli.MockUi)
+	cmd := &EvalStatusCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-address=" + url, e.ID}), ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Evaluated 3 nodes, 2 filtered, 1 exhausted")
+	require.Contains(out, `Constraint "${attr.kernel.name} = windows" filtered 2 nodes`)
+	require.Contains(out, "Class Eligibility")
+	require.Regexp(`v1:123\s+true`, out)
+	require.Regexp(`v1:456\s+false`, out)
+}
@@ -0,0 +1,256 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclwrite"
+	"github.com/posener/complete"
+)
+
+// fmtExtensions are the extensions of the files formatted when a directory is
+// given to the fmt command.
+var fmtExtensions = []string{".nomad", ".hcl"}
+
+// FmtCommand rewrites job files in the canonical HCL style.
+type FmtCommand struct {
+	Meta
+
+	// stdin is read when the path "-" is given. It defaults to os.Stdin and
+	// is set by tests.
+	stdin io.Reader
+
+	check     bool
+	list      bool
+	write     bool
+	recursive bool
+
+	// unformatted is set if any file's formatting differs from the
+	// canonical style.
+	unformatted bool
+}
+
+func (c *FmtCommand) Help() string {
+	helpText := `
+Usage: nomad fmt [options] [path ...]
+
+  Rewrites Nomad job files to the canonical format, aligning and indenting
+  them consistently. Files are checked for syntax errors before they are
+  formatted. Files must use HCL2 compatible syntax; constructs only accepted by
+  HCL1, such as separating arguments with commas, are reported as errors.
+
+  Each path may be a job file or a directory, in which case the files in it
+  ending in .nomad or .hcl are formatted. If no path is given the current
+  directory is used. If the path is "-", the job file is read from stdin and the
+  formatted result is written to stdout.
+
+  Fmt will return one of the following exit codes:
+    * 0: All files are formatted, or were formatted successfully.
+    * 1: An error occurred, or -check was set and a file is not formatted.
+
+Fmt Options:
+
+  -check
+    Check whether the files are formatted without modifying them. The exit
+    code is 1 if any file is not formatted. Implies -write=false.
+
+  -list
+    List the files whose formatting differs from the canonical format.
+    Defaults to true.
+
+  -recursive
+    Also format the files in subdirectories of the given directories.
+
+  -write
+    Overwrite the files with the formatted result. Defaults to true.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *FmtCommand) Synopsis() string {
+	return "Rewrite job files to the canonical format"
+}
+
+func (c *FmtCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-check":     complete.PredictNothing,
+		"-list":      complete.PredictNothing,
+		"-recursive": complete.PredictNothing,
+		"-write":     complete.PredictNothing,
+	}
+}
+
+func (c *FmtCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictDirs("*"),
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"))
+}
+
+func (c *FmtCommand) Name() string { return "fmt" }
+
+func (c *FmtCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&c.check, "check", false, "")
+	flags.BoolVar(&c.list, "list", true, "")
+	flags.BoolVar(&c.recursive, "recursive", false, "")
+	flags.BoolVar(&c.write, "write", true, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if c.check {
+		c.write = false
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	failed := false
+	for _, path := range paths {
+		if path == "-" {
+			if err := c.formatStdin(); err != nil {
+				c.Ui.Error(err.Error())
+				failed = true
+			}
+			continue
+		}
+
+		if err := c.formatPath(path, true); err != nil {
+			c.Ui.Error(err.Error())
+			failed = true
+		}
+	}
+
+	if failed || (c.check && c.unformatted) {
+		return 1
+	}
+	return 0
+}
+
+// formatStdin formats the job file read from stdin and writes the result to
+// stdout.
+func (c *FmtCommand) formatStdin() error {
+	r := c.stdin
+	if r == nil {
+		r = os.Stdin
+	}
+
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("Error reading stdin: %v", err)
+	}
+
+	out, err := fmtSource(src, "<stdin>")
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(src, out) {
+		c.unformatted = true
+	}
+	if !c.check {
+		c.Ui.Output(strings.TrimSuffix(string(out), "\n"))
+	}
+	return nil
+}
+
+// formatPath formats the file at path, or the job files in it if it is a
+// directory. Subdirectories are only walked if recursive is set. top is true
+// for paths given on the command line, which are formatted regardless of
+// their extension.
+func (c *FmtCommand) formatPath(path string, top bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", path, err)
+	}
+
+	if !info.IsDir() {
+		return c.formatFile(path, info.Mode())
+	}
+
+	if !top && !c.recursive {
+		return nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("Error reading directory %q: %v", path, err)
+	}
+
+	var mErr []string
+	for _, entry := range entries {
+		name := filepath.Join(path, entry.Name())
+		if !entry.IsDir() && !hasFmtExtension(entry.Name()) {
+			continue
+		}
+		if err := c.formatPath(name, false); err != nil {
+			mErr = append(mErr, err.Error())
+		}
+	}
+
+	if len(mErr) != 0 {
+		return fmt.Errorf("%s", strings.Join(mErr, "\n"))
+	}
+	return nil
+}
+
+// formatFile formats a single job file, listing and rewriting it as
+// configured.
+func (c *FmtCommand) formatFile(path string, mode os.FileMode) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error reading %q: %v", path, err)
+	}
+
+	out, err := fmtSource(src, path)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(src, out) {
+		return nil
+	}
+	c.unformatted = true
+
+	if c.list {
+		c.Ui.Output(path)
+	}
+	if c.write {
+		if err := ioutil.WriteFile(path, out, mode.Perm()); err != nil {
+			return fmt.Errorf("Error writing %q: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// fmtSource returns src in the canonical format. An error is returned if src
+// has syntax errors, since formatting it could produce unexpected results.
+func fmtSource(src []byte, filename string) ([]byte, error) {
+	_, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("Error parsing %q: %v", filename, diags)
+	}
+
+	return hclwrite.Format(src), nil
+}
+
+func hasFmtExtension(name string) bool {
+	for _, ext := range fmtExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
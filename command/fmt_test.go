@@ -0,0 +1,139 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fmtTestUnformatted = `job "example" {
+  datacenters = ["dc1"]
+  group "cache" {
+      count = 1
+      task "redis" {
+        driver = "docker"
+      }
+  }
+}
+`
+
+	fmtTestFormatted = `job "example" {
+  datacenters = ["dc1"]
+  group "cache" {
+    count = 1
+    task "redis" {
+      driver = "docker"
+    }
+  }
+}
+`
+)
+
+func TestFmtCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &FmtCommand{}
+}
+
+func TestFmtCommand_Dir(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-fmt")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	unformatted := filepath.Join(dir, "unformatted.nomad")
+	formatted := filepath.Join(dir, "formatted.hcl")
+	ignored := filepath.Join(dir, "ignored.txt")
+	nested := filepath.Join(dir, "sub", "nested.nomad")
+	require.NoError(os.Mkdir(filepath.Join(dir, "sub"), 0755))
+	require.NoError(ioutil.WriteFile(unformatted, []byte(fmtTestUnformatted), 0644))
+	require.NoError(ioutil.WriteFile(formatted, []byte(fmtTestFormatted), 0644))
+	require.NoError(ioutil.WriteFile(ignored, []byte(fmtTestUnformatted), 0644))
+	require.NoError(ioutil.WriteFile(nested, []byte(fmtTestUnformatted), 0644))
+
+	// Check mode lists the unformatted file and fails without modifying it
+	ui := new(cli.MockUi)
+	cmd := &FmtCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, cmd.Run([]string{"-check", dir}))
+	require.Equal(unformatted, strings.TrimSpace(ui.OutputWriter.String()))
+	contents, err := ioutil.ReadFile(unformatted)
+	require.NoError(err)
+	require.Equal(fmtTestUnformatted, string(contents))
+
+	// Formatting rewrites only the unformatted job file in the directory
+	ui = new(cli.MockUi)
+	cmd = &FmtCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{dir}))
+	require.Equal(unformatted, strings.TrimSpace(ui.OutputWriter.String()))
+	for path, expected := range map[string]string{
+		unformatted: fmtTestFormatted,
+		formatted:   fmtTestFormatted,
+		ignored:     fmtTestUnformatted,
+		nested:      fmtTestUnformatted,
+	} {
+		contents, err := ioutil.ReadFile(path)
+		require.NoError(err)
+		require.Equal(expected, string(contents), path)
+	}
+
+	// Check mode now passes for the directory but not its subdirectories
+	ui = new(cli.MockUi)
+	cmd = &FmtCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-check", dir}))
+	require.Equal(1, cmd.Run([]string{"-check", "-recursive", dir}))
+	require.Equal(nested, strings.TrimSpace(ui.OutputWriter.String()))
+}
+
+func TestFmtCommand_Stdin(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &FmtCommand{
+		Meta:  Meta{Ui: ui},
+		stdin: strings.NewReader(fmtTestUnformatted),
+	}
+
+	if code := cmd.Run([]string{"-"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	if out := ui.OutputWriter.String(); out != fmtTestFormatted {
+		t.Fatalf("expected formatted output, got: %s", out)
+	}
+}
+
+func TestFmtCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &FmtCommand{Meta: Meta{Ui: ui}}
+
+	// Fails when the file does not exist
+	if code := cmd.Run([]string{"/unicorns/leprechauns.nomad"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error reading") {
+		t.Fatalf("expected reading error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on syntax errors without modifying the file
+	fh, err := ioutil.TempFile("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(fh.Name())
+	if _, err := fh.WriteString(`job "example" {`); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if code := cmd.Run([]string{fh.Name()}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error parsing") {
+		t.Fatalf("expected parsing error, got: %s", out)
+	}
+}
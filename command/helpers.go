@@ -2,6 +2,7 @@ package command
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,6 +13,7 @@ import (
 
 	gg "github.com/hashicorp/go-getter"
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/jobspec"
 	"github.com/kr/text"
 	"github.com/posener/complete"
@@ -96,6 +98,32 @@ func formatTimeDifference(first, second time.Time, d time.Duration) string {
 	return second.Truncate(d).Sub(first.Truncate(d)).String()
 }
 
+// periodicLaunchPreview is the number of upcoming launches shown when
+// validating or planning a periodic job.
+const periodicLaunchPreview = 5
+
+// periodicLaunches returns up to count launch times of the periodic config
+// after the passed time. Fewer times are returned if the spec stops matching.
+// The location of the returned times matches that of the passed time.
+func periodicLaunches(p *api.PeriodicConfig, from time.Time, count int) ([]time.Time, error) {
+	pc := *p
+	pc.Canonicalize()
+
+	var launches []time.Time
+	for len(launches) < count {
+		next, err := pc.Next(from)
+		if err != nil {
+			return nil, err
+		}
+		if next.IsZero() {
+			break
+		}
+		launches = append(launches, next)
+		from = next
+	}
+	return launches, nil
+}
+
 // fmtInt formats v into the tail of buf.
 // It returns the index where the output begins.
 func fmtInt(buf []byte, v uint64) int {
@@ -376,12 +404,30 @@ READ:
 }
 
 type JobGetter struct {
+	// vars and varFiles set the variables declared by the job file
+	vars     flaghelper.StringFlag
+	varFiles flaghelper.StringFlag
+
 	// The fields below can be overwritten for tests
 	testStdin io.Reader
 }
 
+// setFlags registers the -var and -var-file flags that set the variables of
+// the job file.
+func (j *JobGetter) setFlags(flags *flag.FlagSet) {
+	flags.Var(&j.vars, "var", "")
+	flags.Var(&j.varFiles, "var-file", "")
+}
+
 // StructJob returns the Job struct from jobfile.
 func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
+	job, _, err := j.ApiJobWithSubmission(jpath)
+	return job, err
+}
+
+// ApiJobWithSubmission returns the Job struct from jobfile, along with the
+// job file and variable values to store with the job version.
+func (j *JobGetter) ApiJobWithSubmission(jpath string) (*api.Job, *api.JobSubmission, error) {
 	var jobfile io.Reader
 	switch jpath {
 	case "-":
@@ -392,23 +438,23 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		job, err := ioutil.TempFile("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(job.Name())
 
 		if err := job.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -418,24 +464,39 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(job.Name())
 			defer file.Close()
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			jobfile = file
 		}
 	}
 
-	// Parse the JobFile
-	jobStruct, err := jobspec.Parse(jobfile)
+	// Keep the contents of the JobFile to store them with the job
+	source, err := ioutil.ReadAll(jobfile)
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
 	}
 
-	return jobStruct, nil
+	// Parse the JobFile, setting its variables from the environment and flags
+	vars := &jobspec.Variables{
+		Env:   os.Environ(),
+		Files: j.varFiles,
+		Vars:  j.vars,
+	}
+	jobStruct, values, err := jobspec.ParseWithValues(bytes.NewReader(source), vars)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
+	}
+
+	submission := &api.JobSubmission{
+		Source:    string(source),
+		Variables: values,
+	}
+	return jobStruct, submission, nil
 }
 
 // COMPAT: Remove in 0.7.0
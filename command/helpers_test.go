@@ -1,11 +1,13 @@
 package command
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -272,6 +274,75 @@ func TestJobGetter_LocalFile(t *testing.T) {
 }
 
 // Test StructJob with jobfile from HTTP Server
+func TestJobGetter_Variables(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	jobPath := filepath.Join(dir, "example.nomad")
+	jobFile := `
+variable "name" {}
+variable "datacenters" {
+  type = "list"
+}
+variable "count" {
+  default = 1
+}
+
+job "${var.name}" {
+  datacenters = "${var.datacenters}"
+  group "web" {
+    count = "${var.count}"
+    task "web" {
+      driver = "exec"
+    }
+  }
+}
+`
+	varPath := filepath.Join(dir, "prod.vars")
+	varFile := `
+name        = "web-prod"
+datacenters = ["dc1", "dc2"]
+count       = 5
+`
+	if err := ioutil.WriteFile(jobPath, []byte(jobFile), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(varPath, []byte(varFile), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	j := &JobGetter{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	j.setFlags(flags)
+	if err := flags.Parse([]string{"-var-file", varPath, "-var", "count=3"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	aj, err := j.ApiJob(jobPath)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if *aj.ID != "web-prod" {
+		t.Fatalf("bad ID: %q", *aj.ID)
+	}
+	if !reflect.DeepEqual(aj.Datacenters, []string{"dc1", "dc2"}) {
+		t.Fatalf("bad datacenters: %v", aj.Datacenters)
+	}
+	if *aj.TaskGroups[0].Count != 3 {
+		t.Fatalf("bad count: %d", *aj.TaskGroups[0].Count)
+	}
+
+	// Variables without a value are reported
+	_, err = (&JobGetter{}).ApiJob(jobPath)
+	if err == nil || !strings.Contains(err.Error(), `missing value for variable "datacenters"`) {
+		t.Fatalf("expected missing variable error, got: %v", err)
+	}
+}
+
 func TestJobGetter_HTTPServer(t *testing.T) {
 	t.Parallel()
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -295,6 +366,43 @@ func TestJobGetter_HTTPServer(t *testing.T) {
 	}
 }
 
+func TestHelpers_PeriodicLaunches(t *testing.T) {
+	t.Parallel()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	from := time.Date(2019, time.January, 1, 12, 0, 0, 0, loc)
+
+	// The launches are in the location of the passed time
+	p := &api.PeriodicConfig{
+		Spec:     helper.StringToPtr("0 3 * * *"),
+		TimeZone: helper.StringToPtr("America/New_York"),
+	}
+	launches, err := periodicLaunches(p, from, 3)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := []time.Time{
+		time.Date(2019, time.January, 2, 3, 0, 0, 0, loc),
+		time.Date(2019, time.January, 3, 3, 0, 0, 0, loc),
+		time.Date(2019, time.January, 4, 3, 0, 0, 0, loc),
+	}
+	if !reflect.DeepEqual(launches, expected) {
+		t.Fatalf("bad launches: %v", pretty.Diff(launches, expected))
+	}
+
+	// Specs that stop matching return fewer launches
+	p.Spec = helper.StringToPtr("0 3 1 1 * 2020")
+	launches, err = periodicLaunches(p, from, 3)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if len(launches) != 1 || !launches[0].Equal(time.Date(2020, time.January, 1, 3, 0, 0, 0, loc)) {
+		t.Fatalf("bad launches: %v", launches)
+	}
+}
+
 func TestPrettyTimeDiff(t *testing.T) {
 	// Grab the time and truncate to the nearest second. This allows our tests
 	// to be deterministic since we don't have to worry about rounding.
@@ -27,6 +27,10 @@ Deployments Options:
   -json
     Output the deployments in a JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display deployments using a Go template.
 
@@ -47,6 +51,7 @@ func (c *JobDeploymentsCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-json":    complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
 			"-t":       complete.PredictAnything,
 			"-latest":  complete.PredictNothing,
 			"-verbose": complete.PredictNothing,
@@ -54,37 +59,30 @@ func (c *JobDeploymentsCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *JobDeploymentsCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobDeploymentsCommand) Name() string { return "job deployments" }
 
 func (c *JobDeploymentsCommand) Run(args []string) int {
-	var json, latest, verbose bool
-	var tmpl string
+	var latest, verbose bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&latest, "latest", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got exactly one node
 	args = flags.Args()
 	if l := len(args); l != 1 {
@@ -131,8 +129,8 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 			return 1
 		}
 
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, deploy)
+		if format.enabled() {
+			out, err := format.Format(deploy)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -152,8 +150,8 @@ func (c *JobDeploymentsCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, deploys)
+	if format.enabled() {
+		out, err := format.Format(deploys)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
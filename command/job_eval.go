@@ -6,6 +6,8 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
 
@@ -32,6 +34,12 @@ Eval Options:
     Force reschedule failed allocations even if they are not currently
     eligible for rescheduling.
 
+  -forget-node
+    Stop the allocations of a system job on the node with the given ID or ID
+    prefix and never place the job on it again, by adding the job to the
+    node's "nomad.exclude_system_jobs" metadata. The node must be running to
+    update its metadata. This flag may be repeated.
+
   -detach
     Return immediately instead of entering monitor mode. The ID
     of the evaluation created will be printed to the screen, which can be
@@ -51,34 +59,26 @@ func (c *JobEvalCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-force-reschedule": complete.PredictNothing,
+			"-forget-node":      c.Meta.PredictSearch(contexts.Nodes),
 			"-detach":           complete.PredictNothing,
 			"-verbose":          complete.PredictNothing,
 		})
 }
 
 func (c *JobEvalCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobEvalCommand) Name() string { return "job eval" }
 
 func (c *JobEvalCommand) Run(args []string) int {
 	var detach, verbose bool
+	var forgetNodes []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&c.forceRescheduling, "force-reschedule", false, "")
+	flags.Var((*flaghelper.StringFlag)(&forgetNodes), "forget-node", "")
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
 
@@ -109,6 +109,13 @@ func (c *JobEvalCommand) Run(args []string) int {
 	// Call eval endpoint
 	jobID := args[0]
 
+	if len(forgetNodes) != 0 {
+		if err := c.forgetNodes(client, jobID, forgetNodes); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
 	opts := api.EvalOptions{
 		ForceReschedule: c.forceRescheduling,
 	}
@@ -126,3 +133,55 @@ func (c *JobEvalCommand) Run(args []string) int {
 	mon := newMonitor(c.Ui, client, length)
 	return mon.monitor(evalId, false)
 }
+
+// forgetNodes adds the system job to the jobs excluded by the metadata of the
+// nodes, so that the job's allocations on them are stopped.
+func (c *JobEvalCommand) forgetNodes(client *api.Client, jobID string, nodes []string) error {
+	job, _, err := client.Jobs().Info(jobID, nil)
+	if err != nil {
+		return fmt.Errorf("Error querying job: %s", err)
+	}
+	if job.Type == nil || *job.Type != api.JobTypeSystem {
+		return fmt.Errorf("-forget-node is only supported for system jobs")
+	}
+
+	for _, node := range nodes {
+		nodeID, err := lookupNodeMetaID(client, node)
+		if err != nil {
+			return err
+		}
+
+		meta, err := client.Nodes().ReadMeta(nodeID, nil)
+		if err != nil {
+			return fmt.Errorf("Error reading node metadata: %s", err)
+		}
+		value, ok := excludeSystemJob(meta.Meta[structs.NodeMetaExcludeSystemJobs], *job.ID)
+		if !ok {
+			continue
+		}
+
+		req := &api.NodeMetaApplyRequest{
+			NodeID: nodeID,
+			Meta: map[string]*string{
+				structs.NodeMetaExcludeSystemJobs: &value,
+			},
+		}
+		if _, err := client.Nodes().ApplyMeta(req, nil); err != nil {
+			return fmt.Errorf("Error applying node metadata: %s", err)
+		}
+		c.Ui.Output(fmt.Sprintf("Node %q excludes job %q", limit(nodeID, shortId), *job.ID))
+	}
+	return nil
+}
+
+// excludeSystemJob returns the value of the node metadata excluding system
+// jobs with the job added, or false if the node already excludes the job.
+func excludeSystemJob(value, jobID string) (string, bool) {
+	jobs := structs.ParseExcludedSystemJobs(value)
+	for _, job := range jobs {
+		if job == jobID || job == structs.NodeExcludeAllSystemJobs {
+			return value, false
+		}
+	}
+	return strings.Join(append(jobs, jobID), ","), true
+}
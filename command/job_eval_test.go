@@ -102,6 +102,85 @@ func TestJobEvalCommand_Run(t *testing.T) {
 
 }
 
+func TestJobEvalCommand_ForgetNode(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for a node to be ready
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		for _, node := range nodes {
+			if node.Status == structs.NodeStatusReady {
+				nodeID = node.ID
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no ready nodes")
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	state := srv.Agent.Server().State()
+	job := mock.Job()
+	require.NoError(state.UpsertJob(11, job))
+	sysJob := mock.SystemJob()
+	require.NoError(state.UpsertJob(12, sysJob))
+
+	// Only system jobs can forget nodes
+	ui := new(cli.MockUi)
+	cmd := &JobEvalCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + url, "-forget-node=" + nodeID, "-detach", job.ID}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	require.Contains(ui.ErrorWriter.String(), "only supported for system jobs")
+
+	ui = new(cli.MockUi)
+	cmd = &JobEvalCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + url, "-forget-node=" + nodeID[:8], "-detach", sysJob.ID}); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	require.Contains(ui.OutputWriter.String(), "excludes job")
+
+	// The node registration reflects the exclusion
+	testutil.WaitForResult(func() (bool, error) {
+		node, _, err := client.Nodes().Info(nodeID, nil)
+		if err != nil {
+			return false, err
+		}
+		if node.Meta[structs.NodeMetaExcludeSystemJobs] != sysJob.ID {
+			return false, fmt.Errorf("node doesn't exclude job: %v", node.Meta)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestExcludeSystemJob(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	value, ok := excludeSystemJob("", "web")
+	require.True(ok)
+	require.Equal("web", value)
+
+	value, ok = excludeSystemJob("api, cache", "web")
+	require.True(ok)
+	require.Equal("api,cache,web", value)
+
+	_, ok = excludeSystemJob("api,web", "web")
+	require.False(ok)
+
+	_, ok = excludeSystemJob(structs.NodeExcludeAllSystemJobs, "web")
+	require.False(ok)
+}
+
 func TestJobEvalCommand_AutocompleteArgs(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
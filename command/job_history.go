@@ -44,6 +44,10 @@ History Options:
   -json
     Output the job versions in a JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display the job versions using a Go template.
 `
@@ -61,43 +65,38 @@ func (c *JobHistoryCommand) AutocompleteFlags() complete.Flags {
 			"-full":    complete.PredictNothing,
 			"-version": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
 			"-t":       complete.PredictAnything,
 		})
 }
 
 func (c *JobHistoryCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobHistoryCommand) Name() string { return "job history" }
 
 func (c *JobHistoryCommand) Run(args []string) int {
-	var json, diff, full bool
-	var tmpl, versionStr string
+	var diff, full bool
+	var versionStr string
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&diff, "p", false, "")
 	flags.BoolVar(&full, "full", false, "")
-	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&versionStr, "version", "", "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got exactly one node
 	args = flags.Args()
 	if l := len(args); l < 1 || l > 2 {
@@ -106,8 +105,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		return 1
 	}
 
-	if (json || len(tmpl) != 0) && (diff || full) {
-		c.Ui.Error("-json and -t are exclusive with -p and -full")
+	if format.enabled() && (diff || full) {
+		c.Ui.Error("-json, -t and -output are exclusive with -p and -full")
 		return 1
 	}
 
@@ -171,8 +170,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			}
 		}
 
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, job)
+		if format.enabled() {
+			out, err := format.Format(job)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -188,8 +187,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		}
 
 	} else {
-		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, versions)
+		if format.enabled() {
+			out, err := format.Format(versions)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
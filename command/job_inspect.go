@@ -29,9 +29,18 @@ Inspect Options:
   -version <job version>
     Display the job at the given job version.
 
+  -source
+    Display the job file the job version was submitted with, followed by the
+    values of its variables as comments. The output formats display the job
+    file and the variable values as an object.
+
   -json
     Output the job in its JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display job using a Go template.
 `
@@ -46,41 +55,38 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-version": complete.PredictAnything,
+			"-source":  complete.PredictNothing,
 			"-json":    complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
 			"-t":       complete.PredictAnything,
 		})
 }
 
 func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
-	var tmpl, versionStr string
+	var versionStr string
+	var source bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 	flags.StringVar(&versionStr, "version", "", "")
+	flags.BoolVar(&source, "source", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
+
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
 	args = flags.Args()
 
 	// Get the HTTP client
@@ -91,14 +97,14 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 
 	// If args not specified but output format is specified, format and output the jobs data list
-	if len(args) == 0 && json || len(tmpl) > 0 {
+	if len(args) == 0 && format.enabled() {
 		jobs, _, err := client.Jobs().List(nil)
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying jobs: %v", err))
 			return 1
 		}
 
-		out, err := Format(json, tmpl, jobs)
+		out, err := format.Format(jobs)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -149,9 +155,13 @@ func (c *JobInspectCommand) Run(args []string) int {
 		return 1
 	}
 
+	if source {
+		return c.outputSource(client, job, &format)
+	}
+
 	// If output format is specified, format and output the data
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, job)
+	if format.enabled() {
+		out, err := format.Format(job)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -178,6 +188,36 @@ func (c *JobInspectCommand) Run(args []string) int {
 	return 0
 }
 
+// outputSource outputs the job file the job version was submitted with.
+func (c *JobInspectCommand) outputSource(client *api.Client, job *api.Job, format *outputFormat) int {
+	sub, _, err := client.Jobs().Submission(*job.ID, *job.Version, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving job file of version %d: %s", *job.Version, err))
+		return 1
+	}
+
+	if format.enabled() {
+		out, err := format.Format(sub)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(strings.TrimRight(sub.Source, "\n"))
+	if sub.Variables != "" {
+		var vars []string
+		for _, line := range strings.Split(strings.TrimRight(sub.Variables, "\n"), "\n") {
+			vars = append(vars, "# "+line)
+		}
+		c.Ui.Output("\n# Variable values:\n" + strings.Join(vars, "\n"))
+	}
+	return 0
+}
+
 // getJob retrieves the job optionally at a particular version.
 func getJob(client *api.Client, jobID string, version *uint64) (*api.Job, error) {
 	if version == nil {
@@ -5,9 +5,11 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInspectCommand_Implements(t *testing.T) {
@@ -81,3 +83,37 @@ func TestInspectCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(j.ID, res[0])
 }
+
+func TestInspectCommand_Source(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	require.NoError(state.UpsertJob(1000, j))
+	require.NoError(state.UpsertJobSubmission(1001, &structs.JobSubmission{
+		Source:    "job \"example\" {}\n",
+		Variables: "count = 2\n",
+		Namespace: j.Namespace,
+		JobID:     j.ID,
+		Version:   j.Version,
+	}))
+
+	ui := new(cli.MockUi)
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-version=0", "-source", j.ID})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Equal("job \"example\" {}\n\n# Variable values:\n# count = 2\n", ui.OutputWriter.String())
+
+	// Versions registered without a job file fail
+	other := mock.Job()
+	require.NoError(state.UpsertJob(1002, other))
+
+	ui = new(cli.MockUi)
+	cmd = &JobInspectCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-source", other.ID})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error retrieving job file of version 0")
+}
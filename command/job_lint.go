@@ -0,0 +1,264 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// lintSeverityWarning findings make `nomad job validate` exit with 2.
+	lintSeverityWarning = "warning"
+
+	// lintSeverityError findings make `nomad job validate` exit with 1.
+	lintSeverityError = "error"
+)
+
+// LintFinding is a problem found in a job specification by a lint rule.
+type LintFinding struct {
+	Rule      string
+	Severity  string
+	TaskGroup string `json:",omitempty"`
+	Task      string `json:",omitempty"`
+	Message   string
+}
+
+// lintRule is a check of the job specification run by
+// `nomad job validate -lint`. Its findings are warnings unless the rule is
+// configured as an error.
+type lintRule struct {
+	Name        string
+	Description string
+	Check       func(job *api.Job) []*LintFinding
+}
+
+// lintRules are the built-in lint rules, sorted by name.
+var lintRules = []*lintRule{
+	{
+		Name:        "image-latest",
+		Description: "Tasks use images without a tag or with the latest tag",
+		Check:       lintImageLatest,
+	},
+	{
+		Name:        "resources-missing",
+		Description: "Tasks don't set their cpu and memory resources",
+		Check:       lintResourcesMissing,
+	},
+	{
+		Name:        "restart-unbounded",
+		Description: "Groups restart their tasks forever in the delay mode",
+		Check:       lintRestartUnbounded,
+	},
+	{
+		Name:        "update-missing",
+		Description: "Groups of service jobs don't have an update stanza",
+		Check:       lintUpdateMissing,
+	},
+}
+
+// lintConfig selects the lint rules that run and their severity.
+type lintConfig struct {
+	// Disabled are the names of the rules that don't run.
+	Disabled []string
+
+	// Errors are the names of the rules whose findings are errors.
+	Errors []string
+}
+
+// validate returns an error if the config references unknown rules.
+func (c *lintConfig) validate() error {
+	for _, name := range append(append([]string{}, c.Disabled...), c.Errors...) {
+		if lookupLintRule(name) == nil {
+			return fmt.Errorf("unknown lint rule %q", name)
+		}
+	}
+	return nil
+}
+
+// lookupLintRule returns the lint rule of the name, or nil if there is none.
+func lookupLintRule(name string) *lintRule {
+	for _, rule := range lintRules {
+		if rule.Name == name {
+			return rule
+		}
+	}
+	return nil
+}
+
+// lintJob runs the enabled lint rules against the job and returns their
+// findings, sorted by task group, task and rule.
+func lintJob(job *api.Job, conf *lintConfig) ([]*LintFinding, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+
+	disabled := helper.SliceStringToSet(conf.Disabled)
+	errors := helper.SliceStringToSet(conf.Errors)
+
+	findings := []*LintFinding{}
+	for _, rule := range lintRules {
+		if _, ok := disabled[rule.Name]; ok {
+			continue
+		}
+
+		severity := lintSeverityWarning
+		if _, ok := errors[rule.Name]; ok {
+			severity = lintSeverityError
+		}
+		for _, f := range rule.Check(job) {
+			f.Rule = rule.Name
+			f.Severity = severity
+			findings = append(findings, f)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.TaskGroup != b.TaskGroup {
+			return a.TaskGroup < b.TaskGroup
+		}
+		return a.Task < b.Task
+	})
+	return findings, nil
+}
+
+// formatLintFindings returns the findings as a list, prefixed with the task
+// group and task they apply to.
+func formatLintFindings(findings []*LintFinding) string {
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		var scope string
+		switch {
+		case f.Task != "":
+			scope = fmt.Sprintf("task %q in group %q: ", f.Task, f.TaskGroup)
+		case f.TaskGroup != "":
+			scope = fmt.Sprintf("group %q: ", f.TaskGroup)
+		}
+		lines = append(lines, fmt.Sprintf("* %s (%s): %s%s", f.Rule, f.Severity, scope, f.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatLintRules returns the help text listing the built-in lint rules.
+func formatLintRules() string {
+	lines := make([]string, 0, len(lintRules))
+	for _, rule := range lintRules {
+		lines = append(lines, fmt.Sprintf("    * %s: %s", rule.Name, rule.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lintGroupName returns the name of the task group, which may not be set in
+// job files given as JSON.
+func lintGroupName(tg *api.TaskGroup) string {
+	if tg.Name == nil {
+		return ""
+	}
+	return *tg.Name
+}
+
+func lintImageLatest(job *api.Job) []*LintFinding {
+	var findings []*LintFinding
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			image, ok := task.Config["image"].(string)
+			if !ok || image == "" {
+				continue
+			}
+
+			// Images pinned by digest are immutable
+			if strings.Contains(image, "@") {
+				continue
+			}
+
+			// The tag follows the last colon of the last path segment, as
+			// the registry host may have a port
+			name := image[strings.LastIndex(image, "/")+1:]
+			var message string
+			if i := strings.LastIndex(name, ":"); i == -1 {
+				message = fmt.Sprintf("image %q has no tag and defaults to the latest tag", image)
+			} else if name[i+1:] == "latest" {
+				message = fmt.Sprintf("image %q uses the latest tag", image)
+			} else {
+				continue
+			}
+
+			findings = append(findings, &LintFinding{
+				TaskGroup: lintGroupName(tg),
+				Task:      task.Name,
+				Message:   message + "; pin a version so that restarts and rescheduling run the same image",
+			})
+		}
+	}
+	return findings
+}
+
+func lintResourcesMissing(job *api.Job) []*LintFinding {
+	defaults := api.DefaultResources()
+
+	var findings []*LintFinding
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			r := task.Resources
+			if r != nil && (r.CPU != nil || r.Cores != nil) && r.MemoryMB != nil {
+				continue
+			}
+
+			var missing []string
+			if r == nil || (r.CPU == nil && r.Cores == nil) {
+				missing = append(missing, fmt.Sprintf("cpu (defaults to %d MHz)", *defaults.CPU))
+			}
+			if r == nil || r.MemoryMB == nil {
+				missing = append(missing, fmt.Sprintf("memory (defaults to %d MB)", *defaults.MemoryMB))
+			}
+			findings = append(findings, &LintFinding{
+				TaskGroup: lintGroupName(tg),
+				Task:      task.Name,
+				Message:   fmt.Sprintf("resources don't set %s", strings.Join(missing, " and ")),
+			})
+		}
+	}
+	return findings
+}
+
+func lintRestartUnbounded(job *api.Job) []*LintFinding {
+	var findings []*LintFinding
+	for _, tg := range job.TaskGroups {
+		rp := tg.RestartPolicy
+		if rp == nil || rp.Mode == nil || *rp.Mode != api.RestartPolicyModeDelay {
+			continue
+		}
+
+		findings = append(findings, &LintFinding{
+			TaskGroup: lintGroupName(tg),
+			Message: fmt.Sprintf("restart mode %q restarts failing tasks forever instead of rescheduling them; use mode %q",
+				api.RestartPolicyModeDelay, api.RestartPolicyModeFail),
+		})
+	}
+	return findings
+}
+
+func lintUpdateMissing(job *api.Job) []*LintFinding {
+	if job.Type != nil && *job.Type != api.JobTypeService {
+		return nil
+	}
+	if job.Update != nil {
+		return nil
+	}
+
+	var findings []*LintFinding
+	for _, tg := range job.TaskGroups {
+		if tg.Update != nil {
+			continue
+		}
+
+		findings = append(findings, &LintFinding{
+			TaskGroup: lintGroupName(tg),
+			Message:   "no update stanza; updates replace all allocations at once without health checks, canaries or auto-revert",
+		})
+	}
+	return findings
+}
@@ -0,0 +1,118 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintJob(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job := &api.Job{
+		ID: helper.StringToPtr("example"),
+		TaskGroups: []*api.TaskGroup{
+			{
+				Name: helper.StringToPtr("web"),
+				RestartPolicy: &api.RestartPolicy{
+					Mode: helper.StringToPtr(api.RestartPolicyModeDelay),
+				},
+				Tasks: []*api.Task{
+					{
+						Name:   "pinned",
+						Driver: "docker",
+						Config: map[string]interface{}{"image": "registry:5000/redis:3.2"},
+						Resources: &api.Resources{
+							CPU:      helper.IntToPtr(100),
+							MemoryMB: helper.IntToPtr(128),
+						},
+					},
+					{
+						Name:   "latest",
+						Driver: "docker",
+						Config: map[string]interface{}{"image": "registry:5000/redis"},
+						Resources: &api.Resources{
+							Cores: helper.IntToPtr(1),
+						},
+					},
+				},
+			},
+			{
+				Name:   helper.StringToPtr("cache"),
+				Update: &api.UpdateStrategy{},
+				Tasks: []*api.Task{
+					{
+						Name:   "redis",
+						Driver: "docker",
+						Config: map[string]interface{}{"image": "redis@sha256:abcdef"},
+					},
+				},
+			},
+		},
+	}
+
+	findings, err := lintJob(job, &lintConfig{})
+	require.NoError(err)
+	require.Equal([]*LintFinding{
+		{
+			Rule:      "resources-missing",
+			Severity:  lintSeverityWarning,
+			TaskGroup: "cache",
+			Task:      "redis",
+			Message:   "resources don't set cpu (defaults to 100 MHz) and memory (defaults to 300 MB)",
+		},
+		{
+			Rule:      "restart-unbounded",
+			Severity:  lintSeverityWarning,
+			TaskGroup: "web",
+			Message:   `restart mode "delay" restarts failing tasks forever instead of rescheduling them; use mode "fail"`,
+		},
+		{
+			Rule:      "update-missing",
+			Severity:  lintSeverityWarning,
+			TaskGroup: "web",
+			Message:   "no update stanza; updates replace all allocations at once without health checks, canaries or auto-revert",
+		},
+		{
+			Rule:      "image-latest",
+			Severity:  lintSeverityWarning,
+			TaskGroup: "web",
+			Task:      "latest",
+			Message:   `image "registry:5000/redis" has no tag and defaults to the latest tag; pin a version so that restarts and rescheduling run the same image`,
+		},
+		{
+			Rule:      "resources-missing",
+			Severity:  lintSeverityWarning,
+			TaskGroup: "web",
+			Task:      "latest",
+			Message:   "resources don't set memory (defaults to 300 MB)",
+		},
+	}, findings)
+
+	// Rules can be disabled or configured as errors
+	findings, err = lintJob(job, &lintConfig{
+		Disabled: []string{"resources-missing", "restart-unbounded"},
+		Errors:   []string{"image-latest"},
+	})
+	require.NoError(err)
+	require.Len(findings, 2)
+	require.Equal("update-missing", findings[0].Rule)
+	require.Equal(lintSeverityWarning, findings[0].Severity)
+	require.Equal("image-latest", findings[1].Rule)
+	require.Equal(lintSeverityError, findings[1].Severity)
+
+	// Batch jobs don't need an update stanza
+	job.Type = helper.StringToPtr(api.JobTypeBatch)
+	job.TaskGroups[0].Tasks[1].Config["image"] = "redis:latest"
+	findings, err = lintJob(job, &lintConfig{Disabled: []string{"resources-missing", "restart-unbounded"}})
+	require.NoError(err)
+	require.Len(findings, 1)
+	require.Equal(`image "redis:latest" uses the latest tag; pin a version so that restarts and rescheduling run the same image`, findings[0].Message)
+
+	// Unknown rules are rejected
+	_, err = lintJob(job, &lintConfig{Errors: []string{"nope"}})
+	require.EqualError(err, `unknown lint rule "nope"`)
+}
@@ -71,9 +71,32 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -json
+    Output the plan results in JSON format. Equivalent to -output=json.
+
+  -output=<json|yaml|template>
+    Output the plan results in the given format instead of the human readable
+    summary. The output contains the structured diff, the scheduler's
+    annotations, placement failures, warnings and the job modify index. The
+    exit code is unchanged.
+
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
 
+  -t
+    Format and display the plan results using a Go template. Equivalent to
+    -output=template.
+
+  -var 'name=value'
+    Sets the value of a variable declared by the job file. Can be specified
+    multiple times. Lists and maps are given in HCL syntax.
+
+  -var-file=<path>
+    Sets the variables assigned in the given HCL file of name = value lines.
+    Can be specified multiple times. The -var flags take precedence over the
+    variable files, which take precedence over the NOMAD_VAR_<name>
+    environment variables.
+
   -verbose
     Increase diff verbosity.
 `
@@ -88,7 +111,12 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
+			"-json":            complete.PredictNothing,
+			"-output":          complete.PredictSet("json", "yaml", "template"),
 			"-policy-override": complete.PredictNothing,
+			"-t":               complete.PredictAnything,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*"),
 			"-verbose":         complete.PredictNothing,
 		})
 }
@@ -100,12 +128,15 @@ func (c *JobPlanCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
 	var diff, policyOverride, verbose bool
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&diff, "diff", true, "")
+	format.setFlags(flags)
 	flags.BoolVar(&policyOverride, "policy-override", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	c.JobGetter.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 255
@@ -119,6 +150,12 @@ func (c *JobPlanCommand) Run(args []string) int {
 		return 255
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 255
+	}
+
 	path := args[0]
 	// Get Job struct from Jobfile
 	job, err := c.JobGetter.ApiJob(args[0])
@@ -160,6 +197,16 @@ func (c *JobPlanCommand) Run(args []string) int {
 		return 255
 	}
 
+	if format.enabled() {
+		out, err := format.Format(resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 255
+		}
+		c.Ui.Output(out)
+		return getExitCode(resp)
+	}
+
 	// Print the diff if not disabled
 	if diff {
 		c.Ui.Output(fmt.Sprintf("%s\n",
@@ -316,6 +363,16 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 			now := time.Now().In(loc)
 			out += fmt.Sprintf("[green]- If submitted now, next periodic launch would be at %s (%s from now).\n",
 				formatTime(next), formatTimeDifference(now, next, time.Second))
+
+			launches, err := periodicLaunches(job.Periodic, next.In(loc), periodicLaunchPreview-1)
+			if err != nil {
+				out += fmt.Sprintf("[yellow]- Failed to determine the following periodic launches: %v\n", err)
+			} else if len(launches) > 0 {
+				out += "[green]  Following launches would be at:\n"
+				for _, l := range launches {
+					out += fmt.Sprintf("[green]    %s (%s from now)\n", formatTime(l), formatTimeDifference(now, l, time.Second))
+				}
+			}
 		}
 	}
 
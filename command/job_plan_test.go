@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -113,6 +114,66 @@ job "job1" {
 	}
 }
 
+func TestPlanCommand_Output_Json(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobPlanCommand{Meta: Meta{Ui: ui}}
+
+	fh, err := ioutil.TempFile("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+job "job1" {
+	type = "service"
+	datacenters = [ "dc1" ]
+	group "group1" {
+		count = 1
+		task "task1" {
+			driver = "exec"
+			resources = {
+				cpu = 1000
+				memory = 512
+			}
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Rejects unknown formats
+	if code := cmd.Run([]string{"-address=" + url, "-output=xml", fh.Name()}); code != 255 {
+		t.Fatalf("expected exit code 255, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Unsupported output format") {
+		t.Fatalf("expected unsupported format error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// The job is new, so the plan places allocations and exits 1
+	if code := cmd.Run([]string{"-address=" + url, "-output=json", fh.Name()}); code != 1 {
+		t.Fatalf("expected exit code 1, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	var resp api.JobPlanResponse
+	out := ui.OutputWriter.String()
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("expected JSON output: %v\n%s", err, out)
+	}
+	require := require.New(t)
+	require.NotNil(resp.Diff)
+	require.Equal("Added", resp.Diff.Type)
+	require.NotNil(resp.Annotations)
+	require.Equal(uint64(1), resp.Annotations.DesiredTGUpdates["group1"].Place)
+	require.Contains(resp.FailedTGAllocs, "group1")
+	require.NotContains(out, "Scheduler dry-run")
+}
+
 func TestPlanCommand_From_STDIN(t *testing.T) {
 	t.Parallel()
 	stdinR, stdinW, err := os.Pipe()
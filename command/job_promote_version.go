@@ -0,0 +1,106 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobPromoteVersionCommand struct {
+	Meta
+}
+
+func (c *JobPromoteVersionCommand) Help() string {
+	helpText := `
+Usage: nomad job promote-version [options] <job> <version>
+
+  Promote-version pins the job to a minimum version. Once pinned, registering
+  a job spec matching an older version of the job, such as one from a stale
+  build artifact, or reverting to an older version is rejected unless the
+  -override-minimum-version flag of "nomad job run" or "nomad job revert" is
+  set. This protects against deploy pipelines completing out of order.
+
+  The version must not be newer than the current version of the job. Pinning
+  the version 0 unpins the job. Only the most recent versions of the job are
+  tracked, so specs matching older untracked versions can't be detected.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobPromoteVersionCommand) Synopsis() string {
+	return "Pin a job to a minimum version"
+}
+
+func (c *JobPromoteVersionCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *JobPromoteVersionCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Jobs)
+}
+
+func (c *JobPromoteVersionCommand) Name() string { return "job promote-version" }
+
+func (c *JobPromoteVersionCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got two args
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error("This command takes two arguments: <job> <version>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	jobID := args[0]
+	version, _, err := parseVersion(args[1])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse version: %v", err))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing jobs: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 && strings.TrimSpace(jobID) != jobs[0].ID {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
+		return 1
+	}
+
+	// Prefix lookup matched a single job
+	if _, _, err := client.Jobs().PinVersion(jobs[0].ID, version, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error pinning job version: %s", err))
+		return 1
+	}
+
+	if version == 0 {
+		c.Ui.Output(fmt.Sprintf("Job %q unpinned", jobs[0].ID))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Job %q pinned to minimum version %d", jobs[0].ID, version))
+	}
+	return 0
+}
@@ -0,0 +1,241 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+type JobRestartCommand struct {
+	Meta
+}
+
+func (c *JobRestartCommand) Help() string {
+	helpText := `
+Usage: nomad job restart [options] <job>
+
+  Restart the running allocations of a job in place. The tasks of each
+  allocation are restarted on the node they are running on, without
+  rescheduling the allocation or registering a new version of the job. This is
+  useful to have tasks pick up new secrets or flush their caches.
+
+  Allocations are restarted group by group, in batches the size of the group's
+  update max_parallel. Each batch must become healthy before the next batch is
+  restarted, using the health_check, min_healthy_time and healthy_deadline of
+  the group's update stanza. If an allocation is unhealthy after being
+  restarted, the remaining allocations are not restarted and the command exits
+  with an error.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Restart Options:
+
+  -batch-size=<n>
+    Number of allocations of each group to restart at a time. Defaults to the
+    max_parallel of the group's update stanza, or 1 if it is not set.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobRestartCommand) Synopsis() string {
+	return "Restart the allocations of a job in place"
+}
+
+func (c *JobRestartCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-batch-size": complete.PredictAnything,
+			"-verbose":    complete.PredictNothing,
+		})
+}
+
+func (c *JobRestartCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Jobs)
+}
+
+func (c *JobRestartCommand) Name() string { return "job restart" }
+
+func (c *JobRestartCommand) Run(args []string) int {
+	var batchSize int
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.IntVar(&batchSize, "batch-size", 0, "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	jobID := args[0]
+
+	if batchSize < 0 {
+		c.Ui.Error("Batch size must not be negative")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 && strings.TrimSpace(jobID) != jobs[0].ID {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
+		return 1
+	}
+	// Prefix lookup matched a single job
+	job, _, err := client.Jobs().Info(jobs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+
+	allocs, _, err := client.Jobs().Allocations(*job.ID, false, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job allocations: %s", err))
+		return 1
+	}
+
+	// Only allocations that are running and should keep running are
+	// restarted
+	byGroup := make(map[string][]*api.AllocationListStub)
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus != structs.AllocDesiredStatusRun ||
+			alloc.ClientStatus != structs.AllocClientStatusRunning {
+			continue
+		}
+		byGroup[alloc.TaskGroup] = append(byGroup[alloc.TaskGroup], alloc)
+	}
+
+	if len(byGroup) == 0 {
+		c.Ui.Output(fmt.Sprintf("Job %q has no running allocations to restart", *job.ID))
+		return 0
+	}
+
+	for _, tg := range job.TaskGroups {
+		groupAllocs := byGroup[*tg.Name]
+		if len(groupAllocs) == 0 {
+			continue
+		}
+		sort.Slice(groupAllocs, func(i, j int) bool {
+			return groupAllocs[i].Name < groupAllocs[j].Name
+		})
+
+		size := restartBatchSize(tg, batchSize)
+		c.Ui.Output(fmt.Sprintf("==> Restarting %d allocation(s) of group %q, %d at a time",
+			len(groupAllocs), *tg.Name, size))
+
+		for i := 0; i < len(groupAllocs); i += size {
+			end := i + size
+			if end > len(groupAllocs) {
+				end = len(groupAllocs)
+			}
+
+			if err := c.restartBatch(client, groupAllocs[i:end], length); err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+		}
+	}
+
+	c.Ui.Output(fmt.Sprintf("==> Job %q restarted", *job.ID))
+	return 0
+}
+
+// restartBatch restarts the allocations concurrently and waits for them to be
+// healthy. An error is returned if any allocation fails to restart or is
+// unhealthy after restarting.
+func (c *JobRestartCommand) restartBatch(client *api.Client, allocs []*api.AllocationListStub, length int) error {
+	type result struct {
+		healthy bool
+		err     error
+	}
+
+	results := make([]result, len(allocs))
+	wg := sync.WaitGroup{}
+	for i, alloc := range allocs {
+		c.Ui.Output(fmt.Sprintf("    Restarting allocation %q", limit(alloc.ID, length)))
+
+		wg.Add(1)
+		go func(i int, alloc *api.AllocationListStub) {
+			defer wg.Done()
+			resp, err := client.Allocations().Restart(&api.Allocation{ID: alloc.ID},
+				&api.AllocRestartRequest{WaitHealthy: true}, nil)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			results[i].healthy = resp.Healthy
+		}(i, alloc)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, alloc := range allocs {
+		id := limit(alloc.ID, length)
+		switch res := results[i]; {
+		case res.err != nil:
+			failed = append(failed, fmt.Sprintf("Error restarting allocation %q: %s", id, res.err))
+		case !res.healthy:
+			failed = append(failed, fmt.Sprintf("Allocation %q is unhealthy after restarting", id))
+		default:
+			c.Ui.Output(fmt.Sprintf("    Allocation %q restarted and healthy", id))
+		}
+	}
+
+	if len(failed) != 0 {
+		return fmt.Errorf("%s\nThe remaining allocations were not restarted", strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// restartBatchSize returns the number of the group's allocations to restart
+// at a time. The override is used if set and otherwise the max_parallel of the
+// group's update stanza.
+func restartBatchSize(tg *api.TaskGroup, override int) int {
+	if override > 0 {
+		return override
+	}
+	if tg.Update != nil && tg.Update.MaxParallel != nil && *tg.Update.MaxParallel > 0 {
+		return *tg.Update.MaxParallel
+	}
+	return 1
+}
@@ -0,0 +1,127 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRestartCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &JobRestartCommand{}
+}
+
+func TestJobRestartCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobRestartCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on a negative batch size
+	if code := cmd.Run([]string{"-batch-size=-1", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "must not be negative") {
+		t.Fatalf("expected batch size error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying job") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestJobRestartCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		if _, ok := nodes[0].Drivers["mock_driver"]; !ok {
+			return false, fmt.Errorf("mock_driver not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Register a long running service job
+	job := testJob("job1")
+	job.Type = helper.StringToPtr(api.JobTypeService)
+	job.TaskGroups[0].Count = helper.IntToPtr(2)
+	job.TaskGroups[0].Tasks[0].Config["run_for"] = "30s"
+	job.TaskGroups[0].Update = &api.UpdateStrategy{
+		MaxParallel:     helper.IntToPtr(1),
+		HealthCheck:     helper.StringToPtr("task_states"),
+		MinHealthyTime:  helper.TimeToPtr(100 * time.Millisecond),
+		HealthyDeadline: helper.TimeToPtr(10 * time.Second),
+	}
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations("job1", false, nil)
+		if err != nil {
+			return false, err
+		}
+		running := 0
+		for _, alloc := range allocs {
+			if alloc.ClientStatus == api.AllocClientStatusRunning {
+				running++
+			}
+		}
+		if running != 2 {
+			return false, fmt.Errorf("expected 2 running allocations, got %d", running)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	ui := new(cli.MockUi)
+	cmd := &JobRestartCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, `Restarting 2 allocation(s) of group "group1", 1 at a time`)
+	require.Equal(2, strings.Count(out, "restarted and healthy"), out)
+	require.Contains(out, `Job "job1" restarted`)
+
+	// The allocations were restarted in place
+	allocs, _, err := client.Jobs().Allocations("job1", false, nil)
+	require.NoError(err)
+	require.Len(allocs, 2)
+	for _, stub := range allocs {
+		state := stub.TaskStates["task1"]
+		require.NotNil(state)
+		require.Equal(uint64(1), state.Restarts)
+	}
+}
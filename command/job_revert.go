@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
 	"github.com/posener/complete"
 )
@@ -30,6 +31,10 @@ Revert Options:
     the evaluation ID will be printed to the screen, which can be used to
     examine the evaluation using the eval-status command.
 
+  -override-minimum-version
+    Reverts the job even if the version is older than the minimum version the
+    job is pinned to with "nomad job promote-version".
+
   -verbose
     Display full information.
 `
@@ -43,35 +48,26 @@ func (c *JobRevertCommand) Synopsis() string {
 func (c *JobRevertCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-detach":  complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
+			"-detach":                   complete.PredictNothing,
+			"-verbose":                  complete.PredictNothing,
+			"-override-minimum-version": complete.PredictNothing,
 		})
 }
 
 func (c *JobRevertCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobRevertCommand) Name() string { return "job revert" }
 
 func (c *JobRevertCommand) Run(args []string) int {
-	var detach, verbose bool
+	var detach, verbose, overrideMinVersion bool
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&overrideMinVersion, "override-minimum-version", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -125,7 +121,8 @@ func (c *JobRevertCommand) Run(args []string) int {
 	}
 
 	// Prefix lookup matched a single job
-	resp, _, err := client.Jobs().Revert(jobs[0].ID, revertVersion, nil, nil)
+	opts := &api.RevertOptions{OverrideMinimumVersion: overrideMinVersion}
+	resp, _, err := client.Jobs().RevertOpts(jobs[0].ID, revertVersion, opts, nil)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving job versions: %s", err))
 		return 1
@@ -3,6 +3,7 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"regexp"
 	"strconv"
@@ -79,15 +80,34 @@ Run Options:
     Output the JSON that would be submitted to the HTTP API without submitting
     the job.
 
+  -override-minimum-version
+    Registers the job even if it matches a version older than the minimum
+    version the job is pinned to with "nomad job promote-version".
+
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
 
+  -signature=<path>
+    Path to the detached signature of the job produced by "nomad job sign".
+    The servers verify the signature against the signers configured for the
+    job's namespace.
+
   -vault-token
     If set, the passed Vault token is stored in the job before sending to the
     Nomad servers. This allows passing the Vault token without storing it in
     the job file. This overrides the token found in $VAULT_TOKEN environment
     variable and that found in the job.
 
+  -var 'name=value'
+    Sets the value of a variable declared by the job file. Can be specified
+    multiple times. Lists and maps are given in HCL syntax.
+
+  -var-file=<path>
+    Sets the variables assigned in the given HCL file of name = value lines.
+    Can be specified multiple times. The -var flags take precedence over the
+    variable files, which take precedence over the NOMAD_VAR_<name>
+    environment variables.
+
   -verbose
     Display full information.
 `
@@ -101,12 +121,16 @@ func (c *JobRunCommand) Synopsis() string {
 func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-check-index":     complete.PredictNothing,
-			"-detach":          complete.PredictNothing,
-			"-verbose":         complete.PredictNothing,
-			"-vault-token":     complete.PredictAnything,
-			"-output":          complete.PredictNothing,
-			"-policy-override": complete.PredictNothing,
+			"-check-index":              complete.PredictNothing,
+			"-detach":                   complete.PredictNothing,
+			"-verbose":                  complete.PredictNothing,
+			"-vault-token":              complete.PredictAnything,
+			"-var":                      complete.PredictAnything,
+			"-var-file":                 complete.PredictFiles("*"),
+			"-output":                   complete.PredictNothing,
+			"-policy-override":          complete.PredictNothing,
+			"-signature":                complete.PredictFiles("*"),
+			"-override-minimum-version": complete.PredictNothing,
 		})
 }
 
@@ -117,8 +141,8 @@ func (c *JobRunCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobRunCommand) Name() string { return "job run" }
 
 func (c *JobRunCommand) Run(args []string) int {
-	var detach, verbose, output, override bool
-	var checkIndexStr, vaultToken string
+	var detach, verbose, output, override, overrideMinVersion bool
+	var checkIndexStr, vaultToken, signaturePath string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -126,8 +150,11 @@ func (c *JobRunCommand) Run(args []string) int {
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&output, "output", false, "")
 	flags.BoolVar(&override, "policy-override", false, "")
+	flags.BoolVar(&overrideMinVersion, "override-minimum-version", false, "")
 	flags.StringVar(&checkIndexStr, "check-index", "", "")
 	flags.StringVar(&vaultToken, "vault-token", "", "")
+	flags.StringVar(&signaturePath, "signature", "", "")
+	c.JobGetter.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -148,7 +175,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.ApiJob(args[0])
+	job, submission, err := c.JobGetter.ApiJobWithSubmission(args[0])
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
@@ -205,7 +232,10 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Set the register options
-	opts := &api.RegisterOptions{}
+	opts := &api.RegisterOptions{
+		Submission:             submission,
+		OverrideMinimumVersion: overrideMinVersion,
+	}
 	if enforce {
 		opts.EnforceIndex = true
 		opts.ModifyIndex = checkIndex
@@ -213,6 +243,14 @@ func (c *JobRunCommand) Run(args []string) int {
 	if override {
 		opts.PolicyOverride = true
 	}
+	if signaturePath != "" {
+		signature, err := ioutil.ReadFile(signaturePath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading signature: %s", err))
+			return 1
+		}
+		opts.Signature = strings.TrimSpace(string(signature))
+	}
 
 	// Submit the job
 	resp, _, err := client.Jobs().RegisterOpts(job, opts, nil)
@@ -0,0 +1,162 @@
+package command
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+type JobSignCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *JobSignCommand) Help() string {
+	helpText := `
+Usage: nomad job sign [options] <path>
+
+  Computes the detached signature of the job specification located at <path>
+  with the given private key and outputs it base64 encoded. The signature is
+  submitted with the job by passing it to "nomad job run -signature". Servers
+  configured with the matching public key for the job's namespace verify the
+  signature and record the signer in the job's metadata.
+
+  The signature covers the job as submitted, so the same variables must be
+  given when signing and running the job. The Vault token of the job isn't
+  covered by the signature.
+
+  If the supplied path is "-", the jobfile is read from stdin. Otherwise
+  it is read from the file at the supplied path or downloaded and
+  read from URL specified.
+
+Sign Options:
+
+  -key=<path>
+    Path to the PEM encoded private key to sign the job with. Ed25519, ECDSA
+    and RSA keys are supported. Required.
+
+  -var 'name=value'
+    Sets the value of a variable declared by the job file. Can be specified
+    multiple times. Lists and maps are given in HCL syntax.
+
+  -var-file=<path>
+    Sets the variables assigned in the given HCL file of name = value lines.
+    Can be specified multiple times. The -var flags take precedence over the
+    variable files, which take precedence over the NOMAD_VAR_<name>
+    environment variables.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobSignCommand) Synopsis() string {
+	return "Sign a job specification"
+}
+
+func (c *JobSignCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-key":      complete.PredictFiles("*"),
+		"-var":      complete.PredictAnything,
+		"-var-file": complete.PredictFiles("*"),
+	}
+}
+
+func (c *JobSignCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(complete.PredictFiles("*.nomad"), complete.PredictFiles("*.hcl"))
+}
+
+func (c *JobSignCommand) Name() string { return "job sign" }
+
+func (c *JobSignCommand) Run(args []string) int {
+	var keyPath string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&keyPath, "key", "", "")
+	c.JobGetter.setFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if keyPath == "" {
+		c.Ui.Error("The -key flag is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	keyPEM, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading key: %s", err))
+		return 1
+	}
+	key, err := parseSigningKey(keyPEM)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing key: %s", err))
+		return 1
+	}
+
+	// Get Job struct from Jobfile
+	job, err := c.JobGetter.ApiJob(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+
+	// Compute the payload the same way the servers do
+	sJob := agent.ApiJobToStructJob(job)
+	sJob.Canonicalize()
+	payload, err := sJob.SigningPayload()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error encoding job: %s", err))
+		return 1
+	}
+
+	signature, err := structs.SignJobPayload(key, payload)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error signing job: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(base64.StdEncoding.EncodeToString(signature))
+	return 0
+}
+
+// parseSigningKey parses an Ed25519, ECDSA or RSA private key in the PKCS #1,
+// SEC 1 or PKCS #8 formats.
+func parseSigningKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode key PEM")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported key format")
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type")
+	}
+	return signer, nil
+}
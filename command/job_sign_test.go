@@ -0,0 +1,100 @@
+package command
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/jobspec"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobSignCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &JobSignCommand{}
+}
+
+func TestJobSignCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobSignCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails without a key
+	if code := cmd.Run([]string{"job.nomad"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-key flag is required") {
+		t.Fatalf("expected key error, got: %s", out)
+	}
+}
+
+func TestJobSignCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(err)
+
+	keyFile, err := ioutil.TempFile("", "nomad")
+	require.NoError(err)
+	defer os.Remove(keyFile.Name())
+	_, err = keyFile.Write(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(err)
+
+	jobFile, err := ioutil.TempFile("", "nomad")
+	require.NoError(err)
+	defer os.Remove(jobFile.Name())
+	_, err = jobFile.WriteString(`
+job "job1" {
+	type = "service"
+	datacenters = [ "dc1" ]
+	group "group1" {
+		count = 1
+		task "task1" {
+			driver = "exec"
+			config {
+				command = "/bin/sleep"
+			}
+		}
+	}
+}`)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &JobSignCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-key", keyFile.Name(), jobFile.Name()}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ui.OutputWriter.String()))
+	require.NoError(err)
+
+	// The signature verifies against the payload the servers compute
+	apiJob, err := jobspec.ParseFile(jobFile.Name())
+	require.NoError(err)
+	job := agent.ApiJobToStructJob(apiJob)
+	job.Canonicalize()
+	payload, err := job.SigningPayload()
+	require.NoError(err)
+	require.NoError(structs.VerifyJobSignature(pub, payload, signature))
+}
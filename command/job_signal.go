@@ -0,0 +1,228 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+type JobSignalCommand struct {
+	Meta
+}
+
+func (c *JobSignalCommand) Help() string {
+	helpText := `
+Usage: nomad job signal [options] <job>
+
+  Send a signal to the tasks of a job's running allocations. The signal is sent
+  to every matching allocation at once, which is useful to have all tasks of a
+  service reload their configuration.
+
+  By default the signal is sent to every task of every running allocation of
+  the job. The -group and -task flags restrict which allocations and tasks are
+  signaled.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Signal Options:
+
+  -signal=<signal>
+    The signal to send, such as SIGHUP. Required.
+
+  -group=<group>
+    Only signal the allocations of the given task group.
+
+  -task=<task>
+    Only signal the task with the given name.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobSignalCommand) Synopsis() string {
+	return "Send a signal to the allocations of a job"
+}
+
+func (c *JobSignalCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-signal":  complete.PredictAnything,
+			"-group":   complete.PredictAnything,
+			"-task":    complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *JobSignalCommand) AutocompleteArgs() complete.Predictor {
+	return c.Meta.PredictSearch(contexts.Jobs)
+}
+
+func (c *JobSignalCommand) Name() string { return "job signal" }
+
+func (c *JobSignalCommand) Run(args []string) int {
+	var signal, group, task string
+	var verbose bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&signal, "signal", "", "")
+	flags.StringVar(&group, "group", "", "")
+	flags.StringVar(&task, "task", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	jobID := args[0]
+
+	if signal == "" {
+		c.Ui.Error("A signal must be given with -signal")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobs, _, err := client.Jobs().PrefixList(jobID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+	if len(jobs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No job(s) with prefix or id %q found", jobID))
+		return 1
+	}
+	if len(jobs) > 1 && strings.TrimSpace(jobID) != jobs[0].ID {
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple jobs\n\n%s", createStatusListOutput(jobs)))
+		return 1
+	}
+	// Prefix lookup matched a single job
+	job, _, err := client.Jobs().Info(jobs[0].ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+		return 1
+	}
+
+	// Determine the groups to signal
+	groups := make(map[string]struct{})
+	for _, tg := range job.TaskGroups {
+		if group != "" && *tg.Name != group {
+			continue
+		}
+		if task != "" && !groupHasTask(tg, task) {
+			continue
+		}
+		groups[*tg.Name] = struct{}{}
+	}
+	if len(groups) == 0 {
+		switch {
+		case group != "" && task != "":
+			c.Ui.Error(fmt.Sprintf("Job %q has no task %q in group %q", *job.ID, task, group))
+		case group != "":
+			c.Ui.Error(fmt.Sprintf("Job %q has no group %q", *job.ID, group))
+		default:
+			c.Ui.Error(fmt.Sprintf("Job %q has no task %q", *job.ID, task))
+		}
+		return 1
+	}
+
+	allocs, _, err := client.Jobs().Allocations(*job.ID, false, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying job allocations: %s", err))
+		return 1
+	}
+
+	// Only allocations that are running and should keep running are
+	// signaled
+	var targets []*api.AllocationListStub
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus != structs.AllocDesiredStatusRun ||
+			alloc.ClientStatus != structs.AllocClientStatusRunning {
+			continue
+		}
+		if _, ok := groups[alloc.TaskGroup]; !ok {
+			continue
+		}
+		targets = append(targets, alloc)
+	}
+
+	if len(targets) == 0 {
+		c.Ui.Output(fmt.Sprintf("Job %q has no running allocations to signal", *job.ID))
+		return 0
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Name < targets[j].Name
+	})
+
+	c.Ui.Output(fmt.Sprintf("==> Sending %s to %d allocation(s)", signal, len(targets)))
+
+	errs := make([]error, len(targets))
+	wg := sync.WaitGroup{}
+	for i, alloc := range targets {
+		wg.Add(1)
+		go func(i int, alloc *api.AllocationListStub) {
+			defer wg.Done()
+			errs[i] = client.Allocations().Signal(&api.Allocation{ID: alloc.ID}, task, signal, nil)
+		}(i, alloc)
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, alloc := range targets {
+		id := limit(alloc.ID, length)
+		if err := errs[i]; err != nil {
+			failed++
+			c.Ui.Error(fmt.Sprintf("    Error signaling allocation %q: %s", id, err))
+			continue
+		}
+		c.Ui.Output(fmt.Sprintf("    Allocation %q signaled", id))
+	}
+
+	if failed != 0 {
+		c.Ui.Error(fmt.Sprintf("Failed to signal %d of %d allocation(s)", failed, len(targets)))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("==> Job %q signaled", *job.ID))
+	return 0
+}
+
+// groupHasTask returns whether the task group has a task with the given name.
+func groupHasTask(tg *api.TaskGroup, name string) bool {
+	for _, t := range tg.Tasks {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobSignalCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &JobSignalCommand{}
+}
+
+func TestJobSignalCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobSignalCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails without a signal
+	if code := cmd.Run([]string{"example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "must be given with -signal") {
+		t.Fatalf("expected signal error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "-signal=SIGHUP", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying job") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestJobSignalCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		if _, ok := nodes[0].Drivers["mock_driver"]; !ok {
+			return false, fmt.Errorf("mock_driver not ready")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Register a long running service job
+	job := testJob("job1")
+	job.Type = helper.StringToPtr(api.JobTypeService)
+	job.TaskGroups[0].Count = helper.IntToPtr(2)
+	job.TaskGroups[0].Tasks[0].Config["run_for"] = "30s"
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations("job1", false, nil)
+		if err != nil {
+			return false, err
+		}
+		running := 0
+		for _, alloc := range allocs {
+			if alloc.ClientStatus == api.AllocClientStatusRunning {
+				running++
+			}
+		}
+		if running != 2 {
+			return false, fmt.Errorf("expected 2 running allocations, got %d", running)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Fails on an unknown group
+	ui := new(cli.MockUi)
+	cmd := &JobSignalCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-signal=SIGHUP", "-group=foo", "job1"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), `Job "job1" has no group "foo"`)
+
+	ui = new(cli.MockUi)
+	cmd = &JobSignalCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-signal=SIGHUP", "-task=task1", "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Sending SIGHUP to 2 allocation(s)")
+	require.Equal(2, strings.Count(out, "    Allocation "), out)
+	require.Contains(out, `Job "job1" signaled`)
+
+	// The tasks received the signal
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations("job1", false, nil)
+		if err != nil {
+			return false, err
+		}
+		for _, stub := range allocs {
+			state := stub.TaskStates["task1"]
+			if state == nil {
+				return false, fmt.Errorf("missing task state of alloc %q", stub.ID)
+			}
+			found := false
+			for _, e := range state.Events {
+				if e.Type == api.TaskSignaling {
+					found = true
+				}
+			}
+			if !found {
+				return false, fmt.Errorf("missing signal event: %#v", state.Events)
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
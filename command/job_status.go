@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
@@ -24,6 +25,16 @@ type JobStatusCommand struct {
 	evals     bool
 	allAllocs bool
 	verbose   bool
+	format    outputFormat
+}
+
+// jobStatusOutput is the data output by the status command for a single job
+// when a machine readable format is requested.
+type jobStatusOutput struct {
+	Job              *api.Job
+	Summary          *api.JobSummary
+	Allocations      []*api.AllocationListStub
+	LatestDeployment *api.Deployment
 }
 
 func (c *JobStatusCommand) Help() string {
@@ -50,6 +61,22 @@ Status Options:
     Display all allocations matching the job ID, including those from an older
     instance of the job.
 
+  -tag <tag>
+    List only the jobs with the given tag. Used only when listing jobs. May be
+    specified multiple times, in which case only the jobs with all of the tags
+    are listed.
+
+  -json
+    Output the job status in JSON format. Equivalent to -output=json.
+
+  -output=<json|yaml|template>
+    Output the job status in the given format. For a single job the output
+    contains the job, its summary, its allocations and its latest deployment.
+
+  -t
+    Format and display the job status using a Go template. Equivalent to
+    -output=template.
+
   -verbose
     Display full information.
 `
@@ -65,30 +92,24 @@ func (c *JobStatusCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-all-allocs": complete.PredictNothing,
 			"-evals":      complete.PredictNothing,
+			"-json":       complete.PredictNothing,
+			"-output":     complete.PredictSet("json", "yaml", "template"),
 			"-short":      complete.PredictNothing,
+			"-t":          complete.PredictAnything,
+			"-tag":        complete.PredictAnything,
 			"-verbose":    complete.PredictNothing,
 		})
 }
 
 func (c *JobStatusCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobStatusCommand) Name() string { return "status" }
 
 func (c *JobStatusCommand) Run(args []string) int {
 	var short bool
+	var tags []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -96,11 +117,19 @@ func (c *JobStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.evals, "evals", false, "")
 	flags.BoolVar(&c.allAllocs, "all-allocs", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.Var((*flaghelper.StringFlag)(&tags), "tag", "")
+	c.format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := c.format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Check that we either got no jobs or exactly one.
 	args = flags.Args()
 	if len(args) > 1 {
@@ -122,14 +151,35 @@ func (c *JobStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if len(tags) != 0 && len(args) != 0 {
+		c.Ui.Error("The -tag flag can only be used when listing jobs")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Invoke list mode if no job ID.
 	if len(args) == 0 {
-		jobs, _, err := client.Jobs().List(nil)
+		var jobs []*api.JobListStub
+		if len(tags) != 0 {
+			jobs, _, err = client.Jobs().Tagged(tags, nil)
+		} else {
+			jobs, _, err = client.Jobs().List(nil)
+		}
 		if err != nil {
 			c.Ui.Error(fmt.Sprintf("Error querying jobs: %s", err))
 			return 1
 		}
 
+		if c.format.enabled() {
+			out, err := c.format.Format(jobs)
+			if err != nil {
+				c.Ui.Error(err.Error())
+				return 1
+			}
+			c.Ui.Output(out)
+			return 0
+		}
+
 		if len(jobs) == 0 {
 			// No output if we have no jobs
 			c.Ui.Output("No running jobs")
@@ -162,6 +212,16 @@ func (c *JobStatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.format.enabled() {
+		out, err := c.formatJobStatus(client, job)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
 	periodic := job.IsPeriodic()
 	parameterized := job.IsParameterized()
 
@@ -178,6 +238,14 @@ func (c *JobStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Parameterized|%v", parameterized),
 	}
 
+	if len(job.Tags) != 0 {
+		basic = append(basic, fmt.Sprintf("Tags|%s", strings.Join(job.Tags, ",")))
+	}
+
+	if job.MinimumVersion != 0 {
+		basic = append(basic, fmt.Sprintf("Minimum Version|%d", job.MinimumVersion))
+	}
+
 	if periodic && !parameterized {
 		if *job.Stop {
 			basic = append(basic, fmt.Sprintf("Next Periodic Launch|none (job stopped)"))
@@ -223,6 +291,34 @@ func (c *JobStatusCommand) Run(args []string) int {
 	return 0
 }
 
+// formatJobStatus returns the status of the job in the requested machine
+// readable format.
+func (c *JobStatusCommand) formatJobStatus(client *api.Client, job *api.Job) (string, error) {
+	out := &jobStatusOutput{Job: job}
+
+	summary, _, err := client.Jobs().Summary(*job.ID, nil)
+	if err != nil {
+		return "", fmt.Errorf("Error querying job summary: %s", err)
+	}
+	out.Summary = summary
+
+	allocs, _, err := client.Jobs().Allocations(*job.ID, c.allAllocs, nil)
+	if err != nil {
+		return "", fmt.Errorf("Error querying job allocations: %s", err)
+	}
+	out.Allocations = allocs
+
+	if !job.IsPeriodic() && !job.IsParameterized() {
+		deploy, _, err := client.Jobs().LatestDeployment(*job.ID, nil)
+		if err != nil {
+			return "", fmt.Errorf("Error querying latest job deployment: %s", err)
+		}
+		out.LatestDeployment = deploy
+	}
+
+	return c.format.Format(out)
+}
+
 // outputPeriodicInfo prints information about the passed periodic job. If a
 // request fails, an error is returned.
 func (c *JobStatusCommand) outputPeriodicInfo(client *api.Client, job *api.Job) error {
@@ -374,6 +470,7 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 
 	if blockedEval && latestFailedPlacement != nil {
 		c.outputFailedPlacements(latestFailedPlacement)
+		c.outputUnplaceableReasons(latestFailedPlacement)
 	}
 
 	c.outputReschedulingEvals(client, job, jobAllocs, c.length)
@@ -510,10 +607,17 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 		summaries := make([]string, len(summary.Summary)+1)
 		summaries[0] = "Task Group|Queued|Starting|Running|Failed|Complete|Lost"
 		taskGroups := make([]string, 0, len(summary.Summary))
-		for taskGroup := range summary.Summary {
+		showUnknown := false
+		for taskGroup, tgs := range summary.Summary {
 			taskGroups = append(taskGroups, taskGroup)
+			showUnknown = showUnknown || tgs.Unknown != 0
 		}
 		sort.Strings(taskGroups)
+
+		// Only show the unknown allocations of disconnected nodes if any
+		if showUnknown {
+			summaries[0] += "|Unknown"
+		}
 		for idx, taskGroup := range taskGroups {
 			tgs := summary.Summary[taskGroup]
 			summaries[idx+1] = fmt.Sprintf("%s|%d|%d|%d|%d|%d|%d",
@@ -521,6 +625,9 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 				tgs.Running, tgs.Failed,
 				tgs.Complete, tgs.Lost,
 			)
+			if showUnknown {
+				summaries[idx+1] += fmt.Sprintf("|%d", tgs.Unknown)
+			}
 		}
 		c.Ui.Output(formatList(summaries))
 	}
@@ -636,6 +743,77 @@ func (c *JobStatusCommand) outputFailedPlacements(failedEval *api.Evaluation) {
 	}
 }
 
+// outputUnplaceableReasons prints a breakdown of why the failed task groups
+// couldn't be placed, aggregating the exhausted dimensions by datacenter and
+// node class along with the nodes filtered out.
+func (c *JobStatusCommand) outputUnplaceableReasons(failedEval *api.Evaluation) {
+	if failedEval == nil || len(failedEval.FailedTGAllocs) == 0 {
+		return
+	}
+
+	reasons := formatUnplaceableReasons(failedEval.FailedTGAllocs)
+	if len(reasons) == 1 {
+		return
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Unplaceable Because[reset]"))
+	c.Ui.Output(formatList(reasons))
+}
+
+// formatUnplaceableReasons returns the rows of the unplaceable reasons of the
+// failed task groups, with the reasons affecting the most nodes first.
+func formatUnplaceableReasons(failedTGAllocs map[string]*api.AllocationMetric) []string {
+	type reason struct {
+		desc  string
+		dc    string
+		class string
+		nodes int
+	}
+
+	out := []string{"Task Group|Reason|Datacenter|Node Class|Nodes"}
+	for _, tg := range sortedTaskGroupFromMetrics(failedTGAllocs) {
+		metrics := failedTGAllocs[tg]
+
+		var reasons []reason
+		for _, e := range metrics.ExhaustedDimensions {
+			class := e.NodeClass
+			if class == "" {
+				class = "<none>"
+			}
+			reasons = append(reasons, reason{
+				desc:  fmt.Sprintf("Dimension %q exhausted", e.Dimension),
+				dc:    e.Datacenter,
+				class: class,
+				nodes: e.Nodes,
+			})
+		}
+		for class, num := range metrics.ClassFiltered {
+			reasons = append(reasons, reason{"Class filtered", "*", class, num})
+		}
+		for cs, num := range metrics.ConstraintFiltered {
+			reasons = append(reasons, reason{fmt.Sprintf("Constraint %q filtered", cs), "*", "*", num})
+		}
+
+		sort.Slice(reasons, func(i, j int) bool {
+			if reasons[i].nodes != reasons[j].nodes {
+				return reasons[i].nodes > reasons[j].nodes
+			}
+			if reasons[i].desc != reasons[j].desc {
+				return reasons[i].desc < reasons[j].desc
+			}
+			if reasons[i].dc != reasons[j].dc {
+				return reasons[i].dc < reasons[j].dc
+			}
+			return reasons[i].class < reasons[j].class
+		})
+
+		for _, r := range reasons {
+			out = append(out, fmt.Sprintf("%s|%s|%s|%s|%d", tg, r.desc, r.dc, r.class, r.nodes))
+		}
+	}
+	return out
+}
+
 // list general information about a list of jobs
 func createStatusListOutput(jobs []*api.JobListStub) string {
 	out := make([]string, len(jobs)+1)
@@ -69,6 +69,7 @@ func TestJobStatusCommand_Run(t *testing.T) {
 	}
 
 	job2 := testJob("job2_sfx")
+	job2.Tags = []string{"owner:web"}
 	resp2, _, err := client.Jobs().Register(job2, nil)
 	if err != nil {
 		t.Fatalf("err: %s", err)
@@ -87,6 +88,16 @@ func TestJobStatusCommand_Run(t *testing.T) {
 	}
 	ui.OutputWriter.Reset()
 
+	// Query the tagged jobs
+	if code := cmd.Run([]string{"-address=" + url, "-tag", "owner:web"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	out = ui.OutputWriter.String()
+	if strings.Contains(out, "job1_sfx") || !strings.Contains(out, "job2_sfx") {
+		t.Fatalf("expected only job2_sfx, got: %s", out)
+	}
+	ui.OutputWriter.Reset()
+
 	// Query a single job
 	if code := cmd.Run([]string{"-address=" + url, "job2_sfx"}); code != 0 {
 		t.Fatalf("expected exit 0, got: %d", code)
@@ -95,6 +106,9 @@ func TestJobStatusCommand_Run(t *testing.T) {
 	if strings.Contains(out, "job1_sfx") || !strings.Contains(out, "job2_sfx") {
 		t.Fatalf("expected only job2_sfx, got: %s", out)
 	}
+	if !strings.Contains(out, "owner:web") {
+		t.Fatalf("should dump tags")
+	}
 	if !strings.Contains(out, "Allocations") {
 		t.Fatalf("should dump allocations")
 	}
@@ -221,6 +235,58 @@ func TestJobStatusCommand_Fails(t *testing.T) {
 	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying jobs") {
 		t.Fatalf("expected failed query error, got: %s", out)
 	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on conflicting output flags
+	if code := cmd.Run([]string{"-address=nope", "-output=yaml", "-json"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-json can not be combined") {
+		t.Fatalf("expected output flag error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on tags with a job
+	if code := cmd.Run([]string{"-address=nope", "-tag", "owner:web", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-tag flag can only be used when listing jobs") {
+		t.Fatalf("expected tag flag error, got: %s", out)
+	}
+}
+
+func TestJobStatusCommand_Output(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	job := testJob("job1_sfx")
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &JobStatusCommand{Meta: Meta{Ui: ui}}
+
+	// The job list is output as YAML
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-output=yaml"}), ui.ErrorWriter.String())
+	out := ui.OutputWriter.String()
+	require.Contains(out, "ID: job1_sfx")
+	require.Contains(out, "JobSummary:")
+	ui.OutputWriter.Reset()
+
+	// A single job includes its summary and allocations
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-output=json", "job1_sfx"}), ui.ErrorWriter.String())
+	out = ui.OutputWriter.String()
+	require.Contains(out, `"Job": {`)
+	require.Contains(out, `"Summary": {`)
+	require.Contains(out, `"Allocations": `)
+	require.Contains(out, `"LatestDeployment": `)
+	ui.OutputWriter.Reset()
+
+	// Templates are applied to the single job output
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-t", "{{.Job.ID}}", "job1_sfx"}), ui.ErrorWriter.String())
+	require.Equal("job1_sfx", strings.TrimSpace(ui.OutputWriter.String()))
 }
 
 func TestJobStatusCommand_AutocompleteArgs(t *testing.T) {
@@ -372,3 +438,28 @@ func waitForSuccess(ui cli.Ui, client *api.Client, length int, t *testing.T, eva
 	monErr := mon.monitor(evalId, false)
 	return monErr
 }
+
+func TestJobStatusCommand_UnplaceableReasons(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	out := formatUnplaceableReasons(map[string]*api.AllocationMetric{
+		"web": {
+			ExhaustedDimensions: []*api.ExhaustedDimension{
+				{Datacenter: "dc2", Dimension: "cpu", Nodes: 1},
+				{Datacenter: "dc1", NodeClass: "large", Dimension: "memory", Nodes: 5},
+			},
+			ConstraintFiltered: map[string]int{"${attr.kernel.name} = linux": 2},
+		},
+		"cache": {
+			ClassFiltered: map[string]int{"gpu": 3},
+		},
+	})
+	require.Equal([]string{
+		"Task Group|Reason|Datacenter|Node Class|Nodes",
+		"cache|Class filtered|*|gpu|3",
+		`web|Dimension "memory" exhausted|dc1|large|5`,
+		`web|Constraint "${attr.kernel.name} = linux" filtered|*|*|2`,
+		`web|Dimension "cpu" exhausted|dc2|<none>|1`,
+	}, out)
+}
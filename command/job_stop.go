@@ -3,7 +3,9 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
 	"github.com/posener/complete"
 )
@@ -29,6 +31,16 @@ General Options:
 
 Stop Options:
 
+  -deregister-first
+    Deregister the services of the running allocations and wait for the
+    duration given with -wait before killing their tasks, giving load
+    balancers time to drain connections. The wait overrides the
+    shutdown_delay of the tasks if it is longer. Requires -wait.
+
+  -wait=<duration>
+    The duration to wait between deregistering the services of the
+    allocations and killing their tasks when -deregister-first is set.
+
   -detach
     Return immediately instead of entering monitor mode. After the
     deregister command is submitted, a new evaluation ID is printed to the
@@ -55,32 +67,24 @@ func (c *JobStopCommand) Synopsis() string {
 func (c *JobStopCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-detach":  complete.PredictNothing,
-			"-purge":   complete.PredictNothing,
-			"-yes":     complete.PredictNothing,
-			"-verbose": complete.PredictNothing,
+			"-deregister-first": complete.PredictNothing,
+			"-wait":             complete.PredictAnything,
+			"-detach":           complete.PredictNothing,
+			"-purge":            complete.PredictNothing,
+			"-yes":              complete.PredictNothing,
+			"-verbose":          complete.PredictNothing,
 		})
 }
 
 func (c *JobStopCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Jobs]
-	})
+	return c.Meta.PredictSearch(contexts.Jobs)
 }
 
 func (c *JobStopCommand) Name() string { return "job stop" }
 
 func (c *JobStopCommand) Run(args []string) int {
-	var detach, purge, verbose, autoYes bool
+	var detach, purge, verbose, autoYes, deregisterFirst bool
+	var wait time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -88,11 +92,25 @@ func (c *JobStopCommand) Run(args []string) int {
 	flags.BoolVar(&verbose, "verbose", false, "")
 	flags.BoolVar(&autoYes, "yes", false, "")
 	flags.BoolVar(&purge, "purge", false, "")
+	flags.BoolVar(&deregisterFirst, "deregister-first", false, "")
+	flags.DurationVar(&wait, "wait", 0, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	// The wait only applies when deregistering services first
+	if deregisterFirst && wait <= 0 {
+		c.Ui.Error("-deregister-first requires a positive -wait duration")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if !deregisterFirst && wait != 0 {
+		c.Ui.Error("-wait requires -deregister-first")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Truncate the id unless full length is requested
 	length := shortId
 	if verbose {
@@ -160,7 +178,11 @@ func (c *JobStopCommand) Run(args []string) int {
 	}
 
 	// Invoke the stop
-	evalID, _, err := client.Jobs().Deregister(*job.ID, purge, nil)
+	opts := &api.DeregisterOptions{
+		Purge:         purge,
+		ShutdownDelay: wait,
+	}
+	evalID, _, err := client.Jobs().DeregisterOpts(*job.ID, opts, nil)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error deregistering job: %s", err))
 		return 1
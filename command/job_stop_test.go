@@ -32,6 +32,24 @@ func TestStopCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 
+	// Fails on -deregister-first without a wait, and on a wait without
+	// -deregister-first
+	if code := cmd.Run([]string{"-address=" + url, "-deregister-first", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-deregister-first requires a positive -wait duration") {
+		t.Fatalf("expected wait error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=" + url, "-wait=10s", "example"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "-wait requires -deregister-first") {
+		t.Fatalf("expected deregister-first error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
 	// Fails on nonexistent job ID
 	if code := cmd.Run([]string{"-address=" + url, "nope"}); code != 1 {
 		t.Fatalf("expect exit 1, got: %d", code)
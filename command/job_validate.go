@@ -3,10 +3,12 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/posener/complete"
 )
@@ -27,6 +29,60 @@ Alias: nomad validate
   If the supplied path is "-", the jobfile is read from stdin. Otherwise
   it is read from the file at the supplied path or downloaded and
   read from URL specified.
+
+  For periodic jobs, the next launch times are printed in the time zone of the
+  job once it is valid.
+
+  Validate will return one of the following exit codes:
+    * 0: The job is valid.
+    * 1: The job is invalid, a lint rule configured as an error reported a
+         finding or an error occurred.
+    * 2: The job is valid but the admission checks or the lint rules reported
+         warnings.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Validate Options:
+
+  -admission
+    Also run the checks the servers perform when the job is registered and the
+    checks that depend on the state of the cluster: whether any node can
+    satisfy each task group's constraints and drivers, whether the job's
+    namespace exists and whether the Vault token grants the job's Vault
+    policies. Requires a connection to a Nomad agent and, when ACLs are
+    enabled, a token with the submit-job capability.
+
+  -lint
+    Also check the job against the built-in lint rules, which report
+    specifications that are valid but are likely to cause problems in
+    production. The rules are:
+
+` + formatLintRules() + `
+
+  -lint-disable=<rule>
+    Disables a lint rule. Can be specified multiple times or as a comma
+    separated list. Implies -lint.
+
+  -lint-error=<rule>
+    Reports the findings of a lint rule as errors instead of warnings, making
+    the command exit with 1. Can be specified multiple times or as a comma
+    separated list. Implies -lint.
+
+  -json
+    Outputs the lint findings as a JSON list. The other messages are written
+    to stderr so that the output can be parsed by CI systems.
+
+  -var 'name=value'
+    Sets the value of a variable declared by the job file. Can be specified
+    multiple times. Lists and maps are given in HCL syntax.
+
+  -var-file=<path>
+    Sets the variables assigned in the given HCL file of name = value lines.
+    Can be specified multiple times. The -var flags take precedence over the
+    variable files, which take precedence over the NOMAD_VAR_<name>
+    environment variables.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -36,7 +92,16 @@ func (c *JobValidateCommand) Synopsis() string {
 }
 
 func (c *JobValidateCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-admission":    complete.PredictNothing,
+			"-lint":         complete.PredictNothing,
+			"-lint-disable": complete.PredictAnything,
+			"-lint-error":   complete.PredictAnything,
+			"-json":         complete.PredictNothing,
+			"-var":          complete.PredictAnything,
+			"-var-file":     complete.PredictFiles("*"),
+		})
 }
 
 func (c *JobValidateCommand) AutocompleteArgs() complete.Predictor {
@@ -46,12 +111,37 @@ func (c *JobValidateCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobValidateCommand) Name() string { return "job validate" }
 
 func (c *JobValidateCommand) Run(args []string) int {
-	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	var admission, lint, jsonOutput bool
+	var lintDisable, lintError flaghelper.StringFlag
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&admission, "admission", false, "")
+	flags.BoolVar(&lint, "lint", false, "")
+	flags.Var(&lintDisable, "lint-disable", "")
+	flags.Var(&lintError, "lint-error", "")
+	flags.BoolVar(&jsonOutput, "json", false, "")
+	c.JobGetter.setFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	lintConf := &lintConfig{
+		Disabled: splitLintRules(lintDisable),
+		Errors:   splitLintRules(lintError),
+	}
+	lint = lint || len(lintConf.Disabled) > 0 || len(lintConf.Errors) > 0
+	if err := lintConf.validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid lint configuration: %s", err))
+		return 1
+	}
+
+	// Messages go to stderr when the output is the JSON findings
+	output := c.Ui.Output
+	if jsonOutput {
+		output = c.Ui.Warn
+	}
+
 	// Check that we got exactly one node
 	args = flags.Args()
 	if len(args) != 1 {
@@ -67,6 +157,15 @@ func (c *JobValidateCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Lint the job as written, before validation canonicalizes it
+	findings := []*LintFinding{}
+	if lint {
+		if findings, err = lintJob(job, lintConf); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error linting job: %s", err))
+			return 1
+		}
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -79,9 +178,11 @@ func (c *JobValidateCommand) Run(args []string) int {
 		client.SetRegion(*r)
 	}
 
-	// Check that the job is valid
-	jr, _, err := client.Jobs().Validate(job, nil)
-	if err != nil {
+	// Check that the job is valid. The admission checks can only be run by
+	// the servers, so only fall back to local validation without them.
+	opts := &api.ValidateOptions{Admission: admission}
+	jr, _, err := client.Jobs().ValidateOpts(job, opts, nil)
+	if err != nil && !admission {
 		jr, err = c.validateLocal(job)
 	}
 	if err != nil {
@@ -90,7 +191,7 @@ func (c *JobValidateCommand) Run(args []string) int {
 	}
 
 	if jr != nil && !jr.DriverConfigValidated {
-		c.Ui.Output(
+		output(
 			c.Colorize().Color("[bold][yellow]Driver configuration not validated since connection to Nomad agent couldn't be established.[reset]\n"))
 	}
 
@@ -103,16 +204,121 @@ func (c *JobValidateCommand) Run(args []string) int {
 
 	// Print any warnings if there are any
 	if jr.Warnings != "" {
-		c.Ui.Output(
+		output(
 			c.Colorize().Color(fmt.Sprintf("[bold][yellow]Job Warnings:\n%s[reset]\n", jr.Warnings)))
 	}
 
+	// Preview the next launches of periodic jobs
+	if job.IsPeriodic() && !job.IsParameterized() {
+		if out, err := formatNextPeriodicLaunches(job.Periodic); err != nil {
+			c.Ui.Warn(fmt.Sprintf("Error determining the next periodic launches: %s", err))
+		} else if out != "" {
+			output(c.Colorize().Color(out))
+		}
+	}
+
+	// Print any admission warnings
+	warned := false
+	if len(jr.AdmissionWarnings) > 0 {
+		output(c.Colorize().Color(
+			fmt.Sprintf("[bold][yellow]Job Admission Warnings:\n%s[reset]\n", formatAdmissionWarnings(jr.AdmissionWarnings))))
+		warned = true
+	}
+
+	// Print the lint findings
+	failed := false
+	for _, f := range findings {
+		if f.Severity == lintSeverityError {
+			failed = true
+		} else {
+			warned = true
+		}
+	}
+	if jsonOutput {
+		out, err := Format(true, "", findings)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+	} else if len(findings) > 0 {
+		color := "yellow"
+		if failed {
+			color = "red"
+		}
+		output(c.Colorize().Color(
+			fmt.Sprintf("[bold][%s]Job Lint Findings:\n%s[reset]\n", color, formatLintFindings(findings))))
+	}
+
+	switch {
+	case failed:
+		c.Ui.Error(
+			c.Colorize().Color("[bold][red]Job validation failed on lint errors[reset]"))
+		return 1
+	case warned:
+		output(
+			c.Colorize().Color("[bold][yellow]Job validation successful with warnings[reset]"))
+		return 2
+	}
+
 	// Done!
-	c.Ui.Output(
+	output(
 		c.Colorize().Color("[bold][green]Job validation successful[reset]"))
 	return 0
 }
 
+// splitLintRules returns the rule names of the lint flags, which may be
+// comma separated lists.
+func splitLintRules(values []string) []string {
+	var rules []string
+	for _, v := range values {
+		for _, rule := range strings.Split(v, ",") {
+			if rule = strings.TrimSpace(rule); rule != "" {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// formatAdmissionWarnings returns the admission warnings as a list, prefixed
+// with the task group and task they apply to.
+func formatAdmissionWarnings(warnings []*api.JobAdmissionWarning) string {
+	lines := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		var scope string
+		switch {
+		case w.Task != "":
+			scope = fmt.Sprintf("task %q in group %q: ", w.Task, w.TaskGroup)
+		case w.TaskGroup != "":
+			scope = fmt.Sprintf("group %q: ", w.TaskGroup)
+		}
+		lines = append(lines, fmt.Sprintf("* %s: %s%s", w.Type, scope, w.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatNextPeriodicLaunches returns the next launch times of the periodic
+// job in its time zone, or nothing if it will never be launched.
+func formatNextPeriodicLaunches(periodic *api.PeriodicConfig) (string, error) {
+	loc, err := periodic.GetLocation()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().In(loc)
+	launches, err := periodicLaunches(periodic, now, periodicLaunchPreview)
+	if err != nil || len(launches) == 0 {
+		return "", err
+	}
+
+	out := fmt.Sprintf("[bold]Next Periodic Launches (%s):[reset]\n", loc)
+	for _, l := range launches {
+		out += fmt.Sprintf("  %s (%s from now)\n", formatTime(l), formatTimeDifference(now, l, time.Second))
+	}
+	return out, nil
+}
+
 // validateLocal validates without talking to a Nomad agent
 func (c *JobValidateCommand) validateLocal(aj *api.Job) (*api.JobValidateResponse, error) {
 	var out api.JobValidateResponse
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -57,6 +58,62 @@ job "job1" {
 	}
 }
 
+func TestValidateCommand_Admission(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobValidateCommand{Meta: Meta{Ui: ui}}
+
+	fh, err := ioutil.TempFile("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+job "job1" {
+	type = "service"
+	datacenters = [ "dc1" ]
+	group "group1" {
+		count = 1
+		task "task1" {
+			driver = "exec"
+			resources = {
+				cpu = 1000
+				memory = 512
+			}
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Without admission checks the job is valid
+	if code := cmd.Run([]string{"-address=" + url, fh.Name()}); code != 0 {
+		t.Fatalf("expect exit 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	ui.OutputWriter.Reset()
+
+	// There are no clients, so the task group can't be placed
+	if code := cmd.Run([]string{"-address=" + url, "-admission", fh.Name()}); code != 2 {
+		t.Fatalf("expect exit 2, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "Job Admission Warnings") || !strings.Contains(out, `constraint: group "group1": no nodes are available`) {
+		t.Fatalf("expected admission warnings, got: %s", out)
+	}
+
+	// Admission checks require an agent
+	if code := cmd.Run([]string{"-address=http://127.0.0.1:1", "-admission", fh.Name()}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error validating job") {
+		t.Fatalf("expected validation error, got: %s", out)
+	}
+}
+
 func TestValidateCommand_Fails(t *testing.T) {
 	t.Parallel()
 	ui := new(cli.MockUi)
@@ -177,3 +234,87 @@ func TestValidateCommand_From_URL(t *testing.T) {
 		t.Fatalf("expected error getting jobfile, got: %s", out)
 	}
 }
+
+func TestValidateCommand_Lint(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobValidateCommand{Meta: Meta{Ui: ui}}
+
+	fh, err := ioutil.TempFile("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+job "job1" {
+	type = "batch"
+	datacenters = [ "dc1" ]
+	group "group1" {
+		count = 1
+		task "task1" {
+			driver = "docker"
+			config {
+				image = "redis"
+			}
+			resources = {
+				cpu = 1000
+				memory = 512
+			}
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Validate locally, without an agent
+	address := "-address=http://127.0.0.1:1"
+
+	// Findings are warnings by default
+	if code := cmd.Run([]string{address, "-lint", fh.Name()}); code != 2 {
+		t.Fatalf("expect exit 2, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	if !strings.Contains(out, "Job Lint Findings") || !strings.Contains(out, `image-latest (warning): task "task1" in group "group1"`) {
+		t.Fatalf("expected lint findings, got: %s", out)
+	}
+	ui.OutputWriter.Reset()
+
+	// Disabled rules don't report findings
+	if code := cmd.Run([]string{address, "-lint-disable=image-latest", fh.Name()}); code != 0 {
+		t.Fatalf("expect exit 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	ui.OutputWriter.Reset()
+
+	// Rules configured as errors fail the validation
+	if code := cmd.Run([]string{address, "-lint-error=image-latest", fh.Name()}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Job validation failed on lint errors") {
+		t.Fatalf("expected lint error, got: %s", out)
+	}
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// The JSON output only holds the findings
+	if code := cmd.Run([]string{address, "-lint", "-json", fh.Name()}); code != 2 {
+		t.Fatalf("expect exit 2, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+	var findings []*LintFinding
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &findings); err != nil {
+		t.Fatalf("err: %s: %s", err, ui.OutputWriter.String())
+	}
+	if len(findings) != 1 || findings[0].Rule != "image-latest" || findings[0].Severity != "warning" {
+		t.Fatalf("bad findings: %#v", findings)
+	}
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// Unknown rules are rejected
+	if code := cmd.Run([]string{address, "-lint-disable=nope", fh.Name()}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, `unknown lint rule "nope"`) {
+		t.Fatalf("expected unknown rule error, got: %s", out)
+	}
+}
@@ -0,0 +1,257 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/posener/complete"
+)
+
+const (
+	// defaultOIDCCallbackAddr is the address the login command listens on
+	// for the OIDC provider to redirect the user back to.
+	defaultOIDCCallbackAddr = "localhost:4649"
+
+	// oidcCallbackPath is the path of the redirect URI of the login command.
+	oidcCallbackPath = "/oidc/callback"
+
+	// oidcLoginTimeout is how long the user has to log in with the provider.
+	oidcLoginTimeout = 5 * time.Minute
+)
+
+type LoginCommand struct {
+	Meta
+}
+
+func (c *LoginCommand) Help() string {
+	helpText := `
+Usage: nomad login [options]
+
+  Login is used to log in with an ACL auth method and receive an ACL token.
+  The token is valid for the token TTL of the auth method and has the policies
+  the user's identity maps to.
+
+  For OIDC auth methods, the command prints the URL of the OIDC provider to
+  log in at and waits for the provider to redirect back to a local callback
+  address. The redirect URI, "http://<callback-addr>/oidc/callback", must be
+  allowed by the auth method.
+
+  For JWT auth methods, the signed JWT to log in with is given with
+  -login-token, such as the identity token of a CI pipeline.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Login Options:
+
+  -method=<name>
+    The name of the auth method to log in with. If not given, the default auth
+    method is used.
+
+  -oidc-callback-addr=<addr>
+    The address to listen on for the OIDC provider's redirect. Defaults to
+    "localhost:4649".
+
+  -login-token=<jwt>
+    The signed JWT to log in with a JWT auth method. Prefix the value with "@"
+    to read it from a file, or use "@-" to read it from stdin.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Log in with an ACL auth method"
+}
+
+func (c *LoginCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-method":             complete.PredictAnything,
+			"-oidc-callback-addr": complete.PredictAnything,
+			"-login-token":        complete.PredictAnything,
+		})
+}
+
+func (c *LoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *LoginCommand) Name() string { return "login" }
+
+func (c *LoginCommand) Run(args []string) int {
+	var method, callbackAddr, loginToken string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&method, "method", "", "")
+	flags.StringVar(&callbackAddr, "oidc-callback-addr", defaultOIDCCallbackAddr, "")
+	flags.StringVar(&loginToken, "login-token", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if loginToken != "" {
+		return c.loginJWT(client, method, loginToken)
+	}
+
+	// Listen for the callback before starting the login so that the
+	// provider can't redirect the user to a closed port
+	ln, err := net.Listen("tcp", callbackAddr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listening for the OIDC callback: %s", err))
+		return 1
+	}
+	defer ln.Close()
+
+	redirectURI := "http://" + callbackAddr + oidcCallbackPath
+	nonce := uuid.Generate()
+	state := uuid.Generate()
+
+	resp, _, err := client.ACLOIDC().GetAuthURL(&api.ACLOIDCAuthURLRequest{
+		AuthMethodName: method,
+		RedirectURI:    redirectURI,
+		ClientNonce:    nonce,
+		State:          state,
+	}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error starting OIDC login: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Complete the login by opening the following URL in a browser:\n\n    %s\n", resp.AuthURL))
+
+	code, err := c.waitForCallback(ln, state)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error completing OIDC login: %s", err))
+		return 1
+	}
+
+	token, _, err := client.ACLOIDC().CompleteAuth(&api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method,
+		RedirectURI:    redirectURI,
+		ClientNonce:    nonce,
+		Code:           code,
+	}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error completing OIDC login: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Successfully logged in via OIDC")
+	c.Ui.Output(formatKVACLToken(token))
+	c.Ui.Output("\nSet the NOMAD_TOKEN environment variable to the Secret ID to use the token.")
+	return 0
+}
+
+// loginJWT logs in with a JWT auth method by exchanging the signed JWT for an
+// ACL token.
+func (c *LoginCommand) loginJWT(client *api.Client, method, loginToken string) int {
+	// Read the JWT from a file or stdin if requested
+	if strings.HasPrefix(loginToken, "@") {
+		var raw []byte
+		var err error
+		if path := loginToken[1:]; path == "-" {
+			raw, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			raw, err = ioutil.ReadFile(path)
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read login token: %v", err))
+			return 1
+		}
+		loginToken = strings.TrimSpace(string(raw))
+	}
+
+	token, _, err := client.ACLAuthMethods().Login(&api.ACLLoginRequest{
+		AuthMethodName: method,
+		LoginToken:     loginToken,
+	}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("Successfully logged in via JWT")
+	c.Ui.Output(formatKVACLToken(token))
+	c.Ui.Output("\nSet the NOMAD_TOKEN environment variable to the Secret ID to use the token.")
+	return 0
+}
+
+// waitForCallback serves the OIDC callback on the listener and returns the
+// authorization code the provider redirects the user back with.
+func (c *LoginCommand) waitForCallback(ln net.Listener, state string) (string, error) {
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			res.err = fmt.Errorf("callback state does not match the login")
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("provider returned error %q: %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			res.err = fmt.Errorf("callback has no authorization code")
+		default:
+			res.code = q.Get("code")
+		}
+
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Signed in to Nomad. You can close this window and return to the CLI.")
+		}
+
+		select {
+		case resultCh <- res:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Shutdown(context.Background())
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	select {
+	case res := <-resultCh:
+		return res.code, res.err
+	case <-signalCh:
+		return "", fmt.Errorf("interrupted")
+	case <-time.After(oidcLoginTimeout):
+		return "", fmt.Errorf("timed out waiting for the OIDC provider")
+	}
+}
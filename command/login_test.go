@@ -0,0 +1,56 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &LoginCommand{}
+}
+
+func TestLoginCommand_NoDefaultMethod(t *testing.T) {
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Arguments are rejected
+	code := cmd.Run([]string{"-address=" + url, "foo"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "takes no arguments")
+	ui.ErrorWriter.Reset()
+
+	// Without an auth method the login can't start
+	code = cmd.Run([]string{"-address=" + url, "-oidc-callback-addr=127.0.0.1:0"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "no default auth method")
+}
+
+func TestLoginCommand_JWTNoDefaultMethod(t *testing.T) {
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Logging in with a JWT doesn't listen for a callback
+	code := cmd.Run([]string{"-address=" + url, "-login-token=header.payload.signature"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "no default auth method")
+}
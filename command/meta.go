@@ -55,6 +55,10 @@ type Meta struct {
 	clientCert string
 	clientKey  string
 	insecure   bool
+
+	// relayThroughServers sends the requests targeting client nodes through
+	// the servers instead of dialing the nodes directly
+	relayThroughServers bool
 }
 
 // FlagSet returns a FlagSet with the common flags that every
@@ -78,6 +82,7 @@ func (m *Meta) FlagSet(n string, fs FlagSetFlags) *flag.FlagSet {
 		f.BoolVar(&m.insecure, "insecure", false, "")
 		f.BoolVar(&m.insecure, "tls-skip-verify", false, "")
 		f.StringVar(&m.token, "token", "", "")
+		f.BoolVar(&m.relayThroughServers, "relay-through-servers", false, "")
 
 	}
 
@@ -106,7 +111,7 @@ func (m *Meta) AutocompleteFlags(fs FlagSetFlags) complete.Flags {
 	return complete.Flags{
 		"-address":         complete.PredictAnything,
 		"-region":          complete.PredictAnything,
-		"-namespace":       NamespacePredictor(m.Client, nil),
+		"-namespace":       NamespacePredictor(m.autocompleteClient, nil),
 		"-no-color":        complete.PredictNothing,
 		"-ca-cert":         complete.PredictFiles("*"),
 		"-ca-path":         complete.PredictDirs("*"),
@@ -115,11 +120,14 @@ func (m *Meta) AutocompleteFlags(fs FlagSetFlags) complete.Flags {
 		"-insecure":        complete.PredictNothing,
 		"-tls-skip-verify": complete.PredictNothing,
 		"-token":           complete.PredictAnything,
+
+		"-relay-through-servers": complete.PredictNothing,
 	}
 }
 
-// ApiClientFactory is the signature of a API client factory
-type ApiClientFactory func() (*api.Client, error)
+// ApiClientFactory is the signature of the API client factory used by
+// predictors, which configures the client from the arguments completed so far.
+type ApiClientFactory func(a complete.Args) (*api.Client, error)
 
 // Client is used to initialize and return a new API client using
 // the default command line arguments and env vars.
@@ -151,6 +159,10 @@ func (m *Meta) Client() (*api.Client, error) {
 		config.SecretID = m.token
 	}
 
+	if m.relayThroughServers {
+		config.RelayThroughServers = true
+	}
+
 	return api.NewClient(config)
 }
 
@@ -212,6 +224,12 @@ func generalOptionsUsage() string {
   -token
     The SecretID of an ACL token to use to authenticate API requests with.
     Overrides the NOMAD_TOKEN environment variable if set.
+
+  -relay-through-servers
+    Send the requests targeting client nodes, such as reading the files and
+    logs of allocations, through the Nomad servers instead of connecting to
+    the nodes directly. Relaying will also be used if
+    NOMAD_RELAY_THROUGH_SERVERS is set.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -31,6 +31,7 @@ func TestMeta_FlagSet(t *testing.T) {
 				"insecure",
 				"tls-skip-verify",
 				"token",
+				"relay-through-servers",
 			},
 		},
 	}
@@ -335,7 +335,7 @@ func (m *monitor) monitor(evalID string, allowPrefix bool) int {
 func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix string) string {
 	// Print a helpful message if we have an eligibility problem
 	var out string
-	if metrics.NodesEvaluated == 0 {
+	if metrics.NodesEvaluated == 0 && metrics.ConcurrencyGroupHeldBy == "" {
 		out += fmt.Sprintf("%s* No nodes were eligible for evaluation\n", prefix)
 	}
 
@@ -371,6 +371,11 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 		out += fmt.Sprintf("%s* Quota limit hit %q\n", prefix, dim)
 	}
 
+	// Print concurrency group info
+	if job := metrics.ConcurrencyGroupHeldBy; job != "" {
+		out += fmt.Sprintf("%s* Queued behind job %q running in the concurrency group\n", prefix, job)
+	}
+
 	// Print scores
 	if scores {
 		if len(metrics.ScoreMetaData) > 0 {
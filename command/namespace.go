@@ -53,7 +53,7 @@ func (f *NamespaceCommand) Run(args []string) int {
 // specific namespaces
 func NamespacePredictor(factory ApiClientFactory, filter map[string]struct{}) complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := factory()
+		client, err := factory(a)
 		if err != nil {
 			return nil
 		}
@@ -39,12 +39,12 @@ func (c *NamespaceApplyCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-description": complete.PredictAnything,
-			"-quota":       QuotaPredictor(c.Meta.Client),
+			"-quota":       QuotaPredictor(c.Meta.autocompleteClient),
 		})
 }
 
 func (c *NamespaceApplyCommand) AutocompleteArgs() complete.Predictor {
-	return NamespacePredictor(c.Meta.Client, nil)
+	return NamespacePredictor(c.Meta.autocompleteClient, nil)
 }
 
 func (c *NamespaceApplyCommand) Synopsis() string {
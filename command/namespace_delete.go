@@ -30,7 +30,7 @@ func (c *NamespaceDeleteCommand) AutocompleteFlags() complete.Flags {
 
 func (c *NamespaceDeleteCommand) AutocompleteArgs() complete.Predictor {
 	filter := map[string]struct{}{"default": {}}
-	return NamespacePredictor(c.Meta.Client, filter)
+	return NamespacePredictor(c.Meta.autocompleteClient, filter)
 }
 
 func (c *NamespaceDeleteCommand) Synopsis() string {
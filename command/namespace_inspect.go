@@ -23,6 +23,10 @@ General Options:
 
 Inspect Options:
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display the namespaces using a Go template.
 `
@@ -33,12 +37,13 @@ Inspect Options:
 func (c *NamespaceInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-t": complete.PredictAnything,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
 		})
 }
 
 func (c *NamespaceInspectCommand) AutocompleteArgs() complete.Predictor {
-	return NamespacePredictor(c.Meta.Client, nil)
+	return NamespacePredictor(c.Meta.autocompleteClient, nil)
 }
 
 func (c *NamespaceInspectCommand) Synopsis() string {
@@ -48,15 +53,20 @@ func (c *NamespaceInspectCommand) Synopsis() string {
 func (c *NamespaceInspectCommand) Name() string { return "namespace inspect" }
 
 func (c *NamespaceInspectCommand) Run(args []string) int {
-	var tmpl string
+	var format outputFormat
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got one arguments
 	args = flags.Args()
 	if l := len(args); l != 1 {
@@ -86,7 +96,12 @@ func (c *NamespaceInspectCommand) Run(args []string) int {
 		return 1
 	}
 
-	out, err := Format(len(tmpl) == 0, tmpl, ns)
+	// Output JSON unless another format was requested
+	if !format.enabled() {
+		format.json = true
+	}
+
+	out, err := format.Format(ns)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -30,7 +30,7 @@ func (c *NamespaceStatusCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *NamespaceStatusCommand) AutocompleteArgs() complete.Predictor {
-	return NamespacePredictor(c.Meta.Client, nil)
+	return NamespacePredictor(c.Meta.autocompleteClient, nil)
 }
 
 func (c *NamespaceStatusCommand) Synopsis() string {
@@ -98,18 +98,7 @@ func (c *NodeDrainCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *NodeDrainCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Nodes]
-	})
+	return c.Meta.PredictSearch(contexts.Nodes)
 }
 
 func (c *NodeDrainCommand) Name() string { return "node-drain" }
@@ -3,6 +3,7 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api/contexts"
 	"github.com/posener/complete"
@@ -22,6 +23,8 @@ Usage: nomad node eligibility [options] <node>
 
   It is required that either -enable or -disable is specified, but not both.
   The -self flag is useful to set the scheduling eligibility of the local node.
+  The -ttl flag marks the node as eligible again once the duration has
+  elapsed.
 
 General Options:
 
@@ -37,6 +40,10 @@ Node Eligibility Options:
 
   -self
     Set the eligibility of the local node.
+
+  -ttl <duration>
+    Mark the node as eligible again after the given duration. Only valid with
+    -disable.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -51,34 +58,26 @@ func (c *NodeEligibilityCommand) AutocompleteFlags() complete.Flags {
 			"-disable": complete.PredictNothing,
 			"-enable":  complete.PredictNothing,
 			"-self":    complete.PredictNothing,
+			"-ttl":     complete.PredictAnything,
 		})
 }
 
 func (c *NodeEligibilityCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Nodes]
-	})
+	return c.Meta.PredictSearch(contexts.Nodes)
 }
 
 func (c *NodeEligibilityCommand) Name() string { return "node-eligibility" }
 
 func (c *NodeEligibilityCommand) Run(args []string) int {
 	var enable, disable, self bool
+	var ttl time.Duration
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&enable, "enable", false, "Mark node as eligibile for scheduling")
 	flags.BoolVar(&disable, "disable", false, "Mark node as ineligibile for scheduling")
 	flags.BoolVar(&self, "self", false, "")
+	flags.DurationVar(&ttl, "ttl", 0, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -91,6 +90,17 @@ func (c *NodeEligibilityCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Check that the TTL is only set when marking the node as ineligible
+	if ttl < 0 {
+		c.Ui.Error("The '-ttl' flag must not be negative")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	} else if ttl > 0 && !disable {
+		c.Ui.Error("The '-ttl' flag can only be used with '-disable'")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Check that we got a node ID
 	args = flags.Args()
 	if l := len(args); self && l != 0 || !self && l != 1 {
@@ -149,13 +159,20 @@ func (c *NodeEligibilityCommand) Run(args []string) int {
 	}
 
 	// Toggle node eligibility
-	if _, err := client.Nodes().ToggleEligibility(node.ID, enable, nil); err != nil {
+	if ttl > 0 {
+		_, err = client.Nodes().MarkIneligible(node.ID, ttl, nil)
+	} else {
+		_, err = client.Nodes().ToggleEligibility(node.ID, enable, nil)
+	}
+	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error updating scheduling eligibility: %s", err))
 		return 1
 	}
 
 	if enable {
 		c.Ui.Output(fmt.Sprintf("Node %q scheduling eligibility set: eligible for scheduling", node.ID))
+	} else if ttl > 0 {
+		c.Ui.Output(fmt.Sprintf("Node %q scheduling eligibility set: ineligible for scheduling for %s", node.ID, ttl))
 	} else {
 		c.Ui.Output(fmt.Sprintf("Node %q scheduling eligibility set: ineligible for scheduling", node.ID))
 	}
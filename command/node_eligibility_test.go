@@ -70,6 +70,15 @@ func TestNodeEligibilityCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 
+	// Fails if a TTL is used with enable
+	if code := cmd.Run([]string{"-enable", "-ttl=1h", "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "can only be used with '-disable'") {
+		t.Fatalf("expected ttl error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
 	// Fail on identifier with too few characters
 	if code := cmd.Run([]string{"-address=" + url, "-enable", "1"}); code != 1 {
 		t.Fatalf("expected exit 1, got: %d", code)
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type NodeMetaCommand struct {
+	Meta
+}
+
+func (f *NodeMetaCommand) Help() string {
+	helpText := `
+Usage: nomad node meta <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the metadata of nodes.
+  Metadata set with these commands is persisted by the node across restarts and
+  can be used in constraints as soon as it is applied, without restarting the
+  agent.
+
+  Set metadata on the local node and unset one of its keys:
+
+      $ nomad node meta apply -unset=old_key rack=r1 zone=z2
+
+  Read the metadata of a node:
+
+      $ nomad node meta read -node-id <node-id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodeMetaCommand) Synopsis() string {
+	return "Interact with node metadata"
+}
+
+func (f *NodeMetaCommand) Name() string { return "node meta" }
+
+func (f *NodeMetaCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// lookupNodeMetaID resolves the node ID prefix to the node's full ID. An empty
+// prefix resolves to the empty ID, which targets the local agent's node.
+func lookupNodeMetaID(client *api.Client, nodeID string) (string, error) {
+	if nodeID == "" {
+		return "", nil
+	}
+
+	if len(nodeID) == 1 {
+		return "", fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	nodeID = sanitizeUUIDPrefix(nodeID)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		return "", fmt.Errorf("Error querying node: %s", err)
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("No node(s) with prefix or id %q found", nodeID)
+	}
+	if len(nodes) > 1 {
+		return "", fmt.Errorf("Prefix matched multiple nodes\n\n%s", formatNodeStubList(nodes, true))
+	}
+
+	return nodes[0].ID, nil
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/posener/complete"
+)
+
+type NodeMetaApplyCommand struct {
+	Meta
+}
+
+func (c *NodeMetaApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node meta apply [options] <key>=<value>...
+
+  Modify the metadata of a node at runtime. The metadata is persisted by the
+  node across restarts and the node is updated immediately, so the new metadata
+  can be used in constraints without restarting the agent.
+
+  Metadata set at runtime takes precedence over the metadata in the agent's
+  configuration. Unsetting a key removes it even if it is set in the agent's
+  configuration.
+
+  If -node-id is not given, the metadata of the local agent's node is modified.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Apply Options:
+
+  -node-id
+    Modify the metadata of the node with the given ID or ID prefix.
+
+  -unset
+    Comma separated list of metadata keys to unset.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaApplyCommand) Synopsis() string {
+	return "Modify the metadata of a node"
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": c.Meta.PredictSearch(contexts.Nodes),
+			"-unset":   complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *NodeMetaApplyCommand) Name() string { return "node meta apply" }
+
+func (c *NodeMetaApplyCommand) Run(args []string) int {
+	var nodeID, unset string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&unset, "unset", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	meta, err := parseNodeMetaArgs(flags.Args(), unset)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	nodeID, err = lookupNodeMetaID(client, nodeID)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	req := &api.NodeMetaApplyRequest{
+		NodeID: nodeID,
+		Meta:   meta,
+	}
+	if _, err := client.Nodes().ApplyMeta(req, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node metadata: %s", err))
+		return 1
+	}
+
+	return 0
+}
+
+// parseNodeMetaArgs parses the key=value arguments and the comma separated
+// keys to unset into the metadata to apply.
+func parseNodeMetaArgs(args []string, unset string) (map[string]*string, error) {
+	meta := make(map[string]*string, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid metadata %q: must be in the form key=value", arg)
+		}
+		meta[parts[0]] = helper.StringToPtr(parts[1])
+	}
+
+	for _, key := range strings.Split(unset, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if _, ok := meta[key]; ok {
+			return nil, fmt.Errorf("Metadata key %q can not be both set and unset", key)
+		}
+		meta[key] = nil
+	}
+
+	if len(meta) == 0 {
+		return nil, fmt.Errorf("At least one key to set or unset must be given")
+	}
+	return meta, nil
+}
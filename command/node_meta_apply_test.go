@@ -0,0 +1,121 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMetaApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodeMetaApplyCommand{}
+}
+
+func TestNodeMetaApplyCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without metadata
+	if code := cmd.Run(nil); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on malformed metadata
+	if code := cmd.Run([]string{"foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "must be in the form key=value") {
+		t.Fatalf("expected malformed metadata error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "-node-id=12345678", "foo=bar"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error querying node") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+}
+
+func TestNodeMetaApplyCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, fmt.Errorf("missing node")
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-address=" + url, "-node-id=" + nodeID[:8], "-unset=baz", "foo=bar"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+
+	// The node registration reflects the new metadata
+	testutil.WaitForResult(func() (bool, error) {
+		node, _, err := client.Nodes().Info(nodeID, nil)
+		if err != nil {
+			return false, err
+		}
+		if node.Meta["foo"] != "bar" {
+			return false, fmt.Errorf("metadata not applied: %v", node.Meta)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Reading the metadata of the local node returns it
+	ui = new(cli.MockUi)
+	readCmd := &NodeMetaReadCommand{Meta: Meta{Ui: ui}}
+	if code := readCmd.Run([]string{"-address=" + url}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	require.Regexp(`foo\s+= bar`, ui.OutputWriter.String())
+}
+
+func TestParseNodeMetaArgs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	meta, err := parseNodeMetaArgs([]string{"foo=bar", "empty=", "eq=a=b"}, "baz, qux")
+	require.NoError(err)
+	require.Equal(map[string]*string{
+		"foo":   helper.StringToPtr("bar"),
+		"empty": helper.StringToPtr(""),
+		"eq":    helper.StringToPtr("a=b"),
+		"baz":   nil,
+		"qux":   nil,
+	}, meta)
+
+	_, err = parseNodeMetaArgs([]string{"=bar"}, "")
+	require.Error(err)
+
+	_, err = parseNodeMetaArgs([]string{"foo=bar"}, "foo")
+	require.Error(err)
+	require.Contains(err.Error(), "both set and unset")
+}
@@ -0,0 +1,134 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type NodeMetaReadCommand struct {
+	Meta
+}
+
+func (c *NodeMetaReadCommand) Help() string {
+	helpText := `
+Usage: nomad node meta read [options]
+
+  Read the metadata of a node, including the metadata set at runtime with the
+  node meta apply command.
+
+  If -node-id is not given, the metadata of the local agent's node is read.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Read Options:
+
+  -node-id
+    Read the metadata of the node with the given ID or ID prefix.
+
+  -json
+    Output the metadata in its JSON format.
+
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
+  -t
+    Format and display the metadata using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaReadCommand) Synopsis() string {
+	return "Read the metadata of a node"
+}
+
+func (c *NodeMetaReadCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": c.Meta.PredictSearch(contexts.Nodes),
+			"-json":    complete.PredictNothing,
+			"-output":  complete.PredictSet("json", "yaml", "template"),
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaReadCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeMetaReadCommand) Name() string { return "node meta read" }
+
+func (c *NodeMetaReadCommand) Run(args []string) int {
+	var nodeID string
+	var format outputFormat
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	format.setFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	nodeID, err = lookupNodeMetaID(client, nodeID)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	resp, err := client.Nodes().ReadMeta(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading node metadata: %s", err))
+		return 1
+	}
+
+	if format.enabled() {
+		out, err := format.Format(resp)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	keys := make([]string, 0, len(resp.Meta))
+	for k := range resp.Meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	meta := make([]string, 0, len(keys))
+	for _, k := range keys {
+		meta = append(meta, fmt.Sprintf("%s|%s", k, resp.Meta[k]))
+	}
+	c.Ui.Output(formatKV(meta))
+	return 0
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type NodePoolCommand struct {
+	Meta
+}
+
+func (f *NodePoolCommand) Help() string {
+	helpText := `
+Usage: nomad node pool <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with node pools. Nodes belong
+  to the node pool set in their client configuration and jobs are only placed
+  on the nodes of the node pool they target.
+
+  Create or update a node pool:
+
+      $ nomad node pool apply -description="GPU nodes" gpu
+
+  List the node pools:
+
+      $ nomad node pool list
+
+  Please see the individual subcommand help for detailed usage information.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (f *NodePoolCommand) Synopsis() string {
+	return "Interact with node pools"
+}
+
+func (f *NodePoolCommand) Name() string { return "node pool" }
+
+func (f *NodePoolCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,154 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
+	"github.com/posener/complete"
+)
+
+type NodePoolApplyCommand struct {
+	Meta
+}
+
+func (c *NodePoolApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node pool apply [options] <pool>
+
+  Apply is used to create or update a node pool. It takes the node pool name to
+  create or update as its only argument. Fields that aren't set by a flag keep
+  their current value.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -description
+    An optional description for the node pool.
+
+  -meta <key>=<value>
+    Metadata of the node pool. May be specified multiple times. Setting any
+    metadata replaces all the existing metadata of the node pool.
+
+  -system-scheduler-preemption=<true|false>
+    Overrides whether system jobs may preempt lower priority allocations on the
+    nodes of the pool. Pools without an override use the cluster's scheduler
+    configuration.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-description":                 complete.PredictAnything,
+			"-meta":                        complete.PredictAnything,
+			"-system-scheduler-preemption": complete.PredictSet("true", "false"),
+		})
+}
+
+func (c *NodePoolApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodePoolApplyCommand) Synopsis() string {
+	return "Create or update a node pool"
+}
+
+func (c *NodePoolApplyCommand) Name() string { return "node pool apply" }
+
+func (c *NodePoolApplyCommand) Run(args []string) int {
+	var description *string
+	var preemption *bool
+	var meta flaghelper.StringFlag
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var((flaghelper.FuncVar)(func(s string) error {
+		description = &s
+		return nil
+	}), "description", "")
+	flags.Var(&meta, "meta", "")
+	flags.Var((flaghelper.FuncBoolVar)(func(b bool) error {
+		preemption = &b
+		return nil
+	}), "system-scheduler-preemption", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	name := args[0]
+	if name == "" {
+		c.Ui.Error("Node pool name required")
+		return 1
+	}
+
+	// Parse the metadata
+	var metaMap map[string]string
+	if len(meta) != 0 {
+		metaMap = make(map[string]string, len(meta))
+		for _, kv := range meta {
+			parts := strings.SplitN(kv, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				c.Ui.Error(fmt.Sprintf("Invalid metadata %q: must be <key>=<value>", kv))
+				return 1
+			}
+			metaMap[parts[0]] = parts[1]
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Lookup the given node pool
+	pool, _, err := client.NodePools().Info(name, nil)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		c.Ui.Error(fmt.Sprintf("Error looking up node pool: %s", err))
+		return 1
+	}
+
+	if pool == nil {
+		pool = &api.NodePool{
+			Name: name,
+		}
+	}
+
+	// Add what is set
+	if description != nil {
+		pool.Description = *description
+	}
+	if metaMap != nil {
+		pool.Meta = metaMap
+	}
+	if preemption != nil {
+		pool.SchedulerConfiguration = &api.NodePoolSchedulerConfiguration{
+			SystemSchedulerPreemptionEnabled: preemption,
+		}
+	}
+
+	_, err = client.NodePools().Register(pool, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node pool: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully applied node pool %q!", name))
+	return 0
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolApplyCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodePoolApplyCommand{}
+}
+
+func TestNodePoolApplyCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on invalid metadata
+	code = cmd.Run([]string{"-meta=nope", "gpu"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Invalid metadata")
+}
+
+func TestNodePoolApplyCommand_Good(t *testing.T) {
+	t.Parallel()
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Create a node pool
+	code := cmd.Run([]string{"-address=" + url, "-description=GPU nodes",
+		"-meta=team=data", "-system-scheduler-preemption=false", "gpu"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+	require.True(t, strings.Contains(ui.OutputWriter.String(), "gpu"))
+
+	pool, _, err := client.NodePools().Info("gpu", nil)
+	require.NoError(t, err)
+	require.Equal(t, "GPU nodes", pool.Description)
+	require.Equal(t, map[string]string{"team": "data"}, pool.Meta)
+	require.NotNil(t, pool.SchedulerConfiguration)
+	require.False(t, *pool.SchedulerConfiguration.SystemSchedulerPreemptionEnabled)
+
+	// Updating the description keeps the other fields
+	code = cmd.Run([]string{"-address=" + url, "-description=New", "gpu"})
+	require.Equal(t, 0, code, ui.ErrorWriter.String())
+
+	pool, _, err = client.NodePools().Info("gpu", nil)
+	require.NoError(t, err)
+	require.Equal(t, "New", pool.Description)
+	require.Equal(t, map[string]string{"team": "data"}, pool.Meta)
+}
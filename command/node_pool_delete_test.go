@@ -0,0 +1,40 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolDeleteCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodePoolDeleteCommand{}
+}
+
+func TestNodePoolDeleteCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{Name: "gpu"}, nil)
+	require.NoError(t, err)
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolDeleteCommand{Meta: Meta{Ui: ui}}
+
+	// Built-in node pools can't be deleted
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, api.NodePoolDefault}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error deleting node pool")
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "gpu"}))
+	require.Contains(t, ui.OutputWriter.String(), "Successfully deleted")
+
+	pools, _, err := client.NodePools().List(nil)
+	require.NoError(t, err)
+	require.Len(t, pools, 2)
+}
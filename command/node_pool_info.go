@@ -0,0 +1,99 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodePoolInfoCommand struct {
+	Meta
+}
+
+func (c *NodePoolInfoCommand) Help() string {
+	helpText := `
+Usage: nomad node pool info <pool>
+
+  Info is used to fetch information on an existing node pool.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodePoolInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *NodePoolInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodePoolInfoCommand) Synopsis() string {
+	return "Fetch info on an existing node pool"
+}
+
+func (c *NodePoolInfoCommand) Name() string { return "node pool info" }
+
+func (c *NodePoolInfoCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <pool>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Fetch info on the node pool
+	pool, _, err := client.NodePools().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error fetching info on node pool: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVNodePool(pool))
+	return 0
+}
+
+// formatKVNodePool returns a K/V formatted node pool
+func formatKVNodePool(pool *api.NodePool) string {
+	meta := make([]string, 0, len(pool.Meta))
+	for k, v := range pool.Meta {
+		meta = append(meta, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(meta)
+
+	preemption := "<cluster default>"
+	if sc := pool.SchedulerConfiguration; sc != nil && sc.SystemSchedulerPreemptionEnabled != nil {
+		preemption = fmt.Sprintf("%v", *sc.SystemSchedulerPreemptionEnabled)
+	}
+
+	output := []string{
+		fmt.Sprintf("Name|%s", pool.Name),
+		fmt.Sprintf("Description|%s", pool.Description),
+		fmt.Sprintf("Meta|%s", strings.Join(meta, ",")),
+		fmt.Sprintf("System Scheduler Preemption|%s", preemption),
+		fmt.Sprintf("Create Index|%d", pool.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", pool.ModifyIndex),
+	}
+	return formatKV(output)
+}
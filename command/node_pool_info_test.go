@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolInfoCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodePoolInfoCommand{}
+}
+
+func TestNodePoolInfoCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{
+		Name: "gpu",
+		Meta: map[string]string{"team": "data"},
+	}, nil)
+	require.NoError(t, err)
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolInfoCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url}))
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing node pool
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "missing"}))
+	require.Contains(t, ui.ErrorWriter.String(), "Error fetching info on node pool")
+	ui.ErrorWriter.Reset()
+
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "gpu"}))
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, "gpu")
+	require.Contains(t, out, "team=data")
+	require.Contains(t, out, "<cluster default>")
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodePoolListCommand{}
+}
+
+func TestNodePoolListCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	_, err := client.NodePools().Register(&api.NodePool{
+		Name:        "gpu",
+		Description: "GPU nodes",
+	}, nil)
+	require.NoError(t, err)
+
+	ui := new(cli.MockUi)
+	cmd := &NodePoolListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on arguments
+	require.Equal(t, 1, cmd.Run([]string{"-address=" + url, "nope"}))
+	ui.ErrorWriter.Reset()
+
+	// Lists the built-in and the registered node pools
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url}))
+	out := ui.OutputWriter.String()
+	require.Contains(t, out, api.NodePoolAll)
+	require.Contains(t, out, api.NodePoolDefault)
+	require.Contains(t, out, "GPU nodes")
+	ui.OutputWriter.Reset()
+
+	// JSON output
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + url, "-json"}))
+	require.Contains(t, ui.OutputWriter.String(), `"Name": "gpu"`)
+}
@@ -31,8 +31,8 @@ type NodeStatusCommand struct {
 	list_allocs bool
 	self        bool
 	stats       bool
-	json        bool
-	tmpl        string
+	hardware    bool
+	format      outputFormat
 }
 
 func (c *NodeStatusCommand) Help() string {
@@ -60,6 +60,11 @@ Node Status Options:
   -stats
     Display detailed resource usage statistics.
 
+  -hardware
+    Display the detailed hardware inventory of the node, including its CPU
+    models, NUMA layout, disks and network interfaces. The CPU flags are
+    displayed with -verbose.
+
   -allocs
     Display a count of running allocations for each node.
 
@@ -73,6 +78,10 @@ Node Status Options:
   -json
     Output the node in its JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display node using a Go template.
 `
@@ -86,29 +95,20 @@ func (c *NodeStatusCommand) Synopsis() string {
 func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-allocs":  complete.PredictNothing,
-			"-json":    complete.PredictNothing,
-			"-self":    complete.PredictNothing,
-			"-short":   complete.PredictNothing,
-			"-stats":   complete.PredictNothing,
-			"-t":       complete.PredictAnything,
-			"-verbose": complete.PredictNothing,
+			"-allocs":   complete.PredictNothing,
+			"-hardware": complete.PredictNothing,
+			"-json":     complete.PredictNothing,
+			"-self":     complete.PredictNothing,
+			"-short":    complete.PredictNothing,
+			"-stats":    complete.PredictNothing,
+			"-output":   complete.PredictSet("json", "yaml", "template"),
+			"-t":        complete.PredictAnything,
+			"-verbose":  complete.PredictNothing,
 		})
 }
 
 func (c *NodeStatusCommand) AutocompleteArgs() complete.Predictor {
-	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
-		if err != nil {
-			return nil
-		}
-
-		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
-		if err != nil {
-			return []string{}
-		}
-		return resp.Matches[contexts.Nodes]
-	})
+	return c.Meta.PredictSearch(contexts.Nodes)
 }
 
 func (c *NodeStatusCommand) Name() string { return "node-status" }
@@ -122,13 +122,18 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.list_allocs, "allocs", false, "")
 	flags.BoolVar(&c.self, "self", false, "")
 	flags.BoolVar(&c.stats, "stats", false, "")
-	flags.BoolVar(&c.json, "json", false, "")
-	flags.StringVar(&c.tmpl, "t", "", "")
+	flags.BoolVar(&c.hardware, "hardware", false, "")
+	c.format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := c.format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got either a single node or none
 	args = flags.Args()
 	if len(args) > 1 {
@@ -161,8 +166,8 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		}
 
 		// If output format is specified, format and output the node data list
-		if c.json || len(c.tmpl) > 0 {
-			out, err := Format(c.json, c.tmpl, nodes)
+		if c.format.enabled() {
+			out, err := c.format.Format(nodes)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -265,8 +270,8 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	}
 
 	// If output format is specified, format and output the data
-	if c.json || len(c.tmpl) > 0 {
-		out, err := Format(c.json, c.tmpl, node)
+	if c.format.enabled() {
+		out, err := c.format.Format(node)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
@@ -320,15 +325,25 @@ func formatDrain(n *api.Node) string {
 	return strconv.FormatBool(n.Drain)
 }
 
+// formatEligibility returns the eligibility of the node and when it is marked
+// as eligible again, if its ineligibility expires.
+func formatEligibility(n *api.Node) string {
+	if n.SchedulingEligibility == api.NodeSchedulingIneligible && !n.IneligibleUntil.IsZero() {
+		return fmt.Sprintf("%s; until %s", n.SchedulingEligibility, formatTime(n.IneligibleUntil))
+	}
+	return n.SchedulingEligibility
+}
+
 func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 	// Format the header output
 	basic := []string{
 		fmt.Sprintf("ID|%s", limit(node.ID, c.length)),
 		fmt.Sprintf("Name|%s", node.Name),
 		fmt.Sprintf("Class|%s", node.NodeClass),
+		fmt.Sprintf("Node Pool|%s", node.NodePool),
 		fmt.Sprintf("DC|%s", node.Datacenter),
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
-		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
+		fmt.Sprintf("Eligibility|%s", formatEligibility(node)),
 		fmt.Sprintf("Status|%s", node.Status),
 	}
 
@@ -405,6 +420,15 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 				printDeviceStats(c.Ui, hostStats.DeviceStats)
 			}
 		}
+
+		if c.hardware {
+			hardware, err := client.Nodes().Hardware(node.ID, nil)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("error fetching node hardware: %v", err))
+			} else {
+				c.printHardware(hardware)
+			}
+		}
 	}
 
 	nodeAllocs, _, err := client.Nodes().Allocations(node.ID, nil)
@@ -619,6 +643,60 @@ func (c *NodeStatusCommand) printDiskStats(hostStats *api.HostStats) {
 	}
 }
 
+// printHardware outputs the hardware inventory of the node, skipping the parts
+// that couldn't be determined on the platform of the node.
+func (c *NodeStatusCommand) printHardware(hardware *api.HostHardware) {
+	cpus := make([]string, len(hardware.CPUs)+1)
+	cpus[0] = "ID|Vendor|Model|MHz|Cores|Threads"
+	for i, cpu := range hardware.CPUs {
+		cpus[i+1] = fmt.Sprintf("%s|%s|%s|%v|%d|%d",
+			cpu.ID, cpu.Vendor, cpu.Model, humanize.FormatFloat(floatFormat, cpu.MHz), cpu.Cores, cpu.Threads)
+	}
+	c.Ui.Output(c.Colorize().Color("\n[bold]CPUs[reset]"))
+	c.Ui.Output(formatList(cpus))
+
+	if c.verbose {
+		for _, cpu := range hardware.CPUs {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf("\n[bold]CPU %q Flags[reset]", cpu.ID)))
+			c.Ui.Output(strings.Join(cpu.Flags, " "))
+		}
+	}
+
+	if len(hardware.NUMANodes) > 0 {
+		nodes := make([]string, len(hardware.NUMANodes)+1)
+		nodes[0] = "ID|CPUs|Memory"
+		for i, node := range hardware.NUMANodes {
+			nodes[i+1] = fmt.Sprintf("%d|%s|%s", node.ID, node.CPUs, humanize.IBytes(node.Memory))
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]NUMA Nodes[reset]"))
+		c.Ui.Output(formatList(nodes))
+	}
+
+	if len(hardware.Disks) > 0 {
+		disks := make([]string, len(hardware.Disks)+1)
+		disks[0] = "Name|Model|Size|Rotational"
+		for i, disk := range hardware.Disks {
+			disks[i+1] = fmt.Sprintf("%s|%s|%s|%v", disk.Name, disk.Model, humanize.IBytes(disk.Size), disk.Rotational)
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]Disks[reset]"))
+		c.Ui.Output(formatList(disks))
+	}
+
+	if len(hardware.NICs) > 0 {
+		nics := make([]string, len(hardware.NICs)+1)
+		nics[0] = "Name|MAC|MTU|Speed"
+		for i, nic := range hardware.NICs {
+			speed := "<unknown>"
+			if nic.Speed != 0 {
+				speed = fmt.Sprintf("%d Mb/s", nic.Speed)
+			}
+			nics[i+1] = fmt.Sprintf("%s|%s|%d|%s", nic.Name, nic.MAC, nic.MTU, speed)
+		}
+		c.Ui.Output(c.Colorize().Color("\n[bold]Network Interfaces[reset]"))
+		c.Ui.Output(formatList(nics))
+	}
+}
+
 // getRunningAllocs returns a slice of allocation id's running on the node
 func getRunningAllocs(client *api.Client, nodeID string) ([]*api.Allocation, error) {
 	var allocs []*api.Allocation
@@ -163,6 +163,16 @@ func TestNodeStatusCommand_Run(t *testing.T) {
 	if !strings.Contains(out, "mynode") {
 		t.Fatalf("expect to find mynode, got: %s", out)
 	}
+	ui.OutputWriter.Reset()
+
+	// Request the hardware inventory
+	if code := cmd.Run([]string{"-address=" + url, "-hardware", nodeID}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	out = ui.OutputWriter.String()
+	if !strings.Contains(out, "CPUs") || !strings.Contains(out, "Threads") {
+		t.Fatalf("expected hardware inventory, got: %s", out)
+	}
 }
 
 func TestNodeStatusCommand_Fails(t *testing.T) {
@@ -0,0 +1,406 @@
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+const (
+	// defaultDebugMaxNodes is the default number of client nodes captured
+	defaultDebugMaxNodes = 10
+)
+
+type OperatorDebugCommand struct {
+	Meta
+}
+
+func (c *OperatorDebugCommand) Help() string {
+	helpText := `
+Usage: nomad operator debug [options]
+
+  Builds an archive of debugging information for support bundles. The archive
+  contains the cluster members and nodes along with profiles of the agent the
+  command talks to and of the selected client nodes:
+
+    * agent-self.json: the configuration and stats of the agent
+    * profile.prof: a CPU profile over -pprof-duration
+    * trace.out: an execution trace over -pprof-duration
+    * goroutine.prof, goroutine-debug1.txt, goroutine-debug2.txt: goroutine
+      dumps in the pprof and text formats
+    * heap.prof: a heap profile
+
+  Client nodes are reached directly at their advertised HTTP address and also
+  include their node.json and plugins.json, which holds the health of their
+  driver and device plugins.
+
+  Profiling requires an ACL token with agent write permissions, or the
+  enable_debug option of the agents when ACLs are disabled.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Debug Options:
+
+  -node-id=<node1>,<node2>
+    Comma separated IDs or ID prefixes of the client nodes to capture, or
+    "all" to capture every ready node. No client nodes are captured by default.
+
+  -max-nodes=<count>
+    The maximum number of client nodes to capture. Defaults to 10.
+
+  -pprof-duration=<duration>
+    The duration of the CPU profiles and execution traces. Defaults to 1s.
+
+  -output=<path>
+    The directory the archive is written to. Defaults to the current
+    directory.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorDebugCommand) Synopsis() string {
+	return "Build an archive of debugging information"
+}
+
+func (c *OperatorDebugCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id":        c.Meta.PredictSearch(contexts.Nodes),
+			"-max-nodes":      complete.PredictAnything,
+			"-pprof-duration": complete.PredictAnything,
+			"-output":         complete.PredictDirs("*"),
+		})
+}
+
+func (c *OperatorDebugCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorDebugCommand) Name() string { return "operator debug" }
+
+func (c *OperatorDebugCommand) Run(args []string) int {
+	var nodeIDs, output string
+	var maxNodes int
+	var pprofDuration time.Duration
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeIDs, "node-id", "", "")
+	flags.IntVar(&maxNodes, "max-nodes", defaultDebugMaxNodes, "")
+	flags.DurationVar(&pprofDuration, "pprof-duration", time.Second, "")
+	flags.StringVar(&output, "output", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if pprofDuration < time.Second {
+		c.Ui.Error("The -pprof-duration must be at least 1s")
+		return 1
+	}
+	if maxNodes <= 0 {
+		c.Ui.Error("The -max-nodes must be positive")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	nodes, err := c.debugNodes(client, nodeIDs, maxNodes)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if output == "" {
+		output, err = os.Getwd()
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error getting working directory: %s", err))
+			return 1
+		}
+	}
+
+	tmp, err := ioutil.TempDir("", "nomad-debug")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating temporary directory: %s", err))
+		return 1
+	}
+	defer os.RemoveAll(tmp)
+
+	name := fmt.Sprintf("nomad-debug-%s", time.Now().UTC().Format("2006-01-02-150405Z"))
+	d := &debugCapture{
+		ui:      c.Ui,
+		dir:     filepath.Join(tmp, name),
+		seconds: int(pprofDuration / time.Second),
+	}
+
+	c.Ui.Output("Capturing cluster state and the profiles of the agent")
+	d.captureCluster(client)
+	d.captureAgent("agent", client)
+
+	for _, node := range nodes {
+		c.Ui.Output(fmt.Sprintf("Capturing client node %q", limit(node.ID, shortId)))
+		d.captureNode(client, node)
+	}
+
+	archive := filepath.Join(output, name+".tar.gz")
+	if err := writeDebugArchive(archive, tmp, name); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing archive: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Created debug archive: %s", archive))
+	return 0
+}
+
+// debugNodes resolves the -node-id flag into the client nodes to capture.
+func (c *OperatorDebugCommand) debugNodes(client *api.Client, nodeIDs string, maxNodes int) ([]*api.NodeListStub, error) {
+	if nodeIDs == "" {
+		return nil, nil
+	}
+
+	var nodes []*api.NodeListStub
+	seen := make(map[string]struct{})
+	add := func(node *api.NodeListStub) {
+		if _, ok := seen[node.ID]; ok {
+			return
+		}
+		seen[node.ID] = struct{}{}
+		nodes = append(nodes, node)
+	}
+
+	if nodeIDs == "all" {
+		stubs, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying nodes: %s", err)
+		}
+		for _, node := range stubs {
+			if node.Status == "ready" {
+				add(node)
+			}
+		}
+	} else {
+		for _, prefix := range strings.Split(nodeIDs, ",") {
+			prefix = strings.TrimSpace(prefix)
+			if prefix == "" {
+				continue
+			}
+			if len(prefix)%2 == 1 {
+				// Identifiers must be of even length, so we strip off the last byte
+				// to support both line completion and full IDs.
+				prefix = prefix[:len(prefix)-1]
+			}
+
+			stubs, _, err := client.Nodes().PrefixList(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("Error querying nodes: %s", err)
+			}
+			if len(stubs) == 0 {
+				return nil, fmt.Errorf("No node(s) with prefix %q found", prefix)
+			}
+			for _, node := range stubs {
+				add(node)
+			}
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	if len(nodes) > maxNodes {
+		c.Ui.Warn(fmt.Sprintf("Capturing %d of %d nodes, use -max-nodes to capture more", maxNodes, len(nodes)))
+		nodes = nodes[:maxNodes]
+	}
+	return nodes, nil
+}
+
+// debugCapture writes the files of a debug archive. Failures to collect a
+// file are reported as warnings so that the rest of the archive is still
+// built.
+type debugCapture struct {
+	ui      cli.Ui
+	dir     string
+	seconds int
+}
+
+// captureCluster writes the cluster members and nodes.
+func (d *debugCapture) captureCluster(client *api.Client) {
+	members, err := client.Agent().Members()
+	d.writeJSON("cluster", "members.json", members, err)
+
+	nodes, _, err := client.Nodes().List(nil)
+	d.writeJSON("cluster", "nodes.json", nodes, err)
+}
+
+// captureNode writes the node, the health of its plugins and the profiles of
+// its agent.
+func (d *debugCapture) captureNode(client *api.Client, stub *api.NodeListStub) {
+	dir := filepath.Join("client", stub.ID)
+
+	node, _, err := client.Nodes().Info(stub.ID, nil)
+	d.writeJSON(dir, "node.json", node, err)
+	if err == nil {
+		plugins := &debugPluginHealth{Drivers: node.Drivers}
+		if node.NodeResources != nil {
+			plugins.Devices = node.NodeResources.Devices
+		}
+		d.writeJSON(dir, "plugins.json", plugins, nil)
+	}
+
+	nodeClient, err := client.GetNodeClient(stub.ID, nil)
+	if err != nil {
+		d.warn(dir, err)
+		return
+	}
+
+	// When relaying through the servers, the node client is the client of the
+	// servers, so the agent of the node can't be captured.
+	if nodeClient == client {
+		d.warn(dir, fmt.Errorf("agent of the node can't be reached when relaying through the servers"))
+		return
+	}
+	d.captureAgent(dir, nodeClient)
+}
+
+// captureAgent writes the configuration and the profiles of the agent.
+func (d *debugCapture) captureAgent(dir string, client *api.Client) {
+	self, err := client.Agent().Self()
+	d.writeJSON(dir, "agent-self.json", self, err)
+
+	agent := client.Agent()
+	opts := api.PprofOptions{Seconds: d.seconds}
+
+	profile, err := agent.CPUProfile(opts, nil)
+	d.writeFile(dir, "profile.prof", profile, err)
+
+	trace, err := agent.Trace(opts, nil)
+	d.writeFile(dir, "trace.out", trace, err)
+
+	goroutines, err := agent.Lookup("goroutine", api.PprofOptions{}, nil)
+	d.writeFile(dir, "goroutine.prof", goroutines, err)
+
+	for _, debug := range []int{1, 2} {
+		goroutines, err := agent.Lookup("goroutine", api.PprofOptions{Debug: debug}, nil)
+		d.writeFile(dir, fmt.Sprintf("goroutine-debug%d.txt", debug), goroutines, err)
+	}
+
+	heap, err := agent.Lookup("heap", api.PprofOptions{}, nil)
+	d.writeFile(dir, "heap.prof", heap, err)
+}
+
+func (d *debugCapture) writeJSON(dir, file string, obj interface{}, err error) {
+	if err != nil {
+		d.warn(filepath.Join(dir, file), err)
+		return
+	}
+
+	buf, err := json.MarshalIndent(obj, "", "    ")
+	d.writeFile(dir, file, buf, err)
+}
+
+func (d *debugCapture) writeFile(dir, file string, buf []byte, err error) {
+	path := filepath.Join(dir, file)
+	if err != nil {
+		d.warn(path, err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Join(d.dir, dir), 0755); err != nil {
+		d.warn(path, err)
+		return
+	}
+	if err := ioutil.WriteFile(filepath.Join(d.dir, path), buf, 0644); err != nil {
+		d.warn(path, err)
+	}
+}
+
+func (d *debugCapture) warn(path string, err error) {
+	d.ui.Warn(fmt.Sprintf("Failed to capture %s: %s", path, err))
+}
+
+// debugPluginHealth is the health of the plugins of a node.
+type debugPluginHealth struct {
+	Drivers map[string]*api.DriverInfo
+	Devices []*api.NodeDeviceResource
+}
+
+// writeDebugArchive writes the directory name within dir to a gzipped tar
+// archive at path.
+func writeDebugArchive(path, dir, name string) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(filepath.Join(dir, name), func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
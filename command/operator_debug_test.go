@@ -0,0 +1,151 @@
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorDebugCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorDebugCommand{}
+}
+
+func TestOperatorDebugCommand(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, client, url := testServer(t, true, func(c *agent.Config) {
+		c.EnableDebug = true
+	})
+	defer srv.Shutdown()
+
+	// Wait for the node to be ready
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 || nodes[0].Status != "ready" {
+			return false, fmt.Errorf("missing ready node")
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	output, err := ioutil.TempDir("", "nomad-debug-test")
+	require.NoError(err)
+	defer os.RemoveAll(output)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorDebugCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-node-id=" + nodeID[:8], "-output=" + output})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Empty(ui.ErrorWriter.String())
+
+	archives, err := filepath.Glob(filepath.Join(output, "nomad-debug-*.tar.gz"))
+	require.NoError(err)
+	require.Len(archives, 1)
+	require.Contains(ui.OutputWriter.String(), archives[0])
+
+	files := readDebugArchive(t, archives[0])
+	for _, dir := range []string{"agent", "client/" + nodeID} {
+		for _, file := range []string{"agent-self.json", "profile.prof", "trace.out", "goroutine.prof", "goroutine-debug1.txt", "goroutine-debug2.txt", "heap.prof"} {
+			require.Contains(files, dir+"/"+file)
+			require.NotEmpty(files[dir+"/"+file])
+		}
+	}
+	require.Contains(files, "cluster/members.json")
+	require.Contains(files, "cluster/nodes.json")
+	require.Contains(files["client/"+nodeID+"/node.json"], nodeID)
+	require.Contains(files["client/"+nodeID+"/plugins.json"], "mock_driver")
+	require.Contains(files["agent/goroutine-debug2.txt"], "goroutine")
+}
+
+func TestOperatorDebugCommand_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.EnableDebug = false
+	})
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorDebugCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-pprof-duration=10ms"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "at least 1s")
+	ui.ErrorWriter.Reset()
+
+	// Fails on unknown nodes
+	code = cmd.Run([]string{"-address=" + url, "-node-id=12345678"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "No node(s) with prefix")
+	ui.ErrorWriter.Reset()
+
+	// Profiles that can not be collected are skipped with a warning
+	output, err := ioutil.TempDir("", "nomad-debug-test")
+	require.NoError(err)
+	defer os.RemoveAll(output)
+
+	code = cmd.Run([]string{"-address=" + url, "-output=" + output})
+	require.Equal(0, code)
+	require.Contains(ui.ErrorWriter.String(), "Failed to capture agent/profile.prof")
+	require.Contains(ui.ErrorWriter.String(), "enable_debug")
+}
+
+// readDebugArchive returns the contents of the files of a debug archive by
+// their path within the archive directory.
+func readDebugArchive(t *testing.T, path string) map[string]string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		buf, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+
+		// Strip the archive directory
+		parts := strings.SplitN(header.Name, "/", 2)
+		require.Len(t, parts, 2)
+		require.True(t, strings.HasPrefix(parts[0], "nomad-debug-"))
+		files[parts[1]] = string(buf)
+	}
+	return files
+}
@@ -3,7 +3,9 @@ package command
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
 	"github.com/ryanuber/columnize"
@@ -17,7 +19,11 @@ func (c *OperatorRaftListCommand) Help() string {
 	helpText := `
 Usage: nomad operator raft list-peers [options]
 
-  Displays the current Raft peer configuration.
+  Displays the current Raft peer configuration, along with the replication
+  and log store health of each peer: the time since it last heard from the
+  leader, the number of log entries it is behind the leader, the age of its
+  last snapshot and the size of its log store. Warnings are printed for the
+  peers whose stats couldn't be fetched or whose log store needs compaction.
 
 General Options:
 
@@ -81,16 +87,51 @@ func (c *OperatorRaftListCommand) Run(args []string) int {
 	}
 
 	// Format it as a nice table.
-	result := []string{"Node|ID|Address|State|Voter|RaftProtocol"}
+	now := time.Now()
+	result := []string{"Node|ID|Address|State|Voter|RaftProtocol|LastContact|Lag|LastSnapshot|LogSize"}
+	var warnings []string
 	for _, s := range reply.Servers {
 		state := "follower"
 		if s.Leader {
 			state = "leader"
 		}
-		result = append(result, fmt.Sprintf("%s|%s|%s|%s|%v|%s",
-			s.Node, s.ID, s.Address, state, s.Voter, s.RaftProtocol))
+		contact, lag, snapshot, size := "-", "-", "-", "-"
+		if st := s.Stats; st != nil {
+			contact, lag, snapshot, size = formatRaftLastContact(st.LastContact, s.Leader), fmt.Sprint(s.Lag), "never", "in-memory"
+			if !st.LastSnapshotTime.IsZero() {
+				snapshot = prettyTimeDiff(st.LastSnapshotTime, now)
+			}
+			if st.LogStoreSize != 0 {
+				size = humanize.IBytes(uint64(st.LogStoreSize))
+			}
+			for _, w := range st.Warnings {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", s.Node, w))
+			}
+		} else if s.StatsError != "" {
+			warnings = append(warnings, fmt.Sprintf("%s: Failed to fetch stats: %s", s.Node, s.StatsError))
+		}
+		result = append(result, fmt.Sprintf("%s|%s|%s|%s|%v|%s|%s|%s|%s|%s",
+			s.Node, s.ID, s.Address, state, s.Voter, s.RaftProtocol, contact, lag, snapshot, size))
 	}
 	c.Ui.Output(columnize.SimpleFormat(result))
 
+	if len(warnings) != 0 {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"\n[bold][yellow]Warnings:\n%s[reset]", strings.Join(warnings, "\n"))))
+	}
+
 	return 0
 }
+
+// formatRaftLastContact formats the time since a peer last heard from the
+// leader.
+func formatRaftLastContact(d time.Duration, leader bool) string {
+	switch {
+	case leader:
+		return "-"
+	case d < 0:
+		return "never"
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
@@ -29,4 +29,7 @@ func TestOperator_Raft_ListPeers(t *testing.T) {
 	if !strings.Contains(output, "leader") {
 		t.Fatalf("bad: %s", output)
 	}
+	if !strings.Contains(output, "LastContact") || !strings.Contains(output, "in-memory") {
+		t.Fatalf("bad: %s", output)
+	}
 }
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorReplicationCommand struct {
+	Meta
+}
+
+func (c *OperatorReplicationCommand) Name() string { return "operator replication" }
+
+func (c *OperatorReplicationCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *OperatorReplicationCommand) Synopsis() string {
+	return "Provides tools for inspecting cross-region replication"
+}
+
+func (c *OperatorReplicationCommand) Help() string {
+	helpText := `
+Usage: nomad operator replication <subcommand> [options]
+
+  This command groups subcommands for inspecting the replication of data such
+  as ACL policies and tokens from the authoritative region.
+
+  Display the replication status of the targeted region:
+
+      $ nomad operator replication status
+
+  Please see the individual subcommand help for detailed usage information.
+  `
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorReplicationStatusCommand struct {
+	Meta
+}
+
+func (c *OperatorReplicationStatusCommand) Help() string {
+	helpText := `
+Usage: nomad operator replication status [options]
+
+  Displays the status of replication from the authoritative region to the
+  region being queried. For each replicated type of data the output includes
+  the index replicated so far, the current index in the authoritative region
+  and the time of the last success and error, which helps diagnose regions
+  whose ACLs are out of date.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Replication Status Options:
+
+  -json
+    Output the replication status in its JSON format.
+
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
+  -t
+    Format and display the replication status using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorReplicationStatusCommand) Synopsis() string {
+	return "Display the status of cross-region replication"
+}
+
+func (c *OperatorReplicationStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":   complete.PredictNothing,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
+		})
+}
+
+func (c *OperatorReplicationStatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorReplicationStatusCommand) Name() string {
+	return "operator replication status"
+}
+
+func (c *OperatorReplicationStatusCommand) Run(args []string) int {
+	var format outputFormat
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	format.setFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	status, _, err := client.Operator().ReplicationStatus(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying replication status: %s", err))
+		return 1
+	}
+
+	if format.enabled() {
+		out, err := format.Format(status)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatReplicationStatus(status)))
+	return 0
+}
+
+// formatReplicationStatus returns a human readable view of the replication
+// status.
+func formatReplicationStatus(status *api.ReplicationStatus) string {
+	basic := []string{
+		fmt.Sprintf("Enabled|%v", status.Enabled),
+		fmt.Sprintf("Source Region|%s", status.SourceRegion),
+	}
+	if status.SourceError != "" {
+		basic = append(basic, fmt.Sprintf("Source Error|%s", status.SourceError))
+	}
+	out := formatKV(basic)
+
+	if !status.Enabled {
+		return out
+	}
+
+	types := []struct {
+		name   string
+		status *api.ReplicationTypeStatus
+	}{
+		{"ACL Policies", status.ACLPolicies},
+		{"ACL Roles", status.ACLRoles},
+		{"ACL Tokens", status.ACLTokens},
+	}
+
+	rows := []string{"Type|Running|Replicated Index|Source Index|Last Success|Last Error"}
+	var errors []string
+	for _, t := range types {
+		if t.status == nil {
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("%s|%v|%d|%d|%s|%s",
+			t.name, t.status.Running, t.status.ReplicatedIndex, t.status.SourceIndex,
+			formatTime(t.status.LastSuccess), formatTime(t.status.LastError)))
+		if t.status.LastErrorMessage != "" {
+			errors = append(errors, fmt.Sprintf("%s|%s", t.name, t.status.LastErrorMessage))
+		}
+	}
+	out += "\n\n[bold]Replication[reset]\n" + formatList(rows)
+
+	if len(errors) > 0 {
+		out += "\n\n[bold]Last Errors[reset]\n" + formatKV(errors)
+	}
+
+	return out
+}
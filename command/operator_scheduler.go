@@ -0,0 +1,45 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorSchedulerCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerCommand) Name() string { return "operator scheduler" }
+
+func (c *OperatorSchedulerCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *OperatorSchedulerCommand) Synopsis() string {
+	return "Provides tools for inspecting Nomad's schedulers"
+}
+
+func (c *OperatorSchedulerCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler <subcommand> [options]
+
+  This command groups subcommands for interacting with Nomad's schedulers and
+  the evaluation broker that feeds them work.
+
+  Display the state of the evaluation broker:
+
+      $ nomad operator scheduler broker-status
+
+  Display the scheduler configuration:
+
+      $ nomad operator scheduler get-config
+
+  Enable preemption for system jobs:
+
+      $ nomad operator scheduler set-config -preempt-system-scheduler=true
+
+  Please see the individual subcommand help for detailed usage information.
+  `
+	return strings.TrimSpace(helpText)
+}
@@ -0,0 +1,146 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerBrokerStatusCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerBrokerStatusCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler broker-status [options]
+
+  Displays the state of the evaluation broker running on the leader. The output
+  includes the number of ready, unacknowledged and blocked evaluations for each
+  scheduler and how long the oldest of them have been waiting, which helps
+  diagnose scheduling stalls.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Broker Status Options:
+
+  -json
+    Output the broker status in its JSON format.
+
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
+  -t
+    Format and display the broker status using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerBrokerStatusCommand) Synopsis() string {
+	return "Display the state of the evaluation broker"
+}
+
+func (c *OperatorSchedulerBrokerStatusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":   complete.PredictNothing,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
+		})
+}
+
+func (c *OperatorSchedulerBrokerStatusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSchedulerBrokerStatusCommand) Name() string {
+	return "operator scheduler broker-status"
+}
+
+func (c *OperatorSchedulerBrokerStatusCommand) Run(args []string) int {
+	var format outputFormat
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	format.setFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	status, _, err := client.Operator().EvalBrokerStatus(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation broker status: %s", err))
+		return 1
+	}
+
+	if format.enabled() {
+		out, err := format.Format(status)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatEvalBrokerStatus(status)))
+	return 0
+}
+
+// formatEvalBrokerStatus returns a human readable view of the broker's state.
+func formatEvalBrokerStatus(status *api.EvalBrokerStatus) string {
+	basic := []string{
+		fmt.Sprintf("Enabled|%v", status.Enabled),
+		fmt.Sprintf("Total Ready|%d", status.TotalReady),
+		fmt.Sprintf("Total Unacked|%d", status.TotalUnacked),
+		fmt.Sprintf("Total Blocked|%d", status.TotalBlocked),
+		fmt.Sprintf("Total Waiting|%d", status.TotalWaiting),
+		fmt.Sprintf("Total Failed|%d", status.TotalFailed),
+	}
+	out := formatKV(basic)
+
+	if len(status.ByScheduler) == 0 {
+		return out
+	}
+
+	schedulers := make([]string, 0, len(status.ByScheduler))
+	for sched := range status.ByScheduler {
+		schedulers = append(schedulers, sched)
+	}
+	sort.Strings(schedulers)
+
+	rows := make([]string, 0, len(schedulers)+1)
+	rows = append(rows, "Scheduler|Ready|Unacked|Blocked|Oldest Ready|Oldest Unacked")
+	for _, sched := range schedulers {
+		s := status.ByScheduler[sched]
+		rows = append(rows, fmt.Sprintf("%s|%d|%d|%d|%s|%s",
+			sched, s.Ready, s.Unacked, s.Blocked, s.OldestReady, s.OldestUnacked))
+	}
+
+	return out + "\n\n[bold]Schedulers[reset]\n" + formatList(rows)
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestOperatorSchedulerBrokerStatusCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSchedulerBrokerStatusCommand{}
+}
+
+func TestOperatorSchedulerBrokerStatusCommand(t *testing.T) {
+	t.Parallel()
+	s, _, addr := testServer(t, false, nil)
+	defer s.Shutdown()
+
+	ui := new(cli.MockUi)
+	c := &OperatorSchedulerBrokerStatusCommand{Meta: Meta{Ui: ui}}
+	args := []string{"-address=" + addr}
+
+	code := c.Run(args)
+	if code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+	output := strings.TrimSpace(ui.OutputWriter.String())
+	if !strings.Contains(output, "Enabled") || !strings.Contains(output, "true") {
+		t.Fatalf("bad: %s", output)
+	}
+
+	// Check the JSON output
+	ui.OutputWriter.Reset()
+	code = c.Run([]string{"-address=" + addr, "-json"})
+	if code != 0 {
+		t.Fatalf("bad: %d. %#v", code, ui.ErrorWriter.String())
+	}
+	output = ui.OutputWriter.String()
+	if !strings.Contains(output, `"Enabled": true`) {
+		t.Fatalf("bad: %s", output)
+	}
+}
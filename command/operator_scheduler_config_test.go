@@ -0,0 +1,76 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerConfigCommands_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSchedulerGetConfigCommand{}
+	var _ cli.Command = &OperatorSchedulerSetConfigCommand{}
+}
+
+func TestOperatorSchedulerConfigCommands(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s, _, addr := testServer(t, false, nil)
+	defer s.Shutdown()
+
+	ui := new(cli.MockUi)
+	set := &OperatorSchedulerSetConfigCommand{Meta: Meta{Ui: ui}}
+	code := set.Run([]string{
+		"-address=" + addr,
+		"-preempt-system-scheduler=false",
+		"-plan-rejection-tracker=true",
+		"-plan-rejection-node-threshold=42",
+		"-plan-rejection-node-window=90s",
+	})
+	require.EqualValues(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Scheduler configuration updated")
+
+	client, err := set.Client()
+	require.NoError(err)
+	resp, _, err := client.Operator().SchedulerGetConfiguration(nil)
+	require.NoError(err)
+	conf := resp.SchedulerConfig
+	require.False(conf.PreemptionConfig.SystemSchedulerEnabled)
+	require.True(conf.PlanRejectionTracker.Enabled)
+	require.Equal(42, conf.PlanRejectionTracker.NodeThreshold)
+	require.Equal(90*time.Second, conf.PlanRejectionTracker.NodeWindow)
+
+	// Flags that aren't given leave their setting unchanged
+	ui.OutputWriter.Reset()
+	code = set.Run([]string{"-address=" + addr, "-plan-rejection-tracker=false"})
+	require.EqualValues(0, code, ui.ErrorWriter.String())
+	resp, _, err = client.Operator().SchedulerGetConfiguration(nil)
+	require.NoError(err)
+	require.False(resp.SchedulerConfig.PlanRejectionTracker.Enabled)
+	require.Equal(42, resp.SchedulerConfig.PlanRejectionTracker.NodeThreshold)
+
+	// A stale check index is rejected
+	ui.ErrorWriter.Reset()
+	code = set.Run([]string{"-address=" + addr, "-check-index=1", "-preempt-system-scheduler=true"})
+	require.EqualValues(1, code)
+	require.Contains(ui.ErrorWriter.String(), "modified concurrently")
+
+	// Check the displayed configuration
+	ui.OutputWriter.Reset()
+	get := &OperatorSchedulerGetConfigCommand{Meta: Meta{Ui: ui}}
+	code = get.Run([]string{"-address=" + addr})
+	require.EqualValues(0, code, ui.ErrorWriter.String())
+	output := ui.OutputWriter.String()
+	require.Contains(output, "Preemption System Scheduler")
+	require.Contains(output, "Plan Rejection Node Threshold")
+	require.Contains(output, "42")
+	require.Contains(output, "1m30s")
+
+	ui.OutputWriter.Reset()
+	code = get.Run([]string{"-address=" + addr, "-json"})
+	require.EqualValues(0, code, ui.ErrorWriter.String())
+	require.True(strings.Contains(ui.OutputWriter.String(), `"NodeThreshold": 42`))
+}
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerGetConfigCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerGetConfigCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler get-config [options]
+
+  Displays the current scheduler configuration.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Get Config Options:
+
+  -json
+    Output the scheduler configuration in its JSON format.
+
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
+  -t
+    Format and display the scheduler configuration using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerGetConfigCommand) Synopsis() string {
+	return "Display the current scheduler configuration"
+}
+
+func (c *OperatorSchedulerGetConfigCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":   complete.PredictNothing,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
+		})
+}
+
+func (c *OperatorSchedulerGetConfigCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSchedulerGetConfigCommand) Name() string {
+	return "operator scheduler get-config"
+}
+
+func (c *OperatorSchedulerGetConfigCommand) Run(args []string) int {
+	var format outputFormat
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	format.setFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.Operator().SchedulerGetConfiguration(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying scheduler configuration: %s", err))
+		return 1
+	}
+
+	if format.enabled() {
+		out, err := format.Format(resp.SchedulerConfig)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatSchedulerConfig(resp.SchedulerConfig))
+	return 0
+}
+
+// formatSchedulerConfig returns a human readable view of the scheduler
+// configuration. Unset plan rejection tracker settings show their defaults.
+func formatSchedulerConfig(conf *api.SchedulerConfiguration) string {
+	threshold := conf.PlanRejectionTracker.NodeThreshold
+	if threshold <= 0 {
+		threshold = structs.DefaultPlanRejectionNodeThreshold
+	}
+	window := conf.PlanRejectionTracker.NodeWindow
+	if window <= 0 {
+		window = structs.DefaultPlanRejectionNodeWindow
+	}
+
+	return formatKV([]string{
+		fmt.Sprintf("Preemption System Scheduler|%v", conf.PreemptionConfig.SystemSchedulerEnabled),
+		fmt.Sprintf("Plan Rejection Tracker Enabled|%v", conf.PlanRejectionTracker.Enabled),
+		fmt.Sprintf("Plan Rejection Node Threshold|%d", threshold),
+		fmt.Sprintf("Plan Rejection Node Window|%s", window),
+		fmt.Sprintf("Modify Index|%d", conf.ModifyIndex),
+	})
+}
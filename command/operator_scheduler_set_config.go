@@ -0,0 +1,145 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/command/flags"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerSetConfigCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerSetConfigCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler set-config [options]
+
+  Modifies the current scheduler configuration. Only the settings given as
+  flags are changed. The configuration is updated with a check-and-set
+  operation, so the command fails instead of overwriting a concurrent change.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Set Config Options:
+
+  -check-index=<index>
+    Only update the configuration if its modify index still matches the given
+    index, as displayed by "nomad operator scheduler get-config". Defaults to
+    the modify index of the configuration read by this command.
+
+  -preempt-system-scheduler=[true|false]
+    Controls whether the system scheduler may preempt lower priority
+    allocations to place system jobs.
+
+  -plan-rejection-tracker=[true|false]
+    Controls whether nodes whose placements are repeatedly rejected by the
+    plan applier are marked as ineligible for scheduling.
+
+  -plan-rejection-node-threshold=<count>
+    The number of plan rejections within the window after which a node is
+    marked as ineligible. Zero resets it to its default of 100.
+
+  -plan-rejection-node-window=<duration>
+    The sliding window plan rejections are counted in, such as "5m". Zero
+    resets it to its default of 5 minutes.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSetConfigCommand) Synopsis() string {
+	return "Modify the current scheduler configuration"
+}
+
+func (c *OperatorSchedulerSetConfigCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-check-index":                   complete.PredictAnything,
+			"-preempt-system-scheduler":      complete.PredictSet("true", "false"),
+			"-plan-rejection-tracker":        complete.PredictSet("true", "false"),
+			"-plan-rejection-node-threshold": complete.PredictAnything,
+			"-plan-rejection-node-window":    complete.PredictAnything,
+		})
+}
+
+func (c *OperatorSchedulerSetConfigCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSchedulerSetConfigCommand) Name() string {
+	return "operator scheduler set-config"
+}
+
+func (c *OperatorSchedulerSetConfigCommand) Run(args []string) int {
+	var checkIndex, nodeThreshold flags.UintValue
+	var preemptSystem, rejectionTracker flags.BoolValue
+	var nodeWindow flags.DurationValue
+
+	f := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	f.Usage = func() { c.Ui.Output(c.Help()) }
+	f.Var(&checkIndex, "check-index", "")
+	f.Var(&preemptSystem, "preempt-system-scheduler", "")
+	f.Var(&rejectionTracker, "plan-rejection-tracker", "")
+	f.Var(&nodeThreshold, "plan-rejection-node-threshold", "")
+	f.Var(&nodeWindow, "plan-rejection-node-window", "")
+
+	if err := f.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = f.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Fetch the current configuration
+	operator := client.Operator()
+	resp, _, err := operator.SchedulerGetConfiguration(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying scheduler configuration: %s", err))
+		return 1
+	}
+	conf := resp.SchedulerConfig
+
+	// Update the config values based on the set flags
+	preemptSystem.Merge(&conf.PreemptionConfig.SystemSchedulerEnabled)
+	rejectionTracker.Merge(&conf.PlanRejectionTracker.Enabled)
+	threshold := uint(conf.PlanRejectionTracker.NodeThreshold)
+	nodeThreshold.Merge(&threshold)
+	conf.PlanRejectionTracker.NodeThreshold = int(threshold)
+	nodeWindow.Merge(&conf.PlanRejectionTracker.NodeWindow)
+	if conf.PlanRejectionTracker.NodeWindow < 0 {
+		c.Ui.Error("Plan rejection node window must not be negative")
+		return 1
+	}
+
+	index := uint(conf.ModifyIndex)
+	checkIndex.Merge(&index)
+	conf.ModifyIndex = uint64(index)
+
+	// Check-and-set the new configuration
+	result, _, err := operator.SchedulerCASConfiguration(conf, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error setting scheduler configuration: %s", err))
+		return 1
+	}
+	if !result.Updated {
+		c.Ui.Error("Configuration was modified concurrently, please check it and try again")
+		return 1
+	}
+
+	c.Ui.Output("Scheduler configuration updated!")
+	return 0
+}
@@ -0,0 +1,41 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorUsageCommand struct {
+	Meta
+}
+
+func (c *OperatorUsageCommand) Name() string { return "operator usage" }
+
+func (c *OperatorUsageCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *OperatorUsageCommand) Synopsis() string {
+	return "Provides tools for reporting the usage of the cluster"
+}
+
+func (c *OperatorUsageCommand) Help() string {
+	helpText := `
+Usage: nomad operator usage <subcommand> [options]
+
+  This command groups subcommands for reporting the inventory of the cluster
+  and the resources allocated to its jobs, such as for chargeback.
+
+  Export the usage of the last day as JSON:
+
+      $ nomad operator usage export
+
+  Export the usage of each job over the last week as CSV:
+
+      $ nomad operator usage export -window=168h -format=csv -table=jobs
+
+  Please see the individual subcommand help for detailed usage information.
+  `
+	return strings.TrimSpace(helpText)
+}
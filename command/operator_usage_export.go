@@ -0,0 +1,221 @@
+package command
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorUsageExportCommand struct {
+	Meta
+}
+
+func (c *OperatorUsageExportCommand) Help() string {
+	helpText := `
+Usage: nomad operator usage export [options]
+
+  Export the inventory of the jobs, allocations and nodes of the cluster along
+  with the resources allocated to them within a time window, from the state of
+  the servers. Usage is measured as the CPU and memory reserved by allocations
+  over the time they were running within the window, in MHz-seconds and
+  MB-seconds. Only allocations that haven't been garbage collected are
+  included, so the window should be shorter than the garbage collection
+  thresholds of the servers.
+
+  The whole export is written in JSON. The CSV format writes a single table of
+  the export, selected with -table.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Usage Export Options:
+
+  -start=<time>
+    The start of the window, in RFC3339 format. Defaults to the end of the
+    window minus -window.
+
+  -end=<time>
+    The end of the window, in RFC3339 format. Defaults to the current time.
+
+  -window=<duration>
+    The length of the window if no start is given. Defaults to 24h.
+
+  -format=<json|csv>
+    The format of the export. Defaults to json.
+
+  -table=<jobs|allocations|nodes|totals>
+    The table written in the CSV format. Defaults to jobs.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorUsageExportCommand) Synopsis() string {
+	return "Export the inventory and resource usage of the cluster"
+}
+
+func (c *OperatorUsageExportCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-start":  complete.PredictAnything,
+			"-end":    complete.PredictAnything,
+			"-window": complete.PredictAnything,
+			"-format": complete.PredictSet("json", "csv"),
+			"-table":  complete.PredictSet("jobs", "allocations", "nodes", "totals"),
+		})
+}
+
+func (c *OperatorUsageExportCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorUsageExportCommand) Name() string { return "operator usage export" }
+
+func (c *OperatorUsageExportCommand) Run(args []string) int {
+	var startStr, endStr, format, table string
+	var window time.Duration
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&startStr, "start", "", "")
+	flags.StringVar(&endStr, "end", "", "")
+	flags.DurationVar(&window, "window", 24*time.Hour, "")
+	flags.StringVar(&format, "format", "json", "")
+	flags.StringVar(&table, "table", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	switch format {
+	case "json":
+		if table != "" {
+			c.Ui.Error("The -table flag requires the csv format")
+			return 1
+		}
+	case "csv":
+		switch table {
+		case "":
+			table = "jobs"
+		case "jobs", "allocations", "nodes", "totals":
+		default:
+			c.Ui.Error(fmt.Sprintf("Unsupported table %q: must be one of jobs, allocations, nodes or totals", table))
+			return 1
+		}
+	default:
+		c.Ui.Error(fmt.Sprintf("Unsupported format %q: must be one of json or csv", format))
+		return 1
+	}
+
+	// Determine the window
+	end := time.Now()
+	if endStr != "" {
+		t, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing end time: %s", err))
+			return 1
+		}
+		end = t
+	}
+	if window <= 0 {
+		c.Ui.Error("The window must be positive")
+		return 1
+	}
+	start := end.Add(-window)
+	if startStr != "" {
+		t, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing start time: %s", err))
+			return 1
+		}
+		start = t
+	}
+	if !start.Before(end) {
+		c.Ui.Error("The start of the window must be before its end")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	usage, _, err := client.Operator().UsageExport(start, end, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error exporting usage: %s", err))
+		return 1
+	}
+
+	var out string
+	if format == "json" {
+		out, err = Format(true, "", usage)
+	} else {
+		out, err = formatUsageCSV(usage, table)
+	}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	c.Ui.Output(out)
+	return 0
+}
+
+// formatUsageCSV returns a table of the usage report in CSV format.
+func formatUsageCSV(usage *api.UsageReport, table string) (string, error) {
+	var rows [][]string
+	itoa := func(i int64) string { return strconv.FormatInt(i, 10) }
+
+	switch table {
+	case "jobs":
+		rows = append(rows, []string{"namespace", "id", "parent_id", "type", "allocations", "cpu_mhz_seconds", "memory_mb_seconds"})
+		for _, j := range usage.Jobs {
+			rows = append(rows, []string{j.Namespace, j.ID, j.ParentID, j.Type,
+				strconv.Itoa(j.Allocations), itoa(j.CPUMHzSeconds), itoa(j.MemoryMBSeconds)})
+		}
+	case "allocations":
+		rows = append(rows, []string{"id", "namespace", "job_id", "task_group", "node_id", "client_status", "start", "end", "cpu_mhz", "memory_mb", "cpu_mhz_seconds", "memory_mb_seconds"})
+		for _, a := range usage.Allocations {
+			rows = append(rows, []string{a.ID, a.Namespace, a.JobID, a.TaskGroup, a.NodeID, a.ClientStatus,
+				a.Start.Format(time.RFC3339), a.End.Format(time.RFC3339),
+				itoa(a.CPUMHz), itoa(a.MemoryMB), itoa(a.CPUMHzSeconds), itoa(a.MemoryMBSeconds)})
+		}
+	case "nodes":
+		rows = append(rows, []string{"id", "name", "datacenter", "node_class", "node_pool", "status", "cpu_mhz", "memory_mb", "allocations", "cpu_mhz_seconds", "memory_mb_seconds"})
+		for _, n := range usage.Nodes {
+			rows = append(rows, []string{n.ID, n.Name, n.Datacenter, n.NodeClass, n.NodePool, n.Status,
+				itoa(n.CPUMHz), itoa(n.MemoryMB), strconv.Itoa(n.Allocations), itoa(n.CPUMHzSeconds), itoa(n.MemoryMBSeconds)})
+		}
+	case "totals":
+		rows = append(rows, []string{"start", "end", "jobs", "allocations", "nodes", "cpu_mhz", "memory_mb", "cpu_mhz_seconds", "memory_mb_seconds"})
+		if t := usage.Totals; t != nil {
+			rows = append(rows, []string{usage.Start.Format(time.RFC3339), usage.End.Format(time.RFC3339),
+				strconv.Itoa(t.Jobs), strconv.Itoa(t.Allocations), strconv.Itoa(t.Nodes),
+				itoa(t.CPUMHz), itoa(t.MemoryMB), itoa(t.CPUMHzSeconds), itoa(t.MemoryMBSeconds)})
+		}
+	default:
+		return "", fmt.Errorf("Unsupported table %q", table)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return "", fmt.Errorf("Error formatting the usage: %s", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorUsageExportCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorUsageExportCommand{}
+}
+
+func TestOperatorUsageExportCommand_Fails(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"some", "bad", "args"}, commandErrorText(&OperatorUsageExportCommand{})},
+		{[]string{"-format=xml"}, "Unsupported format"},
+		{[]string{"-format=csv", "-table=evals"}, "Unsupported table"},
+		{[]string{"-table=jobs"}, "requires the csv format"},
+		{[]string{"-end=yesterday"}, "Error parsing end time"},
+		{[]string{"-start=2019-01-02T00:00:00Z", "-end=2019-01-01T00:00:00Z"}, "must be before its end"},
+		{[]string{"-address=nope"}, "Error exporting usage"},
+	}
+
+	for _, c := range cases {
+		ui := new(cli.MockUi)
+		cmd := &OperatorUsageExportCommand{Meta: Meta{Ui: ui}}
+		require.Equal(t, 1, cmd.Run(c.args), "args: %v", c.args)
+		require.Contains(t, ui.ErrorWriter.String(), c.expected)
+	}
+}
+
+func TestOperatorUsageExportCommand_Run(t *testing.T) {
+	t.Parallel()
+	s, _, addr := testServer(t, false, nil)
+	defer s.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorUsageExportCommand{Meta: Meta{Ui: ui}}
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + addr}), ui.ErrorWriter.String())
+	require.Contains(t, ui.OutputWriter.String(), `"Totals"`)
+
+	ui.OutputWriter.Reset()
+	require.Equal(t, 0, cmd.Run([]string{"-address=" + addr, "-format=csv", "-table=totals"}), ui.ErrorWriter.String())
+	require.True(t, strings.HasPrefix(ui.OutputWriter.String(), "start,end,jobs,"))
+}
+
+func TestFormatUsageCSV(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	usage := &api.UsageReport{
+		Start: start,
+		End:   start.Add(time.Hour),
+		Totals: &api.UsageTotals{
+			Jobs:          1,
+			Allocations:   1,
+			CPUMHzSeconds: 3600,
+		},
+		Jobs: []*api.JobUsage{{
+			Namespace:     "default",
+			ID:            "example, with comma",
+			Type:          "service",
+			Allocations:   1,
+			CPUMHzSeconds: 3600,
+		}},
+	}
+
+	out, err := formatUsageCSV(usage, "jobs")
+	require.NoError(err)
+	require.Equal(`namespace,id,parent_id,type,allocations,cpu_mhz_seconds,memory_mb_seconds
+default,"example, with comma",,service,1,3600,0`, out)
+
+	out, err = formatUsageCSV(usage, "totals")
+	require.NoError(err)
+	require.Equal(`start,end,jobs,allocations,nodes,cpu_mhz,memory_mb,cpu_mhz_seconds,memory_mb_seconds
+2019-01-01T00:00:00Z,2019-01-01T01:00:00Z,1,1,0,0,0,3600,0`, out)
+
+	_, err = formatUsageCSV(usage, "evals")
+	require.Error(err)
+}
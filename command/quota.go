@@ -53,7 +53,7 @@ func (f *QuotaCommand) Run(args []string) int {
 // QuotaPredictor returns a quota predictor
 func QuotaPredictor(factory ApiClientFactory) complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := factory()
+		client, err := factory(a)
 		if err != nil {
 			return nil
 		}
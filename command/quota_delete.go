@@ -29,7 +29,7 @@ func (c *QuotaDeleteCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *QuotaDeleteCommand) AutocompleteArgs() complete.Predictor {
-	return QuotaPredictor(c.Meta.Client)
+	return QuotaPredictor(c.Meta.autocompleteClient)
 }
 
 func (c *QuotaDeleteCommand) Synopsis() string {
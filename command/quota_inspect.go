@@ -30,6 +30,10 @@ General Options:
 
 Inspect Options:
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display the namespaces using a Go template.
 `
@@ -40,12 +44,13 @@ Inspect Options:
 func (c *QuotaInspectCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-t": complete.PredictAnything,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
 		})
 }
 
 func (c *QuotaInspectCommand) AutocompleteArgs() complete.Predictor {
-	return QuotaPredictor(c.Meta.Client)
+	return QuotaPredictor(c.Meta.autocompleteClient)
 }
 
 func (c *QuotaInspectCommand) Synopsis() string {
@@ -55,15 +60,20 @@ func (c *QuotaInspectCommand) Synopsis() string {
 func (c *QuotaInspectCommand) Name() string { return "quota inspect" }
 
 func (c *QuotaInspectCommand) Run(args []string) int {
-	var tmpl string
+	var format outputFormat
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got one arguments
 	args = flags.Args()
 	if l := len(args); l != 1 {
@@ -108,7 +118,12 @@ func (c *QuotaInspectCommand) Run(args []string) int {
 		Failures: failuresConverted,
 	}
 
-	out, err := Format(len(tmpl) == 0, tmpl, data)
+	// Output JSON unless another format was requested
+	if !format.enabled() {
+		format.json = true
+	}
+
+	out, err := format.Format(data)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
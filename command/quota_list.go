@@ -28,6 +28,10 @@ List Options:
   -json
     Output the quota specifications in a JSON format.
 
+  -output=<json|yaml|template>
+    Output the data in the given format. The template format requires a Go
+    template given with -t.
+
   -t
     Format and display the quota specifications using a Go template.
 `
@@ -37,8 +41,9 @@ List Options:
 func (c *QuotaListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json": complete.PredictNothing,
-			"-t":    complete.PredictAnything,
+			"-json":   complete.PredictNothing,
+			"-output": complete.PredictSet("json", "yaml", "template"),
+			"-t":      complete.PredictAnything,
 		})
 }
 
@@ -52,18 +57,21 @@ func (c *QuotaListCommand) Synopsis() string {
 
 func (c *QuotaListCommand) Name() string { return "quota list" }
 func (c *QuotaListCommand) Run(args []string) int {
-	var json bool
-	var tmpl string
+	var format outputFormat
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
-	flags.BoolVar(&json, "json", false, "")
-	flags.StringVar(&tmpl, "t", "", "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
 
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Check that we got no arguments
 	args = flags.Args()
 	if l := len(args); l != 0 {
@@ -85,8 +93,8 @@ func (c *QuotaListCommand) Run(args []string) int {
 		return 1
 	}
 
-	if json || len(tmpl) > 0 {
-		out, err := Format(json, tmpl, quotas)
+	if format.enabled() {
+		out, err := format.Format(quotas)
 		if err != nil {
 			c.Ui.Error(err.Error())
 			return 1
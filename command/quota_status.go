@@ -33,7 +33,7 @@ func (c *QuotaStatusCommand) AutocompleteFlags() complete.Flags {
 }
 
 func (c *QuotaStatusCommand) AutocompleteArgs() complete.Predictor {
-	return QuotaPredictor(c.Meta.Client)
+	return QuotaPredictor(c.Meta.autocompleteClient)
 }
 
 func (c *QuotaStatusCommand) Synopsis() string {
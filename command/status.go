@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
@@ -21,9 +22,29 @@ Usage: nomad status [options] <identifier>
   detect the type of resource being queried and display the appropriate
   status output.
 
+  When -capacity is given instead of an identifier, the total, allocated and
+  actually used CPU, memory and disk of the ready nodes are summarized per
+  datacenter and node class.
+
 General Options:
 
-  ` + generalOptionsUsage()
+  ` + generalOptionsUsage() + `
+
+Status Options:
+
+  -capacity
+    Display the capacity of the cluster instead of the status of a resource.
+
+  -json
+    Output the capacity in its JSON format.
+
+  -output=<json|yaml|template>
+    Output the capacity in the given format. The template format requires a
+    Go template given with -t.
+
+  -t
+    Format and display the capacity using a Go template.
+`
 
 	return strings.TrimSpace(helpText)
 }
@@ -33,12 +54,18 @@ func (c *StatusCommand) Synopsis() string {
 }
 
 func (c *StatusCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-capacity": complete.PredictNothing,
+			"-json":     complete.PredictNothing,
+			"-output":   complete.PredictSet("json", "yaml", "template"),
+			"-t":        complete.PredictAnything,
+		})
 }
 
 func (c *StatusCommand) AutocompleteArgs() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
+		client, err := c.Meta.autocompleteClient(a)
 		if err != nil {
 			return nil
 		}
@@ -62,9 +89,16 @@ func (c *StatusCommand) AutocompleteArgs() complete.Predictor {
 	})
 }
 
+func (c *StatusCommand) Name() string { return "status" }
+
 func (c *StatusCommand) Run(args []string) int {
-	flags := c.Meta.FlagSet("status", FlagSetClient)
+	var capacity bool
+	var format outputFormat
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&capacity, "capacity", false, "")
+	format.setFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing arguments: %q", err))
@@ -84,6 +118,15 @@ func (c *StatusCommand) Run(args []string) int {
 		return 1
 	}
 
+	if capacity {
+		if len(args) != 0 {
+			c.Ui.Error("The -capacity flag takes no arguments")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		return c.outputCapacity(client, format)
+	}
+
 	// If no identifier is provided, default to listing jobs
 	if len(args) == 0 {
 		cmd := &JobStatusCommand{Meta: c.Meta}
@@ -157,6 +200,33 @@ func (c *StatusCommand) Run(args []string) int {
 	return cmd.Run(argsCopy)
 }
 
+// outputCapacity outputs the capacity of the cluster.
+func (c *StatusCommand) outputCapacity(client *api.Client, format outputFormat) int {
+	if err := format.validate(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	summaries, err := clusterCapacity(client)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if format.enabled() {
+		out, err := format.Format(summaries)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(formatCapacity(summaries)))
+	return 0
+}
+
 // logMultiMatchError is used to log an error message when multiple matches are
 // found. The error message logged displays the matched IDs per context.
 func (c *StatusCommand) logMultiMatchError(id string, matches map[contexts.Context][]string) {
@@ -0,0 +1,192 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+)
+
+// CapacitySummary is the capacity of the ready nodes of a datacenter and node
+// class. CPU is in MHz.
+type CapacitySummary struct {
+	Datacenter string
+	NodeClass  string
+	Nodes      int
+
+	// NodesMissingStats is the number of nodes whose utilization could not
+	// be queried and is missing from the used resources.
+	NodesMissingStats int
+
+	Total     CapacityResources
+	Allocated CapacityResources
+	Used      CapacityResources
+}
+
+// CapacityResources is an amount of CPU, memory and disk.
+type CapacityResources struct {
+	CPU      float64
+	MemoryMB uint64
+	DiskMB   uint64
+}
+
+func (r *CapacityResources) add(o CapacityResources) {
+	r.CPU += o.CPU
+	r.MemoryMB += o.MemoryMB
+	r.DiskMB += o.DiskMB
+}
+
+// clusterCapacity returns the capacity of the ready nodes grouped by
+// datacenter and node class. Allocated resources are those of the running
+// allocations while used resources come from the host stats of the nodes.
+func clusterCapacity(client *api.Client) ([]*CapacitySummary, error) {
+	stubs, _, err := client.Nodes().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying nodes: %s", err)
+	}
+
+	groups := make(map[[2]string]*CapacitySummary)
+	for _, stub := range stubs {
+		if stub.Status != "ready" {
+			continue
+		}
+
+		node, _, err := client.Nodes().Info(stub.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying node %q: %s", stub.ID, err)
+		}
+		allocs, err := getRunningAllocs(client, stub.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying allocations of node %q: %s", stub.ID, err)
+		}
+
+		key := [2]string{node.Datacenter, node.NodeClass}
+		group, ok := groups[key]
+		if !ok {
+			group = &CapacitySummary{Datacenter: node.Datacenter, NodeClass: node.NodeClass}
+			groups[key] = group
+		}
+		group.Nodes++
+
+		total := computeNodeTotalResources(node)
+		group.Total.add(CapacityResources{
+			CPU:      float64(*total.CPU),
+			MemoryMB: uint64(*total.MemoryMB),
+			DiskMB:   uint64(*total.DiskMB),
+		})
+
+		for _, alloc := range allocs {
+			group.Allocated.add(CapacityResources{
+				CPU:      float64(*alloc.Resources.CPU),
+				MemoryMB: uint64(*alloc.Resources.MemoryMB),
+				DiskMB:   uint64(*alloc.Resources.DiskMB),
+			})
+		}
+
+		hostStats, err := client.Nodes().Stats(stub.ID, nil)
+		if err != nil || hostStats.Memory == nil {
+			group.NodesMissingStats++
+			continue
+		}
+		used := CapacityResources{
+			CPU:      hostStats.CPUTicksConsumed,
+			MemoryMB: hostStats.Memory.Used / bytesPerMegabyte,
+		}
+		storageDevice := node.Attributes["unique.storage.volume"]
+		for _, disk := range hostStats.DiskStats {
+			if disk.Device == storageDevice {
+				used.DiskMB = disk.Used / bytesPerMegabyte
+			}
+		}
+		group.Used.add(used)
+	}
+
+	summaries := make([]*CapacitySummary, 0, len(groups))
+	for _, group := range groups {
+		summaries = append(summaries, group)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Datacenter != summaries[j].Datacenter {
+			return summaries[i].Datacenter < summaries[j].Datacenter
+		}
+		return summaries[i].NodeClass < summaries[j].NodeClass
+	})
+	return summaries, nil
+}
+
+// formatCapacity returns the capacity of the cluster followed by a table of
+// the capacity summaries.
+func formatCapacity(summaries []*CapacitySummary) string {
+	if len(summaries) == 0 {
+		return "No ready nodes"
+	}
+
+	total := &CapacitySummary{}
+	for _, s := range summaries {
+		total.Nodes += s.Nodes
+		total.NodesMissingStats += s.NodesMissingStats
+		total.Total.add(s.Total)
+		total.Allocated.add(s.Allocated)
+		total.Used.add(s.Used)
+	}
+
+	basic := []string{
+		fmt.Sprintf("Ready Nodes|%d", total.Nodes),
+		fmt.Sprintf("CPU Allocated|%s", formatCapacityCPU(total.Allocated.CPU, total.Total.CPU)),
+		fmt.Sprintf("CPU Used|%s", formatCapacityCPU(total.Used.CPU, total.Total.CPU)),
+		fmt.Sprintf("CPU Total|%v MHz", math.Floor(total.Total.CPU)),
+		fmt.Sprintf("Memory Allocated|%s", formatCapacityBytes(total.Allocated.MemoryMB, total.Total.MemoryMB)),
+		fmt.Sprintf("Memory Used|%s", formatCapacityBytes(total.Used.MemoryMB, total.Total.MemoryMB)),
+		fmt.Sprintf("Memory Total|%s", humanize.IBytes(total.Total.MemoryMB*bytesPerMegabyte)),
+		fmt.Sprintf("Disk Allocated|%s", formatCapacityBytes(total.Allocated.DiskMB, total.Total.DiskMB)),
+		fmt.Sprintf("Disk Used|%s", formatCapacityBytes(total.Used.DiskMB, total.Total.DiskMB)),
+		fmt.Sprintf("Disk Total|%s", humanize.IBytes(total.Total.DiskMB*bytesPerMegabyte)),
+	}
+	out := formatKV(basic)
+
+	rows := make([]string, 0, len(summaries)+1)
+	rows = append(rows, "Datacenter|Node Class|Nodes|CPU Allocated|CPU Used|CPU Total|"+
+		"Memory Allocated|Memory Used|Memory Total|Disk Allocated|Disk Used|Disk Total")
+	for _, s := range summaries {
+		rows = append(rows, formatCapacityRow(s))
+	}
+	out += "\n\n[bold]Datacenters[reset]\n" + formatList(rows)
+
+	if total.NodesMissingStats > 0 {
+		out += fmt.Sprintf("\n\nUtilization of %d node(s) could not be queried and is not included in the used resources",
+			total.NodesMissingStats)
+	}
+	return out
+}
+
+func formatCapacityRow(s *CapacitySummary) string {
+	return fmt.Sprintf("%s|%s|%d|%s|%s|%v MHz|%s|%s|%s|%s|%s|%s",
+		s.Datacenter, s.NodeClass, s.Nodes,
+		formatCapacityCPU(s.Allocated.CPU, s.Total.CPU),
+		formatCapacityCPU(s.Used.CPU, s.Total.CPU),
+		math.Floor(s.Total.CPU),
+		formatCapacityBytes(s.Allocated.MemoryMB, s.Total.MemoryMB),
+		formatCapacityBytes(s.Used.MemoryMB, s.Total.MemoryMB),
+		humanize.IBytes(s.Total.MemoryMB*bytesPerMegabyte),
+		formatCapacityBytes(s.Allocated.DiskMB, s.Total.DiskMB),
+		formatCapacityBytes(s.Used.DiskMB, s.Total.DiskMB),
+		humanize.IBytes(s.Total.DiskMB*bytesPerMegabyte))
+}
+
+func formatCapacityCPU(value, total float64) string {
+	return fmt.Sprintf("%v MHz (%s)", math.Floor(value), formatCapacityPercent(value, total))
+}
+
+func formatCapacityBytes(valueMB, totalMB uint64) string {
+	return fmt.Sprintf("%s (%s)", humanize.IBytes(valueMB*bytesPerMegabyte),
+		formatCapacityPercent(float64(valueMB), float64(totalMB)))
+}
+
+func formatCapacityPercent(value, total float64) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", value/total*100)
+}
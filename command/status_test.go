@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/nomad/command/agent"
@@ -134,6 +135,88 @@ func TestStatusCommand_Run_NodeStatus(t *testing.T) {
 	ui.OutputWriter.Reset()
 }
 
+func TestStatusCommand_Run_Capacity(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+
+	srv, client, url := testServer(t, true, func(c *agent.Config) {
+		c.Client.NodeClass = "batch"
+	})
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &StatusCommand{Meta: Meta{Ui: ui}}
+
+	// Wait for the node to be ready
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 || nodes[0].Status != "ready" {
+			return false, fmt.Errorf("missing ready node")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	if code := cmd.Run([]string{"-address=" + url, "-capacity"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	assert.Contains(out, "Ready Nodes      = 1")
+	assert.Contains(out, "CPU Allocated    = 0 MHz (0%)")
+	assert.Contains(out, "dc1         batch")
+	ui.OutputWriter.Reset()
+
+	// Machine readable output
+	if code := cmd.Run([]string{"-address=" + url, "-capacity", "-t", "{{range .}}{{.Datacenter}}/{{.NodeClass}}/{{.Nodes}}{{end}}"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d; %v", code, ui.ErrorWriter.String())
+	}
+	assert.Equal("dc1/batch/1", strings.TrimSpace(ui.OutputWriter.String()))
+
+	// Arguments are rejected
+	if code := cmd.Run([]string{"-address=" + url, "-capacity", "foo"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	assert.Contains(ui.ErrorWriter.String(), "takes no arguments")
+}
+
+func TestStatusCommand_FormatCapacity(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+
+	assert.Equal("No ready nodes", formatCapacity(nil))
+
+	summaries := []*CapacitySummary{
+		{
+			Datacenter: "dc1",
+			Nodes:      2,
+			Total:      CapacityResources{CPU: 4000, MemoryMB: 8192, DiskMB: 10240},
+			Allocated:  CapacityResources{CPU: 1000, MemoryMB: 2048, DiskMB: 1024},
+			Used:       CapacityResources{CPU: 500.5, MemoryMB: 1024, DiskMB: 5120},
+		},
+		{
+			Datacenter:        "dc2",
+			NodeClass:         "gpu",
+			Nodes:             1,
+			NodesMissingStats: 1,
+			Total:             CapacityResources{CPU: 4000, MemoryMB: 8192, DiskMB: 10240},
+			Allocated:         CapacityResources{CPU: 3000, MemoryMB: 4096, DiskMB: 0},
+		},
+	}
+
+	out := formatCapacity(summaries)
+	assert.Contains(out, "Ready Nodes      = 3")
+	assert.Contains(out, "CPU Allocated    = 4000 MHz (50%)")
+	assert.Contains(out, "Memory Total     = 16 GiB")
+	assert.Contains(out, "dc1         <none>")
+	assert.Contains(out, "500 MHz (13%)")
+	assert.Contains(out, "dc2         gpu")
+	assert.Contains(out, "Utilization of 1 node(s) could not be queried")
+}
+
 func TestStatusCommand_Run_AllocStatus(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
@@ -0,0 +1,107 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type SystemGCCommand struct {
+	Meta
+}
+
+func (c *SystemGCCommand) Help() string {
+	helpText := `
+Usage: nomad system gc [options]
+
+  Initializes a garbage collection of jobs, evaluations, allocations, and nodes.
+  The collection can be restricted to the objects of a namespace or a single
+  job, for example to reclaim a large backlog of dispatched jobs without
+  lowering the cluster wide garbage collection thresholds.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+GC Options:
+
+  -job <job>
+    Only garbage collect the objects belonging to the given job. The job is
+    looked up in the namespace selected by -namespace or NOMAD_NAMESPACE.
+
+  When -namespace is given explicitly, only the objects belonging to that
+  namespace are garbage collected and nodes are not collected.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SystemGCCommand) Synopsis() string {
+	return "Run the system garbage collection process"
+}
+
+func (c *SystemGCCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job": c.Meta.PredictSearch(contexts.Jobs),
+		})
+}
+
+func (c *SystemGCCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *SystemGCCommand) Name() string { return "system gc" }
+
+func (c *SystemGCCommand) Run(args []string) int {
+	var jobID string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&jobID, "job", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Only restrict the collection to a namespace if it was explicitly
+	// requested, or if it is needed to look up the job.
+	namespace := c.Meta.namespace
+	if jobID != "" && namespace == "" {
+		namespace = os.Getenv("NOMAD_NAMESPACE")
+		if namespace == "" {
+			namespace = api.DefaultNamespace
+		}
+	}
+
+	if namespace == "" {
+		err = client.System().GarbageCollect()
+	} else {
+		req := &api.GarbageCollectRequest{
+			Namespace: namespace,
+			JobID:     jobID,
+		}
+		err = client.System().GarbageCollectTarget(req, nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running system garbage-collection: %s", err))
+		return 1
+	}
+	return 0
+}
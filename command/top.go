@@ -0,0 +1,509 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// topRefreshInterval is the default interval at which nomad top queries
+	// the displayed data, which is also refreshed on cluster events.
+	topRefreshInterval = 2 * time.Second
+
+	// topHelp is the key help displayed at the bottom of nomad top
+	topHelp = "up/down: select  enter: details  esc: back  tab: next view  q: quit"
+)
+
+type TopCommand struct {
+	Meta
+}
+
+func (c *TopCommand) Help() string {
+	helpText := `
+Usage: nomad top [options]
+
+  Displays a live dashboard of the cluster in the terminal. The dashboard has
+  three views, switched between with tab:
+
+    * Nodes: the allocated and used CPU and memory of each node. Selecting a
+      node displays the resource usage of its running allocations, and
+      selecting an allocation displays the usage of its tasks.
+
+    * Pending Evaluations: the evaluations waiting to be scheduled, highest
+      priority first.
+
+    * Active Deployments: the progress of the running and paused deployments.
+      Selecting a deployment displays the progress of its task groups.
+
+  The displayed data is refreshed periodically and whenever the event stream
+  reports node, allocation or deployment changes. Rows are selected with the
+  arrow keys or j and k, enter displays their details and escape goes back.
+  Press q to quit.
+
+  When the output is not a terminal, a snapshot of the views is printed
+  instead.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Top Options:
+
+  -interval=<duration>
+    The interval at which the displayed data is refreshed. Defaults to 2s.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TopCommand) Synopsis() string {
+	return "Display a live dashboard of the cluster"
+}
+
+func (c *TopCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-interval": complete.PredictAnything,
+		})
+}
+
+func (c *TopCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *TopCommand) Name() string { return "top" }
+
+func (c *TopCommand) Run(args []string) int {
+	var interval time.Duration
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.DurationVar(&interval, "interval", topRefreshInterval, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if args = flags.Args(); len(args) > 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if interval <= 0 {
+		c.Ui.Error("The -interval must be positive")
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) || !terminal.IsTerminal(int(os.Stdout.Fd())) {
+		return c.snapshot(client)
+	}
+	return c.interactive(client, interval)
+}
+
+// snapshot outputs each view once.
+func (c *TopCommand) snapshot(client *api.Client) int {
+	for i, kind := range topViews {
+		table, err := collectTop(client, &topScreen{kind: kind})
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		if i > 0 {
+			c.Ui.Output("")
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[bold]%s[reset]", kind)))
+		if len(table.Rows) == 0 {
+			c.Ui.Output(fmt.Sprintf("No %s", strings.ToLower(kind.String())))
+			continue
+		}
+		c.Ui.Output(formatList(table.lines()))
+	}
+	return 0
+}
+
+// topResult is the data collected for a screen.
+type topResult struct {
+	screen *topScreen
+	table  *topTable
+	err    error
+}
+
+// interactive runs the dashboard until the user quits.
+func (c *TopCommand) interactive(client *api.Client, interval time.Duration) int {
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	oldState, err := terminal.MakeRaw(stdin)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error configuring terminal: %s", err))
+		return 1
+	}
+	defer terminal.Restore(stdin, oldState)
+
+	// Use the alternate screen so the dashboard does not clobber the
+	// terminal and hide the cursor
+	fmt.Fprint(os.Stdout, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(os.Stdout, "\x1b[?25h\x1b[?1049l")
+
+	keysCh := make(chan []byte)
+	go func() {
+		for {
+			buf := make([]byte, 32)
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			keysCh <- buf[:n]
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Refresh on cluster events. Polling alone is used if the event stream
+	// is unavailable.
+	topics := map[api.Topic][]string{
+		api.TopicNode:       {"*"},
+		api.TopicAllocation: {"*"},
+		api.TopicDeployment: {"*"},
+	}
+	eventsCh, err := client.EventStream().Stream(ctx, topics, 0, nil)
+	if err != nil {
+		eventsCh = nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ui := newTopUI()
+	resultsCh := make(chan topResult, 1)
+	fetching, pending := false, false
+	fetch := func() {
+		if fetching {
+			pending = true
+			return
+		}
+		fetching = true
+		screen := ui.screen()
+		go func() {
+			table, err := collectTop(client, screen)
+			resultsCh <- topResult{screen: screen, table: table, err: err}
+		}()
+	}
+	draw := func() {
+		width, height, err := terminal.GetSize(stdout)
+		if err != nil {
+			width, height = 80, 24
+		}
+		lines := ui.render(client.Address(), width, height)
+		fmt.Fprint(os.Stdout, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+	}
+
+	fetch()
+	draw()
+	for {
+		select {
+		case buf := <-keysCh:
+			switch ui.handleKeys(parseTopKeys(buf)) {
+			case topActionQuit:
+				return 0
+			case topActionFetch:
+				fetch()
+				draw()
+			case topActionDraw:
+				draw()
+			}
+		case <-ticker.C:
+			fetch()
+		case events, ok := <-eventsCh:
+			if !ok {
+				eventsCh = nil
+				continue
+			}
+			if events.Err == nil && !events.IsHeartbeat() {
+				fetch()
+			}
+		case result := <-resultsCh:
+			fetching = false
+			ui.setResult(result)
+			draw()
+			if pending {
+				pending = false
+				fetch()
+			}
+		}
+	}
+}
+
+// topKey is a key pressed in nomad top.
+type topKey int
+
+const (
+	topKeyUp topKey = iota
+	topKeyDown
+	topKeyEnter
+	topKeyBack
+	topKeyTab
+	topKeyQuit
+)
+
+// parseTopKeys returns the keys read from the terminal.
+func parseTopKeys(buf []byte) []topKey {
+	// A lone escape is the escape key rather than the start of a sequence
+	if len(buf) == 1 && buf[0] == 0x1b {
+		return []topKey{topKeyBack}
+	}
+
+	var keys []topKey
+	for i := 0; i < len(buf); i++ {
+		switch b := buf[i]; b {
+		case 0x1b:
+			// Arrow keys are sent as ESC [ A or ESC O A
+			if i+2 < len(buf) && (buf[i+1] == '[' || buf[i+1] == 'O') {
+				switch buf[i+2] {
+				case 'A':
+					keys = append(keys, topKeyUp)
+				case 'B':
+					keys = append(keys, topKeyDown)
+				case 'C':
+					keys = append(keys, topKeyEnter)
+				case 'D':
+					keys = append(keys, topKeyBack)
+				}
+				i += 2
+			}
+		case 'k':
+			keys = append(keys, topKeyUp)
+		case 'j':
+			keys = append(keys, topKeyDown)
+		case '\r', '\n':
+			keys = append(keys, topKeyEnter)
+		case 'h', 0x7f, 0x08:
+			keys = append(keys, topKeyBack)
+		case '\t':
+			keys = append(keys, topKeyTab)
+		case 'q', 0x03:
+			keys = append(keys, topKeyQuit)
+		}
+	}
+	return keys
+}
+
+// topAction is what nomad top does after handling keys.
+type topAction int
+
+const (
+	topActionNone topAction = iota
+	topActionDraw
+	topActionFetch
+	topActionQuit
+)
+
+// topUI is the state of the dashboard.
+type topUI struct {
+	// view is the index of the displayed view in topViews
+	view int
+
+	// stack holds the screens drilled down into from the view
+	stack []*topScreen
+
+	table   *topTable
+	err     error
+	updated time.Time
+}
+
+func newTopUI() *topUI {
+	return &topUI{stack: []*topScreen{{kind: topViews[0]}}}
+}
+
+// screen returns the displayed screen.
+func (u *topUI) screen() *topScreen {
+	return u.stack[len(u.stack)-1]
+}
+
+// handleKeys updates the state for the keys and returns what to do next.
+func (u *topUI) handleKeys(keys []topKey) topAction {
+	action := topActionNone
+	for _, key := range keys {
+		screen := u.screen()
+		switch key {
+		case topKeyQuit:
+			return topActionQuit
+		case topKeyUp:
+			if screen.selected > 0 {
+				screen.selected--
+			}
+			action = maxTopAction(action, topActionDraw)
+		case topKeyDown:
+			if u.table != nil && screen.selected < len(u.table.Rows)-1 {
+				screen.selected++
+			}
+			action = maxTopAction(action, topActionDraw)
+		case topKeyEnter:
+			if u.table == nil || screen.selected >= len(u.table.Rows) {
+				continue
+			}
+			if next := screen.drillDown(u.table.Rows[screen.selected].Key); next != nil {
+				u.stack = append(u.stack, next)
+				u.clear()
+				action = topActionFetch
+			}
+		case topKeyBack:
+			if len(u.stack) > 1 {
+				u.stack = u.stack[:len(u.stack)-1]
+				u.clear()
+				action = topActionFetch
+			}
+		case topKeyTab:
+			u.view = (u.view + 1) % len(topViews)
+			u.stack = []*topScreen{{kind: topViews[u.view]}}
+			u.clear()
+			action = topActionFetch
+		}
+	}
+	return action
+}
+
+func maxTopAction(a, b topAction) topAction {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// clear removes the data of the previous screen.
+func (u *topUI) clear() {
+	u.table = nil
+	u.err = nil
+}
+
+// setResult stores the data collected for the displayed screen, ignoring
+// data collected for screens that were left since.
+func (u *topUI) setResult(result topResult) {
+	screen := u.screen()
+	if result.screen != screen {
+		return
+	}
+
+	u.err = result.err
+	if result.err != nil {
+		return
+	}
+	u.table = result.table
+	u.updated = time.Now()
+	if last := len(u.table.Rows) - 1; screen.selected > last {
+		screen.selected = last
+	}
+	if screen.selected < 0 {
+		screen.selected = 0
+	}
+}
+
+// render returns the lines of the dashboard for a terminal of the given size.
+func (u *topUI) render(address string, width, height int) []string {
+	var lines []string
+	add := func(line, style string) {
+		line = truncateTopLine(line, width)
+		if style != "" {
+			line = style + line + "\x1b[0m"
+		}
+		lines = append(lines, line)
+	}
+
+	title := fmt.Sprintf("nomad top - %s", address)
+	if !u.updated.IsZero() {
+		title += fmt.Sprintf(" - updated %s", u.updated.Format("15:04:05"))
+	}
+	add(title, "\x1b[1m")
+
+	tabs := make([]string, len(topViews))
+	for i, kind := range topViews {
+		if i == u.view {
+			tabs[i] = fmt.Sprintf("[%s]", kind)
+		} else {
+			tabs[i] = fmt.Sprintf(" %s ", kind)
+		}
+	}
+	add(strings.Join(tabs, " "), "")
+
+	crumbs := make([]string, len(u.stack))
+	for i, screen := range u.stack {
+		crumbs[i] = screen.kind.String()
+	}
+	if u.table != nil && u.table.Title != "" {
+		crumbs[len(crumbs)-1] += ": " + u.table.Title
+	}
+	add(strings.Join(crumbs, " > "), "")
+	add("", "")
+
+	// Leave room for the help at the bottom
+	available := height - len(lines) - 2
+
+	switch {
+	case u.err != nil:
+		add(fmt.Sprintf("Error: %s", u.err), "")
+	case u.table == nil:
+		add("Loading...", "")
+	case len(u.table.Rows) == 0:
+		add(fmt.Sprintf("No %s", strings.ToLower(u.screen().kind.String())), "")
+	default:
+		formatted := strings.Split(formatList(u.table.lines()), "\n")
+		add(formatted[0], "\x1b[1m")
+
+		// Scroll so that the selected row is visible
+		rows := formatted[1:]
+		selected := u.screen().selected
+		offset := 0
+		if available > 1 && selected >= available-1 {
+			offset = selected - (available - 2)
+		}
+		for i := offset; i < len(rows) && i-offset < available-1; i++ {
+			style := ""
+			if i == selected {
+				style = "\x1b[7m"
+			}
+			add(rows[i], style)
+		}
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	add(topHelp, "\x1b[2m")
+	return lines
+}
+
+// lines returns the header and the rows of the table for formatList.
+func (t *topTable) lines() []string {
+	lines := make([]string, 0, len(t.Rows)+1)
+	lines = append(lines, t.Header)
+	for _, row := range t.Rows {
+		lines = append(lines, row.Cells)
+	}
+	return lines
+}
+
+// truncateTopLine truncates the line to the width of the terminal.
+func truncateTopLine(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+	runes := []rune(line)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
@@ -0,0 +1,372 @@
+package command
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+)
+
+// topScreenKind is the kind of data a screen of nomad top displays.
+type topScreenKind int
+
+const (
+	topNodes topScreenKind = iota
+	topEvals
+	topDeployments
+	topNodeAllocs
+	topAllocTasks
+	topDeploymentGroups
+)
+
+// topViews are the top level screens of nomad top, switched between with tab.
+var topViews = []topScreenKind{topNodes, topEvals, topDeployments}
+
+func (k topScreenKind) String() string {
+	switch k {
+	case topNodes:
+		return "Nodes"
+	case topEvals:
+		return "Pending Evaluations"
+	case topDeployments:
+		return "Active Deployments"
+	case topNodeAllocs:
+		return "Allocations"
+	case topAllocTasks:
+		return "Tasks"
+	case topDeploymentGroups:
+		return "Task Groups"
+	default:
+		return "Unknown"
+	}
+}
+
+// topScreen is a screen of nomad top. Drilling down into a row pushes the
+// screen of the row on top of the current screen.
+type topScreen struct {
+	kind topScreenKind
+
+	// id is the ID of the node, allocation or deployment the screen
+	// displays the details of
+	id string
+
+	// selected is the index of the selected row
+	selected int
+}
+
+// drillDown returns the screen displaying the details of the row with the
+// given key, or nil if the rows of the screen have no details.
+func (s *topScreen) drillDown(key string) *topScreen {
+	switch s.kind {
+	case topNodes:
+		return &topScreen{kind: topNodeAllocs, id: key}
+	case topNodeAllocs:
+		return &topScreen{kind: topAllocTasks, id: key}
+	case topDeployments:
+		return &topScreen{kind: topDeploymentGroups, id: key}
+	default:
+		return nil
+	}
+}
+
+// topTable is the data of a screen.
+type topTable struct {
+	// Title describes the data, for example the node whose allocations
+	// are displayed.
+	Title string
+
+	Header string
+	Rows   []topRow
+}
+
+// topRow is a row of a table. The key identifies the object of the row when
+// drilling down.
+type topRow struct {
+	Key   string
+	Cells string
+}
+
+// collectTop returns the data of the screen.
+func collectTop(client *api.Client, screen *topScreen) (*topTable, error) {
+	switch screen.kind {
+	case topNodes:
+		return collectTopNodes(client)
+	case topEvals:
+		return collectTopEvals(client)
+	case topDeployments:
+		return collectTopDeployments(client)
+	case topNodeAllocs:
+		return collectTopNodeAllocs(client, screen.id)
+	case topAllocTasks:
+		return collectTopAllocTasks(client, screen.id)
+	case topDeploymentGroups:
+		return collectTopDeploymentGroups(client, screen.id)
+	default:
+		return nil, fmt.Errorf("unknown screen %d", screen.kind)
+	}
+}
+
+// collectTopNodes returns the allocated and used resources of the nodes.
+func collectTopNodes(client *api.Client) (*topTable, error) {
+	stubs, _, err := client.Nodes().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying nodes: %s", err)
+	}
+	sort.Slice(stubs, func(i, j int) bool { return stubs[i].Name < stubs[j].Name })
+
+	table := &topTable{
+		Header: "ID|Name|DC|Class|Status|Allocs|CPU Allocated|CPU Used|Memory Allocated|Memory Used",
+	}
+	for _, stub := range stubs {
+		status := stub.Status
+		if stub.Drain {
+			status += " (draining)"
+		}
+
+		row := topRow{Key: stub.ID}
+		if stub.Status != "ready" {
+			row.Cells = fmt.Sprintf("%s|%s|%s|%s|%s|-|-|-|-|-",
+				limit(stub.ID, shortId), stub.Name, stub.Datacenter, stub.NodeClass, status)
+			table.Rows = append(table.Rows, row)
+			continue
+		}
+
+		node, _, err := client.Nodes().Info(stub.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying node %q: %s", stub.ID, err)
+		}
+		allocs, err := getRunningAllocs(client, stub.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Error querying allocations of node %q: %s", stub.ID, err)
+		}
+
+		total := computeNodeTotalResources(node)
+		var cpu, mem int
+		for _, alloc := range allocs {
+			cpu += *alloc.Resources.CPU
+			mem += *alloc.Resources.MemoryMB
+		}
+		cpuUsed, memUsed := "-", "-"
+		if hostStats, err := client.Nodes().Stats(stub.ID, nil); err == nil && hostStats.Memory != nil {
+			cpuUsed = fmt.Sprintf("%v/%d MHz", math.Floor(hostStats.CPUTicksConsumed), *total.CPU)
+			memUsed = fmt.Sprintf("%s/%s", humanize.IBytes(hostStats.Memory.Used),
+				humanize.IBytes(uint64(*total.MemoryMB*bytesPerMegabyte)))
+		}
+
+		row.Cells = fmt.Sprintf("%s|%s|%s|%s|%s|%d|%d/%d MHz|%s|%s/%s|%s",
+			limit(stub.ID, shortId), stub.Name, stub.Datacenter, stub.NodeClass, status,
+			len(allocs), cpu, *total.CPU, cpuUsed,
+			humanize.IBytes(uint64(mem*bytesPerMegabyte)),
+			humanize.IBytes(uint64(*total.MemoryMB*bytesPerMegabyte)), memUsed)
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// collectTopEvals returns the evaluations waiting to be scheduled, highest
+// priority first.
+func collectTopEvals(client *api.Client) (*topTable, error) {
+	evals, _, err := client.Evaluations().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying evaluations: %s", err)
+	}
+
+	var pending []*api.Evaluation
+	for _, eval := range evals {
+		if eval.Status == "pending" || eval.Status == "blocked" {
+			pending = append(pending, eval)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].Priority != pending[j].Priority {
+			return pending[i].Priority > pending[j].Priority
+		}
+		return pending[i].CreateIndex < pending[j].CreateIndex
+	})
+
+	table := &topTable{
+		Header: "ID|Priority|Type|Triggered By|Job ID|Status|Description",
+	}
+	for _, eval := range pending {
+		table.Rows = append(table.Rows, topRow{
+			Key: eval.ID,
+			Cells: fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s",
+				limit(eval.ID, shortId), eval.Priority, eval.Type, eval.TriggeredBy,
+				eval.JobID, eval.Status, eval.StatusDescription),
+		})
+	}
+	return table, nil
+}
+
+// collectTopDeployments returns the progress of the running and paused
+// deployments.
+func collectTopDeployments(client *api.Client) (*topTable, error) {
+	deployments, _, err := client.Deployments().List(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying deployments: %s", err)
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].CreateIndex > deployments[j].CreateIndex })
+
+	table := &topTable{
+		Header: "ID|Job ID|Job Version|Status|Placed|Healthy|Unhealthy|Desired|Progress",
+	}
+	for _, d := range deployments {
+		if d.Status != "running" && d.Status != "paused" {
+			continue
+		}
+
+		var placed, healthy, unhealthy, desired int
+		for _, state := range d.TaskGroups {
+			placed += state.PlacedAllocs
+			healthy += state.HealthyAllocs
+			unhealthy += state.UnhealthyAllocs
+			desired += state.DesiredTotal
+		}
+		table.Rows = append(table.Rows, topRow{
+			Key: d.ID,
+			Cells: fmt.Sprintf("%s|%s|%d|%s|%d|%d|%d|%d|%s",
+				limit(d.ID, shortId), d.JobID, d.JobVersion, d.Status,
+				placed, healthy, unhealthy, desired, topProgress(healthy, desired)),
+		})
+	}
+	return table, nil
+}
+
+// collectTopNodeAllocs returns the allocated and used resources of the
+// running allocations of a node.
+func collectTopNodeAllocs(client *api.Client, nodeID string) (*topTable, error) {
+	node, _, err := client.Nodes().Info(nodeID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying node %q: %s", nodeID, err)
+	}
+	allocs, err := getRunningAllocs(client, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying allocations of node %q: %s", nodeID, err)
+	}
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i].Name < allocs[j].Name })
+
+	table := &topTable{
+		Title:  fmt.Sprintf("Node %q (%s)", node.Name, limit(node.ID, shortId)),
+		Header: "ID|Job ID|Task Group|Status|CPU|Memory",
+	}
+	for _, alloc := range allocs {
+		cpu, mem := "-", "-"
+		if stats, err := client.Allocations().Stats(alloc, nil); err == nil {
+			cpu, mem = formatTopUsage(stats.ResourceUsage, alloc.Resources)
+		}
+		table.Rows = append(table.Rows, topRow{
+			Key: alloc.ID,
+			Cells: fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+				limit(alloc.ID, shortId), alloc.JobID, alloc.TaskGroup, alloc.ClientStatus, cpu, mem),
+		})
+	}
+	return table, nil
+}
+
+// collectTopAllocTasks returns the state and the used resources of the tasks
+// of an allocation.
+func collectTopAllocTasks(client *api.Client, allocID string) (*topTable, error) {
+	alloc, _, err := client.Allocations().Info(allocID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying allocation %q: %s", allocID, err)
+	}
+	stats, statsErr := client.Allocations().Stats(alloc, nil)
+
+	table := &topTable{
+		Title:  fmt.Sprintf("Allocation %q (%s)", alloc.Name, limit(alloc.ID, shortId)),
+		Header: "Task|State|Restarts|CPU|Memory",
+	}
+
+	tasks := make([]string, 0, len(alloc.TaskStates))
+	for task := range alloc.TaskStates {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+
+	for _, task := range tasks {
+		state := alloc.TaskStates[task]
+		cpu, mem := "-", "-"
+		if statsErr == nil {
+			if usage, ok := stats.Tasks[task]; ok {
+				cpu, mem = formatTopUsage(usage.ResourceUsage, alloc.TaskResources[task])
+			}
+		}
+		table.Rows = append(table.Rows, topRow{
+			Key:   task,
+			Cells: fmt.Sprintf("%s|%s|%d|%s|%s", task, state.State, state.Restarts, cpu, mem),
+		})
+	}
+	return table, nil
+}
+
+// collectTopDeploymentGroups returns the progress of the task groups of a
+// deployment.
+func collectTopDeploymentGroups(client *api.Client, deploymentID string) (*topTable, error) {
+	d, _, err := client.Deployments().Info(deploymentID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying deployment %q: %s", deploymentID, err)
+	}
+
+	table := &topTable{
+		Title:  fmt.Sprintf("Deployment %q of job %q: %s", limit(d.ID, shortId), d.JobID, d.StatusDescription),
+		Header: "Task Group|Promoted|Canaries|Placed|Healthy|Unhealthy|Desired|Progress",
+	}
+
+	groups := make([]string, 0, len(d.TaskGroups))
+	for group := range d.TaskGroups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		state := d.TaskGroups[group]
+		table.Rows = append(table.Rows, topRow{
+			Key: group,
+			Cells: fmt.Sprintf("%s|%v|%d/%d|%d|%d|%d|%d|%s",
+				group, state.Promoted, len(state.PlacedCanaries), state.DesiredCanaries,
+				state.PlacedAllocs, state.HealthyAllocs, state.UnhealthyAllocs, state.DesiredTotal,
+				topProgress(state.HealthyAllocs, state.DesiredTotal)),
+		})
+	}
+	return table, nil
+}
+
+// formatTopUsage returns the used CPU and memory out of the resources of an
+// allocation or task.
+func formatTopUsage(usage *api.ResourceUsage, resources *api.Resources) (string, string) {
+	cpu, mem := "-", "-"
+	if usage == nil {
+		return cpu, mem
+	}
+
+	if usage.CpuStats != nil {
+		cpu = fmt.Sprintf("%v MHz", math.Floor(usage.CpuStats.TotalTicks))
+		if resources != nil && resources.CPU != nil {
+			cpu = fmt.Sprintf("%v/%d MHz", math.Floor(usage.CpuStats.TotalTicks), *resources.CPU)
+		}
+	}
+	if usage.MemoryStats != nil {
+		mem = humanize.IBytes(usage.MemoryStats.RSS)
+		if resources != nil && resources.MemoryMB != nil {
+			mem = fmt.Sprintf("%s/%s", mem, humanize.IBytes(uint64(*resources.MemoryMB*bytesPerMegabyte)))
+		}
+	}
+	return cpu, mem
+}
+
+// topProgress returns a bar of the healthy allocations out of the desired
+// ones.
+func topProgress(healthy, desired int) string {
+	const width = 20
+	if desired <= 0 {
+		return "[" + strings.Repeat(" ", width) + "] 0%"
+	}
+	if healthy > desired {
+		healthy = desired
+	}
+	done := healthy * width / desired
+	return fmt.Sprintf("[%s%s] %d%%", strings.Repeat("=", done), strings.Repeat(" ", width-done), healthy*100/desired)
+}
@@ -0,0 +1,191 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &TopCommand{}
+}
+
+func TestTopCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &TopCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-interval=0s"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "must be positive") {
+		t.Fatalf("expected interval error, got: %s", out)
+	}
+}
+
+func TestTopCommand_Snapshot(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for the node to be ready and run a job on it
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 || nodes[0].Status != "ready" {
+			return false, fmt.Errorf("missing ready node")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	job := testJob("top")
+	job.TaskGroups[0].Tasks[0].Config["run_for"] = "30s"
+	_, _, err := client.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	var alloc *api.AllocationListStub
+	testutil.WaitForResult(func() (bool, error) {
+		allocs, _, err := client.Jobs().Allocations("top", false, nil)
+		if err != nil {
+			return false, err
+		}
+		if len(allocs) == 0 || allocs[0].ClientStatus != "running" {
+			return false, fmt.Errorf("missing running allocation")
+		}
+		alloc = allocs[0]
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// Without a terminal the views are output once
+	ui := new(cli.MockUi)
+	cmd := &TopCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Nodes")
+	require.Contains(out, limit(alloc.NodeID, shortId))
+	require.Contains(out, "No pending evaluations")
+	require.Contains(out, "Active Deployments")
+
+	// Drilling down into the node and the allocation
+	table, err := collectTop(client, &topScreen{kind: topNodeAllocs, id: alloc.NodeID})
+	require.NoError(err)
+	require.Len(table.Rows, 1)
+	require.Equal(alloc.ID, table.Rows[0].Key)
+	require.Contains(table.Rows[0].Cells, "top")
+
+	table, err = collectTop(client, &topScreen{kind: topAllocTasks, id: alloc.ID})
+	require.NoError(err)
+	require.Len(table.Rows, 1)
+	require.Equal("task1", table.Rows[0].Key)
+	require.Contains(table.Rows[0].Cells, "running")
+}
+
+func TestTopCommand_ParseKeys(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.Equal([]topKey{topKeyBack}, parseTopKeys([]byte{0x1b}))
+	require.Equal([]topKey{topKeyUp, topKeyDown, topKeyEnter, topKeyBack},
+		parseTopKeys([]byte("\x1b[A\x1b[B\x1bOC\x1b[D")))
+	require.Equal([]topKey{topKeyUp, topKeyDown, topKeyEnter, topKeyTab, topKeyBack, topKeyQuit, topKeyQuit},
+		parseTopKeys([]byte("kj\r\th q\x03")))
+	require.Empty(parseTopKeys([]byte("x")))
+}
+
+func TestTopCommand_UI(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ui := newTopUI()
+	nodes := ui.screen()
+	require.Equal(topNodes, nodes.kind)
+
+	// Nothing is selected before the data is collected
+	require.Equal(topActionNone, ui.handleKeys([]topKey{topKeyEnter}))
+	require.Contains(strings.Join(ui.render("http://127.0.0.1:4646", 100, 12), "\n"), "Loading...")
+
+	table := &topTable{Header: "ID|Name"}
+	for i := 0; i < 10; i++ {
+		table.Rows = append(table.Rows, topRow{Key: fmt.Sprintf("node-%d", i), Cells: fmt.Sprintf("%d|node-%d", i, i)})
+	}
+	ui.setResult(topResult{screen: nodes, table: table})
+
+	// Selection is bounded by the rows
+	require.Equal(topActionDraw, ui.handleKeys([]topKey{topKeyUp}))
+	require.Equal(0, nodes.selected)
+	ui.handleKeys([]topKey{topKeyDown, topKeyDown, topKeyDown, topKeyDown, topKeyDown, topKeyDown, topKeyDown})
+	require.Equal(7, nodes.selected)
+
+	// The selected row is scrolled into view and highlighted
+	lines := ui.render("http://127.0.0.1:4646", 100, 12)
+	require.Len(lines, 12)
+	require.Contains(lines[0], "nomad top - http://127.0.0.1:4646")
+	require.Contains(lines[1], "[Nodes]")
+	require.Contains(strings.Join(lines, "\n"), "\x1b[7m7   node-7")
+	require.NotContains(strings.Join(lines, "\n"), "node-0")
+	require.Contains(lines[11], topHelp)
+
+	// Drilling down displays the allocations of the node
+	require.Equal(topActionFetch, ui.handleKeys([]topKey{topKeyEnter}))
+	allocs := ui.screen()
+	require.Equal(topNodeAllocs, allocs.kind)
+	require.Equal("node-7", allocs.id)
+	require.Nil(ui.table)
+
+	// Data collected for a previous screen is ignored
+	ui.setResult(topResult{screen: nodes, table: table})
+	require.Nil(ui.table)
+
+	ui.setResult(topResult{screen: allocs, table: &topTable{Title: `Node "node-7"`, Header: "ID"}})
+	out := strings.Join(ui.render("http://127.0.0.1:4646", 100, 12), "\n")
+	require.Contains(out, `Nodes > Allocations: Node "node-7"`)
+	require.Contains(out, "No allocations")
+
+	// Going back restores the selection
+	require.Equal(topActionFetch, ui.handleKeys([]topKey{topKeyBack}))
+	require.Equal(nodes, ui.screen())
+	require.Equal(7, ui.screen().selected)
+	require.Equal(topActionNone, ui.handleKeys([]topKey{topKeyBack}))
+
+	// Tab switches views
+	require.Equal(topActionFetch, ui.handleKeys([]topKey{topKeyTab}))
+	require.Equal(topEvals, ui.screen().kind)
+	ui.handleKeys([]topKey{topKeyTab, topKeyTab})
+	require.Equal(topNodes, ui.screen().kind)
+
+	require.Equal(topActionQuit, ui.handleKeys([]topKey{topKeyDown, topKeyQuit}))
+}
+
+func TestTopCommand_Progress(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.Equal("[                    ] 0%", topProgress(0, 0))
+	require.Equal("[==========          ] 50%", topProgress(2, 4))
+	require.Equal("[====================] 100%", topProgress(5, 4))
+}
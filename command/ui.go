@@ -29,18 +29,30 @@ object. Supported identifiers are jobs, allocations and nodes.
 
 General Options:
 
-  ` + generalOptionsUsage()
+  ` + generalOptionsUsage() + `
+
+UI Options:
+
+  -authenticate
+    Authenticate the Web UI session as the ACL token of the command. A
+    one-time token, which the UI exchanges for the ACL token, is added to
+    the opened URL so the secret ID of the ACL token isn't exposed. The
+    one-time token expires after 10 minutes if it isn't used.
+`
 
 	return strings.TrimSpace(helpText)
 }
 
 func (c *UiCommand) AutocompleteFlags() complete.Flags {
-	return c.Meta.AutocompleteFlags(FlagSetClient)
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-authenticate": complete.PredictNothing,
+		})
 }
 
 func (c *UiCommand) AutocompleteArgs() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string {
-		client, err := c.Meta.Client()
+		client, err := c.Meta.autocompleteClient(a)
 		if err != nil {
 			return nil
 		}
@@ -75,8 +87,11 @@ func (c *UiCommand) Synopsis() string {
 func (c *UiCommand) Name() string { return "ui" }
 
 func (c *UiCommand) Run(args []string) int {
+	var authenticate bool
+
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&authenticate, "authenticate", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -148,7 +163,7 @@ func (c *UiCommand) Run(args []string) int {
 
 		switch match {
 		case contexts.Nodes:
-			url.Path = fmt.Sprintf("ui/nodes/%s", fullID)
+			url.Path = fmt.Sprintf("ui/clients/%s", fullID)
 		case contexts.Allocs:
 			url.Path = fmt.Sprintf("ui/allocations/%s", fullID)
 		case contexts.Jobs:
@@ -160,6 +175,24 @@ func (c *UiCommand) Run(args []string) int {
 	}
 
 	c.Ui.Output(fmt.Sprintf("Opening URL %q", url.String()))
+
+	if authenticate {
+		ott, _, err := client.ACLTokens().UpsertOneTimeToken(nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error creating one-time token: %s", err))
+			return 1
+		}
+
+		// The root path redirects to the UI without keeping the query
+		if url.Path == "" {
+			url.Path = "ui/"
+		}
+		query := url.Query()
+		query.Set("ott", ott.OneTimeSecretID)
+		url.RawQuery = query.Encode()
+		c.Ui.Output("Authenticating the UI with a one-time token")
+	}
+
 	if err := open.Start(url.String()); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error opening URL: %s", err))
 		return 1
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (f *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  are sets of key value pairs stored encrypted by the Nomad servers at a path
+  within a namespace. Tasks can render the variables below the path
+  "nomad/jobs/<job>" of their job with the "nomadVar" template function.
+
+  Create or update a variable:
+
+      $ nomad var put <path> <key>=<value> [<key>=<value>...]
+
+  Read a variable:
+
+      $ nomad var get <path>
+
+  List variables:
+
+      $ nomad var list [<prefix>]
+
+  Delete a variable:
+
+      $ nomad var delete <path>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VarCommand) Synopsis() string {
+	return "Interact with variables"
+}
+
+func (f *VarCommand) Name() string { return "var" }
+
+func (f *VarCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// formatVariable returns the metadata and items of the variable.
+func formatVariable(v *api.Variable) string {
+	out := formatKV([]string{
+		fmt.Sprintf("Namespace|%s", v.Namespace),
+		fmt.Sprintf("Path|%s", v.Path),
+		fmt.Sprintf("Create Time|%s", formatUnixNanoTime(v.CreateTime)),
+		fmt.Sprintf("Modify Time|%s", formatUnixNanoTime(v.ModifyTime)),
+		fmt.Sprintf("Modify Index|%d", v.ModifyIndex),
+	})
+
+	keys := make([]string, 0, len(v.Items))
+	for k := range v.Items {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, len(keys))
+	for i, k := range keys {
+		items[i] = fmt.Sprintf("%s|%s", k, v.Items[k])
+	}
+	return fmt.Sprintf("%s\n\n%s\n%s", out, "[bold]Items[reset]", formatKV(items))
+}
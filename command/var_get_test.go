@@ -0,0 +1,84 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarGetCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarGetCommand{}
+}
+
+func TestVarGetCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails when combining -item with an output format
+	if code := cmd.Run([]string{"-item=password", "-json", "project/db"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "can not be combined") {
+		t.Fatalf("expected flag conflict error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope", "project/db"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error reading variable") {
+		t.Fatalf("expected failed read error, got: %s", out)
+	}
+}
+
+func TestVarGetCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	_, _, err := client.Variables().Upsert(&api.Variable{
+		Path:  "project/db",
+		Items: map[string]string{"password": "hunter2"},
+	}, nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &VarGetCommand{Meta: Meta{Ui: ui}}
+
+	// Read the whole variable
+	if code := cmd.Run([]string{"-address=" + url, "project/db"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	require.Contains(out, "project/db")
+	require.Contains(out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	// Read a single item
+	if code := cmd.Run([]string{"-address=" + url, "-item=password", "project/db"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	require.Equal("hunter2\n", ui.OutputWriter.String())
+
+	// Reading a missing item fails
+	if code := cmd.Run([]string{"-address=" + url, "-item=user", "project/db"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	require.Contains(ui.ErrorWriter.String(), `has no item "user"`)
+}
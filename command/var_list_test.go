@@ -0,0 +1,72 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarListCommand{}
+}
+
+func TestVarListCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &VarListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error listing variables") {
+		t.Fatalf("expected failed list error, got: %s", out)
+	}
+}
+
+func TestVarListCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &VarListCommand{Meta: Meta{Ui: ui}}
+
+	// Listing without variables
+	if code := cmd.Run([]string{"-address=" + url}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	require.Contains(ui.OutputWriter.String(), "No variables found")
+	ui.OutputWriter.Reset()
+
+	for _, path := range []string{"project/db", "other/db"} {
+		_, _, err := client.Variables().Upsert(&api.Variable{
+			Path:  path,
+			Items: map[string]string{"password": "hunter2"},
+		}, nil)
+		require.NoError(err)
+	}
+
+	// Listing by prefix
+	if code := cmd.Run([]string{"-address=" + url, "project"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, ui.ErrorWriter.String())
+	}
+	out := ui.OutputWriter.String()
+	require.Contains(out, "project/db")
+	require.NotContains(out, "other/db")
+	require.NotContains(out, "hunter2")
+}
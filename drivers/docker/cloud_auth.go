@@ -0,0 +1,262 @@
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/helper/awsjson"
+	"github.com/hashicorp/nomad/helper/cloudjson"
+)
+
+const (
+	// cloudRegistryECR, cloudRegistryGCR and cloudRegistryACR are the cloud
+	// registries whose credentials can be exchanged for the identity of the
+	// instance
+	cloudRegistryECR = "ecr"
+	cloudRegistryGCR = "gcr"
+	cloudRegistryACR = "acr"
+
+	// cloudAuthExpiryMargin is how long before their expiry cached
+	// credentials are renewed
+	cloudAuthExpiryMargin = 5 * time.Minute
+
+	// defaultAzureMetadataURL is the address of the Azure instance metadata
+	// service
+	defaultAzureMetadataURL = "http://169.254.169.254/metadata"
+
+	// acrUsername is the username to log in to ACR with a refresh token
+	acrUsername = "00000000-0000-0000-0000-000000000000"
+)
+
+var (
+	// ecrRegistryRe matches the host of an ECR registry, capturing the
+	// account ID and the region
+	ecrRegistryRe = regexp.MustCompile(`^([0-9]{12})\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+)
+
+// validCloudRegistries are the valid values of the auth.cloud_registries
+// plugin option.
+var validCloudRegistries = map[string]struct{}{
+	cloudRegistryECR: {},
+	cloudRegistryGCR: {},
+	cloudRegistryACR: {},
+}
+
+// cloudRegistryAuth exchanges the identity of the instance the client runs on
+// for the credentials of ECR, GCR and Artifact Registry, and ACR registries.
+// Credentials are cached per registry until shortly before they expire.
+type cloudRegistryAuth struct {
+	// awsConfig is the base configuration of the AWS clients, which use the
+	// default credential chain
+	awsConfig *aws.Config
+
+	// gcpMetadataURL and azureMetadataURL are the addresses of the metadata
+	// services providing the instance's access tokens
+	gcpMetadataURL   string
+	azureMetadataURL string
+
+	// acrScheme is the scheme used to reach ACR registries
+	acrScheme string
+
+	httpClient *http.Client
+
+	cache     map[string]*cachedRegistryAuth
+	cacheLock sync.Mutex
+}
+
+type cachedRegistryAuth struct {
+	auth   *docker.AuthConfiguration
+	expiry time.Time
+}
+
+func newCloudRegistryAuth() *cloudRegistryAuth {
+	return &cloudRegistryAuth{
+		awsConfig:        aws.NewConfig(),
+		gcpMetadataURL:   cloudjson.DefaultGCPMetadataURL,
+		azureMetadataURL: defaultAzureMetadataURL,
+		acrScheme:        "https",
+		httpClient:       &http.Client{Timeout: 10 * time.Second},
+		cache:            make(map[string]*cachedRegistryAuth),
+	}
+}
+
+// backend returns an authBackend exchanging the instance identity for the
+// credentials of the repo's registry, if it is one of the given cloud
+// registries.
+func (c *cloudRegistryAuth) backend(registries []string) authBackend {
+	return func(repo string) (*docker.AuthConfiguration, error) {
+		if len(registries) == 0 {
+			return nil, nil
+		}
+		repoInfo, err := parseRepositoryInfo(repo)
+		if err != nil {
+			return nil, err
+		}
+		host := repoInfo.Index.Name
+
+		for _, registry := range registries {
+			var exchange func(string) (*docker.AuthConfiguration, time.Time, error)
+			switch {
+			case registry == cloudRegistryECR && ecrRegistryRe.MatchString(host):
+				exchange = c.ecrAuth
+			case registry == cloudRegistryGCR && isGCRHost(host):
+				exchange = c.gcrAuth
+			case registry == cloudRegistryACR && strings.HasSuffix(host, ".azurecr.io"):
+				exchange = c.acrAuth
+			default:
+				continue
+			}
+			return c.cached(registry+"/"+host, host, exchange)
+		}
+		return nil, nil
+	}
+}
+
+// cached returns the cached credentials of the registry, exchanging new ones
+// if there are none or they are about to expire.
+func (c *cloudRegistryAuth) cached(key, host string, exchange func(string) (*docker.AuthConfiguration, time.Time, error)) (*docker.AuthConfiguration, error) {
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+
+	if cached, ok := c.cache[key]; ok && time.Now().Add(cloudAuthExpiryMargin).Before(cached.expiry) {
+		return cached.auth, nil
+	}
+
+	auth, expiry, err := exchange(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials of registry %q: %v", host, err)
+	}
+	c.cache[key] = &cachedRegistryAuth{auth: auth, expiry: expiry}
+	return auth, nil
+}
+
+// isGCRHost returns whether the host is a Container Registry or Artifact
+// Registry host.
+func isGCRHost(host string) bool {
+	return host == "gcr.io" || strings.HasSuffix(host, ".gcr.io") || strings.HasSuffix(host, "-docker.pkg.dev")
+}
+
+// ecrAuth returns the credentials of an ECR registry, using the default AWS
+// credential chain.
+func (c *cloudRegistryAuth) ecrAuth(host string) (*docker.AuthConfiguration, time.Time, error) {
+	m := ecrRegistryRe.FindStringSubmatch(host)
+	if m == nil {
+		return nil, time.Time{}, fmt.Errorf("not an ECR registry")
+	}
+	accountID, region := m[1], m[2]
+
+	in := &struct {
+		RegistryIds []string `json:"registryIds"`
+	}{[]string{accountID}}
+	var out struct {
+		AuthorizationData []struct {
+			AuthorizationToken string  `json:"authorizationToken"`
+			ExpiresAt          float64 `json:"expiresAt"`
+		} `json:"authorizationData"`
+	}
+	cfg := c.awsConfig.Copy().WithRegion(region)
+	target := "AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken"
+	if err := awsjson.Call(cfg, "ecr", target, in, &out); err != nil {
+		return nil, time.Time{}, err
+	}
+	if len(out.AuthorizationData) == 0 {
+		return nil, time.Time{}, fmt.Errorf("no authorization data returned")
+	}
+
+	data := out.AuthorizationData[0]
+	token, err := base64.StdEncoding.DecodeString(data.AuthorizationToken)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to decode authorization token: %v", err)
+	}
+	parts := strings.SplitN(string(token), ":", 2)
+	if len(parts) != 2 {
+		return nil, time.Time{}, fmt.Errorf("invalid authorization token")
+	}
+
+	auth := &docker.AuthConfiguration{
+		Username:      parts[0],
+		Password:      parts[1],
+		ServerAddress: host,
+	}
+	return auth, time.Unix(int64(data.ExpiresAt), 0), nil
+}
+
+// gcrAuth returns the credentials of a Container Registry or Artifact
+// Registry host, using the access token of the default service account of
+// the GCE instance.
+func (c *cloudRegistryAuth) gcrAuth(host string) (*docker.AuthConfiguration, time.Time, error) {
+	token, expiry, err := cloudjson.GCPInstanceToken(c.httpClient, c.gcpMetadataURL)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	auth := &docker.AuthConfiguration{
+		Username:      "oauth2accesstoken",
+		Password:      token,
+		ServerAddress: host,
+	}
+	return auth, expiry, nil
+}
+
+// acrAuth returns the credentials of an ACR registry, by exchanging the
+// access token of the managed identity of the Azure instance for a registry
+// refresh token.
+func (c *cloudRegistryAuth) acrAuth(host string) (*docker.AuthConfiguration, time.Time, error) {
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {"https://management.azure.com/"},
+	}
+	req, err := http.NewRequest("GET", c.azureMetadataURL+"/identity/oauth2/token?"+query.Encode(), nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req.Header.Set("Metadata", "true")
+
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if err := cloudjson.Do(c.httpClient, req, &token); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get access token: %v", err)
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid access token expiry %q", token.ExpiresIn)
+	}
+
+	form := url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {host},
+		"access_token": {token.AccessToken},
+	}
+	req, err = http.NewRequest("POST", c.acrScheme+"://"+host+"/oauth2/exchange", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var exchange struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := cloudjson.Do(c.httpClient, req, &exchange); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to exchange access token: %v", err)
+	}
+
+	// The refresh token is valid for longer than the access token it was
+	// exchanged for
+	auth := &docker.AuthConfiguration{
+		Username:      acrUsername,
+		Password:      exchange.RefreshToken,
+		ServerAddress: host,
+	}
+	return auth, time.Now().Add(time.Duration(expiresIn) * time.Second), nil
+}
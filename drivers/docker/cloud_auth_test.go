@@ -0,0 +1,117 @@
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudRegistryAuth_Backend(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var lock sync.Mutex
+	tokens := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal("Google", req.Header.Get("Metadata-Flavor"))
+		lock.Lock()
+		tokens++
+		lock.Unlock()
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`)
+	}))
+	defer srv.Close()
+
+	c := newCloudRegistryAuth()
+	c.gcpMetadataURL = srv.URL
+	backend := c.backend([]string{cloudRegistryGCR})
+
+	// Registries that aren't enabled are skipped
+	auth, err := backend("123456789012.dkr.ecr.us-east-1.amazonaws.com/app")
+	require.NoError(err)
+	require.Nil(auth)
+
+	auth, err = backend("us-docker.pkg.dev/project/repo/app")
+	require.NoError(err)
+	require.Equal("oauth2accesstoken", auth.Username)
+	require.Equal("token", auth.Password)
+
+	// The credentials are cached
+	_, err = backend("us-docker.pkg.dev/project/repo/app")
+	require.NoError(err)
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Equal(1, tokens)
+}
+
+func TestCloudRegistryAuth_ECR(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	expiresAt := time.Now().Add(12 * time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal("AmazonEC2ContainerRegistry_V20150921.GetAuthorizationToken", req.Header.Get("X-Amz-Target"))
+		require.Contains(req.Header.Get("Authorization"), "/us-west-2/ecr/")
+
+		var in struct {
+			RegistryIds []string `json:"registryIds"`
+		}
+		require.NoError(json.NewDecoder(req.Body).Decode(&in))
+		require.Equal([]string{"123456789012"}, in.RegistryIds)
+
+		token := base64.StdEncoding.EncodeToString([]byte("AWS:password"))
+		fmt.Fprintf(w, `{"authorizationData":[{"authorizationToken":%q,"expiresAt":%d}]}`, token, expiresAt)
+	}))
+	defer srv.Close()
+
+	c := newCloudRegistryAuth()
+	c.awsConfig = aws.NewConfig().
+		WithEndpoint(srv.URL).
+		WithCredentials(credentials.NewStaticCredentials("AKIAEXAMPLE", "secret", ""))
+
+	auth, expiry, err := c.ecrAuth("123456789012.dkr.ecr.us-west-2.amazonaws.com")
+	require.NoError(err)
+	require.Equal("AWS", auth.Username)
+	require.Equal("password", auth.Password)
+	require.Equal(expiresAt, expiry.Unix())
+}
+
+func TestCloudRegistryAuth_ACR(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	imds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal("true", req.Header.Get("Metadata"))
+		require.Equal("/identity/oauth2/token", req.URL.Path)
+		fmt.Fprint(w, `{"access_token":"aad","expires_in":"3599","token_type":"Bearer"}`)
+	}))
+	defer imds.Close()
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal("/oauth2/exchange", req.URL.Path)
+		require.NoError(req.ParseForm())
+		require.Equal("access_token", req.Form.Get("grant_type"))
+		require.Equal("aad", req.Form.Get("access_token"))
+		fmt.Fprint(w, `{"refresh_token":"refresh"}`)
+	}))
+	defer registry.Close()
+
+	c := newCloudRegistryAuth()
+	c.azureMetadataURL = imds.URL
+	c.acrScheme = "http"
+
+	auth, _, err := c.acrAuth(strings.TrimPrefix(registry.URL, "http://"))
+	require.NoError(err)
+	require.Equal(acrUsername, auth.Username)
+	require.Equal("refresh", auth.Password)
+}
@@ -59,6 +59,12 @@ func PluginLoader(opts map[string]string) (map[string]interface{}, error) {
 	if v, ok := opts["docker.auth.helper"]; ok {
 		authConf["helper"] = v
 	}
+	if v, ok := opts["docker.auth.helpers"]; ok {
+		authConf["helpers"] = strings.Split(v, ",")
+	}
+	if v, ok := opts["docker.auth.cloud_registries"]; ok {
+		authConf["cloud_registries"] = strings.Split(v, ",")
+	}
 	conf["auth"] = authConf
 
 	// dockerd tls
@@ -143,6 +149,8 @@ var (
 	//		auth {
 	//			config = "/etc/docker-auth.json"
 	//			helper = "docker-credential-aws"
+	//			helpers = ["ecr-login", "gcr"]
+	//			cloud_registries = ["ecr", "gcr", "acr"]
 	//		}
 	//		tls {
 	//			cert = "/etc/nomad/nomad.pub"
@@ -160,6 +168,7 @@ var (
 	//		}
 	//		allow_privileged = false
 	//		allow_caps = ["CHOWN", "NET_RAW" ... ]
+	//		allow_host_network = true
 	//		nvidia_runtime = "nvidia"
 	//		}
 	//	}
@@ -168,8 +177,10 @@ var (
 
 		// docker daemon auth option for image registry
 		"auth": hclspec.NewBlock("auth", false, hclspec.NewObject(map[string]*hclspec.Spec{
-			"config": hclspec.NewAttr("config", "string", false),
-			"helper": hclspec.NewAttr("helper", "string", false),
+			"config":           hclspec.NewAttr("config", "string", false),
+			"helper":           hclspec.NewAttr("helper", "string", false),
+			"helpers":          hclspec.NewAttr("helpers", "list(string)", false),
+			"cloud_registries": hclspec.NewAttr("cloud_registries", "list(string)", false),
 		})),
 
 		// client tls options
@@ -212,10 +223,18 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(`["CHOWN","DAC_OVERRIDE","FSETID","FOWNER","MKNOD","NET_RAW","SETGID","SETUID","SETFCAP","SETPCAP","NET_BIND_SERVICE","SYS_CHROOT","KILL","AUDIT_WRITE"]`),
 		),
+		"allow_host_network": hclspec.NewDefault(
+			hclspec.NewAttr("allow_host_network", "bool", false),
+			hclspec.NewLiteral("true"),
+		),
 		"nvidia_runtime": hclspec.NewDefault(
 			hclspec.NewAttr("nvidia_runtime", "string", false),
 			hclspec.NewLiteral(`"nvidia"`),
 		),
+		"infra_image": hclspec.NewDefault(
+			hclspec.NewAttr("infra_image", "string", false),
+			hclspec.NewLiteral(`"gcr.io/google_containers/pause-amd64:3.0"`),
+		),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -477,19 +496,31 @@ type DockerVolumeDriverConfig struct {
 }
 
 type DriverConfig struct {
-	Endpoint        string       `codec:"endpoint"`
-	Auth            AuthConfig   `codec:"auth"`
-	TLS             TLSConfig    `codec:"tls"`
-	GC              GCConfig     `codec:"gc"`
-	Volumes         VolumeConfig `codec:"volumes"`
-	AllowPrivileged bool         `codec:"allow_privileged"`
-	AllowCaps       []string     `codec:"allow_caps"`
-	GPURuntimeName  string       `codec:"nvidia_runtime"`
+	Endpoint         string       `codec:"endpoint"`
+	Auth             AuthConfig   `codec:"auth"`
+	TLS              TLSConfig    `codec:"tls"`
+	GC               GCConfig     `codec:"gc"`
+	Volumes          VolumeConfig `codec:"volumes"`
+	AllowPrivileged  bool         `codec:"allow_privileged"`
+	AllowCaps        []string     `codec:"allow_caps"`
+	AllowHostNetwork bool         `codec:"allow_host_network"`
+	GPURuntimeName   string       `codec:"nvidia_runtime"`
+
+	// InfraImage is the image of the containers holding the IPC namespace
+	// and /dev/shm shared by the tasks of an allocation
+	InfraImage string `codec:"infra_image"`
 }
 
 type AuthConfig struct {
 	Config string `codec:"config"`
 	Helper string `codec:"helper"`
+
+	// Helpers are credential helpers tried in order after Helper
+	Helpers []string `codec:"helpers"`
+
+	// CloudRegistries are the cloud registries whose credentials are
+	// exchanged for the identity of the instance
+	CloudRegistries []string `codec:"cloud_registries"`
 }
 
 type TLSConfig struct {
@@ -526,6 +557,12 @@ func (d *Driver) SetConfig(c *base.Config) error {
 		}
 	}
 
+	for _, registry := range config.Auth.CloudRegistries {
+		if _, ok := validCloudRegistries[registry]; !ok {
+			return fmt.Errorf("invalid cloud registry %q; must be one of ecr, gcr or acr", registry)
+		}
+	}
+
 	d.config = &config
 	if len(d.config.GC.ImageDelay) > 0 {
 		dur, err := time.ParseDuration(d.config.GC.ImageDelay)
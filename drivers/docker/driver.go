@@ -58,6 +58,13 @@ var (
 	taskHandleVersion = 1
 )
 
+const (
+	// hugePages2MBPath and hugePages1GBPath are where the huge page file
+	// systems of each page size are mounted on the host and in containers
+	hugePages2MBPath = "/dev/hugepages"
+	hugePages1GBPath = "/dev/hugepages1G"
+)
+
 type Driver struct {
 	// eventer is used to handle multiplexing of TaskEvents calls such that an
 	// event can be broadcast to all callers
@@ -85,6 +92,13 @@ type Driver struct {
 	// coordinator is what tracks multiple image pulls against the same docker image
 	coordinator *dockerCoordinator
 
+	// sharedMemory tracks the containers holding the IPC namespace and
+	// /dev/shm shared by the tasks of allocations
+	sharedMemory *sharedMemoryStore
+
+	// cloudAuth exchanges the instance identity for cloud registry credentials
+	cloudAuth *cloudRegistryAuth
+
 	// logger will log to the Nomad agent
 	logger hclog.Logger
 
@@ -110,6 +124,8 @@ func NewDockerDriver(logger hclog.Logger) drivers.DriverPlugin {
 		eventer:        eventer.NewEventer(ctx, logger),
 		config:         &DriverConfig{},
 		tasks:          newTaskStore(),
+		sharedMemory:   newSharedMemoryStore(),
+		cloudAuth:      newCloudRegistryAuth(),
 		ctx:            ctx,
 		signalShutdown: cancel,
 		logger:         logger,
@@ -194,6 +210,12 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 		net:                   handleState.DriverNetwork,
 	}
 
+	if handle.Config.SharedMemoryMB > 0 {
+		if err := d.restoreSharedMemory(handle.Config); err != nil {
+			return err
+		}
+	}
+
 	h.dlogger, h.dloggerPluginClient, err = d.reattachToDockerLogger(handleState.ReattachConfig)
 	if err != nil {
 		d.logger.Warn("failed to reattach to docker logger process", "error", err)
@@ -203,6 +225,7 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 			if err := client.StopContainer(handleState.ContainerID, 0); err != nil {
 				d.logger.Warn("failed to stop container during cleanup", "container_id", handleState.ContainerID, "error", err)
 			}
+			d.releaseSharedMemory(handle.Config)
 			return fmt.Errorf("failed to setup replacement docker logger: %v", err)
 		}
 
@@ -210,6 +233,7 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 			if err := client.StopContainer(handleState.ContainerID, 0); err != nil {
 				d.logger.Warn("failed to stop container during cleanup", "container_id", handleState.ContainerID, "error", err)
 			}
+			d.releaseSharedMemory(handle.Config)
 			return fmt.Errorf("failed to store driver state: %v", err)
 		}
 	}
@@ -252,11 +276,24 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("Failed to create container configuration for image %q (%q): %v", driverConfig.Image, id, err)
 	}
 
+	// Join the IPC namespace and /dev/shm shared by the allocation
+	if cfg.SharedMemoryMB > 0 {
+		if driverConfig.IPCMode != "" || driverConfig.ShmSize != 0 {
+			return nil, nil, fmt.Errorf("ipc_mode and shm_size can't be set when the task group shares memory")
+		}
+		shmID, err := d.acquireSharedMemory(cfg)
+		if err != nil {
+			return nil, nil, nstructs.WrapRecoverable(err.Error(), err)
+		}
+		containerCfg.HostConfig.IpcMode = "container:" + shmID
+	}
+
 	startAttempts := 0
 CREATE:
 	container, err := d.createContainer(client, containerCfg, &driverConfig)
 	if err != nil {
 		d.logger.Error("failed to create container", "error", err)
+		d.releaseSharedMemory(cfg)
 		return nil, nil, nstructs.WrapRecoverable(fmt.Sprintf("failed to create container: %v", err), err)
 	}
 
@@ -279,6 +316,7 @@ CREATE:
 				d.logger.Debug("reattempting container create/start sequence", "attempt", startAttempts, "container_id", id)
 				goto CREATE
 			}
+			d.releaseSharedMemory(cfg)
 			return nil, nil, nstructs.WrapRecoverable(fmt.Sprintf("Failed to start container %s: %s", container.ID, err), err)
 		}
 
@@ -289,6 +327,7 @@ CREATE:
 		if err != nil {
 			msg := "failed to inspect started container"
 			d.logger.Error(msg, "error", err)
+			d.releaseSharedMemory(cfg)
 			return nil, nil, nstructs.NewRecoverableError(fmt.Errorf("%s %s: %s", msg, container.ID, err), true)
 		}
 		container = runningContainer
@@ -302,6 +341,7 @@ CREATE:
 	if err != nil {
 		d.logger.Error("an error occurred after container startup, terminating container", "container_id", container.ID)
 		client.RemoveContainer(docker.RemoveContainerOptions{ID: container.ID, Force: true})
+		d.releaseSharedMemory(cfg)
 		return nil, nil, err
 	}
 
@@ -335,6 +375,7 @@ CREATE:
 		dlogger.Stop()
 		pluginClient.Kill()
 		client.RemoveContainer(docker.RemoveContainerOptions{ID: container.ID, Force: true})
+		d.releaseSharedMemory(cfg)
 		return nil, nil, err
 	}
 
@@ -534,6 +575,47 @@ func (d *Driver) pullImage(task *drivers.TaskConfig, driverConfig *TaskConfig, c
 	return d.coordinator.PullImage(driverConfig.Image, authOptions, task.ID, d.emitEventFunc(task))
 }
 
+// PrefetchImage pulls the image ahead of the tasks using it, keeping a
+// reference to it for the caller so it isn't garbage collected. Only the
+// registry authentication of the plugin configuration is used.
+func (d *Driver) PrefetchImage(image, callerID string) error {
+	client, _, err := d.dockerClients()
+	if err != nil {
+		return fmt.Errorf("Failed to connect to docker daemon: %s", err)
+	}
+
+	repo, tag := parseDockerImage(image)
+	if tag != "latest" {
+		if dockerImage, _ := client.InspectImage(image); dockerImage != nil {
+			d.coordinator.IncrementImageReference(dockerImage.ID, image, callerID)
+			return nil
+		}
+	}
+
+	authOptions, err := d.resolveRegistryAuthentication(&TaskConfig{Image: image}, repo)
+	if err != nil {
+		return fmt.Errorf("Failed to find docker auth for repo %q: %v", repo, err)
+	}
+
+	d.logger.Debug("prefetching image", "image_ref", dockerImageRef(repo, tag))
+	_, err = d.coordinator.PullImage(image, authOptions, callerID, func(string, map[string]string) {})
+	return err
+}
+
+// ReleaseImage releases the reference of the caller to a prefetched image,
+// removing the image once it's no longer used.
+func (d *Driver) ReleaseImage(image, callerID string) {
+	client, _, err := d.dockerClients()
+	if err != nil {
+		return
+	}
+	dockerImage, err := client.InspectImage(image)
+	if err != nil {
+		return
+	}
+	d.coordinator.RemoveImage(dockerImage.ID, callerID)
+}
+
 func (d *Driver) emitEventFunc(task *drivers.TaskConfig) LogEventFn {
 	return func(msg string, annotations map[string]string) {
 		d.eventer.EmitEvent(&drivers.TaskEvent{
@@ -553,11 +635,17 @@ type authBackend func(string) (*docker.AuthConfiguration, error)
 // resolveRegistryAuthentication attempts to retrieve auth credentials for the
 // repo, trying all authentication-backends possible.
 func (d *Driver) resolveRegistryAuthentication(driverConfig *TaskConfig, repo string) (*docker.AuthConfiguration, error) {
-	return firstValidAuth(repo, []authBackend{
+	backends := []authBackend{
 		authFromTaskConfig(driverConfig),
 		authFromDockerConfig(d.config.Auth.Config),
 		authFromHelper(d.config.Auth.Helper),
-	})
+	}
+	for _, helper := range d.config.Auth.Helpers {
+		backends = append(backends, authFromHelper(helper))
+	}
+	backends = append(backends, d.cloudAuth.backend(d.config.Auth.CloudRegistries))
+
+	return firstValidAuth(repo, backends)
 }
 
 // loadImage creates an image by loading it from the file system
@@ -661,8 +749,9 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 	}
 
 	hostConfig := &docker.HostConfig{
-		Memory:    task.Resources.LinuxResources.MemoryLimitBytes,
-		CPUShares: task.Resources.LinuxResources.CPUShares,
+		Memory:     task.Resources.LinuxResources.MemoryLimitBytes,
+		CPUShares:  task.Resources.LinuxResources.CPUShares,
+		CPUSetCPUs: task.Resources.LinuxResources.CpusetCPUs,
 
 		// Binds are used to mount a host volume into the container. We mount a
 		// local directory for storage and a shared alloc directory that can be
@@ -718,7 +807,8 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 
 	logger.Debug("configured resources", "memory", hostConfig.Memory,
 		"cpu_shares", hostConfig.CPUShares, "cpu_quota", hostConfig.CPUQuota,
-		"cpu_period", hostConfig.CPUPeriod)
+		"cpu_period", hostConfig.CPUPeriod, "cpuset_cpus", hostConfig.CPUSetCPUs,
+		"cpu_rt_runtime", task.Resources.LinuxResources.CPURealtimeRuntime)
 	logger.Debug("binding directories", "binds", hclog.Fmt("%#v", hostConfig.Binds))
 
 	//  set privileged mode
@@ -759,6 +849,25 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 	hostConfig.CapAdd = driverConfig.CapAdd
 	hostConfig.CapDrop = driverConfig.CapDrop
 
+	// Give the task its real-time runtime and the capability to use the
+	// real-time scheduling policies. The Docker daemon must have been given
+	// enough real-time runtime with its --cpu-rt-runtime option.
+	if lr := task.Resources.LinuxResources; lr.CPURealtimeRuntime > 0 {
+		hostConfig.CPURealtimePeriod = lr.CPURealtimePeriod
+		hostConfig.CPURealtimeRuntime = lr.CPURealtimeRuntime
+		hostConfig.CapAdd = append(hostConfig.CapAdd, "SYS_NICE")
+	}
+
+	// Mount the huge page file systems of the host. Docker can't limit the
+	// huge pages of containers, so tasks are trusted to only use the ones
+	// they reserved.
+	if task.Resources.LinuxResources.HugePages2MB > 0 {
+		hostConfig.Binds = append(hostConfig.Binds, hugePages2MBPath+":"+hugePages2MBPath)
+	}
+	if task.Resources.LinuxResources.HugePages1GB > 0 {
+		hostConfig.Binds = append(hostConfig.Binds, hugePages1GBPath+":"+hugePages1GBPath)
+	}
+
 	// set SHM size
 	if driverConfig.ShmSize != 0 {
 		hostConfig.ShmSize = driverConfig.ShmSize
@@ -836,6 +945,11 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 
 	hostConfig.ReadonlyRootfs = driverConfig.ReadonlyRootfs
 
+	// set host network mode
+	if driverConfig.NetworkMode == "host" && !d.config.AllowHostNetwork {
+		return c, fmt.Errorf(`Docker host network mode is disabled on this Nomad agent`)
+	}
+
 	hostConfig.NetworkMode = driverConfig.NetworkMode
 	if hostConfig.NetworkMode == "" {
 		// docker default
@@ -1098,6 +1212,7 @@ func (d *Driver) DestroyTask(taskID string, force bool) error {
 			"error", err)
 	}
 
+	d.releaseSharedMemory(h.task)
 	d.tasks.Delete(taskID)
 	return nil
 }
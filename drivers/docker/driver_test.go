@@ -943,6 +943,62 @@ func TestDockerDriver_CreateContainerConfig(t *testing.T) {
 	require.Equal(t, containerName, c.Name)
 }
 
+func TestDockerDriver_CreateContainerConfig_Cpuset(t *testing.T) {
+	t.Parallel()
+
+	task, cfg, _ := dockerTask(t)
+	task.Resources.LinuxResources.CpusetCPUs = "1,3"
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+	require.Equal(t, "1,3", c.HostConfig.CPUSetCPUs)
+}
+
+func TestDockerDriver_CreateContainerConfig_RealtimeHugePages(t *testing.T) {
+	t.Parallel()
+
+	task, cfg, _ := dockerTask(t)
+	task.Resources.LinuxResources.CPURealtimeRuntime = 50000
+	task.Resources.LinuxResources.CPURealtimePeriod = 1000000
+	task.Resources.LinuxResources.HugePages2MB = 64
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+	require.EqualValues(t, 50000, c.HostConfig.CPURealtimeRuntime)
+	require.EqualValues(t, 1000000, c.HostConfig.CPURealtimePeriod)
+	require.Contains(t, c.HostConfig.CapAdd, "SYS_NICE")
+	require.Contains(t, c.HostConfig.Binds, "/dev/hugepages:/dev/hugepages")
+	require.NotContains(t, c.HostConfig.Binds, "/dev/hugepages1G:/dev/hugepages1G")
+}
+
+func TestDockerDriver_CreateContainerConfig_HostNetwork(t *testing.T) {
+	t.Parallel()
+
+	task, cfg, _ := dockerTask(t)
+	cfg.NetworkMode = "host"
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.NoError(t, err)
+	require.Equal(t, "host", c.HostConfig.NetworkMode)
+
+	driver.config.AllowHostNetwork = false
+	_, err = driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "host network mode is disabled")
+}
+
 func TestDockerDriver_CreateContainerConfig_Logging(t *testing.T) {
 	t.Parallel()
 
@@ -1627,6 +1683,87 @@ func TestDockerDriver_Mounts(t *testing.T) {
 	}
 }
 
+func TestDockerDriver_SharedMemory(t *testing.T) {
+	if !tu.IsCI() {
+		t.Parallel()
+	}
+	// This test requires that the alloc dir be mounted into docker as a volume
+	if !testutil.DockerIsConnected(t) || dockerIsRemote(t) {
+		t.Skip("Docker not connected")
+	}
+
+	d := dockerDriverHarness(t, nil)
+	allocID := uuid.Generate()
+
+	// The server writes to the shared memory and the client reads it
+	server, serverCfg, _ := dockerTask(t)
+	server.Name = "server"
+	server.AllocID = allocID
+	server.SharedMemoryMB = 32
+	serverCfg.Command = "sh"
+	serverCfg.Args = []string{"-c", "echo -n hello > /dev/shm/greeting; sleep 10000"}
+	require.NoError(t, server.EncodeConcreteDriverConfig(serverCfg))
+	cleanup := d.MkAllocDir(server, true)
+	defer cleanup()
+	copyImage(t, server.TaskDir(), "busybox.tar")
+
+	_, _, err := d.StartTask(server)
+	require.NoError(t, err)
+	defer d.DestroyTask(server.ID, true)
+
+	client, clientCfg, _ := dockerTask(t)
+	client.Name = "client"
+	client.AllocID = allocID
+	client.SharedMemoryMB = 32
+	clientCfg.Command = "sh"
+	clientCfg.Args = []string{"-c", fmt.Sprintf("until [ -f /dev/shm/greeting ]; do sleep 0.1; done; cat /dev/shm/greeting > $%s/greeting",
+		taskenv.AllocDir)}
+	require.NoError(t, client.EncodeConcreteDriverConfig(clientCfg))
+	cleanup = d.MkAllocDir(client, true)
+	defer cleanup()
+	copyImage(t, client.TaskDir(), "busybox.tar")
+
+	_, _, err = d.StartTask(client)
+	require.NoError(t, err)
+
+	waitCh, err := d.WaitTask(context.Background(), client.ID)
+	require.NoError(t, err)
+	select {
+	case res := <-waitCh:
+		require.True(t, res.Successful(), "task should have exited successfully: %v", res)
+	case <-time.After(time.Duration(tu.TestMultiplier()*10) * time.Second):
+		require.Fail(t, "timeout")
+	}
+
+	greeting, err := ioutil.ReadFile(filepath.Join(client.TaskDir().SharedAllocDir, "greeting"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(greeting))
+
+	// The shared memory is removed along with the last task
+	dockerClient := newTestDockerClient(t)
+	shm, err := dockerClient.InspectContainer(sharedMemoryContainerName(allocID))
+	require.NoError(t, err)
+	require.EqualValues(t, 32*1024*1024, shm.HostConfig.ShmSize)
+
+	require.NoError(t, d.DestroyTask(client.ID, true))
+	require.NoError(t, d.DestroyTask(server.ID, true))
+	_, err = dockerClient.InspectContainer(shm.ID)
+	require.Error(t, err)
+
+	// Tasks sharing memory can't set their own
+	task, cfg, _ := dockerTask(t)
+	task.SharedMemoryMB = 32
+	cfg.IPCMode = "host"
+	require.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+	cleanup = d.MkAllocDir(task, true)
+	defer cleanup()
+	copyImage(t, task.TaskDir(), "busybox.tar")
+
+	_, _, err = d.StartTask(task)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "ipc_mode and shm_size can't be set")
+}
+
 func TestDockerDriver_AuthConfiguration(t *testing.T) {
 	if !tu.IsCI() {
 		t.Parallel()
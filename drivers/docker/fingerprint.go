@@ -113,6 +113,11 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		fp.Attributes["driver.docker.privileged.enabled"] = pstructs.NewBoolAttribute(true)
 	}
 
+	// The allowed capabilities and host networking are used by the servers to
+	// only place tasks on the nodes that permit them
+	fp.Attributes["driver.docker.caps"] = pstructs.NewStringAttribute(allowedCaps(d.config.AllowCaps))
+	fp.Attributes["driver.docker.host_network.enabled"] = pstructs.NewBoolAttribute(d.config.AllowHostNetwork)
+
 	if d.config.Volumes.Enabled {
 		fp.Attributes["driver.docker.volumes.enabled"] = pstructs.NewBoolAttribute(true)
 	}
@@ -178,3 +183,27 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 
 	return fp
 }
+
+// allowedCaps returns the sorted, comma separated list of the allowed
+// capabilities in lower case and without their "CAP_" prefix, or "all" if
+// every capability is allowed.
+func allowedCaps(caps []string) string {
+	set := make(map[string]struct{}, len(caps))
+	for _, cap := range caps {
+		cap = strings.ToLower(strings.TrimSpace(cap))
+		cap = strings.TrimPrefix(cap, "cap_")
+		if cap == "all" {
+			return "all"
+		}
+		if cap != "" {
+			set[cap] = struct{}{}
+		}
+	}
+
+	allowed := make([]string, 0, len(set))
+	for cap := range set {
+		allowed = append(allowed, cap)
+	}
+	sort.Strings(allowed)
+	return strings.Join(allowed, ",")
+}
@@ -25,3 +25,11 @@ func TestDockerDriver_FingerprintHealth(t *testing.T) {
 	fp := d.buildFingerprint()
 	require.Equal(t, drivers.HealthStateHealthy, fp.Health)
 }
+
+func TestDockerDriver_AllowedCaps(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", allowedCaps(nil))
+	require.Equal(t, "chown,net_admin,net_raw", allowedCaps([]string{"NET_RAW", " CAP_NET_ADMIN", "chown", "NET_RAW"}))
+	require.Equal(t, "all", allowedCaps([]string{"CHOWN", "ALL"}))
+}
@@ -0,0 +1,155 @@
+package docker
+
+import (
+	"fmt"
+	"sync"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// sharedMemory is the container holding the IPC namespace and /dev/shm
+// shared by the tasks of an allocation.
+type sharedMemory struct {
+	// containerID is the ID of the container the tasks join
+	containerID string
+
+	// refs is the number of tasks of the allocation using the shared memory
+	refs int
+}
+
+// sharedMemoryStore tracks the shared memory of the allocations, which is
+// created when their first task starts and destroyed along with their last
+// task.
+type sharedMemoryStore struct {
+	store map[string]*sharedMemory
+	lock  sync.Mutex
+}
+
+func newSharedMemoryStore() *sharedMemoryStore {
+	return &sharedMemoryStore{store: map[string]*sharedMemory{}}
+}
+
+// sharedMemoryContainerName returns the name of the container holding the
+// shared memory of the allocation.
+func sharedMemoryContainerName(allocID string) string {
+	return fmt.Sprintf("nomad_shm-%s", allocID)
+}
+
+// acquireSharedMemory returns the ID of the container holding the shared
+// memory of the allocation of the task, starting it if the task is the first
+// of the allocation to start.
+func (d *Driver) acquireSharedMemory(task *drivers.TaskConfig) (string, error) {
+	d.sharedMemory.lock.Lock()
+	defer d.sharedMemory.lock.Unlock()
+
+	shm, ok := d.sharedMemory.store[task.AllocID]
+	if !ok {
+		id, err := d.createSharedMemory(task)
+		if err != nil {
+			return "", fmt.Errorf("failed to create shared memory: %v", err)
+		}
+		shm = &sharedMemory{containerID: id}
+		d.sharedMemory.store[task.AllocID] = shm
+	}
+	shm.refs++
+	return shm.containerID, nil
+}
+
+// createSharedMemory starts the container holding the shared memory of the
+// allocation of the task, or returns the one already running.
+func (d *Driver) createSharedMemory(task *drivers.TaskConfig) (string, error) {
+	client, _, err := d.dockerClients()
+	if err != nil {
+		return "", fmt.Errorf("Failed to connect to docker daemon: %s", err)
+	}
+
+	image := d.config.InfraImage
+	if err := d.PrefetchImage(image, sharedMemoryContainerName(task.AllocID)); err != nil {
+		return "", fmt.Errorf("failed to pull image %q: %v", image, err)
+	}
+
+	config := docker.CreateContainerOptions{
+		Name: sharedMemoryContainerName(task.AllocID),
+		Config: &docker.Config{
+			Image: image,
+		},
+		HostConfig: &docker.HostConfig{
+			IpcMode:     "shareable",
+			ShmSize:     task.SharedMemoryMB * 1024 * 1024,
+			NetworkMode: "none",
+		},
+	}
+	container, err := d.createContainer(client, config, &TaskConfig{Image: image})
+	if err != nil {
+		d.ReleaseImage(image, config.Name)
+		return "", err
+	}
+
+	if !container.State.Running {
+		if err := d.startContainer(container); err != nil {
+			client.RemoveContainer(docker.RemoveContainerOptions{ID: container.ID, Force: true})
+			d.ReleaseImage(image, config.Name)
+			return "", err
+		}
+	}
+
+	d.logger.Info("started shared memory container", "alloc_id", task.AllocID, "container_id", container.ID)
+	return container.ID, nil
+}
+
+// restoreSharedMemory registers a recovered task using the running shared
+// memory of its allocation.
+func (d *Driver) restoreSharedMemory(task *drivers.TaskConfig) error {
+	d.sharedMemory.lock.Lock()
+	defer d.sharedMemory.lock.Unlock()
+
+	shm, ok := d.sharedMemory.store[task.AllocID]
+	if !ok {
+		client, _, err := d.dockerClients()
+		if err != nil {
+			return fmt.Errorf("failed to get docker client: %v", err)
+		}
+		container, err := client.InspectContainer(sharedMemoryContainerName(task.AllocID))
+		if err != nil {
+			return fmt.Errorf("failed to inspect shared memory container: %v", err)
+		}
+		shm = &sharedMemory{containerID: container.ID}
+		d.sharedMemory.store[task.AllocID] = shm
+	}
+	shm.refs++
+	return nil
+}
+
+// releaseSharedMemory marks a task using shared memory as destroyed,
+// removing the container holding it if it was the last task of its
+// allocation.
+func (d *Driver) releaseSharedMemory(task *drivers.TaskConfig) {
+	if task.SharedMemoryMB <= 0 {
+		return
+	}
+
+	d.sharedMemory.lock.Lock()
+	defer d.sharedMemory.lock.Unlock()
+
+	shm, ok := d.sharedMemory.store[task.AllocID]
+	if !ok {
+		return
+	}
+	shm.refs--
+	if shm.refs > 0 {
+		return
+	}
+	delete(d.sharedMemory.store, task.AllocID)
+
+	client, _, err := d.dockerClients()
+	if err != nil {
+		d.logger.Warn("failed to remove shared memory container", "alloc_id", task.AllocID, "error", err)
+		return
+	}
+	err = client.RemoveContainer(docker.RemoveContainerOptions{ID: shm.containerID, Force: true})
+	if err != nil {
+		d.logger.Warn("failed to remove shared memory container", "alloc_id", task.AllocID, "error", err)
+	}
+	d.ReleaseImage(d.config.InfraImage, sharedMemoryContainerName(task.AllocID))
+}
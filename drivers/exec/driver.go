@@ -90,6 +90,10 @@ type Driver struct {
 	// tasks is the in memory datastore mapping taskIDs to driverHandles
 	tasks *taskStore
 
+	// sharedMemory is the shared memory of the allocations whose tasks
+	// share an IPC namespace and /dev/shm
+	sharedMemory *sharedMemoryStore
+
 	// ctx is the context for the driver. It is passed to other subsystems to
 	// coordinate shutdown
 	ctx context.Context
@@ -130,6 +134,7 @@ func NewExecDriver(logger hclog.Logger) drivers.DriverPlugin {
 	return &Driver{
 		eventer:        eventer.NewEventer(ctx, logger),
 		tasks:          newTaskStore(),
+		sharedMemory:   newSharedMemoryStore(logger),
 		ctx:            ctx,
 		signalShutdown: cancel,
 		logger:         logger,
@@ -302,6 +307,10 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 		exitResult:   &drivers.ExitResult{},
 	}
 
+	if taskState.TaskConfig.SharedMemoryMB > 0 {
+		d.sharedMemory.Restore(taskState.TaskConfig)
+	}
+
 	d.tasks.Set(taskState.TaskConfig.ID, h)
 
 	go h.run()
@@ -322,6 +331,18 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 	handle := drivers.NewTaskHandle(taskHandleVersion)
 	handle.Config = cfg
 
+	// Join the IPC namespace and /dev/shm shared by the allocation
+	mounts := cfg.Mounts
+	var ipcNamespace string
+	if cfg.SharedMemoryMB > 0 {
+		shm, err := d.sharedMemory.Acquire(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		mounts = append(append([]*drivers.MountConfig{}, cfg.Mounts...), shm.mount())
+		ipcNamespace = shm.ipcNamespace
+	}
+
 	pluginLogFile := filepath.Join(cfg.TaskDir().Dir, "executor.out")
 	executorConfig := &executor.ExecutorConfig{
 		LogFile:     pluginLogFile,
@@ -333,6 +354,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		d.logger.With("task_name", handle.Config.Name, "alloc_id", handle.Config.AllocID),
 		d.nomadConfig, executorConfig)
 	if err != nil {
+		d.releaseSharedMemory(cfg)
 		return nil, nil, fmt.Errorf("failed to create executor: %v", err)
 	}
 
@@ -351,13 +373,15 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		TaskDir:        cfg.TaskDir().Dir,
 		StdoutPath:     cfg.StdoutPath,
 		StderrPath:     cfg.StderrPath,
-		Mounts:         cfg.Mounts,
+		Mounts:         mounts,
 		Devices:        cfg.Devices,
+		IPCNamespace:   ipcNamespace,
 	}
 
 	ps, err := exec.Launch(execCmd)
 	if err != nil {
 		pluginClient.Kill()
+		d.releaseSharedMemory(cfg)
 		return nil, nil, fmt.Errorf("failed to launch command with executor: %v", err)
 	}
 
@@ -382,6 +406,7 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		d.logger.Error("failed to start task, error setting driver state", "error", err)
 		exec.Shutdown("", 0)
 		pluginClient.Kill()
+		d.releaseSharedMemory(cfg)
 		return nil, nil, fmt.Errorf("failed to set driver state: %v", err)
 	}
 
@@ -462,10 +487,18 @@ func (d *Driver) DestroyTask(taskID string, force bool) error {
 		handle.pluginClient.Kill()
 	}
 
+	d.releaseSharedMemory(handle.taskConfig)
 	d.tasks.Delete(taskID)
 	return nil
 }
 
+// releaseSharedMemory releases the shared memory used by the task, if any.
+func (d *Driver) releaseSharedMemory(cfg *drivers.TaskConfig) {
+	if cfg.SharedMemoryMB > 0 {
+		d.sharedMemory.Release(cfg.AllocID)
+	}
+}
+
 func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
 	handle, ok := d.tasks.Get(taskID)
 	if !ok {
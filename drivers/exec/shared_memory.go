@@ -0,0 +1,109 @@
+package exec
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// sharedMemory is the IPC namespace and /dev/shm shared by the tasks of an
+// allocation.
+type sharedMemory struct {
+	// ipcNamespace is the path of the bind mount persisting the IPC namespace
+	ipcNamespace string
+
+	// shmDir is the tmpfs mounted at /dev/shm in the tasks
+	shmDir string
+
+	// refs is the number of tasks of the allocation using the shared memory
+	refs int
+}
+
+// newSharedMemory returns the shared memory of the allocation of the task.
+// It's kept in the allocation directory, next to the task directories.
+func newSharedMemory(cfg *drivers.TaskConfig) *sharedMemory {
+	return &sharedMemory{
+		ipcNamespace: filepath.Join(cfg.AllocDir, ".ipc_namespace"),
+		shmDir:       filepath.Join(cfg.AllocDir, ".shm"),
+	}
+}
+
+// mount returns the mount of the shared /dev/shm in the tasks.
+func (s *sharedMemory) mount() *drivers.MountConfig {
+	return &drivers.MountConfig{
+		TaskPath: "/dev/shm",
+		HostPath: s.shmDir,
+	}
+}
+
+// sharedMemoryStore tracks the shared memory of the allocations, which is
+// created when their first task starts and destroyed along with their last
+// task.
+type sharedMemoryStore struct {
+	store  map[string]*sharedMemory
+	lock   sync.Mutex
+	logger hclog.Logger
+}
+
+func newSharedMemoryStore(logger hclog.Logger) *sharedMemoryStore {
+	return &sharedMemoryStore{
+		store:  map[string]*sharedMemory{},
+		logger: logger,
+	}
+}
+
+// Acquire returns the shared memory of the allocation of the task, creating
+// it if the task is the first of the allocation to start.
+func (s *sharedMemoryStore) Acquire(cfg *drivers.TaskConfig) (*sharedMemory, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	shm, ok := s.store[cfg.AllocID]
+	if !ok {
+		shm = newSharedMemory(cfg)
+		if err := shm.create(cfg.SharedMemoryMB); err != nil {
+			return nil, fmt.Errorf("failed to create shared memory: %v", err)
+		}
+		s.store[cfg.AllocID] = shm
+	}
+	shm.refs++
+	return shm, nil
+}
+
+// Restore registers a recovered task using the existing shared memory of its
+// allocation.
+func (s *sharedMemoryStore) Restore(cfg *drivers.TaskConfig) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	shm, ok := s.store[cfg.AllocID]
+	if !ok {
+		shm = newSharedMemory(cfg)
+		s.store[cfg.AllocID] = shm
+	}
+	shm.refs++
+}
+
+// Release marks a task of the allocation as destroyed, destroying the shared
+// memory if it was the last one using it.
+func (s *sharedMemoryStore) Release(allocID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	shm, ok := s.store[allocID]
+	if !ok {
+		return
+	}
+	shm.refs--
+	if shm.refs > 0 {
+		return
+	}
+
+	delete(s.store, allocID)
+	if err := shm.destroy(); err != nil {
+		s.logger.Warn("failed to destroy shared memory", "alloc_id", allocID, "error", err)
+	}
+}
@@ -0,0 +1,13 @@
+// +build !linux
+
+package exec
+
+import "fmt"
+
+func (s *sharedMemory) create(sizeMB int64) error {
+	return fmt.Errorf("shared memory is only supported on Linux")
+}
+
+func (s *sharedMemory) destroy() error {
+	return nil
+}
@@ -0,0 +1,76 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/sys/unix"
+)
+
+// create mounts the shared /dev/shm with the size and creates the IPC
+// namespace.
+func (s *sharedMemory) create(sizeMB int64) error {
+	if err := os.MkdirAll(s.shmDir, 0755); err != nil {
+		return err
+	}
+	data := fmt.Sprintf("mode=1777,size=%dm", sizeMB)
+	if err := unix.Mount("shm", s.shmDir, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, data); err != nil {
+		os.Remove(s.shmDir)
+		return fmt.Errorf("failed to mount %s: %v", s.shmDir, err)
+	}
+
+	if err := newIPCNamespace(s.ipcNamespace); err != nil {
+		unix.Unmount(s.shmDir, unix.MNT_DETACH)
+		os.Remove(s.shmDir)
+		return fmt.Errorf("failed to create IPC namespace: %v", err)
+	}
+	return nil
+}
+
+// destroy unmounts the shared /dev/shm, releasing its memory, and the IPC
+// namespace.
+func (s *sharedMemory) destroy() error {
+	var mErr multierror.Error
+	for _, path := range []string{s.ipcNamespace, s.shmDir} {
+		if err := unix.Unmount(path, unix.MNT_DETACH); err != nil && err != unix.EINVAL && err != unix.ENOENT {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to unmount %s: %v", path, err))
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// newIPCNamespace creates an IPC namespace and bind mounts it at the path so
+// that it outlives the processes joining it.
+func newIPCNamespace(path string) error {
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return err
+	}
+	f.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		// Only the locked thread moves to the new namespace. It's never
+		// unlocked so that it exits along with the goroutine rather than
+		// running other goroutines in the namespace.
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWIPC); err != nil {
+			errCh <- err
+			return
+		}
+		ns := fmt.Sprintf("/proc/self/task/%d/ns/ipc", unix.Gettid())
+		errCh <- unix.Mount(ns, path, "none", unix.MS_BIND, "")
+	}()
+
+	if err := <-errCh; err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
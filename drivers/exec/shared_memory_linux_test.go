@@ -0,0 +1,62 @@
+package exec
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+
+	ctestutils "github.com/hashicorp/nomad/client/testutil"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestSharedMemoryStore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctestutils.RequireRoot(t)
+
+	allocDir, err := ioutil.TempDir("", "nomad_shared_memory")
+	require.NoError(err)
+	defer os.RemoveAll(allocDir)
+
+	cfg := &drivers.TaskConfig{
+		AllocID:        uuid.Generate(),
+		AllocDir:       allocDir,
+		SharedMemoryMB: 16,
+	}
+	store := newSharedMemoryStore(testlog.HCLogger(t))
+
+	var host syscall.Stat_t
+	require.NoError(syscall.Stat("/proc/self/ns/ipc", &host))
+
+	// The tasks of the allocation share the same memory
+	shm, err := store.Acquire(cfg)
+	require.NoError(err)
+	other, err := store.Acquire(cfg)
+	require.NoError(err)
+	require.Equal(shm, other)
+
+	// /dev/shm is a tmpfs of the requested size
+	var fs unix.Statfs_t
+	require.NoError(unix.Statfs(shm.shmDir, &fs))
+	require.EqualValues(unix.TMPFS_MAGIC, fs.Type)
+	require.EqualValues(16*1024*1024, fs.Blocks*uint64(fs.Bsize))
+
+	// The IPC namespace isn't the one of the host
+	var ns syscall.Stat_t
+	require.NoError(syscall.Stat(shm.ipcNamespace, &ns))
+	require.NotEqual(host.Ino, ns.Ino)
+
+	// The shared memory is destroyed along with the last task
+	store.Release(cfg.AllocID)
+	require.DirExists(shm.shmDir)
+	store.Release(cfg.AllocID)
+	_, err = os.Stat(shm.shmDir)
+	require.True(os.IsNotExist(err))
+	_, err = os.Stat(shm.ipcNamespace)
+	require.True(os.IsNotExist(err))
+}
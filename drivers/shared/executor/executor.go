@@ -122,6 +122,10 @@ type ExecCommand struct {
 
 	// Devices are the the device nodes to be created in isolation environment
 	Devices []*drivers.DeviceConfig
+
+	// IPCNamespace is the path of the IPC namespace the process joins. If
+	// empty, the process shares the IPC namespace of the host.
+	IPCNamespace string
 }
 
 // SetWriters sets the writer for the process stdout and stderr. This should
@@ -435,8 +435,9 @@ func (l *LibcontainerExecutor) handleStats(ch chan *cstructs.TaskResourceUsage,
 		}
 		taskResUsage := cstructs.TaskResourceUsage{
 			ResourceUsage: &cstructs.ResourceUsage{
-				MemoryStats: ms,
-				CpuStats:    cs,
+				MemoryStats:   ms,
+				CpuStats:      cs,
+				PressureStats: l.cgroupPressure(),
 			},
 			Timestamp: ts.UTC().UnixNano(),
 			Pids:      pidStats,
@@ -451,6 +452,27 @@ func (l *LibcontainerExecutor) handleStats(ch chan *cstructs.TaskResourceUsage,
 	}
 }
 
+// cgroupPressure returns the pressure stall information of the container's
+// cgroups, or nil if the kernel doesn't expose it.
+func (l *LibcontainerExecutor) cgroupPressure() *stats.PressureStats {
+	state, err := l.container.State()
+	if err != nil {
+		return nil
+	}
+
+	path := func(subsystem, file string) string {
+		dir, ok := state.CgroupPaths[subsystem]
+		if !ok {
+			return ""
+		}
+		return filepath.Join(dir, file)
+	}
+	return stats.ReadPressure(
+		path("cpu", "cpu.pressure"),
+		path("memory", "memory.pressure"),
+		path("blkio", "io.pressure"))
+}
+
 // Signal sends a signal to the process managed by the executor
 func (l *LibcontainerExecutor) Signal(s os.Signal) error {
 	return l.userProc.Signal(s)
@@ -529,6 +551,7 @@ func configureCapabilities(cfg *lconfigs.Config, command *ExecCommand) error {
 //
 // * the task directory as the chroot
 // * dedicated mount points namespace, but shares the PID, User, domain, network namespaces with host
+// * the IPC namespace of the host, or the given one
 // * small subset of devices (e.g. stdout/stderr/stdin, tty, shm, pts); default to using the same set of devices as Docker
 // * some special filesystems: `/proc`, `/sys`.  Some case is given to avoid exec escaping or setting malicious values through them.
 func configureIsolation(cfg *lconfigs.Config, command *ExecCommand) error {
@@ -541,6 +564,9 @@ func configureIsolation(cfg *lconfigs.Config, command *ExecCommand) error {
 	cfg.Namespaces = lconfigs.Namespaces{
 		{Type: lconfigs.NEWNS},
 	}
+	if command.IPCNamespace != "" {
+		cfg.Namespaces = append(cfg.Namespaces, lconfigs.Namespace{Type: lconfigs.NEWIPC, Path: command.IPCNamespace})
+	}
 
 	// paths to mask using a bind mount to /dev/null to prevent reading
 	cfg.MaskPaths = []string{
@@ -605,12 +631,53 @@ func configureIsolation(cfg *lconfigs.Config, command *ExecCommand) error {
 	}
 
 	if len(command.Mounts) > 0 {
+		// A mount of /dev/shm replaces the private one of the task
+		for _, m := range command.Mounts {
+			if m.TaskPath == "/dev/shm" {
+				cfg.Mounts = removeMount(cfg.Mounts, "/dev/shm")
+			}
+		}
 		cfg.Mounts = append(cfg.Mounts, cmdMounts(command.Mounts)...)
 	}
 
+	// Mount a huge page file system for each size of huge pages the task
+	// may use
+	if command.Resources != nil && command.Resources.LinuxResources != nil {
+		lr := command.Resources.LinuxResources
+		if lr.HugePages2MB > 0 {
+			cfg.Mounts = append(cfg.Mounts, hugetlbfsMount("/dev/hugepages", "2M"))
+		}
+		if lr.HugePages1GB > 0 {
+			cfg.Mounts = append(cfg.Mounts, hugetlbfsMount("/dev/hugepages1G", "1G"))
+		}
+	}
+
 	return nil
 }
 
+// hugetlbfsMount returns the mount of a huge page file system of the page
+// size at the destination.
+func hugetlbfsMount(dest, pageSize string) *lconfigs.Mount {
+	return &lconfigs.Mount{
+		Source:      "hugetlbfs",
+		Destination: dest,
+		Device:      "hugetlbfs",
+		Flags:       syscall.MS_NOSUID | syscall.MS_NODEV,
+		Data:        "pagesize=" + pageSize,
+	}
+}
+
+// removeMount returns the mounts other than the one of the destination.
+func removeMount(mounts []*lconfigs.Mount, dest string) []*lconfigs.Mount {
+	r := make([]*lconfigs.Mount, 0, len(mounts))
+	for _, m := range mounts {
+		if m.Destination != dest {
+			r = append(r, m)
+		}
+	}
+	return r
+}
+
 func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 
 	// If resources are not limited then manually create cgroups needed
@@ -641,6 +708,34 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	// Set the relative CPU shares for this cgroup.
 	cfg.Cgroups.Resources.CpuShares = uint64(cpuShares)
 
+	// Pin the task to its reserved cores
+	if lr := command.Resources.LinuxResources; lr != nil && lr.CpusetCPUs != "" {
+		cfg.Cgroups.Resources.CpusetCpus = lr.CpusetCPUs
+	}
+
+	if lr := command.Resources.LinuxResources; lr != nil && (lr.HugePages2MB > 0 || lr.HugePages1GB > 0) {
+		// Limit the task to its reserved huge pages, for the page sizes
+		// supported by the kernel
+		limits := map[string]uint64{
+			"2MB": uint64(lr.HugePages2MB) * 2 * 1024 * 1024,
+			"1GB": uint64(lr.HugePages1GB) * 1024 * 1024 * 1024,
+		}
+		sizes, _ := cgroups.GetHugePageSize()
+		for _, size := range sizes {
+			if limit, ok := limits[size]; ok {
+				cfg.Cgroups.Resources.HugetlbLimit = append(cfg.Cgroups.Resources.HugetlbLimit,
+					&lconfigs.HugepageLimit{Pagesize: size, Limit: limit})
+			}
+		}
+	}
+
+	// Give the task its real-time runtime. The cgroup parent must have been
+	// given enough real-time runtime by the operator.
+	if lr := command.Resources.LinuxResources; lr != nil && lr.CPURealtimeRuntime > 0 {
+		cfg.Cgroups.Resources.CpuRtPeriod = uint64(lr.CPURealtimePeriod)
+		cfg.Cgroups.Resources.CpuRtRuntime = lr.CPURealtimeRuntime
+	}
+
 	return nil
 }
 
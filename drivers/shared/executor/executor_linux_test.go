@@ -260,3 +260,15 @@ func TestExecutor_cmdMounts(t *testing.T) {
 
 	require.EqualValues(t, expected, cmdMounts(input))
 }
+
+func TestExecutor_parseNumericUser(t *testing.T) {
+	uid, gid, ok := parseNumericUser("80000:80001")
+	require.True(t, ok)
+	require.EqualValues(t, 80000, uid)
+	require.EqualValues(t, 80001, gid)
+
+	for _, user := range []string{"nobody", "80000", "80000:", "nobody:80000", "-1:0"} {
+		_, _, ok := parseNumericUser(user)
+		require.False(t, ok, user)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/user"
 	"strconv"
+	"strings"
 	"syscall"
 
 	multierror "github.com/hashicorp/go-multierror"
@@ -15,8 +16,14 @@ import (
 )
 
 // runAs takes a user id as a string and looks up the user, and sets the command
-// to execute as that user.
+// to execute as that user. Users given as "uid:gid" don't need to exist, as
+// is the case of the dynamic users of allocations.
 func (e *UniversalExecutor) runAs(userid string) error {
+	if uid, gid, ok := parseNumericUser(userid); ok {
+		e.setCredential(uid, gid, nil)
+		return nil
+	}
+
 	u, err := user.Lookup(userid)
 	if err != nil {
 		return fmt.Errorf("Failed to identify user %v: %v", userid, err)
@@ -49,19 +56,41 @@ func (e *UniversalExecutor) runAs(userid string) error {
 	}
 
 	// Set the command to run as that user and group.
+	e.setCredential(uint32(uid), uint32(gid), gids)
+	return nil
+}
+
+// setCredential sets the command to run as the user and groups.
+func (e *UniversalExecutor) setCredential(uid, gid uint32, gids []uint32) {
 	if e.childCmd.SysProcAttr == nil {
 		e.childCmd.SysProcAttr = &syscall.SysProcAttr{}
 	}
 	if e.childCmd.SysProcAttr.Credential == nil {
 		e.childCmd.SysProcAttr.Credential = &syscall.Credential{}
 	}
-	e.childCmd.SysProcAttr.Credential.Uid = uint32(uid)
-	e.childCmd.SysProcAttr.Credential.Gid = uint32(gid)
+	e.childCmd.SysProcAttr.Credential.Uid = uid
+	e.childCmd.SysProcAttr.Credential.Gid = gid
 	e.childCmd.SysProcAttr.Credential.Groups = gids
 
 	e.logger.Debug("setting process user", "user", uid, "group", gid, "additional_groups", gids)
+}
 
-	return nil
+// parseNumericUser parses a user given as "uid:gid".
+func parseNumericUser(userid string) (uint32, uint32, bool) {
+	parts := strings.SplitN(userid, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	uid, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	gid, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint32(uid), uint32(gid), true
 }
 
 // configureResourceContainer configured the cgroups to be used to track pids
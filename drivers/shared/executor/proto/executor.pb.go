@@ -38,6 +38,7 @@ type LaunchRequest struct {
 	BasicProcessCgroup   bool              `protobuf:"varint,10,opt,name=basic_process_cgroup,json=basicProcessCgroup,proto3" json:"basic_process_cgroup,omitempty"`
 	Mounts               []*proto1.Mount   `protobuf:"bytes,11,rep,name=mounts,proto3" json:"mounts,omitempty"`
 	Devices              []*proto1.Device  `protobuf:"bytes,12,rep,name=devices,proto3" json:"devices,omitempty"`
+	IpcNamespace         string            `protobuf:"bytes,13,opt,name=ipc_namespace,json=ipcNamespace,proto3" json:"ipc_namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -151,6 +152,13 @@ func (m *LaunchRequest) GetDevices() []*proto1.Device {
 	return nil
 }
 
+func (m *LaunchRequest) GetIpcNamespace() string {
+	if m != nil {
+		return m.IpcNamespace
+	}
+	return ""
+}
+
 type LaunchResponse struct {
 	Process              *ProcessState `protobuf:"bytes,1,opt,name=process,proto3" json:"process,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -1131,61 +1139,62 @@ func init() {
 }
 
 var fileDescriptor_executor_1eb9aa6040002cd3 = []byte{
-	// 885 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x4b, 0x6f, 0xe4, 0x44,
-	0x10, 0x5e, 0xc7, 0x99, 0x57, 0xcd, 0xe4, 0xa1, 0x16, 0x0a, 0x5e, 0x73, 0xd8, 0xc1, 0x07, 0x76,
-	0x04, 0x8b, 0x27, 0xca, 0xbe, 0xb8, 0x00, 0x12, 0xc9, 0xc2, 0x25, 0xac, 0x22, 0x67, 0x61, 0x25,
-	0x0e, 0x0c, 0x1d, 0xbb, 0xb1, 0x5b, 0x99, 0x71, 0x9b, 0xee, 0xf6, 0x30, 0x48, 0x48, 0x9c, 0xf8,
-	0x07, 0xfc, 0x52, 0x8e, 0x9c, 0x50, 0xbf, 0x9c, 0x99, 0xec, 0x12, 0x79, 0x40, 0x9c, 0xa6, 0xab,
-	0x5c, 0xdf, 0x57, 0x55, 0xdd, 0x55, 0xdf, 0xc0, 0xa3, 0x8c, 0xd3, 0x25, 0xe1, 0x62, 0x2a, 0x0a,
-	0xcc, 0x49, 0x36, 0x25, 0x2b, 0x92, 0xd6, 0x92, 0xf1, 0x69, 0xc5, 0x99, 0x64, 0x8d, 0x19, 0x6b,
-	0x13, 0x7d, 0x50, 0x60, 0x51, 0xd0, 0x94, 0xf1, 0x2a, 0x2e, 0xd9, 0x02, 0x67, 0x71, 0x35, 0xaf,
-	0x73, 0x5a, 0x8a, 0x78, 0x33, 0x2e, 0x7c, 0x90, 0x33, 0x96, 0xcf, 0x89, 0x21, 0xb9, 0xaa, 0x7f,
-	0x9c, 0x4a, 0xba, 0x20, 0x42, 0xe2, 0x45, 0x65, 0x03, 0x3e, 0xcd, 0xa9, 0x2c, 0xea, 0xab, 0x38,
-	0x65, 0x8b, 0x69, 0xc3, 0x39, 0xd5, 0x9c, 0x53, 0xcb, 0x39, 0x75, 0x95, 0x99, 0x4a, 0x8c, 0x65,
-	0xe0, 0xd1, 0x9f, 0x3e, 0xec, 0x9d, 0xe3, 0xba, 0x4c, 0x8b, 0x84, 0xfc, 0x54, 0x13, 0x21, 0xd1,
-	0x21, 0xf8, 0xe9, 0x22, 0x0b, 0xbc, 0xb1, 0x37, 0x19, 0x24, 0xea, 0x88, 0x10, 0xec, 0x62, 0x9e,
-	0x8b, 0x60, 0x67, 0xec, 0x4f, 0x06, 0x89, 0x3e, 0xa3, 0x97, 0x30, 0xe0, 0x44, 0xb0, 0x9a, 0xa7,
-	0x44, 0x04, 0xfe, 0xd8, 0x9b, 0x0c, 0x4f, 0x8e, 0xe3, 0x7f, 0xea, 0xc9, 0xe6, 0x37, 0x29, 0xe3,
-	0xc4, 0xe1, 0x92, 0x1b, 0x0a, 0xf4, 0x00, 0x86, 0x42, 0x66, 0xac, 0x96, 0xb3, 0x0a, 0xcb, 0x22,
-	0xd8, 0xd5, 0xd9, 0xc1, 0xb8, 0x2e, 0xb0, 0x2c, 0x6c, 0x00, 0xe1, 0xdc, 0x04, 0x74, 0x9a, 0x00,
-	0xc2, 0xb9, 0x0e, 0x38, 0x04, 0x9f, 0x94, 0xcb, 0xa0, 0xab, 0x8b, 0x54, 0x47, 0x55, 0x77, 0x2d,
-	0x08, 0x0f, 0x7a, 0x3a, 0x56, 0x9f, 0xd1, 0x7d, 0xe8, 0x4b, 0x2c, 0xae, 0x67, 0x19, 0xe5, 0x41,
-	0x5f, 0xfb, 0x7b, 0xca, 0x3e, 0xa3, 0x1c, 0x3d, 0x84, 0x03, 0x57, 0xcf, 0x6c, 0x4e, 0x17, 0x54,
-	0x8a, 0x60, 0x30, 0xf6, 0x26, 0xfd, 0x64, 0xdf, 0xb9, 0xcf, 0xb5, 0x17, 0x1d, 0xc3, 0x3b, 0x57,
-	0x58, 0xd0, 0x74, 0x56, 0x71, 0x96, 0x12, 0x21, 0x66, 0x69, 0xce, 0x59, 0x5d, 0x05, 0xa0, 0xa3,
-	0x91, 0xfe, 0x76, 0x61, 0x3e, 0x9d, 0xea, 0x2f, 0xe8, 0x0c, 0xba, 0x0b, 0x56, 0x97, 0x52, 0x04,
-	0xc3, 0xb1, 0x3f, 0x19, 0x9e, 0x3c, 0x6a, 0x79, 0x55, 0x5f, 0x2b, 0x50, 0x62, 0xb1, 0xe8, 0x2b,
-	0xe8, 0x65, 0x64, 0x49, 0xd5, 0x8d, 0x8f, 0x34, 0xcd, 0xc7, 0x2d, 0x69, 0xce, 0x34, 0x2a, 0x71,
-	0xe8, 0xe8, 0x07, 0xd8, 0x77, 0x6f, 0x2e, 0x2a, 0x56, 0x0a, 0x82, 0x5e, 0x42, 0xcf, 0x36, 0xa3,
-	0x1f, 0x7e, 0x78, 0xf2, 0x24, 0x6e, 0x37, 0xa0, 0xb1, 0x6d, 0xf4, 0x52, 0x62, 0x49, 0x12, 0x47,
-	0x12, 0xed, 0xc1, 0xf0, 0x35, 0xa6, 0xd2, 0xce, 0x54, 0xf4, 0x3d, 0x8c, 0x8c, 0xf9, 0x3f, 0xa5,
-	0x3b, 0x87, 0x83, 0xcb, 0xa2, 0x96, 0x19, 0xfb, 0xb9, 0x74, 0x63, 0x7c, 0x04, 0x5d, 0x41, 0xf3,
-	0x12, 0xcf, 0xed, 0x24, 0x5b, 0x0b, 0xbd, 0x0f, 0xa3, 0x9c, 0xe3, 0x94, 0xcc, 0x2a, 0xc2, 0x29,
-	0xcb, 0x82, 0x9d, 0xb1, 0x37, 0xf1, 0x93, 0xa1, 0xf6, 0x5d, 0x68, 0x57, 0x84, 0xe0, 0xf0, 0x86,
-	0xcd, 0x54, 0x1c, 0x15, 0x70, 0xf4, 0x4d, 0x95, 0xa9, 0xa4, 0xcd, 0xf4, 0xda, 0x44, 0x1b, 0x9b,
-	0xe0, 0xfd, 0xe7, 0x4d, 0x88, 0xee, 0xc3, 0xbb, 0x6f, 0x64, 0xb2, 0x45, 0x1c, 0xc2, 0xfe, 0xb7,
-	0x84, 0x0b, 0xca, 0x5c, 0x97, 0xd1, 0x47, 0x70, 0xd0, 0x78, 0xec, 0xdd, 0x06, 0xd0, 0x5b, 0x1a,
-	0x97, 0xed, 0xdc, 0x99, 0xd1, 0x87, 0x30, 0x52, 0xf7, 0xd6, 0x54, 0x1e, 0x42, 0x9f, 0x96, 0x92,
-	0xf0, 0xa5, 0xbd, 0x24, 0x3f, 0x69, 0xec, 0xe8, 0x35, 0xec, 0xd9, 0x58, 0x4b, 0xfb, 0x25, 0x74,
-	0x84, 0x72, 0x6c, 0xd9, 0xe2, 0x2b, 0x2c, 0xae, 0x0d, 0x91, 0x81, 0x47, 0x0f, 0x61, 0xef, 0x52,
-	0xbf, 0xc4, 0xdb, 0x1f, 0xaa, 0xe3, 0x1e, 0x4a, 0x35, 0xeb, 0x02, 0x6d, 0xfb, 0xd7, 0x30, 0x7c,
-	0xb1, 0x22, 0xa9, 0x03, 0x3e, 0x83, 0x7e, 0x46, 0x70, 0x36, 0xa7, 0x25, 0xb1, 0x45, 0x85, 0xb1,
-	0x51, 0xcb, 0xd8, 0xa9, 0x65, 0xfc, 0xca, 0xa9, 0x65, 0xd2, 0xc4, 0x3a, 0x81, 0xdb, 0x79, 0x53,
-	0xe0, 0xfc, 0x1b, 0x81, 0x8b, 0x4e, 0x61, 0x64, 0x92, 0xd9, 0xfe, 0x8f, 0xa0, 0xcb, 0x6a, 0x59,
-	0xd5, 0x52, 0xe7, 0x1a, 0x25, 0xd6, 0x42, 0xef, 0xc1, 0x80, 0xac, 0xa8, 0x9c, 0xa5, 0x2c, 0x23,
-	0x9a, 0xb3, 0x93, 0xf4, 0x95, 0xe3, 0x94, 0x65, 0x24, 0xfa, 0xdd, 0x83, 0xd1, 0xfa, 0xc4, 0xaa,
-	0xdc, 0x15, 0xcd, 0x6c, 0xa7, 0xea, 0x78, 0x27, 0x7e, 0xed, 0x6e, 0xfc, 0xf5, 0xbb, 0x41, 0x31,
-	0xec, 0xaa, 0xff, 0x01, 0x2d, 0x93, 0x77, 0xb7, 0xad, 0xe3, 0x4e, 0xfe, 0xea, 0x41, 0xff, 0x85,
-	0x5d, 0x24, 0xf4, 0x0b, 0x74, 0xcd, 0xf6, 0xa3, 0xa7, 0x6d, 0xb7, 0x6e, 0xe3, 0x1f, 0x22, 0x7c,
-	0xb6, 0x2d, 0xcc, 0xbe, 0xdf, 0x3d, 0x24, 0x60, 0x57, 0xe9, 0x00, 0x7a, 0xdc, 0x96, 0x61, 0x4d,
-	0x44, 0xc2, 0x27, 0xdb, 0x81, 0x9a, 0xa4, 0xbf, 0x41, 0xdf, 0xad, 0x33, 0x7a, 0xde, 0x96, 0xe3,
-	0x96, 0x9c, 0x84, 0x9f, 0x6c, 0x0f, 0x6c, 0x0a, 0xf8, 0xc3, 0x83, 0x83, 0x5b, 0x2b, 0x8d, 0x3e,
-	0x6b, 0xcb, 0xf7, 0x76, 0xd5, 0x09, 0x3f, 0xff, 0xd7, 0xf8, 0xa6, 0xac, 0x5f, 0xa1, 0x67, 0xb5,
-	0x03, 0xb5, 0x7e, 0xd1, 0x4d, 0xf9, 0x09, 0x9f, 0x6f, 0x8d, 0x6b, 0xb2, 0xaf, 0xa0, 0xa3, 0x75,
-	0x01, 0xb5, 0x7e, 0xd6, 0x75, 0xed, 0x0a, 0x9f, 0x6e, 0x89, 0x72, 0x79, 0x8f, 0x3d, 0x35, 0xff,
-	0x46, 0x58, 0xda, 0xcf, 0xff, 0x86, 0x62, 0xb5, 0x9f, 0xff, 0x5b, 0xfa, 0xa5, 0xe7, 0x5f, 0xad,
-	0x61, 0xfb, 0xf9, 0x5f, 0xd3, 0xbb, 0xf6, 0xf3, 0xbf, 0xae, 0x5b, 0xd1, 0xbd, 0x2f, 0x7a, 0xdf,
-	0x75, 0x8c, 0x30, 0x74, 0xf5, 0xcf, 0xe3, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x2f, 0x08, 0xfe,
-	0x1f, 0xaa, 0x0a, 0x00, 0x00,
+	// 900 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x5b, 0x6f, 0xe3, 0x44,
+	0x14, 0xc6, 0x75, 0xae, 0x27, 0x49, 0x5b, 0x8d, 0x50, 0xf1, 0x9a, 0x87, 0x0d, 0x46, 0x62, 0x23,
+	0x58, 0x9c, 0xaa, 0xdb, 0xed, 0xf2, 0x02, 0x48, 0xb4, 0x0b, 0x2f, 0xa5, 0xaa, 0xdc, 0x85, 0x95,
+	0x78, 0x20, 0x4c, 0xed, 0x21, 0x1e, 0x35, 0xf1, 0x98, 0x99, 0x71, 0x28, 0x12, 0x12, 0x4f, 0xfc,
+	0x03, 0x24, 0xfe, 0x2b, 0x4f, 0x68, 0x6e, 0x6e, 0xb2, 0x5b, 0xc0, 0x01, 0xf1, 0x94, 0x39, 0xc7,
+	0xe7, 0xfb, 0xce, 0x65, 0xce, 0x7c, 0x81, 0xc7, 0x19, 0xa7, 0x2b, 0xc2, 0xc5, 0x54, 0xe4, 0x98,
+	0x93, 0x6c, 0x4a, 0x6e, 0x49, 0x5a, 0x49, 0xc6, 0xa7, 0x25, 0x67, 0x92, 0xd5, 0x66, 0xac, 0x4d,
+	0xf4, 0x5e, 0x8e, 0x45, 0x4e, 0x53, 0xc6, 0xcb, 0xb8, 0x60, 0x4b, 0x9c, 0xc5, 0xe5, 0xa2, 0x9a,
+	0xd3, 0x42, 0xc4, 0x9b, 0x71, 0xe1, 0xc3, 0x39, 0x63, 0xf3, 0x05, 0x31, 0x24, 0xd7, 0xd5, 0xf7,
+	0x53, 0x49, 0x97, 0x44, 0x48, 0xbc, 0x2c, 0x6d, 0xc0, 0xc7, 0x73, 0x2a, 0xf3, 0xea, 0x3a, 0x4e,
+	0xd9, 0x72, 0x5a, 0x73, 0x4e, 0x35, 0xe7, 0xd4, 0x72, 0x4e, 0x5d, 0x65, 0xa6, 0x12, 0x63, 0x19,
+	0x78, 0xf4, 0x7b, 0x0b, 0x46, 0xe7, 0xb8, 0x2a, 0xd2, 0x3c, 0x21, 0x3f, 0x54, 0x44, 0x48, 0xb4,
+	0x0f, 0x7e, 0xba, 0xcc, 0x02, 0x6f, 0xec, 0x4d, 0xfa, 0x89, 0x3a, 0x22, 0x04, 0x2d, 0xcc, 0xe7,
+	0x22, 0xd8, 0x19, 0xfb, 0x93, 0x7e, 0xa2, 0xcf, 0xe8, 0x02, 0xfa, 0x9c, 0x08, 0x56, 0xf1, 0x94,
+	0x88, 0xc0, 0x1f, 0x7b, 0x93, 0xc1, 0xd1, 0x61, 0xfc, 0x57, 0x3d, 0xd9, 0xfc, 0x26, 0x65, 0x9c,
+	0x38, 0x5c, 0x72, 0x47, 0x81, 0x1e, 0xc2, 0x40, 0xc8, 0x8c, 0x55, 0x72, 0x56, 0x62, 0x99, 0x07,
+	0x2d, 0x9d, 0x1d, 0x8c, 0xeb, 0x12, 0xcb, 0xdc, 0x06, 0x10, 0xce, 0x4d, 0x40, 0xbb, 0x0e, 0x20,
+	0x9c, 0xeb, 0x80, 0x7d, 0xf0, 0x49, 0xb1, 0x0a, 0x3a, 0xba, 0x48, 0x75, 0x54, 0x75, 0x57, 0x82,
+	0xf0, 0xa0, 0xab, 0x63, 0xf5, 0x19, 0x3d, 0x80, 0x9e, 0xc4, 0xe2, 0x66, 0x96, 0x51, 0x1e, 0xf4,
+	0xb4, 0xbf, 0xab, 0xec, 0x33, 0xca, 0xd1, 0x23, 0xd8, 0x73, 0xf5, 0xcc, 0x16, 0x74, 0x49, 0xa5,
+	0x08, 0xfa, 0x63, 0x6f, 0xd2, 0x4b, 0x76, 0x9d, 0xfb, 0x5c, 0x7b, 0xd1, 0x21, 0xbc, 0x79, 0x8d,
+	0x05, 0x4d, 0x67, 0x25, 0x67, 0x29, 0x11, 0x62, 0x96, 0xce, 0x39, 0xab, 0xca, 0x00, 0x74, 0x34,
+	0xd2, 0xdf, 0x2e, 0xcd, 0xa7, 0x53, 0xfd, 0x05, 0x9d, 0x41, 0x67, 0xc9, 0xaa, 0x42, 0x8a, 0x60,
+	0x30, 0xf6, 0x27, 0x83, 0xa3, 0xc7, 0x0d, 0x47, 0xf5, 0xa5, 0x02, 0x25, 0x16, 0x8b, 0xbe, 0x80,
+	0x6e, 0x46, 0x56, 0x54, 0x4d, 0x7c, 0xa8, 0x69, 0x3e, 0x6c, 0x48, 0x73, 0xa6, 0x51, 0x89, 0x43,
+	0xa3, 0x77, 0x61, 0x44, 0xcb, 0x74, 0x56, 0xe0, 0x25, 0x11, 0x25, 0x4e, 0x49, 0x30, 0xd2, 0x93,
+	0x18, 0xd2, 0x32, 0xbd, 0x70, 0xbe, 0xe8, 0x3b, 0xd8, 0x75, 0x8b, 0x21, 0x4a, 0x56, 0x08, 0x82,
+	0x2e, 0xa0, 0x6b, 0x3b, 0xd6, 0xdb, 0x31, 0x38, 0x3a, 0x8e, 0x9b, 0x6d, 0x71, 0x6c, 0xa7, 0x71,
+	0x25, 0xb1, 0x24, 0x89, 0x23, 0x89, 0x46, 0x30, 0x78, 0x89, 0xa9, 0xb4, 0x8b, 0x17, 0x7d, 0x0b,
+	0x43, 0x63, 0xfe, 0x4f, 0xe9, 0xce, 0x61, 0xef, 0x2a, 0xaf, 0x64, 0xc6, 0x7e, 0x2c, 0xdc, 0xae,
+	0x1f, 0x40, 0x47, 0xd0, 0x79, 0x81, 0x17, 0x76, 0xdd, 0xad, 0x85, 0xde, 0x81, 0xe1, 0x9c, 0xe3,
+	0x94, 0xcc, 0x4a, 0xc2, 0x29, 0xcb, 0x82, 0x9d, 0xb1, 0x37, 0xf1, 0x93, 0x81, 0xf6, 0x5d, 0x6a,
+	0x57, 0x84, 0x60, 0xff, 0x8e, 0xcd, 0x54, 0x1c, 0xe5, 0x70, 0xf0, 0x55, 0x99, 0xa9, 0xa4, 0xf5,
+	0x8a, 0xdb, 0x44, 0x1b, 0xcf, 0xc5, 0xfb, 0xcf, 0xcf, 0x25, 0x7a, 0x00, 0x6f, 0xbd, 0x96, 0xc9,
+	0x16, 0xb1, 0x0f, 0xbb, 0x5f, 0x13, 0x2e, 0x28, 0x73, 0x5d, 0x46, 0x1f, 0xc0, 0x5e, 0xed, 0xb1,
+	0xb3, 0x0d, 0xa0, 0xbb, 0x32, 0x2e, 0xdb, 0xb9, 0x33, 0xa3, 0xf7, 0x61, 0xa8, 0xe6, 0x56, 0x57,
+	0x1e, 0x42, 0x8f, 0x16, 0x92, 0xf0, 0x95, 0x1d, 0x92, 0x9f, 0xd4, 0x76, 0xf4, 0x12, 0x46, 0x36,
+	0xd6, 0xd2, 0x7e, 0x0e, 0x6d, 0xa1, 0x1c, 0x5b, 0xb6, 0xf8, 0x02, 0x8b, 0x1b, 0x43, 0x64, 0xe0,
+	0xd1, 0x23, 0x18, 0x5d, 0xe9, 0x9b, 0xb8, 0xff, 0xa2, 0xda, 0xee, 0xa2, 0x54, 0xb3, 0x2e, 0xd0,
+	0xb6, 0x7f, 0x03, 0x83, 0xe7, 0xb7, 0x24, 0x75, 0xc0, 0x13, 0xe8, 0x65, 0x04, 0x67, 0x0b, 0x5a,
+	0x10, 0x5b, 0x54, 0x18, 0x1b, 0x49, 0x8d, 0x9d, 0xa4, 0xc6, 0x2f, 0x9c, 0xa4, 0x26, 0x75, 0xac,
+	0x53, 0xc1, 0x9d, 0xd7, 0x55, 0xd0, 0xbf, 0x53, 0xc1, 0xe8, 0x14, 0x86, 0x26, 0x99, 0xed, 0xff,
+	0x00, 0x3a, 0xac, 0x92, 0x65, 0x25, 0x75, 0xae, 0x61, 0x62, 0x2d, 0xf4, 0x36, 0xf4, 0xc9, 0x2d,
+	0x95, 0xb3, 0x94, 0x65, 0x44, 0x73, 0xb6, 0x93, 0x9e, 0x72, 0x9c, 0xb2, 0x8c, 0x44, 0xbf, 0x7a,
+	0x30, 0x5c, 0xdf, 0x58, 0x95, 0xbb, 0xa4, 0x99, 0xed, 0x54, 0x1d, 0xff, 0x16, 0xbf, 0x36, 0x1b,
+	0x7f, 0x7d, 0x36, 0x28, 0x86, 0x96, 0xfa, 0xb3, 0x08, 0x5a, 0xff, 0xd8, 0xb6, 0x8e, 0x3b, 0xfa,
+	0xa3, 0x0b, 0xbd, 0xe7, 0xf6, 0x21, 0xa1, 0x9f, 0xa0, 0x63, 0x5e, 0x3f, 0x7a, 0xda, 0xf4, 0xd5,
+	0x6d, 0xfc, 0x8d, 0x84, 0x27, 0xdb, 0xc2, 0xec, 0xfd, 0xbd, 0x81, 0x04, 0xb4, 0x94, 0x0e, 0xa0,
+	0x27, 0x4d, 0x19, 0xd6, 0x44, 0x24, 0x3c, 0xde, 0x0e, 0x54, 0x27, 0xfd, 0x05, 0x7a, 0xee, 0x39,
+	0xa3, 0x67, 0x4d, 0x39, 0x5e, 0x91, 0x93, 0xf0, 0xa3, 0xed, 0x81, 0x75, 0x01, 0xbf, 0x79, 0xb0,
+	0xf7, 0xca, 0x93, 0x46, 0x9f, 0x34, 0xe5, 0xbb, 0x5f, 0x75, 0xc2, 0x4f, 0xff, 0x35, 0xbe, 0x2e,
+	0xeb, 0x67, 0xe8, 0x5a, 0xed, 0x40, 0x8d, 0x6f, 0x74, 0x53, 0x7e, 0xc2, 0x67, 0x5b, 0xe3, 0xea,
+	0xec, 0xb7, 0xd0, 0xd6, 0xba, 0x80, 0x1a, 0x5f, 0xeb, 0xba, 0x76, 0x85, 0x4f, 0xb7, 0x44, 0xb9,
+	0xbc, 0x87, 0x9e, 0xda, 0x7f, 0x23, 0x2c, 0xcd, 0xf7, 0x7f, 0x43, 0xb1, 0xc2, 0x93, 0x6d, 0x61,
+	0xeb, 0xfb, 0xaf, 0x9e, 0x61, 0xf3, 0xfd, 0x5f, 0xd3, 0xbb, 0xf0, 0x78, 0x3b, 0x90, 0x4b, 0xfa,
+	0x59, 0xf7, 0x9b, 0xb6, 0x11, 0x86, 0x8e, 0xfe, 0x79, 0xf2, 0xe7, 0x00, 0x05, 0xf6, 0xe4, 0x23,
+	0xcf, 0x0a, 0x00, 0x00,
 }
@@ -0,0 +1,88 @@
+// Package awsjson calls AWS APIs using the JSON 1.1 protocol, which the
+// vendored AWS SDK doesn't implement.
+package awsjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/signer/v4"
+)
+
+// Call calls the operation of an AWS service identified by the target, such
+// as "AmazonSSM.GetParameter". The input and output must be pointers to
+// structs, which are encoded to and decoded from JSON. Requests are signed
+// with the default credential chain unless the configuration has
+// credentials. If the configuration has no region, the region of the EC2
+// instance is used.
+func Call(cfg *aws.Config, service, target string, in, out interface{}) error {
+	sess := session.New(cfg)
+	if aws.StringValue(sess.Config.Region) == "" {
+		region, err := ec2metadata.New(sess).Region()
+		if err != nil {
+			return fmt.Errorf("failed to determine the AWS region: %v", err)
+		}
+		sess.Config.Region = aws.String(region)
+	}
+
+	cc := sess.ClientConfig(service)
+	svc := client.New(
+		*cc.Config,
+		metadata.ClientInfo{
+			ServiceName:   service,
+			SigningRegion: cc.SigningRegion,
+			Endpoint:      cc.Endpoint,
+		},
+		cc.Handlers,
+	)
+	svc.Handlers.Sign.PushBack(v4.Sign)
+	svc.Handlers.Build.PushBack(func(r *request.Request) {
+		body, err := json.Marshal(r.Params)
+		if err != nil {
+			r.Error = awserr.New("SerializationError", "failed to encode request", err)
+			return
+		}
+		r.SetBufferBody(body)
+		r.HTTPRequest.Header.Set("Content-Type", "application/x-amz-json-1.1")
+		r.HTTPRequest.Header.Set("X-Amz-Target", target)
+	})
+	svc.Handlers.UnmarshalMeta.PushBack(func(r *request.Request) {
+		r.RequestID = r.HTTPResponse.Header.Get("X-Amzn-Requestid")
+	})
+	svc.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		defer r.HTTPResponse.Body.Close()
+		if err := json.NewDecoder(r.HTTPResponse.Body).Decode(r.Data); err != nil {
+			r.Error = awserr.New("SerializationError", "failed to decode response", err)
+		}
+	})
+	svc.Handlers.UnmarshalError.PushBack(func(r *request.Request) {
+		defer r.HTTPResponse.Body.Close()
+		var resp struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(r.HTTPResponse.Body).Decode(&resp)
+
+		// The type may be prefixed by the namespace of the error
+		code := resp.Type[strings.LastIndex(resp.Type, "#")+1:]
+		if code == "" {
+			code = "UnknownError"
+		}
+		r.Error = awserr.NewRequestFailure(awserr.New(code, resp.Message, nil), r.HTTPResponse.StatusCode, r.RequestID)
+	})
+
+	op := &request.Operation{
+		Name:       target[strings.LastIndex(target, ".")+1:],
+		HTTPMethod: "POST",
+		HTTPPath:   "/",
+	}
+	return svc.NewRequest(op, in, out).Send()
+}
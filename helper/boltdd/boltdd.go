@@ -37,12 +37,26 @@ func IsErrNotFound(e error) bool {
 	return ok
 }
 
+// encryptedMarker prefixes the encrypted values of buckets. It is never used
+// by msgpack so it can't be the first byte of a plaintext value.
+const encryptedMarker = 0xc1
+
+// Cipher encrypts and decrypts the values of the buckets of a DB.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
 // DB wraps an underlying bolt.DB to create write deduplicating buckets and
 // msgpack encoded values.
 type DB struct {
 	rootBuckets     map[string]*bucketMeta
 	rootBucketsLock sync.Mutex
 
+	// cipher encrypts the values of buckets if set
+	cipher     Cipher
+	cipherLock sync.RWMutex
+
 	bdb *bolt.DB
 }
 
@@ -65,6 +79,53 @@ func New(bdb *bolt.DB) *DB {
 	}
 }
 
+// SetCipher sets the cipher encrypting the values written from now on and
+// decrypting the encrypted values read. Values written before remain in
+// plaintext until they are written again or encrypted with
+// Bucket.EncryptValues.
+func (db *DB) SetCipher(c Cipher) {
+	db.cipherLock.Lock()
+	defer db.cipherLock.Unlock()
+	db.cipher = c
+}
+
+func (db *DB) getCipher() Cipher {
+	db.cipherLock.RLock()
+	defer db.cipherLock.RUnlock()
+	return db.cipher
+}
+
+// seal encrypts the value if the DB has a cipher.
+func (db *DB) seal(val []byte) ([]byte, error) {
+	c := db.getCipher()
+	if c == nil {
+		return val, nil
+	}
+
+	ciphertext, err := c.Encrypt(val)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt value: %v", err)
+	}
+	return append([]byte{encryptedMarker}, ciphertext...), nil
+}
+
+// open decrypts the value if it is encrypted.
+func (db *DB) open(val []byte) ([]byte, error) {
+	if len(val) == 0 || val[0] != encryptedMarker {
+		return val, nil
+	}
+
+	c := db.getCipher()
+	if c == nil {
+		return nil, fmt.Errorf("value is encrypted but no cipher is set")
+	}
+	plaintext, err := c.Decrypt(val[1:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %v", err)
+	}
+	return plaintext, nil
+}
+
 func (db *DB) bucket(btx *bolt.Tx, name []byte) *Bucket {
 	bb := btx.Bucket(name)
 	if bb == nil {
@@ -84,7 +145,7 @@ func (db *DB) bucket(btx *bolt.Tx, name []byte) *Bucket {
 		db.rootBuckets[string(name)] = b
 	}
 
-	return newBucket(b, bb)
+	return newBucket(db, b, bb)
 }
 
 func (db *DB) createBucket(btx *bolt.Tx, name []byte) (*Bucket, error) {
@@ -107,7 +168,7 @@ func (db *DB) createBucket(btx *bolt.Tx, name []byte) (*Bucket, error) {
 	b := newBucketMeta()
 	db.rootBuckets[string(name)] = b
 
-	return newBucket(b, bb), nil
+	return newBucket(db, b, bb), nil
 }
 
 func (db *DB) createBucketIfNotExists(btx *bolt.Tx, name []byte) (*Bucket, error) {
@@ -131,7 +192,7 @@ func (db *DB) createBucketIfNotExists(btx *bolt.Tx, name []byte) (*Bucket, error
 		db.rootBuckets[string(name)] = b
 	}
 
-	return newBucket(b, bb), nil
+	return newBucket(db, b, bb), nil
 }
 
 func (db *DB) Update(fn func(*Tx) error) error {
@@ -282,14 +343,16 @@ func (bm *bucketMeta) getOrCreateBucket(name []byte) *bucketMeta {
 }
 
 type Bucket struct {
+	db         *DB
 	bm         *bucketMeta
 	boltBucket *bolt.Bucket
 }
 
 // newBucket creates a new view into a bucket backed by a boltdb
 // transaction.
-func newBucket(b *bucketMeta, bb *bolt.Bucket) *Bucket {
+func newBucket(db *DB, b *bucketMeta, bb *bolt.Bucket) *Bucket {
 	return &Bucket{
+		db:         db,
 		bm:         b,
 		boltBucket: bb,
 	}
@@ -317,8 +380,12 @@ func (b *Bucket) Put(key []byte, val interface{}) error {
 		return nil
 	}
 
-	// New value: write it to the underlying boltdb
-	if err := b.boltBucket.Put(key, buf.Bytes()); err != nil {
+	// New value: encrypt it if needed and write it to the underlying boltdb
+	data, err := b.db.seal(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := b.boltBucket.Put(key, data); err != nil {
 		return fmt.Errorf("failed to write data at key %s: %v", key, err)
 	}
 
@@ -338,6 +405,11 @@ func (b *Bucket) Get(key []byte, obj interface{}) error {
 		return NotFound(string(key))
 	}
 
+	data, err := b.db.open(data)
+	if err != nil {
+		return err
+	}
+
 	// Deserialize the object
 	if err := codec.NewDecoderBytes(data, structs.MsgpackHandle).Decode(obj); err != nil {
 		return fmt.Errorf("failed to decode data into passed object: %v", err)
@@ -365,7 +437,7 @@ func (b *Bucket) Bucket(name []byte) *Bucket {
 	}
 
 	bmeta := b.bm.getOrCreateBucket(name)
-	return newBucket(bmeta, bb)
+	return newBucket(b.db, bmeta, bb)
 }
 
 // CreateBucket creates a new bucket at the given key and returns the new
@@ -379,7 +451,7 @@ func (b *Bucket) CreateBucket(name []byte) (*Bucket, error) {
 	}
 
 	bmeta := b.bm.createBucket(name)
-	return newBucket(bmeta, bb), nil
+	return newBucket(b.db, bmeta, bb), nil
 }
 
 // CreateBucketIfNotExists creates a new bucket if it doesn't already exist and
@@ -392,7 +464,7 @@ func (b *Bucket) CreateBucketIfNotExists(name []byte) (*Bucket, error) {
 	}
 
 	bmeta := b.bm.getOrCreateBucket(name)
-	return newBucket(bmeta, bb), nil
+	return newBucket(b.db, bmeta, bb), nil
 }
 
 // DeleteBucket deletes a child bucket. Returns an error if the bucket
@@ -410,6 +482,49 @@ func (b *Bucket) DeleteBucket(name []byte) error {
 	return err
 }
 
+// EncryptValues encrypts the plaintext values of the bucket and its nested
+// buckets with the cipher of the DB. It returns the number of values
+// encrypted.
+func (b *Bucket) EncryptValues() (int, error) {
+	if b.db.getCipher() == nil {
+		return 0, fmt.Errorf("no cipher is set")
+	}
+
+	// Collect the plaintext values and nested buckets first as buckets
+	// can't be modified while iterating over them
+	var keys, buckets [][]byte
+	c := b.boltBucket.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		key := append([]byte(nil), k...)
+		if v == nil {
+			buckets = append(buckets, key)
+		} else if len(v) != 0 && v[0] != encryptedMarker {
+			keys = append(keys, key)
+		}
+	}
+
+	n := 0
+	for _, k := range keys {
+		data, err := b.db.seal(b.boltBucket.Get(k))
+		if err != nil {
+			return n, err
+		}
+		if err := b.boltBucket.Put(k, data); err != nil {
+			return n, fmt.Errorf("failed to write data at key %s: %v", k, err)
+		}
+		n++
+	}
+
+	for _, name := range buckets {
+		nested, err := b.Bucket(name).EncryptValues()
+		n += nested
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
 // BoltBucket returns the internal bolt.Bucket for this Bucket. Only valid
 // for the duration of the current transaction.
 func (b *Bucket) BoltBucket() *bolt.Bucket {
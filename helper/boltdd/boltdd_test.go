@@ -165,6 +165,93 @@ func TestBucket_DedupeWrites(t *testing.T) {
 	require.Equal(putWrites+3, putWrites2)
 }
 
+// xorCipher is an insecure Cipher for testing.
+type xorCipher byte
+
+func (c xorCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	out := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		out[i] = b ^ byte(c)
+	}
+	return out, nil
+}
+
+func (c xorCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return c.Encrypt(ciphertext)
+}
+
+func TestBucket_Cipher(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	db, cleanup := setupBoltDB(t)
+	defer cleanup()
+
+	name := []byte("cipher_test")
+	childName := []byte("child")
+	plainKey := []byte("plain")
+	childKey := []byte("child_plain")
+	encryptedKey := []byte("encrypted")
+
+	// Write plaintext values before the cipher is set
+	require.NoError(db.Update(func(tx *Tx) error {
+		b, err := tx.CreateBucket(name)
+		require.NoError(err)
+		require.NoError(b.Put(plainKey, plainKey))
+
+		child, err := b.CreateBucket(childName)
+		require.NoError(err)
+		return child.Put(childKey, childKey)
+	}))
+
+	// Values written with the cipher are encrypted and plaintext values
+	// are still readable
+	db.SetCipher(xorCipher(0x42))
+	require.NoError(db.Update(func(tx *Tx) error {
+		b := tx.Bucket(name)
+		require.NoError(b.Put(encryptedKey, encryptedKey))
+		require.Equal(byte(encryptedMarker), b.BoltBucket().Get(encryptedKey)[0])
+		require.NotEqual(byte(encryptedMarker), b.BoltBucket().Get(plainKey)[0])
+
+		var v []byte
+		require.NoError(b.Get(encryptedKey, &v))
+		require.Equal(encryptedKey, v)
+		require.NoError(b.Get(plainKey, &v))
+		require.Equal(plainKey, v)
+		return nil
+	}))
+
+	// Encrypting the values of the bucket encrypts the remaining plaintext
+	// values, including the ones of nested buckets
+	require.NoError(db.Update(func(tx *Tx) error {
+		n, err := tx.Bucket(name).EncryptValues()
+		require.NoError(err)
+		require.Equal(2, n)
+		return nil
+	}))
+	require.NoError(db.View(func(tx *Tx) error {
+		b := tx.Bucket(name)
+		child := b.Bucket(childName)
+		require.Equal(byte(encryptedMarker), b.BoltBucket().Get(plainKey)[0])
+		require.Equal(byte(encryptedMarker), child.BoltBucket().Get(childKey)[0])
+
+		var v []byte
+		require.NoError(child.Get(childKey, &v))
+		require.Equal(childKey, v)
+		return nil
+	}))
+
+	// Encrypted values can't be read without the cipher
+	db.SetCipher(nil)
+	require.NoError(db.View(func(tx *Tx) error {
+		var v []byte
+		err := tx.Bucket(name).Get(plainKey, &v)
+		require.Error(err)
+		require.Contains(err.Error(), "value is encrypted but no cipher is set")
+		return nil
+	}))
+}
+
 func TestBucket_Delete(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
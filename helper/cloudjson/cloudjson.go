@@ -0,0 +1,127 @@
+// Package cloudjson calls the JSON APIs of cloud providers that the vendored
+// SDKs don't cover, and gets the access tokens of the GCE instance from its
+// metadata server.
+package cloudjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultGCPMetadataURL is the address of the GCE metadata server
+	DefaultGCPMetadataURL = "http://metadata.google.internal/computeMetadata/v1"
+
+	// gcpTokenExpiryMargin is how long before their expiry cached access
+	// tokens are renewed
+	gcpTokenExpiryMargin = time.Minute
+)
+
+// StatusError is returned by Do if the response isn't successful.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response code %d: %s", e.Code, e.Message)
+}
+
+// IsNotFound returns whether the error is a StatusError for a resource that
+// doesn't exist.
+func IsNotFound(err error) bool {
+	statusErr, ok := err.(*StatusError)
+	return ok && statusErr.Code == http.StatusNotFound
+}
+
+// Do sends the request and decodes the JSON response into out.
+func Do(client *http.Client, req *http.Request, out interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// GCPInstanceToken returns an access token of the default service account of
+// the GCE instance and its expiry time.
+func GCPInstanceToken(client *http.Client, metadataURL string) (string, time.Time, error) {
+	req, err := http.NewRequest("GET", metadataURL+"/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := Do(client, req, &out); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get access token: %v", err)
+	}
+	return out.AccessToken, time.Now().Add(time.Duration(out.ExpiresIn) * time.Second), nil
+}
+
+// GCPMetadata returns a value of the GCE metadata server, such as
+// "project/project-id".
+func GCPMetadata(client *http.Client, metadataURL, path string) (string, error) {
+	req, err := http.NewRequest("GET", metadataURL+"/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// GCPTokenCache caches the access token of the default service account of
+// the GCE instance until shortly before it expires. The zero value is ready
+// to use.
+type GCPTokenCache struct {
+	token  string
+	expiry time.Time
+	lock   sync.Mutex
+}
+
+// Token returns the cached access token, getting a new one from the metadata
+// server if there is none or it is about to expire.
+func (c *GCPTokenCache) Token(client *http.Client, metadataURL string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.token != "" && time.Now().Add(gcpTokenExpiryMargin).Before(c.expiry) {
+		return c.token, nil
+	}
+
+	token, expiry, err := GCPInstanceToken(client, metadataURL)
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expiry = token, expiry
+	return token, nil
+}
@@ -20,6 +20,10 @@ func (i *InmemCodec) ReadRequestHeader(req *rpc.Request) error {
 }
 
 func (i *InmemCodec) ReadRequestBody(args interface{}) error {
+	// args is nil when the request is discarded, such as for unknown methods
+	if args == nil {
+		return nil
+	}
 	sourceValue := reflect.Indirect(reflect.Indirect(reflect.ValueOf(i.Args)))
 	dst := reflect.Indirect(reflect.Indirect(reflect.ValueOf(args)))
 	dst.Set(sourceValue)
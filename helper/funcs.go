@@ -316,6 +316,19 @@ func CopySliceInt(s []int) []int {
 	return c
 }
 
+func CopySliceUint16(s []uint16) []uint16 {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	c := make([]uint16, l)
+	for i, v := range s {
+		c[i] = v
+	}
+	return c
+}
+
 // CleanEnvVar replaces all occurrences of illegal characters in an environment
 // variable with the specified byte.
 func CleanEnvVar(s string, r byte) string {
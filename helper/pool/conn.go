@@ -12,4 +12,8 @@ const (
 	// RpcMultiplexV2 allows a multiplexed connection to switch modes between
 	// RpcNomad and RpcStreaming per opened stream.
 	RpcMultiplexV2 = 0x06
+
+	// RpcCompressed switches the rest of the connection to a compressed
+	// stream, starting with the byte of the mode of the connection.
+	RpcCompressed = 0x07
 )
@@ -0,0 +1,91 @@
+package pool
+
+import (
+	"compress/flate"
+	"context"
+	"io"
+	"net"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// compressedConn compresses the data written to a connection and decompresses
+// the data read from it.
+type compressedConn struct {
+	net.Conn
+
+	r io.ReadCloser
+
+	w     *flate.Writer
+	wLock sync.Mutex
+}
+
+// NewCompressedConn returns a connection compressing the data written to conn
+// and decompressing the data read from it. Both ends of the connection must be
+// wrapped, which is negotiated by the RpcCompressed byte.
+func NewCompressedConn(conn net.Conn) net.Conn {
+	// NewWriter only fails for invalid compression levels
+	w, _ := flate.NewWriter(conn, flate.BestSpeed)
+	return &compressedConn{
+		Conn: conn,
+		r:    flate.NewReader(conn),
+		w:    w,
+	}
+}
+
+func (c *compressedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+// Write compresses and flushes the data right away, since RPCs wait for the
+// response to what they wrote.
+func (c *compressedConn) Write(b []byte) (int, error) {
+	c.wLock.Lock()
+	defer c.wLock.Unlock()
+
+	n, err := c.w.Write(b)
+	if err != nil {
+		return n, err
+	}
+	return n, c.w.Flush()
+}
+
+// limitedConn limits the bandwidth of the data written to a connection.
+type limitedConn struct {
+	net.Conn
+
+	limiter *rate.Limiter
+}
+
+// NewLimitedConn returns a connection waiting for the limiter before writing
+// to conn. The limiter may be shared between connections to limit their total
+// bandwidth.
+func NewLimitedConn(conn net.Conn, limiter *rate.Limiter) net.Conn {
+	return &limitedConn{
+		Conn:    conn,
+		limiter: limiter,
+	}
+}
+
+// Write writes at most the burst of the limiter at a time.
+func (c *limitedConn) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		n := len(b)
+		if burst := c.limiter.Burst(); n > burst {
+			n = burst
+		}
+		if err := c.limiter.WaitN(context.Background(), n); err != nil {
+			return written, err
+		}
+
+		m, err := c.Conn.Write(b[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
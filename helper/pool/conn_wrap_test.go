@@ -0,0 +1,60 @@
+package pool
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestCompressedConn(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	client, server := NewCompressedConn(c1), NewCompressedConn(c2)
+
+	// Each write is readable right away
+	for _, msg := range []string{"ping", "pong"} {
+		go client.Write([]byte(msg))
+		buf := make([]byte, len(msg))
+		_, err := io.ReadFull(server, buf)
+		require.NoError(err)
+		require.Equal(msg, string(buf))
+	}
+
+	// Large writes round trip
+	payload := bytes.Repeat([]byte("nomad log line\n"), 10000)
+	go server.Write(payload)
+	buf := make([]byte, len(payload))
+	_, err := io.ReadFull(client, buf)
+	require.NoError(err)
+	require.Equal(payload, buf)
+}
+
+func TestLimitedConn(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	// Writing twice the burst waits for the limiter to refill
+	limiter := rate.NewLimiter(rate.Limit(1000), 100)
+	conn := NewLimitedConn(c1, limiter)
+	go io.Copy(ioutil.Discard, c2)
+
+	start := time.Now()
+	n, err := conn.Write(make([]byte, 200))
+	require.NoError(err)
+	require.Equal(200, n)
+	require.True(time.Since(start) >= 90*time.Millisecond, "wrote in %s", time.Since(start))
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/yamux"
+	"golang.org/x/time/rate"
 )
 
 // NewClientCodec returns a new rpc.ClientCodec to be used to make RPC calls.
@@ -144,6 +145,13 @@ type ConnPool struct {
 	// TLS wrapper
 	tlsWrap tlsutil.RegionWrapper
 
+	// compression enables the compression of new connections
+	compression bool
+
+	// bandwidthLimiter limits the bandwidth of the data written to all the
+	// connections of the pool if not nil
+	bandwidthLimiter *rate.Limiter
+
 	// Used to indicate the pool is shutdown
 	shutdown   bool
 	shutdownCh chan struct{}
@@ -211,6 +219,30 @@ func (p *ConnPool) ReloadTLS(tlsWrap tlsutil.RegionWrapper) {
 	p.tlsWrap = tlsWrap
 }
 
+// SetCompression sets whether new connections are compressed. Existing
+// connections are closed so that they are reopened with the setting.
+func (p *ConnPool) SetCompression(enabled bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.compression == enabled {
+		return
+	}
+	for _, conn := range p.pool {
+		conn.Close()
+	}
+	p.pool = make(map[string]*Conn)
+	p.compression = enabled
+}
+
+// SetBandwidthLimiter sets the limiter shared by new connections to limit the
+// bandwidth of the data they write. A nil limiter disables the limit.
+func (p *ConnPool) SetBandwidthLimiter(limiter *rate.Limiter) {
+	p.Lock()
+	defer p.Unlock()
+	p.bandwidthLimiter = limiter
+}
+
 // SetConnListener is used to listen to new connections being made. The
 // channel will be closed when the conn pool is closed or a new listener is set.
 func (p *ConnPool) SetConnListener(l chan<- *yamux.Session) {
@@ -312,8 +344,17 @@ func (p *ConnPool) getNewConn(region string, addr net.Addr, version int) (*Conn,
 		tcp.SetNoDelay(true)
 	}
 
+	p.Lock()
+	tlsWrap, compression, limiter := p.tlsWrap, p.compression, p.bandwidthLimiter
+	p.Unlock()
+
+	// Limit the bandwidth of the data sent on the wire
+	if limiter != nil {
+		conn = NewLimitedConn(conn, limiter)
+	}
+
 	// Check if TLS is enabled
-	if p.tlsWrap != nil {
+	if tlsWrap != nil {
 		// Switch the connection into TLS mode
 		if _, err := conn.Write([]byte{byte(RpcTLS)}); err != nil {
 			conn.Close()
@@ -321,7 +362,7 @@ func (p *ConnPool) getNewConn(region string, addr net.Addr, version int) (*Conn,
 		}
 
 		// Wrap the connection in a TLS client
-		tlsConn, err := p.tlsWrap(region, conn)
+		tlsConn, err := tlsWrap(region, conn)
 		if err != nil {
 			conn.Close()
 			return nil, err
@@ -329,6 +370,15 @@ func (p *ConnPool) getNewConn(region string, addr net.Addr, version int) (*Conn,
 		conn = tlsConn
 	}
 
+	// Check if compression is enabled
+	if compression {
+		if _, err := conn.Write([]byte{byte(RpcCompressed)}); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = NewCompressedConn(conn)
+	}
+
 	// Write the multiplex byte to set the mode
 	if _, err := conn.Write([]byte{byte(RpcMultiplex)}); err != nil {
 		conn.Close()
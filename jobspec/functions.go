@@ -0,0 +1,324 @@
+package jobspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// function is a function that can be called by the expressions of a job file.
+// Functions are evaluated when the job file is parsed.
+type function func(args []ast.Node) (ast.Node, error)
+
+// functions are the functions that can be called by job files, by name.
+var functions map[string]function
+
+func init() {
+	// Set on init since templatefile evaluates the functions called by the
+	// template
+	functions = map[string]function{
+		"cidrhost":     cidrhostFunc,
+		"cidrnetmask":  cidrnetmaskFunc,
+		"cidrsubnet":   cidrsubnetFunc,
+		"file":         fileFunc,
+		"jsondecode":   jsondecodeFunc,
+		"templatefile": templatefileFunc,
+		"yamldecode":   yamldecodeFunc,
+	}
+}
+
+// fileFunc returns the contents of the file at the given path.
+func fileFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 1); err != nil {
+		return nil, err
+	}
+	path, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return stringNode(string(contents)), nil
+}
+
+// templatefileFunc renders the template file at the given path. The ${...}
+// sequences of the template reference the variables of the given map by name
+// and can call functions. Other sequences are left to the runtime
+// interpolation.
+func templatefileFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 2); err != nil {
+		return nil, err
+	}
+	path, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	vars, ok := args[1].(*ast.ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("argument 2 must be a map")
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &scope{names: make(map[string]ast.Node, len(vars.List.Items))}
+	for _, item := range vars.List.Items {
+		if len(item.Keys) != 1 {
+			return nil, fmt.Errorf("argument 2 must be a map of variables")
+		}
+		s.names[fmt.Sprintf("%v", item.Keys[0].Token.Value())] = item.Val
+	}
+
+	out, err := s.evalTemplate(string(contents), false)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %v", path, err)
+	}
+	if out == nil {
+		return stringNode(string(contents)), nil
+	}
+	return out, nil
+}
+
+// jsondecodeFunc decodes the given JSON string.
+func jsondecodeFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 1); err != nil {
+		return nil, err
+	}
+	str, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader([]byte(str)))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return valueNode(v)
+}
+
+// yamldecodeFunc decodes the given YAML string.
+func yamldecodeFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 1); err != nil {
+		return nil, err
+	}
+	str, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(str), &v); err != nil {
+		return nil, err
+	}
+	return valueNode(v)
+}
+
+// cidrhostFunc returns the address of the given host number within the
+// prefix. Negative host numbers count back from the end of the prefix.
+func cidrhostFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 2); err != nil {
+		return nil, err
+	}
+	network, err := prefixArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	hostnum, err := intArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := network.Mask.Size()
+	hosts := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	num := big.NewInt(hostnum)
+	if hostnum < 0 {
+		num.Add(num, hosts)
+	}
+	if num.Sign() < 0 || num.Cmp(hosts) >= 0 {
+		return nil, fmt.Errorf("prefix %s has no host number %d", network, hostnum)
+	}
+
+	ip := new(big.Int).SetBytes(network.IP)
+	return stringNode(intToIP(ip.Or(ip, num), len(network.IP)).String()), nil
+}
+
+// cidrnetmaskFunc returns the netmask of the given IPv4 prefix.
+func cidrnetmaskFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 1); err != nil {
+		return nil, err
+	}
+	network, err := prefixArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(network.Mask) != net.IPv4len {
+		return nil, fmt.Errorf("only IPv4 prefixes have a netmask")
+	}
+	return stringNode(net.IP(network.Mask).String()), nil
+}
+
+// cidrsubnetFunc returns the prefix of the given subnet number within the
+// prefix extended by the given number of bits.
+func cidrsubnetFunc(args []ast.Node) (ast.Node, error) {
+	if err := checkArgs(args, 3); err != nil {
+		return nil, err
+	}
+	network, err := prefixArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	newbits, err := intArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	netnum, err := intArg(args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := network.Mask.Size()
+	if newbits < 0 || int64(ones)+newbits > int64(bits) {
+		return nil, fmt.Errorf("prefix %s can not be extended by %d bits", network, newbits)
+	}
+	subnets := new(big.Int).Lsh(big.NewInt(1), uint(newbits))
+	num := big.NewInt(netnum)
+	if num.Sign() < 0 || num.Cmp(subnets) >= 0 {
+		return nil, fmt.Errorf("prefix %s extended by %d bits has no subnet number %d", network, newbits, netnum)
+	}
+
+	ip := new(big.Int).SetBytes(network.IP)
+	ip.Or(ip, num.Lsh(num, uint(int64(bits-ones)-newbits)))
+	return stringNode(fmt.Sprintf("%s/%d", intToIP(ip, len(network.IP)), int64(ones)+newbits)), nil
+}
+
+// intToIP returns the IP address of the given length with the value of i.
+func intToIP(i *big.Int, length int) net.IP {
+	b := i.Bytes()
+	ip := make(net.IP, length)
+	copy(ip[length-len(b):], b)
+	return ip
+}
+
+// checkArgs checks the number of arguments of a function call.
+func checkArgs(args []ast.Node, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d arguments, got %d", n, len(args))
+	}
+	return nil
+}
+
+// stringArg returns the argument at the given index as a string.
+func stringArg(args []ast.Node, i int) (string, error) {
+	lit, ok := args[i].(*ast.LiteralType)
+	if !ok {
+		return "", fmt.Errorf("argument %d must be a string", i+1)
+	}
+	return fmt.Sprintf("%v", lit.Token.Value()), nil
+}
+
+// intArg returns the argument at the given index as an integer.
+func intArg(args []ast.Node, i int) (int64, error) {
+	if lit, ok := args[i].(*ast.LiteralType); ok {
+		switch v := lit.Token.Value().(type) {
+		case int64:
+			return v, nil
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("argument %d must be a whole number", i+1)
+}
+
+// prefixArg returns the argument at the given index as an IP prefix.
+func prefixArg(args []ast.Node, i int) (*net.IPNet, error) {
+	str, err := stringArg(args, i)
+	if err != nil {
+		return nil, err
+	}
+	_, network, err := net.ParseCIDR(str)
+	if err != nil {
+		return nil, fmt.Errorf("argument %d must be an IP prefix: %v", i+1, err)
+	}
+	return network, nil
+}
+
+// valueNode returns the node of the given decoded value. Maps are sorted by
+// key.
+func valueNode(v interface{}) (ast.Node, error) {
+	switch v := v.(type) {
+	case string:
+		return stringNode(v), nil
+	case bool:
+		return &ast.LiteralType{Token: token.Token{Type: token.BOOL, Text: strconv.FormatBool(v)}}, nil
+	case int:
+		return numberNode(int64(v)), nil
+	case int64:
+		return numberNode(v), nil
+	case uint64:
+		return &ast.LiteralType{Token: token.Token{Type: token.FLOAT, Text: strconv.FormatUint(v, 10)}}, nil
+	case float64:
+		return &ast.LiteralType{Token: token.Token{Type: token.FLOAT, Text: strconv.FormatFloat(v, 'f', -1, 64)}}, nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return numberNode(n), nil
+		}
+		return &ast.LiteralType{Token: token.Token{Type: token.FLOAT, Text: v.String()}}, nil
+	case []interface{}:
+		list := &ast.ListType{List: make([]ast.Node, len(v))}
+		for i, elem := range v {
+			n, err := valueNode(elem)
+			if err != nil {
+				return nil, err
+			}
+			list.List[i] = n
+		}
+		return list, nil
+	case map[string]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for key, elem := range v {
+			m[key] = elem
+		}
+		return valueNode(m)
+	case map[interface{}]interface{}:
+		keys := make([]string, 0, len(v))
+		elems := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			k := fmt.Sprintf("%v", key)
+			keys = append(keys, k)
+			elems[k] = elem
+		}
+		sort.Strings(keys)
+
+		obj := &ast.ObjectType{List: &ast.ObjectList{}}
+		for _, key := range keys {
+			n, err := valueNode(elems[key])
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", key, err)
+			}
+			obj.List.Add(&ast.ObjectItem{Keys: []*ast.ObjectKey{stringKey(key)}, Val: n})
+		}
+		return obj, nil
+	case nil:
+		return nil, fmt.Errorf("null values are not supported")
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", v)
+}
@@ -0,0 +1,741 @@
+package jobspec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// scope resolves the references of the expressions of a job file. References
+// that are not resolved by the scope, such as ${node.datacenter}, are left to
+// the runtime interpolation of the clients.
+type scope struct {
+	// vars are the variables declared by the job file, referenced as
+	// var.<name>. Nil if variables can't be referenced.
+	vars map[string]*variable
+
+	// names are the values referenced by their name, such as the iterators
+	// of dynamic blocks and the variables of templates.
+	names map[string]ast.Node
+
+	parent *scope
+}
+
+// interpolate returns a copy of the given list with the expressions of its
+// strings evaluated and its dynamic blocks expanded.
+func interpolate(list *ast.ObjectList, declared map[string]*variable) (*ast.ObjectList, error) {
+	s := &scope{vars: declared}
+	return s.evalList(list)
+}
+
+// child returns a scope that also resolves the given name.
+func (s *scope) child(name string, value ast.Node) *scope {
+	return &scope{
+		vars:   s.vars,
+		names:  map[string]ast.Node{name: value},
+		parent: s,
+	}
+}
+
+// lookup returns the value of the given name.
+func (s *scope) lookup(name string) (ast.Node, bool) {
+	for ; s != nil; s = s.parent {
+		if n, ok := s.names[name]; ok {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+// resolves returns whether the expression is evaluated by the scope rather
+// than left to the runtime interpolation.
+func (s *scope) resolves(e expr) bool {
+	ref, ok := e.(refExpr)
+	if !ok {
+		return true
+	}
+	if ref[0] == "var" && s.vars != nil {
+		return true
+	}
+	_, ok = s.lookup(ref[0])
+	return ok
+}
+
+// evalList returns a copy of the list with its expressions evaluated and its
+// dynamic blocks expanded.
+func (s *scope) evalList(list *ast.ObjectList) (*ast.ObjectList, error) {
+	var mErr multierror.Error
+	out := &ast.ObjectList{Items: make([]*ast.ObjectItem, 0, len(list.Items))}
+	for _, item := range list.Items {
+		if isDynamic(item) {
+			items, err := s.expandDynamic(item)
+			if err != nil {
+				multierror.Append(&mErr, err)
+				continue
+			}
+			out.Items = append(out.Items, items...)
+			continue
+		}
+
+		o := *item
+		o.Keys = make([]*ast.ObjectKey, len(item.Keys))
+		for i, key := range item.Keys {
+			k := *key
+			replaced, err := s.evalToken(key.Token, false)
+			if err != nil {
+				multierror.Append(&mErr, err)
+			} else if replaced != nil {
+				k.Token = replaced.(*ast.LiteralType).Token
+			}
+			o.Keys[i] = &k
+		}
+
+		val, err := s.evalNode(item.Val)
+		if err != nil {
+			multierror.Append(&mErr, err)
+			continue
+		}
+		o.Val = val
+		out.Items = append(out.Items, &o)
+	}
+	return out, mErr.ErrorOrNil()
+}
+
+// evalNode returns a copy of the node with its expressions evaluated and its
+// dynamic blocks expanded.
+func (s *scope) evalNode(node ast.Node) (ast.Node, error) {
+	switch n := node.(type) {
+	case *ast.ObjectList:
+		return s.evalList(n)
+	case *ast.ObjectType:
+		list, err := s.evalList(n.List)
+		if err != nil {
+			return nil, err
+		}
+		o := *n
+		o.List = list
+		return &o, nil
+	case *ast.ListType:
+		var mErr multierror.Error
+		l := *n
+		l.List = make([]ast.Node, len(n.List))
+		for i, elem := range n.List {
+			val, err := s.evalNode(elem)
+			if err != nil {
+				multierror.Append(&mErr, err)
+				continue
+			}
+			l.List[i] = val
+		}
+		if err := mErr.ErrorOrNil(); err != nil {
+			return nil, err
+		}
+		return &l, nil
+	case *ast.LiteralType:
+		replaced, err := s.evalToken(n.Token, true)
+		if err != nil {
+			return nil, err
+		} else if replaced != nil {
+			return replaced, nil
+		}
+	}
+	return node, nil
+}
+
+// evalToken returns the replacement of the given token, or nil if it has no
+// expressions evaluated by the scope. Only strings are returned unless typed
+// values are allowed.
+func (s *scope) evalToken(tok token.Token, typed bool) (ast.Node, error) {
+	if tok.Type != token.STRING && tok.Type != token.HEREDOC {
+		return nil, nil
+	}
+	str, ok := tok.Value().(string)
+	if !ok {
+		return nil, nil
+	}
+
+	replaced, err := s.evalTemplate(str, typed)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", tok.Pos, err)
+	}
+	return replaced, nil
+}
+
+// evalTemplate evaluates the ${...} expressions of the given string, or
+// returns nil if it has none evaluated by the scope. A string that is a single
+// expression is replaced by its value, keeping its type, if typed values are
+// allowed.
+func (s *scope) evalTemplate(str string, typed bool) (ast.Node, error) {
+	parts := parseTemplate(str)
+
+	evaluated := false
+	for _, p := range parts {
+		if p.expr != nil && s.resolves(p.expr) {
+			evaluated = true
+			break
+		}
+	}
+	if !evaluated {
+		return nil, nil
+	}
+
+	if typed && len(parts) == 1 {
+		return s.eval(parts[0].expr)
+	}
+
+	var buf strings.Builder
+	for _, p := range parts {
+		if p.expr == nil || !s.resolves(p.expr) {
+			buf.WriteString(p.text)
+			continue
+		}
+
+		val, err := s.eval(p.expr)
+		if err != nil {
+			return nil, err
+		}
+		lit, ok := val.(*ast.LiteralType)
+		if !ok {
+			typ := varNodeType(val)
+			if ref, ok := p.expr.(refExpr); ok && ref[0] == "var" && len(ref) == 2 {
+				return nil, fmt.Errorf("variable %q of type %s can not be interpolated in a string", ref[1], typ)
+			}
+			return nil, fmt.Errorf("%s is of type %s and can not be interpolated in a string", p.text, typ)
+		}
+		buf.WriteString(fmt.Sprintf("%v", lit.Token.Value()))
+	}
+	return stringNode(buf.String()), nil
+}
+
+// eval returns the value of the expression.
+func (s *scope) eval(e expr) (ast.Node, error) {
+	switch e := e.(type) {
+	case *ast.LiteralType:
+		return e, nil
+	case refExpr:
+		return s.resolve(e)
+	case listExpr:
+		l := &ast.ListType{List: make([]ast.Node, len(e))}
+		for i, elem := range e {
+			val, err := s.eval(elem)
+			if err != nil {
+				return nil, err
+			}
+			l.List[i] = val
+		}
+		return l, nil
+	case *objectExpr:
+		o := &ast.ObjectType{List: &ast.ObjectList{}}
+		for i, key := range e.keys {
+			val, err := s.eval(e.values[i])
+			if err != nil {
+				return nil, err
+			}
+			o.List.Add(&ast.ObjectItem{Keys: []*ast.ObjectKey{stringKey(key)}, Val: val})
+		}
+		return o, nil
+	case *callExpr:
+		fn, ok := functions[e.name]
+		if !ok {
+			return nil, fmt.Errorf("call to unknown function %q", e.name)
+		}
+		args := make([]ast.Node, len(e.args))
+		for i, arg := range e.args {
+			val, err := s.eval(arg)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = val
+		}
+		out, err := fn(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", e.name, err)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("invalid expression")
+}
+
+// resolve returns the value of the reference, following the attributes of
+// maps.
+func (s *scope) resolve(ref refExpr) (ast.Node, error) {
+	var node ast.Node
+	attrs := ref[1:]
+	if ref[0] == "var" && s.vars != nil {
+		if len(ref) < 2 {
+			return nil, fmt.Errorf("reference to var must name a variable")
+		}
+		v, ok := s.vars[ref[1]]
+		if !ok {
+			return nil, fmt.Errorf("reference to undeclared variable %q", ref[1])
+		}
+		node, attrs = v.value, ref[2:]
+	} else {
+		n, ok := s.lookup(ref[0])
+		if !ok {
+			return nil, fmt.Errorf("%q can not be evaluated when the job is parsed", strings.Join(ref, "."))
+		}
+		node = n
+	}
+
+	for i, attr := range attrs {
+		name := strings.Join(ref[:len(ref)-len(attrs)+i], ".")
+		obj, ok := node.(*ast.ObjectType)
+		if !ok {
+			return nil, fmt.Errorf("%q of type %s has no attribute %q", name, varNodeType(node), attr)
+		}
+		node = nil
+		for _, item := range obj.List.Filter(attr).Items {
+			if len(item.Keys) == 0 {
+				node = item.Val
+			}
+		}
+		if node == nil {
+			return nil, fmt.Errorf("%q has no attribute %q", name, attr)
+		}
+	}
+	return node, nil
+}
+
+// isDynamic returns whether the item is a dynamic block.
+func isDynamic(item *ast.ObjectItem) bool {
+	if len(item.Keys) < 2 {
+		return false
+	}
+	key := item.Keys[0].Token
+	return key.Type == token.IDENT && key.Text == "dynamic"
+}
+
+// expandDynamic returns the blocks generated by the dynamic block, one for
+// each element of its for_each value.
+func (s *scope) expandDynamic(item *ast.ObjectItem) ([]*ast.ObjectItem, error) {
+	blockType, ok := item.Keys[1].Token.Value().(string)
+	if !ok || len(item.Keys) != 2 {
+		return nil, fmt.Errorf("%s: dynamic block must have exactly one label, the type of the generated blocks", item.Keys[0].Token.Pos)
+	}
+	prefix := fmt.Sprintf("dynamic '%s':", blockType)
+
+	obj, ok := item.Val.(*ast.ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("%s should be an object", prefix)
+	}
+
+	valid := []string{
+		"content",
+		"for_each",
+		"iterator",
+		"labels",
+	}
+	if err := helper.CheckHCLKeys(obj.List, valid); err != nil {
+		return nil, multierror.Prefix(err, prefix)
+	}
+
+	iterator := blockType
+	if o := obj.List.Filter("iterator"); len(o.Items) > 0 {
+		if err := hcl.DecodeObject(&iterator, o.Items[0].Val); err != nil {
+			return nil, multierror.Prefix(err, prefix)
+		}
+		if !reVarName.MatchString(iterator) {
+			return nil, fmt.Errorf("%s invalid iterator name %q", prefix, iterator)
+		}
+	}
+
+	var labels ast.Node
+	if o := obj.List.Filter("labels"); len(o.Items) > 0 {
+		labels = o.Items[0].Val
+	}
+
+	o := obj.List.Filter("content")
+	if len(o.Items) != 1 || len(o.Items[0].Keys) != 0 {
+		return nil, fmt.Errorf("%s must have exactly one 'content' block", prefix)
+	}
+	content, ok := o.Items[0].Val.(*ast.ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("%s 'content' should be an object", prefix)
+	}
+
+	o = obj.List.Filter("for_each")
+	if len(o.Items) == 0 {
+		return nil, fmt.Errorf("%s missing 'for_each'", prefix)
+	}
+	forEach, err := s.evalNode(o.Items[0].Val)
+	if err != nil {
+		return nil, multierror.Prefix(err, prefix)
+	}
+
+	// Iterate the elements of lists by index and the items of maps by key
+	var keys, values []ast.Node
+	switch f := forEach.(type) {
+	case *ast.ListType:
+		for i, elem := range f.List {
+			keys = append(keys, numberNode(int64(i)))
+			values = append(values, elem)
+		}
+	case *ast.ObjectType:
+		for _, elem := range f.List.Items {
+			if len(elem.Keys) != 1 {
+				return nil, fmt.Errorf("%s 'for_each' map keys must be attributes", prefix)
+			}
+			keys = append(keys, stringNode(fmt.Sprintf("%v", elem.Keys[0].Token.Value())))
+			values = append(values, elem.Val)
+		}
+	default:
+		return nil, fmt.Errorf("%s 'for_each' must be a list or a map, got %s", prefix, varNodeType(forEach))
+	}
+
+	items := make([]*ast.ObjectItem, 0, len(keys))
+	for i := range keys {
+		child := s.child(iterator, &ast.ObjectType{
+			List: &ast.ObjectList{
+				Items: []*ast.ObjectItem{
+					{Keys: []*ast.ObjectKey{stringKey("key")}, Val: keys[i]},
+					{Keys: []*ast.ObjectKey{stringKey("value")}, Val: values[i]},
+				},
+			},
+		})
+
+		generated := &ast.ObjectItem{
+			Keys: []*ast.ObjectKey{{Token: token.Token{Type: token.IDENT, Text: blockType}}},
+		}
+		if labels != nil {
+			val, err := child.evalNode(labels)
+			if err != nil {
+				return nil, multierror.Prefix(err, prefix)
+			}
+			list, ok := val.(*ast.ListType)
+			if !ok {
+				return nil, fmt.Errorf("%s 'labels' must be a list", prefix)
+			}
+			for _, label := range list.List {
+				lit, ok := label.(*ast.LiteralType)
+				if !ok {
+					return nil, fmt.Errorf("%s 'labels' must be a list of strings", prefix)
+				}
+				generated.Keys = append(generated.Keys, stringKey(fmt.Sprintf("%v", lit.Token.Value())))
+			}
+		}
+
+		val, err := child.evalNode(content)
+		if err != nil {
+			return nil, multierror.Prefix(err, prefix)
+		}
+		generated.Val = val
+		items = append(items, generated)
+	}
+	return items, nil
+}
+
+// stringNode returns a string literal of the given value.
+func stringNode(s string) *ast.LiteralType {
+	// Unquote strings as JSON does, since HCL leaves the escapes within
+	// ${...} sequences untouched
+	return &ast.LiteralType{Token: token.Token{Type: token.STRING, Text: strconv.Quote(s), JSON: true}}
+}
+
+// numberNode returns a number literal of the given value.
+func numberNode(n int64) *ast.LiteralType {
+	return &ast.LiteralType{Token: token.Token{Type: token.NUMBER, Text: strconv.FormatInt(n, 10)}}
+}
+
+// stringKey returns an object key of the given name.
+func stringKey(name string) *ast.ObjectKey {
+	return &ast.ObjectKey{Token: stringNode(name).Token}
+}
+
+// expr is an expression of a ${...} sequence: a literal value, a reference, a
+// list, a map or a function call.
+type expr interface{}
+
+// refExpr is a reference to a name and its attributes, such as var.name.
+type refExpr []string
+
+// listExpr is a list of expressions.
+type listExpr []expr
+
+// objectExpr is a map of expressions.
+type objectExpr struct {
+	keys   []string
+	values []expr
+}
+
+// callExpr is a function call.
+type callExpr struct {
+	name string
+	args []expr
+}
+
+// templatePart is a part of a string, either literal text or a ${...}
+// sequence with its expression.
+type templatePart struct {
+	text string
+	expr expr
+}
+
+// parseTemplate splits the string into literal text and the ${...} sequences
+// it contains. Sequences that are not valid expressions are kept as text.
+func parseTemplate(s string) []templatePart {
+	var parts []templatePart
+	text := func(t string) {
+		if t == "" {
+			return
+		}
+		if n := len(parts); n > 0 && parts[n-1].expr == nil {
+			parts[n-1].text += t
+			return
+		}
+		parts = append(parts, templatePart{text: t})
+	}
+
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			text(s)
+			return parts
+		}
+		text(s[:start])
+
+		end := matchBrace(s, start+2)
+		if end < 0 {
+			text(s[start:])
+			return parts
+		}
+
+		seq := s[start : end+1]
+		if e, err := parseExpr(s[start+2 : end]); err != nil {
+			text(seq)
+		} else {
+			parts = append(parts, templatePart{text: seq, expr: e})
+		}
+		s = s[end+1:]
+	}
+}
+
+// matchBrace returns the index of the brace closing the sequence starting at
+// the given index, skipping quoted strings, or -1 if the sequence is not
+// closed.
+func matchBrace(s string, i int) int {
+	depth := 1
+	quoted := false
+	for ; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// exprParser parses the expression of a ${...} sequence.
+type exprParser struct {
+	s   string
+	pos int
+}
+
+// parseExpr parses the given expression.
+func parseExpr(s string) (expr, error) {
+	p := &exprParser{s: s}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.s) {
+		return nil, fmt.Errorf("unexpected %q", p.s[p.pos:])
+	}
+	return e, nil
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips the given character, returning whether it was next.
+func (p *exprParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) expr() (expr, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return nil, fmt.Errorf("expected an expression")
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '"':
+		str, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		return stringNode(str), nil
+	case c == '-' || isDigit(c):
+		return p.number()
+	case c == '[':
+		p.pos++
+		var list listExpr
+		for !p.consume(']') {
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, e)
+			if !p.consume(',') {
+				if !p.consume(']') {
+					return nil, fmt.Errorf("expected ',' or ']'")
+				}
+				break
+			}
+		}
+		return list, nil
+	case c == '{':
+		p.pos++
+		obj := &objectExpr{}
+		for !p.consume('}') {
+			p.skipSpace()
+			var key string
+			var err error
+			if p.pos < len(p.s) && p.s[p.pos] == '"' {
+				key, err = p.str()
+			} else if key = p.ident(); key == "" {
+				err = fmt.Errorf("expected a map key")
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume('=') && !p.consume(':') {
+				return nil, fmt.Errorf("expected '=' after map key %q", key)
+			}
+			e, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			obj.keys = append(obj.keys, key)
+			obj.values = append(obj.values, e)
+			if !p.consume(',') {
+				if !p.consume('}') {
+					return nil, fmt.Errorf("expected ',' or '}'")
+				}
+				break
+			}
+		}
+		return obj, nil
+	case isIdentStart(c):
+		name := p.ident()
+		if p.consume('(') {
+			return p.call(name)
+		}
+		switch name {
+		case "true", "false":
+			return &ast.LiteralType{Token: token.Token{Type: token.BOOL, Text: name}}, nil
+		}
+
+		ref := refExpr{name}
+		for p.pos < len(p.s) && p.s[p.pos] == '.' {
+			p.pos++
+			attr := p.ident()
+			if attr == "" {
+				return nil, fmt.Errorf("expected an attribute name after %q", strings.Join(ref, "."))
+			}
+			ref = append(ref, attr)
+		}
+		return ref, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", p.s[p.pos:])
+}
+
+// call parses the arguments of a function call.
+func (p *exprParser) call(name string) (expr, error) {
+	call := &callExpr{name: name}
+	for !p.consume(')') {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if !p.consume(',') {
+			if !p.consume(')') {
+				return nil, fmt.Errorf("expected ',' or ')' in call to %q", name)
+			}
+			break
+		}
+	}
+	return call, nil
+}
+
+// ident parses a name, which may contain dashes as the attributes of nodes do.
+func (p *exprParser) ident() string {
+	start := p.pos
+	if p.pos < len(p.s) && isIdentStart(p.s[p.pos]) {
+		p.pos++
+		for p.pos < len(p.s) && (isIdentStart(p.s[p.pos]) || isDigit(p.s[p.pos]) || p.s[p.pos] == '-') {
+			p.pos++
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+// str parses a quoted string.
+func (p *exprParser) str() (string, error) {
+	start := p.pos
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			return strconv.Unquote(p.s[start:p.pos])
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// number parses an integer or a float.
+func (p *exprParser) number() (expr, error) {
+	start := p.pos
+	if p.s[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.s) && (isDigit(p.s[p.pos]) || p.s[p.pos] == '.') {
+		p.pos++
+	}
+
+	text := p.s[start:p.pos]
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return numberNode(n), nil
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return &ast.LiteralType{Token: token.Token{Type: token.FLOAT, Text: text}}, nil
+	}
+	return nil, fmt.Errorf("invalid number %q", text)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -28,31 +28,47 @@ var errPortLabel = fmt.Errorf("Port label does not conform to naming requirement
 // Due to current internal limitations, the entire contents of the
 // io.Reader will be copied into memory first before parsing.
 func Parse(r io.Reader) (*api.Job, error) {
+	return ParseWithVariables(r, nil)
+}
+
+// ParseWithVariables parses the job spec from the given io.Reader, setting
+// the variables declared by the job spec from the given sources. Variables
+// without a value from vars must have a default.
+func ParseWithVariables(r io.Reader, vars *Variables) (*api.Job, error) {
+	job, _, err := ParseWithValues(r, vars)
+	return job, err
+}
+
+// ParseWithValues is like ParseWithVariables but also returns the values
+// bound to the variables declared by the job spec, as the contents of a
+// variable file.
+func ParseWithValues(r io.Reader, vars *Variables) (*api.Job, string, error) {
 	// Copy the reader into an in-memory buffer first since HCL requires it.
 	var buf bytes.Buffer
 	if _, err := io.Copy(&buf, r); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Parse the buffer
 	root, err := hcl.Parse(buf.String())
 	if err != nil {
-		return nil, fmt.Errorf("error parsing: %s", err)
+		return nil, "", fmt.Errorf("error parsing: %s", err)
 	}
 	buf.Reset()
 
 	// Top-level item should be a list
 	list, ok := root.Node.(*ast.ObjectList)
 	if !ok {
-		return nil, fmt.Errorf("error parsing: root should be an object")
+		return nil, "", fmt.Errorf("error parsing: root should be an object")
 	}
 
 	// Check for invalid keys
 	valid := []string{
 		"job",
+		"variable",
 	}
 	if err := helper.CheckHCLKeys(list, valid); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var job api.Job
@@ -60,13 +76,23 @@ func Parse(r io.Reader) (*api.Job, error) {
 	// Parse the job out
 	matches := list.Filter("job")
 	if len(matches.Items) == 0 {
-		return nil, fmt.Errorf("'job' stanza not found")
+		return nil, "", fmt.Errorf("'job' stanza not found")
+	}
+
+	// Bind the variables, then evaluate the expressions of the job and
+	// expand its dynamic blocks
+	declared, err := parseVariables(list.Filter("variable"), vars)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing 'variable': %s", err)
+	}
+	if matches, err = interpolate(matches, declared); err != nil {
+		return nil, "", fmt.Errorf("error interpolating job: %s", err)
 	}
 	if err := parseJob(&job, matches); err != nil {
-		return nil, fmt.Errorf("error parsing 'job': %s", err)
+		return nil, "", fmt.Errorf("error parsing 'job': %s", err)
 	}
 
-	return &job, nil
+	return &job, formatVariables(declared), nil
 }
 
 // ParseFile parses the given path as a job spec.
@@ -108,10 +134,12 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "migrate")
 	delete(m, "parameterized")
 	delete(m, "periodic")
+	delete(m, "prefetch")
 	delete(m, "reschedule")
 	delete(m, "update")
 	delete(m, "vault")
 	delete(m, "spread")
+	delete(m, "version_retention")
 
 	// Set the ID and name to the object key
 	result.ID = helper.StringToPtr(obj.Keys[0].Token.Value().(string))
@@ -133,10 +161,12 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	// Check for invalid keys
 	valid := []string{
 		"all_at_once",
+		"concurrency_group",
 		"constraint",
 		"affinity",
 		"spread",
 		"datacenters",
+		"node_pool",
 		"group",
 		"id",
 		"meta",
@@ -145,14 +175,17 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"namespace",
 		"parameterized",
 		"periodic",
+		"prefetch",
 		"priority",
 		"region",
 		"reschedule",
+		"tags",
 		"task",
 		"type",
 		"update",
 		"vault",
 		"vault_token",
+		"version_retention",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "job:")
@@ -214,6 +247,20 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// If we have a prefetch hint, then parse that
+	if o := listVal.Filter("prefetch"); len(o.Items) > 0 {
+		if err := parsePrefetch(&result.Prefetch, o); err != nil {
+			return multierror.Prefix(err, "prefetch ->")
+		}
+	}
+
+	// If we have a version retention, then parse that
+	if o := listVal.Filter("version_retention"); len(o.Items) > 0 {
+		if err := parseVersionRetention(&result.VersionRetention, o); err != nil {
+			return multierror.Prefix(err, "version_retention ->")
+		}
+	}
+
 	// Parse out meta fields. These are in HCL as a list so we need
 	// to iterate over them and merge them.
 	if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
@@ -310,11 +357,15 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"meta",
 			"task",
 			"ephemeral_disk",
+			"shared_memory",
+			"consul",
 			"update",
 			"reschedule",
 			"vault",
 			"migrate",
 			"spread",
+			"max_client_disconnect",
+			"reconnect_strategy",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -330,6 +381,8 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "task")
 		delete(m, "restart")
 		delete(m, "ephemeral_disk")
+		delete(m, "shared_memory")
+		delete(m, "consul")
 		delete(m, "update")
 		delete(m, "vault")
 		delete(m, "migrate")
@@ -338,7 +391,15 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		// Build the group with the basic decode
 		var g api.TaskGroup
 		g.Name = helper.StringToPtr(n)
-		if err := mapstructure.WeakDecode(m, &g); err != nil {
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &g,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
 			return err
 		}
 
@@ -384,6 +445,20 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse shared memory
+		if o := listVal.Filter("shared_memory"); len(o.Items) > 0 {
+			if err := parseSharedMemory(&g.SharedMemory, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', shared_memory ->", n))
+			}
+		}
+
+		// Parse the Consul cluster
+		if o := listVal.Filter("consul"); len(o.Items) > 0 {
+			if err := parseGroupConsul(&g.Consul, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', consul ->", n))
+			}
+		}
+
 		// If we have an update strategy, then parse that
 		if o := listVal.Filter("update"); len(o.Items) > 0 {
 			if err := parseUpdate(&g.Update, o); err != nil {
@@ -721,6 +796,68 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 	return nil
 }
 
+func parseSharedMemory(result **api.SharedMemory, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'shared_memory' block allowed")
+	}
+
+	// Get our shared_memory object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"size",
+	}
+	if err := helper.CheckHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+
+	var sharedMemory api.SharedMemory
+	if err := mapstructure.WeakDecode(m, &sharedMemory); err != nil {
+		return err
+	}
+	*result = &sharedMemory
+
+	return nil
+}
+
+func parseGroupConsul(result **api.Consul, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'consul' block allowed")
+	}
+
+	// Get our consul object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"cluster",
+	}
+	if err := helper.CheckHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+
+	var consul api.Consul
+	if err := mapstructure.WeakDecode(m, &consul); err != nil {
+		return err
+	}
+	*result = &consul
+
+	return nil
+}
+
 func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -856,6 +993,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 
 		// Check for invalid keys
 		valid := []string{
+			"action",
 			"artifact",
 			"config",
 			"constraint",
@@ -863,6 +1001,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			"dispatch_payload",
 			"driver",
 			"env",
+			"identity",
 			"kill_timeout",
 			"leader",
 			"logs",
@@ -874,6 +1013,9 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			"user",
 			"vault",
 			"kill_signal",
+			"kill_escalation",
+			"metric_labels",
+			"secret",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -883,15 +1025,19 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 		if err := hcl.DecodeObject(&m, item.Val); err != nil {
 			return err
 		}
+		delete(m, "action")
 		delete(m, "artifact")
 		delete(m, "config")
 		delete(m, "constraint")
 		delete(m, "affinity")
 		delete(m, "dispatch_payload")
 		delete(m, "env")
+		delete(m, "identity")
+		delete(m, "kill_escalation")
 		delete(m, "logs")
 		delete(m, "meta")
 		delete(m, "resources")
+		delete(m, "secret")
 		delete(m, "service")
 		delete(m, "template")
 		delete(m, "vault")
@@ -999,6 +1145,8 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			valid := []string{
 				"max_files",
 				"max_file_size",
+				"event_log_providers",
+				"event_log_channel",
 			}
 			if err := helper.CheckHCLKeys(logsBlock.Val, valid); err != nil {
 				return multierror.Prefix(err, fmt.Sprintf("'%s', logs ->", n))
@@ -1030,6 +1178,27 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			}
 		}
 
+		// Parse actions
+		if o := listVal.Filter("action"); len(o.Items) > 0 {
+			if err := parseActions(&t.Actions, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', action ->", n))
+			}
+		}
+
+		// Parse secrets
+		if o := listVal.Filter("secret"); len(o.Items) > 0 {
+			if err := parseSecrets(&t.Secrets, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', secret ->", n))
+			}
+		}
+
+		// Parse the kill escalation chain
+		if o := listVal.Filter("kill_escalation"); len(o.Items) > 0 {
+			if err := parseKillEscalation(&t.KillEscalation, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', kill_escalation ->", n))
+			}
+		}
+
 		// If we have a vault block, then parse that
 		if o := listVal.Filter("vault"); len(o.Items) > 0 {
 			v := &api.Vault{
@@ -1070,6 +1239,33 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			}
 		}
 
+		// If we have an identity block parse that
+		if o := listVal.Filter("identity"); len(o.Items) > 0 {
+			if len(o.Items) > 1 {
+				return fmt.Errorf("only one identity block is allowed in a task. Number of identity blocks found: %d", len(o.Items))
+			}
+			var m map[string]interface{}
+			identityBlock := o.Items[0]
+
+			// Check for invalid keys
+			valid := []string{
+				"env",
+				"file",
+			}
+			if err := helper.CheckHCLKeys(identityBlock.Val, valid); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', identity ->", n))
+			}
+
+			if err := hcl.DecodeObject(&m, identityBlock.Val); err != nil {
+				return err
+			}
+
+			t.Identity = &api.WorkloadIdentity{}
+			if err := mapstructure.WeakDecode(m, t.Identity); err != nil {
+				return err
+			}
+		}
+
 		*result = append(*result, &t)
 	}
 
@@ -1148,6 +1344,7 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 		// Check for invalid keys
 		valid := []string{
 			"change_mode",
+			"change_script",
 			"change_signal",
 			"data",
 			"destination",
@@ -1167,6 +1364,7 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 		if err := hcl.DecodeObject(&m, o.Val); err != nil {
 			return err
 		}
+		delete(m, "change_script")
 
 		templ := &api.Template{
 			ChangeMode: helper.StringToPtr("restart"),
@@ -1174,6 +1372,15 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 			Perms:      helper.StringToPtr("0644"),
 		}
 
+		// Parse the change script
+		if ot, ok := o.Val.(*ast.ObjectType); ok {
+			if cs := ot.List.Filter("change_script"); len(cs.Items) > 0 {
+				if err := parseChangeScript(&templ.ChangeScript, cs); err != nil {
+					return multierror.Prefix(err, "change_script ->")
+				}
+			}
+		}
+
 		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
 			WeaklyTypedInput: true,
@@ -1192,6 +1399,187 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseChangeScript(result **api.ChangeScript, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'change_script' block allowed per template")
+	}
+
+	// Get our change script object
+	o := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"command",
+		"args",
+		"timeout",
+		"fail_on_error",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	var cs api.ChangeScript
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &cs,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	*result = &cs
+	return nil
+}
+
+func parseActions(result *[]*api.Action, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("action '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"args",
+			"command",
+			"timeout",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		action := &api.Action{
+			Name: helper.StringToPtr(n),
+		}
+
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           action,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		*result = append(*result, action)
+	}
+
+	return nil
+}
+
+func parseKillEscalation(result *[]*api.KillStep, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"signal",
+			"timeout",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var step api.KillStep
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &step,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		*result = append(*result, &step)
+	}
+
+	return nil
+}
+
+func parseSecrets(result *[]*api.Secret, list *ast.ObjectList) error {
+	list = list.Children()
+	if len(list.Items) == 0 {
+		return nil
+	}
+
+	// Go through each object and turn it into an actual result.
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("secret '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"change_mode",
+			"change_signal",
+			"gid",
+			"key",
+			"path",
+			"perms",
+			"provider",
+			"uid",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		secret := &api.Secret{
+			Name: helper.StringToPtr(n),
+		}
+		if err := mapstructure.WeakDecode(m, secret); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		*result = append(*result, secret)
+	}
+
+	return nil
+}
+
 func parseServices(jobName string, taskGroupName string, task *api.Task, serviceObjs *ast.ObjectList) error {
 	task.Services = make([]*api.Service, len(serviceObjs.Items))
 	for idx, o := range serviceObjs.Items {
@@ -1204,6 +1592,7 @@ func parseServices(jobName string, taskGroupName string, task *api.Task, service
 			"check",
 			"address_mode",
 			"check_restart",
+			"provider",
 		}
 		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("service (%d) ->", idx))
@@ -1276,6 +1665,7 @@ func parseChecks(service *api.Service, checkObjs *ast.ObjectList) error {
 			"address_mode",
 			"grpc_service",
 			"grpc_use_tls",
+			"role",
 		}
 		if err := helper.CheckHCLKeys(co.Val, valid); err != nil {
 			return multierror.Prefix(err, "check ->")
@@ -1361,6 +1751,8 @@ func parseCheckRestart(cro *ast.ObjectItem) (*api.CheckRestart, error) {
 		"limit",
 		"grace",
 		"ignore_warnings",
+		"delay",
+		"max_delay",
 	}
 
 	if err := helper.CheckHCLKeys(cro.Val, valid); err != nil {
@@ -1411,6 +1803,10 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	// Check for invalid keys
 	valid := []string{
 		"cpu",
+		"cores",
+		"hugepages_2mb",
+		"hugepages_1gb",
+		"realtime",
 		"iops", // COMPAT(0.10): Remove after one release to allow it to be removed from jobspecs
 		"disk",
 		"memory",
@@ -1442,6 +1838,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		valid := []string{
 			"mbits",
 			"port",
+			"dns",
 		}
 		if err := helper.CheckHCLKeys(o.Items[0].Val, valid); err != nil {
 			return multierror.Prefix(err, "resources, network ->")
@@ -1452,6 +1849,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		if err := hcl.DecodeObject(&m, o.Items[0].Val); err != nil {
 			return err
 		}
+		delete(m, "dns")
 		if err := mapstructure.WeakDecode(m, &r); err != nil {
 			return err
 		}
@@ -1466,6 +1864,17 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 			return multierror.Prefix(err, "resources, network, ports ->")
 		}
 
+		if o := networkObj.Filter("dns"); len(o.Items) > 0 {
+			if len(o.Items) > 1 {
+				return fmt.Errorf("only one 'dns' block allowed per network")
+			}
+			dns, err := parseDNS(o.Items[0])
+			if err != nil {
+				return multierror.Prefix(err, "resources, network ->")
+			}
+			r.DNS = dns
+		}
+
 		result.Networks = []*api.NetworkResource{&r}
 	}
 
@@ -1536,11 +1945,34 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseDNS(o *ast.ObjectItem) (*api.DNSConfig, error) {
+	valid := []string{
+		"servers",
+		"searches",
+		"options",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return nil, multierror.Prefix(err, "dns ->")
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return nil, err
+	}
+
+	var dns api.DNSConfig
+	if err := mapstructure.WeakDecode(m, &dns); err != nil {
+		return nil, err
+	}
+	return &dns, nil
+}
+
 func parsePorts(networkObj *ast.ObjectList, nw *api.NetworkResource) error {
 	// Check for invalid keys
 	valid := []string{
 		"mbits",
 		"port",
+		"dns",
 	}
 	if err := helper.CheckHCLKeys(networkObj, valid); err != nil {
 		return err
@@ -1604,10 +2036,13 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"progress_deadline",
 		"auto_revert",
 		"canary",
+		"manual_step",
+		"progress_webhook",
 	}
 	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 		return err
 	}
+	delete(m, "progress_webhook")
 
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
@@ -1617,7 +2052,52 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 	if err != nil {
 		return err
 	}
-	return dec.Decode(m)
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the progress webhook
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		if webhook := ot.List.Filter("progress_webhook"); len(webhook.Items) > 0 {
+			if *result == nil {
+				*result = new(api.UpdateStrategy)
+			}
+			if err := parseDeploymentWebhook(&(*result).ProgressWebhook, webhook); err != nil {
+				return multierror.Prefix(err, "progress_webhook ->")
+			}
+		}
+	}
+	return nil
+}
+
+func parseDeploymentWebhook(result **api.DeploymentWebhook, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'progress_webhook' block allowed")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"url",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var webhook api.DeploymentWebhook
+	if err := mapstructure.WeakDecode(m, &webhook); err != nil {
+		return err
+	}
+	*result = &webhook
+	return nil
 }
 
 func parseMigrate(result **api.MigrateStrategy, list *ast.ObjectList) error {
@@ -1656,6 +2136,71 @@ func parseMigrate(result **api.MigrateStrategy, list *ast.ObjectList) error {
 	return dec.Decode(m)
 }
 
+func parseVersionRetention(result **api.JobVersionRetention, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'version_retention' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"count",
+		"age",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+	return dec.Decode(m)
+}
+
+func parsePrefetch(result **api.JobPrefetch, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'prefetch' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"images",
+		"artifacts",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var prefetch api.JobPrefetch
+	if err := mapstructure.WeakDecode(m, &prefetch); err != nil {
+		return err
+	}
+	*result = &prefetch
+	return nil
+}
+
 func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -1727,6 +2272,8 @@ func parseVault(result *api.Vault, list *ast.ObjectList) error {
 	// Check for invalid keys
 	valid := []string{
 		"policies",
+		"namespace",
+		"cluster",
 		"env",
 		"change_mode",
 		"change_signal",
@@ -1760,12 +2307,14 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	if err := hcl.DecodeObject(&m, o.Val); err != nil {
 		return err
 	}
+	delete(m, "meta")
 
 	// Check for invalid keys
 	valid := []string{
 		"payload",
 		"meta_required",
 		"meta_optional",
+		"meta",
 	}
 	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 		return err
@@ -1777,6 +2326,48 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 		return err
 	}
 
+	// Parse the meta configs
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		if metaList := ot.List.Filter("meta"); len(metaList.Items) > 0 {
+			if err := parseDispatchMetaConfigs(&d.MetaConfigs, metaList); err != nil {
+				return multierror.Prefix(err, "meta ->")
+			}
+		}
+	}
+
 	*result = &d
 	return nil
 }
+
+func parseDispatchMetaConfigs(result *[]*api.DispatchMetaConfig, list *ast.ObjectList) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("meta block must have exactly one key")
+		}
+		key := item.Keys[0].Token.Value().(string)
+
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"enum",
+			"regex",
+			"required_with",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", key))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		c := &api.DispatchMetaConfig{Key: key}
+		if err := mapstructure.WeakDecode(m, c); err != nil {
+			return err
+		}
+		*result = append(*result, c)
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package jobspec
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -12,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/kr/pretty"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
@@ -29,10 +33,13 @@ func TestParse(t *testing.T) {
 				Priority:    helper.IntToPtr(52),
 				AllAtOnce:   helper.BoolToPtr(true),
 				Datacenters: []string{"us2", "eu1"},
+				NodePool:    helper.StringToPtr("gpu"),
 				Region:      helper.StringToPtr("fooregion"),
 				Namespace:   helper.StringToPtr("foonamespace"),
 				VaultToken:  helper.StringToPtr("foo"),
 
+				Tags: []string{"owner:storage", "tier:1"},
+
 				Meta: map[string]string{
 					"foo": "bar",
 				},
@@ -80,6 +87,9 @@ func TestParse(t *testing.T) {
 					ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
 					AutoRevert:       helper.BoolToPtr(true),
 					Canary:           helper.IntToPtr(1),
+					ProgressWebhook: &api.DeploymentWebhook{
+						URL: helper.StringToPtr("https://ci.example.com/deployments"),
+					},
 				},
 
 				TaskGroups: []*api.TaskGroup{
@@ -152,6 +162,8 @@ func TestParse(t *testing.T) {
 							Interval: helper.TimeToPtr(12 * time.Hour),
 							Attempts: helper.IntToPtr(5),
 						},
+						MaxClientDisconnect: helper.TimeToPtr(10 * time.Minute),
+						ReconnectStrategy:   helper.StringToPtr("keep_original"),
 						EphemeralDisk: &api.EphemeralDisk{
 							Sticky: helper.BoolToPtr(true),
 							SizeMB: helper.IntToPtr(150),
@@ -164,6 +176,7 @@ func TestParse(t *testing.T) {
 							ProgressDeadline: helper.TimeToPtr(1 * time.Minute),
 							AutoRevert:       helper.BoolToPtr(false),
 							Canary:           helper.IntToPtr(2),
+							ManualStep:       helper.BoolToPtr(true),
 						},
 						Migrate: &api.MigrateStrategy{
 							MaxParallel:     helper.IntToPtr(2),
@@ -209,6 +222,8 @@ func TestParse(t *testing.T) {
 												CheckRestart: &api.CheckRestart{
 													Limit:          3,
 													Grace:          helper.TimeToPtr(10 * time.Second),
+													Delay:          helper.TimeToPtr(30 * time.Second),
+													MaxDelay:       helper.TimeToPtr(5 * time.Minute),
 													IgnoreWarnings: true,
 												},
 											},
@@ -227,6 +242,11 @@ func TestParse(t *testing.T) {
 											MBits:         helper.IntToPtr(100),
 											ReservedPorts: []api.Port{{Label: "one", Value: 1}, {Label: "two", Value: 2}, {Label: "three", Value: 3}},
 											DynamicPorts:  []api.Port{{Label: "http", Value: 0}, {Label: "https", Value: 0}, {Label: "admin", Value: 0}},
+											DNS: &api.DNSConfig{
+												Servers:  []string{"10.0.0.53"},
+												Searches: []string{"service.consul"},
+												Options:  []string{"ndots:2"},
+											},
 										},
 									},
 									Devices: []*api.RequestedDevice{
@@ -302,6 +322,19 @@ func TestParse(t *testing.T) {
 										LeftDelim:  helper.StringToPtr("--"),
 										RightDelim: helper.StringToPtr("__"),
 									},
+									{
+										SourcePath: helper.StringToPtr("baz"),
+										DestPath:   helper.StringToPtr("baz"),
+										ChangeMode: helper.StringToPtr(structs.TemplateChangeModeScript),
+										ChangeScript: &api.ChangeScript{
+											Command:     helper.StringToPtr("/bin/reload"),
+											Args:        []string{"-config", "baz"},
+											Timeout:     helper.TimeToPtr(10 * time.Second),
+											FailOnError: helper.BoolToPtr(true),
+										},
+										Splay: helper.TimeToPtr(5 * time.Second),
+										Perms: helper.StringToPtr("0644"),
+									},
 								},
 								Leader:     true,
 								KillSignal: "",
@@ -313,6 +346,7 @@ func TestParse(t *testing.T) {
 								Config: map[string]interface{}{
 									"image": "hashicorp/storagelocker",
 								},
+								MetricLabels: []string{"team"},
 								Resources: &api.Resources{
 									CPU:      helper.IntToPtr(500),
 									MemoryMB: helper.IntToPtr(128),
@@ -326,10 +360,15 @@ func TestParse(t *testing.T) {
 								},
 								Vault: &api.Vault{
 									Policies:     []string{"foo", "bar"},
+									Namespace:    helper.StringToPtr("engineering"),
 									Env:          helper.BoolToPtr(false),
 									ChangeMode:   helper.StringToPtr(structs.VaultChangeModeSignal),
 									ChangeSignal: helper.StringToPtr("SIGUSR1"),
 								},
+								Identity: &api.WorkloadIdentity{
+									Env:  true,
+									File: true,
+								},
 							},
 						},
 					},
@@ -457,6 +496,42 @@ func TestParse(t *testing.T) {
 			false,
 		},
 
+		{
+			"version-retention.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				VersionRetention: &api.JobVersionRetention{
+					Count: 20,
+					Age:   720 * time.Hour,
+				},
+			},
+			false,
+		},
+
+		{
+			"concurrency-group.hcl",
+			&api.Job{
+				ID:               helper.StringToPtr("foo"),
+				Name:             helper.StringToPtr("foo"),
+				Type:             helper.StringToPtr("batch"),
+				ConcurrencyGroup: helper.StringToPtr("migrations"),
+			},
+			false,
+		},
+
+		{
+			"prefetch.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				Prefetch: &api.JobPrefetch{
+					Artifacts: helper.BoolToPtr(false),
+				},
+			},
+			false,
+		},
+
 		{
 			"specify-job.hcl",
 			&api.Job{
@@ -641,6 +716,18 @@ func TestParse(t *testing.T) {
 					Payload:      "required",
 					MetaRequired: []string{"foo", "bar"},
 					MetaOptional: []string{"baz", "bam"},
+					MetaConfigs: []*api.DispatchMetaConfig{
+						{
+							Key:  "foo",
+							Type: "int",
+							Enum: []string{"1", "2"},
+						},
+						{
+							Key:          "baz",
+							Regex:        "^s3://",
+							RequiredWith: []string{"bam"},
+						},
+					},
 				},
 
 				TaskGroups: []*api.TaskGroup{
@@ -683,6 +770,168 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"job-with-kill-escalation.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								KillEscalation: []*api.KillStep{
+									{
+										Signal:  helper.StringToPtr("SIGTERM"),
+										Timeout: helper.TimeToPtr(30 * time.Second),
+									},
+									{
+										Signal:  helper.StringToPtr("SIGINT"),
+										Timeout: helper.TimeToPtr(10 * time.Second),
+									},
+								},
+								Config: map[string]interface{}{
+									"image": "hashicorp/image",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-actions.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "redis",
+								},
+								Actions: []*api.Action{
+									{
+										Name:    helper.StringToPtr("flush-cache"),
+										Command: helper.StringToPtr("redis-cli"),
+										Args:    []string{"FLUSHALL"},
+										Timeout: helper.TimeToPtr(30 * time.Second),
+									},
+									{
+										Name:    helper.StringToPtr("info"),
+										Command: helper.StringToPtr("redis-cli"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"resources-cores.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "redis",
+								},
+								Resources: &api.Resources{
+									Cores:    helper.IntToPtr(2),
+									MemoryMB: helper.IntToPtr(512),
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"resources-hugepages.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "exec",
+								Config: map[string]interface{}{
+									"command": "/bin/dpdk-app",
+								},
+								Resources: &api.Resources{
+									CPU:          helper.IntToPtr(500),
+									MemoryMB:     helper.IntToPtr(512),
+									HugePages2MB: helper.IntToPtr(64),
+									HugePages1GB: helper.IntToPtr(1),
+									Realtime:     helper.BoolToPtr(true),
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"task-secrets.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "redis",
+								},
+								Secrets: []*api.Secret{
+									{
+										Name:     helper.StringToPtr("db_password"),
+										Provider: helper.StringToPtr("vault"),
+										Path:     helper.StringToPtr("secret/data/db"),
+										Key:      helper.StringToPtr("password"),
+										Perms:    helper.StringToPtr("0440"),
+										Uid:      helper.IntToPtr(1000),
+										Gid:      helper.IntToPtr(1000),
+									},
+									{
+										Name:         helper.StringToPtr("api_key"),
+										Provider:     helper.StringToPtr("nomad"),
+										Path:         helper.StringToPtr("nomad/jobs/foo/api"),
+										Key:          helper.StringToPtr("key"),
+										ChangeMode:   helper.StringToPtr("signal"),
+										ChangeSignal: helper.StringToPtr("SIGHUP"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-check-driver-address.hcl",
 			&api.Job{
@@ -731,6 +980,119 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"service-check-role.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("check_role"),
+				Name: helper.StringToPtr("check_role"),
+				Type: helper.StringToPtr("service"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name: "task",
+								Services: []*api.Service{
+									{
+										Name:      "http-service",
+										PortLabel: "http",
+										CheckRestart: &api.CheckRestart{
+											Limit: 3,
+										},
+										Checks: []api.ServiceCheck{
+											{
+												Name: "ready",
+												Type: "http",
+												Path: "/ready",
+												Role: "readiness",
+											},
+											{
+												Name: "alive",
+												Type: "http",
+												Path: "/alive",
+												Role: "liveness",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"windows-task.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("windows"),
+				Name: helper.StringToPtr("windows"),
+				Type: helper.StringToPtr("service"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name: "task",
+								LogConfig: &api.LogConfig{
+									EventLogProviders: []string{"MSSQLSERVER", "SQLSERVERAGENT"},
+								},
+								Services: []*api.Service{
+									{
+										Name: "sql",
+										Checks: []api.ServiceCheck{
+											{
+												Name:     "pipe",
+												Type:     "pipe",
+												Path:     `\\.\pipe\sql\query`,
+												Interval: 10 * time.Second,
+												Timeout:  2 * time.Second,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"service-provider.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("service_provider"),
+				Name: helper.StringToPtr("service_provider"),
+				Type: helper.StringToPtr("service"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name: "task",
+								Services: []*api.Service{
+									{
+										Name:      "http-service",
+										PortLabel: "http",
+										Provider:  "nomad",
+										Checks: []api.ServiceCheck{
+											{
+												Name:     "http-check",
+												Type:     "http",
+												Path:     "/",
+												Interval: 10 * time.Second,
+												Timeout:  2 * time.Second,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-check-restart.hcl",
 			&api.Job{
@@ -869,6 +1231,69 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"shared-memory.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("inference"),
+						SharedMemory: &api.SharedMemory{
+							SizeMB: helper.IntToPtr(1024),
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "server",
+								Driver: "docker",
+								Config: map[string]interface{}{
+									"image": "tritonserver",
+								},
+							},
+							{
+								Name:   "preprocess",
+								Driver: "exec",
+								Config: map[string]interface{}{
+									"command": "preprocess",
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"clusters.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("web"),
+						Consul: &api.Consul{
+							Cluster: helper.StringToPtr("edge"),
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "server",
+								Driver: "exec",
+								Config: map[string]interface{}{
+									"command": "server",
+								},
+								Vault: &api.Vault{
+									Cluster:    helper.StringToPtr("secrets"),
+									Policies:   []string{"web"},
+									Env:        helper.BoolToPtr(true),
+									ChangeMode: helper.StringToPtr(structs.VaultChangeModeRestart),
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -941,3 +1366,234 @@ func TestIncorrectKey(t *testing.T) {
 		t.Fatalf("Expected key error; got %v", err)
 	}
 }
+
+func TestParse_Variables(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join("./test-fixtures", "variables.hcl")
+	varFile := filepath.Join("./test-fixtures", "variables.vars")
+
+	parse := func(vars *Variables) (*api.Job, error) {
+		f, err := os.Open(path)
+		require.NoError(err)
+		defer f.Close()
+		return ParseWithVariables(f, vars)
+	}
+
+	// Variables without a default must be set
+	_, err := parse(nil)
+	require.Error(err)
+	require.Contains(err.Error(), `missing value for variable "count"`)
+
+	// Defaults are interpolated with their types
+	job, err := parse(&Variables{Vars: []string{"count=2"}})
+	require.NoError(err)
+	require.Equal("web", *job.ID)
+	require.Equal([]string{"dc1"}, job.Datacenters)
+	require.Equal(2, *job.TaskGroups[0].Count)
+	task := job.TaskGroups[0].Tasks[0]
+	require.Equal("redis:3.2", task.Config["image"])
+	require.Equal([]map[string]interface{}{{"env": "dev"}}, task.Config["labels"])
+	require.Equal(false, task.Config["privileged"])
+	require.Equal(map[string]string{"DC": "${node.datacenter}", "TAG": "v3.2-false"}, task.Env)
+
+	// The environment, variable files and name=value pairs override the
+	// defaults in order
+	job, err = parse(&Variables{
+		Env: []string{
+			"NOMAD_VAR_name=api",
+			"NOMAD_VAR_count=1",
+			"NOMAD_VAR_undeclared=ignored",
+			"HOME=/root",
+		},
+		Files: []string{varFile},
+		Vars:  []string{"image_tag=4.0", "labels={ env = \"prod\" }", "canary=true"},
+	})
+	require.NoError(err)
+	require.Equal("api", *job.ID)
+	require.Equal([]string{"dc1", "dc2"}, job.Datacenters)
+	require.Equal(3, *job.TaskGroups[0].Count)
+	task = job.TaskGroups[0].Tasks[0]
+	require.Equal("redis:4.0", task.Config["image"])
+	require.Equal([]map[string]interface{}{{"env": "prod"}}, task.Config["labels"])
+	require.Equal(true, task.Config["privileged"])
+	require.Equal("v4.0-true", task.Env["TAG"])
+
+	// Invalid values
+	for vars, expected := range map[string]string{
+		"count=many":  `"many" is not a number`,
+		"canary=3":    `"3" is not a bool`,
+		"count":       "must be of the form name=value",
+		"other=value": `variable "other" is not declared`,
+	} {
+		_, err = parse(&Variables{Vars: []string{"count=1", vars}})
+		require.Error(err, vars)
+		require.Contains(err.Error(), expected, vars)
+	}
+	_, err = parse(&Variables{Vars: []string{"count=1", "datacenters=\"dc1\""}})
+	require.Error(err)
+	require.Contains(err.Error(), "expected a value of type list")
+}
+
+func TestParse_VariableValues(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join("./test-fixtures", "variables.hcl")
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+	job, values, err := ParseWithValues(f, &Variables{
+		Vars: []string{"count=2", "labels={ env = \"prod\", team = \"cache\" }"},
+	})
+	require.NoError(err)
+	require.Equal(`canary = false
+count = 2
+datacenters = ["dc1"]
+image_tag = "3.2"
+labels = { env = "prod", team = "cache" }
+name = "web"
+`, values)
+
+	// The values reproduce the job when given as a variable file
+	dir, err := ioutil.TempDir("", "jobspec")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	varFile := filepath.Join(dir, "values.vars")
+	require.NoError(ioutil.WriteFile(varFile, []byte(values), 0644))
+
+	_, err = f.Seek(0, 0)
+	require.NoError(err)
+	out, err := ParseWithVariables(f, &Variables{Files: []string{varFile}})
+	require.NoError(err)
+	require.Equal(job, out)
+}
+
+func TestParse_Variables_Invalid(t *testing.T) {
+	cases := map[string]string{
+		`job "foo" { region = "${var.region}" }`: `reference to undeclared variable "region"`,
+		`variable "dcs" { default = ["dc1"] }
+job "foo" { region = "r-${var.dcs}" }`: `variable "dcs" of type list can not be interpolated`,
+		`variable "count" {
+  type    = "number"
+  default = "lots"
+}
+job "foo" {}`: `invalid default: "lots" is not a number`,
+		`variable "v" { type = "set" }
+job "foo" {}`: `invalid type "set"`,
+		`variable "v" { value = "x" }
+job "foo" {}`: "invalid key: value",
+	}
+	for spec, expected := range cases {
+		_, err := Parse(strings.NewReader(spec))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error %q for\n%s\ngot: %v", expected, spec, err)
+		}
+	}
+}
+
+func TestParse_Dynamic(t *testing.T) {
+	require := require.New(t)
+	job, err := ParseFile(filepath.Join("./test-fixtures", "dynamic.hcl"))
+	require.NoError(err)
+
+	// A group is generated for each item of the map, in order
+	require.Len(job.TaskGroups, 2)
+	for i, expected := range []struct {
+		name  string
+		count int
+		addr  string
+		port  int
+	}{
+		{"web", 2, "10.1.2.10", 8080},
+		{"api", 1, "10.1.1.10", 9090},
+	} {
+		tg := job.TaskGroups[i]
+		require.Equal(expected.name, *tg.Name)
+		require.Equal(expected.count, *tg.Count)
+
+		task := tg.Tasks[0]
+		require.Equal(expected.name+":latest", task.Config["image"])
+		require.Equal(map[string]string{
+			"ADDR":    expected.addr,
+			"NETMASK": "255.255.0.0",
+			"NODE":    "${node.unique.name}",
+		}, task.Env)
+		require.Equal(map[string]string{"owner": "platform", "tier": "2"}, task.Meta)
+		require.Equal(fmt.Sprintf("name   = %q\nlisten = \":%d\"\nlogs   = \"${NOMAD_ALLOC_DIR}/logs\"\n", expected.name, expected.port),
+			*task.Templates[0].EmbeddedTmpl)
+
+		// A port is generated for each element of the list
+		ports := task.Resources.Networks[0].DynamicPorts
+		require.Len(ports, 2)
+		require.Equal("http", ports[0].Label)
+		require.Equal("admin", ports[1].Label)
+	}
+}
+
+func TestParse_Dynamic_Invalid(t *testing.T) {
+	cases := map[string]string{
+		`job "foo" {
+  dynamic "group" {
+    content {}
+  }
+}`: "missing 'for_each'",
+		`job "foo" {
+  dynamic "group" {
+    for_each = "dc1"
+    content {}
+  }
+}`: "'for_each' must be a list or a map, got string",
+		`job "foo" {
+  dynamic "group" {
+    for_each = ["a"]
+  }
+}`: "must have exactly one 'content' block",
+		`job "foo" {
+  dynamic "group" {
+    for_each = ["a"]
+    iterator = "g"
+    labels   = ["${g.value.name}"]
+    content {}
+  }
+}`: `"g.value" of type string has no attribute "name"`,
+		`job "foo" { region = "${upper("a")}" }`:                        `call to unknown function "upper"`,
+		`job "foo" { region = "${cidrhost("10.0.0.0/30", 4)}" }`:        "cidrhost: prefix 10.0.0.0/30 has no host number 4",
+		`job "foo" { region = "${cidrsubnet("10.0.0.0/30", 4, 0)}" }`:   "can not be extended by 4 bits",
+		`job "foo" { region = "${cidrnetmask("fd00::/8")}" }`:           "only IPv4 prefixes have a netmask",
+		`job "foo" { region = "r-${yamldecode("a: b")}" }`:              "is of type map and can not be interpolated",
+		`job "foo" { region = "${jsondecode("{\"a\": null}")}" }`:       "null values are not supported",
+		`job "foo" { region = "${file("test-fixtures/missing.txt")}" }`: "no such file or directory",
+	}
+	for spec, expected := range cases {
+		_, err := Parse(strings.NewReader(spec))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected error %q for\n%s\ngot: %v", expected, spec, err)
+		}
+	}
+}
+
+func TestParse_Functions(t *testing.T) {
+	require := require.New(t)
+	job, err := Parse(strings.NewReader(`
+job "foo" {
+  datacenters = "${jsondecode("[\"dc1\", \"dc2\"]")}"
+
+  meta {
+    host     = "${cidrhost("10.12.0.0/16", 258)}"
+    last     = "${cidrhost("10.12.0.0/16", -2)}"
+    host6    = "${cidrhost("fd00:fd12:3456:7890::/56", 16)}"
+    subnet   = "${cidrsubnet("10.12.0.0/16", 4, 15)}"
+    netmask  = "${cidrnetmask("172.16.0.0/12")}"
+    combined = "${cidrhost("10.0.0.0/8", 1)}:${jsondecode("8080")}"
+  }
+}`))
+	require.NoError(err)
+	require.Equal(map[string]string{
+		"host":     "10.12.1.2",
+		"last":     "10.12.255.254",
+		"host6":    "fd00:fd12:3456:7800::10",
+		"subnet":   "10.12.240.0/20",
+		"netmask":  "255.240.0.0",
+		"combined": "10.0.0.1:8080",
+	}, job.Meta)
+	require.Equal([]string{"dc1", "dc2"}, job.Datacenters)
+}
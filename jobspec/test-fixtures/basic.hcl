@@ -5,8 +5,11 @@ job "binstore-storagelocker" {
   priority    = 52
   all_at_once = true
   datacenters = ["us2", "eu1"]
+  node_pool   = "gpu"
   vault_token = "foo"
 
+  tags = ["owner:storage", "tier:1"]
+
   meta {
     foo = "bar"
   }
@@ -43,6 +46,10 @@ job "binstore-storagelocker" {
     progress_deadline = "10m"
     auto_revert = true
     canary = 1
+
+    progress_webhook {
+      url = "https://ci.example.com/deployments"
+    }
   }
 
   task "outside" {
@@ -72,6 +79,9 @@ job "binstore-storagelocker" {
        interval = "12h"
     }
 
+    max_client_disconnect = "10m"
+    reconnect_strategy    = "keep_original"
+
     ephemeral_disk {
         sticky = true
         size = 150
@@ -85,6 +95,7 @@ job "binstore-storagelocker" {
         progress_deadline = "1m"
         auto_revert = false
         canary = 2
+        manual_step = true
     }
 
     migrate {
@@ -163,6 +174,8 @@ job "binstore-storagelocker" {
           check_restart {
             limit = 3
             grace = "10s"
+            delay = "30s"
+            max_delay = "5m"
             ignore_warnings = true
           }
         }
@@ -195,6 +208,12 @@ job "binstore-storagelocker" {
 
           port "admin" {
           }
+
+          dns {
+            servers  = ["10.0.0.53"]
+            searches = ["service.consul"]
+            options  = ["ndots:2"]
+          }
         }
 
         device "nvidia/gpu" {
@@ -258,10 +277,24 @@ job "binstore-storagelocker" {
         left_delimiter = "--"
         right_delimiter = "__"
       }
+
+      template {
+        source = "baz"
+        destination = "baz"
+        change_mode = "script"
+
+        change_script {
+          command = "/bin/reload"
+          args = ["-config", "baz"]
+          timeout = "10s"
+          fail_on_error = true
+        }
+      }
     }
 
     task "storagelocker" {
       driver = "docker"
+      metric_labels = ["team"]
 
       config {
         image = "hashicorp/storagelocker"
@@ -279,10 +312,16 @@ job "binstore-storagelocker" {
 
       vault {
         policies = ["foo", "bar"]
+        namespace = "engineering"
         env = false
         change_mode = "signal"
         change_signal = "SIGUSR1"
       }
+
+      identity {
+        env  = true
+        file = true
+      }
     }
 
     constraint {
@@ -0,0 +1,19 @@
+job "foo" {
+  group "web" {
+    consul {
+      cluster = "edge"
+    }
+
+    task "server" {
+      driver = "exec"
+      config {
+        command = "server"
+      }
+
+      vault {
+        cluster  = "secrets"
+        policies = ["web"]
+      }
+    }
+  }
+}
@@ -0,0 +1,4 @@
+job "foo" {
+  type              = "batch"
+  concurrency_group = "migrations"
+}
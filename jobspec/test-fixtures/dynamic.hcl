@@ -0,0 +1,69 @@
+variable "groups" {
+  type = "map"
+
+  default = {
+    web = {
+      count = 2
+      port  = 8080
+    }
+
+    api = {
+      count = 1
+      port  = 9090
+    }
+  }
+}
+
+variable "ports" {
+  default = ["http", "admin"]
+}
+
+variable "subnet" {
+  default = "10.1.0.0/16"
+}
+
+job "dynamic" {
+  datacenters = ["dc1"]
+
+  dynamic "group" {
+    for_each = "${var.groups}"
+    labels   = ["${group.key}"]
+
+    content {
+      count = "${group.value.count}"
+
+      task "server" {
+        driver = "docker"
+
+        config {
+          image = "${group.key}:latest"
+        }
+
+        env {
+          ADDR    = "${cidrhost(cidrsubnet(var.subnet, 8, group.value.count), 10)}"
+          NETMASK = "${cidrnetmask(var.subnet)}"
+          NODE    = "${node.unique.name}"
+        }
+
+        meta = "${yamldecode(file("test-fixtures/dynamic-meta.yaml"))}"
+
+        template {
+          data        = "${templatefile("test-fixtures/dynamic.tpl", { name = group.key, port = group.value.port })}"
+          destination = "local/app.conf"
+        }
+
+        resources {
+          network {
+            dynamic "port" {
+              for_each = "${var.ports}"
+              iterator = "p"
+              labels   = ["${p.value}"]
+
+              content {}
+            }
+          }
+        }
+      }
+    }
+  }
+}
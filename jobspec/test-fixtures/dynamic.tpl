@@ -0,0 +1,3 @@
+name   = "${name}"
+listen = ":${port}"
+logs   = "${NOMAD_ALLOC_DIR}/logs"
@@ -0,0 +1,19 @@
+job "foo" {
+  task "bar" {
+    driver = "docker"
+
+    kill_escalation {
+      signal  = "SIGTERM"
+      timeout = "30s"
+    }
+
+    kill_escalation {
+      signal  = "SIGINT"
+      timeout = "10s"
+    }
+
+    config {
+      image = "hashicorp/image"
+    }
+  }
+}
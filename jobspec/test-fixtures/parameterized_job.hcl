@@ -3,6 +3,16 @@ job "parameterized_job" {
         payload = "required"
         meta_required = ["foo", "bar"]
         meta_optional = ["baz", "bam"]
+
+        meta "foo" {
+            type = "int"
+            enum = ["1", "2"]
+        }
+
+        meta "baz" {
+            regex = "^s3://"
+            required_with = ["bam"]
+        }
     }
     group "foo" {
         task "bar" {
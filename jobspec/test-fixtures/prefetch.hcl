@@ -0,0 +1,5 @@
+job "foo" {
+    prefetch {
+        artifacts = false
+    }
+}
@@ -0,0 +1,14 @@
+job "foo" {
+  task "bar" {
+    driver = "docker"
+
+    config {
+      image = "redis"
+    }
+
+    resources {
+      cores  = 2
+      memory = 512
+    }
+  }
+}
@@ -0,0 +1,17 @@
+job "foo" {
+  task "bar" {
+    driver = "exec"
+
+    config {
+      command = "/bin/dpdk-app"
+    }
+
+    resources {
+      cpu           = 500
+      memory        = 512
+      hugepages_2mb = 64
+      hugepages_1gb = 1
+      realtime      = true
+    }
+  }
+}
@@ -0,0 +1,29 @@
+job "check_role" {
+    type = "service"
+    group "group" {
+        task "task" {
+          service {
+            name = "http-service"
+            port = "http"
+
+            check_restart {
+              limit = 3
+            }
+
+            check {
+              name     = "ready"
+              type     = "http"
+              path     = "/ready"
+              role     = "readiness"
+            }
+
+            check {
+              name     = "alive"
+              type     = "http"
+              path     = "/alive"
+              role     = "liveness"
+            }
+          }
+        }
+    }
+}
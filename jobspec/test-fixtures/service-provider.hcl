@@ -0,0 +1,20 @@
+job "service_provider" {
+    type = "service"
+    group "group" {
+        task "task" {
+          service {
+            name     = "http-service"
+            port     = "http"
+            provider = "nomad"
+
+            check {
+              name     = "http-check"
+              type     = "http"
+              path     = "/"
+              interval = "10s"
+              timeout  = "2s"
+            }
+          }
+        }
+    }
+}
@@ -0,0 +1,21 @@
+job "foo" {
+  group "inference" {
+    shared_memory {
+      size = 1024
+    }
+
+    task "server" {
+      driver = "docker"
+      config {
+        image = "tritonserver"
+      }
+    }
+
+    task "preprocess" {
+      driver = "exec"
+      config {
+        command = "preprocess"
+      }
+    }
+  }
+}
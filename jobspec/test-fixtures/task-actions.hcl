@@ -0,0 +1,19 @@
+job "foo" {
+  task "bar" {
+    driver = "docker"
+
+    config {
+      image = "redis"
+    }
+
+    action "flush-cache" {
+      command = "redis-cli"
+      args    = ["FLUSHALL"]
+      timeout = "30s"
+    }
+
+    action "info" {
+      command = "redis-cli"
+    }
+  }
+}
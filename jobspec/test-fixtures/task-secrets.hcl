@@ -0,0 +1,26 @@
+job "foo" {
+  task "bar" {
+    driver = "docker"
+
+    config {
+      image = "redis"
+    }
+
+    secret "db_password" {
+      provider = "vault"
+      path     = "secret/data/db"
+      key      = "password"
+      perms    = "0440"
+      uid      = 1000
+      gid      = 1000
+    }
+
+    secret "api_key" {
+      provider      = "nomad"
+      path          = "nomad/jobs/foo/api"
+      key           = "key"
+      change_mode   = "signal"
+      change_signal = "SIGHUP"
+    }
+  }
+}
@@ -0,0 +1,51 @@
+variable "name" {
+  description = "The name of the job"
+  default     = "web"
+}
+
+variable "datacenters" {
+  type    = "list"
+  default = ["dc1"]
+}
+
+variable "count" {
+  type = "number"
+}
+
+variable "image_tag" {
+  default = "3.2"
+}
+
+variable "labels" {
+  type    = "map"
+  default = {
+    env = "dev"
+  }
+}
+
+variable "canary" {
+  default = false
+}
+
+job "${var.name}" {
+  datacenters = "${var.datacenters}"
+
+  group "cache" {
+    count = "${var.count}"
+
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image      = "redis:${var.image_tag}"
+        labels     = "${var.labels}"
+        privileged = "${var.canary}"
+      }
+
+      env {
+        DC  = "${node.datacenter}"
+        TAG = "v${var.image_tag}-${var.canary}"
+      }
+    }
+  }
+}
@@ -0,0 +1,6 @@
+job "foo" {
+    version_retention {
+        count = 20
+        age = "720h"
+    }
+}
@@ -0,0 +1,22 @@
+job "windows" {
+    type = "service"
+    group "group" {
+        task "task" {
+          logs {
+            event_log_providers = ["MSSQLSERVER", "SQLSERVERAGENT"]
+          }
+
+          service {
+            name = "sql"
+
+            check {
+              name     = "pipe"
+              type     = "pipe"
+              path     = "\\\\.\\pipe\\sql\\query"
+              interval = "10s"
+              timeout  = "2s"
+            }
+          }
+        }
+    }
+}
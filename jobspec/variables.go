@@ -0,0 +1,341 @@
+package jobspec
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// VarEnvPrefix is the prefix of the environment variables that set the
+	// variables of a job file.
+	VarEnvPrefix = "NOMAD_VAR_"
+
+	VarTypeString = "string"
+	VarTypeNumber = "number"
+	VarTypeBool   = "bool"
+	VarTypeList   = "list"
+	VarTypeMap    = "map"
+)
+
+var (
+	// reVarName matches the valid variable names
+	reVarName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+)
+
+// Variables are the sources of the values of the variables declared by a job
+// file. Values are set, from lowest to highest precedence, by the default of
+// the variable, the environment, the variable files and the name=value pairs.
+type Variables struct {
+	// Env are environment variables in the "key=value" form of os.Environ.
+	// The NOMAD_VAR_<name> variables set the value of <name>.
+	Env []string
+
+	// Files are the paths of HCL files of "name = value" assignments.
+	Files []string
+
+	// Vars are the "name=value" pairs given on the command line.
+	Vars []string
+}
+
+// variable is a variable declared by a job file.
+type variable struct {
+	name  string
+	typ   string
+	value ast.Node
+}
+
+// parseVariables parses the variable blocks of the job file and binds their
+// values.
+func parseVariables(list *ast.ObjectList, vars *Variables) (map[string]*variable, error) {
+	declared := make(map[string]*variable)
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return nil, fmt.Errorf("variable block must have exactly one name")
+		}
+		name := item.Keys[0].Token.Value().(string)
+		if !reVarName.MatchString(name) {
+			return nil, fmt.Errorf("invalid variable name %q", name)
+		}
+		if _, ok := declared[name]; ok {
+			return nil, fmt.Errorf("variable %q declared more than once", name)
+		}
+
+		v, err := parseVariable(name, item.Val)
+		if err != nil {
+			return nil, fmt.Errorf("variable %q: %v", name, err)
+		}
+		declared[name] = v
+	}
+
+	if vars == nil {
+		vars = &Variables{}
+	}
+
+	for _, env := range vars.Env {
+		if !strings.HasPrefix(env, VarEnvPrefix) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(env, VarEnvPrefix), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		// Variables of the environment are set for any job and so are
+		// ignored when the job does not declare them.
+		v, ok := declared[parts[0]]
+		if !ok {
+			continue
+		}
+		value, err := convertVarString(v.typ, parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for variable %q from environment variable %s%s: %v",
+				v.name, VarEnvPrefix, v.name, err)
+		}
+		v.value = value
+	}
+
+	for _, path := range vars.Files {
+		if err := parseVarFile(path, declared); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, kv := range vars.Vars {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid variable %q, must be of the form name=value", kv)
+		}
+		v, ok := declared[parts[0]]
+		if !ok {
+			return nil, fmt.Errorf("variable %q is not declared in the job file", parts[0])
+		}
+		value, err := convertVarString(v.typ, parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for variable %q: %v", v.name, err)
+		}
+		v.value = value
+	}
+
+	var mErr multierror.Error
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if declared[name].value == nil {
+			multierror.Append(&mErr, fmt.Errorf("missing value for variable %q", name))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+	return declared, nil
+}
+
+func parseVariable(name string, node ast.Node) (*variable, error) {
+	obj, ok := node.(*ast.ObjectType)
+	if !ok {
+		return nil, fmt.Errorf("should be an object")
+	}
+
+	valid := []string{
+		"default",
+		"description",
+		"type",
+	}
+	if err := helper.CheckHCLKeys(obj.List, valid); err != nil {
+		return nil, err
+	}
+
+	v := &variable{name: name}
+	if o := obj.List.Filter("type"); len(o.Items) > 0 {
+		if err := hcl.DecodeObject(&v.typ, o.Items[0].Val); err != nil {
+			return nil, err
+		}
+		switch v.typ {
+		case VarTypeString, VarTypeNumber, VarTypeBool, VarTypeList, VarTypeMap:
+		default:
+			return nil, fmt.Errorf("invalid type %q, must be one of string, number, bool, list or map", v.typ)
+		}
+	}
+
+	if o := obj.List.Filter("default"); len(o.Items) > 0 {
+		def := o.Items[0].Val
+		if v.typ == "" {
+			v.typ = varNodeType(def)
+		}
+		value, err := convertVarNode(v.typ, def)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default: %v", err)
+		}
+		v.value = value
+	}
+
+	if v.typ == "" {
+		v.typ = VarTypeString
+	}
+	return v, nil
+}
+
+// parseVarFile sets the variables assigned by the variable file at the given
+// path.
+func parseVarFile(path string, declared map[string]*variable) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading variable file: %v", err)
+	}
+
+	root, err := hcl.Parse(string(contents))
+	if err != nil {
+		return fmt.Errorf("error parsing variable file %q: %v", path, err)
+	}
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return fmt.Errorf("error parsing variable file %q: root should be an object", path)
+	}
+
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("error parsing variable file %q: variables must be assigned as name = value", path)
+		}
+		name := item.Keys[0].Token.Value().(string)
+		v, ok := declared[name]
+		if !ok {
+			return fmt.Errorf("variable %q of variable file %q is not declared in the job file", name, path)
+		}
+		value, err := convertVarNode(v.typ, item.Val)
+		if err != nil {
+			return fmt.Errorf("invalid value for variable %q in variable file %q: %v", name, path, err)
+		}
+		v.value = value
+	}
+	return nil
+}
+
+// formatVariables returns the values of the given variables as "name = value"
+// assignments, in the form of variable files.
+func formatVariables(declared map[string]*variable) string {
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s = %s\n", name, formatVarNode(declared[name].value))
+	}
+	return buf.String()
+}
+
+// formatVarNode returns the given value in HCL syntax, on a single line.
+func formatVarNode(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.LiteralType:
+		if n.Token.Type == token.HEREDOC {
+			return strconv.Quote(n.Token.Value().(string))
+		}
+		return n.Token.Text
+	case *ast.ListType:
+		elems := make([]string, len(n.List))
+		for i, elem := range n.List {
+			elems[i] = formatVarNode(elem)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case *ast.ObjectType:
+		items := make([]string, len(n.List.Items))
+		for i, item := range n.List.Items {
+			keys := make([]string, len(item.Keys))
+			for j, key := range item.Keys {
+				keys[j] = key.Token.Text
+			}
+			items[i] = fmt.Sprintf("%s = %s", strings.Join(keys, " "), formatVarNode(item.Val))
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	}
+	return ""
+}
+
+// varNodeType returns the variable type of the given value.
+func varNodeType(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.ListType:
+		return VarTypeList
+	case *ast.ObjectType:
+		return VarTypeMap
+	case *ast.LiteralType:
+		switch n.Token.Type {
+		case token.NUMBER, token.FLOAT:
+			return VarTypeNumber
+		case token.BOOL:
+			return VarTypeBool
+		}
+	}
+	return VarTypeString
+}
+
+// convertVarNode converts the given value to the variable type. Literal
+// values are converted between scalar types as their string form would be.
+func convertVarNode(typ string, node ast.Node) (ast.Node, error) {
+	switch n := node.(type) {
+	case *ast.LiteralType:
+		if typ == VarTypeList || typ == VarTypeMap {
+			break
+		}
+		return convertVarString(typ, fmt.Sprintf("%v", n.Token.Value()))
+	case *ast.ListType:
+		if typ == VarTypeList {
+			return n, nil
+		}
+	case *ast.ObjectType:
+		if typ == VarTypeMap {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("expected a value of type %s", typ)
+}
+
+// convertVarString converts the given string to the variable type. Lists and
+// maps are given in HCL syntax.
+func convertVarString(typ, value string) (ast.Node, error) {
+	switch typ {
+	case VarTypeString:
+		return &ast.LiteralType{Token: token.Token{Type: token.STRING, Text: strconv.Quote(value)}}, nil
+	case VarTypeNumber:
+		if _, err := strconv.ParseInt(value, 0, 64); err == nil {
+			return &ast.LiteralType{Token: token.Token{Type: token.NUMBER, Text: value}}, nil
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return &ast.LiteralType{Token: token.Token{Type: token.FLOAT, Text: value}}, nil
+		}
+		return nil, fmt.Errorf("%q is not a number", value)
+	case VarTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", value)
+		}
+		return &ast.LiteralType{Token: token.Token{Type: token.BOOL, Text: strconv.FormatBool(b)}}, nil
+	default:
+		root, err := hcl.Parse("value = " + value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s %q: %v", typ, value, err)
+		}
+		list := root.Node.(*ast.ObjectList)
+		if len(list.Items) != 1 {
+			return nil, fmt.Errorf("expected a value of type %s", typ)
+		}
+		return convertVarNode(typ, list.Items[0].Val)
+	}
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -31,6 +32,15 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 		return nil, err
 	}
 
+	// Workload identities are JWTs, unlike the UUID secret IDs of tokens
+	if strings.Count(secretID, ".") == 2 {
+		claims, err := verifyIdentity(snap, secretID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return identityACL(claims)
+	}
+
 	// Resolve the ACL
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
@@ -54,6 +64,9 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
+		if token.IsExpired(time.Now().UTC()) {
+			return nil, structs.ErrTokenExpired
+		}
 	}
 
 	// Check if this is a management token
@@ -61,9 +74,13 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		return acl.ManagementACL, nil
 	}
 
-	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
-	for _, policyName := range token.Policies {
+	// Get all associated policies, including those of the token's roles
+	policyNames, err := resolveTokenPolicyNames(&snap.StateStore, token)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
@@ -84,3 +101,35 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 	}
 	return aclObj, nil
 }
+
+// resolveTokenPolicyNames returns the names of the policies of a token and of
+// its roles, without duplicates. Roles that don't exist are ignored, since
+// they don't grant any more privilege.
+func resolveTokenPolicyNames(state *state.StateStore, token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	seen := make(map[string]struct{}, len(token.Policies))
+	names := make([]string, 0, len(token.Policies))
+	add := func(policies []string) {
+		for _, name := range policies {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+
+	add(token.Policies)
+	for _, roleName := range token.Roles {
+		role, err := state.ACLRoleByName(nil, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if role != nil {
+			add(role.Policies)
+		}
+	}
+	return names, nil
+}
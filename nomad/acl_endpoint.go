@@ -5,12 +5,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -148,10 +150,11 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 			return structs.ErrTokenNotFound
 		}
 
-		policies = make(map[string]struct{}, len(token.Policies))
-		for _, p := range token.Policies {
-			policies[p] = struct{}{}
+		names, err := resolveTokenPolicyNames(&snap.StateStore, token)
+		if err != nil {
+			return err
 		}
+		policies = helper.SliceStringToSet(names)
 	}
 
 	// Setup the blocking query
@@ -235,15 +238,11 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 			return structs.ErrTokenNotFound
 		}
 
-		found := false
-		for _, p := range token.Policies {
-			if p == args.Name {
-				found = true
-				break
-			}
+		names, err := resolveTokenPolicyNames(&snap.StateStore, token)
+		if err != nil {
+			return err
 		}
-
-		if !found {
+		if _, ok := helper.SliceStringToSet(names)[args.Name]; !ok {
 			return structs.ErrPermissionDenied
 		}
 	}
@@ -304,8 +303,15 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 	if token == nil {
 		return structs.ErrTokenNotFound
 	}
-	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
-		return structs.ErrPermissionDenied
+	if token.Type != structs.ACLManagementToken {
+		// Tokens may also query the policies of their roles
+		names, err := resolveTokenPolicyNames(a.srv.State(), token)
+		if err != nil {
+			return err
+		}
+		if subset, _ := helper.SliceStringIsSubset(names, args.Names); !subset {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Setup the blocking query
@@ -396,6 +402,14 @@ func (a *ACL) Bootstrap(args *structs.ACLTokenBootstrapRequest, reply *structs.A
 		return err
 	}
 
+	// The reset file only allows a single reset, so remove it once used
+	if args.ResetIndex != 0 {
+		path := filepath.Join(a.srv.config.DataDir, aclBootstrapReset)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			a.logger.Error("failed to remove bootstrap file", "path", path, "error", err)
+		}
+	}
+
 	// Populate the response. We do a lookup against the state to
 	// pickup the proper create / modify times.
 	state, err = a.srv.State().Snapshot()
@@ -503,6 +517,17 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			token.SecretID = uuid.Generate()
 			token.CreateTime = time.Now().UTC()
 
+			// Compute the expiration of expiring tokens
+			if ttl := token.ExpirationTTL; ttl != 0 {
+				if ttl < structs.ACLTokenMinExpirationTTL {
+					return fmt.Errorf("token %d invalid: expiration TTL must be at least %v", idx, structs.ACLTokenMinExpirationTTL)
+				}
+				if max := a.srv.config.ACLTokenMaxExpirationTTL; max != 0 && ttl > max {
+					return fmt.Errorf("token %d invalid: expiration TTL must be at most %v", idx, max)
+				}
+				expiration := token.CreateTime.Add(ttl)
+				token.ExpirationTime = &expiration
+			}
 		} else {
 			// Verify the token exists
 			out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
@@ -517,6 +542,10 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			if token.Global != out.Global {
 				return fmt.Errorf("cannot toggle global mode of %s", token.AccessorID)
 			}
+
+			// Updates cannot extend the lifetime of an expiring token
+			token.ExpirationTime = out.ExpirationTime
+			token.ExpirationTTL = out.ExpirationTTL
 		}
 
 		// Compute the token hash
@@ -833,3 +862,1097 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 	}
 	return nil
 }
+
+// UpsertOneTimeToken is used to create a one-time token for the ACL token of
+// the request, which can be exchanged once for the ACL token until it expires
+func (a *ACL) UpsertOneTimeToken(args *structs.OneTimeTokenUpsertRequest, reply *structs.OneTimeTokenUpsertResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.UpsertOneTimeToken", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_one_time_token"}, time.Now())
+
+	// The anonymous token can't be handed over
+	if args.AuthToken == "" {
+		return structs.ErrPermissionDenied
+	}
+	token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	now := time.Now().UTC()
+	if token.IsExpired(now) {
+		return structs.ErrTokenExpired
+	}
+
+	ott := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      token.AccessorID,
+		ExpiresAt:       now.Add(structs.OneTimeTokenTTL),
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.OneTimeTokenUpsertRequestType, ott)
+	if err != nil {
+		return err
+	}
+
+	ott.CreateIndex = index
+	ott.ModifyIndex = index
+	reply.OneTimeToken = ott
+	reply.Index = index
+	return nil
+}
+
+// ExchangeOneTimeToken is used to exchange a one-time token for the ACL token
+// it was created with. The one-time token is deleted by the exchange.
+func (a *ACL) ExchangeOneTimeToken(args *structs.OneTimeTokenExchangeRequest, reply *structs.OneTimeTokenExchangeResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ExchangeOneTimeToken", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "exchange_one_time_token"}, time.Now())
+
+	// Unknown, expired and malformed one-time tokens are all denied alike
+	if !helper.IsUUID(args.OneTimeSecretID) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Look up and delete the one-time token in a single Raft apply, so that
+	// concurrent exchanges of the same one-time token can't both succeed
+	args.Timestamp = time.Now().UTC()
+	out, index, err := a.srv.raftApply(structs.OneTimeTokenExchangeRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := out.(error); ok && err != nil {
+		if err == structs.ErrOneTimeTokenNotFound || err == structs.ErrTokenNotFound {
+			return structs.ErrPermissionDenied
+		}
+		return err
+	}
+	token, ok := out.(*structs.ACLToken)
+	if !ok || token == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Token = token
+	reply.Index = index
+	return nil
+}
+
+// ExpireOneTimeTokens is used to delete the expired one-time tokens
+func (a *ACL) ExpireOneTimeTokens(args *structs.OneTimeTokenExpireRequest, reply *structs.GenericResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ExpireOneTimeTokens", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "expire_one_time_tokens"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Update via Raft
+	args.Timestamp = time.Now().UTC()
+	_, index, err := a.srv.raftApply(structs.OneTimeTokenExpireRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// UpsertRoles is used to create or update a set of roles
+func (a *ACL) UpsertRoles(args *structs.ACLRoleUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Roles) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Validate each role, compute hash
+	for idx, role := range args.Roles {
+		if err := role.Validate(); err != nil {
+			return fmt.Errorf("role %d invalid: %v", idx, err)
+		}
+		role.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRoles is used to delete roles
+func (a *ACL) DeleteRoles(args *structs.ACLRoleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list the roles
+func (a *ACL) ListRoles(args *structs.ACLRoleListRequest, reply *structs.ACLRoleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine the roles that may be listed
+	mgt := acl.IsManagement()
+	var roles map[string]struct{}
+	if !mgt {
+		token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+		roles = helper.SliceStringToSet(token.Roles)
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the roles
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLRoleByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLRoles(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the roles to a list stub
+			reply.Roles = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				role := raw.(*structs.ACLRole)
+				if _, ok := roles[role.Name]; ok || mgt {
+					reply.Roles = append(reply.Roles, role.Stub())
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRole is used to get a specific role
+func (a *ACL) GetRole(args *structs.ACLRoleSpecificRequest, reply *structs.SingleACLRoleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRole", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine if it can get this role
+	if !acl.IsManagement() {
+		token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+		if !token.RoleSubset([]string{args.Name}) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the role
+			out, err := state.ACLRoleByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Role = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the role table
+				index, err := state.Index("acl_role")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRoles is used to get a set of roles
+func (a *ACL) GetRoles(args *structs.ACLRoleSetRequest, reply *structs.ACLRoleSetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles"}, time.Now())
+
+	var token *structs.ACLToken
+	var err error
+	if args.AuthToken == "" {
+		// No need to look up the anonymous token
+		token = structs.AnonymousACLToken
+	} else {
+		// For client typed tokens, allow them to query any roles associated with that token.
+		// This is used by clients which are resolving the policies to enforce.
+		token, err = a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+	if token.Type != structs.ACLManagementToken && !token.RoleSubset(args.Names) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Roles = make(map[string]*structs.ACLRole, len(args.Names))
+
+			// Look for the roles
+			for _, roleName := range args.Names {
+				out, err := state.ACLRoleByName(ws, roleName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Roles[roleName] = out
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertAuthMethods is used to create or update a set of auth methods
+func (a *ACL) UpsertAuthMethods(args *structs.ACLAuthMethodUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.AuthMethods) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Validate each auth method, compute hash
+	var defaultMethod string
+	for idx, method := range args.AuthMethods {
+		if err := method.Validate(); err != nil {
+			return fmt.Errorf("auth method %d invalid: %v", idx, err)
+		}
+		if method.Default {
+			if defaultMethod != "" {
+				return fmt.Errorf("only one auth method may be the default")
+			}
+			defaultMethod = method.Name
+		}
+		method.SetHash()
+	}
+
+	// Ensure there is only a single default auth method
+	if defaultMethod != "" {
+		state, err := a.srv.State().Snapshot()
+		if err != nil {
+			return err
+		}
+		existing, err := state.DefaultACLAuthMethod(nil)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.Name != defaultMethod {
+			return fmt.Errorf("auth method %q is already the default", existing.Name)
+		}
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to delete auth methods
+func (a *ACL) DeleteAuthMethods(args *structs.ACLAuthMethodDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods. The listing holds no
+// secrets and is used before logging in, so it doesn't require a token.
+func (a *ACL) ListAuthMethods(args *structs.ACLAuthMethodListRequest, reply *structs.ACLAuthMethodListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.ListAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Convert all the auth methods to a list stub
+			reply.AuthMethods = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				method := raw.(*structs.ACLAuthMethod)
+				reply.AuthMethods = append(reply.AuthMethods, method.Stub())
+			}
+
+			// Use the last index that affected the auth method table
+			index, err := state.Index("acl_auth_method")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethod is used to get a specific auth method
+func (a *ACL) GetAuthMethod(args *structs.ACLAuthMethodSpecificRequest, reply *structs.SingleACLAuthMethodResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.GetAuthMethod", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method"}, time.Now())
+
+	// Check management level permissions, since the config holds the
+	// client secret
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the auth method
+			out, err := state.ACLAuthMethodByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.AuthMethod = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the auth method table
+				index, err := state.Index("acl_auth_method")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertBindingRules is used to create or update a set of binding rules.
+// Binding rules without an ID are created and given one.
+func (a *ACL) UpsertBindingRules(args *structs.ACLBindingRuleUpsertRequest, reply *structs.ACLBindingRuleUpsertResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.BindingRules) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule, generate IDs and compute hash
+	for idx, rule := range args.BindingRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("binding rule %d invalid: %v", idx, err)
+		}
+
+		method, err := state.ACLAuthMethodByName(nil, rule.AuthMethod)
+		if err != nil {
+			return err
+		}
+		if method == nil {
+			return fmt.Errorf("binding rule %d invalid: auth method %q not found", idx, rule.AuthMethod)
+		}
+
+		if rule.ID == "" {
+			rule.ID = uuid.Generate()
+		} else {
+			out, err := state.ACLBindingRuleByID(nil, rule.ID)
+			if err != nil {
+				return fmt.Errorf("binding rule lookup failed: %v", err)
+			}
+			if out == nil {
+				return fmt.Errorf("cannot find binding rule %s", rule.ID)
+			}
+			if out.AuthMethod != rule.AuthMethod {
+				return fmt.Errorf("cannot change the auth method of binding rule %s", rule.ID)
+			}
+		}
+		rule.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify times.
+	state, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, rule := range args.BindingRules {
+		out, err := state.ACLBindingRuleByID(nil, rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+		reply.BindingRules = append(reply.BindingRules, out)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to delete binding rules
+func (a *ACL) DeleteBindingRules(args *structs.ACLBindingRuleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.IDs) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list the binding rules, optionally only those
+// of an auth method
+func (a *ACL) ListBindingRules(args *structs.ACLBindingRuleListRequest, reply *structs.ACLBindingRuleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.ListBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			var iter memdb.ResultIterator
+			var err error
+			if args.AuthMethod != "" {
+				iter, err = state.ACLBindingRulesByAuthMethod(ws, args.AuthMethod)
+			} else {
+				iter, err = state.ACLBindingRules(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the binding rules to a list stub
+			reply.BindingRules = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				rule := raw.(*structs.ACLBindingRule)
+				reply.BindingRules = append(reply.BindingRules, rule.Stub())
+			}
+
+			// Use the last index that affected the binding rule table
+			index, err := state.Index("acl_binding_rule")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetBindingRule is used to get a specific binding rule
+func (a *ACL) GetBindingRule(args *structs.ACLBindingRuleSpecificRequest, reply *structs.SingleACLBindingRuleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.GetBindingRule", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the binding rule
+			out, err := state.ACLBindingRuleByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.BindingRule = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the binding rule table
+				index, err := state.Index("acl_binding_rule")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// authMethod returns the named auth method, or the default auth method if
+// the name is empty, and checks that it is of the given type.
+func (a *ACL) authMethod(name, methodType string) (*structs.ACLAuthMethod, error) {
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	var method *structs.ACLAuthMethod
+	if name == "" {
+		method, err = state.DefaultACLAuthMethod(nil)
+		if err != nil {
+			return nil, err
+		}
+		if method == nil {
+			return nil, fmt.Errorf("no auth method given and there is no default auth method")
+		}
+	} else {
+		method, err = state.ACLAuthMethodByName(nil, name)
+		if err != nil {
+			return nil, err
+		}
+		if method == nil {
+			return nil, fmt.Errorf("auth method %q not found", name)
+		}
+	}
+
+	if method.Type != methodType {
+		return nil, fmt.Errorf("auth method %q is not of type %q", method.Name, methodType)
+	}
+	return method, nil
+}
+
+// oidcAuthMethod returns the named OIDC auth method, or the default auth
+// method if the name is empty, and checks that the redirect URI is allowed.
+func (a *ACL) oidcAuthMethod(name, redirectURI string) (*structs.ACLAuthMethod, error) {
+	method, err := a.authMethod(name, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, uri := range method.Config.AllowedRedirectURIs {
+		if uri == redirectURI {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf("redirect URI %q is not allowed by auth method %q", redirectURI, method.Name)
+	}
+	return method, nil
+}
+
+// OIDCAuthURL is used to start an OIDC login. It returns the URL of the
+// provider that the user logs in at.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.OIDCAuthURL", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	if args.ClientNonce == "" || args.State == "" {
+		return fmt.Errorf("missing client nonce or state")
+	}
+
+	method, err := a.oidcAuthMethod(args.AuthMethodName, args.RedirectURI)
+	if err != nil {
+		return err
+	}
+	provider, err := oidcDiscover(method.Config)
+	if err != nil {
+		return err
+	}
+
+	reply.AuthURL = oidcAuthURL(method.Config, provider, args.RedirectURI, args.State, args.ClientNonce)
+	return nil
+}
+
+// OIDCCompleteAuth is used to complete an OIDC login. The authorization code
+// returned by the provider is exchanged for the user's ID token, and a global
+// client token expiring after the auth method's token TTL is created with the
+// policies the claims of the user map to.
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.OIDCCompleteAuth", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	if args.ClientNonce == "" || args.Code == "" {
+		return fmt.Errorf("missing client nonce or authorization code")
+	}
+
+	method, err := a.oidcAuthMethod(args.AuthMethodName, args.RedirectURI)
+	if err != nil {
+		return err
+	}
+	provider, err := oidcDiscover(method.Config)
+	if err != nil {
+		return err
+	}
+	idToken, err := oidcExchangeCode(method.Config, provider, args.RedirectURI, args.Code)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	claims, err := oidcVerifyIDToken(method.Config, provider, idToken, args.ClientNonce, now)
+	if err != nil {
+		return err
+	}
+	token, index, err := a.loginToken(method, claims, "OIDC", now)
+	if err != nil {
+		return err
+	}
+
+	reply.Token = token
+	reply.Index = index
+	return nil
+}
+
+// Login is used to exchange a JWT for an ACL token with a JWT auth method.
+// The JWT is verified against the keys, issuer and audiences of the auth
+// method, and a global client token expiring after the auth method's token
+// TTL is created with the policies and roles the claims map to.
+func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.Login", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "login"}, time.Now())
+
+	if args.LoginToken == "" {
+		return fmt.Errorf("missing login token")
+	}
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeJWT)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	claims, err := jwtVerifyLoginToken(method.Config, args.LoginToken, now)
+	if err != nil {
+		return err
+	}
+	token, index, err := a.loginToken(method, claims, "JWT", now)
+	if err != nil {
+		return err
+	}
+
+	reply.Token = token
+	reply.Index = index
+	return nil
+}
+
+// loginGrants returns the policies and roles given to a login with the
+// claims: those of the auth method itself and those of the binding rules of
+// the auth method that match the claims.
+func (a *ACL) loginGrants(method *structs.ACLAuthMethod, claims map[string]interface{}) ([]string, []string, error) {
+	policies, err := authMethodPolicies(method.Config, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, method.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	policySet := make(map[string]struct{}, len(policies))
+	for _, p := range policies {
+		policySet[p] = struct{}{}
+	}
+	roleSet := make(map[string]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		if !bindingRuleMatches(rule, claims) {
+			continue
+		}
+		name, ok := rule.BindNameFor(claims)
+		if !ok {
+			continue
+		}
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypePolicy:
+			policySet[name] = struct{}{}
+		case structs.ACLBindingRuleBindTypeRole:
+			roleSet[name] = struct{}{}
+		}
+	}
+	if len(policySet) == 0 && len(roleSet) == 0 {
+		return nil, nil, fmt.Errorf("auth method grants no policies or roles to the user")
+	}
+
+	policies = make([]string, 0, len(policySet))
+	for p := range policySet {
+		policies = append(policies, p)
+	}
+	sort.Strings(policies)
+	var roles []string
+	for r := range roleSet {
+		roles = append(roles, r)
+	}
+	sort.Strings(roles)
+	return policies, roles, nil
+}
+
+// loginToken creates the token of a login with the verified claims. The
+// token is a global client token expiring after the auth method's token TTL.
+func (a *ACL) loginToken(method *structs.ACLAuthMethod, claims map[string]interface{}, kind string, now time.Time) (*structs.ACLToken, uint64, error) {
+	subject, _ := claims["sub"].(string)
+	policies, roles, err := a.loginGrants(method, claims)
+	if err != nil {
+		a.logger.Warn("denied login", "auth_method", method.Name, "subject", subject, "error", err)
+		return nil, 0, structs.ErrPermissionDenied
+	}
+
+	expiration := now.Add(method.TokenTTL)
+	token := &structs.ACLToken{
+		AccessorID:     uuid.Generate(),
+		SecretID:       uuid.Generate(),
+		Name:           fmt.Sprintf("%s-%s-%s", kind, method.Name, subject),
+		Type:           structs.ACLClientToken,
+		Policies:       policies,
+		Roles:          roles,
+		Global:         true,
+		CreateTime:     now,
+		ExpirationTime: &expiration,
+	}
+	token.SetHash()
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens:       []*structs.ACLToken{token},
+		WriteRequest: structs.WriteRequest{Region: a.srv.config.AuthoritativeRegion},
+	}
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Lookup the token to pickup the proper create / modify indexes
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("token lookup failed: %v", err)
+	}
+
+	a.logger.Info("created token with login", "auth_method", method.Name, "subject", subject, "accessor_id", token.AccessorID)
+	return out, index, nil
+}
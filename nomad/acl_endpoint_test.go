@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestACLEndpoint_GetPolicy(t *testing.T) {
@@ -1027,6 +1028,10 @@ func TestACLEndpoint_Bootstrap_Reset(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, created, out)
 
+	// The reset file is consumed
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
 	// Try again, should fail
 	if err := msgpackrpc.CallWithCodec(codec, "ACL.Bootstrap", req, &resp); err == nil {
 		t.Fatalf("expected err")
@@ -1089,6 +1094,47 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_ExpirationTTL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.ACLTokenMaxExpirationTTL = 24 * time.Hour
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	p1 := mock.ACLToken()
+	p1.AccessorID = ""
+	p1.ExpirationTTL = time.Hour
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{p1},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+
+	// The expiration is computed from the TTL
+	created := resp.Tokens[0]
+	require.NotNil(created.ExpirationTime)
+	require.Equal(created.CreateTime.Add(time.Hour), *created.ExpirationTime)
+	require.Equal(time.Hour, created.ExpirationTTL)
+
+	// TTLs outside of the bounds are rejected
+	for _, ttl := range []time.Duration{time.Second, 48 * time.Hour} {
+		p2 := mock.ACLToken()
+		p2.AccessorID = ""
+		p2.ExpirationTTL = ttl
+		req.Tokens = []*structs.ACLToken{p2}
+		err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+		require.Error(err)
+		require.Contains(err.Error(), "expiration TTL")
+	}
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1147,3 +1193,610 @@ func TestACLEndpoint_ResolveToken(t *testing.T) {
 	assert.Equal(t, uint64(1000), resp.Index)
 	assert.Nil(t, resp.Token)
 }
+
+func TestACLEndpoint_OneTimeToken(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.ACLToken()
+	require.NoError(s1.fsm.State().UpsertACLTokens(1000, []*structs.ACLToken{token}))
+
+	// Anonymous requests can't create one-time tokens
+	upsert := &structs.OneTimeTokenUpsertRequest{
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.OneTimeTokenUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertOneTimeToken", upsert, &upsertResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Create a one-time token for the token
+	upsert.AuthToken = token.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertOneTimeToken", upsert, &upsertResp))
+	ott := upsertResp.OneTimeToken
+	require.NotNil(ott)
+	require.Equal(token.AccessorID, ott.AccessorID)
+	require.NotEqual(token.SecretID, ott.OneTimeSecretID)
+
+	// Exchange it for the token
+	exchange := &structs.OneTimeTokenExchangeRequest{
+		OneTimeSecretID: ott.OneTimeSecretID,
+		WriteRequest:    structs.WriteRequest{Region: "global"},
+	}
+	var exchangeResp structs.OneTimeTokenExchangeResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.ExchangeOneTimeToken", exchange, &exchangeResp))
+	require.Equal(token.SecretID, exchangeResp.Token.SecretID)
+
+	// It can only be exchanged once
+	err = msgpackrpc.CallWithCodec(codec, "ACL.ExchangeOneTimeToken", exchange, &exchangeResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Expired one-time tokens can't be exchanged
+	expired := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      token.AccessorID,
+		ExpiresAt:       time.Now().UTC().Add(-time.Minute),
+	}
+	require.NoError(s1.fsm.State().UpsertOneTimeToken(2000, expired))
+	exchange.OneTimeSecretID = expired.OneTimeSecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.ExchangeOneTimeToken", exchange, &exchangeResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
+
+// TestACLEndpoint_OneTimeToken_Concurrent asserts a one-time token exchanged
+// concurrently is only exchanged once.
+func TestACLEndpoint_OneTimeToken_Concurrent(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.ACLToken()
+	require.NoError(s1.fsm.State().UpsertACLTokens(1000, []*structs.ACLToken{token}))
+	ott := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      token.AccessorID,
+		ExpiresAt:       time.Now().UTC().Add(time.Minute),
+	}
+	require.NoError(s1.fsm.State().UpsertOneTimeToken(1001, ott))
+
+	const exchanges = 2
+	errCh := make(chan error, exchanges)
+	for i := 0; i < exchanges; i++ {
+		go func() {
+			codec := rpcClient(t, s1)
+			exchange := &structs.OneTimeTokenExchangeRequest{
+				OneTimeSecretID: ott.OneTimeSecretID,
+				WriteRequest:    structs.WriteRequest{Region: "global"},
+			}
+			var resp structs.OneTimeTokenExchangeResponse
+			err := msgpackrpc.CallWithCodec(codec, "ACL.ExchangeOneTimeToken", exchange, &resp)
+			if err == nil && resp.Token.SecretID != token.SecretID {
+				err = fmt.Errorf("unexpected token %q", resp.Token.SecretID)
+			}
+			errCh <- err
+		}()
+	}
+
+	var succeeded int
+	for i := 0; i < exchanges; i++ {
+		if err := <-errCh; err == nil {
+			succeeded++
+		} else {
+			require.EqualError(err, structs.ErrPermissionDenied.Error())
+		}
+	}
+	require.Equal(1, succeeded)
+}
+
+func TestACLEndpoint_UpsertDeleteRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	role := mock.ACLRole()
+	req := &structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+
+	out, err := s1.fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.NotEmpty(t, out.Hash)
+
+	// Invalid roles are rejected
+	invalid := mock.ACLRole()
+	invalid.Policies = nil
+	req.Roles = []*structs.ACLRole{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing policies")
+
+	// Management tokens are required
+	req.Roles = []*structs.ACLRole{mock.ACLRole()}
+	req.AuthToken = mock.ACLToken().SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	require.Error(t, err)
+
+	// Delete the role
+	del := &structs.ACLRoleDeleteRequest{
+		Names: []string{role.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.DeleteRoles", del, &resp))
+
+	out, err = s1.fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestACLEndpoint_ListGetRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	require.NoError(t, s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{r1, r2}))
+
+	// Create a token associated with the first role
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{r1.Name}
+	require.NoError(t, s1.fsm.State().UpsertACLTokens(1001, []*structs.ACLToken{token}))
+
+	// Management tokens list all the roles
+	list := &structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var listResp structs.ACLRoleListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", list, &listResp))
+	require.Len(t, listResp.Roles, 2)
+	require.Equal(t, uint64(1000), listResp.Index)
+
+	// Other tokens only list their roles
+	list.AuthToken = token.SecretID
+	listResp = structs.ACLRoleListResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", list, &listResp))
+	require.Len(t, listResp.Roles, 1)
+	require.Equal(t, r1.Name, listResp.Roles[0].Name)
+
+	// Tokens may get their roles
+	get := &structs.ACLRoleSpecificRequest{
+		Name: r1.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleACLRoleResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &getResp))
+	require.Equal(t, r1, getResp.Role)
+
+	get.Name = r2.Name
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetRole", get, &getResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Tokens may get a set of their roles
+	set := &structs.ACLRoleSetRequest{
+		Names: []string{r1.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var setResp structs.ACLRoleSetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", set, &setResp))
+	require.Len(t, setResp.Roles, 1)
+	require.Equal(t, r1, setResp.Roles[r1.Name])
+
+	set.Names = []string{r1.Name, r2.Name}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", set, &setResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Tokens may get the policies of their roles
+	policy := mock.ACLPolicy()
+	policy.Name = "foo"
+	require.NoError(t, s1.fsm.State().UpsertACLPolicies(1002, []*structs.ACLPolicy{policy}))
+
+	policies := &structs.ACLPolicySetRequest{
+		Names: []string{"foo"},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var policiesResp structs.ACLPolicySetResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetPolicies", policies, &policiesResp))
+	require.Len(t, policiesResp.Policies, 1)
+}
+
+func TestACLEndpoint_UpsertAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	method := mock.ACLAuthMethod()
+	method.Default = true
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{method},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, method.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.NotEmpty(t, out.Hash)
+
+	// A second default auth method is rejected
+	method2 := mock.ACLAuthMethod()
+	method2.Default = true
+	req.AuthMethods = []*structs.ACLAuthMethod{method2}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already the default")
+
+	// Invalid auth methods are rejected
+	method2.Default = false
+	method2.TokenTTL = 0
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "token TTL")
+
+	// Management tokens are required
+	req.AuthMethods = []*structs.ACLAuthMethod{mock.ACLAuthMethod()}
+	req.AuthToken = mock.ACLToken().SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.Error(t, err)
+}
+
+func TestACLEndpoint_ListGetDeleteAuthMethods(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	method := mock.ACLAuthMethod()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	// Listing doesn't require a token
+	list := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ACLAuthMethodListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListAuthMethods", list, &listResp))
+	require.Len(t, listResp.AuthMethods, 1)
+	require.Equal(t, method.Name, listResp.AuthMethods[0].Name)
+	require.Equal(t, uint64(1000), listResp.Index)
+
+	// Getting an auth method requires a management token
+	get := &structs.ACLAuthMethodSpecificRequest{
+		Name:         method.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleACLAuthMethodResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &getResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &getResp))
+	require.Equal(t, method, getResp.AuthMethod)
+
+	// Delete the auth method
+	del := &structs.ACLAuthMethodDeleteRequest{
+		Names: []string{method.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.DeleteAuthMethods", del, &delResp))
+
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, method.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	p := newTestOIDCProvider(t)
+	defer p.srv.Close()
+	p.nonce = "nonce1"
+	p.claims = map[string]interface{}{
+		"team":   "engineering",
+		"groups": []interface{}{"admins"},
+	}
+
+	method := mock.ACLAuthMethod()
+	method.Default = true
+	method.Config.OIDCDiscoveryURL = p.srv.URL
+	method.SetHash()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	redirectURI := method.Config.AllowedRedirectURIs[0]
+
+	// Start the login with the default auth method
+	authReq := &structs.ACLOIDCAuthURLRequest{
+		RedirectURI:  redirectURI,
+		ClientNonce:  "nonce1",
+		State:        "state1",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var authResp structs.ACLOIDCAuthURLResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", authReq, &authResp))
+	require.True(t, strings.HasPrefix(authResp.AuthURL, p.srv.URL+"/auth?"))
+	require.Contains(t, authResp.AuthURL, "state=state1")
+
+	// Redirect URIs must be allowed
+	authReq.RedirectURI = "http://evil.example.com/callback"
+	err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", authReq, &authResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed")
+
+	// Complete the login
+	completeReq := &structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method.Name,
+		RedirectURI:    redirectURI,
+		ClientNonce:    "nonce1",
+		Code:           "valid-code",
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var completeResp structs.ACLLoginResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", completeReq, &completeResp))
+
+	token := completeResp.Token
+	require.NotNil(t, token)
+	require.Equal(t, structs.ACLClientToken, token.Type)
+	require.Equal(t, []string{"admin", "engineering"}, token.Policies)
+	require.True(t, token.Global)
+	require.NotNil(t, token.ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(method.TokenTTL), *token.ExpirationTime, time.Minute)
+
+	// The token can be used
+	_, err = s1.ResolveToken(token.SecretID)
+	require.NoError(t, err)
+
+	// Users that don't match the bound claims are denied
+	p.claims["team"] = "sales"
+	err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", completeReq, &completeResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+}
+
+func TestACLEndpoint_UpsertBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	method := mock.ACLJWTAuthMethod()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	rule := mock.ACLBindingRule(method.Name)
+	rule.ID = ""
+	req := &structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{rule},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRuleUpsertResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp))
+	require.NotEqual(t, uint64(0), resp.Index)
+	require.Len(t, resp.BindingRules, 1)
+
+	// The binding rule is given an ID
+	created := resp.BindingRules[0]
+	require.NotEmpty(t, created.ID)
+	require.NotEmpty(t, created.Hash)
+
+	// Binding rules can be updated by ID
+	update := created.Copy()
+	update.BindName = "deploy"
+	req.BindingRules = []*structs.ACLBindingRule{update}
+	resp = structs.ACLBindingRuleUpsertResponse{}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp))
+	out, err := s1.fsm.State().ACLBindingRuleByID(nil, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "deploy", out.BindName)
+	require.Equal(t, created.CreateIndex, out.CreateIndex)
+
+	// Unknown binding rules can't be updated
+	unknown := mock.ACLBindingRule(method.Name)
+	req.BindingRules = []*structs.ACLBindingRule{unknown}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot find binding rule")
+
+	// The auth method must exist
+	missing := mock.ACLBindingRule("missing")
+	missing.ID = ""
+	req.BindingRules = []*structs.ACLBindingRule{missing}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not found")
+
+	// Management tokens are required
+	rule = mock.ACLBindingRule(method.Name)
+	rule.ID = ""
+	req.BindingRules = []*structs.ACLBindingRule{rule}
+	req.AuthToken = mock.ACLToken().SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.Error(t, err)
+}
+
+func TestACLEndpoint_ListGetDeleteBindingRules(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	rule := mock.ACLBindingRule("ci")
+	other := mock.ACLBindingRule("other")
+	require.NoError(t, s1.fsm.State().UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule, other}))
+
+	// List the binding rules of an auth method
+	list := &structs.ACLBindingRuleListRequest{
+		AuthMethod: "ci",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var listResp structs.ACLBindingRuleListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", list, &listResp))
+	require.Len(t, listResp.BindingRules, 1)
+	require.Equal(t, rule.ID, listResp.BindingRules[0].ID)
+	require.Equal(t, uint64(1000), listResp.Index)
+
+	list.AuthMethod = ""
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", list, &listResp))
+	require.Len(t, listResp.BindingRules, 2)
+
+	// Getting a binding rule requires a management token
+	get := &structs.ACLBindingRuleSpecificRequest{
+		ID:           rule.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleACLBindingRuleResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetBindingRule", get, &getResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetBindingRule", get, &getResp))
+	require.Equal(t, rule, getResp.BindingRule)
+
+	// Delete the binding rule
+	del := &structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{rule.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.DeleteBindingRules", del, &delResp))
+
+	out, err := s1.fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestACLEndpoint_JWTLogin(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	p := newTestOIDCProvider(t)
+	defer p.srv.Close()
+
+	method := mock.ACLJWTAuthMethod()
+	method.Config.JWKSURL = p.srv.URL + "/keys"
+	method.Config.BoundIssuer = p.srv.URL
+	method.SetHash()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	// Pipelines of the main branch get the deploy role of their project,
+	// and every pipeline gets the readonly policy
+	deploy := mock.ACLBindingRule(method.Name)
+	readonly := &structs.ACLBindingRule{
+		ID:         uuid.Generate(),
+		AuthMethod: method.Name,
+		BindType:   structs.ACLBindingRuleBindTypePolicy,
+		BindName:   "readonly",
+	}
+	require.NoError(t, s1.fsm.State().UpsertACLBindingRules(1001, []*structs.ACLBindingRule{deploy, readonly}))
+
+	claims := p.idTokenClaims()
+	claims["ref"] = "refs/heads/main"
+	claims["project"] = "web"
+	req := &structs.ACLLoginRequest{
+		AuthMethodName: method.Name,
+		LoginToken:     p.sign(t, claims),
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ACLLoginResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp))
+
+	token := resp.Token
+	require.NotNil(t, token)
+	require.Equal(t, structs.ACLClientToken, token.Type)
+	require.Equal(t, []string{"readonly"}, token.Policies)
+	require.Equal(t, []string{"deploy-web"}, token.Roles)
+	require.True(t, token.Global)
+	require.NotNil(t, token.ExpirationTime)
+	require.WithinDuration(t, time.Now().Add(method.TokenTTL), *token.ExpirationTime, time.Minute)
+
+	// Other branches only match the readonly rule
+	claims["ref"] = "refs/heads/feature"
+	req.LoginToken = p.sign(t, claims)
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp))
+	require.Equal(t, []string{"readonly"}, resp.Token.Policies)
+	require.Empty(t, resp.Token.Roles)
+
+	// Logins granted nothing are denied
+	del := []string{readonly.ID}
+	require.NoError(t, s1.fsm.State().DeleteACLBindingRules(1002, del))
+	err := msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Invalid tokens are rejected
+	req.LoginToken = "not.a.jwt"
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	require.Error(t, err)
+
+	// OIDC auth methods can't be used
+	oidc := mock.ACLAuthMethod()
+	require.NoError(t, s1.fsm.State().UpsertACLAuthMethods(1003, []*structs.ACLAuthMethod{oidc}))
+	req.AuthMethodName = oidc.Name
+	req.LoginToken = p.sign(t, claims)
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not of type")
+}
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -107,3 +108,51 @@ func TestResolveACLToken_LeaderToken(t *testing.T) {
 		assert.True(token.IsManagement())
 	}
 }
+
+func TestResolveACLToken_Expired(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create a token that has expired
+	token := mock.ACLToken()
+	expired := time.Now().UTC().Add(-time.Minute)
+	token.ExpirationTime = &expired
+	assert.Nil(t, state.UpsertACLTokens(110, []*structs.ACLToken{token}))
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Equal(t, structs.ErrTokenExpired, err)
+	assert.Nil(t, aclObj)
+}
+
+func TestResolveACLToken_Roles(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	assert.Nil(t, err)
+
+	// Create a token that is only granted a policy through a role
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name, "missing"}
+	assert.Nil(t, state.UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	assert.Nil(t, state.UpsertACLRoles(110, []*structs.ACLRole{role}))
+	assert.Nil(t, state.UpsertACLTokens(120, []*structs.ACLToken{token}))
+
+	snap, err := state.Snapshot()
+	assert.Nil(t, err)
+
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	assert.Nil(t, err)
+	assert.NotNil(t, aclObj)
+	assert.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilitySubmitJob))
+}
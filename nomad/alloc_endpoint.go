@@ -10,6 +10,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -85,6 +86,7 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "get_alloc"}, time.Now())
 
 	// Check namespace read-job permissions
+	var redact []string
 	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
 		// If ResolveToken had an unexpected error return that
 		if err != structs.ErrTokenNotFound {
@@ -107,6 +109,8 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 		}
 	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
+	} else {
+		redact = a.srv.redactedEnvVars(aclObj)
 	}
 
 	// Setup the blocking query
@@ -121,7 +125,7 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 			}
 
 			// Setup the output
-			reply.Alloc = out
+			reply.Alloc = redactAllocEnv(out, redact)
 			if out != nil {
 				reply.Index = out.ModifyIndex
 			} else {
@@ -236,3 +240,77 @@ func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransiti
 	reply.Index = index
 	return nil
 }
+
+// RescheduleNow is used to reschedule a failed allocation without waiting for
+// its reschedule delay to elapse. The allocation must have reschedule
+// attempts left.
+func (a *Alloc) RescheduleNow(args *structs.AllocRescheduleNowRequest, reply *structs.AllocRescheduleNowResponse) error {
+	if done, err := a.srv.forward("Alloc.RescheduleNow", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "reschedule_now"}, time.Now())
+
+	if args.AllocID == "" {
+		return fmt.Errorf("missing allocation ID")
+	}
+
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return fmt.Errorf("allocation %q not found", args.AllocID)
+	}
+
+	// Check for submit-job permissions in the namespace of the allocation
+	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if alloc.NextAllocation != "" {
+		return fmt.Errorf("allocation %q has already been replaced by %q", alloc.ID, alloc.NextAllocation)
+	}
+	if alloc.ClientStatus != structs.AllocClientStatusFailed {
+		return fmt.Errorf("allocation %q is not failed, its status is %q", alloc.ID, alloc.ClientStatus)
+	}
+	if _, eligible := alloc.NextRescheduleTime(); !eligible {
+		return fmt.Errorf("allocation %q is not eligible to be rescheduled", alloc.ID)
+	}
+
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      alloc.Namespace,
+		Priority:       alloc.Job.Priority,
+		Type:           alloc.Job.Type,
+		TriggeredBy:    structs.EvalTriggerRetryFailedAlloc,
+		JobID:          alloc.JobID,
+		JobModifyIndex: alloc.Job.ModifyIndex,
+		Status:         structs.EvalStatusPending,
+	}
+
+	// Mark the allocation to be force rescheduled and create the evaluation
+	// that reschedules it
+	req := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: map[string]*structs.DesiredTransition{
+			alloc.ID: allowForceRescheduleTransition,
+		},
+		Evals:        []*structs.Evaluation{eval},
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, req)
+	if err != nil {
+		a.logger.Error("AllocUpdateDesiredTransitionRequest failed", "error", err)
+		return err
+	}
+
+	reply.EvalID = eval.ID
+	reply.EvalCreateIndex = index
+	reply.Index = index
+	return nil
+}
@@ -567,3 +567,69 @@ func TestAllocEndpoint_UpdateDesiredTransition(t *testing.T) {
 	require.True(*out1.DesiredTransition.Migrate)
 	require.True(*out2.DesiredTransition.Migrate)
 }
+
+func TestAllocEndpoint_RescheduleNow(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	failed := mock.Alloc()
+	failed.ClientStatus = structs.AllocClientStatusFailed
+	running := mock.Alloc()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertJobSummary(998, mock.JobSummary(failed.JobID)))
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(running.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{failed, running}))
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "test-read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	submitToken := mock.CreatePolicyAndToken(t, state, 1003, "test-submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	req := &structs.AllocRescheduleNowRequest{
+		AllocID: failed.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: readToken.SecretID,
+		},
+	}
+
+	// Try without submit-job permissions
+	var resp structs.AllocRescheduleNowResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.RescheduleNow", req, &resp)
+	require.NotNil(err)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// Try with submit-job permissions
+	req.AuthToken = submitToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.RescheduleNow", req, &resp))
+	require.NotZero(resp.Index)
+	require.NotEmpty(resp.EvalID)
+
+	out, err := state.AllocByID(nil, failed.ID)
+	require.NoError(err)
+	require.True(out.DesiredTransition.ShouldForceReschedule())
+
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	require.NoError(err)
+	require.NotNil(eval)
+	require.Equal(structs.EvalTriggerRetryFailedAlloc, eval.TriggeredBy)
+	require.Equal(failed.JobID, eval.JobID)
+
+	// Only failed allocations can be rescheduled
+	req.AllocID = running.ID
+	req.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.RescheduleNow", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "is not failed")
+
+	// Unknown allocations are rejected
+	req.AllocID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.RescheduleNow", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
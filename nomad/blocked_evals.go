@@ -593,6 +593,36 @@ func (b *BlockedEvals) UnblockFailed() {
 	}
 }
 
+// UnblockEval unblocks the blocked evaluation with the given ID regardless of
+// node capacity changes. It returns false if the evaluation isn't tracked.
+func (b *BlockedEvals) UnblockEval(evalID string) bool {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	// Do nothing if not enabled
+	if !b.enabled {
+		return false
+	}
+
+	wrapped, ok := b.captured[evalID]
+	if ok {
+		delete(b.captured, evalID)
+	} else if wrapped, ok = b.escaped[evalID]; ok {
+		delete(b.escaped, evalID)
+		b.stats.TotalEscaped -= 1
+	} else {
+		return false
+	}
+
+	delete(b.jobs, structs.NewNamespacedID(wrapped.eval.JobID, wrapped.eval.Namespace))
+	b.stats.TotalBlocked -= 1
+	if wrapped.eval.QuotaLimitReached != "" {
+		b.stats.TotalQuotaLimit -= 1
+	}
+	b.evalBroker.EnqueueAll(map[*structs.Evaluation]string{wrapped.eval: wrapped.token})
+	return true
+}
+
 // GetDuplicates returns all the duplicate evaluations and blocks until the
 // passed timeout.
 func (b *BlockedEvals) GetDuplicates(timeout time.Duration) []*structs.Evaluation {
@@ -655,6 +655,55 @@ func TestBlockedEvals_UnblockFailed(t *testing.T) {
 	}
 }
 
+func TestBlockedEvals_UnblockEval(t *testing.T) {
+	t.Parallel()
+	blocked, broker := testBlockedEvals(t)
+
+	// Create a captured and an escaped blocked eval
+	e := mock.Eval()
+	e.Status = structs.EvalStatusBlocked
+	e.ClassEligibility = map[string]bool{"v1:123": false}
+	e.QuotaLimitReached = "foo"
+	blocked.Block(e)
+
+	e2 := mock.Eval()
+	e2.Status = structs.EvalStatusBlocked
+	e2.EscapedComputedClass = true
+	blocked.Block(e2)
+
+	// Unblock the captured eval only
+	if !blocked.UnblockEval(e.ID) {
+		t.Fatalf("eval not unblocked")
+	}
+	bs := blocked.Stats()
+	if bs.TotalBlocked != 1 || bs.TotalEscaped != 1 || bs.TotalQuotaLimit != 0 {
+		t.Fatalf("bad: %#v", bs)
+	}
+
+	// Unblock the escaped eval, and an untracked one
+	if !blocked.UnblockEval(e2.ID) {
+		t.Fatalf("eval not unblocked")
+	}
+	if blocked.UnblockEval(e2.ID) {
+		t.Fatalf("untracked eval unblocked")
+	}
+	bs = blocked.Stats()
+	if bs.TotalBlocked != 0 || bs.TotalEscaped != 0 {
+		t.Fatalf("bad: %#v", bs)
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		// Verify the unblocks caused an enqueue
+		brokerStats := broker.Stats()
+		if brokerStats.TotalReady != 2 {
+			return false, fmt.Errorf("bad: %#v", brokerStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
 func TestBlockedEvals_Untrack(t *testing.T) {
 	t.Parallel()
 	blocked, _ := testBlockedEvals(t)
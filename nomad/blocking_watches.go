@@ -0,0 +1,122 @@
+package nomad
+
+import (
+	"context"
+	"encoding/binary"
+	"reflect"
+	"sort"
+	"sync"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+)
+
+// blockingWatches coalesces the identical watches of concurrent blocking
+// queries. Queries reading the same objects of the state store at the same
+// index watch the same set of channels, so a single watch is made for them
+// and its firing is fanned out to all the queries. This bounds the number of
+// goroutines watching the state store when many clients long-poll the same
+// endpoints.
+type blockingWatches struct {
+	l       sync.Mutex
+	watches map[string]*sharedWatch
+}
+
+// sharedWatch is a watch of a set of channels shared by the blocking queries
+// waiting on it.
+type sharedWatch struct {
+	// doneCh is closed when one of the watched channels fires
+	doneCh chan struct{}
+
+	// cancelFn stops the watch
+	cancelFn context.CancelFunc
+
+	// waiters is the number of queries waiting on the watch
+	waiters int
+}
+
+func newBlockingWatches() *blockingWatches {
+	return &blockingWatches{
+		watches: make(map[string]*sharedWatch),
+	}
+}
+
+// Watch blocks until one of the channels of the watch set fires, returning
+// nil, or until the context is done, returning its error. The watch is shared
+// with the concurrent calls watching the same set of channels and is stopped
+// once none of them waits on it anymore.
+func (b *blockingWatches) Watch(ctx context.Context, ws memdb.WatchSet) error {
+	key := watchSetKey(ws)
+
+	b.l.Lock()
+	w, ok := b.watches[key]
+	if ok {
+		metrics.IncrCounter([]string{"nomad", "rpc", "query", "coalesced"}, 1)
+	} else {
+		w = b.startWatch(key, ws)
+	}
+	w.waiters++
+	b.l.Unlock()
+
+	select {
+	case <-w.doneCh:
+		return nil
+	case <-ctx.Done():
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	w.waiters--
+	if w.waiters == 0 {
+		w.cancelFn()
+		if b.watches[key] == w {
+			delete(b.watches, key)
+		}
+	}
+	return ctx.Err()
+}
+
+// startWatch starts watching the watch set and registers the watch with the
+// given key. It must be called with the lock held.
+func (b *blockingWatches) startWatch(key string, ws memdb.WatchSet) *sharedWatch {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &sharedWatch{
+		doneCh:   make(chan struct{}),
+		cancelFn: cancel,
+	}
+	b.watches[key] = w
+
+	go func() {
+		err := ws.WatchCtx(ctx)
+
+		// Remove the watch before notifying the waiters so that queries
+		// blocking again start a new watch
+		b.l.Lock()
+		if b.watches[key] == w {
+			delete(b.watches, key)
+		}
+		b.l.Unlock()
+
+		if err == nil {
+			close(w.doneCh)
+		}
+		cancel()
+	}()
+	return w
+}
+
+// watchSetKey returns a key identifying the set of channels of the watch set.
+func watchSetKey(ws memdb.WatchSet) string {
+	ptrs := make([]uintptr, 0, len(ws))
+	for ch := range ws {
+		ptrs = append(ptrs, reflect.ValueOf(ch).Pointer())
+	}
+	sort.Slice(ptrs, func(i, j int) bool { return ptrs[i] < ptrs[j] })
+
+	key := make([]byte, 8*len(ptrs))
+	for i, ptr := range ptrs {
+		binary.LittleEndian.PutUint64(key[8*i:], uint64(ptr))
+	}
+	return string(key)
+}
@@ -0,0 +1,126 @@
+package nomad
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// numWatches returns the number of shared watches.
+func (b *blockingWatches) numWatches() int {
+	b.l.Lock()
+	defer b.l.Unlock()
+	return len(b.watches)
+}
+
+func TestBlockingWatches_FanOut(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := newBlockingWatches()
+
+	ch1, ch2 := make(chan struct{}), make(chan struct{})
+	newWatchSet := func() memdb.WatchSet {
+		ws := memdb.NewWatchSet()
+		ws.Add(ch1)
+		ws.Add(ch2)
+		return ws
+	}
+
+	// Concurrent identical watches share a single watch
+	errCh := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			errCh <- b.Watch(context.Background(), newWatchSet())
+		}()
+	}
+
+	// Another set of channels is watched separately
+	other := memdb.NewWatchSet()
+	other.Add(make(chan struct{}))
+	otherCtx, otherCancel := context.WithCancel(context.Background())
+	otherErrCh := make(chan error, 1)
+	go func() {
+		otherErrCh <- b.Watch(otherCtx, other)
+	}()
+
+	testutil.WaitForResult(func() (bool, error) {
+		return b.numWatches() == 2, nil
+	}, func(err error) {
+		t.Fatalf("expected 2 watches, got %d", b.numWatches())
+	})
+
+	// Firing a channel wakes all the queries watching it
+	close(ch2)
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-errCh:
+			require.NoError(err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("watch %d didn't fire", i)
+		}
+	}
+
+	// The other watch is stopped once its query is done
+	otherCancel()
+	select {
+	case err := <-otherErrCh:
+		require.Equal(context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch wasn't cancelled")
+	}
+	require.Zero(b.numWatches())
+}
+
+func TestBlockingWatches_Cancel(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := newBlockingWatches()
+
+	ch := make(chan struct{})
+	ws := memdb.NewWatchSet()
+	ws.Add(ch)
+
+	// A query giving up doesn't stop the watch of the other queries
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	errCh1 := make(chan error, 1)
+	go func() {
+		errCh1 <- b.Watch(ctx1, ws)
+	}()
+	errCh2 := make(chan error, 1)
+	go func() {
+		errCh2 <- b.Watch(context.Background(), ws)
+	}()
+
+	testutil.WaitForResult(func() (bool, error) {
+		b.l.Lock()
+		defer b.l.Unlock()
+		for _, w := range b.watches {
+			return w.waiters == 2, nil
+		}
+		return false, nil
+	}, func(err error) {
+		t.Fatalf("expected 2 queries waiting on the watch")
+	})
+
+	cancel1()
+	require.Equal(context.Canceled, <-errCh1)
+	require.Equal(1, b.numWatches())
+
+	close(ch)
+	select {
+	case err := <-errCh2:
+		require.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch didn't fire")
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		return b.numWatches() == 0, nil
+	}, func(err error) {
+		t.Fatalf("expected no watches, got %d", b.numWatches())
+	})
+}
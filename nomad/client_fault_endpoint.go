@@ -0,0 +1,89 @@
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+
+	"github.com/hashicorp/nomad/client/structs"
+)
+
+// Fault is used to forward RPC requests to the targed Nomad client's Fault
+// endpoint.
+type Fault struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Apply is used to set the faults injected into a node.
+func (f *Fault) Apply(args *structs.FaultApplyRequest, reply *structs.FaultResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := f.srv.forward("Fault.Apply", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fault", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return forwardToNode(f.srv, args.NodeID, "Fault.Apply", args, reply)
+}
+
+// Read is used to retrieve the faults injected into a node.
+func (f *Fault) Read(args *nstructs.NodeSpecificRequest, reply *structs.FaultResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := f.srv.forward("Fault.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fault", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return forwardToNode(f.srv, args.NodeID, "Fault.Read", args, reply)
+}
+
+// KillTask is used to kill a random running task of a job on a node.
+func (f *Fault) KillTask(args *structs.FaultKillTaskRequest, reply *structs.FaultKillTaskResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := f.srv.forward("Fault.KillTask", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fault", "kill_task"}, time.Now())
+
+	// Check node write and submit job permissions
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && (!aclObj.AllowNodeWrite() ||
+		!aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob)) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return forwardToNode(f.srv, args.NodeID, "Fault.KillTask", args, reply)
+}
@@ -0,0 +1,135 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFault_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+		c.EnableFaultInjection = true
+	})
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &cstructs.FaultApplyRequest{
+		FailAllocations: helper.IntToPtr(3),
+		QueryOptions:    structs.QueryOptions{Region: "global"},
+	}
+
+	var resp cstructs.FaultResponse
+	err := msgpackrpc.CallWithCodec(codec, "Fault.Apply", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Apply the faults setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.FaultResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Fault.Apply", req, &resp2))
+	require.Equal(3, resp2.FailAllocations)
+
+	// Read the faults back
+	readReq := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp3 cstructs.FaultResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Fault.Read", readReq, &resp3))
+	require.Equal(3, resp3.FailAllocations)
+	require.Zero(resp3.HeartbeatDelay)
+
+	// Killing a task of a job without allocations on the node fails
+	killReq := &cstructs.FaultKillTaskRequest{
+		NodeID:       c.NodeID(),
+		JobID:        "foo",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var killResp cstructs.FaultKillTaskResponse
+	err = msgpackrpc.CallWithCodec(codec, "Fault.KillTask", killReq, &killResp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "no running task")
+}
+
+func TestFault_Local_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a read-only token and a write token
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+	tokenWrite := mock.CreatePolicyAndToken(t, s.State(), 1009, "write", mock.NodePolicy(acl.PolicyWrite))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "read token",
+			Token:         tokenRead.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "write token",
+			Token:         tokenWrite.SecretID,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FaultApplyRequest{
+				NodeID:         uuid.Generate(),
+				HeartbeatDelay: helper.TimeToPtr(time.Second),
+				QueryOptions: structs.QueryOptions{
+					AuthToken: c.Token,
+					Region:    "global",
+				},
+			}
+
+			var resp cstructs.FaultResponse
+			err := msgpackrpc.CallWithCodec(codec, "Fault.Apply", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
@@ -28,6 +28,7 @@ type FileSystem struct {
 func (f *FileSystem) register() {
 	f.srv.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.srv.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.srv.streamingRpcs.Register("FileSystem.Archive", f.archive)
 }
 
 // handleStreamResultError is a helper for sending an error with a potential
@@ -85,7 +86,8 @@ func (f *FileSystem) forwardRegionStreamingRpc(conn io.ReadWriteCloser,
 	}
 	defer srvConn.Close()
 
-	// Send the request.
+	// Mark that we are forwarding the RPC and send the request.
+	qo.SetForwarded()
 	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
 	if err := outEncoder.Encode(args); err != nil {
 		f.handleStreamResultError(err, nil, encoder)
@@ -102,6 +104,14 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 	// in the forwarding chain.
 	args.QueryOptions.AllowStale = true
 
+	// Check the request may be relayed before forwarding it to a different
+	// region, as the servers of that region don't count it again
+	if args.RequestRegion() != f.srv.Region() {
+		if err := f.srv.checkClientRelay(args); err != nil {
+			return err
+		}
+	}
+
 	// Potentially forward to a different region.
 	if done, err := f.srv.forward("FileSystem.List", args, args, reply); done {
 		return err
@@ -115,6 +125,11 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 		return structs.ErrPermissionDenied
 	}
 
+	// Check the request may be relayed to the client
+	if err := f.srv.checkClientRelay(args); err != nil {
+		return err
+	}
+
 	// Verify the arguments.
 	if args.AllocID == "" {
 		return errors.New("missing allocation ID")
@@ -157,6 +172,14 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	// in the forwarding chain.
 	args.QueryOptions.AllowStale = true
 
+	// Check the request may be relayed before forwarding it to a different
+	// region, as the servers of that region don't count it again
+	if args.RequestRegion() != f.srv.Region() {
+		if err := f.srv.checkClientRelay(args); err != nil {
+			return err
+		}
+	}
+
 	// Potentially forward to a different region.
 	if done, err := f.srv.forward("FileSystem.Stat", args, args, reply); done {
 		return err
@@ -170,6 +193,11 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 		return structs.ErrPermissionDenied
 	}
 
+	// Check the request may be relayed to the client
+	if err := f.srv.checkClientRelay(args); err != nil {
+		return err
+	}
+
 	// Verify the arguments.
 	if args.AllocID == "" {
 		return errors.New("missing allocation ID")
@@ -205,6 +233,74 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 	return NodeRpc(state.Session, "FileSystem.Stat", args, reply)
 }
 
+// Write is used to write a file in the allocation's directory.
+func (f *FileSystem) Write(args *cstructs.FsWriteRequest, reply *structs.GenericResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hope
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Check the request may be relayed before forwarding it to a different
+	// region, as the servers of that region don't count it again
+	if args.RequestRegion() != f.srv.Region() {
+		if err := f.srv.checkClientRelay(args); err != nil {
+			return err
+		}
+	}
+
+	// Potentially forward to a different region.
+	if done, err := f.srv.forward("FileSystem.Write", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "write"}, time.Now())
+
+	// Check filesystem write permissions
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityWriteFS) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Check the request may be relayed to the client
+	if err := f.srv.checkClientRelay(args); err != nil {
+		return err
+	}
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		return errors.New("missing allocation ID")
+	}
+
+	// Lookup the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+
+	// Make sure Node is valid and new enough to support RPC
+	_, err = getNodeForRpc(snap, alloc.NodeID)
+	if err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := f.srv.getNodeConn(alloc.NodeID)
+	if !ok {
+		return findNodeConnAndForward(f.srv, alloc.NodeID, "FileSystem.Write", args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, "FileSystem.Write", args, reply)
+}
+
 // stream is is used to stream the contents of file in an allocation's
 // directory.
 func (f *FileSystem) stream(conn io.ReadWriteCloser) {
@@ -223,6 +319,12 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 
 	// Check if we need to forward to a different region
 	if r := args.RequestRegion(); r != f.srv.Region() {
+		// Check the request may be relayed before forwarding it, as the
+		// servers of that region don't count it again
+		if err := f.srv.checkClientRelay(&args); err != nil {
+			f.handleStreamResultError(err, clientRelayErrCode(err), encoder)
+			return
+		}
 		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Stream",
 			args.AllocID, &args.QueryOptions)
 		return
@@ -237,6 +339,12 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 		return
 	}
 
+	// Check the request may be relayed to the client
+	if err := f.srv.checkClientRelay(&args); err != nil {
+		f.handleStreamResultError(err, clientRelayErrCode(err), encoder)
+		return
+	}
+
 	// Verify the arguments.
 	if args.AllocID == "" {
 		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
@@ -303,6 +411,9 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 		}
 
 		clientConn = conn
+
+		// Mark that we are forwarding the RPC
+		args.SetForwarded()
 	} else {
 		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Stream")
 		if err != nil {
@@ -324,6 +435,139 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	return
 }
 
+// archive is used to stream a tar archive of a directory of an allocation.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "archive"}, time.Now())
+
+	// Decode the arguments
+	var args cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != f.srv.Region() {
+		// Check the request may be relayed before forwarding it, as the
+		// servers of that region don't count it again
+		if err := f.srv.checkClientRelay(&args); err != nil {
+			f.handleStreamResultError(err, clientRelayErrCode(err), encoder)
+			return
+		}
+		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Archive",
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+
+	// Check node read permissions
+	if aclObj, err := f.srv.ResolveToken(args.AuthToken); err != nil {
+		f.handleStreamResultError(err, nil, encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityReadFS) {
+		f.handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	// Check the request may be relayed to the client
+	if err := f.srv.checkClientRelay(&args); err != nil {
+		f.handleStreamResultError(err, clientRelayErrCode(err), encoder)
+		return
+	}
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Retrieve the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		f.handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		f.handleStreamResultError(err, nil, encoder)
+		return
+	}
+	if alloc == nil {
+		f.handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+	nodeID := alloc.NodeID
+
+	// Make sure Node is valid and new enough to support RPC
+	node, err := snap.NodeByID(nil, nodeID)
+	if err != nil {
+		f.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	if node == nil {
+		err := fmt.Errorf("Unknown node %q", nodeID)
+		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	if err := nodeSupportsRpc(node); err != nil {
+		f.handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Get the connection to the client either by forwarding to another server
+	// or creating a direct stream
+	var clientConn net.Conn
+	state, ok := f.srv.getNodeConn(nodeID)
+	if !ok {
+		// Determine the Server that has a connection to the node.
+		srv, err := f.srv.serverWithNodeConn(nodeID, f.srv.Region())
+		if err != nil {
+			var code *int64
+			if structs.IsErrNoNodeConn(err) {
+				code = helper.Int64ToPtr(404)
+			}
+			f.handleStreamResultError(err, code, encoder)
+			return
+		}
+
+		// Get a connection to the server
+		conn, err := f.srv.streamingRpc(srv, "FileSystem.Archive")
+		if err != nil {
+			f.handleStreamResultError(err, nil, encoder)
+			return
+		}
+
+		clientConn = conn
+
+		// Mark that we are forwarding the RPC
+		args.SetForwarded()
+	} else {
+		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Archive")
+		if err != nil {
+			f.handleStreamResultError(err, nil, encoder)
+			return
+		}
+		clientConn = stream
+	}
+	defer clientConn.Close()
+
+	// Send the request.
+	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		f.handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	structs.Bridge(conn, clientConn)
+	return
+}
+
 // logs is used to access an task's logs for a given allocation
 func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	defer conn.Close()
@@ -341,6 +585,12 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 
 	// Check if we need to forward to a different region
 	if r := args.RequestRegion(); r != f.srv.Region() {
+		// Check the request may be relayed before forwarding it, as the
+		// servers of that region don't count it again
+		if err := f.srv.checkClientRelay(&args); err != nil {
+			f.handleStreamResultError(err, clientRelayErrCode(err), encoder)
+			return
+		}
 		f.forwardRegionStreamingRpc(conn, encoder, &args, "FileSystem.Logs",
 			args.AllocID, &args.QueryOptions)
 		return
@@ -359,6 +609,12 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		}
 	}
 
+	// Check the request may be relayed to the client
+	if err := f.srv.checkClientRelay(&args); err != nil {
+		f.handleStreamResultError(err, clientRelayErrCode(err), encoder)
+		return
+	}
+
 	// Verify the arguments.
 	if args.AllocID == "" {
 		f.handleStreamResultError(errors.New("missing AllocID"), helper.Int64ToPtr(400), encoder)
@@ -425,6 +681,9 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		}
 
 		clientConn = conn
+
+		// Mark that we are forwarding the RPC
+		args.SetForwarded()
 	} else {
 		stream, err := NodeStreamingRpc(state.Session, "FileSystem.Logs")
 		if err != nil {
@@ -0,0 +1,122 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &cstructs.NodeMetaApplyRequest{
+		Meta:         map[string]*string{"foo": helper.StringToPtr("bar")},
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	var resp cstructs.NodeMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Apply the metadata setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.NodeMetaResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp2))
+	require.Equal("bar", resp2.Meta["foo"])
+
+	// Read the metadata back
+	readReq := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp3 cstructs.NodeMetaResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodeMeta.Read", readReq, &resp3))
+	require.Equal("bar", resp3.Meta["foo"])
+	require.Equal(req.Meta, resp3.Dynamic)
+}
+
+func TestNodeMeta_Local_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create a read-only token and a write token
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+	tokenWrite := mock.CreatePolicyAndToken(t, s.State(), 1009, "write", mock.NodePolicy(acl.PolicyWrite))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "read token",
+			Token:         tokenRead.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "write token",
+			Token:         tokenWrite.SecretID,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.NodeMetaApplyRequest{
+				NodeID: uuid.Generate(),
+				Meta:   map[string]*string{"foo": helper.StringToPtr("bar")},
+				QueryOptions: structs.QueryOptions{
+					AuthToken: c.Token,
+					Region:    "global",
+				},
+			}
+
+			var resp cstructs.NodeMetaResponse
+			err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package nomad
+
+import (
+	"math"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"golang.org/x/time/rate"
+)
+
+// newClientRelayLimiter returns the limiter of the requests the server relays
+// to the clients, or nil if their rate isn't limited.
+func newClientRelayLimiter(config *Config) *rate.Limiter {
+	relay := config.ClientRelay
+	if relay == nil || relay.RateLimit <= 0 {
+		return nil
+	}
+
+	burst := relay.Burst
+	if burst == 0 {
+		burst = int(math.Ceil(relay.RateLimit))
+	}
+	return rate.NewLimiter(rate.Limit(relay.RateLimit), burst)
+}
+
+// checkClientRelay returns an error if the server must not relay a
+// filesystem, logs or exec request to a client, either because relaying is
+// disabled or because the rate limit of relayed requests is exceeded. Only the
+// server that first receives a request counts it against the rate limit, so
+// that a request forwarded between servers isn't counted on every hop.
+func (s *Server) checkClientRelay(info structs.RPCInfo) error {
+	if !s.config.ClientRelay.IsEnabled() {
+		return structs.ErrClientRelayDisabled
+	}
+	if info.IsForwarded() {
+		return nil
+	}
+	if s.clientRelayLimiter != nil && !s.clientRelayLimiter.Allow() {
+		return structs.ErrClientRelayRateLimited
+	}
+	return nil
+}
+
+// clientRelayErrCode returns the HTTP status code of an error returned by
+// checkClientRelay, to be sent along with the error of a streaming RPC.
+func clientRelayErrCode(err error) *int64 {
+	if structs.IsErrClientRelayRateLimited(err) {
+		return helper.Int64ToPtr(429)
+	}
+	return helper.Int64ToPtr(403)
+}
@@ -0,0 +1,197 @@
+package nomad
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/client"
+	cconfig "github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+func TestClientRelay_Disabled(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s := TestServer(t, func(c *Config) {
+		c.ClientRelay = &config.ClientRelayConfig{
+			Enabled: helper.BoolToPtr(false),
+		}
+	})
+	defer s.Shutdown()
+	rpc := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Unary requests are rejected
+	req := &cstructs.FsListRequest{
+		AllocID:      uuid.Generate(),
+		Path:         "/",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp cstructs.FsListResponse
+	err := msgpackrpc.CallWithCodec(rpc, "FileSystem.List", req, &resp)
+	require.True(structs.IsErrClientRelayDisabled(err), "unexpected error: %v", err)
+
+	actionReq := &cstructs.AllocActionRequest{
+		AllocID:      uuid.Generate(),
+		Task:         "web",
+		Action:       "date",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var actionResp cstructs.AllocActionResponse
+	err = msgpackrpc.CallWithCodec(rpc, "ClientAllocations.RunAction", actionReq, &actionResp)
+	require.True(structs.IsErrClientRelayDisabled(err), "unexpected error: %v", err)
+
+	// Streaming requests are rejected with a 403
+	handler, err := s.StreamingRpcHandler("FileSystem.Logs")
+	require.NoError(err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	logsReq := &cstructs.FsLogsRequest{
+		AllocID:      uuid.Generate(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	require.NoError(codec.NewEncoder(p1, structs.MsgpackHandle).Encode(logsReq))
+
+	var msg cstructs.StreamErrWrapper
+	require.NoError(codec.NewDecoder(p1, structs.MsgpackHandle).Decode(&msg))
+	require.NotNil(msg.Error)
+	require.True(structs.IsErrClientRelayDisabled(msg.Error))
+	require.EqualValues(403, *msg.Error.Code)
+}
+
+func TestClientRelay_RateLimit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s := TestServer(t, func(c *Config) {
+		c.ClientRelay = &config.ClientRelayConfig{
+			RateLimit: 0.001,
+			Burst:     1,
+		}
+	})
+	defer s.Shutdown()
+	rpc := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// The first request is relayed and fails to find the allocation
+	req := &cstructs.FsStatRequest{
+		AllocID:      uuid.Generate(),
+		Path:         "/",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp cstructs.FsStatResponse
+	err := msgpackrpc.CallWithCodec(rpc, "FileSystem.Stat", req, &resp)
+	require.True(structs.IsErrUnknownAllocation(err), "unexpected error: %v", err)
+
+	// The next one exceeds the rate limit
+	err = msgpackrpc.CallWithCodec(rpc, "FileSystem.Stat", req, &resp)
+	require.True(structs.IsErrClientRelayRateLimited(err), "unexpected error: %v", err)
+}
+
+func TestClientRelay_RateLimit_Forwarded(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	relay := func(c *Config) {
+		c.ClientRelay = &config.ClientRelayConfig{
+			RateLimit: 0.001,
+			Burst:     1,
+		}
+	}
+	s1 := TestServer(t, relay)
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+		relay(c)
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	rpc := rpcClient(t, s1)
+
+	// Connect the client to the second server only
+	c, cleanup := client.TestClient(t, func(c *cconfig.Config) {
+		c.Servers = []string{s2.config.RPCAddr.String()}
+	})
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s2.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Run an allocation on the client
+	a := mock.Alloc()
+	a.Job.Type = structs.JobTypeBatch
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &structs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "2s",
+		},
+		LogConfig: structs.DefaultLogConfig(),
+		Resources: &structs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+	for _, s := range []*Server{s1, s2} {
+		require.Nil(s.State().UpsertJob(999, a.Job))
+		require.Nil(s.State().UpsertAllocs(1003, []*structs.Allocation{a}))
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := s2.State().AllocByID(nil, a.ID)
+		if err != nil {
+			return false, err
+		}
+		if alloc == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusComplete {
+			return false, fmt.Errorf("alloc client status: %v", alloc.ClientStatus)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("Alloc on node %q not finished: %v", c.NodeID(), err)
+	})
+
+	// Force remove the connection locally in case it exists, so that the
+	// request is relayed through both servers
+	s1.nodeConnsLock.Lock()
+	delete(s1.nodeConns, c.NodeID())
+	s1.nodeConnsLock.Unlock()
+
+	req := &cstructs.FsListRequest{
+		AllocID:      a.ID,
+		Path:         "/",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp cstructs.FsListResponse
+	require.NoError(msgpackrpc.CallWithCodec(rpc, "FileSystem.List", req, &resp))
+	require.NotEmpty(resp.Files)
+
+	// Only the server that received it counted it
+	require.False(s1.clientRelayLimiter.Allow())
+	require.True(s2.clientRelayLimiter.Allow())
+}
@@ -281,6 +281,36 @@ func NodeStreamingRpc(session *yamux.Session, method string) (net.Conn, error) {
 	return stream, nil
 }
 
+// forwardToNode makes the RPC to the node, going through the server connected
+// to it if it isn't connected to this server. This does not work for streaming
+// RPCs.
+func forwardToNode(srv *Server, nodeID, method string, args, reply interface{}) error {
+	// Verify the arguments.
+	if nodeID == "" {
+		return errors.New("missing NodeID")
+	}
+
+	// Check if the node even exists and is compatible with NodeRpc
+	snap, err := srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Make sure Node is new enough to support RPC
+	if _, err := getNodeForRpc(snap, nodeID); err != nil {
+		return err
+	}
+
+	// Get the connection to the client
+	state, ok := srv.getNodeConn(nodeID)
+	if !ok {
+		return findNodeConnAndForward(srv, nodeID, method, args, reply)
+	}
+
+	// Make the RPC
+	return NodeRpc(state.Session, method, args, reply)
+}
+
 // findNodeConnAndForward is a helper for finding the server with a connection
 // to the given node and forwarding the RPC to the correct server. This does not
 // work for streaming RPCs.
@@ -295,5 +325,10 @@ func findNodeConnAndForward(srv *Server, nodeID, method string, args, reply inte
 		return structs.ErrNoNodeConn
 	}
 
+	// Mark that we are forwarding the RPC
+	if info, ok := args.(structs.RPCInfo); ok {
+		info.SetForwarded()
+	}
+
 	return srv.forwardServer(srvWithConn, method, args, reply)
 }
@@ -74,3 +74,26 @@ func (s *ClientStats) Stats(args *nstructs.NodeSpecificRequest, reply *structs.C
 	// Make the RPC
 	return NodeRpc(state.Session, "ClientStats.Stats", args, reply)
 }
+
+// Hardware is used to retrieve the hardware inventory of a node.
+func (s *ClientStats) Hardware(args *nstructs.NodeSpecificRequest, reply *structs.ClientHardwareResponse) error {
+	// We only allow stale reads since the only potentially stale information is
+	// the Node registration and the cost is fairly high for adding another hop
+	// in the forwarding chain.
+	args.QueryOptions.AllowStale = true
+
+	// Potentially forward to a different region.
+	if done, err := s.srv.forward("ClientStats.Hardware", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client_stats", "hardware"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := s.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return forwardToNode(s.srv, args.NodeID, "ClientStats.Hardware", args, reply)
+}
@@ -57,6 +57,48 @@ func TestClientStats_Stats_Local(t *testing.T) {
 	require.NotNil(resp2.HostStats)
 }
 
+func TestClientStats_Hardware_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanup := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &structs.NodeSpecificRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Fetch the response
+	var resp cstructs.ClientHardwareResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientStats.Hardware", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Fetch the response setting the node id
+	req.NodeID = c.NodeID()
+	var resp2 cstructs.ClientHardwareResponse
+	err = msgpackrpc.CallWithCodec(codec, "ClientStats.Hardware", req, &resp2)
+	require.Nil(err)
+	require.NotNil(resp2.Hardware)
+	require.NotEmpty(resp2.Hardware.CPUs)
+}
+
 func TestClientStats_Stats_Local_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
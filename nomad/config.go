@@ -148,6 +148,18 @@ type Config struct {
 	// that the workers dequeue for processing.
 	EnabledSchedulers []string
 
+	// SchedulerWorkers configures the workers pinned to each scheduler type
+	// and the autoscaling of the shared workers.
+	SchedulerWorkers *config.SchedulerWorkersConfig
+
+	// ClientRelay configures the relaying of filesystem, logs and exec
+	// requests to the clients on behalf of API consumers.
+	ClientRelay *config.ClientRelayConfig
+
+	// WriteRateLimit limits the rate of the write RPCs, such as job
+	// registrations, of each ACL token and of each namespace.
+	WriteRateLimit *config.WriteRateLimitConfig
+
 	// ReconcileInterval controls how often we reconcile the strongly
 	// consistent store with the Serf info. This is used to handle nodes
 	// that are force removed, as well as intermittent unavailability during
@@ -169,6 +181,13 @@ type Config struct {
 	// the user time to inspect the job.
 	JobGCThreshold time.Duration
 
+	// BatchJobGCThreshold, ServiceJobGCThreshold and SystemJobGCThreshold
+	// override JobGCThreshold for jobs of the matching type. A zero value
+	// uses JobGCThreshold.
+	BatchJobGCThreshold   time.Duration
+	ServiceJobGCThreshold time.Duration
+	SystemJobGCThreshold  time.Duration
+
 	// NodeGCInterval is how often we dispatch a job to GC failed nodes.
 	NodeGCInterval time.Duration
 
@@ -184,6 +203,10 @@ type Config struct {
 	// for GC. This gives users some time to view terminal deployments.
 	DeploymentGCThreshold time.Duration
 
+	// ACLTokenExpirationGCInterval is how often we dispatch a job to GC
+	// expired ACL tokens.
+	ACLTokenExpirationGCInterval time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -196,6 +219,10 @@ type Config struct {
 	// complete eventually fails out of the system.
 	EvalDeliveryLimit int
 
+	// EventBufferSize is the number of batches of state store events kept
+	// for event stream subscribers resuming from an earlier index.
+	EventBufferSize int
+
 	// EvalNackInitialReenqueueDelay is the delay applied before reenqueuing a
 	// Nacked evaluation for the first time. This value should be small as the
 	// initial Nack can be due to a down machine and the eval should be retried
@@ -239,12 +266,33 @@ type Config struct {
 	// of all the heartbeats.
 	FailoverHeartbeatTTL time.Duration
 
+	// NodeEligibilityWindowInterval is how often the leader marks the nodes
+	// within a maintenance window as ineligible, and the nodes whose
+	// ineligibility expired as eligible again.
+	NodeEligibilityWindowInterval time.Duration
+
 	// ConsulConfig is this Agent's Consul configuration
 	ConsulConfig *config.ConsulConfig
 
 	// VaultConfig is this Agent's Vault configuration
 	VaultConfig *config.VaultConfig
 
+	// ConsulClusters are the named Consul clusters jobs may select instead
+	// of the default cluster configured by ConsulConfig.
+	ConsulClusters []*config.ConsulConfig
+
+	// VaultClusters are the named Vault clusters jobs may derive their tokens
+	// from instead of the default cluster configured by VaultConfig.
+	VaultClusters []*config.VaultConfig
+
+	// SnapshotAgentConfig configures the scheduled snapshot agent that runs
+	// on the leader.
+	SnapshotAgentConfig *config.SnapshotAgentConfig
+
+	// ExternalDNSConfig configures the controller that publishes Nomad
+	// services to an external DNS provider from the leader.
+	ExternalDNSConfig *config.ExternalDNSConfig
+
 	// RPCHoldTimeout is how long an RPC can be "held" before it is errored.
 	// This is used to paper over a loss of leadership by instead holding RPCs,
 	// so that the caller experiences a slow response rather than an error.
@@ -266,12 +314,53 @@ type Config struct {
 	// the Authoritative Region.
 	ReplicationToken string
 
+	// ACLBootstrapTokenFile is the path of a file holding the Secret ID of a
+	// pre-generated management token, used by the leader of the authoritative
+	// region to bootstrap the ACL system if it isn't bootstrapped yet.
+	ACLBootstrapTokenFile string
+
+	// ACLTokenMaxExpirationTTL is the longest expiration TTL a token can be
+	// created with. Zero allows any TTL.
+	ACLTokenMaxExpirationTTL time.Duration
+
 	// SentinelGCInterval is the interval that we GC unused policies.
 	SentinelGCInterval time.Duration
 
 	// SentinelConfig is this Agent's Sentinel configuration
 	SentinelConfig *config.SentinelConfig
 
+	// JobAdmissionWebhooks are called in order when a job is registered and
+	// may modify or reject the job.
+	JobAdmissionWebhooks []*config.JobAdmissionWebhookConfig
+
+	// JobAdmissionRules restrict the datacenters, node classes and drivers
+	// the jobs of a namespace may use. There is at most one rule per
+	// namespace.
+	JobAdmissionRules []*config.JobAdmissionRuleConfig
+
+	// JobSigning configures the verification of the detached signatures of
+	// the jobs registered in each namespace.
+	JobSigning []*config.JobSigningConfig
+
+	// NamespaceDefaults set the Vault and Consul clusters used by the jobs of
+	// each namespace that don't select a cluster themselves. There are at
+	// most one defaults per namespace.
+	NamespaceDefaults []*config.NamespaceDefaultsConfig
+
+	// DispatchPayloadSizeLimit is the maximum size of the uncompressed
+	// payload of dispatch requests.
+	DispatchPayloadSizeLimit int
+
+	// DeploymentWebhookSigningKey is the secret the payloads sent to the
+	// progress webhooks of deployments are signed with. If empty, the
+	// payloads are not signed.
+	DeploymentWebhookSigningKey string
+
+	// RedactEnvVars is the list of glob patterns of the names of the task
+	// environment variables whose values are redacted from the jobs,
+	// allocations and events returned to non-management tokens.
+	RedactEnvVars []string
+
 	// StatsCollectionInterval is the interval at which the Nomad server
 	// publishes metrics which are periodic in nature like updating gauges
 	StatsCollectionInterval time.Duration
@@ -321,6 +410,25 @@ func (c *Config) CheckVersion() error {
 	return nil
 }
 
+// jobGCThreshold returns how old a job of the given type must be before it is
+// eligible for GC.
+func (c *Config) jobGCThreshold(jobType string) time.Duration {
+	var threshold time.Duration
+	switch jobType {
+	case structs.JobTypeBatch:
+		threshold = c.BatchJobGCThreshold
+	case structs.JobTypeService:
+		threshold = c.ServiceJobGCThreshold
+	case structs.JobTypeSystem:
+		threshold = c.SystemJobGCThreshold
+	}
+
+	if threshold == 0 {
+		return c.JobGCThreshold
+	}
+	return threshold
+}
+
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	hostname, err := os.Hostname()
@@ -350,8 +458,10 @@ func DefaultConfig() *Config {
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
 		DeploymentGCThreshold:            1 * time.Hour,
+		ACLTokenExpirationGCInterval:     5 * time.Minute,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
+		EventBufferSize:                  100,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
 		EvalNackSubsequentReenqueueDelay: 20 * time.Second,
 		EvalFailedFollowupBaselineDelay:  1 * time.Minute,
@@ -360,13 +470,19 @@ func DefaultConfig() *Config {
 		MaxHeartbeatsPerSecond:           50.0,
 		HeartbeatGrace:                   10 * time.Second,
 		FailoverHeartbeatTTL:             300 * time.Second,
+		NodeEligibilityWindowInterval:    30 * time.Second,
 		ConsulConfig:                     config.DefaultConsulConfig(),
 		VaultConfig:                      config.DefaultVaultConfig(),
+		SnapshotAgentConfig:              config.DefaultSnapshotAgentConfig(),
+		ExternalDNSConfig:                config.DefaultExternalDNSConfig(),
+		SchedulerWorkers:                 config.DefaultSchedulerWorkersConfig(),
+		ClientRelay:                      config.DefaultClientRelayConfig(),
 		RPCHoldTimeout:                   5 * time.Second,
 		StatsCollectionInterval:          1 * time.Minute,
 		TLSConfig:                        &config.TLSConfig{},
 		ReplicationBackoff:               30 * time.Second,
 		SentinelGCInterval:               30 * time.Second,
+		DispatchPayloadSizeLimit:         DispatchPayloadSizeLimit,
 		AutopilotConfig: &structs.AutopilotConfig{
 			CleanupDeadServers:      true,
 			LastContactThreshold:    200 * time.Millisecond,
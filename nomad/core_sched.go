@@ -49,13 +49,54 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.jobGC(eval)
 	case structs.CoreJobDeploymentGC:
 		return c.deploymentGC(eval)
+	case structs.CoreJobExpiredACLTokenGC:
+		return c.expiredACLTokenGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
+		if _, _, ok := structs.ParseCoreJobForceGCTarget(eval.JobID); ok {
+			return c.forceGC(eval)
+		}
 		return fmt.Errorf("core scheduler cannot handle job '%s'", eval.JobID)
 	}
 }
 
+// gcTarget restricts a forced garbage collection to the objects of a single
+// namespace and optionally a single job. The zero value matches everything.
+type gcTarget struct {
+	namespace string
+	jobID     string
+}
+
+// matches returns whether an object belonging to the given namespace and job
+// is covered by the target.
+func (t gcTarget) matches(namespace, jobID string) bool {
+	if t.namespace != "" && t.namespace != namespace {
+		return false
+	}
+	if t.jobID != "" && t.jobID != jobID {
+		return false
+	}
+	return true
+}
+
+// all returns whether the target covers every object.
+func (t gcTarget) all() bool {
+	return t.namespace == "" && t.jobID == ""
+}
+
+// forceGCTarget returns whether the evaluation forces garbage collection and,
+// if so, which objects the collection is restricted to.
+func forceGCTarget(eval *structs.Evaluation) (bool, gcTarget) {
+	if eval.JobID == structs.CoreJobForceGC {
+		return true, gcTarget{}
+	}
+	if namespace, jobID, ok := structs.ParseCoreJobForceGCTarget(eval.JobID); ok {
+		return true, gcTarget{namespace: namespace, jobID: jobID}
+	}
+	return false, gcTarget{}
+}
+
 // forceGC is used to garbage collect all eligible objects.
 func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.jobGC(eval); err != nil {
@@ -68,6 +109,15 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 		return err
 	}
 
+	// Nodes and tokens don't belong to a namespace or job, so only collect
+	// them when the GC isn't targeted.
+	if _, target := forceGCTarget(eval); !target.all() {
+		return nil
+	}
+	if err := c.expiredACLTokenGC(eval); err != nil {
+		return err
+	}
+
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
 	return c.nodeGC(eval)
@@ -82,19 +132,32 @@ func (c *CoreScheduler) jobGC(eval *structs.Evaluation) error {
 		return err
 	}
 
-	var oldThreshold uint64
-	if eval.JobID == structs.CoreJobForceGC {
+	// The threshold depends on the job type, so compute the cutoff index
+	// lazily for each type encountered.
+	var thresholdIndex func(jobType string) uint64
+	forced, target := forceGCTarget(eval)
+	if forced {
 		// The GC was forced, so set the threshold to its maximum so everything
 		// will GC.
-		oldThreshold = math.MaxUint64
-		c.logger.Debug("forced job GC")
+		thresholdIndex = func(string) uint64 { return math.MaxUint64 }
+		c.logger.Debug("forced job GC", "namespace", target.namespace, "job", target.jobID)
 	} else {
 		// Get the time table to calculate GC cutoffs.
 		tt := c.srv.fsm.TimeTable()
-		cutoff := time.Now().UTC().Add(-1 * c.srv.config.JobGCThreshold)
-		oldThreshold = tt.NearestIndex(cutoff)
-		c.logger.Debug("job GC scanning before cutoff index",
-			"index", oldThreshold, "job_gc_threshold", c.srv.config.JobGCThreshold)
+		now := time.Now().UTC()
+		cutoffs := make(map[string]uint64)
+		thresholdIndex = func(jobType string) uint64 {
+			if index, ok := cutoffs[jobType]; ok {
+				return index
+			}
+
+			threshold := c.srv.config.jobGCThreshold(jobType)
+			index := tt.NearestIndex(now.Add(-1 * threshold))
+			cutoffs[jobType] = index
+			c.logger.Debug("job GC scanning before cutoff index",
+				"job_type", jobType, "index", index, "job_gc_threshold", threshold)
+			return index
+		}
 	}
 
 	// Collect the allocations, evaluations and jobs to GC
@@ -105,7 +168,13 @@ OUTER:
 	for i := iter.Next(); i != nil; i = iter.Next() {
 		job := i.(*structs.Job)
 
+		// Ignore jobs outside of a targeted GC.
+		if !target.matches(job.Namespace, job.ID) {
+			continue
+		}
+
 		// Ignore new jobs.
+		oldThreshold := thresholdIndex(job.Type)
 		if job.CreateIndex > oldThreshold {
 			continue
 		}
@@ -220,11 +289,12 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	}
 
 	var oldThreshold uint64
-	if eval.JobID == structs.CoreJobForceGC {
+	forced, target := forceGCTarget(eval)
+	if forced {
 		// The GC was forced, so set the threshold to its maximum so everything
 		// will GC.
 		oldThreshold = math.MaxUint64
-		c.logger.Debug("forced eval GC")
+		c.logger.Debug("forced eval GC", "namespace", target.namespace, "job", target.jobID)
 	} else {
 		// Compute the old threshold limit for GC using the FSM
 		// time table.  This is a rough mapping of a time to the
@@ -241,6 +311,11 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
 		eval := raw.(*structs.Evaluation)
 
+		// Ignore evaluations outside of a targeted GC.
+		if !target.matches(eval.Namespace, eval.JobID) {
+			continue
+		}
+
 		// The Evaluation GC should not handle batch jobs since those need to be
 		// garbage collected in one shot
 		gc, allocs, err := c.gcEval(eval, oldThreshold, false)
@@ -511,11 +586,12 @@ func (c *CoreScheduler) deploymentGC(eval *structs.Evaluation) error {
 	}
 
 	var oldThreshold uint64
-	if eval.JobID == structs.CoreJobForceGC {
+	forced, target := forceGCTarget(eval)
+	if forced {
 		// The GC was forced, so set the threshold to its maximum so everything
 		// will GC.
 		oldThreshold = math.MaxUint64
-		c.logger.Debug("forced deployment GC")
+		c.logger.Debug("forced deployment GC", "namespace", target.namespace, "job", target.jobID)
 	} else {
 		// Compute the old threshold limit for GC using the FSM
 		// time table.  This is a rough mapping of a time to the
@@ -538,6 +614,11 @@ OUTER:
 		}
 		deploy := raw.(*structs.Deployment)
 
+		// Ignore deployments outside of a targeted GC.
+		if !target.matches(deploy.Namespace, deploy.JobID) {
+			continue
+		}
+
 		// Ignore non-terminal and new deployments
 		if deploy.Active() || deploy.ModifyIndex > oldThreshold {
 			continue
@@ -614,6 +695,119 @@ func (c *CoreScheduler) partitionDeploymentReap(deployments []string) []*structs
 	return requests
 }
 
+// expiredACLTokenGC is used to garbage collect expired ACL tokens and
+// one-time tokens. Global tokens are only collected by the authoritative
+// region, the other regions remove them when replicating.
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation) error {
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+
+	if err := c.expiredOneTimeTokenGC(eval); err != nil {
+		return err
+	}
+
+	iter, err := c.snap.ACLTokens(nil)
+	if err != nil {
+		return err
+	}
+
+	authoritative := c.srv.config.Region == c.srv.config.AuthoritativeRegion
+	now := time.Now().UTC()
+
+	// Collect the expired tokens, split by locality since a single delete
+	// request can't mix local and global tokens
+	var local, global []string
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		token := raw.(*structs.ACLToken)
+		if !token.IsExpired(now) {
+			continue
+		}
+		if !token.Global {
+			local = append(local, token.AccessorID)
+		} else if authoritative {
+			global = append(global, token.AccessorID)
+		}
+	}
+
+	// Fast-path the nothing case
+	if len(local) == 0 && len(global) == 0 {
+		return nil
+	}
+	c.logger.Debug("expired ACL token GC found eligible tokens",
+		"local", len(local), "global", len(global))
+
+	if err := c.aclTokenReap(local, eval.LeaderACL); err != nil {
+		return err
+	}
+	return c.aclTokenReap(global, eval.LeaderACL)
+}
+
+// expiredOneTimeTokenGC is used to garbage collect the one-time tokens that
+// expired without being exchanged.
+func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
+	iter, err := c.snap.OneTimeTokens(nil)
+	if err != nil {
+		return err
+	}
+
+	// Only issue a request if a one-time token expired
+	now := time.Now().UTC()
+	found := false
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.OneTimeToken).IsExpired(now) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	req := &structs.OneTimeTokenExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.config.Region,
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	var resp structs.GenericResponse
+	if err := c.srv.RPC("ACL.ExpireOneTimeTokens", req, &resp); err != nil {
+		c.logger.Error("one-time token expiration failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// aclTokenReap contacts the leader and issues a delete of the passed tokens.
+func (c *CoreScheduler) aclTokenReap(accessors []string, leaderACL string) error {
+	for len(accessors) != 0 {
+		batch := accessors
+		if len(batch) > maxIdsPerReap {
+			batch = batch[:maxIdsPerReap]
+		}
+		accessors = accessors[len(batch):]
+
+		req := &structs.ACLTokenDeleteRequest{
+			AccessorIDs: batch,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: leaderACL,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := c.srv.RPC("ACL.DeleteTokens", req, &resp); err != nil {
+			c.logger.Error("ACL token reap failed", "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
 // allocGCEligible returns if the allocation is eligible to be garbage collected
 // according to its terminal status and its reschedule trackers
 func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime time.Time, thresholdIndex uint64) bool {
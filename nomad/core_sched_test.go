@@ -1567,6 +1567,127 @@ func TestCoreScheduler_JobGC_Force(t *testing.T) {
 	}
 }
 
+func TestCoreScheduler_JobGC_ForceTarget(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert two dead batch jobs, each with a terminal eval and deployment
+	state := s1.fsm.State()
+	var jobs []*structs.Job
+	var evals []*structs.Evaluation
+	var deployments []*structs.Deployment
+	for i := 0; i < 2; i++ {
+		job := mock.Job()
+		job.Type = structs.JobTypeBatch
+		job.Status = structs.JobStatusDead
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+		jobs = append(jobs, job)
+
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		eval.Status = structs.EvalStatusComplete
+		require.NoError(state.UpsertEvals(uint64(1010+i), []*structs.Evaluation{eval}))
+		evals = append(evals, eval)
+
+		d := mock.Deployment()
+		d.JobID = job.ID
+		d.Status = structs.DeploymentStatusSuccessful
+		require.NoError(state.UpsertDeployment(uint64(1020+i), d))
+		deployments = append(deployments, d)
+	}
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	require.NoError(err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt a GC targeting the first job
+	target := structs.CoreJobForceGCTarget(jobs[0].Namespace, jobs[0].ID)
+	gc := s1.coreJobEval(target, 2000)
+	require.NoError(core.Process(gc))
+
+	// Only the objects of the first job should be collected
+	ws := memdb.NewWatchSet()
+	for i, collected := range []bool{true, false} {
+		out, err := state.JobByID(ws, jobs[i].Namespace, jobs[i].ID)
+		require.NoError(err)
+		require.Equal(collected, out == nil, "job %d", i)
+
+		outE, err := state.EvalByID(ws, evals[i].ID)
+		require.NoError(err)
+		require.Equal(collected, outE == nil, "eval %d", i)
+
+		outD, err := state.DeploymentByID(ws, deployments[i].ID)
+		require.NoError(err)
+		require.Equal(collected, outD == nil, "deployment %d", i)
+	}
+}
+
+func TestCoreScheduler_JobGC_PerTypeThreshold(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.BatchJobGCThreshold = 1 * time.Minute
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	// Insert a dead batch job and a stopped service job
+	state := s1.fsm.State()
+	batch := mock.Job()
+	batch.Type = structs.JobTypeBatch
+	batch.Stop = true
+	require.NoError(state.UpsertJob(1000, batch))
+
+	service := mock.Job()
+	service.Stop = true
+	require.NoError(state.UpsertJob(1001, service))
+
+	// Insert a terminal eval for each job
+	var evals []*structs.Evaluation
+	for _, job := range []*structs.Job{batch, service} {
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		eval.Type = job.Type
+		eval.Status = structs.EvalStatusComplete
+		evals = append(evals, eval)
+	}
+	require.NoError(state.UpsertEvals(1002, evals))
+
+	// Witness an index that is older than the batch threshold but newer than
+	// the default job threshold
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-10*time.Minute))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	require.NoError(err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobJobGC, 2000)
+	require.NoError(core.Process(gc))
+
+	// Only the batch job should be collected
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, batch.Namespace, batch.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = state.JobByID(ws, service.Namespace, service.ID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
 // This test ensures parameterized jobs only get gc'd when stopped
 func TestCoreScheduler_JobGC_Parameterized(t *testing.T) {
 	t.Parallel()
@@ -2170,3 +2291,55 @@ func TestAllocation_GCEligible(t *testing.T) {
 	alloc.ClientStatus = structs.AllocClientStatusComplete
 	require.True(allocGCEligible(alloc, nil, time.Now(), 1000))
 }
+
+func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert an expired local, an expired global and a live token
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+	t1, t2, t3 := mock.ACLToken(), mock.ACLToken(), mock.ACLToken()
+	t1.ExpirationTime = &past
+	t2.ExpirationTime = &past
+	t2.Global = true
+	t3.ExpirationTime = &future
+	state := s1.fsm.State()
+	require.Nil(state.UpsertACLTokens(1000, []*structs.ACLToken{t1, t2, t3}))
+
+	// Insert an expired one-time token
+	ott := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      t3.AccessorID,
+		ExpiresAt:       past,
+	}
+	require.Nil(state.UpsertOneTimeToken(1001, ott))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	require.Nil(err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobExpiredACLTokenGC, 2000)
+	require.Nil(core.Process(gc))
+
+	// The expired tokens should be gone
+	out, err := state.ACLTokenByAccessorID(nil, t1.AccessorID)
+	require.Nil(err)
+	require.Nil(out)
+	out, err = state.ACLTokenByAccessorID(nil, t2.AccessorID)
+	require.Nil(err)
+	require.Nil(out)
+	out, err = state.ACLTokenByAccessorID(nil, t3.AccessorID)
+	require.Nil(err)
+	require.NotNil(out)
+
+	// The expired one-time token should be gone
+	outOTT, err := state.OneTimeTokenBySecret(nil, ott.OneTimeSecretID)
+	require.Nil(err)
+	require.Nil(outOTT)
+}
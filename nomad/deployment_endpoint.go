@@ -9,6 +9,7 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -112,6 +113,99 @@ func (d *Deployment) Fail(args *structs.DeploymentFailRequest, reply *structs.De
 	return d.srv.deploymentWatcher.FailDeployment(args, reply)
 }
 
+// Rollback is used to roll the job of a deployment back to the latest stable
+// version that preceded the deployment. An active deployment is failed, even
+// if its task groups do not auto revert.
+func (d *Deployment) Rollback(args *structs.DeploymentRollbackRequest, reply *structs.DeploymentUpdateResponse) error {
+	if done, err := d.srv.forward("Deployment.Rollback", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "deployment", "rollback"}, time.Now())
+
+	// Check namespace submit-job permissions
+	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.DeploymentID == "" {
+		return fmt.Errorf("missing deployment ID")
+	}
+
+	// Lookup the deployment and its job
+	snap, err := d.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	deploy, err := snap.DeploymentByID(ws, args.DeploymentID)
+	if err != nil {
+		return err
+	}
+	if deploy == nil || deploy.Namespace != args.RequestNamespace() {
+		return fmt.Errorf("deployment not found")
+	}
+
+	job, err := snap.JobByID(ws, deploy.Namespace, deploy.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.CreateIndex != deploy.JobCreateIndex {
+		return fmt.Errorf("job %q of deployment not found", deploy.JobID)
+	}
+	if job.Version != deploy.JobVersion {
+		return fmt.Errorf("can't roll back deployment of job version %d: job has been updated to version %d",
+			deploy.JobVersion, job.Version)
+	}
+
+	// Find the stable version that was running before the deployment
+	versions, err := snap.JobVersionsByID(ws, deploy.Namespace, deploy.JobID)
+	if err != nil {
+		return err
+	}
+	var rollbackJob *structs.Job
+	for _, v := range versions {
+		if v.Version < deploy.JobVersion && v.Stable {
+			rollbackJob = v
+			break
+		}
+	}
+	if rollbackJob == nil {
+		return fmt.Errorf("no stable job version prior to version %d to roll back to", deploy.JobVersion)
+	}
+	if !job.SpecChanged(rollbackJob) {
+		return fmt.Errorf("job version %d has the same specification as version %d", rollbackJob.Version, job.Version)
+	}
+
+	// Fail the active deployment through the deployment watcher, which
+	// commits the rollback along with the deployment status.
+	if deploy.Active() {
+		return d.srv.deploymentWatcher.RollbackDeployment(args, rollbackJob, reply)
+	}
+
+	// The deployment is done so revert the job as a new version.
+	revert := &structs.JobRevertRequest{
+		JobID:               deploy.JobID,
+		JobVersion:          rollbackJob.Version,
+		EnforcePriorVersion: helper.Uint64ToPtr(job.Version),
+		WriteRequest:        args.WriteRequest,
+	}
+
+	var resp structs.JobRegisterResponse
+	if err := d.srv.staticEndpoints.Job.Revert(revert, &resp); err != nil {
+		return err
+	}
+
+	reply.EvalID = resp.EvalID
+	reply.EvalCreateIndex = resp.EvalCreateIndex
+	reply.RevertedJobVersion = helper.Uint64ToPtr(rollbackJob.Version)
+	reply.Index = resp.Index
+	return nil
+}
+
 // Pause is used to pause a deployment
 func (d *Deployment) Pause(args *structs.DeploymentPauseRequest, reply *structs.DeploymentUpdateResponse) error {
 	if done, err := d.srv.forward("Deployment.Pause", args, args, reply); done {
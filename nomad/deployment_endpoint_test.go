@@ -348,6 +348,170 @@ func TestDeploymentEndpoint_Fail_Rollback(t *testing.T) {
 	assert.EqualValues(2, jout.Version, "reverted job version")
 }
 
+func TestDeploymentEndpoint_Rollback(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+	state := s1.fsm.State()
+
+	// Create the stable original job and a job version that failed its
+	// deployment without auto reverting
+	j := mock.Job()
+	j.Stable = true
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	assert.Nil(state.UpsertJob(998, j), "UpsertJob")
+
+	j2 := j.Copy()
+	j2.Stable = false
+	j2.Meta["foo"] = "bar"
+	assert.Nil(state.UpsertJob(999, j2), "UpsertJob")
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.JobVersion = 1
+	d.JobCreateIndex = 998
+	assert.Nil(state.UpsertDeployment(1000, d), "UpsertDeployment")
+
+	// Roll back the active deployment
+	req := &structs.DeploymentRollbackRequest{
+		DeploymentID: d.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DeploymentUpdateResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.Rollback", req, &resp), "RPC")
+	assert.NotEqual(resp.Index, uint64(0), "bad response index")
+	assert.NotNil(resp.RevertedJobVersion, "bad revert version")
+	assert.EqualValues(0, *resp.RevertedJobVersion, "bad revert version")
+
+	// Lookup the evaluation
+	ws := memdb.NewWatchSet()
+	eval, err := state.EvalByID(ws, resp.EvalID)
+	assert.Nil(err, "EvalByID failed")
+	assert.NotNil(eval, "Expect eval")
+	assert.Equal(eval.TriggeredBy, structs.EvalTriggerDeploymentWatcher, "eval trigger")
+	assert.Equal(eval.DeploymentID, d.ID, "eval deployment id")
+
+	// Lookup the deployment
+	expectedDesc := structs.DeploymentStatusDescriptionRollback(structs.DeploymentStatusDescriptionFailedByUser, 0)
+	dout, err := state.DeploymentByID(ws, d.ID)
+	assert.Nil(err, "DeploymentByID failed")
+	assert.Equal(dout.Status, structs.DeploymentStatusFailed, "wrong status")
+	assert.Equal(dout.StatusDescription, expectedDesc, "wrong status description")
+
+	// Lookup the job
+	jout, err := state.JobByID(ws, j.Namespace, j.ID)
+	assert.Nil(err, "JobByID")
+	assert.EqualValues(2, jout.Version, "reverted job version")
+	assert.NotContains(jout.Meta, "foo", "reverted job meta")
+
+	// The deployment can not be rolled back again since the job changed
+	err = msgpackrpc.CallWithCodec(codec, "Deployment.Rollback", req, &resp)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "job has been updated to version 2")
+}
+
+func TestDeploymentEndpoint_Rollback_Terminal(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+	state := s1.fsm.State()
+
+	// Create a stable job, an unstable version and a version whose
+	// deployment succeeded
+	j := mock.Job()
+	j.Stable = true
+	assert.Nil(state.UpsertJob(997, j), "UpsertJob")
+
+	j2 := j.Copy()
+	j2.Stable = false
+	j2.Meta["foo"] = "bar"
+	assert.Nil(state.UpsertJob(998, j2), "UpsertJob")
+
+	j3 := j2.Copy()
+	j3.Meta["foo"] = "baz"
+	assert.Nil(state.UpsertJob(999, j3), "UpsertJob")
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.JobVersion = 2
+	d.JobCreateIndex = 997
+	d.Status = structs.DeploymentStatusSuccessful
+	assert.Nil(state.UpsertDeployment(1000, d), "UpsertDeployment")
+
+	// Roll back the deployment to the stable version before it
+	req := &structs.DeploymentRollbackRequest{
+		DeploymentID: d.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DeploymentUpdateResponse
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.Rollback", req, &resp), "RPC")
+	assert.NotEqual(resp.Index, uint64(0), "bad response index")
+	assert.NotNil(resp.RevertedJobVersion, "bad revert version")
+	assert.EqualValues(0, *resp.RevertedJobVersion, "bad revert version")
+
+	ws := memdb.NewWatchSet()
+	eval, err := state.EvalByID(ws, resp.EvalID)
+	assert.Nil(err, "EvalByID failed")
+	assert.NotNil(eval, "Expect eval")
+	assert.Equal(eval.TriggeredBy, structs.EvalTriggerJobRegister, "eval trigger")
+
+	jout, err := state.JobByID(ws, j.Namespace, j.ID)
+	assert.Nil(err, "JobByID")
+	assert.EqualValues(3, jout.Version, "reverted job version")
+	assert.NotContains(jout.Meta, "foo", "reverted job meta")
+
+	dout, err := state.DeploymentByID(ws, d.ID)
+	assert.Nil(err, "DeploymentByID failed")
+	assert.Equal(dout.Status, structs.DeploymentStatusSuccessful, "wrong status")
+}
+
+func TestDeploymentEndpoint_Rollback_NoStableVersion(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+	state := s1.fsm.State()
+
+	// Create a job whose first deployment is running
+	j := mock.Job()
+	assert.Nil(state.UpsertJob(999, j), "UpsertJob")
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.JobVersion = 0
+	d.JobCreateIndex = 999
+	assert.Nil(state.UpsertDeployment(1000, d), "UpsertDeployment")
+
+	req := &structs.DeploymentRollbackRequest{
+		DeploymentID: d.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DeploymentUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Deployment.Rollback", req, &resp)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "no stable job version prior to version 0")
+
+	// A deployment of another namespace is not found
+	req.Namespace = "other"
+	err = msgpackrpc.CallWithCodec(codec, "Deployment.Rollback", req, &resp)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "deployment not found")
+}
+
 func TestDeploymentEndpoint_Pause(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
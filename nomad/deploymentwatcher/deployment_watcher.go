@@ -280,6 +280,12 @@ func (w *deploymentWatcher) PauseDeployment(
 	}
 	update := w.getDeploymentStatusUpdate(status, desc)
 
+	// Resuming the deployment lets the groups updated in manual steps place
+	// their next batch
+	if !req.Pause {
+		update.StepAllocs = w.nextSteps()
+	}
+
 	// Commit the change
 	i, err := w.upsertDeploymentStatusUpdate(update, eval, nil)
 	if err != nil {
@@ -326,6 +332,25 @@ func (w *deploymentWatcher) FailDeployment(
 		}
 	}
 
+	return w.commitFail(status, desc, rollbackJob, resp)
+}
+
+// RollbackDeployment fails the deployment and rolls the job back to the given
+// version, regardless of whether the task groups auto revert.
+func (w *deploymentWatcher) RollbackDeployment(
+	req *structs.DeploymentRollbackRequest,
+	rollbackJob *structs.Job,
+	resp *structs.DeploymentUpdateResponse) error {
+
+	desc := structs.DeploymentStatusDescriptionRollback(structs.DeploymentStatusDescriptionFailedByUser, rollbackJob.Version)
+	return w.commitFail(structs.DeploymentStatusFailed, desc, rollbackJob, resp)
+}
+
+// commitFail commits the failed deployment status along with the optional
+// job to roll back to.
+func (w *deploymentWatcher) commitFail(status, desc string, rollbackJob *structs.Job,
+	resp *structs.DeploymentUpdateResponse) error {
+
 	// Commit the change
 	update := w.getDeploymentStatusUpdate(status, desc)
 	eval := w.getEval()
@@ -460,6 +485,16 @@ FAIL:
 				break FAIL
 			}
 
+			// A batch of a group updated in manual steps is healthy, so wait
+			// for the deployment to be resumed
+			if res.pauseStep {
+				u := w.getDeploymentStatusUpdate(structs.DeploymentStatusPaused, structs.DeploymentStatusDescriptionPausedManualStep)
+				if _, err := w.upsertDeploymentStatusUpdate(u, nil, nil); err != nil {
+					w.logger.Error("failed to pause deployment for manual step", "error", err)
+				}
+				continue
+			}
+
 			// Create an eval to push the deployment along
 			if res.createEval || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
@@ -505,6 +540,7 @@ type allocUpdateResult struct {
 	createEval        bool
 	failDeployment    bool
 	rollback          bool
+	pauseStep         bool
 	allowReplacements []string
 }
 
@@ -568,9 +604,68 @@ func (w *deploymentWatcher) handleAllocUpdate(allocs []*structs.AllocListStub) (
 		}
 	}
 
+	if !res.failDeployment {
+		pause, err := w.stepHealthy(allocs)
+		if err != nil {
+			return res, err
+		}
+		res.pauseStep = pause
+	}
+
 	return res, nil
 }
 
+// stepHealthy returns whether the running deployment has a group updated in
+// manual steps with all the allocations of its current step healthy and more
+// allocations left to place.
+func (w *deploymentWatcher) stepHealthy(allocs []*structs.AllocListStub) (bool, error) {
+	// The cached deployment may predate a resume, so read the latest one
+	d, err := w.state.DeploymentByID(nil, w.deploymentID)
+	if err != nil || d == nil || d.Status != structs.DeploymentStatusRunning {
+		return false, err
+	}
+
+	healthy := make(map[string]int)
+	for _, alloc := range allocs {
+		if alloc.DeploymentStatus.IsHealthy() {
+			healthy[alloc.TaskGroup]++
+		}
+	}
+
+	for _, tg := range w.j.TaskGroups {
+		dstate, ok := d.TaskGroups[tg.Name]
+		if !ok || tg.Update == nil || !tg.Update.ManualStep || dstate.StepAllocs == 0 {
+			continue
+		}
+		if dstate.StepAllocs < dstate.DesiredTotal && healthy[tg.Name] >= dstate.StepAllocs {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// nextSteps returns the next step of the groups updated in manual steps that
+// have placed all the allocations of their current step.
+func (w *deploymentWatcher) nextSteps() map[string]int {
+	var steps map[string]int
+	d := w.getDeployment()
+	for _, tg := range w.j.TaskGroups {
+		dstate, ok := d.TaskGroups[tg.Name]
+		if !ok || tg.Update == nil || !tg.Update.ManualStep || dstate.StepAllocs == 0 {
+			continue
+		}
+		if dstate.PlacedAllocs < dstate.StepAllocs {
+			continue
+		}
+
+		if steps == nil {
+			steps = make(map[string]int)
+		}
+		steps[tg.Name] = dstate.StepAllocs + tg.Update.MaxParallel
+	}
+	return steps
+}
+
 // shouldFail returns whether the job should be failed and whether it should
 // rolled back to an earlier stable version by examining the allocations in the
 // deployment.
@@ -82,6 +82,10 @@ type Watcher struct {
 	// allocation desired transition updates
 	allocUpdateBatcher *AllocUpdateBatcher
 
+	// webhookSigningKey is the secret the payloads of the deployment progress
+	// webhooks are signed with
+	webhookSigningKey string
+
 	// ctx and exitFn are used to cancel the watcher
 	ctx    context.Context
 	exitFn context.CancelFunc
@@ -90,15 +94,17 @@ type Watcher struct {
 }
 
 // NewDeploymentsWatcher returns a deployments watcher that is used to watch
-// deployments and trigger the scheduler as needed.
+// deployments and trigger the scheduler as needed. The progress webhooks of
+// the deployments are signed with the given key, unless it is empty.
 func NewDeploymentsWatcher(logger log.Logger,
 	raft DeploymentRaftEndpoints, stateQueriesPerSecond float64,
-	updateBatchDuration time.Duration) *Watcher {
+	updateBatchDuration time.Duration, webhookSigningKey string) *Watcher {
 
 	return &Watcher{
 		raft:                raft,
 		queryLimiter:        rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
 		updateBatchDuration: updateBatchDuration,
+		webhookSigningKey:   webhookSigningKey,
 		logger:              logger.Named("deployments_watcher"),
 	}
 }
@@ -147,6 +153,8 @@ func (w *Watcher) flush() {
 // add and remove watchers on.
 func (w *Watcher) watchDeployments(ctx context.Context) {
 	dindex := uint64(1)
+	progress := make(map[string]*deploymentProgress)
+	first := true
 	for {
 		// Block getting all deployments using the last deployment index.
 		deployments, idx, err := w.getDeploys(ctx, dindex)
@@ -172,6 +180,13 @@ func (w *Watcher) watchDeployments(ctx context.Context) {
 				w.remove(d)
 			}
 		}
+
+		// Notify the progress webhooks of the deployments, except on the
+		// first pass which records the progress of the existing deployments
+		if err == nil {
+			w.notifyProgress(deployments, progress, first)
+			first = false
+		}
 	}
 }
 
@@ -358,6 +373,17 @@ func (w *Watcher) FailDeployment(req *structs.DeploymentFailRequest, resp *struc
 	return watcher.FailDeployment(req, resp)
 }
 
+// RollbackDeployment is used to fail the deployment and roll its job back to
+// the given job version.
+func (w *Watcher) RollbackDeployment(req *structs.DeploymentRollbackRequest, rollbackJob *structs.Job, resp *structs.DeploymentUpdateResponse) error {
+	watcher, err := w.getOrCreateWatcher(req.DeploymentID)
+	if err != nil {
+		return err
+	}
+
+	return watcher.RollbackDeployment(req, rollbackJob, resp)
+}
+
 // createUpdate commits the given allocation desired transition and evaluation
 // to Raft but batches the commit with other calls.
 func (w *Watcher) createUpdate(allocs map[string]*structs.DesiredTransition, eval *structs.Evaluation) (uint64, error) {
@@ -19,7 +19,7 @@ import (
 
 func testDeploymentWatcher(t *testing.T, qps float64, batchDur time.Duration) (*Watcher, *mockBackend) {
 	m := newMockBackend(t)
-	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, qps, batchDur)
+	w := NewDeploymentsWatcher(testlog.HCLogger(t), m, qps, batchDur, "")
 	return w, m
 }
 
@@ -613,6 +613,58 @@ func TestWatcher_PauseDeployment_Unpause_Paused(t *testing.T) {
 	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
 }
 
+// Test unpausing a deployment paused for a manual step places the next step
+func TestWatcher_PauseDeployment_Unpause_ManualStep(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a job updated in manual steps and a deployment that has placed
+	// its first step
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.MaxParallel = 2
+	j.TaskGroups[0].Update.ManualStep = true
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.Status = structs.DeploymentStatusPaused
+	d.StatusDescription = structs.DeploymentStatusDescriptionPausedManualStep
+	d.TaskGroups["web"].DesiredTotal = 10
+	d.TaskGroups["web"].PlacedAllocs = 2
+	d.TaskGroups["web"].StepAllocs = 2
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+
+	// require that we get a call to UpsertDeploymentStatusUpdate
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusRunning,
+		StatusDescription: structs.DeploymentStatusDescriptionRunning,
+		Eval:              true,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == len(w.watchers), nil },
+		func(err error) { require.Equal(1, len(w.watchers), "Should have 1 deployment") })
+
+	// Call PauseDeployment
+	req := &structs.DeploymentPauseRequest{
+		DeploymentID: d.ID,
+		Pause:        false,
+	}
+	var resp structs.DeploymentUpdateResponse
+	err := w.PauseDeployment(req, &resp)
+	require.Nil(err, "PauseDeployment")
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+
+	// The next step is a further batch of allocations
+	out, err := m.state.DeploymentByID(nil, d.ID)
+	require.Nil(err, "DeploymentByID")
+	require.Equal(4, out.TaskGroups["web"].StepAllocs)
+}
+
 // Test unpausing a deployment that is running
 func TestWatcher_PauseDeployment_Unpause_Running(t *testing.T) {
 	t.Parallel()
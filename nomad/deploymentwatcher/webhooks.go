@@ -0,0 +1,206 @@
+package deploymentwatcher
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// webhookTimeout bounds how long a call to a progress webhook may take.
+	webhookTimeout = 10 * time.Second
+
+	// WebhookEventHeader is the header holding the event of a progress
+	// webhook call.
+	WebhookEventHeader = "X-Nomad-Event"
+
+	// WebhookSignatureHeader is the header holding the hex encoded
+	// HMAC-SHA256 signature of the payload of a progress webhook call.
+	WebhookSignatureHeader = "X-Nomad-Signature"
+)
+
+// webhookClient is the HTTP client used to call the progress webhooks.
+var webhookClient = &http.Client{Timeout: webhookTimeout}
+
+// WebhookPayload is the body POSTed to the progress webhooks of a deployment.
+type WebhookPayload struct {
+	// Event is one of "started", "promoted", "failed" or "successful".
+	Event string
+
+	// Time is when the event was observed.
+	Time time.Time
+
+	// Deployment is the deployment as of the event.
+	Deployment *structs.Deployment
+}
+
+// deploymentProgress is the last observed progress of a deployment, used to
+// detect the events to send.
+type deploymentProgress struct {
+	status   string
+	promoted bool
+}
+
+// progressOf returns the progress of the deployment. A deployment is promoted
+// once all its canaries are.
+func progressOf(d *structs.Deployment) deploymentProgress {
+	p := deploymentProgress{status: d.Status}
+	for _, group := range d.TaskGroups {
+		if group.DesiredCanaries == 0 {
+			continue
+		}
+		if !group.Promoted {
+			return p
+		}
+		p.promoted = true
+	}
+	return p
+}
+
+// progressEvents returns the events to send for the deployment given its
+// previously observed progress.
+func progressEvents(prev *deploymentProgress, d *structs.Deployment) []string {
+	cur := progressOf(d)
+
+	var events []string
+	if prev == nil && d.Active() {
+		events = append(events, structs.DeploymentWebhookEventStarted)
+	}
+	if cur.promoted && (prev == nil || !prev.promoted) {
+		events = append(events, structs.DeploymentWebhookEventPromoted)
+	}
+	if prev == nil || prev.status != cur.status {
+		switch cur.status {
+		case structs.DeploymentStatusFailed:
+			events = append(events, structs.DeploymentWebhookEventFailed)
+		case structs.DeploymentStatusSuccessful:
+			events = append(events, structs.DeploymentWebhookEventSuccessful)
+		}
+	}
+	return events
+}
+
+// notifyProgress sends the progress events of the given deployments to their
+// webhooks and records their progress. If seed is set, the progress is only
+// recorded so that deployments already known when the watcher is enabled
+// don't send their events again.
+func (w *Watcher) notifyProgress(deployments []*structs.Deployment, progress map[string]*deploymentProgress, seed bool) {
+	seen := make(map[string]struct{}, len(deployments))
+	for _, d := range deployments {
+		seen[d.ID] = struct{}{}
+
+		prev := progress[d.ID]
+		cur := progressOf(d)
+		progress[d.ID] = &cur
+		if seed {
+			continue
+		}
+
+		events := progressEvents(prev, d)
+		if len(events) == 0 {
+			continue
+		}
+
+		webhooks, err := w.progressWebhooks(d)
+		if err != nil {
+			w.logger.Error("failed to look up deployment progress webhooks", "deployment_id", d.ID, "error", err)
+			continue
+		}
+		for _, event := range events {
+			for _, webhook := range webhooks {
+				go w.callWebhook(webhook, event, d)
+			}
+		}
+	}
+
+	// Forget the deployments that were garbage collected
+	for id := range progress {
+		if _, ok := seen[id]; !ok {
+			delete(progress, id)
+		}
+	}
+}
+
+// progressWebhooks returns the distinct progress webhooks of the task groups
+// of the deployment.
+func (w *Watcher) progressWebhooks(d *structs.Deployment) ([]*structs.DeploymentWebhook, error) {
+	job, err := w.state.JobByIDAndVersion(nil, d.Namespace, d.JobID, d.JobVersion)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, nil
+	}
+
+	var webhooks []*structs.DeploymentWebhook
+	urls := make(map[string]struct{})
+	for _, tg := range job.TaskGroups {
+		if _, ok := d.TaskGroups[tg.Name]; !ok {
+			continue
+		}
+		if tg.Update == nil || tg.Update.ProgressWebhook == nil {
+			continue
+		}
+		if _, ok := urls[tg.Update.ProgressWebhook.URL]; ok {
+			continue
+		}
+		urls[tg.Update.ProgressWebhook.URL] = struct{}{}
+		webhooks = append(webhooks, tg.Update.ProgressWebhook)
+	}
+	return webhooks, nil
+}
+
+// callWebhook POSTs the event of the deployment to the webhook. Failures are
+// logged and not retried.
+func (w *Watcher) callWebhook(webhook *structs.DeploymentWebhook, event string, d *structs.Deployment) {
+	body, err := json.Marshal(&WebhookPayload{
+		Event:      event,
+		Time:       time.Now().UTC(),
+		Deployment: d,
+	})
+	if err != nil {
+		w.logger.Error("failed to encode deployment progress webhook payload", "deployment_id", d.ID, "error", err)
+		return
+	}
+
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		w.logger.Error("invalid deployment progress webhook", "deployment_id", d.ID, "url", webhook.URL, "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	if w.webhookSigningKey != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.webhookSigningKey, body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		w.logger.Warn("failed to call deployment progress webhook", "deployment_id", d.ID, "event", event, "url", webhook.URL, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		w.logger.Warn("deployment progress webhook returned an error", "deployment_id", d.ID, "event", event, "url", webhook.URL,
+			"error", fmt.Sprintf("unexpected response code %d", resp.StatusCode))
+	}
+}
+
+// SignWebhookPayload returns the value of the signature header of a progress
+// webhook call with the given payload.
+func SignWebhookPayload(key string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
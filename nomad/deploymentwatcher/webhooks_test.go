@@ -0,0 +1,135 @@
+package deploymentwatcher
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the progress webhooks are notified of the deployment events
+func TestWatcher_ProgressWebhooks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	type call struct {
+		payload   WebhookPayload
+		event     string
+		signature string
+		body      []byte
+	}
+	calls := make(chan call, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(err)
+		c := call{
+			event:     r.Header.Get(WebhookEventHeader),
+			signature: r.Header.Get(WebhookSignatureHeader),
+			body:      body,
+		}
+		require.NoError(json.Unmarshal(body, &c.payload))
+		calls <- c
+	}))
+	defer ts.Close()
+
+	m := newMockBackend(t)
+	w := NewDeploymentsWatcher(testlog.HCLogger(t), m,
+		LimitStateQueriesPerSecond, CrossDeploymentUpdateBatchDuration, "secret")
+
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.Canary = 1
+	j.TaskGroups[0].Update.ProgressWebhook = &structs.DeploymentWebhook{URL: ts.URL}
+	require.NoError(m.state.UpsertJob(m.nextIndex(), j))
+
+	// Deployments that exist when the watcher is enabled aren't notified
+	d1 := mock.Deployment()
+	d1.JobID = j.ID
+	d1.JobVersion = j.Version
+	require.NoError(m.state.UpsertDeployment(m.nextIndex(), d1))
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == len(w.watchers), nil },
+		func(err error) { require.Equal(1, len(w.watchers), "1 deployment returned") })
+
+	next := func(event string) call {
+		select {
+		case c := <-calls:
+			require.Equal(event, c.event)
+			require.Equal(event, c.payload.Event)
+			require.Equal(SignWebhookPayload("secret", c.body), c.signature)
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the %q event", event)
+		}
+		return call{}
+	}
+
+	// A new deployment is started
+	d2 := mock.Deployment()
+	d2.JobID = j.ID
+	d2.JobVersion = j.Version
+	d2.TaskGroups["web"].DesiredCanaries = 1
+	require.NoError(m.state.UpsertDeployment(m.nextIndex(), d2))
+	c := next(structs.DeploymentWebhookEventStarted)
+	require.Equal(d2.ID, c.payload.Deployment.ID)
+
+	// Its canaries are promoted
+	d2 = d2.Copy()
+	d2.TaskGroups["web"].Promoted = true
+	require.NoError(m.state.UpsertDeployment(m.nextIndex(), d2))
+	next(structs.DeploymentWebhookEventPromoted)
+
+	// It succeeds
+	d2 = d2.Copy()
+	d2.Status = structs.DeploymentStatusSuccessful
+	require.NoError(m.state.UpsertDeployment(m.nextIndex(), d2))
+	c = next(structs.DeploymentWebhookEventSuccessful)
+	require.Equal(structs.DeploymentStatusSuccessful, c.payload.Deployment.Status)
+
+	// The existing deployment fails
+	d1 = d1.Copy()
+	d1.Status = structs.DeploymentStatusFailed
+	require.NoError(m.state.UpsertDeployment(m.nextIndex(), d1))
+	c = next(structs.DeploymentWebhookEventFailed)
+	require.Equal(d1.ID, c.payload.Deployment.ID)
+
+	select {
+	case c := <-calls:
+		t.Fatalf("unexpected %q event", c.event)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestWatcher_ProgressEvents(t *testing.T) {
+	t.Parallel()
+
+	d := mock.Deployment()
+	d.TaskGroups["web"].DesiredCanaries = 1
+	require.Equal(t, []string{structs.DeploymentWebhookEventStarted}, progressEvents(nil, d))
+
+	// Nothing changed
+	prev := progressOf(d)
+	require.Empty(t, progressEvents(&prev, d))
+
+	// Promoted and successful at once
+	d.TaskGroups["web"].Promoted = true
+	d.Status = structs.DeploymentStatusSuccessful
+	require.Equal(t, []string{
+		structs.DeploymentWebhookEventPromoted,
+		structs.DeploymentWebhookEventSuccessful,
+	}, progressEvents(&prev, d))
+
+	// Deployments without canaries are never promoted
+	d = mock.Deployment()
+	d.Status = structs.DeploymentStatusCancelled
+	require.Empty(t, progressEvents(nil, d))
+}
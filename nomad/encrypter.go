@@ -0,0 +1,113 @@
+package nomad
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rootKeySize is the size in bytes of the AES-256 root keys
+	rootKeySize = 32
+)
+
+// newRootKey generates a new random root key.
+func newRootKey() (*structs.RootKey, error) {
+	key := make([]byte, rootKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate root key: %v", err)
+	}
+
+	return &structs.RootKey{
+		KeyID:      uuid.Generate(),
+		Algorithm:  structs.RootKeyAlgorithmAES256GCM,
+		Key:        key,
+		CreateTime: time.Now().UnixNano(),
+	}, nil
+}
+
+// newAEAD returns the cipher used to encrypt data with the root key.
+func newAEAD(key *structs.RootKey) (cipher.AEAD, error) {
+	if key.Algorithm != structs.RootKeyAlgorithmAES256GCM {
+		return nil, fmt.Errorf("unsupported root key algorithm %q", key.Algorithm)
+	}
+
+	block, err := aes.NewCipher(key.Key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptVariable encodes and encrypts the items of the variable with the
+// given root key. The variable metadata is used as additional authenticated
+// data so that encrypted items can't be moved to another path.
+func encryptVariable(key *structs.RootKey, v *structs.VariableDecrypted) (*structs.VariableEncrypted, error) {
+	plaintext, err := json.Marshal(v.Items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode variable: %v", err)
+	}
+	if len(plaintext) > structs.MaxVariableSize {
+		return nil, fmt.Errorf("variable size %d bytes exceeds maximum of %d bytes", len(plaintext), structs.MaxVariableSize)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %v", err)
+	}
+
+	return &structs.VariableEncrypted{
+		VariableMetadata: v.VariableMetadata,
+		Data:             aead.Seal(nonce, nonce, plaintext, variableAAD(v.Namespace, v.Path)),
+		KeyID:            key.KeyID,
+	}, nil
+}
+
+// decryptVariable decrypts the variable with the root key it was encrypted
+// with.
+func decryptVariable(state *state.StateStore, v *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	key, err := state.RootKeyByID(nil, v.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("root key %q for variable %q not found", v.KeyID, v.Path)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	size := aead.NonceSize()
+	if len(v.Data) < size {
+		return nil, fmt.Errorf("malformed variable %q", v.Path)
+	}
+	plaintext, err := aead.Open(nil, v.Data[:size], v.Data[size:], variableAAD(v.Namespace, v.Path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt variable %q: %v", v.Path, err)
+	}
+
+	dv := &structs.VariableDecrypted{VariableMetadata: v.VariableMetadata}
+	if err := json.Unmarshal(plaintext, &dv.Items); err != nil {
+		return nil, fmt.Errorf("failed to decode variable %q: %v", v.Path, err)
+	}
+	return dv, nil
+}
+
+// variableAAD returns the additional authenticated data of a variable.
+func variableAAD(namespace, path string) []byte {
+	return []byte(namespace + "/" + path)
+}
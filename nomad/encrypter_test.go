@@ -0,0 +1,47 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncrypter_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := state.TestStateStore(t)
+
+	key, err := newRootKey()
+	require.NoError(err)
+	require.Len(key.Key, rootKeySize)
+	require.NoError(state.UpsertRootKey(1000, key))
+
+	v := &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "project/db",
+		},
+		Items: map[string]string{"password": "hunter2"},
+	}
+	encrypted, err := encryptVariable(key, v)
+	require.NoError(err)
+	require.Equal(key.KeyID, encrypted.KeyID)
+	require.NotContains(string(encrypted.Data), "hunter2")
+
+	decrypted, err := decryptVariable(state, encrypted)
+	require.NoError(err)
+	require.Equal(v, decrypted)
+
+	// The encrypted items can't be moved to another path
+	encrypted.Path = "project/other"
+	_, err = decryptVariable(state, encrypted)
+	require.Error(err)
+
+	// Variables over the size limit are rejected
+	v.Items["big"] = string(make([]byte, structs.MaxVariableSize))
+	_, err = encryptVariable(key, v)
+	require.Error(err)
+	require.Contains(err.Error(), "exceeds maximum")
+}
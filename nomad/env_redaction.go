@@ -0,0 +1,159 @@
+package nomad
+
+import (
+	"strings"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/structs"
+	glob "github.com/ryanuber/go-glob"
+)
+
+const (
+	// redactedEnvValue replaces the values of the redacted environment
+	// variables.
+	redactedEnvValue = "<redacted>"
+)
+
+// redactedEnvVars returns the patterns of the environment variables whose
+// values are redacted from the responses to the ACL, or nil if nothing is
+// redacted. Management tokens always read the values.
+func (s *Server) redactedEnvVars(aclObj *acl.ACL) []string {
+	if aclObj != nil && aclObj.IsManagement() {
+		return nil
+	}
+	return s.config.RedactEnvVars
+}
+
+// envVarRedacted returns whether the value of the environment variable is
+// redacted by the patterns.
+func envVarRedacted(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJobEnv returns the job with the values of the environment variables
+// matching the patterns redacted. The job is copied if anything is redacted,
+// as it may be shared with the state store.
+func redactJobEnv(job *structs.Job, patterns []string) *structs.Job {
+	if job == nil || len(patterns) == 0 || !jobEnvRedacted(job, patterns) {
+		return job
+	}
+
+	job = job.Copy()
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			for k := range task.Env {
+				if envVarRedacted(patterns, k) {
+					task.Env[k] = redactedEnvValue
+				}
+			}
+		}
+	}
+	return job
+}
+
+// jobEnvRedacted returns whether the job has environment variables matching
+// the patterns.
+func jobEnvRedacted(job *structs.Job, patterns []string) bool {
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			for k := range task.Env {
+				if envVarRedacted(patterns, k) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// redactAllocEnv returns the allocation with the values of the environment
+// variables of its job matching the patterns redacted. The allocation is
+// copied if anything is redacted.
+func redactAllocEnv(alloc *structs.Allocation, patterns []string) *structs.Allocation {
+	if alloc == nil || alloc.Job == nil {
+		return alloc
+	}
+
+	job := redactJobEnv(alloc.Job, patterns)
+	if job == alloc.Job {
+		return alloc
+	}
+
+	alloc = alloc.CopySkipJob()
+	alloc.Job = job
+	return alloc
+}
+
+// redactJobDiffEnv redacts the old and new values of the environment
+// variables matching the patterns from the diff in place. Redacting the diff
+// rather than the diffed jobs keeps changes to the values visible.
+func redactJobDiffEnv(diff *structs.JobDiff, patterns []string) {
+	if diff == nil || len(patterns) == 0 {
+		return
+	}
+
+	for _, tg := range diff.TaskGroups {
+		for _, task := range tg.Tasks {
+			for _, field := range task.Fields {
+				if !strings.HasPrefix(field.Name, "Env[") || !strings.HasSuffix(field.Name, "]") {
+					continue
+				}
+				if !envVarRedacted(patterns, field.Name[len("Env["):len(field.Name)-1]) {
+					continue
+				}
+				if field.Old != "" {
+					field.Old = redactedEnvValue
+				}
+				if field.New != "" {
+					field.New = redactedEnvValue
+				}
+			}
+		}
+	}
+}
+
+// redactEventsEnv returns the events with the values of the environment
+// variables of the jobs matching the patterns redacted. The events are copied
+// if anything is redacted, as they are shared by the subscribers.
+func redactEventsEnv(events *structs.Events, patterns []string) *structs.Events {
+	if len(patterns) == 0 {
+		return events
+	}
+
+	var redacted *structs.Events
+	for i, event := range events.Events {
+		var payload interface{}
+		switch p := event.Payload.(type) {
+		case *structs.JobEvent:
+			if job := redactJobEnv(p.Job, patterns); job != p.Job {
+				payload = &structs.JobEvent{Job: job, Summary: p.Summary}
+			}
+		case *structs.AllocationEvent:
+			if alloc := redactAllocEnv(p.Allocation, patterns); alloc != p.Allocation {
+				payload = &structs.AllocationEvent{Allocation: alloc}
+			}
+		}
+		if payload == nil {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = &structs.Events{
+				Index:  events.Index,
+				Events: make([]structs.Event, len(events.Events)),
+			}
+			copy(redacted.Events, events.Events)
+		}
+		redacted.Events[i].Payload = payload
+	}
+
+	if redacted == nil {
+		return events
+	}
+	return redacted
+}
@@ -0,0 +1,175 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactJobEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Env = map[string]string{
+		"FOO":         "bar",
+		"DB_PASSWORD": "hunter2",
+	}
+
+	// Jobs without matching variables are not copied
+	require.True(job == redactJobEnv(job, nil))
+	require.True(job == redactJobEnv(job, []string{"AWS_*"}))
+
+	out := redactJobEnv(job, []string{"*_PASSWORD"})
+	require.False(job == out)
+	require.Equal("bar", out.TaskGroups[0].Tasks[0].Env["FOO"])
+	require.Equal(redactedEnvValue, out.TaskGroups[0].Tasks[0].Env["DB_PASSWORD"])
+
+	// The original job is untouched
+	require.Equal("hunter2", job.TaskGroups[0].Tasks[0].Env["DB_PASSWORD"])
+}
+
+func TestRedactAllocEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	alloc := mock.Alloc()
+	require.True(alloc == redactAllocEnv(alloc, []string{"DB_*"}))
+
+	out := redactAllocEnv(alloc, []string{"FOO"})
+	require.False(alloc == out)
+	require.Equal(alloc.ID, out.ID)
+	require.Equal(redactedEnvValue, out.Job.TaskGroups[0].Tasks[0].Env["FOO"])
+	require.Equal("bar", alloc.Job.TaskGroups[0].Tasks[0].Env["FOO"])
+}
+
+func TestRedactJobDiffEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	old := mock.Job()
+	new := old.Copy()
+	new.TaskGroups[0].Tasks[0].Env["FOO"] = "baz"
+	new.TaskGroups[0].Tasks[0].Env["DB_PASSWORD"] = "hunter2"
+
+	diff, err := old.Diff(new, true)
+	require.NoError(err)
+	redactJobDiffEnv(diff, []string{"FOO", "*_PASSWORD"})
+
+	fields := make(map[string]*structs.FieldDiff)
+	for _, f := range diff.TaskGroups[0].Tasks[0].Fields {
+		fields[f.Name] = f
+	}
+
+	// The changes stay visible without their values
+	require.Equal(structs.DiffTypeEdited, fields["Env[FOO]"].Type)
+	require.Equal(redactedEnvValue, fields["Env[FOO]"].Old)
+	require.Equal(redactedEnvValue, fields["Env[FOO]"].New)
+	require.Equal(structs.DiffTypeAdded, fields["Env[DB_PASSWORD]"].Type)
+	require.Equal("", fields["Env[DB_PASSWORD]"].Old)
+	require.Equal(redactedEnvValue, fields["Env[DB_PASSWORD]"].New)
+}
+
+func TestRedactEventsEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job := mock.Job()
+	node := mock.Node()
+	events := &structs.Events{
+		Index: 10,
+		Events: []structs.Event{
+			{Topic: structs.TopicJob, Payload: &structs.JobEvent{Job: job}},
+			{Topic: structs.TopicNode, Payload: &structs.NodeStreamEvent{Node: node}},
+		},
+	}
+
+	require.True(events == redactEventsEnv(events, nil))
+	require.True(events == redactEventsEnv(events, []string{"DB_*"}))
+
+	out := redactEventsEnv(events, []string{"FOO"})
+	require.False(events == out)
+	require.Equal(uint64(10), out.Index)
+	require.Len(out.Events, 2)
+	redacted := out.Events[0].Payload.(*structs.JobEvent).Job
+	require.Equal(redactedEnvValue, redacted.TaskGroups[0].Tasks[0].Env["FOO"])
+	require.True(node == out.Events[1].Payload.(*structs.NodeStreamEvent).Node)
+
+	// The published events are untouched
+	require.True(job == events.Events[0].Payload.(*structs.JobEvent).Job)
+	require.Equal("bar", job.TaskGroups[0].Tasks[0].Env["FOO"])
+}
+
+func TestJobEndpoint_GetJob_RedactEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.RedactEnvVars = []string{"FOO"}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	get := &structs.JobSpecificRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Read-job tokens read the redacted values
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = token.SecretID
+	var resp structs.SingleJobResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJob", get, &resp))
+	require.Equal(redactedEnvValue, resp.Job.TaskGroups[0].Tasks[0].Env["FOO"])
+
+	// Management tokens read the values
+	get.AuthToken = root.SecretID
+	var mgmtResp structs.SingleJobResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJob", get, &mgmtResp))
+	require.Equal("bar", mgmtResp.Job.TaskGroups[0].Tasks[0].Env["FOO"])
+
+	// The stored job is untouched
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal("bar", out.TaskGroups[0].Tasks[0].Env["FOO"])
+}
+
+func TestAllocEndpoint_GetAlloc_RedactEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.RedactEnvVars = []string{"FOO"}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	get := &structs.AllocSpecificRequest{
+		AllocID:      alloc.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleAllocResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", get, &resp))
+	require.Equal(alloc.ID, resp.Alloc.ID)
+	require.Equal(redactedEnvValue, resp.Alloc.Job.TaskGroups[0].Tasks[0].Env["FOO"])
+}
@@ -56,6 +56,10 @@ type EvalBroker struct {
 	// and is used to eventually fail an evaluation.
 	evals map[string]int
 
+	// enqueuedAt tracks when queued evaluations were first enqueued by ID. It
+	// is used to report the age of the oldest evaluations.
+	enqueuedAt map[string]time.Time
+
 	// jobEvals tracks queued evaluations by a job's ID and namespace to serialize them
 	jobEvals map[structs.NamespacedID]string
 
@@ -133,6 +137,7 @@ func NewEvalBroker(timeout, initialNackDelay, subsequentNackDelay time.Duration,
 		enabled:              false,
 		stats:                new(BrokerStats),
 		evals:                make(map[string]int),
+		enqueuedAt:           make(map[string]time.Time),
 		jobEvals:             make(map[structs.NamespacedID]string),
 		blocked:              make(map[structs.NamespacedID]PendingEvaluations),
 		ready:                make(map[string]PendingEvaluations),
@@ -223,6 +228,7 @@ func (b *EvalBroker) processEnqueue(eval *structs.Evaluation, token string) {
 		return
 	} else if b.enabled {
 		b.evals[eval.ID] = 0
+		b.enqueuedAt[eval.ID] = time.Now()
 	}
 
 	// Check if we need to enforce a wait
@@ -554,6 +560,7 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	// Cleanup
 	delete(b.unack, evalID)
 	delete(b.evals, evalID)
+	delete(b.enqueuedAt, evalID)
 
 	namespacedID := structs.NamespacedID{
 		ID:        jobID,
@@ -714,6 +721,7 @@ func (b *EvalBroker) flush() {
 	b.stats.TotalWaiting = 0
 	b.stats.ByScheduler = make(map[string]*SchedulerStats)
 	b.evals = make(map[string]int)
+	b.enqueuedAt = make(map[string]time.Time)
 	b.jobEvals = make(map[structs.NamespacedID]string)
 	b.blocked = make(map[structs.NamespacedID]PendingEvaluations)
 	b.ready = make(map[string]PendingEvaluations)
@@ -814,6 +822,89 @@ func (b *EvalBroker) Stats() *BrokerStats {
 	return stats
 }
 
+// Ready returns the number of ready evaluations of the given schedulers.
+func (b *EvalBroker) Ready(schedulers []string) int {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	ready := 0
+	for _, sched := range schedulers {
+		if subStat, ok := b.stats.ByScheduler[sched]; ok {
+			ready += subStat.Ready
+		}
+	}
+	return ready
+}
+
+// Status returns a detailed view of the broker's state, including the age of
+// the oldest evaluations held for each scheduler.
+func (b *EvalBroker) Status() *structs.EvalBrokerStatus {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	status := &structs.EvalBrokerStatus{
+		Enabled:      b.enabled,
+		TotalReady:   b.stats.TotalReady,
+		TotalUnacked: b.stats.TotalUnacked,
+		TotalBlocked: b.stats.TotalBlocked,
+		TotalWaiting: b.stats.TotalWaiting,
+		ByScheduler:  make(map[string]*structs.EvalBrokerSchedulerStatus),
+	}
+
+	now := time.Now()
+	schedStatus := func(sched string) *structs.EvalBrokerSchedulerStatus {
+		s, ok := status.ByScheduler[sched]
+		if !ok {
+			s = &structs.EvalBrokerSchedulerStatus{}
+			status.ByScheduler[sched] = s
+		}
+		return s
+	}
+	age := func(evalID string) time.Duration {
+		if t, ok := b.enqueuedAt[evalID]; ok {
+			return now.Sub(t)
+		}
+		return 0
+	}
+
+	for sched, pending := range b.ready {
+		s := schedStatus(sched)
+		s.Ready = len(pending)
+		for _, eval := range pending {
+			if a := age(eval.ID); a > s.OldestReady {
+				s.OldestReady = a
+			}
+		}
+	}
+
+	for _, unack := range b.unack {
+		// Unacked evaluations that reached the delivery limit belong to the
+		// failed queue.
+		sched := unack.Eval.Type
+		if b.evals[unack.Eval.ID] > b.deliveryLimit {
+			sched = failedQueue
+		}
+
+		s := schedStatus(sched)
+		s.Unacked++
+		if a := age(unack.Eval.ID); a > s.OldestUnacked {
+			s.OldestUnacked = a
+		}
+	}
+
+	for _, blocked := range b.blocked {
+		for _, eval := range blocked {
+			schedStatus(eval.Type).Blocked++
+		}
+	}
+
+	if failed, ok := status.ByScheduler[failedQueue]; ok {
+		status.TotalFailed = failed.Ready + failed.Unacked
+	}
+
+	return status
+}
+
 // EmitStats is used to export metrics about the broker while enabled
 func (b *EvalBroker) EmitStats(period time.Duration, stopCh chan struct{}) {
 	for {
@@ -49,6 +49,46 @@ func testBrokerFromConfig(t *testing.T, c *Config) *EvalBroker {
 	return b
 }
 
+func TestEvalBroker_Status(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	// Enqueue two evals for the same job so one is blocked, and one for
+	// another scheduler
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	eval3 := mock.Eval()
+	eval3.Type = structs.JobTypeBatch
+	b.Enqueue(eval1)
+	b.Enqueue(eval2)
+	b.Enqueue(eval3)
+
+	status := b.Status()
+	require.True(status.Enabled)
+	require.Equal(2, status.TotalReady)
+	require.Equal(1, status.TotalBlocked)
+	require.Equal(1, status.ByScheduler[structs.JobTypeService].Ready)
+	require.Equal(1, status.ByScheduler[structs.JobTypeService].Blocked)
+	require.Equal(1, status.ByScheduler[structs.JobTypeBatch].Ready)
+	require.NotZero(status.ByScheduler[structs.JobTypeService].OldestReady)
+
+	// Dequeue the service eval
+	out, _, err := b.Dequeue([]string{structs.JobTypeService}, time.Second)
+	require.NoError(err)
+	require.Equal(eval1, out)
+
+	status = b.Status()
+	require.Equal(1, status.TotalUnacked)
+	require.Equal(0, status.ByScheduler[structs.JobTypeService].Ready)
+	require.Equal(1, status.ByScheduler[structs.JobTypeService].Unacked)
+	require.Zero(status.ByScheduler[structs.JobTypeService].OldestReady)
+	require.NotZero(status.ByScheduler[structs.JobTypeService].OldestUnacked)
+	require.Zero(status.TotalFailed)
+}
+
 func TestEvalBroker_Enqueue_Dequeue_Nack_Ack(t *testing.T) {
 	t.Parallel()
 	b := testBroker(t, 0)
@@ -123,6 +123,7 @@ func (e *Eval) Dequeue(args *structs.EvalDequeueRequest,
 		reply.Token = token
 		reply.WaitIndex = waitIndex
 	}
+	reply.Ready = e.srv.evalBroker.Ready(args.Schedulers)
 
 	// Set the query response
 	e.srv.setQueryMeta(&reply.QueryMeta)
@@ -304,6 +305,56 @@ func (e *Eval) Reblock(args *structs.EvalUpdateRequest, reply *structs.GenericRe
 	return nil
 }
 
+// Requeue is used to enqueue a blocked evaluation without waiting for node
+// capacity changes, so that a stuck evaluation can be retried without
+// updating its job.
+func (e *Eval) Requeue(args *structs.EvalRequeueRequest, reply *structs.GenericResponse) error {
+	if done, err := e.srv.forward("Eval.Requeue", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "requeue"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.EvalID == "" {
+		return fmt.Errorf("missing evaluation ID")
+	}
+
+	// Look for the eval
+	snap, err := e.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	eval, err := snap.EvalByID(ws, args.EvalID)
+	if err != nil {
+		return err
+	}
+	if eval == nil || eval.Namespace != args.RequestNamespace() {
+		return fmt.Errorf("evaluation %q not found", args.EvalID)
+	}
+	if eval.Status != structs.EvalStatusBlocked {
+		return fmt.Errorf("evaluation %q is not blocked, its status is %q", args.EvalID, eval.Status)
+	}
+
+	// Unblock the eval, or enqueue it directly if it was lost by the blocked
+	// evaluation tracker
+	if !e.srv.blockedEvals.UnblockEval(eval.ID) {
+		e.logger.Warn("requeued blocked evaluation wasn't tracked", "eval_id", eval.ID)
+		e.srv.evalBroker.Enqueue(eval)
+	}
+
+	reply.Index = eval.ModifyIndex
+	return nil
+}
+
 // Reap is used to cleanup dead evaluations and allocations
 func (e *Eval) Reap(args *structs.EvalDeleteRequest,
 	reply *structs.GenericResponse) error {
@@ -1120,3 +1120,97 @@ func TestEvalEndpoint_Reblock(t *testing.T) {
 		t.Fatalf("ReblockEval didn't insert eval into the blocked eval tracker")
 	}
 }
+
+func TestEvalEndpoint_Requeue(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return s1.evalBroker.Enabled() && s1.blockedEvals.Enabled(), nil
+	}, func(err error) {
+		t.Fatalf("should enable eval broker and blocked evals")
+	})
+
+	// Create a tracked and an untracked blocked eval, and a complete one
+	eval1 := mock.Eval()
+	eval1.Status = structs.EvalStatusBlocked
+	eval2 := mock.Eval()
+	eval2.Status = structs.EvalStatusBlocked
+	eval3 := mock.Eval()
+	eval3.Status = structs.EvalStatusComplete
+	require.NoError(s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1, eval2, eval3}))
+	s1.blockedEvals.Block(eval1)
+
+	req := &structs.EvalRequeueRequest{
+		EvalID:       eval1.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp))
+	require.EqualValues(1000, resp.Index)
+
+	req.EvalID = eval2.ID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp))
+
+	// Both evals are enqueued
+	require.Zero(s1.blockedEvals.Stats().TotalBlocked)
+	require.Equal(2, s1.evalBroker.Stats().TotalReady)
+
+	// Only blocked evals can be requeued
+	req.EvalID = eval3.ID
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "is not blocked")
+
+	req.EvalID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
+func TestEvalEndpoint_Requeue_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	eval1 := mock.Eval()
+	eval1.Status = structs.EvalStatusBlocked
+	state := s1.fsm.State()
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1}))
+
+	// Create ACL tokens
+	validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	req := &structs.EvalRequeueRequest{
+		EvalID:       eval1.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Try with no token and with an invalid token
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Requeue the eval with a valid token
+	req.AuthToken = validToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.Requeue", req, &resp))
+}
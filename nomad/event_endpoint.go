@@ -0,0 +1,226 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// eventStreamHeartbeat is the interval at which empty frames are sent
+	// on idle event streams so subscribers can detect dead connections.
+	eventStreamHeartbeat = 10 * time.Second
+)
+
+// Event endpoint is used to stream the events of the state store.
+type Event struct {
+	srv    *Server
+	logger log.Logger
+}
+
+func (e *Event) register() {
+	e.srv.streamingRpcs.Register("Event.Stream", e.stream)
+}
+
+// handleStreamResultError is a helper for sending an error with a potential
+// error code. The transmission of the error is ignored if the error has been
+// generated by the closing of the underlying transport.
+func (e *Event) handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
+	// Nothing to do as the conn is closed
+	if err == io.EOF || strings.Contains(err.Error(), "closed") {
+		return
+	}
+
+	// Attempt to send the error
+	encoder.Encode(&cstructs.StreamErrWrapper{
+		Error: cstructs.NewRpcError(err, code),
+	})
+}
+
+// stream streams the events matching the request as newline delimited JSON
+// payloads, with an empty object sent as heartbeat on idle streams.
+func (e *Event) stream(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	// Decode the arguments
+	var args structs.EventStreamRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		e.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != e.srv.Region() {
+		e.forwardRegion(conn, encoder, &args)
+		return
+	}
+
+	if len(args.Topics) == 0 {
+		args.Topics = map[structs.Topic][]string{structs.TopicAll: {"*"}}
+	}
+	for topic := range args.Topics {
+		switch topic {
+		case structs.TopicAll, structs.TopicJob, structs.TopicAllocation,
+			structs.TopicDeployment, structs.TopicNode:
+		default:
+			e.handleStreamResultError(fmt.Errorf("unknown topic %q", topic), helper.Int64ToPtr(400), encoder)
+			return
+		}
+	}
+
+	// Check the permissions to read the topics
+	ns := args.RequestNamespace()
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		e.handleStreamResultError(err, nil, encoder)
+		return
+	} else if !allowEventTopics(aclObj, ns, args.Topics) {
+		e.handleStreamResultError(structs.ErrPermissionDenied, helper.Int64ToPtr(403), encoder)
+		return
+	}
+	redact := e.srv.redactedEnvVars(aclObj)
+
+	sub := e.srv.eventBroker.Subscribe(&stream.SubscribeRequest{
+		Topics:    args.Topics,
+		Namespace: ns,
+		Index:     args.Index,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Nothing is read after the arguments, so reading returns once the
+	// subscriber closes the connection
+	go func() {
+		io.Copy(ioutil.Discard, conn)
+		cancel()
+	}()
+
+	eventsCh := make(chan *structs.Events)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			events, err := sub.Next(ctx)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case eventsCh <- events:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	// Send a heartbeat right away so the subscriber knows the stream is
+	// established before any event is published
+	payload := []byte("{}\n")
+	for {
+		if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: payload}); err != nil {
+			e.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		}
+
+		payload = nil
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.srv.shutdownCh:
+			return
+		case err := <-errCh:
+			if err != context.Canceled {
+				e.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			}
+			return
+		case <-heartbeat.C:
+			payload = []byte("{}\n")
+		case events := <-eventsCh:
+			events = redactEventsEnv(events, redact)
+			if err := codec.NewEncoderBytes(&payload, structs.JsonHandle).Encode(events); err != nil {
+				e.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+				return
+			}
+			payload = append(payload, '\n')
+		}
+	}
+}
+
+// forwardRegion bridges the stream to a server of the region of the
+// request.
+func (e *Event) forwardRegion(conn io.ReadWriteCloser, encoder *codec.Encoder, args *structs.EventStreamRequest) {
+	region := args.RequestRegion()
+	e.srv.peerLock.RLock()
+	servers := e.srv.peers[region]
+	if len(servers) == 0 {
+		e.srv.peerLock.RUnlock()
+		e.handleStreamResultError(structs.ErrNoRegionPath, nil, encoder)
+		return
+	}
+	server := servers[rand.Intn(len(servers))]
+	e.srv.peerLock.RUnlock()
+
+	srvConn, err := e.srv.streamingRpc(server, "Event.Stream")
+	if err != nil {
+		e.handleStreamResultError(err, nil, encoder)
+		return
+	}
+	defer srvConn.Close()
+
+	// Send the request.
+	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		e.handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	structs.Bridge(conn, srvConn)
+}
+
+// allowEventTopics returns whether the ACL allows reading the events of the
+// topics. Node events require node read permissions and the other topics
+// require read-job permissions in the namespace, while the events of every
+// namespace can only be read with a management token.
+func allowEventTopics(aclObj *acl.ACL, ns string, topics map[structs.Topic][]string) bool {
+	if aclObj == nil || aclObj.IsManagement() {
+		return true
+	}
+
+	readNode := aclObj.AllowNodeRead()
+	readJob := ns != "*" && aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob)
+	for topic := range topics {
+		switch topic {
+		case structs.TopicNode:
+			if !readNode {
+				return false
+			}
+		case structs.TopicAll:
+			if !readNode || !readJob {
+				return false
+			}
+		default:
+			if !readJob {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package nomad
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// testEventStream starts an event stream on the server and returns the
+// channel of its messages.
+func testEventStream(t *testing.T, s *Server, req *structs.EventStreamRequest) (<-chan *cstructs.StreamErrWrapper, func()) {
+	handler, err := s.StreamingRpcHandler("Event.Stream")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	go handler(p2)
+
+	msgCh := make(chan *cstructs.StreamErrWrapper, 10)
+	go func() {
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err != io.EOF && !strings.Contains(err.Error(), "closed") {
+					t.Errorf("error decoding: %v", err)
+				}
+				return
+			}
+			msgCh <- &msg
+		}
+	}()
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(req))
+	return msgCh, func() {
+		p1.Close()
+		p2.Close()
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	rpcCodec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	job := mock.Job()
+	msgCh, cleanup := testEventStream(t, s, &structs.EventStreamRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {job.ID}},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	})
+	defer cleanup()
+
+	// The stream starts with a heartbeat
+	select {
+	case msg := <-msgCh:
+		require.Nil(msg.Error)
+		require.Equal("{}\n", string(msg.Payload))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for heartbeat")
+	}
+
+	// Registering the job publishes an event
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(rpcCodec, "Job.Register", req, &resp))
+
+	select {
+	case msg := <-msgCh:
+		require.Nil(msg.Error)
+
+		var events struct {
+			Index  uint64
+			Events []struct {
+				Topic   structs.Topic
+				Type    string
+				Key     string
+				Payload map[string]map[string]interface{}
+			}
+		}
+		require.NoError(codec.NewDecoderBytes(msg.Payload, structs.JsonHandle).Decode(&events))
+		require.Equal(resp.JobModifyIndex, events.Index)
+		require.Len(events.Events, 1)
+		require.Equal(structs.TopicJob, events.Events[0].Topic)
+		require.Equal(structs.TypeJobRegistered, events.Events[0].Type)
+		require.Equal(job.ID, events.Events[0].Key)
+		require.Equal(job.ID, events.Events[0].Payload["Job"]["ID"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func TestEventStream_ACL(t *testing.T) {
+	t.Parallel()
+
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	policyJob := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	tokenJob := mock.CreatePolicyAndToken(t, s.State(), 1005, "job", policyJob)
+
+	policyNode := mock.NodePolicy(acl.PolicyRead)
+	tokenNode := mock.CreatePolicyAndToken(t, s.State(), 1007, "node", policyNode)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		Namespace     string
+		Topic         structs.Topic
+		ExpectedError string
+	}{
+		{
+			Name:          "job token reading jobs",
+			Token:         tokenJob.SecretID,
+			Topic:         structs.TopicJob,
+			ExpectedError: "",
+		},
+		{
+			Name:          "job token reading jobs of every namespace",
+			Token:         tokenJob.SecretID,
+			Namespace:     "*",
+			Topic:         structs.TopicJob,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "job token reading nodes",
+			Token:         tokenJob.SecretID,
+			Topic:         structs.TopicNode,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "node token reading every topic",
+			Token:         tokenNode.SecretID,
+			Topic:         structs.TopicAll,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "root token reading every topic",
+			Token:         root.SecretID,
+			Namespace:     "*",
+			Topic:         structs.TopicAll,
+			ExpectedError: "",
+		},
+		{
+			Name:          "unknown topic",
+			Token:         root.SecretID,
+			Topic:         structs.Topic("Unknown"),
+			ExpectedError: "unknown topic",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			ns := c.Namespace
+			if ns == "" {
+				ns = structs.DefaultNamespace
+			}
+			msgCh, cleanup := testEventStream(t, s, &structs.EventStreamRequest{
+				Topics: map[structs.Topic][]string{c.Topic: {"*"}},
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Namespace: ns,
+					AuthToken: c.Token,
+				},
+			})
+			defer cleanup()
+
+			select {
+			case msg := <-msgCh:
+				if c.ExpectedError == "" {
+					require.Nil(t, msg.Error)
+					return
+				}
+				require.NotNil(t, msg.Error)
+				require.Contains(t, msg.Error.Error(), c.ExpectedError, fmt.Sprintf("bad error: %v", msg.Error))
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package nomad
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/consul/api"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// externalDNSMinSyncInterval is the minimum time between two syncs, so
+	// that bursts of registration changes are published together.
+	externalDNSMinSyncInterval = 5 * time.Second
+
+	// defaultCoreDNSPathPrefix is the etcd key prefix CoreDNS reads records
+	// from by default.
+	defaultCoreDNSPathPrefix = "/skydns"
+)
+
+// validDNSLabel matches the service names and namespaces that can be used as
+// a DNS label.
+var validDNSLabel = regexp.MustCompile("^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$")
+
+// externalDNSRecord is the address of a service registration published to
+// the external DNS provider.
+type externalDNSRecord struct {
+	// Name is the fully qualified name of the record without a trailing dot:
+	// <service>.<namespace>.<zone>
+	Name string
+
+	// ID is the ID of the service registration.
+	ID string
+
+	Address string
+	Port    int
+}
+
+// externalDNSProvider is a DNS provider the records are published to.
+type externalDNSProvider interface {
+	// Sync makes the records the provider serves below the zone match the
+	// given records, creating, updating and deleting records as needed.
+	Sync(records []*externalDNSRecord) error
+
+	// String returns a description of the provider for logging.
+	String() string
+}
+
+// externalDNS publishes the addresses of the services registered with the
+// Nomad service provider to an external DNS provider while the server is the
+// leader.
+type externalDNS struct {
+	config   *config.ExternalDNSConfig
+	zone     string
+	provider externalDNSProvider
+	logger   log.Logger
+}
+
+// newExternalDNS returns an external DNS controller for the given
+// configuration.
+func newExternalDNS(c *config.ExternalDNSConfig, logger log.Logger) (*externalDNS, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	e := &externalDNS{
+		config: c,
+		zone:   strings.ToLower(strings.TrimSuffix(c.Zone, ".")),
+		logger: logger.Named("external_dns"),
+	}
+
+	switch {
+	case c.Route53 != nil:
+		e.provider = newRoute53DNSProvider(c.Route53, e.zone, c.TTL)
+	case c.CoreDNS != nil:
+		e.provider = newCoreDNSProvider(c.CoreDNS, e.zone, c.TTL)
+	}
+
+	return e, nil
+}
+
+// run publishes the records whenever the service registrations change, and
+// at least every interval, until the stop channel is closed.
+func (e *externalDNS) run(stopCh chan struct{}, stateFn func() *state.StateStore) {
+	resync := time.NewTicker(e.config.Interval)
+	defer resync.Stop()
+
+	for {
+		store := stateFn()
+		ws := memdb.NewWatchSet()
+		ws.Add(store.AbandonCh())
+		ws.Add(stopCh)
+
+		if err := e.sync(ws, store); err != nil {
+			e.logger.Error("failed to publish services", "provider", e.provider, "error", err)
+		}
+
+		ws.Watch(resync.C)
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(externalDNSMinSyncInterval):
+		}
+	}
+}
+
+// sync publishes the records of the current service registrations.
+func (e *externalDNS) sync(ws memdb.WatchSet, store *state.StateStore) error {
+	defer metrics.MeasureSince([]string{"nomad", "external_dns", "sync"}, time.Now())
+
+	records, err := externalDNSRecords(ws, store, e.zone)
+	if err != nil {
+		return err
+	}
+	return e.provider.Sync(records)
+}
+
+// externalDNSRecords returns the records of the service registrations that
+// are not critical. Registrations whose service name or namespace is not a
+// valid DNS label, or whose address is not an IP address, are skipped.
+func externalDNSRecords(ws memdb.WatchSet, store *state.StateStore, zone string) ([]*externalDNSRecord, error) {
+	iter, err := store.ServiceRegistrations(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*externalDNSRecord
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		reg := raw.(*structs.ServiceRegistration)
+		if reg.Status == api.HealthCritical {
+			continue
+		}
+
+		service := strings.ToLower(reg.ServiceName)
+		namespace := strings.ToLower(reg.Namespace)
+		if !validDNSLabel.MatchString(service) || !validDNSLabel.MatchString(namespace) {
+			continue
+		}
+		if net.ParseIP(reg.Address) == nil {
+			continue
+		}
+
+		records = append(records, &externalDNSRecord{
+			Name:    service + "." + namespace + "." + zone,
+			ID:      reg.ID,
+			Address: reg.Address,
+			Port:    reg.Port,
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Name != records[j].Name {
+			return records[i].Name < records[j].Name
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+// isExternalDNSRecordName returns whether the name is of the form
+// <service>.<namespace>.<zone>, which are the only names that are managed.
+func isExternalDNSRecordName(name, zone string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if !strings.HasSuffix(name, "."+zone) {
+		return false
+	}
+	return strings.Count(strings.TrimSuffix(name, "."+zone), ".") == 1
+}
+
+// coreDNSProvider publishes records to the etcd backend of CoreDNS, using
+// the JSON gateway of the etcd v3 API. Each registration is stored as a
+// SkyDNS style record below the key of its name, so CoreDNS serves both A,
+// AAAA and SRV records for it.
+type coreDNSProvider struct {
+	address string
+	prefix  string
+	zone    string
+	ttl     int
+	client  *http.Client
+}
+
+// coreDNSRecord is the value of a record in etcd.
+type coreDNSRecord struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"`
+	TTL  int    `json:"ttl,omitempty"`
+}
+
+func newCoreDNSProvider(c *config.ExternalDNSCoreDNSConfig, zone string, ttl time.Duration) *coreDNSProvider {
+	prefix := strings.TrimSuffix(c.PathPrefix, "/")
+	if prefix == "" {
+		prefix = defaultCoreDNSPathPrefix
+	}
+
+	return &coreDNSProvider{
+		address: strings.TrimSuffix(c.EtcdAddress, "/"),
+		prefix:  prefix,
+		zone:    zone,
+		ttl:     int(ttl / time.Second),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// coreDNSPath returns the etcd path of a name: its labels in reverse order.
+func coreDNSPath(name string) string {
+	labels := strings.Split(name, ".")
+	var path string
+	for i := len(labels) - 1; i >= 0; i-- {
+		path += "/" + labels[i]
+	}
+	return path
+}
+
+func (p *coreDNSProvider) Sync(records []*externalDNSRecord) error {
+	// Build the desired keys. The key of a record is the path of its name
+	// followed by a hash of the registration ID, which may contain slashes.
+	desired := make(map[string]string, len(records))
+	for _, r := range records {
+		value, err := json.Marshal(&coreDNSRecord{Host: r.Address, Port: r.Port, TTL: p.ttl})
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256([]byte(r.ID))
+		key := p.prefix + coreDNSPath(r.Name) + "/" + hex.EncodeToString(sum[:8])
+		desired[key] = string(value)
+	}
+
+	zonePrefix := p.prefix + coreDNSPath(p.zone) + "/"
+	current, err := p.rangePrefix(zonePrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list records: %v", err)
+	}
+
+	for key := range current {
+		// Only keys of the form <zone>/<namespace>/<service>/<hash> are
+		// managed
+		if strings.Count(strings.TrimPrefix(key, zonePrefix), "/") != 2 {
+			continue
+		}
+		if _, ok := desired[key]; !ok {
+			if err := p.call("deleterange", map[string]string{"key": etcdBytes(key)}, nil); err != nil {
+				return fmt.Errorf("failed to delete record %q: %v", key, err)
+			}
+		}
+	}
+	for key, value := range desired {
+		if current[key] == value {
+			continue
+		}
+		args := map[string]string{"key": etcdBytes(key), "value": etcdBytes(value)}
+		if err := p.call("put", args, nil); err != nil {
+			return fmt.Errorf("failed to put record %q: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// rangePrefix returns the keys and values below the prefix.
+func (p *coreDNSProvider) rangePrefix(prefix string) (map[string]string, error) {
+	// The range end of a prefix is the prefix with its last byte incremented
+	end := []byte(prefix)
+	end[len(end)-1]++
+
+	args := map[string]string{
+		"key":       etcdBytes(prefix),
+		"range_end": base64.StdEncoding.EncodeToString(end),
+	}
+	var resp struct {
+		Kvs []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	if err := p.call("range", args, &resp); err != nil {
+		return nil, err
+	}
+
+	kvs := make(map[string]string, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		kvs[string(key)] = string(value)
+	}
+	return kvs, nil
+}
+
+// call POSTs the arguments to the etcd KV API method and decodes the reply
+// into out if it is non-nil.
+func (p *coreDNSProvider) call(method string, args interface{}, out interface{}) error {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Post(p.address+"/v3/kv/"+method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (p *coreDNSProvider) String() string {
+	return "coredns " + p.address
+}
+
+// etcdBytes encodes a string as the bytes of the etcd JSON gateway.
+func etcdBytes(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
@@ -0,0 +1,284 @@
+package nomad
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol/restxml"
+	"github.com/aws/aws-sdk-go/private/signer/v4"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// route53ServiceName and route53APIVersion identify the Route 53 API.
+	route53ServiceName = "route53"
+	route53APIVersion  = "2013-04-01"
+
+	// route53MaxChangesPerBatch is the number of changes sent in a single
+	// ChangeResourceRecordSets request, well below the API's limit.
+	route53MaxChangesPerBatch = 100
+)
+
+// route53DNSProvider publishes records to an AWS Route 53 hosted zone. The
+// addresses of each name are published as an A and an AAAA record set.
+type route53DNSProvider struct {
+	client       *client.Client
+	hostedZoneID string
+	zone         string
+	ttl          int64
+}
+
+func newRoute53DNSProvider(c *config.ExternalDNSRoute53Config, zone string, ttl time.Duration) *route53DNSProvider {
+	// Route 53 is a global service signed in us-east-1
+	awsConfig := aws.NewConfig().WithRegion("us-east-1")
+	if c.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(c.Endpoint)
+	}
+	if c.AccessKeyID != "" || c.SecretAccessKey != "" {
+		awsConfig = awsConfig.WithCredentials(
+			credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, ""))
+	}
+
+	// The vendored SDK doesn't include the Route 53 service, so build its
+	// client from the generic REST XML protocol.
+	cc := session.New(awsConfig).ClientConfig(route53ServiceName)
+	svc := client.New(
+		*cc.Config,
+		metadata.ClientInfo{
+			ServiceName:   route53ServiceName,
+			SigningRegion: cc.SigningRegion,
+			Endpoint:      cc.Endpoint,
+			APIVersion:    route53APIVersion,
+		},
+		cc.Handlers,
+	)
+	svc.Handlers.Sign.PushBack(v4.Sign)
+	svc.Handlers.Build.PushBack(restxml.Build)
+	svc.Handlers.Unmarshal.PushBack(restxml.Unmarshal)
+	svc.Handlers.UnmarshalMeta.PushBack(restxml.UnmarshalMeta)
+	svc.Handlers.UnmarshalError.PushBack(restxml.UnmarshalError)
+
+	return &route53DNSProvider{
+		client:       svc,
+		hostedZoneID: strings.TrimPrefix(c.HostedZoneID, "/hostedzone/"),
+		zone:         zone,
+		ttl:          int64(ttl / time.Second),
+	}
+}
+
+func (p *route53DNSProvider) Sync(records []*externalDNSRecord) error {
+	desired := make(map[string]*route53ResourceRecordSet)
+	for _, r := range records {
+		recordType := "A"
+		if net.ParseIP(r.Address).To4() == nil {
+			recordType = "AAAA"
+		}
+
+		name := r.Name + "."
+		set, ok := desired[recordType+" "+name]
+		if !ok {
+			set = &route53ResourceRecordSet{
+				Name: aws.String(name),
+				Type: aws.String(recordType),
+				TTL:  aws.Int64(p.ttl),
+			}
+			desired[recordType+" "+name] = set
+		}
+
+		// Many registrations may share an address
+		if !route53RecordSetContains(set, r.Address) {
+			set.ResourceRecords = append(set.ResourceRecords, &route53ResourceRecord{Value: aws.String(r.Address)})
+		}
+	}
+
+	current, err := p.listRecordSets()
+	if err != nil {
+		return fmt.Errorf("failed to list records: %v", err)
+	}
+
+	var changes []*route53Change
+	for key, set := range current {
+		if _, ok := desired[key]; !ok {
+			changes = append(changes, &route53Change{Action: aws.String("DELETE"), ResourceRecordSet: set})
+		}
+	}
+	for key, set := range desired {
+		if existing, ok := current[key]; ok && route53RecordSetsEqual(existing, set) {
+			continue
+		}
+		changes = append(changes, &route53Change{Action: aws.String("UPSERT"), ResourceRecordSet: set})
+	}
+
+	for len(changes) > 0 {
+		n := len(changes)
+		if n > route53MaxChangesPerBatch {
+			n = route53MaxChangesPerBatch
+		}
+		if err := p.changeRecordSets(changes[:n]); err != nil {
+			return fmt.Errorf("failed to change records: %v", err)
+		}
+		changes = changes[n:]
+	}
+	return nil
+}
+
+// listRecordSets returns the managed A and AAAA record sets of the hosted
+// zone, keyed by their type and name.
+func (p *route53DNSProvider) listRecordSets() (map[string]*route53ResourceRecordSet, error) {
+	sets := make(map[string]*route53ResourceRecordSet)
+	input := &route53ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(p.hostedZoneID),
+	}
+
+	for {
+		output := &route53ListResourceRecordSetsOutput{}
+		op := &request.Operation{
+			Name:       "ListResourceRecordSets",
+			HTTPMethod: "GET",
+			HTTPPath:   "/" + route53APIVersion + "/hostedzone/{Id}/rrset",
+		}
+		if err := p.client.NewRequest(op, input, output).Send(); err != nil {
+			return nil, err
+		}
+
+		for _, set := range output.ResourceRecordSets {
+			name, recordType := aws.StringValue(set.Name), aws.StringValue(set.Type)
+			if recordType != "A" && recordType != "AAAA" {
+				continue
+			}
+			if !isExternalDNSRecordName(name, p.zone) {
+				continue
+			}
+			sets[recordType+" "+strings.ToLower(name)] = set
+		}
+
+		if !aws.BoolValue(output.IsTruncated) {
+			return sets, nil
+		}
+		input.StartRecordName = output.NextRecordName
+		input.StartRecordType = output.NextRecordType
+	}
+}
+
+// changeRecordSets applies a batch of changes to the hosted zone.
+func (p *route53DNSProvider) changeRecordSets(changes []*route53Change) error {
+	input := &route53ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(p.hostedZoneID),
+		ChangeBatch: &route53ChangeBatch{
+			Comment: aws.String("Nomad services"),
+			Changes: changes,
+		},
+	}
+	op := &request.Operation{
+		Name:       "ChangeResourceRecordSets",
+		HTTPMethod: "POST",
+		HTTPPath:   "/" + route53APIVersion + "/hostedzone/{Id}/rrset/",
+	}
+	return p.client.NewRequest(op, input, &route53ChangeResourceRecordSetsOutput{}).Send()
+}
+
+func (p *route53DNSProvider) String() string {
+	return "route53 " + p.hostedZoneID
+}
+
+// route53RecordSetContains returns whether the record set contains the value.
+func route53RecordSetContains(set *route53ResourceRecordSet, value string) bool {
+	for _, r := range set.ResourceRecords {
+		if aws.StringValue(r.Value) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// route53RecordSetsEqual returns whether the record sets have the same TTL and
+// values, in any order.
+func route53RecordSetsEqual(a, b *route53ResourceRecordSet) bool {
+	if aws.Int64Value(a.TTL) != aws.Int64Value(b.TTL) || len(a.ResourceRecords) != len(b.ResourceRecords) {
+		return false
+	}
+
+	values := func(set *route53ResourceRecordSet) []string {
+		out := make([]string, 0, len(set.ResourceRecords))
+		for _, r := range set.ResourceRecords {
+			out = append(out, aws.StringValue(r.Value))
+		}
+		sort.Strings(out)
+		return out
+	}
+	av, bv := values(a), values(b)
+	for i := range av {
+		if av[i] != bv[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// The following types mirror the shapes of the Route 53 API that are used.
+
+type route53ListResourceRecordSetsInput struct {
+	_ struct{} `type:"structure"`
+
+	HostedZoneId    *string `location:"uri" locationName:"Id" type:"string" required:"true"`
+	StartRecordName *string `location:"querystring" locationName:"name" type:"string"`
+	StartRecordType *string `location:"querystring" locationName:"type" type:"string"`
+}
+
+type route53ListResourceRecordSetsOutput struct {
+	_ struct{} `type:"structure"`
+
+	IsTruncated        *bool                       `type:"boolean" required:"true"`
+	NextRecordName     *string                     `type:"string"`
+	NextRecordType     *string                     `type:"string"`
+	ResourceRecordSets []*route53ResourceRecordSet `locationNameList:"ResourceRecordSet" type:"list" required:"true"`
+}
+
+type route53ChangeResourceRecordSetsInput struct {
+	_ struct{} `locationName:"ChangeResourceRecordSetsRequest" type:"structure" xmlURI:"https://route53.amazonaws.com/doc/2013-04-01/"`
+
+	ChangeBatch  *route53ChangeBatch `type:"structure" required:"true"`
+	HostedZoneId *string             `location:"uri" locationName:"Id" type:"string" required:"true"`
+}
+
+type route53ChangeResourceRecordSetsOutput struct {
+	_ struct{} `type:"structure"`
+}
+
+type route53ChangeBatch struct {
+	_ struct{} `type:"structure"`
+
+	Changes []*route53Change `locationNameList:"Change" type:"list" required:"true"`
+	Comment *string          `type:"string"`
+}
+
+type route53Change struct {
+	_ struct{} `type:"structure"`
+
+	Action            *string                   `type:"string" required:"true"`
+	ResourceRecordSet *route53ResourceRecordSet `type:"structure" required:"true"`
+}
+
+type route53ResourceRecordSet struct {
+	_ struct{} `type:"structure"`
+
+	Name            *string                  `type:"string" required:"true"`
+	Type            *string                  `type:"string" required:"true"`
+	TTL             *int64                   `type:"long"`
+	ResourceRecords []*route53ResourceRecord `locationNameList:"ResourceRecord" type:"list"`
+}
+
+type route53ResourceRecord struct {
+	_ struct{} `type:"structure"`
+
+	Value *string `type:"string" required:"true"`
+}
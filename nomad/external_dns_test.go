@@ -0,0 +1,267 @@
+package nomad
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDNSRecords(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	store := state.TestStateStore(t)
+
+	alloc := mock.Alloc()
+	require.NoError(store.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(store.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	passing := mock.ServiceRegistration(alloc)
+	passing.ServiceName = "Web"
+
+	critical := mock.ServiceRegistration(alloc)
+	critical.ServiceName = "api"
+	critical.Status = "critical"
+
+	invalidName := mock.ServiceRegistration(alloc)
+	invalidName.ServiceName = "web_admin"
+
+	hostname := mock.ServiceRegistration(alloc)
+	hostname.ServiceName = "db"
+	hostname.Address = "db.internal"
+
+	regs := []*structs.ServiceRegistration{passing, critical, invalidName, hostname}
+	require.NoError(store.UpsertServiceRegistrations(1001, regs))
+
+	records, err := externalDNSRecords(memdb.NewWatchSet(), store, "nomad.example.com")
+	require.NoError(err)
+	require.Equal([]*externalDNSRecord{
+		{
+			Name:    "web.default.nomad.example.com",
+			ID:      passing.ID,
+			Address: passing.Address,
+			Port:    passing.Port,
+		},
+	}, records)
+}
+
+func TestIsExternalDNSRecordName(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	zone := "nomad.example.com"
+	require.True(isExternalDNSRecordName("web.default.nomad.example.com.", zone))
+	require.True(isExternalDNSRecordName("WEB.default.nomad.example.com", zone))
+	require.False(isExternalDNSRecordName("nomad.example.com.", zone))
+	require.False(isExternalDNSRecordName("default.nomad.example.com.", zone))
+	require.False(isExternalDNSRecordName("a.web.default.nomad.example.com.", zone))
+	require.False(isExternalDNSRecordName("web.default.example.com.", zone))
+}
+
+// fakeEtcd implements the parts of the etcd v3 JSON gateway used by the
+// CoreDNS provider.
+type fakeEtcd struct {
+	sync.Mutex
+	kvs map[string]string
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	var args map[string]string
+	if err := json.NewDecoder(req.Body).Decode(&args); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	decode := func(s string) string {
+		b, _ := base64.StdEncoding.DecodeString(s)
+		return string(b)
+	}
+	encode := func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	}
+
+	switch req.URL.Path {
+	case "/v3/kv/range":
+		key, end := decode(args["key"]), decode(args["range_end"])
+		var kvs []map[string]string
+		for k, v := range f.kvs {
+			if k >= key && k < end {
+				kvs = append(kvs, map[string]string{"key": encode(k), "value": encode(v)})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"kvs": kvs})
+	case "/v3/kv/put":
+		f.kvs[decode(args["key"])] = decode(args["value"])
+		w.Write([]byte("{}"))
+	case "/v3/kv/deleterange":
+		delete(f.kvs, decode(args["key"]))
+		w.Write([]byte("{}"))
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func TestCoreDNSProvider_Sync(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	etcd := &fakeEtcd{
+		kvs: map[string]string{
+			// A stale record, which is deleted
+			"/skydns/com/example/nomad/default/old/0000000000000000": `{"host":"10.0.0.9"}`,
+
+			// Records that aren't managed by Nomad
+			"/skydns/com/example/nomad/static": `{"host":"10.0.0.10"}`,
+			"/skydns/com/example/www":          `{"host":"10.0.0.11"}`,
+		},
+	}
+	srv := httptest.NewServer(etcd)
+	defer srv.Close()
+
+	p := newCoreDNSProvider(&config.ExternalDNSCoreDNSConfig{EtcdAddress: srv.URL}, "nomad.example.com", 30*time.Second)
+	records := []*externalDNSRecord{
+		{Name: "web.default.nomad.example.com", ID: "a", Address: "10.0.0.1", Port: 8080},
+		{Name: "web.default.nomad.example.com", ID: "b", Address: "10.0.0.2", Port: 8080},
+	}
+	require.NoError(p.Sync(records))
+
+	etcd.Lock()
+	kvs := make(map[string]string, len(etcd.kvs))
+	var keys []string
+	for k, v := range etcd.kvs {
+		kvs[k] = v
+		keys = append(keys, k)
+	}
+	etcd.Unlock()
+
+	require.Len(keys, 4)
+	require.Contains(keys, "/skydns/com/example/nomad/static")
+	require.Contains(keys, "/skydns/com/example/www")
+	require.NotContains(keys, "/skydns/com/example/nomad/default/old/0000000000000000")
+
+	var hosts []string
+	for _, k := range keys {
+		if !strings.HasPrefix(k, "/skydns/com/example/nomad/default/web/") {
+			continue
+		}
+		var r coreDNSRecord
+		require.NoError(json.Unmarshal([]byte(kvs[k]), &r))
+		require.Equal(8080, r.Port)
+		require.Equal(30, r.TTL)
+		hosts = append(hosts, r.Host)
+	}
+	require.ElementsMatch([]string{"10.0.0.1", "10.0.0.2"}, hosts)
+
+	// Syncing again makes no changes
+	require.NoError(p.Sync(records))
+	etcd.Lock()
+	require.Len(etcd.kvs, 4)
+	etcd.Unlock()
+}
+
+func TestRoute53DNSProvider_Sync(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	listResponse := `<?xml version="1.0" encoding="UTF-8"?>
+<ListResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+  <ResourceRecordSets>
+    <ResourceRecordSet>
+      <Name>old.default.nomad.example.com.</Name>
+      <Type>A</Type>
+      <TTL>30</TTL>
+      <ResourceRecords>
+        <ResourceRecord><Value>10.0.0.9</Value></ResourceRecord>
+      </ResourceRecords>
+    </ResourceRecordSet>
+    <ResourceRecordSet>
+      <Name>www.example.com.</Name>
+      <Type>A</Type>
+      <TTL>300</TTL>
+      <ResourceRecords>
+        <ResourceRecord><Value>10.0.0.11</Value></ResourceRecord>
+      </ResourceRecords>
+    </ResourceRecordSet>
+  </ResourceRecordSets>
+  <IsTruncated>false</IsTruncated>
+  <MaxItems>100</MaxItems>
+</ListResourceRecordSetsResponse>`
+
+	var lock sync.Mutex
+	var changeBodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, "/2013-04-01/hostedzone/Z123/rrset") {
+			http.NotFound(w, req)
+			return
+		}
+		switch req.Method {
+		case "GET":
+			fmt.Fprint(w, listResponse)
+		case "POST":
+			body, _ := ioutil.ReadAll(req.Body)
+			lock.Lock()
+			changeBodies = append(changeBodies, string(body))
+			lock.Unlock()
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><ChangeResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><ChangeInfo><Id>/change/C1</Id><Status>PENDING</Status></ChangeInfo></ChangeResourceRecordSetsResponse>`)
+		}
+	}))
+	defer srv.Close()
+
+	p := newRoute53DNSProvider(&config.ExternalDNSRoute53Config{
+		HostedZoneID:    "/hostedzone/Z123",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKIAEXAMPLE",
+		SecretAccessKey: "secret",
+	}, "nomad.example.com", 30*time.Second)
+
+	records := []*externalDNSRecord{
+		{Name: "web.default.nomad.example.com", ID: "a", Address: "10.0.0.1", Port: 8080},
+		{Name: "web.default.nomad.example.com", ID: "b", Address: "10.0.0.1", Port: 8081},
+		{Name: "web.default.nomad.example.com", ID: "c", Address: "fd00::1", Port: 8080},
+	}
+	require.NoError(p.Sync(records))
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.Len(changeBodies, 1)
+
+	var batch struct {
+		Changes []struct {
+			Action string `xml:"Action"`
+			Set    struct {
+				Name   string   `xml:"Name"`
+				Type   string   `xml:"Type"`
+				TTL    int      `xml:"TTL"`
+				Values []string `xml:"ResourceRecords>ResourceRecord>Value"`
+			} `xml:"ResourceRecordSet"`
+		} `xml:"ChangeBatch>Changes>Change"`
+	}
+	require.NoError(xml.Unmarshal([]byte(changeBodies[0]), &batch))
+
+	changes := make(map[string][]string)
+	for _, c := range batch.Changes {
+		key := c.Action + " " + c.Set.Type + " " + c.Set.Name
+		changes[key] = c.Set.Values
+	}
+	require.Equal(map[string][]string{
+		"DELETE A old.default.nomad.example.com.":    {"10.0.0.9"},
+		"UPSERT A web.default.nomad.example.com.":    {"10.0.0.1"},
+		"UPSERT AAAA web.default.nomad.example.com.": {"fd00::1"},
+	}, changes)
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"reflect"
@@ -8,10 +9,13 @@ import (
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	"github.com/golang/snappy"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
@@ -45,6 +49,16 @@ const (
 	ACLPolicySnapshot
 	ACLTokenSnapshot
 	SchedulerConfigSnapshot
+	VariableSnapshot
+	RootKeySnapshot
+	ServiceRegistrationSnapshot
+	ACLAuthMethodSnapshot
+	ACLRoleSnapshot
+	ACLBindingRuleSnapshot
+	NodePoolSnapshot
+	JobSubmissionSnapshot
+	OneTimeTokenSnapshot
+	CompressedJobVersionSnapshot
 )
 
 // LogApplier is the definition of a function that can apply a Raft log
@@ -68,6 +82,7 @@ type nomadFSM struct {
 	evalBroker         *EvalBroker
 	blockedEvals       *BlockedEvals
 	periodicDispatcher *PeriodicDispatch
+	eventBroker        *stream.EventBroker
 	logger             log.Logger
 	state              *state.StateStore
 	timetable          *TimeTable
@@ -94,6 +109,9 @@ type nomadFSM struct {
 type nomadSnapshot struct {
 	snap      *state.StateSnapshot
 	timetable *TimeTable
+
+	// compressJobVersions is whether the job versions are written compressed
+	compressJobVersions bool
 }
 
 // snapshotHeader is the first entry in our snapshot
@@ -113,11 +131,20 @@ type FSMConfig struct {
 	// be added to.
 	Blocked *BlockedEvals
 
+	// EventBroker is the broker the events of applied Raft logs are
+	// published to. Events are not published if it is nil.
+	EventBroker *stream.EventBroker
+
 	// Logger is the logger used by the FSM
 	Logger log.Logger
 
 	// Region is the region of the server embedding the FSM
 	Region string
+
+	// CompressJobVersions returns whether snapshots may write the job
+	// versions compressed, which servers older than 0.9.0 can't restore. Job
+	// versions are written uncompressed if it is nil.
+	CompressJobVersions func() bool
 }
 
 // NewFSMPath is used to construct a new FSM with a blank state
@@ -136,6 +163,7 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		evalBroker:          config.EvalBroker,
 		periodicDispatcher:  config.Periodic,
 		blockedEvals:        config.Blocked,
+		eventBroker:         config.EventBroker,
 		logger:              config.Logger.Named("fsm"),
 		config:              config,
 		state:               state,
@@ -249,6 +277,40 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyBatchDrainUpdate(buf[1:], log.Index)
 	case structs.SchedulerConfigRequestType:
 		return n.applySchedulerConfigUpdate(buf[1:], log.Index)
+	case structs.VariableUpsertRequestType:
+		return n.applyVariableUpsert(buf[1:], log.Index)
+	case structs.VariableDeleteRequestType:
+		return n.applyVariableDelete(buf[1:], log.Index)
+	case structs.RootKeyUpsertRequestType:
+		return n.applyRootKeyUpsert(buf[1:], log.Index)
+	case structs.ServiceRegistrationUpsertRequestType:
+		return n.applyServiceRegistrationUpsert(buf[1:], log.Index)
+	case structs.ServiceRegistrationDeleteByIDRequestType:
+		return n.applyServiceRegistrationDeleteByID(buf[1:], log.Index)
+	case structs.ACLAuthMethodUpsertRequestType:
+		return n.applyACLAuthMethodUpsert(buf[1:], log.Index)
+	case structs.ACLAuthMethodDeleteRequestType:
+		return n.applyACLAuthMethodDelete(buf[1:], log.Index)
+	case structs.ACLRoleUpsertRequestType:
+		return n.applyACLRoleUpsert(buf[1:], log.Index)
+	case structs.ACLRoleDeleteRequestType:
+		return n.applyACLRoleDelete(buf[1:], log.Index)
+	case structs.ACLBindingRuleUpsertRequestType:
+		return n.applyACLBindingRuleUpsert(buf[1:], log.Index)
+	case structs.ACLBindingRuleDeleteRequestType:
+		return n.applyACLBindingRuleDelete(buf[1:], log.Index)
+	case structs.NodePoolUpsertRequestType:
+		return n.applyNodePoolUpsert(buf[1:], log.Index)
+	case structs.NodePoolDeleteRequestType:
+		return n.applyNodePoolDelete(buf[1:], log.Index)
+	case structs.JobPinVersionRequestType:
+		return n.applyJobPinVersion(buf[1:], log.Index)
+	case structs.OneTimeTokenUpsertRequestType:
+		return n.applyOneTimeTokenUpsert(buf[1:], log.Index)
+	case structs.OneTimeTokenExchangeRequestType:
+		return n.applyOneTimeTokenExchange(buf[1:], log.Index)
+	case structs.OneTimeTokenExpireRequestType:
+		return n.applyOneTimeTokenExpire(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -280,6 +342,10 @@ func (n *nomadFSM) applyUpsertNode(buf []byte, index uint64) interface{} {
 		return err
 	}
 
+	n.publishEvents(index, func() []structs.Event {
+		return n.nodeEvents(structs.TypeNodeRegistration, req.Node.ID)
+	})
+
 	// Unblock evals for the nodes computed node class if it is in a ready
 	// state.
 	if req.Node.Status == structs.NodeStatusReady {
@@ -300,6 +366,10 @@ func (n *nomadFSM) applyDeregisterNode(buf []byte, index uint64) interface{} {
 		n.logger.Error("DeleteNode failed", "error", err)
 		return err
 	}
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.nodeEvents(structs.TypeNodeDeregistration, req.NodeID)
+	})
 	return nil
 }
 
@@ -310,11 +380,15 @@ func (n *nomadFSM) applyStatusUpdate(buf []byte, index uint64) interface{} {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.UpdateNodeStatus(index, req.NodeID, req.Status, req.NodeEvent); err != nil {
+	if err := n.state.UpdateNodeStatus(index, req.NodeID, req.Status, req.UpdatedAt, req.NodeEvent); err != nil {
 		n.logger.Error("UpdateNodeStatus failed", "error", err)
 		return err
 	}
 
+	n.publishEvents(index, func() []structs.Event {
+		return n.nodeEvents(structs.TypeNodeStatusUpdate, req.NodeID)
+	})
+
 	// Unblock evals for the nodes computed node class if it is in a ready
 	// state.
 	if req.Status == structs.NodeStatusReady {
@@ -356,6 +430,10 @@ func (n *nomadFSM) applyDrainUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpdateNodeDrain failed", "error", err)
 		return err
 	}
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.nodeEvents(structs.TypeNodeDrain, req.NodeID)
+	})
 	return nil
 }
 
@@ -370,6 +448,14 @@ func (n *nomadFSM) applyBatchDrainUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("BatchUpdateNodeDrain failed", "error", err)
 		return err
 	}
+
+	n.publishEvents(index, func() []structs.Event {
+		nodeIDs := make([]string, 0, len(req.Updates))
+		for nodeID := range req.Updates {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+		return n.nodeEvents(structs.TypeNodeDrain, nodeIDs...)
+	})
 	return nil
 }
 
@@ -387,11 +473,15 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(buf []byte, index uint64) interfac
 		return err
 	}
 
-	if err := n.state.UpdateNodeEligibility(index, req.NodeID, req.Eligibility, req.NodeEvent); err != nil {
+	if err := n.state.UpdateNodeEligibility(index, req.NodeID, req.Eligibility, req.IneligibleUntil, req.NodeEvent); err != nil {
 		n.logger.Error("UpdateNodeEligibility failed", "error", err)
 		return err
 	}
 
+	n.publishEvents(index, func() []structs.Event {
+		return n.nodeEvents(structs.TypeNodeEligibilityUpdate, req.NodeID)
+	})
+
 	// Unblock evals for the nodes computed node class if it is in a ready
 	// state.
 	if node != nil && node.SchedulingEligibility == structs.NodeSchedulingIneligible &&
@@ -423,6 +513,22 @@ func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 		return err
 	}
 
+	// Store the job file of the version the job was registered at
+	if req.Submission != nil {
+		sub := req.Submission.Copy()
+		sub.Namespace = req.Job.Namespace
+		sub.JobID = req.Job.ID
+		sub.Version = req.Job.Version
+		if err := n.state.UpsertJobSubmission(index, sub); err != nil {
+			n.logger.Error("UpsertJobSubmission failed", "error", err)
+			return err
+		}
+	}
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.jobEvents(structs.TypeJobRegistered, structs.NamespacedID{ID: req.Job.ID, Namespace: req.Job.Namespace})
+	})
+
 	// We always add the job to the periodic dispatcher because there is the
 	// possibility that the periodic spec was removed and then we should stop
 	// tracking it.
@@ -502,7 +608,7 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	return n.state.WithWriteTransaction(func(tx state.Txn) error {
+	err := n.state.WithWriteTransaction(func(tx state.Txn) error {
 		if err := n.handleJobDeregister(index, req.JobID, req.Namespace, req.Purge, tx); err != nil {
 			n.logger.Error("deregistering job failed", "error", err)
 			return err
@@ -510,6 +616,15 @@ func (n *nomadFSM) applyDeregisterJob(buf []byte, index uint64) interface{} {
 
 		return nil
 	})
+
+	if err != nil {
+		return err
+	}
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.jobEvents(structs.TypeJobDeregistered, structs.NamespacedID{ID: req.JobID, Namespace: req.Namespace})
+	})
+	return nil
 }
 
 func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{} {
@@ -544,6 +659,14 @@ func (n *nomadFSM) applyBatchDeregisterJob(buf []byte, index uint64) interface{}
 
 	// perform the side effects outside the transactions
 	n.handleUpsertedEvals(req.Evals)
+
+	n.publishEvents(index, func() []structs.Event {
+		jobs := make([]structs.NamespacedID, 0, len(req.Jobs))
+		for jobNS := range req.Jobs {
+			jobs = append(jobs, jobNS)
+		}
+		return n.jobEvents(structs.TypeJobDeregistered, jobs...)
+	})
 	return nil
 }
 
@@ -596,7 +719,22 @@ func (n *nomadFSM) applyUpdateEval(buf []byte, index uint64) interface{} {
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
-	return n.upsertEvals(index, req.Evals)
+
+	// The last evaluation of a batch job may complete it
+	jobs := make([]structs.NamespacedID, 0, len(req.Evals))
+	for _, eval := range req.Evals {
+		jobs = append(jobs, structs.NamespacedID{ID: eval.JobID, Namespace: eval.Namespace})
+	}
+	statuses := n.batchJobStatuses(jobs...)
+
+	if err := n.upsertEvals(index, req.Evals); err != nil {
+		return err
+	}
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.jobCompletedEvents(statuses)
+	})
+	return nil
 }
 
 func (n *nomadFSM) upsertEvals(index uint64, evals []*structs.Evaluation) error {
@@ -683,6 +821,10 @@ func (n *nomadFSM) applyAllocUpdate(buf []byte, index uint64) interface{} {
 		n.logger.Error("UpsertAllocs failed", "error", err)
 		return err
 	}
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.allocEvents(structs.TypeAllocationUpdated, allocIDs(req.Alloc)...)
+	})
 	return nil
 }
 
@@ -700,13 +842,18 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 	ws := memdb.NewWatchSet()
 
 	// Updating the allocs with the job id and task group name
+	jobs := make([]structs.NamespacedID, 0, len(req.Alloc))
 	for _, alloc := range req.Alloc {
 		if existing, _ := n.state.AllocByID(ws, alloc.ID); existing != nil {
 			alloc.JobID = existing.JobID
 			alloc.TaskGroup = existing.TaskGroup
+			jobs = append(jobs, structs.NamespacedID{ID: existing.JobID, Namespace: existing.Namespace})
 		}
 	}
 
+	// Record the status of the batch jobs to detect their completion
+	statuses := n.batchJobStatuses(jobs...)
+
 	// Update all the client allocations
 	if err := n.state.UpdateAllocsFromClient(index, req.Alloc); err != nil {
 		n.logger.Error("UpdateAllocFromClient failed", "error", err)
@@ -721,6 +868,11 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 		}
 	}
 
+	n.publishEvents(index, func() []structs.Event {
+		events := n.allocEvents(structs.TypeAllocationClientUpdated, allocIDs(req.Alloc)...)
+		return append(events, n.jobCompletedEvents(statuses)...)
+	})
+
 	// Unblock evals for the nodes computed node class if the client has
 	// finished running an allocation.
 	for _, alloc := range req.Alloc {
@@ -763,6 +915,14 @@ func (n *nomadFSM) applyAllocUpdateDesiredTransition(buf []byte, index uint64) i
 	}
 
 	n.handleUpsertedEvals(req.Evals)
+
+	n.publishEvents(index, func() []structs.Event {
+		ids := make([]string, 0, len(req.Allocs))
+		for id := range req.Allocs {
+			ids = append(ids, id)
+		}
+		return n.allocEvents(structs.TypeAllocationUpdateDesiredStatus, ids...)
+	})
 	return nil
 }
 
@@ -838,6 +998,19 @@ func (n *nomadFSM) applyPlanResults(buf []byte, index uint64) interface{} {
 
 	// Add evals for jobs that were preempted
 	n.handleUpsertedEvals(req.PreemptionEvals)
+
+	n.publishEvents(index, func() []structs.Event {
+		var deploymentIDs []string
+		if req.Deployment != nil {
+			deploymentIDs = append(deploymentIDs, req.Deployment.ID)
+		}
+		for _, u := range req.DeploymentUpdates {
+			deploymentIDs = append(deploymentIDs, u.DeploymentID)
+		}
+
+		events := n.allocEvents(structs.TypeAllocationUpdated, allocIDs(req.Alloc, req.NodePreemptions)...)
+		return append(events, n.deploymentEvents(structs.TypeDeploymentUpdated, deploymentIDs...)...)
+	})
 	return nil
 }
 
@@ -856,6 +1029,10 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(buf []byte, index uint64) interfa
 	}
 
 	n.handleUpsertedEval(req.Eval)
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.deploymentEvents(structs.TypeDeploymentStatusUpdate, req.DeploymentUpdate.DeploymentID)
+	})
 	return nil
 }
 
@@ -873,6 +1050,10 @@ func (n *nomadFSM) applyDeploymentPromotion(buf []byte, index uint64) interface{
 	}
 
 	n.handleUpsertedEval(req.Eval)
+
+	n.publishEvents(index, func() []structs.Event {
+		return n.deploymentEvents(structs.TypeDeploymentPromotion, req.DeploymentID)
+	})
 	return nil
 }
 
@@ -891,6 +1072,12 @@ func (n *nomadFSM) applyDeploymentAllocHealth(buf []byte, index uint64) interfac
 	}
 
 	n.handleUpsertedEval(req.Eval)
+
+	n.publishEvents(index, func() []structs.Event {
+		ids := append(helper.CopySliceString(req.HealthyAllocationIDs), req.UnhealthyAllocationIDs...)
+		events := n.deploymentEvents(structs.TypeDeploymentAllocHealth, req.DeploymentID)
+		return append(events, n.allocEvents(structs.TypeDeploymentAllocHealth, ids...)...)
+	})
 	return nil
 }
 
@@ -902,11 +1089,24 @@ func (n *nomadFSM) applyDeploymentDelete(buf []byte, index uint64) interface{} {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
+	// Build the events before the deployments are deleted so they keep the
+	// namespaces and jobs of the deployments
+	var deleted []structs.Event
+	if n.eventBroker != nil {
+		deleted = n.deploymentEvents(structs.TypeDeploymentDeleted, req.Deployments...)
+	}
+
 	if err := n.state.DeleteDeployment(index, req.Deployments); err != nil {
 		n.logger.Error("DeleteDeployment failed", "error", err)
 		return err
 	}
 
+	n.publishEvents(index, func() []structs.Event {
+		for i := range deleted {
+			deleted[i].Payload = &structs.DeploymentEvent{}
+		}
+		return deleted
+	})
 	return nil
 }
 
@@ -926,6 +1126,72 @@ func (n *nomadFSM) applyJobStability(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyJobPinVersion is used to pin a job to a minimum version
+func (n *nomadFSM) applyJobPinVersion(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_pin_version"}, time.Now())
+	var req structs.JobPinVersionRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateJobMinimumVersion(index, req.Namespace, req.JobID, req.JobVersion); err != nil {
+		n.logger.Error("UpdateJobMinimumVersion failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyOneTimeTokenUpsert is used to upsert a one-time token
+func (n *nomadFSM) applyOneTimeTokenUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_one_time_token_upsert"}, time.Now())
+	var token structs.OneTimeToken
+	if err := structs.Decode(buf, &token); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertOneTimeToken(index, &token); err != nil {
+		n.logger.Error("UpsertOneTimeToken failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyOneTimeTokenExchange is used to exchange a one-time token for the ACL
+// token it was created for. It returns the ACL token, or an error if the
+// one-time token can't be exchanged.
+func (n *nomadFSM) applyOneTimeTokenExchange(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_one_time_token_exchange"}, time.Now())
+	var req structs.OneTimeTokenExchangeRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	token, err := n.state.ExchangeOneTimeToken(index, req.OneTimeSecretID, req.Timestamp)
+	if err != nil {
+		if err != structs.ErrOneTimeTokenNotFound && err != structs.ErrTokenNotFound {
+			n.logger.Error("ExchangeOneTimeToken failed", "error", err)
+		}
+		return err
+	}
+	return token
+}
+
+// applyOneTimeTokenExpire is used to delete the expired one-time tokens
+func (n *nomadFSM) applyOneTimeTokenExpire(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_one_time_token_expire"}, time.Now())
+	var req structs.OneTimeTokenExpireRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ExpireOneTimeTokens(index, req.Timestamp); err != nil {
+		n.logger.Error("ExpireOneTimeTokens failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLPolicyUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLPolicyUpsert(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
@@ -956,6 +1222,126 @@ func (n *nomadFSM) applyACLPolicyDelete(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyACLAuthMethodUpsert is used to upsert a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_upsert"}, time.Now())
+	var req structs.ACLAuthMethodUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLAuthMethods(index, req.AuthMethods); err != nil {
+		n.logger.Error("UpsertACLAuthMethods failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLAuthMethodDelete is used to delete a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_delete"}, time.Now())
+	var req structs.ACLAuthMethodDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLAuthMethods(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLAuthMethods failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLRoleUpsert is used to upsert a set of roles
+func (n *nomadFSM) applyACLRoleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_upsert"}, time.Now())
+	var req structs.ACLRoleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLRoles(index, req.Roles); err != nil {
+		n.logger.Error("UpsertACLRoles failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLRoleDelete is used to delete a set of roles
+func (n *nomadFSM) applyACLRoleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_delete"}, time.Now())
+	var req structs.ACLRoleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLRoles(index, req.Names); err != nil {
+		n.logger.Error("DeleteACLRoles failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRuleUpsert is used to upsert a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_upsert"}, time.Now())
+	var req structs.ACLBindingRuleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLBindingRules(index, req.BindingRules); err != nil {
+		n.logger.Error("UpsertACLBindingRules failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRuleDelete is used to delete a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_delete"}, time.Now())
+	var req structs.ACLBindingRuleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLBindingRules(index, req.IDs); err != nil {
+		n.logger.Error("DeleteACLBindingRules failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyNodePoolUpsert is used to upsert a set of node pools
+func (n *nomadFSM) applyNodePoolUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_upsert"}, time.Now())
+	var req structs.NodePoolUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNodePools(index, req.NodePools); err != nil {
+		n.logger.Error("UpsertNodePools failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyNodePoolDelete is used to delete a set of node pools
+func (n *nomadFSM) applyNodePoolDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_node_pool_delete"}, time.Now())
+	var req structs.NodePoolDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteNodePools(index, req.Names); err != nil {
+		n.logger.Error("DeleteNodePools failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLTokenUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
@@ -1035,6 +1421,81 @@ func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interfac
 	return n.state.SchedulerSetConfig(index, &req.Config)
 }
 
+// applyVariableUpsert is used to upsert an encrypted variable
+func (n *nomadFSM) applyVariableUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_upsert"}, time.Now())
+	var req structs.VariablesEncryptedUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertVariable(index, req.Variable); err != nil {
+		n.logger.Error("UpsertVariable failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyVariableDelete is used to delete a variable
+func (n *nomadFSM) applyVariableDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_delete"}, time.Now())
+	var req structs.VariablesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteVariable(index, req.RequestNamespace(), req.Path); err != nil {
+		n.logger.Error("DeleteVariable failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyRootKeyUpsert is used to upsert a root key
+func (n *nomadFSM) applyRootKeyUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_upsert"}, time.Now())
+	var req structs.RootKeyUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertRootKey(index, req.RootKey); err != nil {
+		n.logger.Error("UpsertRootKey failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyServiceRegistrationUpsert is used to upsert service registrations
+func (n *nomadFSM) applyServiceRegistrationUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_upsert"}, time.Now())
+	var req structs.ServiceRegistrationUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertServiceRegistrations(index, req.Services); err != nil {
+		n.logger.Error("UpsertServiceRegistrations failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyServiceRegistrationDeleteByID is used to delete a service registration
+func (n *nomadFSM) applyServiceRegistrationDeleteByID(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_delete_by_id"}, time.Now())
+	var req structs.ServiceRegistrationDeleteByIDRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteServiceRegistrationByID(index, req.ID); err != nil {
+		n.logger.Error("DeleteServiceRegistrationByID failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 	// Create a new snapshot
 	snap, err := n.state.Snapshot()
@@ -1043,8 +1504,9 @@ func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 	}
 
 	ns := &nomadSnapshot{
-		snap:      snap,
-		timetable: n.timetable,
+		snap:                snap,
+		timetable:           n.timetable,
+		compressJobVersions: n.config.CompressJobVersions != nil && n.config.CompressJobVersions(),
 	}
 	return ns, nil
 }
@@ -1222,6 +1684,24 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case CompressedJobVersionSnapshot:
+			var compressed []byte
+			if err := dec.Decode(&compressed); err != nil {
+				return err
+			}
+			raw, err := snappy.Decode(nil, compressed)
+			if err != nil {
+				return fmt.Errorf("failed to decompress job version: %v", err)
+			}
+			version := new(structs.Job)
+			if err := structs.Decode(raw, version); err != nil {
+				return err
+			}
+
+			if err := restore.JobVersionRestore(version); err != nil {
+				return err
+			}
+
 		case DeploymentSnapshot:
 			deployment := new(structs.Deployment)
 			if err := dec.Decode(deployment); err != nil {
@@ -1264,6 +1744,87 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case VariableSnapshot:
+			variable := new(structs.VariableEncrypted)
+			if err := dec.Decode(variable); err != nil {
+				return err
+			}
+			if err := restore.VariableRestore(variable); err != nil {
+				return err
+			}
+
+		case RootKeySnapshot:
+			key := new(structs.RootKey)
+			if err := dec.Decode(key); err != nil {
+				return err
+			}
+			if err := restore.RootKeyRestore(key); err != nil {
+				return err
+			}
+
+		case ServiceRegistrationSnapshot:
+			service := new(structs.ServiceRegistration)
+			if err := dec.Decode(service); err != nil {
+				return err
+			}
+			if err := restore.ServiceRegistrationRestore(service); err != nil {
+				return err
+			}
+
+		case ACLAuthMethodSnapshot:
+			method := new(structs.ACLAuthMethod)
+			if err := dec.Decode(method); err != nil {
+				return err
+			}
+			if err := restore.ACLAuthMethodRestore(method); err != nil {
+				return err
+			}
+
+		case ACLRoleSnapshot:
+			role := new(structs.ACLRole)
+			if err := dec.Decode(role); err != nil {
+				return err
+			}
+			if err := restore.ACLRoleRestore(role); err != nil {
+				return err
+			}
+
+		case ACLBindingRuleSnapshot:
+			rule := new(structs.ACLBindingRule)
+			if err := dec.Decode(rule); err != nil {
+				return err
+			}
+			if err := restore.ACLBindingRuleRestore(rule); err != nil {
+				return err
+			}
+
+		case NodePoolSnapshot:
+			pool := new(structs.NodePool)
+			if err := dec.Decode(pool); err != nil {
+				return err
+			}
+			if err := restore.NodePoolRestore(pool); err != nil {
+				return err
+			}
+
+		case JobSubmissionSnapshot:
+			sub := new(structs.JobSubmission)
+			if err := dec.Decode(sub); err != nil {
+				return err
+			}
+			if err := restore.JobSubmissionRestore(sub); err != nil {
+				return err
+			}
+
+		case OneTimeTokenSnapshot:
+			token := new(structs.OneTimeToken)
+			if err := dec.Decode(token); err != nil {
+				return err
+			}
+			if err := restore.OneTimeTokenRestore(token); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -1560,6 +2121,42 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistRootKeys(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVariables(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistServiceRegistrations(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLRoles(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistNodePools(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistOneTimeTokens(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -1780,6 +2377,10 @@ func (s *nomadSnapshot) persistVaultAccessors(sink raft.SnapshotSink,
 	return nil
 }
 
+// persistJobVersions writes out the job versions compressed, as the versions
+// of a job are mostly identical and can make up most of the snapshot. They
+// are written uncompressed while servers that can't restore compressed job
+// versions may be sent the snapshot.
 func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
@@ -1789,6 +2390,29 @@ func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
 		return err
 	}
 
+	if !s.compressJobVersions {
+		for {
+			// Get the next item
+			raw := versions.Next()
+			if raw == nil {
+				break
+			}
+
+			// Prepare the request struct
+			job := raw.(*structs.Job)
+
+			// Write out a job registration
+			sink.Write([]byte{byte(JobVersionSnapshot)})
+			if err := encoder.Encode(job); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	var compressed []byte
+	jobEncoder := codec.NewEncoder(&buf, structs.MsgpackHandle)
 	for {
 		// Get the next item
 		raw := versions.Next()
@@ -1799,9 +2423,17 @@ func (s *nomadSnapshot) persistJobVersions(sink raft.SnapshotSink,
 		// Prepare the request struct
 		job := raw.(*structs.Job)
 
+		// Compress the encoded job
+		buf.Reset()
+		jobEncoder.Reset(&buf)
+		if err := jobEncoder.Encode(job); err != nil {
+			return err
+		}
+		compressed = snappy.Encode(compressed[:cap(compressed)], buf.Bytes())
+
 		// Write out a job registration
-		sink.Write([]byte{byte(JobVersionSnapshot)})
-		if err := encoder.Encode(job); err != nil {
+		sink.Write([]byte{byte(CompressedJobVersionSnapshot)})
+		if err := encoder.Encode(compressed); err != nil {
 			return err
 		}
 	}
@@ -1907,6 +2539,240 @@ func (s *nomadSnapshot) persistSchedulerConfig(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistRootKeys(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the root keys
+	ws := memdb.NewWatchSet()
+	keys, err := s.snap.RootKeys(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := keys.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a root key registration
+		key := raw.(*structs.RootKey)
+		sink.Write([]byte{byte(RootKeySnapshot)})
+		if err := encoder.Encode(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the variables
+	ws := memdb.NewWatchSet()
+	variables, err := s.snap.Variables(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := variables.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a variable registration
+		variable := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariableSnapshot)})
+		if err := encoder.Encode(variable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the service registrations
+	ws := memdb.NewWatchSet()
+	services, err := s.snap.ServiceRegistrations(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := services.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a service registration
+		service := raw.(*structs.ServiceRegistration)
+		sink.Write([]byte{byte(ServiceRegistrationSnapshot)})
+		if err := encoder.Encode(service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the auth methods
+	ws := memdb.NewWatchSet()
+	methods, err := s.snap.ACLAuthMethods(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := methods.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out an auth method
+		method := raw.(*structs.ACLAuthMethod)
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the roles
+	ws := memdb.NewWatchSet()
+	roles, err := s.snap.ACLRoles(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := roles.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a role
+		role := raw.(*structs.ACLRole)
+		sink.Write([]byte{byte(ACLRoleSnapshot)})
+		if err := encoder.Encode(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the binding rules
+	ws := memdb.NewWatchSet()
+	rules, err := s.snap.ACLBindingRules(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := rules.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a binding rule
+		rule := raw.(*structs.ACLBindingRule)
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistNodePools(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the node pools
+	ws := memdb.NewWatchSet()
+	pools, err := s.snap.NodePools(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := pools.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a node pool
+		pool := raw.(*structs.NodePool)
+		sink.Write([]byte{byte(NodePoolSnapshot)})
+		if err := encoder.Encode(pool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the job submissions
+	ws := memdb.NewWatchSet()
+	subs, err := s.snap.JobSubmissions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := subs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a job submission
+		sub := raw.(*structs.JobSubmission)
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistOneTimeTokens(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the one-time tokens
+	ws := memdb.NewWatchSet()
+	tokens, err := s.snap.OneTimeTokens(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := tokens.Next()
+		if raw == nil {
+			break
+		}
+
+		// Write out a one-time token
+		token := raw.(*structs.OneTimeToken)
+		sink.Write([]byte{byte(OneTimeTokenSnapshot)})
+		if err := encoder.Encode(token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
@@ -0,0 +1,208 @@
+package nomad
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// publishEvents publishes the events of the Raft log applied at the index to
+// the event broker. The events are only built if there is a broker, and all
+// the events of an index must be published at once.
+func (n *nomadFSM) publishEvents(index uint64, build func() []structs.Event) {
+	if n.eventBroker == nil {
+		return
+	}
+
+	events := build()
+	if len(events) == 0 {
+		return
+	}
+	for i := range events {
+		events[i].Index = index
+	}
+	n.eventBroker.Publish(&structs.Events{Index: index, Events: events})
+}
+
+// nodeEvents returns the events for the changes to the nodes, without their
+// secret IDs.
+func (n *nomadFSM) nodeEvents(eventType string, nodeIDs ...string) []structs.Event {
+	events := make([]structs.Event, 0, len(nodeIDs))
+	for _, id := range nodeIDs {
+		node, err := n.state.NodeByID(nil, id)
+		if err != nil {
+			n.logger.Error("looking up node for event failed", "node_id", id, "error", err)
+			continue
+		}
+		if node != nil {
+			node = node.Copy()
+			node.SecretID = ""
+		}
+
+		events = append(events, structs.Event{
+			Topic:   structs.TopicNode,
+			Type:    eventType,
+			Key:     id,
+			Payload: &structs.NodeStreamEvent{Node: node},
+		})
+	}
+	return events
+}
+
+// jobEvents returns the events for the changes to the jobs.
+func (n *nomadFSM) jobEvents(eventType string, jobs ...structs.NamespacedID) []structs.Event {
+	events := make([]structs.Event, 0, len(jobs))
+	for _, ns := range jobs {
+		job, err := n.state.JobByID(nil, ns.Namespace, ns.ID)
+		if err != nil {
+			n.logger.Error("looking up job for event failed", "job", ns, "error", err)
+			continue
+		}
+
+		events = append(events, structs.Event{
+			Topic:     structs.TopicJob,
+			Type:      eventType,
+			Key:       ns.ID,
+			Namespace: ns.Namespace,
+			Payload:   &structs.JobEvent{Job: job},
+		})
+	}
+	return events
+}
+
+// batchJobStatuses returns the statuses of the batch jobs, so that their
+// completion can be detected by jobCompletedEvents once the Raft log is
+// applied. It returns nil if no events are published.
+func (n *nomadFSM) batchJobStatuses(jobs ...structs.NamespacedID) map[structs.NamespacedID]string {
+	if n.eventBroker == nil {
+		return nil
+	}
+
+	statuses := make(map[structs.NamespacedID]string, len(jobs))
+	for _, ns := range jobs {
+		job, err := n.state.JobByID(nil, ns.Namespace, ns.ID)
+		if err != nil {
+			n.logger.Error("looking up job for event failed", "job", ns, "error", err)
+			continue
+		}
+		if job != nil && job.Type == structs.JobTypeBatch {
+			statuses[ns] = job.Status
+		}
+	}
+	return statuses
+}
+
+// jobCompletedEvents returns the events for the batch jobs whose status
+// changed to dead without being stopped since their statuses were recorded.
+// The events include the job summary so that the outcome of the job is known
+// without listing its allocations.
+func (n *nomadFSM) jobCompletedEvents(statuses map[structs.NamespacedID]string) []structs.Event {
+	var events []structs.Event
+	for ns, status := range statuses {
+		if status == structs.JobStatusDead {
+			continue
+		}
+
+		job, err := n.state.JobByID(nil, ns.Namespace, ns.ID)
+		if err != nil {
+			n.logger.Error("looking up job for event failed", "job", ns, "error", err)
+			continue
+		}
+		if job == nil || job.Stop || job.Status != structs.JobStatusDead {
+			continue
+		}
+
+		summary, err := n.state.JobSummaryByID(nil, ns.Namespace, ns.ID)
+		if err != nil {
+			n.logger.Error("looking up job summary for event failed", "job", ns, "error", err)
+			continue
+		}
+
+		events = append(events, structs.Event{
+			Topic:     structs.TopicJob,
+			Type:      structs.TypeJobCompleted,
+			Key:       ns.ID,
+			Namespace: ns.Namespace,
+			Payload:   &structs.JobEvent{Job: job, Summary: summary},
+		})
+	}
+
+	// Publish the events in a stable order
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Namespace != events[j].Namespace {
+			return events[i].Namespace < events[j].Namespace
+		}
+		return events[i].Key < events[j].Key
+	})
+	return events
+}
+
+// allocEvents returns the events for the changes to the allocations, without
+// their jobs. Allocations match subscriptions to their job and deployment.
+func (n *nomadFSM) allocEvents(eventType string, allocIDs ...string) []structs.Event {
+	events := make([]structs.Event, 0, len(allocIDs))
+	for _, id := range allocIDs {
+		alloc, err := n.state.AllocByID(nil, id)
+		if err != nil {
+			n.logger.Error("looking up allocation for event failed", "alloc_id", id, "error", err)
+			continue
+		}
+		if alloc == nil {
+			continue
+		}
+		alloc = alloc.CopySkipJob()
+		alloc.Job = nil
+
+		filterKeys := []string{alloc.JobID}
+		if alloc.DeploymentID != "" {
+			filterKeys = append(filterKeys, alloc.DeploymentID)
+		}
+
+		events = append(events, structs.Event{
+			Topic:      structs.TopicAllocation,
+			Type:       eventType,
+			Key:        id,
+			Namespace:  alloc.Namespace,
+			FilterKeys: filterKeys,
+			Payload:    &structs.AllocationEvent{Allocation: alloc},
+		})
+	}
+	return events
+}
+
+// deploymentEvents returns the events for the changes to the deployments,
+// which match subscriptions to their job.
+func (n *nomadFSM) deploymentEvents(eventType string, deploymentIDs ...string) []structs.Event {
+	events := make([]structs.Event, 0, len(deploymentIDs))
+	for _, id := range deploymentIDs {
+		d, err := n.state.DeploymentByID(nil, id)
+		if err != nil {
+			n.logger.Error("looking up deployment for event failed", "deployment_id", id, "error", err)
+			continue
+		}
+
+		event := structs.Event{
+			Topic:   structs.TopicDeployment,
+			Type:    eventType,
+			Key:     id,
+			Payload: &structs.DeploymentEvent{Deployment: d},
+		}
+		if d != nil {
+			event.Namespace = d.Namespace
+			event.FilterKeys = []string{d.JobID}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// allocIDs returns the IDs of the allocations.
+func allocIDs(allocs ...[]*structs.Allocation) []string {
+	var ids []string
+	for _, list := range allocs {
+		for _, alloc := range list {
+			ids = append(ids, alloc.ID)
+		}
+	}
+	return ids
+}
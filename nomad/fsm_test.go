@@ -2,7 +2,9 @@ package nomad
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"testing"
@@ -15,12 +17,14 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 type MockSink struct {
@@ -558,6 +562,35 @@ func TestFSM_UpdateNodeEligibility_Unblock(t *testing.T) {
 	})
 }
 
+func TestFSM_RegisterJob_Submission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	job := mock.Job()
+	req := structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:    `job "example" {}`,
+			Variables: `count = 2`,
+		},
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobRegisterRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// The submission is stored with the job version
+	out, err := fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(req.Submission.Source, out.Source)
+	require.Equal(req.Submission.Variables, out.Variables)
+	require.Equal(job.ID, out.JobID)
+}
+
 func TestFSM_RegisterJob(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2162,6 +2195,89 @@ func TestFSM_DeleteDeployment(t *testing.T) {
 	}
 }
 
+func TestFSM_UpsertDeleteVariable(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	v := mock.VariableEncrypted()
+	req := structs.VariablesEncryptedUpsertRequest{
+		Variable: v,
+	}
+	buf, err := structs.Encode(structs.VariableUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	out, err := fsm.State().VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.NotNil(out)
+
+	del := structs.VariablesDeleteRequest{
+		Path: v.Path,
+	}
+	buf, err = structs.Encode(structs.VariableDeleteRequestType, del)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are not registered
+	out, err = fsm.State().VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_UpsertRootKey(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	key := mock.RootKey()
+	req := structs.RootKeyUpsertRequest{
+		RootKey: key,
+	}
+	buf, err := structs.Encode(structs.RootKeyUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().RootKeyByID(nil, key.KeyID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestFSM_UpsertDeleteServiceRegistration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	alloc := mock.Alloc()
+	fsm.State().UpsertJobSummary(999, mock.JobSummary(alloc.JobID))
+	require.NoError(fsm.State().UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	service := mock.ServiceRegistration(alloc)
+	req := structs.ServiceRegistrationUpsertRequest{
+		Services: []*structs.ServiceRegistration{service},
+	}
+	buf, err := structs.Encode(structs.ServiceRegistrationUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Delete the registration
+	delReq := structs.ServiceRegistrationDeleteByIDRequest{
+		ID: service.ID,
+	}
+	buf, err = structs.Encode(structs.ServiceRegistrationDeleteByIDRequestType, delReq)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
 func TestFSM_UpsertACLPolicies(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2187,6 +2303,122 @@ func TestFSM_UpsertACLPolicies(t *testing.T) {
 	assert.NotNil(t, out)
 }
 
+func TestFSM_UpsertACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	method := mock.ACLAuthMethod()
+	req := structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{method},
+	}
+	buf, err := structs.Encode(structs.ACLAuthMethodUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLAuthMethodByName(nil, method.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete the auth method
+	del := structs.ACLAuthMethodDeleteRequest{
+		Names: []string{method.Name},
+	}
+	buf, err = structs.Encode(structs.ACLAuthMethodDeleteRequestType, del)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ACLAuthMethodByName(nil, method.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_UpsertACLRoles(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	req := structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+	}
+	buf, err := structs.Encode(structs.ACLRoleUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete the role
+	del := structs.ACLRoleDeleteRequest{
+		Names: []string{role.Name},
+	}
+	buf, err = structs.Encode(structs.ACLRoleDeleteRequestType, del)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_UpsertACLBindingRules(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	rule := mock.ACLBindingRule("ci")
+	req := structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{rule},
+	}
+	buf, err := structs.Encode(structs.ACLBindingRuleUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete the binding rule
+	del := structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{rule.ID},
+	}
+	buf, err = structs.Encode(structs.ACLBindingRuleDeleteRequestType, del)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_UpsertNodePools(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	pool := mock.NodePool()
+	req := structs.NodePoolUpsertRequest{
+		NodePools: []*structs.NodePool{pool},
+	}
+	buf, err := structs.Encode(structs.NodePoolUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err := fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Delete the node pool
+	del := structs.NodePoolDeleteRequest{
+		Names: []string{pool.Name},
+	}
+	buf, err = structs.Encode(structs.NodePoolDeleteRequestType, del)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
 func TestFSM_DeleteACLPolicies(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2610,6 +2842,105 @@ func TestFSM_SnapshotRestore_JobVersions(t *testing.T) {
 	}
 }
 
+// TestFSM_SnapshotRestore_JobVersions_MixedVersions asserts that job versions
+// are only written compressed once every server can restore them, so that
+// servers not yet upgraded can restore the snapshots of upgraded ones.
+func TestFSM_SnapshotRestore_JobVersions_MixedVersions(t *testing.T) {
+	t.Parallel()
+
+	// snapshotTypes returns the types of the records of a snapshot
+	snapshotTypes := func(t *testing.T, fsm *nomadFSM) (map[SnapshotType]int, *bytes.Buffer) {
+		snap, err := fsm.Snapshot()
+		require.NoError(t, err)
+		defer snap.Release()
+
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, snap.Persist(&MockSink{buf, false}))
+		out := bytes.NewBuffer(buf.Bytes())
+
+		dec := codec.NewDecoder(buf, structs.MsgpackHandle)
+		var header snapshotHeader
+		require.NoError(t, dec.Decode(&header))
+
+		types := make(map[SnapshotType]int)
+		msgType := make([]byte, 1)
+		for {
+			if _, err := buf.Read(msgType); err == io.EOF {
+				break
+			}
+			var record interface{}
+			require.NoError(t, dec.Decode(&record))
+			types[SnapshotType(msgType[0])]++
+		}
+		return types, out
+	}
+
+	cases := []struct {
+		name     string
+		upgraded bool
+	}{
+		{name: "mixed versions", upgraded: false},
+		{name: "all upgraded", upgraded: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+
+			fsm := testFSM(t)
+			fsm.config.CompressJobVersions = func() bool { return c.upgraded }
+			job1 := mock.Job()
+			require.NoError(fsm.State().UpsertJob(1000, job1))
+			job2 := job1.Copy()
+			job2.Priority = 60
+			require.NoError(fsm.State().UpsertJob(1001, job2))
+
+			types, buf := snapshotTypes(t, fsm)
+			if c.upgraded {
+				require.Equal(2, types[CompressedJobVersionSnapshot])
+				require.Zero(types[JobVersionSnapshot])
+			} else {
+				// Servers older than 0.9.0 only understand the record
+				// types preceding the compressed job versions
+				require.Equal(2, types[JobVersionSnapshot])
+				for typ := range types {
+					require.True(typ < CompressedJobVersionSnapshot, "unexpected snapshot type %d", typ)
+				}
+			}
+
+			fsm2 := testFSM(t)
+			require.NoError(fsm2.Restore(&MockSink{buf, false}))
+			for _, job := range []*structs.Job{job1, job2} {
+				out, err := fsm2.State().JobByIDAndVersion(nil, job.Namespace, job.ID, job.Version)
+				require.NoError(err)
+				require.Equal(job, out)
+			}
+		})
+	}
+}
+
+// TestFSM_SnapshotRestore_UncompressedJobVersions asserts that the job versions
+// of snapshots written before they were compressed are restored.
+func TestFSM_SnapshotRestore_UncompressedJobVersions(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job := mock.Job()
+	job.Version = 3
+
+	buf := bytes.NewBuffer(nil)
+	encoder := codec.NewEncoder(buf, structs.MsgpackHandle)
+	require.NoError(encoder.Encode(&snapshotHeader{}))
+	buf.Write([]byte{byte(JobVersionSnapshot)})
+	require.NoError(encoder.Encode(job))
+
+	fsm := testFSM(t)
+	require.NoError(fsm.Restore(&MockSink{buf, false}))
+
+	out, err := fsm.State().JobByIDAndVersion(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(err)
+	require.Equal(job, out)
+}
+
 func TestFSM_SnapshotRestore_Deployments(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2659,6 +2990,108 @@ func TestFSM_SnapshotRestore_ACLPolicy(t *testing.T) {
 	assert.Equal(t, p2, out2)
 }
 
+func TestFSM_SnapshotRestore_ACLAuthMethods(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	m1 := mock.ACLAuthMethod()
+	m2 := mock.ACLAuthMethod()
+	state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1, m2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.ACLAuthMethodByName(nil, m1.Name)
+	out2, _ := state2.ACLAuthMethodByName(nil, m2.Name)
+	assert.Equal(t, m1, out1)
+	assert.Equal(t, m2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLRoles(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	state.UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.ACLRoleByName(nil, r1.Name)
+	out2, _ := state2.ACLRoleByName(nil, r2.Name)
+	assert.Equal(t, r1, out1)
+	assert.Equal(t, r2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLBindingRules(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	r1 := mock.ACLBindingRule("ci")
+	r2 := mock.ACLBindingRule("ci")
+	state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{r1, r2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.ACLBindingRuleByID(nil, r1.ID)
+	out2, _ := state2.ACLBindingRuleByID(nil, r2.ID)
+	assert.Equal(t, r1, out1)
+	assert.Equal(t, r2, out2)
+}
+
+func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	p1 := mock.NodePool()
+	p2 := &structs.NodePool{
+		Name:        structs.NodePoolDefault,
+		Description: "Updated default pool",
+	}
+	state.UpsertNodePools(1000, []*structs.NodePool{p1, p2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.NodePoolByName(nil, p1.Name)
+	out2, _ := state2.NodePoolByName(nil, p2.Name)
+	assert.Equal(t, p1, out1)
+	assert.Equal(t, p2, out2)
+
+	// The built-in pools are kept
+	out3, _ := state2.NodePoolByName(nil, structs.NodePoolAll)
+	assert.NotNil(t, out3)
+}
+
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.Job()
+	state.UpsertJob(1000, job)
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Variables: `count = 2`,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Version:   job.Version,
+	}
+	state.UpsertJobSubmission(1001, sub)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	assert.Equal(t, sub, out)
+}
+
 func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2701,6 +3134,49 @@ func TestFSM_SnapshotRestore_SchedulerConfiguration(t *testing.T) {
 
 }
 
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	key := mock.RootKey()
+	v := mock.VariableEncrypted()
+	v.KeyID = key.KeyID
+	state.UpsertRootKey(1000, key)
+	state.UpsertVariable(1001, v)
+
+	// Verify the contents
+	require := require.New(t)
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	outKey, err := state2.RootKeyByID(nil, key.KeyID)
+	require.NoError(err)
+	require.Equal(key, outKey)
+	out, err := state2.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Equal(v, out)
+}
+
+func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	alloc := mock.Alloc()
+	service := mock.ServiceRegistration(alloc)
+	state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID))
+	state.UpsertAllocs(1000, []*structs.Allocation{alloc})
+	state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{service})
+
+	// Verify the contents
+	require := require.New(t)
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Equal(service, out)
+}
+
 func TestFSM_SnapshotRestore_AddMissingSummary(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -3010,3 +3486,151 @@ func TestFSM_SchedulerConfig(t *testing.T) {
 	// Verify that preemption is still enabled
 	require.True(config.PreemptionConfig.SystemSchedulerEnabled)
 }
+
+func TestFSM_PublishEvents(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.eventBroker = stream.NewEventBroker(10)
+
+	sub := fsm.eventBroker.Subscribe(&stream.SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicAll: {"*"}},
+		Namespace: "*",
+	})
+	next := func() *structs.Events {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		events, err := sub.Next(ctx)
+		require.NoError(err)
+		return events
+	}
+
+	// Node events don't include the secret ID of the node
+	node := mock.Node()
+	buf, err := structs.Encode(structs.NodeRegisterRequestType, structs.NodeRegisterRequest{Node: node})
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	events := next()
+	require.Equal(uint64(1), events.Index)
+	require.Len(events.Events, 1)
+	require.Equal(structs.TypeNodeRegistration, events.Events[0].Type)
+	require.Equal(node.ID, events.Events[0].Key)
+	payload := events.Events[0].Payload.(*structs.NodeStreamEvent)
+	require.Equal(node.ID, payload.Node.ID)
+	require.Empty(payload.Node.SecretID)
+
+	// Plan results publish the allocations and deployment at the same index
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	d := mock.Deployment()
+	d.JobID = alloc.JobID
+	alloc.DeploymentID = d.ID
+	req := structs.ApplyPlanResultsRequest{
+		AllocUpdateRequest: structs.AllocUpdateRequest{
+			Job:   alloc.Job,
+			Alloc: []*structs.Allocation{alloc},
+		},
+		Deployment: d,
+	}
+	buf, err = structs.Encode(structs.ApplyPlanResultsRequestType, req)
+	require.NoError(err)
+	log := makeLog(buf)
+	log.Index = 2
+	require.Nil(fsm.Apply(log))
+
+	events = next()
+	require.Equal(uint64(2), events.Index)
+	require.Len(events.Events, 2)
+
+	allocEvent := events.Events[0]
+	require.Equal(structs.TopicAllocation, allocEvent.Topic)
+	require.Equal(alloc.ID, allocEvent.Key)
+	require.Equal([]string{alloc.JobID, d.ID}, allocEvent.FilterKeys)
+	require.Nil(allocEvent.Payload.(*structs.AllocationEvent).Allocation.Job)
+
+	deploymentEvent := events.Events[1]
+	require.Equal(structs.TopicDeployment, deploymentEvent.Topic)
+	require.Equal(structs.TypeDeploymentUpdated, deploymentEvent.Type)
+	require.Equal(d.ID, deploymentEvent.Key)
+	require.Equal([]string{d.JobID}, deploymentEvent.FilterKeys)
+}
+
+func TestFSM_PublishEvents_JobCompleted(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.eventBroker = stream.NewEventBroker(10)
+
+	sub := fsm.eventBroker.Subscribe(&stream.SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		Namespace: "*",
+	})
+
+	// Create a batch job with a running allocation
+	job := mock.Job()
+	job.Type = structs.JobTypeBatch
+	require.NoError(fsm.State().UpsertJob(1, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(fsm.State().UpsertAllocs(3, []*structs.Allocation{alloc}))
+
+	// Completing the allocation completes the job
+	update := alloc.Copy()
+	update.Job = nil
+	update.ClientStatus = structs.AllocClientStatusComplete
+	update.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State: structs.TaskStateDead,
+			Events: []*structs.TaskEvent{
+				structs.NewTaskEvent(structs.TaskTerminated).SetExitCode(0),
+			},
+		},
+	}
+	buf, err := structs.Encode(structs.AllocClientUpdateRequestType, structs.AllocUpdateRequest{
+		Alloc: []*structs.Allocation{update},
+	})
+	require.NoError(err)
+	log := makeLog(buf)
+	log.Index = 4
+	require.Nil(fsm.Apply(log))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := sub.Next(ctx)
+	require.NoError(err)
+	require.Equal(uint64(4), events.Index)
+	require.Len(events.Events, 1)
+
+	event := events.Events[0]
+	require.Equal(structs.TypeJobCompleted, event.Type)
+	require.Equal(job.ID, event.Key)
+	payload := event.Payload.(*structs.JobEvent)
+	require.Equal(structs.JobStatusDead, payload.Job.Status)
+	require.Equal(1, payload.Summary.Summary["web"].Complete)
+	require.Equal(map[string]int{"web": 0}, payload.Summary.Summary["web"].LastExitCodes)
+}
+
+func TestFSM_SnapshotRestore_OneTimeTokens(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	ott := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      uuid.Generate(),
+		ExpiresAt:       time.Now().UTC().Add(time.Minute),
+	}
+	state.UpsertOneTimeToken(1000, ott)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.OneTimeTokenBySecret(nil, ott.OneTimeSecretID)
+	require.NotNil(t, out)
+	assert.Equal(t, ott.AccessorID, out.AccessorID)
+	assert.True(t, ott.ExpiresAt.Equal(out.ExpiresAt))
+}
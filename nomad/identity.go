@@ -0,0 +1,214 @@
+package nomad
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// workloadIdentityTTL is how long a workload identity used to log in to
+	// Vault is valid for. Tasks only use it to log in, so it is kept short.
+	// Identities for the Nomad API don't expire but are only valid while
+	// their allocation is running.
+	workloadIdentityTTL = 15 * time.Minute
+
+	// identitySigningContext separates the signing key derived from a root
+	// key from any other use of the root key.
+	identitySigningContext = "nomad workload identity"
+)
+
+// identitySigningKey derives the ECDSA P-256 key that signs workload
+// identities from the root key. The derivation is deterministic so that every
+// server signs with the same key without it being stored in Raft.
+func identitySigningKey(key *structs.RootKey) (*ecdsa.PrivateKey, error) {
+	if len(key.Key) == 0 {
+		return nil, fmt.Errorf("root key %q is empty", key.KeyID)
+	}
+
+	curve := elliptic.P256()
+	seed := sha256.Sum256(append([]byte(identitySigningContext), key.Key...))
+
+	// Map the seed into [1, N-1]
+	n := new(big.Int).Sub(curve.Params().N, big.NewInt(1))
+	d := new(big.Int).SetBytes(seed[:])
+	d.Mod(d, n)
+	d.Add(d, big.NewInt(1))
+
+	priv := &ecdsa.PrivateKey{D: d}
+	priv.PublicKey.Curve = curve
+	priv.PublicKey.X, priv.PublicKey.Y = curve.ScalarBaseMult(d.Bytes())
+	return priv, nil
+}
+
+// identityPublicKey returns the public key that verifies the workload
+// identities signed with the root key.
+func identityPublicKey(key *structs.RootKey) (*structs.JSONWebKey, error) {
+	priv, err := identitySigningKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.JSONWebKey{
+		KeyID:     key.KeyID,
+		KeyType:   "EC",
+		Use:       "sig",
+		Algorithm: "ES256",
+		Curve:     "P-256",
+		X:         base64.RawURLEncoding.EncodeToString(padCoordinate(priv.X)),
+		Y:         base64.RawURLEncoding.EncodeToString(padCoordinate(priv.Y)),
+	}, nil
+}
+
+// signIdentity returns the claims as a JWT signed with the key derived from
+// the root key. The validity of the claims is set starting now, for the TTL if
+// it is not zero.
+func signIdentity(key *structs.RootKey, claims *structs.IdentityClaims, now time.Time, ttl time.Duration) (string, error) {
+	priv, err := identitySigningKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	claims.IssuedAt = now.Unix()
+	claims.NotBefore = now.Unix()
+	if ttl != 0 {
+		claims.Expiry = now.Add(ttl).Unix()
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"alg": "ES256",
+		"typ": "JWT",
+		"kid": key.KeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign workload identity: %v", err)
+	}
+
+	// JWS encodes ECDSA signatures as the fixed size concatenation of r and s
+	sig := append(padCoordinate(r), padCoordinate(s)...)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// padCoordinate returns the big-endian bytes of the P-256 value, left padded
+// to 32 bytes.
+func padCoordinate(i *big.Int) []byte {
+	b := i.Bytes()
+	if len(b) >= 32 {
+		return b
+	}
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return padded
+}
+
+// verifyIdentity returns the claims of a workload identity JWT for the Nomad
+// API once its signature is verified with the root key that signed it. The
+// identity is only valid while its allocation is running.
+func verifyIdentity(snap *state.StateSnapshot, jwt string, now time.Time) (*structs.IdentityClaims, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil || header.Algorithm != "ES256" {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	key, err := snap.RootKeyByID(nil, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	priv, err := identitySigningKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return nil, structs.ErrTokenNotFound
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&priv.PublicKey, digest[:], r, s) {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	var claims structs.IdentityClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	if claims.Audience != structs.IdentityAudienceNomad {
+		return nil, structs.ErrTokenNotFound
+	}
+	if now.Unix() < claims.NotBefore || (claims.Expiry != 0 && now.Unix() >= claims.Expiry) {
+		return nil, structs.ErrTokenExpired
+	}
+
+	alloc, err := snap.AllocByID(nil, claims.AllocationID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.TerminalStatus() {
+		return nil, structs.ErrTokenExpired
+	}
+	if alloc.Namespace != claims.Namespace || alloc.JobID != claims.JobID {
+		return nil, structs.ErrTokenNotFound
+	}
+	return &claims, nil
+}
+
+// identityACL returns the ACL object granted to a workload identity: it may
+// read its job and read and list the variables below the path of its job.
+func identityACL(claims *structs.IdentityClaims) (*acl.ACL, error) {
+	jobPath := structs.VariablesJobsPrefix + "/" + claims.JobID
+	variableCaps := []string{acl.VariablesCapabilityRead, acl.VariablesCapabilityList}
+
+	policy := &acl.Policy{
+		Namespaces: []*acl.NamespacePolicy{{
+			Name: claims.Namespace,
+			Jobs: []*acl.JobPolicy{{
+				JobSpec:      claims.JobID,
+				Capabilities: []string{acl.NamespaceCapabilityReadJob},
+			}},
+			Variables: &acl.VariablesPolicy{
+				Paths: []*acl.VariablesPathPolicy{
+					{PathSpec: jobPath, Capabilities: variableCaps},
+					{PathSpec: jobPath + "/*", Capabilities: variableCaps},
+				},
+			},
+		}},
+	}
+	return acl.NewACL(false, []*acl.Policy{policy})
+}
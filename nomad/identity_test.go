@@ -0,0 +1,143 @@
+package nomad
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentity_SignVerify(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	key, err := newRootKey()
+	require.NoError(err)
+
+	// The signing key is derived deterministically
+	k1, err := identitySigningKey(key)
+	require.NoError(err)
+	k2, err := identitySigningKey(key)
+	require.NoError(err)
+	require.Equal(k1.D, k2.D)
+
+	alloc := mock.Alloc()
+	now := time.Now()
+	jwt, err := signIdentity(key, structs.NewIdentityClaims(alloc, "web"), now, workloadIdentityTTL)
+	require.NoError(err)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(parts, 3)
+
+	// Check the header and claims
+	var header map[string]string
+	decodeJWTPart(t, parts[0], &header)
+	require.Equal("ES256", header["alg"])
+	require.Equal(key.KeyID, header["kid"])
+
+	var claims structs.IdentityClaims
+	decodeJWTPart(t, parts[1], &claims)
+	require.Equal(alloc.ID, claims.AllocationID)
+	require.Equal(alloc.JobID, claims.JobID)
+	require.Equal("web", claims.Task)
+	require.Equal(now.Unix(), claims.IssuedAt)
+	require.Equal(now.Add(workloadIdentityTTL).Unix(), claims.Expiry)
+
+	// Verify the signature with the published public key
+	jwk, err := identityPublicKey(key)
+	require.NoError(err)
+	require.Equal(key.KeyID, jwk.KeyID)
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	require.NoError(err)
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	require.NoError(err)
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(err)
+	require.Len(sig, 64)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	require.True(ecdsa.Verify(pub, digest[:], r, s))
+
+	// Another root key doesn't verify the identity
+	other, err := newRootKey()
+	require.NoError(err)
+	otherKey, err := identitySigningKey(other)
+	require.NoError(err)
+	require.False(ecdsa.Verify(&otherKey.PublicKey, digest[:], r, s))
+}
+
+func decodeJWTPart(t *testing.T, part string, out interface{}) {
+	buf, err := base64.RawURLEncoding.DecodeString(part)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(buf, out))
+}
+
+func TestIdentity_Verify(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	key, err := newRootKey()
+	require.NoError(err)
+	alloc := mock.Alloc()
+
+	s := state.TestStateStore(t)
+	require.NoError(s.UpsertRootKey(1, key))
+	require.NoError(s.UpsertAllocs(2, []*structs.Allocation{alloc}))
+	snap, err := s.Snapshot()
+	require.NoError(err)
+
+	claims := structs.NewIdentityClaims(alloc, "web")
+	claims.Audience = structs.IdentityAudienceNomad
+	now := time.Now()
+	jwt, err := signIdentity(key, claims, now, 0)
+	require.NoError(err)
+
+	out, err := verifyIdentity(snap, jwt, now)
+	require.NoError(err)
+	require.Equal(alloc.ID, out.AllocationID)
+	require.Zero(out.Expiry)
+
+	// A tampered identity isn't valid
+	parts := strings.Split(jwt, ".")
+	other := structs.NewIdentityClaims(mock.Alloc(), "web")
+	other.Audience = structs.IdentityAudienceNomad
+	payload, err := json.Marshal(other)
+	require.NoError(err)
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+	_, err = verifyIdentity(snap, tampered, now)
+	require.Equal(structs.ErrTokenNotFound, err)
+
+	// Identities for Vault aren't valid for the Nomad API
+	vaultJWT, err := signIdentity(key, structs.NewIdentityClaims(alloc, "web"), now, workloadIdentityTTL)
+	require.NoError(err)
+	_, err = verifyIdentity(snap, vaultJWT, now)
+	require.Equal(structs.ErrTokenNotFound, err)
+
+	// The identity can read its job only
+	aclObj, err := identityACL(out)
+	require.NoError(err)
+	require.True(aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilityReadJob))
+	require.False(aclObj.AllowJobOp(alloc.Namespace, "other", acl.NamespaceCapabilityReadJob))
+	require.False(aclObj.AllowJobOp(alloc.Namespace, alloc.JobID, acl.NamespaceCapabilitySubmitJob))
+	require.True(aclObj.AllowVariableOperation(alloc.Namespace, "nomad/jobs/"+alloc.JobID+"/db", acl.VariablesCapabilityRead))
+	require.False(aclObj.AllowVariableOperation(alloc.Namespace, "nomad/jobs/"+alloc.JobID+"/db", acl.VariablesCapabilityWrite))
+}
@@ -0,0 +1,70 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// admitJobRules checks the job against the admission rule of its namespace.
+// Jobs using a datacenter or driver that isn't allowed are rejected, while
+// jobs of namespaces restricted to node classes are constrained to them.
+func (j *Job) admitJobRules(job *structs.Job) error {
+	for _, rule := range j.srv.config.JobAdmissionRules {
+		if rule.Namespace == job.Namespace {
+			return applyJobAdmissionRule(rule, job)
+		}
+	}
+	return nil
+}
+
+// applyJobAdmissionRule returns an error if the job doesn't comply with the
+// rule, and adds the node class constraint of the rule to its task groups.
+func applyJobAdmissionRule(rule *config.JobAdmissionRuleConfig, job *structs.Job) error {
+	if len(rule.Datacenters) != 0 {
+		allowed := helper.SliceStringToSet(rule.Datacenters)
+		for _, dc := range job.Datacenters {
+			if _, ok := allowed[dc]; !ok {
+				return fmt.Errorf("job rejected by admission rule of namespace %q: datacenter %q is not allowed",
+					rule.Namespace, dc)
+			}
+		}
+	}
+
+	if len(rule.Drivers) != 0 {
+		allowed := helper.SliceStringToSet(rule.Drivers)
+		for _, tg := range job.TaskGroups {
+			for _, task := range tg.Tasks {
+				if _, ok := allowed[task.Driver]; !ok {
+					return fmt.Errorf("job rejected by admission rule of namespace %q: driver %q of task %q is not allowed",
+						rule.Namespace, task.Driver, task.Name)
+				}
+			}
+		}
+	}
+
+	if len(rule.NodeClasses) != 0 {
+		classConstraint := &structs.Constraint{
+			LTarget: "${node.class}",
+			RTarget: strings.Join(rule.NodeClasses, ","),
+			Operand: structs.ConstraintSetContainsAny,
+		}
+		for _, tg := range job.TaskGroups {
+			found := false
+			for _, c := range tg.Constraints {
+				if c.Equal(classConstraint) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				tg.Constraints = append(tg.Constraints, classConstraint.Copy())
+			}
+		}
+	}
+	return nil
+}
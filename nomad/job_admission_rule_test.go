@@ -0,0 +1,79 @@
+package nomad
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobEndpoint_Register_AdmissionRules(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobAdmissionRules = []*config.JobAdmissionRuleConfig{
+			{
+				Namespace:   structs.DefaultNamespace,
+				Datacenters: []string{"dc1", "dc2"},
+				NodeClasses: []string{"sandbox", "batch"},
+				Drivers:     []string{"exec"},
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(job *structs.Job) error {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+
+	// A complying job is constrained to the allowed node classes
+	job := mock.Job()
+	require.NoError(register(job))
+
+	out, err := s1.fsm.State().JobByID(memdb.NewWatchSet(), job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Contains(out.TaskGroups[0].Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "sandbox,batch",
+		Operand: structs.ConstraintSetContainsAny,
+	})
+
+	// Registering the job again doesn't add the constraint twice
+	job = out.Copy()
+	require.NoError(register(job))
+	out, err = s1.fsm.State().JobByID(memdb.NewWatchSet(), job.Namespace, job.ID)
+	require.NoError(err)
+	require.Len(out.TaskGroups[0].Constraints, len(job.TaskGroups[0].Constraints))
+
+	// Jobs using a datacenter that isn't allowed are rejected
+	job = mock.Job()
+	job.Datacenters = []string{"dc3"}
+	err = register(job)
+	require.Error(err)
+	require.Contains(err.Error(), `datacenter "dc3" is not allowed`)
+
+	// Jobs using a driver that isn't allowed are rejected
+	job = mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "raw_exec"
+	err = register(job)
+	require.Error(err)
+	require.Contains(err.Error(), `driver "raw_exec" of task "web" is not allowed`)
+}
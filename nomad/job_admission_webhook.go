@@ -0,0 +1,174 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// jobAdmissionWebhookClient is the HTTP client used to call the job admission
+// webhooks. Each call is bounded by the webhook's timeout.
+var jobAdmissionWebhookClient = &http.Client{}
+
+// jobAdmissionWebhookRequest is the body POSTed to a job admission webhook.
+type jobAdmissionWebhookRequest struct {
+	Job *structs.Job
+}
+
+// jobAdmissionWebhookResponse is the body a job admission webhook replies
+// with.
+type jobAdmissionWebhookResponse struct {
+	// Allowed must be true for the job to be admitted.
+	Allowed bool
+
+	// Message explains why the job was rejected.
+	Message string
+
+	// Warnings are returned to the user registering the job.
+	Warnings []string
+
+	// Job is the modified job returned by a mutating webhook. If nil, the job
+	// is not modified.
+	Job *structs.Job
+}
+
+// admitJobWebhooks sends the job to the configured admission webhooks. The
+// mutating webhooks are called first, in order, each receiving the job as
+// modified by the previous ones. The modified job is then canonicalized and
+// validated again before the validating webhooks are called with it. The job
+// to register is returned along with any warnings.
+func (j *Job) admitJobWebhooks(job *structs.Job) (*structs.Job, error, error) {
+	webhooks := j.srv.config.JobAdmissionWebhooks
+	if len(webhooks) == 0 {
+		return job, nil, nil
+	}
+
+	// Never send the Vault token to the webhooks
+	vaultToken := job.VaultToken
+	job = job.Copy()
+	job.VaultToken = ""
+
+	var warnings multierror.Error
+	mutated := false
+	for _, webhook := range webhooks {
+		if webhook.Type != config.JobAdmissionWebhookMutating {
+			continue
+		}
+
+		resp, err := j.callJobAdmissionWebhook(webhook, job, &warnings)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil || resp.Job == nil {
+			continue
+		}
+
+		if resp.Job.ID != job.ID || resp.Job.Namespace != job.Namespace {
+			return nil, nil, fmt.Errorf("admission webhook %q must not change the job ID or namespace", webhook.Name)
+		}
+		job = resp.Job
+		mutated = true
+	}
+
+	if mutated {
+		if warn := job.Canonicalize(); warn != nil {
+			multierror.Append(&warnings, warn)
+		}
+		setImplicitConstraints(job)
+		err, warn := validateJob(job)
+		if err != nil {
+			return nil, nil, fmt.Errorf("job modified by admission webhooks is invalid: %v", err)
+		}
+		if warn != nil {
+			multierror.Append(&warnings, warn)
+		}
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.Type != config.JobAdmissionWebhookValidating {
+			continue
+		}
+
+		if _, err := j.callJobAdmissionWebhook(webhook, job, &warnings); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	job.VaultToken = vaultToken
+	return job, warnings.ErrorOrNil(), nil
+}
+
+// callJobAdmissionWebhook calls a single webhook and returns an error if it
+// rejects the job. If the webhook can't be called and its failure policy is to
+// ignore failures, a warning is added and a nil response is returned.
+func (j *Job) callJobAdmissionWebhook(webhook *config.JobAdmissionWebhookConfig,
+	job *structs.Job, warnings *multierror.Error) (*jobAdmissionWebhookResponse, error) {
+
+	resp, err := postJobAdmissionWebhook(webhook, job)
+	if err != nil {
+		if webhook.FailurePolicy == config.JobAdmissionWebhookFailurePolicyIgnore {
+			j.logger.Warn("ignoring failed admission webhook", "webhook", webhook.Name, "job", job.ID, "error", err)
+			multierror.Append(warnings, fmt.Errorf("admission webhook %q was skipped: %v", webhook.Name, err))
+			return nil, nil
+		}
+		j.logger.Error("admission webhook failed", "webhook", webhook.Name, "job", job.ID, "error", err)
+		return nil, fmt.Errorf("admission webhook %q failed: %v", webhook.Name, err)
+	}
+
+	for _, warn := range resp.Warnings {
+		multierror.Append(warnings, fmt.Errorf("admission webhook %q: %s", webhook.Name, warn))
+	}
+	if !resp.Allowed {
+		return nil, fmt.Errorf("job rejected by admission webhook %q: %s", webhook.Name, resp.Message)
+	}
+	return resp, nil
+}
+
+// postJobAdmissionWebhook POSTs the job to the webhook and decodes its
+// response.
+func postJobAdmissionWebhook(webhook *config.JobAdmissionWebhookConfig, job *structs.Job) (*jobAdmissionWebhookResponse, error) {
+	defer metrics.MeasureSinceWithLabels([]string{"nomad", "job", "admission_webhook"}, time.Now(),
+		[]metrics.Label{{Name: "webhook", Value: webhook.Name}})
+
+	body, err := json.Marshal(&jobAdmissionWebhookRequest{Job: job})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhook.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := jobAdmissionWebhookClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var out jobAdmissionWebhookResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &out, nil
+}
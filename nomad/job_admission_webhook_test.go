@@ -0,0 +1,154 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAdmissionWebhook starts a webhook server that decodes the job and
+// replies with the response built by the handler.
+func newTestAdmissionWebhook(t *testing.T, handler func(job *structs.Job) *jobAdmissionWebhookResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var args jobAdmissionWebhookRequest
+		if err := json.NewDecoder(req.Body).Decode(&args); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(handler(args.Job))
+	}))
+}
+
+func TestJobEndpoint_Register_AdmissionWebhooks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// The mutating webhook sets a meta value and the validating webhook
+	// requires it
+	mutating := newTestAdmissionWebhook(t, func(job *structs.Job) *jobAdmissionWebhookResponse {
+		if job.VaultToken != "" {
+			return &jobAdmissionWebhookResponse{Message: "received vault token"}
+		}
+		if job.Meta == nil {
+			job.Meta = make(map[string]string)
+		}
+		job.Meta["team"] = "platform"
+		return &jobAdmissionWebhookResponse{Allowed: true, Job: job}
+	})
+	defer mutating.Close()
+
+	validating := newTestAdmissionWebhook(t, func(job *structs.Job) *jobAdmissionWebhookResponse {
+		if job.Meta["team"] == "" {
+			return &jobAdmissionWebhookResponse{Message: "missing team meta"}
+		}
+		if job.TaskGroups[0].Tasks[0].Driver == "raw_exec" {
+			return &jobAdmissionWebhookResponse{Message: "raw_exec is forbidden"}
+		}
+		return &jobAdmissionWebhookResponse{Allowed: true, Warnings: []string{"checked"}}
+	})
+	defer validating.Close()
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobAdmissionWebhooks = []*config.JobAdmissionWebhookConfig{
+			{
+				Name:          "validate",
+				Type:          config.JobAdmissionWebhookValidating,
+				URL:           validating.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.JobAdmissionWebhookFailurePolicyFail,
+			},
+			{
+				Name:          "mutate",
+				Type:          config.JobAdmissionWebhookMutating,
+				URL:           mutating.URL,
+				Timeout:       5 * time.Second,
+				FailurePolicy: config.JobAdmissionWebhookFailurePolicyFail,
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The mutated job is admitted and registered
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(resp.Warnings, `admission webhook "validate": checked`)
+
+	out, err := s1.fsm.State().JobByID(memdb.NewWatchSet(), job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("platform", out.Meta["team"])
+
+	// The validating webhook rejects the job
+	job = mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "raw_exec"
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `job rejected by admission webhook "validate": raw_exec is forbidden`)
+}
+
+func TestJobEndpoint_Register_AdmissionWebhookFailurePolicy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	webhook := &config.JobAdmissionWebhookConfig{
+		Name:          "down",
+		Type:          config.JobAdmissionWebhookValidating,
+		URL:           failing.URL,
+		Timeout:       5 * time.Second,
+		FailurePolicy: config.JobAdmissionWebhookFailurePolicyFail,
+	}
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobAdmissionWebhooks = []*config.JobAdmissionWebhookConfig{webhook}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Failing closed rejects the job
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `admission webhook "down" failed`)
+
+	// Failing open admits the job with a warning
+	webhook.FailurePolicy = config.JobAdmissionWebhookFailurePolicyIgnore
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.Contains(resp.Warnings, `admission webhook "down" was skipped`)
+}
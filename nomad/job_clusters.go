@@ -0,0 +1,59 @@
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// applyNamespaceDefaults sets the Vault cluster of the tasks and the Consul
+// cluster of the groups that don't select a cluster to the defaults of the
+// job's namespace. The Consul cluster is only set on groups using Consul.
+func (j *Job) applyNamespaceDefaults(job *structs.Job) {
+	for _, defaults := range j.srv.config.NamespaceDefaults {
+		if defaults.Namespace != job.Namespace {
+			continue
+		}
+
+		for _, tg := range job.TaskGroups {
+			if defaults.ConsulCluster != "" && tg.UsesConsul() {
+				if tg.Consul == nil {
+					tg.Consul = &structs.Consul{}
+				}
+				if tg.Consul.Cluster == "" {
+					tg.Consul.Cluster = defaults.ConsulCluster
+				}
+			}
+
+			for _, task := range tg.Tasks {
+				if defaults.VaultCluster != "" && task.Vault != nil && task.Vault.Cluster == "" {
+					task.Vault.Cluster = defaults.VaultCluster
+				}
+			}
+		}
+		return
+	}
+}
+
+// validateConsulClusters returns an error if a group of the job selects a
+// Consul cluster the servers aren't configured with.
+func (j *Job) validateConsulClusters(job *structs.Job) error {
+	for _, tg := range job.TaskGroups {
+		cluster := tg.ConsulCluster()
+		if cluster == structs.ConsulDefaultCluster {
+			continue
+		}
+
+		found := false
+		for _, conf := range j.srv.config.ConsulClusters {
+			if conf.Name == cluster {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Task group %q: Consul cluster %q not configured", tg.Name, cluster)
+		}
+	}
+	return nil
+}
@@ -2,9 +2,13 @@ package nomad
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -27,8 +31,8 @@ const (
 	// enforcing the job modify index during registers.
 	RegisterEnforceIndexErrPrefix = "Enforcing job modify index"
 
-	// DispatchPayloadSizeLimit is the maximum size of the uncompressed input
-	// data payload.
+	// DispatchPayloadSizeLimit is the default maximum size of the
+	// uncompressed input data payload.
 	DispatchPayloadSizeLimit = 16 * 1024
 )
 
@@ -41,6 +45,33 @@ var (
 		Operand: structs.ConstraintVersion,
 	}
 
+	// dockerPrivilegedConstraint is the implicit constraint added to jobs
+	// running docker tasks in privileged mode
+	dockerPrivilegedConstraint = &structs.Constraint{
+		LTarget: "${attr.driver.docker.privileged.enabled}",
+		RTarget: "true",
+		Operand: "=",
+	}
+
+	// dockerHostNetworkConstraint is the implicit constraint added to jobs
+	// running docker tasks in the host network mode.
+	//
+	// COMPAT: Clients prior to 0.9 don't fingerprint whether host networking is
+	// allowed but always allow it, so only exclude the nodes disallowing it.
+	dockerHostNetworkConstraint = &structs.Constraint{
+		LTarget: "${attr.driver.docker.host_network.enabled}",
+		RTarget: "false",
+		Operand: "!=",
+	}
+
+	// realtimeConstraint is the implicit constraint added to jobs running
+	// tasks with the real-time scheduling policies
+	realtimeConstraint = &structs.Constraint{
+		LTarget: "${attr.cpu.realtime}",
+		RTarget: "true",
+		Operand: "=",
+	}
+
 	// allowRescheduleTransition is the transition that allows failed
 	// allocations to be force rescheduled. We create a one off
 	// variable to avoid creating a new object for every request.
@@ -53,10 +84,21 @@ var (
 type Job struct {
 	srv    *Server
 	logger log.Logger
+
+	// dispatchLock serializes the dispatches with an idempotency token so
+	// that concurrent requests with the same token dispatch a single job.
+	dispatchLock sync.Mutex
 }
 
 // Register is used to upsert a job for scheduling
 func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	return j.register(args, reply, false)
+}
+
+// register upserts a job for scheduling. Reverts set revert so that the job
+// version being reverted to keeps the signature verified when it was
+// registered.
+func (j *Job) register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse, revert bool) error {
 	if done, err := j.srv.forward("Job.Register", args, args, reply); done {
 		return err
 	}
@@ -70,6 +112,23 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Initialize the job fields (sets defaults and any necessary init work).
 	canonicalizeWarnings := args.Job.Canonicalize()
 
+	// Verify the signature of the job before the server modifies it
+	var signature *jobSignature
+	if signer := args.Job.Meta[structs.JobMetaSigner]; revert && signer != "" {
+		signature = &jobSignature{
+			Signer: signer,
+			Digest: args.Job.Meta[structs.JobMetaSignatureDigest],
+		}
+	} else {
+		var err error
+		if signature, err = j.verifyJobSignature(args); err != nil {
+			return err
+		}
+	}
+
+	// Select the clusters of the namespace defaults
+	j.applyNamespaceDefaults(args.Job)
+
 	// Add implicit constraints
 	setImplicitConstraints(args.Job)
 
@@ -78,6 +137,17 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	if err != nil {
 		return err
 	}
+	if err := j.validateConsulClusters(args.Job); err != nil {
+		return err
+	}
+
+	// Drop job files too large to be stored
+	if sub := args.Submission; sub != nil && sub.Size() > structs.JobSubmissionMaxSize {
+		args.Submission = nil
+		warnings = multierror.Append(warnings, fmt.Errorf(
+			"job file not stored, its size of %d bytes exceeds the limit of %d bytes",
+			sub.Size(), structs.JobSubmissionMaxSize))
+	}
 
 	// Set the warning message
 	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
@@ -86,7 +156,13 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		if !aclObj.AllowJobOp(args.RequestNamespace(), args.Job.ID, acl.NamespaceCapabilitySubmitJob) {
+			return structs.ErrPermissionDenied
+		}
+		// Jobs may always be submitted to the default node pool so that
+		// existing policies keep working.
+		if pool := args.Job.NodePool; pool != structs.NodePoolDefault &&
+			!aclObj.AllowNodePoolOp(pool, acl.NodePoolCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
 		// Check if override is set and we do not have permissions
@@ -99,6 +175,30 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
+	// Enforce the write rate limits of the token and namespace
+	if err := j.srv.writeLimiter.allow(args.AuthToken, args.RequestNamespace()); err != nil {
+		return err
+	}
+
+	// Run the job through the admission webhooks
+	job, webhookWarnings, err := j.admitJobWebhooks(args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+	if webhookWarnings != nil {
+		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
+			canonicalizeWarnings, webhookWarnings)
+	}
+
+	// Enforce the admission rule of the namespace
+	if err := j.admitJobRules(args.Job); err != nil {
+		return err
+	}
+
+	// Record the verified signature
+	recordJobSignature(args.Job, signature)
+
 	// Lookup the job
 	snap, err := j.srv.State().Snapshot()
 	if err != nil {
@@ -110,6 +210,13 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Ensure the node pool exists
+	if pool, err := snap.NodePoolByName(ws, args.Job.NodePool); err != nil {
+		return err
+	} else if pool == nil {
+		return fmt.Errorf("node pool %q does not exist", args.Job.NodePool)
+	}
+
 	// If EnforceIndex set, check it before trying to apply
 	if args.EnforceIndex {
 		jmi := args.JobModifyIndex
@@ -130,43 +237,23 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
-	// Ensure that the job has permissions for the requested Vault tokens
-	policies := args.Job.VaultPolicies()
-	if len(policies) != 0 {
-		vconf := j.srv.config.VaultConfig
-		if !vconf.IsEnabled() {
-			return fmt.Errorf("Vault not enabled and Vault policies requested")
-		}
-
-		// Have to check if the user has permissions
-		if !vconf.AllowsUnauthenticated() {
-			if args.Job.VaultToken == "" {
-				return fmt.Errorf("Vault policies requested but missing Vault Token")
-			}
-
-			vault := j.srv.vault
-			s, err := vault.LookupToken(context.Background(), args.Job.VaultToken)
-			if err != nil {
-				return err
-			}
-
-			allowedPolicies, err := PoliciesFrom(s)
-			if err != nil {
+	// Keep the pinned minimum version, which can only be set through
+	// Job.PinVersion, and protect it against stale specs
+	args.Job.MinimumVersion = 0
+	if existingJob != nil {
+		args.Job.MinimumVersion = existingJob.MinimumVersion
+		if !args.OverrideMinimumVersion {
+			if err := validateJobMinimumVersion(snap, existingJob, args.Job); err != nil {
 				return err
 			}
-
-			// If we are given a root token it can access all policies
-			if !lib.StrContains(allowedPolicies, "root") {
-				flatPolicies := structs.VaultPoliciesSet(policies)
-				subset, offending := helper.SliceStringIsSubset(allowedPolicies, flatPolicies)
-				if !subset {
-					return fmt.Errorf("Passed Vault Token doesn't allow access to the following policies: %s",
-						strings.Join(offending, ", "))
-				}
-			}
 		}
 	}
 
+	// Ensure that the job has permissions for the requested Vault tokens
+	if err := j.validateVaultPolicies(args.Job); err != nil {
+		return err
+	}
+
 	// Enforce Sentinel policies
 	policyWarnings, err := j.enforceSubmitJob(args.PolicyOverride, args.Job)
 	if err != nil {
@@ -174,7 +261,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	}
 	if policyWarnings != nil {
 		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+			canonicalizeWarnings, webhookWarnings, policyWarnings)
 	}
 
 	// Clear the Vault token
@@ -248,29 +335,89 @@ func setImplicitConstraints(j *structs.Job) {
 	// Get the required signals
 	signals := j.RequiredSignals()
 
+	// Get the required docker options
+	docker := dockerConstraints(j)
+
+	// Get the required named Consul clusters
+	consul := consulClusterConstraints(j)
+
+	// Get the task groups requiring real-time scheduling
+	realtime := realtimeTaskGroups(j)
+
 	// Hot path
-	if len(signals) == 0 && len(policies) == 0 {
+	if len(signals) == 0 && len(policies) == 0 && len(docker) == 0 && len(consul) == 0 && len(realtime) == 0 {
 		return
 	}
 
-	// Add Vault constraints
+	// Add Vault constraints for each Vault cluster of the group
 	for _, tg := range j.TaskGroups {
-		_, ok := policies[tg.Name]
+		tgPolicies, ok := policies[tg.Name]
 		if !ok {
 			// Not requesting Vault
 			continue
 		}
 
+		clusters := make([]string, 0, len(tgPolicies))
+		for _, v := range tgPolicies {
+			if !lib.StrContains(clusters, v.GetCluster()) {
+				clusters = append(clusters, v.GetCluster())
+			}
+		}
+		sort.Strings(clusters)
+
+		for _, cluster := range clusters {
+			constraint := vaultClusterConstraint(cluster)
+
+			found := false
+			for _, c := range tg.Constraints {
+				if c.Equal(constraint) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				tg.Constraints = append(tg.Constraints, constraint)
+			}
+		}
+	}
+
+	// Add Consul cluster constraints
+	for _, tg := range j.TaskGroups {
+		constraint, ok := consul[tg.Name]
+		if !ok {
+			continue
+		}
+
+		found := false
+		for _, c := range tg.Constraints {
+			if c.Equal(constraint) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			tg.Constraints = append(tg.Constraints, constraint)
+		}
+	}
+
+	// Add real-time scheduling constraints
+	for _, tg := range j.TaskGroups {
+		if _, ok := realtime[tg.Name]; !ok {
+			continue
+		}
+
 		found := false
 		for _, c := range tg.Constraints {
-			if c.Equal(vaultConstraint) {
+			if c.Equal(realtimeConstraint) {
 				found = true
 				break
 			}
 		}
 
 		if !found {
-			tg.Constraints = append(tg.Constraints, vaultConstraint)
+			tg.Constraints = append(tg.Constraints, realtimeConstraint)
 		}
 	}
 
@@ -298,6 +445,69 @@ func setImplicitConstraints(j *structs.Job) {
 			tg.Constraints = append(tg.Constraints, sigConstraint)
 		}
 	}
+
+	// Add docker constraints
+	for _, tg := range j.TaskGroups {
+		for _, dockerConstraint := range docker[tg.Name] {
+			found := false
+			for _, c := range tg.Constraints {
+				if c.Equal(dockerConstraint) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				tg.Constraints = append(tg.Constraints, dockerConstraint)
+			}
+		}
+	}
+}
+
+// vaultClusterConstraint returns the implicit constraint of the tasks using
+// the Vault cluster, which requires the nodes to have fingerprinted it.
+func vaultClusterConstraint(cluster string) *structs.Constraint {
+	if cluster == structs.VaultDefaultCluster {
+		return vaultConstraint
+	}
+	return &structs.Constraint{
+		LTarget: fmt.Sprintf("${attr.vault.%s.version}", cluster),
+		RTarget: vaultConstraint.RTarget,
+		Operand: structs.ConstraintVersion,
+	}
+}
+
+// consulClusterConstraints returns the implicit constraints of the task
+// groups using a named Consul cluster, which restrict them to the nodes that
+// fingerprinted the cluster.
+func consulClusterConstraints(j *structs.Job) map[string]*structs.Constraint {
+	constraints := make(map[string]*structs.Constraint)
+	for _, tg := range j.TaskGroups {
+		cluster := tg.ConsulCluster()
+		if cluster == structs.ConsulDefaultCluster || !tg.UsesConsul() {
+			continue
+		}
+		constraints[tg.Name] = &structs.Constraint{
+			LTarget: fmt.Sprintf("${attr.consul.%s.version}", cluster),
+			Operand: structs.ConstraintAttributeIsSet,
+		}
+	}
+	return constraints
+}
+
+// realtimeTaskGroups returns the names of the task groups with tasks using
+// the real-time scheduling policies.
+func realtimeTaskGroups(j *structs.Job) map[string]struct{} {
+	groups := make(map[string]struct{})
+	for _, tg := range j.TaskGroups {
+		for _, task := range tg.Tasks {
+			if task.Resources != nil && task.Resources.Realtime {
+				groups[tg.Name] = struct{}{}
+				break
+			}
+		}
+	}
+	return groups
 }
 
 // getSignalConstraint builds a suitable constraint based on the required
@@ -311,6 +521,101 @@ func getSignalConstraint(signals []string) *structs.Constraint {
 	}
 }
 
+// dockerConstraints returns the constraints restricting the docker tasks of
+// each task group to the nodes permitting their privileged mode, host network
+// mode and added capabilities, so that they are not placed on nodes where
+// they would fail to start.
+func dockerConstraints(j *structs.Job) map[string][]*structs.Constraint {
+	var constraints map[string][]*structs.Constraint
+	for _, tg := range j.TaskGroups {
+		var tgConstraints []*structs.Constraint
+		for _, task := range tg.Tasks {
+			if task.Driver != "docker" {
+				continue
+			}
+
+			if privileged, _ := task.Config["privileged"].(bool); privileged {
+				tgConstraints = append(tgConstraints, dockerPrivilegedConstraint)
+			}
+			if mode, _ := task.Config["network_mode"].(string); mode == "host" {
+				tgConstraints = append(tgConstraints, dockerHostNetworkConstraint)
+			}
+			for _, cap := range dockerAddedCaps(task.Config) {
+				tgConstraints = append(tgConstraints, getDockerCapConstraint(cap))
+			}
+		}
+
+		if len(tgConstraints) == 0 {
+			continue
+		}
+		if constraints == nil {
+			constraints = make(map[string][]*structs.Constraint)
+		}
+		constraints[tg.Name] = tgConstraints
+	}
+	return constraints
+}
+
+// dockerAddedCaps returns the capabilities added but not dropped by the docker
+// task config, in the format fingerprinted by the docker driver.
+func dockerAddedCaps(config map[string]interface{}) []string {
+	dropped := make(map[string]struct{})
+	for _, cap := range dockerConfigStrings(config["cap_drop"]) {
+		dropped[normalizeDockerCap(cap)] = struct{}{}
+	}
+
+	var caps []string
+	for _, cap := range dockerConfigStrings(config["cap_add"]) {
+		cap = normalizeDockerCap(cap)
+		if _, ok := dropped[cap]; ok || cap == "" {
+			continue
+		}
+		caps = append(caps, cap)
+	}
+	return caps
+}
+
+// dockerConfigStrings returns the strings of a list in the docker task config,
+// which is decoded as a list of interfaces.
+func dockerConfigStrings(raw interface{}) []string {
+	switch v := raw.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		strs := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
+
+// normalizeDockerCap returns the capability in lower case and without its
+// "CAP_" prefix.
+func normalizeDockerCap(cap string) string {
+	cap = strings.ToLower(strings.TrimSpace(cap))
+	return strings.TrimPrefix(cap, "cap_")
+}
+
+// getDockerCapConstraint builds a constraint matching the nodes allowing the
+// docker capability, either explicitly or by allowing all capabilities.
+func getDockerCapConstraint(cap string) *structs.Constraint {
+	allowed := "all"
+	if cap != "all" {
+		allowed = fmt.Sprintf("all|%s", regexp.QuoteMeta(cap))
+	}
+
+	return &structs.Constraint{
+		Operand: structs.ConstraintRegex,
+		LTarget: "${attr.driver.docker.caps}",
+		RTarget: fmt.Sprintf("(^|,)(%s)(,|$)", allowed),
+	}
+}
+
 // Summary retrieves the summary of a job
 func (j *Job) Summary(args *structs.JobSummaryRequest,
 	reply *structs.JobSummaryResponse) error {
@@ -360,13 +665,26 @@ func (j *Job) Summary(args *structs.JobSummaryRequest,
 
 // Validate validates a job
 func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValidateResponse) error {
+	// The admission checks use the Vault client, which is only active on the
+	// leader.
+	if args.Admission {
+		if done, err := j.srv.forward("Job.Validate", args, args, reply); done {
+			return err
+		}
+	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "validate"}, time.Now())
 
-	// Check for read-job permissions
+	// Check for read-job permissions. The admission checks look up the job's
+	// Vault token, so they require the submit-job permissions of Register.
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
-		return structs.ErrPermissionDenied
+	} else if aclObj != nil {
+		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+			return structs.ErrPermissionDenied
+		}
+		if args.Admission && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Initialize the job fields (sets defaults and any necessary init work).
@@ -392,6 +710,17 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 	// Set the warning message
 	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
 	reply.DriverConfigValidated = true
+
+	// Only run the admission checks on valid jobs, since they rely on the job
+	// being well formed.
+	if args.Admission && reply.Error == "" {
+		admissionWarnings, err := j.admissionWarnings(args.Job)
+		if err != nil {
+			return err
+		}
+		reply.AdmissionWarnings = admissionWarnings
+	}
+
 	return nil
 }
 
@@ -405,7 +734,7 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -440,10 +769,18 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 		return fmt.Errorf("job %q in namespace %q at version %d not found", args.JobID, args.RequestNamespace(), args.JobVersion)
 	}
 
+	// Keep the job file of the version being reverted to
+	sub, err := snap.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.JobVersion)
+	if err != nil {
+		return err
+	}
+
 	// Build the register request
 	reg := &structs.JobRegisterRequest{
-		Job:          jobV.Copy(),
-		WriteRequest: args.WriteRequest,
+		Job:                    jobV.Copy(),
+		Submission:             sub.Copy(),
+		OverrideMinimumVersion: args.OverrideMinimumVersion,
+		WriteRequest:           args.WriteRequest,
 	}
 
 	// If the request is enforcing the existing version do a check.
@@ -457,7 +794,7 @@ func (j *Job) Revert(args *structs.JobRevertRequest, reply *structs.JobRegisterR
 	}
 
 	// Register the version.
-	return j.Register(reg, reply)
+	return j.register(reg, reply, true)
 }
 
 // Stable is used to mark the job version as stable
@@ -470,7 +807,7 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 	// Check for read-job permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -506,6 +843,55 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 	return nil
 }
 
+// PinVersion is used to pin the job to a minimum version. Registering a job
+// spec matching an older version is then rejected unless overridden.
+func (j *Job) PinVersion(args *structs.JobPinVersionRequest, reply *structs.JobPinVersionResponse) error {
+	if done, err := j.srv.forward("Job.PinVersion", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "pin_version"}, time.Now())
+
+	// Check for submit-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if args.JobID == "" {
+		return fmt.Errorf("missing job ID for pinning job version")
+	}
+
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	job, err := snap.JobByID(ws, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return fmt.Errorf("job %q not found", args.JobID)
+	}
+	if args.JobVersion > job.Version {
+		return fmt.Errorf("can't pin version %d newer than the current version %d", args.JobVersion, job.Version)
+	}
+
+	// Commit this pin request via Raft
+	_, modifyIndex, err := j.srv.raftApply(structs.JobPinVersionRequestType, args)
+	if err != nil {
+		j.logger.Error("submitting job pin version request failed", "error", err)
+		return err
+	}
+
+	// Setup the reply
+	reply.Index = modifyIndex
+	return nil
+}
+
 // Evaluate is used to force a job for re-evaluation
 func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegisterResponse) error {
 	if done, err := j.srv.forward("Job.Evaluate", args, args, reply); done {
@@ -609,14 +995,22 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilitySubmitJob) {
 		return structs.ErrPermissionDenied
 	}
 
+	// Enforce the write rate limits of the token and namespace
+	if err := j.srv.writeLimiter.allow(args.AuthToken, args.RequestNamespace()); err != nil {
+		return err
+	}
+
 	// Validate the arguments
 	if args.JobID == "" {
 		return fmt.Errorf("missing job ID for deregistering")
 	}
+	if args.ShutdownDelay < 0 {
+		return fmt.Errorf("shutdown delay must not be negative")
+	}
 
 	// Lookup the job
 	snap, err := j.srv.fsm.State().Snapshot()
@@ -629,6 +1023,15 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 		return err
 	}
 
+	// Record the shutdown delay on the running allocations before they are
+	// stopped, so clients deregister their services and wait before killing
+	// their tasks
+	if args.ShutdownDelay > 0 {
+		if err := j.setShutdownDelay(snap, args); err != nil {
+			return err
+		}
+	}
+
 	// Commit this update via Raft
 	_, index, err := j.srv.raftApply(structs.JobDeregisterRequestType, args)
 	if err != nil {
@@ -676,6 +1079,37 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	return nil
 }
 
+// setShutdownDelay sets the shutdown delay of the deregister request as the
+// desired transition of the non-terminal allocations of the job.
+func (j *Job) setShutdownDelay(snap *state.StateSnapshot, args *structs.JobDeregisterRequest) error {
+	allocs, err := snap.AllocsByJob(nil, args.RequestNamespace(), args.JobID, true)
+	if err != nil {
+		return err
+	}
+
+	delay := args.ShutdownDelay
+	transitions := make(map[string]*structs.DesiredTransition)
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() {
+			continue
+		}
+		transitions[alloc.ID] = &structs.DesiredTransition{ShutdownDelay: &delay}
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	req := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs:       transitions,
+		WriteRequest: structs.WriteRequest{Region: args.Region},
+	}
+	if _, _, err := j.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, req); err != nil {
+		j.logger.Error("setting shutdown delay failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 // BatchDeregister is used to remove a set of jobs from the cluster.
 func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *structs.JobBatchDeregisterResponse) error {
 	if done, err := j.srv.forward("Job.BatchDeregister", args, args, reply); done {
@@ -700,7 +1134,7 @@ func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *st
 	// Loop through checking for permissions
 	for jobNS := range args.Jobs {
 		// Check for submit-job permissions
-		if aclObj != nil && !aclObj.AllowNsOp(jobNS.Namespace, acl.NamespaceCapabilitySubmitJob) {
+		if aclObj != nil && !aclObj.AllowJobOp(jobNS.Namespace, jobNS.ID, acl.NamespaceCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
 	}
@@ -758,6 +1192,132 @@ func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *st
 	return nil
 }
 
+// Bulk is used to stop, run or force-evaluate all the jobs of a namespace
+// matching a filter in a single request. The operation is applied to each job
+// through its own endpoint, so the permissions and rate limits of the token
+// apply per job and a failure for one job doesn't stop the others.
+func (j *Job) Bulk(args *structs.JobBulkRequest, reply *structs.JobBulkResponse) error {
+	if done, err := j.srv.forward("Job.Bulk", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "bulk"}, time.Now())
+
+	// Check for list-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate the arguments
+	if err := args.Validate(); err != nil {
+		return err
+	}
+
+	// Find the matching jobs
+	snap, err := j.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	var iter memdb.ResultIterator
+	if args.Prefix != "" {
+		iter, err = snap.JobsByIDPrefix(nil, args.RequestNamespace(), args.Prefix)
+	} else {
+		iter, err = snap.JobsByTag(nil, args.RequestNamespace(), args.Tags[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	var jobs []*structs.Job
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		if job := raw.(*structs.Job); args.Matches(job) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].ID < jobs[k].ID })
+
+	for _, job := range jobs {
+		result := &structs.JobBulkResult{ID: job.ID}
+		reply.Jobs = append(reply.Jobs, result)
+		if args.DryRun {
+			continue
+		}
+
+		evalID, index, err := j.bulkApply(snap, args, job)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		result.EvalID = evalID
+		reply.Index = helper.Uint64Max(reply.Index, index)
+	}
+	return nil
+}
+
+// bulkApply applies the operation of the bulk request to a job, returning the
+// ID of the created evaluation, if any, and the index of the operation.
+func (j *Job) bulkApply(snap *state.StateSnapshot, args *structs.JobBulkRequest, job *structs.Job) (string, uint64, error) {
+	wr := structs.WriteRequest{
+		Region:    args.Region,
+		Namespace: args.RequestNamespace(),
+		AuthToken: args.AuthToken,
+	}
+
+	switch args.Operation {
+	case structs.JobBulkOperationStop:
+		req := &structs.JobDeregisterRequest{
+			JobID:        job.ID,
+			Purge:        args.Purge,
+			WriteRequest: wr,
+		}
+		var resp structs.JobDeregisterResponse
+		if err := j.Deregister(req, &resp); err != nil {
+			return "", 0, err
+		}
+		return resp.EvalID, helper.Uint64Max(resp.JobModifyIndex, resp.Index), nil
+
+	case structs.JobBulkOperationRun:
+		// Register the current version of the job again, keeping its job
+		// file and the signature it was registered with
+		sub, err := snap.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+		if err != nil {
+			return "", 0, err
+		}
+		running := job.Copy()
+		running.Stop = false
+		req := &structs.JobRegisterRequest{
+			Job:            running,
+			Submission:     sub.Copy(),
+			EnforceIndex:   true,
+			JobModifyIndex: job.JobModifyIndex,
+			WriteRequest:   wr,
+		}
+		var resp structs.JobRegisterResponse
+		if err := j.register(req, &resp, true); err != nil {
+			return "", 0, err
+		}
+		return resp.EvalID, resp.Index, nil
+
+	case structs.JobBulkOperationEvaluate:
+		req := &structs.JobEvaluateRequest{
+			JobID:        job.ID,
+			EvalOptions:  args.EvalOptions,
+			WriteRequest: wr,
+		}
+		var resp structs.JobRegisterResponse
+		if err := j.Evaluate(req, &resp); err != nil {
+			return "", 0, err
+		}
+		return resp.EvalID, resp.Index, nil
+	}
+	return "", 0, fmt.Errorf("invalid bulk operation %q", args.Operation)
+}
+
 // GetJob is used to request information about a specific job
 func (j *Job) GetJob(args *structs.JobSpecificRequest,
 	reply *structs.SingleJobResponse) error {
@@ -767,11 +1327,13 @@ func (j *Job) GetJob(args *structs.JobSpecificRequest,
 	defer metrics.MeasureSince([]string{"nomad", "job", "get_job"}, time.Now())
 
 	// Check for read-job permissions
-	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
+	redact := j.srv.redactedEnvVars(aclObj)
 
 	// Setup the blocking query
 	opts := blockingOptions{
@@ -785,7 +1347,7 @@ func (j *Job) GetJob(args *structs.JobSpecificRequest,
 			}
 
 			// Setup the output
-			reply.Job = out
+			reply.Job = redactJobEnv(out, redact)
 			if out != nil {
 				reply.Index = out.ModifyIndex
 			} else {
@@ -813,11 +1375,13 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_versions"}, time.Now())
 
 	// Check for read-job permissions
-	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
 	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
 		return structs.ErrPermissionDenied
 	}
+	redact := j.srv.redactedEnvVars(aclObj)
 
 	// Setup the blocking query
 	opts := blockingOptions{
@@ -832,6 +1396,12 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 
 			// Setup the output
 			reply.Versions = out
+			if len(redact) != 0 {
+				reply.Versions = make([]*structs.Job, len(out))
+				for i, job := range out {
+					reply.Versions[i] = redactJobEnv(job, redact)
+				}
+			}
 			if len(out) != 0 {
 				reply.Index = out[0].ModifyIndex
 
@@ -843,6 +1413,7 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 						if err != nil {
 							return fmt.Errorf("failed to create job diff: %v", err)
 						}
+						redactJobDiffEnv(d, redact)
 						reply.Diffs = append(reply.Diffs, d)
 					}
 				}
@@ -862,6 +1433,47 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobSubmission is used to retrieve the job file a job version was
+// registered with
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward("Job.GetJobSubmission", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.JobSubmission(ws, args.RequestNamespace(), args.JobID, args.Version)
+			if err != nil {
+				return err
+			}
+
+			// Use the last index that affected the job submissions table
+			reply.Submission = out
+			index, err := state.Index("job_submission")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // List is used to list the jobs registered in the system
 func (j *Job) List(args *structs.JobListRequest,
 	reply *structs.JobListResponse) error {
@@ -885,8 +1497,15 @@ func (j *Job) List(args *structs.JobListRequest,
 			// Capture all the jobs
 			var err error
 			var iter memdb.ResultIterator
-			if prefix := args.QueryOptions.Prefix; prefix != "" {
+			prefix := args.QueryOptions.Prefix
+			if prefix == "" && args.ParentID != "" {
+				// The IDs of child jobs are prefixed by the ID of their parent
+				prefix = args.ParentID + "/"
+			}
+			if prefix != "" {
 				iter, err = state.JobsByIDPrefix(ws, args.RequestNamespace(), prefix)
+			} else if len(args.Tags) != 0 {
+				iter, err = state.JobsByTag(ws, args.RequestNamespace(), args.Tags[0])
 			} else {
 				iter, err = state.JobsByNamespace(ws, args.RequestNamespace())
 			}
@@ -894,13 +1513,23 @@ func (j *Job) List(args *structs.JobListRequest,
 				return err
 			}
 
+			// Jobs are iterated in ID order, so a page resumes at the ID of
+			// the first job that did not fit in the previous one.
 			var jobs []*structs.JobListStub
+			reply.NextToken = ""
 			for {
 				raw := iter.Next()
 				if raw == nil {
 					break
 				}
 				job := raw.(*structs.Job)
+				if job.ID < args.NextToken || !args.Matches(job) {
+					continue
+				}
+				if args.PerPage > 0 && len(jobs) == int(args.PerPage) {
+					reply.NextToken = job.ID
+					break
+				}
 				summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
 				if err != nil {
 					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
@@ -976,6 +1605,62 @@ func (j *Job) Allocations(args *structs.JobSpecificRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// RescheduleStatus is used to return the reschedule tracker state of the
+// latest allocation of each reschedule lineage of a job. Only lineages that
+// have failed or been rescheduled are returned.
+func (j *Job) RescheduleStatus(args *structs.JobSpecificRequest,
+	reply *structs.JobRescheduleStatusResponse) error {
+	if done, err := j.srv.forward("Job.RescheduleStatus", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "reschedule_status"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			allocs, err := state.AllocsByJob(ws, args.RequestNamespace(), args.JobID, false)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			reply.Allocs = nil
+			for _, alloc := range allocs {
+				// Skip allocations that have been replaced
+				if alloc.NextAllocation != "" {
+					continue
+				}
+
+				rescheduled := alloc.RescheduleTracker != nil && len(alloc.RescheduleTracker.Events) > 0
+				if alloc.ClientStatus != structs.AllocClientStatusFailed && !rescheduled {
+					continue
+				}
+				reply.Allocs = append(reply.Allocs, alloc.RescheduleStatus(now))
+			}
+
+			// Use the last index that affected the allocs table
+			index, err := state.Index("allocs")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // Evaluations is used to list the evaluations for a job
 func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 	reply *structs.JobEvaluationsResponse) error {
@@ -1123,6 +1808,9 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	// Initialize the job fields (sets defaults and any necessary init work).
 	canonicalizeWarnings := args.Job.Canonicalize()
 
+	// Select the clusters of the namespace defaults
+	j.applyNamespaceDefaults(args.Job)
+
 	// Add implicit constraints
 	setImplicitConstraints(args.Job)
 
@@ -1131,6 +1819,9 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	if err != nil {
 		return err
 	}
+	if err := j.validateConsulClusters(args.Job); err != nil {
+		return err
+	}
 
 	// Set the warning message
 	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
@@ -1139,7 +1830,7 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil {
-		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		if !aclObj.AllowJobOp(args.RequestNamespace(), args.Job.ID, acl.NamespaceCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
 		// Check if override is set and we do not have permissions
@@ -1261,6 +1952,221 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	return nil
 }
 
+// validateVaultPolicies ensures that the job's Vault token has permissions
+// for the Vault policies requested by the job in each Vault cluster.
+func (j *Job) validateVaultPolicies(job *structs.Job) error {
+	policies := job.VaultPolicies()
+	if len(policies) == 0 {
+		return nil
+	}
+
+	// Group the Vault blocks by cluster
+	clusters := make(map[string][]*structs.Vault)
+	for _, tg := range policies {
+		for _, v := range tg {
+			clusters[v.GetCluster()] = append(clusters[v.GetCluster()], v)
+		}
+	}
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := j.validateVaultClusterPolicies(job, name, clusters[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateVaultClusterPolicies ensures that the job's Vault token has
+// permissions for the policies of the Vault blocks using the cluster.
+func (j *Job) validateVaultClusterPolicies(job *structs.Job, cluster string, blocks []*structs.Vault) error {
+	vconf := j.srv.vaultConfig(cluster)
+	if vconf == nil {
+		return fmt.Errorf("Vault cluster %q not configured and Vault policies requested", cluster)
+	}
+	if !vconf.IsEnabled() {
+		return fmt.Errorf("Vault not enabled and Vault policies requested")
+	}
+
+	// Ensure the requested Vault namespaces are allowed by the servers
+	var disallowed []string
+	for _, v := range blocks {
+		if !vconf.AllowsNamespace(v.Namespace) && !lib.StrContains(disallowed, v.Namespace) {
+			disallowed = append(disallowed, v.Namespace)
+		}
+	}
+	if len(disallowed) != 0 {
+		sort.Strings(disallowed)
+		return fmt.Errorf("Vault namespaces not allowed: %s", strings.Join(disallowed, ", "))
+	}
+
+	// Tasks log in with their workload identity, so the policies they get are
+	// decided by the role of the Vault JWT auth method
+	if vconf.UsesIdentity() {
+		return nil
+	}
+
+	// Have to check if the user has permissions
+	if vconf.AllowsUnauthenticated() {
+		return nil
+	}
+
+	if job.VaultToken == "" {
+		return fmt.Errorf("Vault policies requested but missing Vault Token")
+	}
+
+	vault, err := j.srv.vaultClient(cluster)
+	if err != nil {
+		return err
+	}
+	s, err := vault.LookupToken(context.Background(), job.VaultToken)
+	if err != nil {
+		return err
+	}
+
+	allowedPolicies, err := PoliciesFrom(s)
+	if err != nil {
+		return err
+	}
+
+	// If we are given a root token it can access all policies
+	if !lib.StrContains(allowedPolicies, "root") {
+		var flatPolicies []string
+		for _, v := range blocks {
+			for _, p := range v.Policies {
+				if !lib.StrContains(flatPolicies, p) {
+					flatPolicies = append(flatPolicies, p)
+				}
+			}
+		}
+		subset, offending := helper.SliceStringIsSubset(allowedPolicies, flatPolicies)
+		if !subset {
+			return fmt.Errorf("Passed Vault Token doesn't allow access to the following policies: %s",
+				strings.Join(offending, ", "))
+		}
+	}
+
+	return nil
+}
+
+// admissionWarnings runs the checks that depend on the state of the cluster
+// and returns a warning for each problem that would either cause the job to be
+// rejected when registered or prevent it from ever being placed.
+func (j *Job) admissionWarnings(job *structs.Job) ([]*structs.JobAdmissionWarning, error) {
+	var warnings []*structs.JobAdmissionWarning
+
+	if err := j.validateNamespace(job); err != nil {
+		warnings = append(warnings, &structs.JobAdmissionWarning{
+			Type:    structs.JobAdmissionWarningNamespace,
+			Message: err.Error(),
+		})
+	}
+
+	if err := j.validateVaultPolicies(job); err != nil {
+		warnings = append(warnings, &structs.JobAdmissionWarning{
+			Type:    structs.JobAdmissionWarningVault,
+			Message: err.Error(),
+		})
+	}
+
+	snap, err := j.srv.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	constraintWarnings, err := infeasibleTaskGroups(snap, job, j.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(warnings, constraintWarnings...), nil
+}
+
+// infeasibleTaskGroups returns a warning for each task group of the job that
+// no node in the job's datacenters can run, because the nodes either do not
+// satisfy the constraints or lack the task drivers. Nodes that are down are not
+// considered, but ineligible and draining nodes are since they may become
+// eligible again.
+func infeasibleTaskGroups(snap *state.StateSnapshot, job *structs.Job, logger log.Logger) ([]*structs.JobAdmissionWarning, error) {
+	ws := memdb.NewWatchSet()
+	iter, err := snap.Nodes(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*structs.Node
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		node := raw.(*structs.Node)
+		if node.Status == structs.NodeStatusDown {
+			continue
+		}
+		if !lib.StrContains(job.Datacenters, node.Datacenter) {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	var warnings []*structs.JobAdmissionWarning
+	for _, tg := range job.TaskGroups {
+		if len(nodes) == 0 {
+			warnings = append(warnings, &structs.JobAdmissionWarning{
+				Type:      structs.JobAdmissionWarningConstraint,
+				TaskGroup: tg.Name,
+				Message: fmt.Sprintf("no nodes are available in datacenters %s",
+					strings.Join(job.Datacenters, ", ")),
+			})
+			continue
+		}
+
+		constraints := append([]*structs.Constraint{}, job.Constraints...)
+		constraints = append(constraints, tg.Constraints...)
+		drivers := make(map[string]struct{})
+		for _, task := range tg.Tasks {
+			drivers[task.Driver] = struct{}{}
+			constraints = append(constraints, task.Constraints...)
+		}
+
+		ctx := scheduler.NewEvalContext(snap, &structs.Plan{}, logger)
+		constraintChecker := scheduler.NewConstraintChecker(ctx, constraints)
+		driverChecker := scheduler.NewDriverChecker(ctx, drivers)
+
+		feasible := false
+		for _, node := range nodes {
+			if driverChecker.Feasible(node) && constraintChecker.Feasible(node) {
+				feasible = true
+				break
+			}
+		}
+		if feasible {
+			continue
+		}
+
+		// Summarize why the nodes were filtered
+		filtered := ctx.Metrics().ConstraintFiltered
+		reasons := make([]string, 0, len(filtered))
+		for reason, count := range filtered {
+			reasons = append(reasons, fmt.Sprintf("%q filtered %d nodes", reason, count))
+		}
+		sort.Strings(reasons)
+
+		warnings = append(warnings, &structs.JobAdmissionWarning{
+			Type:      structs.JobAdmissionWarningConstraint,
+			TaskGroup: tg.Name,
+			Message: fmt.Sprintf("none of the %d nodes in datacenters %s can run the task group: %s",
+				len(nodes), strings.Join(job.Datacenters, ", "), strings.Join(reasons, ", ")),
+		})
+	}
+
+	return warnings, nil
+}
+
 // validateJob validates a Job and task drivers and returns an error if there is
 // a validation problem or if the Job is of a type a user is not allowed to
 // submit.
@@ -1325,6 +2231,38 @@ func validateJobUpdate(old, new *structs.Job) error {
 	return nil
 }
 
+// validateJobMinimumVersion returns an error if the job spec being registered
+// matches a tracked version older than the minimum version the existing job
+// is pinned to, unless it also matches an allowed version. This protects
+// against out of order deploys rolling back the job.
+func validateJobMinimumVersion(snap *state.StateSnapshot, existing, new *structs.Job) error {
+	if existing.MinimumVersion == 0 || !existing.SpecChanged(new) {
+		return nil
+	}
+
+	versions, err := snap.JobVersionsByID(nil, existing.Namespace, existing.ID)
+	if err != nil {
+		return err
+	}
+
+	var stale *structs.Job
+	for _, v := range versions {
+		if v.SpecChanged(new) {
+			continue
+		}
+		if v.Version >= existing.MinimumVersion {
+			return nil
+		}
+		stale = v
+	}
+
+	if stale != nil {
+		return fmt.Errorf("job spec matches version %d which is older than the minimum version %d; override the minimum version to register it anyway",
+			stale.Version, existing.MinimumVersion)
+	}
+	return nil
+}
+
 // Dispatch a parameterized job.
 func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
 	if done, err := j.srv.forward("Job.Dispatch", args, args, reply); done {
@@ -1335,10 +2273,15 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityDispatchJob) {
+	} else if aclObj != nil && !aclObj.AllowJobOp(args.RequestNamespace(), args.JobID, acl.NamespaceCapabilityDispatchJob) {
 		return structs.ErrPermissionDenied
 	}
 
+	// Enforce the write rate limits of the token and namespace
+	if err := j.srv.writeLimiter.allow(args.AuthToken, args.RequestNamespace()); err != nil {
+		return err
+	}
+
 	// Lookup the parameterized job
 	if args.JobID == "" {
 		return fmt.Errorf("missing parameterized job ID")
@@ -1366,10 +2309,21 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	}
 
 	// Validate the arguments
-	if err := validateDispatchRequest(args, parameterizedJob); err != nil {
+	if err := validateDispatchRequest(args, parameterizedJob, j.srv.config.DispatchPayloadSizeLimit); err != nil {
 		return err
 	}
 
+	// Return the job already dispatched with the idempotency token, if any
+	if args.IdempotencyToken != "" {
+		j.dispatchLock.Lock()
+		defer j.dispatchLock.Unlock()
+
+		found, err := j.dispatchedByToken(args, reply)
+		if err != nil || found {
+			return err
+		}
+	}
+
 	// Derive the child job and commit it via Raft
 	dispatchJob := parameterizedJob.Copy()
 	dispatchJob.ID = structs.DispatchedID(parameterizedJob.ID, time.Now())
@@ -1388,6 +2342,9 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 
 	// Compress the payload
 	dispatchJob.Payload = snappy.Encode(nil, args.Payload)
+	payloadHash := sha256.Sum256(args.Payload)
+	dispatchJob.DispatchPayloadHash = hex.EncodeToString(payloadHash[:])
+	dispatchJob.DispatchIdempotencyToken = args.IdempotencyToken
 
 	regReq := &structs.JobRegisterRequest{
 		Job:          dispatchJob,
@@ -1443,9 +2400,56 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	return nil
 }
 
+// dispatchedByToken sets the reply to the job already dispatched from the
+// parameterized job with the idempotency token of the request and its
+// evaluation, and returns whether one was found. It must be called with the
+// dispatch lock held so that the state includes any concurrent dispatch.
+func (j *Job) dispatchedByToken(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) (bool, error) {
+	ws := memdb.NewWatchSet()
+	state := j.srv.fsm.State()
+	iter, err := state.JobsByIDPrefix(ws, args.RequestNamespace(), args.JobID+structs.DispatchLaunchSuffix)
+	if err != nil {
+		return false, err
+	}
+
+	var existing *structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.ParentID == args.JobID && job.DispatchIdempotencyToken == args.IdempotencyToken {
+			existing = job
+			break
+		}
+	}
+	if existing == nil {
+		return false, nil
+	}
+
+	reply.DispatchedJobID = existing.ID
+	reply.JobCreateIndex = existing.CreateIndex
+	reply.Index = existing.CreateIndex
+
+	// The evaluation created by the dispatch is the first one of the job
+	evals, err := state.EvalsByJob(ws, args.RequestNamespace(), existing.ID)
+	if err != nil {
+		return false, err
+	}
+	var first *structs.Evaluation
+	for _, eval := range evals {
+		if first == nil || eval.CreateIndex < first.CreateIndex {
+			first = eval
+		}
+	}
+	if first != nil {
+		reply.EvalID = first.ID
+		reply.EvalCreateIndex = first.CreateIndex
+		reply.Index = helper.Uint64Max(reply.Index, first.CreateIndex)
+	}
+	return true, nil
+}
+
 // validateDispatchRequest returns whether the request is valid given the
-// parameterized job.
-func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job) error {
+// parameterized job and the maximum payload size.
+func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job, payloadLimit int) error {
 	// Check the payload constraint is met
 	hasInputData := len(req.Payload) != 0
 	if job.ParameterizedJob.Payload == structs.DispatchPayloadRequired && !hasInputData {
@@ -1455,8 +2459,8 @@ func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job)
 	}
 
 	// Check the payload doesn't exceed the size limit
-	if l := len(req.Payload); l > DispatchPayloadSizeLimit {
-		return fmt.Errorf("Payload exceeds maximum size; %d > %d", l, DispatchPayloadSizeLimit)
+	if l := len(req.Payload); l > payloadLimit {
+		return fmt.Errorf("Payload exceeds maximum size; %d > %d", l, payloadLimit)
 	}
 
 	// Check if the metadata is a set
@@ -1506,5 +2510,13 @@ func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job)
 		return fmt.Errorf("Dispatch did not provide required meta keys: %v", flat)
 	}
 
-	return nil
+	// Check the metadata values are valid
+	var mErr multierror.Error
+	for _, c := range job.ParameterizedJob.MetaConfigs {
+		if err := c.ValidateDispatch(req.Meta); err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+
+	return mErr.ErrorOrNil()
 }
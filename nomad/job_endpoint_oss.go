@@ -2,9 +2,23 @@
 
 package nomad
 
-import "github.com/hashicorp/nomad/nomad/structs"
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
 
 // enforceSubmitJob is used to check any Sentinel policies for the submit-job scope
 func (j *Job) enforceSubmitJob(override bool, job *structs.Job) (error, error) {
 	return nil, nil
 }
+
+// validateNamespace returns an error if the job's namespace does not exist.
+// Only the default namespace exists without Nomad Enterprise.
+func (j *Job) validateNamespace(job *structs.Job) error {
+	if job.Namespace != structs.DefaultNamespace {
+		return fmt.Errorf("namespace %q does not exist; only the %q namespace is supported",
+			job.Namespace, structs.DefaultNamespace)
+	}
+	return nil
+}
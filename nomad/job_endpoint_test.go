@@ -1,8 +1,12 @@
 package nomad
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +18,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/require"
@@ -141,6 +146,59 @@ func TestJobEndpoint_Register_ACL(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_NodePool(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	pool := mock.NodePool()
+	require.NoError(state.UpsertNodePools(1000, []*structs.NodePool{pool}))
+
+	// A token that may submit jobs but not to the node pool
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob})
+	token := mock.CreatePolicyAndToken(t, state, 1001, "submit", policy)
+
+	job := mock.Job()
+	job.NodePool = pool.Name
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Jobs in the default node pool don't need a node pool policy
+	req.Job = mock.Job()
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Granting submit-job on the node pool allows the registration
+	token = mock.CreatePolicyAndToken(t, state, 1003, "pool",
+		policy+"\n"+mock.NodePoolPolicy(pool.Name, acl.PolicyWrite))
+	req.Job = job
+	req.AuthToken = token.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Jobs can't target a missing node pool
+	missing := mock.Job()
+	missing.NodePool = "missing"
+	req.Job = missing
+	req.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "does not exist")
+}
+
 func TestJobEndpoint_Register_InvalidNamespace(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
@@ -667,6 +725,129 @@ func TestJobEndpoint_Register_Vault_AllowUnauthenticated(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_Vault_Namespace(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Enable vault, allow unauthenticated and a single namespace
+	tr := true
+	s1.config.VaultConfig.Enabled = &tr
+	s1.config.VaultConfig.AllowUnauthenticated = &tr
+	s1.config.VaultConfig.AllowedNamespaces = []string{"engineering"}
+
+	// Replace the Vault Client on the server
+	s1.vault = &TestVaultClient{}
+
+	// Create the register request with a job asking for a disallowed
+	// namespace
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{
+		Policies:   []string{"foo"},
+		Namespace:  "finance",
+		ChangeMode: structs.VaultChangeModeRestart,
+	}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	if err == nil || !strings.Contains(err.Error(), "namespaces not allowed: finance") {
+		t.Fatalf("expected namespace not allowed error: %v", err)
+	}
+
+	// Use an allowed namespace
+	job.TaskGroups[0].Tasks[0].Vault.Namespace = "engineering"
+	if err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+}
+
+func TestJobEndpoint_Register_Clusters(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Add named clusters used by default by the jobs of the default namespace
+	tr := true
+	s1.config.VaultClusters = []*config.VaultConfig{
+		{Name: "secrets", Enabled: &tr, AllowUnauthenticated: &tr},
+	}
+	s1.vaults = map[string]VaultClient{"secrets": &TestVaultClient{}}
+	s1.config.ConsulClusters = []*config.ConsulConfig{{Name: "edge"}}
+	s1.config.NamespaceDefaults = []*config.NamespaceDefaultsConfig{
+		{
+			Namespace:     structs.DefaultNamespace,
+			VaultCluster:  "secrets",
+			ConsulCluster: "edge",
+		},
+	}
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{
+		Policies:   []string{"foo"},
+		ChangeMode: structs.VaultChangeModeRestart,
+	}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// The defaults of the namespace are applied and constrain the job to
+	// the nodes of the clusters
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	tg := out.TaskGroups[0]
+	require.Equal("edge", tg.ConsulCluster())
+	require.Equal("secrets", tg.Tasks[0].Vault.GetCluster())
+
+	var targets []string
+	for _, c := range tg.Constraints {
+		targets = append(targets, c.LTarget)
+	}
+	require.Contains(targets, "${attr.vault.secrets.version}")
+	require.Contains(targets, "${attr.consul.edge.version}")
+
+	// Jobs can't use clusters the servers aren't configured with
+	job = mock.Job()
+	job.TaskGroups[0].Consul = &structs.Consul{Cluster: "unknown"}
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `Consul cluster "unknown" not configured`)
+
+	job = mock.Job()
+	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{
+		Policies:   []string{"foo"},
+		Cluster:    "unknown",
+		ChangeMode: structs.VaultChangeModeRestart,
+	}
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `Vault cluster "unknown" not configured`)
+}
+
 func TestJobEndpoint_Register_Vault_NoToken(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
@@ -1212,6 +1393,111 @@ func TestJobEndpoint_Stable_ACL(t *testing.T) {
 	require.Equal(true, out.Stable)
 }
 
+func TestJobEndpoint_PinVersion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register three versions of the job
+	job := mock.Job()
+	register := func(job *structs.Job, override bool) error {
+		req := &structs.JobRegisterRequest{
+			Job:                    job.Copy(),
+			OverrideMinimumVersion: override,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	}
+	v0 := job.Copy()
+	require.NoError(register(v0, false))
+	v1 := job.Copy()
+	v1.Priority = 60
+	require.NoError(register(v1, false))
+	v2 := job.Copy()
+	v2.Priority = 70
+	require.NoError(register(v2, false))
+
+	// Pinning a version newer than the current one fails
+	pinReq := &structs.JobPinVersionRequest{
+		JobID:      job.ID,
+		JobVersion: 3,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var pinResp structs.JobPinVersionResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.PinVersion", pinReq, &pinResp)
+	require.Error(err)
+	require.Contains(err.Error(), "newer than the current version")
+
+	// Pin the job to version 1
+	pinReq.JobVersion = 1
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.PinVersion", pinReq, &pinResp))
+	require.NotZero(pinResp.Index)
+
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(uint64(1), out.MinimumVersion)
+	require.Equal(uint64(2), out.Version)
+
+	// Registering the spec of version 0 is rejected
+	err = register(v0, false)
+	require.Error(err)
+	require.Contains(err.Error(), "older than the minimum version 1")
+
+	// So is reverting to it
+	revertReq := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var revertResp structs.JobRegisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Revert", revertReq, &revertResp)
+	require.Error(err)
+	require.Contains(err.Error(), "older than the minimum version 1")
+
+	// Specs matching the minimum version or new specs are allowed, and the
+	// job stays pinned
+	require.NoError(register(v1, false))
+	v4 := job.Copy()
+	v4.Priority = 80
+	require.NoError(register(v4, false))
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(uint64(1), out.MinimumVersion)
+	require.Equal(uint64(4), out.Version)
+
+	// The pin can be overridden
+	revertReq.OverrideMinimumVersion = true
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Revert", revertReq, &revertResp))
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(uint64(5), out.Version)
+	require.Equal(v0.Priority, out.Priority)
+
+	// Unpin the job
+	pinReq.JobVersion = 0
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.PinVersion", pinReq, &pinResp))
+	require.NoError(register(v2, false))
+}
+
 func TestJobEndpoint_Evaluate(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
@@ -1611,6 +1897,64 @@ func TestJobEndpoint_Deregister(t *testing.T) {
 	require.Equal(structs.EvalStatusPending, eval.Status)
 }
 
+func TestJobEndpoint_Deregister_ShutdownDelay(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a job with a running and a terminal allocation
+	state := s1.fsm.State()
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	running := mock.Alloc()
+	running.Job = job
+	running.JobID = job.ID
+	stopped := mock.Alloc()
+	stopped.Job = job
+	stopped.JobID = job.ID
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(job.ID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{running, stopped}))
+
+	// Negative delays are rejected
+	dereg := &structs.JobDeregisterRequest{
+		JobID:         job.ID,
+		ShutdownDelay: -time.Second,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobDeregisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "shutdown delay must not be negative")
+
+	// The delay is set on the running allocation before the job is stopped
+	dereg.ShutdownDelay = 30 * time.Second
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &resp))
+
+	out, err := state.AllocByID(nil, running.ID)
+	require.NoError(err)
+	require.NotNil(out.DesiredTransition.ShutdownDelay)
+	require.Equal(30*time.Second, *out.DesiredTransition.ShutdownDelay)
+	require.True(out.ModifyIndex < resp.JobModifyIndex)
+
+	out, err = state.AllocByID(nil, stopped.ID)
+	require.NoError(err)
+	require.Nil(out.DesiredTransition.ShutdownDelay)
+
+	outJob, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.True(outJob.Stop)
+}
+
 func TestJobEndpoint_Deregister_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -2026,6 +2370,173 @@ func TestJobEndpoint_BatchDeregister_ACL(t *testing.T) {
 	require.NotEqual(validResp2.Index, 0)
 }
 
+func TestJobEndpoint_Bulk(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register two tagged jobs and an untagged one
+	var jobs []*structs.Job
+	for i, tags := range [][]string{{"owner:payments"}, {"owner:payments"}, nil} {
+		job := mock.Job()
+		job.ID = fmt.Sprintf("web-%d", i)
+		job.Tags = tags
+		reg := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+		jobs = append(jobs, job)
+	}
+
+	// A dry run returns the matching jobs without stopping them
+	req := &structs.JobBulkRequest{
+		Operation: structs.JobBulkOperationStop,
+		Tags:      []string{"owner:payments"},
+		DryRun:    true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var resp structs.JobBulkResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Len(resp.Jobs, 2)
+	require.Equal("web-0", resp.Jobs[0].ID)
+	require.Equal("web-1", resp.Jobs[1].ID)
+	require.Empty(resp.Jobs[0].EvalID)
+
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, structs.DefaultNamespace, "web-0")
+	require.NoError(err)
+	require.False(out.Stop)
+
+	// Stop the tagged jobs
+	req.DryRun = false
+	resp = structs.JobBulkResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Len(resp.Jobs, 2)
+	require.NotZero(resp.Index)
+	for _, result := range resp.Jobs {
+		require.Empty(result.Error)
+		require.NotEmpty(result.EvalID)
+
+		out, err := state.JobByID(nil, structs.DefaultNamespace, result.ID)
+		require.NoError(err)
+		require.True(out.Stop)
+
+		eval, err := state.EvalByID(nil, result.EvalID)
+		require.NoError(err)
+		require.Equal(structs.EvalTriggerJobDeregister, eval.TriggeredBy)
+	}
+	out, err = state.JobByID(nil, structs.DefaultNamespace, "web-2")
+	require.NoError(err)
+	require.False(out.Stop)
+
+	// Stopped jobs don't match anymore
+	resp = structs.JobBulkResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Empty(resp.Jobs)
+
+	// Run the stopped jobs again
+	req.Operation = structs.JobBulkOperationRun
+	req.Tags = nil
+	req.Prefix = "web-"
+	resp = structs.JobBulkResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Len(resp.Jobs, 2)
+	for _, result := range resp.Jobs {
+		require.Empty(result.Error)
+		require.NotEmpty(result.EvalID)
+
+		out, err := state.JobByID(nil, structs.DefaultNamespace, result.ID)
+		require.NoError(err)
+		require.False(out.Stop)
+		require.EqualValues(2, out.Version)
+	}
+
+	// Force-evaluate all of them
+	req.Operation = structs.JobBulkOperationEvaluate
+	resp = structs.JobBulkResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Len(resp.Jobs, 3)
+	for _, result := range resp.Jobs {
+		require.Empty(result.Error)
+		eval, err := state.EvalByID(nil, result.EvalID)
+		require.NoError(err)
+		require.Equal(structs.EvalTriggerJobRegister, eval.TriggeredBy)
+		require.Equal(result.ID, eval.JobID)
+	}
+
+	// A filter is required
+	req.Prefix = ""
+	err = msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "require a job ID prefix or tags")
+}
+
+func TestJobEndpoint_Bulk_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	req := &structs.JobBulkRequest{
+		Operation: structs.JobBulkOperationStop,
+		Prefix:    job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Expect failure for a request without a token
+	var resp structs.JobBulkResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Tokens that can list but not submit jobs get an error per job
+	listToken := mock.CreatePolicyAndToken(t, state, 1001, "test-list",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	req.AuthToken = listToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Len(resp.Jobs, 1)
+	require.Contains(resp.Jobs[0].Error, "Permission denied")
+
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.False(out.Stop)
+
+	// The job is stopped with a management token
+	req.AuthToken = root.SecretID
+	resp = structs.JobBulkResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Bulk", req, &resp))
+	require.Len(resp.Jobs, 1)
+	require.Empty(resp.Jobs[0].Error)
+
+	out, err = state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.True(out.Stop)
+}
+
 func TestJobEndpoint_GetJob(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -2309,24 +2820,93 @@ func TestJobEndpoint_GetJobVersions(t *testing.T) {
 	}
 }
 
-func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
-
-	s1, root := TestACLServer(t, nil)
+	s1 := TestServer(t, nil)
 	defer s1.Shutdown()
 	codec := rpcClient(t, s1)
 	testutil.WaitForLeader(t, s1.RPC)
-	state := s1.fsm.State()
 
-	// Create two versions of a job with different priorities
+	// Register the job with its job file
 	job := mock.Job()
-	job.Priority = 88
-	err := state.UpsertJob(10, job)
-	require.Nil(err)
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source:    `job "example" {}`,
+			Variables: `count = 2`,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
 
+	// Register a version with a job file too large to be stored
 	job.Priority = 100
-	err = state.UpsertJob(100, job)
+	reg.Submission = &structs.JobSubmission{
+		Source: strings.Repeat("#", structs.JobSubmissionMaxSize+1),
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Contains(resp.Warnings, "job file not stored")
+
+	get := &structs.JobSubmissionRequest{
+		JobID:   job.ID,
+		Version: 0,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var subResp structs.JobSubmissionResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.NotNil(subResp.Submission)
+	require.Equal(`job "example" {}`, subResp.Submission.Source)
+	require.Equal(`count = 2`, subResp.Submission.Variables)
+	require.EqualValues(0, subResp.Submission.Version)
+
+	get.Version = 1
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.Nil(subResp.Submission)
+
+	// Reverting keeps the job file of the version reverted to
+	revert := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Revert", revert, &resp))
+
+	get.Version = 2
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.NotNil(subResp.Submission)
+	require.Equal(`job "example" {}`, subResp.Submission.Source)
+	require.EqualValues(2, subResp.Submission.Version)
+}
+
+func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create two versions of a job with different priorities
+	job := mock.Job()
+	job.Priority = 88
+	err := state.UpsertJob(10, job)
+	require.Nil(err)
+
+	job.Priority = 100
+	err = state.UpsertJob(100, job)
 	require.Nil(err)
 
 	// Lookup the job
@@ -2859,6 +3439,66 @@ func TestJobEndpoint_ListJobs(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ListJobs_Pagination(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the jobs
+	state := s1.fsm.State()
+	ids := []string{"job-a", "job-b", "job-c", "job-d", "job-e"}
+	for i, id := range ids {
+		job := mock.Job()
+		job.ID = id
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+	}
+
+	// Lookup the jobs a page at a time
+	var listed []string
+	get := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			PerPage:   2,
+		},
+	}
+	for pages := 1; ; pages++ {
+		var resp structs.JobListResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp))
+		require.EqualValues(1004, resp.Index)
+		require.True(len(resp.Jobs) <= 2)
+		for _, job := range resp.Jobs {
+			listed = append(listed, job.ID)
+		}
+		if resp.NextToken == "" {
+			require.Equal(3, pages)
+			break
+		}
+		get.NextToken = resp.NextToken
+	}
+	require.Equal(ids, listed)
+
+	// Pagination applies to prefix searches
+	get = &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			Prefix:    "job-",
+			PerPage:   3,
+			NextToken: "job-c",
+		},
+	}
+	var resp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp))
+	require.Len(resp.Jobs, 3)
+	require.Equal("job-c", resp.Jobs[0].ID)
+	require.Equal("job-e", resp.Jobs[2].ID)
+	require.Empty(resp.NextToken)
+}
+
 func TestJobEndpoint_ListJobs_WithACL(t *testing.T) {
 	require := require.New(t)
 	t.Parallel()
@@ -3026,6 +3666,55 @@ func TestJobEndpoint_Allocations(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_RescheduleStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// A running alloc that was never rescheduled, a failed alloc and a failed
+	// alloc that has been replaced by it
+	running := mock.Alloc()
+	failed := mock.Alloc()
+	failed.Job = running.Job
+	failed.JobID = running.JobID
+	failed.ClientStatus = structs.AllocClientStatusFailed
+	failed.RescheduleTracker = &structs.RescheduleTracker{Events: []*structs.RescheduleEvent{
+		structs.NewRescheduleEvent(time.Now().UTC().UnixNano(), "prev", "node", 5*time.Second),
+	}}
+	replaced := mock.Alloc()
+	replaced.Job = running.Job
+	replaced.JobID = running.JobID
+	replaced.ClientStatus = structs.AllocClientStatusFailed
+	replaced.NextAllocation = failed.ID
+
+	state := s1.fsm.State()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(running.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{running, failed, replaced}))
+
+	get := &structs.JobSpecificRequest{
+		JobID: running.JobID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: running.Job.Namespace,
+		},
+	}
+	var resp structs.JobRescheduleStatusResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.RescheduleStatus", get, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.Allocs, 1)
+
+	status := resp.Allocs[0]
+	require.Equal(failed.ID, status.AllocID)
+	require.Equal(1, status.Attempts)
+	require.Equal(2, status.MaxAttempts)
+	require.Len(status.Events, 1)
+	require.True(status.Eligible)
+}
+
 func TestJobEndpoint_Allocations_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -3883,6 +4572,115 @@ func TestJobEndpoint_ImplicitConstraints_Signals(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ImplicitConstraints_Docker(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request with a job asking for a privileged docker
+	// task on the host network with added capabilities
+	job := mock.Job()
+	task := job.TaskGroups[0].Tasks[0]
+	task.Driver = "docker"
+	task.Config = map[string]interface{}{
+		"image":        "redis:3.2",
+		"privileged":   true,
+		"network_mode": "host",
+		"cap_add":      []interface{}{"NET_ADMIN", "CAP_SYS_TIME"},
+		"cap_drop":     []interface{}{"SYS_TIME"},
+	}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Fetch the response
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Check for the job in the FSM
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Check that there are the implicit docker constraints
+	expected := []*structs.Constraint{
+		dockerPrivilegedConstraint,
+		dockerHostNetworkConstraint,
+		getDockerCapConstraint("net_admin"),
+	}
+	require.Equal(expected, out.TaskGroups[0].Constraints)
+}
+
+func TestJobEndpoint_ImplicitConstraints_Realtime(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the register request with a job asking for real-time scheduling
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.Realtime = true
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Fetch the response
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Check for the job in the FSM
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Check that there is the implicit real-time constraint
+	require.Equal([]*structs.Constraint{realtimeConstraint}, out.TaskGroups[0].Constraints)
+}
+
+func TestJobEndpoint_DockerCapConstraint(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		cap     string
+		allowed string
+		match   bool
+	}{
+		{"net_admin", "chown,net_admin,net_raw", true},
+		{"net_admin", "net_admin", true},
+		{"net_admin", "all", true},
+		{"net_admin", "chown,net_raw", false},
+		{"net_admin", "chown,net_admin_extra", false},
+		{"all", "all", true},
+		{"all", "chown,net_admin", false},
+	}
+
+	for _, c := range cases {
+		constraint := getDockerCapConstraint(c.cap)
+		match, err := regexp.MatchString(constraint.RTarget, c.allowed)
+		require.NoError(t, err)
+		require.Equal(t, c.match, match, "cap %q allowed %q", c.cap, c.allowed)
+	}
+}
+
 func TestJobEndpoint_ValidateJobUpdate(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -3963,6 +4761,128 @@ func TestJobEndpoint_ValidateJobUpdate_ACL(t *testing.T) {
 	require.Equal("", validResp.Warnings)
 }
 
+func TestJobEndpoint_Validate_Admission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	validate := func(job *structs.Job, admission bool) *structs.JobValidateResponse {
+		req := &structs.JobValidateRequest{
+			Job:       job,
+			Admission: admission,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobValidateResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+		require.Equal("", resp.Error)
+		return &resp
+	}
+
+	// Without admission checks no warnings are returned
+	resp := validate(mock.Job(), false)
+	require.Empty(resp.AdmissionWarnings)
+
+	// There are no nodes to place the job on
+	resp = validate(mock.Job(), true)
+	require.Len(resp.AdmissionWarnings, 1)
+	require.Equal(structs.JobAdmissionWarningConstraint, resp.AdmissionWarnings[0].Type)
+	require.Equal("web", resp.AdmissionWarnings[0].TaskGroup)
+	require.Contains(resp.AdmissionWarnings[0].Message, "no nodes are available")
+
+	// Register a node the job can be placed on
+	node := mock.Node()
+	node.Attributes["vault.version"] = "1.1.0"
+	require.NoError(s1.fsm.State().UpsertNode(1000, node))
+	resp = validate(mock.Job(), true)
+	require.Empty(resp.AdmissionWarnings)
+
+	// A constraint no node satisfies
+	job := mock.Job()
+	job.TaskGroups[0].Constraints = append(job.TaskGroups[0].Constraints, &structs.Constraint{
+		LTarget: "${attr.kernel.name}",
+		RTarget: "windows",
+		Operand: "=",
+	})
+	resp = validate(job, true)
+	require.Len(resp.AdmissionWarnings, 1)
+	require.Equal(structs.JobAdmissionWarningConstraint, resp.AdmissionWarnings[0].Type)
+	require.Contains(resp.AdmissionWarnings[0].Message, "${attr.kernel.name} = windows")
+
+	// A driver no node has
+	job = mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "unicorn"
+	resp = validate(job, true)
+	require.Len(resp.AdmissionWarnings, 1)
+	require.Contains(resp.AdmissionWarnings[0].Message, "missing drivers")
+
+	// A namespace that does not exist and Vault policies while Vault is
+	// disabled
+	job = mock.Job()
+	job.Namespace = "foo"
+	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{
+		Policies:   []string{"foo"},
+		ChangeMode: structs.VaultChangeModeRestart,
+	}
+	resp = validate(job, true)
+	require.Len(resp.AdmissionWarnings, 2)
+	require.Equal(structs.JobAdmissionWarningNamespace, resp.AdmissionWarnings[0].Type)
+	require.Equal(structs.JobAdmissionWarningVault, resp.AdmissionWarnings[1].Type)
+	require.Contains(resp.AdmissionWarnings[1].Message, "Vault not enabled")
+}
+
+func TestJobEndpoint_Validate_Admission_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	submitToken := mock.CreatePolicyAndToken(t, state, 1003, "submit",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob, acl.NamespaceCapabilitySubmitJob}))
+
+	validate := func(token string, admission bool) error {
+		job := mock.Job()
+		job.VaultToken = "foo"
+		req := &structs.JobValidateRequest{
+			Job:       job,
+			Admission: admission,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.JobValidateResponse
+		return msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp)
+	}
+
+	// Validating the job itself only requires read-job
+	require.NoError(validate(readToken.SecretID, false))
+
+	// The admission checks require submit-job
+	err := validate(readToken.SecretID, true)
+	require.Error(err)
+	require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+	require.NoError(validate(submitToken.SecretID, true))
+	require.NoError(validate(root.SecretID, true))
+}
+
 func TestJobEndpoint_Dispatch_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -4080,6 +5000,23 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 	d7.ParameterizedJob = &structs.ParameterizedJobConfig{}
 	d7.Stop = true
 
+	// Validated meta
+	d8 := mock.BatchJob()
+	d8.ParameterizedJob = &structs.ParameterizedJobConfig{
+		MetaOptional: []string{"foo", "bar"},
+		MetaConfigs: []*structs.DispatchMetaConfig{
+			{
+				Key:          "foo",
+				Enum:         []string{"f1", "f2"},
+				RequiredWith: []string{"bar"},
+			},
+			{
+				Key:  "bar",
+				Type: structs.DispatchMetaTypeInt,
+			},
+		},
+	}
+
 	reqNoInputNoMeta := &structs.JobDispatchRequest{}
 	reqInputDataNoMeta := &structs.JobDispatchRequest{
 		Payload: []byte("hello world"),
@@ -4108,6 +5045,23 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 	reqInputDataTooLarge := &structs.JobDispatchRequest{
 		Payload: make([]byte, DispatchPayloadSizeLimit+100),
 	}
+	reqValidMeta := &structs.JobDispatchRequest{
+		Meta: map[string]string{
+			"foo": "f2",
+			"bar": "10",
+		},
+	}
+	reqInvalidMeta := &structs.JobDispatchRequest{
+		Meta: map[string]string{
+			"foo": "f3",
+			"bar": "ten",
+		},
+	}
+	reqMissingRequiredWithMeta := &structs.JobDispatchRequest{
+		Meta: map[string]string{
+			"foo": "f1",
+		},
+	}
 
 	type testCase struct {
 		name             string
@@ -4208,6 +5162,33 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 			err:              true,
 			errStr:           "stopped",
 		},
+		{
+			name:             "validated meta w/ valid meta",
+			parameterizedJob: d8,
+			dispatchReq:      reqValidMeta,
+			err:              false,
+		},
+		{
+			name:             "validated meta w/ invalid enum value",
+			parameterizedJob: d8,
+			dispatchReq:      reqInvalidMeta,
+			err:              true,
+			errStr:           `"f3" is not one of [f1 f2]`,
+		},
+		{
+			name:             "validated meta w/ invalid type",
+			parameterizedJob: d8,
+			dispatchReq:      reqInvalidMeta,
+			err:              true,
+			errStr:           `"ten" is not of type int`,
+		},
+		{
+			name:             "validated meta w/o required with meta",
+			parameterizedJob: d8,
+			dispatchReq:      reqMissingRequiredWithMeta,
+			err:              true,
+			errStr:           `Meta key "bar" must be provided with meta key "foo"`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -4316,3 +5297,264 @@ func TestJobEndpoint_Dispatch(t *testing.T) {
 		})
 	}
 }
+
+func TestJobEndpoint_Dispatch_PayloadSizeLimit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.DispatchPayloadSizeLimit = 4 * DispatchPayloadSizeLimit
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	regReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	// Payloads over the default limit are accepted up to the configured one
+	req := &structs.JobDispatchRequest{
+		JobID:   job.ID,
+		Payload: make([]byte, 2*DispatchPayloadSizeLimit),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobDispatchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp))
+
+	req.Payload = make([]byte, 4*DispatchPayloadSizeLimit+1)
+	err := msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "Payload exceeds maximum size")
+}
+
+func TestJobEndpoint_Dispatch_IdempotencyToken(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a parameterized job
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(state.UpsertJob(400, job))
+
+	req := &structs.JobDispatchRequest{
+		JobID:            job.ID,
+		Payload:          []byte("hello"),
+		IdempotencyToken: "foo",
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.JobDispatchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp))
+	require.NotEmpty(resp.EvalID)
+
+	out, err := state.JobByID(nil, job.Namespace, resp.DispatchedJobID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("foo", out.DispatchIdempotencyToken)
+	hash := sha256.Sum256([]byte("hello"))
+	require.Equal(hex.EncodeToString(hash[:]), out.DispatchPayloadHash)
+
+	// Dispatching with the same token returns the existing job
+	var resp2 structs.JobDispatchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp2))
+	require.Equal(resp.DispatchedJobID, resp2.DispatchedJobID)
+	require.Equal(resp.EvalID, resp2.EvalID)
+	require.Equal(resp.JobCreateIndex, resp2.JobCreateIndex)
+	require.Equal(resp.EvalCreateIndex, resp2.EvalCreateIndex)
+
+	// Dispatching with another token dispatches a new job
+	req.IdempotencyToken = "bar"
+	var resp3 structs.JobDispatchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp3))
+	require.NotEqual(resp.DispatchedJobID, resp3.DispatchedJobID)
+
+	iter, err := state.JobsByIDPrefix(nil, job.Namespace, job.ID+structs.DispatchLaunchSuffix)
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(2, count)
+}
+
+func TestJobEndpoint_ListJobs_Tags(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	state := s1.fsm.State()
+	job1 := mock.Job()
+	job1.ID = "a"
+	job1.Tags = []string{"owner:payments", "tier:1"}
+	require.NoError(state.UpsertJob(1000, job1))
+
+	job2 := mock.Job()
+	job2.ID = "b"
+	job2.Tags = []string{"owner:payments", "tier:2"}
+	require.NoError(state.UpsertJob(1001, job2))
+
+	job3 := mock.Job()
+	job3.ID = "c"
+	require.NoError(state.UpsertJob(1002, job3))
+
+	cases := []struct {
+		name     string
+		tags     []string
+		perPage  int32
+		expected []string
+	}{
+		{
+			name:     "single tag",
+			tags:     []string{"owner:payments"},
+			expected: []string{job1.ID, job2.ID},
+		},
+		{
+			name:     "all tags",
+			tags:     []string{"owner:payments", "tier:2"},
+			expected: []string{job2.ID},
+		},
+		{
+			name:     "paginated",
+			tags:     []string{"owner:payments"},
+			perPage:  1,
+			expected: []string{job1.ID},
+		},
+		{
+			name: "no match",
+			tags: []string{"owner:search"},
+		},
+	}
+
+	for _, c := range cases {
+		get := &structs.JobListRequest{
+			Tags: c.tags,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				Namespace: job1.Namespace,
+				PerPage:   c.perPage,
+			},
+		}
+		var resp structs.JobListResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", get, &resp), c.name)
+
+		var ids []string
+		for _, j := range resp.Jobs {
+			ids = append(ids, j.ID)
+		}
+		require.Equal(c.expected, ids, c.name)
+	}
+}
+
+func TestJobEndpoint_ListJobs_DispatchFilters(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a parameterized job and its children
+	state := s1.fsm.State()
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(state.UpsertJob(1000, parent))
+
+	child1 := parent.Copy()
+	child1.ID = structs.DispatchedID(parent.ID, time.Now())
+	child1.ParentID = parent.ID
+	child1.Dispatched = true
+	child1.DispatchPayloadHash = "aaa"
+	child1.Meta = map[string]string{"queue": "a"}
+	require.NoError(state.UpsertJob(1001, child1))
+
+	child2 := child1.Copy()
+	child2.ID = structs.DispatchedID(parent.ID, time.Now())
+	child2.DispatchPayloadHash = "bbb"
+	child2.Meta = map[string]string{"queue": "b"}
+	require.NoError(state.UpsertJob(1002, child2))
+
+	// Complete the evaluation of the second child so that it is dead
+	eval := mock.Eval()
+	eval.JobID = child2.ID
+	eval.Status = structs.EvalStatusComplete
+	require.NoError(state.UpsertEvals(1003, []*structs.Evaluation{eval}))
+
+	other := mock.Job()
+	require.NoError(state.UpsertJob(1004, other))
+
+	cases := []struct {
+		name     string
+		req      structs.JobListRequest
+		expected []string
+	}{
+		{
+			name:     "parent",
+			req:      structs.JobListRequest{ParentID: parent.ID},
+			expected: []string{child1.ID, child2.ID},
+		},
+		{
+			name:     "meta",
+			req:      structs.JobListRequest{ParentID: parent.ID, Meta: map[string]string{"queue": "b"}},
+			expected: []string{child2.ID},
+		},
+		{
+			name:     "payload hash",
+			req:      structs.JobListRequest{PayloadHash: "aaa"},
+			expected: []string{child1.ID},
+		},
+		{
+			name:     "status",
+			req:      structs.JobListRequest{ParentID: parent.ID, Status: structs.JobStatusDead},
+			expected: []string{child2.ID},
+		},
+		{
+			name: "no match",
+			req:  structs.JobListRequest{ParentID: parent.ID, Meta: map[string]string{"queue": "c"}},
+		},
+	}
+
+	for _, c := range cases {
+		get := c.req
+		get.QueryOptions = structs.QueryOptions{
+			Region:    "global",
+			Namespace: parent.Namespace,
+		}
+		var resp structs.JobListResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", &get, &resp), c.name)
+
+		var ids []string
+		for _, stub := range resp.Jobs {
+			ids = append(ids, stub.ID)
+		}
+		sort.Strings(ids)
+		sort.Strings(c.expected)
+		require.Equal(c.expected, ids, c.name)
+	}
+}
@@ -0,0 +1,99 @@
+package nomad
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// jobSignature is the verified signature of a job being registered.
+type jobSignature struct {
+	// Signer is the name of the configured signer whose key verified the
+	// signature
+	Signer string
+
+	// Digest is the hex encoded SHA-256 digest of the signed payload
+	Digest string
+}
+
+// jobSigningConfig returns the job signing config of the namespace or nil if
+// the jobs of the namespace aren't verified.
+func (j *Job) jobSigningConfig(namespace string) *config.JobSigningConfig {
+	for _, signing := range j.srv.config.JobSigning {
+		if signing.Namespace == namespace {
+			return signing
+		}
+	}
+	return nil
+}
+
+// verifyJobSignature verifies the detached signature of the job being
+// registered against the signers configured for its namespace. The job must
+// be canonicalized but not yet modified by the server. Any signature metadata
+// submitted with the job is removed, so only verified signatures are
+// recorded. A nil signature is returned for unsigned jobs of namespaces that
+// don't require signatures.
+func (j *Job) verifyJobSignature(args *structs.JobRegisterRequest) (*jobSignature, error) {
+	job := args.Job
+	job.StripSignatureMeta()
+
+	signing := j.jobSigningConfig(job.Namespace)
+	if signing == nil {
+		if args.Signature != "" {
+			return nil, fmt.Errorf("job signatures are not configured for namespace %q", job.Namespace)
+		}
+		return nil, nil
+	}
+
+	if args.Signature == "" {
+		if signing.Required {
+			return nil, fmt.Errorf("namespace %q requires signed jobs", job.Namespace)
+		}
+		return nil, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(args.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode job signature: %v", err)
+	}
+	payload, err := job.SigningPayload()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signer := range signing.Signers {
+		key, err := signer.ParsePublicKey()
+		if err != nil {
+			// Keys are validated when the agent starts
+			j.logger.Error("invalid job signer public key", "signer", signer.Name, "error", err)
+			continue
+		}
+		if err := structs.VerifyJobSignature(key, payload, signature); err != nil {
+			continue
+		}
+
+		digest := sha256.Sum256(payload)
+		return &jobSignature{
+			Signer: signer.Name,
+			Digest: hex.EncodeToString(digest[:]),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("job signature not verified by any signer of namespace %q", job.Namespace)
+}
+
+// recordJobSignature records the verified signature in the job's metadata.
+func recordJobSignature(job *structs.Job, signature *jobSignature) {
+	if signature == nil {
+		return
+	}
+	if job.Meta == nil {
+		job.Meta = make(map[string]string, 2)
+	}
+	job.Meta[structs.JobMetaSigner] = signature.Signer
+	job.Meta[structs.JobMetaSignatureDigest] = signature.Digest
+}
@@ -0,0 +1,160 @@
+package nomad
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testJobSigner returns a signing key and the config of a signer with its
+// public key.
+func testJobSigner(t *testing.T, name string) (ed25519.PrivateKey, *config.JobSignerConfig) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+
+	return priv, &config.JobSignerConfig{
+		Name:      name,
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+}
+
+// testSignJob returns the base64 encoded signature of the job.
+func testSignJob(t *testing.T, key ed25519.PrivateKey, job *structs.Job) string {
+	job = job.Copy()
+	job.Canonicalize()
+	payload, err := job.SigningPayload()
+	require.NoError(t, err)
+	signature, err := structs.SignJobPayload(key, payload)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestJobEndpoint_Register_Signed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	key, signer := testJobSigner(t, "release-bot")
+	otherKey, _ := testJobSigner(t, "other")
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobSigning = []*config.JobSigningConfig{
+			{
+				Namespace: structs.DefaultNamespace,
+				Required:  true,
+				Signers:   []*config.JobSignerConfig{signer},
+			},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Unsigned jobs are rejected
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "requires signed jobs")
+
+	// Jobs signed by an unknown key are rejected
+	req.Signature = testSignJob(t, otherKey, job)
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not verified")
+
+	// Jobs modified after signing are rejected
+	req.Signature = testSignJob(t, key, job)
+	req.Job = job.Copy()
+	req.Job.TaskGroups[0].Count++
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not verified")
+
+	// Signed jobs are registered with the signer recorded
+	req.Job = job
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	ws := memdb.NewWatchSet()
+	out, err := s1.fsm.State().JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("release-bot", out.Meta[structs.JobMetaSigner])
+	require.NotEmpty(out.Meta[structs.JobMetaSignatureDigest])
+
+	// Jobs can't claim a signer without a signature
+	forged := job.Copy()
+	forged.Meta[structs.JobMetaSigner] = "release-bot"
+	req.Job = forged
+	req.Signature = ""
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "requires signed jobs")
+
+	// Register a second signed version and revert to the first one, which
+	// keeps its signer
+	job2 := job.Copy()
+	job2.TaskGroups[0].Count++
+	req.Job = job2
+	req.Signature = testSignJob(t, key, job2)
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	revertReq := &structs.JobRevertRequest{
+		JobID:      job.ID,
+		JobVersion: 0,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Revert", revertReq, &resp))
+
+	out, err = s1.fsm.State().JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.EqualValues(2, out.Version)
+	require.Equal("release-bot", out.Meta[structs.JobMetaSigner])
+}
+
+func TestJobEndpoint_Register_SignatureNotConfigured(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	key, _ := testJobSigner(t, "release-bot")
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job:       job,
+		Signature: testSignJob(t, key, job),
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not configured")
+}
@@ -0,0 +1,49 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jwtVerifyLoginToken verifies the signature and validity of a JWT presented
+// to a JWT auth method and returns its claims.
+func jwtVerifyLoginToken(config *structs.ACLAuthMethodConfig, jwt string, now time.Time) (map[string]interface{}, error) {
+	claims, err := verifySignedJWT(config.JWKSURL, jwt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the standard claims
+	if config.BoundIssuer != "" {
+		if iss, _ := claims["iss"].(string); iss != config.BoundIssuer {
+			return nil, fmt.Errorf("JWT issuer %q is not %q", iss, config.BoundIssuer)
+		}
+	}
+	if !claimContainsAny(claims["aud"], config.BoundAudiences) {
+		return nil, fmt.Errorf("JWT audience is not bound by the auth method")
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("JWT has no expiry")
+	}
+	if now.Add(-oidcClockSkew).After(time.Unix(int64(exp), 0)) {
+		return nil, fmt.Errorf("JWT expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, fmt.Errorf("JWT is not valid yet")
+	}
+	return claims, nil
+}
+
+// bindingRuleMatches returns whether the selector of the binding rule matches
+// the claims of a login.
+func bindingRuleMatches(rule *structs.ACLBindingRule, claims map[string]interface{}) bool {
+	for k, v := range rule.Selector {
+		if !claimContainsAny(claims[k], []string{v}) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWT_VerifyLoginToken(t *testing.T) {
+	t.Parallel()
+	p := newTestOIDCProvider(t)
+	defer p.srv.Close()
+
+	config := &structs.ACLAuthMethodConfig{
+		JWKSURL:        p.srv.URL + "/keys",
+		BoundIssuer:    p.srv.URL,
+		BoundAudiences: []string{"nomad"},
+	}
+	jwt := p.sign(t, p.idTokenClaims())
+
+	claims, err := jwtVerifyLoginToken(config, jwt, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "alice", claims["sub"])
+
+	// Expired tokens are rejected
+	_, err = jwtVerifyLoginToken(config, jwt, time.Now().Add(time.Hour))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expired")
+
+	// The issuer and audience must be bound
+	config.BoundIssuer = "https://other.example.com"
+	_, err = jwtVerifyLoginToken(config, jwt, time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "issuer")
+	config.BoundIssuer = p.srv.URL
+
+	config.BoundAudiences = []string{"other"}
+	_, err = jwtVerifyLoginToken(config, jwt, time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "audience")
+	config.BoundAudiences = []string{"nomad"}
+
+	// Tokens that aren't valid yet are rejected
+	future := p.idTokenClaims()
+	future["nbf"] = time.Now().Add(time.Hour).Unix()
+	_, err = jwtVerifyLoginToken(config, p.sign(t, future), time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not valid yet")
+
+	// Tokens signed by other keys are rejected
+	other := newTestOIDCProvider(t)
+	defer other.srv.Close()
+	_, err = jwtVerifyLoginToken(config, other.sign(t, p.idTokenClaims()), time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature")
+}
+
+func TestJWT_BindingRuleMatches(t *testing.T) {
+	t.Parallel()
+	rule := &structs.ACLBindingRule{
+		Selector: map[string]string{
+			"ref":         "refs/heads/main",
+			"environment": "production",
+		},
+	}
+
+	require.True(t, bindingRuleMatches(rule, map[string]interface{}{
+		"ref":         "refs/heads/main",
+		"environment": []interface{}{"staging", "production"},
+	}))
+	require.False(t, bindingRuleMatches(rule, map[string]interface{}{
+		"ref":         "refs/heads/feature",
+		"environment": "production",
+	}))
+	require.False(t, bindingRuleMatches(rule, map[string]interface{}{
+		"ref": "refs/heads/main",
+	}))
+
+	// Empty selectors match every login
+	require.True(t, bindingRuleMatches(&structs.ACLBindingRule{}, nil))
+}
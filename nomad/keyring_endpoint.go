@@ -0,0 +1,55 @@
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring endpoint is used to read the public keys derived from the root keys
+type Keyring struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// ListPublic is used to list the public keys that verify workload identities.
+// The keys are public so no ACL check is made.
+func (k *Keyring) ListPublic(args *structs.GenericRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward("Keyring.ListPublic", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.RootKeys(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Keys = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				jwk, err := identityPublicKey(raw.(*structs.RootKey))
+				if err != nil {
+					return err
+				}
+				reply.Keys = append(reply.Keys, jwk)
+			}
+
+			// Use the last index that affected the root keys table
+			index, err := state.Index("root_keys")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return k.srv.blockingRPC(&opts)
+}
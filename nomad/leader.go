@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net"
 	"sync"
@@ -17,6 +18,7 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -44,6 +46,10 @@ var minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
 
 var minSchedulerConfigVersion = version.Must(version.NewVersion("0.9.0"))
 
+var minVariablesVersion = version.Must(version.NewVersion("0.9.0"))
+
+var minCompressedJobVersionsVersion = version.Must(version.NewVersion("0.9.0"))
+
 // Default configuration for scheduler with preemption enabled for system jobs
 var defaultSchedulerConfig = &structs.SchedulerConfiguration{
 	PreemptionConfig: structs.PreemptionConfig{
@@ -199,6 +205,11 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Initialize scheduler configuration
 	s.getOrCreateSchedulerConfig()
 
+	// Initialize the root key used to encrypt variables
+	if _, err := s.getOrCreateRootKey(); err != nil {
+		s.logger.Named("core").Warn("failed to initialize root key", "error", err)
+	}
+
 	// Enable the plan queue, since we are now the leader
 	s.planQueue.SetEnabled(true)
 
@@ -223,8 +234,8 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 		return err
 	}
 
-	// Activate the vault client
-	s.vault.SetActive(true)
+	// Activate the vault clients
+	s.setVaultActive(true)
 	if err := s.restoreRevokingAccessors(); err != nil {
 		return err
 	}
@@ -252,6 +263,25 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job summary metrics
 	go s.publishJobSummaryMetrics(stopCh)
 
+	// Apply the node events and quarantines of the nodes whose placements
+	// are rejected by the plan applier
+	s.planRejections.Reset()
+	go s.applyPlanRejectionUpdates(stopCh)
+
+	// Periodically apply the maintenance windows and eligibility TTLs of
+	// the nodes
+	go s.watchNodeEligibility(stopCh)
+
+	// Periodically save snapshots of the Raft state
+	if s.snapshotAgent != nil {
+		go s.snapshotAgent.run(stopCh, s.raft)
+	}
+
+	// Publish Nomad services to the external DNS provider
+	if s.externalDNS != nil {
+		go s.externalDNS.run(stopCh, s.State)
+	}
+
 	// Setup the heartbeat timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node, effectively this means all the timers are renewed at the time of failover.
@@ -279,9 +309,20 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// and we are not the authoritative region.
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
+		go s.replicateACLRoles(stopCh)
 		go s.replicateACLTokens(stopCh)
 	}
 
+	// Bootstrap the ACL system with the pre-generated bootstrap token so the
+	// cluster can be brought up unattended
+	if s.config.ACLEnabled && s.config.ACLBootstrapTokenFile != "" &&
+		s.config.Region == s.config.AuthoritativeRegion {
+		if err := s.bootstrapACLTokenFromFile(); err != nil {
+			s.logger.Error("failed to bootstrap ACLs from the bootstrap token file",
+				"path", s.config.ACLBootstrapTokenFile, "error", err)
+		}
+	}
+
 	// Setup any enterprise systems required.
 	if err := s.establishEnterpriseLeadership(stopCh); err != nil {
 		return err
@@ -290,6 +331,60 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	return nil
 }
 
+// bootstrapACLTokenFromFile bootstraps the ACL system with a management token
+// whose Secret ID is read from the bootstrap token file, such as one written
+// by a process decrypting it with a KMS. It does nothing if the ACL system is
+// already bootstrapped.
+func (s *Server) bootstrapACLTokenFromFile() error {
+	ok, _, err := s.fsm.State().CanBootstrapACLToken()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	secret, err := readACLBootstrapTokenFile(s.config.ACLBootstrapTokenFile)
+	if err != nil {
+		return err
+	}
+
+	req := &structs.ACLTokenBootstrapRequest{
+		Token: &structs.ACLToken{
+			AccessorID: uuid.Generate(),
+			SecretID:   secret,
+			Name:       "Bootstrap Token",
+			Type:       structs.ACLManagementToken,
+			Global:     true,
+			CreateTime: time.Now().UTC(),
+		},
+		WriteRequest: structs.WriteRequest{Region: s.config.Region},
+	}
+	req.Token.SetHash()
+
+	if _, _, err := s.raftApply(structs.ACLTokenBootstrapRequestType, req); err != nil {
+		return err
+	}
+
+	s.logger.Info("bootstrapped ACLs from the bootstrap token file", "accessor_id", req.Token.AccessorID)
+	return nil
+}
+
+// readACLBootstrapTokenFile returns the Secret ID of the bootstrap token held
+// by the file.
+func readACLBootstrapTokenFile(path string) (string, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(string(raw))
+	if !helper.IsUUID(secret) {
+		return "", fmt.Errorf("bootstrap token in %q is not a UUID", path)
+	}
+	return secret, nil
+}
+
 // restoreEvals is used to restore pending evaluations into the eval broker and
 // blocked evaluations into the blocked eval tracker. The broker and blocked
 // eval tracker is maintained only by the leader, so it must be restored anytime
@@ -362,7 +457,7 @@ func (s *Server) restoreRevokingAccessors() error {
 	}
 
 	if len(revoke) != 0 {
-		if err := s.vault.RevokeTokens(context.Background(), revoke, true); err != nil {
+		if err := s.revokeVaultTokens(context.Background(), revoke, true); err != nil {
 			return fmt.Errorf("failed to revoke tokens: %v", err)
 		}
 	}
@@ -449,6 +544,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer jobGC.Stop()
 	deploymentGC := time.NewTicker(s.config.DeploymentGCInterval)
 	defer deploymentGC.Stop()
+	aclTokenExpirationGC := time.NewTicker(s.config.ACLTokenExpirationGCInterval)
+	defer aclTokenExpirationGC.Stop()
 
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
@@ -481,6 +578,13 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeploymentGC, index))
 			}
+		case <-aclTokenExpirationGC.C:
+			if !s.config.ACLEnabled {
+				continue
+			}
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobExpiredACLTokenGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -691,6 +795,8 @@ func (s *Server) iterateJobSummaryMetrics(summary *structs.JobSummary) {
 				float32(tgSummary.Starting), labels)
 			metrics.SetGaugeWithLabels([]string{"nomad", "job_summary", "lost"},
 				float32(tgSummary.Lost), labels)
+			metrics.SetGaugeWithLabels([]string{"nomad", "job_summary", "unknown"},
+				float32(tgSummary.Unknown), labels)
 		}
 		if s.config.BackwardsCompatibleMetrics {
 			metrics.SetGauge([]string{"nomad", "job_summary", summary.JobID, name, "queued"}, float32(tgSummary.Queued))
@@ -726,8 +832,8 @@ func (s *Server) revokeLeadership() error {
 	// Disable the periodic dispatcher, since it is only useful as a leader
 	s.periodicDispatcher.SetEnabled(false)
 
-	// Disable the Vault client as it is only useful as a leader.
-	s.vault.SetActive(false)
+	// Disable the Vault clients as they are only useful as a leader.
+	s.setVaultActive(false)
 
 	// Disable the deployment watcher as it is only useful as a leader.
 	s.deploymentWatcher.SetEnabled(false, nil)
@@ -958,6 +1064,8 @@ func (s *Server) replicateACLPolicies(stopCh chan struct{}) {
 		},
 	}
 	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.aclPolicyReplication.start()
+	defer s.aclPolicyReplication.stop()
 	s.logger.Debug("starting ACL policy replication from authoritative region", "authoritative_region", req.Region)
 
 START:
@@ -976,6 +1084,7 @@ START:
 				"ACL.ListPolicies", &req, &resp)
 			if err != nil {
 				s.logger.Error("failed to fetch policies from authoritative region", "error", err)
+				s.aclPolicyReplication.failure(err)
 				goto ERR_WAIT
 			}
 
@@ -990,6 +1099,7 @@ START:
 				_, _, err := s.raftApply(structs.ACLPolicyDeleteRequestType, args)
 				if err != nil {
 					s.logger.Error("failed to delete policies", "error", err)
+					s.aclPolicyReplication.failure(err)
 					goto ERR_WAIT
 				}
 			}
@@ -1010,6 +1120,7 @@ START:
 				if err := s.forwardRegion(s.config.AuthoritativeRegion,
 					"ACL.GetPolicies", &req, &reply); err != nil {
 					s.logger.Error("failed to fetch policies from authoritative region", "error", err)
+					s.aclPolicyReplication.failure(err)
 					goto ERR_WAIT
 				}
 				for _, policy := range reply.Policies {
@@ -1025,10 +1136,13 @@ START:
 				_, _, err := s.raftApply(structs.ACLPolicyUpsertRequestType, args)
 				if err != nil {
 					s.logger.Error("failed to update policies", "error", err)
+					s.aclPolicyReplication.failure(err)
 					goto ERR_WAIT
 				}
 			}
 
+			s.aclPolicyReplication.success(resp.Index)
+
 			// Update the minimum query index, blocks until there
 			// is a change.
 			req.MinQueryIndex = resp.Index
@@ -1089,6 +1203,155 @@ func diffACLPolicies(state *state.StateStore, minIndex uint64, remoteList []*str
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from
+// the authoritative region to this region.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.aclRoleReplication.start()
+	defer s.aclRoleReplication.stop()
+	s.logger.Debug("starting ACL role replication from authoritative region", "authoritative_region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRoleListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListRoles", &req, &resp)
+			if err != nil {
+				s.logger.Error("failed to fetch roles from authoritative region", "error", err)
+				s.aclRoleReplication.failure(err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.Roles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRoleDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleDeleteRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to delete roles", "error", err)
+					s.aclRoleReplication.failure(err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRoleSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRoleSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetRoles", &req, &reply); err != nil {
+					s.logger.Error("failed to fetch roles from authoritative region", "error", err)
+					s.aclRoleReplication.failure(err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.Roles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles
+			if len(fetched) > 0 {
+				args := &structs.ACLRoleUpsertRequest{
+					Roles: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleUpsertRequestType, args)
+				if err != nil {
+					s.logger.Error("failed to update roles", "error", err)
+					s.aclRoleReplication.failure(err)
+					goto ERR_WAIT
+				}
+			}
+
+			s.aclRoleReplication.success(resp.Index)
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local
+// roles and the remote roles to determine which roles need to
+// be deleted or updated.
+func diffACLRoles(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := state.ACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local roles")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		role := raw.(*structs.ACLRole)
+		local[role.Name] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rr := range remoteList {
+		remote[rr.Name] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rr.Name]; !ok {
+			update = append(update, rr.Name)
+
+			// Check if role is newer remotely and there is a hash mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.Name)
+		}
+	}
+
+	// Check if role should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
 // replicateACLTokens is used to replicate global ACL tokens from
 // the authoritative region to this region.
 func (s *Server) replicateACLTokens(stopCh chan struct{}) {
@@ -1100,6 +1363,8 @@ func (s *Server) replicateACLTokens(stopCh chan struct{}) {
 		},
 	}
 	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.aclTokenReplication.start()
+	defer s.aclTokenReplication.stop()
 	s.logger.Debug("starting ACL token replication from authoritative region", "authoritative_region", req.Region)
 
 START:
@@ -1118,6 +1383,7 @@ START:
 				"ACL.ListTokens", &req, &resp)
 			if err != nil {
 				s.logger.Error("failed to fetch tokens from authoritative region", "error", err)
+				s.aclTokenReplication.failure(err)
 				goto ERR_WAIT
 			}
 
@@ -1132,6 +1398,7 @@ START:
 				_, _, err := s.raftApply(structs.ACLTokenDeleteRequestType, args)
 				if err != nil {
 					s.logger.Error("failed to delete tokens", "error", err)
+					s.aclTokenReplication.failure(err)
 					goto ERR_WAIT
 				}
 			}
@@ -1152,6 +1419,7 @@ START:
 				if err := s.forwardRegion(s.config.AuthoritativeRegion,
 					"ACL.GetTokens", &req, &reply); err != nil {
 					s.logger.Error("failed to fetch tokens from authoritative region", "error", err)
+					s.aclTokenReplication.failure(err)
 					goto ERR_WAIT
 				}
 				for _, token := range reply.Tokens {
@@ -1167,10 +1435,13 @@ START:
 				_, _, err := s.raftApply(structs.ACLTokenUpsertRequestType, args)
 				if err != nil {
 					s.logger.Error("failed to update tokens", "error", err)
+					s.aclTokenReplication.failure(err)
 					goto ERR_WAIT
 				}
 			}
 
+			s.aclTokenReplication.success(resp.Index)
+
 			// Update the minimum query index, blocks until there
 			// is a change.
 			req.MinQueryIndex = resp.Index
@@ -1283,3 +1554,30 @@ func (s *Server) getOrCreateSchedulerConfig() *structs.SchedulerConfiguration {
 
 	return config
 }
+
+// getOrCreateRootKey is used to get the root key used to encrypt variables. We
+// create a new key if none exists for bootstrapping an empty cluster.
+func (s *Server) getOrCreateRootKey() (*structs.RootKey, error) {
+	state := s.fsm.State()
+	key, err := state.LatestRootKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		return key, nil
+	}
+	if !ServersMeetMinimumVersion(s.Members(), minVariablesVersion) {
+		return nil, fmt.Errorf("all servers must be running version %v or later to use variables", minVariablesVersion)
+	}
+
+	key, err = newRootKey()
+	if err != nil {
+		return nil, err
+	}
+
+	req := structs.RootKeyUpsertRequest{RootKey: key}
+	if _, _, err = s.raftApply(structs.RootKeyUpsertRequestType, req); err != nil {
+		return nil, fmt.Errorf("failed to store root key: %v", err)
+	}
+	return key, nil
+}
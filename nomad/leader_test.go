@@ -3,12 +3,16 @@ package nomad
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/consul/testutil/retry"
 	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -753,6 +757,38 @@ func TestLeader_DiffACLPolicies(t *testing.T) {
 	assert.Equal(t, []string{p3.Name, p4.Name}, update)
 }
 
+func TestLeader_DiffACLRoles(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	r3 := mock.ACLRole()
+	assert.Nil(t, state.UpsertACLRoles(100, []*structs.ACLRole{r1, r2, r3}))
+
+	// Simulate a remote list
+	r2Stub := r2.Stub()
+	r2Stub.ModifyIndex = 50 // Ignored, same index
+	r3Stub := r3.Stub()
+	r3Stub.ModifyIndex = 100 // Updated, higher index
+	r3Stub.Hash = []byte{0, 1, 2, 3}
+	r4 := mock.ACLRole()
+	remoteList := []*structs.ACLRoleListStub{
+		r2Stub,
+		r3Stub,
+		r4.Stub(),
+	}
+	delete, update := diffACLRoles(state, 50, remoteList)
+
+	// R1 does not exist on the remote side, should delete
+	assert.Equal(t, []string{r1.Name}, delete)
+
+	// R2 is un-modified - ignore. R3 modified, R4 new.
+	assert.Equal(t, []string{r3.Name, r4.Name}, update)
+}
+
 func TestLeader_ReplicateACLTokens(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, func(c *Config) {
@@ -790,6 +826,101 @@ func TestLeader_ReplicateACLTokens(t *testing.T) {
 	})
 }
 
+func TestLeader_BootstrapACLTokenFromFile(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	secret := uuid.Generate()
+	path := filepath.Join(dir, "bootstrap-token")
+	require.NoError(ioutil.WriteFile(path, []byte(secret+"\n"), 0600))
+
+	s1 := TestServer(t, func(c *Config) {
+		c.ACLEnabled = true
+		c.ACLBootstrapTokenFile = path
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Wait for the token of the file to be bootstrapped
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := s1.State().ACLTokenBySecretID(nil, secret)
+		if err != nil {
+			return false, err
+		}
+		if out == nil {
+			return false, fmt.Errorf("token not bootstrapped")
+		}
+		if out.Type != structs.ACLManagementToken || !out.Global {
+			return false, fmt.Errorf("bad token: %#v", out)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	ok, _, err := s1.State().CanBootstrapACLToken()
+	require.NoError(err)
+	require.False(ok)
+}
+
+func TestLeader_BootstrapACLTokenFromFile_Bootstrapped(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	secret := uuid.Generate()
+	path := filepath.Join(dir, "bootstrap-token")
+	require.NoError(ioutil.WriteFile(path, []byte(secret), 0600))
+
+	// The cluster is already bootstrapped so the file is ignored
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.ACLBootstrapTokenFile = path
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	require.NoError(s1.bootstrapACLTokenFromFile())
+
+	out, err := s1.State().ACLTokenBySecretID(nil, secret)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = s1.State().ACLTokenBySecretID(nil, root.SecretID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestLeader_ReadACLBootstrapTokenFile(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bootstrap-token")
+	_, err = readACLBootstrapTokenFile(path)
+	require.Error(err)
+
+	require.NoError(ioutil.WriteFile(path, []byte("not-a-uuid"), 0600))
+	_, err = readACLBootstrapTokenFile(path)
+	require.Error(err)
+	require.Contains(err.Error(), "not a UUID")
+
+	secret := uuid.Generate()
+	require.NoError(ioutil.WriteFile(path, []byte("  "+secret+"\n"), 0600))
+	out, err := readACLBootstrapTokenFile(path)
+	require.NoError(err)
+	require.Equal(secret, out)
+}
+
 func TestLeader_DiffACLTokens(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +48,17 @@ func NodePolicy(policy string) string {
 	return fmt.Sprintf("node {\n\tpolicy = %q\n}\n", policy)
 }
 
+// NodePoolPolicy is a helper for generating the hcl for a given node pool
+// policy.
+func NodePoolPolicy(pool, policy string) string {
+	return fmt.Sprintf("node_pool %q {\n\tpolicy = %q\n}\n", pool, policy)
+}
+
+// OperatorPolicy is a helper for generating the hcl for a given operator policy.
+func OperatorPolicy(policy string) string {
+	return fmt.Sprintf("operator {\n\tpolicy = %q\n}\n", policy)
+}
+
 // QuotaPolicy is a helper for generating the hcl for a given quota policy.
 func QuotaPolicy(policy string) string {
 	return fmt.Sprintf("quota {\n\tpolicy = %q\n}\n", policy)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
 	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
@@ -84,6 +85,7 @@ func Node() *structs.Node {
 			"version":  "5.6",
 		},
 		NodeClass:             "linux-medium-pci",
+		NodePool:              structs.NodePoolDefault,
 		Status:                structs.NodeStatusReady,
 		SchedulingEligibility: structs.NodeSchedulingEligible,
 	}
@@ -727,6 +729,72 @@ func ACLToken() *structs.ACLToken {
 	return tk
 }
 
+func ACLRole() *structs.ACLRole {
+	role := &structs.ACLRole{
+		Name:        fmt.Sprintf("role-%s", uuid.Generate()),
+		Description: "Super cool role!",
+		Policies:    []string{"foo", "bar"},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	role.SetHash()
+	return role
+}
+
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	am := &structs.ACLAuthMethod{
+		Name:     fmt.Sprintf("auth-method-%s", uuid.Generate()[:8]),
+		Type:     structs.ACLAuthMethodTypeOIDC,
+		TokenTTL: time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "https://oidc.example.com",
+			OIDCClientID:        "nomad",
+			OIDCClientSecret:    "very-secret",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			BoundClaims:         map[string]string{"team": "engineering"},
+			Policies:            []string{"engineering"},
+			GroupsClaim:         "groups",
+			GroupPolicies:       map[string][]string{"admins": {"admin"}},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	am.SetHash()
+	return am
+}
+
+func ACLJWTAuthMethod() *structs.ACLAuthMethod {
+	am := &structs.ACLAuthMethod{
+		Name:     fmt.Sprintf("auth-method-%s", uuid.Generate()[:8]),
+		Type:     structs.ACLAuthMethodTypeJWT,
+		TokenTTL: time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			JWKSURL:        "https://ci.example.com/.well-known/jwks",
+			BoundIssuer:    "https://ci.example.com",
+			BoundAudiences: []string{"nomad"},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	am.SetHash()
+	return am
+}
+
+func ACLBindingRule(authMethod string) *structs.ACLBindingRule {
+	rule := &structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "Deploy from main",
+		AuthMethod:  authMethod,
+		Selector:    map[string]string{"ref": "refs/heads/main"},
+		BindType:    structs.ACLBindingRuleBindTypeRole,
+		BindName:    "deploy-${project}",
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	rule.SetHash()
+	return rule
+}
+
 func ACLManagementToken() *structs.ACLToken {
 	return &structs.ACLToken{
 		AccessorID:  uuid.Generate(),
@@ -739,3 +807,57 @@ func ACLManagementToken() *structs.ACLToken {
 		ModifyIndex: 20,
 	}
 }
+
+func VariableEncrypted() *structs.VariableEncrypted {
+	now := time.Now().UnixNano()
+	return &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace:  structs.DefaultNamespace,
+			Path:       "project/" + uuid.Generate(),
+			CreateTime: now,
+			ModifyTime: now,
+		},
+		Data:  []byte("encrypted"),
+		KeyID: uuid.Generate(),
+	}
+}
+
+func RootKey() *structs.RootKey {
+	return &structs.RootKey{
+		KeyID:      uuid.Generate(),
+		Algorithm:  structs.RootKeyAlgorithmAES256GCM,
+		Key:        make([]byte, 32),
+		CreateTime: time.Now().UnixNano(),
+	}
+}
+
+// ServiceRegistration returns the registration of a service of the given
+// allocation.
+func ServiceRegistration(alloc *structs.Allocation) *structs.ServiceRegistration {
+	return &structs.ServiceRegistration{
+		ID:          "_nomad-task-" + uuid.Generate(),
+		ServiceName: "web",
+		Namespace:   alloc.Namespace,
+		NodeID:      alloc.NodeID,
+		Datacenter:  "dc1",
+		JobID:       alloc.JobID,
+		AllocID:     alloc.ID,
+		Tags:        []string{"primary"},
+		Address:     "192.168.0.100",
+		Port:        5000,
+		Status:      "passing",
+	}
+}
+
+func NodePool() *structs.NodePool {
+	return &structs.NodePool{
+		Name:        fmt.Sprintf("pool-%s", uuid.Generate()[:8]),
+		Description: "Pool of the batch nodes",
+		Meta:        map[string]string{"team": "data"},
+		SchedulerConfiguration: &structs.NodePoolSchedulerConfiguration{
+			SystemSchedulerPreemptionEnabled: helper.BoolToPtr(true),
+		},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+}
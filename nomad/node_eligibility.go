@@ -0,0 +1,112 @@
+package nomad
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// watchNodeEligibility periodically marks the nodes within a maintenance
+// window as ineligible for scheduling, and the nodes whose ineligibility
+// expired as eligible again, until leadership is lost.
+func (s *Server) watchNodeEligibility(stopCh chan struct{}) {
+	ticker := time.NewTicker(s.config.NodeEligibilityWindowInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.updateNodeEligibility(time.Now().UTC())
+		}
+	}
+}
+
+// updateNodeEligibility applies the maintenance windows and eligibility
+// expiries of the nodes at the given time. Draining nodes are skipped since
+// their eligibility is handled by the drainer.
+func (s *Server) updateNodeEligibility(now time.Time) {
+	snap, err := s.fsm.State().Snapshot()
+	if err != nil {
+		s.logger.Error("failed to get state snapshot", "error", err)
+		return
+	}
+
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		s.logger.Error("failed to list nodes", "error", err)
+		return
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.DrainStrategy != nil {
+			continue
+		}
+
+		windowEnd, inWindow := node.MaintenanceWindowEnd(now)
+		switch node.SchedulingEligibility {
+		case structs.NodeSchedulingEligible:
+			if !inWindow {
+				continue
+			}
+			s.setNodeEligibility(node, structs.NodeSchedulingIneligible, windowEnd,
+				NodeEligibilityEventMaintenanceWindow)
+
+		case structs.NodeSchedulingIneligible:
+			// Nodes marked as ineligible without a TTL stay ineligible
+			if node.IneligibleUntil.IsZero() || now.Before(node.IneligibleUntil) {
+				continue
+			}
+
+			// Extend the ineligibility if another window started before the
+			// previous one ended
+			if inWindow && windowEnd.After(node.IneligibleUntil) {
+				s.setNodeEligibility(node, structs.NodeSchedulingIneligible, windowEnd,
+					NodeEligibilityEventMaintenanceWindow)
+				continue
+			}
+			s.setNodeEligibility(node, structs.NodeSchedulingEligible, time.Time{},
+				NodeEligibilityEventExpired)
+		}
+	}
+}
+
+// setNodeEligibility updates the eligibility of the node through Raft and
+// creates the node evaluations if it is marked as eligible.
+func (s *Server) setNodeEligibility(node *structs.Node, eligibility string, until time.Time, message string) {
+	event := structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemCluster).
+		SetMessage(message)
+	if !until.IsZero() {
+		event.AddDetail("ineligible_until", until.Format(time.RFC3339))
+	}
+
+	req := structs.NodeUpdateEligibilityRequest{
+		NodeID:          node.ID,
+		Eligibility:     eligibility,
+		IneligibleUntil: until,
+		NodeEvent:       event,
+		WriteRequest: structs.WriteRequest{
+			Region: s.config.Region,
+		},
+	}
+	resp, index, err := s.raftApply(structs.NodeUpdateEligibilityRequestType, &req)
+	if respErr, ok := resp.(error); ok && err == nil {
+		err = respErr
+	}
+	if err != nil {
+		s.logger.Error("failed to update node eligibility", "node_id", node.ID, "error", err)
+		return
+	}
+	s.logger.Info("updated node eligibility", "node_id", node.ID, "eligibility", eligibility,
+		"ineligible_until", until)
+
+	// Evaluate the system jobs that may now be placed on the node
+	if eligibility == structs.NodeSchedulingEligible {
+		if _, _, err := s.staticEndpoints.Node.createNodeEvals(node.ID, index); err != nil {
+			s.logger.Error("failed to create node evaluations", "node_id", node.ID, "error", err)
+		}
+	}
+}
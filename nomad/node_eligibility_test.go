@@ -0,0 +1,96 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_updateNodeEligibility(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create a node with a daily window from 02:00 to 04:00 UTC, and a node
+	// without windows that is ineligible until 03:00 UTC
+	windowed := mock.Node()
+	windowed.MaintenanceWindows = []*structs.NodeMaintenanceWindow{
+		{Cron: "0 2 * * *", Duration: 2 * time.Hour},
+	}
+	require.Nil(state.UpsertNode(100, windowed))
+
+	ttl := mock.Node()
+	require.Nil(state.UpsertNode(101, ttl))
+	until := time.Date(2019, 3, 1, 3, 0, 0, 0, time.UTC)
+	require.Nil(state.UpdateNodeEligibility(102, ttl.ID, structs.NodeSchedulingIneligible, until, nil))
+
+	// Within the window the windowed node is marked as ineligible
+	s1.updateNodeEligibility(time.Date(2019, 3, 1, 2, 30, 0, 0, time.UTC))
+
+	out, err := state.NodeByID(nil, windowed.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.Equal(time.Date(2019, 3, 1, 4, 0, 0, 0, time.UTC), out.IneligibleUntil)
+	require.Equal(NodeEligibilityEventMaintenanceWindow, out.Events[len(out.Events)-1].Message)
+
+	out, err = state.NodeByID(nil, ttl.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+
+	// Once the ineligibility expired the nodes are marked as eligible again
+	s1.updateNodeEligibility(time.Date(2019, 3, 1, 3, 30, 0, 0, time.UTC))
+
+	out, err = state.NodeByID(nil, ttl.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+	require.True(out.IneligibleUntil.IsZero())
+	require.Equal(NodeEligibilityEventExpired, out.Events[len(out.Events)-1].Message)
+
+	s1.updateNodeEligibility(time.Date(2019, 3, 1, 4, 0, 0, 0, time.UTC))
+
+	out, err = state.NodeByID(nil, windowed.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+}
+
+func TestServer_updateNodeEligibility_Manual(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Nodes marked as ineligible without a TTL and draining nodes are left
+	// alone, even within a window
+	manual := mock.Node()
+	manual.MaintenanceWindows = []*structs.NodeMaintenanceWindow{
+		{Cron: "0 2 * * *", Duration: 2 * time.Hour},
+	}
+	require.Nil(state.UpsertNode(100, manual))
+	require.Nil(state.UpdateNodeEligibility(101, manual.ID, structs.NodeSchedulingIneligible, time.Time{}, nil))
+
+	draining := mock.Node()
+	draining.MaintenanceWindows = manual.MaintenanceWindows
+	draining.DrainStrategy = &structs.DrainStrategy{}
+	draining.SchedulingEligibility = structs.NodeSchedulingEligible
+	require.Nil(state.UpsertNode(102, draining))
+
+	s1.updateNodeEligibility(time.Date(2019, 3, 1, 3, 0, 0, 0, time.UTC))
+
+	out, err := state.NodeByID(nil, manual.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.True(out.IneligibleUntil.IsZero())
+
+	out, err = state.NodeByID(nil, draining.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+}
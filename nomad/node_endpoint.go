@@ -43,6 +43,14 @@ const (
 	// ineligible
 	NodeEligibilityEventIneligible = "Node marked as ineligible for scheduling"
 
+	// NodeEligibilityEventMaintenanceWindow is used when the node is marked
+	// ineligible by one of its maintenance windows
+	NodeEligibilityEventMaintenanceWindow = "Node marked as ineligible for scheduling during maintenance window"
+
+	// NodeEligibilityEventExpired is used when the node is marked eligible
+	// after its ineligibility expired
+	NodeEligibilityEventExpired = "Node marked as eligible for scheduling after its ineligibility expired"
+
 	// NodeHeartbeatEventReregistered is the message used when the node becomes
 	// reregistered by the heartbeat.
 	NodeHeartbeatEventReregistered = "Node reregistered by heartbeat"
@@ -109,6 +117,14 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 	if args.Node.SecretID == "" {
 		return fmt.Errorf("missing node secret ID for client registration")
 	}
+	if pool := args.Node.NodePool; pool != "" {
+		if !structs.ValidNodePoolName(pool) {
+			return fmt.Errorf("invalid node pool %q for client registration", pool)
+		}
+		if pool == structs.NodePoolAll {
+			return fmt.Errorf("nodes can't be registered in the %q node pool", pool)
+		}
+	}
 
 	// Default the status if none is given
 	if args.Node.Status == "" {
@@ -172,7 +188,7 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		originalStatus = originalNode.Status
 	}
 	transitionToReady := transitionedToReady(args.Node.Status, originalStatus)
-	if structs.ShouldDrainNode(args.Node.Status) || transitionToReady {
+	if structs.ShouldDrainNode(args.Node.Status) || transitionToReady || excludedSystemJobsChanged(originalNode, args.Node) {
 		evalIDs, evalIndex, err := n.createNodeEvals(args.Node.ID, index)
 		if err != nil {
 			n.logger.Error("eval creation failed", "error", err)
@@ -305,7 +321,7 @@ func (n *Node) Deregister(args *structs.NodeDeregisterRequest, reply *structs.No
 
 	if l := len(accessors); l != 0 {
 		n.logger.Debug("revoking accessors on node due to deregister", "num_accessors", l, "node_id", args.NodeID)
-		if err := n.srv.vault.RevokeTokens(context.Background(), accessors, true); err != nil {
+		if err := n.srv.revokeVaultTokens(context.Background(), accessors, true); err != nil {
 			n.logger.Error("revoking accessors for node failed", "node_id", args.NodeID, "error", err)
 			return err
 		}
@@ -382,6 +398,7 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 				SetMessage(NodeHeartbeatEventReregistered)
 		}
 
+		args.UpdatedAt = node.StatusUpdatedAt
 		_, index, err = n.srv.raftApply(structs.NodeUpdateStatusRequestType, args)
 		if err != nil {
 			n.logger.Error("status update failed", "error", err)
@@ -414,7 +431,7 @@ func (n *Node) UpdateStatus(args *structs.NodeUpdateStatusRequest, reply *struct
 
 		if l := len(accessors); l != 0 {
 			n.logger.Debug("revoking accessors on node due to down state", "num_accessors", l, "node_id", args.NodeID)
-			if err := n.srv.vault.RevokeTokens(context.Background(), accessors, true); err != nil {
+			if err := n.srv.revokeVaultTokens(context.Background(), accessors, true); err != nil {
 				n.logger.Error("revoking accessors for node failed", "node_id", args.NodeID, "error", err)
 				return err
 			}
@@ -448,6 +465,15 @@ func transitionedToReady(newStatus, oldStatus string) bool {
 	return initToReady || terminalToReady
 }
 
+// excludedSystemJobsChanged returns whether a registered node changed the
+// system jobs it opts out of, so that their allocations are stopped or placed.
+func excludedSystemJobsChanged(original, node *structs.Node) bool {
+	if original == nil {
+		return false
+	}
+	return original.Meta[structs.NodeMetaExcludeSystemJobs] != node.Meta[structs.NodeMetaExcludeSystemJobs]
+}
+
 // UpdateDrain is used to update the drain mode of a client node
 func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 	reply *structs.NodeDrainUpdateResponse) error {
@@ -566,6 +592,17 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 		return fmt.Errorf("invalid scheduling eligibility %q", args.Eligibility)
 	}
 
+	// Compute when the node is marked as eligible again
+	args.IneligibleUntil = time.Time{}
+	if args.TTL < 0 {
+		return fmt.Errorf("TTL must not be negative")
+	} else if args.TTL > 0 {
+		if args.Eligibility != structs.NodeSchedulingIneligible {
+			return fmt.Errorf("TTL can only be set when marking a node as ineligible")
+		}
+		args.IneligibleUntil = time.Now().UTC().Add(args.TTL)
+	}
+
 	// Look for the node
 	snap, err := n.srv.fsm.State().Snapshot()
 	if err != nil {
@@ -591,12 +628,15 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 
 	// Construct the node event
 	args.NodeEvent = structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemCluster)
-	if node.SchedulingEligibility == args.Eligibility {
+	if node.SchedulingEligibility == args.Eligibility && node.IneligibleUntil.Equal(args.IneligibleUntil) {
 		return nil // Nothing to do
 	} else if args.Eligibility == structs.NodeSchedulingEligible {
 		args.NodeEvent.SetMessage(NodeEligibilityEventEligible)
 	} else {
 		args.NodeEvent.SetMessage(NodeEligibilityEventIneligible)
+		if !args.IneligibleUntil.IsZero() {
+			args.NodeEvent.AddDetail("ineligible_until", args.IneligibleUntil.Format(time.RFC3339))
+		}
 	}
 
 	// Commit this update via Raft
@@ -983,6 +1023,57 @@ func (n *Node) GetClientAllocs(args *structs.NodeSpecificRequest,
 	return n.srv.blockingRPC(&opts)
 }
 
+// GetPrefetches is used to request the images and artifacts a client should
+// prefetch for the jobs with a prefetch hint it can run.
+func (n *Node) GetPrefetches(args *structs.NodeSpecificRequest,
+	reply *structs.NodePrefetchesResponse) error {
+	if done, err := n.srv.forward("Node.GetPrefetches", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "get_prefetches"}, time.Now())
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			node, err := state.NodeByID(ws, args.NodeID)
+			if err != nil {
+				return err
+			}
+
+			reply.Prefetches = nil
+			if node != nil {
+				if args.SecretID != node.SecretID {
+					return fmt.Errorf("node secret ID does not match")
+				}
+
+				reply.Prefetches, err = nodePrefetches(ws, state, node, n.logger)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Use the last index that affected the jobs or nodes tables
+			index, err := state.Index("jobs")
+			if err != nil {
+				return err
+			}
+			nodeIndex, err := state.Index("nodes")
+			if err != nil {
+				return err
+			}
+			reply.Index = maxUint64(1, index, nodeIndex)
+			return nil
+		}}
+	return n.srv.blockingRPC(&opts)
+}
+
 // UpdateAlloc is used to update the client status of an allocation
 func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.GenericResponse) error {
 	if done, err := n.srv.forward("Node.UpdateAlloc", args, args, reply); done {
@@ -1134,7 +1225,7 @@ func (n *Node) batchUpdate(future *structs.BatchFuture, updates []*structs.Alloc
 
 	if l := len(revoke); l != 0 {
 		n.logger.Debug("revoking accessors due to terminal allocations", "num_accessors", l)
-		if err := n.srv.vault.RevokeTokens(context.Background(), revoke, true); err != nil {
+		if err := n.srv.revokeVaultTokens(context.Background(), revoke, true); err != nil {
 			n.logger.Error("batched Vault accessor revocation failed", "error", err)
 			mErr.Errors = append(mErr.Errors, err)
 		}
@@ -1416,6 +1507,17 @@ func (n *Node) DeriveVaultToken(args *structs.DeriveVaultTokenRequest,
 		return nil
 	}
 
+	// Resolve the clients of the Vault clusters of the tasks
+	vaults := make(map[string]VaultClient, len(args.Tasks))
+	for _, task := range args.Tasks {
+		vault, err := n.srv.vaultClient(tg[task].Cluster)
+		if err != nil {
+			setErr(fmt.Errorf("Task %q: %v", task, err), false)
+			return nil
+		}
+		vaults[task] = vault
+	}
+
 	// At this point the request is valid and we should contact Vault for
 	// tokens.
 
@@ -1442,7 +1544,7 @@ func (n *Node) DeriveVaultToken(args *structs.DeriveVaultTokenRequest,
 						return nil
 					}
 
-					secret, err := n.srv.vault.CreateToken(ctx, alloc, task)
+					secret, err := vaults[task].CreateToken(ctx, alloc, task)
 					if err != nil {
 						return err
 					}
@@ -1483,6 +1585,8 @@ func (n *Node) DeriveVaultToken(args *structs.DeriveVaultTokenRequest,
 			NodeID:      alloc.NodeID,
 			AllocID:     alloc.ID,
 			CreationTTL: w.TTL,
+			Namespace:   tg[task].Namespace,
+			Cluster:     tg[task].Cluster,
 		}
 
 		accessors = append(accessors, accessor)
@@ -1492,7 +1596,7 @@ func (n *Node) DeriveVaultToken(args *structs.DeriveVaultTokenRequest,
 	if createErr != nil {
 		n.logger.Error("Vault token creation for alloc failed", "alloc_id", alloc.ID, "error", createErr)
 
-		if revokeErr := n.srv.vault.RevokeTokens(context.Background(), accessors, false); revokeErr != nil {
+		if revokeErr := n.srv.revokeVaultTokens(context.Background(), accessors, false); revokeErr != nil {
 			n.logger.Error("Vault token revocation for alloc failed", "alloc_id", alloc.ID, "error", revokeErr)
 		}
 
@@ -1528,6 +1632,155 @@ func (n *Node) DeriveVaultToken(args *structs.DeriveVaultTokenRequest,
 	return nil
 }
 
+// SignIdentities is used by the clients to request the workload identity JWTs
+// of tasks, which they exchange for Vault tokens with the Vault JWT auth
+// method.
+func (n *Node) SignIdentities(args *structs.SignIdentitiesRequest,
+	reply *structs.SignIdentitiesResponse) error {
+
+	// setErr is a helper for setting the recoverable error on the reply and
+	// logging it
+	setErr := func(e error, recoverable bool) {
+		if e == nil {
+			return
+		}
+		re, ok := e.(*structs.RecoverableError)
+		if ok {
+			// No need to wrap if error is already a RecoverableError
+			reply.Error = re
+		} else {
+			reply.Error = structs.NewRecoverableError(e, recoverable).(*structs.RecoverableError)
+		}
+
+		n.logger.Error("SignIdentities failed", "recoverable", recoverable, "error", e)
+	}
+
+	if done, err := n.srv.forward("Node.SignIdentities", args, args, reply); done {
+		setErr(err, structs.IsRecoverable(err) || err == structs.ErrNoLeader)
+		return nil
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "sign_identities"}, time.Now())
+
+	// Verify the arguments
+	if args.NodeID == "" {
+		setErr(fmt.Errorf("missing node ID"), false)
+		return nil
+	}
+	if args.SecretID == "" {
+		setErr(fmt.Errorf("missing node SecretID"), false)
+		return nil
+	}
+	if args.AllocID == "" {
+		setErr(fmt.Errorf("missing allocation ID"), false)
+		return nil
+	}
+	if len(args.Tasks) == 0 {
+		setErr(fmt.Errorf("no tasks specified"), false)
+		return nil
+	}
+
+	// Verify the following:
+	// * The Node exists and has the correct SecretID
+	// * The Allocation exists on the specified node
+	// * The allocation contains the given tasks and they each use Vault
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		setErr(err, false)
+		return nil
+	}
+	ws := memdb.NewWatchSet()
+	node, err := snap.NodeByID(ws, args.NodeID)
+	if err != nil {
+		setErr(err, false)
+		return nil
+	}
+	if node == nil {
+		setErr(fmt.Errorf("Node %q does not exist", args.NodeID), false)
+		return nil
+	}
+	if node.SecretID != args.SecretID {
+		setErr(fmt.Errorf("SecretID mismatch"), false)
+		return nil
+	}
+
+	alloc, err := snap.AllocByID(ws, args.AllocID)
+	if err != nil {
+		setErr(err, false)
+		return nil
+	}
+	if alloc == nil {
+		setErr(fmt.Errorf("Allocation %q does not exist", args.AllocID), false)
+		return nil
+	}
+	if alloc.NodeID != args.NodeID {
+		setErr(fmt.Errorf("Allocation %q not running on Node %q", args.AllocID, args.NodeID), false)
+		return nil
+	}
+	if alloc.TerminalStatus() {
+		setErr(fmt.Errorf("Can't sign identities for terminal allocation"), false)
+		return nil
+	}
+
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		setErr(fmt.Errorf("Task group %q does not exist", alloc.TaskGroup), false)
+		return nil
+	}
+
+	// Identities for the Nomad API are only given to the tasks that expose
+	// theirs, and are valid while the allocation runs. The others are only
+	// given to tasks using Vault.
+	forNomad := args.Audience == structs.IdentityAudienceNomad
+	stanza, ttl := "vault", workloadIdentityTTL
+	if forNomad {
+		stanza, ttl = "identity", 0
+	}
+
+	var unneeded []string
+	for _, name := range args.Tasks {
+		task := tg.LookupTask(name)
+		if task == nil || (forNomad && task.Identity == nil) || (!forNomad && task.Vault == nil) {
+			unneeded = append(unneeded, name)
+		}
+	}
+	if len(unneeded) != 0 {
+		e := fmt.Errorf("Requested identities for tasks without a %s stanza: %s",
+			stanza, strings.Join(unneeded, ", "))
+		setErr(e, false)
+		return nil
+	}
+
+	// The root key is created by the leader once it is established
+	key, err := snap.LatestRootKey(ws)
+	if err != nil {
+		setErr(err, false)
+		return nil
+	}
+	if key == nil {
+		setErr(fmt.Errorf("no root key to sign identities with"), true)
+		return nil
+	}
+
+	now := time.Now()
+	reply.Tasks = make(map[string]string, len(args.Tasks))
+	for _, task := range args.Tasks {
+		claims := structs.NewIdentityClaims(alloc, task)
+		if forNomad {
+			claims.Audience = structs.IdentityAudienceNomad
+		}
+		jwt, err := signIdentity(key, claims, now, ttl)
+		if err != nil {
+			setErr(err, false)
+			return nil
+		}
+		reply.Tasks[task] = jwt
+	}
+
+	reply.Index = alloc.ModifyIndex
+	n.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 func (n *Node) EmitEvents(args *structs.EmitNodeEventsRequest, reply *structs.EmitNodeEventsResponse) error {
 	if done, err := n.srv.forward("Node.EmitEvents", args, args, reply); done {
 		return err
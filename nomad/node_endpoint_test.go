@@ -653,6 +653,46 @@ func TestClientEndpoint_Register_GetEvals(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_Register_ExcludeSystemJobs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register a system job and a ready node
+	job := mock.SystemJob()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertJob(1, job))
+
+	node := mock.Node()
+	node.Status = structs.NodeStatusReady
+	reg := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp))
+	require.Len(resp.EvalIDs, 1)
+
+	// Registering the node again doesn't create evals
+	var resp2 structs.NodeUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp2))
+	require.Empty(resp2.EvalIDs)
+
+	// Opting out of the system job evaluates it
+	node.Meta[structs.NodeMetaExcludeSystemJobs] = job.ID
+	var resp3 structs.NodeUpdateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.Register", reg, &resp3))
+	require.Len(resp3.EvalIDs, 1)
+
+	eval, err := state.EvalByID(nil, resp3.EvalIDs[0])
+	require.NoError(err)
+	require.Equal(job.ID, eval.JobID)
+	require.Equal(structs.EvalTriggerNodeUpdate, eval.TriggeredBy)
+}
+
 func TestClientEndpoint_UpdateStatus_GetEvals(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -1182,6 +1222,52 @@ func TestClientEndpoint_UpdateEligibility(t *testing.T) {
 	require.Equal(NodeEligibilityEventEligible, out.Events[2].Message)
 }
 
+func TestClientEndpoint_UpdateEligibility_TTL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the node
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertNode(1, node))
+
+	// A TTL can't be set when marking the node as eligible
+	elig := &structs.NodeUpdateEligibilityRequest{
+		NodeID:       node.ID,
+		Eligibility:  structs.NodeSchedulingEligible,
+		TTL:          time.Hour,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeEligibilityUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", elig, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "TTL can only be set")
+
+	// Mark the node as ineligible with a TTL
+	start := time.Now().UTC()
+	elig.Eligibility = structs.NodeSchedulingIneligible
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", elig, &resp))
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.False(out.IneligibleUntil.Before(start.Add(time.Hour)))
+	require.Contains(out.Events[len(out.Events)-1].Details, "ineligible_until")
+
+	// Marking the node as ineligible without a TTL clears the expiry
+	elig.TTL = 0
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.UpdateEligibility", elig, &resp))
+
+	out, err = state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.True(out.IneligibleUntil.IsZero())
+}
+
 func TestClientEndpoint_UpdateEligibility_ACL(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1606,6 +1692,57 @@ func TestClientEndpoint_GetAllocs_ACL_Basic(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_GetPrefetches(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNode(98, node))
+
+	job := mock.Job()
+	job.Prefetch = &structs.JobPrefetch{Images: true}
+	job.TaskGroups[0].Tasks[0].Config["image"] = "app:1.0"
+	require.NoError(state.UpsertJob(100, job))
+
+	get := &structs.NodeSpecificRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NodePrefetchesResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.GetPrefetches", get, &resp))
+	require.EqualValues(100, resp.Index)
+	require.Len(resp.Prefetches, 1)
+	require.Equal("app:1.0", resp.Prefetches[0].Image)
+
+	// Block until the job is stopped
+	job2 := job.Copy()
+	job2.Stop = true
+	time.AfterFunc(100*time.Millisecond, func() {
+		require.NoError(state.UpsertJob(200, job2))
+	})
+	get.MinQueryIndex = 150
+	var resp2 structs.NodePrefetchesResponse
+	start := time.Now()
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.GetPrefetches", get, &resp2))
+	require.True(time.Since(start) >= 100*time.Millisecond, "should block")
+	require.EqualValues(200, resp2.Index)
+	require.Empty(resp2.Prefetches)
+
+	// Lookup node with bad SecretID
+	get.SecretID = "foobarbaz"
+	get.MinQueryIndex = 0
+	var resp3 structs.NodePrefetchesResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.GetPrefetches", get, &resp3)
+	require.Error(err)
+	require.Contains(err.Error(), "does not match")
+}
+
 func TestClientEndpoint_GetClientAllocs(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -2688,7 +2825,7 @@ func TestClientEndpoint_ListNodes_Blocking(t *testing.T) {
 
 	// Node status update triggers watches
 	time.AfterFunc(100*time.Millisecond, func() {
-		errCh <- state.UpdateNodeStatus(40, node.ID, structs.NodeStatusDown, nil)
+		errCh <- state.UpdateNodeStatus(40, node.ID, structs.NodeStatusDown, 0, nil)
 	})
 
 	req.MinQueryIndex = 38
@@ -2739,6 +2876,160 @@ func TestClientEndpoint_ListNodes_Blocking(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_SignIdentities(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the node
+	node := mock.Node()
+	require.Nil(state.UpsertNode(2, node))
+
+	// Create an alloc running on the node
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	require.Nil(state.UpsertAllocs(3, []*structs.Allocation{alloc}))
+
+	req := &structs.SignIdentitiesRequest{
+		NodeID:   node.ID,
+		SecretID: uuid.Generate(),
+		AllocID:  alloc.ID,
+		Tasks:    []string{task.Name},
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	var resp structs.SignIdentitiesResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp))
+	require.NotNil(resp.Error)
+	require.Contains(resp.Error.Error(), "SecretID mismatch")
+
+	// The task doesn't use Vault
+	req.SecretID = node.SecretID
+	resp = structs.SignIdentitiesResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp))
+	require.NotNil(resp.Error)
+	require.Contains(resp.Error.Error(), "without a vault stanza")
+
+	// Give the task a vault stanza
+	alloc = alloc.Copy()
+	alloc.Job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{Policies: []string{"a"}}
+	require.Nil(state.UpsertAllocs(4, []*structs.Allocation{alloc}))
+
+	resp = structs.SignIdentitiesResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp))
+	require.Nil(resp.Error)
+	require.Contains(resp.Tasks, task.Name)
+	require.Len(strings.Split(resp.Tasks[task.Name], "."), 3)
+
+	// The identity can be verified with the public keys
+	var keys structs.KeyringListPublicResponse
+	listReq := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Keyring.ListPublic", listReq, &keys))
+	require.Len(keys.Keys, 1)
+	require.Equal("ES256", keys.Keys[0].Algorithm)
+
+	key, err := state.LatestRootKey(nil)
+	require.Nil(err)
+	require.Equal(key.KeyID, keys.Keys[0].KeyID)
+}
+
+func TestClientEndpoint_SignIdentities_Nomad(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the node
+	node := mock.Node()
+	require.Nil(state.UpsertNode(2, node))
+
+	// Create an alloc running on the node, whose task exposes its identity
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Identity = &structs.WorkloadIdentity{Env: true}
+	require.Nil(state.UpsertJob(3, alloc.Job))
+	require.Nil(state.UpsertAllocs(4, []*structs.Allocation{alloc}))
+
+	req := &structs.SignIdentitiesRequest{
+		NodeID:   node.ID,
+		SecretID: node.SecretID,
+		AllocID:  alloc.ID,
+		Tasks:    []string{task.Name},
+		Audience: structs.IdentityAudienceNomad,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.SignIdentitiesResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", req, &resp))
+	require.Nil(resp.Error)
+	token := resp.Tasks[task.Name]
+	require.NotEmpty(token)
+
+	// Vault identities aren't valid for the Nomad API
+	vaultReq := *req
+	vaultReq.Audience = ""
+	resp = structs.SignIdentitiesResponse{}
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Node.SignIdentities", &vaultReq, &resp))
+	require.NotNil(resp.Error)
+	require.Contains(resp.Error.Error(), "without a vault stanza")
+
+	// The identity can read its job
+	jobReq := &structs.JobSpecificRequest{
+		JobID: alloc.JobID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: alloc.Namespace,
+			AuthToken: token,
+		},
+	}
+	var jobResp structs.SingleJobResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Job.GetJob", jobReq, &jobResp))
+	require.NotNil(jobResp.Job)
+
+	// But not another job
+	jobReq.JobID = "other"
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJob", jobReq, &jobResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The identity can read the variables of its job only
+	varReq := &structs.VariablesReadRequest{
+		Path: structs.VariablesJobsPrefix + "/" + alloc.JobID + "/config",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: alloc.Namespace,
+			AuthToken: token,
+		},
+	}
+	var varResp structs.VariablesReadResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Variables.Read", varReq, &varResp))
+	varReq.Path = structs.VariablesJobsPrefix + "/other/config"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", varReq, &varResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The identity is no longer valid once the allocation is terminal
+	alloc = alloc.Copy()
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	require.Nil(state.UpsertAllocs(5, []*structs.Allocation{alloc}))
+	jobReq.JobID = alloc.JobID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJob", jobReq, &jobResp)
+	require.EqualError(err, structs.ErrTokenExpired.Error())
+}
+
 func TestClientEndpoint_DeriveVaultToken_Bad(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -2912,6 +3203,66 @@ func TestClientEndpoint_DeriveVaultToken(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_DeriveVaultToken_Cluster(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	state := s1.fsm.State()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Replace the Vault Clients on the server, the task only using the
+	// named cluster
+	s1.vault = &TestVaultClient{}
+	tvc := &TestVaultClient{}
+	s1.vaults = map[string]VaultClient{"secrets": tvc}
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(2, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Vault = &structs.Vault{Policies: []string{"a"}, Cluster: "secrets"}
+	require.NoError(state.UpsertAllocs(3, []*structs.Allocation{alloc}))
+
+	accessor := uuid.Generate()
+	tvc.SetCreateTokenSecret(alloc.ID, task.Name, &vapi.Secret{
+		WrapInfo: &vapi.SecretWrapInfo{
+			Token:           uuid.Generate(),
+			WrappedAccessor: accessor,
+			TTL:             10,
+		},
+	})
+
+	req := &structs.DeriveVaultTokenRequest{
+		NodeID:   node.ID,
+		SecretID: node.SecretID,
+		AllocID:  alloc.ID,
+		Tasks:    []string{task.Name},
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.DeriveVaultTokenResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.DeriveVaultToken", req, &resp))
+	require.Nil(resp.Error)
+
+	// The accessor records the cluster its token was created in
+	va, err := state.VaultAccessor(nil, accessor)
+	require.NoError(err)
+	require.NotNil(va)
+	require.Equal("secrets", va.Cluster)
+
+	// Tasks can't use unknown clusters
+	task.Vault.Cluster = "unknown"
+	require.NoError(state.UpsertAllocs(4, []*structs.Allocation{alloc}))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.DeriveVaultToken", req, &resp))
+	require.NotNil(resp.Error)
+	require.Contains(resp.Error.Error(), `unknown Vault cluster "unknown"`)
+}
+
 func TestClientEndpoint_DeriveVaultToken_VaultError(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -0,0 +1,146 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePoolEndpoint_UpsertNodePools(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := mock.NodePool()
+	req := &structs.NodePoolUpsertRequest{
+		NodePools:    []*structs.NodePool{pool},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Check the state store
+	out, err := s1.fsm.State().NodePoolByName(nil, pool.Name)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(resp.Index, out.CreateIndex)
+
+	// Invalid node pools are rejected
+	invalid := mock.NodePool()
+	invalid.Name = "bad name"
+	req.NodePools = []*structs.NodePool{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+}
+
+func TestNodePoolEndpoint_GetNodePool(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	pool := mock.NodePool()
+	require.NoError(s1.fsm.State().UpsertNodePools(1000, []*structs.NodePool{pool}))
+
+	get := &structs.NodePoolSpecificRequest{
+		Name:         pool.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleNodePoolResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", get, &resp))
+	require.Equal(uint64(1000), resp.Index)
+	require.Equal(pool, resp.NodePool)
+
+	// Lookup a missing node pool
+	get.Name = "missing"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.GetNodePool", get, &resp))
+	require.Nil(resp.NodePool)
+}
+
+func TestNodePoolEndpoint_List_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	pool1 := mock.NodePool()
+	pool1.Name = "dev-1"
+	pool2 := mock.NodePool()
+	pool2.Name = "prod-1"
+	require.NoError(state.UpsertNodePools(1000, []*structs.NodePool{pool1, pool2}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "dev",
+		mock.NodePoolPolicy("dev-*", acl.PolicyRead))
+
+	get := &structs.NodePoolListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// The management token sees every node pool
+	get.AuthToken = root.SecretID
+	var resp structs.NodePoolListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.List", get, &resp))
+	require.Len(resp.NodePools, 4)
+
+	// The token only sees the pools it may read
+	get.AuthToken = token.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.List", get, &resp))
+	require.Len(resp.NodePools, 1)
+	require.Equal(pool1.Name, resp.NodePools[0].Name)
+}
+
+func TestNodePoolEndpoint_DeleteNodePools(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	pool := mock.NodePool()
+	require.NoError(state.UpsertNodePools(1000, []*structs.NodePool{pool}))
+
+	node := mock.Node()
+	node.NodePool = pool.Name
+	require.NoError(state.UpsertNode(1001, node))
+
+	req := &structs.NodePoolDeleteRequest{
+		Names:        []string{pool.Name},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Node pools with nodes can't be deleted
+	err := msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "has nodes")
+
+	require.NoError(state.DeleteNode(1002, node.ID))
+	require.NoError(msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp))
+
+	out, err := state.NodePoolByName(nil, pool.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Built-in node pools can't be deleted
+	req.Names = []string{structs.NodePoolDefault}
+	err = msgpackrpc.CallWithCodec(codec, "NodePool.DeleteNodePools", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "built-in")
+}
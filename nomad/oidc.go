@@ -0,0 +1,372 @@
+package nomad
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// oidcDiscoveryPath is the path of the OIDC provider configuration
+	// below the issuer URL.
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+	// oidcClockSkew is how far the clocks of the servers and the OIDC
+	// provider may differ when checking the validity of an ID token.
+	oidcClockSkew = time.Minute
+
+	// oidcMaxResponseSize limits the size of the documents read from the
+	// OIDC provider.
+	oidcMaxResponseSize = 1 << 20
+)
+
+// oidcHTTPClient is used for all requests to OIDC providers.
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// oidcProvider is the part of the OIDC provider configuration used to log
+// users in.
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcJSONWebKey is a public key of an OIDC provider, in the JSON Web Key
+// format of RFC 7517. Only RSA and EC P-256 keys are supported.
+type oidcJSONWebKey struct {
+	KeyID   string `json:"kid"`
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+}
+
+// oidcGetJSON fetches the URL and decodes its JSON body into out.
+func oidcGetJSON(u string, out interface{}) error {
+	resp, err := oidcHTTPClient.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code %d from %s", resp.StatusCode, u)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, oidcMaxResponseSize)).Decode(out)
+}
+
+// oidcDiscover returns the configuration of the OIDC provider of the auth
+// method.
+func oidcDiscover(config *structs.ACLAuthMethodConfig) (*oidcProvider, error) {
+	issuer := strings.TrimSuffix(config.OIDCDiscoveryURL, "/")
+
+	var provider oidcProvider
+	if err := oidcGetJSON(issuer+oidcDiscoveryPath, &provider); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %v", err)
+	}
+
+	switch {
+	case strings.TrimSuffix(provider.Issuer, "/") != issuer:
+		return nil, fmt.Errorf("OIDC provider issuer %q does not match discovery URL %q", provider.Issuer, config.OIDCDiscoveryURL)
+	case provider.AuthorizationEndpoint == "":
+		return nil, fmt.Errorf("OIDC provider has no authorization endpoint")
+	case provider.TokenEndpoint == "":
+		return nil, fmt.Errorf("OIDC provider has no token endpoint")
+	case provider.JWKSURI == "":
+		return nil, fmt.Errorf("OIDC provider has no JWKS URI")
+	}
+	return &provider, nil
+}
+
+// oidcAuthURL returns the URL of the provider that the user is sent to to
+// log in.
+func oidcAuthURL(config *structs.ACLAuthMethodConfig, provider *oidcProvider, redirectURI, state, nonce string) string {
+	scopes := append([]string{"openid"}, config.OIDCScopes...)
+	params := url.Values{
+		"response_type": {"code"},
+		"client_id":     {config.OIDCClientID},
+		"redirect_uri":  {redirectURI},
+		"scope":         {strings.Join(scopes, " ")},
+		"state":         {state},
+		"nonce":         {nonce},
+	}
+
+	sep := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return provider.AuthorizationEndpoint + sep + params.Encode()
+}
+
+// oidcExchangeCode exchanges the authorization code for the ID token of the
+// user.
+func oidcExchangeCode(config *structs.ACLAuthMethodConfig, provider *oidcProvider, redirectURI, code string) (string, error) {
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+	req, err := http.NewRequest("POST", provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(config.OIDCClientID), url.QueryEscape(config.OIDCClientSecret))
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %v", err)
+	}
+
+	var out struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("failed to decode token response with code %d: %v", resp.StatusCode, err)
+	}
+	if out.Error != "" {
+		return "", fmt.Errorf("failed to exchange authorization code: %s: %s", out.Error, out.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to exchange authorization code: unexpected response code %d", resp.StatusCode)
+	}
+	if out.IDToken == "" {
+		return "", fmt.Errorf("token response has no ID token")
+	}
+	return out.IDToken, nil
+}
+
+// oidcVerifyIDToken verifies the signature and validity of the ID token and
+// returns its claims.
+func oidcVerifyIDToken(config *structs.ACLAuthMethodConfig, provider *oidcProvider, idToken, nonce string, now time.Time) (map[string]interface{}, error) {
+	claims, err := verifySignedJWT(provider.JWKSURI, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the standard claims
+	if iss, _ := claims["iss"].(string); iss != provider.Issuer {
+		return nil, fmt.Errorf("ID token issuer %q is not %q", iss, provider.Issuer)
+	}
+	audiences := config.BoundAudiences
+	if len(audiences) == 0 {
+		audiences = []string{config.OIDCClientID}
+	}
+	if !claimContainsAny(claims["aud"], audiences) {
+		return nil, fmt.Errorf("ID token audience is not bound by the auth method")
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("ID token has no expiry")
+	}
+	if now.Add(-oidcClockSkew).After(time.Unix(int64(exp), 0)) {
+		return nil, fmt.Errorf("ID token expired")
+	}
+	if n, _ := claims["nonce"].(string); n != nonce {
+		return nil, fmt.Errorf("ID token nonce does not match")
+	}
+	return claims, nil
+}
+
+// verifySignedJWT verifies the signature of the JWT with the keys of the
+// JSON Web Key Set at the URL and returns its claims. The claims themselves
+// are left to the caller to check.
+func verifySignedJWT(jwksURL, jwt string) (map[string]interface{}, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed JWT header: %v", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT signature: %v", err)
+	}
+
+	var jwks struct {
+		Keys []*oidcJSONWebKey `json:"keys"`
+	}
+	if err := oidcGetJSON(jwksURL, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %v", err)
+	}
+
+	var key *oidcJSONWebKey
+	for _, k := range jwks.Keys {
+		if header.KeyID == "" || k.KeyID == header.KeyID {
+			key = k
+			break
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("no signing key with ID %q", header.KeyID)
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := verifyJWTSignature(header.Algorithm, key, digest[:], sig); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed JWT claims: %v", err)
+	}
+	return claims, nil
+}
+
+// decodeJWTSegment decodes the base64 encoded JSON segment of a JWT.
+func decodeJWTSegment(segment string, out interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// verifyJWTSignature verifies the signature of the SHA-256 digest of a JWT
+// with the key.
+func verifyJWTSignature(alg string, key *oidcJSONWebKey, digest, sig []byte) error {
+	switch alg {
+	case "RS256":
+		if key.KeyType != "RSA" {
+			return fmt.Errorf("signing key %q is not an RSA key", key.KeyID)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			return fmt.Errorf("malformed signing key %q: %v", key.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			return fmt.Errorf("malformed signing key %q: %v", key.KeyID, err)
+		}
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, sig); err != nil {
+			return fmt.Errorf("invalid JWT signature")
+		}
+
+	case "ES256":
+		if key.KeyType != "EC" || key.Curve != "P-256" {
+			return fmt.Errorf("signing key %q is not a P-256 key", key.KeyID)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(key.X)
+		if err != nil {
+			return fmt.Errorf("malformed signing key %q: %v", key.KeyID, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(key.Y)
+		if err != nil {
+			return fmt.Errorf("malformed signing key %q: %v", key.KeyID, err)
+		}
+		if len(sig) != 64 {
+			return fmt.Errorf("invalid JWT signature")
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("invalid JWT signature")
+		}
+
+	default:
+		return fmt.Errorf("unsupported JWT signing algorithm %q", alg)
+	}
+	return nil
+}
+
+// claimContainsAny returns whether the string or list claim contains any of
+// the values.
+func claimContainsAny(claim interface{}, values []string) bool {
+	for _, v := range claimStrings(claim) {
+		for _, want := range values {
+			if v == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimStrings returns the string values of a string or list claim.
+func claimStrings(claim interface{}) []string {
+	switch c := claim.(type) {
+	case string:
+		return []string{c}
+	case []interface{}:
+		out := make([]string, 0, len(c))
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// authMethodPolicies checks the claims of the user against the bound claims
+// of the auth method and returns the policies the auth method itself gives
+// the user. Binding rules may grant more.
+func authMethodPolicies(config *structs.ACLAuthMethodConfig, claims map[string]interface{}) ([]string, error) {
+	for k, v := range config.BoundClaims {
+		if !claimContainsAny(claims[k], []string{v}) {
+			return nil, fmt.Errorf("claim %q does not match the bound claims of the auth method", k)
+		}
+	}
+
+	set := make(map[string]struct{})
+	for _, p := range config.Policies {
+		set[p] = struct{}{}
+	}
+	if config.GroupsClaim != "" {
+		for _, group := range claimStrings(claims[config.GroupsClaim]) {
+			for _, p := range config.GroupPolicies[group] {
+				set[p] = struct{}{}
+			}
+		}
+	}
+
+	policies := make([]string, 0, len(set))
+	for p := range set {
+		policies = append(policies, p)
+	}
+	sort.Strings(policies)
+	return policies, nil
+}
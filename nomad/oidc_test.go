@@ -0,0 +1,240 @@
+package nomad
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testOIDCProvider is a fake OIDC provider that issues ID tokens for the
+// authorization code "valid-code".
+type testOIDCProvider struct {
+	srv *httptest.Server
+	key *ecdsa.PrivateKey
+
+	// nonce and claims are put in the ID tokens issued for codes
+	nonce  string
+	claims map[string]interface{}
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p := &testOIDCProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.srv.URL,
+			"authorization_endpoint": p.srv.URL + "/auth",
+			"token_endpoint":         p.srv.URL + "/token",
+			"jwks_uri":               p.srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []*oidcJSONWebKey{{
+				KeyID:   "test",
+				KeyType: "EC",
+				Curve:   "P-256",
+				X:       base64.RawURLEncoding.EncodeToString(padCoordinate(key.X)),
+				Y:       base64.RawURLEncoding.EncodeToString(padCoordinate(key.Y)),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, _, _ := r.BasicAuth()
+		if r.FormValue("code") != "valid-code" || id != "nomad" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.sign(t, p.idTokenClaims())})
+	})
+	p.srv = httptest.NewServer(mux)
+	return p
+}
+
+// idTokenClaims returns the claims of a valid ID token.
+func (p *testOIDCProvider) idTokenClaims() map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss":   p.srv.URL,
+		"aud":   "nomad",
+		"sub":   "alice",
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"nonce": p.nonce,
+	}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	return claims
+}
+
+// sign returns the claims as an ES256 signed JWT.
+func (p *testOIDCProvider) sign(t *testing.T, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "kid": "test"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, p.key, digest[:])
+	require.NoError(t, err)
+
+	sig := append(padCoordinate(r), padCoordinate(s)...)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (p *testOIDCProvider) config() *structs.ACLAuthMethodConfig {
+	return &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    p.srv.URL,
+		OIDCClientID:        "nomad",
+		OIDCClientSecret:    "secret",
+		AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		Policies:            []string{"engineering"},
+	}
+}
+
+func TestOIDC_AuthURL(t *testing.T) {
+	t.Parallel()
+	p := newTestOIDCProvider(t)
+	defer p.srv.Close()
+
+	config := p.config()
+	config.OIDCScopes = []string{"email", "groups"}
+	provider, err := oidcDiscover(config)
+	require.NoError(t, err)
+
+	u, err := url.Parse(oidcAuthURL(config, provider, "http://localhost:4649/oidc/callback", "state1", "nonce1"))
+	require.NoError(t, err)
+	require.Equal(t, "/auth", u.Path)
+	q := u.Query()
+	require.Equal(t, "code", q.Get("response_type"))
+	require.Equal(t, "nomad", q.Get("client_id"))
+	require.Equal(t, "openid email groups", q.Get("scope"))
+	require.Equal(t, "state1", q.Get("state"))
+	require.Equal(t, "nonce1", q.Get("nonce"))
+}
+
+func TestOIDC_ExchangeAndVerify(t *testing.T) {
+	t.Parallel()
+	p := newTestOIDCProvider(t)
+	defer p.srv.Close()
+	p.nonce = "nonce1"
+
+	config := p.config()
+	provider, err := oidcDiscover(config)
+	require.NoError(t, err)
+
+	// An invalid code is rejected
+	_, err = oidcExchangeCode(config, provider, "http://localhost:4649/oidc/callback", "bad-code")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid_grant")
+
+	idToken, err := oidcExchangeCode(config, provider, "http://localhost:4649/oidc/callback", "valid-code")
+	require.NoError(t, err)
+
+	claims, err := oidcVerifyIDToken(config, provider, idToken, "nonce1", time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "alice", claims["sub"])
+
+	// The nonce must match
+	_, err = oidcVerifyIDToken(config, provider, idToken, "nonce2", time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "nonce")
+
+	// Expired tokens are rejected
+	_, err = oidcVerifyIDToken(config, provider, idToken, "nonce1", time.Now().Add(time.Hour))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expired")
+
+	// The audience must be bound
+	config.BoundAudiences = []string{"other"}
+	_, err = oidcVerifyIDToken(config, provider, idToken, "nonce1", time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "audience")
+	config.BoundAudiences = nil
+
+	// Tampered claims fail the signature check
+	parts := strings.Split(idToken, ".")
+	claims["sub"] = "mallory"
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	_, err = oidcVerifyIDToken(config, provider, strings.Join(parts, "."), "nonce1", time.Now())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "signature")
+}
+
+func TestOIDC_VerifyJWTSignature_RS256(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256([]byte("header.payload"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	jwk := &oidcJSONWebKey{
+		KeyID:   "rsa",
+		KeyType: "RSA",
+		N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:       base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1}),
+	}
+	require.NoError(t, verifyJWTSignature("RS256", jwk, digest[:], sig))
+
+	other := sha256.Sum256([]byte("header.other"))
+	require.Error(t, verifyJWTSignature("RS256", jwk, other[:], sig))
+	require.Error(t, verifyJWTSignature("HS256", jwk, digest[:], sig))
+}
+
+func TestAuthMethod_Policies(t *testing.T) {
+	t.Parallel()
+	config := &structs.ACLAuthMethodConfig{
+		BoundClaims:   map[string]string{"team": "engineering"},
+		Policies:      []string{"engineering"},
+		GroupsClaim:   "groups",
+		GroupPolicies: map[string][]string{"admins": {"admin", "engineering"}},
+	}
+
+	// Bound claims must match
+	_, err := authMethodPolicies(config, map[string]interface{}{"team": "sales"})
+	require.Error(t, err)
+	_, err = authMethodPolicies(config, map[string]interface{}{})
+	require.Error(t, err)
+
+	policies, err := authMethodPolicies(config, map[string]interface{}{"team": "engineering"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"engineering"}, policies)
+
+	// Bound claims match list claims and groups add policies
+	policies, err = authMethodPolicies(config, map[string]interface{}{
+		"team":   []interface{}{"support", "engineering"},
+		"groups": []interface{}{"admins", "users"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"admin", "engineering"}, policies)
+
+	// Without policies of the auth method, binding rules may still grant
+	// some
+	config.Policies = nil
+	policies, err = authMethodPolicies(config, map[string]interface{}{"team": "engineering"})
+	require.NoError(t, err)
+	require.Empty(t, policies)
+}
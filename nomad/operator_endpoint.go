@@ -3,6 +3,7 @@ package nomad
 import (
 	"fmt"
 	"net"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 
@@ -40,20 +41,33 @@ func (op *Operator) RaftGetConfiguration(args *structs.GenericRequest, reply *st
 
 	// Index the Nomad information about the servers.
 	serverMap := make(map[raft.ServerAddress]serf.Member)
+	partsMap := make(map[raft.ServerAddress]*serverParts)
 	for _, member := range op.srv.serf.Members() {
 		valid, parts := isNomadServer(member)
 		if !valid {
 			continue
 		}
 
-		addr := (&net.TCPAddr{IP: member.Addr, Port: parts.Port}).String()
-		serverMap[raft.ServerAddress(addr)] = member
+		addr := raft.ServerAddress((&net.TCPAddr{IP: member.Addr, Port: parts.Port}).String())
+		serverMap[addr] = member
+		partsMap[addr] = parts
 	}
 
+	// Fetch the replication and log store stats of the servers
+	servers := future.Configuration().Servers
+	stats := op.srv.fetchRaftServerStats(servers, partsMap)
+
 	// Fill out the reply.
 	leader := op.srv.raft.Leader()
+	var leaderIndex uint64
+	for _, server := range servers {
+		if s := stats[server.ID]; server.Address == leader && s.stats != nil {
+			leaderIndex = s.stats.LastIndex
+		}
+	}
+
 	reply.Index = future.Index()
-	for _, server := range future.Configuration().Servers {
+	for _, server := range servers {
 		node := "(unknown)"
 		raftProtocolVersion := "unknown"
 		if member, ok := serverMap[server.Address]; ok {
@@ -71,6 +85,14 @@ func (op *Operator) RaftGetConfiguration(args *structs.GenericRequest, reply *st
 			Voter:        server.Suffrage == raft.Voter,
 			RaftProtocol: raftProtocolVersion,
 		}
+		if s := stats[server.ID]; s.err != nil {
+			entry.StatsError = s.err.Error()
+		} else {
+			entry.Stats = s.stats
+			if leaderIndex > s.stats.LastIndex {
+				entry.Lag = leaderIndex - s.stats.LastIndex
+			}
+		}
 		reply.Servers = append(reply.Servers, entry)
 	}
 	return nil
@@ -355,3 +377,120 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 
 	return nil
 }
+
+// EvalBrokerStatus is used to retrieve a detailed view of the evaluation
+// broker's state. The broker only runs on the leader.
+func (op *Operator) EvalBrokerStatus(args *structs.GenericRequest, reply *structs.EvalBrokerStatusResponse) error {
+	if done, err := op.srv.forward("Operator.EvalBrokerStatus", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Status = op.srv.evalBroker.Status()
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// PlanRejections is used to retrieve the plan rejections of the nodes whose
+// placements were rejected by the plan applier, which only runs on the leader.
+func (op *Operator) PlanRejections(args *structs.GenericRequest, reply *structs.PlanRejectionsResponse) error {
+	if done, err := op.srv.forward("Operator.PlanRejections", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	var config structs.PlanRejectionTrackerConfig
+	_, schedConfig, err := op.srv.fsm.State().SchedulerConfig()
+	if err != nil {
+		return err
+	} else if schedConfig != nil {
+		config = schedConfig.PlanRejectionTracker
+	}
+
+	reply.Nodes = op.srv.planRejections.Status(&config)
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// ReplicationStatus is used to retrieve the status of replication from the
+// authoritative region. Replication only runs on the leader.
+func (op *Operator) ReplicationStatus(args *structs.GenericRequest, reply *structs.ReplicationStatusResponse) error {
+	if done, err := op.srv.forward("Operator.ReplicationStatus", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Status = op.srv.replicationStatus()
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
+// UsageExport is used to export the inventory of the jobs, allocations and
+// nodes of the cluster along with the resources allocated to them within a
+// time window.
+func (op *Operator) UsageExport(args *structs.UsageExportRequest, reply *structs.UsageExportResponse) error {
+	if done, err := op.srv.forward("Operator.UsageExport", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	start, end, err := usageWindow(args, time.Now())
+	if err != nil {
+		return err
+	}
+
+	snap, err := op.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	reply.Usage, err = usageReport(snap, start, end)
+	if err != nil {
+		return err
+	}
+
+	// Use the last index that affected the reported tables
+	index, err := snap.Index("allocs")
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{"jobs", "nodes"} {
+		tableIndex, err := snap.Index(table)
+		if err != nil {
+			return err
+		}
+		if tableIndex > index {
+			index = tableIndex
+		}
+	}
+	reply.Index = index
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul/lib/freeport"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
@@ -42,6 +43,9 @@ func TestOperator_RaftGetConfiguration(t *testing.T) {
 		t.Fatalf("bad: %v", future.Configuration().Servers)
 	}
 	me := future.Configuration().Servers[0]
+	if len(reply.Servers) != 1 || reply.Servers[0].Stats == nil {
+		t.Fatalf("bad: %v", reply.Servers)
+	}
 	expected := structs.RaftConfigurationResponse{
 		Servers: []*structs.RaftServer{
 			{
@@ -51,6 +55,7 @@ func TestOperator_RaftGetConfiguration(t *testing.T) {
 				Leader:       true,
 				Voter:        true,
 				RaftProtocol: fmt.Sprintf("%d", s1.config.RaftConfig.ProtocolVersion),
+				Stats:        reply.Servers[0].Stats,
 			},
 		},
 		Index: future.Index(),
@@ -106,6 +111,8 @@ func TestOperator_RaftGetConfiguration_ACL(t *testing.T) {
 		assert.Len(future.Configuration().Servers, 1)
 
 		me := future.Configuration().Servers[0]
+		assert.Len(reply.Servers, 1)
+		assert.NotNil(reply.Servers[0].Stats)
 		expected := structs.RaftConfigurationResponse{
 			Servers: []*structs.RaftServer{
 				{
@@ -115,6 +122,7 @@ func TestOperator_RaftGetConfiguration_ACL(t *testing.T) {
 					Leader:       true,
 					Voter:        true,
 					RaftProtocol: fmt.Sprintf("%d", s1.config.RaftConfig.ProtocolVersion),
+					Stats:        reply.Servers[0].Stats,
 				},
 			},
 			Index: future.Index(),
@@ -123,6 +131,89 @@ func TestOperator_RaftGetConfiguration_ACL(t *testing.T) {
 	}
 }
 
+func TestOperator_RaftGetConfiguration_Stats(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.DevDisableBootstrap = true
+	})
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 2
+		c.DevDisableBootstrap = true
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	testutil.WaitForResult(func() (bool, error) {
+		var reply structs.RaftConfigurationResponse
+		arg := structs.GenericRequest{
+			QueryOptions: structs.QueryOptions{
+				Region: s1.config.Region,
+			},
+		}
+		if err := s1.RPC("Operator.RaftGetConfiguration", &arg, &reply); err != nil {
+			return false, err
+		}
+		if len(reply.Servers) != 2 {
+			return false, fmt.Errorf("got %d servers", len(reply.Servers))
+		}
+
+		// The stats of the follower are fetched from it
+		for _, server := range reply.Servers {
+			if server.Stats == nil {
+				return false, fmt.Errorf("no stats for %s: %s", server.Node, server.StatsError)
+			}
+			if server.Stats.LastIndex == 0 || server.Stats.LogStoreEntries == 0 {
+				return false, fmt.Errorf("empty stats for %s: %#v", server.Node, server.Stats)
+			}
+			if server.Leader && server.Stats.LastContact != 0 {
+				return false, fmt.Errorf("leader last contact: %v", server.Stats.LastContact)
+			}
+			if !server.Leader && server.Stats.LastContact < 0 {
+				return false, fmt.Errorf("follower never contacted")
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(err)
+	})
+}
+
+func TestServer_raftServerStats(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.RaftConfig.TrailingLogs = 1
+		c.RaftConfig.SnapshotThreshold = 1
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Apply enough entries for the log store to warrant a snapshot
+	for i := 0; i < 5; i++ {
+		node := mock.Node()
+		req := &structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		_, _, err := s1.raftApply(structs.NodeRegisterRequestType, req)
+		require.NoError(err)
+	}
+
+	stats, err := s1.raftServerStats()
+	require.NoError(err)
+	require.Zero(stats.LastContact)
+	require.NotZero(stats.LastIndex)
+	require.True(stats.LogStoreEntries > 3)
+	require.Len(stats.Warnings, 1)
+	require.Contains(stats.Warnings[0], "check that snapshots succeed")
+}
+
 func TestOperator_RaftRemovePeerByAddress(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -494,3 +585,274 @@ func TestOperator_SchedulerSetConfiguration_ACL(t *testing.T) {
 	}
 
 }
+
+func TestOperator_EvalBrokerStatus(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	arg := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	var reply structs.EvalBrokerStatusResponse
+	require := require.New(t)
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStatus", &arg, &reply))
+	require.NotNil(reply.Status)
+	require.True(reply.Status.Enabled)
+}
+
+func TestOperator_EvalBrokerStatus_ACL(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create ACL tokens
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+	validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid", mock.OperatorPolicy(acl.PolicyRead))
+
+	arg := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	require := require.New(t)
+	var reply structs.EvalBrokerStatusResponse
+
+	// Try with no token and expect permission denied
+	{
+		err := msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStatus", &arg, &reply)
+		require.NotNil(err)
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an invalid token and expect permission denied
+	{
+		arg.AuthToken = invalidToken.SecretID
+		err := msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStatus", &arg, &reply)
+		require.NotNil(err)
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an operator read token
+	{
+		arg.AuthToken = validToken.SecretID
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStatus", &arg, &reply))
+	}
+
+	// Try with root token, should succeed
+	{
+		arg.AuthToken = root.SecretID
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.EvalBrokerStatus", &arg, &reply))
+	}
+}
+
+func TestOperator_PlanRejections(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	s1.planRejections.add("node", "memory", "eval", "job", time.Now(), &structs.PlanRejectionTrackerConfig{})
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+	validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid", mock.OperatorPolicy(acl.PolicyRead))
+
+	arg := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	require := require.New(t)
+	var reply structs.PlanRejectionsResponse
+
+	// Try with an invalid token and expect permission denied
+	arg.AuthToken = invalidToken.SecretID
+	err := msgpackrpc.CallWithCodec(codec, "Operator.PlanRejections", &arg, &reply)
+	require.NotNil(err)
+	require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Try with an operator read token
+	arg.AuthToken = validToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.PlanRejections", &arg, &reply))
+	require.Len(reply.Nodes, 1)
+	require.Equal("node", reply.Nodes[0].NodeID)
+	require.Equal(1, reply.Nodes[0].Rejections)
+	require.Equal("memory", reply.Nodes[0].LastReason)
+
+	// Try with root token, should succeed
+	arg.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.PlanRejections", &arg, &reply))
+}
+
+func TestOperator_ReplicationStatus(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+	codec := rpcClient(t, s2)
+
+	// Write a policy to the authoritative region
+	p1 := mock.ACLPolicy()
+	require := require.New(t)
+	require.NoError(s1.State().UpsertACLPolicies(100, []*structs.ACLPolicy{p1}))
+
+	arg := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "region2",
+			AuthToken: root.SecretID,
+		},
+	}
+
+	// Wait for the policy to replicate and be reported
+	testutil.WaitForResult(func() (bool, error) {
+		var reply structs.ReplicationStatusResponse
+		if err := msgpackrpc.CallWithCodec(codec, "Operator.ReplicationStatus", &arg, &reply); err != nil {
+			return false, err
+		}
+		status := reply.Status
+		if !status.Enabled || status.SourceRegion != "region1" || status.SourceError != "" {
+			return false, fmt.Errorf("bad status: %#v", status)
+		}
+		if !status.ACLPolicies.Running || !status.ACLTokens.Running {
+			return false, fmt.Errorf("replication not running: %#v", status)
+		}
+		if status.ACLPolicies.ReplicatedIndex != 100 || status.ACLPolicies.SourceIndex != 100 {
+			return false, fmt.Errorf("bad policy indexes: %#v", status.ACLPolicies)
+		}
+		if status.ACLPolicies.LastSuccess.IsZero() {
+			return false, fmt.Errorf("no successful replication: %#v", status.ACLPolicies)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The authoritative region does not replicate
+	arg.Region = "region1"
+	var reply structs.ReplicationStatusResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.ReplicationStatus", &arg, &reply))
+	require.False(reply.Status.Enabled)
+	require.False(reply.Status.ACLPolicies.Running)
+}
+
+func TestOperator_ReplicationStatus_ACL(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Create ACL tokens
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+	validToken := mock.CreatePolicyAndToken(t, state, 1003, "test-valid", mock.OperatorPolicy(acl.PolicyRead))
+
+	arg := structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	require := require.New(t)
+	var reply structs.ReplicationStatusResponse
+
+	// Try with no token and expect permission denied
+	{
+		err := msgpackrpc.CallWithCodec(codec, "Operator.ReplicationStatus", &arg, &reply)
+		require.NotNil(err)
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an invalid token and expect permission denied
+	{
+		arg.AuthToken = invalidToken.SecretID
+		err := msgpackrpc.CallWithCodec(codec, "Operator.ReplicationStatus", &arg, &reply)
+		require.NotNil(err)
+		require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+	}
+
+	// Try with an operator read token
+	{
+		arg.AuthToken = validToken.SecretID
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.ReplicationStatus", &arg, &reply))
+		require.False(reply.Status.Enabled)
+	}
+
+	// Try with root token, should succeed
+	{
+		arg.AuthToken = root.SecretID
+		require.Nil(msgpackrpc.CallWithCodec(codec, "Operator.ReplicationStatus", &arg, &reply))
+	}
+}
+
+func TestOperator_UsageExport(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+	require := require.New(t)
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.CreateTime = time.Now().Add(-time.Hour).UnixNano()
+	require.NoError(state.UpsertJob(1001, alloc.Job))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid", mock.OperatorPolicy(acl.PolicyRead))
+
+	arg := structs.UsageExportRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+	var reply structs.UsageExportResponse
+
+	// Try with an invalid token and expect permission denied
+	arg.AuthToken = invalidToken.SecretID
+	err := msgpackrpc.CallWithCodec(codec, "Operator.UsageExport", &arg, &reply)
+	require.NotNil(err)
+	require.Equal(err.Error(), structs.ErrPermissionDenied.Error())
+
+	// Try with an operator read token
+	arg.AuthToken = validToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.UsageExport", &arg, &reply))
+	require.Equal(uint64(1002), reply.Index)
+	require.Len(reply.Usage.Nodes, 1)
+	require.Len(reply.Usage.Jobs, 1)
+	require.Len(reply.Usage.Allocations, 1)
+	require.Equal(alloc.ID, reply.Usage.Allocations[0].ID)
+	require.Equal(1, reply.Usage.Nodes[0].Allocations)
+
+	// An inverted window is rejected
+	arg.AuthToken = root.SecretID
+	arg.Start = time.Now().UnixNano()
+	arg.End = time.Now().Add(-time.Hour).UnixNano()
+	require.Error(msgpackrpc.CallWithCodec(codec, "Operator.UsageExport", &arg, &reply))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 )
 
@@ -24,6 +25,10 @@ type planner struct {
 	// planQueue is used to manage the submitted allocation
 	// plans that are waiting to be assessed by the leader
 	planQueue *PlanQueue
+
+	// planRejections tracks the nodes whose placements are rejected to
+	// quarantine those that are repeatedly rejected
+	planRejections *planRejectionTracker
 }
 
 // newPlanner returns a new planner to be used for managing allocation plans.
@@ -35,9 +40,10 @@ func newPlanner(s *Server) (*planner, error) {
 	}
 
 	return &planner{
-		Server:    s,
-		log:       s.logger.Named("planner"),
-		planQueue: planQueue,
+		Server:         s,
+		log:            s.logger.Named("planner"),
+		planQueue:      planQueue,
+		planRejections: newPlanRejectionTracker(s.logger),
 	}, nil
 }
 
@@ -115,6 +121,11 @@ func (p *planner) planApply() {
 			continue
 		}
 
+		// Track the nodes whose placements were rejected
+		if len(result.RejectedNodes) != 0 {
+			p.planRejections.Add(snap, pending.plan, result.RejectedNodes)
+		}
+
 		// Fast-path the response if there is nothing to do
 		if result.IsNoOp() {
 			pending.respond(result, nil)
@@ -281,9 +292,60 @@ func evaluatePlan(pool *EvaluatePool, snap *state.StateSnapshot, plan *structs.P
 		return &structs.PlanResult{RefreshIndex: index}, nil
 	}
 
+	// Reject the plan and force the scheduler to refresh if another job of the
+	// concurrency group started running allocations since the scheduler's
+	// snapshot
+	held, err := evaluatePlanConcurrency(snap, plan)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		index, err := refreshIndex(snap)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Debug("plan for evaluation places allocations in a held concurrency group. Forcing state refresh", "eval_id", plan.EvalID, "refresh_index", index)
+		return &structs.PlanResult{RefreshIndex: index}, nil
+	}
+
 	return evaluatePlanPlacements(pool, snap, plan, logger)
 }
 
+// evaluatePlanConcurrency returns whether the plan places new allocations while
+// another job holds the concurrency group of the plan's job.
+func evaluatePlanConcurrency(snap *state.StateSnapshot, plan *structs.Plan) (bool, error) {
+	if plan.Job == nil || plan.Job.ConcurrencyGroup == "" {
+		return false, nil
+	}
+
+	placing := false
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			existing, err := snap.AllocByID(nil, alloc.ID)
+			if err != nil {
+				return false, err
+			}
+			if existing == nil {
+				placing = true
+				break
+			}
+		}
+		if placing {
+			break
+		}
+	}
+	if !placing {
+		return false, nil
+	}
+
+	holder, err := scheduler.ConcurrencyGroupHolder(snap, plan.Job)
+	if err != nil {
+		return false, err
+	}
+	return holder != "", nil
+}
+
 // evaluatePlanPlacements is used to determine what portions of a plan can be
 // applied if any, looking for node over commitment. Returns if there should be
 // a plan application which may be partial or if there was an error
@@ -330,6 +392,14 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 			if reason != "" {
 				logger.Debug("plan for node rejected", "node_id", nodeID, "reason", reason)
 			}
+
+			// Record the rejection so that nodes that are repeatedly rejected
+			// can be tracked
+			if result.RejectedNodes == nil {
+				result.RejectedNodes = make(map[string]string)
+			}
+			result.RejectedNodes[nodeID] = reason
+
 			// Set that this is a partial commit
 			partialCommit = true
 
@@ -497,6 +567,9 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	// the Raft commit happens.
 	if node == nil {
 		return false, "node does not exist", nil
+	} else if node.Status == structs.NodeStatusDown && isValidForDownNode(plan, nodeID) {
+		// Allocations of down nodes can only be marked as unknown
+		return true, "", nil
 	} else if node.Status != structs.NodeStatusReady {
 		return false, "node is not ready for placements", nil
 	} else if node.SchedulingEligibility == structs.NodeSchedulingIneligible {
@@ -538,3 +611,14 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	fit, reason, _, err := structs.AllocsFit(node, proposed, nil, true)
 	return fit, reason, err
 }
+
+// isValidForDownNode returns whether the plan only marks allocations of the
+// down node as unknown, which is the only update allowed on a down node.
+func isValidForDownNode(plan *structs.Plan, nodeID string) bool {
+	for _, alloc := range plan.NodeAllocation[nodeID] {
+		if alloc.ClientStatus != structs.AllocClientStatusUnknown {
+			return false
+		}
+	}
+	return true
+}
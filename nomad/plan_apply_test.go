@@ -272,6 +272,56 @@ func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_ConcurrencyGroup(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	// Another job of the group started running an alloc
+	running := mock.Job()
+	running.ConcurrencyGroup = "migrations"
+	require.NoError(state.UpsertJob(1001, running))
+	runningAlloc := mock.Alloc()
+	runningAlloc.Job = running
+	runningAlloc.JobID = running.ID
+	runningAlloc.NodeID = node.ID
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{runningAlloc}))
+
+	alloc := mock.Alloc()
+	alloc.Job.ConcurrencyGroup = "migrations"
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	// The placement is rejected and the scheduler refreshes its state
+	snap, err := state.Snapshot()
+	require.NoError(err)
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(err)
+	require.EqualValues(1002, result.RefreshIndex)
+	require.Empty(result.NodeAllocation)
+
+	// The placement is accepted once the other job's alloc is terminal
+	runningAlloc = runningAlloc.Copy()
+	runningAlloc.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1003, []*structs.Allocation{runningAlloc}))
+
+	snap, err = state.Snapshot()
+	require.NoError(err)
+	result, err = evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(err)
+	require.Zero(result.RefreshIndex)
+	require.Equal(plan.NodeAllocation, result.NodeAllocation)
+}
+
 func TestPlanApply_EvalPlan_Preemption(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -442,6 +492,12 @@ func TestPlanApply_EvalPlan_Partial(t *testing.T) {
 	if result.RefreshIndex != 1001 {
 		t.Fatalf("bad: %d", result.RefreshIndex)
 	}
+
+	// Check the rejection was recorded with the resource that conflicted
+	expected := map[string]string{node2.ID: "cpu"}
+	if !reflect.DeepEqual(result.RejectedNodes, expected) {
+		t.Fatalf("bad: %v", result.RejectedNodes)
+	}
 }
 
 func TestPlanApply_EvalPlan_Partial_AllAtOnce(t *testing.T) {
@@ -550,6 +606,39 @@ func TestPlanApply_EvalNodePlan_NodeNotReady(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalNodePlan_NodeDown_Unknown(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	node.Status = structs.NodeStatusDown
+	require.NoError(state.UpsertNode(1000, node))
+	snap, _ := state.Snapshot()
+
+	// Allocations of down nodes can be marked as unknown
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusUnknown
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	fit, reason, err := evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(err)
+	require.True(fit)
+	require.Empty(reason)
+
+	// But nothing else can be placed on them
+	plan.NodeAllocation[node.ID] = append(plan.NodeAllocation[node.ID], mock.Alloc())
+	fit, reason, err = evaluateNodePlan(snap, plan, node.ID)
+	require.NoError(err)
+	require.False(fit)
+	require.Equal("node is not ready for placements", reason)
+}
+
 func TestPlanApply_EvalNodePlan_NodeDrain(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
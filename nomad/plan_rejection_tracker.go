@@ -0,0 +1,238 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// planRejectionUpdateBuffer is the number of node events and quarantines
+	// buffered before new ones are dropped.
+	planRejectionUpdateBuffer = 64
+)
+
+// planRejectionTracker tracks the nodes whose placements are rejected by the
+// plan applier. A node whose placements keep being rejected usually has
+// resources that are out of sync with the schedulers' view of it, so the
+// schedulers keep picking it. The first rejection of a node within the window
+// is recorded as a node event, and if enabled by the scheduler configuration,
+// nodes that reach the rejection threshold are marked as ineligible for
+// scheduling.
+type planRejectionTracker struct {
+	logger log.Logger
+
+	nodes map[string]*nodePlanRejections
+	l     sync.Mutex
+
+	// updateCh is used to hand the node events and quarantines to the
+	// leader, which applies them through Raft outside of the plan applier.
+	updateCh chan *planRejectionUpdate
+}
+
+// nodePlanRejections are the rejections of a single node.
+type nodePlanRejections struct {
+	// times are the times of the rejections within the window
+	times  []time.Time
+	status *structs.NodePlanRejections
+}
+
+// planRejectionUpdate is a node event or a quarantine to apply to a node.
+type planRejectionUpdate struct {
+	nodeID     string
+	event      *structs.NodeEvent
+	quarantine bool
+}
+
+// newPlanRejectionTracker returns a new plan rejection tracker.
+func newPlanRejectionTracker(logger log.Logger) *planRejectionTracker {
+	return &planRejectionTracker{
+		logger:   logger.Named("plan_rejections"),
+		nodes:    make(map[string]*nodePlanRejections),
+		updateCh: make(chan *planRejectionUpdate, planRejectionUpdateBuffer),
+	}
+}
+
+// Reset clears the tracked rejections. It is called when the server becomes
+// the leader since the rejections of the previous leader aren't known.
+func (t *planRejectionTracker) Reset() {
+	t.l.Lock()
+	defer t.l.Unlock()
+	t.nodes = make(map[string]*nodePlanRejections)
+}
+
+// Add records the rejection of the placements of the plan on the given nodes,
+// which map to the reason of their rejection. Only rejections of nodes that
+// are ready and eligible are tracked since the others are rejected because
+// the schedulers haven't seen their status change yet.
+func (t *planRejectionTracker) Add(snap *state.StateSnapshot, plan *structs.Plan, rejected map[string]string) {
+	var config structs.PlanRejectionTrackerConfig
+	if _, schedConfig, err := snap.SchedulerConfig(); err != nil {
+		t.logger.Error("failed to get scheduler configuration", "error", err)
+	} else if schedConfig != nil {
+		config = schedConfig.PlanRejectionTracker
+	}
+
+	var jobID string
+	if plan.Job != nil {
+		jobID = plan.Job.ID
+	}
+
+	now := time.Now().UTC()
+	for nodeID, reason := range rejected {
+		node, err := snap.NodeByID(nil, nodeID)
+		if err != nil {
+			t.logger.Error("failed to lookup node", "node_id", nodeID, "error", err)
+			continue
+		}
+		if node == nil || node.Status != structs.NodeStatusReady ||
+			node.SchedulingEligibility != structs.NodeSchedulingEligible {
+			continue
+		}
+
+		metrics.IncrCounterWithLabels([]string{"nomad", "plan", "node_rejected"}, 1,
+			[]metrics.Label{{Name: "node_id", Value: nodeID}})
+
+		t.add(nodeID, reason, plan.EvalID, jobID, now, &config)
+	}
+}
+
+// add records a single rejection of a node and emits its node event or
+// quarantine if any.
+func (t *planRejectionTracker) add(nodeID, reason, evalID, jobID string, now time.Time, config *structs.PlanRejectionTrackerConfig) {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	n, ok := t.nodes[nodeID]
+	if !ok {
+		n = &nodePlanRejections{
+			status: &structs.NodePlanRejections{NodeID: nodeID},
+		}
+		t.nodes[nodeID] = n
+	}
+
+	n.prune(now.Add(-config.Window()))
+	n.times = append(n.times, now)
+	n.status.Total++
+	n.status.LastReason = reason
+	n.status.LastEvalID = evalID
+	n.status.LastJobID = jobID
+	n.status.LastRejection = now
+
+	event := structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemScheduler).
+		SetTimestamp(now).
+		AddDetail("reason", reason).
+		AddDetail("eval_id", evalID).
+		AddDetail("job_id", jobID).
+		AddDetail("rejections", fmt.Sprintf("%d", len(n.times)))
+
+	// Quarantine the node once it reaches the threshold
+	if config.Enabled && len(n.times) >= config.Threshold() {
+		event.SetMessage(fmt.Sprintf("Node marked as ineligible for scheduling after %d plan rejections within %s",
+			len(n.times), config.Window()))
+		t.send(&planRejectionUpdate{
+			nodeID:     nodeID,
+			event:      event,
+			quarantine: true,
+		})
+		n.times = nil
+		n.status.Quarantined = true
+		return
+	}
+
+	// Record the first rejection within the window as a node event, later
+	// ones are only counted to not flood the node events
+	if len(n.times) == 1 {
+		event.SetMessage("Placements rejected by the plan applier")
+		t.send(&planRejectionUpdate{
+			nodeID: nodeID,
+			event:  event,
+		})
+	}
+}
+
+// send hands the update to the leader, dropping it if the leader is behind.
+func (t *planRejectionTracker) send(update *planRejectionUpdate) {
+	select {
+	case t.updateCh <- update:
+	default:
+		t.logger.Warn("dropping plan rejection update", "node_id", update.nodeID, "quarantine", update.quarantine)
+	}
+}
+
+// prune drops the rejections that happened before the cutoff.
+func (n *nodePlanRejections) prune(cutoff time.Time) {
+	i := 0
+	for ; i < len(n.times); i++ {
+		if n.times[i].After(cutoff) {
+			break
+		}
+	}
+	n.times = n.times[i:]
+}
+
+// Status returns the rejections of the tracked nodes, sorted by node ID.
+func (t *planRejectionTracker) Status(config *structs.PlanRejectionTrackerConfig) []*structs.NodePlanRejections {
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	cutoff := time.Now().UTC().Add(-config.Window())
+	out := make([]*structs.NodePlanRejections, 0, len(t.nodes))
+	for _, n := range t.nodes {
+		n.prune(cutoff)
+		status := *n.status
+		status.Rejections = len(n.times)
+		out = append(out, &status)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].NodeID < out[j].NodeID })
+	return out
+}
+
+// applyPlanRejectionUpdates applies the node events and quarantines of the
+// plan rejection tracker until leadership is lost.
+func (s *Server) applyPlanRejectionUpdates(stopCh chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case update := <-s.planRejections.updateCh:
+			var err error
+			if update.quarantine {
+				req := structs.NodeUpdateEligibilityRequest{
+					NodeID:      update.nodeID,
+					Eligibility: structs.NodeSchedulingIneligible,
+					NodeEvent:   update.event,
+					WriteRequest: structs.WriteRequest{
+						Region: s.config.Region,
+					},
+				}
+				_, _, err = s.raftApply(structs.NodeUpdateEligibilityRequestType, &req)
+			} else {
+				req := structs.EmitNodeEventsRequest{
+					NodeEvents: map[string][]*structs.NodeEvent{
+						update.nodeID: {update.event},
+					},
+					WriteRequest: structs.WriteRequest{
+						Region: s.config.Region,
+					},
+				}
+				_, _, err = s.raftApply(structs.UpsertNodeEventsType, &req)
+			}
+
+			if err != nil {
+				s.logger.Error("failed to apply plan rejection update", "node_id", update.nodeID,
+					"quarantine", update.quarantine, "error", err)
+			} else if update.quarantine {
+				s.logger.Warn("node marked as ineligible after repeated plan rejections", "node_id", update.nodeID)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRejectionTracker(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+	down := mock.Node()
+	down.Status = structs.NodeStatusDown
+	require.NoError(state.UpsertNode(1001, down))
+	require.NoError(state.SchedulerSetConfig(1002, &structs.SchedulerConfiguration{
+		PlanRejectionTracker: structs.PlanRejectionTrackerConfig{
+			Enabled:       true,
+			NodeThreshold: 3,
+			NodeWindow:    time.Minute,
+		},
+	}))
+	snap, err := state.Snapshot()
+	require.NoError(err)
+
+	tracker := newPlanRejectionTracker(testlog.HCLogger(t))
+	plan := &structs.Plan{
+		EvalID: "eval",
+		Job:    mock.Job(),
+	}
+	rejected := map[string]string{
+		node.ID: "memory",
+		down.ID: "node is not ready for placements",
+	}
+
+	// The first rejection is recorded as a node event, and rejections of
+	// nodes that aren't ready are ignored
+	tracker.Add(snap, plan, rejected)
+	require.Len(tracker.updateCh, 1)
+	update := <-tracker.updateCh
+	require.Equal(node.ID, update.nodeID)
+	require.False(update.quarantine)
+	require.Equal(structs.NodeEventSubsystemScheduler, update.event.Subsystem)
+	require.Equal("memory", update.event.Details["reason"])
+	require.Equal(plan.Job.ID, update.event.Details["job_id"])
+
+	// The node is quarantined once it reaches the threshold
+	tracker.Add(snap, plan, rejected)
+	require.Empty(tracker.updateCh)
+	tracker.Add(snap, plan, rejected)
+	require.Len(tracker.updateCh, 1)
+	update = <-tracker.updateCh
+	require.Equal(node.ID, update.nodeID)
+	require.True(update.quarantine)
+	require.Equal("3", update.event.Details["rejections"])
+
+	status := tracker.Status(&structs.PlanRejectionTrackerConfig{NodeWindow: time.Minute})
+	require.Len(status, 1)
+	require.Equal(node.ID, status[0].NodeID)
+	require.Equal(0, status[0].Rejections)
+	require.EqualValues(3, status[0].Total)
+	require.Equal("memory", status[0].LastReason)
+	require.Equal("eval", status[0].LastEvalID)
+	require.True(status[0].Quarantined)
+
+	// Rejections out of the window are dropped
+	tracker.add(node.ID, "cpu", "eval", "job", time.Now().Add(-2*time.Minute), &structs.PlanRejectionTrackerConfig{})
+	status = tracker.Status(&structs.PlanRejectionTrackerConfig{NodeWindow: time.Minute})
+	require.Equal(0, status[0].Rejections)
+	require.EqualValues(4, status[0].Total)
+
+	tracker.Reset()
+	require.Empty(tracker.Status(&structs.PlanRejectionTrackerConfig{}))
+}
+
+func TestPlanRejectionTracker_Quarantine(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(1000, node))
+	require.NoError(t, state.SchedulerSetConfig(1001, &structs.SchedulerConfiguration{
+		PlanRejectionTracker: structs.PlanRejectionTrackerConfig{
+			Enabled:       true,
+			NodeThreshold: 1,
+		},
+	}))
+	snap, err := state.Snapshot()
+	require.NoError(t, err)
+
+	s1.planRejections.Add(snap, &structs.Plan{EvalID: "eval"}, map[string]string{node.ID: "cpu"})
+
+	// The leader marks the node as ineligible with an event
+	testutil.WaitForResult(func() (bool, error) {
+		out, err := state.NodeByID(nil, node.ID)
+		if err != nil {
+			return false, err
+		}
+		if out.SchedulingEligibility != structs.NodeSchedulingIneligible {
+			return false, nil
+		}
+		last := out.Events[len(out.Events)-1]
+		return last.Subsystem == structs.NodeEventSubsystemScheduler && last.Details["reason"] == "cpu", nil
+	}, func(err error) {
+		t.Fatalf("node not quarantined: %v", err)
+	})
+}
@@ -0,0 +1,71 @@
+package nomad
+
+import (
+	"github.com/hashicorp/consul/lib"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+// nodePrefetches returns the prefetches of the jobs with a prefetch hint whose
+// task groups the node can run. Only nodes that are ready and eligible
+// prefetch.
+func nodePrefetches(ws memdb.WatchSet, state *state.StateStore, node *structs.Node, logger log.Logger) ([]*structs.TaskPrefetch, error) {
+	if node.Status != structs.NodeStatusReady || node.Drain ||
+		node.SchedulingEligibility != structs.NodeSchedulingEligible {
+		return nil, nil
+	}
+
+	iter, err := state.Jobs(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefetches []*structs.TaskPrefetch
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+
+		// Dispatched and periodic jobs are covered by their parent
+		job := raw.(*structs.Job)
+		if job.Prefetch == nil || job.ParentID != "" || job.Stopped() ||
+			job.Status == structs.JobStatusDead {
+			continue
+		}
+		if !lib.StrContains(job.Datacenters, node.Datacenter) ||
+			!scheduler.NodeInPool(node, job.NodePool) {
+			continue
+		}
+
+		for _, tg := range job.TaskGroups {
+			tgPrefetches := job.TaskGroupPrefetches(tg)
+			if len(tgPrefetches) == 0 {
+				continue
+			}
+			if taskGroupFeasible(state, job, tg, node, logger) {
+				prefetches = append(prefetches, tgPrefetches...)
+			}
+		}
+	}
+	return prefetches, nil
+}
+
+// taskGroupFeasible returns whether the node meets the constraints and has
+// the drivers of the task group.
+func taskGroupFeasible(state scheduler.State, job *structs.Job, tg *structs.TaskGroup, node *structs.Node, logger log.Logger) bool {
+	constraints := append([]*structs.Constraint{}, job.Constraints...)
+	constraints = append(constraints, tg.Constraints...)
+	drivers := make(map[string]struct{})
+	for _, task := range tg.Tasks {
+		drivers[task.Driver] = struct{}{}
+		constraints = append(constraints, task.Constraints...)
+	}
+
+	ctx := scheduler.NewEvalContext(state, &structs.Plan{}, logger)
+	return scheduler.NewDriverChecker(ctx, drivers).Feasible(node) &&
+		scheduler.NewConstraintChecker(ctx, constraints).Feasible(node)
+}
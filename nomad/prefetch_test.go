@@ -0,0 +1,62 @@
+package nomad
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodePrefetches(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	logger := testlog.HCLogger(t)
+
+	s := state.TestStateStore(t)
+	node := mock.Node()
+	require.NoError(s.UpsertNode(1000, node))
+
+	// A job with a prefetch hint the node can run
+	job := mock.Job()
+	job.Prefetch = &structs.JobPrefetch{Images: true}
+	job.TaskGroups[0].Tasks[0].Config["image"] = "app:1.0"
+	require.NoError(s.UpsertJob(1001, job))
+
+	// A job without a prefetch hint
+	noHint := mock.Job()
+	noHint.TaskGroups[0].Tasks[0].Config["image"] = "other:1.0"
+	require.NoError(s.UpsertJob(1002, noHint))
+
+	// A job the node can't run
+	infeasible := mock.Job()
+	infeasible.Prefetch = &structs.JobPrefetch{Images: true}
+	infeasible.Constraints[0].RTarget = "windows"
+	infeasible.TaskGroups[0].Tasks[0].Config["image"] = "windows:1.0"
+	require.NoError(s.UpsertJob(1003, infeasible))
+
+	// A job in another datacenter
+	otherDC := mock.Job()
+	otherDC.Prefetch = &structs.JobPrefetch{Images: true}
+	otherDC.Datacenters = []string{"dc2"}
+	otherDC.TaskGroups[0].Tasks[0].Config["image"] = "dc2:1.0"
+	require.NoError(s.UpsertJob(1004, otherDC))
+
+	prefetches, err := nodePrefetches(memdb.NewWatchSet(), s, node, logger)
+	require.NoError(err)
+	require.Len(prefetches, 1)
+	require.Equal(job.ID, prefetches[0].JobID)
+	require.Equal("web", prefetches[0].Task)
+	require.Equal("exec", prefetches[0].Driver)
+	require.Equal("app:1.0", prefetches[0].Image)
+
+	// Ineligible nodes don't prefetch
+	node = node.Copy()
+	node.SchedulingEligibility = structs.NodeSchedulingIneligible
+	prefetches, err = nodePrefetches(memdb.NewWatchSet(), s, node, logger)
+	require.NoError(err)
+	require.Empty(prefetches)
+}
@@ -0,0 +1,154 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	// raftStatsTimeout bounds the time spent fetching the stats of the other
+	// servers of the Raft configuration.
+	raftStatsTimeout = 5 * time.Second
+
+	// raftLogStoreWarnSize is the size of the log store above which a
+	// warning is returned. BoltDB never gives back the space it allocated,
+	// so a log store that grew during a burst of writes stays large until
+	// it is compacted offline.
+	raftLogStoreWarnSize = 1024 * 1024 * 1024
+)
+
+// raftServerStats returns the replication and log store stats of the local
+// server.
+func (s *Server) raftServerStats() (*structs.RaftServerStats, error) {
+	stats := s.raft.Stats()
+	reply := &structs.RaftServerStats{
+		LastContact: -1,
+	}
+
+	var err error
+	if stats["last_contact"] != "never" {
+		reply.LastContact, err = time.ParseDuration(stats["last_contact"])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing server's last_contact value: %s", err)
+		}
+	}
+	reply.LastIndex, err = strconv.ParseUint(stats["last_log_index"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing server's last_log_index value: %s", err)
+	}
+
+	// The file snapshot store names snapshots after their term, index and
+	// creation time in milliseconds
+	if s.raftSnapshots != nil {
+		snapshots, err := s.raftSnapshots.List()
+		if err != nil {
+			return nil, fmt.Errorf("error listing snapshots: %v", err)
+		}
+		if len(snapshots) != 0 {
+			reply.LastSnapshotIndex = snapshots[0].Index
+			parts := strings.Split(snapshots[0].ID, "-")
+			if msec, err := strconv.ParseInt(parts[len(parts)-1], 10, 64); err == nil {
+				reply.LastSnapshotTime = time.Unix(0, msec*int64(time.Millisecond))
+			}
+		}
+	}
+
+	var logs raft.LogStore
+	if s.raftStore != nil {
+		logs = s.raftStore
+		fi, err := os.Stat(filepath.Join(s.config.DataDir, raftState, "raft.db"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading log store size: %v", err)
+		}
+		reply.LogStoreSize = fi.Size()
+	} else if s.raftInmem != nil {
+		logs = s.raftInmem
+	}
+	if logs != nil {
+		first, err := logs.FirstIndex()
+		if err != nil {
+			return nil, fmt.Errorf("error reading log store first index: %v", err)
+		}
+		if first != 0 && reply.LastIndex >= first {
+			reply.LogStoreEntries = reply.LastIndex - first + 1
+		}
+	}
+
+	// Snapshots truncate the log store down to the trailing logs once it
+	// holds more than the snapshot threshold, so holding many more entries
+	// means snapshots are failing or not keeping up.
+	conf := s.config.RaftConfig
+	if max := conf.TrailingLogs + 2*conf.SnapshotThreshold; reply.LogStoreEntries > max {
+		reply.Warnings = append(reply.Warnings, fmt.Sprintf(
+			"Log store holds %d entries, more than the %d expected between snapshots; check that snapshots succeed",
+			reply.LogStoreEntries, max))
+	}
+	if reply.LogStoreSize > raftLogStoreWarnSize {
+		reply.Warnings = append(reply.Warnings, fmt.Sprintf(
+			"Log store is %s; its free space is only reclaimed by compacting raft.db while the server is stopped",
+			humanize.IBytes(uint64(reply.LogStoreSize))))
+	}
+
+	return reply, nil
+}
+
+// raftStatsResult is the result of fetching the stats of a server.
+type raftStatsResult struct {
+	stats *structs.RaftServerStats
+	err   error
+}
+
+// fetchRaftServerStats fetches the stats of the servers in parallel, locally
+// for the local server and through the Status.RaftServerStats RPC for the
+// others. Servers that don't reply within raftStatsTimeout get an error.
+func (s *Server) fetchRaftServerStats(servers []raft.Server, members map[raft.ServerAddress]*serverParts) map[raft.ServerID]*raftStatsResult {
+	results := make(map[raft.ServerID]*raftStatsResult, len(servers))
+	chans := make(map[raft.ServerID]chan *raftStatsResult, len(servers))
+	for _, server := range servers {
+		if server.Address == s.raftTransport.LocalAddr() {
+			stats, err := s.raftServerStats()
+			results[server.ID] = &raftStatsResult{stats: stats, err: err}
+			continue
+		}
+
+		parts, ok := members[server.Address]
+		if !ok {
+			results[server.ID] = &raftStatsResult{err: fmt.Errorf("server is unknown to Serf")}
+			continue
+		}
+
+		ch := make(chan *raftStatsResult, 1)
+		chans[server.ID] = ch
+		go func() {
+			var args struct{}
+			var reply structs.RaftServerStats
+			err := s.connPool.RPC(s.config.Region, parts.Addr, parts.MajorVersion, "Status.RaftServerStats", &args, &reply)
+			if err != nil {
+				ch <- &raftStatsResult{err: err}
+				return
+			}
+			ch <- &raftStatsResult{stats: &reply}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), raftStatsTimeout)
+	defer cancel()
+	for id, ch := range chans {
+		select {
+		case result := <-ch:
+			results[id] = result
+		case <-ctx.Done():
+			results[id] = &raftStatsResult{err: fmt.Errorf("timed out fetching stats")}
+		}
+	}
+	return results
+}
@@ -0,0 +1,102 @@
+package nomad
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// replicationTracker records the progress of a replication loop so that it can
+// be reported by the Operator.ReplicationStatus endpoint.
+type replicationTracker struct {
+	status structs.ReplicationTypeStatus
+	l      sync.Mutex
+}
+
+// start marks the replication loop as running.
+func (r *replicationTracker) start() {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.status.Running = true
+}
+
+// stop marks the replication loop as no longer running. The results of the
+// last replication are retained.
+func (r *replicationTracker) stop() {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.status.Running = false
+}
+
+// success records a successful replication up to the given remote index.
+func (r *replicationTracker) success(index uint64) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.status.ReplicatedIndex = index
+	r.status.LastSuccess = time.Now().UTC()
+}
+
+// failure records a failed replication attempt.
+func (r *replicationTracker) failure(err error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.status.LastError = time.Now().UTC()
+	r.status.LastErrorMessage = err.Error()
+}
+
+// get returns a copy of the current status.
+func (r *replicationTracker) get() *structs.ReplicationTypeStatus {
+	r.l.Lock()
+	defer r.l.Unlock()
+	status := r.status
+	return &status
+}
+
+// replicationStatus returns the status of replication from the authoritative
+// region. If replication is enabled, the authoritative region is queried for
+// its current indexes so the lag can be determined.
+func (s *Server) replicationStatus() *structs.ReplicationStatus {
+	status := &structs.ReplicationStatus{
+		Enabled:      s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion,
+		SourceRegion: s.config.AuthoritativeRegion,
+		ACLPolicies:  s.aclPolicyReplication.get(),
+		ACLRoles:     s.aclRoleReplication.get(),
+		ACLTokens:    s.aclTokenReplication.get(),
+	}
+	if !status.Enabled {
+		return status
+	}
+
+	opts := structs.QueryOptions{
+		Region:     s.config.AuthoritativeRegion,
+		AuthToken:  s.ReplicationToken(),
+		AllowStale: true,
+	}
+
+	policiesReq := structs.ACLPolicyListRequest{QueryOptions: opts}
+	var policiesResp structs.ACLPolicyListResponse
+	if err := s.forwardRegion(s.config.AuthoritativeRegion, "ACL.ListPolicies", &policiesReq, &policiesResp); err != nil {
+		status.SourceError = err.Error()
+		return status
+	}
+	status.ACLPolicies.SourceIndex = policiesResp.Index
+
+	rolesReq := structs.ACLRoleListRequest{QueryOptions: opts}
+	var rolesResp structs.ACLRoleListResponse
+	if err := s.forwardRegion(s.config.AuthoritativeRegion, "ACL.ListRoles", &rolesReq, &rolesResp); err != nil {
+		status.SourceError = err.Error()
+		return status
+	}
+	status.ACLRoles.SourceIndex = rolesResp.Index
+
+	tokensReq := structs.ACLTokenListRequest{GlobalOnly: true, QueryOptions: opts}
+	var tokensResp structs.ACLTokenListResponse
+	if err := s.forwardRegion(s.config.AuthoritativeRegion, "ACL.ListTokens", &tokensReq, &tokensResp); err != nil {
+		status.SourceError = err.Error()
+		return status
+	}
+	status.ACLTokens.SourceIndex = tokensResp.Index
+
+	return status
+}
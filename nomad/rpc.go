@@ -51,6 +51,9 @@ type rpcHandler struct {
 	*Server
 	logger   log.Logger
 	gologger *golog.Logger
+
+	// watches coalesces the identical watches of blocking queries
+	watches *blockingWatches
 }
 
 func newRpcHandler(s *Server) *rpcHandler {
@@ -59,6 +62,7 @@ func newRpcHandler(s *Server) *rpcHandler {
 		Server:   s,
 		logger:   logger,
 		gologger: logger.StandardLogger(&log.StandardLoggerOptions{InferLevels: true}),
+		watches:  newBlockingWatches(),
 	}
 }
 
@@ -217,6 +221,12 @@ func (r *rpcHandler) handleConn(ctx context.Context, conn net.Conn, rpcCtx *RPCC
 
 		r.handleConn(ctx, conn, rpcCtx)
 
+	case pool.RpcCompressed:
+		// The rest of the connection is compressed, starting with the byte
+		// of the mode of the connection
+		conn = pool.NewCompressedConn(conn)
+		r.handleConn(ctx, conn, rpcCtx)
+
 	case pool.RpcStreaming:
 		r.handleStreamingConn(conn)
 
@@ -716,9 +726,10 @@ RUN_QUERY:
 	// Block up to the timeout if we didn't see anything fresh.
 	err := opts.run(ws, stateSnap)
 
-	// Check for minimum query time
+	// Check for minimum query time. The watch is shared with the concurrent
+	// queries watching the same objects.
 	if err == nil && opts.queryOpts.MinQueryIndex > 0 && opts.queryMeta.Index <= opts.queryOpts.MinQueryIndex {
-		if err := ws.WatchCtx(ctx); err == nil {
+		if err := r.watches.Watch(ctx, ws); err == nil {
 			goto RUN_QUERY
 		}
 	}
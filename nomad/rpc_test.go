@@ -327,3 +327,32 @@ func TestRPC_handleMultiplexV2(t *testing.T) {
 	require.True(structs.IsErrUnknownMethod(err))
 
 }
+
+func TestRPC_handleCompressed(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go s.handleConn(context.Background(), p2, &RPCContext{Conn: p2})
+
+	// Switch the connection to compression and make an RPC
+	_, err := p1.Write([]byte{byte(pool.RpcCompressed)})
+	require.Nil(err)
+	conn := pool.NewCompressedConn(p1)
+
+	_, err = conn.Write([]byte{byte(pool.RpcNomad)})
+	require.Nil(err)
+
+	args := &structs.GenericRequest{}
+	var l string
+	err = msgpackrpc.CallWithCodec(pool.NewClientCodec(conn), "Status.Leader", args, &l)
+	require.Nil(err)
+	require.NotEmpty(l)
+}
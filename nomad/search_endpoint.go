@@ -87,6 +87,8 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 	switch context {
 	case structs.Jobs:
 		return state.JobsByIDPrefix(ws, namespace, prefix)
+	case structs.JobTags:
+		return state.JobsByTagPrefix(ws, namespace, prefix)
 	case structs.Evals:
 		return state.EvalsByIDPrefix(ws, namespace, prefix)
 	case structs.Allocs:
@@ -101,9 +103,9 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 }
 
 // If the length of a prefix is odd, return a subset to the last even character
-// This only applies to UUIDs, jobs are excluded
+// This only applies to UUIDs, jobs and job tags are excluded
 func roundUUIDDownIfOdd(prefix string, context structs.Context) string {
-	if context == structs.Jobs {
+	if context == structs.Jobs || context == structs.JobTags {
 		return prefix
 	}
 
@@ -169,9 +171,14 @@ func (s *Search) PrefixSearch(args *structs.SearchRequest, reply *structs.Search
 				}
 			}
 
-			// Return matches for the given prefix
+			// Return matches for the given prefix. Jobs are matched on their
+			// tags rather than their IDs for the job tags context.
 			for k, v := range iters {
-				res, isTrunc := s.getMatches(v, args.Prefix)
+				prefix := args.Prefix
+				if k == structs.JobTags {
+					prefix = ""
+				}
+				res, isTrunc := s.getMatches(v, prefix)
 				reply.Matches[k] = res
 				reply.Truncations[k] = isTrunc
 			}
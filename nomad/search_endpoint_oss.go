@@ -19,6 +19,9 @@ var (
 
 // contextToIndex returns the index name to lookup in the state store.
 func contextToIndex(ctx structs.Context) string {
+	if ctx == structs.JobTags {
+		return "jobs"
+	}
 	return string(ctx)
 }
 
@@ -56,7 +59,7 @@ func anySearchPerms(aclObj *acl.ACL, namespace string, context structs.Context)
 	}
 	if !jobRead {
 		switch context {
-		case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs:
+		case structs.Allocs, structs.Deployments, structs.Evals, structs.Jobs, structs.JobTags:
 			return false
 		}
 	}
@@ -88,7 +91,7 @@ func searchContexts(aclObj *acl.ACL, namespace string, context structs.Context)
 	available := make([]structs.Context, 0, len(all))
 	for _, c := range all {
 		switch c {
-		case structs.Allocs, structs.Jobs, structs.Evals, structs.Deployments:
+		case structs.Allocs, structs.Jobs, structs.JobTags, structs.Evals, structs.Deployments:
 			if jobRead {
 				available = append(available, c)
 			}
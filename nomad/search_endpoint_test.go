@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jobIndex = 1000
@@ -60,6 +61,48 @@ func TestSearch_PrefixSearch_Job(t *testing.T) {
 	assert.Equal(uint64(jobIndex), resp.Index)
 }
 
+func TestSearch_PrefixSearch_JobTags(t *testing.T) {
+	require := require.New(t)
+
+	t.Parallel()
+	s := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	state := s.fsm.State()
+	job1 := mock.Job()
+	job1.Tags = []string{"owner:payments", "tier:1"}
+	require.NoError(state.UpsertJob(jobIndex, job1))
+
+	job2 := mock.Job()
+	job2.Tags = []string{"owner:search"}
+	require.NoError(state.UpsertJob(jobIndex+1, job2))
+
+	req := &structs.SearchRequest{
+		Prefix:  "owner:pay",
+		Context: structs.JobTags,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job1.Namespace,
+		},
+	}
+
+	var resp structs.SearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp))
+	require.Equal([]string{job1.ID}, resp.Matches[structs.JobTags])
+	require.False(resp.Truncations[structs.JobTags])
+	require.Equal(uint64(jobIndex+1), resp.Index)
+
+	req.Prefix = "owner:"
+	var resp2 structs.SearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp2))
+	require.ElementsMatch([]string{job1.ID, job2.ID}, resp2.Matches[structs.JobTags])
+}
+
 func TestSearch_PrefixSearch_ACL(t *testing.T) {
 	assert := assert.New(t)
 	jobID := "aaaaaaaa-e8f7-fd38-c855-ab94ceb8970"
@@ -22,6 +22,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/codec"
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/helper/stats"
@@ -29,12 +30,14 @@ import (
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
 	raftboltdb "github.com/hashicorp/raft-boltdb"
 	"github.com/hashicorp/serf/serf"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -102,6 +105,7 @@ type Server struct {
 	raftLayer     *RaftLayer
 	raftStore     *raftboltdb.BoltStore
 	raftInmem     *raft.InmemStore
+	raftSnapshots raft.SnapshotStore
 	raftTransport *raft.NetworkTransport
 
 	// autopilot is the Autopilot instance for this server.
@@ -150,6 +154,14 @@ type Server struct {
 	nodeConns     map[string][]*nodeConnState
 	nodeConnsLock sync.RWMutex
 
+	// clientRelayLimiter limits the rate of the filesystem, logs and exec
+	// requests relayed to the clients. It is nil if the rate isn't limited.
+	clientRelayLimiter *rate.Limiter
+
+	// writeLimiter limits the rate of the write RPCs of each ACL token and
+	// namespace. It is nil if the rate isn't limited.
+	writeLimiter *writeLimiter
+
 	// peers is used to track the known Nomad servers. This is
 	// used for region forwarding and clustering.
 	peers      map[string][]*serverParts
@@ -184,6 +196,10 @@ type Server struct {
 	// that are waiting to be brokered to a sub-scheduler
 	evalBroker *EvalBroker
 
+	// eventBroker is used to stream the events of the state store to
+	// subscribers
+	eventBroker *stream.EventBroker
+
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
@@ -201,9 +217,28 @@ type Server struct {
 	// vault is the client for communicating with Vault.
 	vault VaultClient
 
+	// vaults are the clients of the named Vault clusters, keyed by name.
+	vaults map[string]VaultClient
+
+	// snapshotAgent periodically saves snapshots of the Raft state while
+	// this server is the leader. It is nil unless enabled.
+	snapshotAgent *snapshotAgent
+
+	// externalDNS publishes Nomad services to an external DNS provider while
+	// this server is the leader. It is nil unless enabled.
+	externalDNS *externalDNS
+
 	// Worker used for processing
 	workers []*Worker
 
+	// pinnedWorkers only process evaluations of the scheduler they are
+	// pinned to. They aren't paused while this server is the leader.
+	pinnedWorkers []*Worker
+
+	// workerAutoscaler starts additional workers while evaluations are
+	// waiting in the broker. It is nil unless enabled.
+	workerAutoscaler *workerAutoscaler
+
 	// aclCache is used to maintain the parsed ACL objects
 	aclCache *lru.TwoQueueCache
 
@@ -216,6 +251,13 @@ type Server struct {
 	// Nomad router.
 	statsFetcher *StatsFetcher
 
+	// aclPolicyReplication, aclRoleReplication and aclTokenReplication track
+	// the progress of ACL replication from the authoritative region while
+	// this server is leader.
+	aclPolicyReplication replicationTracker
+	aclRoleReplication   replicationTracker
+	aclTokenReplication  replicationTracker
+
 	// EnterpriseState is used to fill in state for Pro/Ent builds
 	EnterpriseState
 
@@ -240,12 +282,22 @@ type endpoints struct {
 	System     *System
 	Operator   *Operator
 	ACL        *ACL
+	Variables  *Variables
+	Keyring    *Keyring
 	Enterprise *EnterpriseEndpoints
 
+	ServiceRegistration *ServiceRegistration
+	NodePool            *NodePool
+
+	// Event is only a streaming endpoint
+	Event *Event
+
 	// Client endpoints
 	ClientStats       *ClientStats
 	FileSystem        *FileSystem
 	ClientAllocations *ClientAllocations
+	NodeMeta          *NodeMeta
+	Fault             *Fault
 }
 
 // NewServer is used to construct a new Nomad server from the
@@ -287,23 +339,26 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI) (*Server, error)
 
 	// Create the server
 	s := &Server{
-		config:        config,
-		consulCatalog: consulCatalog,
-		connPool:      pool.NewPool(logger, serverRPCCache, serverMaxStreams, tlsWrap),
-		logger:        logger,
-		tlsWrap:       tlsWrap,
-		rpcServer:     rpc.NewServer(),
-		streamingRpcs: structs.NewStreamingRpcRegistry(),
-		nodeConns:     make(map[string][]*nodeConnState),
-		peers:         make(map[string][]*serverParts),
-		localPeers:    make(map[raft.ServerAddress]*serverParts),
-		reconcileCh:   make(chan serf.Member, 32),
-		eventCh:       make(chan serf.Event, 256),
-		evalBroker:    evalBroker,
-		blockedEvals:  NewBlockedEvals(evalBroker, logger),
-		rpcTLS:        incomingTLS,
-		aclCache:      aclCache,
-		shutdownCh:    make(chan struct{}),
+		config:             config,
+		consulCatalog:      consulCatalog,
+		connPool:           pool.NewPool(logger, serverRPCCache, serverMaxStreams, tlsWrap),
+		logger:             logger,
+		tlsWrap:            tlsWrap,
+		rpcServer:          rpc.NewServer(),
+		streamingRpcs:      structs.NewStreamingRpcRegistry(),
+		nodeConns:          make(map[string][]*nodeConnState),
+		clientRelayLimiter: newClientRelayLimiter(config),
+		writeLimiter:       newWriteLimiter(config.WriteRateLimit),
+		peers:              make(map[string][]*serverParts),
+		localPeers:         make(map[raft.ServerAddress]*serverParts),
+		reconcileCh:        make(chan serf.Member, 32),
+		eventCh:            make(chan serf.Event, 256),
+		evalBroker:         evalBroker,
+		blockedEvals:       NewBlockedEvals(evalBroker, logger),
+		eventBroker:        stream.NewEventBroker(config.EventBufferSize),
+		rpcTLS:             incomingTLS,
+		aclCache:           aclCache,
+		shutdownCh:         make(chan struct{}),
 	}
 
 	// Create the RPC handler
@@ -332,6 +387,32 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI) (*Server, error)
 		return nil, fmt.Errorf("Failed to setup Vault client: %v", err)
 	}
 
+	// Setup the snapshot agent
+	if s.config.SnapshotAgentConfig.IsEnabled() {
+		agent, err := newSnapshotAgent(s.config.SnapshotAgentConfig, s.logger)
+		if err != nil {
+			s.Shutdown()
+			s.logger.Error("failed to setup snapshot agent", "error", err)
+			return nil, fmt.Errorf("Failed to setup snapshot agent: %v", err)
+		}
+		s.snapshotAgent = agent
+
+		if s.config.DevMode {
+			s.logger.Warn("snapshot agent is enabled but Raft snapshots are discarded in dev mode")
+		}
+	}
+
+	// Setup the external DNS controller
+	if s.config.ExternalDNSConfig.IsEnabled() {
+		controller, err := newExternalDNS(s.config.ExternalDNSConfig, s.logger)
+		if err != nil {
+			s.Shutdown()
+			s.logger.Error("failed to setup external DNS", "error", err)
+			return nil, fmt.Errorf("Failed to setup external DNS: %v", err)
+		}
+		s.externalDNS = controller
+	}
+
 	// Initialize the RPC layer
 	if err := s.setupRPC(tlsWrap); err != nil {
 		s.Shutdown()
@@ -565,6 +646,9 @@ func (s *Server) Shutdown() error {
 	if s.vault != nil {
 		s.vault.Stop()
 	}
+	for _, v := range s.vaults {
+		v.Stop()
+	}
 
 	return nil
 }
@@ -688,6 +772,13 @@ func (s *Server) Reload(newConfig *Config) error {
 			multierror.Append(&mErr, err)
 		}
 	}
+	for _, conf := range newConfig.VaultClusters {
+		if v, ok := s.vaults[conf.Name]; ok {
+			if err := v.SetConfig(conf); err != nil {
+				multierror.Append(&mErr, err)
+			}
+		}
+	}
 
 	shouldReloadTLS, err := tlsutil.ShouldReloadRPCConnections(s.config.TLSConfig, newConfig.TLSConfig)
 	if err != nil {
@@ -911,7 +1002,8 @@ func (s *Server) setupDeploymentWatcher() error {
 	s.deploymentWatcher = deploymentwatcher.NewDeploymentsWatcher(
 		s.logger, raftShim,
 		deploymentwatcher.LimitStateQueriesPerSecond,
-		deploymentwatcher.CrossDeploymentUpdateBatchDuration)
+		deploymentwatcher.CrossDeploymentUpdateBatchDuration,
+		s.config.DeploymentWebhookSigningKey)
 
 	return nil
 }
@@ -940,9 +1032,82 @@ func (s *Server) setupVaultClient() error {
 		return err
 	}
 	s.vault = v
+
+	s.vaults = make(map[string]VaultClient, len(s.config.VaultClusters))
+	for _, conf := range s.config.VaultClusters {
+		v, err := NewVaultClient(conf, s.logger.With("vault_cluster", conf.Name), s.purgeVaultAccessors)
+		if err != nil {
+			return fmt.Errorf("failed to create client of Vault cluster %q: %v", conf.Name, err)
+		}
+		s.vaults[conf.Name] = v
+	}
 	return nil
 }
 
+// vaultClient returns the client of the Vault cluster of the name. The empty
+// name is the default cluster.
+func (s *Server) vaultClient(cluster string) (VaultClient, error) {
+	if cluster == "" || cluster == structs.VaultDefaultCluster {
+		return s.vault, nil
+	}
+	if v, ok := s.vaults[cluster]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("unknown Vault cluster %q", cluster)
+}
+
+// vaultConfig returns the config of the Vault cluster of the name, or nil if
+// the cluster isn't configured. The empty name is the default cluster.
+func (s *Server) vaultConfig(cluster string) *config.VaultConfig {
+	if cluster == "" || cluster == structs.VaultDefaultCluster {
+		return s.config.VaultConfig
+	}
+	for _, conf := range s.config.VaultClusters {
+		if conf.Name == cluster {
+			return conf
+		}
+	}
+	return nil
+}
+
+// setVaultActive activates or de-activates the clients of all the Vault
+// clusters.
+func (s *Server) setVaultActive(active bool) {
+	s.vault.SetActive(active)
+	for _, v := range s.vaults {
+		v.SetActive(active)
+	}
+}
+
+// revokeVaultTokens revokes the tokens of the accessors with the clients of
+// the Vault clusters they were created in. The committed accessors of
+// clusters that are no longer configured are purged without being revoked,
+// leaving their tokens to expire.
+func (s *Server) revokeVaultTokens(ctx context.Context, accessors []*structs.VaultAccessor, committed bool) error {
+	byCluster := make(map[string][]*structs.VaultAccessor)
+	for _, va := range accessors {
+		byCluster[va.Cluster] = append(byCluster[va.Cluster], va)
+	}
+
+	var mErr multierror.Error
+	for cluster, accessors := range byCluster {
+		v, err := s.vaultClient(cluster)
+		if err != nil {
+			s.logger.Warn("not revoking Vault tokens of unknown cluster", "vault_cluster", cluster, "tokens", len(accessors))
+			if committed {
+				if err := s.purgeVaultAccessors(accessors); err != nil {
+					multierror.Append(&mErr, err)
+				}
+			}
+			continue
+		}
+		if err := v.RevokeTokens(ctx, accessors, committed); err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 // setupRPC is used to setup the RPC listener
 func (s *Server) setupRPC(tlsWrap tlsutil.RegionWrapper) error {
 	// Populate the static RPC server
@@ -1022,15 +1187,23 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.Status = &Status{srv: s, logger: s.logger.Named("status")}
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
+		s.staticEndpoints.Variables = &Variables{srv: s, logger: s.logger.Named("variables")}
+		s.staticEndpoints.Keyring = &Keyring{srv: s, logger: s.logger.Named("keyring")}
+		s.staticEndpoints.ServiceRegistration = &ServiceRegistration{srv: s, logger: s.logger.Named("service_registration")}
+		s.staticEndpoints.NodePool = &NodePool{srv: s, logger: s.logger.Named("node_pool")}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// Client endpoints
 		s.staticEndpoints.ClientStats = &ClientStats{srv: s, logger: s.logger.Named("client_stats")}
 		s.staticEndpoints.ClientAllocations = &ClientAllocations{srv: s, logger: s.logger.Named("client_allocs")}
+		s.staticEndpoints.NodeMeta = &NodeMeta{srv: s, logger: s.logger.Named("node_meta")}
+		s.staticEndpoints.Fault = &Fault{srv: s, logger: s.logger.Named("fault")}
 
 		// Streaming endpoints
 		s.staticEndpoints.FileSystem = &FileSystem{srv: s, logger: s.logger.Named("client_fs")}
 		s.staticEndpoints.FileSystem.register()
+		s.staticEndpoints.Event = &Event{srv: s, logger: s.logger.Named("event")}
+		s.staticEndpoints.Event.register()
 	}
 
 	// Register the static handlers
@@ -1046,9 +1219,15 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.Status)
 	server.Register(s.staticEndpoints.System)
 	server.Register(s.staticEndpoints.Search)
+	server.Register(s.staticEndpoints.Variables)
+	server.Register(s.staticEndpoints.Keyring)
+	server.Register(s.staticEndpoints.ServiceRegistration)
+	server.Register(s.staticEndpoints.NodePool)
 	s.staticEndpoints.Enterprise.Register(server)
 	server.Register(s.staticEndpoints.ClientStats)
 	server.Register(s.staticEndpoints.ClientAllocations)
+	server.Register(s.staticEndpoints.NodeMeta)
+	server.Register(s.staticEndpoints.Fault)
 	server.Register(s.staticEndpoints.FileSystem)
 
 	// Create new dynamic endpoints and add them to the RPC server.
@@ -1071,11 +1250,19 @@ func (s *Server) setupRaft() error {
 
 	// Create the FSM
 	fsmConfig := &FSMConfig{
-		EvalBroker: s.evalBroker,
-		Periodic:   s.periodicDispatcher,
-		Blocked:    s.blockedEvals,
-		Logger:     s.logger,
-		Region:     s.Region(),
+		EvalBroker:  s.evalBroker,
+		Periodic:    s.periodicDispatcher,
+		Blocked:     s.blockedEvals,
+		EventBroker: s.eventBroker,
+		Logger:      s.logger,
+		Region:      s.Region(),
+		CompressJobVersions: func() bool {
+			// Serf is set up after Raft, so the servers are unknown until then
+			if s.serf == nil {
+				return false
+			}
+			return ServersMeetMinimumVersion(s.Members(), minCompressedJobVersionsVersion)
+		},
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
@@ -1217,6 +1404,7 @@ func (s *Server) setupRaft() error {
 	s.leaderCh = leaderCh
 
 	// Setup the Raft store
+	s.raftSnapshots = snap
 	s.raft, err = raft.NewRaft(s.config.RaftConfig, s.fsm, log, stable, snap, trans)
 	if err != nil {
 		return err
@@ -1305,6 +1493,17 @@ func (s *Server) setupWorkers() error {
 		return fmt.Errorf("invalid configuration: %q scheduler not enabled", structs.JobTypeCore)
 	}
 
+	// Check that the workers are only pinned to enabled schedulers
+	workersConfig := s.config.SchedulerWorkers
+	if workersConfig != nil {
+		enabled := helper.SliceStringToSet(s.config.EnabledSchedulers)
+		for sched := range workersConfig.Pinned {
+			if _, ok := enabled[sched]; !ok {
+				return fmt.Errorf("invalid configuration: workers pinned to scheduler %q which is not enabled", sched)
+			}
+		}
+	}
+
 	// Start the workers
 	for i := 0; i < s.config.NumSchedulers; i++ {
 		if w, err := NewWorker(s); err != nil {
@@ -1314,6 +1513,26 @@ func (s *Server) setupWorkers() error {
 		}
 	}
 	s.logger.Info("starting scheduling worker(s)", "num_workers", s.config.NumSchedulers, "schedulers", s.config.EnabledSchedulers)
+
+	if workersConfig == nil {
+		return nil
+	}
+
+	// Start the workers pinned to a scheduler
+	for sched, count := range workersConfig.Pinned {
+		for i := 0; i < count; i++ {
+			s.pinnedWorkers = append(s.pinnedWorkers, newWorker(s, sched))
+		}
+		if count > 0 {
+			s.logger.Info("starting pinned scheduling worker(s)", "num_workers", count, "scheduler", sched)
+		}
+	}
+
+	// Start the autoscaler of the shared workers
+	if workersConfig.AutoscaleEnabled() {
+		s.workerAutoscaler = newWorkerAutoscaler(s)
+		go s.workerAutoscaler.run(s.shutdownCh)
+	}
 	return nil
 }
 
@@ -1333,6 +1552,12 @@ func (s *Server) IsLeader() bool {
 	return s.raft.State() == raft.Leader
 }
 
+// PlanQueueDepth returns the number of plans waiting to be applied, which is
+// only non-zero on the leader.
+func (s *Server) PlanQueueDepth() int {
+	return s.planQueue.Stats().Depth
+}
+
 // Join is used to have Nomad join the gossip ring
 // The target address should be another node listening on the
 // Serf address
@@ -0,0 +1,263 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceRegistration endpoint is used for the services registered with the
+// Nomad service provider
+type ServiceRegistration struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used by clients to register the services of their tasks
+func (s *ServiceRegistration) Upsert(args *structs.ServiceRegistrationUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "upsert"}, time.Now())
+
+	if len(args.Services) == 0 {
+		return fmt.Errorf("missing services")
+	}
+
+	// Only the node running the allocations may register their services
+	node, err := s.nodeBySecretID(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return structs.ErrPermissionDenied
+	}
+	snap, err := s.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, service := range args.Services {
+		if err := service.Validate(); err != nil {
+			return fmt.Errorf("invalid service registration %q: %v", service.ID, err)
+		}
+		if service.NodeID != node.ID {
+			return structs.ErrPermissionDenied
+		}
+
+		alloc, err := snap.AllocByID(nil, service.AllocID)
+		if err != nil {
+			return err
+		}
+		if alloc == nil {
+			return fmt.Errorf("unknown allocation %q", service.AllocID)
+		}
+		if alloc.NodeID != node.ID || alloc.Namespace != service.Namespace || alloc.JobID != service.JobID {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Update via Raft
+	_, index, err := s.srv.raftApply(structs.ServiceRegistrationUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// DeleteByID is used to remove a service registration
+func (s *ServiceRegistration) DeleteByID(args *structs.ServiceRegistrationDeleteByIDRequest, reply *structs.GenericResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.DeleteByID", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "delete_id"}, time.Now())
+
+	if args.ID == "" {
+		return fmt.Errorf("missing service registration ID")
+	}
+
+	service, err := s.srv.fsm.State().ServiceRegistrationByID(nil, args.ID)
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		return fmt.Errorf("service registration %q not found", args.ID)
+	}
+
+	// Either the node running the service or a token that can submit jobs
+	// in the namespace may remove the registration
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	switch err {
+	case nil:
+		if aclObj != nil && !aclObj.AllowNsOp(service.Namespace, acl.NamespaceCapabilitySubmitJob) {
+			return structs.ErrPermissionDenied
+		}
+	case structs.ErrTokenNotFound:
+		node, err := s.nodeBySecretID(args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			return structs.ErrTokenNotFound
+		}
+		if node.ID != service.NodeID {
+			return structs.ErrPermissionDenied
+		}
+	default:
+		return err
+	}
+
+	// Update via Raft
+	_, index, err := s.srv.raftApply(structs.ServiceRegistrationDeleteByIDRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the services registered in a namespace
+func (s *ServiceRegistration) List(args *structs.ServiceRegistrationListRequest, reply *structs.ServiceRegistrationListResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "list"}, time.Now())
+
+	ns := args.RequestNamespace()
+	if err := s.checkReadAccess(args.AuthToken, ns); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ServiceRegistrationsByNamespace(ws, ns)
+			if err != nil {
+				return err
+			}
+
+			// Group the registrations by service name
+			tags := make(map[string]map[string]struct{})
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				service := raw.(*structs.ServiceRegistration)
+				if _, ok := tags[service.ServiceName]; !ok {
+					tags[service.ServiceName] = make(map[string]struct{})
+				}
+				for _, tag := range service.Tags {
+					tags[service.ServiceName][tag] = struct{}{}
+				}
+			}
+
+			reply.Services = make([]*structs.ServiceRegistrationStub, 0, len(tags))
+			for name, set := range tags {
+				stub := &structs.ServiceRegistrationStub{
+					ServiceName: name,
+					Tags:        make([]string, 0, len(set)),
+				}
+				for tag := range set {
+					stub.Tags = append(stub.Tags, tag)
+				}
+				sort.Strings(stub.Tags)
+				reply.Services = append(reply.Services, stub)
+			}
+			sort.Slice(reply.Services, func(i, j int) bool {
+				return reply.Services[i].ServiceName < reply.Services[j].ServiceName
+			})
+
+			// Use the last index that affected the service registrations table
+			index, err := state.Index("service_registrations")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetService is used to read the registrations of a service
+func (s *ServiceRegistration) GetService(args *structs.ServiceRegistrationByNameRequest, reply *structs.ServiceRegistrationByNameResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.GetService", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "get_service"}, time.Now())
+
+	if args.ServiceName == "" {
+		return fmt.Errorf("missing service name")
+	}
+
+	// Nodes may read the services to render them into templates
+	ns := args.RequestNamespace()
+	if err := s.checkReadAccess(args.AuthToken, ns); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.ServiceRegistrationsByName(ws, ns, args.ServiceName)
+			if err != nil {
+				return err
+			}
+
+			reply.Services = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.Services = append(reply.Services, raw.(*structs.ServiceRegistration))
+			}
+
+			// Use the last index that affected the service registrations table
+			index, err := state.Index("service_registrations")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// checkReadAccess checks that the token may read the services of the
+// namespace. The token may also be the secret ID of a node.
+func (s *ServiceRegistration) checkReadAccess(token, ns string) error {
+	aclObj, err := s.srv.ResolveToken(token)
+	switch err {
+	case nil:
+		if aclObj != nil && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+			return structs.ErrPermissionDenied
+		}
+		return nil
+	case structs.ErrTokenNotFound:
+		node, err := s.nodeBySecretID(token)
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			return structs.ErrTokenNotFound
+		}
+		return nil
+	default:
+		return err
+	}
+}
+
+// nodeBySecretID returns the node with the given secret ID, or nil if
+// there's no such node.
+func (s *ServiceRegistration) nodeBySecretID(secretID string) (*structs.Node, error) {
+	if secretID == "" {
+		return nil, nil
+	}
+	return s.srv.fsm.State().NodeBySecretID(nil, secretID)
+}
@@ -0,0 +1,142 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistrationEndpoint_UpsertListDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a node running an allocation
+	state := s1.fsm.State()
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	service := mock.ServiceRegistration(alloc)
+	req := &structs.ServiceRegistrationUpsertRequest{
+		Services:     []*structs.ServiceRegistration{service},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+
+	// Registering without the node's secret fails
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Other nodes can't register the services of the allocation
+	other := mock.Node()
+	require.NoError(state.UpsertNode(1003, other))
+	req.AuthToken = other.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Register the service
+	req.AuthToken = node.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp))
+	require.NotZero(resp.Index)
+
+	// List the services of the namespace
+	listReq := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ServiceRegistrationListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", listReq, &listResp))
+	require.Len(listResp.Services, 1)
+	require.Equal(service.ServiceName, listResp.Services[0].ServiceName)
+	require.Equal(service.Tags, listResp.Services[0].Tags)
+	require.Equal(resp.Index, listResp.Index)
+
+	// Read the registrations of the service
+	getReq := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  service.ServiceName,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", getReq, &getResp))
+	require.Len(getResp.Services, 1)
+	require.Equal(service.Address, getResp.Services[0].Address)
+	require.Equal(service.Port, getResp.Services[0].Port)
+
+	// Remove the registration
+	delReq := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID:           service.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", delReq, &resp))
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", getReq, &getResp))
+	require.Empty(getResp.Services)
+}
+
+func TestServiceRegistrationEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a node running an allocation with a registered service
+	state := s1.fsm.State()
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	service := mock.ServiceRegistration(alloc)
+	require.NoError(state.UpsertServiceRegistrations(1003, []*structs.ServiceRegistration{service}))
+
+	getReq := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  service.ServiceName,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+
+	// Reading without a token fails
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", getReq, &getResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Reading with a token that can read jobs succeeds
+	readToken := mock.CreatePolicyAndToken(t, state, 1004, "read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	getReq.AuthToken = readToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", getReq, &getResp))
+	require.Len(getResp.Services, 1)
+
+	// Nodes can read the services
+	getReq.AuthToken = node.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", getReq, &getResp))
+	require.Len(getResp.Services, 1)
+
+	// Removing the registration requires the submit-job capability
+	delReq := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID:           service.ID,
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: readToken.SecretID},
+	}
+	var resp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", delReq, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	delReq.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", delReq, &resp))
+}
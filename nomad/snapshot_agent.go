@@ -0,0 +1,371 @@
+package nomad
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/raft"
+)
+
+const (
+	// snapshotAgentFilePrefix and snapshotAgentFileSuffix surround the
+	// timestamp in the name of every snapshot the agent saves. Only files
+	// matching both are considered when enforcing retention.
+	snapshotAgentFilePrefix = "nomad-snapshot-"
+	snapshotAgentFileSuffix = ".snap"
+
+	// snapshotAgentTimeFormat is the timestamp format used in snapshot names.
+	// It sorts lexically in chronological order.
+	snapshotAgentTimeFormat = "20060102T150405.000000000Z"
+)
+
+// snapshotDestination is a location the snapshot agent saves snapshots to.
+type snapshotDestination interface {
+	// Save stores the snapshot read from r under the given name.
+	Save(name string, r io.ReadSeeker) error
+
+	// List returns the names of the stored snapshots.
+	List() ([]string, error)
+
+	// Delete removes the named snapshot.
+	Delete(name string) error
+
+	// String returns a description of the destination for logging.
+	String() string
+}
+
+// snapshotAgent periodically saves a snapshot of the Raft state to its
+// destinations while the server is the leader, and removes old snapshots
+// beyond the configured retention.
+type snapshotAgent struct {
+	config       *config.SnapshotAgentConfig
+	destinations []snapshotDestination
+	logger       log.Logger
+}
+
+// newSnapshotAgent returns a snapshot agent for the given configuration.
+func newSnapshotAgent(c *config.SnapshotAgentConfig, logger log.Logger) (*snapshotAgent, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	a := &snapshotAgent{
+		config: c,
+		logger: logger.Named("snapshot_agent"),
+	}
+
+	if c.LocalPath != "" {
+		if err := os.MkdirAll(c.LocalPath, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create local_path: %v", err)
+		}
+		a.destinations = append(a.destinations, &localSnapshotDestination{path: c.LocalPath})
+	}
+
+	if c.S3 != nil {
+		a.destinations = append(a.destinations, newS3SnapshotDestination(c.S3))
+	}
+
+	return a, nil
+}
+
+// run saves a snapshot every interval until the stop channel is closed.
+func (a *snapshotAgent) run(stopCh chan struct{}, r *raft.Raft) {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if _, err := a.snapshot(r); err != nil {
+				a.logger.Error("failed to save snapshot", "error", err)
+			}
+		}
+	}
+}
+
+// snapshot takes a Raft snapshot, saves it to every destination and enforces
+// retention. It returns the name of the saved snapshot.
+func (a *snapshotAgent) snapshot(r *raft.Raft) (string, error) {
+	defer metrics.MeasureSince([]string{"nomad", "snapshot_agent", "snapshot"}, time.Now())
+
+	future := r.Snapshot()
+	if err := future.Error(); err != nil {
+		return "", fmt.Errorf("failed to take snapshot: %v", err)
+	}
+	meta, state, err := future.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open snapshot: %v", err)
+	}
+	defer state.Close()
+
+	// Write the archive to a temporary file so it can be sent to each
+	// destination.
+	archive, err := ioutil.TempFile("", snapshotAgentFilePrefix)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if err := writeSnapshotArchive(archive, meta, state); err != nil {
+		return "", fmt.Errorf("failed to write snapshot archive: %v", err)
+	}
+
+	name := snapshotAgentFilePrefix + time.Now().UTC().Format(snapshotAgentTimeFormat) + snapshotAgentFileSuffix
+
+	var failed bool
+	for _, dest := range a.destinations {
+		if _, err := archive.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		if err := dest.Save(name, archive); err != nil {
+			a.logger.Error("failed to save snapshot", "destination", dest.String(), "error", err)
+			failed = true
+			continue
+		}
+		a.logger.Info("saved snapshot", "destination", dest.String(), "name", name, "index", meta.Index)
+
+		if err := a.prune(dest); err != nil {
+			a.logger.Error("failed to remove old snapshots", "destination", dest.String(), "error", err)
+		}
+	}
+
+	if failed {
+		return name, fmt.Errorf("failed to save snapshot %q to one or more destinations", name)
+	}
+	return name, nil
+}
+
+// prune deletes the oldest snapshots in the destination so that at most the
+// configured number are retained.
+func (a *snapshotAgent) prune(dest snapshotDestination) error {
+	if a.config.Retain == nil || *a.config.Retain == 0 {
+		return nil
+	}
+	retain := *a.config.Retain
+
+	all, err := dest.List()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, name := range all {
+		if strings.HasPrefix(name, snapshotAgentFilePrefix) && strings.HasSuffix(name, snapshotAgentFileSuffix) {
+			names = append(names, name)
+		}
+	}
+	if len(names) <= retain {
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-retain] {
+		if err := dest.Delete(name); err != nil {
+			return err
+		}
+		a.logger.Debug("removed old snapshot", "destination", dest.String(), "name", name)
+	}
+	return nil
+}
+
+// writeSnapshotArchive writes a gzipped tar archive containing the snapshot
+// metadata as meta.json, the state as state.bin and a SHA256SUMS file with the
+// checksums of both.
+func writeSnapshotArchive(w io.Writer, meta *raft.SnapshotMeta, state io.Reader) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	now := time.Now()
+
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	sums := make(map[string]hash.Hash)
+	writeFile := func(name string, size int64, r io.Reader) error {
+		if err := archive.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    size,
+			ModTime: now,
+		}); err != nil {
+			return err
+		}
+
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(archive, h), r); err != nil {
+			return err
+		}
+		sums[name] = h
+		return nil
+	}
+
+	if err := writeFile("meta.json", int64(len(metaJSON)), bytes.NewReader(metaJSON)); err != nil {
+		return err
+	}
+	if err := writeFile("state.bin", meta.Size, state); err != nil {
+		return err
+	}
+
+	var sha256sums strings.Builder
+	for _, name := range []string{"meta.json", "state.bin"} {
+		fmt.Fprintf(&sha256sums, "%x  %s\n", sums[name].Sum(nil), name)
+	}
+	if err := writeFile("SHA256SUMS", int64(sha256sums.Len()), strings.NewReader(sha256sums.String())); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// localSnapshotDestination saves snapshots to a local directory.
+type localSnapshotDestination struct {
+	path string
+}
+
+func (d *localSnapshotDestination) Save(name string, r io.ReadSeeker) error {
+	// Write to a temporary file first so a partial snapshot is never left
+	// under the final name.
+	f, err := ioutil.TempFile(d.path, name+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(d.path, name))
+}
+
+func (d *localSnapshotDestination) List() ([]string, error) {
+	files, err := ioutil.ReadDir(d.path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Mode().IsRegular() {
+			names = append(names, f.Name())
+		}
+	}
+	return names, nil
+}
+
+func (d *localSnapshotDestination) Delete(name string) error {
+	return os.Remove(filepath.Join(d.path, name))
+}
+
+func (d *localSnapshotDestination) String() string {
+	return d.path
+}
+
+// s3SnapshotDestination saves snapshots to an S3-compatible bucket.
+type s3SnapshotDestination struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func newS3SnapshotDestination(c *config.SnapshotAgentS3Config) *s3SnapshotDestination {
+	awsConfig := aws.NewConfig()
+	if c.Region != "" {
+		awsConfig = awsConfig.WithRegion(c.Region)
+	}
+	if c.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(c.Endpoint)
+	}
+	if c.ForcePathStyle != nil {
+		awsConfig = awsConfig.WithS3ForcePathStyle(*c.ForcePathStyle)
+	}
+	if c.AccessKeyID != "" || c.SecretAccessKey != "" {
+		awsConfig = awsConfig.WithCredentials(
+			credentials.NewStaticCredentials(c.AccessKeyID, c.SecretAccessKey, ""))
+	}
+
+	return &s3SnapshotDestination{
+		client: s3.New(session.New(awsConfig)),
+		bucket: c.Bucket,
+		prefix: c.KeyPrefix,
+	}
+}
+
+func (d *s3SnapshotDestination) Save(name string, r io.ReadSeeker) error {
+	_, err := d.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(d.prefix + name),
+		Body:   r,
+	})
+	return err
+}
+
+func (d *s3SnapshotDestination) List() ([]string, error) {
+	input := &s3.ListObjectsInput{
+		Bucket: aws.String(d.bucket),
+		Prefix: aws.String(d.prefix + snapshotAgentFilePrefix),
+	}
+
+	var names []string
+	for {
+		out, err := d.client.ListObjects(input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range out.Contents {
+			key := aws.StringValue(obj.Key)
+			names = append(names, strings.TrimPrefix(key, d.prefix))
+			input.Marker = obj.Key
+		}
+
+		if !aws.BoolValue(out.IsTruncated) || len(out.Contents) == 0 {
+			return names, nil
+		}
+	}
+}
+
+func (d *s3SnapshotDestination) Delete(name string) error {
+	_, err := d.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(d.prefix + name),
+	})
+	return err
+}
+
+func (d *s3SnapshotDestination) String() string {
+	return fmt.Sprintf("s3://%s/%s", d.bucket, d.prefix)
+}
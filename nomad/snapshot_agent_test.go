@@ -0,0 +1,138 @@
+package nomad
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotAgent_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+	snapshotDir := filepath.Join(dir, "snapshots")
+
+	s1 := TestServer(t, func(c *Config) {
+		c.DevMode = false
+		c.DataDir = filepath.Join(dir, "server")
+		c.Bootstrap = true
+		c.DevDisableBootstrap = false
+		c.SnapshotAgentConfig = &config.SnapshotAgentConfig{
+			Enabled:   helper.BoolToPtr(true),
+			Interval:  time.Hour,
+			Retain:    helper.IntToPtr(2),
+			LocalPath: snapshotDir,
+		}
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	require.NotNil(s1.snapshotAgent)
+
+	// Write something to snapshot
+	node := mock.Node()
+	req := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeUpdateResponse
+	require.NoError(s1.RPC("Node.Register", req, &resp))
+
+	name, err := s1.snapshotAgent.snapshot(s1.raft)
+	require.NoError(err)
+
+	// Check the archive contents
+	f, err := os.Open(filepath.Join(snapshotDir, name))
+	require.NoError(err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(err)
+	archive := tar.NewReader(gz)
+
+	files := make(map[string][]byte)
+	for {
+		hdr, err := archive.Next()
+		if err != nil {
+			break
+		}
+		data, err := ioutil.ReadAll(archive)
+		require.NoError(err)
+		files[hdr.Name] = data
+	}
+	require.Len(files, 3)
+	require.NotEmpty(files["state.bin"])
+	require.Contains(string(files["SHA256SUMS"]), "state.bin")
+
+	var meta raft.SnapshotMeta
+	require.NoError(json.Unmarshal(files["meta.json"], &meta))
+	require.Equal(int64(len(files["state.bin"])), meta.Size)
+	require.True(meta.Index >= resp.Index)
+
+	// Take more snapshots and check that only the newest are retained
+	var names []string
+	for i := 0; i < 3; i++ {
+		name, err := s1.snapshotAgent.snapshot(s1.raft)
+		require.NoError(err)
+		names = append(names, name)
+	}
+
+	infos, err := ioutil.ReadDir(snapshotDir)
+	require.NoError(err)
+	var found []string
+	for _, info := range infos {
+		require.False(strings.HasSuffix(info.Name(), ".tmp"), "temporary file left behind: %s", info.Name())
+		found = append(found, info.Name())
+	}
+	require.Equal(names[1:], found)
+}
+
+func TestSnapshotAgent_Prune(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+
+	agent, err := newSnapshotAgent(&config.SnapshotAgentConfig{
+		Enabled:   helper.BoolToPtr(true),
+		Interval:  time.Hour,
+		LocalPath: dir,
+	}, testlog.HCLogger(t))
+	require.NoError(err)
+
+	// Files not created by the agent are ignored
+	for _, name := range []string{
+		snapshotAgentFilePrefix + "1" + snapshotAgentFileSuffix,
+		snapshotAgentFilePrefix + "2" + snapshotAgentFileSuffix,
+		"other",
+	} {
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte("x"), 0600))
+	}
+
+	require.NoError(agent.prune(agent.destinations[0]))
+	names, err := agent.destinations[0].List()
+	require.NoError(err)
+	require.Len(names, 3)
+
+	agent.config.Retain = helper.IntToPtr(1)
+	require.NoError(agent.prune(agent.destinations[0]))
+	names, err = agent.destinations[0].List()
+	require.NoError(err)
+	require.Equal([]string{snapshotAgentFilePrefix + "2" + snapshotAgentFileSuffix, "other"}, names)
+}
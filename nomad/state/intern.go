@@ -0,0 +1,159 @@
+package state
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// maxInternedStrings bounds the number of distinct strings interned by a
+	// state store per generation. Once reached, the table is rotated.
+	maxInternedStrings = 1 << 16
+)
+
+// stringInterner deduplicates the strings repeated across the objects of the
+// state store, such as the datacenters and attributes of the nodes, so that a
+// single copy of each is kept in memory. Strings that identify a single object
+// shouldn't be interned, as they would only grow the table.
+//
+// The table is pruned by generations: once it's full it becomes the previous
+// generation, whose strings are moved back to the table as they are interned
+// again. The strings that weren't interned for a whole generation, such as
+// the ones of deleted nodes and jobs, are then released.
+type stringInterner struct {
+	strings  map[string]string
+	previous map[string]string
+	l        sync.Mutex
+}
+
+// newStringInterner returns an empty string interner.
+func newStringInterner() *stringInterner {
+	return &stringInterner{
+		strings: make(map[string]string),
+	}
+}
+
+// intern returns the interned copy of the string.
+func (i *stringInterner) intern(s string) string {
+	if i == nil || s == "" {
+		return s
+	}
+
+	i.l.Lock()
+	defer i.l.Unlock()
+	if interned, ok := i.strings[s]; ok {
+		return interned
+	}
+	if len(i.strings) >= maxInternedStrings {
+		i.previous = i.strings
+		i.strings = make(map[string]string)
+	}
+	if interned, ok := i.previous[s]; ok {
+		s = interned
+	}
+	i.strings[s] = s
+	return s
+}
+
+// internSlice returns a copy of the slice with its strings interned.
+func (i *stringInterner) internSlice(s []string) []string {
+	if i == nil || len(s) == 0 {
+		return s
+	}
+
+	interned := make([]string, len(s))
+	for j, v := range s {
+		interned[j] = i.intern(v)
+	}
+	return interned
+}
+
+// internMap returns a copy of the map with its keys interned. The values are
+// interned too unless the key is under the unique namespace, as their values
+// differ for every node.
+func (i *stringInterner) internMap(m map[string]string) map[string]string {
+	if i == nil || len(m) == 0 {
+		return m
+	}
+
+	interned := make(map[string]string, len(m))
+	for k, v := range m {
+		if !structs.IsUniqueNamespace(k) {
+			v = i.intern(v)
+		}
+		interned[i.intern(k)] = v
+	}
+	return interned
+}
+
+// internNode interns the strings the node shares with the other nodes of the
+// cluster.
+func (i *stringInterner) internNode(node *structs.Node) {
+	if i == nil || node == nil {
+		return
+	}
+
+	node.Datacenter = i.intern(node.Datacenter)
+	node.NodeClass = i.intern(node.NodeClass)
+	node.NodePool = i.intern(node.NodePool)
+	node.ComputedClass = i.intern(node.ComputedClass)
+	node.Attributes = i.internMap(node.Attributes)
+	node.Meta = i.internMap(node.Meta)
+
+	if len(node.Drivers) != 0 {
+		drivers := make(map[string]*structs.DriverInfo, len(node.Drivers))
+		for name, info := range node.Drivers {
+			if info != nil {
+				info = info.Copy()
+				info.Attributes = i.internMap(info.Attributes)
+				info.HealthDescription = i.intern(info.HealthDescription)
+			}
+			drivers[i.intern(name)] = info
+		}
+		node.Drivers = drivers
+	}
+}
+
+// internJob interns the strings the job shares with the other jobs of the
+// cluster, such as the many dispatched children of a parameterized job.
+func (i *stringInterner) internJob(job *structs.Job) {
+	if i == nil || job == nil {
+		return
+	}
+
+	job.Region = i.intern(job.Region)
+	job.Namespace = i.intern(job.Namespace)
+	job.ParentID = i.intern(job.ParentID)
+	job.Type = i.intern(job.Type)
+	job.NodePool = i.intern(job.NodePool)
+	job.Datacenters = i.internSlice(job.Datacenters)
+}
+
+// shareTaskGroups replaces the task groups of the job with the equal task
+// groups of the other jobs, such as its previous version or its parent, so
+// that the versions of a job and the many dispatched children of a
+// parameterized job share a single copy of their task groups. Objects of the
+// state store are never modified in place, so they can safely share them.
+func shareTaskGroups(job *structs.Job, others ...*structs.Job) {
+	if job == nil {
+		return
+	}
+
+	for j, tg := range job.TaskGroups {
+		for _, other := range others {
+			if other == nil {
+				continue
+			}
+			otherTG := other.LookupTaskGroup(tg.Name)
+			if otherTG == tg {
+				break
+			}
+			if otherTG != nil && reflect.DeepEqual(tg, otherTG) {
+				job.TaskGroups[j] = otherTG
+				break
+			}
+		}
+	}
+}
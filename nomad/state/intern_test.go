@@ -0,0 +1,191 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringInterner(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	i := newStringInterner()
+	require.Equal("", i.intern(""))
+	require.Empty(i.strings)
+
+	m := map[string]string{
+		"kernel.name":        "linux",
+		"unique.hostname":    "node-1",
+		"unique.network.ip":  "10.0.0.1",
+		"driver.docker":      "1",
+		"driver.docker.info": "1",
+	}
+	out := i.internMap(m)
+	require.Equal(m, out)
+
+	// The values of unique keys aren't interned
+	require.Contains(i.strings, "unique.hostname")
+	require.Contains(i.strings, "linux")
+	require.NotContains(i.strings, "node-1")
+	require.NotContains(i.strings, "10.0.0.1")
+
+	// The table is rotated once full
+	i = newStringInterner()
+	for j := 0; j < maxInternedStrings+10; j++ {
+		s := fmt.Sprintf("s%d", j)
+		require.Equal(s, i.intern(s))
+	}
+	require.Len(i.strings, 10)
+	require.Len(i.previous, maxInternedStrings)
+
+	// Strings of the previous generation are kept once interned again, while
+	// the others are released on the next rotation
+	require.Equal("s0", i.intern("s0"))
+	require.Contains(i.strings, "s0")
+	for j := 0; j < maxInternedStrings-11; j++ {
+		i.intern(fmt.Sprintf("t%d", j))
+	}
+	require.Len(i.strings, maxInternedStrings)
+	i.intern("u")
+	require.Contains(i.previous, "s0")
+	require.NotContains(i.previous, "s1")
+	require.Len(i.strings, 1)
+
+	// A nil interner returns the strings as is
+	var nilInterner *stringInterner
+	require.Equal("foo", nilInterner.intern("foo"))
+	require.Equal(m, nilInterner.internMap(m))
+}
+
+func TestStateStore_UpsertNode_Interned(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	node := mock.Node()
+	node.Attributes["unique.hostname"] = "node-1"
+	expected := node.Copy()
+	require.NoError(state.UpsertNode(1000, node))
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal(expected.Datacenter, out.Datacenter)
+	require.Equal(expected.Attributes, out.Attributes)
+	require.Equal(expected.Drivers, out.Drivers)
+
+	require.Contains(state.interner.strings, node.Datacenter)
+	require.Contains(state.interner.strings, node.ComputedClass)
+	require.Contains(state.interner.strings, "kernel.name")
+	require.NotContains(state.interner.strings, "node-1")
+}
+
+func TestStateStore_UpsertJob_Interned(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	parent := mock.Job()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(state.UpsertJob(1000, parent))
+
+	child := mock.Job()
+	child.ParentID = parent.ID
+	child.Dispatched = true
+	child.Datacenters = []string{"dc-west"}
+	require.NoError(state.UpsertJob(1001, child))
+
+	out, err := state.JobByID(nil, child.Namespace, child.ID)
+	require.NoError(err)
+	require.Equal([]string{"dc-west"}, out.Datacenters)
+
+	require.Contains(state.interner.strings, parent.ID)
+	require.Contains(state.interner.strings, "dc-west")
+	require.NotContains(state.interner.strings, child.ID)
+}
+
+func TestStateStore_UpsertJob_SharedTaskGroups(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	parent := mock.Job()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(state.UpsertJob(1000, parent))
+	parent, err := state.JobByID(nil, parent.Namespace, parent.ID)
+	require.NoError(err)
+
+	// A dispatched child shares the task groups of its parent
+	child := parent.Copy()
+	child.ID = structs.DispatchedID(parent.ID, time.Now())
+	child.ParentID = parent.ID
+	child.ParameterizedJob = nil
+	child.Dispatched = true
+	require.NoError(state.UpsertJob(1001, child))
+
+	out, err := state.JobByID(nil, child.Namespace, child.ID)
+	require.NoError(err)
+	require.True(out.TaskGroups[0] == parent.TaskGroups[0])
+
+	// A new version shares the task groups left unchanged
+	update := parent.Copy()
+	update.Meta = map[string]string{"version": "1"}
+	require.NoError(state.UpsertJob(1002, update))
+
+	out, err = state.JobByID(nil, parent.Namespace, parent.ID)
+	require.NoError(err)
+	require.Equal(uint64(1), out.Version)
+	require.True(out.TaskGroups[0] == parent.TaskGroups[0])
+
+	// Changed task groups aren't shared
+	update = parent.Copy()
+	update.TaskGroups[0].Count++
+	require.NoError(state.UpsertJob(1003, update))
+
+	out, err = state.JobByID(nil, parent.Namespace, parent.ID)
+	require.NoError(err)
+	require.Equal(parent.TaskGroups[0].Count+1, out.TaskGroups[0].Count)
+	require.False(out.TaskGroups[0] == parent.TaskGroups[0])
+}
+
+func TestStateStore_RestoreJob_SharedTaskGroups(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	parent := mock.Job()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	child := parent.Copy()
+	child.ID = structs.DispatchedID(parent.ID, time.Now())
+	child.ParentID = parent.ID
+	child.ParameterizedJob = nil
+	child.Dispatched = true
+
+	// The child is restored before its parent, and the versions are decoded
+	// copies of the jobs
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.JobRestore(child))
+	require.NoError(restore.JobRestore(parent))
+	require.NoError(restore.JobVersionRestore(child.Copy()))
+	require.NoError(restore.JobVersionRestore(parent.Copy()))
+	restore.Commit()
+
+	out, err := state.JobByID(nil, child.Namespace, child.ID)
+	require.NoError(err)
+	require.True(out.TaskGroups[0] == parent.TaskGroups[0])
+
+	versions, err := state.JobVersionsByID(nil, child.Namespace, child.ID)
+	require.NoError(err)
+	require.Len(versions, 1)
+	require.True(versions[0].TaskGroups[0] == parent.TaskGroups[0])
+
+	versions, err = state.JobVersionsByID(nil, parent.Namespace, parent.ID)
+	require.NoError(err)
+	require.Len(versions, 1)
+	require.True(versions[0].TaskGroups[0] == parent.TaskGroups[0])
+}
@@ -0,0 +1,134 @@
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobSubmissionTableSchema returns the MemDB schema for the job submissions
+// table. This table is used to store the job files of the tracked job
+// versions
+func jobSubmissionTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "job_submission",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID,
+				// Version) is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "JobID",
+							Lowercase: true,
+						},
+
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// UpsertJobSubmission is used to store the submission of a job version. The
+// submission is dropped if the version is no longer tracked.
+func (s *StateStore) UpsertJobSubmission(index uint64, sub *structs.JobSubmission) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// COMPAT 0.7: Upgrade old objects that do not have namespaces
+	if sub.Namespace == "" {
+		sub.Namespace = structs.DefaultNamespace
+	}
+
+	version, err := txn.First("job_version", "id", sub.Namespace, sub.JobID, sub.Version)
+	if err != nil {
+		return fmt.Errorf("job version lookup failed: %v", err)
+	}
+	if version == nil {
+		return nil
+	}
+
+	if err := txn.Insert("job_submission", sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"job_submission", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// deleteJobSubmissionTxn deletes the submission of a job version, if any.
+func (s *StateStore) deleteJobSubmissionTxn(index uint64, namespace, jobID string, version uint64, txn *memdb.Txn) error {
+	existing, err := txn.First("job_submission", "id", namespace, jobID, version)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	if existing == nil {
+		return nil
+	}
+
+	if err := txn.Delete("job_submission", existing); err != nil {
+		return fmt.Errorf("deleting job submission failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"job_submission", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmission returns the submission of a job version, or nil if it was
+// registered without one.
+func (s *StateStore) JobSubmission(ws memdb.WatchSet, namespace, jobID string, version uint64) (*structs.JobSubmission, error) {
+	txn := s.db.Txn(false)
+
+	// COMPAT 0.7: Upgrade old objects that do not have namespaces
+	if namespace == "" {
+		namespace = structs.DefaultNamespace
+	}
+
+	watchCh, existing, err := txn.FirstWatch("job_submission", "id", namespace, jobID, version)
+	if err != nil {
+		return nil, fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobSubmission), nil
+	}
+	return nil, nil
+}
+
+// JobSubmissions returns an iterator over all the job submissions
+func (s *StateStore) JobSubmissions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("job_submission", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobSubmissionRestore is used to restore a job submission
+func (r *StateRestore) JobSubmissionRestore(sub *structs.JobSubmission) error {
+	if err := r.txn.Insert("job_submission", sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertJobSubmission(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(1000, job))
+
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Variables: `count = 2`,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+		Version:   job.Version,
+	}
+	require.NoError(t, state.UpsertJobSubmission(1001, sub))
+
+	out, err := state.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(t, err)
+	require.Equal(t, sub, out)
+
+	index, err := state.Index("job_submission")
+	require.NoError(t, err)
+	require.EqualValues(t, 1001, index)
+
+	// Submissions of untracked versions are dropped
+	unknown := sub.Copy()
+	unknown.Version = 5
+	require.NoError(t, state.UpsertJobSubmission(1002, unknown))
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 5)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestStateStore_JobSubmission_PurgeOldVersions(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	job := mock.Job()
+	for i := 0; i <= structs.JobTrackedVersions; i++ {
+		next := mock.Job()
+		next.ID = job.ID
+		next.Priority = i + 1
+		index := uint64(1000 + 2*i)
+		require.NoError(t, state.UpsertJob(index, next))
+		require.NoError(t, state.UpsertJobSubmission(index+1, &structs.JobSubmission{
+			Source:    "source",
+			Namespace: next.Namespace,
+			JobID:     next.ID,
+			Version:   next.Version,
+		}))
+	}
+
+	// The submission of the purged version is deleted with it
+	out, err := state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 1)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Deleting the job deletes the remaining submissions
+	require.NoError(t, state.DeleteJob(2000, job.Namespace, job.ID))
+
+	iter, err := state.JobSubmissions(nil)
+	require.NoError(t, err)
+	require.Nil(t, iter.Next())
+
+	index, err := state.Index("job_submission")
+	require.NoError(t, err)
+	require.EqualValues(t, 2000, index)
+}
@@ -0,0 +1,217 @@
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// nodePoolTableSchema returns the MemDB schema for the node pools table.
+// This table is used to store the node pools nodes belong to and jobs are
+// placed in
+func nodePoolTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "node_pools",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// upsertBuiltInNodePools creates the built-in node pools when the state
+// store is created. Restoring a snapshot replaces them with the pools of the
+// snapshot.
+func (s *StateStore) upsertBuiltInNodePools() error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	pools := []*structs.NodePool{
+		{
+			Name:        structs.NodePoolAll,
+			Description: "Node pool with all the nodes of the region.",
+		},
+		{
+			Name:        structs.NodePoolDefault,
+			Description: "Default node pool.",
+		},
+	}
+	for _, pool := range pools {
+		if err := s.upsertNodePoolTxn(txn, 1, pool); err != nil {
+			return err
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"node_pools", 1}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// UpsertNodePools is used to create or update a set of node pools
+func (s *StateStore) UpsertNodePools(index uint64, pools []*structs.NodePool) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, pool := range pools {
+		if err := s.upsertNodePoolTxn(txn, index, pool); err != nil {
+			return err
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"node_pools", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// upsertNodePoolTxn is used to create or update a node pool within a
+// transaction
+func (s *StateStore) upsertNodePoolTxn(txn *memdb.Txn, index uint64, pool *structs.NodePool) error {
+	existing, err := txn.First("node_pools", "id", pool.Name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		pool.CreateIndex = existing.(*structs.NodePool).CreateIndex
+		pool.ModifyIndex = index
+	} else {
+		pool.CreateIndex = index
+		pool.ModifyIndex = index
+	}
+
+	if err := txn.Insert("node_pools", pool); err != nil {
+		return fmt.Errorf("upserting node pool failed: %v", err)
+	}
+	return nil
+}
+
+// ensureNodePoolTxn creates the node pool of a registering node if it
+// doesn't exist yet.
+func (s *StateStore) ensureNodePoolTxn(txn *memdb.Txn, index uint64, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	existing, err := txn.First("node_pools", "id", name)
+	if err != nil {
+		return fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	if existing != nil {
+		return nil
+	}
+
+	if err := s.upsertNodePoolTxn(txn, index, &structs.NodePool{Name: name}); err != nil {
+		return err
+	}
+	if err := txn.Insert("index", &IndexEntry{"node_pools", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// DeleteNodePools deletes the node pools with the given names. Built-in node
+// pools and pools that still have nodes or jobs can't be deleted.
+func (s *StateStore) DeleteNodePools(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First("node_pools", "id", name)
+		if err != nil {
+			return fmt.Errorf("node pool lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("node pool %q not found", name)
+		}
+		if existing.(*structs.NodePool).IsBuiltIn() {
+			return fmt.Errorf("built-in node pool %q can't be deleted", name)
+		}
+
+		node, err := txn.First("nodes", "node_pool", name)
+		if err != nil {
+			return fmt.Errorf("node lookup failed: %v", err)
+		}
+		if node != nil {
+			return fmt.Errorf("node pool %q has nodes", name)
+		}
+
+		jobs, err := txn.Get("jobs", "id")
+		if err != nil {
+			return fmt.Errorf("job lookup failed: %v", err)
+		}
+		for raw := jobs.Next(); raw != nil; raw = jobs.Next() {
+			if job := raw.(*structs.Job); job.NodePool == name && !job.Stopped() {
+				return fmt.Errorf("node pool %q has running jobs", name)
+			}
+		}
+
+		if err := txn.Delete("node_pools", existing); err != nil {
+			return fmt.Errorf("deleting node pool failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"node_pools", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// NodePoolByName is used to lookup a node pool by name
+func (s *StateStore) NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("node_pools", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("node pool lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.NodePool), nil
+	}
+	return nil, nil
+}
+
+// NodePools returns an iterator over all the node pools
+func (s *StateStore) NodePools(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("node_pools", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NodesByNodePool returns an iterator over the nodes of a node pool
+func (s *StateStore) NodesByNodePool(ws memdb.WatchSet, pool string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("nodes", "node_pool", pool)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NodePoolRestore is used to restore a node pool
+func (r *StateRestore) NodePoolRestore(pool *structs.NodePool) error {
+	if err := r.txn.Insert("node_pools", pool); err != nil {
+		return fmt.Errorf("inserting node pool failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_NodePools_BuiltIn(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	for _, name := range []string{structs.NodePoolAll, structs.NodePoolDefault} {
+		pool, err := state.NodePoolByName(nil, name)
+		require.NoError(t, err)
+		require.NotNil(t, pool)
+		require.True(t, pool.IsBuiltIn())
+	}
+
+	// Built-in pools can't be deleted
+	err := state.DeleteNodePools(1000, []string{structs.NodePoolDefault})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "built-in")
+}
+
+func TestStateStore_UpsertNodePools(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, state.UpsertNodePools(1000, []*structs.NodePool{pool}))
+
+	out, err := state.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, pool, out)
+	require.EqualValues(t, 1000, out.CreateIndex)
+
+	// Update the pool
+	update := pool.Copy()
+	update.Description = "updated"
+	require.NoError(t, state.UpsertNodePools(1001, []*structs.NodePool{update}))
+
+	out, err = state.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Equal(t, "updated", out.Description)
+	require.EqualValues(t, 1000, out.CreateIndex)
+	require.EqualValues(t, 1001, out.ModifyIndex)
+
+	iter, err := state.NodePools(nil)
+	require.NoError(t, err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 3, count)
+
+	index, err := state.Index("node_pools")
+	require.NoError(t, err)
+	require.EqualValues(t, 1001, index)
+}
+
+func TestStateStore_DeleteNodePools(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	pool := mock.NodePool()
+	require.NoError(t, state.UpsertNodePools(1000, []*structs.NodePool{pool}))
+
+	// Pools with nodes can't be deleted
+	node := mock.Node()
+	node.NodePool = pool.Name
+	require.NoError(t, state.UpsertNode(1001, node))
+	err := state.DeleteNodePools(1002, []string{pool.Name})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has nodes")
+	require.NoError(t, state.DeleteNode(1003, node.ID))
+
+	// Pools with running jobs can't be deleted
+	job := mock.Job()
+	job.NodePool = pool.Name
+	require.NoError(t, state.UpsertJob(1004, job))
+	err = state.DeleteNodePools(1005, []string{pool.Name})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has running jobs")
+
+	stopped := job.Copy()
+	stopped.Stop = true
+	require.NoError(t, state.UpsertJob(1006, stopped))
+	require.NoError(t, state.DeleteNodePools(1007, []string{pool.Name}))
+
+	out, err := state.NodePoolByName(nil, pool.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	// Unknown pools can't be deleted
+	require.Error(t, state.DeleteNodePools(1008, []string{"unknown"}))
+}
+
+func TestStateStore_UpsertNode_NodePool(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	// Registering a node in a new pool creates the pool
+	node := mock.Node()
+	node.NodePool = "gpu"
+	require.NoError(t, state.UpsertNode(1000, node))
+
+	pool, err := state.NodePoolByName(nil, "gpu")
+	require.NoError(t, err)
+	require.NotNil(t, pool)
+	require.EqualValues(t, 1000, pool.CreateIndex)
+
+	iter, err := state.NodesByNodePool(nil, "gpu")
+	require.NoError(t, err)
+	raw := iter.Next()
+	require.NotNil(t, raw)
+	require.Equal(t, node.ID, raw.(*structs.Node).ID)
+	require.Nil(t, iter.Next())
+}
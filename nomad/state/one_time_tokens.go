@@ -0,0 +1,166 @@
+package state
+
+import (
+	"fmt"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// oneTimeTokenTableSchema returns the MemDB schema for the one-time tokens
+// table. This table is used to store the short-lived secrets that can be
+// exchanged once for an ACL token
+func oneTimeTokenTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "one_time_token",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "OneTimeSecretID",
+				},
+			},
+		},
+	}
+}
+
+// UpsertOneTimeToken is used to create or update a one-time token
+func (s *StateStore) UpsertOneTimeToken(index uint64, token *structs.OneTimeToken) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("one_time_token", "id", token.OneTimeSecretID)
+	if err != nil {
+		return fmt.Errorf("one-time token lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		token.CreateIndex = existing.(*structs.OneTimeToken).CreateIndex
+		token.ModifyIndex = index
+	} else {
+		token.CreateIndex = index
+		token.ModifyIndex = index
+	}
+
+	if err := txn.Insert("one_time_token", token); err != nil {
+		return fmt.Errorf("upserting one-time token failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"one_time_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// ExchangeOneTimeToken deletes the one-time token with the given secret ID
+// and returns the ACL token it was created for. The lookup and the delete are
+// done in a single transaction so that a one-time token is only ever
+// exchanged once. ErrOneTimeTokenNotFound is returned if the one-time token
+// doesn't exist or is expired at the given time, and ErrTokenNotFound if its
+// ACL token was deleted since.
+func (s *StateStore) ExchangeOneTimeToken(index uint64, secretID string, now time.Time) (*structs.ACLToken, error) {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	raw, err := txn.First("one_time_token", "id", secretID)
+	if err != nil {
+		return nil, fmt.Errorf("one-time token lookup failed: %v", err)
+	}
+	if raw == nil {
+		return nil, structs.ErrOneTimeTokenNotFound
+	}
+	ott := raw.(*structs.OneTimeToken)
+	if ott.IsExpired(now) {
+		return nil, structs.ErrOneTimeTokenNotFound
+	}
+
+	raw, err = txn.First("acl_token", "id", ott.AccessorID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	if raw == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	if err := txn.Delete("one_time_token", ott); err != nil {
+		return nil, fmt.Errorf("deleting one-time token failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"one_time_token", index}); err != nil {
+		return nil, fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return raw.(*structs.ACLToken), nil
+}
+
+// ExpireOneTimeTokens deletes the one-time tokens expired at the given time
+func (s *StateStore) ExpireOneTimeTokens(index uint64, now time.Time) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	iter, err := txn.Get("one_time_token", "id")
+	if err != nil {
+		return fmt.Errorf("one-time token lookup failed: %v", err)
+	}
+
+	// Collect the expired tokens first, as the table can't be modified while
+	// iterating over it
+	var expired []*structs.OneTimeToken
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if token := raw.(*structs.OneTimeToken); token.IsExpired(now) {
+			expired = append(expired, token)
+		}
+	}
+
+	for _, token := range expired {
+		if err := txn.Delete("one_time_token", token); err != nil {
+			return fmt.Errorf("deleting one-time token failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"one_time_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// OneTimeTokenBySecret is used to lookup a one-time token by its secret ID
+func (s *StateStore) OneTimeTokenBySecret(ws memdb.WatchSet, secretID string) (*structs.OneTimeToken, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("one_time_token", "id", secretID)
+	if err != nil {
+		return nil, fmt.Errorf("one-time token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.OneTimeToken), nil
+	}
+	return nil, nil
+}
+
+// OneTimeTokens returns an iterator over all the one-time tokens
+func (s *StateStore) OneTimeTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("one_time_token", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// OneTimeTokenRestore is used to restore a one-time token
+func (r *StateRestore) OneTimeTokenRestore(token *structs.OneTimeToken) error {
+	if err := r.txn.Insert("one_time_token", token); err != nil {
+		return fmt.Errorf("inserting one-time token failed: %v", err)
+	}
+	return nil
+}
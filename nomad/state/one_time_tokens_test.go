@@ -0,0 +1,95 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_OneTimeTokens(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	now := time.Now().UTC()
+	t1 := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      uuid.Generate(),
+		ExpiresAt:       now.Add(time.Minute),
+	}
+	t2 := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      uuid.Generate(),
+		ExpiresAt:       now.Add(-time.Minute),
+	}
+	require.NoError(t, state.UpsertOneTimeToken(1000, t1))
+	require.NoError(t, state.UpsertOneTimeToken(1001, t2))
+
+	out, err := state.OneTimeTokenBySecret(nil, t1.OneTimeSecretID)
+	require.NoError(t, err)
+	require.Equal(t, t1, out)
+	require.EqualValues(t, 1000, out.CreateIndex)
+
+	// Expire the tokens, only the second one is expired
+	require.NoError(t, state.ExpireOneTimeTokens(1002, now))
+
+	out, err = state.OneTimeTokenBySecret(nil, t2.OneTimeSecretID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	iter, err := state.OneTimeTokens(nil)
+	require.NoError(t, err)
+	require.NotNil(t, iter.Next())
+	require.Nil(t, iter.Next())
+
+	index, err := state.Index("one_time_token")
+	require.NoError(t, err)
+	require.EqualValues(t, 1002, index)
+}
+
+func TestStateStore_ExchangeOneTimeToken(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	token := mock.ACLToken()
+	require.NoError(t, state.UpsertACLTokens(1000, []*structs.ACLToken{token}))
+
+	now := time.Now().UTC()
+	ott := &structs.OneTimeToken{
+		OneTimeSecretID: uuid.Generate(),
+		AccessorID:      token.AccessorID,
+		ExpiresAt:       now.Add(time.Minute),
+	}
+	require.NoError(t, state.UpsertOneTimeToken(1001, ott))
+
+	// Expired one-time tokens can't be exchanged
+	_, err := state.ExchangeOneTimeToken(1002, ott.OneTimeSecretID, now.Add(time.Hour))
+	require.Equal(t, structs.ErrOneTimeTokenNotFound, err)
+
+	// The exchange returns the ACL token and deletes the one-time token
+	out, err := state.ExchangeOneTimeToken(1003, ott.OneTimeSecretID, now)
+	require.NoError(t, err)
+	require.Equal(t, token.SecretID, out.SecretID)
+
+	existing, err := state.OneTimeTokenBySecret(nil, ott.OneTimeSecretID)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	index, err := state.Index("one_time_token")
+	require.NoError(t, err)
+	require.EqualValues(t, 1003, index)
+
+	// It can only be exchanged once
+	_, err = state.ExchangeOneTimeToken(1004, ott.OneTimeSecretID, now)
+	require.Equal(t, structs.ErrOneTimeTokenNotFound, err)
+
+	// One-time tokens whose ACL token was deleted can't be exchanged
+	ott.OneTimeSecretID = uuid.Generate()
+	ott.AccessorID = uuid.Generate()
+	require.NoError(t, state.UpsertOneTimeToken(1005, ott))
+	_, err = state.ExchangeOneTimeToken(1006, ott.OneTimeSecretID, now)
+	require.Equal(t, structs.ErrTokenNotFound, err)
+}
@@ -62,6 +62,9 @@ type StateStore struct {
 	// abandonCh is used to signal watchers that this state store has been
 	// abandoned (usually during a restore). This is only ever closed.
 	abandonCh chan struct{}
+
+	// interner deduplicates the strings repeated across nodes and jobs
+	interner *stringInterner
 }
 
 // NewStateStore is used to create a new state store
@@ -78,6 +81,12 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		db:        db,
 		config:    config,
 		abandonCh: make(chan struct{}),
+		interner:  newStringInterner(),
+	}
+
+	// Create the built-in node pools
+	if err := s.upsertBuiltInNodePools(); err != nil {
+		return nil, fmt.Errorf("node pool setup failed: %v", err)
 	}
 	return s, nil
 }
@@ -107,7 +116,8 @@ func (s *StateStore) Snapshot() (*StateSnapshot, error) {
 func (s *StateStore) Restore() (*StateRestore, error) {
 	txn := s.db.Txn(true)
 	r := &StateRestore{
-		txn: txn,
+		txn:      txn,
+		interner: s.interner,
 	}
 	return r, nil
 }
@@ -595,6 +605,7 @@ func (s *StateStore) UpsertNode(index uint64, node *structs.Node) error {
 
 		node.Drain = exist.Drain                                 // Retain the drain mode
 		node.SchedulingEligibility = exist.SchedulingEligibility // Retain the eligibility
+		node.IneligibleUntil = exist.IneligibleUntil             // Retain the eligibility expiry
 		node.DrainStrategy = exist.DrainStrategy                 // Retain the drain strategy
 	} else {
 		// Because this is the first time the node is being registered, we should
@@ -607,7 +618,13 @@ func (s *StateStore) UpsertNode(index uint64, node *structs.Node) error {
 		node.ModifyIndex = index
 	}
 
+	// Create the node pool of the node if it is new
+	if err := s.ensureNodePoolTxn(txn, index, node.NodePool); err != nil {
+		return err
+	}
+
 	// Insert the node
+	s.interner.internNode(node)
 	if err := txn.Insert("nodes", node); err != nil {
 		return fmt.Errorf("node insert failed: %v", err)
 	}
@@ -646,7 +663,7 @@ func (s *StateStore) DeleteNode(index uint64, nodeID string) error {
 }
 
 // UpdateNodeStatus is used to update the status of a node
-func (s *StateStore) UpdateNodeStatus(index uint64, nodeID, status string, event *structs.NodeEvent) error {
+func (s *StateStore) UpdateNodeStatus(index uint64, nodeID, status string, updatedAt int64, event *structs.NodeEvent) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
@@ -672,6 +689,12 @@ func (s *StateStore) UpdateNodeStatus(index uint64, nodeID, status string, event
 	copyNode.Status = status
 	copyNode.ModifyIndex = index
 
+	// COMPAT: Nomad versions before 0.9 did not include the time of the
+	// status update
+	if updatedAt != 0 {
+		copyNode.StatusUpdatedAt = updatedAt
+	}
+
 	// Insert the node
 	if err := txn.Insert("nodes", copyNode); err != nil {
 		return fmt.Errorf("node update failed: %v", err)
@@ -736,8 +759,10 @@ func (s *StateStore) updateNodeDrainImpl(txn *memdb.Txn, index uint64, nodeID st
 	copyNode.DrainStrategy = drain
 	if drain != nil {
 		copyNode.SchedulingEligibility = structs.NodeSchedulingIneligible
+		copyNode.IneligibleUntil = time.Time{}
 	} else if markEligible {
 		copyNode.SchedulingEligibility = structs.NodeSchedulingEligible
+		copyNode.IneligibleUntil = time.Time{}
 	}
 
 	copyNode.ModifyIndex = index
@@ -753,8 +778,11 @@ func (s *StateStore) updateNodeDrainImpl(txn *memdb.Txn, index uint64, nodeID st
 	return nil
 }
 
-// UpdateNodeEligibility is used to update the scheduling eligibility of a node
-func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibility string, event *structs.NodeEvent) error {
+// UpdateNodeEligibility is used to update the scheduling eligibility of a
+// node. A node marked as ineligible with a non-zero ineligibleUntil is marked
+// as eligible again by the leader at that time.
+func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibility string,
+	ineligibleUntil time.Time, event *structs.NodeEvent) error {
 
 	txn := s.db.Txn(true)
 	defer txn.Abort()
@@ -784,6 +812,10 @@ func (s *StateStore) UpdateNodeEligibility(index uint64, nodeID string, eligibil
 
 	// Update the eligibility in the copy
 	copyNode.SchedulingEligibility = eligibility
+	copyNode.IneligibleUntil = time.Time{}
+	if eligibility == structs.NodeSchedulingIneligible {
+		copyNode.IneligibleUntil = ineligibleUntil
+	}
 	copyNode.ModifyIndex = index
 
 	// Insert the node
@@ -947,6 +979,7 @@ func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion b
 	} else if !exists {
 		return fmt.Errorf("job %q is in nonexistent namespace %q", job.ID, job.Namespace)
 	}
+	s.interner.internJob(job)
 
 	// Check if the job already exists
 	existing, err := txn.First("jobs", "id", job.Namespace, job.ID)
@@ -993,6 +1026,24 @@ func (s *StateStore) upsertJobImpl(index uint64, job *structs.Job, keepVersion b
 		}
 	}
 
+	// Share the task groups left unchanged since the previous version or
+	// copied from the parent job. This is done after setting the status of
+	// new jobs, which copies them
+	var previous, parent *structs.Job
+	if existing != nil {
+		previous = existing.(*structs.Job)
+	}
+	if job.ParentID != "" {
+		raw, err := txn.First("jobs", "id", job.Namespace, job.ParentID)
+		if err != nil {
+			return fmt.Errorf("parent job lookup failed: %v", err)
+		}
+		if raw != nil {
+			parent = raw.(*structs.Job)
+		}
+	}
+	shareTaskGroups(job, previous, parent)
+
 	if err := s.updateSummaryWithJob(index, job, txn); err != nil {
 		return fmt.Errorf("unable to create job summary: %v", err)
 	}
@@ -1151,6 +1202,9 @@ func (s *StateStore) deleteJobVersions(index uint64, job *structs.Job, txn *memd
 		if err := txn.Delete("job_version", j); err != nil {
 			return fmt.Errorf("deleting job versions failed: %v", err)
 		}
+		if err := s.deleteJobSubmissionTxn(index, j.Namespace, j.ID, j.Version, txn); err != nil {
+			return err
+		}
 	}
 
 	if err := txn.Insert("index", &IndexEntry{"job_version", index}); err != nil {
@@ -1183,12 +1237,31 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *memdb
 		return fmt.Errorf("failed to look up job versions for %q: %v", job.ID, err)
 	}
 
-	// If we are below the limit there is no GCing to be done
-	if len(all) <= structs.JobTrackedVersions {
+	// Delete the historic jobs beyond the retention of the job
+	for _, d := range prunedJobVersions(all) {
+		if err := txn.Delete("job_version", d); err != nil {
+			return fmt.Errorf("failed to delete job %v (%d) from job_version", d.ID, d.Version)
+		}
+		if err := s.deleteJobSubmissionTxn(index, d.Namespace, d.ID, d.Version, txn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prunedJobVersions returns the versions of a job, sorted from the latest,
+// beyond the version retention of the latest version. The latest version is
+// always kept, and the latest stable version is kept in place of the oldest
+// other version. Ages are relative to the submit time of the latest version
+// so that all the servers prune the same versions.
+func prunedJobVersions(all []*structs.Job) []*structs.Job {
+	if len(all) == 0 {
 		return nil
 	}
+	latest := all[0]
+	max, maxAge := latest.VersionRetention.Limits()
 
-	// We have to delete a historic job to make room.
 	// Find index of the highest versioned stable job
 	stableIdx := -1
 	for i, j := range all {
@@ -1198,20 +1271,24 @@ func (s *StateStore) upsertJobVersion(index uint64, job *structs.Job, txn *memdb
 		}
 	}
 
-	// If the stable job is the oldest version, do a swap to bring it into the
-	// keep set.
-	max := structs.JobTrackedVersions
-	if stableIdx == max {
-		all[max-1], all[max] = all[max], all[max-1]
+	// If the stable job is beyond the kept versions, it takes the place of
+	// the oldest one.
+	keep := max
+	if stableIdx >= max && max > 1 {
+		keep--
 	}
 
-	// Delete the job outside of the set that are being kept.
-	d := all[max]
-	if err := txn.Delete("job_version", d); err != nil {
-		return fmt.Errorf("failed to delete job %v (%d) from job_version", d.ID, d.Version)
+	var pruned []*structs.Job
+	for i, j := range all {
+		if i == 0 || i == stableIdx && keep < max {
+			continue
+		}
+		expired := maxAge > 0 && latest.SubmitTime-j.SubmitTime > maxAge.Nanoseconds()
+		if i >= keep || expired && i != stableIdx {
+			pruned = append(pruned, j)
+		}
 	}
-
-	return nil
+	return pruned
 }
 
 // JobByID is used to lookup a job by its ID. JobByID returns the current/latest job
@@ -1399,6 +1476,82 @@ func (s *StateStore) jobsByNamespaceImpl(ws memdb.WatchSet, namespace string, tx
 	return iter, nil
 }
 
+// JobsByTag returns an iterator over the jobs of the namespace with the tag.
+func (s *StateStore) JobsByTag(ws memdb.WatchSet, namespace, tag string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("jobs", "tags", tag)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	// Wrap the iterator in a filter
+	wrap := memdb.NewFilterIterator(iter, jobNamespaceFilter(namespace))
+	return wrap, nil
+}
+
+// JobsByConcurrencyGroup returns an iterator over the jobs of the namespace in
+// the concurrency group.
+func (s *StateStore) JobsByConcurrencyGroup(ws memdb.WatchSet, namespace, group string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("jobs", "concurrency_group", group)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	// Wrap the iterator in a filter
+	wrap := memdb.NewFilterIterator(iter, jobNamespaceFilter(namespace))
+	return wrap, nil
+}
+
+// JobsByTagPrefix returns an iterator over the jobs of the namespace with a
+// tag starting with the prefix. Jobs with several matching tags are only
+// returned once.
+func (s *StateStore) JobsByTagPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("jobs", "tags_prefix", prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	// Wrap the iterator in a filter
+	seen := make(map[string]struct{})
+	filter := jobNamespaceFilter(namespace)
+	wrap := memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+		if filter(raw) {
+			return true
+		}
+		job := raw.(*structs.Job)
+		if _, ok := seen[job.ID]; ok {
+			return true
+		}
+		seen[job.ID] = struct{}{}
+		return false
+	})
+	return wrap, nil
+}
+
+// jobNamespaceFilter returns a filter function that filters all jobs not in
+// the given namespace.
+func jobNamespaceFilter(namespace string) func(interface{}) bool {
+	return func(raw interface{}) bool {
+		job, ok := raw.(*structs.Job)
+		if !ok {
+			return true
+		}
+
+		return job.Namespace != namespace
+	}
+}
+
 // JobsByPeriodic returns an iterator over all the periodic or non-periodic jobs.
 func (s *StateStore) JobsByPeriodic(ws memdb.WatchSet, periodic bool) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
@@ -2056,6 +2209,13 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 		return fmt.Errorf("alloc insert failed: %v", err)
 	}
 
+	// Remove the services of terminal allocations
+	if copyAlloc.ClientTerminalStatus() {
+		if err := s.deleteServiceRegistrationsByAllocTxn(index, txn, copyAlloc.ID); err != nil {
+			return err
+		}
+	}
+
 	// Set the job's status
 	forceStatus := ""
 	if !copyAlloc.TerminalStatus() {
@@ -2128,9 +2288,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			// Keep the clients task states
 			alloc.TaskStates = exist.TaskStates
 
-			// If the scheduler is marking this allocation as lost we do not
-			// want to reuse the status of the existing allocation.
-			if alloc.ClientStatus != structs.AllocClientStatusLost {
+			// If the scheduler is marking this allocation as lost or unknown
+			// we do not want to reuse the status of the existing allocation.
+			if alloc.ClientStatus != structs.AllocClientStatusLost &&
+				alloc.ClientStatus != structs.AllocClientStatusUnknown {
 				alloc.ClientStatus = exist.ClientStatus
 				alloc.ClientDescription = exist.ClientDescription
 			}
@@ -2166,6 +2327,13 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return fmt.Errorf("alloc insert failed: %v", err)
 		}
 
+		// Remove the services of terminal allocations
+		if alloc.ClientTerminalStatus() {
+			if err := s.deleteServiceRegistrationsByAllocTxn(index, txn, alloc.ID); err != nil {
+				return err
+			}
+		}
+
 		if alloc.PreviousAllocation != "" {
 			prevAlloc, err := txn.First("allocs", "id", alloc.PreviousAllocation)
 			if err != nil {
@@ -2662,6 +2830,11 @@ func (s *StateStore) updateDeploymentStatusImpl(index uint64, u *structs.Deploym
 	copy.Status = u.Status
 	copy.StatusDescription = u.StatusDescription
 	copy.ModifyIndex = index
+	for group, step := range u.StepAllocs {
+		if dstate, ok := copy.TaskGroups[group]; ok {
+			dstate.StepAllocs = step
+		}
+	}
 
 	// COMPAT 0.7: Upgrade old objects that do not have namespaces
 	if copy.Namespace == "" {
@@ -2735,6 +2908,40 @@ func (s *StateStore) updateJobStabilityImpl(index uint64, namespace, jobID strin
 	return s.upsertJobImpl(index, copy, true, txn)
 }
 
+// UpdateJobMinimumVersion pins the given job to the minimum version. The job
+// version isn't bumped since the spec doesn't change.
+func (s *StateStore) UpdateJobMinimumVersion(index uint64, namespace, jobID string, minVersion uint64) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// COMPAT 0.7: Upgrade old objects that do not have namespaces
+	if namespace == "" {
+		namespace = structs.DefaultNamespace
+	}
+
+	existing, err := txn.First("jobs", "id", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("job %q in namespace %q not found", jobID, namespace)
+	}
+
+	job := existing.(*structs.Job)
+	if job.MinimumVersion == minVersion {
+		return nil
+	}
+
+	copy := job.Copy()
+	copy.MinimumVersion = minVersion
+	if err := s.upsertJobImpl(index, copy, true, txn); err != nil {
+		return err
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // UpdateDeploymentPromotion is used to promote canaries in a deployment and
 // potentially make a evaluation
 func (s *StateStore) UpdateDeploymentPromotion(index uint64, req *structs.ApplyDeploymentPromoteRequest) error {
@@ -3153,7 +3360,9 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			return err
 		}
 
-		// Calculate the summary for the job
+		// Calculate the summary for the job, tracking the last finished
+		// allocation of each task group to restore its exit codes
+		lastFinished := make(map[string]uint64)
 		for {
 			rawAlloc := iterAllocs.Next()
 			if rawAlloc == nil {
@@ -3173,6 +3382,8 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 				tg.Failed += 1
 			case structs.AllocClientStatusLost:
 				tg.Lost += 1
+			case structs.AllocClientStatusUnknown:
+				tg.Unknown += 1
 			case structs.AllocClientStatusComplete:
 				tg.Complete += 1
 			case structs.AllocClientStatusRunning:
@@ -3182,6 +3393,11 @@ func (s *StateStore) ReconcileJobSummaries(index uint64) error {
 			default:
 				s.logger.Error("invalid client status set on allocation", "client_status", alloc.ClientStatus, "alloc_id", alloc.ID)
 			}
+			if job.Type == structs.JobTypeBatch && alloc.RanToCompletion() &&
+				alloc.ModifyIndex >= lastFinished[alloc.TaskGroup] {
+				tg.LastExitCodes = alloc.ExitCodes()
+				lastFinished[alloc.TaskGroup] = alloc.ModifyIndex
+			}
 			summary.Summary[alloc.TaskGroup] = tg
 		}
 
@@ -3556,6 +3772,14 @@ func (s *StateStore) updateDeploymentWithAlloc(index uint64, alloc, existing *st
 		}
 	}
 
+	// Record the progress of the task group. The time comes from the
+	// allocation so that all servers record the same progress.
+	progressTime := time.Unix(0, alloc.ModifyTime)
+	if placed == 0 && !alloc.DeploymentStatus.Timestamp.IsZero() {
+		progressTime = alloc.DeploymentStatus.Timestamp
+	}
+	state.AddProgress(progressTime.UTC(), index)
+
 	// Upsert the deployment
 	if err := s.upsertDeploymentImpl(index, deploymentCopy, txn); err != nil {
 		return err
@@ -3644,6 +3868,8 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			tgSummary.Complete += 1
 		case structs.AllocClientStatusLost:
 			tgSummary.Lost += 1
+		case structs.AllocClientStatusUnknown:
+			tgSummary.Unknown += 1
 		}
 
 		// Decrementing the count of the bin of the last state
@@ -3660,11 +3886,20 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 			if tgSummary.Lost > 0 {
 				tgSummary.Lost -= 1
 			}
+		case structs.AllocClientStatusUnknown:
+			if tgSummary.Unknown > 0 {
+				tgSummary.Unknown -= 1
+			}
 		case structs.AllocClientStatusFailed, structs.AllocClientStatusComplete:
 		default:
 			s.logger.Error("invalid old client status for allocatio",
 				"alloc_id", existingAlloc.ID, "client_status", existingAlloc.ClientStatus)
 		}
+
+		// Record the exit codes of the batch allocations that finished
+		if alloc.Job.Type == structs.JobTypeBatch && alloc.RanToCompletion() {
+			tgSummary.LastExitCodes = alloc.ExitCodes()
+		}
 		summaryChanged = true
 	}
 	jobSummary.Summary[alloc.TaskGroup] = tgSummary
@@ -3690,6 +3925,30 @@ func (s *StateStore) updateSummaryWithAlloc(index uint64, alloc *structs.Allocat
 	return nil
 }
 
+// VariableRestore is used to restore an encrypted variable
+func (r *StateRestore) VariableRestore(variable *structs.VariableEncrypted) error {
+	if err := r.txn.Insert("variables", variable); err != nil {
+		return fmt.Errorf("variable insert failed: %v", err)
+	}
+	return nil
+}
+
+// RootKeyRestore is used to restore a root key
+func (r *StateRestore) RootKeyRestore(key *structs.RootKey) error {
+	if err := r.txn.Insert("root_keys", key); err != nil {
+		return fmt.Errorf("root key insert failed: %v", err)
+	}
+	return nil
+}
+
+// ServiceRegistrationRestore is used to restore a service registration
+func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegistration) error {
+	if err := r.txn.Insert("service_registrations", service); err != nil {
+		return fmt.Errorf("service registration insert failed: %v", err)
+	}
+	return nil
+}
+
 // addEphemeralDiskToTaskGroups adds missing EphemeralDisk objects to TaskGroups
 func (s *StateStore) addEphemeralDiskToTaskGroups(job *structs.Job) {
 	for _, tg := range job.TaskGroups {
@@ -4003,122 +4262,779 @@ func (s *StateStore) BootstrapACLTokens(index, resetIndex uint64, token *structs
 	return nil
 }
 
-// SchedulerConfig is used to get the current Scheduler configuration.
-func (s *StateStore) SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error) {
-	tx := s.db.Txn(false)
-	defer tx.Abort()
+// UpsertACLAuthMethods is used to create or update a set of ACL auth methods
+func (s *StateStore) UpsertACLAuthMethods(index uint64, methods []*structs.ACLAuthMethod) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
 
-	// Get the scheduler config
-	c, err := tx.First("scheduler_config", "id")
-	if err != nil {
-		return 0, nil, fmt.Errorf("failed scheduler config lookup: %s", err)
-	}
+	for _, method := range methods {
+		// Ensure the auth method hash is non-nil. This should be done outside
+		// the state store for performance reasons, but we check here for
+		// defense in depth.
+		if len(method.Hash) == 0 {
+			method.SetHash()
+		}
 
-	config, ok := c.(*structs.SchedulerConfiguration)
-	if !ok {
-		return 0, nil, nil
-	}
+		// Check if the auth method already exists
+		existing, err := txn.First("acl_auth_method", "id", method.Name)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
 
-	return config.ModifyIndex, config, nil
-}
+		// Update all the indexes
+		if existing != nil {
+			method.CreateIndex = existing.(*structs.ACLAuthMethod).CreateIndex
+			method.ModifyIndex = index
+		} else {
+			method.CreateIndex = index
+			method.ModifyIndex = index
+		}
 
-// SchedulerSetConfig is used to set the current Scheduler configuration.
-func (s *StateStore) SchedulerSetConfig(idx uint64, config *structs.SchedulerConfiguration) error {
-	tx := s.db.Txn(true)
-	defer tx.Abort()
+		// Update the auth method
+		if err := txn.Insert("acl_auth_method", method); err != nil {
+			return fmt.Errorf("upserting auth method failed: %v", err)
+		}
+	}
 
-	s.schedulerSetConfigTxn(idx, tx, config)
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_method", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
 
-	tx.Commit()
+	txn.Commit()
 	return nil
 }
 
-// WithWriteTransaction executes the passed function within a write transaction,
-// and returns its result.  If the invocation returns no error, the transaction
-// is committed; otherwise, it's aborted.
-func (s *StateStore) WithWriteTransaction(fn func(Txn) error) error {
-	tx := s.db.Txn(true)
-	defer tx.Abort()
+// DeleteACLAuthMethods deletes the auth methods with the given names
+func (s *StateStore) DeleteACLAuthMethods(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
 
-	err := fn(tx)
-	if err == nil {
-		tx.Commit()
+	// Delete the auth methods and their binding rules
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_auth_method", "id", name); err != nil {
+			return fmt.Errorf("deleting acl auth method failed: %v", err)
+		}
+		if _, err := txn.DeleteAll("acl_binding_rule", "auth_method", name); err != nil {
+			return fmt.Errorf("deleting acl binding rules failed: %v", err)
+		}
 	}
-	return err
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_method", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
 }
 
-// SchedulerCASConfig is used to update the scheduler configuration with a
-// given Raft index. If the CAS index specified is not equal to the last observed index
-// for the config, then the call is a noop.
-func (s *StateStore) SchedulerCASConfig(idx, cidx uint64, config *structs.SchedulerConfiguration) (bool, error) {
-	tx := s.db.Txn(true)
-	defer tx.Abort()
+// ACLAuthMethodByName is used to lookup an auth method by name
+func (s *StateStore) ACLAuthMethodByName(ws memdb.WatchSet, name string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.Txn(false)
 
-	// Check for an existing config
-	existing, err := tx.First("scheduler_config", "id")
+	watchCh, existing, err := txn.FirstWatch("acl_auth_method", "id", name)
 	if err != nil {
-		return false, fmt.Errorf("failed scheduler config lookup: %s", err)
+		return nil, fmt.Errorf("acl auth method lookup failed: %v", err)
 	}
+	ws.Add(watchCh)
 
-	// If the existing index does not match the provided CAS
-	// index arg, then we shouldn't update anything and can safely
-	// return early here.
-	e, ok := existing.(*structs.SchedulerConfiguration)
-	if !ok || (e != nil && e.ModifyIndex != cidx) {
-		return false, nil
+	if existing != nil {
+		return existing.(*structs.ACLAuthMethod), nil
 	}
-
-	s.schedulerSetConfigTxn(idx, tx, config)
-
-	tx.Commit()
-	return true, nil
+	return nil, nil
 }
 
-func (s *StateStore) schedulerSetConfigTxn(idx uint64, tx *memdb.Txn, config *structs.SchedulerConfiguration) error {
-	// Check for an existing config
-	existing, err := tx.First("scheduler_config", "id")
+// ACLAuthMethods returns an iterator over all the acl auth methods
+func (s *StateStore) ACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_auth_method", "id")
 	if err != nil {
-		return fmt.Errorf("failed scheduler config lookup: %s", err)
+		return nil, err
 	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
 
-	// Set the indexes.
-	if existing != nil {
-		config.CreateIndex = existing.(*structs.SchedulerConfiguration).CreateIndex
-	} else {
-		config.CreateIndex = idx
+// DefaultACLAuthMethod returns the auth method marked as the default, or nil
+// if there is none
+func (s *StateStore) DefaultACLAuthMethod(ws memdb.WatchSet) (*structs.ACLAuthMethod, error) {
+	iter, err := s.ACLAuthMethods(ws)
+	if err != nil {
+		return nil, err
 	}
-	config.ModifyIndex = idx
-
-	if err := tx.Insert("scheduler_config", config); err != nil {
-		return fmt.Errorf("failed updating scheduler config: %s", err)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if method := raw.(*structs.ACLAuthMethod); method.Default {
+			return method, nil
+		}
 	}
-	return nil
+	return nil, nil
 }
 
-// StateSnapshot is used to provide a point-in-time snapshot
-type StateSnapshot struct {
-	StateStore
-}
+// UpsertACLRoles is used to create or update a set of ACL roles
+func (s *StateStore) UpsertACLRoles(index uint64, roles []*structs.ACLRole) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
 
-// StateRestore is used to optimize the performance when
-// restoring state by only using a single large transaction
-// instead of thousands of sub transactions
-type StateRestore struct {
-	txn *memdb.Txn
-}
+	for _, role := range roles {
+		// Ensure the role hash is non-nil. This should be done outside the
+		// state store for performance reasons, but we check here for defense
+		// in depth.
+		if len(role.Hash) == 0 {
+			role.SetHash()
+		}
 
-// Abort is used to abort the restore operation
+		// Check if the role already exists
+		existing, err := txn.First("acl_role", "id", role.Name)
+		if err != nil {
+			return fmt.Errorf("role lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			role.CreateIndex = existing.(*structs.ACLRole).CreateIndex
+			role.ModifyIndex = index
+		} else {
+			role.CreateIndex = index
+			role.ModifyIndex = index
+		}
+
+		// Update the role
+		if err := txn.Insert("acl_role", role); err != nil {
+			return fmt.Errorf("upserting role failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_role", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLRoles deletes the roles with the given names
+func (s *StateStore) DeleteACLRoles(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Delete the roles
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_role", "id", name); err != nil {
+			return fmt.Errorf("deleting acl role failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_role", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLRoleByName is used to lookup a role by name
+func (s *StateStore) ACLRoleByName(ws memdb.WatchSet, name string) (*structs.ACLRole, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_role", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLRole), nil
+	}
+	return nil, nil
+}
+
+// ACLRoleByNamePrefix is used to lookup roles by name prefix
+func (s *StateStore) ACLRoleByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_role", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ACLRoles returns an iterator over all the acl roles
+func (s *StateStore) ACLRoles(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_role", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertACLBindingRules is used to create or update a set of ACL binding
+// rules
+func (s *StateStore) UpsertACLBindingRules(index uint64, rules []*structs.ACLBindingRule) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, rule := range rules {
+		// Ensure the binding rule hash is non-nil. This should be done
+		// outside the state store for performance reasons, but we check here
+		// for defense in depth.
+		if len(rule.Hash) == 0 {
+			rule.SetHash()
+		}
+
+		// Check if the binding rule already exists
+		existing, err := txn.First("acl_binding_rule", "id", rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			rule.CreateIndex = existing.(*structs.ACLBindingRule).CreateIndex
+			rule.ModifyIndex = index
+		} else {
+			rule.CreateIndex = index
+			rule.ModifyIndex = index
+		}
+
+		// Update the binding rule
+		if err := txn.Insert("acl_binding_rule", rule); err != nil {
+			return fmt.Errorf("upserting binding rule failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLBindingRules deletes the binding rules with the given IDs
+func (s *StateStore) DeleteACLBindingRules(index uint64, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Delete the binding rules
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_binding_rule", "id", id); err != nil {
+			return fmt.Errorf("deleting acl binding rule failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLBindingRuleByID is used to lookup a binding rule by ID
+func (s *StateStore) ACLBindingRuleByID(ws memdb.WatchSet, id string) (*structs.ACLBindingRule, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_binding_rule", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("acl binding rule lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLBindingRule), nil
+	}
+	return nil, nil
+}
+
+// ACLBindingRules returns an iterator over all the acl binding rules
+func (s *StateStore) ACLBindingRules(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_binding_rule", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLBindingRulesByAuthMethod returns an iterator over the binding rules of
+// an auth method
+func (s *StateStore) ACLBindingRulesByAuthMethod(ws memdb.WatchSet, method string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_binding_rule", "auth_method", method)
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// SchedulerConfig is used to get the current Scheduler configuration.
+func (s *StateStore) SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error) {
+	tx := s.db.Txn(false)
+	defer tx.Abort()
+
+	// Get the scheduler config
+	c, err := tx.First("scheduler_config", "id")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed scheduler config lookup: %s", err)
+	}
+
+	config, ok := c.(*structs.SchedulerConfiguration)
+	if !ok {
+		return 0, nil, nil
+	}
+
+	return config.ModifyIndex, config, nil
+}
+
+// SchedulerSetConfig is used to set the current Scheduler configuration.
+func (s *StateStore) SchedulerSetConfig(idx uint64, config *structs.SchedulerConfiguration) error {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	s.schedulerSetConfigTxn(idx, tx, config)
+
+	tx.Commit()
+	return nil
+}
+
+// WithWriteTransaction executes the passed function within a write transaction,
+// and returns its result.  If the invocation returns no error, the transaction
+// is committed; otherwise, it's aborted.
+func (s *StateStore) WithWriteTransaction(fn func(Txn) error) error {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	err := fn(tx)
+	if err == nil {
+		tx.Commit()
+	}
+	return err
+}
+
+// SchedulerCASConfig is used to update the scheduler configuration with a
+// given Raft index. If the CAS index specified is not equal to the last observed index
+// for the config, then the call is a noop.
+func (s *StateStore) SchedulerCASConfig(idx, cidx uint64, config *structs.SchedulerConfiguration) (bool, error) {
+	tx := s.db.Txn(true)
+	defer tx.Abort()
+
+	// Check for an existing config
+	existing, err := tx.First("scheduler_config", "id")
+	if err != nil {
+		return false, fmt.Errorf("failed scheduler config lookup: %s", err)
+	}
+
+	// If the existing index does not match the provided CAS
+	// index arg, then we shouldn't update anything and can safely
+	// return early here.
+	e, ok := existing.(*structs.SchedulerConfiguration)
+	if !ok || (e != nil && e.ModifyIndex != cidx) {
+		return false, nil
+	}
+
+	s.schedulerSetConfigTxn(idx, tx, config)
+
+	tx.Commit()
+	return true, nil
+}
+
+func (s *StateStore) schedulerSetConfigTxn(idx uint64, tx *memdb.Txn, config *structs.SchedulerConfiguration) error {
+	// Check for an existing config
+	existing, err := tx.First("scheduler_config", "id")
+	if err != nil {
+		return fmt.Errorf("failed scheduler config lookup: %s", err)
+	}
+
+	// Set the indexes.
+	if existing != nil {
+		config.CreateIndex = existing.(*structs.SchedulerConfiguration).CreateIndex
+	} else {
+		config.CreateIndex = idx
+	}
+	config.ModifyIndex = idx
+
+	if err := tx.Insert("scheduler_config", config); err != nil {
+		return fmt.Errorf("failed updating scheduler config: %s", err)
+	}
+	return nil
+}
+
+// UpsertVariable is used to create or update an encrypted variable
+func (s *StateStore) UpsertVariable(index uint64, variable *structs.VariableEncrypted) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Check if the variable already exists
+	existing, err := txn.First("variables", "id", variable.Namespace, variable.Path)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+
+	// Update all the indexes
+	if existing != nil {
+		exist := existing.(*structs.VariableEncrypted)
+		variable.CreateIndex = exist.CreateIndex
+		variable.CreateTime = exist.CreateTime
+		variable.ModifyIndex = index
+	} else {
+		variable.CreateIndex = index
+		variable.ModifyIndex = index
+	}
+
+	// Update the variable
+	if err := txn.Insert("variables", variable); err != nil {
+		return fmt.Errorf("upserting variable failed: %v", err)
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteVariable deletes the variable at the given path in the namespace
+func (s *StateStore) DeleteVariable(index uint64, namespace, path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	if _, err := txn.DeleteAll("variables", "id", namespace, path); err != nil {
+		return fmt.Errorf("deleting variable failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// VariableByPath is used to lookup a variable by its namespace and path
+func (s *StateStore) VariableByPath(ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("variables", "id", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
+
+// VariablesByPathPrefix is used to lookup the variables of a namespace by
+// path prefix
+func (s *StateStore) VariablesByPathPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// Variables returns an iterator over all the variables
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("variables", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertRootKey is used to create or update a root key
+func (s *StateStore) UpsertRootKey(index uint64, key *structs.RootKey) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Check if the key already exists
+	existing, err := txn.First("root_keys", "id", key.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key lookup failed: %v", err)
+	}
+
+	// Update all the indexes
+	if existing != nil {
+		key.CreateIndex = existing.(*structs.RootKey).CreateIndex
+		key.ModifyIndex = index
+	} else {
+		key.CreateIndex = index
+		key.ModifyIndex = index
+	}
+
+	if err := txn.Insert("root_keys", key); err != nil {
+		return fmt.Errorf("upserting root key failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"root_keys", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// RootKeyByID is used to lookup a root key by its ID
+func (s *StateStore) RootKeyByID(ws memdb.WatchSet, id string) (*structs.RootKey, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("root_keys", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("root key lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.RootKey), nil
+	}
+	return nil, nil
+}
+
+// RootKeys returns an iterator over all the root keys
+func (s *StateStore) RootKeys(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("root_keys", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// LatestRootKey returns the most recently created root key, which is the key
+// used to encrypt new variables. It returns nil if no key exists.
+func (s *StateStore) LatestRootKey(ws memdb.WatchSet) (*structs.RootKey, error) {
+	iter, err := s.RootKeys(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *structs.RootKey
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		key := raw.(*structs.RootKey)
+		if latest == nil || key.CreateIndex > latest.CreateIndex {
+			latest = key
+		}
+	}
+	return latest, nil
+}
+
+// UpsertServiceRegistrations is used to create or update the registrations
+// of services
+func (s *StateStore) UpsertServiceRegistrations(index uint64, services []*structs.ServiceRegistration) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, service := range services {
+		// Check if the registration already exists
+		existing, err := txn.First("service_registrations", "id", service.ID)
+		if err != nil {
+			return fmt.Errorf("service registration lookup failed: %v", err)
+		}
+
+		// Don't register the services of terminal allocations, which may
+		// race with the client removing them
+		alloc, err := txn.First("allocs", "id", service.AllocID)
+		if err != nil {
+			return fmt.Errorf("alloc lookup failed: %v", err)
+		}
+		if alloc == nil || alloc.(*structs.Allocation).ClientTerminalStatus() {
+			continue
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			exist := existing.(*structs.ServiceRegistration)
+			if exist.Equals(service) {
+				continue
+			}
+			service.CreateIndex = exist.CreateIndex
+			service.ModifyIndex = index
+		} else {
+			service.CreateIndex = index
+			service.ModifyIndex = index
+		}
+
+		// Update the registration
+		if err := txn.Insert("service_registrations", service); err != nil {
+			return fmt.Errorf("upserting service registration failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteServiceRegistrationByID deletes the registration with the given ID
+func (s *StateStore) DeleteServiceRegistrationByID(index uint64, id string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	if _, err := txn.DeleteAll("service_registrations", "id", id); err != nil {
+		return fmt.Errorf("deleting service registration failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// deleteServiceRegistrationsByAllocTxn deletes the registrations of the
+// services of an allocation
+func (s *StateStore) deleteServiceRegistrationsByAllocTxn(index uint64, txn *memdb.Txn, allocID string) error {
+	num, err := txn.DeleteAll("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return fmt.Errorf("deleting service registrations failed: %v", err)
+	}
+	if num == 0 {
+		return nil
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// ServiceRegistrationByID is used to lookup a registration by its ID
+func (s *StateStore) ServiceRegistrationByID(ws memdb.WatchSet, id string) (*structs.ServiceRegistration, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("service_registrations", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ServiceRegistration), nil
+	}
+	return nil, nil
+}
+
+// ServiceRegistrationsByName is used to lookup the registrations of a
+// service in a namespace
+func (s *StateStore) ServiceRegistrationsByName(ws memdb.WatchSet, namespace, name string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "service_name", namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrationsByNamespace is used to lookup the registrations of all
+// the services in a namespace
+func (s *StateStore) ServiceRegistrationsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "service_name_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrationsByAllocID is used to lookup the registrations of the
+// services of an allocation
+func (s *StateStore) ServiceRegistrationsByAllocID(ws memdb.WatchSet, allocID string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// ServiceRegistrations returns an iterator over all the registrations
+func (s *StateStore) ServiceRegistrations(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("service_registrations", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// StateSnapshot is used to provide a point-in-time snapshot
+type StateSnapshot struct {
+	StateStore
+}
+
+// StateRestore is used to optimize the performance when
+// restoring state by only using a single large transaction
+// instead of thousands of sub transactions
+type StateRestore struct {
+	txn      *memdb.Txn
+	interner *stringInterner
+
+	// children are the restored jobs whose parent job may be restored after
+	// them. They share the task groups of their parent once all the jobs are
+	// restored.
+	children []*structs.Job
+}
+
+// Abort is used to abort the restore operation
 func (s *StateRestore) Abort() {
 	s.txn.Abort()
 }
 
 // Commit is used to commit the restore operation
 func (s *StateRestore) Commit() {
+	// All the jobs are restored, so the children can share the task groups
+	// of their parent. The lookups can't fail as the index exists.
+	for _, child := range s.children {
+		if parent, err := s.txn.First("jobs", "id", child.Namespace, child.ParentID); err == nil && parent != nil {
+			shareTaskGroups(child, parent.(*structs.Job))
+		}
+	}
 	s.txn.Commit()
 }
 
 // NodeRestore is used to restore a node
 func (r *StateRestore) NodeRestore(node *structs.Node) error {
+	r.interner.internNode(node)
 	if err := r.txn.Insert("nodes", node); err != nil {
 		return fmt.Errorf("node insert failed: %v", err)
 	}
@@ -4127,6 +5043,10 @@ func (r *StateRestore) NodeRestore(node *structs.Node) error {
 
 // JobRestore is used to restore a job
 func (r *StateRestore) JobRestore(job *structs.Job) error {
+	r.interner.internJob(job)
+	if job.ParentID != "" {
+		r.children = append(r.children, job)
+	}
 	if err := r.txn.Insert("jobs", job); err != nil {
 		return fmt.Errorf("job insert failed: %v", err)
 	}
@@ -4175,12 +5095,37 @@ func (r *StateRestore) JobSummaryRestore(jobSummary *structs.JobSummary) error {
 
 // JobVersionRestore is used to restore a job version
 func (r *StateRestore) JobVersionRestore(version *structs.Job) error {
+	r.interner.internJob(version)
+	if err := r.shareTaskGroups(version); err != nil {
+		return err
+	}
 	if err := r.txn.Insert("job_version", version); err != nil {
 		return fmt.Errorf("job version insert failed: %v", err)
 	}
 	return nil
 }
 
+// shareTaskGroups shares the task groups of the job version with its parent
+// job and the current version of the job, which are restored before the job
+// versions.
+func (r *StateRestore) shareTaskGroups(version *structs.Job) error {
+	var others []*structs.Job
+	for _, id := range []string{version.ParentID, version.ID} {
+		if id == "" {
+			continue
+		}
+		raw, err := r.txn.First("jobs", "id", version.Namespace, id)
+		if err != nil {
+			return fmt.Errorf("job lookup failed: %v", err)
+		}
+		if raw != nil {
+			others = append(others, raw.(*structs.Job))
+		}
+	}
+	shareTaskGroups(version, others...)
+	return nil
+}
+
 // DeploymentRestore is used to restore a deployment
 func (r *StateRestore) DeploymentRestore(deployment *structs.Deployment) error {
 	if err := r.txn.Insert("deployment", deployment); err != nil {
@@ -4205,6 +5150,30 @@ func (r *StateRestore) ACLPolicyRestore(policy *structs.ACLPolicy) error {
 	return nil
 }
 
+// ACLAuthMethodRestore is used to restore an ACL auth method
+func (r *StateRestore) ACLAuthMethodRestore(method *structs.ACLAuthMethod) error {
+	if err := r.txn.Insert("acl_auth_method", method); err != nil {
+		return fmt.Errorf("inserting acl auth method failed: %v", err)
+	}
+	return nil
+}
+
+// ACLRoleRestore is used to restore an ACL role
+func (r *StateRestore) ACLRoleRestore(role *structs.ACLRole) error {
+	if err := r.txn.Insert("acl_role", role); err != nil {
+		return fmt.Errorf("inserting acl role failed: %v", err)
+	}
+	return nil
+}
+
+// ACLBindingRuleRestore is used to restore an ACL binding rule
+func (r *StateRestore) ACLBindingRuleRestore(rule *structs.ACLBindingRule) error {
+	if err := r.txn.Insert("acl_binding_rule", rule); err != nil {
+		return fmt.Errorf("inserting acl binding rule failed: %v", err)
+	}
+	return nil
+}
+
 // ACLTokenRestore is used to restore an ACL token
 func (r *StateRestore) ACLTokenRestore(token *structs.ACLToken) error {
 	if err := r.txn.Insert("acl_token", token); err != nil {
@@ -739,7 +739,7 @@ func TestStateStore_UpdateNodeStatus_Node(t *testing.T) {
 		Timestamp: time.Now(),
 	}
 
-	require.NoError(state.UpdateNodeStatus(801, node.ID, structs.NodeStatusReady, event))
+	require.NoError(state.UpdateNodeStatus(801, node.ID, structs.NodeStatusReady, 70, event))
 	require.True(watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -747,6 +747,7 @@ func TestStateStore_UpdateNodeStatus_Node(t *testing.T) {
 	require.NoError(err)
 	require.Equal(structs.NodeStatusReady, out.Status)
 	require.EqualValues(801, out.ModifyIndex)
+	require.EqualValues(70, out.StatusUpdatedAt)
 	require.Len(out.Events, 2)
 	require.Equal(event.Message, out.Events[1].Message)
 
@@ -1015,7 +1016,7 @@ func TestStateStore_UpdateNodeEligibility(t *testing.T) {
 		Subsystem: structs.NodeEventSubsystemCluster,
 		Timestamp: time.Now(),
 	}
-	require.Nil(state.UpdateNodeEligibility(1001, node.ID, expectedEligibility, event))
+	require.Nil(state.UpdateNodeEligibility(1001, node.ID, expectedEligibility, time.Time{}, event))
 	require.True(watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -1031,16 +1032,26 @@ func TestStateStore_UpdateNodeEligibility(t *testing.T) {
 	require.EqualValues(1001, index)
 	require.False(watchFired(ws))
 
-	// Set a drain strategy
+	// Mark the node as ineligible until a given time
+	until := time.Now().Add(time.Hour).UTC()
+	require.Nil(state.UpdateNodeEligibility(1002, node.ID, expectedEligibility, until, nil))
+	out, err = state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.True(until.Equal(out.IneligibleUntil))
+
+	// Set a drain strategy, which keeps the node ineligible
 	expectedDrain := &structs.DrainStrategy{
 		DrainSpec: structs.DrainSpec{
 			Deadline: -1 * time.Second,
 		},
 	}
-	require.Nil(state.UpdateNodeDrain(1002, node.ID, expectedDrain, false, nil))
+	require.Nil(state.UpdateNodeDrain(1003, node.ID, expectedDrain, false, nil))
+	out, err = state.NodeByID(nil, node.ID)
+	require.Nil(err)
+	require.True(out.IneligibleUntil.IsZero())
 
 	// Try to set the node to eligible
-	err = state.UpdateNodeEligibility(1003, node.ID, structs.NodeSchedulingEligible, nil)
+	err = state.UpdateNodeEligibility(1004, node.ID, structs.NodeSchedulingEligible, time.Time{}, nil)
 	require.NotNil(err)
 	require.Contains(err.Error(), "while it is draining")
 }
@@ -1582,6 +1593,60 @@ func TestStateStore_UpdateUpsertJob_JobVersion(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertJob_VersionRetention(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	versions := func(job *structs.Job) []uint64 {
+		all, err := state.JobVersionsByID(nil, job.Namespace, job.ID)
+		require.NoError(err)
+		var out []uint64
+		for _, j := range all {
+			out = append(out, j.Version)
+		}
+		return out
+	}
+
+	// Versions beyond the count are pruned
+	job := mock.Job()
+	job.VersionRetention = &structs.JobVersionRetention{Count: 10}
+	for i := 0; i < 15; i++ {
+		job = job.Copy()
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+	}
+	require.Equal([]uint64{14, 13, 12, 11, 10, 9, 8, 7, 6, 5}, versions(job))
+
+	// Lowering the count prunes the versions beyond it at once
+	job = job.Copy()
+	job.VersionRetention.Count = 2
+	require.NoError(state.UpsertJob(1100, job))
+	require.Equal([]uint64{15, 14}, versions(job))
+
+	// Versions older than the age are pruned, except for the latest stable
+	// version
+	job = mock.Job()
+	job.VersionRetention = &structs.JobVersionRetention{Age: time.Hour}
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		job = job.Copy()
+		job.Stable = i == 1
+		job.SubmitTime = start.Add(time.Duration(i) * 30 * time.Minute).UnixNano()
+		require.NoError(state.UpsertJob(uint64(2000+i), job))
+	}
+	require.Equal([]uint64{4, 3, 2, 1}, versions(job))
+
+	// The latest stable version takes the place of the oldest version kept
+	job = mock.Job()
+	job.VersionRetention = &structs.JobVersionRetention{Count: 3}
+	for i := 0; i < 5; i++ {
+		job = job.Copy()
+		job.Stable = i == 0
+		require.NoError(state.UpsertJob(uint64(3000+i), job))
+	}
+	require.Equal([]uint64{4, 3, 0}, versions(job))
+}
+
 func TestStateStore_DeleteJob_Job(t *testing.T) {
 	state := testStateStore(t)
 	job := mock.Job()
@@ -1998,6 +2063,98 @@ func TestStateStore_JobsByIDPrefix(t *testing.T) {
 	}
 }
 
+func TestStateStore_JobsByTag(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	job1 := mock.Job()
+	job1.Tags = []string{"owner:payments", "tier:1"}
+	require.NoError(state.UpsertJob(1000, job1))
+
+	job2 := mock.Job()
+	job2.Tags = []string{"owner:search"}
+	require.NoError(state.UpsertJob(1001, job2))
+
+	gatherIDs := func(iter memdb.ResultIterator) []string {
+		var ids []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			ids = append(ids, raw.(*structs.Job).ID)
+		}
+		return ids
+	}
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.JobsByTag(ws, structs.DefaultNamespace, "owner:payments")
+	require.NoError(err)
+	require.Equal([]string{job1.ID}, gatherIDs(iter))
+
+	iter, err = state.JobsByTag(ws, structs.DefaultNamespace, "owner")
+	require.NoError(err)
+	require.Empty(gatherIDs(iter))
+
+	// Jobs of other namespaces are filtered
+	iter, err = state.JobsByTag(ws, "other", "owner:payments")
+	require.NoError(err)
+	require.Empty(gatherIDs(iter))
+
+	// Jobs with several matching tags are only returned once
+	job2.Tags = []string{"owner:search", "owner:web"}
+	require.NoError(state.UpsertJob(1003, job2))
+	require.True(watchFired(ws))
+
+	iter, err = state.JobsByTagPrefix(nil, structs.DefaultNamespace, "owner:")
+	require.NoError(err)
+	require.ElementsMatch([]string{job1.ID, job2.ID}, gatherIDs(iter))
+
+	iter, err = state.JobsByTagPrefix(nil, structs.DefaultNamespace, "tier:")
+	require.NoError(err)
+	require.Equal([]string{job1.ID}, gatherIDs(iter))
+}
+
+func TestStateStore_JobsByConcurrencyGroup(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	job1 := mock.Job()
+	job1.ConcurrencyGroup = "migrations"
+	require.NoError(state.UpsertJob(1000, job1))
+
+	job2 := mock.Job()
+	job2.ConcurrencyGroup = "migrations"
+	require.NoError(state.UpsertJob(1001, job2))
+
+	job3 := mock.Job()
+	require.NoError(state.UpsertJob(1002, job3))
+
+	gatherIDs := func(iter memdb.ResultIterator) []string {
+		var ids []string
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			ids = append(ids, raw.(*structs.Job).ID)
+		}
+		return ids
+	}
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.JobsByConcurrencyGroup(ws, structs.DefaultNamespace, "migrations")
+	require.NoError(err)
+	require.ElementsMatch([]string{job1.ID, job2.ID}, gatherIDs(iter))
+
+	// Jobs of other namespaces are filtered
+	iter, err = state.JobsByConcurrencyGroup(nil, "other", "migrations")
+	require.NoError(err)
+	require.Empty(gatherIDs(iter))
+
+	// Jobs leaving the group are no longer returned
+	job2 = job2.Copy()
+	job2.ConcurrencyGroup = ""
+	require.NoError(state.UpsertJob(1003, job2))
+	require.True(watchFired(ws))
+
+	iter, err = state.JobsByConcurrencyGroup(nil, structs.DefaultNamespace, "migrations")
+	require.NoError(err)
+	require.Equal([]string{job1.ID}, gatherIDs(iter))
+}
+
 func TestStateStore_JobsByPeriodic(t *testing.T) {
 	state := testStateStore(t)
 	var periodic, nonPeriodic []*structs.Job
@@ -3663,6 +3820,15 @@ func TestStateStore_UpdateAllocsFromClient_Deployment(t *testing.T) {
 	require.NotNil(dstate)
 	require.Equal(1, dstate.PlacedAllocs)
 	require.True(healthy.Add(pdeadline).Equal(dstate.RequireProgressBy))
+
+	// Check that the placement and the health were recorded as progress
+	require.Len(dstate.Progress, 2)
+	require.Equal(1, dstate.Progress[0].PlacedAllocs)
+	require.Equal(0, dstate.Progress[0].HealthyAllocs)
+	require.Equal(1, dstate.Progress[1].PlacedAllocs)
+	require.Equal(1, dstate.Progress[1].HealthyAllocs)
+	require.True(healthy.Equal(dstate.Progress[1].Time))
+	require.True(dstate.RequireProgressBy.Equal(dstate.Progress[1].RequireProgressBy))
 }
 
 // This tests that the deployment state is merged correctly
@@ -4030,6 +4196,42 @@ func TestStateStore_UpdateAlloc_Lost(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpdateAlloc_Unknown(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	alloc := mock.Alloc()
+	alloc.ClientStatus = structs.AllocClientStatusPending
+
+	require.NoError(state.UpsertJob(999, alloc.Job))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	running := alloc.Copy()
+	running.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(state.UpdateAllocsFromClient(1001, []*structs.Allocation{running}))
+
+	// The scheduler marks the allocation as unknown
+	unknown := alloc.Copy()
+	unknown.ClientStatus = structs.AllocClientStatusUnknown
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{unknown}))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(err)
+	require.Equal(structs.AllocClientStatusUnknown, out.ClientStatus)
+
+	summary, err := state.JobSummaryByID(nil, alloc.Namespace, alloc.JobID)
+	require.NoError(err)
+	require.Equal(1, summary.Summary[alloc.TaskGroup].Unknown)
+	require.Equal(0, summary.Summary[alloc.TaskGroup].Running)
+
+	// The client reports the allocation as running once it reconnects
+	require.NoError(state.UpdateAllocsFromClient(1003, []*structs.Allocation{running}))
+
+	summary, err = state.JobSummaryByID(nil, alloc.Namespace, alloc.JobID)
+	require.NoError(err)
+	require.Equal(0, summary.Summary[alloc.TaskGroup].Unknown)
+	require.Equal(1, summary.Summary[alloc.TaskGroup].Running)
+}
+
 // This test ensures an allocation can be updated when there is no job
 // associated with it. This will happen when a job is stopped by an user which
 // has non-terminal allocations on clients
@@ -4354,6 +4556,58 @@ func TestStateStore_ReconcileJobSummary(t *testing.T) {
 	}
 }
 
+func TestStateStore_JobSummary_LastExitCodes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	job := mock.BatchJob()
+	require.NoError(state.UpsertJob(100, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	alloc2 := alloc.Copy()
+	alloc2.ID = uuid.Generate()
+	require.NoError(state.UpsertAllocs(110, []*structs.Allocation{alloc, alloc2}))
+
+	terminated := func(a *structs.Allocation, status string, code int) *structs.Allocation {
+		a = a.Copy()
+		a.ClientStatus = status
+		a.TaskStates = map[string]*structs.TaskState{
+			"web": {
+				State: structs.TaskStateDead,
+				Events: []*structs.TaskEvent{
+					structs.NewTaskEvent(structs.TaskTerminated).SetExitCode(code),
+				},
+			},
+		}
+		return a
+	}
+
+	// The exit codes of the last finished allocation are recorded
+	require.NoError(state.UpdateAllocsFromClient(120,
+		[]*structs.Allocation{terminated(alloc, structs.AllocClientStatusComplete, 0)}))
+	require.NoError(state.UpdateAllocsFromClient(130,
+		[]*structs.Allocation{terminated(alloc2, structs.AllocClientStatusFailed, 3)}))
+
+	summary, err := state.JobSummaryByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	tgSummary := summary.Summary[alloc.TaskGroup]
+	require.Equal(1, tgSummary.Complete)
+	require.Equal(1, tgSummary.Failed)
+	require.Equal(map[string]int{"web": 3}, tgSummary.LastExitCodes)
+
+	// They are restored when reconciling the summaries
+	require.NoError(state.DeleteJobSummary(140, job.Namespace, job.ID))
+	require.NoError(state.ReconcileJobSummaries(150))
+
+	summary, err = state.JobSummaryByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(tgSummary, summary.Summary[alloc.TaskGroup])
+}
+
 func TestStateStore_ReconcileParentJobSummary(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -5643,6 +5897,31 @@ func TestStateStore_UpdateJobStability(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpdateJobMinimumVersion(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	// Insert a job twice to get two versions
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1, job))
+	require.NoError(state.UpsertJob(2, job.Copy()))
+
+	// Pin the job
+	require.NoError(state.UpdateJobMinimumVersion(3, job.Namespace, job.ID, 1))
+
+	// The job is pinned without bumping its version
+	ws := memdb.NewWatchSet()
+	out, err := state.JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Equal(uint64(1), out.MinimumVersion)
+	require.Equal(uint64(1), out.Version)
+	require.Equal(uint64(3), out.ModifyIndex)
+	require.Equal(uint64(2), out.JobModifyIndex)
+
+	// Pinning a nonexistent job fails
+	require.Error(state.UpdateJobMinimumVersion(4, job.Namespace, "foo", 1))
+}
+
 // Test that nonexistent deployment can't be promoted
 func TestStateStore_UpsertDeploymentPromotion_Nonexistent(t *testing.T) {
 	state := testStateStore(t)
@@ -6480,6 +6759,248 @@ func TestStateStore_UpsertACLPolicy(t *testing.T) {
 	}
 }
 
+func TestStateStore_UpsertACLAuthMethods(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
+	method2.Default = true
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLAuthMethodByName(ws, method.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method, method2}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
+	require.NoError(err)
+	require.Equal(method, out)
+	require.Equal(uint64(1000), out.CreateIndex)
+
+	def, err := state.DefaultACLAuthMethod(ws)
+	require.NoError(err)
+	require.Equal(method2, def)
+
+	// Updates keep the create index
+	method = method.Copy()
+	method.TokenTTL = 2 * method.TokenTTL
+	method.SetHash()
+	require.NoError(state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{method}))
+	require.True(watchFired(ws))
+
+	out, err = state.ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+
+	index, err := state.Index("acl_auth_method")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
+
+func TestStateStore_UpsertACLRoles(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	role := mock.ACLRole()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+	require.Equal(role, out)
+	require.Equal(uint64(1000), out.CreateIndex)
+
+	// Updates keep the create index
+	role = role.Copy()
+	role.Policies = append(role.Policies, "baz")
+	role.SetHash()
+	require.NoError(state.UpsertACLRoles(1001, []*structs.ACLRole{role}))
+	require.True(watchFired(ws))
+
+	out, err = state.ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Equal([]string{"foo", "bar", "baz"}, out.Policies)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+
+	iter, err := state.ACLRoleByNamePrefix(nil, "role-")
+	require.NoError(err)
+	require.NotNil(iter.Next())
+	require.Nil(iter.Next())
+
+	index, err := state.Index("acl_role")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
+
+func TestStateStore_DeleteACLRoles(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	role := mock.ACLRole()
+	role2 := mock.ACLRole()
+
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role, role2}))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+
+	require.NoError(state.DeleteACLRoles(1001, []string{role.Name}))
+	require.True(watchFired(ws))
+
+	out, err := state.ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	iter, err := state.ACLRoles(nil)
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(1, count)
+
+	index, err := state.Index("acl_role")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
+
+func TestStateStore_DeleteACLAuthMethods(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
+
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method, method2}))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLAuthMethodByName(ws, method.Name)
+	require.NoError(err)
+
+	require.NoError(state.DeleteACLAuthMethods(1001, []string{method.Name}))
+	require.True(watchFired(ws))
+
+	out, err := state.ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	iter, err := state.ACLAuthMethods(nil)
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(1, count)
+
+	index, err := state.Index("acl_auth_method")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
+
+func TestStateStore_UpsertACLBindingRules(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule("ci")
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLBindingRuleByID(ws, rule.ID)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLBindingRuleByID(ws, rule.ID)
+	require.NoError(err)
+	require.Equal(rule, out)
+	require.Equal(uint64(1000), out.CreateIndex)
+
+	// Updates keep the create index
+	rule = rule.Copy()
+	rule.BindName = "deploy"
+	rule.SetHash()
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+	require.True(watchFired(ws))
+
+	out, err = state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal("deploy", out.BindName)
+	require.Equal(uint64(1000), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+
+	// Binding rules are listed by auth method
+	require.NoError(state.UpsertACLBindingRules(1002, []*structs.ACLBindingRule{mock.ACLBindingRule("other")}))
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, "ci")
+	require.NoError(err)
+	require.Equal(rule.ID, iter.Next().(*structs.ACLBindingRule).ID)
+	require.Nil(iter.Next())
+
+	index, err := state.Index("acl_binding_rule")
+	require.NoError(err)
+	require.Equal(uint64(1002), index)
+}
+
+func TestStateStore_DeleteACLBindingRules(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule("ci")
+	rule2 := mock.ACLBindingRule("ci")
+
+	require.NoError(state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule, rule2}))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLBindingRuleByID(ws, rule.ID)
+	require.NoError(err)
+
+	require.NoError(state.DeleteACLBindingRules(1001, []string{rule.ID}))
+	require.True(watchFired(ws))
+
+	out, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = state.ACLBindingRuleByID(nil, rule2.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	index, err := state.Index("acl_binding_rule")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+}
+
+func TestStateStore_DeleteACLAuthMethods_BindingRules(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	method := mock.ACLJWTAuthMethod()
+	rule := mock.ACLBindingRule(method.Name)
+	other := mock.ACLBindingRule("other")
+
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule, other}))
+
+	// Deleting an auth method deletes its binding rules
+	require.NoError(state.DeleteACLAuthMethods(1002, []string{method.Name}))
+
+	out, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.ACLBindingRuleByID(nil, other.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	index, err := state.Index("acl_binding_rule")
+	require.NoError(err)
+	require.Equal(uint64(1002), index)
+}
+
 func TestStateStore_DeleteACLPolicy(t *testing.T) {
 	state := testStateStore(t)
 	policy := mock.ACLPolicy()
@@ -6964,6 +7485,255 @@ func TestStateStore_SchedulerConfig(t *testing.T) {
 	require.Equal(schedConfig, out)
 }
 
+func TestStateStore_UpsertVariable(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	v := mock.VariableEncrypted()
+
+	ws := memdb.NewWatchSet()
+	out, err := state.VariableByPath(ws, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Nil(out)
+
+	require.NoError(state.UpsertVariable(1000, v))
+	require.True(watchFired(ws))
+
+	out, err = state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Equal(v, out)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1000, out.ModifyIndex)
+
+	// Updating keeps the create index and time
+	update := mock.VariableEncrypted()
+	update.Path = v.Path
+	require.NoError(state.UpsertVariable(1001, update))
+
+	out, err = state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+	require.Equal(v.CreateTime, out.CreateTime)
+	require.Equal(update.KeyID, out.KeyID)
+
+	index, err := state.Index("variables")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+}
+
+func TestStateStore_DeleteVariable(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	v := mock.VariableEncrypted()
+	require.NoError(state.UpsertVariable(1000, v))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.VariableByPath(ws, v.Namespace, v.Path)
+	require.NoError(err)
+
+	require.NoError(state.DeleteVariable(1001, v.Namespace, v.Path))
+	require.True(watchFired(ws))
+
+	out, err := state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("variables")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+}
+
+func TestStateStore_VariablesByPathPrefix(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	paths := []string{"project/a", "project/b", "other/a"}
+	for i, p := range paths {
+		v := mock.VariableEncrypted()
+		v.Path = p
+		require.NoError(state.UpsertVariable(uint64(1000+i), v))
+	}
+
+	// Variables of another namespace are not returned
+	v := mock.VariableEncrypted()
+	v.Namespace = "other"
+	v.Path = "project/c"
+	require.NoError(state.UpsertVariable(1010, v))
+
+	iter, err := state.VariablesByPathPrefix(nil, structs.DefaultNamespace, "project/")
+	require.NoError(err)
+
+	var found []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		found = append(found, raw.(*structs.VariableEncrypted).Path)
+	}
+	require.Equal([]string{"project/a", "project/b"}, found)
+}
+
+func TestStateStore_RootKeys(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	latest, err := state.LatestRootKey(nil)
+	require.NoError(err)
+	require.Nil(latest)
+
+	key1 := mock.RootKey()
+	key2 := mock.RootKey()
+	require.NoError(state.UpsertRootKey(1000, key1))
+	require.NoError(state.UpsertRootKey(1001, key2))
+
+	out, err := state.RootKeyByID(nil, key1.KeyID)
+	require.NoError(err)
+	require.Equal(key1, out)
+
+	latest, err = state.LatestRootKey(nil)
+	require.NoError(err)
+	require.Equal(key2.KeyID, latest.KeyID)
+
+	index, err := state.Index("root_keys")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+}
+
+func TestStateStore_RestoreVariablesAndRootKeys(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	v := mock.VariableEncrypted()
+	key := mock.RootKey()
+
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.VariableRestore(v))
+	require.NoError(restore.RootKeyRestore(key))
+	restore.Commit()
+
+	out, err := state.VariableByPath(nil, v.Namespace, v.Path)
+	require.NoError(err)
+	require.Equal(v, out)
+
+	outKey, err := state.RootKeyByID(nil, key.KeyID)
+	require.NoError(err)
+	require.Equal(key, outKey)
+}
+
+func TestStateStore_UpsertServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	// Services of unknown allocations are ignored
+	unknown := mock.ServiceRegistration(mock.Alloc())
+	service := mock.ServiceRegistration(alloc)
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ServiceRegistrationsByName(ws, alloc.Namespace, service.ServiceName)
+	require.NoError(err)
+
+	require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{service, unknown}))
+	require.True(watchFired(ws))
+
+	out, err := state.ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Equal(service, out)
+	require.EqualValues(1001, out.CreateIndex)
+
+	out, err = state.ServiceRegistrationByID(nil, unknown.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Upserting an unchanged registration keeps its indexes
+	require.NoError(state.UpsertServiceRegistrations(1002, []*structs.ServiceRegistration{service.Copy()}))
+	out, err = state.ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	// Updating the registration keeps its create index
+	update := service.Copy()
+	update.Status = "critical"
+	require.NoError(state.UpsertServiceRegistrations(1003, []*structs.ServiceRegistration{update}))
+	out, err = state.ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Equal("critical", out.Status)
+	require.EqualValues(1001, out.CreateIndex)
+	require.EqualValues(1003, out.ModifyIndex)
+
+	index, err := state.Index("service_registrations")
+	require.NoError(err)
+	require.EqualValues(1003, index)
+}
+
+func TestStateStore_DeleteServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	alloc1 := mock.Alloc()
+	alloc2 := mock.Alloc()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc1.JobID)))
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc2.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc1, alloc2}))
+
+	service1 := mock.ServiceRegistration(alloc1)
+	service2 := mock.ServiceRegistration(alloc2)
+	require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{service1, service2}))
+
+	iter, err := state.ServiceRegistrationsByNamespace(nil, structs.DefaultNamespace)
+	require.NoError(err)
+	require.Len(serviceRegistrations(iter), 2)
+
+	// Delete a registration by ID
+	require.NoError(state.DeleteServiceRegistrationByID(1002, service1.ID))
+	out, err := state.ServiceRegistrationByID(nil, service1.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The registrations of allocations updated to a terminal status by the
+	// client are removed
+	update := alloc2.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1003, []*structs.Allocation{update}))
+
+	iter, err = state.ServiceRegistrationsByAllocID(nil, alloc2.ID)
+	require.NoError(err)
+	require.Empty(serviceRegistrations(iter))
+
+	// Terminal allocations can't register services
+	require.NoError(state.UpsertServiceRegistrations(1004, []*structs.ServiceRegistration{service2}))
+	iter, err = state.ServiceRegistrations(nil)
+	require.NoError(err)
+	require.Empty(serviceRegistrations(iter))
+}
+
+func TestStateStore_RestoreServiceRegistration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	service := mock.ServiceRegistration(mock.Alloc())
+	restore, err := state.Restore()
+	require.NoError(err)
+	require.NoError(restore.ServiceRegistrationRestore(service))
+	restore.Commit()
+
+	out, err := state.ServiceRegistrationByID(nil, service.ID)
+	require.NoError(err)
+	require.Equal(service, out)
+}
+
+// serviceRegistrations collects the registrations of the iterator
+func serviceRegistrations(iter memdb.ResultIterator) []*structs.ServiceRegistration {
+	var out []*structs.ServiceRegistration
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.ServiceRegistration))
+	}
+	return out
+}
+
 func TestStateStore_Abandon(t *testing.T) {
 	s := testStateStore(t)
 	abandonCh := s.AbandonCh()
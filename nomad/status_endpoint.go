@@ -131,6 +131,18 @@ func (s *Status) RaftStats(args struct{}, reply *autopilot.ServerStats) error {
 	return nil
 }
 
+// RaftServerStats returns the replication and log store stats of the local
+// server. It is used by the leader to fill the Raft configuration returned to
+// operators.
+func (s *Status) RaftServerStats(args struct{}, reply *structs.RaftServerStats) error {
+	stats, err := s.srv.raftServerStats()
+	if err != nil {
+		return err
+	}
+	*reply = *stats
+	return nil
+}
+
 // HasNodeConn returns whether the server has a connection to the requested
 // Node.
 func (s *Status) HasNodeConn(args *structs.NodeSpecificRequest, reply *structs.NodeConnQueryResponse) error {
@@ -0,0 +1,185 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// DefaultBufferSize is the default number of batches of events the
+	// broker keeps for subscribers resuming from an earlier index.
+	DefaultBufferSize = 100
+)
+
+// EventBroker buffers the events published by the FSM and delivers them to
+// subscribers. Publishing never blocks on subscribers; subscribers that fall
+// behind the buffer resume from the oldest batch still buffered.
+type EventBroker struct {
+	// buffer holds the most recent batches of events in index order
+	buffer []*structs.Events
+	size   int
+
+	// notifyCh is closed and replaced when events are published
+	notifyCh chan struct{}
+
+	l sync.Mutex
+}
+
+// NewEventBroker returns an event broker buffering up to size batches of
+// events.
+func NewEventBroker(size int) *EventBroker {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	return &EventBroker{
+		size:     size,
+		notifyCh: make(chan struct{}),
+	}
+}
+
+// Publish adds the events for an index to the buffer and wakes up the
+// subscribers.
+func (b *EventBroker) Publish(events *structs.Events) {
+	if events == nil || len(events.Events) == 0 {
+		return
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.buffer = append(b.buffer, events)
+	if len(b.buffer) > b.size {
+		b.buffer[0] = nil
+		b.buffer = b.buffer[1:]
+	}
+
+	close(b.notifyCh)
+	b.notifyCh = make(chan struct{})
+}
+
+// LastIndex returns the index of the last batch of events published.
+func (b *EventBroker) LastIndex() uint64 {
+	b.l.Lock()
+	defer b.l.Unlock()
+	if len(b.buffer) == 0 {
+		return 0
+	}
+	return b.buffer[len(b.buffer)-1].Index
+}
+
+// SubscribeRequest describes the events a subscription receives.
+type SubscribeRequest struct {
+	// Topics maps topics to the keys of the events to receive. The topic
+	// structs.TopicAll and the key "*" match every topic and key.
+	Topics map[structs.Topic][]string
+
+	// Namespace filters out the events of objects in other namespaces
+	// unless it is "*". Events of objects without namespaces, such as
+	// nodes, are always received.
+	Namespace string
+
+	// Index is the index after which events are received. An index of zero
+	// only receives events published after subscribing.
+	Index uint64
+}
+
+// Subscribe returns a subscription to the events matching the request.
+func (b *EventBroker) Subscribe(req *SubscribeRequest) *Subscription {
+	index := req.Index
+	if index == 0 {
+		index = b.LastIndex()
+	}
+	return &Subscription{
+		broker: b,
+		req:    req,
+		index:  index,
+	}
+}
+
+// Subscription is used to receive the events matching a subscribe request.
+type Subscription struct {
+	broker *EventBroker
+	req    *SubscribeRequest
+
+	// index is the index of the last batch of events returned
+	index uint64
+}
+
+// Next blocks until events matching the subscription are published after the
+// last batch it returned, or until the context is done.
+func (s *Subscription) Next(ctx context.Context) (*structs.Events, error) {
+	for {
+		events, notifyCh := s.next()
+		if events != nil {
+			return events, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-notifyCh:
+		}
+	}
+}
+
+// next returns the next buffered batch with events matching the subscription,
+// or the channel closed when events are next published if there is none.
+func (s *Subscription) next() (*structs.Events, <-chan struct{}) {
+	b := s.broker
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	for _, batch := range b.buffer {
+		if batch.Index <= s.index {
+			continue
+		}
+		s.index = batch.Index
+
+		if filtered := s.filter(batch); filtered != nil {
+			return filtered, nil
+		}
+	}
+	return nil, b.notifyCh
+}
+
+// filter returns the batch with only the events matching the subscription,
+// or nil if none match.
+func (s *Subscription) filter(batch *structs.Events) *structs.Events {
+	var matching []structs.Event
+	for _, event := range batch.Events {
+		if s.matches(&event) {
+			matching = append(matching, event)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	return &structs.Events{Index: batch.Index, Events: matching}
+}
+
+func (s *Subscription) matches(event *structs.Event) bool {
+	if event.Namespace != "" && s.req.Namespace != "*" && event.Namespace != s.req.Namespace {
+		return false
+	}
+
+	return matchesKeys(event, s.req.Topics[structs.TopicAll]) ||
+		matchesKeys(event, s.req.Topics[event.Topic])
+}
+
+// matchesKeys returns whether any of the keys is "*" or matches the key or a
+// filter key of the event.
+func matchesKeys(event *structs.Event, keys []string) bool {
+	for _, key := range keys {
+		if key == "*" || key == event.Key {
+			return true
+		}
+		for _, fk := range event.FilterKeys {
+			if key == fk {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents(index uint64, events ...structs.Event) *structs.Events {
+	for i := range events {
+		events[i].Index = index
+	}
+	return &structs.Events{Index: index, Events: events}
+}
+
+func TestEventBroker_Subscribe_Filter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	b := NewEventBroker(10)
+	b.Publish(testEvents(1, structs.Event{Topic: structs.TopicJob, Key: "web", Namespace: "default"}))
+
+	// Index zero only receives events published after subscribing
+	sub := b.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{
+			structs.TopicJob:        {"web"},
+			structs.TopicAllocation: {"web"},
+			structs.TopicNode:       {"*"},
+		},
+		Namespace: "default",
+	})
+
+	b.Publish(testEvents(2,
+		structs.Event{Topic: structs.TopicJob, Key: "web", Namespace: "default"},
+		structs.Event{Topic: structs.TopicJob, Key: "db", Namespace: "default"},
+	))
+	b.Publish(testEvents(3,
+		structs.Event{Topic: structs.TopicAllocation, Key: "a1", Namespace: "default", FilterKeys: []string{"web"}},
+		structs.Event{Topic: structs.TopicAllocation, Key: "a2", Namespace: "other", FilterKeys: []string{"web"}},
+	))
+	b.Publish(testEvents(4,
+		structs.Event{Topic: structs.TopicNode, Key: "n1"},
+	))
+
+	events, err := sub.Next(context.Background())
+	require.NoError(err)
+	require.Equal(uint64(2), events.Index)
+	require.Len(events.Events, 1)
+	require.Equal("web", events.Events[0].Key)
+
+	// Allocations match on their job and namespace
+	events, err = sub.Next(context.Background())
+	require.NoError(err)
+	require.Equal(uint64(3), events.Index)
+	require.Len(events.Events, 1)
+	require.Equal("a1", events.Events[0].Key)
+
+	// Events without a namespace are always received
+	events, err = sub.Next(context.Background())
+	require.NoError(err)
+	require.Equal(uint64(4), events.Index)
+	require.Equal("n1", events.Events[0].Key)
+}
+
+func TestEventBroker_Subscribe_Blocks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	b := NewEventBroker(10)
+	b.Publish(testEvents(5, structs.Event{Topic: structs.TopicJob, Key: "web"}))
+
+	sub := b.Subscribe(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicAll: {"*"}},
+		Namespace: "*",
+		Index:     5,
+	})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		b.Publish(testEvents(6, structs.Event{Topic: structs.TopicNode, Key: "n1"}))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := sub.Next(ctx)
+	require.NoError(err)
+	require.Equal(uint64(6), events.Index)
+	require.Equal("n1", events.Events[0].Key)
+}
+
+func TestEventBroker_Buffer(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	b := NewEventBroker(2)
+	for i := uint64(1); i <= 4; i++ {
+		b.Publish(testEvents(i, structs.Event{Topic: structs.TopicJob, Key: "web"}))
+	}
+	require.Len(b.buffer, 2)
+	require.Equal(uint64(4), b.LastIndex())
+
+	// Subscribers behind the buffer resume from the oldest batch buffered
+	sub := b.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		Index:  1,
+	})
+	events, err := sub.Next(context.Background())
+	require.NoError(err)
+	require.Equal(uint64(3), events.Index)
+}
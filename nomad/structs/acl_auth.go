@@ -0,0 +1,366 @@
+package structs
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// ACLAuthMethodTypeOIDC is the type of the auth methods that log users in
+	// with an OpenID Connect provider.
+	ACLAuthMethodTypeOIDC = "oidc"
+
+	// ACLAuthMethodTypeJWT is the type of the auth methods that exchange a
+	// JWT signed by a trusted issuer, such as the ID token of a CI job, for
+	// an ACL token.
+	ACLAuthMethodTypeJWT = "jwt"
+
+	// maxACLAuthMethodTokenTTL is the longest lifetime of the tokens created
+	// by logging in with an auth method.
+	maxACLAuthMethodTokenTTL = 7 * 24 * time.Hour
+)
+
+// ACLAuthMethod is used to configure how users log in with an identity
+// provider to receive an ACL token.
+type ACLAuthMethod struct {
+	// Name is the unique name of the auth method.
+	Name string
+
+	// Type is the type of the auth method, either "oidc" or "jwt".
+	Type string
+
+	// TokenTTL is how long the tokens created by logging in with the auth
+	// method are valid for.
+	TokenTTL time.Duration
+
+	// Default marks the auth method used when logging in without naming
+	// one. At most one auth method may be the default.
+	Default bool
+
+	Config *ACLAuthMethodConfig
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is the provider specific configuration of an auth
+// method.
+type ACLAuthMethodConfig struct {
+	// OIDCDiscoveryURL is the issuer URL of the OIDC provider. Its
+	// configuration is discovered from the
+	// /.well-known/openid-configuration document below it.
+	OIDCDiscoveryURL string
+
+	// OIDCClientID and OIDCClientSecret are the credentials of the client
+	// registered with the OIDC provider.
+	OIDCClientID     string
+	OIDCClientSecret string
+
+	// OIDCScopes are requested in addition to the "openid" scope.
+	OIDCScopes []string
+
+	// JWKSURL is the URL of the JSON Web Key Set that JWTs are verified
+	// with. Only used by JWT auth methods.
+	JWKSURL string
+
+	// BoundIssuer is the issuer that must be in the "iss" claim of JWTs.
+	// Only used by JWT auth methods.
+	BoundIssuer string
+
+	// BoundAudiences are the audiences of which one must be in the "aud"
+	// claim of the ID token or JWT. For OIDC auth methods it defaults to
+	// the client ID.
+	BoundAudiences []string
+
+	// AllowedRedirectURIs are the redirect URIs a login may use.
+	AllowedRedirectURIs []string
+
+	// BoundClaims are claims that must be present in the ID token with the
+	// given value. The value of a list claim must contain the given value.
+	BoundClaims map[string]string
+
+	// Policies are the ACL policies of every token created by the auth
+	// method.
+	Policies []string
+
+	// GroupsClaim is the claim listing the groups the user is a member of.
+	// GroupPolicies maps each group to the additional policies its members
+	// are given.
+	GroupsClaim   string
+	GroupPolicies map[string][]string
+}
+
+// Copy returns a deep copy of the auth method config.
+func (c *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(ACLAuthMethodConfig)
+	*nc = *c
+	nc.OIDCScopes = helper.CopySliceString(c.OIDCScopes)
+	nc.BoundAudiences = helper.CopySliceString(c.BoundAudiences)
+	nc.AllowedRedirectURIs = helper.CopySliceString(c.AllowedRedirectURIs)
+	nc.BoundClaims = helper.CopyMapStringString(c.BoundClaims)
+	nc.Policies = helper.CopySliceString(c.Policies)
+	if c.GroupPolicies != nil {
+		nc.GroupPolicies = make(map[string][]string, len(c.GroupPolicies))
+		for group, policies := range c.GroupPolicies {
+			nc.GroupPolicies[group] = helper.CopySliceString(policies)
+		}
+	}
+	return nc
+}
+
+// Copy returns a deep copy of the auth method.
+func (a *ACLAuthMethod) Copy() *ACLAuthMethod {
+	if a == nil {
+		return nil
+	}
+
+	na := new(ACLAuthMethod)
+	*na = *a
+	na.Config = a.Config.Copy()
+	if a.Hash != nil {
+		na.Hash = make([]byte, len(a.Hash))
+		copy(na.Hash, a.Hash)
+	}
+	return na
+}
+
+// Validate is used to sanity check an auth method
+func (a *ACLAuthMethod) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(a.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", a.Name))
+	}
+	if a.Type != ACLAuthMethodTypeOIDC && a.Type != ACLAuthMethodTypeJWT {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid type '%s'", a.Type))
+	}
+	if a.TokenTTL <= 0 || a.TokenTTL > maxACLAuthMethodTokenTTL {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token TTL must be greater than 0 and at most %v", maxACLAuthMethodTokenTTL))
+	}
+
+	c := a.Config
+	if c == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing config"))
+		return mErr.ErrorOrNil()
+	}
+	if len(c.GroupPolicies) != 0 && c.GroupsClaim == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("group policies require a groups claim"))
+	}
+
+	if a.Type == ACLAuthMethodTypeJWT {
+		if u, err := url.Parse(c.JWKSURL); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid JWKS URL '%s'", c.JWKSURL))
+		}
+		if len(c.BoundAudiences) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("must specify at least one bound audience"))
+		}
+		return mErr.ErrorOrNil()
+	}
+
+	if u, err := url.Parse(c.OIDCDiscoveryURL); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid OIDC discovery URL '%s'", c.OIDCDiscoveryURL))
+	}
+	if c.OIDCClientID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing OIDC client ID"))
+	}
+	if len(c.AllowedRedirectURIs) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("must specify at least one allowed redirect URI"))
+	}
+	if len(c.Policies) == 0 && len(c.GroupPolicies) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("must specify policies or group policies"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the auth method
+func (a *ACLAuthMethod) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(a.Name))
+	hash.Write([]byte(a.Type))
+	hash.Write([]byte(a.TokenTTL.String()))
+	if a.Default {
+		hash.Write([]byte("default"))
+	}
+	if c := a.Config; c != nil {
+		hash.Write([]byte(c.OIDCDiscoveryURL))
+		hash.Write([]byte(c.OIDCClientID))
+		hash.Write([]byte(c.OIDCClientSecret))
+		for _, s := range c.OIDCScopes {
+			hash.Write([]byte(s))
+		}
+		hash.Write([]byte(c.JWKSURL))
+		hash.Write([]byte(c.BoundIssuer))
+		for _, aud := range c.BoundAudiences {
+			hash.Write([]byte(aud))
+		}
+		for _, uri := range c.AllowedRedirectURIs {
+			hash.Write([]byte(uri))
+		}
+		for _, k := range sortedKeys(c.BoundClaims) {
+			hash.Write([]byte(k))
+			hash.Write([]byte(c.BoundClaims[k]))
+		}
+		for _, p := range c.Policies {
+			hash.Write([]byte(p))
+		}
+		hash.Write([]byte(c.GroupsClaim))
+		groups := make([]string, 0, len(c.GroupPolicies))
+		for group := range c.GroupPolicies {
+			groups = append(groups, group)
+		}
+		sort.Strings(groups)
+		for _, group := range groups {
+			hash.Write([]byte(group))
+			for _, p := range c.GroupPolicies[group] {
+				hash.Write([]byte(p))
+			}
+		}
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	a.Hash = hashVal
+	return hashVal
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Stub returns the auth method without its config.
+func (a *ACLAuthMethod) Stub() *ACLAuthMethodListStub {
+	return &ACLAuthMethodListStub{
+		Name:        a.Name,
+		Type:        a.Type,
+		Default:     a.Default,
+		Hash:        a.Hash,
+		CreateIndex: a.CreateIndex,
+		ModifyIndex: a.ModifyIndex,
+	}
+}
+
+// ACLAuthMethodListStub is used to for listing auth methods
+type ACLAuthMethodListStub struct {
+	Name        string
+	Type        string
+	Default     bool
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodListRequest is used to request a list of auth methods
+type ACLAuthMethodListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodListResponse is used for a list request
+type ACLAuthMethodListResponse struct {
+	AuthMethods []*ACLAuthMethodListStub
+	QueryMeta
+}
+
+// ACLAuthMethodSpecificRequest is used to query a specific auth method
+type ACLAuthMethodSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleACLAuthMethodResponse is used to return a single auth method
+type SingleACLAuthMethodResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodUpsertRequest is used to upsert a set of auth methods
+type ACLAuthMethodUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLAuthMethodDeleteRequest is used to delete a set of auth methods
+type ACLAuthMethodDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLOIDCAuthURLRequest is used to start an OIDC login. The client keeps
+// the state and nonce and sends the nonce back to complete the login.
+type ACLOIDCAuthURLRequest struct {
+	// AuthMethodName is the auth method to log in with. If empty, the
+	// default auth method is used.
+	AuthMethodName string
+
+	// RedirectURI is where the provider sends the user back to with the
+	// authorization code. It must be allowed by the auth method.
+	RedirectURI string
+
+	// ClientNonce is bound to the ID token issued by the provider.
+	ClientNonce string
+
+	// State is returned by the provider with the authorization code, for
+	// the client to match the callback to the login it started.
+	State string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLResponse returns the URL to send the user to for an OIDC
+// login
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is used to exchange the authorization code of
+// an OIDC login for an ACL token
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	RedirectURI    string
+	ClientNonce    string
+	Code           string
+	WriteRequest
+}
+
+// ACLLoginRequest is used to exchange a JWT for an ACL token with a JWT auth
+// method
+type ACLLoginRequest struct {
+	// AuthMethodName is the auth method to log in with. If empty, the
+	// default auth method is used.
+	AuthMethodName string
+
+	// LoginToken is the JWT to verify with the auth method.
+	LoginToken string
+
+	WriteRequest
+}
+
+// ACLLoginResponse returns the ACL token created by a login
+type ACLLoginResponse struct {
+	Token *ACLToken
+	WriteMeta
+}
@@ -0,0 +1,122 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testACLAuthMethod() *ACLAuthMethod {
+	return &ACLAuthMethod{
+		Name:     "okta",
+		Type:     ACLAuthMethodTypeOIDC,
+		TokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			OIDCDiscoveryURL:    "https://example.okta.com",
+			OIDCClientID:        "nomad",
+			AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+			Policies:            []string{"engineering"},
+		},
+	}
+}
+
+func TestACLAuthMethod_Validate(t *testing.T) {
+	require.NoError(t, testACLAuthMethod().Validate())
+
+	cases := []struct {
+		name   string
+		modify func(*ACLAuthMethod)
+		err    string
+	}{
+		{"bad name", func(a *ACLAuthMethod) { a.Name = "okta!" }, "invalid name"},
+		{"bad type", func(a *ACLAuthMethod) { a.Type = "ldap" }, "invalid type"},
+		{"no ttl", func(a *ACLAuthMethod) { a.TokenTTL = 0 }, "token TTL"},
+		{"long ttl", func(a *ACLAuthMethod) { a.TokenTTL = 30 * 24 * time.Hour }, "token TTL"},
+		{"no config", func(a *ACLAuthMethod) { a.Config = nil }, "missing config"},
+		{"bad url", func(a *ACLAuthMethod) { a.Config.OIDCDiscoveryURL = "example.okta.com" }, "invalid OIDC discovery URL"},
+		{"no client id", func(a *ACLAuthMethod) { a.Config.OIDCClientID = "" }, "missing OIDC client ID"},
+		{"no redirect", func(a *ACLAuthMethod) { a.Config.AllowedRedirectURIs = nil }, "redirect URI"},
+		{"no policies", func(a *ACLAuthMethod) { a.Config.Policies = nil }, "must specify policies"},
+		{"no groups claim", func(a *ACLAuthMethod) {
+			a.Config.GroupPolicies = map[string][]string{"admins": {"admin"}}
+		}, "groups claim"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := testACLAuthMethod()
+			c.modify(a)
+			err := a.Validate()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.err)
+		})
+	}
+}
+
+func TestACLAuthMethod_Validate_JWT(t *testing.T) {
+	method := func() *ACLAuthMethod {
+		return &ACLAuthMethod{
+			Name:     "ci",
+			Type:     ACLAuthMethodTypeJWT,
+			TokenTTL: time.Hour,
+			Config: &ACLAuthMethodConfig{
+				JWKSURL:        "https://ci.example.com/.well-known/jwks",
+				BoundIssuer:    "https://ci.example.com",
+				BoundAudiences: []string{"nomad"},
+			},
+		}
+	}
+
+	// Policies are optional since binding rules may grant them
+	require.NoError(t, method().Validate())
+
+	a := method()
+	a.Config.JWKSURL = "ci.example.com"
+	err := a.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid JWKS URL")
+
+	a = method()
+	a.Config.BoundAudiences = nil
+	err = a.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bound audience")
+}
+
+func TestACLAuthMethod_SetHash(t *testing.T) {
+	a := testACLAuthMethod()
+	out1 := a.SetHash()
+	require.NotNil(t, out1)
+	require.Equal(t, out1, a.Hash)
+
+	a.Config.BoundClaims = map[string]string{"team": "engineering"}
+	out2 := a.SetHash()
+	require.Equal(t, out2, a.Hash)
+	require.NotEqual(t, out1, out2)
+}
+
+func TestACLAuthMethod_Copy(t *testing.T) {
+	a := testACLAuthMethod()
+	a.Config.GroupsClaim = "groups"
+	a.Config.GroupPolicies = map[string][]string{"admins": {"admin"}}
+
+	c := a.Copy()
+	require.Equal(t, a, c)
+
+	c.Config.Policies[0] = "other"
+	c.Config.GroupPolicies["admins"][0] = "other"
+	require.Equal(t, "engineering", a.Config.Policies[0])
+	require.Equal(t, "admin", a.Config.GroupPolicies["admins"][0])
+}
+
+func TestACLToken_IsExpired(t *testing.T) {
+	now := time.Now()
+	tk := &ACLToken{}
+	require.False(t, tk.IsExpired(now))
+
+	exp := now.Add(time.Minute)
+	tk.ExpirationTime = &exp
+	require.False(t, tk.IsExpired(now))
+	require.True(t, tk.IsExpired(exp))
+	require.True(t, tk.IsExpired(exp.Add(time.Second)))
+}
@@ -0,0 +1,205 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// ACLBindingRuleBindTypePolicy and ACLBindingRuleBindTypeRole are the
+	// kinds of objects a binding rule grants to the tokens it applies to.
+	ACLBindingRuleBindTypePolicy = "policy"
+	ACLBindingRuleBindTypeRole   = "role"
+
+	// maxBindingRuleDescriptionLength limits a binding rule description
+	// length
+	maxBindingRuleDescriptionLength = 256
+)
+
+// bindingRuleClaimRef matches the ${claim} references interpolated in the
+// bind name of binding rules
+var bindingRuleClaimRef = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// ACLBindingRule grants a policy or role to the tokens created by logging in
+// with an auth method, when the claims of the login match its selector.
+type ACLBindingRule struct {
+	// ID is the unique ID of the binding rule, generated on creation.
+	ID string
+
+	// Description is human readable.
+	Description string
+
+	// AuthMethod is the name of the auth method the rule applies to.
+	AuthMethod string
+
+	// Selector are claims that must be present in the login with the given
+	// value for the rule to apply. The value of a list claim must contain
+	// the given value. An empty selector matches every login.
+	Selector map[string]string
+
+	// BindType is the kind of object granted, either "policy" or "role".
+	BindType string
+
+	// BindName is the name of the granted policy or role. ${claim}
+	// references are replaced with the value of the string claim, and the
+	// rule doesn't apply to logins missing the claim.
+	BindName string
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a deep copy of the binding rule.
+func (r *ACLBindingRule) Copy() *ACLBindingRule {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(ACLBindingRule)
+	*nr = *r
+	nr.Selector = helper.CopyMapStringString(r.Selector)
+	nr.Hash = make([]byte, len(r.Hash))
+	copy(nr.Hash, r.Hash)
+	return nr
+}
+
+// SetHash is used to compute and set the hash of the binding rule
+func (r *ACLBindingRule) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(r.ID))
+	hash.Write([]byte(r.Description))
+	hash.Write([]byte(r.AuthMethod))
+	for _, k := range sortedKeys(r.Selector) {
+		hash.Write([]byte(k))
+		hash.Write([]byte(r.Selector[k]))
+	}
+	hash.Write([]byte(r.BindType))
+	hash.Write([]byte(r.BindName))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	r.Hash = hashVal
+	return hashVal
+}
+
+// Validate is used to sanity check a binding rule
+func (r *ACLBindingRule) Validate() error {
+	var mErr multierror.Error
+	if r.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing auth method"))
+	}
+	if len(r.Description) > maxBindingRuleDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxBindingRuleDescriptionLength))
+	}
+	if r.BindType != ACLBindingRuleBindTypePolicy && r.BindType != ACLBindingRuleBindTypeRole {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid bind type '%s'", r.BindType))
+	}
+
+	// Claim references are checked with a placeholder value, the
+	// interpolated name is checked again on login
+	name := bindingRuleClaimRef.ReplaceAllString(r.BindName, "claim")
+	if !validPolicyName.MatchString(name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid bind name '%s'", r.BindName))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// BindNameFor returns the bind name with its claim references replaced by
+// the string claims of a login. It returns false if a referenced claim is
+// missing or the resulting name is invalid.
+func (r *ACLBindingRule) BindNameFor(claims map[string]interface{}) (string, bool) {
+	ok := true
+	name := bindingRuleClaimRef.ReplaceAllStringFunc(r.BindName, func(ref string) string {
+		v, isString := claims[bindingRuleClaimRef.FindStringSubmatch(ref)[1]].(string)
+		if !isString || v == "" {
+			ok = false
+		}
+		return v
+	})
+	if !ok || !validPolicyName.MatchString(name) {
+		return "", false
+	}
+	return name, true
+}
+
+// Stub returns the binding rule without its selector.
+func (r *ACLBindingRule) Stub() *ACLBindingRuleListStub {
+	return &ACLBindingRuleListStub{
+		ID:          r.ID,
+		Description: r.Description,
+		AuthMethod:  r.AuthMethod,
+		BindType:    r.BindType,
+		BindName:    r.BindName,
+		Hash:        r.Hash,
+		CreateIndex: r.CreateIndex,
+		ModifyIndex: r.ModifyIndex,
+	}
+}
+
+// ACLBindingRuleListStub is used to for listing binding rules
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	BindType    string
+	BindName    string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRuleListRequest is used to request a list of binding rules
+type ACLBindingRuleListRequest struct {
+	// AuthMethod filters the binding rules to those of an auth method
+	AuthMethod string
+	QueryOptions
+}
+
+// ACLBindingRuleListResponse is used for a list request
+type ACLBindingRuleListResponse struct {
+	BindingRules []*ACLBindingRuleListStub
+	QueryMeta
+}
+
+// ACLBindingRuleSpecificRequest is used to query a specific binding rule
+type ACLBindingRuleSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// SingleACLBindingRuleResponse is used to return a single binding rule
+type SingleACLBindingRuleResponse struct {
+	BindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleUpsertRequest is used to upsert a set of binding rules
+type ACLBindingRuleUpsertRequest struct {
+	BindingRules []*ACLBindingRule
+	WriteRequest
+}
+
+// ACLBindingRuleUpsertResponse returns the binding rules with their IDs
+type ACLBindingRuleUpsertResponse struct {
+	BindingRules []*ACLBindingRule
+	WriteMeta
+}
+
+// ACLBindingRuleDeleteRequest is used to delete a set of binding rules
+type ACLBindingRuleDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
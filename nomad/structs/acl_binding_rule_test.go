@@ -0,0 +1,70 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRule_Validate(t *testing.T) {
+	require := require.New(t)
+
+	rule := &ACLBindingRule{}
+	err := rule.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "missing auth method")
+	require.Contains(err.Error(), "invalid bind type")
+	require.Contains(err.Error(), "invalid bind name")
+
+	rule = &ACLBindingRule{
+		AuthMethod: "ci",
+		BindType:   ACLBindingRuleBindTypeRole,
+		BindName:   "deploy ${project}",
+	}
+	err = rule.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid bind name")
+
+	rule.BindName = "deploy-${project}"
+	require.NoError(rule.Validate())
+}
+
+func TestACLBindingRule_BindNameFor(t *testing.T) {
+	require := require.New(t)
+
+	rule := &ACLBindingRule{BindName: "deploy-${project}"}
+	name, ok := rule.BindNameFor(map[string]interface{}{"project": "web"})
+	require.True(ok)
+	require.Equal("deploy-web", name)
+
+	// Missing or non-string claims don't bind
+	_, ok = rule.BindNameFor(map[string]interface{}{})
+	require.False(ok)
+	_, ok = rule.BindNameFor(map[string]interface{}{"project": []interface{}{"web"}})
+	require.False(ok)
+
+	// Claims can't make the name invalid
+	_, ok = rule.BindNameFor(map[string]interface{}{"project": "group/web"})
+	require.False(ok)
+
+	rule.BindName = "deploy"
+	name, ok = rule.BindNameFor(nil)
+	require.True(ok)
+	require.Equal("deploy", name)
+}
+
+func TestACLBindingRule_SetHash(t *testing.T) {
+	rule := &ACLBindingRule{
+		AuthMethod: "ci",
+		BindType:   ACLBindingRuleBindTypePolicy,
+		BindName:   "deploy",
+	}
+	out1 := rule.SetHash()
+	require.NotNil(t, out1)
+	require.Equal(t, out1, rule.Hash)
+
+	rule.Selector = map[string]string{"ref": "refs/heads/main"}
+	out2 := rule.SetHash()
+	require.Equal(t, out2, rule.Hash)
+	require.NotEqual(t, out1, out2)
+}
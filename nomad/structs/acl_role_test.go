@@ -0,0 +1,62 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRole_Validate(t *testing.T) {
+	require := require.New(t)
+
+	role := &ACLRole{}
+	err := role.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+	require.Contains(err.Error(), "missing policies")
+
+	role = &ACLRole{
+		Name:     "ops",
+		Policies: []string{"readonly", "not a policy"},
+	}
+	err = role.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid policy name 'not a policy'")
+
+	role.Policies = []string{"readonly", "submit-web"}
+	require.NoError(role.Validate())
+}
+
+func TestACLRole_SetHash(t *testing.T) {
+	require := require.New(t)
+
+	role := &ACLRole{
+		Name:     "ops",
+		Policies: []string{"readonly"},
+	}
+	out1 := role.SetHash()
+	require.NotEmpty(out1)
+	require.Equal(out1, role.Hash)
+
+	role.Policies = append(role.Policies, "submit-web")
+	out2 := role.SetHash()
+	require.NotEqual(out1, out2)
+
+	// Copies hash equally
+	require.Equal(role.Hash, role.Copy().SetHash())
+}
+
+func TestACLTokenRoleSubset(t *testing.T) {
+	require := require.New(t)
+
+	tk := &ACLToken{
+		Type:  ACLClientToken,
+		Roles: []string{"foo", "bar"},
+	}
+	require.True(tk.RoleSubset([]string{"foo"}))
+	require.True(tk.RoleSubset([]string{"foo", "bar"}))
+	require.False(tk.RoleSubset([]string{"foo", "new"}))
+
+	tk = &ACLToken{Type: ACLManagementToken}
+	require.True(tk.RoleSubset([]string{"foo", "new"}))
+}
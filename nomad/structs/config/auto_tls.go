@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// AutoTLSProviderVault issues certificates with the PKI secrets engine
+	// of Vault
+	AutoTLSProviderVault = "vault"
+
+	// AutoTLSProviderBuiltin issues certificates with a CA whose certificate
+	// and key are managed by the operator
+	AutoTLSProviderBuiltin = "builtin"
+)
+
+// AutoTLSConfig configures the agent to issue its own TLS certificate and to
+// rotate it before it expires. The certificate is valid for the
+// server.<region>.nomad or client.<region>.nomad names of the agent.
+type AutoTLSConfig struct {
+	// Enabled enables automatic certificates.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Provider issues the certificates, either "vault" or "builtin".
+	Provider string `mapstructure:"provider"`
+
+	// Dir is the directory the certificate, key and CA are written to.
+	// Defaults to the tls directory of the agent's data dir.
+	Dir string `mapstructure:"dir"`
+
+	// TTL is how long certificates are valid for. Certificates are renewed
+	// once two thirds of their TTL has elapsed.
+	TTL time.Duration `mapstructure:"ttl"`
+
+	// AltNames are extra DNS names or IP addresses the certificate is valid
+	// for.
+	AltNames []string `mapstructure:"alt_names"`
+
+	// VaultPKIPath is the mount path of the Vault PKI secrets engine.
+	VaultPKIPath string `mapstructure:"vault_pki_path"`
+
+	// VaultRole is the role of the PKI secrets engine certificates are
+	// issued with.
+	VaultRole string `mapstructure:"vault_role"`
+
+	// CAKeyFile is the key of the CA certificate in tls.ca_file, used to
+	// sign certificates with the builtin provider.
+	CAKeyFile string `mapstructure:"ca_key_file"`
+}
+
+// DefaultAutoTLSConfig returns the canonical defaults for the Nomad
+// `tls.auto` configuration.
+func DefaultAutoTLSConfig() *AutoTLSConfig {
+	return &AutoTLSConfig{
+		Provider:     AutoTLSProviderVault,
+		TTL:          72 * time.Hour,
+		VaultPKIPath: "pki",
+	}
+}
+
+// IsEnabled returns whether the config enables automatic certificates.
+func (a *AutoTLSConfig) IsEnabled() bool {
+	return a != nil && a.Enabled != nil && *a.Enabled
+}
+
+// Validate returns an error if enabled automatic certificates are
+// misconfigured.
+func (a *AutoTLSConfig) Validate() error {
+	if !a.IsEnabled() {
+		return nil
+	}
+
+	if a.TTL <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+	switch a.Provider {
+	case AutoTLSProviderVault:
+		if a.VaultRole == "" {
+			return fmt.Errorf("vault_role must be set with the %q provider", a.Provider)
+		}
+	case AutoTLSProviderBuiltin:
+		if a.CAKeyFile == "" {
+			return fmt.Errorf("ca_key_file must be set with the %q provider", a.Provider)
+		}
+	default:
+		return fmt.Errorf("unknown provider %q", a.Provider)
+	}
+	return nil
+}
+
+// Merge merges two automatic certificate configurations together.
+func (a *AutoTLSConfig) Merge(b *AutoTLSConfig) *AutoTLSConfig {
+	result := a.Copy()
+
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+	if b.Provider != "" {
+		result.Provider = b.Provider
+	}
+	if b.Dir != "" {
+		result.Dir = b.Dir
+	}
+	if b.TTL != 0 {
+		result.TTL = b.TTL
+	}
+	if len(b.AltNames) != 0 {
+		result.AltNames = helper.CopySliceString(b.AltNames)
+	}
+	if b.VaultPKIPath != "" {
+		result.VaultPKIPath = b.VaultPKIPath
+	}
+	if b.VaultRole != "" {
+		result.VaultRole = b.VaultRole
+	}
+	if b.CAKeyFile != "" {
+		result.CAKeyFile = b.CAKeyFile
+	}
+
+	return result
+}
+
+// Copy returns a copy of this automatic certificate config.
+func (a *AutoTLSConfig) Copy() *AutoTLSConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(AutoTLSConfig)
+	*nc = *a
+
+	if a.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*a.Enabled)
+	}
+	nc.AltNames = helper.CopySliceString(a.AltNames)
+
+	return nc
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoTLSConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := DefaultAutoTLSConfig()
+	c2 := &AutoTLSConfig{
+		Enabled:   helper.BoolToPtr(true),
+		Provider:  AutoTLSProviderBuiltin,
+		TTL:       24 * time.Hour,
+		AltNames:  []string{"nomad.example.com"},
+		CAKeyFile: "/etc/nomad/ca-key.pem",
+	}
+
+	e := &AutoTLSConfig{
+		Enabled:      helper.BoolToPtr(true),
+		Provider:     AutoTLSProviderBuiltin,
+		TTL:          24 * time.Hour,
+		AltNames:     []string{"nomad.example.com"},
+		VaultPKIPath: "pki",
+		CAKeyFile:    "/etc/nomad/ca-key.pem",
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(e, result)
+
+	// Merging must not modify the inputs
+	require.Nil(c1.Enabled)
+	require.Empty(c1.AltNames)
+}
+
+func TestAutoTLSConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *AutoTLSConfig
+		err    string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name:   "disabled",
+			config: DefaultAutoTLSConfig(),
+		},
+		{
+			name: "vault",
+			config: &AutoTLSConfig{
+				Enabled:   helper.BoolToPtr(true),
+				Provider:  AutoTLSProviderVault,
+				TTL:       time.Hour,
+				VaultRole: "nomad",
+			},
+		},
+		{
+			name: "vault without role",
+			config: &AutoTLSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Provider: AutoTLSProviderVault,
+				TTL:      time.Hour,
+			},
+			err: "vault_role",
+		},
+		{
+			name: "builtin without key",
+			config: &AutoTLSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Provider: AutoTLSProviderBuiltin,
+				TTL:      time.Hour,
+			},
+			err: "ca_key_file",
+		},
+		{
+			name: "unknown provider",
+			config: &AutoTLSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Provider: "acme",
+				TTL:      time.Hour,
+			},
+			err: "unknown provider",
+		},
+		{
+			name: "no ttl",
+			config: &AutoTLSConfig{
+				Enabled:   helper.BoolToPtr(true),
+				Provider:  AutoTLSProviderVault,
+				VaultRole: "nomad",
+			},
+			err: "ttl",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// ClientRelayConfig configures how a server relays the filesystem, logs and
+// exec requests of API consumers that can't reach the HTTP endpoints of the
+// clients directly.
+type ClientRelayConfig struct {
+	// Enabled allows the server to relay requests to the clients. Relaying is
+	// enabled by default.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// RateLimit is the number of requests per second the server relays to
+	// the clients. A value of zero doesn't limit the rate of requests.
+	RateLimit float64 `mapstructure:"rate_limit"`
+
+	// Burst is the number of requests that can be relayed at once above the
+	// rate limit. It defaults to the rate limit, rounded up.
+	Burst int `mapstructure:"burst"`
+}
+
+// DefaultClientRelayConfig returns the canonical defaults for the Nomad
+// `client_relay` configuration.
+func DefaultClientRelayConfig() *ClientRelayConfig {
+	return &ClientRelayConfig{
+		Enabled: helper.BoolToPtr(true),
+	}
+}
+
+// IsEnabled returns whether the config allows relaying requests to the
+// clients.
+func (c *ClientRelayConfig) IsEnabled() bool {
+	return c == nil || c.Enabled == nil || *c.Enabled
+}
+
+// Validate returns an error if the client relay is misconfigured.
+func (c *ClientRelayConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.RateLimit < 0 {
+		return fmt.Errorf("rate_limit must not be negative")
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
+	}
+	if c.Burst > 0 && c.RateLimit == 0 {
+		return fmt.Errorf("burst requires a rate_limit")
+	}
+	return nil
+}
+
+// Merge merges two client relay configurations together.
+func (c *ClientRelayConfig) Merge(b *ClientRelayConfig) *ClientRelayConfig {
+	result := c.Copy()
+
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+	if b.RateLimit != 0 {
+		result.RateLimit = b.RateLimit
+	}
+	if b.Burst != 0 {
+		result.Burst = b.Burst
+	}
+
+	return result
+}
+
+// Copy returns a copy of this client relay config.
+func (c *ClientRelayConfig) Copy() *ClientRelayConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(ClientRelayConfig)
+	*nc = *c
+
+	if c.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*c.Enabled)
+	}
+
+	return nc
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRelayConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := DefaultClientRelayConfig()
+	c2 := &ClientRelayConfig{
+		Enabled:   helper.BoolToPtr(false),
+		RateLimit: 2.5,
+		Burst:     5,
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(c2, result)
+	require.False(result.IsEnabled())
+
+	// Merging must not modify the inputs
+	require.True(c1.IsEnabled())
+	require.Zero(c1.RateLimit)
+}
+
+func TestClientRelayConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *ClientRelayConfig
+		err    string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name:   "default",
+			config: DefaultClientRelayConfig(),
+		},
+		{
+			name: "negative rate limit",
+			config: &ClientRelayConfig{
+				RateLimit: -1,
+			},
+			err: "rate_limit must not be negative",
+		},
+		{
+			name: "negative burst",
+			config: &ClientRelayConfig{
+				RateLimit: 1,
+				Burst:     -1,
+			},
+			err: "burst must not be negative",
+		},
+		{
+			name: "burst without rate limit",
+			config: &ClientRelayConfig{
+				Burst: 10,
+			},
+			err: "burst requires a rate_limit",
+		},
+		{
+			name: "valid",
+			config: &ClientRelayConfig{
+				Enabled:   helper.BoolToPtr(true),
+				RateLimit: 0.5,
+				Burst:     10,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
@@ -19,6 +19,10 @@ import (
 //
 // Both the Agent and the executor need to be able to import ConsulConfig.
 type ConsulConfig struct {
+	// Name is the name of the Consul cluster that jobs select it by. Empty
+	// for the default cluster.
+	Name string `mapstructure:"name"`
+
 	// ServerServiceName is the name of the service that Nomad uses to register
 	// servers with Consul
 	ServerServiceName string `mapstructure:"server_service_name"`
@@ -114,6 +118,9 @@ func DefaultConsulConfig() *ConsulConfig {
 func (a *ConsulConfig) Merge(b *ConsulConfig) *ConsulConfig {
 	result := a.Copy()
 
+	if b.Name != "" {
+		result.Name = b.Name
+	}
 	if b.ServerServiceName != "" {
 		result.ServerServiceName = b.ServerServiceName
 	}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// ExternalDNSConfig configures the server-side controller that publishes the
+// addresses of services registered with the Nomad service provider to an
+// external DNS provider while the server is the cluster leader.
+type ExternalDNSConfig struct {
+	// Enabled enables the external DNS controller.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Zone is the DNS zone the records are published under. Nomad manages
+	// every A and AAAA record two labels below the zone, so the zone should
+	// be dedicated to Nomad.
+	Zone string `mapstructure:"zone"`
+
+	// TTL is the TTL of the published records.
+	TTL time.Duration `mapstructure:"ttl"`
+
+	// Interval is how often the records are fully resynchronized with the
+	// provider, in addition to whenever the registrations change.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Route53 publishes the records to an AWS Route 53 hosted zone.
+	Route53 *ExternalDNSRoute53Config `mapstructure:"route53"`
+
+	// CoreDNS publishes the records to the etcd backend of CoreDNS.
+	CoreDNS *ExternalDNSCoreDNSConfig `mapstructure:"coredns"`
+}
+
+// ExternalDNSRoute53Config configures AWS Route 53 as the external DNS
+// provider.
+type ExternalDNSRoute53Config struct {
+	// HostedZoneID is the ID of the hosted zone containing the zone.
+	HostedZoneID string `mapstructure:"hosted_zone_id"`
+
+	// Endpoint overrides the Route 53 endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// AccessKeyID and SecretAccessKey are static credentials. If unset, the
+	// default AWS credential chain is used.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+}
+
+// ExternalDNSCoreDNSConfig configures the etcd backend of CoreDNS as the
+// external DNS provider.
+type ExternalDNSCoreDNSConfig struct {
+	// EtcdAddress is the address of the etcd v3 HTTP API.
+	EtcdAddress string `mapstructure:"etcd_address"`
+
+	// PathPrefix is the etcd key prefix CoreDNS reads records from.
+	PathPrefix string `mapstructure:"path_prefix"`
+}
+
+// DefaultExternalDNSConfig returns the canonical defaults for the Nomad
+// `external_dns` configuration.
+func DefaultExternalDNSConfig() *ExternalDNSConfig {
+	return &ExternalDNSConfig{
+		TTL:      30 * time.Second,
+		Interval: 5 * time.Minute,
+	}
+}
+
+// IsEnabled returns whether the config enables the external DNS controller.
+func (a *ExternalDNSConfig) IsEnabled() bool {
+	return a != nil && a.Enabled != nil && *a.Enabled
+}
+
+// Validate returns an error if an enabled external DNS controller is
+// misconfigured.
+func (a *ExternalDNSConfig) Validate() error {
+	if !a.IsEnabled() {
+		return nil
+	}
+
+	zone := strings.TrimSuffix(a.Zone, ".")
+	if zone == "" || strings.HasPrefix(zone, ".") || strings.Contains(zone, "..") {
+		return fmt.Errorf("invalid zone %q", a.Zone)
+	}
+	if a.TTL < time.Second {
+		return fmt.Errorf("ttl must be at least 1s")
+	}
+	if a.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	if (a.Route53 == nil) == (a.CoreDNS == nil) {
+		return fmt.Errorf("exactly one of route53 or coredns must be set")
+	}
+	if a.Route53 != nil && a.Route53.HostedZoneID == "" {
+		return fmt.Errorf("route53 hosted_zone_id must be set")
+	}
+	if a.CoreDNS != nil {
+		u, err := url.Parse(a.CoreDNS.EtcdAddress)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid coredns etcd_address %q", a.CoreDNS.EtcdAddress)
+		}
+	}
+	return nil
+}
+
+// Merge merges two external DNS configurations together.
+func (a *ExternalDNSConfig) Merge(b *ExternalDNSConfig) *ExternalDNSConfig {
+	result := a.Copy()
+
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+	if b.Zone != "" {
+		result.Zone = b.Zone
+	}
+	if b.TTL != 0 {
+		result.TTL = b.TTL
+	}
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if result.Route53 == nil && b.Route53 != nil {
+		result.Route53 = b.Route53.Copy()
+	} else if b.Route53 != nil {
+		result.Route53 = result.Route53.Merge(b.Route53)
+	}
+	if result.CoreDNS == nil && b.CoreDNS != nil {
+		result.CoreDNS = b.CoreDNS.Copy()
+	} else if b.CoreDNS != nil {
+		result.CoreDNS = result.CoreDNS.Merge(b.CoreDNS)
+	}
+
+	return result
+}
+
+// Copy returns a copy of this external DNS config.
+func (a *ExternalDNSConfig) Copy() *ExternalDNSConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(ExternalDNSConfig)
+	*nc = *a
+
+	if a.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*a.Enabled)
+	}
+	nc.Route53 = a.Route53.Copy()
+	nc.CoreDNS = a.CoreDNS.Copy()
+
+	return nc
+}
+
+// Merge merges two Route 53 configurations together.
+func (a *ExternalDNSRoute53Config) Merge(b *ExternalDNSRoute53Config) *ExternalDNSRoute53Config {
+	result := a.Copy()
+
+	if b.HostedZoneID != "" {
+		result.HostedZoneID = b.HostedZoneID
+	}
+	if b.Endpoint != "" {
+		result.Endpoint = b.Endpoint
+	}
+	if b.AccessKeyID != "" {
+		result.AccessKeyID = b.AccessKeyID
+	}
+	if b.SecretAccessKey != "" {
+		result.SecretAccessKey = b.SecretAccessKey
+	}
+
+	return result
+}
+
+// Copy returns a copy of this Route 53 config.
+func (a *ExternalDNSRoute53Config) Copy() *ExternalDNSRoute53Config {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(ExternalDNSRoute53Config)
+	*nc = *a
+	return nc
+}
+
+// Merge merges two CoreDNS configurations together.
+func (a *ExternalDNSCoreDNSConfig) Merge(b *ExternalDNSCoreDNSConfig) *ExternalDNSCoreDNSConfig {
+	result := a.Copy()
+
+	if b.EtcdAddress != "" {
+		result.EtcdAddress = b.EtcdAddress
+	}
+	if b.PathPrefix != "" {
+		result.PathPrefix = b.PathPrefix
+	}
+
+	return result
+}
+
+// Copy returns a copy of this CoreDNS config.
+func (a *ExternalDNSCoreDNSConfig) Copy() *ExternalDNSCoreDNSConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(ExternalDNSCoreDNSConfig)
+	*nc = *a
+	return nc
+}
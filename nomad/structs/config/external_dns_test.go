@@ -0,0 +1,139 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalDNSConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := &ExternalDNSConfig{
+		Enabled:  helper.BoolToPtr(false),
+		Zone:     "nomad.example.com",
+		TTL:      30 * time.Second,
+		Interval: 5 * time.Minute,
+	}
+
+	c2 := &ExternalDNSConfig{
+		Enabled: helper.BoolToPtr(true),
+		TTL:     10 * time.Second,
+		Route53: &ExternalDNSRoute53Config{
+			HostedZoneID: "Z123",
+		},
+	}
+
+	e := &ExternalDNSConfig{
+		Enabled:  helper.BoolToPtr(true),
+		Zone:     "nomad.example.com",
+		TTL:      10 * time.Second,
+		Interval: 5 * time.Minute,
+		Route53: &ExternalDNSRoute53Config{
+			HostedZoneID: "Z123",
+		},
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(e, result)
+
+	// Merging must not modify the inputs
+	require.False(*c1.Enabled)
+	require.Nil(c1.Route53)
+}
+
+func TestExternalDNSConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *ExternalDNSConfig
+		err    string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name: "disabled",
+			config: &ExternalDNSConfig{
+				Enabled: helper.BoolToPtr(false),
+			},
+		},
+		{
+			name: "no zone",
+			config: &ExternalDNSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				TTL:      time.Minute,
+				Interval: time.Minute,
+			},
+			err: "invalid zone",
+		},
+		{
+			name: "no provider",
+			config: &ExternalDNSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Zone:     "nomad.example.com",
+				TTL:      time.Minute,
+				Interval: time.Minute,
+			},
+			err: "exactly one of route53 or coredns",
+		},
+		{
+			name: "both providers",
+			config: &ExternalDNSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Zone:     "nomad.example.com",
+				TTL:      time.Minute,
+				Interval: time.Minute,
+				Route53:  &ExternalDNSRoute53Config{HostedZoneID: "Z123"},
+				CoreDNS:  &ExternalDNSCoreDNSConfig{EtcdAddress: "http://127.0.0.1:2379"},
+			},
+			err: "exactly one of route53 or coredns",
+		},
+		{
+			name: "no hosted zone",
+			config: &ExternalDNSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Zone:     "nomad.example.com",
+				TTL:      time.Minute,
+				Interval: time.Minute,
+				Route53:  &ExternalDNSRoute53Config{},
+			},
+			err: "hosted_zone_id must be set",
+		},
+		{
+			name: "bad etcd address",
+			config: &ExternalDNSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Zone:     "nomad.example.com",
+				TTL:      time.Minute,
+				Interval: time.Minute,
+				CoreDNS:  &ExternalDNSCoreDNSConfig{EtcdAddress: "127.0.0.1:2379"},
+			},
+			err: "invalid coredns etcd_address",
+		},
+		{
+			name: "valid",
+			config: &ExternalDNSConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Zone:     "nomad.example.com.",
+				TTL:      time.Minute,
+				Interval: time.Minute,
+				CoreDNS:  &ExternalDNSCoreDNSConfig{EtcdAddress: "http://127.0.0.1:2379"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
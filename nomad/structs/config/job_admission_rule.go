@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// JobAdmissionRuleConfig restricts the datacenters, node classes and drivers
+// the jobs of a namespace may use. Empty lists leave the corresponding
+// dimension unrestricted.
+type JobAdmissionRuleConfig struct {
+	// Namespace is the namespace whose jobs the rule applies to.
+	Namespace string `mapstructure:"-"`
+
+	// Datacenters are the datacenters the jobs may be registered in.
+	Datacenters []string `mapstructure:"datacenters"`
+
+	// NodeClasses are the node classes the jobs may be placed on.
+	NodeClasses []string `mapstructure:"node_classes"`
+
+	// Drivers are the task drivers the jobs may use.
+	Drivers []string `mapstructure:"drivers"`
+}
+
+// Validate returns an error if the rule is misconfigured.
+func (r *JobAdmissionRuleConfig) Validate() error {
+	if r.Namespace == "" {
+		return fmt.Errorf("rule must be labeled with a namespace")
+	}
+	if len(r.Datacenters) == 0 && len(r.NodeClasses) == 0 && len(r.Drivers) == 0 {
+		return fmt.Errorf("rule %q: at least one of datacenters, node_classes or drivers must be set", r.Namespace)
+	}
+	return nil
+}
+
+// Copy returns a copy of this rule config.
+func (r *JobAdmissionRuleConfig) Copy() *JobAdmissionRuleConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(JobAdmissionRuleConfig)
+	*nr = *r
+	nr.Datacenters = helper.CopySliceString(r.Datacenters)
+	nr.NodeClasses = helper.CopySliceString(r.NodeClasses)
+	nr.Drivers = helper.CopySliceString(r.Drivers)
+	return nr
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobAdmissionRuleConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *JobAdmissionRuleConfig
+		err    string
+	}{
+		{
+			name:   "no namespace",
+			config: &JobAdmissionRuleConfig{Drivers: []string{"docker"}},
+			err:    "labeled with a namespace",
+		},
+		{
+			name:   "no restriction",
+			config: &JobAdmissionRuleConfig{Namespace: "untrusted"},
+			err:    "at least one of",
+		},
+		{
+			name: "valid",
+			config: &JobAdmissionRuleConfig{
+				Namespace:   "untrusted",
+				Datacenters: []string{"dc2"},
+				Drivers:     []string{"docker"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
+
+func TestJobAdmissionRuleConfig_Copy(t *testing.T) {
+	r := &JobAdmissionRuleConfig{
+		Namespace:   "untrusted",
+		Datacenters: []string{"dc2"},
+		NodeClasses: []string{"sandbox"},
+		Drivers:     []string{"docker"},
+	}
+	c := r.Copy()
+	require.Equal(t, r, c)
+
+	c.Drivers[0] = "exec"
+	require.Equal(t, "docker", r.Drivers[0])
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// JobAdmissionWebhookMutating webhooks may modify the job being
+	// registered as well as reject it.
+	JobAdmissionWebhookMutating = "mutating"
+
+	// JobAdmissionWebhookValidating webhooks may only accept or reject the
+	// job being registered.
+	JobAdmissionWebhookValidating = "validating"
+
+	// JobAdmissionWebhookFailurePolicyFail rejects the job if the webhook
+	// can't be reached or returns an invalid response.
+	JobAdmissionWebhookFailurePolicyFail = "fail"
+
+	// JobAdmissionWebhookFailurePolicyIgnore admits the job with a warning if
+	// the webhook can't be reached or returns an invalid response.
+	JobAdmissionWebhookFailurePolicyIgnore = "ignore"
+
+	// defaultJobAdmissionWebhookTimeout is how long a webhook call may take
+	// if no timeout is configured.
+	defaultJobAdmissionWebhookTimeout = 10 * time.Second
+)
+
+// JobAdmissionWebhookConfig configures an external webhook that jobs are sent
+// to when they are registered.
+type JobAdmissionWebhookConfig struct {
+	// Name identifies the webhook in errors, warnings and logs.
+	Name string `mapstructure:"-"`
+
+	// Type is either "mutating" or "validating".
+	Type string `mapstructure:"type"`
+
+	// URL is the address the job is POSTed to.
+	URL string `mapstructure:"url"`
+
+	// Timeout bounds how long a call to the webhook may take.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// FailurePolicy is either "fail" or "ignore" and controls what happens
+	// to the job if the webhook can't be called.
+	FailurePolicy string `mapstructure:"failure_policy"`
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (w *JobAdmissionWebhookConfig) Canonicalize() {
+	if w.Timeout == 0 {
+		w.Timeout = defaultJobAdmissionWebhookTimeout
+	}
+	if w.FailurePolicy == "" {
+		w.FailurePolicy = JobAdmissionWebhookFailurePolicyFail
+	}
+}
+
+// Validate returns an error if the webhook is misconfigured.
+func (w *JobAdmissionWebhookConfig) Validate() error {
+	switch w.Type {
+	case JobAdmissionWebhookMutating, JobAdmissionWebhookValidating:
+	default:
+		return fmt.Errorf("webhook %q: type must be %q or %q", w.Name,
+			JobAdmissionWebhookMutating, JobAdmissionWebhookValidating)
+	}
+
+	if u, err := url.Parse(w.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("webhook %q: invalid url %q", w.Name, w.URL)
+	}
+	if w.Timeout < 0 {
+		return fmt.Errorf("webhook %q: timeout must not be negative", w.Name)
+	}
+
+	switch w.FailurePolicy {
+	case "", JobAdmissionWebhookFailurePolicyFail, JobAdmissionWebhookFailurePolicyIgnore:
+	default:
+		return fmt.Errorf("webhook %q: failure_policy must be %q or %q", w.Name,
+			JobAdmissionWebhookFailurePolicyFail, JobAdmissionWebhookFailurePolicyIgnore)
+	}
+	return nil
+}
+
+// Copy returns a copy of this webhook config.
+func (w *JobAdmissionWebhookConfig) Copy() *JobAdmissionWebhookConfig {
+	if w == nil {
+		return nil
+	}
+
+	nw := new(JobAdmissionWebhookConfig)
+	*nw = *w
+	return nw
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobAdmissionWebhookConfig_Canonicalize(t *testing.T) {
+	w := &JobAdmissionWebhookConfig{
+		Name: "meta",
+		Type: JobAdmissionWebhookValidating,
+		URL:  "https://example.com/validate",
+	}
+	w.Canonicalize()
+	require.Equal(t, 10*time.Second, w.Timeout)
+	require.Equal(t, JobAdmissionWebhookFailurePolicyFail, w.FailurePolicy)
+}
+
+func TestJobAdmissionWebhookConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *JobAdmissionWebhookConfig
+		err    string
+	}{
+		{
+			name: "bad type",
+			config: &JobAdmissionWebhookConfig{
+				Name: "foo",
+				Type: "foo",
+				URL:  "https://example.com",
+			},
+			err: "type must be",
+		},
+		{
+			name: "bad url",
+			config: &JobAdmissionWebhookConfig{
+				Name: "foo",
+				Type: JobAdmissionWebhookMutating,
+				URL:  "example.com",
+			},
+			err: "invalid url",
+		},
+		{
+			name: "bad failure policy",
+			config: &JobAdmissionWebhookConfig{
+				Name:          "foo",
+				Type:          JobAdmissionWebhookValidating,
+				URL:           "https://example.com",
+				FailurePolicy: "open",
+			},
+			err: "failure_policy must be",
+		},
+		{
+			name: "valid",
+			config: &JobAdmissionWebhookConfig{
+				Name:          "foo",
+				Type:          JobAdmissionWebhookMutating,
+				URL:           "http://127.0.0.1:8080/mutate",
+				Timeout:       time.Second,
+				FailurePolicy: JobAdmissionWebhookFailurePolicyIgnore,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
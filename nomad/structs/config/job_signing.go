@@ -0,0 +1,87 @@
+package config
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// JobSigningConfig configures the verification of the detached signatures of
+// the jobs registered in a namespace.
+type JobSigningConfig struct {
+	// Namespace is the namespace whose jobs are verified.
+	Namespace string `mapstructure:"-"`
+
+	// Required rejects the jobs of the namespace that aren't signed. Signed
+	// jobs are always verified.
+	Required bool `mapstructure:"required"`
+
+	// Signers are the trusted signers of the jobs of the namespace.
+	Signers []*JobSignerConfig `mapstructure:"-"`
+}
+
+// JobSignerConfig is a trusted signer of jobs.
+type JobSignerConfig struct {
+	// Name identifies the signer and is recorded in the metadata of the jobs
+	// it signed.
+	Name string `mapstructure:"-"`
+
+	// PublicKey is the PEM encoded public key of the signer. Ed25519, ECDSA
+	// and RSA keys are supported.
+	PublicKey string `mapstructure:"public_key"`
+}
+
+// ParsePublicKey returns the parsed public key of the signer.
+func (s *JobSignerConfig) ParsePublicKey() (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("signer %q: public_key is not PEM encoded", s.Name)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("signer %q: invalid public_key: %v", s.Name, err)
+	}
+	return key, nil
+}
+
+// Validate returns an error if the job signing config is misconfigured.
+func (c *JobSigningConfig) Validate() error {
+	if c.Namespace == "" {
+		return fmt.Errorf("missing namespace")
+	}
+	if len(c.Signers) == 0 {
+		return fmt.Errorf("namespace %q: at least one signer is required", c.Namespace)
+	}
+
+	names := make(map[string]struct{}, len(c.Signers))
+	for _, signer := range c.Signers {
+		if _, ok := names[signer.Name]; ok {
+			return fmt.Errorf("namespace %q: duplicate signer %q", c.Namespace, signer.Name)
+		}
+		names[signer.Name] = struct{}{}
+
+		if _, err := signer.ParsePublicKey(); err != nil {
+			return fmt.Errorf("namespace %q: %v", c.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// Copy returns a copy of this job signing config.
+func (c *JobSigningConfig) Copy() *JobSigningConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(JobSigningConfig)
+	*nc = *c
+	if c.Signers != nil {
+		nc.Signers = make([]*JobSignerConfig, len(c.Signers))
+		for i, signer := range c.Signers {
+			ns := *signer
+			nc.Signers[i] = &ns
+		}
+	}
+	return nc
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testJobSignerPublicKey = `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEADXFRXX3lQICIxRalPeaiiKaD7jSQQ6fxtn8MuM98ook=
+-----END PUBLIC KEY-----
+`
+
+func TestJobSigningConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *JobSigningConfig
+		err    string
+	}{
+		{
+			name: "valid",
+			config: &JobSigningConfig{
+				Namespace: "default",
+				Signers: []*JobSignerConfig{
+					{Name: "release-bot", PublicKey: testJobSignerPublicKey},
+				},
+			},
+		},
+		{
+			name: "no signers",
+			config: &JobSigningConfig{
+				Namespace: "default",
+				Required:  true,
+			},
+			err: "at least one signer",
+		},
+		{
+			name: "duplicate signer",
+			config: &JobSigningConfig{
+				Namespace: "default",
+				Signers: []*JobSignerConfig{
+					{Name: "release-bot", PublicKey: testJobSignerPublicKey},
+					{Name: "release-bot", PublicKey: testJobSignerPublicKey},
+				},
+			},
+			err: "duplicate signer",
+		},
+		{
+			name: "not PEM",
+			config: &JobSigningConfig{
+				Namespace: "default",
+				Signers: []*JobSignerConfig{
+					{Name: "release-bot", PublicKey: "foo"},
+				},
+			},
+			err: "not PEM encoded",
+		},
+		{
+			name: "bad key",
+			config: &JobSigningConfig{
+				Namespace: "default",
+				Signers: []*JobSignerConfig{
+					{Name: "release-bot", PublicKey: "-----BEGIN PUBLIC KEY-----\nZm9v\n-----END PUBLIC KEY-----\n"},
+				},
+			},
+			err: "invalid public_key",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
+
+func TestJobSigningConfig_Copy(t *testing.T) {
+	c := &JobSigningConfig{
+		Namespace: "default",
+		Signers: []*JobSignerConfig{
+			{Name: "release-bot", PublicKey: testJobSignerPublicKey},
+		},
+	}
+	nc := c.Copy()
+	require.Equal(t, c, nc)
+
+	nc.Signers[0].Name = "other"
+	require.Equal(t, "release-bot", c.Signers[0].Name)
+}
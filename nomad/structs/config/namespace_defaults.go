@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+)
+
+// NamespaceDefaultsConfig sets the Vault and Consul clusters used by the jobs
+// of a namespace that don't select a cluster themselves.
+type NamespaceDefaultsConfig struct {
+	// Namespace is the namespace whose jobs the defaults apply to.
+	Namespace string `mapstructure:"-"`
+
+	// VaultCluster is the name of the Vault cluster of the tasks with a vault
+	// block.
+	VaultCluster string `mapstructure:"vault_cluster"`
+
+	// ConsulCluster is the name of the Consul cluster the services of the
+	// task groups are registered in.
+	ConsulCluster string `mapstructure:"consul_cluster"`
+}
+
+// Validate returns an error if the defaults are misconfigured.
+func (d *NamespaceDefaultsConfig) Validate() error {
+	if d.Namespace == "" {
+		return fmt.Errorf("defaults must be labeled with a namespace")
+	}
+	if d.VaultCluster == "" && d.ConsulCluster == "" {
+		return fmt.Errorf("defaults %q: at least one of vault_cluster or consul_cluster must be set", d.Namespace)
+	}
+	return nil
+}
+
+// Copy returns a copy of this defaults config.
+func (d *NamespaceDefaultsConfig) Copy() *NamespaceDefaultsConfig {
+	if d == nil {
+		return nil
+	}
+
+	nd := new(NamespaceDefaultsConfig)
+	*nd = *d
+	return nd
+}
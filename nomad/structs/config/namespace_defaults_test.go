@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceDefaultsConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *NamespaceDefaultsConfig
+		err    string
+	}{
+		{
+			name:   "no namespace",
+			config: &NamespaceDefaultsConfig{VaultCluster: "team-a"},
+			err:    "labeled with a namespace",
+		},
+		{
+			name:   "no cluster",
+			config: &NamespaceDefaultsConfig{Namespace: "team-a"},
+			err:    "at least one of",
+		},
+		{
+			name: "valid",
+			config: &NamespaceDefaultsConfig{
+				Namespace:     "team-a",
+				VaultCluster:  "vault-a",
+				ConsulCluster: "consul-a",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
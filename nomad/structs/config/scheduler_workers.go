@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// SchedulerWorkersConfig configures how the scheduling workers of a server are
+// assigned to the scheduler types and scaled with the depth of the evaluation
+// broker.
+type SchedulerWorkersConfig struct {
+	// Pinned is the number of workers dedicated to each scheduler type, such
+	// as "service" or "batch". Pinned workers only dequeue evaluations of
+	// their scheduler, so a flood of evaluations of another type can't starve
+	// them. They are started in addition to the shared workers.
+	Pinned map[string]int `mapstructure:"pinned"`
+
+	// Autoscale enables starting additional shared workers while evaluations
+	// are waiting in the broker and stopping them once it is drained.
+	Autoscale *bool `mapstructure:"autoscale"`
+
+	// MaxWorkers is the maximum number of shared workers when autoscaling. A
+	// value of zero allows twice the number of schedulers of the server.
+	MaxWorkers int `mapstructure:"max_workers"`
+
+	// ReadyPerWorker is the number of ready evaluations per shared worker
+	// above which another worker is started.
+	ReadyPerWorker int `mapstructure:"ready_per_worker"`
+
+	// ScaleInterval is how often the number of shared workers is adjusted.
+	ScaleInterval time.Duration `mapstructure:"scale_interval"`
+}
+
+// DefaultSchedulerWorkersConfig returns the canonical defaults for the Nomad
+// `scheduler_workers` configuration.
+func DefaultSchedulerWorkersConfig() *SchedulerWorkersConfig {
+	return &SchedulerWorkersConfig{
+		ReadyPerWorker: 10,
+		ScaleInterval:  10 * time.Second,
+	}
+}
+
+// AutoscaleEnabled returns whether the config enables autoscaling the shared
+// workers.
+func (c *SchedulerWorkersConfig) AutoscaleEnabled() bool {
+	return c != nil && c.Autoscale != nil && *c.Autoscale
+}
+
+// Validate returns an error if the scheduler workers are misconfigured.
+func (c *SchedulerWorkersConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	for sched, count := range c.Pinned {
+		if count < 0 {
+			return fmt.Errorf("pinned workers of scheduler %q must not be negative", sched)
+		}
+	}
+	if c.MaxWorkers < 0 {
+		return fmt.Errorf("max_workers must not be negative")
+	}
+
+	if !c.AutoscaleEnabled() {
+		return nil
+	}
+	if c.ReadyPerWorker <= 0 {
+		return fmt.Errorf("ready_per_worker must be positive")
+	}
+	if c.ScaleInterval <= 0 {
+		return fmt.Errorf("scale_interval must be positive")
+	}
+	return nil
+}
+
+// Merge merges two scheduler workers configurations together.
+func (c *SchedulerWorkersConfig) Merge(b *SchedulerWorkersConfig) *SchedulerWorkersConfig {
+	result := c.Copy()
+
+	for sched, count := range b.Pinned {
+		if result.Pinned == nil {
+			result.Pinned = make(map[string]int, len(b.Pinned))
+		}
+		result.Pinned[sched] = count
+	}
+	if b.Autoscale != nil {
+		result.Autoscale = helper.BoolToPtr(*b.Autoscale)
+	}
+	if b.MaxWorkers != 0 {
+		result.MaxWorkers = b.MaxWorkers
+	}
+	if b.ReadyPerWorker != 0 {
+		result.ReadyPerWorker = b.ReadyPerWorker
+	}
+	if b.ScaleInterval != 0 {
+		result.ScaleInterval = b.ScaleInterval
+	}
+
+	return result
+}
+
+// Copy returns a copy of this scheduler workers config.
+func (c *SchedulerWorkersConfig) Copy() *SchedulerWorkersConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(SchedulerWorkersConfig)
+	*nc = *c
+
+	if c.Pinned != nil {
+		nc.Pinned = make(map[string]int, len(c.Pinned))
+		for sched, count := range c.Pinned {
+			nc.Pinned[sched] = count
+		}
+	}
+	if c.Autoscale != nil {
+		nc.Autoscale = helper.BoolToPtr(*c.Autoscale)
+	}
+
+	return nc
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerWorkersConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := &SchedulerWorkersConfig{
+		Pinned: map[string]int{
+			"service": 1,
+		},
+		Autoscale:      helper.BoolToPtr(false),
+		ReadyPerWorker: 10,
+		ScaleInterval:  10 * time.Second,
+	}
+
+	c2 := &SchedulerWorkersConfig{
+		Pinned: map[string]int{
+			"service": 2,
+			"batch":   1,
+		},
+		Autoscale:  helper.BoolToPtr(true),
+		MaxWorkers: 8,
+	}
+
+	e := &SchedulerWorkersConfig{
+		Pinned: map[string]int{
+			"service": 2,
+			"batch":   1,
+		},
+		Autoscale:      helper.BoolToPtr(true),
+		MaxWorkers:     8,
+		ReadyPerWorker: 10,
+		ScaleInterval:  10 * time.Second,
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(e, result)
+
+	// Merging must not modify the inputs
+	require.False(*c1.Autoscale)
+	require.Equal(1, c1.Pinned["service"])
+}
+
+func TestSchedulerWorkersConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *SchedulerWorkersConfig
+		err    string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name: "negative pinned",
+			config: &SchedulerWorkersConfig{
+				Pinned: map[string]int{
+					"batch": -1,
+				},
+			},
+			err: `pinned workers of scheduler "batch"`,
+		},
+		{
+			name: "disabled autoscale",
+			config: &SchedulerWorkersConfig{
+				Autoscale: helper.BoolToPtr(false),
+			},
+		},
+		{
+			name: "bad ready per worker",
+			config: &SchedulerWorkersConfig{
+				Autoscale:     helper.BoolToPtr(true),
+				ScaleInterval: time.Second,
+			},
+			err: "ready_per_worker must be positive",
+		},
+		{
+			name: "bad interval",
+			config: &SchedulerWorkersConfig{
+				Autoscale:      helper.BoolToPtr(true),
+				ReadyPerWorker: 10,
+			},
+			err: "scale_interval must be positive",
+		},
+		{
+			name: "valid",
+			config: &SchedulerWorkersConfig{
+				Pinned: map[string]int{
+					"service": 2,
+				},
+				Autoscale:      helper.BoolToPtr(true),
+				MaxWorkers:     8,
+				ReadyPerWorker: 10,
+				ScaleInterval:  time.Second,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
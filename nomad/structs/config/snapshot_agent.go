@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// SnapshotAgentConfig configures the server-side agent that periodically saves
+// a snapshot of the Raft state while the server is the cluster leader.
+type SnapshotAgentConfig struct {
+	// Enabled enables the snapshot agent.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Interval is how often a snapshot is taken.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Retain is the number of snapshots to keep in each destination. Older
+	// snapshots are deleted after a new one is saved. A value of zero keeps
+	// all snapshots.
+	Retain *int `mapstructure:"retain"`
+
+	// LocalPath is a directory snapshots are written to.
+	LocalPath string `mapstructure:"local_path"`
+
+	// S3 configures an S3-compatible bucket snapshots are uploaded to.
+	S3 *SnapshotAgentS3Config `mapstructure:"s3"`
+}
+
+// SnapshotAgentS3Config configures an S3-compatible destination for the
+// snapshot agent.
+type SnapshotAgentS3Config struct {
+	// Bucket is the name of the bucket to upload snapshots to.
+	Bucket string `mapstructure:"bucket"`
+
+	// KeyPrefix is prepended to the object key of each snapshot.
+	KeyPrefix string `mapstructure:"key_prefix"`
+
+	// Region is the region of the bucket.
+	Region string `mapstructure:"region"`
+
+	// Endpoint overrides the S3 endpoint, allowing S3-compatible services
+	// to be used.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// ForcePathStyle uses path-style addressing for the bucket, which is
+	// required by many S3-compatible services.
+	ForcePathStyle *bool `mapstructure:"force_path_style"`
+
+	// AccessKeyID and SecretAccessKey are static credentials. If unset, the
+	// default AWS credential chain is used.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+}
+
+// DefaultSnapshotAgentConfig returns the canonical defaults for the Nomad
+// `snapshot_agent` configuration.
+func DefaultSnapshotAgentConfig() *SnapshotAgentConfig {
+	return &SnapshotAgentConfig{
+		Interval: 1 * time.Hour,
+		Retain:   helper.IntToPtr(30),
+	}
+}
+
+// IsEnabled returns whether the config enables the snapshot agent.
+func (a *SnapshotAgentConfig) IsEnabled() bool {
+	return a != nil && a.Enabled != nil && *a.Enabled
+}
+
+// Validate returns an error if an enabled snapshot agent is misconfigured.
+func (a *SnapshotAgentConfig) Validate() error {
+	if !a.IsEnabled() {
+		return nil
+	}
+
+	if a.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if a.Retain != nil && *a.Retain < 0 {
+		return fmt.Errorf("retain must not be negative")
+	}
+	if a.LocalPath == "" && a.S3 == nil {
+		return fmt.Errorf("at least one of local_path or s3 must be set")
+	}
+	if a.S3 != nil && a.S3.Bucket == "" {
+		return fmt.Errorf("s3 bucket must be set")
+	}
+	return nil
+}
+
+// Merge merges two snapshot agent configurations together.
+func (a *SnapshotAgentConfig) Merge(b *SnapshotAgentConfig) *SnapshotAgentConfig {
+	result := a.Copy()
+
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if b.Retain != nil {
+		result.Retain = helper.IntToPtr(*b.Retain)
+	}
+	if b.LocalPath != "" {
+		result.LocalPath = b.LocalPath
+	}
+	if result.S3 == nil && b.S3 != nil {
+		result.S3 = b.S3.Copy()
+	} else if b.S3 != nil {
+		result.S3 = result.S3.Merge(b.S3)
+	}
+
+	return result
+}
+
+// Copy returns a copy of this snapshot agent config.
+func (a *SnapshotAgentConfig) Copy() *SnapshotAgentConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(SnapshotAgentConfig)
+	*nc = *a
+
+	if a.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*a.Enabled)
+	}
+	if a.Retain != nil {
+		nc.Retain = helper.IntToPtr(*a.Retain)
+	}
+	nc.S3 = a.S3.Copy()
+
+	return nc
+}
+
+// Merge merges two snapshot agent S3 configurations together.
+func (a *SnapshotAgentS3Config) Merge(b *SnapshotAgentS3Config) *SnapshotAgentS3Config {
+	result := a.Copy()
+
+	if b.Bucket != "" {
+		result.Bucket = b.Bucket
+	}
+	if b.KeyPrefix != "" {
+		result.KeyPrefix = b.KeyPrefix
+	}
+	if b.Region != "" {
+		result.Region = b.Region
+	}
+	if b.Endpoint != "" {
+		result.Endpoint = b.Endpoint
+	}
+	if b.ForcePathStyle != nil {
+		result.ForcePathStyle = helper.BoolToPtr(*b.ForcePathStyle)
+	}
+	if b.AccessKeyID != "" {
+		result.AccessKeyID = b.AccessKeyID
+	}
+	if b.SecretAccessKey != "" {
+		result.SecretAccessKey = b.SecretAccessKey
+	}
+
+	return result
+}
+
+// Copy returns a copy of this snapshot agent S3 config.
+func (a *SnapshotAgentS3Config) Copy() *SnapshotAgentS3Config {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(SnapshotAgentS3Config)
+	*nc = *a
+
+	if a.ForcePathStyle != nil {
+		nc.ForcePathStyle = helper.BoolToPtr(*a.ForcePathStyle)
+	}
+
+	return nc
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotAgentConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := &SnapshotAgentConfig{
+		Enabled:   helper.BoolToPtr(false),
+		Interval:  1 * time.Hour,
+		Retain:    helper.IntToPtr(1),
+		LocalPath: "/tmp/a",
+	}
+
+	c2 := &SnapshotAgentConfig{
+		Enabled: helper.BoolToPtr(true),
+		Retain:  helper.IntToPtr(5),
+		S3: &SnapshotAgentS3Config{
+			Bucket: "snapshots",
+			Region: "us-east-1",
+		},
+	}
+
+	e := &SnapshotAgentConfig{
+		Enabled:   helper.BoolToPtr(true),
+		Interval:  1 * time.Hour,
+		Retain:    helper.IntToPtr(5),
+		LocalPath: "/tmp/a",
+		S3: &SnapshotAgentS3Config{
+			Bucket: "snapshots",
+			Region: "us-east-1",
+		},
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(e, result)
+
+	// Merging must not modify the inputs
+	require.False(*c1.Enabled)
+	require.Nil(c1.S3)
+}
+
+func TestSnapshotAgentConfig_Merge_RetainZero(t *testing.T) {
+	require := require.New(t)
+
+	// Retaining zero snapshots keeps all of them, so it must not be
+	// replaced by the default
+	c := DefaultSnapshotAgentConfig().Merge(&SnapshotAgentConfig{
+		Retain: helper.IntToPtr(0),
+	})
+	require.Equal(0, *c.Retain)
+
+	c = c.Merge(&SnapshotAgentConfig{})
+	require.Equal(0, *c.Retain)
+}
+
+func TestSnapshotAgentConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *SnapshotAgentConfig
+		err    string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name: "disabled",
+			config: &SnapshotAgentConfig{
+				Enabled: helper.BoolToPtr(false),
+			},
+		},
+		{
+			name: "no destination",
+			config: &SnapshotAgentConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Interval: time.Hour,
+			},
+			err: "at least one of local_path or s3",
+		},
+		{
+			name: "bad interval",
+			config: &SnapshotAgentConfig{
+				Enabled:   helper.BoolToPtr(true),
+				LocalPath: "/tmp/a",
+			},
+			err: "interval must be positive",
+		},
+		{
+			name: "no bucket",
+			config: &SnapshotAgentConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Interval: time.Hour,
+				S3:       &SnapshotAgentS3Config{},
+			},
+			err: "bucket must be set",
+		},
+		{
+			name: "valid",
+			config: &SnapshotAgentConfig{
+				Enabled:  helper.BoolToPtr(true),
+				Interval: time.Hour,
+				S3: &SnapshotAgentS3Config{
+					Bucket: "snapshots",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
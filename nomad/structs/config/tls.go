@@ -69,6 +69,10 @@ type TLSConfig struct {
 	// ciphersuite. If true then the server's preference, as expressed in
 	// the order of elements in CipherSuites, is used.
 	TLSPreferServerCipherSuites bool `mapstructure:"tls_prefer_server_cipher_suites"`
+
+	// Auto configures the agent to issue and rotate its certificate, in
+	// place of CertFile and KeyFile
+	Auto *AutoTLSConfig `mapstructure:"auto"`
 }
 
 type KeyLoader struct {
@@ -172,6 +176,8 @@ func (t *TLSConfig) Copy() *TLSConfig {
 
 	new.TLSPreferServerCipherSuites = t.TLSPreferServerCipherSuites
 
+	new.Auto = t.Auto.Copy()
+
 	new.SetChecksum()
 
 	return new
@@ -228,6 +234,11 @@ func (t *TLSConfig) Merge(b *TLSConfig) *TLSConfig {
 	if b.TLSPreferServerCipherSuites {
 		result.TLSPreferServerCipherSuites = true
 	}
+	if result.Auto == nil && b.Auto != nil {
+		result.Auto = b.Auto.Copy()
+	} else if b.Auto != nil {
+		result.Auto = result.Auto.Merge(b.Auto)
+	}
 	return result
 }
 
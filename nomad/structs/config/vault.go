@@ -3,6 +3,7 @@ package config
 import (
 	"time"
 
+	"github.com/hashicorp/nomad/helper"
 	vault "github.com/hashicorp/vault/api"
 )
 
@@ -10,6 +11,10 @@ const (
 	// DefaultVaultConnectRetryIntv is the retry interval between trying to
 	// connect to Vault
 	DefaultVaultConnectRetryIntv = 30 * time.Second
+
+	// DefaultVaultJWTAuthPath is the path of the JWT auth method that tasks
+	// log in to with their workload identity
+	DefaultVaultJWTAuthPath = "jwt"
 )
 
 // VaultConfig contains the configuration information necessary to
@@ -22,6 +27,10 @@ const (
 // - Create child tokens with policy subsets of the Server's token.
 type VaultConfig struct {
 
+	// Name is the name of the Vault cluster that jobs select it by. Empty
+	// for the default cluster.
+	Name string `mapstructure:"name"`
+
 	// Enabled enables or disables Vault support.
 	Enabled *bool `mapstructure:"enabled"`
 
@@ -74,6 +83,23 @@ type VaultConfig struct {
 
 	// TLSServerName, if set, is used to set the SNI host when connecting via TLS.
 	TLSServerName string `mapstructure:"tls_server_name"`
+
+	// UseIdentity makes tasks log in to Vault with a workload identity JWT
+	// signed by the Nomad servers instead of using tokens derived by the
+	// servers. Servers then don't need a Vault token.
+	UseIdentity *bool `mapstructure:"use_identity"`
+
+	// JWTAuthPath is the path of the Vault JWT auth method that tasks log in
+	// to with their workload identity.
+	JWTAuthPath string `mapstructure:"jwt_auth_path"`
+
+	// JWTAuthRole is the role of the JWT auth method that tasks log in with.
+	// If unset, the default role of the auth method is used.
+	JWTAuthRole string `mapstructure:"jwt_auth_role"`
+
+	// AllowedNamespaces is the set of Vault namespaces tasks may request
+	// tokens in. If empty, tasks may not set a Vault namespace.
+	AllowedNamespaces []string `mapstructure:"allowed_namespaces"`
 }
 
 // DefaultVaultConfig() returns the canonical defaults for the Nomad
@@ -82,6 +108,7 @@ func DefaultVaultConfig() *VaultConfig {
 	return &VaultConfig{
 		Addr:                "https://vault.service.consul:8200",
 		ConnectionRetryIntv: DefaultVaultConnectRetryIntv,
+		JWTAuthPath:         DefaultVaultJWTAuthPath,
 		AllowUnauthenticated: func(b bool) *bool {
 			return &b
 		}(true),
@@ -93,6 +120,26 @@ func (a *VaultConfig) IsEnabled() bool {
 	return a.Enabled != nil && *a.Enabled
 }
 
+// UsesIdentity returns whether tasks log in to Vault with their workload
+// identity instead of using tokens derived by the servers
+func (a *VaultConfig) UsesIdentity() bool {
+	return a.UseIdentity != nil && *a.UseIdentity
+}
+
+// AllowsNamespace returns whether tasks may request tokens in the given Vault
+// namespace. The empty namespace is always allowed.
+func (a *VaultConfig) AllowsNamespace(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	for _, ns := range a.AllowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // AllowsUnauthenticated returns whether the config allows unauthenticated
 // access to Vault
 func (a *VaultConfig) AllowsUnauthenticated() bool {
@@ -103,6 +150,9 @@ func (a *VaultConfig) AllowsUnauthenticated() bool {
 func (a *VaultConfig) Merge(b *VaultConfig) *VaultConfig {
 	result := *a
 
+	if b.Name != "" {
+		result.Name = b.Name
+	}
 	if b.Token != "" {
 		result.Token = b.Token
 	}
@@ -142,6 +192,18 @@ func (a *VaultConfig) Merge(b *VaultConfig) *VaultConfig {
 	if b.Enabled != nil {
 		result.Enabled = b.Enabled
 	}
+	if b.UseIdentity != nil {
+		result.UseIdentity = b.UseIdentity
+	}
+	if b.JWTAuthPath != "" {
+		result.JWTAuthPath = b.JWTAuthPath
+	}
+	if b.JWTAuthRole != "" {
+		result.JWTAuthRole = b.JWTAuthRole
+	}
+	if len(b.AllowedNamespaces) != 0 {
+		result.AllowedNamespaces = b.AllowedNamespaces
+	}
 
 	return &result
 }
@@ -192,6 +254,9 @@ func (a *VaultConfig) IsEqual(b *VaultConfig) bool {
 		return false
 	}
 
+	if a.Name != b.Name {
+		return false
+	}
 	if a.Token != b.Token {
 		return false
 	}
@@ -231,5 +296,20 @@ func (a *VaultConfig) IsEqual(b *VaultConfig) bool {
 	if a.Enabled != b.Enabled {
 		return false
 	}
+	if a.UseIdentity != b.UseIdentity {
+		return false
+	}
+	if a.JWTAuthPath != b.JWTAuthPath {
+		return false
+	}
+	if a.JWTAuthRole != b.JWTAuthRole {
+		return false
+	}
+	if len(a.AllowedNamespaces) != len(b.AllowedNamespaces) {
+		return false
+	}
+	if ok, _ := helper.SliceStringIsSubset(a.AllowedNamespaces, b.AllowedNamespaces); !ok {
+		return false
+	}
 	return true
 }
@@ -10,6 +10,7 @@ import (
 func TestVaultConfig_Merge(t *testing.T) {
 	trueValue, falseValue := true, false
 	c1 := &VaultConfig{
+		Name:                 "1",
 		Enabled:              &falseValue,
 		Token:                "1",
 		Role:                 "1",
@@ -22,9 +23,13 @@ func TestVaultConfig_Merge(t *testing.T) {
 		TLSKeyFile:           "1",
 		TLSSkipVerify:        &trueValue,
 		TLSServerName:        "1",
+		UseIdentity:          &falseValue,
+		JWTAuthPath:          "1",
+		AllowedNamespaces:    []string{"1"},
 	}
 
 	c2 := &VaultConfig{
+		Name:                 "2",
 		Enabled:              &trueValue,
 		Token:                "2",
 		Role:                 "2",
@@ -37,9 +42,13 @@ func TestVaultConfig_Merge(t *testing.T) {
 		TLSKeyFile:           "2",
 		TLSSkipVerify:        nil,
 		TLSServerName:        "2",
+		UseIdentity:          &trueValue,
+		JWTAuthRole:          "2",
+		AllowedNamespaces:    []string{"2"},
 	}
 
 	e := &VaultConfig{
+		Name:                 "2",
 		Enabled:              &trueValue,
 		Token:                "2",
 		Role:                 "2",
@@ -52,6 +61,10 @@ func TestVaultConfig_Merge(t *testing.T) {
 		TLSKeyFile:           "2",
 		TLSSkipVerify:        &trueValue,
 		TLSServerName:        "2",
+		UseIdentity:          &trueValue,
+		JWTAuthPath:          "1",
+		JWTAuthRole:          "2",
+		AllowedNamespaces:    []string{"2"},
 	}
 
 	result := c1.Merge(c2)
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+)
+
+// WriteRateLimitConfig configures the token buckets limiting the rate of the
+// write RPCs, such as job registrations and dispatches, of each ACL token and
+// of each namespace.
+type WriteRateLimitConfig struct {
+	// TokenRate is the number of write requests per second allowed for each
+	// ACL token. Requests without an ACL token share a single bucket. A value
+	// of zero doesn't limit the rate of requests.
+	TokenRate float64 `mapstructure:"token_rate"`
+
+	// TokenBurst is the number of write requests an ACL token can make at
+	// once above the rate limit. It defaults to the rate, rounded up.
+	TokenBurst int `mapstructure:"token_burst"`
+
+	// NamespaceRate is the number of write requests per second allowed for
+	// each namespace. A value of zero doesn't limit the rate of requests.
+	NamespaceRate float64 `mapstructure:"namespace_rate"`
+
+	// NamespaceBurst is the number of write requests that can target a
+	// namespace at once above the rate limit. It defaults to the rate,
+	// rounded up.
+	NamespaceBurst int `mapstructure:"namespace_burst"`
+}
+
+// Validate returns an error if the write rate limits are misconfigured.
+func (c *WriteRateLimitConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.TokenRate < 0 {
+		return fmt.Errorf("token_rate must not be negative")
+	}
+	if c.TokenBurst < 0 {
+		return fmt.Errorf("token_burst must not be negative")
+	}
+	if c.TokenBurst > 0 && c.TokenRate == 0 {
+		return fmt.Errorf("token_burst requires a token_rate")
+	}
+	if c.NamespaceRate < 0 {
+		return fmt.Errorf("namespace_rate must not be negative")
+	}
+	if c.NamespaceBurst < 0 {
+		return fmt.Errorf("namespace_burst must not be negative")
+	}
+	if c.NamespaceBurst > 0 && c.NamespaceRate == 0 {
+		return fmt.Errorf("namespace_burst requires a namespace_rate")
+	}
+	return nil
+}
+
+// Merge merges two write rate limit configurations together.
+func (c *WriteRateLimitConfig) Merge(b *WriteRateLimitConfig) *WriteRateLimitConfig {
+	result := c.Copy()
+
+	if b.TokenRate != 0 {
+		result.TokenRate = b.TokenRate
+	}
+	if b.TokenBurst != 0 {
+		result.TokenBurst = b.TokenBurst
+	}
+	if b.NamespaceRate != 0 {
+		result.NamespaceRate = b.NamespaceRate
+	}
+	if b.NamespaceBurst != 0 {
+		result.NamespaceBurst = b.NamespaceBurst
+	}
+
+	return result
+}
+
+// Copy returns a copy of this write rate limit config.
+func (c *WriteRateLimitConfig) Copy() *WriteRateLimitConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(WriteRateLimitConfig)
+	*nc = *c
+	return nc
+}
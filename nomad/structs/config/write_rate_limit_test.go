@@ -0,0 +1,96 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRateLimitConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := &WriteRateLimitConfig{
+		TokenRate:  1,
+		TokenBurst: 5,
+	}
+	c2 := &WriteRateLimitConfig{
+		TokenRate:     2,
+		NamespaceRate: 10,
+	}
+
+	e := &WriteRateLimitConfig{
+		TokenRate:     2,
+		TokenBurst:    5,
+		NamespaceRate: 10,
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(e, result)
+
+	// Merging must not modify the inputs
+	require.Equal(1.0, c1.TokenRate)
+	require.Zero(c1.NamespaceRate)
+}
+
+func TestWriteRateLimitConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *WriteRateLimitConfig
+		err    string
+	}{
+		{
+			name:   "nil",
+			config: nil,
+		},
+		{
+			name: "negative token rate",
+			config: &WriteRateLimitConfig{
+				TokenRate: -1,
+			},
+			err: "token_rate must not be negative",
+		},
+		{
+			name: "token burst without rate",
+			config: &WriteRateLimitConfig{
+				TokenBurst: 5,
+			},
+			err: "token_burst requires a token_rate",
+		},
+		{
+			name: "negative namespace burst",
+			config: &WriteRateLimitConfig{
+				NamespaceRate:  1,
+				NamespaceBurst: -1,
+			},
+			err: "namespace_burst must not be negative",
+		},
+		{
+			name: "namespace burst without rate",
+			config: &WriteRateLimitConfig{
+				NamespaceBurst: 5,
+			},
+			err: "namespace_burst requires a namespace_rate",
+		},
+		{
+			name: "valid",
+			config: &WriteRateLimitConfig{
+				TokenRate:      0.5,
+				TokenBurst:     5,
+				NamespaceRate:  10,
+				NamespaceBurst: 50,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.config.Validate()
+			if c.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
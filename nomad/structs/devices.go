@@ -17,6 +17,10 @@ type DeviceAccounterInstance struct {
 	// Instances is a mapping of the device IDs to their usage.
 	// Only a value of 0 indicates that the instance is unused.
 	Instances map[string]int
+
+	// Capacity is a mapping of the device IDs to the number of allocations
+	// that can use them at the same time.
+	Capacity map[string]int
 }
 
 // NewDeviceAccounter returns a new device accounter. The node is used to
@@ -41,6 +45,7 @@ func NewDeviceAccounter(n *Node) *DeviceAccounter {
 		d.Devices[id] = &DeviceAccounterInstance{
 			Device:    dev,
 			Instances: make(map[string]int, len(dev.Instances)),
+			Capacity:  make(map[string]int, len(dev.Instances)),
 		}
 		for _, instance := range dev.Instances {
 			// Skip unhealthy devices as they aren't allocatable
@@ -49,6 +54,7 @@ func NewDeviceAccounter(n *Node) *DeviceAccounter {
 			}
 
 			d.Devices[id].Instances[instance.ID] = 0
+			d.Devices[id].Capacity[instance.ID] = instance.MaxAllocs()
 		}
 	}
 
@@ -90,7 +96,7 @@ func (d *DeviceAccounter) AddAllocs(allocs []*Allocation) (collision bool) {
 							// Mark that the device is in use
 							devInst.Instances[instanceID]++
 
-							if i != 0 {
+							if i >= devInst.Capacity[instanceID] {
 								collision = true
 							}
 						}
@@ -119,8 +125,9 @@ func (d *DeviceAccounter) AddReserved(res *AllocatedDeviceResource) (collision b
 			continue
 		}
 
-		// It has already been used, so mark that there is a collision
-		if cur != 0 {
+		// It has already been used by as many allocations as can share it,
+		// so mark that there is a collision
+		if cur >= devInst.Capacity[id] {
 			collision = true
 		}
 
@@ -130,13 +137,22 @@ func (d *DeviceAccounter) AddReserved(res *AllocatedDeviceResource) (collision b
 	return
 }
 
-// FreeCount returns the number of free device instances
+// FreeCount returns the number of device instances that can be used by
+// another allocation.
 func (i *DeviceAccounterInstance) FreeCount() int {
 	count := 0
-	for _, c := range i.Instances {
-		if c == 0 {
+	for id := range i.Instances {
+		if i.Free(id) {
 			count++
 		}
 	}
 	return count
 }
+
+// Free returns whether the device instance can be used by another allocation.
+// Instances are free while they are used by fewer allocations than their
+// slices.
+func (i *DeviceAccounterInstance) Free(id string) bool {
+	used, ok := i.Instances[id]
+	return ok && used < i.Capacity[id]
+}
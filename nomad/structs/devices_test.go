@@ -214,3 +214,29 @@ func TestDeviceAccounter_AddReserved_Collision(t *testing.T) {
 	res.DeviceIDs = []string{nvidiaDev0ID}
 	require.True(d.AddReserved(res))
 }
+
+// Test that devices with slices can be shared until all their slices are used
+func TestDeviceAccounter_AddAllocs_Slices(t *testing.T) {
+	require := require.New(t)
+	n := devNode()
+	n.NodeResources.Devices[0].Instances[0].Slices = 2
+	d := NewDeviceAccounter(n)
+	require.NotNil(d)
+
+	nvidiaDev0ID := n.NodeResources.Devices[0].Instances[0].ID
+	nvidiaDevice := d.Devices[*n.NodeResources.Devices[0].ID()]
+	require.True(nvidiaDevice.Free(nvidiaDev0ID))
+
+	// Two allocations can share the device
+	a1, a2, a3 := nvidiaAlloc(), nvidiaAlloc(), nvidiaAlloc()
+	a1.AllocatedResources.Tasks["web"].Devices[0].DeviceIDs = []string{nvidiaDev0ID}
+	a2.AllocatedResources.Tasks["web"].Devices[0].DeviceIDs = []string{nvidiaDev0ID}
+	require.False(d.AddAllocs([]*Allocation{a1, a2}))
+	require.Equal(2, nvidiaDevice.Instances[nvidiaDev0ID])
+	require.False(nvidiaDevice.Free(nvidiaDev0ID))
+	require.Equal(1, nvidiaDevice.FreeCount())
+
+	// A third one collides
+	a3.AllocatedResources.Tasks["web"].Devices[0].DeviceIDs = []string{nvidiaDev0ID}
+	require.True(d.AddAllocs([]*Allocation{a3}))
+}
@@ -61,8 +61,9 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	// in 0.6.0
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
-	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
-		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime"}
+	filter := []string{"ID", "Status", "StatusDescription", "Version", "MinimumVersion", "Stable", "CreateIndex",
+		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "DispatchIdempotencyToken",
+		"DispatchPayloadHash"}
 
 	if j == nil && other == nil {
 		return diff, nil
@@ -94,6 +95,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, setDiff)
 	}
 
+	// Tags diff
+	if setDiff := stringSetDiff(j.Tags, other.Tags, "Tags", contextual); setDiff != nil && setDiff.Type != DiffTypeNone {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
 	// Constraints diff
 	conDiff := primitiveObjectSetDiff(
 		interfaceSlice(j.Constraints),
@@ -133,6 +139,16 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// VersionRetention diff
+	if rDiff := primitiveObjectDiff(j.VersionRetention, other.VersionRetention, nil, "VersionRetention", contextual); rDiff != nil {
+		diff.Objects = append(diff.Objects, rDiff)
+	}
+
+	// Prefetch diff
+	if pDiff := primitiveObjectDiff(j.Prefetch, other.Prefetch, nil, "Prefetch", contextual); pDiff != nil {
+		diff.Objects = append(diff.Objects, pDiff)
+	}
+
 	// Check to see if there is a diff. We don't use reflect because we are
 	// filtering quite a few fields that will change on each diff.
 	if diff.Type == DiffTypeNone {
@@ -228,6 +244,19 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 	// Diff the primitive fields.
 	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, false)
 
+	// Diff MaxClientDisconnect, which isn't flattened since it's a pointer
+	var oldDisconnect, newDisconnect string
+	if tg.MaxClientDisconnect != nil {
+		oldDisconnect = fmt.Sprintf("%d", *tg.MaxClientDisconnect)
+	}
+	if other.MaxClientDisconnect != nil {
+		newDisconnect = fmt.Sprintf("%d", *other.MaxClientDisconnect)
+	}
+	if fd := fieldDiff(oldDisconnect, newDisconnect, "MaxClientDisconnect", false); fd != nil {
+		diff.Fields = append(diff.Fields, fd)
+		sort.Sort(FieldDiffs(diff.Fields))
+	}
+
 	// Constraints diff
 	conDiff := primitiveObjectSetDiff(
 		interfaceSlice(tg.Constraints),
@@ -268,9 +297,20 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, diskDiff)
 	}
 
+	// SharedMemory diff
+	shmDiff := primitiveObjectDiff(tg.SharedMemory, other.SharedMemory, nil, "SharedMemory", contextual)
+	if shmDiff != nil {
+		diff.Objects = append(diff.Objects, shmDiff)
+	}
+
+	// Consul diff
+	consulDiff := primitiveObjectDiff(tg.Consul, other.Consul, nil, "Consul", contextual)
+	if consulDiff != nil {
+		diff.Objects = append(diff.Objects, consulDiff)
+	}
+
 	// Update diff
-	// COMPAT: Remove "Stagger" in 0.7.0.
-	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
+	if uDiff := updateStrategyDiff(tg.Update, other.Update, contextual); uDiff != nil {
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
@@ -432,6 +472,23 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 
 	// LogConfig diff
 	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	var oldProviders, newProviders []string
+	if t.LogConfig != nil {
+		oldProviders = t.LogConfig.EventLogProviders
+	}
+	if other.LogConfig != nil {
+		newProviders = other.LogConfig.EventLogProviders
+	}
+	if len(oldProviders) != 0 || len(newProviders) != 0 {
+		if pDiff := stringSetDiff(oldProviders, newProviders, "EventLogProviders", contextual); pDiff != nil {
+			if lDiff == nil {
+				lDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+			} else if lDiff.Type == DiffTypeNone && pDiff.Type != DiffTypeNone {
+				lDiff.Type = DiffTypeEdited
+			}
+			lDiff.Objects = append(lDiff.Objects, pDiff)
+		}
+	}
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -442,6 +499,17 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// Identity diff
+	iDiff := primitiveObjectDiff(t.Identity, other.Identity, nil, "Identity", contextual)
+	if iDiff != nil {
+		diff.Objects = append(diff.Objects, iDiff)
+	}
+
+	// Actions diff
+	if aDiffs := actionDiffs(t.Actions, other.Actions, contextual); aDiffs != nil {
+		diff.Objects = append(diff.Objects, aDiffs...)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -475,6 +543,33 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, tmplDiffs...)
 	}
 
+	// Secret diff
+	secretDiffs := primitiveObjectSetDiff(
+		interfaceSlice(t.Secrets),
+		interfaceSlice(other.Secrets),
+		nil,
+		"Secret",
+		contextual)
+	if secretDiffs != nil {
+		diff.Objects = append(diff.Objects, secretDiffs...)
+	}
+
+	// KillEscalation diff
+	killDiffs := primitiveObjectSetDiff(
+		interfaceSlice(t.KillEscalation),
+		interfaceSlice(other.KillEscalation),
+		nil,
+		"KillStep",
+		contextual)
+	if killDiffs != nil {
+		diff.Objects = append(diff.Objects, killDiffs...)
+	}
+
+	// MetricLabels diff
+	if setDiff := stringSetDiff(t.MetricLabels, other.MetricLabels, "MetricLabels", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
 	return diff, nil
 }
 
@@ -616,6 +711,73 @@ func serviceDiffs(old, new []*Service, contextual bool) []*ObjectDiff {
 	return diffs
 }
 
+// actionDiff returns the diff of two action objects. If contextual diff is
+// enabled, all fields will be returned, even if no diff occurred.
+func actionDiff(old, new *Action, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Action"}
+	var oldFlat, newFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newFlat = actionFlatten(new)
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldFlat = actionFlatten(old)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldFlat = actionFlatten(old)
+		newFlat = actionFlatten(new)
+	}
+
+	diff.Fields = fieldDiffs(oldFlat, newFlat, contextual)
+	return diff
+}
+
+// actionFlatten flattens the primitive fields of an action along with its
+// arguments. The arguments are ordered so they are diffed by index.
+func actionFlatten(a *Action) map[string]string {
+	flat := flatmap.Flatten(a, nil, true)
+	for i, arg := range a.Args {
+		flat[fmt.Sprintf("Args[%d]", i)] = arg
+	}
+	return flat
+}
+
+// actionDiffs diffs a set of actions. If contextual diff is enabled, unchanged
+// fields within objects nested in the tasks will be returned.
+func actionDiffs(old, new []*Action, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*Action, len(old))
+	newMap := make(map[string]*Action, len(new))
+	for _, o := range old {
+		oldMap[o.Name] = o
+	}
+	for _, n := range new {
+		newMap[n.Name] = n
+	}
+
+	var diffs []*ObjectDiff
+	for name, oldAction := range oldMap {
+		// Diff the same, deleted and edited
+		if diff := actionDiff(oldAction, newMap[name], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	for name, newAction := range newMap {
+		// Diff the added
+		if old, ok := oldMap[name]; !ok {
+			if diff := actionDiff(old, newAction, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
 // serviceCheckDiff returns the diff of two service check objects. If contextual
 // diff is enabled, all fields will be returned, even if no diff occurred.
 func serviceCheckDiff(old, new *ServiceCheck, contextual bool) *ObjectDiff {
@@ -807,9 +969,82 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 		diff.Objects = append(diff.Objects, requiredDiff)
 	}
 
+	// Meta config diffs
+	diff.Objects = append(diff.Objects, dispatchMetaConfigDiffs(old.MetaConfigs, new.MetaConfigs, contextual)...)
+
 	return diff
 }
 
+// dispatchMetaConfigDiff returns the diff of two dispatch meta config objects.
+// If contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func dispatchMetaConfigDiff(old, new *DispatchMetaConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "MetaConfig"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &DispatchMetaConfig{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &DispatchMetaConfig{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	if enumDiff := stringSetDiff(old.Enum, new.Enum, "Enum", contextual); enumDiff != nil {
+		diff.Objects = append(diff.Objects, enumDiff)
+	}
+
+	if requiredWithDiff := stringSetDiff(old.RequiredWith, new.RequiredWith, "RequiredWith", contextual); requiredWithDiff != nil {
+		diff.Objects = append(diff.Objects, requiredWithDiff)
+	}
+
+	return diff
+}
+
+// dispatchMetaConfigDiffs diffs a set of dispatch meta configs. If contextual
+// diff is enabled, unchanged fields within the configs will be returned.
+func dispatchMetaConfigDiffs(old, new []*DispatchMetaConfig, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*DispatchMetaConfig, len(old))
+	newMap := make(map[string]*DispatchMetaConfig, len(new))
+	for _, o := range old {
+		oldMap[o.Key] = o
+	}
+	for _, n := range new {
+		newMap[n.Key] = n
+	}
+
+	var diffs []*ObjectDiff
+	for key, oldConfig := range oldMap {
+		// Diff the same, deleted and edited
+		if diff := dispatchMetaConfigDiff(oldConfig, newMap[key], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	for key, newConfig := range newMap {
+		// Diff the added
+		if old, ok := oldMap[key]; !ok {
+			if diff := dispatchMetaConfigDiff(old, newConfig, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
 // Diff returns a diff of two resource objects. If contextual diff is enabled,
 // non-changed fields will still be returned.
 func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
@@ -884,6 +1119,39 @@ func (r *NetworkResource) Diff(other *NetworkResource, contextual bool) *ObjectD
 		diff.Objects = append(diff.Objects, dynPorts...)
 	}
 
+	// DNS diff
+	if dnsDiff := dnsConfigDiff(r.DNS, other.DNS, contextual); dnsDiff != nil {
+		diff.Objects = append(diff.Objects, dnsDiff)
+	}
+
+	return diff
+}
+
+// dnsConfigDiff returns the diff of two DNS configurations. If contextual diff
+// is enabled, non-changed fields will still be returned.
+func dnsConfigDiff(old, new *DNSConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "DNS"}
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &DNSConfig{}
+		diff.Type = DiffTypeAdded
+	} else if new == nil {
+		new = &DNSConfig{}
+		diff.Type = DiffTypeDeleted
+	} else {
+		diff.Type = DiffTypeEdited
+	}
+
+	if setDiff := stringSetDiff(old.Servers, new.Servers, "Servers", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+	if setDiff := stringSetDiff(old.Searches, new.Searches, "Searches", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+	if setDiff := stringSetDiff(old.Options, new.Options, "Options", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
 	return diff
 }
 
@@ -1287,6 +1555,31 @@ func stringSetDiff(old, new []string, name string, contextual bool) *ObjectDiff
 // The filter field can be used to exclude fields from the diff. The name is the
 // name of the objects. If contextual is set, non-changed fields will also be
 // stored in the object diff.
+// updateStrategyDiff returns the diff of two update strategies, including
+// their progress webhooks.
+func updateStrategyDiff(old, new *UpdateStrategy, contextual bool) *ObjectDiff {
+	// COMPAT: Remove "Stagger" in 0.7.0.
+	diff := primitiveObjectDiff(old, new, []string{"Stagger"}, "Update", contextual)
+
+	var oldWebhook, newWebhook *DeploymentWebhook
+	if old != nil {
+		oldWebhook = old.ProgressWebhook
+	}
+	if new != nil {
+		newWebhook = new.ProgressWebhook
+	}
+
+	webhookDiff := primitiveObjectDiff(oldWebhook, newWebhook, nil, "ProgressWebhook", contextual)
+	if webhookDiff == nil {
+		return diff
+	}
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+	}
+	diff.Objects = append(diff.Objects, webhookDiff)
+	return diff
+}
+
 func primitiveObjectDiff(old, new interface{}, filter []string, name string, contextual bool) *ObjectDiff {
 	oldPrimitiveFlat := flatmap.Flatten(old, filter, true)
 	newPrimitiveFlat := flatmap.Flatten(new, filter, true)
@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/hashicorp/nomad/helper"
 )
 
 func TestJobDiff(t *testing.T) {
@@ -296,6 +298,38 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Tags diff
+			Old: &Job{
+				Tags: []string{"owner:payments", "tier:2"},
+			},
+			New: &Job{
+				Tags: []string{"owner:payments", "tier:1"},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Tags",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Tags",
+								Old:  "",
+								New:  "tier:1",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Tags",
+								Old:  "tier:2",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Datacenter diff both added and removed
 			Old: &Job{
@@ -1287,6 +1321,28 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Max client disconnect added
+			Old: &TaskGroup{
+				Name: "foo",
+			},
+			New: &TaskGroup{
+				Name:                "foo",
+				MaxClientDisconnect: helper.TimeToPtr(time.Minute),
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Name: "foo",
+				Fields: []*FieldDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "MaxClientDisconnect",
+						Old:  "",
+						New:  "60000000000",
+					},
+				},
+			},
+		},
 		{
 			// Map diff
 			Old: &TaskGroup{
@@ -1995,6 +2051,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "0",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "ManualStep",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "MaxParallel",
@@ -2051,6 +2113,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "0",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "ManualStep",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "MaxParallel",
@@ -2208,6 +2276,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "30000000000",
 								New:  "30000000000",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "ManualStep",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxParallel",
@@ -2231,6 +2305,108 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Update strategy progress webhook edited
+			Old: &TaskGroup{
+				Update: &UpdateStrategy{
+					MaxParallel: 5,
+					ProgressWebhook: &DeploymentWebhook{
+						URL: "https://ci.example.com/old",
+					},
+				},
+			},
+			New: &TaskGroup{
+				Update: &UpdateStrategy{
+					MaxParallel: 5,
+					ProgressWebhook: &DeploymentWebhook{
+						URL: "https://ci.example.com/new",
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Update",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "ProgressWebhook",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "URL",
+										Old:  "https://ci.example.com/old",
+										New:  "https://ci.example.com/new",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// SharedMemory edited
+			Old: &TaskGroup{
+				SharedMemory: &SharedMemory{
+					SizeMB: 64,
+				},
+			},
+			New: &TaskGroup{
+				SharedMemory: &SharedMemory{
+					SizeMB: 512,
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "SharedMemory",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "SizeMB",
+								Old:  "64",
+								New:  "512",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Consul cluster edited
+			Old: &TaskGroup{
+				Consul: &Consul{
+					Cluster: "default",
+				},
+			},
+			New: &TaskGroup{
+				Consul: &Consul{
+					Cluster: "edge",
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Consul",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Cluster",
+								Old:  "default",
+								New:  "edge",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// EphemeralDisk added
 			Old: &TaskGroup{},
@@ -3048,6 +3224,12 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "EventLogChannel",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "MaxFileSizeMB",
@@ -3065,6 +3247,52 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "LogConfig event log providers edited",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:          1,
+					MaxFileSizeMB:     10,
+					EventLogProviders: []string{"foo", "bar"},
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:          1,
+					MaxFileSizeMB:     10,
+					EventLogProviders: []string{"foo", "baz"},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "EventLogProviders",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "EventLogProviders",
+										Old:  "",
+										New:  "baz",
+									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "EventLogProviders",
+										Old:  "bar",
+										New:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Artifacts edited",
 			Old: &Task{
@@ -3171,6 +3399,150 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Actions edited",
+			Old: &Task{
+				Actions: []*Action{
+					{
+						Name:    "flush",
+						Command: "/bin/flush",
+						Args:    []string{"-a"},
+						Timeout: time.Minute,
+					},
+				},
+			},
+			New: &Task{
+				Actions: []*Action{
+					{
+						Name:    "flush",
+						Command: "/bin/flush",
+						Args:    []string{"-a", "-b"},
+						Timeout: time.Minute,
+					},
+					{
+						Name:    "compact",
+						Command: "/bin/compact",
+						Timeout: time.Second,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Action",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Args[1]",
+								Old:  "",
+								New:  "-b",
+							},
+						},
+					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Action",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Command",
+								Old:  "",
+								New:  "/bin/compact",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Name",
+								Old:  "",
+								New:  "compact",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Timeout",
+								Old:  "",
+								New:  "1000000000",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "Secret added",
+			Old:  &Task{},
+			New: &Task{
+				Secrets: []*Secret{
+					{
+						Name:       "token",
+						Provider:   SecretProviderNomad,
+						Path:       "nomad/jobs/example",
+						Key:        "token",
+						Perms:      "0400",
+						ChangeMode: TemplateChangeModeRestart,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Secret",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "ChangeMode",
+								Old:  "",
+								New:  "restart",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Gid",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Key",
+								Old:  "",
+								New:  "token",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Name",
+								Old:  "",
+								New:  "token",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Path",
+								Old:  "",
+								New:  "nomad/jobs/example",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Perms",
+								Old:  "",
+								New:  "0400",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Provider",
+								Old:  "",
+								New:  "nomad",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Uid",
+								Old:  "",
+								New:  "0",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Resources edited (no networks)",
 			Old: &Task{
@@ -3247,12 +3619,30 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "200",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Cores",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "DiskMB",
 								Old:  "100",
 								New:  "200",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "HugePages1GB",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "HugePages2MB",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "IOPS",
@@ -3265,6 +3655,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Realtime",
+								Old:  "false",
+								New:  "false",
+							},
 						},
 					},
 				},
@@ -3574,12 +3970,30 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Cores",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "DiskMB",
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "HugePages1GB",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "HugePages2MB",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "IOPS",
@@ -3592,6 +4006,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Realtime",
+								Old:  "false",
+								New:  "false",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -4000,6 +4420,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "bar",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -4093,6 +4519,12 @@ func TestTaskDiff(t *testing.T) {
 								Type: DiffTypeNone,
 								Name: "PortLabel",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -4431,6 +4863,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -4503,6 +4941,12 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "http",
 										New:  "http",
 									},
+									{
+										Type: DiffTypeNone,
+										Name: "Role",
+										Old:  "",
+										New:  "",
+									},
 									{
 										Type: DiffTypeNone,
 										Name: "TLSSkipVerify",
@@ -4689,6 +5133,11 @@ func TestTaskDiff(t *testing.T) {
 										Type: DiffTypeAdded,
 										Name: "CheckRestart",
 										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeAdded,
+												Name: "Delay",
+												New:  "0",
+											},
 											{
 												Type: DiffTypeAdded,
 												Name: "Grace",
@@ -4704,6 +5153,11 @@ func TestTaskDiff(t *testing.T) {
 												Name: "Limit",
 												New:  "1",
 											},
+											{
+												Type: DiffTypeAdded,
+												Name: "MaxDelay",
+												New:  "0",
+											},
 										},
 									},
 								},
@@ -4716,6 +5170,11 @@ func TestTaskDiff(t *testing.T) {
 										Type: DiffTypeDeleted,
 										Name: "CheckRestart",
 										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeDeleted,
+												Name: "Delay",
+												Old:  "0",
+											},
 											{
 												Type: DiffTypeDeleted,
 												Name: "Grace",
@@ -4731,6 +5190,11 @@ func TestTaskDiff(t *testing.T) {
 												Name: "Limit",
 												Old:  "2",
 											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "MaxDelay",
+												Old:  "0",
+											},
 										},
 									},
 								},
@@ -4968,12 +5432,24 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "SIGUSR1",
 								New:  "SIGUSR1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Cluster",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "Env",
 								Old:  "true",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Namespace",
+								Old:  "",
+								New:  "",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -5005,6 +5481,38 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "MetricLabels edited",
+			Old: &Task{
+				MetricLabels: []string{"team", "tier"},
+			},
+			New: &Task{
+				MetricLabels: []string{"team", "owner"},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "MetricLabels",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "MetricLabels",
+								Old:  "",
+								New:  "owner",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MetricLabels",
+								Old:  "tier",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Template edited",
 			Old: &Task{
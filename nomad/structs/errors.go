@@ -10,11 +10,15 @@ const (
 	errNoLeader            = "No cluster leader"
 	errNoRegionPath        = "No path to region"
 	errTokenNotFound       = "ACL token not found"
+	errTokenExpired        = "ACL token expired"
 	errPermissionDenied    = "Permission denied"
 	errNoNodeConn          = "No path to node"
 	errUnknownMethod       = "Unknown rpc method"
 	errUnknownNomadVersion = "Unable to determine Nomad version"
 	errNodeLacksRpc        = "Node does not support RPC; requires 0.8 or later"
+	errClientRelayDisabled = "Relaying requests to clients is disabled"
+	errClientRelayLimited  = "Client relay rate limit exceeded"
+	errWriteRateLimited    = "Write rate limit exceeded"
 
 	// Prefix based errors that are used to check if the error is of a given
 	// type. These errors should be created with the associated constructor.
@@ -29,11 +33,16 @@ var (
 	ErrNoLeader            = errors.New(errNoLeader)
 	ErrNoRegionPath        = errors.New(errNoRegionPath)
 	ErrTokenNotFound       = errors.New(errTokenNotFound)
+	ErrTokenExpired        = errors.New(errTokenExpired)
 	ErrPermissionDenied    = errors.New(errPermissionDenied)
 	ErrNoNodeConn          = errors.New(errNoNodeConn)
 	ErrUnknownMethod       = errors.New(errUnknownMethod)
 	ErrUnknownNomadVersion = errors.New(errUnknownNomadVersion)
 	ErrNodeLacksRpc        = errors.New(errNodeLacksRpc)
+
+	ErrClientRelayDisabled    = errors.New(errClientRelayDisabled)
+	ErrClientRelayRateLimited = errors.New(errClientRelayLimited)
+	ErrWriteRateLimited       = errors.New(errWriteRateLimited)
 )
 
 // IsErrNoLeader returns whether the error is due to there being no leader.
@@ -53,6 +62,12 @@ func IsErrTokenNotFound(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errTokenNotFound)
 }
 
+// IsErrTokenExpired returns whether the error is due to the passed token
+// having expired.
+func IsErrTokenExpired(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errTokenExpired)
+}
+
 // IsErrPermissionDenied returns whether the error is due to the operation not
 // being allowed due to lack of permissions.
 func IsErrPermissionDenied(err error) bool {
@@ -140,3 +155,21 @@ func IsErrUnknownNomadVersion(err error) bool {
 func IsErrNodeLacksRpc(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errNodeLacksRpc)
 }
+
+// IsErrClientRelayDisabled returns whether the error is due to the server not
+// being allowed to relay requests to clients.
+func IsErrClientRelayDisabled(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errClientRelayDisabled)
+}
+
+// IsErrClientRelayRateLimited returns whether the error is due to the rate
+// limit of the requests relayed to clients being exceeded.
+func IsErrClientRelayRateLimited(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errClientRelayLimited)
+}
+
+// IsErrWriteRateLimited returns whether the error is due to the ACL token or
+// the namespace of a write request exceeding its rate limit.
+func IsErrWriteRateLimited(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errWriteRateLimited)
+}
@@ -0,0 +1,108 @@
+package structs
+
+// Topic is the kind of object an event of the event stream is about.
+type Topic string
+
+const (
+	TopicDeployment Topic = "Deployment"
+	TopicAllocation Topic = "Allocation"
+	TopicJob        Topic = "Job"
+	TopicNode       Topic = "Node"
+
+	// TopicAll matches the events of every topic when subscribing.
+	TopicAll Topic = "*"
+)
+
+const (
+	TypeNodeRegistration      = "NodeRegistration"
+	TypeNodeDeregistration    = "NodeDeregistration"
+	TypeNodeStatusUpdate      = "NodeStatusUpdate"
+	TypeNodeDrain             = "NodeDrain"
+	TypeNodeEligibilityUpdate = "NodeEligibilityUpdate"
+
+	TypeJobRegistered   = "JobRegistered"
+	TypeJobDeregistered = "JobDeregistered"
+	TypeJobCompleted    = "JobCompleted"
+
+	TypeAllocationUpdated             = "AllocationUpdated"
+	TypeAllocationClientUpdated       = "AllocationClientUpdated"
+	TypeAllocationUpdateDesiredStatus = "AllocationUpdateDesiredStatus"
+
+	TypeDeploymentUpdated      = "DeploymentUpdated"
+	TypeDeploymentStatusUpdate = "DeploymentStatusUpdate"
+	TypeDeploymentPromotion    = "DeploymentPromotion"
+	TypeDeploymentAllocHealth  = "DeploymentAllocHealth"
+	TypeDeploymentDeleted      = "DeploymentDeleted"
+)
+
+// Event is a change to an object of the state store, published once the Raft
+// log that made the change is applied.
+type Event struct {
+	// Topic is the kind of object that changed.
+	Topic Topic
+
+	// Type describes the change, for example JobRegistered.
+	Type string
+
+	// Key is the ID of the object that changed.
+	Key string
+
+	// Namespace is the namespace of the object, if it is namespaced.
+	Namespace string
+
+	// FilterKeys are additional keys subscriptions can match the event on,
+	// such as the job ID of an allocation.
+	FilterKeys []string
+
+	// Index is the Raft index of the change.
+	Index uint64
+
+	// Payload is the object after the change, wrapped in one of the event
+	// types below.
+	Payload interface{}
+}
+
+// Events is the batch of events published for a Raft index.
+type Events struct {
+	Index  uint64
+	Events []Event
+}
+
+// JobEvent is the payload of the events of the Job topic. The job is nil if
+// it was purged. The summary is only set for JobCompleted events.
+type JobEvent struct {
+	Job     *Job
+	Summary *JobSummary `json:",omitempty"`
+}
+
+// AllocationEvent is the payload of the events of the Allocation topic. The
+// job of the allocation is removed to keep events small.
+type AllocationEvent struct {
+	Allocation *Allocation
+}
+
+// DeploymentEvent is the payload of the events of the Deployment topic. The
+// deployment is nil if it was deleted.
+type DeploymentEvent struct {
+	Deployment *Deployment
+}
+
+// NodeStreamEvent is the payload of the events of the Node topic. The secret
+// ID of the node is removed and the node is nil if it was deregistered.
+type NodeStreamEvent struct {
+	Node *Node
+}
+
+// EventStreamRequest is used to subscribe to the event stream.
+type EventStreamRequest struct {
+	// Topics maps the topics to subscribe to to the keys of the events to
+	// receive, where the key "*" matches every event of the topic.
+	Topics map[Topic][]string
+
+	// Index is the Raft index after which to stream events. Events still
+	// buffered by the server are sent first, while an index of zero only
+	// streams new events.
+	Index uint64
+
+	QueryOptions
+}
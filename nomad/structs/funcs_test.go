@@ -439,6 +439,113 @@ func TestAllocsFit_TerminalAlloc(t *testing.T) {
 	require.EqualValues(2048, used.Flattened.Memory.MemoryMB)
 }
 
+func TestAllocsFit_Cores(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			Cpu: NodeCpuResources{
+				CpuShares:          4000,
+				TotalCpuCores:      4,
+				ReservableCpuCores: []uint16{0, 1, 2, 3},
+			},
+			Memory: NodeMemoryResources{
+				MemoryMB: 2048,
+			},
+		},
+	}
+
+	alloc := func(cores ...uint16) *Allocation {
+		return &Allocation{
+			AllocatedResources: &AllocatedResources{
+				Tasks: map[string]*AllocatedTaskResources{
+					"web": {
+						Cpu: AllocatedCpuResources{
+							CpuShares:     int64(len(cores)) * 1000,
+							ReservedCores: cores,
+						},
+						Memory: AllocatedMemoryResources{
+							MemoryMB: 256,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Allocations reserving distinct cores fit
+	fit, _, used, err := AllocsFit(n, []*Allocation{alloc(0, 1), alloc(2)}, nil, false)
+	require.NoError(err)
+	require.True(fit)
+	require.EqualValues(3000, used.Flattened.Cpu.CpuShares)
+
+	// Allocations reserving the same core don't fit
+	fit, dim, _, err := AllocsFit(n, []*Allocation{alloc(0, 1), alloc(1)}, nil, false)
+	require.NoError(err)
+	require.False(fit)
+	require.Equal("cores", dim)
+
+	// Allocations reserving a core that isn't reservable don't fit
+	fit, dim, _, err = AllocsFit(n, []*Allocation{alloc(4)}, nil, false)
+	require.NoError(err)
+	require.False(fit)
+	require.Equal("cores", dim)
+}
+
+func TestAllocsFit_HugePages(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			Cpu: NodeCpuResources{
+				CpuShares: 4000,
+			},
+			Memory: NodeMemoryResources{
+				MemoryMB:     2048,
+				HugePages2MB: 512,
+				HugePages1GB: 1,
+			},
+		},
+	}
+
+	alloc := func(pages2MB, pages1GB int64) *Allocation {
+		return &Allocation{
+			AllocatedResources: &AllocatedResources{
+				Tasks: map[string]*AllocatedTaskResources{
+					"web": {
+						Cpu: AllocatedCpuResources{
+							CpuShares: 1000,
+						},
+						Memory: AllocatedMemoryResources{
+							MemoryMB:     256,
+							HugePages2MB: pages2MB,
+							HugePages1GB: pages1GB,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Allocations within the node's huge pages fit
+	fit, _, used, err := AllocsFit(n, []*Allocation{alloc(256, 1), alloc(256, 0)}, nil, false)
+	require.NoError(err)
+	require.True(fit)
+	require.EqualValues(512, used.Flattened.Memory.HugePages2MB)
+	require.EqualValues(1, used.Flattened.Memory.HugePages1GB)
+
+	// Allocations exceeding the node's huge pages don't fit
+	fit, dim, _, err := AllocsFit(n, []*Allocation{alloc(256, 0), alloc(512, 0)}, nil, false)
+	require.NoError(err)
+	require.False(fit)
+	require.Equal("hugepages_2mb", dim)
+
+	fit, dim, _, err = AllocsFit(n, []*Allocation{alloc(0, 1), alloc(0, 1)}, nil, false)
+	require.NoError(err)
+	require.False(fit)
+	require.Equal("hugepages_1gb", dim)
+}
+
 // Tests that AllocsFit detects device collisions
 func TestAllocsFit_Devices(t *testing.T) {
 	require := require.New(t)
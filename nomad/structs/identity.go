@@ -0,0 +1,117 @@
+package structs
+
+import (
+	"fmt"
+)
+
+const (
+	// IdentityAudienceNomad is the audience of the workload identities that
+	// tasks call the Nomad API with
+	IdentityAudienceNomad = "nomad"
+)
+
+// WorkloadIdentity configures how the workload identity of a task is exposed
+// to it. The identity is a token valid for the Nomad API, which grants read
+// access to the task's job and to the variables below the job's path.
+type WorkloadIdentity struct {
+	// Env sets the NOMAD_TOKEN environment variable of the task
+	Env bool
+
+	// File writes the identity to the nomad_token file of the task's secrets
+	// directory
+	File bool
+}
+
+func (w *WorkloadIdentity) Copy() *WorkloadIdentity {
+	if w == nil {
+		return nil
+	}
+	nw := new(WorkloadIdentity)
+	*nw = *w
+	return nw
+}
+
+// IdentityClaims are the claims of the workload identity JWT of a task. The
+// Nomad specific claims can be bound or mapped by the roles of a Vault JWT
+// auth method.
+type IdentityClaims struct {
+	Namespace    string `json:"nomad_namespace"`
+	JobID        string `json:"nomad_job_id"`
+	TaskGroup    string `json:"nomad_task_group"`
+	Task         string `json:"nomad_task"`
+	AllocationID string `json:"nomad_allocation_id"`
+
+	// Subject uniquely identifies the task within the region, in the form
+	// <namespace>:<job>:<group>:<task>
+	Subject string `json:"sub"`
+
+	// Audience is set on identities that are only valid for a given
+	// audience, such as IdentityAudienceNomad
+	Audience string `json:"aud,omitempty"`
+
+	// IssuedAt, NotBefore and Expiry are Unix timestamps. Identities without
+	// an expiry are valid until their allocation is terminal.
+	IssuedAt  int64 `json:"iat"`
+	NotBefore int64 `json:"nbf"`
+	Expiry    int64 `json:"exp,omitempty"`
+}
+
+// NewIdentityClaims returns the identity claims of the task of the allocation.
+func NewIdentityClaims(alloc *Allocation, task string) *IdentityClaims {
+	return &IdentityClaims{
+		Namespace:    alloc.Namespace,
+		JobID:        alloc.JobID,
+		TaskGroup:    alloc.TaskGroup,
+		Task:         task,
+		AllocationID: alloc.ID,
+		Subject:      fmt.Sprintf("%s:%s:%s:%s", alloc.Namespace, alloc.JobID, alloc.TaskGroup, task),
+	}
+}
+
+// SignIdentitiesRequest is used by a client to request the workload identity
+// JWTs of the given tasks of an allocation
+type SignIdentitiesRequest struct {
+	NodeID   string
+	SecretID string
+	AllocID  string
+	Tasks    []string
+
+	// Audience requests identities for the Nomad API when set to
+	// IdentityAudienceNomad. Otherwise the identities are used to log in to
+	// Vault.
+	Audience string
+
+	QueryOptions
+}
+
+// SignIdentitiesResponse returns the signed workload identities of each
+// requested task
+type SignIdentitiesResponse struct {
+	// Tasks is a mapping between the task name and its signed JWT
+	Tasks map[string]string
+
+	// Error stores any error that occurred. Errors are stored here so we can
+	// communicate whether it is retriable
+	Error *RecoverableError
+
+	QueryMeta
+}
+
+// JSONWebKey is the public key used to verify workload identities, in the
+// JSON Web Key format of RFC 7517.
+type JSONWebKey struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+// KeyringListPublicResponse is used to return the public keys that verify
+// workload identities
+type KeyringListPublicResponse struct {
+	Keys []*JSONWebKey
+	QueryMeta
+}
@@ -0,0 +1,107 @@
+package structs
+
+import "strings"
+
+// JobPrefetch is a hint for the clients likely to run a job to fetch the
+// images and artifacts of its tasks ahead of its placements, so deployments
+// don't wait on large downloads.
+type JobPrefetch struct {
+	// Images prefetches the images of the tasks whose drivers support it.
+	Images bool
+
+	// Artifacts prefetches the artifacts of the tasks that can be cached by
+	// the clients. See TaskArtifact.Prefetchable.
+	Artifacts bool
+}
+
+// Copy returns a copy of the prefetch hint.
+func (p *JobPrefetch) Copy() *JobPrefetch {
+	if p == nil {
+		return nil
+	}
+	np := *p
+	return &np
+}
+
+// TaskPrefetch is the image and artifacts of a task a client prefetches.
+type TaskPrefetch struct {
+	Namespace string
+	JobID     string
+	TaskGroup string
+	Task      string
+	Driver    string
+
+	// Image is the image of the task, empty if it isn't prefetched.
+	Image string
+
+	// Artifacts are the artifacts of the task that are prefetched.
+	Artifacts []*TaskArtifact
+}
+
+// NodePrefetchesResponse is used to return the prefetches of a node.
+type NodePrefetchesResponse struct {
+	Prefetches []*TaskPrefetch
+	QueryMeta
+}
+
+// TaskGroupPrefetches returns the prefetches of the tasks of the task group
+// following the prefetch hint of the job, or nil if the job has none.
+func (j *Job) TaskGroupPrefetches(tg *TaskGroup) []*TaskPrefetch {
+	p := j.Prefetch
+	if p == nil {
+		return nil
+	}
+
+	var prefetches []*TaskPrefetch
+	for _, task := range tg.Tasks {
+		tp := &TaskPrefetch{
+			Namespace: j.Namespace,
+			JobID:     j.ID,
+			TaskGroup: tg.Name,
+			Task:      task.Name,
+			Driver:    task.Driver,
+		}
+		if p.Images {
+			tp.Image = task.Image()
+		}
+		if p.Artifacts {
+			for _, artifact := range task.Artifacts {
+				if artifact.Prefetchable() {
+					tp.Artifacts = append(tp.Artifacts, artifact.Copy())
+				}
+			}
+		}
+		if tp.Image != "" || len(tp.Artifacts) != 0 {
+			prefetches = append(prefetches, tp)
+		}
+	}
+	return prefetches
+}
+
+// Image returns the image of the task from the image option of its driver
+// configuration, or an empty string if it has none or it's interpolated.
+func (t *Task) Image() string {
+	image, _ := t.Config["image"].(string)
+	if strings.Contains(image, "${") {
+		return ""
+	}
+	return image
+}
+
+// Prefetchable returns whether the artifact can be prefetched and shared
+// between tasks. Its content can't change, as it must have a checksum, and
+// its source and options mustn't be interpolated.
+func (ta *TaskArtifact) Prefetchable() bool {
+	if _, ok := ta.GetterOptions["checksum"]; !ok {
+		return false
+	}
+	if strings.Contains(ta.GetterSource, "${") {
+		return false
+	}
+	for _, v := range ta.GetterOptions {
+		if strings.Contains(v, "${") {
+			return false
+		}
+	}
+	return true
+}
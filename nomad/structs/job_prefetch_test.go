@@ -0,0 +1,96 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskArtifact_Prefetchable(t *testing.T) {
+	cases := []struct {
+		name     string
+		artifact *TaskArtifact
+		expected bool
+	}{
+		{
+			name: "checksum",
+			artifact: &TaskArtifact{
+				GetterSource:  "https://example.com/app.tar.gz",
+				GetterOptions: map[string]string{"checksum": "sha256:abc"},
+			},
+			expected: true,
+		},
+		{
+			name: "no checksum",
+			artifact: &TaskArtifact{
+				GetterSource: "https://example.com/app.tar.gz",
+			},
+		},
+		{
+			name: "interpolated source",
+			artifact: &TaskArtifact{
+				GetterSource:  "https://example.com/${NOMAD_META_version}.tar.gz",
+				GetterOptions: map[string]string{"checksum": "sha256:abc"},
+			},
+		},
+		{
+			name: "interpolated checksum",
+			artifact: &TaskArtifact{
+				GetterSource:  "https://example.com/app.tar.gz",
+				GetterOptions: map[string]string{"checksum": "${NOMAD_META_checksum}"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, c.artifact.Prefetchable())
+		})
+	}
+}
+
+func TestJob_TaskGroupPrefetches(t *testing.T) {
+	require := require.New(t)
+
+	artifact := &TaskArtifact{
+		GetterSource:  "https://example.com/app.tar.gz",
+		GetterOptions: map[string]string{"checksum": "sha256:abc"},
+	}
+	tg := &TaskGroup{
+		Name: "web",
+		Tasks: []*Task{
+			{
+				Name:      "app",
+				Driver:    "docker",
+				Config:    map[string]interface{}{"image": "app:1.0"},
+				Artifacts: []*TaskArtifact{artifact, {GetterSource: "https://example.com/config"}},
+			},
+			{
+				Name:   "sidecar",
+				Driver: "docker",
+				Config: map[string]interface{}{"image": "proxy:${NOMAD_META_version}"},
+			},
+		},
+	}
+	job := &Job{Namespace: DefaultNamespace, ID: "example", TaskGroups: []*TaskGroup{tg}}
+
+	// No hint
+	require.Nil(job.TaskGroupPrefetches(tg))
+
+	job.Prefetch = &JobPrefetch{Images: true, Artifacts: true}
+	require.Equal([]*TaskPrefetch{{
+		Namespace: DefaultNamespace,
+		JobID:     "example",
+		TaskGroup: "web",
+		Task:      "app",
+		Driver:    "docker",
+		Image:     "app:1.0",
+		Artifacts: []*TaskArtifact{artifact},
+	}}, job.TaskGroupPrefetches(tg))
+
+	job.Prefetch = &JobPrefetch{Artifacts: true}
+	prefetches := job.TaskGroupPrefetches(tg)
+	require.Len(prefetches, 1)
+	require.Empty(prefetches[0].Image)
+	require.Len(prefetches[0].Artifacts, 1)
+}
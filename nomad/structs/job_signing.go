@@ -0,0 +1,93 @@
+package structs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// JobMetaSigner is the job metadata key the servers record the name of
+	// the signer of a job under, once its signature is verified.
+	JobMetaSigner = "nomad_signer"
+
+	// JobMetaSignatureDigest is the job metadata key the servers record the
+	// hex encoded SHA-256 digest of the signed payload of a job under.
+	JobMetaSignatureDigest = "nomad_signature_digest"
+)
+
+// StripSignatureMeta removes the metadata the servers record about the
+// signature of the job, so that it can only be set by verifying a signature.
+func (j *Job) StripSignatureMeta() {
+	delete(j.Meta, JobMetaSigner)
+	delete(j.Meta, JobMetaSignatureDigest)
+}
+
+// SigningPayload returns the bytes the detached signature of the job is
+// computed over. The job must be canonicalized. Fields set by the servers and
+// the Vault token, which is a secret given at submission, are excluded so the
+// signature can be produced ahead of the submission.
+func (j *Job) SigningPayload() ([]byte, error) {
+	job := j.Copy()
+	job.StripSignatureMeta()
+	if len(job.Meta) == 0 {
+		job.Meta = nil
+	}
+	job.VaultToken = ""
+	job.Status = ""
+	job.StatusDescription = ""
+	job.Stable = false
+	job.Version = 0
+	job.SubmitTime = 0
+	job.CreateIndex = 0
+	job.ModifyIndex = 0
+	job.JobModifyIndex = 0
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job signing payload: %v", err)
+	}
+	return payload, nil
+}
+
+// SignJobPayload signs the signing payload of a job. Ed25519 keys sign the
+// payload itself while ECDSA and RSA keys sign its SHA-256 digest.
+func SignJobPayload(key crypto.Signer, payload []byte) ([]byte, error) {
+	switch key.Public().(type) {
+	case ed25519.PublicKey:
+		return key.Sign(rand.Reader, payload, crypto.Hash(0))
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		return key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", key.Public())
+	}
+}
+
+// VerifyJobSignature verifies the signature of the signing payload of a job
+// against the public key.
+func VerifyJobSignature(key crypto.PublicKey, payload, signature []byte) error {
+	var ok bool
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, payload, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		ok = ecdsa.VerifyASN1(k, digest[:], signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	if !ok {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package structs
+
+const (
+	// JobSubmissionMaxSize is the maximum size of the source and variables of
+	// a job submission. Larger submissions aren't stored so that job files
+	// embedding large payloads don't bloat the state.
+	JobSubmissionMaxSize = 1024 * 1024
+)
+
+// JobSubmission is the job file a job version was parsed from, as submitted
+// by the user, along with the values bound to the variables it declares.
+// Submissions are stored and garbage collected along with the job versions.
+type JobSubmission struct {
+	// Source is the job file
+	Source string
+
+	// Variables are the values of the variables declared by the job file, in
+	// the "name = value" form of variable files
+	Variables string
+
+	// Namespace, JobID and Version identify the job version, and are set by
+	// the servers when it's registered
+	Namespace string
+	JobID     string
+	Version   uint64
+}
+
+// Size returns the size of the source and variables of the submission.
+func (s *JobSubmission) Size() int {
+	return len(s.Source) + len(s.Variables)
+}
+
+func (s *JobSubmission) Copy() *JobSubmission {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// JobSubmissionRequest is used to get the submission of a job version
+type JobSubmissionRequest struct {
+	JobID   string
+	Version uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is used to return the submission of a job version.
+// Submission is nil if the version was registered without one.
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
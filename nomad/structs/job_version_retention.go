@@ -0,0 +1,67 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// JobMaxTrackedVersions is the maximum number of historic job versions that
+// can be kept for a job.
+const JobMaxTrackedVersions = 100
+
+// JobVersionRetention configures how many historic versions of a job the
+// servers keep. Versions beyond Count, or submitted longer than Age before
+// the latest version, are pruned. The latest version is always kept, and the
+// latest stable version is kept in place of the oldest other version.
+type JobVersionRetention struct {
+	// Count is the number of versions kept, including the latest one. Zero
+	// keeps JobTrackedVersions versions, or up to JobMaxTrackedVersions if
+	// Age is set.
+	Count int
+
+	// Age is how long before the latest version older versions are kept.
+	// Zero if versions aren't pruned by age.
+	Age time.Duration
+}
+
+// Copy returns a copy of the version retention.
+func (r *JobVersionRetention) Copy() *JobVersionRetention {
+	if r == nil {
+		return nil
+	}
+	nr := *r
+	return &nr
+}
+
+// Validate validates the version retention.
+func (r *JobVersionRetention) Validate() error {
+	var mErr multierror.Error
+	if r.Count < 0 || r.Count > JobMaxTrackedVersions {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("Version retention count must be between 0 and %d", JobMaxTrackedVersions))
+	}
+	if r.Age < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Version retention age must not be negative"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Limits returns the number of versions kept and the age beyond which
+// versions are pruned, zero if versions aren't pruned by age. A nil version
+// retention keeps JobTrackedVersions versions.
+func (r *JobVersionRetention) Limits() (int, time.Duration) {
+	if r == nil {
+		return JobTrackedVersions, 0
+	}
+
+	count := r.Count
+	if count == 0 {
+		count = JobTrackedVersions
+		if r.Age != 0 {
+			count = JobMaxTrackedVersions
+		}
+	}
+	return count, r.Age
+}
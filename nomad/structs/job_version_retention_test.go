@@ -0,0 +1,41 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobVersionRetention_Validate(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError((&JobVersionRetention{Count: 50, Age: time.Hour}).Validate())
+
+	err := (&JobVersionRetention{Count: JobMaxTrackedVersions + 1, Age: -time.Hour}).Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "count must be between")
+	require.Contains(err.Error(), "age must not be negative")
+}
+
+func TestJobVersionRetention_Limits(t *testing.T) {
+	cases := []struct {
+		name      string
+		retention *JobVersionRetention
+		count     int
+		age       time.Duration
+	}{
+		{"unset", nil, JobTrackedVersions, 0},
+		{"count", &JobVersionRetention{Count: 20}, 20, 0},
+		{"age", &JobVersionRetention{Age: time.Hour}, JobMaxTrackedVersions, time.Hour},
+		{"both", &JobVersionRetention{Count: 3, Age: time.Hour}, 3, time.Hour},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			count, age := c.retention.Limits()
+			require.Equal(t, c.count, count)
+			require.Equal(t, c.age, age)
+		})
+	}
+}
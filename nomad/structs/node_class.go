@@ -42,7 +42,7 @@ func (n *Node) ComputeClass() error {
 // included in the computed node class.
 func (n Node) HashInclude(field string, v interface{}) (bool, error) {
 	switch field {
-	case "Datacenter", "Attributes", "Meta", "NodeClass", "NodeResources":
+	case "Datacenter", "Attributes", "Meta", "NodeClass", "NodePool", "NodeResources":
 		return true, nil
 	default:
 		return false, nil
@@ -108,7 +108,7 @@ func (n NodeDeviceResource) HashIncludeMap(field string, k, v interface{}) (bool
 func EscapedConstraints(constraints []*Constraint) []*Constraint {
 	var escaped []*Constraint
 	for _, c := range constraints {
-		if constraintTargetEscapes(c.LTarget) || constraintTargetEscapes(c.RTarget) {
+		if ConstraintEscapes(c) {
 			escaped = append(escaped, c)
 		}
 	}
@@ -116,6 +116,12 @@ func EscapedConstraints(constraints []*Constraint) []*Constraint {
 	return escaped
 }
 
+// ConstraintEscapes returns whether the constraint escapes computed node
+// classes.
+func ConstraintEscapes(c *Constraint) bool {
+	return constraintTargetEscapes(c.LTarget) || constraintTargetEscapes(c.RTarget)
+}
+
 // constraintTargetEscapes returns whether the target of a constraint escapes
 // computed node class optimization.
 func constraintTargetEscapes(target string) bool {
@@ -0,0 +1,87 @@
+package structs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// NodeMaintenanceWindow is a recurring window during which a node is
+// automatically marked as ineligible for scheduling, after which it is marked
+// as eligible again.
+type NodeMaintenanceWindow struct {
+	// Cron is the cron expression of the start of the window
+	Cron string
+
+	// Duration is how long the window lasts
+	Duration time.Duration
+
+	// TimeZone is the time zone the cron expression is evaluated in. It
+	// defaults to UTC.
+	TimeZone string
+}
+
+// Copy returns a copy of the maintenance window.
+func (w *NodeMaintenanceWindow) Copy() *NodeMaintenanceWindow {
+	if w == nil {
+		return nil
+	}
+	nw := new(NodeMaintenanceWindow)
+	*nw = *w
+	return nw
+}
+
+// Validate returns an error if the maintenance window is invalid.
+func (w *NodeMaintenanceWindow) Validate() error {
+	var mErr multierror.Error
+	if _, err := cronexpr.Parse(w.Cron); err != nil {
+		multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", w.Cron, err))
+	}
+	if w.Duration <= 0 {
+		multierror.Append(&mErr, fmt.Errorf("Duration must be positive"))
+	}
+	if w.TimeZone != "" {
+		if _, err := time.LoadLocation(w.TimeZone); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("Invalid time zone %q: %v", w.TimeZone, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// End returns the end of the occurrence of the window the given time is
+// within, or false if it isn't within the window.
+func (w *NodeMaintenanceWindow) End(now time.Time) (time.Time, bool) {
+	e, err := cronexpr.Parse(w.Cron)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	loc := time.UTC
+	if w.TimeZone != "" {
+		if loc, err = time.LoadLocation(w.TimeZone); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	// The time is within the window if it started after the time minus the
+	// duration of the window
+	start, err := CronParseNext(e, now.Add(-w.Duration).In(loc), w.Cron)
+	if err != nil || start.IsZero() || start.After(now) {
+		return time.Time{}, false
+	}
+	return start.Add(w.Duration).UTC(), true
+}
+
+// MaintenanceWindowEnd returns the latest end of the maintenance windows of
+// the node the given time is within, or false if it isn't within any.
+func (n *Node) MaintenanceWindowEnd(now time.Time) (time.Time, bool) {
+	var end time.Time
+	for _, w := range n.MaintenanceWindows {
+		if e, ok := w.End(now); ok && e.After(end) {
+			end = e
+		}
+	}
+	return end, !end.IsZero()
+}
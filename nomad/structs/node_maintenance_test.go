@@ -0,0 +1,92 @@
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMaintenanceWindow_Validate(t *testing.T) {
+	require := require.New(t)
+
+	w := &NodeMaintenanceWindow{
+		Cron:     "0 2 * * SUN",
+		Duration: 2 * time.Hour,
+		TimeZone: "America/New_York",
+	}
+	require.NoError(w.Validate())
+
+	w = &NodeMaintenanceWindow{
+		Cron:     "not a cron",
+		TimeZone: "Nowhere/Special",
+	}
+	err := w.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "Invalid cron spec")
+	require.Contains(err.Error(), "Duration must be positive")
+	require.Contains(err.Error(), "Invalid time zone")
+}
+
+func TestNodeMaintenanceWindow_End(t *testing.T) {
+	// Every day from 02:00 to 04:00 UTC
+	w := &NodeMaintenanceWindow{
+		Cron:     "0 2 * * *",
+		Duration: 2 * time.Hour,
+	}
+
+	cases := []struct {
+		name string
+		now  time.Time
+		end  time.Time
+		ok   bool
+	}{
+		{
+			name: "before",
+			now:  time.Date(2019, 3, 1, 1, 59, 0, 0, time.UTC),
+		},
+		{
+			name: "start",
+			now:  time.Date(2019, 3, 1, 2, 0, 0, 0, time.UTC),
+			end:  time.Date(2019, 3, 1, 4, 0, 0, 0, time.UTC),
+			ok:   true,
+		},
+		{
+			name: "within",
+			now:  time.Date(2019, 3, 1, 3, 30, 0, 0, time.UTC),
+			end:  time.Date(2019, 3, 1, 4, 0, 0, 0, time.UTC),
+			ok:   true,
+		},
+		{
+			name: "after",
+			now:  time.Date(2019, 3, 1, 4, 1, 0, 0, time.UTC),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			end, ok := w.End(c.now)
+			require.Equal(t, c.ok, ok)
+			require.True(t, c.end.Equal(end), "expected %v, got %v", c.end, end)
+		})
+	}
+}
+
+func TestNode_MaintenanceWindowEnd(t *testing.T) {
+	require := require.New(t)
+
+	// Overlapping windows end with the latest of them
+	n := &Node{
+		MaintenanceWindows: []*NodeMaintenanceWindow{
+			{Cron: "0 2 * * *", Duration: time.Hour},
+			{Cron: "30 2 * * *", Duration: 2 * time.Hour},
+		},
+	}
+
+	end, ok := n.MaintenanceWindowEnd(time.Date(2019, 3, 1, 2, 45, 0, 0, time.UTC))
+	require.True(ok)
+	require.Equal(time.Date(2019, 3, 1, 4, 30, 0, 0, time.UTC), end)
+
+	_, ok = n.MaintenanceWindowEnd(time.Date(2019, 3, 1, 5, 0, 0, 0, time.UTC))
+	require.False(ok)
+}
@@ -0,0 +1,141 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// NodePoolAll is the built-in node pool of all the nodes of the region.
+	// Nodes can't be registered in it, but jobs may target it to be placed
+	// on any node.
+	NodePoolAll = "all"
+
+	// NodePoolDefault is the built-in node pool of the nodes and jobs that
+	// don't set a node pool.
+	NodePoolDefault = "default"
+
+	// maxNodePoolDescriptionLength limits a node pool description length
+	maxNodePoolDescriptionLength = 256
+)
+
+// validNodePoolName is used to validate a node pool name
+var validNodePoolName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+
+// NodePool is a named set of nodes. Nodes belong to exactly one pool and jobs
+// are only placed on the nodes of the pool they target, which isolates the
+// workloads of different pools more coarsely than constraints.
+type NodePool struct {
+	Name        string
+	Description string
+	Meta        map[string]string
+
+	// SchedulerConfiguration overrides the scheduler configuration of the
+	// cluster for the jobs of the pool. It is nil if the pool uses the
+	// cluster's configuration.
+	SchedulerConfiguration *NodePoolSchedulerConfiguration
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NodePoolSchedulerConfiguration is the scheduler configuration of a node
+// pool. Unset fields use the value of the cluster's scheduler configuration.
+type NodePoolSchedulerConfiguration struct {
+	// SystemSchedulerPreemptionEnabled specifies whether system jobs may
+	// preempt lower priority allocations on the nodes of the pool.
+	SystemSchedulerPreemptionEnabled *bool
+}
+
+// IsBuiltIn returns whether the node pool is one of the built-in pools, which
+// can't be deleted.
+func (n *NodePool) IsBuiltIn() bool {
+	return n.Name == NodePoolAll || n.Name == NodePoolDefault
+}
+
+// Copy returns a deep copy of the node pool.
+func (n *NodePool) Copy() *NodePool {
+	if n == nil {
+		return nil
+	}
+
+	nn := new(NodePool)
+	*nn = *n
+	nn.Meta = helper.CopyMapStringString(n.Meta)
+	if n.SchedulerConfiguration != nil {
+		sc := *n.SchedulerConfiguration
+		if sc.SystemSchedulerPreemptionEnabled != nil {
+			sc.SystemSchedulerPreemptionEnabled = helper.BoolToPtr(*sc.SystemSchedulerPreemptionEnabled)
+		}
+		nn.SchedulerConfiguration = &sc
+	}
+	return nn
+}
+
+// Validate is used to sanity check a node pool
+func (n *NodePool) Validate() error {
+	var mErr multierror.Error
+	if !validNodePoolName.MatchString(n.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name %q", n.Name))
+	}
+	if len(n.Description) > maxNodePoolDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxNodePoolDescriptionLength))
+	}
+	if n.Name == NodePoolAll && n.SchedulerConfiguration != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("the %q node pool can't have a scheduler configuration", NodePoolAll))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SystemSchedulerPreemptionEnabled returns whether system jobs may preempt
+// allocations on the nodes of the pool, given the cluster's scheduler
+// configuration.
+func (n *NodePool) SystemSchedulerPreemptionEnabled(config *SchedulerConfiguration) bool {
+	if n != nil && n.SchedulerConfiguration != nil && n.SchedulerConfiguration.SystemSchedulerPreemptionEnabled != nil {
+		return *n.SchedulerConfiguration.SystemSchedulerPreemptionEnabled
+	}
+	return config == nil || config.PreemptionConfig.SystemSchedulerEnabled
+}
+
+// ValidNodePoolName returns whether the name is a valid node pool name.
+func ValidNodePoolName(name string) bool {
+	return validNodePoolName.MatchString(name)
+}
+
+// NodePoolListRequest is used to request a list of node pools
+type NodePoolListRequest struct {
+	QueryOptions
+}
+
+// NodePoolListResponse is used for a list request
+type NodePoolListResponse struct {
+	NodePools []*NodePool
+	QueryMeta
+}
+
+// NodePoolSpecificRequest is used to query a specific node pool
+type NodePoolSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNodePoolResponse is used to return a single node pool
+type SingleNodePoolResponse struct {
+	NodePool *NodePool
+	QueryMeta
+}
+
+// NodePoolUpsertRequest is used to upsert a set of node pools
+type NodePoolUpsertRequest struct {
+	NodePools []*NodePool
+	WriteRequest
+}
+
+// NodePoolDeleteRequest is used to delete a set of node pools
+type NodePoolDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -0,0 +1,67 @@
+package structs
+
+import (
+	"errors"
+	"time"
+)
+
+// OneTimeTokenTTL is how long a one-time token can be exchanged for the ACL
+// token it was created for.
+const OneTimeTokenTTL = 10 * time.Minute
+
+// ErrOneTimeTokenNotFound is returned when exchanging a one-time token that
+// doesn't exist, was already exchanged or is expired.
+var ErrOneTimeTokenNotFound = errors.New("one-time token not found")
+
+// OneTimeToken is a short-lived secret that can be exchanged once for the ACL
+// token it was created with. It lets the CLI hand its ACL token over to the
+// web UI without the secret ID of the ACL token appearing in a URL.
+type OneTimeToken struct {
+	OneTimeSecretID string
+	AccessorID      string // Accessor ID of the ACL token
+	ExpiresAt       time.Time
+	CreateIndex     uint64
+	ModifyIndex     uint64
+}
+
+// IsExpired returns whether the one-time token can no longer be exchanged at
+// the given time.
+func (o *OneTimeToken) IsExpired(now time.Time) bool {
+	return !now.Before(o.ExpiresAt)
+}
+
+// OneTimeTokenUpsertRequest is used to create a one-time token
+type OneTimeTokenUpsertRequest struct {
+	WriteRequest
+}
+
+// OneTimeTokenUpsertResponse is used to return the created one-time token
+type OneTimeTokenUpsertResponse struct {
+	OneTimeToken *OneTimeToken
+	WriteMeta
+}
+
+// OneTimeTokenExchangeRequest is used to exchange a one-time token for the
+// ACL token it was created with
+type OneTimeTokenExchangeRequest struct {
+	OneTimeSecretID string
+
+	// Timestamp is set by the server to check whether the one-time token is
+	// expired when applying the exchange
+	Timestamp time.Time
+
+	WriteRequest
+}
+
+// OneTimeTokenExchangeResponse is used to return the exchanged ACL token
+type OneTimeTokenExchangeResponse struct {
+	Token *ACLToken
+	WriteMeta
+}
+
+// OneTimeTokenExpireRequest is used to delete the one-time tokens expired at
+// the timestamp
+type OneTimeTokenExpireRequest struct {
+	Timestamp time.Time
+	WriteRequest
+}
@@ -31,6 +31,43 @@ type RaftServer struct {
 
 	// RaftProtocol is the version of the Raft protocol spoken by this server.
 	RaftProtocol string
+
+	// Stats has the replication and log store stats of this server, or is
+	// nil if they couldn't be fetched, in which case StatsError has the
+	// reason.
+	Stats      *RaftServerStats
+	StatsError string
+
+	// Lag is the number of log entries this server is behind the leader.
+	Lag uint64
+}
+
+// RaftServerStats has the replication and log store stats of a server in the
+// Raft configuration.
+type RaftServerStats struct {
+	// LastContact is the time since the server last heard from the leader,
+	// zero on the leader itself or -1 if the server never heard from it.
+	LastContact time.Duration
+
+	// LastIndex is the index of the last log entry of the server.
+	LastIndex uint64
+
+	// LastSnapshotIndex and LastSnapshotTime are the index and the time of
+	// the last snapshot of the server, zero if it didn't take any.
+	LastSnapshotIndex uint64
+	LastSnapshotTime  time.Time
+
+	// LogStoreSize is the size in bytes of the log store of the server, zero
+	// if the log store is in memory.
+	LogStoreSize int64
+
+	// LogStoreEntries is the number of entries in the log store of the
+	// server, which snapshots truncate.
+	LogStoreEntries uint64
+
+	// Warnings has the issues found with the log store of the server, such
+	// as it needing compaction.
+	Warnings []string
 }
 
 // RaftConfigurationResponse is returned when querying for the current Raft
@@ -126,6 +163,10 @@ type SchedulerConfiguration struct {
 	// priority jobs to place higher priority jobs.
 	PreemptionConfig PreemptionConfig
 
+	// PlanRejectionTracker configures the quarantine of nodes whose
+	// placements are repeatedly rejected by the plan applier.
+	PlanRejectionTracker PlanRejectionTrackerConfig
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -155,6 +196,45 @@ type PreemptionConfig struct {
 	SystemSchedulerEnabled bool
 }
 
+const (
+	// DefaultPlanRejectionNodeThreshold and DefaultPlanRejectionNodeWindow
+	// are used when the plan rejection tracker doesn't set them.
+	DefaultPlanRejectionNodeThreshold = 100
+	DefaultPlanRejectionNodeWindow    = 5 * time.Minute
+)
+
+// PlanRejectionTrackerConfig configures the quarantine of nodes whose
+// placements are repeatedly rejected by the plan applier, which happens when
+// the resources of a node are out of sync with the schedulers' view of it.
+type PlanRejectionTrackerConfig struct {
+	// Enabled is whether nodes are marked as ineligible for scheduling once
+	// they reach NodeThreshold rejections within NodeWindow.
+	Enabled bool
+
+	// NodeThreshold is the number of rejections within the window after which
+	// a node is quarantined.
+	NodeThreshold int
+
+	// NodeWindow is the sliding window rejections are counted in.
+	NodeWindow time.Duration
+}
+
+// Threshold returns the node threshold, or its default if unset.
+func (c *PlanRejectionTrackerConfig) Threshold() int {
+	if c.NodeThreshold <= 0 {
+		return DefaultPlanRejectionNodeThreshold
+	}
+	return c.NodeThreshold
+}
+
+// Window returns the node window, or its default if unset.
+func (c *PlanRejectionTrackerConfig) Window() time.Duration {
+	if c.NodeWindow <= 0 {
+		return DefaultPlanRejectionNodeWindow
+	}
+	return c.NodeWindow
+}
+
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
 // current Scheduler configuration of the cluster.
 type SchedulerSetConfigRequest struct {
@@ -167,3 +247,240 @@ type SchedulerSetConfigRequest struct {
 	// WriteRequest holds the ACL token to go along with this request.
 	WriteRequest
 }
+
+// EvalBrokerStatus is a point in time view of the evaluation broker's
+// internal state. It is used to diagnose scheduling stalls.
+type EvalBrokerStatus struct {
+	// Enabled is whether the broker is enabled. The broker is only enabled
+	// on the leader.
+	Enabled bool
+
+	// TotalReady is the number of evaluations ready to be dequeued.
+	TotalReady int
+
+	// TotalUnacked is the number of evaluations dequeued by a scheduler but
+	// not yet acknowledged.
+	TotalUnacked int
+
+	// TotalBlocked is the number of evaluations waiting for an outstanding
+	// evaluation of the same job to complete.
+	TotalBlocked int
+
+	// TotalWaiting is the number of evaluations waiting for their wait time
+	// to elapse before being enqueued.
+	TotalWaiting int
+
+	// TotalFailed is the number of evaluations that reached their delivery
+	// limit and are waiting for a failed follow-up evaluation to be created.
+	TotalFailed int
+
+	// ByScheduler breaks down the broker's state by scheduler type.
+	ByScheduler map[string]*EvalBrokerSchedulerStatus
+}
+
+// EvalBrokerSchedulerStatus is the evaluation broker's state for a single
+// scheduler type.
+type EvalBrokerSchedulerStatus struct {
+	Ready   int
+	Unacked int
+	Blocked int
+
+	// OldestReady and OldestUnacked are how long the oldest ready and unacked
+	// evaluations have been held by the broker.
+	OldestReady   time.Duration
+	OldestUnacked time.Duration
+}
+
+// EvalBrokerStatusResponse is used to return the evaluation broker's status.
+type EvalBrokerStatusResponse struct {
+	Status *EvalBrokerStatus
+	QueryMeta
+}
+
+// NodePlanRejections are the plan rejections of a node tracked by the
+// leader's plan applier.
+type NodePlanRejections struct {
+	NodeID string
+
+	// Rejections is the number of rejections within the window of the plan
+	// rejection tracker, and Total is the number of rejections since the
+	// server became the leader.
+	Rejections int
+	Total      uint64
+
+	// LastReason is the resource or claim that conflicted for the last
+	// rejection, such as "memory" or "reserved port collision".
+	LastReason string
+
+	// LastEvalID and LastJobID are the evaluation and job of the last
+	// rejected plan.
+	LastEvalID string
+	LastJobID  string
+
+	// LastRejection is the time of the last rejection.
+	LastRejection time.Time
+
+	// Quarantined is set once the node is marked as ineligible for
+	// scheduling because it reached the rejection threshold.
+	Quarantined bool
+}
+
+// PlanRejectionsResponse is used to return the plan rejections of nodes.
+type PlanRejectionsResponse struct {
+	Nodes []*NodePlanRejections
+	QueryMeta
+}
+
+// ReplicationStatus is a point in time view of this region's replication of
+// data from the authoritative region.
+type ReplicationStatus struct {
+	// Enabled is whether replication is configured for this region. It is
+	// only enabled for non-authoritative regions with ACLs enabled.
+	Enabled bool
+
+	// SourceRegion is the authoritative region data is replicated from.
+	SourceRegion string
+
+	// SourceError is set if the authoritative region could not be queried
+	// for its current indexes.
+	SourceError string
+
+	// ACLPolicies, ACLRoles and ACLTokens are the status of ACL policy, role
+	// and token replication.
+	ACLPolicies *ReplicationTypeStatus
+	ACLRoles    *ReplicationTypeStatus
+	ACLTokens   *ReplicationTypeStatus
+}
+
+// ReplicationTypeStatus is the replication status for a single type of data.
+type ReplicationTypeStatus struct {
+	// Running is whether the leader is currently replicating this data.
+	Running bool
+
+	// ReplicatedIndex is the authoritative region's index of the data as of
+	// the last successful replication.
+	ReplicatedIndex uint64
+
+	// SourceIndex is the current index of the data in the authoritative
+	// region. It is zero if it could not be determined.
+	SourceIndex uint64
+
+	// LastSuccess is the time of the last successful replication.
+	LastSuccess time.Time
+
+	// LastError and LastErrorMessage are the time and error of the last
+	// failed replication attempt.
+	LastError        time.Time
+	LastErrorMessage string
+}
+
+// ReplicationStatusResponse is used to return the replication status.
+type ReplicationStatusResponse struct {
+	Status *ReplicationStatus
+	QueryMeta
+}
+
+// UsageExportRequest is used to export the inventory and resource usage of the
+// cluster within a time window.
+type UsageExportRequest struct {
+	// Start and End bound the window in Unix nanoseconds. End defaults to
+	// the current time and Start to a day before End.
+	Start int64
+	End   int64
+
+	QueryOptions
+}
+
+// UsageReport is the inventory of the jobs, allocations and nodes of the
+// cluster along with the resources allocated to them within a time window.
+// Resource usage is measured as the resources reserved by allocations over the
+// time they were running within the window, such as CPUMHzSeconds. It only
+// includes the allocations that haven't been garbage collected yet.
+type UsageReport struct {
+	Start time.Time
+	End   time.Time
+
+	Totals      *UsageTotals
+	Jobs        []*JobUsage
+	Allocations []*AllocUsage
+	Nodes       []*NodeUsage
+}
+
+// UsageTotals are the totals of a usage report.
+type UsageTotals struct {
+	Jobs        int
+	Allocations int
+	Nodes       int
+
+	// CPUMHz and MemoryMB are the resources the nodes can allocate.
+	CPUMHz   int64
+	MemoryMB int64
+
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// JobUsage is the resources allocated to a job within the window of a usage
+// report.
+type JobUsage struct {
+	Namespace string
+	ID        string
+	ParentID  string
+	Type      string
+
+	// Allocations is the number of allocations of the job running within
+	// the window.
+	Allocations int
+
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// AllocUsage is the resources allocated to an allocation within the window of
+// a usage report.
+type AllocUsage struct {
+	ID           string
+	Namespace    string
+	JobID        string
+	TaskGroup    string
+	NodeID       string
+	ClientStatus string
+
+	// Start and End are the time the allocation was running within the
+	// window.
+	Start time.Time
+	End   time.Time
+
+	CPUMHz          int64
+	MemoryMB        int64
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// NodeUsage is the capacity of a node and the resources allocated on it
+// within the window of a usage report.
+type NodeUsage struct {
+	ID         string
+	Name       string
+	Datacenter string
+	NodeClass  string
+	NodePool   string
+	Status     string
+
+	// CPUMHz and MemoryMB are the resources the node can allocate.
+	CPUMHz   int64
+	MemoryMB int64
+
+	// Allocations is the number of allocations running on the node within
+	// the window.
+	Allocations int
+
+	CPUMHzSeconds   int64
+	MemoryMBSeconds int64
+}
+
+// UsageExportResponse is used to return a usage report.
+type UsageExportResponse struct {
+	Usage *UsageReport
+	QueryMeta
+}
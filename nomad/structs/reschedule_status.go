@@ -0,0 +1,90 @@
+package structs
+
+import "time"
+
+// AllocRescheduleStatus is the state of the reschedule tracker of the latest
+// allocation of a reschedule lineage.
+type AllocRescheduleStatus struct {
+	AllocID      string
+	TaskGroup    string
+	ClientStatus string
+
+	// Attempts is the number of reschedule attempts made within the interval
+	// of the reschedule policy
+	Attempts int
+
+	// MaxAttempts and Unlimited are copied from the reschedule policy of the
+	// task group
+	MaxAttempts int
+	Unlimited   bool
+
+	// Events are the previous reschedule attempts of the lineage
+	Events []*RescheduleEvent
+
+	// NextRescheduleTime is the time on or after which a failed allocation is
+	// rescheduled. It is zero if the allocation hasn't failed.
+	NextRescheduleTime time.Time
+
+	// Eligible is whether the failed allocation will be rescheduled
+	Eligible bool
+
+	// FollowupEvalID is the ID of the evaluation that will reschedule the
+	// allocation once its delay has elapsed
+	FollowupEvalID string
+}
+
+// RescheduleStatus returns the reschedule status of the allocation. Attempts
+// are counted relative to the failure time of the allocation, or to now if it
+// hasn't failed.
+func (a *Allocation) RescheduleStatus(now time.Time) *AllocRescheduleStatus {
+	status := &AllocRescheduleStatus{
+		AllocID:        a.ID,
+		TaskGroup:      a.TaskGroup,
+		ClientStatus:   a.ClientStatus,
+		FollowupEvalID: a.FollowupEvalID,
+	}
+
+	refTime := now
+	if a.ClientStatus == AllocClientStatusFailed {
+		refTime = a.LastEventTime()
+		status.NextRescheduleTime, status.Eligible = a.NextRescheduleTime()
+	}
+
+	policy := a.ReschedulePolicy()
+	if policy != nil {
+		status.MaxAttempts = policy.Attempts
+		status.Unlimited = policy.Unlimited
+	}
+
+	if a.RescheduleTracker != nil {
+		for _, event := range a.RescheduleTracker.Events {
+			status.Events = append(status.Events, event.Copy())
+			if policy != nil && refTime.UTC().UnixNano()-event.RescheduleTime < policy.Interval.Nanoseconds() {
+				status.Attempts++
+			}
+		}
+	}
+
+	return status
+}
+
+// JobRescheduleStatusResponse is used to return the reschedule status of the
+// latest allocation of each reschedule lineage of a job
+type JobRescheduleStatusResponse struct {
+	Allocs []*AllocRescheduleStatus
+	QueryMeta
+}
+
+// AllocRescheduleNowRequest is used to reschedule a failed allocation without
+// waiting for its reschedule delay to elapse
+type AllocRescheduleNowRequest struct {
+	AllocID string
+	WriteRequest
+}
+
+// AllocRescheduleNowResponse is used to respond to a reschedule now request
+type AllocRescheduleNowResponse struct {
+	EvalID          string
+	EvalCreateIndex uint64
+	WriteMeta
+}
@@ -0,0 +1,159 @@
+package structs
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/consul/api"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// ServiceProviderConsul registers the services of a task with Consul.
+	ServiceProviderConsul = "consul"
+
+	// ServiceProviderNomad registers the services of a task in Nomad's state
+	// store.
+	ServiceProviderNomad = "nomad"
+)
+
+// ServiceRegistration is the registration of a service of a task with the
+// Nomad service provider. Registrations are written by the client running
+// the task and removed once the task stops or its allocation is terminal.
+type ServiceRegistration struct {
+	// ID is the unique ID of the registration, derived from the allocation,
+	// task and service definition.
+	ID string
+
+	// ServiceName is the name of the service.
+	ServiceName string
+
+	Namespace  string
+	NodeID     string
+	Datacenter string
+	JobID      string
+	AllocID    string
+
+	// Tags are the tags of the service, which are its canary tags when the
+	// allocation is a canary.
+	Tags []string
+
+	// Address and Port are the address the service is advertised on.
+	Address string
+	Port    int
+
+	// Status is the aggregated status of the health checks of the service:
+	// passing, warning or critical. Services without checks are passing.
+	Status string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a copy of the registration.
+func (s *ServiceRegistration) Copy() *ServiceRegistration {
+	if s == nil {
+		return nil
+	}
+	ns := new(ServiceRegistration)
+	*ns = *s
+	ns.Tags = helper.CopySliceString(s.Tags)
+	return ns
+}
+
+// Equals returns whether the registrations are equal, ignoring their Raft
+// indexes.
+func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.ID == o.ID &&
+		s.ServiceName == o.ServiceName &&
+		s.Namespace == o.Namespace &&
+		s.NodeID == o.NodeID &&
+		s.Datacenter == o.Datacenter &&
+		s.JobID == o.JobID &&
+		s.AllocID == o.AllocID &&
+		reflect.DeepEqual(s.Tags, o.Tags) &&
+		s.Address == o.Address &&
+		s.Port == o.Port &&
+		s.Status == o.Status
+}
+
+// Validate checks that the registration is complete.
+func (s *ServiceRegistration) Validate() error {
+	var mErr multierror.Error
+	if s.ID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing registration ID"))
+	}
+	if s.ServiceName == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing service name"))
+	}
+	if s.Namespace == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing namespace"))
+	}
+	if s.NodeID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing node ID"))
+	}
+	if s.JobID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing job ID"))
+	}
+	if s.AllocID == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing allocation ID"))
+	}
+
+	switch s.Status {
+	case api.HealthPassing, api.HealthWarning, api.HealthCritical:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid status %q", s.Status))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ServiceRegistrationStub is the summary of a service of a namespace.
+type ServiceRegistrationStub struct {
+	ServiceName string
+
+	// Tags is the union of the tags of the registrations of the service.
+	Tags []string
+}
+
+// ServiceRegistrationUpsertRequest is used by clients to register services.
+type ServiceRegistrationUpsertRequest struct {
+	Services []*ServiceRegistration
+	WriteRequest
+}
+
+// ServiceRegistrationDeleteByIDRequest is used to remove a registration.
+type ServiceRegistrationDeleteByIDRequest struct {
+	ID string
+	WriteRequest
+}
+
+// ServiceRegistrationListRequest is used to list the services of a
+// namespace.
+type ServiceRegistrationListRequest struct {
+	QueryOptions
+}
+
+// ServiceRegistrationListResponse is used to return the services of a
+// namespace.
+type ServiceRegistrationListResponse struct {
+	Services []*ServiceRegistrationStub
+	QueryMeta
+}
+
+// ServiceRegistrationByNameRequest is used to read the registrations of a
+// service.
+type ServiceRegistrationByNameRequest struct {
+	ServiceName string
+	QueryOptions
+}
+
+// ServiceRegistrationByNameResponse is used to return the registrations of a
+// service.
+type ServiceRegistrationByNameResponse struct {
+	Services []*ServiceRegistration
+	QueryMeta
+}
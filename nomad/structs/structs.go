@@ -45,6 +45,23 @@ var (
 	// validPolicyName is used to validate a policy name
 	validPolicyName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 
+	// validMetricLabel is used to validate the meta keys a task attaches to
+	// its metrics as labels
+	validMetricLabel = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]{0,127}$")
+
+	// validJobTag is used to validate the tags of jobs, which are filtered as
+	// comma separated lists
+	validJobTag = regexp.MustCompile(`^[^\s,]{1,128}$`)
+
+	// validConcurrencyGroup is used to validate the concurrency group of jobs
+	validConcurrencyGroup = regexp.MustCompile("^[a-zA-Z0-9-_.]{1,128}$")
+
+	// reservedMetricLabels are the labels the client already attaches to
+	// task metrics
+	reservedMetricLabels = helper.SliceStringToSet([]string{
+		"job", "task_group", "alloc_id", "task", "parent_id", "dispatch_id", "periodic_id",
+	})
+
 	// b32 is a lowercase base32 encoding for use in URL friendly service hashes
 	b32 = base32.NewEncoding(strings.ToLower("abcdefghijklmnopqrstuvwxyz234567"))
 )
@@ -83,6 +100,23 @@ const (
 	NodeUpdateEligibilityRequestType
 	BatchNodeUpdateDrainRequestType
 	SchedulerConfigRequestType
+	VariableUpsertRequestType
+	VariableDeleteRequestType
+	RootKeyUpsertRequestType
+	ServiceRegistrationUpsertRequestType
+	ServiceRegistrationDeleteByIDRequestType
+	ACLAuthMethodUpsertRequestType
+	ACLAuthMethodDeleteRequestType
+	ACLRoleUpsertRequestType
+	ACLRoleDeleteRequestType
+	ACLBindingRuleUpsertRequestType
+	ACLBindingRuleDeleteRequestType
+	NodePoolUpsertRequestType
+	NodePoolDeleteRequestType
+	JobPinVersionRequestType
+	OneTimeTokenUpsertRequestType
+	OneTimeTokenExchangeRequestType
+	OneTimeTokenExpireRequestType
 )
 
 const (
@@ -122,6 +156,10 @@ const (
 	ACLClientToken     = "client"
 	ACLManagementToken = "management"
 
+	// ACLTokenMinExpirationTTL is the shortest expiration TTL a token can be
+	// created with
+	ACLTokenMinExpirationTTL = time.Minute
+
 	// DefaultNamespace is the default namespace.
 	DefaultNamespace            = "default"
 	DefaultNamespaceDescription = "Default shared namespace"
@@ -153,6 +191,7 @@ const (
 	Deployments Context = "deployment"
 	Evals       Context = "evals"
 	Jobs        Context = "jobs"
+	JobTags     Context = "job_tags"
 	Nodes       Context = "nodes"
 	Namespaces  Context = "namespaces"
 	Quotas      Context = "quotas"
@@ -225,6 +264,14 @@ type QueryOptions struct {
 	// If set, used as prefix for resource list searches
 	Prefix string
 
+	// PerPage is the maximum number of results returned by list queries that
+	// support pagination. Zero returns all the results.
+	PerPage int32
+
+	// NextToken is the token returned by the previous page of a paginated
+	// list query, from which the results resume.
+	NextToken string
+
 	// AuthToken is secret portion of the ACL token used for the request
 	AuthToken string
 
@@ -298,6 +345,10 @@ type QueryMeta struct {
 
 	// Used to indicate if there is a known leader node
 	KnownLeader bool
+
+	// NextToken is set by paginated list queries when more results are
+	// available and is passed as the NextToken of the next query.
+	NextToken string
 }
 
 // WriteMeta allows a write response to include potentially
@@ -347,6 +398,9 @@ type NodeUpdateStatusRequest struct {
 	NodeID    string
 	Status    string
 	NodeEvent *NodeEvent
+
+	// UpdatedAt is the time of the status update. It is set by the servers.
+	UpdatedAt int64
 	WriteRequest
 }
 
@@ -396,6 +450,14 @@ type NodeUpdateEligibilityRequest struct {
 	NodeID      string
 	Eligibility string
 
+	// TTL is how long a node marked as ineligible stays ineligible before
+	// being marked as eligible again. Zero keeps the node ineligible.
+	TTL time.Duration
+
+	// IneligibleUntil is the time at which the node is marked as eligible
+	// again. It is set by the server from the TTL.
+	IneligibleUntil time.Time
+
 	// NodeEvent is the event added to the node
 	NodeEvent *NodeEvent
 
@@ -458,6 +520,19 @@ type JobRegisterRequest struct {
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
 
+	// Signature is the base64 encoded detached signature of the job's
+	// signing payload. It is verified against the signers configured for the
+	// job's namespace.
+	Signature string
+
+	// Submission is the job file the job was parsed from. It is stored along
+	// with the job version the registration creates.
+	Submission *JobSubmission
+
+	// OverrideMinimumVersion allows registering a job spec matching a version
+	// older than the minimum version the job is pinned to.
+	OverrideMinimumVersion bool
+
 	WriteRequest
 }
 
@@ -471,6 +546,12 @@ type JobDeregisterRequest struct {
 	// garbage collector
 	Purge bool
 
+	// ShutdownDelay, if set, is the minimum delay between deregistering the
+	// services of the running allocations of the job and killing their
+	// tasks, giving load balancers time to drain connections. It overrides
+	// the shutdown_delay of the tasks if greater.
+	ShutdownDelay time.Duration
+
 	WriteRequest
 }
 
@@ -506,6 +587,79 @@ type EvalOptions struct {
 	ForceReschedule bool
 }
 
+const (
+	// JobBulkOperationStop stops the running jobs, or purges all the jobs
+	// when Purge is set.
+	JobBulkOperationStop = "stop"
+
+	// JobBulkOperationRun runs the stopped jobs again.
+	JobBulkOperationRun = "run"
+
+	// JobBulkOperationEvaluate force-evaluates the running jobs.
+	JobBulkOperationEvaluate = "evaluate"
+)
+
+// JobBulkRequest is used to apply an operation to all the jobs of a namespace
+// matching a filter in a single request.
+type JobBulkRequest struct {
+	// Operation is the operation applied to the matching jobs.
+	Operation string
+
+	// Prefix and Tags filter the jobs by ID prefix and by tags, the jobs
+	// must have all of the tags. At least one of them must be set.
+	Prefix string
+	Tags   []string
+
+	// Purge purges the jobs when stopping them.
+	Purge bool
+
+	// EvalOptions are the options of the evaluations of the jobs when
+	// force-evaluating them.
+	EvalOptions EvalOptions
+
+	// DryRun returns the jobs the operation would apply to without applying
+	// it.
+	DryRun bool
+
+	WriteRequest
+}
+
+// Validate returns an error if the bulk request is invalid.
+func (r *JobBulkRequest) Validate() error {
+	switch r.Operation {
+	case JobBulkOperationStop, JobBulkOperationRun, JobBulkOperationEvaluate:
+	default:
+		return fmt.Errorf("invalid bulk operation %q", r.Operation)
+	}
+	if r.Prefix == "" && len(r.Tags) == 0 {
+		return fmt.Errorf("bulk operations require a job ID prefix or tags")
+	}
+	if r.Purge && r.Operation != JobBulkOperationStop {
+		return fmt.Errorf("purge is only valid when stopping jobs")
+	}
+	return nil
+}
+
+// Matches returns whether the operation of the request applies to the job.
+// The job must already match the prefix of the request.
+func (r *JobBulkRequest) Matches(job *Job) bool {
+	for _, tag := range r.Tags {
+		if !job.HasTag(tag) {
+			return false
+		}
+	}
+
+	switch r.Operation {
+	case JobBulkOperationStop:
+		return r.Purge || !job.Stopped()
+	case JobBulkOperationRun:
+		return job.Stopped()
+	case JobBulkOperationEvaluate:
+		return !job.Stopped() && !job.IsPeriodic() && !job.IsParameterized()
+	}
+	return false
+}
+
 // JobSpecificRequest is used when we just need to specify a target job
 type JobSpecificRequest struct {
 	JobID     string
@@ -515,9 +669,49 @@ type JobSpecificRequest struct {
 
 // JobListRequest is used to parameterize a list request
 type JobListRequest struct {
+	// ParentID lists only the children of the given job
+	ParentID string
+
+	// Meta lists only the jobs with all of the given meta values
+	Meta map[string]string
+
+	// PayloadHash lists only the dispatched jobs whose payload has the given
+	// hash
+	PayloadHash string
+
+	// Status lists only the jobs with the given status
+	Status string
+
+	// Tags lists only the jobs with all of the given tags
+	Tags []string
+
 	QueryOptions
 }
 
+// Matches returns whether the job matches the filters of the request.
+func (r *JobListRequest) Matches(job *Job) bool {
+	if r.ParentID != "" && job.ParentID != r.ParentID {
+		return false
+	}
+	if r.PayloadHash != "" && job.DispatchPayloadHash != r.PayloadHash {
+		return false
+	}
+	if r.Status != "" && job.Status != r.Status {
+		return false
+	}
+	for k, v := range r.Meta {
+		if mv, ok := job.Meta[k]; !ok || mv != v {
+			return false
+		}
+	}
+	for _, tag := range r.Tags {
+		if !job.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
 // JobPlanRequest is used for the Job.Plan endpoint to trigger a dry-run
 // evaluation of the Job.
 type JobPlanRequest struct {
@@ -539,12 +733,24 @@ type JobDispatchRequest struct {
 	JobID   string
 	Payload []byte
 	Meta    map[string]string
+
+	// IdempotencyToken is used to dispatch the job at most once. If a child
+	// of the parameterized job was already dispatched with the same token,
+	// it is returned instead of dispatching a new one.
+	IdempotencyToken string
+
 	WriteRequest
 }
 
 // JobValidateRequest is used to validate a job
 type JobValidateRequest struct {
 	Job *Job
+
+	// Admission additionally runs the checks that depend on the state of the
+	// cluster, such as whether any node can satisfy the job's constraints and
+	// whether the job's Vault token grants its policies.
+	Admission bool
+
 	WriteRequest
 }
 
@@ -560,6 +766,10 @@ type JobRevertRequest struct {
 	// version before reverting.
 	EnforcePriorVersion *uint64
 
+	// OverrideMinimumVersion allows reverting to a version older than the
+	// minimum version the job is pinned to.
+	OverrideMinimumVersion bool
+
 	WriteRequest
 }
 
@@ -579,6 +789,23 @@ type JobStabilityResponse struct {
 	WriteMeta
 }
 
+// JobPinVersionRequest is used to pin a job to a minimum version.
+type JobPinVersionRequest struct {
+	// Job to pin
+	JobID string
+
+	// JobVersion is the minimum version of the job specs that can be
+	// registered. Setting it to zero unpins the job.
+	JobVersion uint64
+
+	WriteRequest
+}
+
+// JobPinVersionResponse is the response when pinning a job version.
+type JobPinVersionResponse struct {
+	WriteMeta
+}
+
 // NodeListRequest is used to parameterize a list request
 type NodeListRequest struct {
 	QueryOptions
@@ -624,6 +851,13 @@ type EvalListRequest struct {
 	QueryOptions
 }
 
+// EvalRequeueRequest is used to requeue a blocked evaluation without waiting
+// for node capacity changes
+type EvalRequeueRequest struct {
+	EvalID string
+	WriteRequest
+}
+
 // PlanRequest is used to submit an allocation plan to the leader
 type PlanRequest struct {
 	Plan *Plan
@@ -764,6 +998,13 @@ type VaultAccessor struct {
 	Accessor    string
 	CreationTTL int
 
+	// Namespace is the Vault namespace the token was created in
+	Namespace string
+
+	// Cluster is the name of the Vault cluster the token was created in.
+	// Empty for the default cluster.
+	Cluster string
+
 	// Raft Indexes
 	CreateIndex uint64
 }
@@ -786,6 +1027,15 @@ type GenericRequest struct {
 	QueryOptions
 }
 
+// GarbageCollectRequest is used to force a garbage collection. If
+// TargetNamespace is set only the objects belonging to that namespace are
+// collected, and if JobID is also set collection is restricted to that job.
+type GarbageCollectRequest struct {
+	TargetNamespace string
+	JobID           string
+	QueryOptions
+}
+
 // DeploymentListRequest is used to list the deployments
 type DeploymentListRequest struct {
 	QueryOptions
@@ -892,6 +1142,13 @@ type DeploymentFailRequest struct {
 	WriteRequest
 }
 
+// DeploymentRollbackRequest is used to roll the job of a deployment back to
+// the stable version that preceded it
+type DeploymentRollbackRequest struct {
+	DeploymentID string
+	WriteRequest
+}
+
 // SingleDeploymentResponse is used to respond with a single deployment
 type SingleDeploymentResponse struct {
 	Deployment *Deployment
@@ -939,6 +1196,26 @@ type JobBatchDeregisterResponse struct {
 	QueryMeta
 }
 
+// JobBulkResponse is used to respond to a bulk job operation
+type JobBulkResponse struct {
+	// Jobs has the result of the operation for each matching job, in job ID
+	// order.
+	Jobs []*JobBulkResult
+	WriteMeta
+}
+
+// JobBulkResult is the result of a bulk operation for a single job.
+type JobBulkResult struct {
+	ID string
+
+	// EvalID is the ID of the evaluation created by the operation, if any.
+	EvalID string
+
+	// Error is set if the operation failed for the job. A failure doesn't
+	// stop the operation for the other jobs.
+	Error string
+}
+
 // JobValidateResponse is the response from validate request
 type JobValidateResponse struct {
 	// DriverConfigValidated indicates whether the agent validated the driver
@@ -954,6 +1231,31 @@ type JobValidateResponse struct {
 	// Warnings contains any warnings about the given job. These may include
 	// deprecation warnings.
 	Warnings string
+
+	// AdmissionWarnings contains the problems found by the admission checks
+	// if they were requested.
+	AdmissionWarnings []*JobAdmissionWarning
+}
+
+const (
+	JobAdmissionWarningConstraint = "constraint"
+	JobAdmissionWarningNamespace  = "namespace"
+	JobAdmissionWarningVault      = "vault"
+)
+
+// JobAdmissionWarning is a problem found when checking whether a job would be
+// admitted and could be placed by the cluster.
+type JobAdmissionWarning struct {
+	// Type is the kind of check that failed.
+	Type string
+
+	// TaskGroup and Task are set if the warning applies to a specific task
+	// group or task.
+	TaskGroup string
+	Task      string
+
+	// Message describes the problem.
+	Message string
 }
 
 // NodeUpdateResponse is used to respond to a node update
@@ -1135,6 +1437,11 @@ type EvalDequeueResponse struct {
 	// scheduler.
 	WaitIndex uint64
 
+	// Ready is the number of evaluations of the requested schedulers left
+	// ready in the broker after the dequeue. It is used to autoscale the
+	// workers.
+	Ready int
+
 	QueryMeta
 }
 
@@ -1239,6 +1546,7 @@ const (
 	NodeEventSubsystemDriver    = "Driver"
 	NodeEventSubsystemHeartbeat = "Heartbeat"
 	NodeEventSubsystemCluster   = "Cluster"
+	NodeEventSubsystemScheduler = "Scheduler"
 )
 
 // NodeEvent is a single unit representing a node’s state change
@@ -1336,6 +1644,29 @@ const (
 	NodeSchedulingIneligible = "ineligible"
 )
 
+const (
+	// NodeMetaExcludeSystemJobs is the node meta key listing the comma
+	// separated IDs of the system jobs that must not run on the node, or "*"
+	// to exclude every system job.
+	NodeMetaExcludeSystemJobs = "nomad.exclude_system_jobs"
+
+	// NodeExcludeAllSystemJobs excludes every system job from the node when
+	// listed in its NodeMetaExcludeSystemJobs meta.
+	NodeExcludeAllSystemJobs = "*"
+)
+
+// ParseExcludedSystemJobs parses the value of the NodeMetaExcludeSystemJobs
+// node meta into the list of excluded job IDs.
+func ParseExcludedSystemJobs(value string) []string {
+	var jobs []string
+	for _, job := range strings.Split(value, ",") {
+		if job = strings.TrimSpace(job); job != "" {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
 // DrainSpec describes a Node's desired drain behavior.
 type DrainSpec struct {
 	// Deadline is the duration after StartTime when the remaining
@@ -1473,6 +1804,10 @@ type Node struct {
 	// together for the purpose of determining scheduling pressure.
 	NodeClass string
 
+	// NodePool is the node pool the node belongs to. Only the jobs of the
+	// pool are placed on the node.
+	NodePool string
+
 	// ComputedClass is a unique id that identifies nodes with a common set of
 	// attributes and capabilities.
 	ComputedClass string
@@ -1492,6 +1827,17 @@ type Node struct {
 	// placements.
 	SchedulingEligibility string
 
+	// IneligibleUntil is the time at which a node that was marked as
+	// ineligible for scheduling with a TTL or by a maintenance window is
+	// marked as eligible again. It is zero if the node isn't marked as
+	// eligible automatically.
+	IneligibleUntil time.Time
+
+	// MaintenanceWindows are the recurring windows during which the node is
+	// automatically marked as ineligible for scheduling. They are set by the
+	// client configuration.
+	MaintenanceWindows []*NodeMaintenanceWindow
+
 	// Status of this node
 	Status string
 
@@ -1520,11 +1866,26 @@ func (n *Node) Ready() bool {
 	return n.Status == NodeStatusReady && !n.Drain && n.SchedulingEligibility == NodeSchedulingEligible
 }
 
+// ExcludesSystemJob returns whether the node opted out of running the system
+// job with its NodeMetaExcludeSystemJobs meta.
+func (n *Node) ExcludesSystemJob(jobID string) bool {
+	for _, excluded := range ParseExcludedSystemJobs(n.Meta[NodeMetaExcludeSystemJobs]) {
+		if excluded == jobID || excluded == NodeExcludeAllSystemJobs {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *Node) Canonicalize() {
 	if n == nil {
 		return
 	}
 
+	if n.NodePool == "" {
+		n.NodePool = NodePoolDefault
+	}
+
 	// COMPAT Remove in 0.10
 	// In v0.8.0 we introduced scheduling eligibility, so we need to set it for
 	// upgrading nodes
@@ -1553,6 +1914,12 @@ func (n *Node) Copy() *Node {
 	nn.Events = copyNodeEvents(n.Events)
 	nn.DrainStrategy = nn.DrainStrategy.Copy()
 	nn.Drivers = copyNodeDrivers(n.Drivers)
+	if n.MaintenanceWindows != nil {
+		nn.MaintenanceWindows = make([]*NodeMaintenanceWindow, len(n.MaintenanceWindows))
+		for i, w := range n.MaintenanceWindows {
+			nn.MaintenanceWindows[i] = w.Copy()
+		}
+	}
 	return nn
 }
 
@@ -1664,6 +2031,7 @@ func (n *Node) Stub() *NodeListStub {
 		Datacenter:            n.Datacenter,
 		Name:                  n.Name,
 		NodeClass:             n.NodeClass,
+		NodePool:              n.NodePool,
 		Version:               n.Attributes["nomad.version"],
 		Drain:                 n.Drain,
 		SchedulingEligibility: n.SchedulingEligibility,
@@ -1683,6 +2051,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
@@ -1697,11 +2066,21 @@ type NodeListStub struct {
 // on a client
 type Resources struct {
 	CPU      int
+	Cores    int
 	MemoryMB int
 	DiskMB   int
 	IOPS     int // COMPAT(0.10): Only being used to issue warnings
 	Networks Networks
 	Devices  []*RequestedDevice
+
+	// HugePages2MB and HugePages1GB are the number of huge pages of each
+	// size reserved for the task.
+	HugePages2MB int
+	HugePages1GB int
+
+	// Realtime allows the task to use the real-time scheduling policies,
+	// within the real-time runtime budget configured on the client.
+	Realtime bool
 }
 
 const (
@@ -1742,6 +2121,19 @@ func (r *Resources) Validate() error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	if r.Cores < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Cores must be greater than or equal to 0; got %d", r.Cores))
+	} else if r.Cores > 0 && r.CPU > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Task can only ask for 'cpu' or 'cores' resource, not both."))
+	}
+
+	if r.HugePages2MB < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("HugePages2MB must be greater than or equal to 0; got %d", r.HugePages2MB))
+	}
+	if r.HugePages1GB < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("HugePages1GB must be greater than or equal to 0; got %d", r.HugePages1GB))
+	}
+
 	// Ensure the task isn't asking for disk resources
 	if r.DiskMB > 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Task can't ask for disk resources, they have to be specified at the task group level."))
@@ -1753,6 +2145,12 @@ func (r *Resources) Validate() error {
 		}
 	}
 
+	for _, n := range r.Networks {
+		if err := n.DNS.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("dns failed validation: %v", err))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -1761,6 +2159,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.CPU != 0 {
 		r.CPU = other.CPU
 	}
+	if other.Cores != 0 {
+		r.Cores = other.Cores
+	}
 	if other.MemoryMB != 0 {
 		r.MemoryMB = other.MemoryMB
 	}
@@ -1773,6 +2174,15 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.HugePages2MB != 0 {
+		r.HugePages2MB = other.HugePages2MB
+	}
+	if other.HugePages1GB != 0 {
+		r.HugePages1GB = other.HugePages1GB
+	}
+	if other.Realtime {
+		r.Realtime = true
+	}
 }
 
 func (r *Resources) Canonicalize() {
@@ -1796,7 +2206,7 @@ func (r *Resources) Canonicalize() {
 func (r *Resources) MeetsMinResources() error {
 	var mErr multierror.Error
 	minResources := MinResources()
-	if r.CPU < minResources.CPU {
+	if r.CPU < minResources.CPU && r.Cores == 0 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum CPU value is %d; got %d", minResources.CPU, r.CPU))
 	}
 	if r.MemoryMB < minResources.MemoryMB {
@@ -1901,6 +2311,10 @@ type NetworkResource struct {
 	MBits         int    // Throughput
 	ReservedPorts []Port // Host Reserved ports
 	DynamicPorts  []Port // Host Dynamically assigned ports
+
+	// DNS is the resolver configuration of the task. It is not a resource
+	// and is ignored when assigning networks.
+	DNS *DNSConfig
 }
 
 func (nr *NetworkResource) Equals(other *NetworkResource) bool {
@@ -1983,6 +2397,7 @@ func (n *NetworkResource) Copy() *NetworkResource {
 		newR.DynamicPorts = make([]Port, len(n.DynamicPorts))
 		copy(newR.DynamicPorts, n.DynamicPorts)
 	}
+	newR.DNS = n.DNS.Copy()
 	return newR
 }
 
@@ -2013,6 +2428,55 @@ func (n *NetworkResource) PortLabels() map[string]int {
 	return labelValues
 }
 
+// DNSConfig is the resolver configuration written to the /etc/resolv.conf
+// of a task.
+type DNSConfig struct {
+	// Servers are the IP addresses of the nameservers
+	Servers []string
+
+	// Searches are the domains searched for host names
+	Searches []string
+
+	// Options are the resolver options, such as "ndots:2"
+	Options []string
+}
+
+func (d *DNSConfig) Copy() *DNSConfig {
+	if d == nil {
+		return nil
+	}
+	return &DNSConfig{
+		Servers:  helper.CopySliceString(d.Servers),
+		Searches: helper.CopySliceString(d.Searches),
+		Options:  helper.CopySliceString(d.Options),
+	}
+}
+
+// Validate returns an error if a nameserver isn't an IP address.
+func (d *DNSConfig) Validate() error {
+	if d == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	for _, s := range d.Servers {
+		if net.ParseIP(s) == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("server %q is not an IP address", s))
+		}
+	}
+	for _, s := range d.Searches {
+		if s == "" || strings.ContainsAny(s, " \t") {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid search domain %q", s))
+		}
+	}
+	for _, o := range d.Options {
+		if o == "" || strings.ContainsAny(o, " \t") {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid option %q", o))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
 // Networks defined for a task on the Resources struct.
 type Networks []*NetworkResource
 
@@ -2154,6 +2618,9 @@ func (n *NodeResources) Copy() *NodeResources {
 	newN := new(NodeResources)
 	*newN = *n
 
+	// Copy the reservable cores
+	newN.Cpu.ReservableCpuCores = helper.CopySliceUint16(n.Cpu.ReservableCpuCores)
+
 	// Copy the networks
 	if n.Networks != nil {
 		networks := len(n.Networks)
@@ -2185,10 +2652,13 @@ func (n *NodeResources) Comparable() *ComparableResources {
 	c := &ComparableResources{
 		Flattened: AllocatedTaskResources{
 			Cpu: AllocatedCpuResources{
-				CpuShares: n.Cpu.CpuShares,
+				CpuShares:     n.Cpu.CpuShares,
+				ReservedCores: n.Cpu.ReservableCpuCores,
 			},
 			Memory: AllocatedMemoryResources{
-				MemoryMB: n.Memory.MemoryMB,
+				MemoryMB:     n.Memory.MemoryMB,
+				HugePages2MB: n.Memory.HugePages2MB,
+				HugePages1GB: n.Memory.HugePages1GB,
 			},
 			Networks: n.Networks,
 		},
@@ -2276,6 +2746,13 @@ type NodeCpuResources struct {
 	// CpuShares is the CPU shares available. This is calculated by number of
 	// cores multiplied by the core frequency.
 	CpuShares int64
+
+	// TotalCpuCores is the number of cores of the node.
+	TotalCpuCores uint16
+
+	// ReservableCpuCores are the IDs of the cores that can be reserved by
+	// tasks asking for whole cores.
+	ReservableCpuCores []uint16
 }
 
 func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
@@ -2286,6 +2763,22 @@ func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
 	if o.CpuShares != 0 {
 		n.CpuShares = o.CpuShares
 	}
+
+	if o.TotalCpuCores != 0 {
+		n.TotalCpuCores = o.TotalCpuCores
+	}
+
+	if len(o.ReservableCpuCores) != 0 {
+		n.ReservableCpuCores = o.ReservableCpuCores
+	}
+}
+
+// SharesPerCore returns the CPU shares of a single core of the node.
+func (n *NodeCpuResources) SharesPerCore() int64 {
+	if n.TotalCpuCores == 0 {
+		return 0
+	}
+	return n.CpuShares / int64(n.TotalCpuCores)
 }
 
 func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
@@ -2301,6 +2794,14 @@ func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
 		return false
 	}
 
+	if n.TotalCpuCores != o.TotalCpuCores {
+		return false
+	}
+
+	if !reflect.DeepEqual(n.ReservableCpuCores, o.ReservableCpuCores) {
+		return false
+	}
+
 	return true
 }
 
@@ -2308,6 +2809,11 @@ func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
 type NodeMemoryResources struct {
 	// MemoryMB is the total available memory on the node
 	MemoryMB int64
+
+	// HugePages2MB and HugePages1GB are the number of huge pages of each
+	// size allocated by the kernel of the node.
+	HugePages2MB int64
+	HugePages1GB int64
 }
 
 func (n *NodeMemoryResources) Merge(o *NodeMemoryResources) {
@@ -2318,6 +2824,14 @@ func (n *NodeMemoryResources) Merge(o *NodeMemoryResources) {
 	if o.MemoryMB != 0 {
 		n.MemoryMB = o.MemoryMB
 	}
+
+	if o.HugePages2MB != 0 {
+		n.HugePages2MB = o.HugePages2MB
+	}
+
+	if o.HugePages1GB != 0 {
+		n.HugePages1GB = o.HugePages1GB
+	}
 }
 
 func (n *NodeMemoryResources) Equals(o *NodeMemoryResources) bool {
@@ -2333,6 +2847,10 @@ func (n *NodeMemoryResources) Equals(o *NodeMemoryResources) bool {
 		return false
 	}
 
+	if n.HugePages2MB != o.HugePages2MB || n.HugePages1GB != o.HugePages1GB {
+		return false
+	}
+
 	return true
 }
 
@@ -2517,6 +3035,19 @@ type NodeDevice struct {
 	// Locality stores HW locality information for the node to optionally be
 	// used when making placement decisions.
 	Locality *NodeDeviceLocality
+
+	// Slices is the number of allocations that can share the device. Devices
+	// with zero or one slice are allocated exclusively.
+	Slices uint32
+}
+
+// MaxAllocs returns the number of allocations that can be allocated the
+// device at the same time.
+func (n *NodeDevice) MaxAllocs() int {
+	if n.Slices > 1 {
+		return int(n.Slices)
+	}
+	return 1
 }
 
 func (n *NodeDevice) Equals(o *NodeDevice) bool {
@@ -2536,6 +3067,8 @@ func (n *NodeDevice) Equals(o *NodeDevice) bool {
 		return false
 	} else if !n.Locality.Equals(o.Locality) {
 		return false
+	} else if n.Slices != o.Slices {
+		return false
 	}
 
 	return false
@@ -2730,6 +3263,9 @@ func (a *AllocatedTaskResources) Copy() *AllocatedTaskResources {
 	newA := new(AllocatedTaskResources)
 	*newA = *a
 
+	// Copy the reserved cores
+	newA.Cpu.ReservedCores = helper.CopySliceUint16(a.Cpu.ReservedCores)
+
 	// Copy the networks
 	if a.Networks != nil {
 		n := len(a.Networks)
@@ -2791,10 +3327,13 @@ func (a *AllocatedTaskResources) Comparable() *ComparableResources {
 	ret := &ComparableResources{
 		Flattened: AllocatedTaskResources{
 			Cpu: AllocatedCpuResources{
-				CpuShares: a.Cpu.CpuShares,
+				CpuShares:     a.Cpu.CpuShares,
+				ReservedCores: a.Cpu.ReservedCores,
 			},
 			Memory: AllocatedMemoryResources{
-				MemoryMB: a.Memory.MemoryMB,
+				MemoryMB:     a.Memory.MemoryMB,
+				HugePages2MB: a.Memory.HugePages2MB,
+				HugePages1GB: a.Memory.HugePages1GB,
 			},
 		},
 	}
@@ -2841,14 +3380,30 @@ func (a *AllocatedSharedResources) Subtract(delta *AllocatedSharedResources) {
 // AllocatedCpuResources captures the allocated CPU resources.
 type AllocatedCpuResources struct {
 	CpuShares int64
+
+	// ReservedCores are the IDs of the cores reserved for the task. No other
+	// task reserving cores is given them, but tasks asking for CPU shares are
+	// not kept off them.
+	ReservedCores []uint16
+
+	// Realtime is whether the task may use the real-time scheduling
+	// policies.
+	Realtime bool
 }
 
+// Add adds the CPU shares and reserved cores of the delta. Cores reserved by
+// both are kept twice so that overlapping reservations can be detected.
 func (a *AllocatedCpuResources) Add(delta *AllocatedCpuResources) {
 	if delta == nil {
 		return
 	}
 
 	a.CpuShares += delta.CpuShares
+	if len(delta.ReservedCores) != 0 {
+		cores := make([]uint16, 0, len(a.ReservedCores)+len(delta.ReservedCores))
+		cores = append(cores, a.ReservedCores...)
+		a.ReservedCores = append(cores, delta.ReservedCores...)
+	}
 }
 
 func (a *AllocatedCpuResources) Subtract(delta *AllocatedCpuResources) {
@@ -2857,11 +3412,29 @@ func (a *AllocatedCpuResources) Subtract(delta *AllocatedCpuResources) {
 	}
 
 	a.CpuShares -= delta.CpuShares
+	if len(delta.ReservedCores) != 0 {
+		cores := make([]uint16, 0, len(a.ReservedCores))
+	OUTER:
+		for _, c := range a.ReservedCores {
+			for _, d := range delta.ReservedCores {
+				if c == d {
+					continue OUTER
+				}
+			}
+			cores = append(cores, c)
+		}
+		a.ReservedCores = cores
+	}
 }
 
 // AllocatedMemoryResources captures the allocated memory resources.
 type AllocatedMemoryResources struct {
 	MemoryMB int64
+
+	// HugePages2MB and HugePages1GB are the number of huge pages of each
+	// size reserved for the task.
+	HugePages2MB int64
+	HugePages1GB int64
 }
 
 func (a *AllocatedMemoryResources) Add(delta *AllocatedMemoryResources) {
@@ -2870,6 +3443,8 @@ func (a *AllocatedMemoryResources) Add(delta *AllocatedMemoryResources) {
 	}
 
 	a.MemoryMB += delta.MemoryMB
+	a.HugePages2MB += delta.HugePages2MB
+	a.HugePages1GB += delta.HugePages1GB
 }
 
 func (a *AllocatedMemoryResources) Subtract(delta *AllocatedMemoryResources) {
@@ -2878,6 +3453,8 @@ func (a *AllocatedMemoryResources) Subtract(delta *AllocatedMemoryResources) {
 	}
 
 	a.MemoryMB -= delta.MemoryMB
+	a.HugePages2MB -= delta.HugePages2MB
+	a.HugePages1GB -= delta.HugePages1GB
 }
 
 type AllocatedDevices []*AllocatedDeviceResource
@@ -2986,15 +3563,44 @@ func (c *ComparableResources) Superset(other *ComparableResources) (bool, string
 	if c.Flattened.Cpu.CpuShares < other.Flattened.Cpu.CpuShares {
 		return false, "cpu"
 	}
+	if !coresSuperset(c.Flattened.Cpu.ReservedCores, other.Flattened.Cpu.ReservedCores) {
+		return false, "cores"
+	}
 	if c.Flattened.Memory.MemoryMB < other.Flattened.Memory.MemoryMB {
 		return false, "memory"
 	}
+	if c.Flattened.Memory.HugePages2MB < other.Flattened.Memory.HugePages2MB {
+		return false, "hugepages_2mb"
+	}
+	if c.Flattened.Memory.HugePages1GB < other.Flattened.Memory.HugePages1GB {
+		return false, "hugepages_1gb"
+	}
 	if c.Shared.DiskMB < other.Shared.DiskMB {
 		return false, "disk"
 	}
 	return true, ""
 }
 
+// coresSuperset returns whether the other cores are a subset of the available
+// cores, each reserved at most once.
+func coresSuperset(available, other []uint16) bool {
+	if len(other) == 0 {
+		return true
+	}
+
+	free := make(map[uint16]struct{}, len(available))
+	for _, c := range available {
+		free[c] = struct{}{}
+	}
+	for _, c := range other {
+		if _, ok := free[c]; !ok {
+			return false
+		}
+		delete(free, c)
+	}
+	return true
+}
+
 // allocated finds the matching net index using device name
 func (c *ComparableResources) NetIndex(n *NetworkResource) int {
 	return c.Flattened.Networks.NetIndex(n)
@@ -3082,6 +3688,10 @@ type Job struct {
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
+	// NodePool is the node pool the job is placed in. The job is only placed
+	// on the nodes of the pool, or on any node for the "all" pool.
+	NodePool string
+
 	// Constraints can be specified at a job level and apply to
 	// all the task groups and tasks.
 	Constraints []*Constraint
@@ -3115,10 +3725,35 @@ type Job struct {
 	// Payload is the payload supplied when the job was dispatched.
 	Payload []byte
 
+	// DispatchIdempotencyToken is the idempotency token of the request that
+	// dispatched the job, if any.
+	DispatchIdempotencyToken string
+
+	// DispatchPayloadHash is the hex encoded SHA-256 hash of the payload
+	// supplied when the job was dispatched.
+	DispatchPayloadHash string
+
 	// Meta is used to associate arbitrary metadata with this
 	// job. This is opaque to Nomad.
 	Meta map[string]string
 
+	// Tags are used to group jobs, such as by owner or tier. Unlike the meta
+	// values they are indexed, so the jobs can be listed and searched by tag.
+	Tags []string
+
+	// ConcurrencyGroup is the name of the group of jobs of the namespace of
+	// which at most one job runs allocations at a time. The other jobs of the
+	// group are queued until the running one's allocations are terminal.
+	ConcurrencyGroup string
+
+	// VersionRetention configures how many historic versions of the job are
+	// kept. If nil, JobTrackedVersions versions are kept.
+	VersionRetention *JobVersionRetention
+
+	// Prefetch is a hint for the clients likely to run the job to fetch the
+	// images and artifacts of its tasks ahead of its placements.
+	Prefetch *JobPrefetch
+
 	// VaultToken is the Vault token that proves the submitter of the job has
 	// access to the specified Vault policies. This field is only used to
 	// transfer the token and is not stored after Job submission.
@@ -3139,6 +3774,11 @@ type Job struct {
 	// on each job register.
 	Version uint64
 
+	// MinimumVersion is the version the job is pinned to. Registering a job
+	// spec matching an older version, such as one from a stale artifact, is
+	// rejected unless overridden. Zero means the job isn't pinned.
+	MinimumVersion uint64
+
 	// SubmitTime is the time at which the job was submitted as a UnixNano in
 	// UTC
 	SubmitTime int64
@@ -3177,6 +3817,11 @@ func (j *Job) Canonicalize() (warnings error) {
 		j.Namespace = DefaultNamespace
 	}
 
+	// Ensure the job is in a node pool.
+	if j.NodePool == "" {
+		j.NodePool = NodePoolDefault
+	}
+
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
 	}
@@ -3201,6 +3846,7 @@ func (j *Job) Copy() *Job {
 	nj := new(Job)
 	*nj = *j
 	nj.Datacenters = helper.CopySliceString(nj.Datacenters)
+	nj.Tags = helper.CopySliceString(nj.Tags)
 	nj.Constraints = CopySliceConstraints(nj.Constraints)
 	nj.Affinities = CopySliceAffinities(nj.Affinities)
 
@@ -3215,6 +3861,8 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = helper.CopyMapStringString(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.VersionRetention = nj.VersionRetention.Copy()
+	nj.Prefetch = nj.Prefetch.Copy()
 	return nj
 }
 
@@ -3249,6 +3897,28 @@ func (j *Job) Validate() error {
 	if len(j.Datacenters) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job datacenters"))
 	}
+	if j.NodePool != "" && !ValidNodePoolName(j.NodePool) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid node pool %q", j.NodePool))
+	}
+	tags := make(map[string]struct{}, len(j.Tags))
+	for _, tag := range j.Tags {
+		if !validJobTag.MatchString(tag) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid tag %q: tags must be at most 128 characters without whitespace or commas", tag))
+			continue
+		}
+		if _, ok := tags[tag]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Duplicate tag %q", tag))
+		}
+		tags[tag] = struct{}{}
+	}
+	if j.ConcurrencyGroup != "" {
+		if !validConcurrencyGroup.MatchString(j.ConcurrencyGroup) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid concurrency group %q: must be at most 128 alphanumeric, '-', '_' or '.' characters", j.ConcurrencyGroup))
+		}
+		if j.Type == JobTypeSystem {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a concurrency group"))
+		}
+	}
 	if len(j.TaskGroups) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job task groups"))
 	}
@@ -3333,6 +4003,12 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.VersionRetention != nil {
+		if err := j.VersionRetention.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -3421,6 +4097,7 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 		ParentID:          j.ParentID,
 		Name:              j.Name,
 		Datacenters:       j.Datacenters,
+		Tags:              j.Tags,
 		Type:              j.Type,
 		Priority:          j.Priority,
 		Periodic:          j.IsPeriodic(),
@@ -3436,6 +4113,16 @@ func (j *Job) Stub(summary *JobSummary) *JobListStub {
 	}
 }
 
+// HasTag returns whether the job has the tag.
+func (j *Job) HasTag(tag string) bool {
+	for _, t := range j.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // IsPeriodic returns whether a job is periodic.
 func (j *Job) IsPeriodic() bool {
 	return j.Periodic != nil
@@ -3495,6 +4182,11 @@ func (j *Job) RequiredSignals() map[string]map[string][]string {
 				taskSignals[task.KillSignal] = struct{}{}
 			}
 
+			// Add the signals of the kill escalation chain
+			for _, step := range task.KillEscalation {
+				taskSignals[step.Signal] = struct{}{}
+			}
+
 			// Check if any template change mode uses signals
 			for _, t := range task.Templates {
 				if t.ChangeMode != TemplateChangeModeSignal {
@@ -3504,6 +4196,13 @@ func (j *Job) RequiredSignals() map[string]map[string][]string {
 				taskSignals[t.ChangeSignal] = struct{}{}
 			}
 
+			// Check if any secret change mode uses signals
+			for _, s := range task.Secrets {
+				if s.ChangeMode == TemplateChangeModeSignal {
+					taskSignals[s.ChangeSignal] = struct{}{}
+				}
+			}
+
 			// Flatten and sort the signals
 			l := len(taskSignals)
 			if l == 0 {
@@ -3544,6 +4243,7 @@ func (j *Job) SpecChanged(new *Job) bool {
 	c.StatusDescription = j.StatusDescription
 	c.Stable = j.Stable
 	c.Version = j.Version
+	c.MinimumVersion = j.MinimumVersion
 	c.CreateIndex = j.CreateIndex
 	c.ModifyIndex = j.ModifyIndex
 	c.JobModifyIndex = j.JobModifyIndex
@@ -3564,6 +4264,7 @@ type JobListStub struct {
 	ParentID          string
 	Name              string
 	Datacenters       []string
+	Tags              []string
 	Type              string
 	Priority          int
 	Periodic          bool
@@ -3603,6 +4304,7 @@ func (js *JobSummary) Copy() *JobSummary {
 	*newJobSummary = *js
 	newTGSummary := make(map[string]TaskGroupSummary, len(js.Summary))
 	for k, v := range js.Summary {
+		v.LastExitCodes = helper.CopyMapStringInt(v.LastExitCodes)
 		newTGSummary[k] = v
 	}
 	newJobSummary.Summary = newTGSummary
@@ -3637,6 +4339,12 @@ type TaskGroupSummary struct {
 	Running  int
 	Starting int
 	Lost     int
+	Unknown  int
+
+	// LastExitCodes are the exit codes of the tasks of the last allocation
+	// of a batch job that completed or failed, keyed by task name. Tasks
+	// that never exited are omitted.
+	LastExitCodes map[string]int
 }
 
 const (
@@ -3706,6 +4414,14 @@ type UpdateStrategy struct {
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
+
+	// ManualStep pauses the deployment after each batch of MaxParallel
+	// allocations is healthy, until it is resumed.
+	ManualStep bool
+
+	// ProgressWebhook is notified as the deployments of the task group start,
+	// are promoted, fail or succeed.
+	ProgressWebhook *DeploymentWebhook
 }
 
 func (u *UpdateStrategy) Copy() *UpdateStrategy {
@@ -3715,6 +4431,7 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 
 	copy := new(UpdateStrategy)
 	*copy = *u
+	copy.ProgressWebhook = u.ProgressWebhook.Copy()
 	return copy
 }
 
@@ -3754,6 +4471,9 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Stagger <= 0 {
 		multierror.Append(&mErr, fmt.Errorf("Stagger must be greater than zero: %v", u.Stagger))
 	}
+	if err := u.ProgressWebhook.Validate(); err != nil {
+		multierror.Append(&mErr, fmt.Errorf("Progress webhook: %v", err))
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -3764,6 +4484,49 @@ func (u *UpdateStrategy) Rolling() bool {
 	return u.Stagger > 0 && u.MaxParallel > 0
 }
 
+const (
+	// DeploymentWebhookEventStarted is sent when a deployment is created.
+	DeploymentWebhookEventStarted = "started"
+
+	// DeploymentWebhookEventPromoted is sent when the canaries of a
+	// deployment are promoted.
+	DeploymentWebhookEventPromoted = "promoted"
+
+	// DeploymentWebhookEventFailed is sent when a deployment fails.
+	DeploymentWebhookEventFailed = "failed"
+
+	// DeploymentWebhookEventSuccessful is sent when a deployment succeeds.
+	DeploymentWebhookEventSuccessful = "successful"
+)
+
+// DeploymentWebhook is an external endpoint that is notified of the progress
+// of deployments.
+type DeploymentWebhook struct {
+	// URL is the address the progress events are POSTed to.
+	URL string
+}
+
+func (w *DeploymentWebhook) Copy() *DeploymentWebhook {
+	if w == nil {
+		return nil
+	}
+
+	copy := new(DeploymentWebhook)
+	*copy = *w
+	return copy
+}
+
+func (w *DeploymentWebhook) Validate() error {
+	if w == nil {
+		return nil
+	}
+
+	if u, err := url.Parse(w.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid url %q", w.URL)
+	}
+	return nil
+}
+
 const (
 	// PeriodicSpecCron is used for a cron spec.
 	PeriodicSpecCron = "cron"
@@ -3828,8 +4591,19 @@ func (p *PeriodicConfig) Validate() error {
 	switch p.SpecType {
 	case PeriodicSpecCron:
 		// Validate the cron spec
-		if _, err := cronexpr.Parse(p.Spec); err != nil {
+		e, err := cronexpr.Parse(p.Spec)
+		if err != nil {
 			multierror.Append(&mErr, fmt.Errorf("Invalid cron spec %q: %v", p.Spec, err))
+			break
+		}
+
+		// Reject specs that parse but can't match any future time, such as
+		// February 30th or a year in the past
+		next, err := CronParseNext(e, time.Now().In(p.GetLocation()), p.Spec)
+		if err != nil {
+			multierror.Append(&mErr, err)
+		} else if next.IsZero() {
+			multierror.Append(&mErr, fmt.Errorf("Cron spec %q will never launch the job", p.Spec))
 		}
 	case PeriodicSpecTest:
 		// No-op
@@ -3949,6 +4723,10 @@ type ParameterizedJobConfig struct {
 
 	// MetaOptional is metadata keys that may be specified by the dispatcher
 	MetaOptional []string
+
+	// MetaConfigs validate the values of the metadata keys specified by the
+	// dispatcher
+	MetaConfigs []*DispatchMetaConfig
 }
 
 func (d *ParameterizedJobConfig) Validate() error {
@@ -3965,6 +4743,24 @@ func (d *ParameterizedJobConfig) Validate() error {
 		multierror.Append(&mErr, fmt.Errorf("Required and optional meta keys should be disjoint. Following keys exist in both: %v", offending))
 	}
 
+	// Check that the validated meta keys are declared
+	declared := helper.SliceStringToSet(d.MetaRequired)
+	for _, k := range d.MetaOptional {
+		declared[k] = struct{}{}
+	}
+	configured := make(map[string]struct{}, len(d.MetaConfigs))
+	for _, c := range d.MetaConfigs {
+		if _, ok := configured[c.Key]; ok {
+			multierror.Append(&mErr, fmt.Errorf("Meta key %q configured more than once", c.Key))
+			continue
+		}
+		configured[c.Key] = struct{}{}
+
+		if err := c.Validate(declared); err != nil {
+			multierror.Append(&mErr, multierror.Prefix(err, fmt.Sprintf("Meta key %q:", c.Key)))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -3972,6 +4768,9 @@ func (d *ParameterizedJobConfig) Canonicalize() {
 	if d.Payload == "" {
 		d.Payload = DispatchPayloadOptional
 	}
+	for _, c := range d.MetaConfigs {
+		c.Canonicalize()
+	}
 }
 
 func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
@@ -3982,9 +4781,156 @@ func (d *ParameterizedJobConfig) Copy() *ParameterizedJobConfig {
 	*nd = *d
 	nd.MetaOptional = helper.CopySliceString(nd.MetaOptional)
 	nd.MetaRequired = helper.CopySliceString(nd.MetaRequired)
+	if d.MetaConfigs != nil {
+		nd.MetaConfigs = make([]*DispatchMetaConfig, len(d.MetaConfigs))
+		for i, c := range d.MetaConfigs {
+			nd.MetaConfigs[i] = c.Copy()
+		}
+	}
 	return nd
 }
 
+const (
+	DispatchMetaTypeString = "string"
+	DispatchMetaTypeInt    = "int"
+	DispatchMetaTypeFloat  = "float"
+	DispatchMetaTypeBool   = "bool"
+)
+
+// DispatchMetaConfig validates the value of a metadata key specified by the
+// dispatcher of a parameterized job. Dispatch requests with invalid values are
+// rejected.
+type DispatchMetaConfig struct {
+	// Key is the metadata key
+	Key string
+
+	// Type is the type the value must parse as
+	Type string
+
+	// Enum is the set of allowed values, if not empty
+	Enum []string
+
+	// Regex is a regular expression the value must match, if set
+	Regex string
+
+	// RequiredWith is the metadata keys that must be specified along with
+	// the key
+	RequiredWith []string
+}
+
+func (c *DispatchMetaConfig) Copy() *DispatchMetaConfig {
+	if c == nil {
+		return nil
+	}
+	nc := new(DispatchMetaConfig)
+	*nc = *c
+	nc.Enum = helper.CopySliceString(c.Enum)
+	nc.RequiredWith = helper.CopySliceString(c.RequiredWith)
+	return nc
+}
+
+func (c *DispatchMetaConfig) Canonicalize() {
+	if c.Type == "" {
+		c.Type = DispatchMetaTypeString
+	}
+}
+
+// Validate validates the config given the set of declared metadata keys.
+func (c *DispatchMetaConfig) Validate(declared map[string]struct{}) error {
+	var mErr multierror.Error
+	if c.Key == "" {
+		return fmt.Errorf("Missing meta key")
+	}
+	if _, ok := declared[c.Key]; !ok {
+		multierror.Append(&mErr, fmt.Errorf("Key must be a required or optional meta key"))
+	}
+
+	switch c.Type {
+	case DispatchMetaTypeString, DispatchMetaTypeInt, DispatchMetaTypeFloat, DispatchMetaTypeBool:
+	default:
+		multierror.Append(&mErr, fmt.Errorf("Unknown type %q", c.Type))
+		return mErr.ErrorOrNil()
+	}
+
+	if c.Regex != "" {
+		if _, err := regexp.Compile(c.Regex); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("Invalid regex %q: %v", c.Regex, err))
+			return mErr.ErrorOrNil()
+		}
+	}
+
+	// Check that the allowed values are valid themselves
+	for _, v := range c.Enum {
+		if err := c.validateValue(v); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("Invalid enum value: %v", err))
+		}
+	}
+
+	for _, k := range c.RequiredWith {
+		if k == c.Key {
+			multierror.Append(&mErr, fmt.Errorf("Key can not be required with itself"))
+		} else if _, ok := declared[k]; !ok {
+			multierror.Append(&mErr, fmt.Errorf("Required with key %q must be a required or optional meta key", k))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ValidateDispatch validates the value of the key given the metadata of a
+// dispatch request.
+func (c *DispatchMetaConfig) ValidateDispatch(meta map[string]string) error {
+	v, ok := meta[c.Key]
+	if !ok {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if err := c.validateValue(v); err != nil {
+		multierror.Append(&mErr, err)
+	} else if len(c.Enum) != 0 {
+		if _, ok := helper.SliceStringToSet(c.Enum)[v]; !ok {
+			multierror.Append(&mErr, fmt.Errorf("Meta key %q value %q is not one of %v", c.Key, v, c.Enum))
+		}
+	}
+
+	for _, k := range c.RequiredWith {
+		if _, ok := meta[k]; !ok {
+			multierror.Append(&mErr, fmt.Errorf("Meta key %q must be provided with meta key %q", k, c.Key))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// validateValue validates the value against the type and regex of the key.
+func (c *DispatchMetaConfig) validateValue(v string) error {
+	var err error
+	switch c.Type {
+	case DispatchMetaTypeInt:
+		_, err = strconv.ParseInt(v, 10, 64)
+	case DispatchMetaTypeFloat:
+		_, err = strconv.ParseFloat(v, 64)
+	case DispatchMetaTypeBool:
+		_, err = strconv.ParseBool(v)
+	}
+	if err != nil {
+		return fmt.Errorf("Meta key %q value %q is not of type %s", c.Key, v, c.Type)
+	}
+
+	if c.Regex != "" {
+		re, err := regexp.Compile(c.Regex)
+		if err != nil {
+			return fmt.Errorf("Meta key %q has an invalid regex: %v", c.Key, err)
+		}
+		if !re.MatchString(v) {
+			return fmt.Errorf("Meta key %q value %q does not match %q", c.Key, v, c.Regex)
+		}
+	}
+
+	return nil
+}
+
 // DispatchedID returns an ID appropriate for a job dispatched against a
 // particular parameterized job
 func DispatchedID(templateID string, t time.Time) string {
@@ -4402,6 +5348,16 @@ func (m *MigrateStrategy) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+const (
+	// ReconnectStrategyKeepOriginal stops the replacement of an allocation
+	// when the node of the allocation reconnects.
+	ReconnectStrategyKeepOriginal = "keep_original"
+
+	// ReconnectStrategyKeepReplacement stops an allocation that was replaced
+	// when its node reconnects.
+	ReconnectStrategyKeepReplacement = "keep_replacement"
+)
+
 // TaskGroup is an atomic unit of placement. Each task group belongs to
 // a job and may contain any number of tasks. A task group support running
 // in many replicas using the same configuration..
@@ -4432,6 +5388,14 @@ type TaskGroup struct {
 	// EphemeralDisk is the disk resources that the task group requests
 	EphemeralDisk *EphemeralDisk
 
+	// SharedMemory, if set, makes the tasks of the group share an IPC
+	// namespace and a /dev/shm of the given size.
+	SharedMemory *SharedMemory
+
+	// Consul configures the Consul cluster the services of the tasks of the
+	// group are registered in and their templates read from.
+	Consul *Consul
+
 	// Meta is used to associate arbitrary metadata with this
 	// task group. This is opaque to Nomad.
 	Meta map[string]string
@@ -4440,6 +5404,17 @@ type TaskGroup struct {
 	// retry failed allocations.
 	ReschedulePolicy *ReschedulePolicy
 
+	// MaxClientDisconnect, if set, is how long the allocations of the group
+	// on a node that missed its heartbeats are kept with an unknown status
+	// before being replaced. If the node reconnects within that window, the
+	// allocations keep running and aren't replaced.
+	MaxClientDisconnect *time.Duration
+
+	// ReconnectStrategy is which of an allocation and its replacement is kept
+	// when the node of the allocation reconnects after the allocation was
+	// replaced. It defaults to keeping the replacement.
+	ReconnectStrategy string
+
 	// Affinities can be specified at the task group level to express
 	// scheduling preferences.
 	Affinities []*Affinity
@@ -4460,6 +5435,9 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.RestartPolicy = ntg.RestartPolicy.Copy()
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
+	if tg.MaxClientDisconnect != nil {
+		ntg.MaxClientDisconnect = helper.TimeToPtr(*tg.MaxClientDisconnect)
+	}
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
 
 	if tg.Tasks != nil {
@@ -4475,9 +5453,31 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	if tg.EphemeralDisk != nil {
 		ntg.EphemeralDisk = tg.EphemeralDisk.Copy()
 	}
+	ntg.SharedMemory = ntg.SharedMemory.Copy()
+	ntg.Consul = ntg.Consul.Copy()
 	return ntg
 }
 
+// UsesConsul returns whether the tasks of the group register services or
+// render templates, which use the Consul cluster of the group.
+func (tg *TaskGroup) UsesConsul() bool {
+	for _, task := range tg.Tasks {
+		if len(task.Services) != 0 || len(task.Templates) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsulCluster returns the name of the Consul cluster used by the tasks of
+// the group.
+func (tg *TaskGroup) ConsulCluster() string {
+	if tg.Consul == nil || tg.Consul.Cluster == "" {
+		return ConsulDefaultCluster
+	}
+	return tg.Consul.Cluster
+}
+
 // Canonicalize is used to canonicalize fields in the TaskGroup.
 func (tg *TaskGroup) Canonicalize(job *Job) {
 	// Ensure that an empty and nil map are treated the same to avoid scheduling
@@ -4566,7 +5566,26 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		if tg.ReschedulePolicy != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs should not have a reschedule policy"))
 		}
+		if tg.MaxClientDisconnect != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not set max_client_disconnect"))
+		}
+		if tg.ReconnectStrategy != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not set reconnect_strategy"))
+		}
 	} else {
+		if tg.MaxClientDisconnect != nil && *tg.MaxClientDisconnect < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("max_client_disconnect cannot be negative"))
+		}
+		switch tg.ReconnectStrategy {
+		case "":
+		case ReconnectStrategyKeepOriginal, ReconnectStrategyKeepReplacement:
+			if tg.MaxClientDisconnect == nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("reconnect_strategy requires max_client_disconnect to be set"))
+			}
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid reconnect_strategy %q, must be one of %q or %q",
+				tg.ReconnectStrategy, ReconnectStrategyKeepOriginal, ReconnectStrategyKeepReplacement))
+		}
 		if tg.ReschedulePolicy != nil {
 			if err := tg.ReschedulePolicy.Validate(); err != nil {
 				mErr.Errors = append(mErr.Errors, err)
@@ -4584,6 +5603,12 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Task Group %v should have an ephemeral disk object", tg.Name))
 	}
 
+	if tg.SharedMemory != nil {
+		if err := tg.SharedMemory.Validate(tg.Tasks); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	// Validate the update strategy
 	if u := tg.Update; u != nil {
 		switch j.Type {
@@ -4703,6 +5728,8 @@ type CheckRestart struct {
 	Limit          int           // Restart task after this many unhealthy intervals
 	Grace          time.Duration // Grace time to give tasks after starting to get healthy
 	IgnoreWarnings bool          // If true treat checks in `warning` as passing
+	Delay          time.Duration // Extra grace after a restart, doubled on each consecutive restart
+	MaxDelay       time.Duration // Upper bound of the extra grace
 }
 
 func (c *CheckRestart) Copy() *CheckRestart {
@@ -4729,6 +5756,14 @@ func (c *CheckRestart) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("grace period must be greater than or equal to 0 but found %d", c.Grace))
 	}
 
+	if c.Delay < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("delay must be greater than or equal to 0 but found %v", c.Delay))
+	}
+
+	if c.MaxDelay != 0 && c.MaxDelay < c.Delay {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max delay (%v) must be greater than or equal to delay (%v)", c.MaxDelay, c.Delay))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -4738,6 +5773,21 @@ const (
 	ServiceCheckScript = "script"
 	ServiceCheckGRPC   = "grpc"
 
+	// ServiceCheckPipe checks connect to the Windows named pipe in their
+	// path from the client, and are registered in Consul as TTL checks like
+	// script checks.
+	ServiceCheckPipe = "pipe"
+
+	// windowsPipePrefix is the prefix of the path of Windows named pipes.
+	windowsPipePrefix = `\\.\pipe\`
+
+	// ServiceCheckRoleReadiness checks control whether the service receives
+	// traffic and never restart the task, while ServiceCheckRoleLiveness
+	// checks restart the task with their check_restart. Checks without a role
+	// do both.
+	ServiceCheckRoleReadiness = "readiness"
+	ServiceCheckRoleLiveness  = "liveness"
+
 	// minCheckInterval is the minimum check interval permitted.  Consul
 	// currently has its MinInterval set to 1s.  Mirror that here for
 	// consistency.
@@ -4768,6 +5818,7 @@ type ServiceCheck struct {
 	CheckRestart  *CheckRestart       // If and when a task should be restarted based on checks
 	GRPCService   string              // Service for GRPC checks
 	GRPCUseTLS    bool                // Whether or not to use TLS for GRPC checks
+	Role          string              // Readiness or liveness, or empty for both
 }
 
 func (sc *ServiceCheck) Copy() *ServiceCheck {
@@ -4827,8 +5878,13 @@ func (sc *ServiceCheck) validate() error {
 			return fmt.Errorf("script type must have a valid script path")
 		}
 
+	case ServiceCheckPipe:
+		if !strings.HasPrefix(strings.ToLower(sc.Path), windowsPipePrefix) || len(sc.Path) == len(windowsPipePrefix) {
+			return fmt.Errorf(`pipe type must have a named pipe path starting with %q`, windowsPipePrefix)
+		}
+
 	default:
-		return fmt.Errorf(`invalid type (%+q), must be one of "http", "tcp", or "script" type`, sc.Type)
+		return fmt.Errorf(`invalid type (%+q), must be one of "http", "tcp", "grpc", "script" or "pipe" type`, sc.Type)
 	}
 
 	// Validate interval and timeout
@@ -4865,6 +5921,21 @@ func (sc *ServiceCheck) validate() error {
 		return fmt.Errorf("invalid address_mode %q", sc.AddressMode)
 	}
 
+	// Validate Role
+	switch sc.Role {
+	case "":
+	case ServiceCheckRoleReadiness:
+		if sc.CheckRestart != nil {
+			return fmt.Errorf("%s checks can't restart the task, check_restart must not be set", sc.Role)
+		}
+	case ServiceCheckRoleLiveness:
+		if !sc.TriggersRestarts() {
+			return fmt.Errorf("%s checks require a check_restart with a limit", sc.Role)
+		}
+	default:
+		return fmt.Errorf("invalid role %q, must be %q, %q or empty", sc.Role, ServiceCheckRoleReadiness, ServiceCheckRoleLiveness)
+	}
+
 	return sc.CheckRestart.Validate()
 }
 
@@ -4958,6 +6029,26 @@ type Service struct {
 	Tags       []string        // List of tags for the service
 	CanaryTags []string        // List of tags for the service when it is a canary
 	Checks     []*ServiceCheck // List of checks associated with the service
+
+	// Provider is the service discovery provider the service is registered
+	// with. An empty value registers the service with Consul. Names other
+	// than consul and nomad are external providers configured on the
+	// clients, such as load balancers.
+	Provider string
+}
+
+// validServiceProvider matches the names of external service providers.
+var validServiceProvider = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+// UsesConsul returns whether the service is registered with Consul.
+func (s *Service) UsesConsul() bool {
+	return s.Provider == "" || s.Provider == ServiceProviderConsul
+}
+
+// UsesExternalProvider returns whether the service is registered with an
+// external service provider configured on the client.
+func (s *Service) UsesExternalProvider() bool {
+	return !s.UsesConsul() && s.Provider != ServiceProviderNomad
 }
 
 func (s *Service) Copy() *Service {
@@ -5028,7 +6119,26 @@ func (s *Service) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("service address_mode must be %q, %q, or %q; not %q", AddressModeAuto, AddressModeHost, AddressModeDriver, s.AddressMode))
 	}
 
+	if s.Provider != "" && !validServiceProvider.MatchString(s.Provider) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("service provider must be %q, %q or the name of an external provider; not %q", ServiceProviderConsul, ServiceProviderNomad, s.Provider))
+	}
+
 	for _, c := range s.Checks {
+		// Providers other than Consul only run the checks the client can
+		// execute itself
+		if !s.UsesConsul() {
+			switch c.Type {
+			case ServiceCheckHTTP, ServiceCheckTCP, ServiceCheckGRPC:
+			default:
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: the %q service provider only supports %q, %q and %q checks", c.Name, s.Provider, ServiceCheckHTTP, ServiceCheckTCP, ServiceCheckGRPC))
+				continue
+			}
+			if c.TriggersRestarts() {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: check_restart is not supported by the %q service provider", c.Name, s.Provider))
+				continue
+			}
+		}
+
 		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: check requires a port but neither check nor service %+q have a port", c.Name, s.Name))
 			continue
@@ -5094,6 +6204,23 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// EventLogProviders are the providers whose Windows Event Log entries
+	// are captured into the eventlog logs of the task, from the
+	// EventLogChannel channel. No entries are captured if empty.
+	EventLogProviders []string
+	EventLogChannel   string
+}
+
+// Copy returns a copy of the log config.
+func (l *LogConfig) Copy() *LogConfig {
+	if l == nil {
+		return nil
+	}
+	nl := new(LogConfig)
+	*nl = *l
+	nl.EventLogProviders = helper.CopySliceString(l.EventLogProviders)
+	return nl
 }
 
 // DefaultLogConfig returns the default LogConfig values.
@@ -5114,6 +6241,15 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	for _, p := range l.EventLogProviders {
+		// The providers are quoted in the event log query
+		if p == "" || strings.ContainsAny(p, `'"`) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid event log provider %q", p))
+		}
+	}
+	if l.EventLogChannel != "" && len(l.EventLogProviders) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("event log channel requires event log providers"))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -5142,6 +6278,10 @@ type Task struct {
 	// have access to.
 	Vault *Vault
 
+	// Identity exposes the workload identity of the task to it, to call the
+	// Nomad API with.
+	Identity *WorkloadIdentity
+
 	// Templates are the set of templates to be rendered for the task.
 	Templates []*Template
 
@@ -5187,6 +6327,24 @@ type Task struct {
 	// KillSignal is the kill signal to use for the task. This is an optional
 	// specification and defaults to SIGINT
 	KillSignal string
+
+	// KillEscalation is the chain of signals sent to kill the task, each
+	// followed by the time the task has to exit before the next is sent. The
+	// task is force killed once the timeout of the last step elapses. It
+	// replaces KillSignal and KillTimeout when set.
+	KillEscalation []*KillStep
+
+	// Actions are the predefined commands that can be run inside the running
+	// task.
+	Actions []*Action
+
+	// Secrets are the secrets written to files in the secrets directory of
+	// the task.
+	Secrets []*Secret
+
+	// MetricLabels are the meta keys whose values are attached as labels to
+	// the metrics the client emits for the task.
+	MetricLabels []string
 }
 
 func (t *Task) Copy() *Task {
@@ -5209,8 +6367,11 @@ func (t *Task) Copy() *Task {
 	nt.Affinities = CopySliceAffinities(nt.Affinities)
 
 	nt.Vault = nt.Vault.Copy()
+	nt.Identity = nt.Identity.Copy()
 	nt.Resources = nt.Resources.Copy()
+	nt.LogConfig = nt.LogConfig.Copy()
 	nt.Meta = helper.CopyMapStringString(nt.Meta)
+	nt.MetricLabels = helper.CopySliceString(nt.MetricLabels)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
 
 	if t.Artifacts != nil {
@@ -5235,9 +6396,53 @@ func (t *Task) Copy() *Task {
 		nt.Templates = templates
 	}
 
+	if t.Actions != nil {
+		actions := make([]*Action, len(t.Actions))
+		for i, a := range nt.Actions {
+			actions[i] = a.Copy()
+		}
+		nt.Actions = actions
+	}
+
+	if t.Secrets != nil {
+		secrets := make([]*Secret, len(t.Secrets))
+		for i, s := range nt.Secrets {
+			secrets[i] = s.Copy()
+		}
+		nt.Secrets = secrets
+	}
+
+	if t.KillEscalation != nil {
+		steps := make([]*KillStep, len(t.KillEscalation))
+		for i, s := range nt.KillEscalation {
+			steps[i] = s.Copy()
+		}
+		nt.KillEscalation = steps
+	}
+
 	return nt
 }
 
+// KillSteps returns the steps taken to kill the task: its kill escalation
+// chain or, if it has none, its kill signal followed by its kill timeout.
+func (t *Task) KillSteps() []*KillStep {
+	if len(t.KillEscalation) != 0 {
+		return t.KillEscalation
+	}
+	return []*KillStep{{Signal: t.KillSignal, Timeout: t.KillTimeout}}
+}
+
+// LookupAction returns the task's action with the given name or nil if it
+// has none.
+func (t *Task) LookupAction(name string) *Action {
+	for _, a := range t.Actions {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
 // Canonicalize canonicalizes fields in the task.
 func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	// Ensure that an empty and nil map are treated the same to avoid scheduling
@@ -5251,6 +6456,9 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	if len(t.Env) == 0 {
 		t.Env = nil
 	}
+	if len(t.MetricLabels) == 0 {
+		t.MetricLabels = nil
+	}
 
 	for _, service := range t.Services {
 		service.Canonicalize(job.Name, tg.Name, t.Name)
@@ -5275,6 +6483,14 @@ func (t *Task) Canonicalize(job *Job, tg *TaskGroup) {
 	for _, template := range t.Templates {
 		template.Canonicalize()
 	}
+
+	for _, secret := range t.Secrets {
+		secret.Canonicalize()
+	}
+
+	for _, step := range t.KillEscalation {
+		step.Canonicalize()
+	}
 }
 
 func (t *Task) GoString() string {
@@ -5376,19 +6592,85 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 			mErr.Errors = append(mErr.Errors, outer)
 		}
 
-		if other, ok := destinations[tmpl.DestPath]; ok {
-			outer := fmt.Errorf("Template %d has same destination as %d", idx+1, other)
+		if other, ok := destinations[tmpl.DestPath]; ok {
+			outer := fmt.Errorf("Template %d has same destination as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			destinations[tmpl.DestPath] = idx + 1
+		}
+	}
+
+	// Validate the dispatch payload block if there
+	if t.DispatchPayload != nil {
+		if err := t.DispatchPayload.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Dispatch Payload validation failed: %v", err))
+		}
+	}
+
+	secrets := make(map[string]int, len(t.Secrets))
+	for idx, secret := range t.Secrets {
+		if err := secret.Validate(); err != nil {
+			outer := fmt.Errorf("Secret %d validation failed: %v", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if other, ok := secrets[secret.Name]; ok {
+			outer := fmt.Errorf("Secret %d has same name as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			secrets[secret.Name] = idx + 1
+		}
+
+		for tidx, tmpl := range t.Templates {
+			if filepath.Clean(tmpl.DestPath) == secret.DestPath() {
+				outer := fmt.Errorf("Secret %d has same destination as template %d", idx+1, tidx+1)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+
+		if secret.Provider == SecretProviderVault && t.Vault == nil {
+			outer := fmt.Errorf("Secret %d is read from Vault but the task has no vault stanza", idx+1)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
+	actions := make(map[string]int, len(t.Actions))
+	for idx, action := range t.Actions {
+		if err := action.Validate(); err != nil {
+			outer := fmt.Errorf("Action %d validation failed: %v", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if other, ok := actions[action.Name]; ok {
+			outer := fmt.Errorf("Action %d has same name as %d", idx+1, other)
+			mErr.Errors = append(mErr.Errors, outer)
+		} else {
+			actions[action.Name] = idx + 1
+		}
+	}
+
+	if len(t.KillEscalation) != 0 && t.KillSignal != "" {
+		mErr.Errors = append(mErr.Errors, errors.New("KillSignal can't be set with a kill escalation chain"))
+	}
+	for idx, step := range t.KillEscalation {
+		if err := step.Validate(); err != nil {
+			outer := fmt.Errorf("Kill step %d validation failed: %v", idx+1, err)
 			mErr.Errors = append(mErr.Errors, outer)
-		} else {
-			destinations[tmpl.DestPath] = idx + 1
 		}
 	}
 
-	// Validate the dispatch payload block if there
-	if t.DispatchPayload != nil {
-		if err := t.DispatchPayload.Validate(); err != nil {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("Dispatch Payload validation failed: %v", err))
+	labels := make(map[string]struct{}, len(t.MetricLabels))
+	for _, label := range t.MetricLabels {
+		if !validMetricLabel.MatchString(label) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Metric label %q must contain only letters, digits and underscores and not start with a digit", label))
+		} else if _, ok := reservedMetricLabels[label]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Metric label %q is reserved", label))
+		}
+
+		if _, ok := labels[label]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Metric label %q is listed more than once", label))
 		}
+		labels[label] = struct{}{}
 	}
 
 	return mErr.ErrorOrNil()
@@ -5546,12 +6828,16 @@ const (
 	// TemplateChangeModeRestart marks that the task should be restarted if the
 	// template is re-rendered
 	TemplateChangeModeRestart = "restart"
+
+	// TemplateChangeModeScript marks that a script should be executed inside
+	// the task if the template is re-rendered
+	TemplateChangeModeScript = "script"
 )
 
 var (
 	// TemplateChangeModeInvalidError is the error for when an invalid change
 	// mode is given
-	TemplateChangeModeInvalidError = errors.New("Invalid change mode. Must be one of the following: noop, signal, restart")
+	TemplateChangeModeInvalidError = errors.New("Invalid change mode. Must be one of the following: noop, signal, restart, script")
 )
 
 // Template represents a template configuration to be rendered for a given task
@@ -5573,6 +6859,10 @@ type Template struct {
 	// requires it.
 	ChangeSignal string
 
+	// ChangeScript is the script that should be executed inside the task if
+	// the change mode requires it.
+	ChangeScript *ChangeScript
+
 	// Splay is used to avoid coordinated restarts of processes by applying a
 	// random wait between 0 and the given splay value before signalling the
 	// application of a change
@@ -5619,6 +6909,7 @@ func (t *Template) Copy() *Template {
 	}
 	copy := new(Template)
 	*copy = *t
+	copy.ChangeScript = t.ChangeScript.Copy()
 	return copy
 }
 
@@ -5659,6 +6950,15 @@ func (t *Template) Validate() error {
 		if t.Envvars {
 			multierror.Append(&mErr, fmt.Errorf("cannot use signals with env var templates"))
 		}
+	case TemplateChangeModeScript:
+		if t.ChangeScript == nil {
+			multierror.Append(&mErr, fmt.Errorf("Must specify change script when change mode is script"))
+		} else if err := t.ChangeScript.Validate(); err != nil {
+			multierror.Append(&mErr, err)
+		}
+		if t.Envvars {
+			multierror.Append(&mErr, fmt.Errorf("cannot use scripts with env var templates"))
+		}
 	default:
 		multierror.Append(&mErr, TemplateChangeModeInvalidError)
 	}
@@ -5682,6 +6982,46 @@ func (t *Template) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// ChangeScript is a command executed inside a task when the template using
+// it is re-rendered.
+type ChangeScript struct {
+	// Command is the full path to the command to execute
+	Command string
+
+	// Args are the arguments passed to the command
+	Args []string
+
+	// Timeout is the maximum duration the command may run
+	Timeout time.Duration
+
+	// FailOnError marks whether the task should be killed if the command
+	// fails or cannot be executed
+	FailOnError bool
+}
+
+func (c *ChangeScript) Copy() *ChangeScript {
+	if c == nil {
+		return nil
+	}
+	nc := new(ChangeScript)
+	*nc = *c
+	nc.Args = helper.CopySliceString(c.Args)
+	return nc
+}
+
+func (c *ChangeScript) Validate() error {
+	var mErr multierror.Error
+
+	if c.Command == "" {
+		multierror.Append(&mErr, fmt.Errorf("Must specify a change script command"))
+	}
+	if c.Timeout <= 0 {
+		multierror.Append(&mErr, fmt.Errorf("Change script timeout must be greater than zero: %v", c.Timeout))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // Set of possible states for a task.
 const (
 	TaskStatePending = "pending" // The task is waiting to be run.
@@ -5748,6 +7088,17 @@ func (ts *TaskState) Copy() *TaskState {
 	return copy
 }
 
+// ExitCode returns the exit code of the last time the task terminated, or
+// false if it never did.
+func (ts *TaskState) ExitCode() (int, bool) {
+	for i := len(ts.Events) - 1; i >= 0; i-- {
+		if e := ts.Events[i]; e.Type == TaskTerminated {
+			return e.ExitCode, true
+		}
+	}
+	return 0, false
+}
+
 // Successful returns whether a task finished successfully. This doesn't really
 // have meaning on a non-batch allocation because a service and system
 // allocation should not finish.
@@ -5814,6 +7165,10 @@ const (
 	// TaskSetup indicates the task runner is setting up the task environment
 	TaskSetup = "Task Setup"
 
+	// TaskUnhealthyCheck indicates that the task is restarted because one of
+	// its checks stayed unhealthy.
+	TaskUnhealthyCheck = "Unhealthy Check"
+
 	// TaskDiskExceeded indicates that one of the tasks in a taskgroup has
 	// exceeded the requested disk resources.
 	TaskDiskExceeded = "Disk Resources Exceeded"
@@ -6012,6 +7367,12 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Task exceeded restart policy"
 		}
+	case TaskDiskExceeded:
+		if event.DiskLimit != 0 {
+			desc = fmt.Sprintf("Disk usage reached the ephemeral disk size of %d MB", event.DiskLimit)
+		} else {
+			desc = "Disk usage reached the ephemeral disk size"
+		}
 	case TaskSiblingFailed:
 		if event.FailedSibling != "" {
 			desc = fmt.Sprintf("Task's sibling %q failed", event.FailedSibling)
@@ -6041,6 +7402,11 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		desc = event.DriverMessage
 	case TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case TaskUnhealthyCheck:
+		desc = fmt.Sprintf("Restarting due to unhealthy check %q", event.Details["check_name"])
+		if output := event.Details["check_output"]; output != "" {
+			desc = fmt.Sprintf("%s: %s", desc, output)
+		}
 	default:
 		desc = event.Message
 	}
@@ -6212,11 +7578,222 @@ func (e *TaskEvent) SetDriverMessage(m string) *TaskEvent {
 	return e
 }
 
+// maxCheckOutput is the number of bytes of check output kept in task events.
+const maxCheckOutput = 512
+
+// SetCheckOutput sets the name and the output, truncated if too long, of the
+// check that failed.
+func (e *TaskEvent) SetCheckOutput(name, output string) *TaskEvent {
+	output = strings.TrimSpace(output)
+	if len(output) > maxCheckOutput {
+		output = output[:maxCheckOutput] + "..."
+	}
+	e.Details["check_name"] = name
+	e.Details["check_output"] = output
+	return e
+}
+
 func (e *TaskEvent) SetOOMKilled(oom bool) *TaskEvent {
 	e.Details["oom_killed"] = strconv.FormatBool(oom)
 	return e
 }
 
+// Action is a predefined command that can be run inside a running task, such
+// as flushing a cache or triggering a compaction.
+type Action struct {
+	// Name is the name of the action, unique within the task.
+	Name string
+
+	// Command is the command to run and Args are its arguments.
+	Command string
+	Args    []string
+
+	// Timeout is the duration the command may run for before it is killed.
+	Timeout time.Duration
+}
+
+func (a *Action) Copy() *Action {
+	if a == nil {
+		return nil
+	}
+	na := new(Action)
+	*na = *a
+	na.Args = helper.CopySliceString(a.Args)
+	return na
+}
+
+func (a *Action) Validate() error {
+	var mErr multierror.Error
+	if a.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing action name"))
+	} else if strings.Contains(a.Name, "/") {
+		mErr.Errors = append(mErr.Errors, errors.New("Action name cannot include slashes"))
+	}
+	if a.Command == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing action command"))
+	}
+	if a.Timeout <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Timeout must be a positive value"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// KillStep is a step of the kill escalation chain of a task: a signal sent
+// to the task and the time it has to exit before the next step.
+type KillStep struct {
+	Signal  string
+	Timeout time.Duration
+}
+
+func (s *KillStep) Copy() *KillStep {
+	if s == nil {
+		return nil
+	}
+	ns := new(KillStep)
+	*ns = *s
+	return ns
+}
+
+func (s *KillStep) Canonicalize() {
+	s.Signal = strings.ToUpper(s.Signal)
+}
+
+func (s *KillStep) Validate() error {
+	var mErr multierror.Error
+	if s.Signal == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing kill signal"))
+	}
+	if s.Timeout <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Timeout must be a positive value"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// SecretProviderVault reads the secret from the Vault KV secrets engine,
+	// using the Vault token of the task.
+	SecretProviderVault = "vault"
+
+	// SecretProviderNomad reads the secret from a Nomad variable below the
+	// path of the job.
+	SecretProviderNomad = "nomad"
+)
+
+// Secret is a single item of a Vault secret or Nomad variable written to a
+// file in the secrets directory of the task. Clients render secrets as
+// templates, so they follow the same change modes as templates without
+// requiring a template to be written for the simple case.
+type Secret struct {
+	// Name is the name of the secret, unique within the task. It is also the
+	// name of the file in the secrets directory.
+	Name string
+
+	// Provider is where the secret is read from, either vault or nomad.
+	Provider string
+
+	// Path is the path of the Vault secret or Nomad variable and Key is the
+	// item of it written to the file.
+	Path string
+	Key  string
+
+	// Perms are the permissions of the file and Uid and Gid its owner.
+	Perms string
+	Uid   int
+	Gid   int
+
+	// ChangeMode is what to do when the secret changes, restart, signal or
+	// noop. ChangeSignal is the signal sent in signal mode.
+	ChangeMode   string
+	ChangeSignal string
+}
+
+func (s *Secret) Copy() *Secret {
+	if s == nil {
+		return nil
+	}
+	ns := new(Secret)
+	*ns = *s
+	return ns
+}
+
+func (s *Secret) Canonicalize() {
+	if s.ChangeSignal != "" {
+		s.ChangeSignal = strings.ToUpper(s.ChangeSignal)
+	}
+}
+
+func (s *Secret) Validate() error {
+	var mErr multierror.Error
+	if s.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing secret name"))
+	} else if strings.ContainsAny(s.Name, `/\`) || s.Name == "." || s.Name == ".." {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Secret name %q must be a file name", s.Name))
+	}
+
+	switch s.Provider {
+	case SecretProviderVault, SecretProviderNomad:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid provider %q, must be %q or %q",
+			s.Provider, SecretProviderVault, SecretProviderNomad))
+	}
+	if s.Path == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing secret path"))
+	}
+	if s.Key == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing secret key"))
+	}
+
+	if s.Perms != "" {
+		if _, err := strconv.ParseUint(s.Perms, 8, 12); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Failed to parse %q as octal: %v", s.Perms, err))
+		}
+	}
+	if s.Uid < 0 || s.Gid < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Uid and gid must not be negative"))
+	}
+
+	switch s.ChangeMode {
+	case TemplateChangeModeNoop, TemplateChangeModeRestart:
+	case TemplateChangeModeSignal:
+		if s.ChangeSignal == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("Must specify signal value when change mode is signal"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, errors.New("Invalid change mode. Must be one of the following: noop, signal, restart"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// DestPath returns the path of the secret's file relative to the task
+// directory.
+func (s *Secret) DestPath() string {
+	return filepath.Join("secrets", s.Name)
+}
+
+// Template returns the template rendering the secret to its file. The
+// template of a Vault secret reads KV version 1 and 2 secrets alike.
+func (s *Secret) Template() *Template {
+	var tmpl string
+	switch s.Provider {
+	case SecretProviderVault:
+		tmpl = fmt.Sprintf(`{{ with secret %q }}{{ if .Data.data }}{{ index .Data.data %q }}{{ else }}{{ index .Data %q }}{{ end }}{{ end }}`,
+			s.Path, s.Key, s.Key)
+	case SecretProviderNomad:
+		tmpl = fmt.Sprintf(`{{ index (nomadVar %q) %q }}`, s.Path, s.Key)
+	}
+
+	return &Template{
+		DestPath:     s.DestPath(),
+		EmbeddedTmpl: tmpl,
+		ChangeMode:   s.ChangeMode,
+		ChangeSignal: s.ChangeSignal,
+		Splay:        5 * time.Second,
+		Perms:        s.Perms,
+		LeftDelim:    "{{",
+		RightDelim:   "}}",
+	}
+}
+
 // TaskArtifact is an artifact to download before running the task.
 type TaskArtifact struct {
 	// GetterSource is the source to download an artifact using go-getter
@@ -6689,6 +8266,66 @@ func (d *EphemeralDisk) Copy() *EphemeralDisk {
 	return ld
 }
 
+// sharedMemoryDrivers are the drivers able to share an IPC namespace and
+// /dev/shm between the tasks of a group.
+var sharedMemoryDrivers = map[string]struct{}{
+	"docker": {},
+	"exec":   {},
+}
+
+// SharedMemory is the IPC namespace and /dev/shm shared by the tasks of a
+// task group.
+type SharedMemory struct {
+	// SizeMB is the size of the shared /dev/shm
+	SizeMB int
+}
+
+// Validate validates the shared memory of the group of the tasks.
+func (s *SharedMemory) Validate(tasks []*Task) error {
+	var mErr multierror.Error
+	if s.SizeMB < 1 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum shared memory size is 1 MB; got %d", s.SizeMB))
+	}
+	for _, task := range tasks {
+		if _, ok := sharedMemoryDrivers[task.Driver]; !ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %q: driver %q does not support shared memory", task.Name, task.Driver))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy copies the SharedMemory struct and returns a new one
+func (s *SharedMemory) Copy() *SharedMemory {
+	if s == nil {
+		return nil
+	}
+	ns := new(SharedMemory)
+	*ns = *s
+	return ns
+}
+
+// ConsulDefaultCluster is the name of the Consul cluster configured by the
+// agents' consul block without a name.
+const ConsulDefaultCluster = "default"
+
+// Consul configures the Consul cluster used by a task group.
+type Consul struct {
+	// Cluster is the name of the Consul cluster. It defaults to the Consul
+	// cluster of the job's namespace defaults when the job is registered,
+	// and to the default cluster otherwise.
+	Cluster string
+}
+
+// Copy copies the Consul struct and returns a new one
+func (c *Consul) Copy() *Consul {
+	if c == nil {
+		return nil
+	}
+	nc := new(Consul)
+	*nc = *c
+	return nc
+}
+
 var (
 	// VaultUnrecoverableError matches unrecoverable errors returned by a Vault
 	// server
@@ -6696,6 +8333,10 @@ var (
 )
 
 const (
+	// VaultDefaultCluster is the name of the Vault cluster configured by the
+	// agents' vault block without a name.
+	VaultDefaultCluster = "default"
+
 	// VaultChangeModeNoop takes no action when a new token is retrieved.
 	VaultChangeModeNoop = "noop"
 
@@ -6711,6 +8352,15 @@ type Vault struct {
 	// Policies is the set of policies that the task needs access to
 	Policies []string
 
+	// Namespace is the Vault namespace the task's token is created in. If
+	// empty, the token is created in the namespace of the servers' token.
+	Namespace string
+
+	// Cluster is the name of the Vault cluster the task's token is created
+	// in. It defaults to the Vault cluster of the job's namespace defaults
+	// when the job is registered, and to the default cluster otherwise.
+	Cluster string
+
 	// Env marks whether the Vault Token should be exposed as an environment
 	// variable
 	Env bool
@@ -6731,6 +8381,15 @@ func DefaultVaultBlock() *Vault {
 	}
 }
 
+// GetCluster returns the name of the Vault cluster of the block, treating
+// an empty cluster as the default cluster.
+func (v *Vault) GetCluster() string {
+	if v == nil || v.Cluster == "" {
+		return VaultDefaultCluster
+	}
+	return v.Cluster
+}
+
 // Copy returns a copy of this Vault block.
 func (v *Vault) Copy() *Vault {
 	if v == nil {
@@ -6791,6 +8450,7 @@ const (
 	DeploymentStatusDescriptionRunning               = "Deployment is running"
 	DeploymentStatusDescriptionRunningNeedsPromotion = "Deployment is running but requires promotion"
 	DeploymentStatusDescriptionPaused                = "Deployment is paused"
+	DeploymentStatusDescriptionPausedManualStep      = "Deployment is paused until the next batch is resumed"
 	DeploymentStatusDescriptionSuccessful            = "Deployment completed successfully"
 	DeploymentStatusDescriptionStoppedJob            = "Cancelled because job is stopped"
 	DeploymentStatusDescriptionNewerJob              = "Cancelled due to newer version of job"
@@ -6983,6 +8643,53 @@ type DeploymentState struct {
 
 	// UnhealthyAllocs are allocations that have been marked as unhealthy.
 	UnhealthyAllocs int
+
+	// StepAllocs is the number of allocations that may be placed before the
+	// deployment pauses for the next batch to be resumed. It is only set for
+	// task groups updated in manual steps.
+	StepAllocs int
+
+	// Progress is the time series of the allocation counts and progress
+	// deadline of the task group, oldest first. At most
+	// DeploymentProgressLimit entries are kept.
+	Progress []*DeploymentProgress
+}
+
+// DeploymentProgressLimit is the maximum number of progress entries kept per
+// task group of a deployment.
+const DeploymentProgressLimit = 100
+
+// DeploymentProgress is the state of a task group of a deployment after one of
+// its allocations was placed or had its health set.
+type DeploymentProgress struct {
+	// Time is when the allocation was placed or had its health set.
+	Time time.Time
+
+	// Index is the Raft index of the change.
+	Index uint64
+
+	PlacedAllocs    int
+	HealthyAllocs   int
+	UnhealthyAllocs int
+
+	// RequireProgressBy is the progress deadline after the change.
+	RequireProgressBy time.Time
+}
+
+// AddProgress records the current allocation counts and progress deadline of
+// the task group, dropping the oldest entries past DeploymentProgressLimit.
+func (d *DeploymentState) AddProgress(t time.Time, index uint64) {
+	d.Progress = append(d.Progress, &DeploymentProgress{
+		Time:              t,
+		Index:             index,
+		PlacedAllocs:      d.PlacedAllocs,
+		HealthyAllocs:     d.HealthyAllocs,
+		UnhealthyAllocs:   d.UnhealthyAllocs,
+		RequireProgressBy: d.RequireProgressBy,
+	})
+	if n := len(d.Progress); n > DeploymentProgressLimit {
+		d.Progress = d.Progress[n-DeploymentProgressLimit:]
+	}
 }
 
 func (d *DeploymentState) GoString() string {
@@ -6994,6 +8701,9 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tHealthy: %d", d.HealthyAllocs)
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
+	if d.StepAllocs != 0 {
+		base += fmt.Sprintf("\n\tStep: %d", d.StepAllocs)
+	}
 	return base
 }
 
@@ -7001,6 +8711,13 @@ func (d *DeploymentState) Copy() *DeploymentState {
 	c := &DeploymentState{}
 	*c = *d
 	c.PlacedCanaries = helper.CopySliceString(d.PlacedCanaries)
+	if d.Progress != nil {
+		c.Progress = make([]*DeploymentProgress, len(d.Progress))
+		for i, p := range d.Progress {
+			pc := *p
+			c.Progress[i] = &pc
+		}
+	}
 	return c
 }
 
@@ -7014,6 +8731,10 @@ type DeploymentStatusUpdate struct {
 
 	// StatusDescription is the new status description of the deployment.
 	StatusDescription string
+
+	// StepAllocs is the new number of allocations that may be placed by the
+	// task groups updated in manual steps, keyed by task group.
+	StepAllocs map[string]int
 }
 
 // RescheduleTracker encapsulates previous reschedule events
@@ -7085,6 +8806,11 @@ type DesiredTransition struct {
 	// This field is only used when operators want to force a placement even if
 	// a failed allocation is not eligible to be rescheduled
 	ForceReschedule *bool
+
+	// ShutdownDelay is the minimum delay between deregistering the services
+	// of the allocation and killing its tasks when it is stopped, set when
+	// its job is stopped with a shutdown delay.
+	ShutdownDelay *time.Duration
 }
 
 // Merge merges the two desired transitions, preferring the values from the
@@ -7101,6 +8827,10 @@ func (d *DesiredTransition) Merge(o *DesiredTransition) {
 	if o.ForceReschedule != nil {
 		d.ForceReschedule = o.ForceReschedule
 	}
+
+	if o.ShutdownDelay != nil {
+		d.ShutdownDelay = o.ShutdownDelay
+	}
 }
 
 // ShouldMigrate returns whether the transition object dictates a migration.
@@ -7135,6 +8865,7 @@ const (
 	AllocClientStatusComplete = "complete"
 	AllocClientStatusFailed   = "failed"
 	AllocClientStatusLost     = "lost"
+	AllocClientStatusUnknown  = "unknown"
 )
 
 // Allocation is used to allocate the placement of a task group to a node.
@@ -7520,6 +9251,27 @@ func (a *Allocation) RanSuccessfully() bool {
 	return allSuccess
 }
 
+// RanToCompletion returns whether the client finished running the allocation,
+// successfully or not, as opposed to it being lost.
+func (a *Allocation) RanToCompletion() bool {
+	return a.ClientStatus == AllocClientStatusComplete || a.ClientStatus == AllocClientStatusFailed
+}
+
+// ExitCodes returns the exit codes of the tasks of the allocation that
+// terminated, keyed by task name.
+func (a *Allocation) ExitCodes() map[string]int {
+	var codes map[string]int
+	for name, state := range a.TaskStates {
+		if code, ok := state.ExitCode(); ok {
+			if codes == nil {
+				codes = make(map[string]int, len(a.TaskStates))
+			}
+			codes[name] = code
+		}
+	}
+	return codes
+}
+
 // ShouldMigrate returns if the allocation needs data migration
 func (a *Allocation) ShouldMigrate() bool {
 	if a.PreviousAllocation == "" {
@@ -7694,6 +9446,15 @@ type AllocMetric struct {
 	// ClassFiltered is the number of nodes filtered by class
 	ClassFiltered map[string]int
 
+	// FeasibilityCacheHits is the number of nodes whose feasibility was
+	// determined by the cached eligibility of their computed node class.
+	FeasibilityCacheHits int
+
+	// FeasibilityCacheMisses is the number of nodes whose feasibility checks
+	// had to be run because the eligibility of their computed node class
+	// wasn't known yet or couldn't be cached.
+	FeasibilityCacheMisses int
+
 	// ConstraintFiltered is the number of failures caused by constraint
 	ConstraintFiltered map[string]int
 
@@ -7707,9 +9468,17 @@ type AllocMetric struct {
 	// DimensionExhausted provides the count by dimension or reason
 	DimensionExhausted map[string]int
 
+	// ExhaustedDimensions breaks down the nodes exhausted of each dimension
+	// by datacenter and node class
+	ExhaustedDimensions []*ExhaustedDimension
+
 	// QuotaExhausted provides the exhausted dimensions
 	QuotaExhausted []string
 
+	// ConcurrencyGroupHeldBy is the ID of the job running allocations in the
+	// concurrency group of the job, which queued the placement.
+	ConcurrencyGroupHeldBy string
+
 	// Scores is the scores of the final few nodes remaining
 	// for placement. The top score is typically selected.
 	// Deprecated: Replaced by ScoreMetaData in Nomad 0.9
@@ -7748,6 +9517,13 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.ConstraintFiltered = helper.CopyMapStringInt(na.ConstraintFiltered)
 	na.ClassExhausted = helper.CopyMapStringInt(na.ClassExhausted)
 	na.DimensionExhausted = helper.CopyMapStringInt(na.DimensionExhausted)
+	if a.ExhaustedDimensions != nil {
+		na.ExhaustedDimensions = make([]*ExhaustedDimension, len(a.ExhaustedDimensions))
+		for i, e := range a.ExhaustedDimensions {
+			ne := *e
+			na.ExhaustedDimensions[i] = &ne
+		}
+	}
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
@@ -7758,6 +9534,14 @@ func (a *AllocMetric) EvaluateNode() {
 	a.NodesEvaluated += 1
 }
 
+func (a *AllocMetric) FeasibilityCacheHit() {
+	a.FeasibilityCacheHits += 1
+}
+
+func (a *AllocMetric) FeasibilityCacheMiss() {
+	a.FeasibilityCacheMisses += 1
+}
+
 func (a *AllocMetric) FilterNode(node *Node, constraint string) {
 	a.NodesFiltered += 1
 	if node != nil && node.NodeClass != "" {
@@ -7788,6 +9572,26 @@ func (a *AllocMetric) ExhaustedNode(node *Node, dimension string) {
 		}
 		a.DimensionExhausted[dimension] += 1
 	}
+	if node != nil && dimension != "" {
+		a.exhaustDimension(node.Datacenter, node.NodeClass, dimension)
+	}
+}
+
+// exhaustDimension counts a node of the datacenter and node class exhausted
+// of the dimension.
+func (a *AllocMetric) exhaustDimension(dc, class, dimension string) {
+	for _, e := range a.ExhaustedDimensions {
+		if e.Datacenter == dc && e.NodeClass == class && e.Dimension == dimension {
+			e.Nodes += 1
+			return
+		}
+	}
+	a.ExhaustedDimensions = append(a.ExhaustedDimensions, &ExhaustedDimension{
+		Datacenter: dc,
+		NodeClass:  class,
+		Dimension:  dimension,
+		Nodes:      1,
+	})
 }
 
 func (a *AllocMetric) ExhaustQuota(dimensions []string) {
@@ -7844,6 +9648,15 @@ func (a *AllocMetric) PopulateScoreMetaData() {
 
 // NodeScoreMeta captures scoring meta data derived from
 // different scoring factors.
+// ExhaustedDimension is the number of nodes of a datacenter and node class
+// that were exhausted of a dimension while placing an allocation.
+type ExhaustedDimension struct {
+	Datacenter string
+	NodeClass  string
+	Dimension  string
+	Nodes      int
+}
+
 type NodeScoreMeta struct {
 	NodeID    string
 	Scores    map[string]float64
@@ -7963,6 +9776,7 @@ const (
 	EvalTriggerRetryFailedAlloc  = "alloc-failure"
 	EvalTriggerQueuedAllocs      = "queued-allocs"
 	EvalTriggerPreemption        = "preemption"
+	EvalTriggerMaxDisconnect     = "max-disconnect-timeout"
 )
 
 const (
@@ -7988,10 +9802,42 @@ const (
 	// check if they are terminal. If so, we delete these out of the system.
 	CoreJobDeploymentGC = "deployment-gc"
 
+	// CoreJobExpiredACLTokenGC is used for the garbage collection of expired
+	// ACL tokens. Expired tokens are rejected as soon as they expire, so they
+	// are only reaped periodically.
+	CoreJobExpiredACLTokenGC = "expired-acl-token-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
+
+	// coreJobForceGCTargetPrefix prefixes the core job ID used to force
+	// garbage collection of the objects in a single namespace or of a single
+	// job. See CoreJobForceGCTarget.
+	coreJobForceGCTargetPrefix = CoreJobForceGC + ":"
 )
 
+// CoreJobForceGCTarget returns the core job ID used to force garbage
+// collection of the GCable objects in the given namespace. If jobID is
+// non-empty, collection is further restricted to objects of that job.
+func CoreJobForceGCTarget(namespace, jobID string) string {
+	return coreJobForceGCTargetPrefix + namespace + ":" + jobID
+}
+
+// ParseCoreJobForceGCTarget parses a core job ID created by
+// CoreJobForceGCTarget, returning the targeted namespace and job ID. ok is
+// false if the ID does not describe a targeted forced garbage collection.
+func ParseCoreJobForceGCTarget(id string) (namespace, jobID string, ok bool) {
+	if !strings.HasPrefix(id, coreJobForceGCTargetPrefix) {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(id, coreJobForceGCTargetPrefix), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
 // Evaluation is used anytime we need to apply business logic as a result
 // of a change to our desired state (job specification) or the emergent state
 // (registered nodes). When the inputs change, we need to "evaluate" them,
@@ -8434,6 +10280,10 @@ type PlanResult struct {
 	// AllocIndex is the Raft index in which the evictions and
 	// allocations took place. This is used for the write index.
 	AllocIndex uint64
+
+	// RejectedNodes maps the nodes whose placements were rejected to the
+	// reason of the rejection.
+	RejectedNodes map[string]string
 }
 
 // IsNoOp checks if this plan result would do nothing
@@ -8763,11 +10613,20 @@ type ACLToken struct {
 	Name        string   // Human friendly name
 	Type        string   // Client or Management
 	Policies    []string // Policies this token ties to
+	Roles       []string // Roles this token ties to
 	Global      bool     // Global or Region local
 	Hash        []byte
 	CreateTime  time.Time // Time of creation
 	CreateIndex uint64
 	ModifyIndex uint64
+
+	// ExpirationTime is the time after which the token can no longer be
+	// used. Tokens without one never expire.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is set when creating a token to have it expire after
+	// the duration. The server computes the ExpirationTime from it.
+	ExpirationTTL time.Duration
 }
 
 var (
@@ -8783,15 +10642,17 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	Hash        []byte
-	CreateTime  time.Time
-	CreateIndex uint64
-	ModifyIndex uint64
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	Hash           []byte
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
 }
 
 // SetHash is used to compute and set the hash of the ACL token
@@ -8808,11 +10669,17 @@ func (a *ACLToken) SetHash() []byte {
 	for _, policyName := range a.Policies {
 		hash.Write([]byte(policyName))
 	}
+	for _, roleName := range a.Roles {
+		hash.Write([]byte("role:" + roleName))
+	}
 	if a.Global {
 		hash.Write([]byte("global"))
 	} else {
 		hash.Write([]byte("local"))
 	}
+	if a.ExpirationTime != nil {
+		hash.Write([]byte(a.ExpirationTime.UTC().Format(time.RFC3339Nano)))
+	}
 
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
@@ -8824,18 +10691,25 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
-		CreateIndex: a.CreateIndex,
-		ModifyIndex: a.ModifyIndex,
+		AccessorID:     a.AccessorID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Policies:       a.Policies,
+		Roles:          a.Roles,
+		Global:         a.Global,
+		Hash:           a.Hash,
+		CreateTime:     a.CreateTime,
+		ExpirationTime: a.ExpirationTime,
+		CreateIndex:    a.CreateIndex,
+		ModifyIndex:    a.ModifyIndex,
 	}
 }
 
+// IsExpired returns whether the token has expired at the given time.
+func (a *ACLToken) IsExpired(now time.Time) bool {
+	return a.ExpirationTime != nil && !now.Before(*a.ExpirationTime)
+}
+
 // Validate is used to sanity check a token
 func (a *ACLToken) Validate() error {
 	var mErr multierror.Error
@@ -8844,16 +10718,22 @@ func (a *ACLToken) Validate() error {
 	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
 		if len(a.Policies) != 0 {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
 		}
+		if len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with roles"))
+		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
 	}
+	if a.ExpirationTTL < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token expiration TTL cannot be negative"))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -8875,6 +10755,24 @@ func (a *ACLToken) PolicySubset(policies []string) bool {
 	return true
 }
 
+// RoleSubset checks if a given set of roles is a subset of the token
+func (a *ACLToken) RoleSubset(roles []string) bool {
+	// Hot-path the management tokens, superset of all roles.
+	if a.Type == ACLManagementToken {
+		return true
+	}
+	associatedRoles := make(map[string]struct{}, len(a.Roles))
+	for _, role := range a.Roles {
+		associatedRoles[role] = struct{}{}
+	}
+	for _, role := range roles {
+		if _, ok := associatedRoles[role]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // ACLTokenListRequest is used to request a list of tokens
 type ACLTokenListRequest struct {
 	GlobalOnly bool
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/consul/api"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
@@ -112,6 +113,110 @@ func TestJob_Validate(t *testing.T) {
 	}
 }
 
+func TestJob_Validate_Tags(t *testing.T) {
+	require := require.New(t)
+
+	j := testJob()
+	j.Tags = []string{"owner:payments", "tier=1", "system"}
+	require.NoError(j.Validate())
+
+	j.Tags = []string{"owner:payments", "owner:payments", "with space", "a,b", ""}
+	err := j.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), `Duplicate tag "owner:payments"`)
+	require.Contains(err.Error(), `Invalid tag "with space"`)
+	require.Contains(err.Error(), `Invalid tag "a,b"`)
+	require.Contains(err.Error(), `Invalid tag ""`)
+
+	j.Tags = []string{strings.Repeat("a", 129)}
+	require.Error(j.Validate())
+}
+
+func TestJob_Validate_ConcurrencyGroup(t *testing.T) {
+	require := require.New(t)
+
+	j := testJob()
+	j.ConcurrencyGroup = "db-migrations.v2"
+	require.NoError(j.Validate())
+
+	j.ConcurrencyGroup = "with space"
+	err := j.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), `Invalid concurrency group "with space"`)
+
+	j.ConcurrencyGroup = "migrations"
+	j.Type = JobTypeSystem
+	err = j.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "System jobs may not have a concurrency group")
+}
+
+func TestJobListRequest_Matches_Tags(t *testing.T) {
+	require := require.New(t)
+
+	j := testJob()
+	j.Tags = []string{"owner:payments", "tier:1"}
+
+	req := &JobListRequest{Tags: []string{"owner:payments"}}
+	require.True(req.Matches(j))
+
+	req.Tags = []string{"owner:payments", "tier:1"}
+	require.True(req.Matches(j))
+
+	req.Tags = []string{"owner:payments", "tier:2"}
+	require.False(req.Matches(j))
+}
+
+func TestJobBulkRequest_Validate(t *testing.T) {
+	require := require.New(t)
+
+	req := &JobBulkRequest{Operation: JobBulkOperationStop, Prefix: "web"}
+	require.NoError(req.Validate())
+
+	req.Operation = "restart"
+	require.Contains(req.Validate().Error(), `invalid bulk operation "restart"`)
+
+	req.Operation = JobBulkOperationRun
+	req.Prefix = ""
+	require.Contains(req.Validate().Error(), "require a job ID prefix or tags")
+
+	req.Tags = []string{"owner:payments"}
+	req.Purge = true
+	require.Contains(req.Validate().Error(), "purge is only valid when stopping jobs")
+}
+
+func TestJobBulkRequest_Matches(t *testing.T) {
+	require := require.New(t)
+
+	j := testJob()
+	j.Periodic = nil
+	j.Tags = []string{"owner:payments"}
+
+	// Only running jobs are stopped, unless they are purged
+	req := &JobBulkRequest{Operation: JobBulkOperationStop, Tags: []string{"owner:payments"}}
+	require.True(req.Matches(j))
+	req.Tags = []string{"owner:search"}
+	require.False(req.Matches(j))
+
+	req.Tags = nil
+	j.Stop = true
+	require.False(req.Matches(j))
+	req.Purge = true
+	require.True(req.Matches(j))
+
+	// Only stopped jobs are run
+	req = &JobBulkRequest{Operation: JobBulkOperationRun}
+	require.True(req.Matches(j))
+	j.Stop = false
+	require.False(req.Matches(j))
+
+	// Periodic jobs can't be evaluated
+	req = &JobBulkRequest{Operation: JobBulkOperationEvaluate}
+	require.True(req.Matches(j))
+	j.Periodic = &PeriodicConfig{Enabled: true}
+	require.False(req.Matches(j))
+}
+
 func TestJob_Warnings(t *testing.T) {
 	cases := []struct {
 		Name     string
@@ -591,6 +696,29 @@ func TestJob_RequiredSignals(t *testing.T) {
 		},
 	}
 
+	j3 := &Job{
+		TaskGroups: []*TaskGroup{
+			{
+				Name: "foo",
+				Tasks: []*Task{
+					{
+						Name: "t1",
+						KillEscalation: []*KillStep{
+							{Signal: "SIGTERM", Timeout: time.Second},
+							{Signal: "SIGQUIT", Timeout: time.Second},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	e3 := map[string]map[string][]string{
+		"foo": {
+			"t1": {"SIGQUIT", "SIGTERM"},
+		},
+	}
+
 	cases := []struct {
 		Job      *Job
 		Expected map[string]map[string][]string
@@ -607,6 +735,10 @@ func TestJob_RequiredSignals(t *testing.T) {
 			Job:      j2,
 			Expected: e2,
 		},
+		{
+			Job:      j3,
+			Expected: e3,
+		},
 	}
 
 	for i, c := range cases {
@@ -771,6 +903,54 @@ func TestTaskGroup_Validate(t *testing.T) {
 	if !strings.Contains(err.Error(), "System jobs should not have a reschedule policy") {
 		t.Fatalf("err: %s", err)
 	}
+
+	tg = &TaskGroup{
+		MaxClientDisconnect: helper.TimeToPtr(-1 * time.Minute),
+	}
+	j.Type = JobTypeService
+	err = tg.Validate(j)
+	if !strings.Contains(err.Error(), "max_client_disconnect cannot be negative") {
+		t.Fatalf("err: %s", err)
+	}
+
+	j.Type = JobTypeSystem
+	err = tg.Validate(j)
+	if !strings.Contains(err.Error(), "System jobs may not set max_client_disconnect") {
+		t.Fatalf("err: %s", err)
+	}
+
+	tg = &TaskGroup{
+		ReconnectStrategy: ReconnectStrategyKeepOriginal,
+	}
+	j.Type = JobTypeService
+	err = tg.Validate(j)
+	if !strings.Contains(err.Error(), "reconnect_strategy requires max_client_disconnect to be set") {
+		t.Fatalf("err: %s", err)
+	}
+
+	tg.ReconnectStrategy = "keep_both"
+	err = tg.Validate(j)
+	if !strings.Contains(err.Error(), `Invalid reconnect_strategy "keep_both"`) {
+		t.Fatalf("err: %s", err)
+	}
+
+	tg = &TaskGroup{
+		Tasks: []*Task{
+			{Name: "server", Driver: "docker"},
+			{Name: "preprocess", Driver: "raw_exec"},
+		},
+		SharedMemory: &SharedMemory{},
+	}
+	err = tg.Validate(j)
+	if !strings.Contains(err.Error(), "minimum shared memory size is 1 MB") {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(err.Error(), `Task "preprocess": driver "raw_exec" does not support shared memory`) {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(err.Error(), `Task "server"`) {
+		t.Fatalf("err: %s", err)
+	}
 }
 
 func TestTask_Validate(t *testing.T) {
@@ -1128,6 +1308,37 @@ func TestTask_Validate_Service_Check(t *testing.T) {
 	}
 }
 
+func TestTask_Validate_Service_Check_Role(t *testing.T) {
+	check := ServiceCheck{
+		Name:     "check-name",
+		Type:     ServiceCheckTCP,
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+		Role:     ServiceCheckRoleReadiness,
+	}
+	require.NoError(t, check.validate())
+
+	// Readiness checks can't restart the task
+	check.CheckRestart = &CheckRestart{Limit: 3}
+	err := check.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "check_restart must not be set")
+
+	// Liveness checks must restart the task
+	check.Role = ServiceCheckRoleLiveness
+	require.NoError(t, check.validate())
+
+	check.CheckRestart = &CheckRestart{}
+	err = check.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "require a check_restart")
+
+	check.Role = "startup"
+	err = check.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid role "startup"`)
+}
+
 // TestTask_Validate_Service_Check_AddressMode asserts that checks do not
 // inherit address mode but do inherit ports.
 func TestTask_Validate_Service_Check_AddressMode(t *testing.T) {
@@ -1328,6 +1539,28 @@ func TestTask_Validate_Service_Check_GRPC(t *testing.T) {
 	assert.NoError(t, service.Validate())
 }
 
+func TestTask_Validate_Service_Check_Pipe(t *testing.T) {
+	t.Parallel()
+	check := &ServiceCheck{
+		Type:     ServiceCheckPipe,
+		Interval: time.Second,
+		Timeout:  time.Second,
+	}
+
+	// Bad (no named pipe path)
+	for _, path := range []string{"", "/var/run/app.sock", `\\.\pipe\`} {
+		check.Path = path
+		err := check.validate()
+		require.Error(t, err, "path: %q", path)
+		require.Contains(t, err.Error(), "named pipe path")
+	}
+
+	// Good
+	check.Path = `\\.\pipe\sql\query`
+	require.NoError(t, check.validate())
+	require.False(t, check.RequiresPort())
+}
+
 func TestTask_Validate_Service_Check_CheckRestart(t *testing.T) {
 	t.Parallel()
 	invalidCheckRestart := &CheckRestart{
@@ -1347,6 +1580,69 @@ func TestTask_Validate_Service_Check_CheckRestart(t *testing.T) {
 	assert.Nil(t, validCheckRestart.Validate())
 }
 
+func TestTask_Validate_Service_Provider(t *testing.T) {
+	t.Parallel()
+	service := &Service{
+		Name:      "web",
+		PortLabel: "http",
+		Provider:  "f5 pool",
+	}
+	err := service.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "service provider must be")
+
+	// Other names are external providers
+	service.Provider = "f5"
+	require.NoError(t, service.Validate())
+	require.True(t, service.UsesExternalProvider())
+	require.False(t, service.UsesConsul())
+
+	// The Nomad provider supports HTTP and TCP checks
+	service.Provider = ServiceProviderNomad
+	service.Checks = []*ServiceCheck{
+		{
+			Name:     "http",
+			Type:     ServiceCheckHTTP,
+			Path:     "/health",
+			Interval: 10 * time.Second,
+			Timeout:  2 * time.Second,
+		},
+		{
+			Name:     "tcp",
+			Type:     ServiceCheckTCP,
+			Interval: 10 * time.Second,
+			Timeout:  2 * time.Second,
+		},
+		{
+			Name:     "grpc",
+			Type:     ServiceCheckGRPC,
+			Interval: 10 * time.Second,
+			Timeout:  2 * time.Second,
+		},
+	}
+	require.NoError(t, service.Validate())
+
+	// Script checks and check_restart are rejected
+	service.Checks = append(service.Checks, &ServiceCheck{
+		Name:     "script",
+		Type:     ServiceCheckScript,
+		Command:  "/bin/true",
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	})
+	service.Checks[0].CheckRestart = &CheckRestart{Limit: 1}
+	err = service.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "only supports")
+	require.Contains(t, err.Error(), "check_restart is not supported")
+
+	// External providers have the same restrictions
+	service.Provider = "f5"
+	err = service.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `the "f5" service provider only supports`)
+}
+
 func TestTask_Validate_LogConfig(t *testing.T) {
 	task := &Task{
 		LogConfig: DefaultLogConfig(),
@@ -1362,6 +1658,203 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 	}
 }
 
+func TestLogConfig_Validate_EventLog(t *testing.T) {
+	t.Parallel()
+	lc := DefaultLogConfig()
+	lc.EventLogProviders = []string{"MSSQLSERVER"}
+	lc.EventLogChannel = "Application"
+	require.NoError(t, lc.Validate())
+
+	lc.EventLogProviders = []string{"", "bad'provider"}
+	err := lc.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid event log provider ""`)
+	require.Contains(t, err.Error(), `invalid event log provider "bad'provider"`)
+
+	lc.EventLogProviders = nil
+	err = lc.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires event log providers")
+}
+
+func TestTask_Validate_Action(t *testing.T) {
+	task := &Task{
+		Actions: []*Action{
+			{},
+			{
+				Name:    "flush/all",
+				Command: "/bin/flush",
+				Timeout: time.Minute,
+			},
+			{
+				Name:    "flush/all",
+				Command: "/bin/flush",
+				Timeout: time.Minute,
+			},
+		},
+	}
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 1,
+	}
+
+	err := task.Validate(ephemeralDisk, JobTypeService)
+	require.Error(t, err)
+	for _, expected := range []string{
+		"Action 1 validation failed",
+		"Missing action name",
+		"Missing action command",
+		"Timeout must be a positive value",
+		"Action name cannot include slashes",
+		"Action 3 has same name as 2",
+	} {
+		require.Contains(t, err.Error(), expected)
+	}
+}
+
+func TestTask_Validate_MetricLabels(t *testing.T) {
+	task := &Task{
+		MetricLabels: []string{"team", "service-tier", "1team", "alloc_id", "team"},
+	}
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 1,
+	}
+
+	err := task.Validate(ephemeralDisk, JobTypeService)
+	require.Error(t, err)
+	for _, expected := range []string{
+		`Metric label "service-tier" must contain only`,
+		`Metric label "1team" must contain only`,
+		`Metric label "alloc_id" is reserved`,
+		`Metric label "team" is listed more than once`,
+	} {
+		require.Contains(t, err.Error(), expected)
+	}
+}
+
+func TestTask_Validate_KillEscalation(t *testing.T) {
+	task := &Task{
+		KillSignal: "SIGQUIT",
+		KillEscalation: []*KillStep{
+			{Signal: "SIGTERM", Timeout: time.Second},
+			{Timeout: -1},
+		},
+	}
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 1,
+	}
+
+	err := task.Validate(ephemeralDisk, JobTypeService)
+	require.Error(t, err)
+	for _, expected := range []string{
+		"KillSignal can't be set with a kill escalation chain",
+		"Kill step 2 validation failed",
+		"Missing kill signal",
+		"Timeout must be a positive value",
+	} {
+		require.Contains(t, err.Error(), expected)
+	}
+	require.NotContains(t, err.Error(), "Kill step 1")
+}
+
+func TestTask_KillSteps(t *testing.T) {
+	task := &Task{
+		KillSignal:  "SIGQUIT",
+		KillTimeout: time.Second,
+	}
+	require.Equal(t, []*KillStep{{Signal: "SIGQUIT", Timeout: time.Second}}, task.KillSteps())
+
+	task.KillSignal = ""
+	task.KillEscalation = []*KillStep{
+		{Signal: "SIGTERM", Timeout: 30 * time.Second},
+		{Signal: "SIGINT", Timeout: 10 * time.Second},
+	}
+	require.Equal(t, task.KillEscalation, task.KillSteps())
+}
+
+func TestTask_Validate_Secret(t *testing.T) {
+	task := &Task{
+		Templates: []*Template{
+			{
+				EmbeddedTmpl: "foo",
+				DestPath:     "secrets/token",
+				ChangeMode:   TemplateChangeModeRestart,
+			},
+		},
+		Secrets: []*Secret{
+			{},
+			{
+				Name:       "../token",
+				Provider:   SecretProviderNomad,
+				Path:       "nomad/jobs/example",
+				Key:        "token",
+				Perms:      "999",
+				Uid:        -1,
+				ChangeMode: TemplateChangeModeSignal,
+			},
+			{
+				Name:       "token",
+				Provider:   SecretProviderVault,
+				Path:       "secret/data/example",
+				Key:        "token",
+				ChangeMode: TemplateChangeModeScript,
+			},
+			{
+				Name:       "token",
+				Provider:   SecretProviderNomad,
+				Path:       "nomad/jobs/example",
+				Key:        "token",
+				ChangeMode: TemplateChangeModeRestart,
+			},
+		},
+	}
+	ephemeralDisk := &EphemeralDisk{
+		SizeMB: 1,
+	}
+
+	err := task.Validate(ephemeralDisk, JobTypeService)
+	require.Error(t, err)
+	for _, expected := range []string{
+		"Secret 1 validation failed",
+		"Missing secret name",
+		`Invalid provider ""`,
+		"Missing secret path",
+		"Missing secret key",
+		`Secret name "../token" must be a file name`,
+		`Failed to parse "999" as octal`,
+		"Uid and gid must not be negative",
+		"Must specify signal value when change mode is signal",
+		"Invalid change mode",
+		"Secret 3 has same destination as template 1",
+		"Secret 3 is read from Vault but the task has no vault stanza",
+		"Secret 4 has same name as 3",
+	} {
+		require.Contains(t, err.Error(), expected)
+	}
+}
+
+func TestSecret_Template(t *testing.T) {
+	s := &Secret{
+		Name:       "db_password",
+		Provider:   SecretProviderNomad,
+		Path:       "nomad/jobs/example/db",
+		Key:        "password",
+		Perms:      "0400",
+		ChangeMode: TemplateChangeModeRestart,
+	}
+	tmpl := s.Template()
+	require.NoError(t, tmpl.Validate())
+	require.Equal(t, "secrets/db_password", tmpl.DestPath)
+	require.Equal(t, `{{ index (nomadVar "nomad/jobs/example/db") "password" }}`, tmpl.EmbeddedTmpl)
+	require.Equal(t, "0400", tmpl.Perms)
+
+	// Vault secrets read KV version 1 and 2 secrets
+	s.Provider = SecretProviderVault
+	s.Path = "secret/data/db"
+	tmpl = s.Template()
+	require.NoError(t, tmpl.Validate())
+	require.Equal(t, `{{ with secret "secret/data/db" }}{{ if .Data.data }}{{ index .Data.data "password" }}{{ else }}{{ index .Data "password" }}{{ end }}{{ end }}`, tmpl.EmbeddedTmpl)
+}
+
 func TestTask_Validate_Template(t *testing.T) {
 
 	bad := &Template{}
@@ -1489,6 +1982,42 @@ func TestTemplate_Validate(t *testing.T) {
 				"as octal",
 			},
 		},
+		{
+			Tmpl: &Template{
+				SourcePath: "foo",
+				DestPath:   "local/foo",
+				ChangeMode: "script",
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				"specify change script",
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath:   "foo",
+				DestPath:     "local/foo",
+				ChangeMode:   "script",
+				ChangeScript: &ChangeScript{},
+			},
+			Fail: true,
+			ContainsErrs: []string{
+				"change script command",
+				"timeout must be greater than zero",
+			},
+		},
+		{
+			Tmpl: &Template{
+				SourcePath: "foo",
+				DestPath:   "local/foo",
+				ChangeMode: "script",
+				ChangeScript: &ChangeScript{
+					Command: "/bin/reload",
+					Timeout: 5 * time.Second,
+				},
+			},
+			Fail: false,
+		},
 	}
 
 	for i, c := range cases {
@@ -1863,6 +2392,31 @@ func TestResource_Add_Network(t *testing.T) {
 	}
 }
 
+func TestDNSConfig_Validate(t *testing.T) {
+	require := require.New(t)
+
+	var d *DNSConfig
+	require.NoError(d.Validate())
+
+	d = &DNSConfig{
+		Servers:  []string{"10.0.0.53", "fd00::53"},
+		Searches: []string{"service.consul"},
+		Options:  []string{"ndots:2"},
+	}
+	require.NoError(d.Validate())
+
+	d = &DNSConfig{
+		Servers:  []string{"dns.example.com"},
+		Searches: []string{"a b"},
+		Options:  []string{""},
+	}
+	err := d.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "is not an IP address")
+	require.Contains(err.Error(), "invalid search domain")
+	require.Contains(err.Error(), "invalid option")
+}
+
 func TestComparableResources_Subtract(t *testing.T) {
 	r1 := &ComparableResources{
 		Flattened: AllocatedTaskResources{
@@ -2210,7 +2764,7 @@ func TestPeriodicConfig_EnabledInvalid(t *testing.T) {
 }
 
 func TestPeriodicConfig_InvalidCron(t *testing.T) {
-	specs := []string{"foo", "* *", "@foo"}
+	specs := []string{"foo", "* *", "@foo", "0 0 30 2 *", "0 0 1 1 * 2017"}
 	for _, spec := range specs {
 		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: spec}
 		p.Canonicalize()
@@ -2277,7 +2831,7 @@ func TestPeriodicConfig_NextCron(t *testing.T) {
 func TestPeriodicConfig_ValidTimeZone(t *testing.T) {
 	zones := []string{"Africa/Abidjan", "America/Chicago", "Europe/Minsk", "UTC"}
 	for _, zone := range zones {
-		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: "0 0 29 2 * 1980", TimeZone: zone}
+		p := &PeriodicConfig{Enabled: true, SpecType: PeriodicSpecCron, Spec: "0 0 29 2 *", TimeZone: zone}
 		p.Canonicalize()
 		if err := p.Validate(); err != nil {
 			t.Fatalf("Valid tz errored: %v", err)
@@ -2695,6 +3249,36 @@ func TestTaskArtifact_Hash(t *testing.T) {
 	require.Len(t, hashes, len(cases))
 }
 
+func TestAllocation_ExitCodes(t *testing.T) {
+	require := require.New(t)
+
+	alloc := &Allocation{
+		TaskStates: map[string]*TaskState{
+			"restarted": {
+				Events: []*TaskEvent{
+					NewTaskEvent(TaskTerminated).SetExitCode(1),
+					NewTaskEvent(TaskRestarting),
+					NewTaskEvent(TaskTerminated).SetExitCode(0),
+				},
+			},
+			"failed": {
+				Events: []*TaskEvent{
+					NewTaskEvent(TaskTerminated).SetExitCode(2),
+					NewTaskEvent(TaskNotRestarting),
+				},
+			},
+			"never-started": {
+				Events: []*TaskEvent{
+					NewTaskEvent(TaskDriverFailure),
+				},
+			},
+		},
+	}
+	require.Equal(map[string]int{"restarted": 0, "failed": 2}, alloc.ExitCodes())
+
+	require.Nil((&Allocation{}).ExitCodes())
+}
+
 func TestAllocation_ShouldMigrate(t *testing.T) {
 	alloc := Allocation{
 		PreviousAllocation: "123",
@@ -3593,6 +4177,56 @@ func TestRescheduleTracker_Copy(t *testing.T) {
 	}
 }
 
+func TestAllocation_RescheduleStatus(t *testing.T) {
+	require := require.New(t)
+	now := time.Now().UTC()
+	failTime := now.Add(-1 * time.Second)
+
+	alloc := &Allocation{
+		ID:             "a",
+		TaskGroup:      "web",
+		ClientStatus:   AllocClientStatusFailed,
+		FollowupEvalID: "e",
+		Job: &Job{
+			TaskGroups: []*TaskGroup{{
+				Name: "web",
+				ReschedulePolicy: &ReschedulePolicy{
+					Attempts:      2,
+					Interval:      10 * time.Minute,
+					Delay:         5 * time.Second,
+					DelayFunction: "constant",
+				},
+			}},
+		},
+		TaskStates: map[string]*TaskState{
+			"web": {State: TaskStateDead, Failed: true, FinishedAt: failTime},
+		},
+		RescheduleTracker: &RescheduleTracker{Events: []*RescheduleEvent{
+			// Outside of the interval
+			{RescheduleTime: failTime.Add(-1 * time.Hour).UnixNano(), PrevAllocID: "x", Delay: 5 * time.Second},
+			{RescheduleTime: failTime.Add(-1 * time.Minute).UnixNano(), PrevAllocID: "y", Delay: 5 * time.Second},
+		}},
+	}
+
+	status := alloc.RescheduleStatus(now)
+	require.Equal("a", status.AllocID)
+	require.Equal("web", status.TaskGroup)
+	require.Equal("e", status.FollowupEvalID)
+	require.Equal(1, status.Attempts)
+	require.Equal(2, status.MaxAttempts)
+	require.False(status.Unlimited)
+	require.Len(status.Events, 2)
+	require.True(status.Eligible)
+	require.Equal(failTime.Add(5*time.Second), status.NextRescheduleTime)
+
+	// Running allocations have no next reschedule time
+	alloc.ClientStatus = AllocClientStatusRunning
+	status = alloc.RescheduleStatus(now)
+	require.Equal(1, status.Attempts)
+	require.False(status.Eligible)
+	require.True(status.NextRescheduleTime.IsZero())
+}
+
 func TestVault_Validate(t *testing.T) {
 	v := &Vault{
 		Env:        true,
@@ -3637,6 +4271,80 @@ func TestParameterizedJobConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestParameterizedJobConfig_Validate_MetaConfigs(t *testing.T) {
+	require := require.New(t)
+	d := &ParameterizedJobConfig{
+		Payload:      DispatchPayloadOptional,
+		MetaRequired: []string{"foo"},
+		MetaOptional: []string{"bar"},
+		MetaConfigs: []*DispatchMetaConfig{
+			{
+				Key:   "foo",
+				Type:  DispatchMetaTypeInt,
+				Enum:  []string{"1", "two"},
+				Regex: "^[0-9]$",
+			},
+			{
+				Key:          "bar",
+				Type:         "date",
+				RequiredWith: []string{"bar", "baz"},
+			},
+			{
+				Key:   "baz",
+				Type:  DispatchMetaTypeString,
+				Regex: "(",
+			},
+			{
+				Key: "foo",
+			},
+		},
+	}
+
+	err := d.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), `Meta key "foo": Invalid enum value: Meta key "foo" value "two" is not of type int`)
+	require.Contains(err.Error(), `Meta key "bar": Unknown type "date"`)
+	require.Contains(err.Error(), `Meta key "baz": Key must be a required or optional meta key`)
+	require.Contains(err.Error(), `Meta key "baz": Invalid regex "("`)
+	require.Contains(err.Error(), `Meta key "foo" configured more than once`)
+
+	d.MetaConfigs = d.MetaConfigs[:1]
+	d.MetaConfigs[0].Enum = []string{"1", "2"}
+	require.NoError(d.Validate())
+}
+
+func TestDispatchMetaConfig_ValidateDispatch(t *testing.T) {
+	require := require.New(t)
+	c := &DispatchMetaConfig{
+		Key:          "region",
+		Enum:         []string{"eu-west-1", "us-east-1"},
+		Regex:        "^[a-z]+-",
+		RequiredWith: []string{"bucket"},
+	}
+	c.Canonicalize()
+	require.Equal(DispatchMetaTypeString, c.Type)
+
+	// Unset keys are not validated
+	require.NoError(c.ValidateDispatch(map[string]string{"bucket": "b"}))
+	require.NoError(c.ValidateDispatch(map[string]string{"region": "us-east-1", "bucket": "b"}))
+
+	err := c.ValidateDispatch(map[string]string{"region": "us-west-2"})
+	require.Error(err)
+	require.Contains(err.Error(), `value "us-west-2" is not one of [eu-west-1 us-east-1]`)
+	require.Contains(err.Error(), `Meta key "bucket" must be provided with meta key "region"`)
+
+	c.Enum = nil
+	err = c.ValidateDispatch(map[string]string{"region": "EU", "bucket": "b"})
+	require.Error(err)
+	require.Contains(err.Error(), `value "EU" does not match "^[a-z]+-"`)
+
+	c.Regex = ""
+	c.Type = DispatchMetaTypeBool
+	err = c.ValidateDispatch(map[string]string{"region": "maybe", "bucket": "b"})
+	require.Error(err)
+	require.Contains(err.Error(), `value "maybe" is not of type bool`)
+}
+
 func TestParameterizedJobConfig_Validate_NonBatch(t *testing.T) {
 	job := testJob()
 	job.ParameterizedJob = &ParameterizedJobConfig{
@@ -3741,6 +4449,27 @@ func TestACLTokenValidate(t *testing.T) {
 	tk.Name = "foo"
 	err = tk.Validate()
 	assert.Nil(t, err)
+
+	// Invalid roles
+	tk.Roles = []string{"foo"}
+	err = tk.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "associated with roles") {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Client tokens may have only roles
+	tk.Type = ACLClientToken
+	err = tk.Validate()
+	assert.Nil(t, err)
+
+	// Negative expiration TTL
+	tk.ExpirationTTL = -time.Minute
+	err = tk.Validate()
+	assert.NotNil(t, err)
+	if !strings.Contains(err.Error(), "cannot be negative") {
+		t.Fatalf("bad: %v", err)
+	}
 }
 
 func TestACLTokenPolicySubset(t *testing.T) {
@@ -4303,3 +5032,84 @@ func TestNodeReservedNetworkResources_ParseReserved(t *testing.T) {
 		require.Equal(out, tc.Parsed)
 	}
 }
+
+func TestCoreJobForceGCTarget(t *testing.T) {
+	require := require.New(t)
+
+	ns, job, ok := ParseCoreJobForceGCTarget(CoreJobForceGCTarget("default", "example"))
+	require.True(ok)
+	require.Equal("default", ns)
+	require.Equal("example", job)
+
+	ns, job, ok = ParseCoreJobForceGCTarget(CoreJobForceGCTarget("default", "dispatch:with:colons"))
+	require.True(ok)
+	require.Equal("default", ns)
+	require.Equal("dispatch:with:colons", job)
+
+	ns, job, ok = ParseCoreJobForceGCTarget(CoreJobForceGCTarget("default", ""))
+	require.True(ok)
+	require.Equal("default", ns)
+	require.Empty(job)
+
+	for _, id := range []string{CoreJobForceGC, CoreJobJobGC, "force-gc:", "force-gc::example"} {
+		_, _, ok := ParseCoreJobForceGCTarget(id)
+		require.False(ok, id)
+	}
+}
+
+func TestDeploymentState_AddProgress(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	state := &DeploymentState{
+		PlacedAllocs:      2,
+		HealthyAllocs:     1,
+		RequireProgressBy: now.Add(time.Minute),
+	}
+	state.AddProgress(now, 10)
+
+	require.Len(state.Progress, 1)
+	require.Equal(&DeploymentProgress{
+		Time:              now,
+		Index:             10,
+		PlacedAllocs:      2,
+		HealthyAllocs:     1,
+		RequireProgressBy: now.Add(time.Minute),
+	}, state.Progress[0])
+
+	// Copies don't share the progress
+	c := state.Copy()
+	c.Progress[0].HealthyAllocs = 2
+	require.Equal(1, state.Progress[0].HealthyAllocs)
+
+	// Only the most recent progress is kept
+	for i := 0; i < DeploymentProgressLimit; i++ {
+		state.AddProgress(now, uint64(11+i))
+	}
+	require.Len(state.Progress, DeploymentProgressLimit)
+	require.EqualValues(11, state.Progress[0].Index)
+}
+
+func TestAllocMetric_ExhaustedNode(t *testing.T) {
+	require := require.New(t)
+
+	m := &AllocMetric{}
+	m.ExhaustedNode(&Node{Datacenter: "dc1", NodeClass: "large"}, "memory")
+	m.ExhaustedNode(&Node{Datacenter: "dc1", NodeClass: "large"}, "memory")
+	m.ExhaustedNode(&Node{Datacenter: "dc1"}, "memory")
+	m.ExhaustedNode(&Node{Datacenter: "dc2", NodeClass: "large"}, "cpu")
+	m.ExhaustedNode(nil, "disk")
+
+	require.Equal(5, m.NodesExhausted)
+	require.Equal(map[string]int{"memory": 3, "cpu": 1, "disk": 1}, m.DimensionExhausted)
+	require.Equal([]*ExhaustedDimension{
+		{Datacenter: "dc1", NodeClass: "large", Dimension: "memory", Nodes: 2},
+		{Datacenter: "dc1", NodeClass: "", Dimension: "memory", Nodes: 1},
+		{Datacenter: "dc2", NodeClass: "large", Dimension: "cpu", Nodes: 1},
+	}, m.ExhaustedDimensions)
+
+	// The breakdown is deep copied
+	c := m.Copy()
+	c.ExhaustedDimensions[0].Nodes++
+	require.Equal(2, m.ExhaustedDimensions[0].Nodes)
+}
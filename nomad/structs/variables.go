@@ -0,0 +1,194 @@
+package structs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MaxVariableSize is the maximum size of the items of a variable once
+	// encoded, before encryption.
+	MaxVariableSize = 64 * 1024
+
+	// VariablesReservedPrefix is the path prefix reserved for variables
+	// managed by Nomad. Only paths below VariablesJobsPrefix may be written.
+	VariablesReservedPrefix = "nomad"
+
+	// VariablesJobsPrefix is the path prefix of the variables that the tasks
+	// of a job are implicitly allowed to read.
+	VariablesJobsPrefix = "nomad/jobs"
+
+	// RootKeyAlgorithmAES256GCM is the algorithm used to encrypt variables
+	// with a root key.
+	RootKeyAlgorithmAES256GCM = "aes256-gcm"
+)
+
+var (
+	// validVariablePath is used to validate a variable path
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_~/]{1,128}$")
+)
+
+// VariableMetadata is the metadata envelope of a variable. It is never
+// encrypted and can be listed without access to the variable's items.
+type VariableMetadata struct {
+	Namespace string
+	Path      string
+
+	// CreateTime and ModifyTime are stored as UnixNano values.
+	CreateTime int64
+	ModifyTime int64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// VariableEncrypted is the representation of a variable stored in Raft. The
+// items are encoded and encrypted with the root key referenced by KeyID.
+type VariableEncrypted struct {
+	VariableMetadata
+
+	// Data is the encrypted items of the variable, prefixed by the nonce.
+	Data []byte
+
+	// KeyID is the ID of the root key used to encrypt the data.
+	KeyID string
+}
+
+// VariableDecrypted is the representation of a variable that is exposed to
+// users and is never stored.
+type VariableDecrypted struct {
+	VariableMetadata
+
+	// Items are the key value pairs of the variable.
+	Items map[string]string
+}
+
+// Copy returns a deep copy of the variable.
+func (v *VariableDecrypted) Copy() *VariableDecrypted {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(VariableDecrypted)
+	*nv = *v
+	if v.Items != nil {
+		nv.Items = make(map[string]string, len(v.Items))
+		for k, val := range v.Items {
+			nv.Items[k] = val
+		}
+	}
+	return nv
+}
+
+// Validate validates the path and items of the variable.
+func (v *VariableDecrypted) Validate() error {
+	if err := ValidateVariablePath(v.Path); err != nil {
+		return err
+	}
+
+	if len(v.Items) == 0 {
+		return fmt.Errorf("variable missing items")
+	}
+	for k := range v.Items {
+		if k == "" {
+			return fmt.Errorf("variable item keys must not be empty")
+		}
+	}
+	return nil
+}
+
+// ValidateVariablePath validates the path of a variable.
+func ValidateVariablePath(path string) error {
+	switch {
+	case path == "":
+		return fmt.Errorf("missing variable path")
+	case !validVariablePath.MatchString(path):
+		return fmt.Errorf("invalid variable path %q: must be at most 128 characters of letters, numbers and the characters \"-_~/\"", path)
+	case strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/"):
+		return fmt.Errorf("invalid variable path %q: must not start or end with \"/\"", path)
+	case strings.Contains(path, "//"):
+		return fmt.Errorf("invalid variable path %q: must not contain empty segments", path)
+	}
+
+	// Only the job variables can be written under the reserved prefix
+	parts := strings.Split(path, "/")
+	if parts[0] == VariablesReservedPrefix {
+		if len(parts) == 1 || parts[1] != "jobs" {
+			return fmt.Errorf("invalid variable path %q: only paths below %q may use the reserved prefix %q",
+				path, VariablesJobsPrefix, VariablesReservedPrefix)
+		}
+	}
+	return nil
+}
+
+// RootKey is the key used by the servers to encrypt variables. The key is
+// stored in Raft so that every server can decrypt the variables.
+type RootKey struct {
+	KeyID     string
+	Algorithm string
+	Key       []byte
+
+	// CreateTime is stored as a UnixNano value.
+	CreateTime int64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// RootKeyUpsertRequest is used to store a root key in Raft.
+type RootKeyUpsertRequest struct {
+	RootKey *RootKey
+	WriteRequest
+}
+
+// VariablesUpsertRequest is used to create or update a variable.
+type VariablesUpsertRequest struct {
+	Variable *VariableDecrypted
+	WriteRequest
+}
+
+// VariablesEncryptedUpsertRequest is used to store an encrypted variable in
+// Raft.
+type VariablesEncryptedUpsertRequest struct {
+	Variable *VariableEncrypted
+	WriteRequest
+}
+
+// VariablesUpsertResponse is the response to a variable upsert.
+type VariablesUpsertResponse struct {
+	// Variable is the metadata of the written variable.
+	Variable *VariableMetadata
+	WriteMeta
+}
+
+// VariablesDeleteRequest is used to delete a variable.
+type VariablesDeleteRequest struct {
+	Path string
+	WriteRequest
+}
+
+// VariablesReadRequest is used to read a single variable.
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is the response to a variable read. Data is nil if
+// the variable does not exist.
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
+
+// VariablesListRequest is used to list the variables of a namespace, filtered
+// by the path prefix set in the query options.
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is the response to a variable listing.
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
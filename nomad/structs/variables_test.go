@@ -0,0 +1,39 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateVariablePath(t *testing.T) {
+	cases := []struct {
+		Path string
+		Err  string
+	}{
+		{Path: "project/db"},
+		{Path: "a-b_c~d"},
+		{Path: "nomad/jobs/example/web"},
+		{Path: "", Err: "missing"},
+		{Path: "project/db!", Err: "letters, numbers"},
+		{Path: strings.Repeat("a", 129), Err: "at most 128"},
+		{Path: "/project", Err: "start or end"},
+		{Path: "project/", Err: "start or end"},
+		{Path: "project//db", Err: "empty segments"},
+		{Path: "nomad", Err: "reserved prefix"},
+		{Path: "nomad/other", Err: "reserved prefix"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Path, func(t *testing.T) {
+			err := ValidateVariablePath(c.Path)
+			if c.Err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.Err)
+			}
+		})
+	}
+}
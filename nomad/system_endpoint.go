@@ -15,8 +15,8 @@ type System struct {
 }
 
 // GarbageCollect is used to trigger the system to immediately garbage collect nodes, evals
-// and jobs.
-func (s *System) GarbageCollect(args *structs.GenericRequest, reply *structs.GenericResponse) error {
+// and jobs. The collection can be restricted to a namespace or a single job.
+func (s *System) GarbageCollect(args *structs.GarbageCollectRequest, reply *structs.GenericResponse) error {
 	if done, err := s.srv.forward("System.GarbageCollect", args, args, reply); done {
 		return err
 	}
@@ -34,7 +34,14 @@ func (s *System) GarbageCollect(args *structs.GenericRequest, reply *structs.Gen
 		return fmt.Errorf("failed to determine state store's index: %v", err)
 	}
 
-	s.srv.evalBroker.Enqueue(s.srv.coreJobEval(structs.CoreJobForceGC, snapshotIndex))
+	coreJob := structs.CoreJobForceGC
+	if args.TargetNamespace != "" {
+		coreJob = structs.CoreJobForceGCTarget(args.TargetNamespace, args.JobID)
+	} else if args.JobID != "" {
+		return fmt.Errorf("garbage collecting a job requires its namespace")
+	}
+
+	s.srv.evalBroker.Enqueue(s.srv.coreJobEval(coreJob, snapshotIndex))
 	return nil
 }
 
@@ -64,6 +64,81 @@ func TestSystemEndpoint_GarbageCollect(t *testing.T) {
 	})
 }
 
+func TestSystemEndpoint_GarbageCollect_Target(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert two jobs that can be GC'd
+	state := s1.fsm.State()
+	job1 := mock.Job()
+	job1.Type = structs.JobTypeBatch
+	job1.Stop = true
+	job2 := job1.Copy()
+	job2.ID = "other"
+	if err := state.UpsertJob(1000, job1); err != nil {
+		t.Fatalf("UpsertJob() failed: %v", err)
+	}
+	if err := state.UpsertJob(1001, job2); err != nil {
+		t.Fatalf("UpsertJob() failed: %v", err)
+	}
+
+	eval1 := mock.Eval()
+	eval1.Status = structs.EvalStatusComplete
+	eval1.JobID = job1.ID
+	eval2 := mock.Eval()
+	eval2.Status = structs.EvalStatusComplete
+	eval2.JobID = job2.ID
+	if err := state.UpsertEvals(1002, []*structs.Evaluation{eval1, eval2}); err != nil {
+		t.Fatalf("UpsertEvals() failed: %v", err)
+	}
+
+	// A job without a namespace is rejected
+	req := &structs.GarbageCollectRequest{
+		JobID: job1.ID,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+	var resp structs.GenericResponse
+	if err := msgpackrpc.CallWithCodec(codec, "System.GarbageCollect", req, &resp); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	// Make the targeted GC request
+	req.TargetNamespace = job1.Namespace
+	if err := msgpackrpc.CallWithCodec(codec, "System.GarbageCollect", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		// Check if the targeted job has been GC'd
+		ws := memdb.NewWatchSet()
+		exist, err := state.JobByID(ws, job1.Namespace, job1.ID)
+		if err != nil {
+			return false, err
+		}
+		if exist != nil {
+			return false, fmt.Errorf("job %+v wasn't garbage collected", job1)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	// The other job should be untouched
+	ws := memdb.NewWatchSet()
+	exist, err := state.JobByID(ws, job2.Namespace, job2.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if exist == nil {
+		t.Fatalf("job %+v was garbage collected", job2)
+	}
+}
+
 func TestSystemEndpoint_GarbageCollect_ACL(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -0,0 +1,194 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// defaultUsageWindow is the window of a usage report if no start is
+	// given.
+	defaultUsageWindow = 24 * time.Hour
+)
+
+// usageReport returns the inventory of the jobs, allocations and nodes in the
+// state and the resources allocated to them between start and end.
+func usageReport(snap *state.StateSnapshot, start, end time.Time) (*structs.UsageReport, error) {
+	report := &structs.UsageReport{
+		Start:  start.UTC(),
+		End:    end.UTC(),
+		Totals: &structs.UsageTotals{},
+	}
+	ws := memdb.NewWatchSet()
+
+	// Add the nodes
+	nodes := make(map[string]*structs.NodeUsage)
+	iter, err := snap.Nodes(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		usage := &structs.NodeUsage{
+			ID:         node.ID,
+			Name:       node.Name,
+			Datacenter: node.Datacenter,
+			NodeClass:  node.NodeClass,
+			NodePool:   node.NodePool,
+			Status:     node.Status,
+		}
+		if node.NodeResources != nil || node.Resources != nil {
+			available := node.ComparableResources()
+			available.Subtract(node.ComparableReservedResources())
+			usage.CPUMHz = available.Flattened.Cpu.CpuShares
+			usage.MemoryMB = available.Flattened.Memory.MemoryMB
+		}
+		nodes[node.ID] = usage
+		report.Nodes = append(report.Nodes, usage)
+
+		report.Totals.CPUMHz += usage.CPUMHz
+		report.Totals.MemoryMB += usage.MemoryMB
+	}
+
+	// Add the jobs
+	jobs := make(map[structs.NamespacedID]*structs.JobUsage)
+	iter, err = snap.Jobs(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		usage := &structs.JobUsage{
+			Namespace: job.Namespace,
+			ID:        job.ID,
+			ParentID:  job.ParentID,
+			Type:      job.Type,
+		}
+		jobs[structs.NamespacedID{Namespace: job.Namespace, ID: job.ID}] = usage
+		report.Jobs = append(report.Jobs, usage)
+	}
+
+	// Add the allocations that ran within the window, along with the jobs
+	// that have been purged since
+	iter, err = snap.Allocs(ws)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		alloc := raw.(*structs.Allocation)
+		usage := allocUsage(alloc, start, end)
+		if usage == nil {
+			continue
+		}
+		report.Allocations = append(report.Allocations, usage)
+
+		id := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+		job, ok := jobs[id]
+		if !ok {
+			job = &structs.JobUsage{
+				Namespace: alloc.Namespace,
+				ID:        alloc.JobID,
+			}
+			if alloc.Job != nil {
+				job.ParentID = alloc.Job.ParentID
+				job.Type = alloc.Job.Type
+			}
+			jobs[id] = job
+			report.Jobs = append(report.Jobs, job)
+		}
+		job.Allocations++
+		job.CPUMHzSeconds += usage.CPUMHzSeconds
+		job.MemoryMBSeconds += usage.MemoryMBSeconds
+
+		if node, ok := nodes[alloc.NodeID]; ok {
+			node.Allocations++
+			node.CPUMHzSeconds += usage.CPUMHzSeconds
+			node.MemoryMBSeconds += usage.MemoryMBSeconds
+		}
+
+		report.Totals.CPUMHzSeconds += usage.CPUMHzSeconds
+		report.Totals.MemoryMBSeconds += usage.MemoryMBSeconds
+	}
+
+	report.Totals.Jobs = len(report.Jobs)
+	report.Totals.Allocations = len(report.Allocations)
+	report.Totals.Nodes = len(report.Nodes)
+
+	sort.Slice(report.Jobs, func(i, j int) bool {
+		if report.Jobs[i].Namespace != report.Jobs[j].Namespace {
+			return report.Jobs[i].Namespace < report.Jobs[j].Namespace
+		}
+		return report.Jobs[i].ID < report.Jobs[j].ID
+	})
+	sort.Slice(report.Allocations, func(i, j int) bool {
+		return report.Allocations[i].ID < report.Allocations[j].ID
+	})
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		return report.Nodes[i].ID < report.Nodes[j].ID
+	})
+	return report, nil
+}
+
+// allocUsage returns the resources allocated to the allocation between start
+// and end, or nil if it wasn't running within the window. Allocations are
+// considered running from their creation until their last update once they
+// are terminal.
+func allocUsage(alloc *structs.Allocation, start, end time.Time) *structs.AllocUsage {
+	from := time.Unix(0, alloc.CreateTime)
+	to := end
+	if alloc.TerminalStatus() {
+		to = time.Unix(0, alloc.ModifyTime)
+	}
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	usage := &structs.AllocUsage{
+		ID:           alloc.ID,
+		Namespace:    alloc.Namespace,
+		JobID:        alloc.JobID,
+		TaskGroup:    alloc.TaskGroup,
+		NodeID:       alloc.NodeID,
+		ClientStatus: alloc.ClientStatus,
+		Start:        from.UTC(),
+		End:          to.UTC(),
+	}
+	if alloc.AllocatedResources != nil || alloc.Resources != nil || alloc.TaskResources != nil {
+		resources := alloc.ComparableResources()
+		usage.CPUMHz = resources.Flattened.Cpu.CpuShares
+		usage.MemoryMB = resources.Flattened.Memory.MemoryMB
+	}
+
+	seconds := int64(to.Sub(from) / time.Second)
+	usage.CPUMHzSeconds = usage.CPUMHz * seconds
+	usage.MemoryMBSeconds = usage.MemoryMB * seconds
+	return usage
+}
+
+// usageWindow returns the window of a usage export request, defaulting the
+// end to now and the start to a day before the end.
+func usageWindow(args *structs.UsageExportRequest, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if args.End != 0 {
+		end = time.Unix(0, args.End)
+	}
+	start := end.Add(-defaultUsageWindow)
+	if args.Start != 0 {
+		start = time.Unix(0, args.Start)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start of the usage window must be before its end")
+	}
+	return start, end, nil
+}
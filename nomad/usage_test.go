@@ -0,0 +1,174 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocUsage(t *testing.T) {
+	t.Parallel()
+
+	end := time.Now()
+	start := end.Add(-time.Hour)
+
+	cases := []struct {
+		name     string
+		create   time.Time
+		modify   time.Time
+		status   string
+		expected time.Duration
+	}{
+		{
+			name:     "running through the window",
+			create:   start.Add(-time.Hour),
+			modify:   start.Add(-time.Hour),
+			status:   structs.AllocClientStatusRunning,
+			expected: time.Hour,
+		},
+		{
+			name:     "started within the window",
+			create:   end.Add(-10 * time.Minute),
+			modify:   end.Add(-10 * time.Minute),
+			status:   structs.AllocClientStatusRunning,
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "completed within the window",
+			create:   start.Add(-time.Hour),
+			modify:   start.Add(20 * time.Minute),
+			status:   structs.AllocClientStatusComplete,
+			expected: 20 * time.Minute,
+		},
+		{
+			name:   "completed before the window",
+			create: start.Add(-2 * time.Hour),
+			modify: start.Add(-time.Hour),
+			status: structs.AllocClientStatusComplete,
+		},
+		{
+			name:   "created after the window",
+			create: end.Add(time.Minute),
+			modify: end.Add(time.Minute),
+			status: structs.AllocClientStatusRunning,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			alloc := mock.Alloc()
+			alloc.ClientStatus = c.status
+			alloc.CreateTime = c.create.UnixNano()
+			alloc.ModifyTime = c.modify.UnixNano()
+
+			usage := allocUsage(alloc, start, end)
+			if c.expected == 0 {
+				require.Nil(t, usage)
+				return
+			}
+			require.NotNil(t, usage)
+
+			seconds := int64(c.expected / time.Second)
+			require.Equal(t, int64(500), usage.CPUMHz)
+			require.Equal(t, int64(256), usage.MemoryMB)
+			require.Equal(t, 500*seconds, usage.CPUMHzSeconds)
+			require.Equal(t, 256*seconds, usage.MemoryMBSeconds)
+		})
+	}
+}
+
+func TestUsageWindow(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	now := time.Now()
+
+	// Defaults to the last day
+	start, end, err := usageWindow(&structs.UsageExportRequest{}, now)
+	require.NoError(err)
+	require.Equal(now, end)
+	require.Equal(now.Add(-defaultUsageWindow), start)
+
+	// Explicit window
+	args := &structs.UsageExportRequest{
+		Start: now.Add(-time.Hour).UnixNano(),
+		End:   now.Add(-time.Minute).UnixNano(),
+	}
+	start, end, err = usageWindow(args, now)
+	require.NoError(err)
+	require.Equal(args.Start, start.UnixNano())
+	require.Equal(args.End, end.UnixNano())
+
+	// Start must be before the end
+	args.Start, args.End = args.End, args.Start
+	_, _, err = usageWindow(args, now)
+	require.Error(err)
+}
+
+func TestUsageReport(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s := state.TestStateStore(t)
+	node := mock.Node()
+	require.NoError(s.UpsertNode(1000, node))
+
+	job := mock.Job()
+	require.NoError(s.UpsertJob(1001, job))
+
+	end := time.Now()
+	start := end.Add(-time.Hour)
+
+	alloc1 := mock.Alloc()
+	alloc1.NodeID = node.ID
+	alloc1.Job = job
+	alloc1.JobID = job.ID
+	alloc1.CreateTime = start.Add(-time.Hour).UnixNano()
+
+	// An allocation of a purged job
+	alloc2 := mock.Alloc()
+	alloc2.NodeID = node.ID
+	alloc2.CreateTime = start.Add(-time.Hour).UnixNano()
+	alloc2.ModifyTime = start.Add(30 * time.Minute).UnixNano()
+	alloc2.ClientStatus = structs.AllocClientStatusComplete
+
+	// An allocation that stopped before the window
+	alloc3 := mock.Alloc()
+	alloc3.NodeID = node.ID
+	alloc3.Job = job
+	alloc3.JobID = job.ID
+	alloc3.CreateTime = start.Add(-2 * time.Hour).UnixNano()
+	alloc3.ModifyTime = start.Add(-time.Hour).UnixNano()
+	alloc3.ClientStatus = structs.AllocClientStatusFailed
+
+	require.NoError(s.UpsertAllocs(1002, []*structs.Allocation{alloc1, alloc2, alloc3}))
+
+	snap, err := s.Snapshot()
+	require.NoError(err)
+	report, err := usageReport(snap, start, end)
+	require.NoError(err)
+
+	require.Len(report.Nodes, 1)
+	require.Len(report.Jobs, 2)
+	require.Len(report.Allocations, 2)
+	require.Equal(2, report.Totals.Jobs)
+	require.Equal(2, report.Totals.Allocations)
+	require.Equal(1, report.Totals.Nodes)
+
+	jobs := make(map[string]*structs.JobUsage)
+	for _, j := range report.Jobs {
+		jobs[j.ID] = j
+	}
+	require.Equal(1, jobs[job.ID].Allocations)
+	require.Equal(int64(500*3600), jobs[job.ID].CPUMHzSeconds)
+	require.Equal(1, jobs[alloc2.JobID].Allocations)
+	require.Equal(int64(256*1800), jobs[alloc2.JobID].MemoryMBSeconds)
+
+	require.Equal(2, report.Nodes[0].Allocations)
+	require.Equal(int64(500*5400), report.Nodes[0].CPUMHzSeconds)
+	require.Equal(int64(500*5400), report.Totals.CPUMHzSeconds)
+	require.NotZero(report.Totals.CPUMHz)
+}
@@ -0,0 +1,256 @@
+package nomad
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables endpoint is used for manipulating the encrypted variables
+type Variables struct {
+	srv    *Server
+	logger log.Logger
+}
+
+// Upsert is used to create or update a variable
+func (v *Variables) Upsert(args *structs.VariablesUpsertRequest, reply *structs.VariablesUpsertResponse) error {
+	if done, err := v.srv.forward("Variables.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "upsert"}, time.Now())
+
+	if args.Variable == nil {
+		return fmt.Errorf("missing variable")
+	}
+
+	variable := args.Variable.Copy()
+	variable.Namespace = args.RequestNamespace()
+	if err := variable.Validate(); err != nil {
+		return err
+	}
+
+	// Check write permissions
+	if err := v.checkOperation(args.AuthToken, variable.Namespace, variable.Path, acl.VariablesCapabilityWrite, false); err != nil {
+		return err
+	}
+
+	// Encrypt the variable with the root key
+	key, err := v.srv.getOrCreateRootKey()
+	if err != nil {
+		return err
+	}
+
+	// The state store keeps the create time of existing variables
+	now := time.Now().UnixNano()
+	variable.CreateTime = now
+	variable.ModifyTime = now
+	encrypted, err := encryptVariable(key, variable)
+	if err != nil {
+		return err
+	}
+
+	// Update via Raft
+	req := &structs.VariablesEncryptedUpsertRequest{
+		Variable:     encrypted,
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := v.srv.raftApply(structs.VariableUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+
+	// Return the stored metadata
+	stored, err := v.srv.fsm.State().VariableByPath(nil, variable.Namespace, variable.Path)
+	if err != nil {
+		return err
+	}
+	if stored != nil {
+		meta := stored.VariableMetadata
+		reply.Variable = &meta
+	}
+	reply.Index = index
+	return nil
+}
+
+// Delete is used to delete a variable
+func (v *Variables) Delete(args *structs.VariablesDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Variables.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "delete"}, time.Now())
+
+	if args.Path == "" {
+		return fmt.Errorf("missing variable path")
+	}
+
+	// Check destroy permissions
+	if err := v.checkOperation(args.AuthToken, args.RequestNamespace(), args.Path, acl.VariablesCapabilityDestroy, false); err != nil {
+		return err
+	}
+
+	// Update via Raft
+	_, index, err := v.srv.raftApply(structs.VariableDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	reply.Index = index
+	return nil
+}
+
+// Read is used to read and decrypt a single variable
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward("Variables.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	if args.Path == "" {
+		return fmt.Errorf("missing variable path")
+	}
+
+	// Check read permissions. Nodes may read the variables of the jobs they
+	// are running.
+	ns := args.RequestNamespace()
+	if err := v.checkOperation(args.AuthToken, ns, args.Path, acl.VariablesCapabilityRead, true); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.VariableByPath(ws, ns, args.Path)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Data = nil
+			if out != nil {
+				if reply.Data, err = decryptVariable(state, out); err != nil {
+					return err
+				}
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the variables table
+				index, err := state.Index("variables")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// List is used to list the metadata of the variables of a namespace
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward("Variables.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	aclObj, err := v.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	ns := args.RequestNamespace()
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.VariablesByPathPrefix(ws, ns, args.QueryOptions.Prefix)
+			if err != nil {
+				return err
+			}
+
+			// Only return the variables that the token may list
+			reply.Data = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				variable := raw.(*structs.VariableEncrypted)
+				if aclObj != nil && !aclObj.AllowVariableOperation(ns, variable.Path, acl.VariablesCapabilityList) {
+					continue
+				}
+				meta := variable.VariableMetadata
+				reply.Data = append(reply.Data, &meta)
+			}
+
+			// Use the last index that affected the variables table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// checkOperation checks that the token may run the operation on the variable
+// at the path. If allowNode is set, the token may also be the secret ID of a
+// node, which is allowed to read the variables below the path of the jobs
+// that have allocations running on the node.
+func (v *Variables) checkOperation(token, ns, path, op string, allowNode bool) error {
+	aclObj, err := v.srv.ResolveToken(token)
+	if err == nil {
+		if aclObj != nil && !aclObj.AllowVariableOperation(ns, path, op) {
+			return structs.ErrPermissionDenied
+		}
+		return nil
+	}
+
+	// If ResolveToken had an unexpected error return that
+	if err != structs.ErrTokenNotFound || !allowNode {
+		return err
+	}
+
+	// Attempt to lookup the token as a Node.SecretID
+	snap, stateErr := v.srv.fsm.State().Snapshot()
+	if stateErr != nil {
+		return stateErr
+	}
+	node, stateErr := snap.NodeBySecretID(nil, token)
+	if stateErr != nil {
+		var merr multierror.Error
+		merr.Errors = append(merr.Errors, err, stateErr)
+		return merr.ErrorOrNil()
+	}
+
+	// Not a node or a valid ACL token
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	allocs, err := snap.AllocsByNode(nil, node.ID)
+	if err != nil {
+		return err
+	}
+	for _, alloc := range allocs {
+		if alloc.Namespace != ns || alloc.TerminalStatus() {
+			continue
+		}
+
+		jobPath := structs.VariablesJobsPrefix + "/" + alloc.JobID
+		if path == jobPath || strings.HasPrefix(path, jobPath+"/") {
+			return nil
+		}
+	}
+	return structs.ErrPermissionDenied
+}
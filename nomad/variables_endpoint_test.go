@@ -0,0 +1,221 @@
+package nomad
+
+import (
+	"strings"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariablesEndpoint_UpsertReadListDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.Build = "0.9.0+unittest"
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Invalid paths are rejected
+	req := &structs.VariablesUpsertRequest{
+		Variable: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "nomad/other"},
+			Items:            map[string]string{"password": "hunter2"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.VariablesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "reserved prefix")
+
+	// Create a variable
+	req.Variable.Path = "project/db"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	require.NotZero(resp.Index)
+	require.Equal(structs.DefaultNamespace, resp.Variable.Namespace)
+	require.Equal(resp.Index, resp.Variable.CreateIndex)
+
+	// The variable is stored encrypted
+	stored, err := s1.fsm.State().VariableByPath(nil, structs.DefaultNamespace, "project/db")
+	require.NoError(err)
+	require.NotNil(stored)
+	require.NotEmpty(stored.KeyID)
+	require.False(strings.Contains(string(stored.Data), "hunter2"))
+
+	// Read the variable back
+	readReq := &structs.VariablesReadRequest{
+		Path:         "project/db",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.NotNil(readResp.Data)
+	require.Equal(map[string]string{"password": "hunter2"}, readResp.Data.Items)
+	require.Equal(resp.Index, readResp.Index)
+
+	// Reading a missing variable returns no data
+	readReq.Path = "project/missing"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.Nil(readResp.Data)
+
+	// Create another variable and list by prefix
+	req.Variable.Path = "other/db"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+
+	listReq := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Prefix: "project/"},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", listReq, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal("project/db", listResp.Data[0].Path)
+
+	// Delete the variable
+	delReq := &structs.VariablesDeleteRequest{
+		Path:         "project/db",
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Delete", delReq, &delResp))
+
+	readReq.Path = "project/db"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.Nil(readResp.Data)
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Build = "0.9.0+unittest"
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create variables with the management token
+	for _, path := range []string{"project/db", "other/db"} {
+		req := &structs.VariablesUpsertRequest{
+			Variable: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            map[string]string{"password": "hunter2"},
+			},
+			WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+		}
+		var resp structs.VariablesUpsertResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	}
+
+	// Create a token that can only access the project variables
+	policy := `namespace "default" {
+		variables {
+			path "project/*" {
+				capabilities = ["list", "read"]
+			}
+		}
+	}`
+	token := mock.CreatePolicyAndToken(t, s1.State(), 1001, "project", policy)
+
+	// Writing is denied
+	req := &structs.VariablesUpsertRequest{
+		Variable: &structs.VariableDecrypted{
+			VariableMetadata: structs.VariableMetadata{Path: "project/db"},
+			Items:            map[string]string{"password": "changed"},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}
+	var resp structs.VariablesUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Reading a project variable is allowed
+	readReq := &structs.VariablesReadRequest{
+		Path:         "project/db",
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.Equal("hunter2", readResp.Data.Items["password"])
+
+	// Reading another variable is denied
+	readReq.Path = "other/db"
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Listing only returns the project variables
+	listReq := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", listReq, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal("project/db", listResp.Data[0].Path)
+
+	// Deleting is denied
+	delReq := &structs.VariablesDeleteRequest{
+		Path:         "project/db",
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}
+	var delResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Delete", delReq, &delResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
+
+func TestVariablesEndpoint_Read_Node(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Build = "0.9.0+unittest"
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a node running an allocation of a job
+	state := s1.fsm.State()
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	jobPath := structs.VariablesJobsPrefix + "/" + alloc.JobID
+	for _, path := range []string{jobPath + "/web", "project/db"} {
+		req := &structs.VariablesUpsertRequest{
+			Variable: &structs.VariableDecrypted{
+				VariableMetadata: structs.VariableMetadata{Path: path},
+				Items:            map[string]string{"password": "hunter2"},
+			},
+			WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+		}
+		var resp structs.VariablesUpsertResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	}
+
+	// The node can read the variables of the job
+	readReq := &structs.VariablesReadRequest{
+		Path:         jobPath + "/web",
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: node.SecretID},
+	}
+	var readResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp))
+	require.Equal("hunter2", readResp.Data.Items["password"])
+
+	// The node can not read other variables
+	readReq.Path = "project/db"
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Unknown tokens are rejected
+	readReq.AuthToken = mock.Node().SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", readReq, &readResp)
+	require.EqualError(err, structs.ErrTokenNotFound.Error())
+}
@@ -247,7 +247,7 @@ func NewVaultClient(c *config.VaultConfig, logger log.Logger, purgeFn PurgeVault
 		tomb:     &tomb.Tomb{},
 	}
 
-	if v.config.IsEnabled() {
+	if derivesVaultTokens(v.config) {
 		if err := v.buildClient(); err != nil {
 			return nil, err
 		}
@@ -346,7 +346,7 @@ func (v *vaultClient) SetConfig(config *config.VaultConfig) error {
 	v.config = config
 
 	// Check if we should relaunch
-	if v.config.IsEnabled() {
+	if derivesVaultTokens(v.config) {
 		// Rebuild the client
 		if err := v.buildClient(); err != nil {
 			return err
@@ -883,7 +883,14 @@ func (v *vaultClient) ConnectionEstablished() (bool, error) {
 func (v *vaultClient) Enabled() bool {
 	v.l.Lock()
 	defer v.l.Unlock()
-	return v.config.IsEnabled()
+	return derivesVaultTokens(v.config)
+}
+
+// derivesVaultTokens returns whether the servers derive the Vault tokens of
+// tasks. Servers don't use Vault when tasks log in with their workload
+// identity.
+func derivesVaultTokens(c *config.VaultConfig) bool {
+	return c.IsEnabled() && !c.UsesIdentity()
 }
 
 // Active returns whether the client is active
@@ -943,17 +950,23 @@ func (v *vaultClient) CreateToken(ctx context.Context, a *structs.Allocation, ta
 		return nil, err
 	}
 
+	// Tokens in a Vault namespace are created by setting the namespace on
+	// the request
+	auth, err := v.tokenAuth(taskVault.Namespace)
+	if err != nil {
+		return nil, structs.NewRecoverableError(fmt.Errorf("failed to create Vault client for namespace %q: %v", taskVault.Namespace, err), true)
+	}
+
 	// Make the request and switch depending on whether we are using a root
 	// token or a role based token
 	var secret *vapi.Secret
-	var err error
 	role := v.getRole()
 	if v.tokenData.Root && role == "" {
 		req.Period = v.childTTL
-		secret, err = v.auth.Create(req)
+		secret, err = auth.Create(req)
 	} else {
 		// Make the token using the role
-		secret, err = v.auth.CreateWithRole(req, v.getRole())
+		secret, err = auth.CreateWithRole(req, v.getRole())
 	}
 
 	// Determine whether it is unrecoverable
@@ -985,6 +998,25 @@ func (v *vaultClient) CreateToken(ctx context.Context, a *structs.Allocation, ta
 	return secret, nil
 }
 
+// tokenAuth returns the token API to use for tokens in the given Vault
+// namespace. The empty namespace uses the namespace of the servers' token.
+func (v *vaultClient) tokenAuth(namespace string) (*vapi.TokenAuth, error) {
+	if namespace == "" {
+		return v.auth, nil
+	}
+
+	// Clone the client so the namespace header isn't sent with the servers'
+	// own requests
+	client, err := v.client.Clone()
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(v.client.Token())
+	client.SetWrappingLookupFunc(v.getWrappingFn())
+	client.SetNamespace(namespace)
+	return client.Auth().Token(), nil
+}
+
 // LookupToken takes a Vault token and does a lookup against Vault. The call is
 // rate limited and may be canceled with passed context.
 func (v *vaultClient) LookupToken(ctx context.Context, token string) (*vapi.Secret, error) {
@@ -1139,7 +1171,11 @@ func (v *vaultClient) parallelRevoke(ctx context.Context, accessors []*structs.V
 						return nil
 					}
 
-					if err := v.auth.RevokeAccessor(va.Accessor); err != nil {
+					auth, err := v.tokenAuth(va.Namespace)
+					if err != nil {
+						return fmt.Errorf("failed to create Vault client for namespace %q: %v", va.Namespace, err)
+					}
+					if err := auth.RevokeAccessor(va.Accessor); err != nil {
 						return fmt.Errorf("failed to revoke token (alloc: %q, node: %q, task: %q): %v", va.AllocID, va.NodeID, va.Task, err)
 					}
 				case <-pCtx.Done():
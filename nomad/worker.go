@@ -63,6 +63,15 @@ type Worker struct {
 
 	failures uint
 
+	// pinned is the scheduler the worker is pinned to. Pinned workers only
+	// dequeue evaluations of their scheduler, while the others dequeue
+	// evaluations of all the enabled schedulers.
+	pinned string
+
+	// stopCh is closed to stop a worker started by the autoscaler once it
+	// completes its current evaluation.
+	stopCh chan struct{}
+
 	evalToken string
 
 	// snapshotIndex is the index of the snapshot in which the scheduler was
@@ -73,14 +82,41 @@ type Worker struct {
 
 // NewWorker starts a new worker associated with the given server
 func NewWorker(srv *Server) (*Worker, error) {
+	return newWorker(srv, ""), nil
+}
+
+// newWorker starts a new worker pinned to the given scheduler, or dequeuing
+// evaluations of all the enabled schedulers if it is empty.
+func newWorker(srv *Server, pinned string) *Worker {
 	w := &Worker{
 		srv:    srv,
 		logger: srv.logger.ResetNamed("worker"),
 		start:  time.Now(),
+		pinned: pinned,
+		stopCh: make(chan struct{}),
+	}
+	if pinned != "" {
+		w.logger = w.logger.With("scheduler", pinned)
 	}
 	w.pauseCond = sync.NewCond(&w.pauseLock)
 	go w.run()
-	return w, nil
+	return w
+}
+
+// Stop stops the worker once it completes its current evaluation.
+func (w *Worker) Stop() {
+	close(w.stopCh)
+}
+
+// isStopped returns whether the server is shutting down or the worker was
+// stopped.
+func (w *Worker) isStopped() bool {
+	select {
+	case <-w.stopCh:
+		return true
+	default:
+		return w.srv.IsShutdown()
+	}
 }
 
 // SetPause is used to pause or unpause a worker
@@ -105,6 +141,11 @@ func (w *Worker) checkPaused() {
 // run is the long-lived goroutine which is used to run the worker
 func (w *Worker) run() {
 	for {
+		// Check if the worker was stopped
+		if w.isStopped() {
+			return
+		}
+
 		// Dequeue a pending evaluation
 		eval, token, waitIndex, shutdown := w.dequeueEvaluation(dequeueTimeout)
 		if shutdown {
@@ -141,9 +182,14 @@ func (w *Worker) run() {
 // This blocks until an evaluation is available or a timeout is reached.
 func (w *Worker) dequeueEvaluation(timeout time.Duration) (
 	eval *structs.Evaluation, token string, waitIndex uint64, shutdown bool) {
+	schedulers := w.srv.config.EnabledSchedulers
+	if w.pinned != "" {
+		schedulers = []string{w.pinned}
+	}
+
 	// Setup the request
 	req := structs.EvalDequeueRequest{
-		Schedulers:       w.srv.config.EnabledSchedulers,
+		Schedulers:       schedulers,
 		Timeout:          timeout,
 		SchedulerVersion: scheduler.SchedulerVersion,
 		WriteRequest: structs.WriteRequest{
@@ -180,6 +226,12 @@ REQ:
 	}
 	w.backoffReset()
 
+	// Report the depth of the broker to the autoscaler. Only the shared
+	// workers report it since pinned workers only see their scheduler.
+	if w.pinned == "" && w.srv.workerAutoscaler != nil {
+		w.srv.workerAutoscaler.observe(resp.Ready)
+	}
+
 	// Check if we got a response
 	if resp.Eval != nil {
 		w.logger.Debug("dequeued evaluation", "eval_id", resp.Eval.ID)
@@ -187,7 +239,7 @@ REQ:
 	}
 
 	// Check for potential shutdown
-	if w.srv.IsShutdown() {
+	if w.isStopped() {
 		return nil, "", 0, true
 	}
 	goto REQ
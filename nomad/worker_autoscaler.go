@@ -0,0 +1,86 @@
+package nomad
+
+import (
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// workerAutoscaler starts additional shared scheduling workers while
+// evaluations are waiting in the broker and stops them once it is drained.
+// The depth of the broker is reported by the shared workers with each
+// dequeue, so followers scale their workers without querying the leader.
+type workerAutoscaler struct {
+	srv    *Server
+	logger log.Logger
+	config *config.SchedulerWorkersConfig
+
+	// ready is the number of ready evaluations last reported by a worker. It
+	// is accessed atomically.
+	ready int64
+
+	// workers are the workers started by the autoscaler. It is only accessed
+	// by the scaling goroutine.
+	workers []*Worker
+}
+
+// newWorkerAutoscaler returns a new worker autoscaler for the server.
+func newWorkerAutoscaler(srv *Server) *workerAutoscaler {
+	return &workerAutoscaler{
+		srv:    srv,
+		logger: srv.logger.Named("worker_autoscaler"),
+		config: srv.config.SchedulerWorkers,
+	}
+}
+
+// observe records the number of ready evaluations reported by a worker.
+func (a *workerAutoscaler) observe(ready int) {
+	atomic.StoreInt64(&a.ready, int64(ready))
+}
+
+// maxWorkers returns the maximum number of shared workers.
+func (a *workerAutoscaler) maxWorkers() int {
+	if a.config.MaxWorkers != 0 {
+		return a.config.MaxWorkers
+	}
+	return 2 * a.srv.config.NumSchedulers
+}
+
+// run adjusts the number of workers until the stop channel is closed.
+func (a *workerAutoscaler) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(a.config.ScaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			a.scale()
+		}
+	}
+}
+
+// scale starts or stops a single worker based on the last reported depth of
+// the broker. Workers are added one at a time so that a short burst of
+// evaluations doesn't start more workers than the server has cores for.
+func (a *workerAutoscaler) scale() {
+	ready := int(atomic.LoadInt64(&a.ready))
+	shared := a.srv.config.NumSchedulers + len(a.workers)
+
+	switch {
+	case ready > shared*a.config.ReadyPerWorker && shared < a.maxWorkers():
+		a.workers = append(a.workers, newWorker(a.srv, ""))
+		a.logger.Debug("started scheduling worker", "ready", ready, "num_workers", shared+1)
+	case ready == 0 && len(a.workers) > 0:
+		last := len(a.workers) - 1
+		a.workers[last].Stop()
+		a.workers = a.workers[:last]
+		a.logger.Debug("stopped scheduling worker", "num_workers", shared-1)
+	}
+
+	metrics.SetGauge([]string{"nomad", "worker", "autoscaled"}, float32(len(a.workers)))
+}
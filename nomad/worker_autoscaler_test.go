@@ -0,0 +1,54 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerAutoscaler_scale(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 1
+		c.SchedulerWorkers = &config.SchedulerWorkersConfig{
+			Autoscale:      helper.BoolToPtr(true),
+			MaxWorkers:     3,
+			ReadyPerWorker: 2,
+			ScaleInterval:  time.Hour,
+		}
+	})
+
+	// Shut the server down so the workers started by the autoscaler exit
+	// without reporting the depth of its broker
+	s1.Shutdown()
+	a := s1.workerAutoscaler
+	require.NotNil(a)
+
+	// Nothing is started while the workers keep up
+	a.observe(2)
+	a.scale()
+	require.Len(a.workers, 0)
+
+	// A worker is started on each interval until the maximum is reached
+	a.observe(10)
+	a.scale()
+	require.Len(a.workers, 1)
+	a.scale()
+	require.Len(a.workers, 2)
+	a.scale()
+	require.Len(a.workers, 2)
+
+	// Workers are stopped one at a time once the broker is drained
+	a.observe(0)
+	a.scale()
+	require.Len(a.workers, 1)
+	a.scale()
+	require.Len(a.workers, 0)
+	a.scale()
+	require.Len(a.workers, 0)
+}
@@ -225,6 +225,39 @@ func TestWorker_dequeueEvaluation_shutdown(t *testing.T) {
 	}
 }
 
+func TestWorker_dequeueEvaluation_pinned(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.EnabledSchedulers = []string{structs.JobTypeService, structs.JobTypeBatch}
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a batch evaluation with a higher priority than the service one
+	eval1 := mock.Eval()
+	eval1.Type = structs.JobTypeBatch
+	eval1.Priority = 100
+	eval2 := mock.Eval()
+	s1.evalBroker.Enqueue(eval1)
+	s1.evalBroker.Enqueue(eval2)
+
+	// Create a worker pinned to the service scheduler
+	w := &Worker{srv: s1, logger: s1.logger, pinned: structs.JobTypeService}
+
+	// The worker should only dequeue the service evaluation
+	eval, token, _, shutdown := w.dequeueEvaluation(10 * time.Millisecond)
+	if shutdown {
+		t.Fatalf("should not shutdown")
+	}
+	if token == "" {
+		t.Fatalf("should get token")
+	}
+	if !reflect.DeepEqual(eval, eval2) {
+		t.Fatalf("bad: %#v %#v", eval, eval2)
+	}
+}
+
 func TestWorker_sendAck(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
@@ -0,0 +1,139 @@
+package nomad
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// writeLimiterPruneInterval is how often the buckets of the ACL tokens
+	// and namespaces that stopped making requests are dropped.
+	writeLimiterPruneInterval = time.Minute
+)
+
+// writeLimiter limits the rate of the write RPCs, such as job registrations,
+// of each ACL token and of each namespace with token buckets. A nil
+// writeLimiter allows all the requests.
+type writeLimiter struct {
+	tokens     *keyedLimiter
+	namespaces *keyedLimiter
+}
+
+// newWriteLimiter returns the write limiter configured by conf, or nil if the
+// rate of the write requests isn't limited.
+func newWriteLimiter(conf *config.WriteRateLimitConfig) *writeLimiter {
+	if conf == nil || (conf.TokenRate <= 0 && conf.NamespaceRate <= 0) {
+		return nil
+	}
+
+	return &writeLimiter{
+		tokens:     newKeyedLimiter(conf.TokenRate, conf.TokenBurst),
+		namespaces: newKeyedLimiter(conf.NamespaceRate, conf.NamespaceBurst),
+	}
+}
+
+// allow returns ErrWriteRateLimited if the ACL token or the namespace of a
+// write request exceeded its rate limit. A rejected request isn't counted
+// against either bucket.
+func (w *writeLimiter) allow(token, namespace string) error {
+	if w == nil {
+		return nil
+	}
+
+	now := time.Now()
+	tokenRes, ok := w.tokens.reserve(token, now)
+	if !ok {
+		return structs.ErrWriteRateLimited
+	}
+	if _, ok := w.namespaces.reserve(namespace, now); !ok {
+		if tokenRes != nil {
+			tokenRes.CancelAt(now)
+		}
+		return structs.ErrWriteRateLimited
+	}
+	return nil
+}
+
+// keyedLimiter holds a token bucket for each key, created when the key makes
+// its first request. A nil keyedLimiter allows all the requests.
+type keyedLimiter struct {
+	limit rate.Limit
+	burst int
+
+	// lastPrune is when the full buckets were last dropped
+	lastPrune time.Time
+
+	buckets map[string]*keyedBucket
+	l       sync.Mutex
+}
+
+// keyedBucket is the token bucket of a key and when it was last used.
+type keyedBucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// newKeyedLimiter returns a limiter allowing each key to make r requests per
+// second, with bursts of up to burst requests. The burst defaults to r,
+// rounded up. It returns nil if r isn't positive.
+func newKeyedLimiter(r float64, burst int) *keyedLimiter {
+	if r <= 0 {
+		return nil
+	}
+	if burst == 0 {
+		burst = int(math.Ceil(r))
+	}
+
+	return &keyedLimiter{
+		limit:   rate.Limit(r),
+		burst:   burst,
+		buckets: make(map[string]*keyedBucket),
+	}
+}
+
+// reserve takes a token from the bucket of the key and returns whether the
+// request is allowed at the given time. The returned reservation can be
+// cancelled to give the token back, and is nil if the rate isn't limited.
+func (k *keyedLimiter) reserve(key string, now time.Time) (*rate.Reservation, bool) {
+	if k == nil {
+		return nil, true
+	}
+
+	k.l.Lock()
+	defer k.l.Unlock()
+
+	if now.Sub(k.lastPrune) >= writeLimiterPruneInterval {
+		k.prune(now)
+	}
+
+	bucket, ok := k.buckets[key]
+	if !ok {
+		bucket = &keyedBucket{limiter: rate.NewLimiter(k.limit, k.burst)}
+		k.buckets[key] = bucket
+	}
+	bucket.lastUsed = now
+
+	r := bucket.limiter.ReserveN(now, 1)
+	if !r.OK() || r.DelayFrom(now) > 0 {
+		r.CancelAt(now)
+		return nil, false
+	}
+	return r, true
+}
+
+// prune drops the buckets that refilled since they were last used, as they
+// allow the same requests as new buckets. The lock must be held.
+func (k *keyedLimiter) prune(now time.Time) {
+	refill := time.Duration(float64(k.burst) / float64(k.limit) * float64(time.Second))
+	for key, bucket := range k.buckets {
+		if now.Sub(bucket.lastUsed) >= refill {
+			delete(k.buckets, key)
+		}
+	}
+	k.lastPrune = now
+}
@@ -0,0 +1,102 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLimiter_Allow(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Nil limiters allow all the requests
+	var nilLimiter *writeLimiter
+	require.NoError(nilLimiter.allow("token", "default"))
+	require.Nil(newWriteLimiter(&config.WriteRateLimitConfig{}))
+
+	l := newWriteLimiter(&config.WriteRateLimitConfig{
+		TokenRate:      0.001,
+		TokenBurst:     1,
+		NamespaceRate:  0.001,
+		NamespaceBurst: 2,
+	})
+
+	// Each token has its own bucket
+	require.NoError(l.allow("a", "default"))
+	require.True(structs.IsErrWriteRateLimited(l.allow("a", "default")))
+	require.NoError(l.allow("b", "default"))
+
+	// The namespace bucket is shared by the tokens. The token rejected by
+	// the namespace doesn't lose its request.
+	require.True(structs.IsErrWriteRateLimited(l.allow("c", "default")))
+	require.NoError(l.allow("c", "other"))
+}
+
+func TestKeyedLimiter_Prune(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	k := newKeyedLimiter(1, 2)
+	now := time.Now()
+	_, ok := k.reserve("a", now)
+	require.True(ok)
+	_, ok = k.reserve("b", now.Add(writeLimiterPruneInterval-time.Second))
+	require.True(ok)
+
+	// Buckets refilled since their last use are dropped
+	_, ok = k.reserve("c", now.Add(writeLimiterPruneInterval))
+	require.True(ok)
+	require.Len(k.buckets, 2)
+	require.Contains(k.buckets, "b")
+	require.Contains(k.buckets, "c")
+}
+
+func TestJobEndpoint_Register_WriteRateLimit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.WriteRateLimit = &config.WriteRateLimitConfig{
+			NamespaceRate:  0.001,
+			NamespaceBurst: 1,
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Registering again in the namespace exceeds its rate limit
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.True(structs.IsErrWriteRateLimited(err), "unexpected error: %v", err)
+
+	// So does deregistering
+	dereg := &structs.JobDeregisterRequest{
+		JobID: job.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Deregister", dereg, &deregResp)
+	require.True(structs.IsErrWriteRateLimited(err), "unexpected error: %v", err)
+}
@@ -125,6 +125,13 @@ type Device struct {
 
 	// HwLocality captures hardware locality information for the device.
 	HwLocality *DeviceLocality
+
+	// Slices is the number of tasks that can be allocated the device at the
+	// same time, such as the time-sliced fractions of a GPU. Devices with zero
+	// or one slice are allocated exclusively. Partitions of a device that are
+	// isolated from each other, such as NVIDIA MIG instances, should instead
+	// be exposed as devices of their own.
+	Slices uint32
 }
 
 // Validate validates that the device is valid
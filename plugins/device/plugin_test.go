@@ -192,6 +192,13 @@ func TestDevicePlugin_Fingerprint(t *testing.T) {
 			Vendor: "nvidia",
 			Type:   DeviceTypeGPU,
 			Name:   "foo",
+			Devices: []*Device{
+				{
+					ID:      "1",
+					Healthy: true,
+					Slices:  4,
+				},
+			},
 			Attributes: map[string]*psstructs.Attribute{
 				"memory": {
 					Int:  helper.Int64ToPtr(4),
@@ -188,10 +188,14 @@ type DetectedDevice struct {
 	HealthDescription string `protobuf:"bytes,3,opt,name=health_description,json=healthDescription,proto3" json:"health_description,omitempty"`
 	// hw_locality is optionally set to expose hardware locality information for
 	// more optimal placement decisions.
-	HwLocality           *DeviceLocality `protobuf:"bytes,4,opt,name=hw_locality,json=hwLocality,proto3" json:"hw_locality,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	HwLocality *DeviceLocality `protobuf:"bytes,4,opt,name=hw_locality,json=hwLocality,proto3" json:"hw_locality,omitempty"`
+	// slices is the number of tasks that can be allocated the device at the
+	// same time, such as the time-sliced fractions of a GPU. Devices with
+	// zero or one slice are allocated exclusively.
+	Slices               uint32   `protobuf:"varint,5,opt,name=slices,proto3" json:"slices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *DetectedDevice) Reset()         { *m = DetectedDevice{} }
@@ -246,6 +250,13 @@ func (m *DetectedDevice) GetHwLocality() *DeviceLocality {
 	return nil
 }
 
+func (m *DetectedDevice) GetSlices() uint32 {
+	if m != nil {
+		return m.Slices
+	}
+	return 0
+}
+
 // DeviceLocality is used to expose HW locality information about a device.
 type DeviceLocality struct {
 	// pci_bus_id is the PCI bus ID for the device. If reported, it
@@ -988,67 +999,67 @@ func init() {
 }
 
 var fileDescriptor_device_a4d1cccedbd8401c = []byte{
-	// 979 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xef, 0x8e, 0x1b, 0x35,
-	0x10, 0x27, 0xb9, 0xcb, 0x25, 0x99, 0xdc, 0x5d, 0x8b, 0x7b, 0x42, 0x61, 0x81, 0xf6, 0x58, 0x09,
-	0xa9, 0x02, 0xba, 0x29, 0x29, 0x12, 0x15, 0x08, 0xa4, 0xf6, 0x52, 0xee, 0xc2, 0x9f, 0x5e, 0xe5,
-	0x56, 0x48, 0x2d, 0x12, 0x2b, 0x67, 0xd7, 0x64, 0xdd, 0xee, 0xda, 0x8b, 0xed, 0x4d, 0x15, 0x3e,
-	0xf1, 0x38, 0x7c, 0xe1, 0x05, 0x78, 0x18, 0x3e, 0xf0, 0x24, 0x68, 0x6d, 0x6f, 0xb2, 0xf7, 0xa7,
-	0x24, 0x69, 0x3f, 0xad, 0x3d, 0x33, 0xbf, 0x99, 0xf1, 0xcc, 0xcf, 0xe3, 0x85, 0x0f, 0xf3, 0xb4,
-	0x98, 0x32, 0xae, 0x06, 0x31, 0x9d, 0xb1, 0x88, 0x0e, 0x72, 0x29, 0xb4, 0x70, 0x9b, 0xc0, 0x6c,
-	0xd0, 0xf5, 0x84, 0xa8, 0x84, 0x45, 0x42, 0xe6, 0x01, 0x17, 0x19, 0x89, 0x03, 0x07, 0x09, 0xac,
-	0x95, 0x77, 0x63, 0x2a, 0xc4, 0x34, 0x75, 0xd0, 0x49, 0xf1, 0xeb, 0x40, 0xb3, 0x8c, 0x2a, 0x4d,
-	0xb2, 0xdc, 0x3a, 0xf0, 0xae, 0x9f, 0x37, 0x88, 0x0b, 0x49, 0x34, 0x13, 0xdc, 0xe9, 0x8f, 0xa7,
-	0x4c, 0x27, 0xc5, 0x24, 0x88, 0x44, 0x36, 0x58, 0xc4, 0x1a, 0x98, 0x58, 0x83, 0x2a, 0x3d, 0x95,
-	0x10, 0x49, 0xe3, 0x81, 0xd2, 0xb2, 0x88, 0xb4, 0x72, 0x69, 0x12, 0xad, 0x25, 0x9b, 0x14, 0xda,
-	0x65, 0xea, 0x1d, 0xbd, 0xae, 0x23, 0xa5, 0x89, 0x56, 0xd6, 0x89, 0x7f, 0x00, 0xe8, 0x5b, 0xc6,
-	0xa7, 0x54, 0xe6, 0x92, 0x71, 0x8d, 0xe9, 0x6f, 0x05, 0x55, 0xda, 0xa7, 0x70, 0xed, 0x8c, 0x54,
-	0xe5, 0x82, 0x2b, 0x8a, 0x1e, 0xc2, 0xae, 0xad, 0x42, 0x38, 0x95, 0xa2, 0xc8, 0xfb, 0x8d, 0xc3,
-	0xad, 0x9b, 0xbd, 0xe1, 0x27, 0xc1, 0xff, 0x97, 0x2c, 0x18, 0x99, 0xcf, 0x71, 0x09, 0xc1, 0xbd,
-	0x78, 0xb9, 0xf1, 0xff, 0xd8, 0x82, 0x5e, 0x4d, 0x89, 0xde, 0x81, 0x9d, 0x19, 0xe5, 0xb1, 0x90,
-	0xfd, 0xc6, 0x61, 0xe3, 0x66, 0x17, 0xbb, 0x1d, 0xba, 0x01, 0x0e, 0x16, 0xea, 0x79, 0x4e, 0xfb,
-	0x4d, 0xa3, 0x04, 0x2b, 0x7a, 0x32, 0xcf, 0x69, 0xcd, 0x80, 0x93, 0x8c, 0xf6, 0xb7, 0xea, 0x06,
-	0x0f, 0x49, 0x46, 0xd1, 0x09, 0xb4, 0xed, 0x4e, 0xf5, 0xb7, 0x4d, 0xd2, 0xc1, 0xea, 0xa4, 0x35,
-	0x8d, 0x34, 0x8d, 0x6d, 0x7e, 0xb8, 0x82, 0xa3, 0x9f, 0x01, 0x16, 0x8d, 0x50, 0xfd, 0x96, 0x71,
-	0xf6, 0xd5, 0x06, 0x15, 0x08, 0xee, 0x2d, 0xd0, 0x0f, 0xb8, 0x96, 0x73, 0x5c, 0x73, 0xe7, 0xe5,
-	0x70, 0xe5, 0x9c, 0x1a, 0x5d, 0x85, 0xad, 0x17, 0x74, 0xee, 0x0a, 0x52, 0x2e, 0xd1, 0x31, 0xb4,
-	0x66, 0x24, 0x2d, 0x6c, 0x1d, 0x7a, 0xc3, 0xcf, 0x5e, 0x19, 0xdc, 0x36, 0x3f, 0x70, 0xcd, 0x5f,
-	0x06, 0xc6, 0x16, 0xff, 0x65, 0xf3, 0x6e, 0xc3, 0xff, 0xbb, 0x01, 0xfb, 0x67, 0x8f, 0x8a, 0xf6,
-	0xa1, 0x39, 0x1e, 0xb9, 0x80, 0xcd, 0xf1, 0x08, 0xf5, 0xa1, 0x9d, 0x50, 0x92, 0xea, 0x64, 0x6e,
-	0x22, 0x76, 0x70, 0xb5, 0x45, 0xb7, 0x00, 0xd9, 0x65, 0x18, 0x53, 0x15, 0x49, 0x96, 0x97, 0x34,
-	0x77, 0xd5, 0x7f, 0xdb, 0x6a, 0x46, 0x4b, 0x05, 0x3a, 0x85, 0x5e, 0xf2, 0x32, 0x4c, 0x45, 0x44,
-	0x52, 0xa6, 0xe7, 0xfd, 0x6d, 0x93, 0x7e, 0xb0, 0x5e, 0xed, 0x7e, 0x70, 0x28, 0x0c, 0xc9, 0xcb,
-	0x6a, 0xed, 0x07, 0x65, 0xee, 0x75, 0x2d, 0x7a, 0x1f, 0x20, 0x8f, 0x58, 0x38, 0x29, 0x54, 0xc8,
-	0x62, 0x77, 0x86, 0x4e, 0x1e, 0xb1, 0xfb, 0x85, 0x1a, 0xc7, 0xfe, 0x00, 0xf6, 0x31, 0x55, 0x54,
-	0xce, 0xa8, 0x23, 0x3a, 0xfa, 0x00, 0x1c, 0x4b, 0x42, 0x16, 0x2b, 0xc3, 0xe7, 0x2e, 0xee, 0x5a,
-	0xc9, 0x38, 0x56, 0x7e, 0x0a, 0x57, 0x16, 0x00, 0x77, 0x07, 0x9e, 0xc2, 0x5e, 0x24, 0xb8, 0x26,
-	0x8c, 0x53, 0x19, 0x4a, 0xaa, 0x4c, 0x90, 0xde, 0xf0, 0xf3, 0x55, 0xc7, 0x38, 0xaa, 0x40, 0xd6,
-	0xa1, 0x99, 0x08, 0x78, 0x37, 0xaa, 0x49, 0xfd, 0x3f, 0x9b, 0x70, 0x70, 0x99, 0x19, 0xc2, 0xb0,
-	0x4d, 0xf9, 0x4c, 0xb9, 0xfb, 0xf6, 0xcd, 0xeb, 0x84, 0x0a, 0x1e, 0xf0, 0x99, 0x23, 0x9c, 0xf1,
-	0x85, 0xbe, 0x86, 0x9d, 0x4c, 0x14, 0x5c, 0xab, 0x7e, 0xd3, 0x78, 0xfd, 0x68, 0x95, 0xd7, 0x1f,
-	0x4b, 0x6b, 0xec, 0x40, 0x68, 0xb4, 0xbc, 0x50, 0x5b, 0x06, 0xff, 0xf1, 0x7a, 0x7d, 0x7c, 0x9c,
-	0xd3, 0x68, 0x71, 0x99, 0xbc, 0x2f, 0xa0, 0xbb, 0xc8, 0xeb, 0x12, 0xa6, 0x1f, 0xd4, 0x99, 0xde,
-	0xad, 0xd3, 0xf6, 0x17, 0x68, 0x99, 0x7c, 0xd0, 0x7b, 0xd0, 0xd5, 0x44, 0xbd, 0x08, 0x73, 0xa2,
-	0x93, 0xaa, 0xdf, 0xa5, 0xe0, 0x11, 0xd1, 0x49, 0xa9, 0x4c, 0x84, 0xd2, 0x56, 0x69, 0x7d, 0x74,
-	0x4a, 0x41, 0xa5, 0x94, 0x94, 0xc4, 0xa1, 0xe0, 0xe9, 0xdc, 0x70, 0xb6, 0x83, 0x3b, 0xa5, 0xe0,
-	0x94, 0xa7, 0x73, 0x3f, 0x01, 0x58, 0xe6, 0xfb, 0x06, 0x41, 0x0e, 0xa1, 0x97, 0x53, 0x99, 0x31,
-	0xa5, 0x98, 0xe0, 0xca, 0x5d, 0x8d, 0xba, 0xc8, 0x7f, 0x06, 0xbb, 0x8f, 0xcb, 0x79, 0x5c, 0x31,
-	0xf2, 0x3b, 0xb8, 0x16, 0x89, 0x34, 0xa5, 0x51, 0xd9, 0xb5, 0x90, 0x71, 0x5d, 0x76, 0x30, 0x75,
-	0x2c, 0x7b, 0x37, 0xb0, 0x8f, 0x4b, 0x50, 0x3d, 0x2e, 0xc1, 0xc8, 0x3d, 0x2e, 0x18, 0x2d, 0x51,
-	0x63, 0x07, 0xf2, 0x9f, 0xc2, 0x9e, 0xf3, 0xed, 0xc8, 0x7b, 0x02, 0x3b, 0x66, 0x72, 0x57, 0x54,
-	0xba, 0xbd, 0xc1, 0xe0, 0xb2, 0x9e, 0x1c, 0xde, 0xff, 0xab, 0x09, 0x57, 0xcf, 0x2b, 0x5f, 0x39,
-	0xbf, 0x11, 0x6c, 0xd7, 0x06, 0xb7, 0x59, 0x97, 0xb2, 0xda, 0xac, 0x36, 0x6b, 0xf4, 0x1c, 0xf6,
-	0x19, 0x57, 0x9a, 0xf0, 0x88, 0x86, 0xe6, 0x91, 0x72, 0xc3, 0xfa, 0x68, 0xd3, 0x34, 0x83, 0xb1,
-	0x73, 0x63, 0x76, 0x96, 0xf6, 0x7b, 0xac, 0x2e, 0xf3, 0x32, 0x40, 0x17, 0x8d, 0x2e, 0xe1, 0xe0,
-	0xbd, 0xb3, 0xd3, 0x76, 0xcd, 0xc7, 0xce, 0x16, 0xab, 0x46, 0xd8, 0x7f, 0x1a, 0xd5, 0x53, 0x67,
-	0x4b, 0xf5, 0x3d, 0xb4, 0x55, 0x91, 0x65, 0x44, 0xce, 0x5d, 0x6b, 0xd7, 0x1e, 0xe3, 0x25, 0xfe,
-	0xa7, 0xd2, 0x2f, 0xae, 0x3c, 0xa0, 0x13, 0x68, 0xd9, 0x72, 0xd9, 0x1c, 0x87, 0x9b, 0xb8, 0x3a,
-	0x9d, 0x3c, 0xa7, 0x91, 0xc6, 0xd6, 0x01, 0xba, 0x0b, 0xdd, 0xc5, 0xff, 0x8c, 0x69, 0x4d, 0x6f,
-	0xe8, 0x5d, 0xe0, 0xdc, 0x93, 0xca, 0x02, 0x2f, 0x8d, 0x87, 0xff, 0x36, 0x61, 0xd7, 0x1e, 0xf0,
-	0x91, 0x09, 0x86, 0x7e, 0x87, 0x5e, 0xed, 0x1f, 0x02, 0x0d, 0x57, 0x15, 0xee, 0xe2, 0x6f, 0x88,
-	0x77, 0x67, 0x23, 0x8c, 0xe5, 0xb8, 0xff, 0xd6, 0xed, 0x06, 0x4a, 0xa1, 0xed, 0xe6, 0x36, 0x5a,
-	0xf9, 0xbe, 0x9c, 0x7d, 0x11, 0xbc, 0xc1, 0xda, 0xf6, 0x55, 0x3c, 0x94, 0x40, 0xcb, 0x36, 0xf5,
-	0xd3, 0x55, 0xd8, 0xfa, 0x4d, 0xf7, 0x6e, 0xad, 0x69, 0xbd, 0x3c, 0xd7, 0xfd, 0xf6, 0xb3, 0x96,
-	0xed, 0xc2, 0x8e, 0xf9, 0xdc, 0xf9, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x12, 0xb9, 0x76, 0xd8, 0xd1,
-	0x0a, 0x00, 0x00,
+	// 990 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xdd, 0x8e, 0xdb, 0xc4,
+	0x17, 0xff, 0x3b, 0xbb, 0xd9, 0x4d, 0x8e, 0x77, 0xd3, 0xfe, 0xa7, 0x2b, 0x64, 0x0c, 0xb4, 0xc1,
+	0x12, 0xd2, 0x0a, 0xa8, 0x53, 0x52, 0x24, 0x2a, 0x10, 0x48, 0xed, 0xa6, 0xec, 0x86, 0x8f, 0x6e,
+	0x35, 0xad, 0x90, 0x5a, 0x24, 0xac, 0x89, 0x3d, 0xc4, 0xd3, 0xda, 0x63, 0xe3, 0x19, 0xa7, 0x0a,
+	0x57, 0x3c, 0x0e, 0x37, 0xbc, 0x0c, 0x0f, 0xc0, 0x05, 0x4f, 0x82, 0x3c, 0x33, 0x4e, 0x9c, 0xdd,
+	0x2d, 0x49, 0xca, 0x95, 0x67, 0xce, 0x39, 0xbf, 0xdf, 0x39, 0x33, 0xe7, 0x63, 0x0c, 0xef, 0xe7,
+	0x49, 0x39, 0x65, 0x5c, 0x0c, 0x22, 0x3a, 0x63, 0x21, 0x1d, 0xe4, 0x45, 0x26, 0x33, 0xb3, 0xf1,
+	0xd5, 0x06, 0xdd, 0x8c, 0x89, 0x88, 0x59, 0x98, 0x15, 0xb9, 0xcf, 0xb3, 0x94, 0x44, 0xbe, 0x81,
+	0xf8, 0xda, 0xca, 0xbd, 0x35, 0xcd, 0xb2, 0x69, 0x62, 0xa0, 0x93, 0xf2, 0xe7, 0x81, 0x64, 0x29,
+	0x15, 0x92, 0xa4, 0xb9, 0x26, 0x70, 0x6f, 0x5e, 0x34, 0x88, 0xca, 0x82, 0x48, 0x96, 0x71, 0xa3,
+	0x3f, 0x9d, 0x32, 0x19, 0x97, 0x13, 0x3f, 0xcc, 0xd2, 0xc1, 0xc2, 0xd7, 0x40, 0xf9, 0x1a, 0xd4,
+	0xe1, 0x89, 0x98, 0x14, 0x34, 0x1a, 0x08, 0x59, 0x94, 0xa1, 0x14, 0x26, 0x4c, 0x22, 0x65, 0xc1,
+	0x26, 0xa5, 0x34, 0x91, 0xba, 0x27, 0x6f, 0x4a, 0x24, 0x24, 0x91, 0x42, 0x93, 0x78, 0x47, 0x80,
+	0xbe, 0x66, 0x7c, 0x4a, 0x8b, 0xbc, 0x60, 0x5c, 0x62, 0xfa, 0x4b, 0x49, 0x85, 0xf4, 0x28, 0xdc,
+	0x58, 0x91, 0x8a, 0x3c, 0xe3, 0x82, 0xa2, 0x47, 0x70, 0xa0, 0x6f, 0x21, 0x98, 0x16, 0x59, 0x99,
+	0x3b, 0x56, 0x7f, 0xe7, 0xd8, 0x1e, 0x7e, 0xe4, 0xff, 0xfb, 0x95, 0xf9, 0x23, 0xf5, 0x39, 0xad,
+	0x20, 0xd8, 0x8e, 0x96, 0x1b, 0xef, 0xb7, 0x1d, 0xb0, 0x1b, 0x4a, 0xf4, 0x16, 0xec, 0xcd, 0x28,
+	0x8f, 0xb2, 0xc2, 0xb1, 0xfa, 0xd6, 0x71, 0x17, 0x9b, 0x1d, 0xba, 0x05, 0x06, 0x16, 0xc8, 0x79,
+	0x4e, 0x9d, 0x96, 0x52, 0x82, 0x16, 0x3d, 0x9d, 0xe7, 0xb4, 0x61, 0xc0, 0x49, 0x4a, 0x9d, 0x9d,
+	0xa6, 0xc1, 0x23, 0x92, 0x52, 0x74, 0x06, 0xfb, 0x7a, 0x27, 0x9c, 0x5d, 0x15, 0xb4, 0xbf, 0x3e,
+	0x68, 0x49, 0x43, 0x49, 0x23, 0x1d, 0x1f, 0xae, 0xe1, 0xe8, 0x47, 0x80, 0x45, 0x22, 0x84, 0xd3,
+	0x56, 0x64, 0x5f, 0x6c, 0x71, 0x03, 0xfe, 0xfd, 0x05, 0xfa, 0x21, 0x97, 0xc5, 0x1c, 0x37, 0xe8,
+	0xdc, 0x1c, 0xae, 0x5d, 0x50, 0xa3, 0xeb, 0xb0, 0xf3, 0x92, 0xce, 0xcd, 0x85, 0x54, 0x4b, 0x74,
+	0x0a, 0xed, 0x19, 0x49, 0x4a, 0x7d, 0x0f, 0xf6, 0xf0, 0x93, 0xd7, 0x3a, 0xd7, 0xc9, 0xf7, 0x4d,
+	0xf2, 0x97, 0x8e, 0xb1, 0xc6, 0x7f, 0xde, 0xba, 0x67, 0x79, 0x7f, 0x5a, 0xd0, 0x5b, 0x3d, 0x2a,
+	0xea, 0x41, 0x6b, 0x3c, 0x32, 0x0e, 0x5b, 0xe3, 0x11, 0x72, 0x60, 0x3f, 0xa6, 0x24, 0x91, 0xf1,
+	0x5c, 0x79, 0xec, 0xe0, 0x7a, 0x8b, 0x6e, 0x03, 0xd2, 0xcb, 0x20, 0xa2, 0x22, 0x2c, 0x58, 0x5e,
+	0x95, 0xb9, 0xb9, 0xfd, 0xff, 0x6b, 0xcd, 0x68, 0xa9, 0x40, 0xe7, 0x60, 0xc7, 0xaf, 0x82, 0x24,
+	0x0b, 0x49, 0xc2, 0xe4, 0xdc, 0xd9, 0xed, 0x5b, 0x9b, 0x25, 0xa2, 0xfa, 0x7c, 0x67, 0x50, 0x18,
+	0xe2, 0x57, 0xf5, 0xba, 0xaa, 0x17, 0x91, 0xa8, 0xa4, 0xb6, 0xfb, 0xd6, 0xf1, 0x21, 0x36, 0x3b,
+	0xcf, 0x87, 0xde, 0x2a, 0x0a, 0xbd, 0x0b, 0x90, 0x87, 0x2c, 0x98, 0x94, 0x22, 0x60, 0x91, 0x39,
+	0x5b, 0x27, 0x0f, 0xd9, 0x83, 0x52, 0x8c, 0x23, 0x6f, 0x00, 0x3d, 0x4c, 0x05, 0x2d, 0x66, 0xd4,
+	0x34, 0x00, 0x7a, 0x0f, 0x4c, 0xf5, 0x04, 0x2c, 0x12, 0xaa, 0xce, 0xbb, 0xb8, 0xab, 0x25, 0xe3,
+	0x48, 0x78, 0x09, 0x5c, 0x5b, 0x00, 0x4c, 0x6f, 0x3c, 0x83, 0xc3, 0x30, 0xe3, 0x92, 0x30, 0x4e,
+	0x8b, 0xa0, 0xa0, 0x42, 0x39, 0xb1, 0x87, 0x9f, 0xae, 0x3b, 0xde, 0x49, 0x0d, 0xd2, 0x84, 0x6a,
+	0x52, 0xe0, 0x83, 0xb0, 0x21, 0xf5, 0x7e, 0x6f, 0xc1, 0xd1, 0x55, 0x66, 0x08, 0xc3, 0x2e, 0xe5,
+	0x33, 0x61, 0xfa, 0xf0, 0xab, 0x37, 0x71, 0xe5, 0x3f, 0xe4, 0x33, 0x53, 0x88, 0x8a, 0x0b, 0x7d,
+	0x09, 0x7b, 0x69, 0x56, 0x72, 0x29, 0x9c, 0x96, 0x62, 0xfd, 0x60, 0x1d, 0xeb, 0xf7, 0x95, 0x35,
+	0x36, 0x20, 0x34, 0x5a, 0x36, 0xda, 0x8e, 0xc2, 0x7f, 0xb8, 0x59, 0x7e, 0x9f, 0xe4, 0x34, 0x5c,
+	0x34, 0x99, 0xfb, 0x19, 0x74, 0x17, 0x71, 0x5d, 0xd1, 0x01, 0x47, 0xcd, 0x0e, 0xe8, 0x36, 0xcb,
+	0xf9, 0x27, 0x68, 0xab, 0x78, 0xd0, 0x3b, 0xd0, 0x95, 0x44, 0xbc, 0x0c, 0x72, 0x22, 0xe3, 0x3a,
+	0xdf, 0x95, 0xe0, 0x31, 0x91, 0x71, 0xa5, 0x8c, 0x33, 0x21, 0xb5, 0x52, 0x73, 0x74, 0x2a, 0x41,
+	0xad, 0x2c, 0x28, 0x89, 0x82, 0x8c, 0x27, 0x73, 0x55, 0xcb, 0x1d, 0xdc, 0xa9, 0x04, 0xe7, 0x3c,
+	0x99, 0x7b, 0x31, 0xc0, 0x32, 0xde, 0xff, 0xe0, 0xa4, 0x0f, 0x76, 0x4e, 0x8b, 0x94, 0x09, 0xc1,
+	0x32, 0x2e, 0x4c, 0xcb, 0x34, 0x45, 0xde, 0x73, 0x38, 0x78, 0x22, 0x89, 0x14, 0x75, 0x45, 0x7e,
+	0x03, 0x37, 0xc2, 0x2c, 0x49, 0x68, 0x58, 0x65, 0x2d, 0x60, 0x5c, 0x56, 0x19, 0x4c, 0x4c, 0x95,
+	0xbd, 0xed, 0xeb, 0x47, 0xc7, 0xaf, 0x1f, 0x1d, 0x7f, 0x64, 0x1e, 0x1d, 0x8c, 0x96, 0xa8, 0xb1,
+	0x01, 0x79, 0xcf, 0xe0, 0xd0, 0x70, 0x9b, 0xe2, 0x3d, 0x83, 0x3d, 0x35, 0xd1, 0xeb, 0x52, 0xba,
+	0xb3, 0xc5, 0x40, 0xd3, 0x4c, 0x06, 0xef, 0xfd, 0xd1, 0x82, 0xeb, 0x17, 0x95, 0xaf, 0x9d, 0xeb,
+	0x08, 0x76, 0x1b, 0x03, 0x5d, 0xad, 0x2b, 0x59, 0x63, 0x86, 0xab, 0x35, 0x7a, 0x01, 0x3d, 0xc6,
+	0x85, 0x24, 0x3c, 0xa4, 0x81, 0x7a, 0xbc, 0xcc, 0x10, 0x3f, 0xd9, 0x36, 0x4c, 0x7f, 0x6c, 0x68,
+	0xd4, 0x4e, 0x97, 0xfd, 0x21, 0x6b, 0xca, 0xdc, 0x14, 0xd0, 0x65, 0xa3, 0x2b, 0x6a, 0xf0, 0xfe,
+	0xea, 0x14, 0xde, 0xf0, 0x11, 0xd4, 0x97, 0xd5, 0x28, 0xd8, 0xbf, 0x2c, 0xb0, 0x1b, 0x2a, 0xf4,
+	0x2d, 0xec, 0x8b, 0x32, 0x4d, 0x49, 0x31, 0x77, 0xac, 0xed, 0xc6, 0x7b, 0x85, 0xff, 0xa1, 0xe2,
+	0xc5, 0x35, 0x03, 0x3a, 0x83, 0xb6, 0xbe, 0x2e, 0x1d, 0xe3, 0x70, 0x1b, 0xaa, 0xf3, 0xc9, 0x0b,
+	0x1a, 0x4a, 0xac, 0x09, 0xd0, 0x3d, 0xe8, 0x2e, 0xfe, 0x73, 0x54, 0x6a, 0xec, 0xa1, 0x7b, 0xa9,
+	0xe6, 0x9e, 0xd6, 0x16, 0x78, 0x69, 0x3c, 0xfc, 0xbb, 0x05, 0x07, 0xfa, 0x80, 0x8f, 0x95, 0x33,
+	0xf4, 0x2b, 0xd8, 0x8d, 0x7f, 0x0b, 0x34, 0x5c, 0x77, 0x71, 0x97, 0x7f, 0x4f, 0xdc, 0xbb, 0x5b,
+	0x61, 0x74, 0x8d, 0x7b, 0xff, 0xbb, 0x63, 0xa1, 0x04, 0xf6, 0xcd, 0xdc, 0x46, 0x6b, 0xdf, 0x9d,
+	0xd5, 0x17, 0xc1, 0x1d, 0x6c, 0x6c, 0x5f, 0xfb, 0x43, 0x31, 0xb4, 0x75, 0x52, 0x3f, 0x5e, 0x87,
+	0x6d, 0x76, 0xba, 0x7b, 0x7b, 0x43, 0xeb, 0xe5, 0xb9, 0x1e, 0xec, 0x3f, 0x6f, 0xeb, 0x2c, 0xec,
+	0xa9, 0xcf, 0xdd, 0x7f, 0x06, 0x00, 0x87, 0xc0, 0x30, 0xa5, 0xe9, 0x0a, 0x00, 0x00,
 }
@@ -60,6 +60,7 @@ func convertProtoDevice(in *proto.DetectedDevice) *Device {
 		Healthy:    in.Healthy,
 		HealthDesc: in.HealthDescription,
 		HwLocality: convertProtoDeviceLocality(in.HwLocality),
+		Slices:     in.Slices,
 	}
 }
 
@@ -196,6 +197,7 @@ func convertStructDevice(in *Device) *proto.DetectedDevice {
 		Healthy:           in.Healthy,
 		HealthDescription: in.HealthDesc,
 		HwLocality:        convertStructDeviceLocality(in.HwLocality),
+		Slices:            in.Slices,
 	}
 }
 
@@ -1,6 +1,7 @@
 package drivers
 
 import (
+	"github.com/hashicorp/nomad/client/stats"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 )
 
@@ -14,6 +15,13 @@ type MemoryStats = cstructs.MemoryStats
 // CpuStats holds cpu usage related stats
 type CpuStats = cstructs.CpuStats
 
+// PressureStats holds the pressure stall information of the cpu, memory and io
+// resources
+type PressureStats = stats.PressureStats
+
+// Pressure holds the pressure stall information of a resource
+type Pressure = stats.Pressure
+
 // ResourceUsage holds information related to cpu and memory stats
 type ResourceUsage = cstructs.ResourceUsage
 
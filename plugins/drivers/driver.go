@@ -64,6 +64,17 @@ type InternalDriverPlugin interface {
 	Shutdown()
 }
 
+// DriverImagePrefetcher is an optional interface implemented by internal
+// driver plugins that can fetch the image of a task ahead of its placement.
+type DriverImagePrefetcher interface {
+	// PrefetchImage fetches the image, keeping it for the caller until it
+	// releases it.
+	PrefetchImage(image, callerID string) error
+
+	// ReleaseImage releases an image the caller prefetched.
+	ReleaseImage(image, callerID string)
+}
+
 // DriverSignalTaskNotSupported can be embedded by drivers which don't support
 // the SignalTask RPC. This satisfies the SignalTask func requirement for the
 // DriverPlugin interface.
@@ -143,6 +154,11 @@ type TaskConfig struct {
 	StdoutPath      string
 	StderrPath      string
 	AllocID         string
+
+	// SharedMemoryMB is the size of the /dev/shm shared by the tasks of the
+	// allocation along with their IPC namespace, zero if the task group
+	// doesn't share memory.
+	SharedMemoryMB int64
 }
 
 func (tc *TaskConfig) Copy() *TaskConfig {
@@ -249,6 +265,17 @@ type LinuxResources struct {
 	CpusetCPUs       string
 	CpusetMems       string
 
+	// HugePages2MB and HugePages1GB are the number of huge pages of each
+	// size the task may use.
+	HugePages2MB int64
+	HugePages1GB int64
+
+	// CPURealtimeRuntime is the time in microseconds the task may run
+	// real-time threads in each CPURealtimePeriod. Zero if the task doesn't
+	// use the real-time scheduling policies.
+	CPURealtimeRuntime int64
+	CPURealtimePeriod  int64
+
 	// PrecentTicks is used to calculate the CPUQuota, currently the docker
 	// driver exposes cpu period and quota through the driver configuration
 	// and thus the calculation for CPUQuota cannot be done on the client.
@@ -1382,7 +1382,11 @@ type TaskConfig struct {
 	// JobName is the name of the job of which this task is part of
 	JobName string `protobuf:"bytes,14,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
 	// AllocId is the ID of the associated allocation
-	AllocId              string   `protobuf:"bytes,15,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	AllocId string `protobuf:"bytes,15,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	// SharedMemoryMb is the size of the /dev/shm shared by the tasks of the
+	// allocation along with their IPC namespace. Zero if the task group
+	// doesn't share memory.
+	SharedMemoryMb       int64    `protobuf:"varint,16,opt,name=shared_memory_mb,json=sharedMemoryMb,proto3" json:"shared_memory_mb,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1517,6 +1521,13 @@ func (m *TaskConfig) GetAllocId() string {
 	return ""
 }
 
+func (m *TaskConfig) GetSharedMemoryMb() int64 {
+	if m != nil {
+		return m.SharedMemoryMb
+	}
+	return 0
+}
+
 type Resources struct {
 	// AllocatedResources are the resources set for the task
 	AllocatedResources *AllocatedTaskResources `protobuf:"bytes,1,opt,name=allocated_resources,json=allocatedResources,proto3" json:"allocated_resources,omitempty"`
@@ -1835,7 +1846,15 @@ type LinuxResources struct {
 	// CpusetMems constrains the allowed set of memory nodes. Default: "" (not specified)
 	CpusetMems string `protobuf:"bytes,7,opt,name=cpuset_mems,json=cpusetMems,proto3" json:"cpuset_mems,omitempty"`
 	// PercentTicks is a compatibility option for docker and should not be used
-	PercentTicks         float64  `protobuf:"fixed64,8,opt,name=PercentTicks,proto3" json:"PercentTicks,omitempty"`
+	PercentTicks float64 `protobuf:"fixed64,8,opt,name=PercentTicks,proto3" json:"PercentTicks,omitempty"`
+	// HugePages2mb and HugePages1gb are the number of huge pages of each size
+	// the task may use. Default: 0 (not specified)
+	HugePages2Mb int64 `protobuf:"varint,9,opt,name=huge_pages2mb,json=hugePages2mb,proto3" json:"huge_pages2mb,omitempty"`
+	HugePages1Gb int64 `protobuf:"varint,10,opt,name=huge_pages1gb,json=hugePages1gb,proto3" json:"huge_pages1gb,omitempty"`
+	// CpuRealtimeRuntime is the time in microseconds the task may run
+	// real-time threads in each CpuRealtimePeriod. Default: 0 (not specified)
+	CpuRealtimeRuntime   int64    `protobuf:"varint,11,opt,name=cpu_realtime_runtime,json=cpuRealtimeRuntime,proto3" json:"cpu_realtime_runtime,omitempty"`
+	CpuRealtimePeriod    int64    `protobuf:"varint,12,opt,name=cpu_realtime_period,json=cpuRealtimePeriod,proto3" json:"cpu_realtime_period,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1921,6 +1940,34 @@ func (m *LinuxResources) GetPercentTicks() float64 {
 	return 0
 }
 
+func (m *LinuxResources) GetHugePages2Mb() int64 {
+	if m != nil {
+		return m.HugePages2Mb
+	}
+	return 0
+}
+
+func (m *LinuxResources) GetHugePages1Gb() int64 {
+	if m != nil {
+		return m.HugePages1Gb
+	}
+	return 0
+}
+
+func (m *LinuxResources) GetCpuRealtimeRuntime() int64 {
+	if m != nil {
+		return m.CpuRealtimeRuntime
+	}
+	return 0
+}
+
+func (m *LinuxResources) GetCpuRealtimePeriod() int64 {
+	if m != nil {
+		return m.CpuRealtimePeriod
+	}
+	return 0
+}
+
 type Mount struct {
 	// TaskPath is the file path within the task directory to mount to
 	TaskPath string `protobuf:"bytes,1,opt,name=task_path,json=taskPath,proto3" json:"task_path,omitempty"`
@@ -2421,10 +2468,12 @@ type TaskResourceUsage struct {
 	// CPU usage stats
 	Cpu *CPUUsage `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
 	// Memory usage stats
-	Memory               *MemoryUsage `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	Memory *MemoryUsage `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// Pressure stall information
+	Pressure             *PressureUsage `protobuf:"bytes,3,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *TaskResourceUsage) Reset()         { *m = TaskResourceUsage{} }
@@ -2465,6 +2514,13 @@ func (m *TaskResourceUsage) GetMemory() *MemoryUsage {
 	return nil
 }
 
+func (m *TaskResourceUsage) GetPressure() *PressureUsage {
+	if m != nil {
+		return m.Pressure
+	}
+	return nil
+}
+
 type CPUUsage struct {
 	SystemMode       float64 `protobuf:"fixed64,1,opt,name=system_mode,json=systemMode,proto3" json:"system_mode,omitempty"`
 	UserMode         float64 `protobuf:"fixed64,2,opt,name=user_mode,json=userMode,proto3" json:"user_mode,omitempty"`
@@ -2723,6 +2779,159 @@ func (m *DriverTaskEvent) GetAnnotations() map[string]string {
 	return nil
 }
 
+type PressureUsage struct {
+	// Pressure of the cpu, memory and io resources
+	Cpu                  *ResourcePressure `protobuf:"bytes,1,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory               *ResourcePressure `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	Io                   *ResourcePressure `protobuf:"bytes,3,opt,name=io,proto3" json:"io,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PressureUsage) Reset()         { *m = PressureUsage{} }
+func (m *PressureUsage) String() string { return proto.CompactTextString(m) }
+func (*PressureUsage) ProtoMessage()    {}
+func (*PressureUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_7505ca5155ee1b5b, []int{46}
+}
+func (m *PressureUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PressureUsage.Unmarshal(m, b)
+}
+func (m *PressureUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PressureUsage.Marshal(b, m, deterministic)
+}
+func (dst *PressureUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PressureUsage.Merge(dst, src)
+}
+func (m *PressureUsage) XXX_Size() int {
+	return xxx_messageInfo_PressureUsage.Size(m)
+}
+func (m *PressureUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_PressureUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PressureUsage proto.InternalMessageInfo
+
+func (m *PressureUsage) GetCpu() *ResourcePressure {
+	if m != nil {
+		return m.Cpu
+	}
+	return nil
+}
+
+func (m *PressureUsage) GetMemory() *ResourcePressure {
+	if m != nil {
+		return m.Memory
+	}
+	return nil
+}
+
+func (m *PressureUsage) GetIo() *ResourcePressure {
+	if m != nil {
+		return m.Io
+	}
+	return nil
+}
+
+type ResourcePressure struct {
+	// Percentage of time some tasks were stalled, averaged over 10, 60 and
+	// 300 seconds, and the total stall time in microseconds
+	SomeAvg10  float64 `protobuf:"fixed64,1,opt,name=some_avg10,json=someAvg10,proto3" json:"some_avg10,omitempty"`
+	SomeAvg60  float64 `protobuf:"fixed64,2,opt,name=some_avg60,json=someAvg60,proto3" json:"some_avg60,omitempty"`
+	SomeAvg300 float64 `protobuf:"fixed64,3,opt,name=some_avg300,json=someAvg300,proto3" json:"some_avg300,omitempty"`
+	SomeTotal  uint64  `protobuf:"varint,4,opt,name=some_total,json=someTotal,proto3" json:"some_total,omitempty"`
+	// Percentage of time all tasks were stalled, averaged over 10, 60 and
+	// 300 seconds, and the total stall time in microseconds
+	FullAvg10            float64  `protobuf:"fixed64,5,opt,name=full_avg10,json=fullAvg10,proto3" json:"full_avg10,omitempty"`
+	FullAvg60            float64  `protobuf:"fixed64,6,opt,name=full_avg60,json=fullAvg60,proto3" json:"full_avg60,omitempty"`
+	FullAvg300           float64  `protobuf:"fixed64,7,opt,name=full_avg300,json=fullAvg300,proto3" json:"full_avg300,omitempty"`
+	FullTotal            uint64   `protobuf:"varint,8,opt,name=full_total,json=fullTotal,proto3" json:"full_total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResourcePressure) Reset()         { *m = ResourcePressure{} }
+func (m *ResourcePressure) String() string { return proto.CompactTextString(m) }
+func (*ResourcePressure) ProtoMessage()    {}
+func (*ResourcePressure) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_7505ca5155ee1b5b, []int{47}
+}
+func (m *ResourcePressure) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourcePressure.Unmarshal(m, b)
+}
+func (m *ResourcePressure) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourcePressure.Marshal(b, m, deterministic)
+}
+func (dst *ResourcePressure) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourcePressure.Merge(dst, src)
+}
+func (m *ResourcePressure) XXX_Size() int {
+	return xxx_messageInfo_ResourcePressure.Size(m)
+}
+func (m *ResourcePressure) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourcePressure.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourcePressure proto.InternalMessageInfo
+
+func (m *ResourcePressure) GetSomeAvg10() float64 {
+	if m != nil {
+		return m.SomeAvg10
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetSomeAvg60() float64 {
+	if m != nil {
+		return m.SomeAvg60
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetSomeAvg300() float64 {
+	if m != nil {
+		return m.SomeAvg300
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetSomeTotal() uint64 {
+	if m != nil {
+		return m.SomeTotal
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetFullAvg10() float64 {
+	if m != nil {
+		return m.FullAvg10
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetFullAvg60() float64 {
+	if m != nil {
+		return m.FullAvg60
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetFullAvg300() float64 {
+	if m != nil {
+		return m.FullAvg300
+	}
+	return 0
+}
+
+func (m *ResourcePressure) GetFullTotal() uint64 {
+	if m != nil {
+		return m.FullTotal
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*TaskConfigSchemaRequest)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfigSchemaRequest")
 	proto.RegisterType((*TaskConfigSchemaResponse)(nil), "hashicorp.nomad.plugins.drivers.proto.TaskConfigSchemaResponse")
@@ -2777,6 +2986,8 @@ func init() {
 	proto.RegisterType((*MemoryUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.MemoryUsage")
 	proto.RegisterType((*DriverTaskEvent)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent")
 	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.proto.DriverTaskEvent.AnnotationsEntry")
+	proto.RegisterType((*PressureUsage)(nil), "hashicorp.nomad.plugins.drivers.proto.PressureUsage")
+	proto.RegisterType((*ResourcePressure)(nil), "hashicorp.nomad.plugins.drivers.proto.ResourcePressure")
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.TaskState", TaskState_name, TaskState_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.FingerprintResponse_HealthState", FingerprintResponse_HealthState_name, FingerprintResponse_HealthState_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.proto.StartTaskResponse_Result", StartTaskResponse_Result_name, StartTaskResponse_Result_value)
@@ -3400,192 +3611,208 @@ func init() {
 }
 
 var fileDescriptor_driver_7505ca5155ee1b5b = []byte{
-	// 2990 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xcb, 0x6f, 0x23, 0xc7,
-	0xd1, 0x17, 0x9f, 0x22, 0x8b, 0x12, 0x35, 0xdb, 0xbb, 0x6b, 0xd3, 0x34, 0xbe, 0xcf, 0xeb, 0x01,
-	0xfc, 0x41, 0xb0, 0xbd, 0x94, 0x2d, 0xe3, 0xdb, 0x57, 0xfc, 0xa2, 0xc9, 0x59, 0x49, 0x5e, 0x89,
-	0x52, 0x9a, 0x14, 0xd6, 0x9b, 0xc4, 0x3b, 0x19, 0xcd, 0xf4, 0x92, 0xb3, 0x9a, 0x97, 0x67, 0x7a,
-	0x64, 0x09, 0x41, 0x90, 0xc0, 0x01, 0x82, 0xe4, 0x10, 0x20, 0x17, 0x23, 0xf7, 0xe4, 0x98, 0xbf,
-	0x20, 0x09, 0xfc, 0x97, 0x24, 0x97, 0x04, 0x08, 0x90, 0x6b, 0x8e, 0xb9, 0x05, 0xfd, 0x98, 0xe1,
-	0x50, 0xd2, 0x7a, 0x87, 0x5c, 0x9f, 0x66, 0xba, 0xba, 0xeb, 0xd7, 0xd5, 0x5d, 0xd5, 0x55, 0xd5,
-	0x5d, 0xa0, 0x06, 0x4e, 0x3c, 0xb6, 0xbd, 0x68, 0xc3, 0x0a, 0xed, 0x13, 0x12, 0x46, 0x1b, 0x41,
-	0xe8, 0x53, 0x5f, 0xb6, 0x3a, 0xbc, 0x81, 0xde, 0x98, 0x18, 0xd1, 0xc4, 0x36, 0xfd, 0x30, 0xe8,
-	0x78, 0xbe, 0x6b, 0x58, 0x1d, 0xc9, 0xd3, 0x91, 0x3c, 0x62, 0x58, 0xfb, 0x7f, 0xc7, 0xbe, 0x3f,
-	0x76, 0x88, 0x40, 0x38, 0x8a, 0x9f, 0x6c, 0x58, 0x71, 0x68, 0x50, 0xdb, 0xf7, 0x64, 0xff, 0x6b,
-	0xe7, 0xfb, 0xa9, 0xed, 0x92, 0x88, 0x1a, 0x6e, 0x20, 0x07, 0x7c, 0x3c, 0xb6, 0xe9, 0x24, 0x3e,
-	0xea, 0x98, 0xbe, 0xbb, 0x91, 0x4e, 0xb9, 0xc1, 0xa7, 0xdc, 0x48, 0xc4, 0x8c, 0x26, 0x46, 0x48,
-	0xac, 0x8d, 0x89, 0xe9, 0x44, 0x01, 0x31, 0xd9, 0x57, 0x67, 0x3f, 0x12, 0x61, 0x2b, 0x3f, 0x42,
-	0x44, 0xc3, 0xd8, 0xa4, 0xc9, 0x7a, 0x0d, 0x4a, 0x43, 0xfb, 0x28, 0xa6, 0x44, 0x00, 0xa9, 0xaf,
-	0xc0, 0xcb, 0x23, 0x23, 0x3a, 0xee, 0xf9, 0xde, 0x13, 0x7b, 0x3c, 0x34, 0x27, 0xc4, 0x35, 0x30,
-	0xf9, 0x22, 0x26, 0x11, 0x55, 0x7f, 0x04, 0xad, 0x8b, 0x5d, 0x51, 0xe0, 0x7b, 0x11, 0x41, 0x1f,
-	0x43, 0x99, 0x49, 0xd3, 0x2a, 0xdc, 0x28, 0xac, 0x37, 0x36, 0xdf, 0xee, 0x3c, 0x6b, 0xe3, 0x84,
-	0x0c, 0x1d, 0xb9, 0x8a, 0xce, 0x30, 0x20, 0x26, 0xe6, 0x9c, 0xea, 0x75, 0xb8, 0xda, 0x33, 0x02,
-	0xe3, 0xc8, 0x76, 0x6c, 0x6a, 0x93, 0x28, 0x99, 0x34, 0x86, 0x6b, 0xb3, 0x64, 0x39, 0xe1, 0xe7,
-	0xb0, 0x62, 0x66, 0xe8, 0x72, 0xe2, 0xbb, 0x9d, 0x5c, 0x1a, 0xeb, 0xf4, 0x79, 0x6b, 0x06, 0x78,
-	0x06, 0x4e, 0xbd, 0x06, 0xe8, 0xbe, 0xed, 0x8d, 0x49, 0x18, 0x84, 0xb6, 0x47, 0x13, 0x61, 0xbe,
-	0x29, 0xc1, 0xd5, 0x19, 0xb2, 0x14, 0xe6, 0x29, 0x40, 0xba, 0x8f, 0x4c, 0x94, 0xd2, 0x7a, 0x63,
-	0xf3, 0xd3, 0x9c, 0xa2, 0x5c, 0x82, 0xd7, 0xe9, 0xa6, 0x60, 0x9a, 0x47, 0xc3, 0x33, 0x9c, 0x41,
-	0x47, 0x8f, 0xa1, 0x3a, 0x21, 0x86, 0x43, 0x27, 0xad, 0xe2, 0x8d, 0xc2, 0x7a, 0x73, 0xf3, 0xfe,
-	0x0b, 0xcc, 0xb3, 0xcd, 0x81, 0x86, 0xd4, 0xa0, 0x04, 0x4b, 0x54, 0x74, 0x13, 0x90, 0xf8, 0xd3,
-	0x2d, 0x12, 0x99, 0xa1, 0x1d, 0x30, 0x43, 0x6e, 0x95, 0x6e, 0x14, 0xd6, 0xeb, 0xf8, 0x8a, 0xe8,
-	0xe9, 0x4f, 0x3b, 0xda, 0x01, 0xac, 0x9d, 0x93, 0x16, 0x29, 0x50, 0x3a, 0x26, 0x67, 0x5c, 0x23,
-	0x75, 0xcc, 0x7e, 0xd1, 0x16, 0x54, 0x4e, 0x0c, 0x27, 0x26, 0x5c, 0xe4, 0xc6, 0xe6, 0xbb, 0xcf,
-	0x33, 0x0f, 0x69, 0xa2, 0xd3, 0x7d, 0xc0, 0x82, 0xff, 0x5e, 0xf1, 0x4e, 0x41, 0xbd, 0x0b, 0x8d,
-	0x8c, 0xdc, 0xa8, 0x09, 0x70, 0x38, 0xe8, 0x6b, 0x23, 0xad, 0x37, 0xd2, 0xfa, 0xca, 0x12, 0x5a,
-	0x85, 0xfa, 0xe1, 0x60, 0x5b, 0xeb, 0xee, 0x8e, 0xb6, 0x1f, 0x29, 0x05, 0xd4, 0x80, 0xe5, 0xa4,
-	0x51, 0x54, 0x4f, 0x01, 0x61, 0x62, 0xfa, 0x27, 0x24, 0x64, 0x86, 0x2c, 0xb5, 0x8a, 0x5e, 0x86,
-	0x65, 0x6a, 0x44, 0xc7, 0xba, 0x6d, 0x49, 0x99, 0xab, 0xac, 0xb9, 0x63, 0xa1, 0x1d, 0xa8, 0x4e,
-	0x0c, 0xcf, 0x72, 0x9e, 0x2f, 0xf7, 0xec, 0x56, 0x33, 0xf0, 0x6d, 0xce, 0x88, 0x25, 0x00, 0xb3,
-	0xee, 0x99, 0x99, 0x85, 0x02, 0xd4, 0x47, 0xa0, 0x0c, 0xa9, 0x11, 0xd2, 0xac, 0x38, 0x1a, 0x94,
-	0xd9, 0xfc, 0xd2, 0xa2, 0xe7, 0x99, 0x53, 0x9c, 0x4c, 0xcc, 0xd9, 0xd5, 0x7f, 0x17, 0xe1, 0x4a,
-	0x06, 0x5b, 0x5a, 0xea, 0x43, 0xa8, 0x86, 0x24, 0x8a, 0x1d, 0xca, 0xe1, 0x9b, 0x9b, 0x1f, 0xe5,
-	0x84, 0xbf, 0x80, 0xd4, 0xc1, 0x1c, 0x06, 0x4b, 0x38, 0xb4, 0x0e, 0x8a, 0xe0, 0xd0, 0x49, 0x18,
-	0xfa, 0xa1, 0xee, 0x46, 0x63, 0xbe, 0x6b, 0x75, 0xdc, 0x14, 0x74, 0x8d, 0x91, 0xf7, 0xa2, 0x71,
-	0x66, 0x57, 0x4b, 0x2f, 0xb8, 0xab, 0xc8, 0x00, 0xc5, 0x23, 0xf4, 0x4b, 0x3f, 0x3c, 0xd6, 0xd9,
-	0xd6, 0x86, 0xb6, 0x45, 0x5a, 0x65, 0x0e, 0x7a, 0x2b, 0x27, 0xe8, 0x40, 0xb0, 0xef, 0x4b, 0x6e,
-	0xbc, 0xe6, 0xcd, 0x12, 0xd4, 0xb7, 0xa0, 0x2a, 0x56, 0xca, 0x2c, 0x69, 0x78, 0xd8, 0xeb, 0x69,
-	0xc3, 0xa1, 0xb2, 0x84, 0xea, 0x50, 0xc1, 0xda, 0x08, 0x33, 0x0b, 0xab, 0x43, 0xe5, 0x7e, 0x77,
-	0xd4, 0xdd, 0x55, 0x8a, 0xea, 0x9b, 0xb0, 0xf6, 0xd0, 0xb0, 0x69, 0x1e, 0xe3, 0x52, 0x7d, 0x50,
-	0xa6, 0x63, 0xa5, 0x76, 0x76, 0x66, 0xb4, 0x93, 0x7f, 0x6b, 0xb4, 0x53, 0x9b, 0x9e, 0xd3, 0x87,
-	0x02, 0x25, 0x12, 0x86, 0x52, 0x05, 0xec, 0x57, 0xfd, 0x12, 0xd6, 0x86, 0xd4, 0x0f, 0x72, 0x59,
-	0xfe, 0x7b, 0xb0, 0xcc, 0x62, 0x94, 0x1f, 0x53, 0x69, 0xfa, 0xaf, 0x74, 0x44, 0x0c, 0xeb, 0x24,
-	0x31, 0xac, 0xd3, 0x97, 0x31, 0x0e, 0x27, 0x23, 0xd1, 0x4b, 0x50, 0x8d, 0xec, 0xb1, 0x67, 0x38,
-	0xd2, 0x5b, 0xc8, 0x96, 0x8a, 0x98, 0x91, 0x27, 0x13, 0x4b, 0xc3, 0xef, 0x01, 0xea, 0x93, 0x88,
-	0x86, 0xfe, 0x59, 0x2e, 0x79, 0xae, 0x41, 0xe5, 0x89, 0x1f, 0x9a, 0xe2, 0x20, 0xd6, 0xb0, 0x68,
-	0xb0, 0x43, 0x35, 0x03, 0x22, 0xb1, 0x6f, 0x02, 0xda, 0xf1, 0x58, 0x4c, 0xc9, 0xa7, 0x88, 0xdf,
-	0x16, 0xe1, 0xea, 0xcc, 0x78, 0xa9, 0x8c, 0xc5, 0xcf, 0x21, 0x73, 0x4c, 0x71, 0x24, 0xce, 0x21,
-	0xda, 0x87, 0xaa, 0x18, 0x21, 0x77, 0xf2, 0xf6, 0x1c, 0x40, 0x22, 0x4c, 0x49, 0x38, 0x09, 0x73,
-	0xa9, 0xd1, 0x97, 0xbe, 0x5b, 0xa3, 0xff, 0x12, 0x94, 0x64, 0x1d, 0xd1, 0x73, 0x75, 0xf3, 0x29,
-	0x5c, 0x35, 0x7d, 0xc7, 0x21, 0x26, 0xb3, 0x06, 0xdd, 0xf6, 0x28, 0x09, 0x4f, 0x0c, 0xe7, 0xf9,
-	0x76, 0x83, 0xa6, 0x5c, 0x3b, 0x92, 0x49, 0xfd, 0x21, 0x5c, 0xc9, 0x4c, 0x2c, 0x15, 0x71, 0x1f,
-	0x2a, 0x11, 0x23, 0x48, 0x4d, 0xbc, 0x33, 0xa7, 0x26, 0x22, 0x2c, 0xd8, 0xd5, 0xab, 0x02, 0x5c,
-	0x3b, 0x21, 0x5e, 0xba, 0x2c, 0xb5, 0x0f, 0x57, 0x86, 0xdc, 0x4c, 0x73, 0xd9, 0xe1, 0xd4, 0xc4,
-	0x8b, 0x33, 0x26, 0x7e, 0x0d, 0x50, 0x16, 0x45, 0x1a, 0xe2, 0x19, 0xac, 0x69, 0xa7, 0xc4, 0xcc,
-	0x85, 0xdc, 0x82, 0x65, 0xd3, 0x77, 0x5d, 0xc3, 0xb3, 0x5a, 0xc5, 0x1b, 0xa5, 0xf5, 0x3a, 0x4e,
-	0x9a, 0xd9, 0xb3, 0x58, 0xca, 0x7b, 0x16, 0xd5, 0xdf, 0x14, 0x40, 0x99, 0xce, 0x2d, 0x37, 0x92,
-	0x49, 0x4f, 0x2d, 0x06, 0xc4, 0xe6, 0x5e, 0xc1, 0xb2, 0x25, 0xe9, 0x89, 0xbb, 0x10, 0x74, 0x12,
-	0x86, 0x19, 0x77, 0x54, 0x7a, 0x41, 0x77, 0xa4, 0xfe, 0xb3, 0x00, 0xe8, 0x62, 0xd2, 0x85, 0x5e,
-	0x87, 0x95, 0x88, 0x78, 0x96, 0x2e, 0xb6, 0x51, 0x68, 0xb8, 0x86, 0x1b, 0x8c, 0x26, 0xf6, 0x33,
-	0x42, 0x08, 0xca, 0xe4, 0x94, 0x98, 0xf2, 0xe4, 0xf3, 0x7f, 0x34, 0x81, 0x95, 0x27, 0x91, 0x6e,
-	0x47, 0xbe, 0x63, 0xa4, 0xd9, 0x49, 0x73, 0x53, 0x5b, 0x38, 0xf9, 0xeb, 0xdc, 0x1f, 0xee, 0x24,
-	0x60, 0xb8, 0xf1, 0x24, 0x4a, 0x1b, 0x6a, 0x07, 0x1a, 0x99, 0x3e, 0x54, 0x83, 0xf2, 0x60, 0x7f,
-	0xa0, 0x29, 0x4b, 0x08, 0xa0, 0xda, 0xdb, 0xc6, 0xfb, 0xfb, 0x23, 0x11, 0x01, 0x76, 0xf6, 0xba,
-	0x5b, 0x9a, 0x52, 0x54, 0xff, 0x54, 0x05, 0x98, 0x86, 0x62, 0xd4, 0x84, 0x62, 0xaa, 0xe9, 0xa2,
-	0x6d, 0xb1, 0xc5, 0x78, 0x86, 0x4b, 0xa4, 0xf5, 0xf0, 0x7f, 0xb4, 0x09, 0xd7, 0xdd, 0x68, 0x1c,
-	0x18, 0xe6, 0xb1, 0x2e, 0x23, 0xa8, 0xc9, 0x99, 0xf9, 0xaa, 0x56, 0xf0, 0x55, 0xd9, 0x29, 0xa5,
-	0x16, 0xb8, 0xbb, 0x50, 0x22, 0xde, 0x49, 0xab, 0xcc, 0x33, 0xcd, 0x7b, 0x73, 0xa7, 0x08, 0x1d,
-	0xcd, 0x3b, 0x11, 0x99, 0x25, 0x83, 0x41, 0x3a, 0x80, 0x45, 0x4e, 0x6c, 0x93, 0xe8, 0x0c, 0xb4,
-	0xc2, 0x41, 0x3f, 0x9e, 0x1f, 0xb4, 0xcf, 0x31, 0x52, 0xe8, 0xba, 0x95, 0xb4, 0xd1, 0x00, 0xea,
-	0x21, 0x89, 0xfc, 0x38, 0x34, 0x49, 0xd4, 0xaa, 0xce, 0x75, 0x8a, 0x71, 0xc2, 0x87, 0xa7, 0x10,
-	0xa8, 0x0f, 0x55, 0xd7, 0x8f, 0x3d, 0x1a, 0xb5, 0x96, 0xb9, 0xb0, 0x6f, 0xe7, 0x04, 0xdb, 0x63,
-	0x4c, 0x58, 0xf2, 0xa2, 0x2d, 0x58, 0x16, 0x22, 0x46, 0xad, 0x1a, 0x87, 0xb9, 0x99, 0xd7, 0x80,
-	0x38, 0x17, 0x4e, 0xb8, 0x99, 0x56, 0xe3, 0x88, 0x84, 0xad, 0xba, 0xd0, 0x2a, 0xfb, 0x47, 0xaf,
-	0x42, 0xdd, 0x70, 0x1c, 0xdf, 0xd4, 0x2d, 0x3b, 0x6c, 0x01, 0xef, 0xa8, 0x71, 0x42, 0xdf, 0x0e,
-	0xd1, 0x6b, 0xd0, 0x10, 0x47, 0x4f, 0x0f, 0x0c, 0x3a, 0x69, 0x35, 0x78, 0x37, 0x08, 0xd2, 0x81,
-	0x41, 0x27, 0x72, 0x00, 0x09, 0x43, 0x31, 0x60, 0x25, 0x1d, 0x40, 0xc2, 0x90, 0x0f, 0xf8, 0x3f,
-	0x58, 0xe3, 0x7e, 0x64, 0x1c, 0xfa, 0x71, 0xa0, 0x73, 0x9b, 0x5a, 0xe5, 0x83, 0x56, 0x19, 0x79,
-	0x8b, 0x51, 0x07, 0xcc, 0xb8, 0x5e, 0x81, 0xda, 0x53, 0xff, 0x48, 0x0c, 0x68, 0xf2, 0x01, 0xcb,
-	0x4f, 0xfd, 0xa3, 0xa4, 0x4b, 0x48, 0x68, 0x5b, 0xad, 0x35, 0xd1, 0xc5, 0xdb, 0x3b, 0x56, 0xfb,
-	0x16, 0xd4, 0x12, 0x35, 0x5e, 0x92, 0xcd, 0x5f, 0xcb, 0x66, 0xf3, 0xf5, 0x4c, 0x6a, 0xde, 0x7e,
-	0x1f, 0x9a, 0xb3, 0x46, 0x30, 0x0f, 0xb7, 0xfa, 0xd7, 0x02, 0xd4, 0x53, 0x75, 0x23, 0x0f, 0xae,
-	0x72, 0x71, 0x0c, 0x4a, 0x2c, 0x7d, 0x6a, 0x3d, 0x22, 0x06, 0x7c, 0x90, 0x53, 0x53, 0xdd, 0x04,
-	0x41, 0xfa, 0x41, 0x69, 0x4a, 0x28, 0x45, 0x9e, 0xce, 0xf7, 0x18, 0xd6, 0x1c, 0xdb, 0x8b, 0x4f,
-	0x33, 0x73, 0x89, 0x10, 0xf6, 0xff, 0x39, 0xe7, 0xda, 0x65, 0xdc, 0xd3, 0x39, 0x9a, 0xce, 0x4c,
-	0x5b, 0xfd, 0xba, 0x08, 0x2f, 0x5d, 0x2e, 0x0e, 0x1a, 0x40, 0xc9, 0x0c, 0x62, 0xb9, 0xb4, 0xf7,
-	0xe7, 0x5d, 0x5a, 0x2f, 0x88, 0xa7, 0xb3, 0x32, 0x20, 0x96, 0xe4, 0xbb, 0xc4, 0xf5, 0xc3, 0x33,
-	0xb9, 0x82, 0x8f, 0xe6, 0x85, 0xdc, 0xe3, 0xdc, 0x53, 0x54, 0x09, 0x87, 0x30, 0xd4, 0x64, 0xaa,
-	0x10, 0x49, 0x37, 0x31, 0x67, 0xca, 0x91, 0x40, 0xe2, 0x14, 0x47, 0xbd, 0x05, 0xd7, 0x2f, 0x5d,
-	0x0a, 0xfa, 0x1f, 0x00, 0x33, 0x88, 0x75, 0x7e, 0x25, 0x14, 0x7a, 0x2f, 0xe1, 0xba, 0x19, 0xc4,
-	0x43, 0x4e, 0x50, 0x6f, 0x43, 0xeb, 0x59, 0xf2, 0xb2, 0xc3, 0x27, 0x24, 0xd6, 0xdd, 0x23, 0xbe,
-	0x07, 0x25, 0x5c, 0x13, 0x84, 0xbd, 0x23, 0xf5, 0x77, 0x45, 0x58, 0x3b, 0x27, 0x0e, 0x8b, 0x80,
-	0xe2, 0x30, 0x27, 0x51, 0x59, 0xb4, 0xd8, 0xc9, 0x36, 0x6d, 0x2b, 0x49, 0xa3, 0xf9, 0x3f, 0xf7,
-	0xe9, 0x81, 0x4c, 0x71, 0x8b, 0x76, 0xc0, 0x0c, 0xda, 0x3d, 0xb2, 0x69, 0xc4, 0x6f, 0x1e, 0x15,
-	0x2c, 0x1a, 0xe8, 0x11, 0x34, 0x43, 0x12, 0x91, 0xf0, 0x84, 0x58, 0x7a, 0xe0, 0x87, 0x34, 0xd9,
-	0xb0, 0xcd, 0xf9, 0x36, 0xec, 0xc0, 0x0f, 0x29, 0x5e, 0x4d, 0x90, 0x58, 0x2b, 0x42, 0x0f, 0x61,
-	0xd5, 0x3a, 0xf3, 0x0c, 0xd7, 0x36, 0x25, 0x72, 0x75, 0x61, 0xe4, 0x15, 0x09, 0xc4, 0x81, 0xd9,
-	0xcd, 0x3a, 0xd3, 0xc9, 0x16, 0xe6, 0x18, 0x47, 0xc4, 0x91, 0x7b, 0x22, 0x1a, 0xb3, 0xe7, 0xb7,
-	0x22, 0xcf, 0xaf, 0xfa, 0x87, 0x22, 0x34, 0x67, 0x0f, 0x40, 0xa2, 0xbf, 0x80, 0x84, 0xb6, 0x6f,
-	0x65, 0xf4, 0x77, 0xc0, 0x09, 0x4c, 0x47, 0xac, 0xfb, 0x8b, 0xd8, 0xa7, 0x46, 0xa2, 0x23, 0x33,
-	0x88, 0xbf, 0xcf, 0xda, 0xe7, 0x74, 0x5f, 0x3a, 0xa7, 0x7b, 0xf4, 0x36, 0x20, 0xa9, 0x5f, 0xc7,
-	0x76, 0x6d, 0xaa, 0x1f, 0x9d, 0x51, 0x22, 0xf6, 0xbf, 0x84, 0x15, 0xd1, 0xb3, 0xcb, 0x3a, 0x3e,
-	0x61, 0x74, 0xa4, 0xc2, 0xaa, 0xef, 0xbb, 0x7a, 0x64, 0xfa, 0x21, 0xd1, 0x0d, 0xeb, 0x69, 0xab,
-	0xc2, 0x07, 0x36, 0x7c, 0xdf, 0x1d, 0x32, 0x5a, 0xd7, 0x7a, 0xca, 0x1c, 0xae, 0x19, 0xc4, 0x11,
-	0xa1, 0x3a, 0xfb, 0xf0, 0x18, 0x55, 0xc7, 0x20, 0x48, 0xbd, 0x20, 0x8e, 0x32, 0x03, 0x5c, 0xe2,
-	0xb2, 0xb8, 0x93, 0x19, 0xb0, 0x47, 0x5c, 0x36, 0xcb, 0xca, 0x01, 0x09, 0x4d, 0xe2, 0xd1, 0x91,
-	0x6d, 0x1e, 0xb3, 0x90, 0x52, 0x58, 0x2f, 0xe0, 0x19, 0x9a, 0xfa, 0x39, 0x54, 0x78, 0x08, 0x62,
-	0x8b, 0xe7, 0xee, 0x9b, 0x7b, 0x77, 0xb1, 0xbd, 0x35, 0x46, 0xe0, 0xbe, 0xfd, 0x55, 0xa8, 0x4f,
-	0xfc, 0x48, 0xc6, 0x06, 0x61, 0x79, 0x35, 0x46, 0xe0, 0x9d, 0x6d, 0xa8, 0x85, 0xc4, 0xb0, 0x7c,
-	0xcf, 0x39, 0xe3, 0xfb, 0x52, 0xc3, 0x69, 0x5b, 0xfd, 0x02, 0xaa, 0xc2, 0xfd, 0xbe, 0x00, 0xfe,
-	0x4d, 0x40, 0xa6, 0x08, 0x2a, 0x01, 0x09, 0x5d, 0x3b, 0x8a, 0x6c, 0xdf, 0x8b, 0x92, 0xe7, 0x1f,
-	0xd1, 0x73, 0x30, 0xed, 0x50, 0xff, 0x56, 0x10, 0xf9, 0x8e, 0xb8, 0x98, 0xb3, 0x2c, 0x96, 0x59,
-	0x1a, 0xcb, 0xc9, 0x0a, 0xdc, 0x3c, 0x92, 0x26, 0xcb, 0x25, 0x65, 0x5a, 0x53, 0x5c, 0xf4, 0x5d,
-	0x43, 0x02, 0x24, 0xf7, 0x01, 0x22, 0xd3, 0xbe, 0x79, 0xef, 0x03, 0x44, 0xdc, 0x07, 0x08, 0x4b,
-	0x3e, 0x65, 0xc2, 0x25, 0xe0, 0xca, 0x3c, 0xdf, 0x6a, 0x58, 0xe9, 0xa5, 0x8b, 0xa8, 0xff, 0x2a,
-	0xa4, 0xbe, 0x22, 0xb9, 0x1c, 0xa1, 0xc7, 0x50, 0x63, 0xc7, 0x4e, 0x77, 0x8d, 0x40, 0x3e, 0xf5,
-	0xf5, 0x16, 0xbb, 0x77, 0x75, 0xd8, 0x29, 0xdb, 0x33, 0x02, 0x91, 0x2e, 0x2d, 0x07, 0xa2, 0xc5,
-	0x7c, 0x8e, 0x61, 0x4d, 0x7d, 0x0e, 0xfb, 0x47, 0x6f, 0x40, 0xd3, 0x88, 0xa9, 0xaf, 0x1b, 0xd6,
-	0x09, 0x09, 0xa9, 0x1d, 0x11, 0xa9, 0xfb, 0x55, 0x46, 0xed, 0x26, 0xc4, 0xf6, 0x3d, 0x58, 0xc9,
-	0x62, 0x3e, 0x2f, 0xfa, 0x56, 0xb2, 0xd1, 0xf7, 0xc7, 0x00, 0xd3, 0xbc, 0x9d, 0xd9, 0x08, 0x39,
-	0xb5, 0xa9, 0x6e, 0xfa, 0x16, 0x91, 0xaa, 0xac, 0x31, 0x42, 0xcf, 0xb7, 0xc8, 0xb9, 0x5b, 0x50,
-	0x25, 0xb9, 0x05, 0xb1, 0x53, 0xcb, 0x0e, 0xda, 0xb1, 0xed, 0x38, 0xc4, 0x92, 0x12, 0xd6, 0x7d,
-	0xdf, 0x7d, 0xc0, 0x09, 0xea, 0x37, 0x45, 0x61, 0x2b, 0xe2, 0x3e, 0x9b, 0x2b, 0x37, 0xfe, 0xae,
-	0x54, 0x7d, 0x17, 0x20, 0xa2, 0x46, 0xc8, 0x52, 0x09, 0x83, 0xca, 0x27, 0xa2, 0xf6, 0x85, 0x6b,
-	0xd4, 0x28, 0x79, 0x96, 0xc7, 0x75, 0x39, 0xba, 0x4b, 0xd1, 0x07, 0xb0, 0x62, 0xfa, 0x6e, 0xe0,
-	0x10, 0xc9, 0x5c, 0x79, 0x2e, 0x73, 0x23, 0x1d, 0xdf, 0xa5, 0x99, 0x3b, 0x54, 0xf5, 0x45, 0xef,
-	0x50, 0x7f, 0x2e, 0x88, 0x6b, 0x79, 0xf6, 0x55, 0x00, 0x8d, 0x2f, 0x79, 0x7a, 0xde, 0x5a, 0xf0,
-	0x89, 0xe1, 0xdb, 0xde, 0x9d, 0xdb, 0x1f, 0xe4, 0x79, 0xe8, 0x7d, 0x76, 0x72, 0xf7, 0x97, 0x12,
-	0xd4, 0xd3, 0x1b, 0xf9, 0x05, 0xdd, 0xdf, 0x81, 0x7a, 0x5a, 0x13, 0x91, 0x0e, 0xe2, 0x5b, 0xd5,
-	0x93, 0x0e, 0x46, 0x4f, 0x00, 0x19, 0xe3, 0x71, 0x9a, 0xb4, 0xe9, 0x71, 0x64, 0x8c, 0x93, 0xf7,
-	0x90, 0x3b, 0x73, 0xec, 0x43, 0x12, 0xb7, 0x0e, 0x19, 0x3f, 0x56, 0x8c, 0xf1, 0x78, 0x86, 0x82,
-	0x7e, 0x02, 0xd7, 0x67, 0xe7, 0xd0, 0x8f, 0xce, 0xf4, 0xc0, 0xb6, 0xe4, 0x1d, 0x6c, 0x7b, 0xde,
-	0x47, 0x89, 0xce, 0x0c, 0xfc, 0x27, 0x67, 0x07, 0xb6, 0x25, 0xf6, 0x1c, 0x85, 0x17, 0x3a, 0xda,
-	0x3f, 0x83, 0x97, 0x9f, 0x31, 0xfc, 0x12, 0x1d, 0x0c, 0x66, 0x1f, 0xdb, 0x17, 0xdf, 0x84, 0x8c,
-	0xf6, 0x7e, 0x5f, 0x10, 0x6f, 0x27, 0xb3, 0x7b, 0xd2, 0xcd, 0xe6, 0xad, 0x1b, 0x39, 0xe7, 0xe9,
-	0x1d, 0x1c, 0x0a, 0x78, 0x9e, 0xaa, 0x7e, 0x7a, 0x2e, 0x55, 0xcd, 0x9b, 0xc4, 0x88, 0x8c, 0x4f,
-	0x00, 0x49, 0x04, 0xf5, 0x8f, 0x25, 0xa8, 0x25, 0xe8, 0xfc, 0x06, 0x75, 0x16, 0x51, 0xe2, 0xea,
-	0x6e, 0xe2, 0xc2, 0x0a, 0x18, 0x04, 0x69, 0x8f, 0x39, 0xb1, 0x57, 0xa1, 0xce, 0x2e, 0x6a, 0xa2,
-	0xbb, 0xc8, 0xbb, 0x6b, 0x8c, 0xc0, 0x3b, 0x5f, 0x83, 0x06, 0xf5, 0xa9, 0xe1, 0xe8, 0x94, 0xc7,
-	0xf2, 0x92, 0xe0, 0xe6, 0x24, 0x1e, 0xc9, 0xd1, 0x5b, 0x70, 0x85, 0x4e, 0x42, 0x9f, 0x52, 0x87,
-	0xe5, 0x77, 0x3c, 0xa3, 0x11, 0x09, 0x48, 0x19, 0x2b, 0x69, 0x87, 0xc8, 0x74, 0x22, 0xe6, 0xbd,
-	0xa7, 0x83, 0x99, 0xe9, 0x72, 0x27, 0x52, 0xc6, 0xab, 0x29, 0x95, 0x99, 0x36, 0x0b, 0x9e, 0x81,
-	0xc8, 0x16, 0xb8, 0xaf, 0x28, 0xe0, 0xa4, 0x89, 0x74, 0x58, 0x73, 0x89, 0x11, 0xc5, 0x21, 0xb1,
-	0xf4, 0x27, 0x36, 0x71, 0x2c, 0x71, 0xf1, 0x6d, 0xe6, 0x4e, 0xbf, 0x93, 0x6d, 0xe9, 0xdc, 0xe7,
-	0xdc, 0xb8, 0x99, 0xc0, 0x89, 0x36, 0xcb, 0x1c, 0xc4, 0x1f, 0x5a, 0x83, 0xc6, 0xf0, 0xd1, 0x70,
-	0xa4, 0xed, 0xe9, 0x7b, 0xfb, 0x7d, 0x4d, 0xd6, 0x53, 0x86, 0x1a, 0x16, 0xcd, 0x02, 0xeb, 0x1f,
-	0xed, 0x8f, 0xba, 0xbb, 0xfa, 0x68, 0xa7, 0xf7, 0x60, 0xa8, 0x14, 0xd1, 0x75, 0xb8, 0x32, 0xda,
-	0xc6, 0xfb, 0xa3, 0xd1, 0xae, 0xd6, 0xd7, 0x0f, 0x34, 0xbc, 0xb3, 0xdf, 0x1f, 0x2a, 0x25, 0x84,
-	0xa0, 0x39, 0x25, 0x8f, 0x76, 0xf6, 0x34, 0xa5, 0x8c, 0x1a, 0xb0, 0x7c, 0xa0, 0xe1, 0x9e, 0x36,
-	0x18, 0x29, 0x15, 0xf5, 0x3f, 0x45, 0x68, 0x64, 0xb4, 0xc8, 0x0c, 0x39, 0x8c, 0x44, 0x9e, 0x5f,
-	0xc6, 0xec, 0x97, 0x39, 0x13, 0xd3, 0x30, 0x27, 0x42, 0x3b, 0x65, 0x2c, 0x1a, 0x3c, 0xb7, 0x37,
-	0x4e, 0x33, 0xe7, 0xbc, 0x8c, 0x6b, 0xae, 0x71, 0x2a, 0x40, 0x5e, 0x87, 0x95, 0x63, 0x12, 0x7a,
-	0xc4, 0x91, 0xfd, 0x42, 0x23, 0x0d, 0x41, 0x13, 0x43, 0xd6, 0x41, 0x91, 0x43, 0xa6, 0x30, 0x42,
-	0x1d, 0x4d, 0x41, 0xdf, 0x4b, 0xc0, 0xae, 0x41, 0x45, 0x74, 0x2f, 0x8b, 0xf9, 0x79, 0x03, 0x1d,
-	0x5d, 0xd4, 0x45, 0x95, 0xeb, 0xe2, 0xee, 0xfc, 0xa6, 0xfb, 0x2c, 0x75, 0x3c, 0x4e, 0xd5, 0xb1,
-	0x0c, 0x25, 0x9c, 0x14, 0x1c, 0x7a, 0xdd, 0xde, 0x36, 0x53, 0xc1, 0x2a, 0xd4, 0xf7, 0xba, 0x9f,
-	0xe9, 0x87, 0x43, 0xfe, 0xe4, 0x84, 0x14, 0x58, 0x79, 0xa0, 0xe1, 0x81, 0xb6, 0x2b, 0x29, 0x25,
-	0x74, 0x0d, 0x14, 0x49, 0x99, 0x8e, 0x2b, 0x33, 0x04, 0xf1, 0x5b, 0x51, 0xff, 0x5e, 0x84, 0x35,
-	0xe1, 0xf8, 0xd3, 0x07, 0xd1, 0x67, 0xbf, 0x4c, 0x66, 0xdf, 0x09, 0x8a, 0x33, 0xef, 0x04, 0x69,
-	0x9a, 0xc9, 0xe3, 0x76, 0x69, 0x9a, 0x66, 0xf2, 0xf7, 0x85, 0x19, 0x9f, 0x5e, 0x9e, 0xc7, 0xa7,
-	0xb7, 0x60, 0xd9, 0x25, 0x51, 0xaa, 0x99, 0x3a, 0x4e, 0x9a, 0xc8, 0x86, 0x86, 0xe1, 0x79, 0x3e,
-	0xe5, 0xaf, 0x71, 0xc9, 0xc5, 0x67, 0x6b, 0xae, 0x77, 0xbf, 0x74, 0xc5, 0x9d, 0xee, 0x14, 0x49,
-	0xb8, 0xde, 0x2c, 0x76, 0xfb, 0x43, 0x50, 0xce, 0x0f, 0x98, 0x27, 0xe0, 0xbd, 0xf9, 0xee, 0x34,
-	0xde, 0x11, 0x66, 0xf9, 0x87, 0x83, 0x07, 0x83, 0xfd, 0x87, 0x03, 0x65, 0x89, 0x35, 0xf0, 0xe1,
-	0x60, 0xb0, 0x33, 0xd8, 0x52, 0x0a, 0x08, 0xa0, 0xaa, 0x7d, 0xb6, 0x33, 0xd2, 0xfa, 0x4a, 0x71,
-	0xf3, 0x1f, 0xab, 0x50, 0x15, 0x42, 0xa2, 0xaf, 0x65, 0xac, 0xcf, 0x16, 0xdb, 0xd1, 0x87, 0x73,
-	0xe7, 0xcc, 0x33, 0x05, 0xfc, 0xf6, 0x47, 0x0b, 0xf3, 0xcb, 0x07, 0xed, 0x25, 0xf4, 0xeb, 0x02,
-	0xac, 0xcc, 0xbc, 0xe0, 0xe6, 0x7d, 0x7c, 0xbc, 0xa4, 0xb6, 0xdf, 0xfe, 0xde, 0x42, 0xbc, 0xa9,
-	0x2c, 0xbf, 0x2a, 0x40, 0x23, 0x53, 0xd5, 0x46, 0x77, 0x17, 0xa9, 0x84, 0x0b, 0x49, 0xee, 0x2d,
-	0x5e, 0x44, 0x57, 0x97, 0xde, 0x29, 0xa0, 0x5f, 0x16, 0xa0, 0x91, 0xa9, 0xef, 0xe6, 0x16, 0xe5,
-	0x62, 0x35, 0x3a, 0xb7, 0x28, 0x97, 0x95, 0x93, 0x97, 0xd0, 0xcf, 0x0b, 0x50, 0x4f, 0x6b, 0xb5,
-	0xe8, 0xf6, 0xfc, 0xd5, 0x5d, 0x21, 0xc4, 0x9d, 0x45, 0xcb, 0xc2, 0xea, 0x12, 0xfa, 0x29, 0xd4,
-	0x92, 0xc2, 0x26, 0xca, 0x1b, 0x9f, 0xce, 0x55, 0x4d, 0xdb, 0xb7, 0xe7, 0xe6, 0xcb, 0x4e, 0x9f,
-	0x54, 0x1b, 0x73, 0x4f, 0x7f, 0xae, 0x2e, 0xda, 0xbe, 0x3d, 0x37, 0x5f, 0x3a, 0x3d, 0xb3, 0x84,
-	0x4c, 0x51, 0x32, 0xb7, 0x25, 0x5c, 0xac, 0x86, 0xe6, 0xb6, 0x84, 0xcb, 0x6a, 0xa0, 0x42, 0x90,
-	0x4c, 0x59, 0x33, 0xb7, 0x20, 0x17, 0x4b, 0xa7, 0xb9, 0x05, 0xb9, 0xa4, 0x8a, 0xaa, 0x2e, 0xa1,
-	0xaf, 0x0a, 0xd9, 0xcc, 0xff, 0xf6, 0xdc, 0xd5, 0xbb, 0x39, 0x4d, 0xf2, 0x42, 0xfd, 0x90, 0x1f,
-	0xd0, 0xaf, 0xe4, 0x3b, 0x85, 0x28, 0xfe, 0xa1, 0x79, 0xc0, 0x66, 0xea, 0x85, 0xed, 0x5b, 0x8b,
-	0x05, 0x1b, 0x2e, 0xc4, 0x2f, 0x0a, 0x00, 0xd3, 0x32, 0x61, 0x6e, 0x21, 0x2e, 0xd4, 0x27, 0xdb,
-	0x77, 0x17, 0xe0, 0xcc, 0x1e, 0x90, 0xa4, 0x32, 0x98, 0xfb, 0x80, 0x9c, 0x2b, 0x63, 0xe6, 0x3e,
-	0x20, 0xe7, 0x4b, 0x90, 0xea, 0xd2, 0x27, 0xcb, 0x3f, 0xa8, 0x88, 0xe8, 0x5f, 0xe5, 0x9f, 0xf7,
-	0xfe, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xc9, 0x5b, 0xdc, 0xf6, 0x89, 0x27, 0x00, 0x00,
+	// 3238 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x5a, 0xdd, 0x6f, 0x1b, 0xc7,
+	0x11, 0x37, 0x49, 0x91, 0x22, 0x87, 0x12, 0x75, 0x5a, 0xc9, 0x09, 0xc3, 0xa0, 0x8d, 0x73, 0x45,
+	0x0a, 0x21, 0x89, 0x29, 0x59, 0x6e, 0x2d, 0xdb, 0xcd, 0x17, 0x43, 0xd1, 0x92, 0x62, 0x89, 0x52,
+	0x97, 0x14, 0x1c, 0xb7, 0x8d, 0xaf, 0xc7, 0xbb, 0x15, 0x79, 0xd6, 0x7d, 0xe5, 0x3e, 0x14, 0x09,
+	0x45, 0xd1, 0x22, 0x05, 0x8a, 0xf6, 0xa1, 0x45, 0x5f, 0x82, 0xfe, 0x11, 0xfd, 0x0f, 0x5a, 0xe4,
+	0x6f, 0xe8, 0x1f, 0xd0, 0xbe, 0xb4, 0x45, 0x81, 0xbe, 0x16, 0xc8, 0x4b, 0xdf, 0x8a, 0xfd, 0xb8,
+	0xe3, 0x1d, 0x25, 0xc7, 0x47, 0x3a, 0x4f, 0xe4, 0xce, 0xec, 0xfc, 0x66, 0x6e, 0x67, 0x76, 0x76,
+	0xf6, 0x03, 0x64, 0xd7, 0x0c, 0x87, 0x86, 0xed, 0xaf, 0xeb, 0x9e, 0x71, 0x46, 0x3c, 0x7f, 0xdd,
+	0xf5, 0x9c, 0xc0, 0x11, 0xad, 0x26, 0x6b, 0xa0, 0x37, 0x46, 0xaa, 0x3f, 0x32, 0x34, 0xc7, 0x73,
+	0x9b, 0xb6, 0x63, 0xa9, 0x7a, 0x53, 0xc8, 0x34, 0x85, 0x0c, 0xef, 0xd6, 0xf8, 0xf6, 0xd0, 0x71,
+	0x86, 0x26, 0xe1, 0x08, 0x83, 0xf0, 0x64, 0x5d, 0x0f, 0x3d, 0x35, 0x30, 0x1c, 0x5b, 0xf0, 0x5f,
+	0x9b, 0xe4, 0x07, 0x86, 0x45, 0xfc, 0x40, 0xb5, 0x5c, 0xd1, 0xe1, 0x83, 0xa1, 0x11, 0x8c, 0xc2,
+	0x41, 0x53, 0x73, 0xac, 0xf5, 0x58, 0xe5, 0x3a, 0x53, 0xb9, 0x1e, 0x99, 0xe9, 0x8f, 0x54, 0x8f,
+	0xe8, 0xeb, 0x23, 0xcd, 0xf4, 0x5d, 0xa2, 0xd1, 0x5f, 0x85, 0xfe, 0x11, 0x08, 0x3b, 0xd9, 0x11,
+	0xfc, 0xc0, 0x0b, 0xb5, 0x20, 0xfa, 0x5e, 0x35, 0x08, 0x3c, 0x63, 0x10, 0x06, 0x84, 0x03, 0xc9,
+	0xaf, 0xc0, 0xcb, 0x7d, 0xd5, 0x3f, 0x6d, 0x3b, 0xf6, 0x89, 0x31, 0xec, 0x69, 0x23, 0x62, 0xa9,
+	0x98, 0x7c, 0x1a, 0x12, 0x3f, 0x90, 0x7f, 0x02, 0xf5, 0xcb, 0x2c, 0xdf, 0x75, 0x6c, 0x9f, 0xa0,
+	0x0f, 0x60, 0x8e, 0x5a, 0x53, 0xcf, 0xdd, 0xc8, 0xad, 0x55, 0x37, 0xdf, 0x6e, 0x3e, 0x6b, 0xe0,
+	0xb8, 0x0d, 0x4d, 0xf1, 0x15, 0xcd, 0x9e, 0x4b, 0x34, 0xcc, 0x24, 0xe5, 0xeb, 0xb0, 0xd2, 0x56,
+	0x5d, 0x75, 0x60, 0x98, 0x46, 0x60, 0x10, 0x3f, 0x52, 0x1a, 0xc2, 0x6a, 0x9a, 0x2c, 0x14, 0x7e,
+	0x02, 0x0b, 0x5a, 0x82, 0x2e, 0x14, 0xdf, 0x6b, 0x66, 0xf2, 0x58, 0x73, 0x9b, 0xb5, 0x52, 0xc0,
+	0x29, 0x38, 0x79, 0x15, 0xd0, 0x03, 0xc3, 0x1e, 0x12, 0xcf, 0xf5, 0x0c, 0x3b, 0x88, 0x8c, 0xf9,
+	0xb2, 0x00, 0x2b, 0x29, 0xb2, 0x30, 0xe6, 0x29, 0x40, 0x3c, 0x8e, 0xd4, 0x94, 0xc2, 0x5a, 0x75,
+	0xf3, 0xa3, 0x8c, 0xa6, 0x5c, 0x81, 0xd7, 0x6c, 0xc5, 0x60, 0x1d, 0x3b, 0xf0, 0x2e, 0x70, 0x02,
+	0x1d, 0x3d, 0x81, 0xd2, 0x88, 0xa8, 0x66, 0x30, 0xaa, 0xe7, 0x6f, 0xe4, 0xd6, 0x6a, 0x9b, 0x0f,
+	0x5e, 0x40, 0xcf, 0x2e, 0x03, 0xea, 0x05, 0x6a, 0x40, 0xb0, 0x40, 0x45, 0x37, 0x01, 0xf1, 0x7f,
+	0x8a, 0x4e, 0x7c, 0xcd, 0x33, 0x5c, 0x1a, 0xc8, 0xf5, 0xc2, 0x8d, 0xdc, 0x5a, 0x05, 0x2f, 0x73,
+	0xce, 0xf6, 0x98, 0xd1, 0x70, 0x61, 0x69, 0xc2, 0x5a, 0x24, 0x41, 0xe1, 0x94, 0x5c, 0x30, 0x8f,
+	0x54, 0x30, 0xfd, 0x8b, 0x76, 0xa0, 0x78, 0xa6, 0x9a, 0x21, 0x61, 0x26, 0x57, 0x37, 0x6f, 0x3d,
+	0x2f, 0x3c, 0x44, 0x88, 0x8e, 0xc7, 0x01, 0x73, 0xf9, 0xfb, 0xf9, 0xbb, 0x39, 0xf9, 0x1e, 0x54,
+	0x13, 0x76, 0xa3, 0x1a, 0xc0, 0x71, 0x77, 0xbb, 0xd3, 0xef, 0xb4, 0xfb, 0x9d, 0x6d, 0xe9, 0x1a,
+	0x5a, 0x84, 0xca, 0x71, 0x77, 0xb7, 0xd3, 0xda, 0xef, 0xef, 0x3e, 0x96, 0x72, 0xa8, 0x0a, 0xf3,
+	0x51, 0x23, 0x2f, 0x9f, 0x03, 0xc2, 0x44, 0x73, 0xce, 0x88, 0x47, 0x03, 0x59, 0x78, 0x15, 0xbd,
+	0x0c, 0xf3, 0x81, 0xea, 0x9f, 0x2a, 0x86, 0x2e, 0x6c, 0x2e, 0xd1, 0xe6, 0x9e, 0x8e, 0xf6, 0xa0,
+	0x34, 0x52, 0x6d, 0xdd, 0x7c, 0xbe, 0xdd, 0xe9, 0xa1, 0xa6, 0xe0, 0xbb, 0x4c, 0x10, 0x0b, 0x00,
+	0x1a, 0xdd, 0x29, 0xcd, 0xdc, 0x01, 0xf2, 0x63, 0x90, 0x7a, 0x81, 0xea, 0x05, 0x49, 0x73, 0x3a,
+	0x30, 0x47, 0xf5, 0xd7, 0x73, 0x53, 0xeb, 0xe4, 0x33, 0x13, 0x33, 0x71, 0xf9, 0xbf, 0x79, 0x58,
+	0x4e, 0x60, 0x8b, 0x48, 0x7d, 0x04, 0x25, 0x8f, 0xf8, 0xa1, 0x19, 0x30, 0xf8, 0xda, 0xe6, 0xfb,
+	0x19, 0xe1, 0x2f, 0x21, 0x35, 0x31, 0x83, 0xc1, 0x02, 0x0e, 0xad, 0x81, 0xc4, 0x25, 0x14, 0xe2,
+	0x79, 0x8e, 0xa7, 0x58, 0xfe, 0x90, 0x8d, 0x5a, 0x05, 0xd7, 0x38, 0xbd, 0x43, 0xc9, 0x07, 0xfe,
+	0x30, 0x31, 0xaa, 0x85, 0x17, 0x1c, 0x55, 0xa4, 0x82, 0x64, 0x93, 0xe0, 0x33, 0xc7, 0x3b, 0x55,
+	0xe8, 0xd0, 0x7a, 0x86, 0x4e, 0xea, 0x73, 0x0c, 0xf4, 0x4e, 0x46, 0xd0, 0x2e, 0x17, 0x3f, 0x14,
+	0xd2, 0x78, 0xc9, 0x4e, 0x13, 0xe4, 0xb7, 0xa0, 0xc4, 0xbf, 0x94, 0x46, 0x52, 0xef, 0xb8, 0xdd,
+	0xee, 0xf4, 0x7a, 0xd2, 0x35, 0x54, 0x81, 0x22, 0xee, 0xf4, 0x31, 0x8d, 0xb0, 0x0a, 0x14, 0x1f,
+	0xb4, 0xfa, 0xad, 0x7d, 0x29, 0x2f, 0xbf, 0x09, 0x4b, 0x8f, 0x54, 0x23, 0xc8, 0x12, 0x5c, 0xb2,
+	0x03, 0xd2, 0xb8, 0xaf, 0xf0, 0xce, 0x5e, 0xca, 0x3b, 0xd9, 0x87, 0xa6, 0x73, 0x6e, 0x04, 0x13,
+	0xfe, 0x90, 0xa0, 0x40, 0x3c, 0x4f, 0xb8, 0x80, 0xfe, 0x95, 0x3f, 0x83, 0xa5, 0x5e, 0xe0, 0xb8,
+	0x99, 0x22, 0xff, 0x36, 0xcc, 0xd3, 0x35, 0xca, 0x09, 0x03, 0x11, 0xfa, 0xaf, 0x34, 0xf9, 0x1a,
+	0xd6, 0x8c, 0xd6, 0xb0, 0xe6, 0xb6, 0x58, 0xe3, 0x70, 0xd4, 0x13, 0xbd, 0x04, 0x25, 0xdf, 0x18,
+	0xda, 0xaa, 0x29, 0xb2, 0x85, 0x68, 0xc9, 0x08, 0xa4, 0xb1, 0x62, 0x11, 0xf8, 0x6d, 0x40, 0xdb,
+	0xc4, 0x0f, 0x3c, 0xe7, 0x22, 0x93, 0x3d, 0xab, 0x50, 0x3c, 0x71, 0x3c, 0x8d, 0x4f, 0xc4, 0x32,
+	0xe6, 0x0d, 0x3a, 0xa9, 0x52, 0x20, 0x02, 0xfb, 0x26, 0xa0, 0x3d, 0x9b, 0xae, 0x29, 0xd9, 0x1c,
+	0xf1, 0x87, 0x3c, 0xac, 0xa4, 0xfa, 0x0b, 0x67, 0xcc, 0x3e, 0x0f, 0x69, 0x62, 0x0a, 0x7d, 0x3e,
+	0x0f, 0xd1, 0x21, 0x94, 0x78, 0x0f, 0x31, 0x92, 0x5b, 0x53, 0x00, 0xf1, 0x65, 0x4a, 0xc0, 0x09,
+	0x98, 0x2b, 0x83, 0xbe, 0xf0, 0xcd, 0x06, 0xfd, 0x67, 0x20, 0x45, 0xdf, 0xe1, 0x3f, 0xd7, 0x37,
+	0x1f, 0xc1, 0x8a, 0xe6, 0x98, 0x26, 0xd1, 0x68, 0x34, 0x28, 0x86, 0x1d, 0x10, 0xef, 0x4c, 0x35,
+	0x9f, 0x1f, 0x37, 0x68, 0x2c, 0xb5, 0x27, 0x84, 0xe4, 0x1f, 0xc3, 0x72, 0x42, 0xb1, 0x70, 0xc4,
+	0x03, 0x28, 0xfa, 0x94, 0x20, 0x3c, 0xb1, 0x31, 0xa5, 0x27, 0x7c, 0xcc, 0xc5, 0xe5, 0x15, 0x0e,
+	0xde, 0x39, 0x23, 0x76, 0xfc, 0x59, 0xf2, 0x36, 0x2c, 0xf7, 0x58, 0x98, 0x66, 0x8a, 0xc3, 0x71,
+	0x88, 0xe7, 0x53, 0x21, 0xbe, 0x0a, 0x28, 0x89, 0x22, 0x02, 0xf1, 0x02, 0x96, 0x3a, 0xe7, 0x44,
+	0xcb, 0x84, 0x5c, 0x87, 0x79, 0xcd, 0xb1, 0x2c, 0xd5, 0xd6, 0xeb, 0xf9, 0x1b, 0x85, 0xb5, 0x0a,
+	0x8e, 0x9a, 0xc9, 0xb9, 0x58, 0xc8, 0x3a, 0x17, 0xe5, 0xdf, 0xe5, 0x40, 0x1a, 0xeb, 0x16, 0x03,
+	0x49, 0xad, 0x0f, 0x74, 0x0a, 0x44, 0x75, 0x2f, 0x60, 0xd1, 0x12, 0xf4, 0x28, 0x5d, 0x70, 0x3a,
+	0xf1, 0xbc, 0x44, 0x3a, 0x2a, 0xbc, 0x60, 0x3a, 0x92, 0xff, 0x9d, 0x03, 0x74, 0xb9, 0xe8, 0x42,
+	0xaf, 0xc3, 0x82, 0x4f, 0x6c, 0x5d, 0xe1, 0xc3, 0xc8, 0x3d, 0x5c, 0xc6, 0x55, 0x4a, 0xe3, 0xe3,
+	0xe9, 0x23, 0x04, 0x73, 0xe4, 0x9c, 0x68, 0x62, 0xe6, 0xb3, 0xff, 0x68, 0x04, 0x0b, 0x27, 0xbe,
+	0x62, 0xf8, 0x8e, 0xa9, 0xc6, 0xd5, 0x49, 0x6d, 0xb3, 0x33, 0x73, 0xf1, 0xd7, 0x7c, 0xd0, 0xdb,
+	0x8b, 0xc0, 0x70, 0xf5, 0xc4, 0x8f, 0x1b, 0x72, 0x13, 0xaa, 0x09, 0x1e, 0x2a, 0xc3, 0x5c, 0xf7,
+	0xb0, 0xdb, 0x91, 0xae, 0x21, 0x80, 0x52, 0x7b, 0x17, 0x1f, 0x1e, 0xf6, 0xf9, 0x0a, 0xb0, 0x77,
+	0xd0, 0xda, 0xe9, 0x48, 0x79, 0xf9, 0x5f, 0x25, 0x80, 0xf1, 0x52, 0x8c, 0x6a, 0x90, 0x8f, 0x3d,
+	0x9d, 0x37, 0x74, 0xfa, 0x31, 0xb6, 0x6a, 0x11, 0x11, 0x3d, 0xec, 0x3f, 0xda, 0x84, 0xeb, 0x96,
+	0x3f, 0x74, 0x55, 0xed, 0x54, 0x11, 0x2b, 0xa8, 0xc6, 0x84, 0xd9, 0x57, 0x2d, 0xe0, 0x15, 0xc1,
+	0x14, 0x56, 0x73, 0xdc, 0x7d, 0x28, 0x10, 0xfb, 0xac, 0x3e, 0xc7, 0x2a, 0xcd, 0xfb, 0x53, 0x97,
+	0x08, 0xcd, 0x8e, 0x7d, 0xc6, 0x2b, 0x4b, 0x0a, 0x83, 0x14, 0x00, 0x9d, 0x9c, 0x19, 0x1a, 0x51,
+	0x28, 0x68, 0x91, 0x81, 0x7e, 0x30, 0x3d, 0xe8, 0x36, 0xc3, 0x88, 0xa1, 0x2b, 0x7a, 0xd4, 0x46,
+	0x5d, 0xa8, 0x78, 0xc4, 0x77, 0x42, 0x4f, 0x23, 0x7e, 0xbd, 0x34, 0xd5, 0x2c, 0xc6, 0x91, 0x1c,
+	0x1e, 0x43, 0xa0, 0x6d, 0x28, 0x59, 0x4e, 0x68, 0x07, 0x7e, 0x7d, 0xfe, 0x46, 0xe1, 0x6b, 0xf7,
+	0x1b, 0x69, 0xb0, 0x03, 0x2a, 0x84, 0x85, 0x2c, 0xda, 0x81, 0x79, 0x6e, 0xa2, 0x5f, 0x2f, 0x33,
+	0x98, 0x9b, 0x59, 0x03, 0x88, 0x49, 0xe1, 0x48, 0x9a, 0x7a, 0x35, 0xf4, 0x89, 0x57, 0xaf, 0x70,
+	0xaf, 0xd2, 0xff, 0xe8, 0x55, 0xa8, 0xa8, 0xa6, 0xe9, 0x68, 0x8a, 0x6e, 0x78, 0x75, 0x60, 0x8c,
+	0x32, 0x23, 0x6c, 0x1b, 0x1e, 0x7a, 0x0d, 0xaa, 0x7c, 0xea, 0x29, 0xae, 0x1a, 0x8c, 0xea, 0x55,
+	0xc6, 0x06, 0x4e, 0x3a, 0x52, 0x83, 0x91, 0xe8, 0x40, 0x3c, 0x8f, 0x77, 0x58, 0x88, 0x3b, 0x10,
+	0xcf, 0x63, 0x1d, 0xbe, 0x0b, 0x4b, 0x2c, 0x8f, 0x0c, 0x3d, 0x27, 0x74, 0x15, 0x16, 0x53, 0x8b,
+	0xac, 0xd3, 0x22, 0x25, 0xef, 0x50, 0x6a, 0x97, 0x06, 0xd7, 0x2b, 0x50, 0x7e, 0xea, 0x0c, 0x78,
+	0x87, 0x1a, 0xeb, 0x30, 0xff, 0xd4, 0x19, 0x44, 0x2c, 0x6e, 0xa1, 0xa1, 0xd7, 0x97, 0x38, 0x8b,
+	0xb5, 0xf7, 0x74, 0x5a, 0xcc, 0xf1, 0x4a, 0x5c, 0xb1, 0x88, 0xe5, 0x78, 0x17, 0x8a, 0x35, 0xa8,
+	0x4b, 0x37, 0x72, 0x6b, 0x05, 0x5c, 0xe3, 0xf4, 0x03, 0x46, 0x3e, 0x18, 0x34, 0xee, 0x40, 0x39,
+	0x72, 0xf8, 0x15, 0x75, 0xff, 0x6a, 0xb2, 0xee, 0xaf, 0x24, 0x8a, 0xf8, 0xc6, 0x3b, 0x50, 0x4b,
+	0x87, 0xcb, 0x34, 0xd2, 0xf2, 0xdf, 0x72, 0x50, 0x89, 0x03, 0x03, 0xd9, 0xb0, 0xc2, 0x0c, 0x57,
+	0x03, 0xa2, 0x2b, 0xe3, 0x38, 0xe3, 0xab, 0xc5, 0xbb, 0x19, 0x7d, 0xda, 0x8a, 0x10, 0x44, 0xc6,
+	0x14, 0x41, 0x87, 0x62, 0xe4, 0xb1, 0xbe, 0x27, 0xb0, 0x64, 0x1a, 0x76, 0x78, 0x9e, 0xd0, 0xc5,
+	0x17, 0xbb, 0xef, 0x67, 0xd4, 0xb5, 0x4f, 0xa5, 0xc7, 0x3a, 0x6a, 0x66, 0xaa, 0x2d, 0x7f, 0x91,
+	0x87, 0x97, 0xae, 0x36, 0x07, 0x75, 0xa1, 0xa0, 0xb9, 0xa1, 0xf8, 0xb4, 0x77, 0xa6, 0xfd, 0xb4,
+	0xb6, 0x1b, 0x8e, 0xb5, 0x52, 0x20, 0xba, 0x1d, 0xe0, 0x1e, 0x16, 0x5f, 0xf0, 0xfe, 0xb4, 0x90,
+	0x3c, 0x10, 0xc6, 0xa8, 0x02, 0x0e, 0x61, 0x28, 0x8b, 0xa2, 0xc2, 0x17, 0x09, 0x65, 0xca, 0xe2,
+	0x24, 0x82, 0xc4, 0x31, 0x8e, 0x7c, 0x07, 0xae, 0x5f, 0xf9, 0x29, 0xe8, 0x5b, 0x00, 0x9a, 0x1b,
+	0x2a, 0x2c, 0x34, 0xb9, 0xdf, 0x0b, 0xb8, 0xa2, 0xb9, 0x61, 0x8f, 0x11, 0xe4, 0x2d, 0xa8, 0x3f,
+	0xcb, 0x5e, 0x3a, 0x4d, 0xc7, 0x21, 0x9e, 0x67, 0x92, 0x65, 0x4b, 0x04, 0xb7, 0xfc, 0xc7, 0x3c,
+	0x2c, 0x4d, 0x98, 0x43, 0xd7, 0x4a, 0x3e, 0xed, 0xa3, 0xf5, 0x9b, 0xb7, 0x68, 0x0e, 0xd0, 0x0c,
+	0x3d, 0x2a, 0xb8, 0xd9, 0x7f, 0x96, 0xfd, 0x5d, 0x51, 0x0c, 0xe7, 0x0d, 0x97, 0x06, 0xb4, 0x35,
+	0x30, 0x02, 0x9f, 0xed, 0x51, 0x8a, 0x98, 0x37, 0xd0, 0x63, 0xa8, 0x79, 0xc4, 0x27, 0xde, 0x19,
+	0xd1, 0x15, 0xd7, 0xf1, 0x82, 0x68, 0xc0, 0x36, 0xa7, 0x1b, 0xb0, 0x23, 0xc7, 0x0b, 0xf0, 0x62,
+	0x84, 0x44, 0x5b, 0x3e, 0x7a, 0x04, 0x8b, 0xfa, 0x85, 0xad, 0x5a, 0x86, 0x26, 0x90, 0x4b, 0x33,
+	0x23, 0x2f, 0x08, 0x20, 0x06, 0x4c, 0xf7, 0xe0, 0x09, 0x26, 0xfd, 0x30, 0x53, 0x1d, 0x10, 0x53,
+	0x8c, 0x09, 0x6f, 0xa4, 0xe7, 0x6f, 0x51, 0xcc, 0x5f, 0xf9, 0xaf, 0x05, 0xa8, 0xa5, 0x27, 0x40,
+	0xe4, 0x3f, 0x97, 0x78, 0x86, 0xa3, 0x27, 0xfc, 0x77, 0xc4, 0x08, 0xd4, 0x47, 0x94, 0xfd, 0x69,
+	0xe8, 0x04, 0x6a, 0xe4, 0x23, 0xcd, 0x0d, 0x7f, 0x48, 0xdb, 0x13, 0xbe, 0x2f, 0x4c, 0xf8, 0x1e,
+	0xbd, 0x0d, 0x48, 0xf8, 0xd7, 0x34, 0x2c, 0x23, 0x50, 0x06, 0x17, 0x01, 0xe1, 0xe3, 0x5f, 0xc0,
+	0x12, 0xe7, 0xec, 0x53, 0xc6, 0x87, 0x94, 0x8e, 0x64, 0x58, 0x74, 0x1c, 0x4b, 0xf1, 0x35, 0xc7,
+	0x23, 0x8a, 0xaa, 0x3f, 0xad, 0x17, 0x59, 0xc7, 0xaa, 0xe3, 0x58, 0x3d, 0x4a, 0x6b, 0xe9, 0x4f,
+	0x69, 0x6a, 0xd6, 0xdc, 0xd0, 0x27, 0x81, 0x42, 0x7f, 0xd8, 0x6a, 0x56, 0xc1, 0xc0, 0x49, 0x6d,
+	0x37, 0xf4, 0x13, 0x1d, 0x2c, 0x62, 0xd1, 0x15, 0x2a, 0xd1, 0xe1, 0x80, 0x58, 0x54, 0xcb, 0xc2,
+	0x11, 0xf1, 0x34, 0x62, 0x07, 0x7d, 0x43, 0x3b, 0xa5, 0x8b, 0x4f, 0x6e, 0x2d, 0x87, 0x53, 0x34,
+	0xf4, 0x1d, 0x58, 0x1c, 0x85, 0x43, 0xa2, 0xb8, 0xea, 0x90, 0xf8, 0x9b, 0xd6, 0x80, 0xad, 0x2d,
+	0x05, 0xbc, 0x40, 0x89, 0x47, 0x82, 0x96, 0xee, 0x74, 0x6b, 0x38, 0xa8, 0xc3, 0x44, 0xa7, 0x5b,
+	0xc3, 0x01, 0xda, 0x80, 0x55, 0x3a, 0x40, 0x1e, 0x51, 0x4d, 0x5a, 0x1c, 0x2a, 0x5e, 0x68, 0xd3,
+	0x5f, 0xb6, 0xe8, 0x14, 0x30, 0xd2, 0xe8, 0x44, 0xe2, 0x2c, 0xcc, 0x39, 0xa8, 0x09, 0x2b, 0x29,
+	0x09, 0xe1, 0x97, 0x05, 0x26, 0xb0, 0x9c, 0x10, 0xe0, 0xfe, 0x91, 0x3f, 0x81, 0x22, 0x5b, 0x58,
+	0xa9, 0xa3, 0xd8, 0xa2, 0xc4, 0xd6, 0x2c, 0x1e, 0x0a, 0x65, 0x4a, 0x60, 0x2b, 0xd6, 0xab, 0x50,
+	0x19, 0x39, 0xbe, 0x58, 0xf1, 0xf8, 0x2c, 0x29, 0x53, 0x02, 0x63, 0x36, 0xa0, 0xec, 0x11, 0x55,
+	0x77, 0x6c, 0xf3, 0x82, 0xf9, 0xb0, 0x8c, 0xe3, 0xb6, 0xfc, 0x29, 0x94, 0xf8, 0x52, 0xf1, 0x02,
+	0xf8, 0x37, 0x01, 0x69, 0x7c, 0xa9, 0x74, 0x89, 0x67, 0x19, 0xbe, 0x6f, 0x38, 0xb6, 0x1f, 0x1d,
+	0x6a, 0x71, 0xce, 0xd1, 0x98, 0x21, 0xff, 0x3d, 0x07, 0x30, 0x3e, 0x6e, 0xa0, 0xb5, 0x39, 0x9d,
+	0x15, 0xb4, 0xd2, 0xcc, 0xb1, 0x50, 0x8e, 0x9a, 0xb4, 0x42, 0x16, 0xc5, 0x5a, 0x7e, 0xd6, 0xd3,
+	0x1a, 0x01, 0x10, 0xed, 0x72, 0x88, 0x28, 0x66, 0xa7, 0xdd, 0xe5, 0x10, 0xbe, 0xcb, 0x21, 0xb4,
+	0xa4, 0x16, 0x65, 0x24, 0x87, 0x9b, 0x63, 0x55, 0x64, 0x55, 0x8f, 0xb7, 0x92, 0x44, 0xfe, 0x4f,
+	0x2e, 0xce, 0x6b, 0xd1, 0x96, 0x0f, 0x3d, 0x81, 0x32, 0x4d, 0x11, 0x8a, 0xa5, 0xba, 0xe2, 0x00,
+	0xb3, 0x3d, 0xdb, 0x6e, 0xb2, 0x49, 0x33, 0xc2, 0x81, 0xea, 0xf2, 0x22, 0x70, 0xde, 0xe5, 0x2d,
+	0x9a, 0x1f, 0x55, 0x7d, 0x9c, 0x1f, 0xe9, 0x7f, 0xf4, 0x06, 0xd4, 0xd4, 0x30, 0x70, 0x14, 0x55,
+	0x3f, 0x23, 0x5e, 0x60, 0xf8, 0x44, 0xf8, 0x7e, 0x91, 0x52, 0x5b, 0x11, 0xb1, 0x71, 0x1f, 0x16,
+	0x92, 0x98, 0xcf, 0xab, 0x14, 0x8a, 0xc9, 0x4a, 0xe1, 0xa7, 0x00, 0xe3, 0xdd, 0x08, 0x8d, 0x11,
+	0x72, 0x6e, 0x04, 0x8a, 0xe6, 0xe8, 0x44, 0xb8, 0xb2, 0x4c, 0x09, 0x6d, 0x47, 0x27, 0x13, 0x7b,
+	0xbb, 0x62, 0xb4, 0xb7, 0xa3, 0x19, 0x86, 0x26, 0x85, 0x53, 0xc3, 0x34, 0x89, 0x2e, 0x2c, 0xac,
+	0x38, 0x8e, 0xf5, 0x90, 0x11, 0xe4, 0x2f, 0xf3, 0x3c, 0x56, 0xf8, 0x2e, 0x3d, 0x53, 0xc5, 0xff,
+	0x4d, 0xb9, 0xfa, 0x1e, 0x80, 0x1f, 0xa8, 0x1e, 0x2d, 0x7b, 0xd4, 0x40, 0x1c, 0x7c, 0x35, 0x2e,
+	0x6d, 0x0e, 0xfb, 0xd1, 0x65, 0x03, 0xae, 0x88, 0xde, 0xad, 0x00, 0xbd, 0x0b, 0x0b, 0x9a, 0x63,
+	0xb9, 0x26, 0x11, 0xc2, 0xc5, 0xe7, 0x0a, 0x57, 0xe3, 0xfe, 0xad, 0x20, 0xb1, 0x33, 0x2c, 0xbd,
+	0xe8, 0xce, 0xf0, 0xcf, 0x39, 0x7e, 0xd8, 0x90, 0x3c, 0xeb, 0x40, 0xc3, 0x2b, 0x0e, 0xd4, 0x77,
+	0x66, 0x3c, 0x38, 0xf9, 0xba, 0xd3, 0xf4, 0xc6, 0xbb, 0x59, 0x8e, 0xaf, 0x9f, 0x5d, 0x88, 0xfe,
+	0xa5, 0x00, 0x95, 0xc8, 0x2d, 0x97, 0x7d, 0x7f, 0x17, 0x2a, 0xf1, 0x4d, 0x4f, 0x3d, 0xff, 0xdc,
+	0x11, 0x1e, 0x77, 0x46, 0x27, 0x80, 0xd4, 0xe1, 0x30, 0x2e, 0x30, 0x95, 0xd0, 0x57, 0x87, 0xd1,
+	0x29, 0xcf, 0xdd, 0x29, 0xc6, 0x21, 0x5a, 0x63, 0x8f, 0xa9, 0x3c, 0x96, 0xd4, 0xe1, 0x30, 0x45,
+	0x41, 0x3f, 0x83, 0xeb, 0x69, 0x1d, 0xca, 0xe0, 0x42, 0x71, 0x0d, 0x5d, 0xec, 0x2c, 0x77, 0xa7,
+	0x3d, 0x6a, 0x69, 0xa6, 0xe0, 0x3f, 0xbc, 0x38, 0x32, 0x74, 0x3e, 0xe6, 0xc8, 0xbb, 0xc4, 0x68,
+	0xfc, 0x02, 0x5e, 0x7e, 0x46, 0xf7, 0x2b, 0x7c, 0xd0, 0x4d, 0x5f, 0x21, 0xcc, 0x3e, 0x08, 0x09,
+	0xef, 0x7d, 0x95, 0x83, 0xe5, 0x4b, 0x1d, 0x50, 0x2b, 0x59, 0x63, 0xaf, 0x67, 0xd4, 0xd3, 0x3e,
+	0x3a, 0xe6, 0xf0, 0x54, 0x16, 0x7d, 0x34, 0x51, 0x56, 0x67, 0x2d, 0xb8, 0x78, 0x75, 0xca, 0x81,
+	0xa2, 0x4a, 0xfa, 0x08, 0xca, 0xae, 0x47, 0x7c, 0x3f, 0xf4, 0xa2, 0x00, 0xf8, 0x5e, 0x46, 0xb4,
+	0x23, 0x21, 0xc6, 0xf1, 0x62, 0x14, 0xf9, 0x4f, 0x05, 0x28, 0x47, 0xf6, 0xb2, 0x9d, 0xe6, 0x85,
+	0x1f, 0x10, 0x4b, 0xb1, 0xa2, 0xa4, 0x98, 0xc3, 0xc0, 0x49, 0x07, 0x34, 0x2d, 0xbe, 0x0a, 0x95,
+	0xd0, 0x27, 0x1e, 0x67, 0xe7, 0x19, 0xbb, 0x4c, 0x09, 0x8c, 0xf9, 0x1a, 0x54, 0x03, 0x27, 0x50,
+	0x4d, 0x25, 0x60, 0x95, 0x4c, 0x81, 0x4b, 0x33, 0x12, 0xaf, 0x63, 0xde, 0x82, 0xe5, 0x60, 0xe4,
+	0x39, 0x41, 0x60, 0xd2, 0xea, 0x96, 0xd5, 0x0b, 0xbc, 0xfc, 0x9a, 0xc3, 0x52, 0xcc, 0xe0, 0x75,
+	0x84, 0x4f, 0xd7, 0x83, 0x71, 0x67, 0x56, 0xa4, 0x14, 0x59, 0xcf, 0xc5, 0x98, 0x4a, 0x27, 0x0b,
+	0x5d, 0x8e, 0x5d, 0x5e, 0x2b, 0xb1, 0xec, 0x93, 0xc3, 0x51, 0x13, 0x29, 0xb0, 0x64, 0x11, 0x95,
+	0x7e, 0xa4, 0xae, 0x9c, 0x18, 0xc4, 0xd4, 0xf9, 0x01, 0x41, 0x2d, 0xf3, 0xe6, 0x23, 0x1a, 0x96,
+	0xe6, 0x03, 0x26, 0x8d, 0x6b, 0x11, 0x1c, 0x6f, 0xd3, 0x5a, 0x84, 0xff, 0x43, 0x4b, 0x50, 0xed,
+	0x3d, 0xee, 0xf5, 0x3b, 0x07, 0xca, 0xc1, 0xe1, 0x76, 0x47, 0xdc, 0x3b, 0xf5, 0x3a, 0x98, 0x37,
+	0x73, 0x94, 0xdf, 0x3f, 0xec, 0xb7, 0xf6, 0x95, 0xfe, 0x5e, 0xfb, 0x61, 0x4f, 0xca, 0xa3, 0xeb,
+	0xb0, 0xdc, 0xdf, 0xc5, 0x87, 0xfd, 0xfe, 0x7e, 0x67, 0x5b, 0x39, 0xea, 0xe0, 0xbd, 0xc3, 0xed,
+	0x9e, 0x54, 0x40, 0x08, 0x6a, 0x63, 0x72, 0x7f, 0xef, 0xa0, 0x23, 0xcd, 0xd1, 0x9b, 0x86, 0xa3,
+	0x0e, 0x6e, 0x77, 0xba, 0x7d, 0xa9, 0x28, 0xff, 0x2f, 0x0f, 0xd5, 0x44, 0x5c, 0xd0, 0xa9, 0xe1,
+	0xf9, 0x7c, 0x97, 0x33, 0x87, 0xe9, 0x5f, 0x9a, 0x9e, 0x34, 0x55, 0x1b, 0x71, 0xef, 0xcc, 0x61,
+	0xde, 0x60, 0x3b, 0x1b, 0xf5, 0x3c, 0x91, 0x39, 0xe6, 0x70, 0xd9, 0x52, 0xcf, 0x39, 0xc8, 0xeb,
+	0xb0, 0x70, 0x4a, 0x3c, 0x9b, 0x98, 0x82, 0xcf, 0x3d, 0x52, 0xe5, 0x34, 0xde, 0x65, 0x0d, 0x24,
+	0xd1, 0x65, 0x0c, 0xc3, 0xdd, 0x51, 0xe3, 0xf4, 0x83, 0x08, 0x6c, 0x15, 0x8a, 0x9c, 0x3d, 0xcf,
+	0xf5, 0xb3, 0x06, 0x1a, 0x5c, 0xf6, 0x45, 0x89, 0xf9, 0xe2, 0xde, 0xf4, 0x93, 0xe1, 0x59, 0xee,
+	0x78, 0x12, 0xbb, 0x63, 0x1e, 0x0a, 0x38, 0xba, 0x98, 0x69, 0xb7, 0xda, 0xbb, 0xd4, 0x05, 0x8b,
+	0x50, 0x39, 0x68, 0x7d, 0xac, 0x1c, 0xf7, 0xd8, 0xd1, 0x1c, 0x92, 0x60, 0xe1, 0x61, 0x07, 0x77,
+	0x3b, 0xfb, 0x82, 0x52, 0x40, 0xab, 0x20, 0x09, 0xca, 0xb8, 0xdf, 0x1c, 0x45, 0xe0, 0x7f, 0x8b,
+	0xf2, 0x3f, 0xf2, 0xb0, 0xc4, 0x97, 0x92, 0xf8, 0xe0, 0xf8, 0xd9, 0x27, 0xb8, 0xc9, 0xf3, 0x94,
+	0x7c, 0xfa, 0x3c, 0x25, 0x2a, 0x5c, 0x59, 0x25, 0x50, 0x18, 0x17, 0xae, 0xec, 0x1c, 0x26, 0xb5,
+	0x4a, 0xcc, 0x4d, 0xb3, 0x4a, 0xd4, 0x61, 0xde, 0x22, 0x7e, 0xec, 0x99, 0x0a, 0x8e, 0x9a, 0xc8,
+	0x80, 0xaa, 0x6a, 0xdb, 0x4e, 0xc0, 0x4e, 0x2d, 0xa3, 0x6d, 0xdf, 0xce, 0x54, 0xe7, 0xa3, 0xf1,
+	0x17, 0x37, 0x5b, 0x63, 0x24, 0x9e, 0xcc, 0x93, 0xd8, 0x8d, 0xf7, 0x40, 0x9a, 0xec, 0x30, 0xd5,
+	0x12, 0xfa, 0x55, 0x0e, 0x16, 0x53, 0x99, 0x0a, 0xed, 0x25, 0x13, 0xf0, 0xd6, 0x94, 0xe7, 0x84,
+	0x11, 0x14, 0x4f, 0xc4, 0x87, 0x13, 0x89, 0x78, 0x66, 0xb4, 0x28, 0x1b, 0xef, 0x40, 0xde, 0x70,
+	0xea, 0x85, 0x17, 0x03, 0xcb, 0x1b, 0x8e, 0xfc, 0xfb, 0x3c, 0x48, 0x93, 0x0c, 0x5a, 0x6a, 0xfa,
+	0x8e, 0x45, 0x14, 0xf5, 0x6c, 0x78, 0x6b, 0x43, 0xe4, 0xe2, 0x0a, 0xa5, 0xb4, 0x28, 0x21, 0xc9,
+	0xbe, 0xb3, 0x51, 0xcf, 0xa7, 0xd8, 0x77, 0x36, 0x58, 0x2a, 0x17, 0xec, 0xdb, 0x1b, 0x1b, 0x51,
+	0x32, 0x16, 0xfc, 0xdb, 0x1b, 0x63, 0x79, 0x96, 0x9f, 0xc5, 0x9c, 0x67, 0xf2, 0x7d, 0x4a, 0xa0,
+	0xec, 0x93, 0xd0, 0x34, 0x85, 0xf6, 0x22, 0x87, 0xa7, 0x94, 0x58, 0x7b, 0xc4, 0xbe, 0xb3, 0x51,
+	0x2f, 0xa5, 0xd8, 0x5c, 0x7b, 0xc4, 0xa6, 0xda, 0xe7, 0xb9, 0x76, 0xc1, 0x17, 0xda, 0x59, 0x07,
+	0xae, 0xbd, 0xcc, 0xb5, 0x53, 0x0a, 0xd3, 0xfe, 0xe6, 0xad, 0x71, 0x25, 0x45, 0x68, 0x06, 0x3c,
+	0xee, 0x3e, 0xec, 0x1e, 0x3e, 0xea, 0x4a, 0xd7, 0x68, 0x03, 0x1f, 0x77, 0xbb, 0x7b, 0xdd, 0x1d,
+	0x29, 0x47, 0xcf, 0xdd, 0x3b, 0x1f, 0xef, 0xd1, 0xab, 0xfe, 0xfc, 0xe6, 0x3f, 0x17, 0xa1, 0xc4,
+	0x83, 0x15, 0x7d, 0x21, 0xaa, 0xc8, 0xe4, 0xe3, 0x14, 0xf4, 0xde, 0xd4, 0xbb, 0xb1, 0xd4, 0x83,
+	0x97, 0xc6, 0xfb, 0x33, 0xcb, 0x8b, 0x0b, 0xa0, 0x6b, 0xe8, 0xb7, 0x39, 0x58, 0x48, 0xdd, 0x78,
+	0x64, 0x3d, 0xac, 0xbf, 0xe2, 0x2d, 0x4c, 0xe3, 0x07, 0x33, 0xc9, 0xc6, 0xb6, 0xfc, 0x26, 0x07,
+	0xd5, 0xc4, 0x2b, 0x10, 0x74, 0x6f, 0x96, 0x97, 0x23, 0xdc, 0x92, 0xfb, 0xb3, 0x3f, 0x3a, 0x91,
+	0xaf, 0x6d, 0xe4, 0xd0, 0xaf, 0x73, 0x50, 0x4d, 0xbc, 0x87, 0xc8, 0x6c, 0xca, 0xe5, 0xd7, 0x1b,
+	0x8d, 0xfb, 0xb3, 0x88, 0xc6, 0x63, 0xf2, 0xcb, 0x1c, 0x54, 0xe2, 0xb7, 0x0d, 0x68, 0x6b, 0xfa,
+	0xd7, 0x10, 0xdc, 0x88, 0xbb, 0xb3, 0x3e, 0xa3, 0x90, 0xaf, 0xa1, 0x9f, 0x43, 0x39, 0x7a, 0x08,
+	0x80, 0xb2, 0xd6, 0x29, 0x13, 0xaf, 0x0c, 0x1a, 0x5b, 0x53, 0xcb, 0x25, 0xd5, 0x47, 0xb7, 0xf3,
+	0x99, 0xd5, 0x4f, 0xbc, 0x23, 0x68, 0x6c, 0x4d, 0x2d, 0x17, 0xab, 0xa7, 0x91, 0x90, 0xb8, 0xc4,
+	0xcf, 0x1c, 0x09, 0x97, 0x5f, 0x0f, 0x34, 0xee, 0xcf, 0x22, 0x9a, 0x32, 0x24, 0xf1, 0x0c, 0x20,
+	0xb3, 0x21, 0x97, 0x9f, 0x1a, 0x34, 0xee, 0xcf, 0x22, 0x1a, 0x1b, 0xf2, 0x79, 0x2e, 0xb9, 0xa7,
+	0xdc, 0x9a, 0xfa, 0xb6, 0x7b, 0xca, 0x90, 0xbc, 0x74, 0xdf, 0xce, 0x26, 0xe8, 0xe7, 0xe2, 0x04,
+	0x8c, 0x5f, 0x96, 0xa3, 0x69, 0xc0, 0x52, 0xf7, 0xeb, 0x8d, 0x3b, 0xb3, 0x15, 0x1d, 0xcc, 0x88,
+	0x5f, 0xe5, 0x00, 0xc6, 0xd7, 0xea, 0x99, 0x8d, 0xb8, 0x74, 0x9f, 0xdf, 0xb8, 0x37, 0x83, 0x64,
+	0x72, 0x82, 0x44, 0x37, 0xe9, 0x99, 0x27, 0xc8, 0xc4, 0xb5, 0x7f, 0x63, 0x6b, 0x6a, 0xb9, 0x48,
+	0xfd, 0x87, 0xf3, 0x3f, 0x2a, 0xf2, 0x2a, 0xb0, 0xc4, 0x7e, 0x6e, 0xff, 0x7f, 0x00, 0x82, 0x06,
+	0x10, 0x7f, 0xb9, 0x2a, 0x00, 0x00,
 }
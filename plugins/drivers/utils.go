@@ -64,6 +64,7 @@ func taskConfigFromProto(pb *proto.TaskConfig) *TaskConfig {
 		StdoutPath:      pb.StdoutPath,
 		StderrPath:      pb.StderrPath,
 		AllocID:         pb.AllocId,
+		SharedMemoryMB:  pb.SharedMemoryMb,
 	}
 }
 
@@ -87,6 +88,7 @@ func taskConfigToProto(cfg *TaskConfig) *proto.TaskConfig {
 		StdoutPath:          cfg.StdoutPath,
 		StderrPath:          cfg.StderrPath,
 		AllocId:             cfg.AllocID,
+		SharedMemoryMb:      cfg.SharedMemoryMB,
 	}
 	return pb
 }
@@ -132,14 +134,18 @@ func ResourcesFromProto(pb *proto.Resources) *Resources {
 
 	if pb.LinuxResources != nil {
 		r.LinuxResources = &LinuxResources{
-			CPUPeriod:        pb.LinuxResources.CpuPeriod,
-			CPUQuota:         pb.LinuxResources.CpuQuota,
-			CPUShares:        pb.LinuxResources.CpuShares,
-			MemoryLimitBytes: pb.LinuxResources.MemoryLimitBytes,
-			OOMScoreAdj:      pb.LinuxResources.OomScoreAdj,
-			CpusetCPUs:       pb.LinuxResources.CpusetCpus,
-			CpusetMems:       pb.LinuxResources.CpusetMems,
-			PercentTicks:     pb.LinuxResources.PercentTicks,
+			CPUPeriod:          pb.LinuxResources.CpuPeriod,
+			CPUQuota:           pb.LinuxResources.CpuQuota,
+			CPUShares:          pb.LinuxResources.CpuShares,
+			MemoryLimitBytes:   pb.LinuxResources.MemoryLimitBytes,
+			OOMScoreAdj:        pb.LinuxResources.OomScoreAdj,
+			CpusetCPUs:         pb.LinuxResources.CpusetCpus,
+			CpusetMems:         pb.LinuxResources.CpusetMems,
+			PercentTicks:       pb.LinuxResources.PercentTicks,
+			HugePages2MB:       pb.LinuxResources.HugePages2Mb,
+			HugePages1GB:       pb.LinuxResources.HugePages1Gb,
+			CPURealtimeRuntime: pb.LinuxResources.CpuRealtimeRuntime,
+			CPURealtimePeriod:  pb.LinuxResources.CpuRealtimePeriod,
 		}
 	}
 
@@ -188,14 +194,18 @@ func ResourcesToProto(r *Resources) *proto.Resources {
 
 	if r.LinuxResources != nil {
 		pb.LinuxResources = &proto.LinuxResources{
-			CpuPeriod:        r.LinuxResources.CPUPeriod,
-			CpuQuota:         r.LinuxResources.CPUQuota,
-			CpuShares:        r.LinuxResources.CPUShares,
-			MemoryLimitBytes: r.LinuxResources.MemoryLimitBytes,
-			OomScoreAdj:      r.LinuxResources.OOMScoreAdj,
-			CpusetCpus:       r.LinuxResources.CpusetCPUs,
-			CpusetMems:       r.LinuxResources.CpusetMems,
-			PercentTicks:     r.LinuxResources.PercentTicks,
+			CpuPeriod:          r.LinuxResources.CPUPeriod,
+			CpuQuota:           r.LinuxResources.CPUQuota,
+			CpuShares:          r.LinuxResources.CPUShares,
+			MemoryLimitBytes:   r.LinuxResources.MemoryLimitBytes,
+			OomScoreAdj:        r.LinuxResources.OOMScoreAdj,
+			CpusetCpus:         r.LinuxResources.CpusetCPUs,
+			CpusetMems:         r.LinuxResources.CpusetMems,
+			PercentTicks:       r.LinuxResources.PercentTicks,
+			HugePages2Mb:       r.LinuxResources.HugePages2MB,
+			HugePages1Gb:       r.LinuxResources.HugePages1GB,
+			CpuRealtimeRuntime: r.LinuxResources.CPURealtimeRuntime,
+			CpuRealtimePeriod:  r.LinuxResources.CPURealtimePeriod,
 		}
 	}
 
@@ -470,8 +480,9 @@ func resourceUsageToProto(ru *ResourceUsage) *proto.TaskResourceUsage {
 	}
 
 	return &proto.TaskResourceUsage{
-		Cpu:    cpu,
-		Memory: memory,
+		Cpu:      cpu,
+		Memory:   memory,
+		Pressure: pressureStatsToProto(ru.PressureStats),
 	}
 }
 
@@ -529,8 +540,67 @@ func resourceUsageFromProto(pb *proto.TaskResourceUsage) *ResourceUsage {
 	}
 
 	return &ResourceUsage{
-		CpuStats:    &cpu,
-		MemoryStats: &memory,
+		CpuStats:      &cpu,
+		MemoryStats:   &memory,
+		PressureStats: pressureStatsFromProto(pb.Pressure),
+	}
+}
+
+func pressureStatsToProto(ps *PressureStats) *proto.PressureUsage {
+	if ps == nil {
+		return nil
+	}
+
+	return &proto.PressureUsage{
+		Cpu:    pressureToProto(ps.CPU),
+		Memory: pressureToProto(ps.Memory),
+		Io:     pressureToProto(ps.IO),
+	}
+}
+
+func pressureToProto(p *Pressure) *proto.ResourcePressure {
+	if p == nil {
+		return nil
+	}
+
+	return &proto.ResourcePressure{
+		SomeAvg10:  p.SomeAvg10,
+		SomeAvg60:  p.SomeAvg60,
+		SomeAvg300: p.SomeAvg300,
+		SomeTotal:  p.SomeTotal,
+		FullAvg10:  p.FullAvg10,
+		FullAvg60:  p.FullAvg60,
+		FullAvg300: p.FullAvg300,
+		FullTotal:  p.FullTotal,
+	}
+}
+
+func pressureStatsFromProto(pb *proto.PressureUsage) *PressureStats {
+	if pb == nil {
+		return nil
+	}
+
+	return &PressureStats{
+		CPU:    pressureFromProto(pb.Cpu),
+		Memory: pressureFromProto(pb.Memory),
+		IO:     pressureFromProto(pb.Io),
+	}
+}
+
+func pressureFromProto(pb *proto.ResourcePressure) *Pressure {
+	if pb == nil {
+		return nil
+	}
+
+	return &Pressure{
+		SomeAvg10:  pb.SomeAvg10,
+		SomeAvg60:  pb.SomeAvg60,
+		SomeAvg300: pb.SomeAvg300,
+		SomeTotal:  pb.SomeTotal,
+		FullAvg10:  pb.FullAvg10,
+		FullAvg60:  pb.FullAvg60,
+		FullAvg300: pb.FullAvg300,
+		FullTotal:  pb.FullTotal,
 	}
 }
 
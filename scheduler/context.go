@@ -182,7 +182,10 @@ type EvalEligibility struct {
 	// job tracks the eligibility at the job level per computed node class.
 	job map[string]ComputedClassFeasibility
 
-	// jobEscaped marks whether constraints have escaped at the job level.
+	// jobEscaped marks whether constraints have escaped at the job level. The
+	// escaped constraints don't affect the eligibility of the computed node
+	// classes, but a blocked evaluation with escaped constraints can't be
+	// unblocked by class.
 	jobEscaped bool
 
 	// taskGroups tracks the eligibility at the task group level per computed
@@ -285,7 +288,10 @@ func (e *EvalEligibility) JobStatus(class string) ComputedClassFeasibility {
 	// COMPAT: Computed node class was introduced in 0.3. Clients running < 0.3
 	// will not have a computed class. The safest value to return is the escaped
 	// case, since it disables any optimization.
-	if e.jobEscaped || class == "" {
+	//
+	// Escaped job constraints don't disable the optimization, as the stacks
+	// check them separately on every node.
+	if class == "" {
 		return EvalComputedClassEscaped
 	}
 
@@ -314,12 +320,6 @@ func (e *EvalEligibility) TaskGroupStatus(tg, class string) ComputedClassFeasibi
 		return EvalComputedClassEscaped
 	}
 
-	if escaped, ok := e.tgEscapedConstraints[tg]; ok {
-		if escaped {
-			return EvalComputedClassEscaped
-		}
-	}
-
 	if classes, ok := e.taskGroups[tg]; ok {
 		if status, ok := classes[class]; ok {
 			return status
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// coreAllocator is used to reserve whole CPU cores for tasks. The allocator
+// tracks the cores reserved by the allocations of the node as to not reserve
+// a core twice.
+type coreAllocator struct {
+	node *structs.Node
+
+	// reserved is the set of cores already reserved on the node
+	reserved map[uint16]struct{}
+}
+
+// newCoreAllocator returns a new core allocator for the reservable cores of
+// the node.
+func newCoreAllocator(n *structs.Node) *coreAllocator {
+	return &coreAllocator{
+		node:     n,
+		reserved: make(map[uint16]struct{}),
+	}
+}
+
+// AddAllocs marks the cores reserved by the non-terminal allocations as
+// reserved.
+func (c *coreAllocator) AddAllocs(allocs []*structs.Allocation) {
+	for _, a := range allocs {
+		if a.TerminalStatus() || a.AllocatedResources == nil {
+			continue
+		}
+		for _, tr := range a.AllocatedResources.Tasks {
+			c.AddReserved(tr.Cpu.ReservedCores)
+		}
+	}
+}
+
+// AddReserved marks the given cores as reserved.
+func (c *coreAllocator) AddReserved(cores []uint16) {
+	for _, core := range cores {
+		c.reserved[core] = struct{}{}
+	}
+}
+
+// AssignCores returns the given number of unreserved cores of the node, in
+// the order of its reservable cores. If not enough cores are available, an
+// error is returned explaining why.
+func (c *coreAllocator) AssignCores(count int) ([]uint16, error) {
+	if c.node.NodeResources == nil {
+		return nil, fmt.Errorf("no reservable cores")
+	}
+
+	var cores []uint16
+	for _, core := range c.node.NodeResources.Cpu.ReservableCpuCores {
+		if len(cores) == count {
+			break
+		}
+		if _, ok := c.reserved[core]; !ok {
+			cores = append(cores, core)
+		}
+	}
+
+	if len(cores) < count {
+		return nil, fmt.Errorf("%d cores requested, %d available", count, len(cores))
+	}
+	return cores, nil
+}
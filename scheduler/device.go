@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
 
 	"math"
 
@@ -47,12 +48,7 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 	// constraints
 	for id, devInst := range d.Devices {
 		// Check if we have enough unused instances to use this
-		assignable := uint64(0)
-		for _, v := range devInst.Instances {
-			if v == 0 {
-				assignable++
-			}
-		}
+		assignable := uint64(devInst.FreeCount())
 
 		// This device doesn't have enough instances
 		if assignable < ask.Count {
@@ -110,16 +106,22 @@ func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (out *struc
 			DeviceIDs: make([]string, 0, ask.Count),
 		}
 
-		assigned := uint64(0)
-		for id, v := range devInst.Instances {
-			if v == 0 && assigned < ask.Count {
-				assigned++
-				offer.DeviceIDs = append(offer.DeviceIDs, id)
-				if assigned == ask.Count {
-					break
-				}
+		// Prefer the shared instances that are already in use so that small
+		// tasks are packed together, keeping the other instances free
+		var free []string
+		for id := range devInst.Instances {
+			if devInst.Free(id) {
+				free = append(free, id)
 			}
 		}
+		sort.Slice(free, func(i, j int) bool {
+			ui, uj := devInst.Instances[free[i]], devInst.Instances[free[j]]
+			if ui != uj {
+				return ui > uj
+			}
+			return free[i] < free[j]
+		})
+		offer.DeviceIDs = append(offer.DeviceIDs, free[:ask.Count]...)
 	}
 
 	// Failed to find a match
@@ -145,6 +145,41 @@ func TestDeviceAllocator_Allocate_NotEnoughInstances(t *testing.T) {
 	require.Contains(err.Error(), "no devices match request")
 }
 
+// Test that shared devices are packed before using another instance
+func TestDeviceAllocator_Allocate_Slices(t *testing.T) {
+	require := require.New(t)
+	_, ctx := testContext(t)
+	n := devNode()
+	for _, instance := range n.NodeResources.Devices[0].Instances {
+		instance.Slices = 2
+	}
+	d := newDeviceAllocator(ctx, n)
+	require.NotNil(d)
+
+	ask := deviceRequest("gpu", 1, nil, nil)
+
+	out, _, err := d.AssignDevice(ask)
+	require.NoError(err)
+	require.Len(out.DeviceIDs, 1)
+	require.False(d.AddReserved(out))
+
+	// The second task shares the instance of the first one
+	out2, _, err := d.AssignDevice(ask)
+	require.NoError(err)
+	require.Equal(out.DeviceIDs, out2.DeviceIDs)
+	require.False(d.AddReserved(out2))
+
+	// The third one uses the other instance
+	out3, _, err := d.AssignDevice(ask)
+	require.NoError(err)
+	require.NotEqual(out.DeviceIDs, out3.DeviceIDs)
+	require.False(d.AddReserved(out3))
+
+	// Asking for two instances fails since only one slice is left
+	_, _, err = d.AssignDevice(deviceRequest("gpu", 2, nil, nil))
+	require.Error(err)
+}
+
 // Test that asking for a device with constraints works
 func TestDeviceAllocator_Allocate_Constraints(t *testing.T) {
 	n := multipleNvidiaNode()
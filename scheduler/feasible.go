@@ -141,7 +141,12 @@ func (c *DriverChecker) hasDrivers(option *structs.Node) bool {
 				return false
 			}
 
-			return driverInfo.Detected && driverInfo.Healthy
+			// An unhealthy driver only makes the node ineligible for the
+			// task groups using it
+			if !driverInfo.Detected || !driverInfo.Healthy {
+				return false
+			}
+			continue
 		}
 
 		value, ok := option.Attributes[driverStr]
@@ -428,6 +433,9 @@ func resolveTarget(target string, node *structs.Node) (interface{}, bool) {
 	case "${node.class}" == target:
 		return node.NodeClass, true
 
+	case "${node.pool}" == target:
+		return node.NodePool, true
+
 	case strings.HasPrefix(target, "${attr."):
 		attr := strings.TrimSuffix(strings.TrimPrefix(target, "${attr."), "}")
 		val, ok := node.Attributes[attr]
@@ -699,23 +707,30 @@ func checkSetContainsAny(lVal, rVal interface{}) bool {
 // FeasibilityWrapper is a FeasibleIterator which wraps both job and task group
 // FeasibilityCheckers in which feasibility checking can be skipped if the
 // computed node class has previously been marked as eligible or ineligible.
+// Checks that escape the computed node class are run separately on every node
+// that passes the class checks, so that they don't disable the caching of the
+// class checks.
 type FeasibilityWrapper struct {
-	ctx         Context
-	source      FeasibleIterator
-	jobCheckers []FeasibilityChecker
-	tgCheckers  []FeasibilityChecker
-	tg          string
+	ctx          Context
+	source       FeasibleIterator
+	jobCheckers  []FeasibilityChecker
+	tgCheckers   []FeasibilityChecker
+	nodeCheckers []FeasibilityChecker
+	tg           string
 }
 
 // NewFeasibilityWrapper returns a FeasibleIterator based on the passed source
-// and FeasibilityCheckers.
+// and FeasibilityCheckers. The job and task group checkers must only examine
+// the computed node class of the node, while the node checkers are run on
+// every node.
 func NewFeasibilityWrapper(ctx Context, source FeasibleIterator,
-	jobCheckers, tgCheckers []FeasibilityChecker) *FeasibilityWrapper {
+	jobCheckers, tgCheckers, nodeCheckers []FeasibilityChecker) *FeasibilityWrapper {
 	return &FeasibilityWrapper{
-		ctx:         ctx,
-		source:      source,
-		jobCheckers: jobCheckers,
-		tgCheckers:  tgCheckers,
+		ctx:          ctx,
+		source:       source,
+		jobCheckers:  jobCheckers,
+		tgCheckers:   tgCheckers,
+		nodeCheckers: nodeCheckers,
 	}
 }
 
@@ -746,6 +761,7 @@ OUTER:
 		switch evalElig.JobStatus(option.ComputedClass) {
 		case EvalComputedClassIneligible:
 			// Fast path the ineligible case
+			metrics.FeasibilityCacheHit()
 			metrics.FilterNode(option, "computed class ineligible")
 			continue
 		case EvalComputedClassEscaped:
@@ -754,23 +770,29 @@ OUTER:
 			jobUnknown = true
 		}
 
-		// Run the job feasibility checks.
-		for _, check := range w.jobCheckers {
-			feasible := check.Feasible(option)
-			if !feasible {
-				// If the job hasn't escaped, set it to be ineligible since it
-				// failed a job check.
-				if !jobEscaped {
-					evalElig.SetJobEligibility(false, option.ComputedClass)
+		// Run the job feasibility checks unless the job has been marked as
+		// eligible for the class.
+		jobCached := !jobEscaped && !jobUnknown
+		if !jobCached {
+			metrics.FeasibilityCacheMiss()
+
+			for _, check := range w.jobCheckers {
+				feasible := check.Feasible(option)
+				if !feasible {
+					// If the job hasn't escaped, set it to be ineligible
+					// since it failed a job check.
+					if !jobEscaped {
+						evalElig.SetJobEligibility(false, option.ComputedClass)
+					}
+					continue OUTER
 				}
-				continue OUTER
 			}
-		}
 
-		// Set the job eligibility if the constraints weren't escaped and it
-		// hasn't been set before.
-		if !jobEscaped && jobUnknown {
-			evalElig.SetJobEligibility(true, option.ComputedClass)
+			// Set the job eligibility if the constraints weren't escaped and
+			// it hasn't been set before.
+			if !jobEscaped && jobUnknown {
+				evalElig.SetJobEligibility(true, option.ComputedClass)
+			}
 		}
 
 		// Check if the task group has been marked as eligible or ineligible.
@@ -778,16 +800,28 @@ OUTER:
 		switch evalElig.TaskGroupStatus(w.tg, option.ComputedClass) {
 		case EvalComputedClassIneligible:
 			// Fast path the ineligible case
+			if jobCached {
+				metrics.FeasibilityCacheHit()
+			}
 			metrics.FilterNode(option, "computed class ineligible")
 			continue
 		case EvalComputedClassEligible:
 			// Fast path the eligible case
+			if jobCached {
+				metrics.FeasibilityCacheHit()
+			}
+			if !w.nodeFeasible(option) {
+				continue
+			}
 			return option
 		case EvalComputedClassEscaped:
 			tgEscaped = true
 		case EvalComputedClassUnknown:
 			tgUnknown = true
 		}
+		if jobCached {
+			metrics.FeasibilityCacheMiss()
+		}
 
 		// Run the task group feasibility checks.
 		for _, check := range w.tgCheckers {
@@ -808,10 +842,24 @@ OUTER:
 			evalElig.SetTaskGroupEligibility(true, w.tg, option.ComputedClass)
 		}
 
+		if !w.nodeFeasible(option) {
+			continue
+		}
 		return option
 	}
 }
 
+// nodeFeasible runs the checks that escape the computed node class against the
+// node. Their results are never cached.
+func (w *FeasibilityWrapper) nodeFeasible(option *structs.Node) bool {
+	for _, check := range w.nodeCheckers {
+		if !check.Feasible(option) {
+			return false
+		}
+	}
+	return true
+}
+
 // DeviceChecker is a FeasibilityChecker which returns whether a node has the
 // devices necessary to scheduler a task group.
 type DeviceChecker struct {
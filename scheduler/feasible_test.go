@@ -189,6 +189,17 @@ func Test_HealthChecks(t *testing.T) {
 		act := checker.Feasible(c.Node)
 		require.Equal(act, c.Result)
 	}
+
+	// A node with a healthy and an unhealthy driver is only feasible for the
+	// healthy one
+	node := mock.Node()
+	node.Drivers = map[string]*structs.DriverInfo{
+		"foo": {Detected: true, Healthy: true},
+		"bar": {Detected: true, Healthy: false},
+	}
+	require.True(NewDriverChecker(ctx, map[string]struct{}{"foo": {}}).Feasible(node))
+	require.False(NewDriverChecker(ctx, map[string]struct{}{"bar": {}}).Feasible(node))
+	require.False(NewDriverChecker(ctx, map[string]struct{}{"foo": {}, "bar": {}}).Feasible(node))
 }
 
 func TestConstraintChecker(t *testing.T) {
@@ -284,6 +295,12 @@ func TestResolveConstraintTarget(t *testing.T) {
 			val:    node.NodeClass,
 			result: true,
 		},
+		{
+			target: "${node.pool}",
+			node:   node,
+			val:    node.NodePool,
+			result: true,
+		},
 		{
 			target: "${node.foo}",
 			node:   node,
@@ -1575,7 +1592,7 @@ func TestFeasibilityWrapper_JobIneligible(t *testing.T) {
 	nodes := []*structs.Node{mock.Node()}
 	static := NewStaticIterator(ctx, nodes)
 	mocked := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil)
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil, nil)
 
 	// Set the job to ineligible
 	ctx.Eligibility().SetJobEligibility(false, nodes[0].ComputedClass)
@@ -1593,7 +1610,7 @@ func TestFeasibilityWrapper_JobEscapes(t *testing.T) {
 	nodes := []*structs.Node{mock.Node()}
 	static := NewStaticIterator(ctx, nodes)
 	mocked := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil)
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{mocked}, nil, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1619,7 +1636,7 @@ func TestFeasibilityWrapper_JobAndTg_Eligible(t *testing.T) {
 	static := NewStaticIterator(ctx, nodes)
 	jobMock := newMockFeasibilityChecker(true)
 	tgMock := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1641,7 +1658,7 @@ func TestFeasibilityWrapper_JobEligible_TgIneligible(t *testing.T) {
 	static := NewStaticIterator(ctx, nodes)
 	jobMock := newMockFeasibilityChecker(true)
 	tgMock := newMockFeasibilityChecker(false)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1663,7 +1680,7 @@ func TestFeasibilityWrapper_JobEligible_TgEscaped(t *testing.T) {
 	static := NewStaticIterator(ctx, nodes)
 	jobMock := newMockFeasibilityChecker(true)
 	tgMock := newMockFeasibilityChecker(true)
-	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock})
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock}, []FeasibilityChecker{tgMock}, nil)
 
 	// Set the job to escaped
 	cc := nodes[0].ComputedClass
@@ -1684,6 +1701,37 @@ func TestFeasibilityWrapper_JobEligible_TgEscaped(t *testing.T) {
 	}
 }
 
+func TestFeasibilityWrapper_NodeCheckers(t *testing.T) {
+	require := require.New(t)
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	static := NewStaticIterator(ctx, nodes)
+	jobMock := newMockFeasibilityChecker(true, true)
+	tgMock := newMockFeasibilityChecker(true, true)
+	nodeMock := newMockFeasibilityChecker(false, true)
+	wrapper := NewFeasibilityWrapper(ctx, static, []FeasibilityChecker{jobMock},
+		[]FeasibilityChecker{tgMock}, []FeasibilityChecker{nodeMock})
+	wrapper.SetTaskGroup("foo")
+
+	// The first node fails the node checks, which doesn't mark its class as
+	// ineligible
+	out := collectFeasible(wrapper)
+	require.Equal(nodes[1:], out)
+	require.Equal(2, nodeMock.calls())
+
+	// The second node was decided by the cached class, without running the
+	// job and task group checks again
+	require.Equal(1, jobMock.calls())
+	require.Equal(1, tgMock.calls())
+	cc := nodes[0].ComputedClass
+	require.Equal(EvalComputedClassEligible, ctx.Eligibility().JobStatus(cc))
+	require.Equal(EvalComputedClassEligible, ctx.Eligibility().TaskGroupStatus("foo", cc))
+
+	met := ctx.Metrics()
+	require.Equal(1, met.FeasibilityCacheMisses)
+	require.Equal(1, met.FeasibilityCacheHits)
+}
+
 func TestSetContainsAny(t *testing.T) {
 	require.True(t, checkSetContainsAny("a", "a"))
 	require.True(t, checkSetContainsAny("a,b", "a"))
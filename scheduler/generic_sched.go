@@ -32,6 +32,19 @@ const (
 	// allocLost is the status used when an allocation is lost
 	allocLost = "alloc is lost since its node is down"
 
+	// allocUnknown is the status used when the node of an allocation is down
+	// but the allocation is kept until its group's max_client_disconnect
+	// elapses
+	allocUnknown = "alloc is unknown since its node is disconnected"
+
+	// allocReplacedOnReconnect is the status used when an allocation is
+	// stopped after its node reconnects because it was replaced
+	allocReplacedOnReconnect = "alloc not needed as it was replaced while its node was disconnected"
+
+	// allocOriginalReconnected is the status used when the replacement of an
+	// allocation is stopped after the node of the allocation reconnects
+	allocOriginalReconnected = "alloc not needed as the alloc it replaced has reconnected"
+
 	// allocInPlace is the status used when speculating on an in-place update
 	allocInPlace = "alloc updating in-place"
 
@@ -51,6 +64,11 @@ const (
 	// up evals for delayed rescheduling
 	reschedulingFollowupEvalDesc = "created for delayed rescheduling"
 
+	// disconnectTimeoutFollowupEvalDesc is the description used when creating
+	// follow up evals to replace allocations once their node has been
+	// disconnected for longer than max_client_disconnect
+	disconnectTimeoutFollowupEvalDesc = "created for max_client_disconnect timeout"
+
 	// maxPastRescheduleEvents is the maximum number of past reschedule event
 	// that we track when unlimited rescheduling is enabled
 	maxPastRescheduleEvents = 5
@@ -130,7 +148,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 		structs.EvalTriggerRollingUpdate, structs.EvalTriggerQueuedAllocs,
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
-		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption:
+		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
+		structs.EvalTriggerMaxDisconnect:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -413,14 +432,50 @@ func (s *GenericScheduler) computeJobAllocs() error {
 	for _, p := range results.destructiveUpdate {
 		destructive = append(destructive, p)
 	}
+
+	// Queue the placements while another job of the concurrency group is
+	// running allocations
+	holder, err := ConcurrencyGroupHolder(s.state, s.job)
+	if err != nil {
+		return err
+	}
+	if holder != "" {
+		s.logger.Debug("concurrency group held by another job, queueing placements",
+			"concurrency_group", s.job.ConcurrencyGroup, "holder", holder)
+		s.queuePlacements(holder, destructive, place)
+		return nil
+	}
+
 	return s.computePlacements(destructive, place)
 }
 
+// queuePlacements marks the placements as failed because the concurrency group
+// of the job is held by another job, so a blocked eval retries them once the
+// other job's allocations are terminal.
+func (s *GenericScheduler) queuePlacements(holder string, destructive, place []placementResult) {
+	for _, results := range [][]placementResult{destructive, place} {
+		for _, missing := range results {
+			tg := missing.TaskGroup()
+			if metric, ok := s.failedTGAllocs[tg.Name]; ok {
+				metric.CoalescedFailures += 1
+				continue
+			}
+
+			if s.failedTGAllocs == nil {
+				s.failedTGAllocs = make(map[string]*structs.AllocMetric)
+			}
+			s.failedTGAllocs[tg.Name] = &structs.AllocMetric{
+				ConcurrencyGroupHeldBy: holder,
+			}
+		}
+	}
+}
+
 // computePlacements computes placements for allocations. It is given the set of
 // destructive updates to place and the set of new placements to place.
 func (s *GenericScheduler) computePlacements(destructive, place []placementResult) error {
 	// Get the base nodes
-	nodes, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters)
+	nodes, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 	if err != nil {
 		return err
 	}
@@ -3458,6 +3458,73 @@ func TestBatchSched_Run_CompleteAlloc(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestBatchSched_ConcurrencyGroup(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create a node
+	node := mock.Node()
+	require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+
+	// Create a job of the group with a running alloc
+	running := mock.Job()
+	running.Type = structs.JobTypeBatch
+	running.ConcurrencyGroup = "migrations"
+	running.TaskGroups[0].Count = 1
+	require.NoError(h.State.UpsertJob(h.NextIndex(), running))
+
+	alloc := mock.Alloc()
+	alloc.Job = running
+	alloc.JobID = running.ID
+	alloc.NodeID = node.ID
+	alloc.Name = "my-job.web[0]"
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+
+	// Register another job of the group
+	job := mock.Job()
+	job.Type = structs.JobTypeBatch
+	job.ConcurrencyGroup = "migrations"
+	job.TaskGroups[0].Count = 2
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+	require.NoError(h.Process(NewBatchScheduler, eval))
+
+	// The placements are queued behind the running job
+	require.Empty(h.Plans)
+	require.Len(h.CreateEvals, 1)
+	require.Equal(structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+	require.Len(h.Evals, 1)
+	metrics := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.NotNil(metrics)
+	require.Equal(running.ID, metrics.ConcurrencyGroupHeldBy)
+	require.Equal(1, metrics.CoalescedFailures)
+	require.Equal(2, h.Evals[0].QueuedAllocations["web"])
+
+	// The placements are made once the running job completes
+	alloc = alloc.Copy()
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(h.State.UpdateAllocsFromClient(h.NextIndex(), []*structs.Allocation{alloc}))
+
+	h = NewHarnessWithState(t, h.State)
+	require.NoError(h.Process(NewBatchScheduler, eval))
+	require.Len(h.Plans, 1)
+	placed := 0
+	for _, allocs := range h.Plans[0].NodeAllocation {
+		placed += len(allocs)
+	}
+	require.Equal(2, placed)
+}
+
 func TestBatchSched_Run_FailedAlloc(t *testing.T) {
 	h := NewHarness(t)
 
@@ -166,6 +166,10 @@ func (iter *BinPackIterator) SetPriority(p int) {
 	iter.priority = p
 }
 
+func (iter *BinPackIterator) SetEvict(evict bool) {
+	iter.evict = evict
+}
+
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
 	iter.taskGroup = taskGroup
 }
@@ -195,6 +199,10 @@ OUTER:
 		devAllocator := newDeviceAllocator(iter.ctx, option.Node)
 		devAllocator.AddAllocs(proposed)
 
+		// Create a core allocator
+		coreAllocator := newCoreAllocator(option.Node)
+		coreAllocator.AddAllocs(proposed)
+
 		// Track the affinities of the devices
 		totalDeviceAffinityWeight := 0.0
 		sumMatchingAffinities := 0.0
@@ -227,12 +235,29 @@ OUTER:
 			taskResources := &structs.AllocatedTaskResources{
 				Cpu: structs.AllocatedCpuResources{
 					CpuShares: int64(task.Resources.CPU),
+					Realtime:  task.Resources.Realtime,
 				},
 				Memory: structs.AllocatedMemoryResources{
-					MemoryMB: int64(task.Resources.MemoryMB),
+					MemoryMB:     int64(task.Resources.MemoryMB),
+					HugePages2MB: int64(task.Resources.HugePages2MB),
+					HugePages1GB: int64(task.Resources.HugePages1GB),
 				},
 			}
 
+			// Reserve whole cores, accounted as the CPU shares of the cores.
+			// Preemption isn't attempted for cores.
+			if task.Resources.Cores > 0 {
+				cores, err := coreAllocator.AssignCores(task.Resources.Cores)
+				if err != nil {
+					iter.ctx.Metrics().ExhaustedNode(option.Node, fmt.Sprintf("cores: %s", err))
+					netIdx.Release()
+					continue OUTER
+				}
+				coreAllocator.AddReserved(cores)
+				taskResources.Cpu.ReservedCores = cores
+				taskResources.Cpu.CpuShares = int64(len(cores)) * option.Node.NodeResources.Cpu.SharesPerCore()
+			}
+
 			// Check if we need a network resource
 			if len(task.Resources.Networks) > 0 {
 				ask := task.Resources.Networks[0].Copy()
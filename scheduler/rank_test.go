@@ -467,6 +467,88 @@ func TestBinPackIterator_ExistingAlloc_PlannedEvict(t *testing.T) {
 	}
 }
 
+func TestBinPackIterator_Cores(t *testing.T) {
+	require := require.New(t)
+	state, ctx := testContext(t)
+
+	node := mock.Node()
+	node.NodeResources.Cpu.TotalCpuCores = 4
+	node.NodeResources.Cpu.ReservableCpuCores = []uint16{0, 1, 2, 3}
+	node.ReservedResources.Cpu.CpuShares = 0
+	nodes := []*RankedNode{{Node: node}}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	// Add an existing allocation reserving the first core
+	j1 := mock.Job()
+	alloc1 := &structs.Allocation{
+		Namespace: structs.DefaultNamespace,
+		ID:        uuid.Generate(),
+		EvalID:    uuid.Generate(),
+		NodeID:    node.ID,
+		JobID:     j1.ID,
+		Job:       j1,
+		AllocatedResources: &structs.AllocatedResources{
+			Tasks: map[string]*structs.AllocatedTaskResources{
+				"web": {
+					Cpu: structs.AllocatedCpuResources{
+						CpuShares:     1000,
+						ReservedCores: []uint16{0},
+					},
+					Memory: structs.AllocatedMemoryResources{
+						MemoryMB: 256,
+					},
+				},
+			},
+		},
+		DesiredStatus: structs.AllocDesiredStatusRun,
+		ClientStatus:  structs.AllocClientStatusPending,
+		TaskGroup:     "web",
+	}
+	require.NoError(state.UpsertJobSummary(998, mock.JobSummary(alloc1.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc1}))
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					Cores:    2,
+					MemoryMB: 256,
+				},
+			},
+			{
+				Name: "sidecar",
+				Resources: &structs.Resources{
+					Cores:    1,
+					MemoryMB: 256,
+				},
+			},
+		},
+	}
+
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+
+	out := collectRanked(binp)
+	require.Len(out, 1)
+
+	web := out[0].TaskResources["web"]
+	require.Equal([]uint16{1, 2}, web.Cpu.ReservedCores)
+	require.Equal(int64(2000), web.Cpu.CpuShares)
+	sidecar := out[0].TaskResources["sidecar"]
+	require.Equal([]uint16{3}, sidecar.Cpu.ReservedCores)
+	require.Equal(int64(1000), sidecar.Cpu.CpuShares)
+
+	// Asking for more cores than are available exhausts the node
+	taskGroup.Tasks[1].Resources.Cores = 2
+	static = NewStaticRankIterator(ctx, nodes)
+	binp = NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+	require.Empty(collectRanked(binp))
+	require.Equal(1, ctx.Metrics().DimensionExhausted["cores: 2 cores requested, 1 available"])
+}
+
 // This is a fairly high level test that asserts the bin packer uses the device
 // allocator properly. It is not intended to handle every possible device
 // request versus availability scenario. That should be covered in device
@@ -340,9 +340,28 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// allocs including the canaries
 	canaries, all := a.handleGroupCanaries(all, desiredChanges)
 
+	// Stop either the allocations of reconnected nodes that were replaced
+	// while their node was disconnected or their replacements, depending on
+	// the reconnect strategy of the group
+	keep, stopReconnected := a.handleReconnected(all.filterByReconnected(a.taintedNodes), all, tg)
+	desiredChanges.Stop += uint64(len(stopReconnected))
+	all = all.difference(stopReconnected)
+
 	// Determine what set of allocations are on tainted nodes
 	untainted, migrate, lost := all.filterByTainted(a.taintedNodes)
 
+	// Determine what set of lost allocations are kept because their node went
+	// down within the group's max_client_disconnect
+	lost, disconnected, expired := lost.filterByDisconnected(a.taintedNodes, tg.MaxClientDisconnect, a.now)
+
+	// Allocations whose window has elapsed are replaced once and are then
+	// left as is until their node reconnects
+	for id, alloc := range expired {
+		if alloc.NextAllocation != "" {
+			delete(expired, id)
+		}
+	}
+
 	// Determine what set of terminal allocations need to be rescheduled
 	untainted, rescheduleNow, rescheduleLater := untainted.filterByRescheduleable(a.batch, a.now, a.evalID, a.deployment)
 
@@ -350,9 +369,14 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// reschedulable later and mark the allocations for in place updating
 	a.handleDelayedReschedules(rescheduleLater, all, tg.Name)
 
+	// Disconnected allocations count as untainted until their window has
+	// elapsed so that they aren't replaced, as do the reconnected allocations
+	// kept in place of their replacement
+	untainted = untainted.union(disconnected, keep)
+
 	// Create a structure for choosing names. Seed with the taken names which is
 	// the union of untainted and migrating nodes (includes canaries)
-	nameIndex := newAllocNameIndex(a.jobID, group, tg.Count, untainted.union(migrate, rescheduleNow))
+	nameIndex := newAllocNameIndex(a.jobID, group, tg.Count, untainted.union(migrate, rescheduleNow, expired))
 
 	// Stop any unneeded allocations and update the untainted set to not
 	// included stopped allocations.
@@ -361,9 +385,16 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	desiredChanges.Stop += uint64(len(stop))
 	untainted = untainted.difference(stop)
 
+	// Mark the remaining disconnected allocations as unknown and create
+	// follow up evaluations to replace them once their window has elapsed
+	disconnected = disconnected.difference(stop)
+	a.handleDisconnected(disconnected, expired, tg)
+
 	// Do inplace upgrades where possible and capture the set of upgrades that
-	// need to be done destructively.
-	ignore, inplace, destructive := a.computeUpdates(tg, untainted)
+	// need to be done destructively. Disconnected allocations can't be
+	// updated until their node reconnects.
+	ignore, inplace, destructive := a.computeUpdates(tg, untainted.difference(disconnected))
+	ignore = ignore.union(disconnected)
 	desiredChanges.Ignore += uint64(len(ignore))
 	desiredChanges.InPlaceUpdate += uint64(len(inplace))
 	if !existingDeployment {
@@ -398,6 +429,11 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		}
 	}
 
+	// Groups updated in manual steps start with the canaries and a batch
+	if !existingDeployment && strategy != nil && strategy.ManualStep {
+		dstate.StepAllocs = dstate.DesiredCanaries + strategy.MaxParallel
+	}
+
 	// Determine how many we can place
 	canaryState = dstate != nil && dstate.DesiredCanaries != 0 && !dstate.Promoted
 	limit := a.computeLimit(tg, untainted, destructive, migrate, canaryState)
@@ -406,7 +442,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 	// * The deployment is not paused or failed
 	// * Not placing any canaries
 	// * If there are any canaries that they have been promoted
-	place := a.computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow)
+	place := a.computePlacements(tg, nameIndex, untainted, migrate, rescheduleNow, expired)
 	if !existingDeployment {
 		dstate.DesiredTotal += len(place)
 	}
@@ -427,8 +463,8 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		// We do not want to place additional allocations but in the case we
 		// have lost allocations or allocations that require rescheduling now,
 		// we do so regardless to avoid odd user experiences.
-		if len(lost) != 0 {
-			allowed := helper.IntMin(len(lost), len(place))
+		if n := len(lost) + len(expired); n != 0 {
+			allowed := helper.IntMin(n, len(place))
 			desiredChanges.Place += uint64(allowed)
 			for _, p := range place[:allowed] {
 				a.result.place = append(a.result.place, p)
@@ -631,6 +667,12 @@ func (a *allocReconciler) computeLimit(group *structs.TaskGroup, untainted, dest
 				limit--
 			}
 		}
+
+		// Groups updated in manual steps don't place beyond the current step
+		// until the deployment is resumed
+		if dstate, ok := a.deployment.TaskGroups[group.Name]; ok && group.Update.ManualStep && dstate.StepAllocs != 0 {
+			limit = helper.IntMin(limit, dstate.StepAllocs-dstate.PlacedAllocs)
+		}
 	}
 
 	// The limit can be less than zero in the case that the job was changed such
@@ -645,7 +687,7 @@ func (a *allocReconciler) computeLimit(group *structs.TaskGroup, untainted, dest
 // computePlacement returns the set of allocations to place given the group
 // definition, the set of untainted, migrating and reschedule allocations for the group.
 func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
-	nameIndex *allocNameIndex, untainted, migrate allocSet, reschedule allocSet) []allocPlaceResult {
+	nameIndex *allocNameIndex, untainted, migrate allocSet, reschedule, expired allocSet) []allocPlaceResult {
 
 	// Add rescheduled placement results
 	var place []allocPlaceResult
@@ -659,8 +701,19 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 		})
 	}
 
+	// Replace the allocations of disconnected nodes whose window has elapsed.
+	// They are chained to their replacement so that the two can be reconciled
+	// if the node reconnects.
+	for _, alloc := range expired {
+		place = append(place, allocPlaceResult{
+			name:          alloc.Name,
+			taskGroup:     group,
+			previousAlloc: alloc,
+		})
+	}
+
 	// Hot path the nothing to do case
-	existing := len(untainted) + len(migrate) + len(reschedule)
+	existing := len(untainted) + len(migrate) + len(reschedule) + len(expired)
 	if existing >= group.Count {
 		return place
 	}
@@ -883,3 +936,107 @@ func (a *allocReconciler) handleDelayedReschedules(rescheduleLater []*delayedRes
 		a.result.attributeUpdates[updatedAlloc.ID] = updatedAlloc
 	}
 }
+
+// handleDisconnected marks the allocations of down nodes that are within their
+// group's max_client_disconnect as unknown, and creates followup evaluations
+// to replace them once the window has elapsed. Allocations that are already
+// unknown have had their evaluation created when they were marked. Expired
+// allocations that weren't marked yet are marked as well.
+func (a *allocReconciler) handleDisconnected(disconnected, expired allocSet, tg *structs.TaskGroup) {
+	for _, alloc := range expired {
+		if alloc.ClientStatus != structs.AllocClientStatusUnknown {
+			a.markUnknown(alloc)
+		}
+	}
+
+	var evals []*structs.Evaluation
+	evalsByExpiry := make(map[int64]*structs.Evaluation)
+	for _, alloc := range disconnected.nameOrder() {
+		if alloc.ClientStatus == structs.AllocClientStatusUnknown {
+			continue
+		}
+
+		// Batch the allocations of nodes that expire at the same time
+		expiry := disconnectExpiry(a.taintedNodes[alloc.NodeID], *tg.MaxClientDisconnect)
+		if _, ok := evalsByExpiry[expiry.Unix()]; !ok {
+			eval := &structs.Evaluation{
+				ID:                uuid.Generate(),
+				Namespace:         a.job.Namespace,
+				Priority:          a.job.Priority,
+				Type:              a.job.Type,
+				TriggeredBy:       structs.EvalTriggerMaxDisconnect,
+				JobID:             a.job.ID,
+				JobModifyIndex:    a.job.ModifyIndex,
+				Status:            structs.EvalStatusPending,
+				StatusDescription: disconnectTimeoutFollowupEvalDesc,
+				WaitUntil:         expiry,
+			}
+			evalsByExpiry[expiry.Unix()] = eval
+			evals = append(evals, eval)
+		}
+
+		a.markUnknown(alloc)
+	}
+
+	if len(evals) != 0 {
+		a.result.desiredFollowupEvals[tg.Name] = append(a.result.desiredFollowupEvals[tg.Name], evals...)
+	}
+}
+
+// markUnknown marks the allocation of a disconnected node as unknown.
+func (a *allocReconciler) markUnknown(alloc *structs.Allocation) {
+	if a.result.attributeUpdates == nil {
+		a.result.attributeUpdates = make(map[string]*structs.Allocation)
+	}
+	updatedAlloc := alloc.Copy()
+	updatedAlloc.ClientStatus = structs.AllocClientStatusUnknown
+	updatedAlloc.ClientDescription = allocUnknown
+	a.result.attributeUpdates[updatedAlloc.ID] = updatedAlloc
+}
+
+// handleReconnected reconciles the allocations of reconnected nodes that were
+// replaced while their node was disconnected with their latest replacement.
+// The replacement is stopped if the group keeps the original allocations and
+// the replacement is still running, otherwise the original allocation is
+// stopped. It returns the allocations kept in place of their replacement and
+// the set of stopped allocations.
+func (a *allocReconciler) handleReconnected(reconnected, all allocSet, tg *structs.TaskGroup) (keep, stop allocSet) {
+	keep = make(map[string]*structs.Allocation)
+	stop = make(map[string]*structs.Allocation)
+	for _, alloc := range reconnected.nameOrder() {
+		// Follow the lineage of the allocation to its latest replacement
+		replacement := all[alloc.NextAllocation]
+		for replacement != nil && replacement.NextAllocation != "" {
+			replacement = all[replacement.NextAllocation]
+		}
+
+		if tg.ReconnectStrategy != structs.ReconnectStrategyKeepOriginal ||
+			replacement == nil || replacement.TerminalStatus() {
+			stop[alloc.ID] = alloc
+			a.result.stop = append(a.result.stop, allocStopResult{
+				alloc:             alloc,
+				statusDescription: allocReplacedOnReconnect,
+			})
+			continue
+		}
+
+		// Unchain the stopped replacement so that the kept allocation is no
+		// longer considered replaced
+		stopped := replacement.Copy()
+		stopped.PreviousAllocation = ""
+		stop[replacement.ID] = replacement
+		a.result.stop = append(a.result.stop, allocStopResult{
+			alloc:             stopped,
+			statusDescription: allocOriginalReconnected,
+		})
+
+		if a.result.attributeUpdates == nil {
+			a.result.attributeUpdates = make(map[string]*structs.Allocation)
+		}
+		updatedAlloc := alloc.Copy()
+		updatedAlloc.NextAllocation = ""
+		a.result.attributeUpdates[updatedAlloc.ID] = updatedAlloc
+		keep[updatedAlloc.ID] = updatedAlloc
+	}
+	return
+}
@@ -736,6 +736,223 @@ func TestReconciler_LostNode(t *testing.T) {
 	assertNamesHaveIndexes(t, intRange(0, 1), placeResultsToNames(r.place))
 }
 
+// Tests the reconciler keeps the allocations of down nodes as unknown within
+// the group's max_client_disconnect
+func TestReconciler_Disconnected(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+
+	// Create 10 existing allocations
+	var allocs []*structs.Allocation
+	for i := 0; i < 10; i++ {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = uuid.Generate()
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+
+	// Build a map of tainted nodes that went down a minute ago
+	now := time.Now()
+	tainted := make(map[string]*structs.Node, 2)
+	for i := 0; i < 2; i++ {
+		n := mock.Node()
+		n.ID = allocs[i].NodeID
+		n.Status = structs.NodeStatusDown
+		n.StatusUpdatedAt = now.Add(-1 * time.Minute).Unix()
+		tainted[n.ID] = n
+	}
+
+	reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	reconciler.now = now
+	r := reconciler.Compute()
+
+	// Assert the allocations are marked unknown and not replaced
+	assertResults(t, r, &resultExpectation{
+		createDeployment:  nil,
+		deploymentUpdates: nil,
+		place:             0,
+		inplace:           0,
+		attributeUpdates:  2,
+		stop:              0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+	assertNamesHaveIndexes(t, intRange(0, 1), attributeUpdatesToNames(r.attributeUpdates))
+	for _, alloc := range r.attributeUpdates {
+		require.Equal(t, structs.AllocClientStatusUnknown, alloc.ClientStatus)
+	}
+
+	// Both nodes expire together so a single follow up eval is created
+	evals := r.desiredFollowupEvals[job.TaskGroups[0].Name]
+	require.Len(t, evals, 1)
+	require.Equal(t, structs.EvalTriggerMaxDisconnect, evals[0].TriggeredBy)
+	require.Equal(t, time.Unix(tainted[allocs[0].NodeID].StatusUpdatedAt, 0).Add(5*time.Minute), evals[0].WaitUntil)
+
+	// Allocations already marked unknown are left as is
+	allocs[0].ClientStatus = structs.AllocClientStatusUnknown
+	allocs[1].ClientStatus = structs.AllocClientStatusUnknown
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	reconciler.now = now
+	r = reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+	require.Empty(t, r.desiredFollowupEvals[job.TaskGroups[0].Name])
+
+	// Once the window has elapsed the allocations are replaced but not stopped
+	// so that they can be reconciled if their node reconnects
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	reconciler.now = now.Add(5 * time.Minute)
+	r = reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		place: 2,
+		stop:  0,
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Place:  2,
+				Ignore: 8,
+			},
+		},
+	})
+	assertNamesHaveIndexes(t, intRange(0, 1), placeResultsToNames(r.place))
+	for _, place := range r.place {
+		require.NotNil(t, place.PreviousAllocation())
+		require.False(t, place.IsRescheduling())
+	}
+
+	// Replaced allocations are left as is while their node is down
+	for _, place := range r.place {
+		replacement := mock.Alloc()
+		replacement.Job = job
+		replacement.JobID = job.ID
+		replacement.Name = place.Name()
+		replacement.PreviousAllocation = place.PreviousAllocation().ID
+		place.PreviousAllocation().NextAllocation = replacement.ID
+		allocs = append(allocs, replacement)
+	}
+	reconciler = NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, tainted, "")
+	reconciler.now = now.Add(10 * time.Minute)
+	r = reconciler.Compute()
+
+	assertResults(t, r, &resultExpectation{
+		desiredTGUpdates: map[string]*structs.DesiredUpdates{
+			job.TaskGroups[0].Name: {
+				Ignore: 10,
+			},
+		},
+	})
+}
+
+// Tests the reconciler stops one of an allocation replaced while its node was
+// disconnected and its replacement once the node reconnects
+func TestReconciler_Reconnected(t *testing.T) {
+	cases := []struct {
+		name              string
+		strategy          string
+		replacementStatus string
+		keepOriginal      bool
+		place             int
+	}{
+		{
+			name:              "default keeps the replacement",
+			replacementStatus: structs.AllocClientStatusRunning,
+		},
+		{
+			name:              "keep replacement",
+			strategy:          structs.ReconnectStrategyKeepReplacement,
+			replacementStatus: structs.AllocClientStatusRunning,
+		},
+		{
+			name:              "keep original",
+			strategy:          structs.ReconnectStrategyKeepOriginal,
+			replacementStatus: structs.AllocClientStatusRunning,
+			keepOriginal:      true,
+		},
+		{
+			name:              "keep original with completed replacement",
+			strategy:          structs.ReconnectStrategyKeepOriginal,
+			replacementStatus: structs.AllocClientStatusComplete,
+			place:             1,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			job := mock.Job()
+			job.TaskGroups[0].Count = 2
+			job.TaskGroups[0].MaxClientDisconnect = helper.TimeToPtr(5 * time.Minute)
+			job.TaskGroups[0].ReconnectStrategy = c.strategy
+
+			// Create 2 existing allocations, the first of which was replaced
+			// while its node was disconnected
+			var allocs []*structs.Allocation
+			for i := 0; i < 2; i++ {
+				alloc := mock.Alloc()
+				alloc.Job = job
+				alloc.JobID = job.ID
+				alloc.NodeID = uuid.Generate()
+				alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+				alloc.ClientStatus = structs.AllocClientStatusRunning
+				allocs = append(allocs, alloc)
+			}
+			original := allocs[0]
+			original.ClientStatus = structs.AllocClientStatusUnknown
+
+			replacement := mock.Alloc()
+			replacement.Job = job
+			replacement.JobID = job.ID
+			replacement.NodeID = uuid.Generate()
+			replacement.Name = original.Name
+			replacement.ClientStatus = c.replacementStatus
+			replacement.PreviousAllocation = original.ID
+			original.NextAllocation = replacement.ID
+			allocs = append(allocs, replacement)
+
+			reconciler := NewAllocReconciler(testlog.HCLogger(t), allocUpdateFnIgnore, false, job.ID, job, nil, allocs, nil, "")
+			r := reconciler.Compute()
+
+			if !c.keepOriginal {
+				// The original allocation is stopped
+				require.Len(t, r.stop, 1)
+				require.Equal(t, original.ID, r.stop[0].alloc.ID)
+				require.Equal(t, allocReplacedOnReconnect, r.stop[0].statusDescription)
+				require.Len(t, r.place, c.place)
+				require.Empty(t, r.attributeUpdates)
+				return
+			}
+
+			// The replacement is stopped and unchained from the original,
+			// which counts towards the group again
+			assertResults(t, r, &resultExpectation{
+				attributeUpdates: 1,
+				stop:             1,
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					job.TaskGroups[0].Name: {
+						Stop:   1,
+						Ignore: 2,
+					},
+				},
+			})
+			require.Equal(t, replacement.ID, r.stop[0].alloc.ID)
+			require.Empty(t, r.stop[0].alloc.PreviousAllocation)
+			require.Equal(t, allocOriginalReconnected, r.stop[0].statusDescription)
+			require.Contains(t, r.attributeUpdates, original.ID)
+			require.Empty(t, r.attributeUpdates[original.ID].NextAllocation)
+		})
+	}
+}
+
 // Tests the reconciler properly handles lost nodes with allocations while
 // scaling up
 func TestReconciler_LostNode_ScaleUp(t *testing.T) {
@@ -3691,6 +3908,91 @@ func TestReconciler_DeploymentLimit_HealthAccounting(t *testing.T) {
 	}
 }
 
+// Tests the reconciler doesn't place beyond the current step of a deployment
+// updated in manual steps
+func TestReconciler_DeploymentLimit_ManualStep(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate.Copy()
+	job.TaskGroups[0].Update.ManualStep = true
+
+	cases := []struct {
+		stepAllocs  int
+		destructive int
+	}{
+		{
+			stepAllocs:  4,
+			destructive: 0,
+		},
+		{
+			stepAllocs:  6,
+			destructive: 2,
+		},
+		{
+			stepAllocs:  8,
+			destructive: 4,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%d step allocs", c.stepAllocs), func(t *testing.T) {
+			// Create an existing deployment that has placed a healthy step
+			d := structs.NewDeployment(job)
+			d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+				Promoted:     true,
+				DesiredTotal: 10,
+				PlacedAllocs: 4,
+				StepAllocs:   c.stepAllocs,
+			}
+
+			// Create 6 allocations from the old job
+			var allocs []*structs.Allocation
+			for i := 4; i < 10; i++ {
+				alloc := mock.Alloc()
+				alloc.Job = job
+				alloc.JobID = job.ID
+				alloc.NodeID = uuid.Generate()
+				alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+				alloc.TaskGroup = job.TaskGroups[0].Name
+				allocs = append(allocs, alloc)
+			}
+
+			// Create the healthy new allocs
+			handled := make(map[string]allocUpdateType)
+			for i := 0; i < 4; i++ {
+				new := mock.Alloc()
+				new.Job = job
+				new.JobID = job.ID
+				new.NodeID = uuid.Generate()
+				new.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+				new.TaskGroup = job.TaskGroups[0].Name
+				new.DeploymentID = d.ID
+				new.DeploymentStatus = &structs.AllocDeploymentStatus{
+					Healthy: helper.BoolToPtr(true),
+				}
+				allocs = append(allocs, new)
+				handled[new.ID] = allocUpdateFnIgnore
+			}
+
+			mockUpdateFn := allocUpdateFnMock(handled, allocUpdateFnDestructive)
+			reconciler := NewAllocReconciler(testlog.HCLogger(t), mockUpdateFn, false, job.ID, job, d, allocs, nil, "")
+			r := reconciler.Compute()
+
+			// Assert the correct results
+			assertResults(t, r, &resultExpectation{
+				createDeployment:  nil,
+				deploymentUpdates: nil,
+				destructive:       c.destructive,
+				desiredTGUpdates: map[string]*structs.DesiredUpdates{
+					job.TaskGroups[0].Name: {
+						DestructiveUpdate: uint64(c.destructive),
+						Ignore:            uint64(10 - c.destructive),
+					},
+				},
+			})
+		})
+	}
+}
+
 // Tests the reconciler handles an alloc on a tainted node during a rolling
 // update
 func TestReconciler_TaintedNode_RollingUpgrade(t *testing.T) {
@@ -230,6 +230,62 @@ func (a allocSet) filterByTainted(nodes map[string]*structs.Node) (untainted, mi
 	return
 }
 
+// filterByDisconnected filters the set of lost allocations into those that
+// must be stopped and replaced, those kept as disconnected because their node
+// went down less than the group's max_client_disconnect ago, and those whose
+// window has elapsed. The latter are replaced but not stopped so that they can
+// be reconciled with their replacement if their node reconnects.
+func (a allocSet) filterByDisconnected(nodes map[string]*structs.Node, maxDisconnect *time.Duration, now time.Time) (lost, disconnected, expired allocSet) {
+	lost = make(map[string]*structs.Allocation)
+	disconnected = make(map[string]*structs.Allocation)
+	expired = make(map[string]*structs.Allocation)
+	for _, alloc := range a {
+		// Allocs on GC'd nodes are always lost
+		n := nodes[alloc.NodeID]
+		if maxDisconnect == nil || n == nil || n.Status != structs.NodeStatusDown {
+			lost[alloc.ID] = alloc
+			continue
+		}
+
+		if now.Before(disconnectExpiry(n, *maxDisconnect)) {
+			disconnected[alloc.ID] = alloc
+		} else {
+			expired[alloc.ID] = alloc
+		}
+	}
+	return
+}
+
+// filterByReconnected returns the set of allocations that were replaced after
+// their node disconnected and whose node has since reconnected. Allocations
+// that failed or completed while disconnected stay replaced.
+func (a allocSet) filterByReconnected(nodes map[string]*structs.Node) allocSet {
+	reconnected := make(map[string]*structs.Allocation)
+	for _, alloc := range a {
+		if alloc.NextAllocation == "" || alloc.DesiredStatus != structs.AllocDesiredStatusRun {
+			continue
+		}
+
+		switch alloc.ClientStatus {
+		case structs.AllocClientStatusUnknown, structs.AllocClientStatusPending, structs.AllocClientStatusRunning:
+		default:
+			continue
+		}
+
+		if n, ok := nodes[alloc.NodeID]; ok && (n == nil || n.TerminalStatus()) {
+			continue
+		}
+		reconnected[alloc.ID] = alloc
+	}
+	return reconnected
+}
+
+// disconnectExpiry returns the time at which the allocations of a down node
+// are lost given the max_client_disconnect of their group.
+func disconnectExpiry(node *structs.Node, maxDisconnect time.Duration) time.Time {
+	return time.Unix(node.StatusUpdatedAt, 0).Add(maxDisconnect)
+}
+
 // filterByRescheduleable filters the allocation set to return the set of allocations that are either
 // untainted or a set of allocations that must be rescheduled now. Allocations that can be rescheduled
 // at a future time are also returned so that we can create follow up evaluations for them. Allocs are
@@ -91,6 +91,13 @@ type State interface {
 
 	// SchedulerConfig returns config options for the scheduler
 	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
+
+	// NodePoolByName is used to lookup a node pool by name
+	NodePoolByName(ws memdb.WatchSet, name string) (*structs.NodePool, error)
+
+	// JobsByConcurrencyGroup returns an iterator over the jobs of the
+	// namespace in the concurrency group
+	JobsByConcurrencyGroup(ws memdb.WatchSet, namespace, group string) (memdb.ResultIterator, error)
 }
 
 // Planner interface is used to submit a task allocation plan.
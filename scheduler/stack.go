@@ -4,6 +4,8 @@ import (
 	"math"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -50,6 +52,11 @@ type GenericStack struct {
 	taskGroupConstraint *ConstraintChecker
 	taskGroupDevices    *DeviceChecker
 
+	// jobEscapedConstraint and taskGroupEscapedConstraint check the
+	// constraints that escape computed node classes on every node
+	jobEscapedConstraint       *ConstraintChecker
+	taskGroupEscapedConstraint *ConstraintChecker
+
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
 	binPack                    *BinPackIterator
@@ -82,7 +89,8 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
-	// Filter on task group drivers first as they are faster
+	// Filter on task group drivers. Driver health is not part of the computed
+	// node class, so the drivers are checked on every node
 	s.taskGroupDrivers = NewDriverChecker(ctx, nil)
 
 	// Filter on task group constraints second
@@ -91,13 +99,19 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on task group devices
 	s.taskGroupDevices = NewDeviceChecker(ctx)
 
+	// Filter on the job and task group constraints that escape computed node
+	// classes last, as they must be checked on every node
+	s.jobEscapedConstraint = NewConstraintChecker(ctx, nil)
+	s.taskGroupEscapedConstraint = NewConstraintChecker(ctx, nil)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
 	jobs := []FeasibilityChecker{s.jobConstraint}
-	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupDevices}
-	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs)
+	tgs := []FeasibilityChecker{s.taskGroupConstraint, s.taskGroupDevices}
+	nodes := []FeasibilityChecker{s.taskGroupDrivers, s.jobEscapedConstraint, s.taskGroupEscapedConstraint}
+	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs, nodes)
 
 	// Filter on distinct host constraints.
 	s.distinctHostsConstraint = NewDistinctHostsIterator(ctx, s.wrappedChecks)
@@ -156,7 +170,9 @@ func (s *GenericStack) SetNodes(baseNodes []*structs.Node) {
 }
 
 func (s *GenericStack) SetJob(job *structs.Job) {
-	s.jobConstraint.SetConstraints(job.Constraints)
+	jobConstr, jobEscaped := splitEscapedConstraints(job.Constraints)
+	s.jobConstraint.SetConstraints(jobConstr)
+	s.jobEscapedConstraint.SetConstraints(jobEscaped)
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetPriority(job.Priority)
@@ -194,10 +210,12 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 
 	// Get the task groups constraints.
 	tgConstr := taskGroupConstraints(tg)
+	tgClassConstr, tgEscaped := splitEscapedConstraints(tgConstr.constraints)
 
 	// Update the parameters of iterators
 	s.taskGroupDrivers.SetDrivers(tgConstr.drivers)
-	s.taskGroupConstraint.SetConstraints(tgConstr.constraints)
+	s.taskGroupConstraint.SetConstraints(tgClassConstr)
+	s.taskGroupEscapedConstraint.SetConstraints(tgEscaped)
 	s.taskGroupDevices.SetTaskGroup(tg)
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
@@ -223,6 +241,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 
 	// Store the compute time
 	s.ctx.Metrics().AllocationTime = time.Since(start)
+	emitFeasibilityCacheMetrics(s.ctx.Metrics())
 	return option
 }
 
@@ -239,6 +258,11 @@ type SystemStack struct {
 	taskGroupConstraint *ConstraintChecker
 	taskGroupDevices    *DeviceChecker
 
+	// jobEscapedConstraint and taskGroupEscapedConstraint check the
+	// constraints that escape computed node classes on every node
+	jobEscapedConstraint       *ConstraintChecker
+	taskGroupEscapedConstraint *ConstraintChecker
+
 	distinctPropertyConstraint *DistinctPropertyIterator
 	binPack                    *BinPackIterator
 	scoreNorm                  *ScoreNormalizationIterator
@@ -260,7 +284,8 @@ func NewSystemStack(ctx Context) *SystemStack {
 	// Attach the job constraints. The job is filled in later.
 	s.jobConstraint = NewConstraintChecker(ctx, nil)
 
-	// Filter on task group drivers first as they are faster
+	// Filter on task group drivers. Driver health is not part of the computed
+	// node class, so the drivers are checked on every node
 	s.taskGroupDrivers = NewDriverChecker(ctx, nil)
 
 	// Filter on task group constraints second
@@ -269,13 +294,19 @@ func NewSystemStack(ctx Context) *SystemStack {
 	// Filter on task group devices
 	s.taskGroupDevices = NewDeviceChecker(ctx)
 
+	// Filter on the job and task group constraints that escape computed node
+	// classes last, as they must be checked on every node
+	s.jobEscapedConstraint = NewConstraintChecker(ctx, nil)
+	s.taskGroupEscapedConstraint = NewConstraintChecker(ctx, nil)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
 	jobs := []FeasibilityChecker{s.jobConstraint}
-	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupDevices}
-	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs)
+	tgs := []FeasibilityChecker{s.taskGroupConstraint, s.taskGroupDevices}
+	nodes := []FeasibilityChecker{s.taskGroupDrivers, s.jobEscapedConstraint, s.taskGroupEscapedConstraint}
+	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs, nodes)
 
 	// Filter on distinct property constraints.
 	s.distinctPropertyConstraint = NewDistinctPropertyIterator(ctx, s.wrappedChecks)
@@ -304,11 +335,19 @@ func (s *SystemStack) SetNodes(baseNodes []*structs.Node) {
 }
 
 func (s *SystemStack) SetJob(job *structs.Job) {
-	s.jobConstraint.SetConstraints(job.Constraints)
+	jobConstr, jobEscaped := splitEscapedConstraints(job.Constraints)
+	s.jobConstraint.SetConstraints(jobConstr)
+	s.jobEscapedConstraint.SetConstraints(jobEscaped)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetPriority(job.Priority)
 	s.ctx.Eligibility().SetJob(job)
 
+	// The node pool of the job may override whether system jobs can preempt
+	// lower priority allocations
+	_, schedConfig, _ := s.ctx.State().SchedulerConfig()
+	pool, _ := s.ctx.State().NodePoolByName(memdb.NewWatchSet(), job.NodePool)
+	s.binPack.SetEvict(pool.SystemSchedulerPreemptionEnabled(schedConfig))
+
 	if contextual, ok := s.quota.(ContextualIterator); ok {
 		contextual.SetJob(job)
 	}
@@ -322,10 +361,12 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ran
 
 	// Get the task groups constraints.
 	tgConstr := taskGroupConstraints(tg)
+	tgClassConstr, tgEscaped := splitEscapedConstraints(tgConstr.constraints)
 
 	// Update the parameters of iterators
 	s.taskGroupDrivers.SetDrivers(tgConstr.drivers)
-	s.taskGroupConstraint.SetConstraints(tgConstr.constraints)
+	s.taskGroupConstraint.SetConstraints(tgClassConstr)
+	s.taskGroupEscapedConstraint.SetConstraints(tgEscaped)
 	s.taskGroupDevices.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
@@ -340,5 +381,17 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ran
 
 	// Store the compute time
 	s.ctx.Metrics().AllocationTime = time.Since(start)
+	emitFeasibilityCacheMetrics(s.ctx.Metrics())
 	return option
 }
+
+// emitFeasibilityCacheMetrics emits how many of the nodes evaluated for a
+// placement were decided by the cached eligibility of their computed class.
+func emitFeasibilityCacheMetrics(m *structs.AllocMetric) {
+	if m.FeasibilityCacheHits > 0 {
+		metrics.IncrCounter([]string{"nomad", "scheduler", "feasibility_cache", "hit"}, float32(m.FeasibilityCacheHits))
+	}
+	if m.FeasibilityCacheMisses > 0 {
+		metrics.IncrCounter([]string{"nomad", "scheduler", "feasibility_cache", "miss"}, float32(m.FeasibilityCacheMisses))
+	}
+}
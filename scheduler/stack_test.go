@@ -271,6 +271,45 @@ func TestServiceStack_Select_ConstraintFilter(t *testing.T) {
 	}
 }
 
+// TestServiceStack_Select_EscapedConstraint asserts that constraints escaping
+// computed node classes are checked on every node without disabling the class
+// cache of the other constraints.
+func TestServiceStack_Select_EscapedConstraint(t *testing.T) {
+	require := require.New(t)
+	_, ctx := testContext(t)
+
+	// All the nodes share a computed class
+	nodes := make([]*structs.Node, 4)
+	for i := range nodes {
+		nodes[i] = mock.Node()
+	}
+	target := nodes[2]
+
+	stack := NewGenericStack(false, ctx)
+	stack.SetNodes(nodes)
+
+	job := mock.Job()
+	job.Constraints = append(job.Constraints, &structs.Constraint{
+		LTarget: "${node.unique.id}",
+		RTarget: target.ID,
+		Operand: "=",
+	})
+	stack.SetJob(job)
+	require.Len(stack.jobConstraint.constraints, 1)
+	require.Len(stack.jobEscapedConstraint.constraints, 1)
+
+	node := stack.Select(job.TaskGroups[0], &SelectOptions{})
+	require.NotNil(node)
+	require.Equal(target, node.Node)
+
+	// Only the first node evaluated ran the class checks
+	met := ctx.Metrics()
+	require.Equal(1, met.FeasibilityCacheMisses)
+	require.Equal(len(nodes)-1, met.FeasibilityCacheHits)
+	require.Equal(len(nodes)-1, met.ConstraintFiltered["${node.unique.id} = "+target.ID])
+	require.True(ctx.Eligibility().HasEscaped())
+}
+
 func TestServiceStack_Select_BinPack_Overflow(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{
@@ -449,6 +488,48 @@ func TestSystemStack_Select_DriverFilter(t *testing.T) {
 	}
 }
 
+// TestSystemStack_Select_UnhealthyDriver asserts that an unhealthy driver on
+// one node doesn't change the eligibility of other nodes sharing its computed
+// class, whichever of them is evaluated first.
+func TestSystemStack_Select_UnhealthyDriver(t *testing.T) {
+	require := require.New(t)
+
+	healthy, unhealthy := mock.Node(), mock.Node()
+	healthy.Drivers = map[string]*structs.DriverInfo{
+		"exec": {Detected: true, Healthy: true},
+	}
+	unhealthy.Drivers = map[string]*structs.DriverInfo{
+		"exec": {Detected: true, Healthy: false},
+	}
+	require.NoError(healthy.ComputeClass())
+	require.NoError(unhealthy.ComputeClass())
+	require.Equal(healthy.ComputedClass, unhealthy.ComputedClass)
+
+	job := mock.Job()
+	cases := [][]*structs.Node{
+		{healthy, unhealthy},
+		{unhealthy, healthy},
+	}
+	for _, order := range cases {
+		_, ctx := testContext(t)
+		stack := NewSystemStack(ctx)
+		stack.SetJob(job)
+
+		// The system scheduler selects one node at a time, sharing the
+		// class eligibility across them
+		for _, n := range order {
+			stack.SetNodes([]*structs.Node{n})
+			option := stack.Select(job.TaskGroups[0], &SelectOptions{})
+			if n == healthy {
+				require.NotNil(option)
+				require.Equal(healthy, option.Node)
+			} else {
+				require.Nil(option)
+			}
+		}
+	}
+}
+
 func TestSystemStack_Select_ConstraintFilter(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"fmt"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
@@ -14,6 +15,10 @@ const (
 	// we will attempt to schedule if we continue to hit conflicts for system
 	// jobs.
 	maxSystemScheduleAttempts = 5
+
+	// allocNodeExcluded is the status used when stopping an alloc because its
+	// node opted out of the job
+	allocNodeExcluded = "alloc not needed as node excludes the job"
 )
 
 // SystemScheduler is used for 'system' jobs. This scheduler is
@@ -35,6 +40,10 @@ type SystemScheduler struct {
 	limitReached bool
 	nextEval     *structs.Evaluation
 
+	// rollingStagger is the stagger after which the next rolling update
+	// evaluation is processed when the limit of placements was reached
+	rollingStagger time.Duration
+
 	failedTGAllocs map[string]*structs.AllocMetric
 	queuedAllocs   map[string]int
 }
@@ -103,10 +112,11 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
-		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters)
+		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 		if err != nil {
 			return false, fmt.Errorf("failed to get ready nodes: %v", err)
 		}
+		s.nodes = filterExcludingNodes(s.nodes, s.nodesByDC, s.job.ID)
 	}
 
 	// Create a plan
@@ -130,16 +140,12 @@ func (s *SystemScheduler) process() (bool, error) {
 		return false, err
 	}
 
-	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
-	// anyways to get the annotations.
-	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
-		return true, nil
-	}
-
 	// If the limit of placements was reached we need to create an evaluation
-	// to pickup from here after the stagger period.
+	// to pickup from here after the stagger period. This is done even if the
+	// plan is a no-op since the updates may be waiting on the health of the
+	// previous ones.
 	if s.limitReached && s.nextEval == nil {
-		s.nextEval = s.eval.NextRollingEval(s.job.Update.Stagger)
+		s.nextEval = s.eval.NextRollingEval(s.rollingStagger)
 		if err := s.planner.CreateEval(s.nextEval); err != nil {
 			s.logger.Error("failed to make next eval for rolling update", "error", err)
 			return false, err
@@ -147,6 +153,12 @@ func (s *SystemScheduler) process() (bool, error) {
 		s.logger.Debug("rolling update limit reached, next eval created", "next_eval_id", s.nextEval.ID)
 	}
 
+	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
+	// anyways to get the annotations.
+	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
+		return true, nil
+	}
+
 	// Submit the plan
 	result, newState, err := s.planner.SubmitPlan(s.plan)
 	s.planResult = result
@@ -201,6 +213,16 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	// Filter out the allocations in a terminal state
 	allocs, terminalAllocs := structs.FilterTerminalAllocs(allocs)
 
+	// Stop the allocations on the nodes that opted out of the job
+	allocs, excluded, err := excludedAllocs(s.state, s.eval.JobID, tainted, allocs)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes excluding job '%s': %v",
+			s.eval.JobID, err)
+	}
+	for _, alloc := range excluded {
+		s.plan.AppendUpdate(alloc, structs.AllocDesiredStatusStop, allocNodeExcluded, "")
+	}
+
 	// Diff the required and existing allocations
 	diff := diffSystemAllocs(s.job, s.nodes, tainted, allocs, terminalAllocs)
 	s.logger.Debug("reconciled current state with desired state",
@@ -234,14 +256,8 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		}
 	}
 
-	// Check if a rolling upgrade strategy is being used
-	limit := len(diff.update)
-	if !s.job.Stopped() && s.job.Update.Rolling() {
-		limit = s.job.Update.MaxParallel
-	}
-
 	// Treat non in-place updates as an eviction and new placement.
-	s.limitReached = evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	s.limitReached, s.rollingStagger = s.evictAndPlaceUpdates(diff)
 
 	// Nothing remaining to do if placement is not required
 	if len(diff.place) == 0 {
@@ -262,6 +278,97 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	return s.computePlacements(diff.place)
 }
 
+// evictAndPlaceUpdates treats the destructive updates as an eviction and new
+// placement, rolling them out according to the update strategy of their task
+// group. Task groups without a rolling update strategy share the limit of the
+// job's update strategy. It returns whether the limit of placements was
+// reached and the stagger after which the next updates should be made.
+func (s *SystemScheduler) evictAndPlaceUpdates(diff *diffResult) (bool, time.Duration) {
+	// Without a rolling update strategy every update is made at once
+	limit := len(diff.update)
+	if s.job.Stopped() {
+		return evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit), 0
+	}
+	if s.job.Update.Rolling() {
+		limit = s.job.Update.MaxParallel
+	}
+
+	// Group the updates by task group, keeping their order
+	var groups []string
+	updates := make(map[string][]allocTuple)
+	for _, tuple := range diff.update {
+		name := tuple.TaskGroup.Name
+		if _, ok := updates[name]; !ok {
+			groups = append(groups, name)
+		}
+		updates[name] = append(updates[name], tuple)
+	}
+
+	limitReached := false
+	var stagger time.Duration
+	for _, name := range groups {
+		tg := s.job.LookupTaskGroup(name)
+		if tg == nil || tg.Update == nil || !tg.Update.Rolling() {
+			if evictAndPlace(s.ctx, diff, updates[name], allocUpdating, &limit) {
+				limitReached = true
+				stagger = minStagger(stagger, s.job.Update.Stagger)
+			}
+			continue
+		}
+
+		tgLimit, ok := s.rollingLimit(tg, diff.ignore)
+		if !ok {
+			s.logger.Warn("rolling update halted due to unhealthy allocations", "task_group", name)
+			continue
+		}
+		if evictAndPlace(s.ctx, diff, updates[name], allocUpdating, &tgLimit) {
+			limitReached = true
+			stagger = minStagger(stagger, tg.Update.Stagger)
+		}
+	}
+
+	return limitReached, stagger
+}
+
+// rollingLimit returns the number of allocations of the task group that can
+// be updated given its update strategy and the up-to-date allocations. Unless
+// the health of the allocations is set manually, the allocations placed for
+// the current version of the job count against max_parallel until they're
+// healthy, and false is returned if any of them is unhealthy to halt the
+// update.
+func (s *SystemScheduler) rollingLimit(tg *structs.TaskGroup, current []allocTuple) (int, bool) {
+	limit := tg.Update.MaxParallel
+	if tg.Update.HealthCheck == structs.UpdateStrategyHealthCheck_Manual {
+		return limit, true
+	}
+
+	for _, tuple := range current {
+		alloc := tuple.Alloc
+		if alloc.TaskGroup != tg.Name || alloc.CreateIndex < s.job.JobModifyIndex {
+			continue
+		}
+		switch {
+		case alloc.DeploymentStatus.IsUnhealthy():
+			return 0, false
+		case !alloc.DeploymentStatus.HasHealth():
+			limit--
+		}
+	}
+
+	if limit < 0 {
+		limit = 0
+	}
+	return limit, true
+}
+
+// minStagger returns the lowest of the staggers, ignoring unset ones.
+func minStagger(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
 // computePlacements computes placements for allocations
 func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 	nodeByID := make(map[string]*structs.Node, len(s.nodes))
@@ -735,6 +735,110 @@ func TestSystemSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+func TestSystemSched_JobModify_RollingHealth(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Generate a fake job with allocations on all but two nodes
+	job := mock.SystemJob()
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes[2:] {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), allocs))
+
+	// Update the job with a rolling update strategy waiting on the health
+	// of the allocations, such that it cannot be done in-place
+	job2 := mock.SystemJob()
+	job2.ID = job.ID
+	job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+		Stagger:         time.Minute,
+		MaxParallel:     2,
+		HealthCheck:     structs.UpdateStrategyHealthCheck_TaskStates,
+		MinHealthyTime:  10 * time.Second,
+		HealthyDeadline: 5 * time.Minute,
+	}
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job2))
+
+	// Place the allocations of the new version on the first two nodes, whose
+	// health isn't known yet
+	var current []*structs.Allocation
+	for _, node := range nodes[:2] {
+		alloc := mock.Alloc()
+		alloc.Job = job2
+		alloc.JobID = job2.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		current = append(current, alloc)
+	}
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), current))
+
+	process := func() *structs.Plan {
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    50,
+			TriggeredBy: structs.EvalTriggerRollingUpdate,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+		h.Plans, h.Evals, h.CreateEvals = nil, nil, nil
+		require.NoError(h.Process(NewSystemScheduler, eval))
+		if len(h.Plans) == 0 {
+			return nil
+		}
+		require.Len(h.Plans, 1)
+		return h.Plans[0]
+	}
+
+	// The allocations waiting on their health use up max_parallel, so no
+	// update is made but a follow up eval is created after the stagger
+	require.Nil(process())
+	require.Len(h.CreateEvals, 1)
+	require.Equal(structs.EvalTriggerRollingUpdate, h.CreateEvals[0].TriggeredBy)
+	require.Equal(time.Minute, h.CreateEvals[0].Wait)
+
+	// Once one of them is healthy a single allocation is updated
+	healthy := current[0].Copy()
+	healthy.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+	require.NoError(h.State.UpdateAllocsFromClient(h.NextIndex(), []*structs.Allocation{healthy}))
+
+	plan := process()
+	require.NotNil(plan)
+	var update []*structs.Allocation
+	for _, updateList := range plan.NodeUpdate {
+		update = append(update, updateList...)
+	}
+	require.Len(update, 1)
+	require.Len(h.CreateEvals, 1)
+
+	// An unhealthy allocation halts the update
+	unhealthy := current[1].Copy()
+	unhealthy.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(false)}
+	require.NoError(h.State.UpdateAllocsFromClient(h.NextIndex(), []*structs.Allocation{unhealthy}))
+
+	require.Nil(process())
+	require.Empty(h.CreateEvals)
+}
+
 func TestSystemSched_JobModify_InPlace(t *testing.T) {
 	h := NewHarness(t)
 
@@ -1220,6 +1324,65 @@ func TestSystemSched_NodeUpdate(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestSystemSched_NodeExcludesJob(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create some nodes, the first two opting out of the job
+	job := mock.SystemJob()
+	var nodes []*structs.Node
+	for i := 0; i < 4; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+	}
+	nodes[0].Meta[structs.NodeMetaExcludeSystemJobs] = "other, " + job.ID
+	nodes[1].Meta[structs.NodeMetaExcludeSystemJobs] = structs.NodeExcludeAllSystemJobs
+	nodes[2].Meta[structs.NodeMetaExcludeSystemJobs] = "other"
+	for _, node := range nodes {
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create an allocation on the first node, which opted out since
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = nodes[0].ID
+	alloc.Name = "my-job.web[0]"
+	require.NoError(h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		NodeID:      nodes[0].ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewSystemScheduler, eval))
+	require.Len(h.Plans, 1)
+	plan := h.Plans[0]
+
+	// Ensure the allocation on the first node is stopped
+	require.Len(plan.NodeUpdate, 1)
+	require.Len(plan.NodeUpdate[nodes[0].ID], 1)
+	stopped := plan.NodeUpdate[nodes[0].ID][0]
+	require.Equal(structs.AllocDesiredStatusStop, stopped.DesiredStatus)
+	require.Equal(allocNodeExcluded, stopped.DesiredDescription)
+
+	// Ensure the job is only placed on the nodes that don't opt out
+	require.Len(plan.NodeAllocation, 2)
+	require.Len(plan.NodeAllocation[nodes[2].ID], 1)
+	require.Len(plan.NodeAllocation[nodes[3].ID], 1)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestSystemSched_RetryLimit(t *testing.T) {
 	h := NewHarness(t)
 	h.Planner = &RejectPlan{h}
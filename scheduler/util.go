@@ -219,9 +219,10 @@ func diffSystemAllocs(job *structs.Job, nodes []*structs.Node, taintedNodes map[
 	return result
 }
 
-// readyNodesInDCs returns all the ready nodes in the given datacenters and a
-// mapping of each data center to the count of ready nodes.
-func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int, error) {
+// readyNodesInDCs returns all the ready nodes of the node pool in the given
+// datacenters and a mapping of each data center to the count of ready nodes.
+// Nodes of any pool are returned for the "all" node pool.
+func readyNodesInDCs(state State, dcs []string, pool string) ([]*structs.Node, map[string]int, error) {
 	// Index the DCs
 	dcMap := make(map[string]int, len(dcs))
 	for _, dc := range dcs {
@@ -255,12 +256,64 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int
 		if _, ok := dcMap[node.Datacenter]; !ok {
 			continue
 		}
+		if !NodeInPool(node, pool) {
+			continue
+		}
 		out = append(out, node)
 		dcMap[node.Datacenter]++
 	}
 	return out, dcMap, nil
 }
 
+// NodeInPool returns whether the node belongs to the node pool. Nodes and
+// jobs that predate node pools belong to the default pool.
+func NodeInPool(node *structs.Node, pool string) bool {
+	if pool == structs.NodePoolAll {
+		return true
+	}
+	nodePool := node.NodePool
+	if nodePool == "" {
+		nodePool = structs.NodePoolDefault
+	}
+	if pool == "" {
+		pool = structs.NodePoolDefault
+	}
+	return nodePool == pool
+}
+
+// ConcurrencyGroupHolder returns the ID of the other job of the concurrency
+// group of the job that is running allocations, or an empty string if the
+// group is free. Allocations hold the group until they're terminal on the
+// client, so stopped allocations still hold it while they shut down.
+func ConcurrencyGroupHolder(state State, job *structs.Job) (string, error) {
+	if job.ConcurrencyGroup == "" {
+		return "", nil
+	}
+
+	iter, err := state.JobsByConcurrencyGroup(nil, job.Namespace, job.ConcurrencyGroup)
+	if err != nil {
+		return "", fmt.Errorf("failed to get jobs of concurrency group %q: %v", job.ConcurrencyGroup, err)
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		other := raw.(*structs.Job)
+		if other.ID == job.ID {
+			continue
+		}
+
+		allocs, err := state.AllocsByJob(nil, other.Namespace, other.ID, true)
+		if err != nil {
+			return "", fmt.Errorf("failed to get allocs for job %q: %v", other.ID, err)
+		}
+		for _, alloc := range allocs {
+			if !alloc.ClientTerminalStatus() {
+				return other.ID, nil
+			}
+		}
+	}
+	return "", nil
+}
+
 // retryMax is used to retry a callback until it returns success or
 // a maximum number of attempts is reached. An optional reset function may be
 // passed which is called after each failed iteration. If the reset function is
@@ -325,6 +378,54 @@ func taintedNodes(state State, allocs []*structs.Allocation) (map[string]*struct
 	return out, nil
 }
 
+// filterExcludingNodes returns the nodes that don't opt out of the system job
+// through their metadata, decrementing the count of ready nodes of the
+// datacenters of the others.
+func filterExcludingNodes(nodes []*structs.Node, nodesByDC map[string]int, jobID string) []*structs.Node {
+	out := make([]*structs.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node.ExcludesSystemJob(jobID) {
+			nodesByDC[node.Datacenter]--
+			continue
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// excludedAllocs splits the allocations of the system job into the ones on
+// nodes that opt out of the job through their metadata and the others. The
+// allocations on tainted nodes are left to the handling of tainted nodes.
+func excludedAllocs(state State, jobID string, tainted map[string]*structs.Node,
+	allocs []*structs.Allocation) (remaining, excluded []*structs.Allocation, err error) {
+
+	excludes := make(map[string]bool)
+	for _, alloc := range allocs {
+		if _, ok := tainted[alloc.NodeID]; ok {
+			remaining = append(remaining, alloc)
+			continue
+		}
+
+		exclude, ok := excludes[alloc.NodeID]
+		if !ok {
+			ws := memdb.NewWatchSet()
+			node, err := state.NodeByID(ws, alloc.NodeID)
+			if err != nil {
+				return nil, nil, err
+			}
+			exclude = node != nil && node.ExcludesSystemJob(jobID)
+			excludes[alloc.NodeID] = exclude
+		}
+
+		if exclude {
+			excluded = append(excluded, alloc)
+		} else {
+			remaining = append(remaining, alloc)
+		}
+	}
+	return remaining, excluded, nil
+}
+
 // shuffleNodes randomizes the slice order with the Fisher-Yates algorithm
 func shuffleNodes(nodes []*structs.Node) {
 	n := len(nodes)
@@ -351,6 +452,16 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		return true
 	}
 
+	// Check shared memory
+	if !reflect.DeepEqual(a.SharedMemory, b.SharedMemory) {
+		return true
+	}
+
+	// Check the Consul cluster the services are registered in
+	if a.ConsulCluster() != b.ConsulCluster() {
+		return true
+	}
+
 	// Check each task
 	for _, at := range a.Tasks {
 		bt := b.LookupTask(at.Name)
@@ -402,13 +513,24 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 			if !reflect.DeepEqual(aPorts, bPorts) {
 				return true
 			}
+
+			// The resolver configuration is written when the task starts
+			if !reflect.DeepEqual(an.DNS, bn.DNS) {
+				return true
+			}
 		}
 
 		// Inspect the non-network resources
 		if ar, br := at.Resources, bt.Resources; ar.CPU != br.CPU {
 			return true
+		} else if ar.Cores != br.Cores {
+			return true
 		} else if ar.MemoryMB != br.MemoryMB {
 			return true
+		} else if ar.HugePages2MB != br.HugePages2MB || ar.HugePages1GB != br.HugePages1GB {
+			return true
+		} else if ar.Realtime != br.Realtime {
+			return true
 		}
 	}
 	return false
@@ -610,6 +732,24 @@ func taskGroupConstraints(tg *structs.TaskGroup) tgConstrainTuple {
 	return c
 }
 
+// splitEscapedConstraints splits the constraints into the ones that can be
+// checked once per computed node class and the ones that escape computed node
+// classes and must be checked on every node.
+func splitEscapedConstraints(constraints []*structs.Constraint) (class, escaped []*structs.Constraint) {
+	escaped = structs.EscapedConstraints(constraints)
+	if len(escaped) == 0 {
+		return constraints, nil
+	}
+
+	class = make([]*structs.Constraint, 0, len(constraints)-len(escaped))
+	for _, c := range constraints {
+		if !structs.ConstraintEscapes(c) {
+			class = append(class, c)
+		}
+	}
+	return class, escaped
+}
+
 // desiredUpdates takes the diffResult as well as the set of inplace and
 // destructive updates and returns a map of task groups to their set of desired
 // updates.
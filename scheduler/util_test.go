@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 // noErr is used to assert there are no errors
@@ -312,7 +313,7 @@ func TestReadyNodesInDCs(t *testing.T) {
 	noErr(t, state.UpsertNode(1002, node3))
 	noErr(t, state.UpsertNode(1003, node4))
 
-	nodes, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"})
+	nodes, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"}, structs.NodePoolDefault)
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -331,6 +332,35 @@ func TestReadyNodesInDCs(t *testing.T) {
 	}
 }
 
+func TestReadyNodesInDCs_NodePool(t *testing.T) {
+	state := state.TestStateStore(t)
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.NodePool = "gpu"
+	node3 := mock.Node()
+	node3.NodePool = ""
+
+	noErr(t, state.UpsertNode(1000, node1))
+	noErr(t, state.UpsertNode(1001, node2))
+	noErr(t, state.UpsertNode(1002, node3))
+
+	cases := []struct {
+		pool     string
+		expected int
+	}{
+		{structs.NodePoolDefault, 2},
+		{"", 2},
+		{"gpu", 1},
+		{"other", 0},
+		{structs.NodePoolAll, 3},
+	}
+	for _, c := range cases {
+		nodes, _, err := readyNodesInDCs(state, []string{"dc1"}, c.pool)
+		require.NoError(t, err)
+		require.Len(t, nodes, c.expected, "pool %q", c.pool)
+	}
+}
+
 func TestRetryMax(t *testing.T) {
 	calls := 0
 	bad := func() (bool, error) {
@@ -570,6 +600,28 @@ func TestTasksUpdated(t *testing.T) {
 	if !tasksUpdated(j1, j18, name) {
 		t.Fatal("bad")
 	}
+
+	// Change the resolver configuration
+	j19 := mock.Job()
+	j19.TaskGroups[0].Tasks[0].Resources.Networks[0].DNS = &structs.DNSConfig{Servers: []string{"10.0.0.53"}}
+	if !tasksUpdated(j1, j19, name) {
+		t.Fatal("bad")
+	}
+
+	// Change the reserved cores
+	j20 := mock.Job()
+	j20.TaskGroups[0].Tasks[0].Resources.CPU = 0
+	j20.TaskGroups[0].Tasks[0].Resources.Cores = 2
+	if !tasksUpdated(j1, j20, name) {
+		t.Fatal("bad")
+	}
+
+	// Share memory between the tasks
+	j21 := mock.Job()
+	j21.TaskGroups[0].SharedMemory = &structs.SharedMemory{SizeMB: 64}
+	if !tasksUpdated(j1, j21, name) {
+		t.Fatal("bad")
+	}
 }
 
 func TestEvictAndPlace_LimitLessThanAllocs(t *testing.T) {
@@ -12,10 +12,13 @@ export default Controller.extend({
 
   queryParams: {
     region: 'region',
+    ott: 'ott',
   },
 
   region: null,
 
+  ott: null,
+
   error: null,
 
   errorStr: computed('error', function() {
@@ -8,6 +8,7 @@ export default Route.extend({
   config: service(),
   system: service(),
   store: service(),
+  token: service(),
 
   queryParams: {
     region: {
@@ -22,6 +23,19 @@ export default Route.extend({
   },
 
   beforeModel(transition) {
+    // Exchange the one-time token handed over by the nomad ui command before
+    // any request is made. An invalid one leaves the current token in place.
+    const oneTimeSecret = transition.queryParams.ott;
+    const exchange = oneTimeSecret
+      ? this.get('token')
+          .exchangeOneTimeToken(oneTimeSecret)
+          .catch(() => {})
+      : RSVP.resolve();
+
+    return exchange.then(() => this.loadRegions(transition));
+  },
+
+  loadRegions(transition) {
     return RSVP.all([this.get('system.regions'), this.get('system.defaultRegion')]).then(
       promises => {
         if (!this.get('system.shouldShowRegions')) return promises;
@@ -61,6 +75,13 @@ export default Route.extend({
       });
     }
 
+    // The one-time token is spent, remove it from the URL
+    if (controller.get('ott')) {
+      next(() => {
+        controller.set('ott', null);
+      });
+    }
+
     return this._super(...arguments);
   },
 
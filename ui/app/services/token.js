@@ -2,6 +2,7 @@ import Service, { inject as service } from '@ember/service';
 import { computed } from '@ember/object';
 import { assign } from '@ember/polyfills';
 import queryString from 'query-string';
+import RSVP from 'rsvp';
 import fetch from 'nomad-ui/utils/fetch';
 
 export default Service.extend({
@@ -48,6 +49,19 @@ export default Service.extend({
 
     return this.authorizedRawRequest(url, options);
   },
+
+  // Exchanges a one-time token, such as the one created by the nomad ui
+  // command, for the secret of the token it was created with.
+  exchangeOneTimeToken(oneTimeSecret) {
+    return fetch('/v1/acl/token/onetime/exchange', {
+      method: 'POST',
+      body: JSON.stringify({ OneTimeSecretID: oneTimeSecret }),
+    })
+      .then(res => (res.ok ? res.json() : RSVP.reject(res)))
+      .then(token => {
+        this.set('secret', token.SecretID);
+      });
+  },
 });
 
 function addParams(url, params) {